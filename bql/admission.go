@@ -0,0 +1,133 @@
+package bql
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+// Keys of the config store of a topology controlling admission of statements
+// issued via TopologyBuilder.AddStmt, TopologyBuilder.AddStmtAs,
+// Session.AddStmt, and TopologyBuilder.AddSelectUnionStmt:
+//
+//   - max_concurrent_selects: the maximum number of streaming SELECT
+//     statements running at the same time
+//   - max_concurrent_statements: the maximum number of statements being
+//     processed at the same time
+//   - admission_timeout: how long a statement waits for another one to
+//     finish when the limit is reached, e.g. "5s"
+//
+// Limits aren't applied when they're omitted or 0. A statement exceeding a
+// limit fails with AdmissionError immediately when admission_timeout is
+// omitted. Statements issued by the topology itself, such as actions of
+// triggers, aren't limited so that ad-hoc queries don't starve them.
+const (
+	maxConcurrentSelectsConfig    = "max_concurrent_selects"
+	maxConcurrentStatementsConfig = "max_concurrent_statements"
+	admissionTimeoutConfig        = "admission_timeout"
+)
+
+// AdmissionError is returned when a statement isn't admitted because the
+// topology is already running the maximum number of statements.
+type AdmissionError struct {
+	// Kind is the kind of limited statements.
+	Kind string
+
+	// Limit is the maximum number of the statements.
+	Limit int
+}
+
+func (e *AdmissionError) Error() string {
+	return fmt.Sprintf("the topology is already running the maximum number of %v: %v", e.Kind, e.Limit)
+}
+
+// IsAdmissionError returns true when the error is an AdmissionError.
+func IsAdmissionError(err error) bool {
+	_, ok := err.(*AdmissionError)
+	return ok
+}
+
+// admission counts statements running in a topology.
+type admission struct {
+	m       sync.Mutex
+	running map[string]int
+
+	// released is closed and replaced when a statement finishes so that
+	// waiting statements can retry.
+	released chan struct{}
+}
+
+// acquire waits until the number of running statements of the kind gets
+// less than the limit, and counts the caller as a running statement.
+func (a *admission) acquire(kind string, limit int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		a.m.Lock()
+		if a.running == nil {
+			a.running = map[string]int{}
+			a.released = make(chan struct{})
+		}
+		if a.running[kind] < limit {
+			a.running[kind]++
+			a.m.Unlock()
+			return nil
+		}
+		released := a.released
+		a.m.Unlock()
+
+		d := deadline.Sub(time.Now())
+		if d <= 0 {
+			return &AdmissionError{Kind: kind, Limit: limit}
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-released:
+			t.Stop()
+		case <-t.C:
+			return &AdmissionError{Kind: kind, Limit: limit}
+		}
+	}
+}
+
+func (a *admission) release(kind string) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	a.running[kind]--
+	close(a.released)
+	a.released = make(chan struct{})
+}
+
+// admit admits a statement limited by the config key. The caller must call
+// the returned function when the statement finishes.
+func (tb *TopologyBuilder) admit(key, kind string) (func(), error) {
+	conf := tb.topology.Context().Config
+	n, err := conf.GetInt(key)
+	if err != nil {
+		if core.IsNotExist(err) {
+			return func() {}, nil
+		}
+		return nil, fmt.Errorf("%v must be an integer: %v", key, err)
+	}
+	if n <= 0 {
+		return func() {}, nil
+	}
+
+	var timeout time.Duration
+	if d, err := conf.GetDuration(admissionTimeoutConfig); err == nil {
+		timeout = d
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("%v must be a duration: %v", admissionTimeoutConfig, err)
+	}
+
+	if err := tb.admission.acquire(kind, int(n), timeout); err != nil {
+		return nil, err
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			tb.admission.release(kind)
+		})
+	}, nil
+}
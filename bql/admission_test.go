@@ -0,0 +1,109 @@
+package bql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestAdmission(t *testing.T) {
+	Convey("Given a topology builder limiting streaming SELECT statements", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;`), ShouldBeNil)
+		conf := dt.Context().Config
+		conf.Set("max_concurrent_selects", data.Int(1))
+
+		istmt, _, err := parser.New().ParseStmt(`SELECT ISTREAM * FROM s [RANGE 1 TUPLES];`)
+		So(err, ShouldBeNil)
+		stmt := istmt.(parser.SelectStmt)
+
+		sn, _, err := tb.AddSelectStmt(&stmt)
+		So(err, ShouldBeNil)
+
+		Convey("When issuing another SELECT statement", func() {
+			_, _, err := tb.AddSelectStmt(&stmt)
+
+			Convey("Then it should be rejected", func() {
+				So(err, ShouldNotBeNil)
+				So(IsAdmissionError(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the running SELECT statement is stopped", func() {
+			So(sn.Stop(), ShouldBeNil)
+
+			Convey("Then another SELECT statement should be admitted", func() {
+				sn2, _, err := tb.AddSelectStmt(&stmt)
+				So(err, ShouldBeNil)
+				So(sn2.Stop(), ShouldBeNil)
+			})
+		})
+
+		Convey("When admission_timeout is set", func() {
+			conf.Set("admission_timeout", data.String("5s"))
+
+			Convey("Then another SELECT statement should wait for the running one", func() {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					sn.Stop()
+				}()
+				sn2, _, err := tb.AddSelectStmt(&stmt)
+				So(err, ShouldBeNil)
+				So(sn2.Stop(), ShouldBeNil)
+			})
+		})
+
+		Convey("When the limit is removed", func() {
+			conf.Set("max_concurrent_selects", data.Int(0))
+
+			Convey("Then SELECT statements should be admitted", func() {
+				sn2, _, err := tb.AddSelectStmt(&stmt)
+				So(err, ShouldBeNil)
+				So(sn2.Stop(), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a topology builder limiting statements", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		dt.Context().Config.Set("max_concurrent_statements", data.Int(1))
+
+		Convey("When no other statement is running", func() {
+			Convey("Then statements including nested ones should be processed", func() {
+				So(addBQLToTopology(tb, `
+					CREATE PAUSED SOURCE s TYPE dummy;
+					CREATE STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES]
+						UNION ALL SELECT ISTREAM * FROM s [RANGE 1 TUPLES];`), ShouldBeNil)
+			})
+		})
+
+		Convey("When another statement is running", func() {
+			release, err := tb.admit("max_concurrent_statements", "statements")
+			So(err, ShouldBeNil)
+			Reset(release)
+
+			Convey("Then a statement should be rejected", func() {
+				err := addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy;`)
+				So(IsAdmissionError(err), ShouldBeTrue)
+			})
+
+			Convey("Then a statement in a session should be rejected", func() {
+				_, err := tb.NewSession().AddStmt(parser.CreateStateStmt{})
+				So(IsAdmissionError(err), ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,580 @@
+package bql
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// alertQueueSize is the maximum number of events waiting to be sent to
+	// notifiers. Events are dropped when the queue is full so that slow
+	// notifiers don't block the input stream.
+	alertQueueSize = 1024
+
+	// alertHistorySize is the number of recent events kept for the status.
+	alertHistorySize = 100
+)
+
+// alertSink is a sink managing alerts fired by tuples in a condition stream.
+// An alert is a sink node so that it's listed, monitored, and stopped along
+// with other nodes in the topology:
+//
+//	CREATE ALERT overheat FROM readings WHERE temperature > 80
+//	  WITH key="device_id", resolve_after=300, severity="critical",
+//	  notifiers=[{"type": "webhook", "url": "http://example.com/hook"}];
+//
+// A tuple satisfying the WHERE clause fires the alert of its key. While the
+// alert is firing, following tuples having the key only update it, so
+// notifiers are called once for the alert. The alert is resolved when a
+// tuple having the key doesn't satisfy the WHERE clause, or when no tuple
+// having the key fires it within resolve_after. It has following
+// parameters:
+//
+//	* key: a JSON Path of the key in each tuple. All tuples share one alert
+//	  when it's omitted.
+//	* severity: a string passed to notifiers. The default is "warning".
+//	* resolve_after: a duration after which an alert without firing tuples
+//	  is resolved. It's in seconds when the value is a number. Alerts are
+//	  only resolved by tuples when it's omitted.
+//	* repeat_interval: a duration after which notifiers are called again
+//	  while an alert keeps firing. Notifiers are called only once when it's
+//	  omitted.
+//	* notifiers: an array of notifier settings. See newAlertNotifier for
+//	  details.
+//
+// Notifications of a silenced alert aren't sent, but the alert is managed
+// as usual.
+type alertSink struct {
+	name           string
+	input          string
+	condition      execution.Evaluator
+	key            data.Path
+	severity       string
+	resolveAfter   time.Duration
+	repeatInterval time.Duration
+	notifiers      []alertNotifier
+	ctx            *core.Context
+
+	m             sync.Mutex
+	alerts        map[string]*alertEntry
+	silences      []*alertSilence
+	nextSilenceID int64
+	history       []data.Map
+	closed        bool
+
+	numFired         int64
+	numResolved      int64
+	numSilenced      int64
+	numDropped       int64
+	numNotifications int64
+	numNotifyErrors  int64
+
+	queue  chan data.Map
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+type alertEntry struct {
+	key          data.Value
+	firedAt      time.Time
+	lastSeen     time.Time
+	lastNotified time.Time
+	count        int64
+	tuple        data.Map
+}
+
+type alertSilence struct {
+	id      int64
+	key     data.Value // nil when all keys are silenced
+	until   time.Time
+	comment string
+}
+
+func (tb *TopologyBuilder) createAlert(stmt *parser.CreateAlertStmt) (core.Node, error) {
+	params, err := tb.mkParamsMap(stmt.Params)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newAlertSink(stmt, params, tb.Reg)
+	if err != nil {
+		return nil, err
+	}
+	s.ctx = tb.topology.Context()
+
+	name := string(stmt.Name)
+	sn, err := tb.topology.AddSink(name, s, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := sn.Input(string(stmt.Input), nil); err != nil {
+		tb.topology.Remove(name)
+		return nil, err
+	}
+	s.start()
+	return sn, nil
+}
+
+func (tb *TopologyBuilder) dropAlert(stmt *parser.DropAlertStmt) error {
+	if _, err := tb.alertSink(string(stmt.Alert)); err != nil {
+		return err
+	}
+	return tb.topology.Remove(string(stmt.Alert))
+}
+
+func newAlertSink(stmt *parser.CreateAlertStmt, params data.Map, reg udf.FunctionRegistry) (*alertSink, error) {
+	c := &struct {
+		Key            string
+		Severity       string
+		ResolveAfter   time.Duration
+		RepeatInterval time.Duration
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if c.ResolveAfter < 0 || c.RepeatInterval < 0 {
+		return nil, errors.New("'resolve_after' and 'repeat_interval' parameters must not be negative")
+	}
+	if c.Severity == "" {
+		c.Severity = "warning"
+	}
+
+	s := &alertSink{
+		name:           string(stmt.Name),
+		input:          string(stmt.Input),
+		severity:       c.Severity,
+		resolveAfter:   c.ResolveAfter,
+		repeatInterval: c.RepeatInterval,
+		alerts:         map[string]*alertEntry{},
+		nextSilenceID:  1,
+		queue:          make(chan data.Map, alertQueueSize),
+		stopCh:         make(chan struct{}),
+	}
+	if c.Key != "" {
+		p, err := data.CompilePath(c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("'key' parameter doesn't have a valid path: %v", err)
+		}
+		s.key = p
+	}
+
+	if v, ok := params["notifiers"]; ok {
+		a, err := data.AsArray(v)
+		if err != nil {
+			return nil, fmt.Errorf("'notifiers' parameter must be an array: %v", err)
+		}
+		for _, e := range a {
+			m, err := data.AsMap(e)
+			if err != nil {
+				return nil, fmt.Errorf("each notifier must be a map: %v", err)
+			}
+			n, err := newAlertNotifier(m)
+			if err != nil {
+				return nil, err
+			}
+			s.notifiers = append(s.notifiers, n)
+		}
+	}
+
+	if stmt.Filter != nil {
+		// the condition is evaluated on the data of the input tuple, so
+		// references to the input stream are removed
+		for rel := range stmt.Filter.ReferencedRelations() {
+			if rel != "" && rel != s.input {
+				return nil, fmt.Errorf("cannot refer to relation '%s' "+
+					"when using only '%s'", rel, s.input)
+			}
+		}
+		expr := stmt.Filter.RenameReferencedRelation(s.input, "")
+		flatExpr, err := execution.ParserExprToFlatExpr(expr, reg)
+		if err != nil {
+			return nil, err
+		}
+		cond, err := execution.ExpressionToEvaluator(flatExpr, reg)
+		if err != nil {
+			return nil, err
+		}
+		s.condition = cond
+	}
+	return s, nil
+}
+
+// start starts goroutines sending notifications and resolving alerts.
+func (s *alertSink) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for ev := range s.queue {
+			s.send(ev)
+		}
+	}()
+
+	if s.resolveAfter <= 0 {
+		return
+	}
+	interval := s.resolveAfter / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	} else if interval > time.Second {
+		interval = time.Second
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case now := <-t.C:
+				s.resolveStale(now)
+			}
+		}
+	}()
+}
+
+// send sends the event to all notifiers.
+func (s *alertSink) send(ev data.Map) {
+	for _, n := range s.notifiers {
+		err := n.notify(ev)
+		s.m.Lock()
+		if err != nil {
+			s.numNotifyErrors++
+		} else {
+			s.numNotifications++
+		}
+		s.m.Unlock()
+		if err != nil && s.ctx != nil {
+			s.ctx.ErrLog(err).WithField("node_type", core.NTSink).
+				WithField("node_name", s.name).
+				WithField("notifier", n.String()).
+				Error("Cannot send the notification of the alert")
+		}
+	}
+}
+
+// Write fires or resolves the alert of the key in the tuple.
+func (s *alertSink) Write(ctx *core.Context, t *core.Tuple) error {
+	firing := true
+	if s.condition != nil {
+		res, err := s.condition.Eval(t.Data)
+		if err != nil {
+			return err
+		}
+		// a NULL value is definitely not "true" as in a WHERE clause
+		if res.Type() == data.TypeNull {
+			firing = false
+		} else if firing, err = data.AsBool(res); err != nil {
+			return err
+		}
+	}
+	var key data.Value = data.Null{}
+	if s.key != nil {
+		if k, err := t.Data.Get(s.key); err == nil {
+			key = k
+		}
+	}
+	id := key.String()
+	now := time.Now()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return errors.New("the alert is already closed")
+	}
+	a, ok := s.alerts[id]
+	if !firing {
+		if ok {
+			s.resolve(id, a, now)
+		}
+		return nil
+	}
+
+	if !ok {
+		a = &alertEntry{
+			key:     key,
+			firedAt: now,
+		}
+		s.alerts[id] = a
+		s.numFired++
+	}
+	a.lastSeen = now
+	a.count++
+	a.tuple = t.Data.Copy()
+	if !ok || (s.repeatInterval > 0 && now.Sub(a.lastNotified) >= s.repeatInterval) {
+		a.lastNotified = now
+		s.enqueue(s.event("firing", a, now), key, now)
+	}
+	return nil
+}
+
+// resolveStale resolves alerts which haven't been fired within
+// resolve_after.
+func (s *alertSink) resolveStale(now time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for id, a := range s.alerts {
+		if now.Sub(a.lastSeen) >= s.resolveAfter {
+			s.resolve(id, a, now)
+		}
+	}
+}
+
+// resolve resolves the alert. The caller must hold the lock.
+func (s *alertSink) resolve(id string, a *alertEntry, now time.Time) {
+	delete(s.alerts, id)
+	s.numResolved++
+	ev := s.event("resolved", a, now)
+	ev["resolved_at"] = data.Timestamp(now)
+	s.enqueue(ev, a.key, now)
+}
+
+// event returns an event of the alert passed to notifiers.
+func (s *alertSink) event(state string, a *alertEntry, now time.Time) data.Map {
+	return data.Map{
+		"alert":    data.String(s.name),
+		"state":    data.String(state),
+		"severity": data.String(s.severity),
+		"key":      a.key,
+		"fired_at": data.Timestamp(a.firedAt),
+		"count":    data.Int(a.count),
+		"tuple":    a.tuple,
+		"time":     data.Timestamp(now),
+	}
+}
+
+// enqueue records the event and passes it to notifiers unless the key is
+// silenced. The caller must hold the lock.
+func (s *alertSink) enqueue(ev data.Map, key data.Value, now time.Time) {
+	silenced := s.silenced(key, now)
+	ev["silenced"] = data.Bool(silenced)
+	s.history = append(s.history, ev)
+	if len(s.history) > alertHistorySize {
+		s.history = s.history[len(s.history)-alertHistorySize:]
+	}
+	if silenced {
+		s.numSilenced++
+		return
+	}
+	if len(s.notifiers) == 0 {
+		return
+	}
+	select {
+	case s.queue <- ev:
+	default:
+		s.numDropped++
+	}
+}
+
+// silenced returns true when the key is silenced. It also removes expired
+// silences. The caller must hold the lock.
+func (s *alertSink) silenced(key data.Value, now time.Time) bool {
+	res := false
+	ss := s.silences[:0]
+	for _, sl := range s.silences {
+		if !now.Before(sl.until) {
+			continue
+		}
+		ss = append(ss, sl)
+		if sl.key == nil || data.Equal(sl.key, key) {
+			res = true
+		}
+	}
+	s.silences = ss
+	return res
+}
+
+// silence silences the key until the given time. All keys are silenced when
+// the key is nil. It returns the ID of the silence.
+func (s *alertSink) silence(key data.Value, until time.Time, comment string) (int64, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return 0, errors.New("the alert is already closed")
+	}
+	id := s.nextSilenceID
+	s.nextSilenceID++
+	s.silences = append(s.silences, &alertSilence{
+		id:      id,
+		key:     key,
+		until:   until,
+		comment: comment,
+	})
+	return id, nil
+}
+
+// unsilence removes the silence having the ID.
+func (s *alertSink) unsilence(id int64) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for i, sl := range s.silences {
+		if sl.id == id {
+			s.silences = append(s.silences[:i], s.silences[i+1:]...)
+			return nil
+		}
+	}
+	return core.NotExistError(fmt.Errorf("the silence %v doesn't exist", id))
+}
+
+// Status returns the status of the alert. It has following fields:
+//
+//	* input: the name of the condition stream
+//	* condition: the WHERE clause (optional)
+//	* severity: the severity of the alert
+//	* firing: an array of firing alerts sorted by the time they were fired
+//	* silences: an array of active silences
+//	* history: an array of recent events
+//	* num_fired, num_resolved, num_silenced: the numbers of events
+//	* num_notifications, num_notify_errors: the numbers of notifications
+//	  sent and failed
+//	* num_dropped: the number of events dropped because notifiers were slow
+func (s *alertSink) Status() data.Map {
+	s.m.Lock()
+	defer s.m.Unlock()
+	now := time.Now()
+	s.silenced(data.Null{}, now) // remove expired silences
+
+	as := make([]*alertEntry, 0, len(s.alerts))
+	for _, a := range s.alerts {
+		as = append(as, a)
+	}
+	sort.Slice(as, func(i, j int) bool {
+		return as[i].firedAt.Before(as[j].firedAt)
+	})
+	firing := make(data.Array, len(as))
+	for i, a := range as {
+		firing[i] = data.Map{
+			"key":       a.key,
+			"fired_at":  data.Timestamp(a.firedAt),
+			"last_seen": data.Timestamp(a.lastSeen),
+			"count":     data.Int(a.count),
+			"tuple":     a.tuple,
+			"silenced":  data.Bool(s.silenced(a.key, now)),
+		}
+	}
+
+	silences := make(data.Array, len(s.silences))
+	for i, sl := range s.silences {
+		m := data.Map{
+			"id":      data.Int(sl.id),
+			"until":   data.Timestamp(sl.until),
+			"comment": data.String(sl.comment),
+		}
+		if sl.key != nil {
+			m["key"] = sl.key
+		}
+		silences[i] = m
+	}
+
+	history := make(data.Array, len(s.history))
+	for i, ev := range s.history {
+		history[i] = ev
+	}
+
+	st := data.Map{
+		"name":              data.String(s.name),
+		"input":             data.String(s.input),
+		"severity":          data.String(s.severity),
+		"firing":            firing,
+		"silences":          silences,
+		"history":           history,
+		"num_fired":         data.Int(s.numFired),
+		"num_resolved":      data.Int(s.numResolved),
+		"num_silenced":      data.Int(s.numSilenced),
+		"num_notifications": data.Int(s.numNotifications),
+		"num_notify_errors": data.Int(s.numNotifyErrors),
+		"num_dropped":       data.Int(s.numDropped),
+	}
+	notifiers := make(data.Array, len(s.notifiers))
+	for i, n := range s.notifiers {
+		notifiers[i] = data.String(n.String())
+	}
+	st["notifiers"] = notifiers
+	return st
+}
+
+func (s *alertSink) Close(ctx *core.Context) error {
+	s.m.Lock()
+	if s.closed {
+		s.m.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stopCh)
+	close(s.queue)
+	s.m.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+// alertSink returns the alert having the name.
+func (tb *TopologyBuilder) alertSink(name string) (*alertSink, error) {
+	sn, err := tb.topology.Sink(name)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := sn.Sink().(*alertSink)
+	if !ok {
+		return nil, core.NotExistError(fmt.Errorf("'%v' is not an alert", name))
+	}
+	return s, nil
+}
+
+// AlertStatus returns the status of the alert. See alertSink.Status for
+// details of the status.
+func (tb *TopologyBuilder) AlertStatus(name string) (data.Map, error) {
+	s, err := tb.alertSink(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Status(), nil
+}
+
+// AlertStatuses returns statuses of all alerts in the topology sorted by
+// their names.
+func (tb *TopologyBuilder) AlertStatuses() []data.Map {
+	var names []string
+	for name, sn := range tb.topology.Sinks() {
+		if _, ok := sn.Sink().(*alertSink); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	res := make([]data.Map, 0, len(names))
+	for _, name := range names {
+		if st, err := tb.AlertStatus(name); err == nil {
+			res = append(res, st)
+		}
+	}
+	return res
+}
+
+// SilenceAlert stops sending notifications of the alert until the given
+// time. Only notifications of the key are stopped when key isn't nil. It
+// returns the ID of the silence, which can be passed to UnsilenceAlert.
+func (tb *TopologyBuilder) SilenceAlert(name string, key data.Value, until time.Time, comment string) (int64, error) {
+	s, err := tb.alertSink(name)
+	if err != nil {
+		return 0, err
+	}
+	return s.silence(key, until, comment)
+}
+
+// UnsilenceAlert removes the silence created by SilenceAlert.
+func (tb *TopologyBuilder) UnsilenceAlert(name string, id int64) error {
+	s, err := tb.alertSink(name)
+	if err != nil {
+		return err
+	}
+	return s.unsilence(id)
+}
@@ -0,0 +1,252 @@
+package bql
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// defaultAlertNotifierTimeout is the default timeout of sending a
+	// notification.
+	defaultAlertNotifierTimeout = 10 * time.Second
+)
+
+// alertNotifier sends a notification of an alert event to an external
+// service.
+type alertNotifier interface {
+	notify(ev data.Map) error
+	String() string
+}
+
+// newAlertNotifier creates a notifier from a map given to the notifiers
+// parameter of CREATE ALERT. The type field of the map selects the notifier:
+//
+//	* webhook: POSTs the event as JSON. It has url (required), headers, and
+//	  timeout parameters.
+//	* email: sends the event to recipients with SMTP. It has addr (required,
+//	  host:port of the SMTP server), from (required), to (required, an array
+//	  of addresses), username, and password parameters.
+//	* mqtt: publishes the event as JSON with QoS 0. It has broker (required,
+//	  host:port), topic (required), client_id, username, password, retain,
+//	  and timeout parameters.
+func newAlertNotifier(params data.Map) (alertNotifier, error) {
+	v, ok := params["type"]
+	if !ok {
+		return nil, errors.New("a notifier must have 'type'")
+	}
+	typ, err := data.AsString(v)
+	if err != nil {
+		return nil, fmt.Errorf("'type' of a notifier must be a string: %v", err)
+	}
+
+	switch typ {
+	case "webhook":
+		n := &webhookNotifier{}
+		if err := data.NewDecoder(nil).Decode(params, n); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(n.URL, "http://") && !strings.HasPrefix(n.URL, "https://") {
+			return nil, fmt.Errorf("'url' of a webhook must be an http(s) URL: %v", n.URL)
+		}
+		if n.Timeout <= 0 {
+			n.Timeout = defaultAlertNotifierTimeout
+		}
+		return n, nil
+
+	case "email":
+		n := &emailNotifier{}
+		if err := data.NewDecoder(nil).Decode(params, n); err != nil {
+			return nil, err
+		}
+		if len(n.To) == 0 {
+			return nil, errors.New("'to' of an email notifier must have at least one address")
+		}
+		return n, nil
+
+	case "mqtt":
+		n := &mqttNotifier{}
+		if err := data.NewDecoder(nil).Decode(params, n); err != nil {
+			return nil, err
+		}
+		if n.ClientID == "" {
+			n.ClientID = fmt.Sprintf("sensorbee-alert-%d", time.Now().UnixNano())
+		}
+		if n.Timeout <= 0 {
+			n.Timeout = defaultAlertNotifierTimeout
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("unsupported notifier type: %v", typ)
+}
+
+type webhookNotifier struct {
+	URL     string `bql:",required"`
+	Headers map[string]string
+	Timeout time.Duration
+}
+
+func (n *webhookNotifier) notify(ev data.Map) error {
+	req, err := http.NewRequest("POST", n.URL, strings.NewReader(ev.String()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := (&http.Client{Timeout: n.Timeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded with status %v", res.Status)
+	}
+	return nil
+}
+
+func (n *webhookNotifier) String() string {
+	return "webhook " + n.URL
+}
+
+type emailNotifier struct {
+	Addr     string   `bql:",required"`
+	From     string   `bql:",required"`
+	To       []string `bql:",required"`
+	Username string
+	Password string
+}
+
+func (n *emailNotifier) notify(ev data.Map) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, err := net.SplitHostPort(n.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+	return smtp.SendMail(n.Addr, auth, n.From, n.To, n.message(ev))
+}
+
+// message returns the email of the event.
+func (n *emailNotifier) message(ev data.Map) []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "From: %v\r\n", n.From)
+	fmt.Fprintf(b, "To: %v\r\n", strings.Join(n.To, ", "))
+	str := func(v data.Value) string {
+		s, _ := data.ToString(v)
+		return s
+	}
+	fmt.Fprintf(b, "Subject: [SensorBee] %v %v (%v)\r\n", str(ev["alert"]), str(ev["state"]), str(ev["key"]))
+	b.WriteString("Content-Type: application/json; charset=UTF-8\r\n\r\n")
+	b.WriteString(ev.String())
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+func (n *emailNotifier) String() string {
+	return "email " + strings.Join(n.To, ",")
+}
+
+// mqttNotifier publishes events with MQTT 3.1.1. It connects to the broker
+// for each event because alerts are rare.
+type mqttNotifier struct {
+	Broker   string `bql:",required"`
+	Topic    string `bql:",required"`
+	ClientID string
+	Username string
+	Password string
+	Retain   bool
+	Timeout  time.Duration
+}
+
+func (n *mqttNotifier) notify(ev data.Map) error {
+	conn, err := net.DialTimeout("tcp", n.Broker, n.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(n.Timeout))
+	w := bufio.NewWriter(conn)
+
+	// CONNECT
+	var flags byte = 0x02 // clean session
+	payload := mqttString(nil, n.ClientID)
+	if n.Username != "" {
+		flags |= 0x80
+		payload = mqttString(payload, n.Username)
+		if n.Password != "" {
+			flags |= 0x40
+			payload = mqttString(payload, n.Password)
+		}
+	}
+	vh := mqttString(nil, "MQTT")
+	vh = append(vh, 4, flags, 0, 60) // level 4, keep alive 60s
+	writeMQTTPacket(w, 0x10, append(vh, payload...))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	ack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		return fmt.Errorf("cannot receive CONNACK: %v", err)
+	}
+	if ack[0] != 0x20 || ack[1] != 2 {
+		return errors.New("the broker sent an invalid CONNACK")
+	}
+	if ack[3] != 0 {
+		return fmt.Errorf("the broker refused the connection: return code %v", ack[3])
+	}
+
+	// PUBLISH with QoS 0 and DISCONNECT
+	var header byte = 0x30
+	if n.Retain {
+		header |= 0x01
+	}
+	writeMQTTPacket(w, header, append(mqttString(nil, n.Topic), ev.String()...))
+	writeMQTTPacket(w, 0xe0, nil)
+	return w.Flush()
+}
+
+func (n *mqttNotifier) String() string {
+	return "mqtt " + n.Broker + "/" + n.Topic
+}
+
+// mqttString appends a length-prefixed string to b.
+func mqttString(b []byte, s string) []byte {
+	b = append(b, 0, 0)
+	binary.BigEndian.PutUint16(b[len(b)-2:], uint16(len(s)))
+	return append(b, s...)
+}
+
+// writeMQTTPacket writes a packet having the fixed header and the body.
+func writeMQTTPacket(w *bufio.Writer, header byte, body []byte) {
+	w.WriteByte(header)
+	l := len(body)
+	for {
+		b := byte(l % 128)
+		l /= 128
+		if l > 0 {
+			b |= 0x80
+		}
+		w.WriteByte(b)
+		if l == 0 {
+			break
+		}
+	}
+	w.Write(body)
+}
@@ -0,0 +1,342 @@
+package bql
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type recordingNotifier struct {
+	m      sync.Mutex
+	events []data.Map
+}
+
+func (n *recordingNotifier) notify(ev data.Map) error {
+	n.m.Lock()
+	defer n.m.Unlock()
+	n.events = append(n.events, ev)
+	return nil
+}
+
+func (n *recordingNotifier) String() string {
+	return "recording"
+}
+
+// wait waits until the notifier receives num events and returns them.
+func (n *recordingNotifier) wait(num int) []data.Map {
+	for i := 0; i < 500; i++ {
+		n.m.Lock()
+		l := len(n.events)
+		n.m.Unlock()
+		if l >= num {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	n.m.Lock()
+	defer n.m.Unlock()
+	return append([]data.Map{}, n.events...)
+}
+
+func newTestAlertSink(bql string) (*alertSink, *recordingNotifier, error) {
+	stmt, _, err := parser.New().ParseStmt(bql)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := stmt.(parser.CreateAlertStmt)
+	params := data.Map{}
+	for _, kv := range s.Params {
+		params[string(kv.Key)] = kv.Value
+	}
+	a, err := newAlertSink(&s, params, udf.CopyGlobalUDFRegistry(core.NewContext(nil)))
+	if err != nil {
+		return nil, nil, err
+	}
+	n := &recordingNotifier{}
+	a.notifiers = append(a.notifiers, n)
+	a.start()
+	return a, n, nil
+}
+
+func TestAlertSink(t *testing.T) {
+	ctx := core.NewContext(nil)
+
+	Convey("Given an alert with a condition and a key", t, func() {
+		a, n, err := newTestAlertSink(`CREATE ALERT hot FROM r WHERE r:temp > 80 WITH key="id", severity="critical"`)
+		So(err, ShouldBeNil)
+		Reset(func() {
+			a.Close(ctx)
+		})
+		write := func(id, temp int) {
+			So(a.Write(ctx, core.NewTuple(data.Map{"id": data.Int(id), "temp": data.Int(temp)})), ShouldBeNil)
+		}
+
+		Convey("When tuples satisfying the condition are written", func() {
+			write(1, 90)
+			write(1, 95)
+			write(2, 85)
+			write(3, 20)
+
+			Convey("Then each key should fire once", func() {
+				evs := n.wait(2)
+				So(len(evs), ShouldEqual, 2)
+				So(evs[0]["state"], ShouldEqual, data.String("firing"))
+				So(evs[0]["key"], ShouldEqual, data.Int(1))
+				So(evs[0]["severity"], ShouldEqual, data.String("critical"))
+				So(evs[1]["key"], ShouldEqual, data.Int(2))
+
+				st := a.Status()
+				So(len(st["firing"].(data.Array)), ShouldEqual, 2)
+				So(st["firing"].(data.Array)[0].(data.Map)["count"], ShouldEqual, data.Int(2))
+			})
+
+			Convey("Then a tuple not satisfying the condition should resolve the key", func() {
+				write(1, 50)
+				evs := n.wait(3)
+				So(len(evs), ShouldEqual, 3)
+				So(evs[2]["state"], ShouldEqual, data.String("resolved"))
+				So(evs[2]["key"], ShouldEqual, data.Int(1))
+				So(a.Status()["num_resolved"], ShouldEqual, data.Int(1))
+			})
+		})
+
+		Convey("When the key is silenced", func() {
+			id, err := a.silence(data.Int(1), time.Now().Add(time.Hour), "maintenance")
+			So(err, ShouldBeNil)
+			write(1, 90)
+			write(2, 90)
+
+			Convey("Then only the other key should be notified", func() {
+				evs := n.wait(1)
+				time.Sleep(10 * time.Millisecond)
+				So(len(n.wait(1)), ShouldEqual, 1)
+				So(evs[0]["key"], ShouldEqual, data.Int(2))
+				st := a.Status()
+				So(st["num_silenced"], ShouldEqual, data.Int(1))
+				So(len(st["silences"].(data.Array)), ShouldEqual, 1)
+			})
+
+			Convey("Then unsilencing should notify following events", func() {
+				So(a.unsilence(id), ShouldBeNil)
+				So(a.unsilence(id), ShouldNotBeNil)
+				write(1, 50)
+				evs := n.wait(2)
+				So(len(evs), ShouldEqual, 2)
+				So(evs[1]["state"], ShouldEqual, data.String("resolved"))
+			})
+		})
+	})
+
+	Convey("Given an alert with resolve_after and repeat_interval", t, func() {
+		a, n, err := newTestAlertSink(`CREATE ALERT failures FROM errors WITH resolve_after="30ms", repeat_interval="10ms"`)
+		So(err, ShouldBeNil)
+		Reset(func() {
+			a.Close(ctx)
+		})
+
+		Convey("When tuples are written repeatedly", func() {
+			So(a.Write(ctx, core.NewTuple(data.Map{"x": data.Int(1)})), ShouldBeNil)
+			time.Sleep(15 * time.Millisecond)
+			So(a.Write(ctx, core.NewTuple(data.Map{"x": data.Int(2)})), ShouldBeNil)
+
+			Convey("Then the alert should be notified again and resolved later", func() {
+				evs := n.wait(3)
+				So(len(evs), ShouldEqual, 3)
+				So(evs[0]["state"], ShouldEqual, data.String("firing"))
+				So(evs[1]["state"], ShouldEqual, data.String("firing"))
+				So(evs[1]["count"], ShouldEqual, data.Int(2))
+				So(evs[2]["state"], ShouldEqual, data.String("resolved"))
+				So(evs[2]["key"], ShouldResemble, data.Null{})
+			})
+		})
+	})
+
+	Convey("Given invalid CREATE ALERT statements", t, func() {
+		for _, s := range []string{
+			`CREATE ALERT a FROM r WHERE x:temp > 1`,
+			`CREATE ALERT a FROM r WITH key="id["`,
+			`CREATE ALERT a FROM r WITH resolve_after=-1`,
+			`CREATE ALERT a FROM r WITH notifiers=1`,
+			`CREATE ALERT a FROM r WITH notifiers=[{"type":"sms"}]`,
+			`CREATE ALERT a FROM r WITH notifiers=[{"type":"webhook","url":"ftp://a"}]`,
+			`CREATE ALERT a FROM r WITH notifiers=[{"type":"email","addr":"localhost:25","from":"a@example.com"}]`,
+			`CREATE ALERT a FROM r WITH notifiers=[{"type":"mqtt","broker":"localhost:1883"}]`,
+		} {
+			Convey("Then creating the alert should fail: "+s, func() {
+				_, _, err := newTestAlertSink(s)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+
+	Convey("Given a topology builder with a source", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy`), ShouldBeNil)
+
+		Convey("When creating an alert on the source", func() {
+			So(addBQLToTopology(tb, `CREATE ALERT big FROM s WHERE int > 2 WITH key="int";`), ShouldBeNil)
+
+			Convey("Then tuples from the source should fire alerts", func() {
+				So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+				var st data.Map
+				for i := 0; i < 500; i++ {
+					if st, err = tb.AlertStatus("big"); err == nil && st["num_fired"] == data.Int(2) {
+						break
+					}
+					time.Sleep(time.Millisecond)
+				}
+				So(st["num_fired"], ShouldEqual, data.Int(2))
+				So(tb.AlertStatuses(), ShouldHaveLength, 1)
+			})
+
+			Convey("Then it should be silenced by the API", func() {
+				id, err := tb.SilenceAlert("big", nil, time.Now().Add(time.Minute), "")
+				So(err, ShouldBeNil)
+				So(tb.UnsilenceAlert("big", id), ShouldBeNil)
+			})
+
+			Convey("Then dropping the alert should remove the sink", func() {
+				So(addBQLToTopology(tb, `DROP ALERT big;`), ShouldBeNil)
+				_, err := dt.Sink("big")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When creating a normal sink", func() {
+			So(addBQLToTopology(tb, `CREATE SINK k TYPE stdout;`), ShouldBeNil)
+
+			Convey("Then it shouldn't be dropped as an alert", func() {
+				So(addBQLToTopology(tb, `DROP ALERT k;`), ShouldNotBeNil)
+				_, err := tb.AlertStatus("k")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestAlertNotifiers(t *testing.T) {
+	ev := data.Map{"alert": data.String("a"), "state": data.String("firing"), "key": data.Int(1)}
+
+	Convey("Given a webhook notifier", t, func() {
+		var body []byte
+		status := http.StatusOK
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(status)
+		}))
+		Reset(ts.Close)
+		n, err := newAlertNotifier(data.Map{"type": data.String("webhook"), "url": data.String(ts.URL)})
+		So(err, ShouldBeNil)
+
+		Convey("When notifying an event", func() {
+			err := n.notify(ev)
+
+			Convey("Then the server should receive it as JSON", func() {
+				So(err, ShouldBeNil)
+				So(string(body), ShouldEqual, ev.String())
+			})
+		})
+
+		Convey("When the server responds with an error", func() {
+			status = http.StatusInternalServerError
+
+			Convey("Then notifying should fail", func() {
+				So(n.notify(ev), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given an MQTT broker", t, func() {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			l.Close()
+		})
+		packets := make(chan []byte, 3)
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for i := 0; i < 3; i++ {
+				header, err := r.ReadByte()
+				if err != nil {
+					return
+				}
+				l, mul := 0, 1
+				for {
+					b, _ := r.ReadByte()
+					l += int(b&0x7f) * mul
+					mul *= 128
+					if b&0x80 == 0 {
+						break
+					}
+				}
+				body := make([]byte, l)
+				io.ReadFull(r, body)
+				packets <- append([]byte{header}, body...)
+				if i == 0 {
+					conn.Write([]byte{0x20, 2, 0, 0})
+				}
+			}
+		}()
+		n, err := newAlertNotifier(data.Map{
+			"type":      data.String("mqtt"),
+			"broker":    data.String(l.Addr().String()),
+			"topic":     data.String("alerts"),
+			"client_id": data.String("c"),
+		})
+		So(err, ShouldBeNil)
+
+		Convey("When notifying an event", func() {
+			So(n.notify(ev), ShouldBeNil)
+
+			Convey("Then the broker should receive CONNECT, PUBLISH, and DISCONNECT", func() {
+				p := <-packets
+				So(p[0], ShouldEqual, 0x10)
+				So(string(p[3:7]), ShouldEqual, "MQTT")
+				p = <-packets
+				So(p[0], ShouldEqual, 0x30)
+				So(string(p[3:9]), ShouldEqual, "alerts")
+				So(string(p[9:]), ShouldEqual, ev.String())
+				p = <-packets
+				So(p, ShouldResemble, []byte{0xe0})
+			})
+		})
+	})
+
+	Convey("Given an email notifier", t, func() {
+		n, err := newAlertNotifier(data.Map{
+			"type": data.String("email"),
+			"addr": data.String("localhost:25"),
+			"from": data.String("sb@example.com"),
+			"to":   data.Array{data.String("ops@example.com")},
+		})
+		So(err, ShouldBeNil)
+
+		Convey("Then the message should have the subject of the event", func() {
+			msg := string(n.(*emailNotifier).message(ev))
+			So(msg, ShouldContainSubstring, "To: ops@example.com\r\n")
+			So(msg, ShouldContainSubstring, "Subject: [SensorBee] a firing (1)\r\n")
+		})
+	})
+}
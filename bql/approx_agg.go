@@ -0,0 +1,158 @@
+package bql
+
+import (
+	"fmt"
+	"math"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// maxApproxFreqWidth is the maximum width of the count-min sketch of
+	// approx_freq, which limits the memory used by a small error bound.
+	maxApproxFreqWidth = 1 << 20
+)
+
+// approxCountDistinctFunc is an aggregate function estimating the number of
+// distinct values with HyperLogLog. Its memory usage doesn't depend on the
+// number of distinct values, so it can replace count of a nested GROUP BY
+// on a high-cardinality key:
+//
+//	SELECT RSTREAM region, approx_count_distinct(user_id) AS users
+//	  FROM events [RANGE 1 MINUTES] GROUP BY region;
+//
+// It can be used in BQL as `approx_count_distinct(expr)` or
+// `approx_count_distinct(expr, error)`. error is the desired relative
+// standard error, which is about 0.008 by default and can be as small as
+// 0.002. Smaller errors need more memory: the sketch has 1.08/error^2 bytes
+// (rounded up to a power of 2).
+//
+//	Input: any (aggregated), Float (optional)
+//	Return Type: Int
+//
+// Null values are ignored.
+type approxCountDistinctFunc struct{}
+
+var _ udf.UDF = &approxCountDistinctFunc{}
+
+func (f *approxCountDistinctFunc) Accept(arity int) bool {
+	return arity == 1 || arity == 2
+}
+
+func (f *approxCountDistinctFunc) IsAggregationParameter(k int) bool {
+	return k == 0
+}
+
+func (f *approxCountDistinctFunc) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, fmt.Errorf("function takes one or two arguments")
+	}
+	arr, err := data.AsArray(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("function needs array input, not %T", args[0])
+	}
+
+	precision := defaultHyperLogLogPrecision
+	if len(args) == 2 {
+		e, err := data.ToFloat(args[1])
+		if err != nil || !(e > 0 && e < 1) {
+			return nil, fmt.Errorf("error must be a number between 0 and 1: %v", args[1])
+		}
+		// The standard error is 1.04/sqrt(2^precision).
+		precision = int(math.Ceil(2 * math.Log2(1.04/e)))
+		if precision < 4 {
+			precision = 4
+		}
+		if precision > 18 {
+			return nil, fmt.Errorf("error is too small: %v", e)
+		}
+	}
+
+	h, err := newHyperLogLog(precision)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, v := range arr {
+		if v.Type() == data.TypeNull {
+			continue
+		}
+		h.addValue(v)
+		n++
+	}
+	if n == 0 {
+		return data.Int(0), nil
+	}
+	return data.Int(h.estimate()), nil
+}
+
+// approxFreqFunc is an aggregate function estimating the number of
+// occurrences of a value with a count-min sketch. Its memory usage doesn't
+// depend on the number of distinct values.
+//
+// It can be used in BQL as `approx_freq(expr, value)` or
+// `approx_freq(expr, value, error)`. The estimate is never less than the
+// actual count, and it exceeds the actual count by at most error*n for n
+// non-null values with the probability of 99%. error is about 0.0013 by
+// default and can be as small as 0.000003. The sketch has 5 rows of
+// ceil(e/error) counters.
+//
+//	Input: any (aggregated), any, Float (optional)
+//	Return Type: Int
+//
+// Null values are ignored.
+type approxFreqFunc struct{}
+
+var _ udf.UDF = &approxFreqFunc{}
+
+func (f *approxFreqFunc) Accept(arity int) bool {
+	return arity == 2 || arity == 3
+}
+
+func (f *approxFreqFunc) IsAggregationParameter(k int) bool {
+	return k == 0
+}
+
+func (f *approxFreqFunc) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, fmt.Errorf("function takes two or three arguments")
+	}
+	arr, err := data.AsArray(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("function needs array input, not %T", args[0])
+	}
+
+	width := defaultCountMinSketchWidth
+	if len(args) == 3 {
+		e, err := data.ToFloat(args[2])
+		if err != nil || !(e > 0 && e <= 1) {
+			return nil, fmt.Errorf("error must be a number between 0 and 1: %v", args[2])
+		}
+		w := math.Ceil(math.E / e)
+		if w > maxApproxFreqWidth {
+			return nil, fmt.Errorf("error is too small: %v", e)
+		}
+		width = int(w)
+	}
+
+	// The depth of 5 makes the probability of exceeding the error bound
+	// exp(-5), which is less than 1%.
+	s, err := newCountMinSketch(width, defaultCountMinSketchDepth)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range arr {
+		if v.Type() == data.TypeNull {
+			continue
+		}
+		s.addCount(v, 1)
+	}
+	return data.Int(s.estimate(args[1])), nil
+}
+
+func init() {
+	udf.MustRegisterGlobalUDF("approx_count_distinct", &approxCountDistinctFunc{})
+	udf.MustRegisterGlobalUDF("approx_freq", &approxFreqFunc{})
+}
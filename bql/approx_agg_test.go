@@ -0,0 +1,126 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestApproxAggregates(t *testing.T) {
+	ctx := core.NewContext(nil)
+
+	Convey("Given the approx_count_distinct function", t, func() {
+		f := &approxCountDistinctFunc{}
+
+		Convey("When counting distinct values", func() {
+			arr := data.Array{data.Null{}}
+			for i := 0; i < 10000; i++ {
+				arr = append(arr, data.Int(i%5000))
+			}
+
+			Convey("Then the estimate should be within the default error", func() {
+				v, err := f.Call(ctx, arr)
+				So(err, ShouldBeNil)
+				n, _ := data.AsInt(v)
+				So(n, ShouldBeBetween, 4800, 5200)
+			})
+
+			Convey("Then a larger error bound should still give a rough estimate", func() {
+				v, err := f.Call(ctx, arr, data.Float(0.1))
+				So(err, ShouldBeNil)
+				n, _ := data.AsInt(v)
+				So(n, ShouldBeBetween, 3500, 6500)
+			})
+		})
+
+		Convey("When the input only has NULLs", func() {
+			v, err := f.Call(ctx, data.Array{data.Null{}})
+
+			Convey("Then it should return 0", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Int(0))
+			})
+		})
+
+		Convey("When the error is invalid", func() {
+			for _, e := range []data.Value{data.Float(0), data.Float(1), data.Float(0.001), data.String("a")} {
+				_, err := f.Call(ctx, data.Array{}, e)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given the approx_freq function", t, func() {
+		f := &approxFreqFunc{}
+		arr := data.Array{data.Null{}}
+		for i := 0; i < 1000; i++ {
+			arr = append(arr, data.Int(i%10))
+		}
+
+		Convey("When estimating the frequency of values", func() {
+			Convey("Then it should never underestimate", func() {
+				for _, c := range []struct {
+					v        data.Value
+					expected int64
+				}{
+					{data.Int(3), 100},
+					{data.String("a"), 0},
+				} {
+					v, err := f.Call(ctx, arr, c.v)
+					So(err, ShouldBeNil)
+					n, _ := data.AsInt(v)
+					So(n, ShouldBeGreaterThanOrEqualTo, c.expected)
+					So(n, ShouldBeLessThanOrEqualTo, c.expected+2)
+				}
+			})
+
+			Convey("Then the error bound should be configurable", func() {
+				v, err := f.Call(ctx, arr, data.Int(5), data.Float(0.5))
+				So(err, ShouldBeNil)
+				n, _ := data.AsInt(v)
+				So(n, ShouldBeBetween, 99, 600)
+			})
+		})
+
+		Convey("When the error is invalid", func() {
+			for _, e := range []data.Value{data.Float(0), data.Float(1.5), data.Float(1e-7)} {
+				_, err := f.Call(ctx, arr, data.Int(1), e)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given a stream counting distinct values by groups", t, func() {
+		tb, err := setupTopology(`CREATE STREAM box AS SELECT RSTREAM int % 2 AS g,
+			approx_count_distinct(int) AS n, approx_freq(int, 3) AS f
+			FROM source [RANGE 4 TUPLES] GROUP BY int % 2`, false)
+		So(err, ShouldBeNil)
+		dt := tb.Topology()
+		Reset(func() {
+			dt.Stop()
+		})
+		sin, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sin.Sink().(*tupleCollectorSink)
+
+		Convey("When tuples are emitted", func() {
+			// each tuple emits a row for each group in the window
+			si.Wait(7)
+
+			Convey("Then each group should have its estimates", func() {
+				res := map[int64]data.Map{}
+				for i := 5; i < 7; i++ {
+					d := si.get(i).Data
+					g, _ := data.AsInt(d["g"])
+					res[g] = d
+				}
+				So(res[0]["n"], ShouldEqual, data.Int(2))
+				So(res[1]["n"], ShouldEqual, data.Int(2))
+				So(res[1]["f"], ShouldEqual, data.Int(1))
+				So(res[0]["f"], ShouldEqual, data.Int(0))
+			})
+		})
+	})
+}
@@ -0,0 +1,871 @@
+package bql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// AvroSchema is a parsed Avro schema. It encodes and decodes values in the
+// Avro binary encoding.
+//
+// Records and maps are converted to data.Map, arrays to data.Array, enums to
+// the names of symbols, and bytes and fixed to data.Blob. Longs having the
+// logical type timestamp-millis or timestamp-micros are converted to
+// data.Timestamp. A value of a union is converted to the value of the
+// selected branch.
+type AvroSchema struct {
+	text string
+	root *avroSchema
+}
+
+type avroType int
+
+const (
+	avroNull avroType = iota
+	avroBoolean
+	avroInt
+	avroLong
+	avroFloat
+	avroDouble
+	avroBytes
+	avroString
+	avroRecord
+	avroEnum
+	avroArray
+	avroMap
+	avroUnion
+	avroFixed
+)
+
+var avroPrimitiveTypes = map[string]avroType{
+	"null":    avroNull,
+	"boolean": avroBoolean,
+	"int":     avroInt,
+	"long":    avroLong,
+	"float":   avroFloat,
+	"double":  avroDouble,
+	"bytes":   avroBytes,
+	"string":  avroString,
+}
+
+type avroSchema struct {
+	typ avroType
+
+	// name is the full name of a named type.
+	name    string
+	logical string
+
+	fields   []*avroField  // record
+	symbols  []string      // enum
+	items    *avroSchema   // array items and map values
+	branches []*avroSchema // union
+	size     int           // fixed
+}
+
+type avroField struct {
+	name       string
+	aliases    []string
+	schema     *avroSchema
+	def        data.Value
+	hasDefault bool
+}
+
+// ParseAvroSchema parses a schema written in JSON.
+func ParseAvroSchema(text string) (*AvroSchema, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %v", err)
+	}
+	p := &avroSchemaParser{
+		names: map[string]*avroSchema{},
+	}
+	s, err := p.parse(v, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %v", err)
+	}
+	return &AvroSchema{
+		text: text,
+		root: s,
+	}, nil
+}
+
+// String returns the JSON representation of the schema.
+func (s *AvroSchema) String() string {
+	return s.text
+}
+
+// Decode decodes a value in the Avro binary encoding.
+func (s *AvroSchema) Decode(b []byte) (data.Value, error) {
+	r := bytes.NewReader(b)
+	v, err := avroDecode(s.root, r)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%v bytes remain after decoding a value", r.Len())
+	}
+	return v, nil
+}
+
+// Encode encodes a value in the Avro binary encoding.
+func (s *AvroSchema) Encode(v data.Value) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := avroEncode(s.root, v, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Resolve converts a value decoded with another schema, i.e. the writer's
+// schema, so that it conforms to this schema. It follows the schema
+// resolution rules of Avro: fields missing in the value are filled with
+// their defaults, fields not defined in the schema are removed, and numbers
+// are promoted.
+func (s *AvroSchema) Resolve(v data.Value) (data.Value, error) {
+	return avroResolve(s.root, v)
+}
+
+type avroSchemaParser struct {
+	names map[string]*avroSchema
+}
+
+func (p *avroSchemaParser) fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func (p *avroSchemaParser) parse(v interface{}, namespace string) (*avroSchema, error) {
+	switch v := v.(type) {
+	case string:
+		if t, ok := avroPrimitiveTypes[v]; ok {
+			return &avroSchema{typ: t}, nil
+		}
+		if s, ok := p.names[p.fullName(v, namespace)]; ok {
+			return s, nil
+		}
+		if s, ok := p.names[v]; ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("undefined type: %v", v)
+
+	case []interface{}:
+		s := &avroSchema{typ: avroUnion}
+		for _, b := range v {
+			bs, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if bs.typ == avroUnion {
+				return nil, errors.New("a union cannot directly contain another union")
+			}
+			s.branches = append(s.branches, bs)
+		}
+		return s, nil
+
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	}
+	return nil, fmt.Errorf("invalid schema: %v", v)
+}
+
+func (p *avroSchemaParser) parseComplex(m map[string]interface{}, namespace string) (*avroSchema, error) {
+	typ, _ := m["type"].(string)
+	if typ == "" {
+		if t, ok := m["type"]; ok {
+			return p.parse(t, namespace) // e.g. {"type": {"type": "array", ...}}
+		}
+		return nil, errors.New("'type' is missing")
+	}
+	logical, _ := m["logicalType"].(string)
+	if t, ok := avroPrimitiveTypes[typ]; ok {
+		return &avroSchema{typ: t, logical: logical}, nil
+	}
+
+	var s *avroSchema
+	switch typ {
+	case "record", "error":
+		s = &avroSchema{typ: avroRecord}
+	case "enum":
+		s = &avroSchema{typ: avroEnum}
+	case "fixed":
+		s = &avroSchema{typ: avroFixed, logical: logical}
+	case "array", "map":
+		key := "items"
+		s = &avroSchema{typ: avroArray}
+		if typ == "map" {
+			key = "values"
+			s.typ = avroMap
+		}
+		it, ok := m[key]
+		if !ok {
+			return nil, fmt.Errorf("'%v' is missing in %v", key, typ)
+		}
+		items, err := p.parse(it, namespace)
+		if err != nil {
+			return nil, err
+		}
+		s.items = items
+		return s, nil
+	default:
+		return p.parse(typ, namespace)
+	}
+
+	// named types
+	name, _ := m["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("%v must have a name", typ)
+	}
+	if ns, ok := m["namespace"].(string); ok && !strings.Contains(name, ".") {
+		namespace = ns
+	}
+	s.name = p.fullName(name, namespace)
+	if i := strings.LastIndex(s.name, "."); i >= 0 {
+		namespace = s.name[:i]
+	}
+	if _, ok := p.names[s.name]; ok {
+		return nil, fmt.Errorf("%v is defined more than once", s.name)
+	}
+	p.names[s.name] = s
+
+	switch s.typ {
+	case avroRecord:
+		fs, ok := m["fields"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("record %v must have fields", s.name)
+		}
+		for _, f := range fs {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid field in record %v", s.name)
+			}
+			fname, _ := fm["name"].(string)
+			if fname == "" {
+				return nil, fmt.Errorf("a field of record %v doesn't have a name", s.name)
+			}
+			fs, err := p.parse(fm["type"], namespace)
+			if err != nil {
+				return nil, fmt.Errorf("field %v of record %v: %v", fname, s.name, err)
+			}
+			field := &avroField{
+				name:   fname,
+				schema: fs,
+			}
+			if as, ok := fm["aliases"].([]interface{}); ok {
+				for _, a := range as {
+					if a, ok := a.(string); ok {
+						field.aliases = append(field.aliases, a)
+					}
+				}
+			}
+			if d, ok := fm["default"]; ok {
+				dv, err := avroDefaultValue(fs, d)
+				if err != nil {
+					return nil, fmt.Errorf("field %v of record %v has an invalid default: %v", fname, s.name, err)
+				}
+				field.def = dv
+				field.hasDefault = true
+			}
+			s.fields = append(s.fields, field)
+		}
+
+	case avroEnum:
+		syms, ok := m["symbols"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("enum %v must have symbols", s.name)
+		}
+		for _, sym := range syms {
+			str, ok := sym.(string)
+			if !ok {
+				return nil, fmt.Errorf("enum %v has an invalid symbol: %v", s.name, sym)
+			}
+			s.symbols = append(s.symbols, str)
+		}
+
+	case avroFixed:
+		n, ok := m["size"].(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("fixed %v must have a size", s.name)
+		}
+		size, err := n.Int64()
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("fixed %v has an invalid size: %v", s.name, n)
+		}
+		s.size = int(size)
+	}
+	return s, nil
+}
+
+// avroDefaultValue converts a default value written in JSON to a value of
+// the schema. The default value of a union corresponds to its first branch.
+func avroDefaultValue(s *avroSchema, v interface{}) (data.Value, error) {
+	if s.typ == avroUnion {
+		if len(s.branches) == 0 {
+			return nil, errors.New("a union must have at least one branch")
+		}
+		return avroDefaultValue(s.branches[0], v)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		if s.typ == avroNull {
+			return data.Null{}, nil
+		}
+	case bool:
+		if s.typ == avroBoolean {
+			return data.Bool(v), nil
+		}
+	case json.Number:
+		switch s.typ {
+		case avroInt, avroLong:
+			i, err := v.Int64()
+			if err != nil {
+				return nil, err
+			}
+			return avroLogicalValue(s, i), nil
+		case avroFloat, avroDouble:
+			f, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			return data.Float(f), nil
+		}
+	case string:
+		switch s.typ {
+		case avroString:
+			return data.String(v), nil
+		case avroEnum:
+			return data.String(v), nil
+		case avroBytes, avroFixed:
+			// Bytes are written as a string whose code points are 0-255.
+			b := make([]byte, 0, len(v))
+			for _, r := range v {
+				if r > 255 {
+					return nil, errors.New("invalid bytes")
+				}
+				b = append(b, byte(r))
+			}
+			return data.Blob(b), nil
+		}
+	case []interface{}:
+		if s.typ == avroArray {
+			a := make(data.Array, len(v))
+			for i, e := range v {
+				ev, err := avroDefaultValue(s.items, e)
+				if err != nil {
+					return nil, err
+				}
+				a[i] = ev
+			}
+			return a, nil
+		}
+	case map[string]interface{}:
+		switch s.typ {
+		case avroMap:
+			m := make(data.Map, len(v))
+			for k, e := range v {
+				ev, err := avroDefaultValue(s.items, e)
+				if err != nil {
+					return nil, err
+				}
+				m[k] = ev
+			}
+			return m, nil
+		case avroRecord:
+			m := make(data.Map, len(s.fields))
+			for _, f := range s.fields {
+				e, ok := v[f.name]
+				if !ok {
+					if !f.hasDefault {
+						return nil, fmt.Errorf("field %v is missing", f.name)
+					}
+					m[f.name] = f.def
+					continue
+				}
+				ev, err := avroDefaultValue(f.schema, e)
+				if err != nil {
+					return nil, err
+				}
+				m[f.name] = ev
+			}
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%v doesn't match the type", v)
+}
+
+// avroLogicalValue converts an int or a long to a value of its logical type.
+func avroLogicalValue(s *avroSchema, i int64) data.Value {
+	switch s.logical {
+	case "timestamp-millis":
+		return data.Timestamp(time.Unix(i/1000, i%1000*int64(time.Millisecond)).UTC())
+	case "timestamp-micros":
+		return data.Timestamp(time.Unix(i/1000000, i%1000000*int64(time.Microsecond)).UTC())
+	}
+	return data.Int(i)
+}
+
+type avroReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+func avroReadLong(r avroReader) (int64, error) {
+	return binary.ReadVarint(r) // zigzag encoding
+}
+
+func avroReadBytes(r avroReader) ([]byte, error) {
+	n, err := avroReadLong(r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > maxDelimitedRecordSize {
+		return nil, fmt.Errorf("invalid length: %v", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// avroReadBlocks reads blocks of an array or a map and calls f for each item.
+func avroReadBlocks(r avroReader, f func() error) error {
+	for {
+		n, err := avroReadLong(r)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if n < 0 {
+			n = -n
+			if _, err := avroReadLong(r); err != nil { // size of the block
+				return err
+			}
+		}
+		for i := int64(0); i < n; i++ {
+			if err := f(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func avroDecode(s *avroSchema, r avroReader) (data.Value, error) {
+	switch s.typ {
+	case avroNull:
+		return data.Null{}, nil
+	case avroBoolean:
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		return data.Bool(b != 0), nil
+	case avroInt, avroLong:
+		i, err := avroReadLong(r)
+		if err != nil {
+			return nil, err
+		}
+		return avroLogicalValue(s, i), nil
+	case avroFloat:
+		var b [4]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		return data.Float(math.Float32frombits(binary.LittleEndian.Uint32(b[:]))), nil
+	case avroDouble:
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		return data.Float(math.Float64frombits(binary.LittleEndian.Uint64(b[:]))), nil
+	case avroBytes:
+		b, err := avroReadBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		return data.Blob(b), nil
+	case avroString:
+		b, err := avroReadBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		return data.String(b), nil
+	case avroFixed:
+		b := make([]byte, s.size)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return data.Blob(b), nil
+	case avroEnum:
+		i, err := avroReadLong(r)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.symbols)) {
+			return nil, fmt.Errorf("invalid symbol index of enum %v: %v", s.name, i)
+		}
+		return data.String(s.symbols[i]), nil
+	case avroUnion:
+		i, err := avroReadLong(r)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.branches)) {
+			return nil, fmt.Errorf("invalid branch index of a union: %v", i)
+		}
+		return avroDecode(s.branches[i], r)
+	case avroRecord:
+		m := make(data.Map, len(s.fields))
+		for _, f := range s.fields {
+			v, err := avroDecode(f.schema, r)
+			if err != nil {
+				return nil, err
+			}
+			m[f.name] = v
+		}
+		return m, nil
+	case avroArray:
+		a := data.Array{}
+		err := avroReadBlocks(r, func() error {
+			v, err := avroDecode(s.items, r)
+			if err != nil {
+				return err
+			}
+			a = append(a, v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	case avroMap:
+		m := data.Map{}
+		err := avroReadBlocks(r, func() error {
+			k, err := avroReadBytes(r)
+			if err != nil {
+				return err
+			}
+			v, err := avroDecode(s.items, r)
+			if err != nil {
+				return err
+			}
+			m[string(k)] = v
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported type: %v", s.typ)
+}
+
+func avroWriteLong(w *bytes.Buffer, i int64) {
+	var b [binary.MaxVarintLen64]byte
+	w.Write(b[:binary.PutVarint(b[:], i)])
+}
+
+func avroWriteBytes(w *bytes.Buffer, b []byte) {
+	avroWriteLong(w, int64(len(b)))
+	w.Write(b)
+}
+
+func avroEncode(s *avroSchema, v data.Value, w *bytes.Buffer) error {
+	switch s.typ {
+	case avroNull:
+		if v.Type() != data.TypeNull {
+			return fmt.Errorf("%v isn't null", v)
+		}
+	case avroBoolean:
+		b, err := data.AsBool(v)
+		if err != nil {
+			return err
+		}
+		if b {
+			w.WriteByte(1)
+		} else {
+			w.WriteByte(0)
+		}
+	case avroInt, avroLong:
+		var i int64
+		if t, err := data.AsTimestamp(v); err == nil && s.logical == "timestamp-millis" {
+			i = t.UnixNano() / int64(time.Millisecond)
+		} else if err == nil && s.logical == "timestamp-micros" {
+			i = t.UnixNano() / int64(time.Microsecond)
+		} else {
+			var err error
+			if i, err = data.AsInt(v); err != nil {
+				return err
+			}
+		}
+		if s.typ == avroInt && (i < math.MinInt32 || i > math.MaxInt32) {
+			return fmt.Errorf("%v is out of the range of int", i)
+		}
+		avroWriteLong(w, i)
+	case avroFloat:
+		f, err := data.AsFloat(v)
+		if err != nil {
+			return err
+		}
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(float32(f)))
+		w.Write(b[:])
+	case avroDouble:
+		f, err := data.AsFloat(v)
+		if err != nil {
+			return err
+		}
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+		w.Write(b[:])
+	case avroBytes:
+		b, err := data.AsBlob(v)
+		if err != nil {
+			return err
+		}
+		avroWriteBytes(w, b)
+	case avroString:
+		str, err := data.AsString(v)
+		if err != nil {
+			return err
+		}
+		avroWriteBytes(w, []byte(str))
+	case avroFixed:
+		b, err := data.AsBlob(v)
+		if err != nil {
+			return err
+		}
+		if len(b) != s.size {
+			return fmt.Errorf("fixed %v must have %v bytes", s.name, s.size)
+		}
+		w.Write(b)
+	case avroEnum:
+		str, err := data.AsString(v)
+		if err != nil {
+			return err
+		}
+		for i, sym := range s.symbols {
+			if sym == str {
+				avroWriteLong(w, int64(i))
+				return nil
+			}
+		}
+		return fmt.Errorf("enum %v doesn't have the symbol %v", s.name, str)
+	case avroUnion:
+		for i, b := range s.branches {
+			if avroMatches(b, v) {
+				avroWriteLong(w, int64(i))
+				return avroEncode(b, v, w)
+			}
+		}
+		return fmt.Errorf("%v doesn't match any branch of the union", v)
+	case avroRecord:
+		m, err := data.AsMap(v)
+		if err != nil {
+			return err
+		}
+		for _, f := range s.fields {
+			fv, ok := m[f.name]
+			if !ok {
+				switch {
+				case f.hasDefault:
+					fv = f.def
+				case avroMatches(f.schema, data.Null{}):
+					fv = data.Null{}
+				default:
+					return fmt.Errorf("field %v of record %v is missing", f.name, s.name)
+				}
+			}
+			if err := avroEncode(f.schema, fv, w); err != nil {
+				return fmt.Errorf("field %v of record %v: %v", f.name, s.name, err)
+			}
+		}
+	case avroArray:
+		a, err := data.AsArray(v)
+		if err != nil {
+			return err
+		}
+		if len(a) > 0 {
+			avroWriteLong(w, int64(len(a)))
+			for _, e := range a {
+				if err := avroEncode(s.items, e, w); err != nil {
+					return err
+				}
+			}
+		}
+		avroWriteLong(w, 0)
+	case avroMap:
+		m, err := data.AsMap(v)
+		if err != nil {
+			return err
+		}
+		if len(m) > 0 {
+			avroWriteLong(w, int64(len(m)))
+			for k, e := range m {
+				avroWriteBytes(w, []byte(k))
+				if err := avroEncode(s.items, e, w); err != nil {
+					return err
+				}
+			}
+		}
+		avroWriteLong(w, 0)
+	default:
+		return fmt.Errorf("unsupported type: %v", s.typ)
+	}
+	return nil
+}
+
+// avroMatches returns true when the value can be a value of the schema. It's
+// used to select a branch of a union.
+func avroMatches(s *avroSchema, v data.Value) bool {
+	if s.typ == avroUnion {
+		for _, b := range s.branches {
+			if avroMatches(b, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch v.Type() {
+	case data.TypeNull:
+		return s.typ == avroNull
+	case data.TypeBool:
+		return s.typ == avroBoolean
+	case data.TypeInt:
+		return s.typ == avroInt || s.typ == avroLong || s.typ == avroFloat || s.typ == avroDouble
+	case data.TypeFloat:
+		return s.typ == avroFloat || s.typ == avroDouble
+	case data.TypeString:
+		if s.typ == avroEnum {
+			str, _ := data.AsString(v)
+			for _, sym := range s.symbols {
+				if sym == str {
+					return true
+				}
+			}
+			return false
+		}
+		return s.typ == avroString
+	case data.TypeBlob:
+		if s.typ == avroFixed {
+			b, _ := data.AsBlob(v)
+			return len(b) == s.size
+		}
+		return s.typ == avroBytes
+	case data.TypeTimestamp:
+		return (s.typ == avroLong || s.typ == avroInt) && strings.HasPrefix(s.logical, "timestamp-")
+	case data.TypeArray:
+		return s.typ == avroArray
+	case data.TypeMap:
+		return s.typ == avroRecord || s.typ == avroMap
+	}
+	return false
+}
+
+func avroResolve(s *avroSchema, v data.Value) (data.Value, error) {
+	switch s.typ {
+	case avroUnion:
+		for _, b := range s.branches {
+			if avroMatches(b, v) {
+				return avroResolve(b, v)
+			}
+		}
+		// bytes and strings can be promoted to each other
+		for _, b := range s.branches {
+			if r, err := avroResolve(b, v); err == nil {
+				return r, nil
+			}
+		}
+		return nil, fmt.Errorf("%v doesn't match any branch of the union", v)
+	case avroFloat, avroDouble:
+		if v.Type() == data.TypeInt {
+			i, _ := data.AsInt(v)
+			return data.Float(i), nil
+		}
+	case avroString:
+		if v.Type() == data.TypeBlob {
+			b, _ := data.AsBlob(v)
+			return data.String(b), nil
+		}
+	case avroBytes:
+		if v.Type() == data.TypeString {
+			str, _ := data.AsString(v)
+			return data.Blob(str), nil
+		}
+	case avroRecord:
+		m, err := data.AsMap(v)
+		if err != nil {
+			return nil, err
+		}
+		res := make(data.Map, len(s.fields))
+		for _, f := range s.fields {
+			fv, ok := m[f.name]
+			for _, a := range f.aliases {
+				if ok {
+					break
+				}
+				fv, ok = m[a]
+			}
+			if !ok {
+				if !f.hasDefault {
+					return nil, fmt.Errorf("field %v of record %v is missing and doesn't have a default", f.name, s.name)
+				}
+				res[f.name] = f.def
+				continue
+			}
+			rv, err := avroResolve(f.schema, fv)
+			if err != nil {
+				return nil, fmt.Errorf("field %v of record %v: %v", f.name, s.name, err)
+			}
+			res[f.name] = rv
+		}
+		return res, nil
+	case avroArray:
+		a, err := data.AsArray(v)
+		if err != nil {
+			return nil, err
+		}
+		res := make(data.Array, len(a))
+		for i, e := range a {
+			if res[i], err = avroResolve(s.items, e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case avroMap:
+		m, err := data.AsMap(v)
+		if err != nil {
+			return nil, err
+		}
+		res := make(data.Map, len(m))
+		for k, e := range m {
+			if res[k], err = avroResolve(s.items, e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	if !avroMatches(s, v) {
+		return nil, fmt.Errorf("%v doesn't match the type", v)
+	}
+	return v, nil
+}
@@ -0,0 +1,229 @@
+package bql
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// AvroSchemaRegistry is a client of a schema registry compatible with
+// Confluent Schema Registry. It caches schemas so that each schema is only
+// fetched once.
+type AvroSchemaRegistry struct {
+	url    string
+	client *http.Client
+
+	m       sync.RWMutex
+	schemas map[int32]*AvroSchema
+	ids     map[string]int32 // subject + "\x00" + schema
+}
+
+// NewAvroSchemaRegistry creates a client of the schema registry at the URL
+// such as "http://localhost:8081".
+func NewAvroSchemaRegistry(registryURL string) *AvroSchemaRegistry {
+	return &AvroSchemaRegistry{
+		url: strings.TrimRight(registryURL, "/"),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		schemas: map[int32]*AvroSchema{},
+		ids:     map[string]int32{},
+	}
+}
+
+// Schema returns the schema having the ID.
+func (r *AvroSchemaRegistry) Schema(id int32) (*AvroSchema, error) {
+	r.m.RLock()
+	s, ok := r.schemas[id]
+	r.m.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	res := struct {
+		Schema string `json:"schema"`
+	}{}
+	if err := r.do("GET", fmt.Sprintf("/schemas/ids/%v", id), nil, &res); err != nil {
+		return nil, fmt.Errorf("cannot fetch the schema %v: %v", id, err)
+	}
+	s, err := ParseAvroSchema(res.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.schemas[id] = s
+	return s, nil
+}
+
+// Register registers the schema under the subject and returns its ID. When
+// the schema is already registered, the registry returns the existing ID.
+func (r *AvroSchemaRegistry) Register(subject string, s *AvroSchema) (int32, error) {
+	key := subject + "\x00" + s.String()
+	r.m.RLock()
+	id, ok := r.ids[key]
+	r.m.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	req := map[string]string{
+		"schema": s.String(),
+	}
+	res := struct {
+		ID int32 `json:"id"`
+	}{}
+	if err := r.do("POST", fmt.Sprintf("/subjects/%v/versions", url.PathEscape(subject)), req, &res); err != nil {
+		return 0, fmt.Errorf("cannot register the schema to %v: %v", subject, err)
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.ids[key] = res.ID
+	r.schemas[res.ID] = s
+	return res.ID, nil
+}
+
+func (r *AvroSchemaRegistry) do(method, path string, body interface{}, res interface{}) error {
+	var b io.Reader
+	if body != nil {
+		js, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		b = bytes.NewReader(js)
+	}
+	req, err := http.NewRequest(method, r.url+path, b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json, application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	js, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the registry returned status %v: %v", resp.StatusCode, strings.TrimSpace(string(js)))
+	}
+	return json.Unmarshal(js, res)
+}
+
+// avroMagicByte is the first byte of a message in the Confluent wire format,
+// which is followed by the 4-byte schema ID in big endian and the value
+// encoded in the Avro binary encoding.
+const avroMagicByte = 0
+
+// AvroDecoder decodes messages in the Confluent wire format. The schema of
+// each message is looked up from the registry by the ID embedded in the
+// message.
+type AvroDecoder struct {
+	registry *AvroSchemaRegistry
+	reader   *AvroSchema
+}
+
+// NewAvroDecoder creates an AvroDecoder. When reader isn't nil, decoded
+// values are resolved to the schema so that messages written with older or
+// newer schemas have the same set of fields.
+func NewAvroDecoder(registry *AvroSchemaRegistry, reader *AvroSchema) *AvroDecoder {
+	return &AvroDecoder{
+		registry: registry,
+		reader:   reader,
+	}
+}
+
+// Decode decodes a message whose value is a record.
+func (d *AvroDecoder) Decode(b []byte) (data.Map, error) {
+	if len(b) < 5 || b[0] != avroMagicByte {
+		return nil, errors.New("the message isn't in the Confluent wire format")
+	}
+	s, err := d.registry.Schema(int32(binary.BigEndian.Uint32(b[1:5])))
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.Decode(b[5:])
+	if err != nil {
+		return nil, err
+	}
+	if d.reader != nil && d.reader.String() != s.String() {
+		if v, err = d.reader.Resolve(v); err != nil {
+			return nil, err
+		}
+	}
+	return data.AsMap(v)
+}
+
+// AvroEncoder encodes values in the Confluent wire format. The schema is
+// registered to the registry when the first value is encoded.
+type AvroEncoder struct {
+	registry *AvroSchemaRegistry
+	subject  string
+	schema   *AvroSchema
+}
+
+// NewAvroEncoder creates an AvroEncoder encoding values with the schema
+// registered under the subject, e.g. "<topic>-value".
+func NewAvroEncoder(registry *AvroSchemaRegistry, subject string, schema *AvroSchema) *AvroEncoder {
+	return &AvroEncoder{
+		registry: registry,
+		subject:  subject,
+		schema:   schema,
+	}
+}
+
+// Encode encodes a map as a record.
+func (e *AvroEncoder) Encode(m data.Map) ([]byte, error) {
+	id, err := e.registry.Register(e.subject, e.schema)
+	if err != nil {
+		return nil, err
+	}
+	b, err := e.schema.Encode(m)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, 5, 5+len(b))
+	res[0] = avroMagicByte
+	binary.BigEndian.PutUint32(res[1:5], uint32(id))
+	return append(res, b...), nil
+}
+
+// newAvroDecoderCreator returns a recordDecoderCreator decoding a stream of
+// length-delimited messages in the Confluent wire format.
+func newAvroDecoderCreator(params data.Map) (recordDecoderCreator, error) {
+	c := &struct {
+		SchemaRegistry string `bql:",required"`
+		Schema         string
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	var reader *AvroSchema
+	if c.Schema != "" {
+		var err error
+		if reader, err = ParseAvroSchema(c.Schema); err != nil {
+			return nil, err
+		}
+	}
+	d := NewAvroDecoder(NewAvroSchemaRegistry(c.SchemaRegistry), reader)
+	return newDelimitedDecoderCreator("avro", d.Decode), nil
+}
@@ -0,0 +1,280 @@
+package bql
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const testAvroSchemaV1 = `{
+	"type": "record",
+	"name": "Reading",
+	"namespace": "sbtest",
+	"fields": [
+		{"name": "sensor_id", "type": "string"},
+		{"name": "value", "type": "int"},
+		{"name": "unit", "type": {"type": "enum", "name": "Unit", "symbols": ["CELSIUS", "FAHRENHEIT"]}},
+		{"name": "ts", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "samples", "type": {"type": "array", "items": "double"}},
+		{"name": "labels", "type": {"type": "map", "values": "string"}},
+		{"name": "note", "type": ["null", "string"], "default": null},
+		{"name": "location", "type": ["null", {
+			"type": "record", "name": "Location",
+			"fields": [{"name": "lat", "type": "double"}, {"name": "lon", "type": "double"}]
+		}]},
+		{"name": "raw", "type": {"type": "fixed", "name": "Raw", "size": 2}}
+	]
+}`
+
+// testAvroSchemaV2 removes some fields, promotes value to double, and adds
+// a field having a default value.
+const testAvroSchemaV2 = `{
+	"type": "record",
+	"name": "Reading",
+	"namespace": "sbtest",
+	"fields": [
+		{"name": "id", "type": "string", "aliases": ["sensor_id"]},
+		{"name": "value", "type": "double"},
+		{"name": "unit", "type": {"type": "enum", "name": "Unit", "symbols": ["CELSIUS", "FAHRENHEIT"]}},
+		{"name": "status", "type": "string", "default": "ok"}
+	]
+}`
+
+type testAvroSchemaRegistry struct {
+	m       sync.Mutex
+	schemas []string
+	fetches int
+}
+
+func (r *testAvroSchemaRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	switch {
+	case req.Method == "GET" && strings.HasPrefix(req.URL.Path, "/schemas/ids/"):
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(req.URL.Path, "/schemas/ids/"), "%d", &id)
+		if id <= 0 || id > len(r.schemas) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error_code":40403,"message":"Schema not found"}`)
+			return
+		}
+		r.fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{"schema": r.schemas[id-1]})
+	case req.Method == "POST" && strings.HasPrefix(req.URL.Path, "/subjects/"):
+		var body map[string]string
+		json.NewDecoder(req.Body).Decode(&body)
+		id := 0
+		for i, s := range r.schemas {
+			if s == body["schema"] {
+				id = i + 1
+			}
+		}
+		if id == 0 {
+			r.schemas = append(r.schemas, body["schema"])
+			id = len(r.schemas)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestAvroSchema(t *testing.T) {
+	ts := time.Date(2015, time.April, 10, 10, 23, 0, 123000000, time.UTC)
+	reading := data.Map{
+		"sensor_id": data.String("s1"),
+		"value":     data.Int(21),
+		"unit":      data.String("FAHRENHEIT"),
+		"ts":        data.Timestamp(ts),
+		"samples":   data.Array{data.Float(1.5), data.Float(-2)},
+		"labels":    data.Map{"room": data.String("a")},
+		"note":      data.Null{},
+		"location":  data.Map{"lat": data.Float(35.5), "lon": data.Float(139.5)},
+		"raw":       data.Blob{1, 2},
+	}
+
+	Convey("Given an avro schema", t, func() {
+		s, err := ParseAvroSchema(testAvroSchemaV1)
+		So(err, ShouldBeNil)
+
+		Convey("When encoding and decoding a value", func() {
+			b, err := s.Encode(reading)
+			So(err, ShouldBeNil)
+			v, err := s.Decode(b)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be the same as the original value", func() {
+				So(v, ShouldResemble, reading)
+			})
+		})
+
+		Convey("When encoding a value without a nullable field", func() {
+			m := reading.Copy()
+			delete(m, "note")
+			delete(m, "location")
+			b, err := s.Encode(m)
+			So(err, ShouldBeNil)
+			v, err := s.Decode(b)
+			So(err, ShouldBeNil)
+
+			Convey("Then the field should be null", func() {
+				mv, _ := data.AsMap(v)
+				So(mv["note"], ShouldResemble, data.Null{})
+				So(mv["location"], ShouldResemble, data.Null{})
+			})
+		})
+
+		Convey("When encoding an invalid value", func() {
+			m := reading.Copy()
+			m["unit"] = data.String("KELVIN")
+			_, err := s.Encode(m)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When decoding a truncated value", func() {
+			b, err := s.Encode(reading)
+			So(err, ShouldBeNil)
+			_, err = s.Decode(b[:len(b)-1])
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When resolving a value to a newer schema", func() {
+			s2, err := ParseAvroSchema(testAvroSchemaV2)
+			So(err, ShouldBeNil)
+			v, err := s2.Resolve(reading)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should follow the new schema", func() {
+				So(v, ShouldResemble, data.Map{
+					"id":     data.String("s1"),
+					"value":  data.Float(21),
+					"unit":   data.String("FAHRENHEIT"),
+					"status": data.String("ok"),
+				})
+			})
+		})
+	})
+
+	Convey("Given invalid avro schemas", t, func() {
+		for _, s := range []string{
+			`{"type": "record", "name": "r"}`,
+			`{"type": "record", "name": "r", "fields": [{"name": "f", "type": "undefined"}]}`,
+			`{"type": "enum", "name": "e"}`,
+			`{"type": "array"}`,
+			`{"type": "record", "name": "r", "fields": [{"name": "f", "type": "int", "default": "a"}]}`,
+			`[["null"]]`,
+		} {
+			Convey("Then parsing "+s+" should fail", func() {
+				_, err := ParseAvroSchema(s)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
+
+func TestAvroCodec(t *testing.T) {
+	Convey("Given a schema registry", t, func() {
+		reg := &testAvroSchemaRegistry{}
+		server := httptest.NewServer(reg)
+		Reset(server.Close)
+
+		s1, err := ParseAvroSchema(`{"type": "record", "name": "r", "fields": [
+			{"name": "a", "type": "int"}, {"name": "b", "type": "string"}]}`)
+		So(err, ShouldBeNil)
+		s2, err := ParseAvroSchema(`{"type": "record", "name": "r", "fields": [
+			{"name": "a", "type": "long"}, {"name": "c", "type": "boolean", "default": true}]}`)
+		So(err, ShouldBeNil)
+
+		enc := NewAvroEncoder(NewAvroSchemaRegistry(server.URL), "readings-value", s1)
+		b, err := enc.Encode(data.Map{"a": data.Int(1), "b": data.String("x")})
+		So(err, ShouldBeNil)
+
+		Convey("When encoding a value", func() {
+			Convey("Then it should be in the Confluent wire format", func() {
+				So(b[0], ShouldEqual, 0)
+				So(binary.BigEndian.Uint32(b[1:5]), ShouldEqual, 1)
+				So(reg.schemas, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When decoding messages", func() {
+			dec := NewAvroDecoder(NewAvroSchemaRegistry(server.URL), nil)
+			for i := 0; i < 3; i++ {
+				m, err := dec.Decode(b)
+				So(err, ShouldBeNil)
+				So(m, ShouldResemble, data.Map{"a": data.Int(1), "b": data.String("x")})
+			}
+
+			Convey("Then the schema should be fetched only once", func() {
+				So(reg.fetches, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When decoding a message with a reader schema", func() {
+			dec := NewAvroDecoder(NewAvroSchemaRegistry(server.URL), s2)
+			m, err := dec.Decode(b)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be resolved to the reader schema", func() {
+				So(m, ShouldResemble, data.Map{"a": data.Int(1), "c": data.True})
+			})
+		})
+
+		Convey("When decoding a message having an unknown schema ID", func() {
+			dec := NewAvroDecoder(NewAvroSchemaRegistry(server.URL), nil)
+			_, err := dec.Decode([]byte{0, 0, 0, 0, 9, 2})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When reading a file of length-delimited messages by file source", func() {
+			dir, err := ioutil.TempDir("", "sbtest_bql_avro")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				os.RemoveAll(dir)
+			})
+			var input []byte
+			for _, msg := range [][]byte{b, {1, 2, 3, 4, 5}, b} {
+				input = binary.AppendUvarint(input, uint64(len(msg)))
+				input = append(input, msg...)
+			}
+			path := dir + "/readings.bin"
+			So(ioutil.WriteFile(path, input, 0644), ShouldBeNil)
+
+			ctx := core.NewContext(nil)
+			w := &testFileWriter{}
+			w.c = sync.NewCond(&w.m)
+			src, err := createFileSource(ctx, &IOParams{}, data.Map{
+				"path":            data.String(path),
+				"format":          data.String("avro"),
+				"schema_registry": data.String(server.URL),
+			})
+			So(err, ShouldBeNil)
+			So(src.GenerateStream(ctx, w), ShouldBeNil)
+
+			Convey("Then it should emit valid messages", func() {
+				So(w.cnt, ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,532 @@
+package bql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// defaultBackfillBufferSize is the maximum number of live tuples buffered
+	// while a backfill is replaying history when BackfillConfig.BufferSize
+	// isn't given.
+	defaultBackfillBufferSize = 10000
+)
+
+var (
+	// backfillPollInterval is the interval at which a backfill checks if
+	// the source has generated all tuples in its history.
+	backfillPollInterval = 100 * time.Millisecond
+)
+
+// BackfillConfig has parameters of a backfill.
+type BackfillConfig struct {
+	// Since is the lower bound (inclusive) of timestamps of tuples replayed.
+	// When it's zero, tuples are replayed from the beginning of the history.
+	Since time.Time
+
+	// Until is the upper bound (exclusive) of timestamps of tuples replayed.
+	// Live tuples having timestamps before Until are discarded so that the
+	// stream doesn't receive the same tuple twice. When it's zero, the time
+	// at which the backfill starts is used.
+	Until time.Time
+
+	// BufferSize is the maximum number of live tuples buffered while history
+	// is being replayed. When the buffer is full, the oldest tuple is dropped.
+	// When it's 0, defaultBackfillBufferSize is used.
+	BufferSize int
+}
+
+// backfill has the progress of a backfill of a stream.
+type backfill struct {
+	stream    string
+	source    string
+	since     time.Time
+	until     time.Time
+	startedAt time.Time
+	replay    *backfillSource
+	gate      *backfillGate
+
+	m           sync.Mutex
+	state       string
+	completedAt time.Time
+	err         error
+}
+
+// Backfill creates a stream from the CREATE STREAM statement and feeds it
+// tuples in the history of its input source before switching to live tuples.
+// The statement must read from exactly one rewindable source. The history is
+// replayed by another instance of the source created from the statement which
+// created the source, and only tuples having timestamps in [Since, Until) are
+// passed to the stream. Live tuples emitted while the history is being
+// replayed are buffered and passed to the stream after the replay has
+// completed. The progress can be obtained by BackfillStatus.
+func (tb *TopologyBuilder) Backfill(stmt *parser.CreateStreamAsSelectStmt, conf *BackfillConfig) (core.Node, error) {
+	if conf == nil {
+		conf = &BackfillConfig{}
+	}
+	until := conf.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	if !conf.Since.IsZero() && !conf.Since.Before(until) {
+		return nil, fmt.Errorf("the time range of the backfill is empty: [%v, %v)", conf.Since, until)
+	}
+	bufSize := conf.BufferSize
+	if bufSize == 0 {
+		bufSize = defaultBackfillBufferSize
+	}
+	if bufSize < 0 {
+		return nil, fmt.Errorf("the buffer size must be positive: %v", bufSize)
+	}
+
+	live, err := tb.backfillInput(stmt)
+	if err != nil {
+		return nil, err
+	}
+	src, extractor, err := tb.recreateSource(live.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	id := topologyBuilderNextTemporaryID()
+	b := &backfill{
+		stream:    string(stmt.Name),
+		source:    live.Name(),
+		since:     conf.Since,
+		until:     until,
+		startedAt: time.Now(),
+		state:     "running",
+		gate: &backfillGate{
+			until:   until,
+			maxSize: bufSize,
+		},
+	}
+	b.replay = &backfillSource{
+		b:         b,
+		source:    src,
+		extractor: extractor,
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+
+	replayName := fmt.Sprintf("sensorbee_backfill_%v", id)
+	sn, err := tb.topology.AddSource(replayName, b.replay, &core.SourceConfig{
+		PausedOnStartup: true,
+	})
+	if err != nil {
+		// The source hasn't been run yet.
+		src.Stop(tb.topology.Context())
+		return nil, err
+	}
+	gateName := fmt.Sprintf("sensorbee_backfill_gate_%v", id)
+	gn, err := tb.topology.AddBox(gateName, b.gate, nil)
+	if err != nil {
+		tb.topology.Remove(replayName)
+		return nil, err
+	}
+	if err := gn.Input(live.Name(), nil); err != nil {
+		tb.topology.Remove(replayName)
+		tb.topology.Remove(gateName)
+		return nil, err
+	}
+	n, err := tb.createStreamAsSelectStmt(stmt, map[string][]string{
+		strings.ToLower(live.Name()): {replayName, gateName},
+	}, false)
+	if err != nil {
+		tb.topology.Remove(replayName)
+		tb.topology.Remove(gateName)
+		return nil, err
+	}
+	sn.StopOnDisconnect()
+	sn.RemoveOnStop()
+	gn.StopOnDisconnect(core.Inbound | core.Outbound)
+	gn.RemoveOnStop()
+
+	tb.stmtMutex.Lock()
+	tb.statements[n.Name()] = stmt.String()
+	tb.stmtMutex.Unlock()
+
+	tb.backfillMutex.Lock()
+	tb.backfills[strings.ToLower(n.Name())] = b
+	tb.backfillMutex.Unlock()
+
+	if err := sn.Resume(); err != nil {
+		tb.topology.Remove(n.Name())
+		return nil, err
+	}
+	return n, nil
+}
+
+// backfillInput returns the rewindable source from which the statement reads.
+func (tb *TopologyBuilder) backfillInput(stmt *parser.CreateStreamAsSelectStmt) (core.SourceNode, error) {
+	var src core.SourceNode
+	for _, rel := range stmt.Select.Relations {
+		if rel.Type != parser.ActualStream {
+			continue
+		}
+		n, err := tb.topology.Node(rel.Name)
+		if err != nil {
+			return nil, err
+		}
+		sn, ok := n.(core.SourceNode)
+		if !ok {
+			return nil, fmt.Errorf("'%v' is not a source and cannot be backfilled", rel.Name)
+		}
+		if _, ok := sn.Source().(core.RewindableSource); !ok {
+			return nil, fmt.Errorf("the source '%v' is not rewindable", rel.Name)
+		}
+		if src != nil && src.Name() != sn.Name() {
+			return nil, errors.New("a backfill can only read from one source")
+		}
+		src = sn
+	}
+	if src == nil {
+		return nil, errors.New("a backfill requires a rewindable source")
+	}
+	return src, nil
+}
+
+// recreateSource creates a new instance of the source from the statement
+// which created it. It also returns the timestamp extractor of the source.
+func (tb *TopologyBuilder) recreateSource(name string) (core.Source, *core.TimestampExtractor, error) {
+	tb.stmtMutex.Lock()
+	stmtStr, ok := tb.statements[name]
+	tb.stmtMutex.Unlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("the statement which created the source '%v' is unknown", name)
+	}
+	s, _, err := parser.New().ParseStmt(stmtStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	stmt, ok := s.(parser.CreateSourceStmt)
+	if !ok {
+		return nil, nil, fmt.Errorf("the source '%v' wasn't created by CREATE SOURCE", name)
+	}
+
+	extractor, err := timestampExtractorFromTimestampBy(stmt.TimestampByAST)
+	if err != nil {
+		return nil, nil, err
+	}
+	creator, err := tb.SourceCreators.Lookup(string(stmt.Type))
+	if err != nil {
+		return nil, nil, err
+	}
+	params, err := tb.mkParamsMap(stmt.Params)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(params, protectedParam)
+	delete(params, labelsParam)
+	if params, err = validateCreatorParams(creator, string(stmt.Type), params); err != nil {
+		return nil, nil, err
+	}
+	src, err := tb.createSource(creator, &stmt, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, extractor, nil
+}
+
+// BackfillStatus returns the progress of the backfill of the stream:
+//
+//   - stream: the name of the stream
+//   - source: the name of the source replayed
+//   - state: "running", "completed", or "failed"
+//   - since, until: the time range of tuples replayed
+//   - num_replayed: the number of tuples in the history sent to the stream
+//   - num_skipped: the number of tuples in the history out of the range
+//   - last_timestamp: the timestamp of the last tuple replayed
+//   - num_buffered: the number of live tuples buffered during the replay
+//   - num_dropped: the number of live tuples dropped due to the buffer size
+//   - started_at, completed_at: when the backfill started and completed
+//   - error: the error which caused the failure
+//
+// It returns an error satisfying core.IsNotExist when the stream doesn't
+// exist or wasn't created by Backfill.
+func (tb *TopologyBuilder) BackfillStatus(stream string) (data.Map, error) {
+	tb.backfillMutex.Lock()
+	b, ok := tb.backfills[strings.ToLower(stream)]
+	tb.backfillMutex.Unlock()
+	if !ok {
+		return nil, core.NotExistError(fmt.Errorf("the stream '%v' isn't backfilled", stream))
+	}
+	if _, err := tb.topology.Box(b.stream); err != nil {
+		tb.backfillMutex.Lock()
+		if tb.backfills[strings.ToLower(stream)] == b {
+			delete(tb.backfills, strings.ToLower(stream))
+		}
+		tb.backfillMutex.Unlock()
+		return nil, err
+	}
+	return b.status(), nil
+}
+
+// BackfillStatuses returns progresses of all backfills. See BackfillStatus
+// for details of each progress.
+func (tb *TopologyBuilder) BackfillStatuses() []data.Map {
+	tb.backfillMutex.Lock()
+	names := make([]string, 0, len(tb.backfills))
+	for name := range tb.backfills {
+		names = append(names, name)
+	}
+	tb.backfillMutex.Unlock()
+
+	res := make([]data.Map, 0, len(names))
+	for _, name := range names {
+		if st, err := tb.BackfillStatus(name); err == nil {
+			res = append(res, st)
+		}
+	}
+	return res
+}
+
+func (b *backfill) complete(err error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.state != "running" {
+		return
+	}
+	b.completedAt = time.Now()
+	if err != nil {
+		b.state = "failed"
+		b.err = err
+	} else {
+		b.state = "completed"
+	}
+}
+
+func (b *backfill) status() data.Map {
+	replayed, skipped, last := b.replay.progress()
+	buffered, dropped := b.gate.progress()
+
+	b.m.Lock()
+	defer b.m.Unlock()
+	m := data.Map{
+		"stream":         data.String(b.stream),
+		"source":         data.String(b.source),
+		"state":          data.String(b.state),
+		"since":          data.Null{},
+		"until":          data.Timestamp(b.until),
+		"num_replayed":   data.Int(replayed),
+		"num_skipped":    data.Int(skipped),
+		"last_timestamp": data.Null{},
+		"num_buffered":   data.Int(buffered),
+		"num_dropped":    data.Int(dropped),
+		"started_at":     data.Timestamp(b.startedAt),
+		"completed_at":   data.Null{},
+	}
+	if !b.since.IsZero() {
+		m["since"] = data.Timestamp(b.since)
+	}
+	if !last.IsZero() {
+		m["last_timestamp"] = data.Timestamp(last)
+	}
+	if !b.completedAt.IsZero() {
+		m["completed_at"] = data.Timestamp(b.completedAt)
+	}
+	if b.err != nil {
+		m["error"] = data.String(b.err.Error())
+	}
+	return m
+}
+
+// backfillSource replays the history of a source. It stops when the source
+// generates a tuple whose timestamp isn't before the upper bound of the time
+// range, or when the source has generated all tuples in its history. Then,
+// it opens the gate of live tuples.
+type backfillSource struct {
+	b         *backfill
+	source    core.Source
+	extractor *core.TimestampExtractor
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	doneCh    chan struct{}
+	doneOnce  sync.Once
+
+	m        sync.Mutex
+	replayed int64
+	skipped  int64
+	last     time.Time
+}
+
+func (s *backfillSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- s.source.GenerateStream(ctx, core.WriterFunc(func(ctx *core.Context, t *core.Tuple) error {
+			return s.write(ctx, w, t)
+		}))
+	}()
+
+	ticker := time.NewTicker(backfillPollInterval)
+	defer ticker.Stop()
+	var err error
+loop:
+	for {
+		select {
+		case err = <-ch:
+			if err == core.ErrSourceStopped {
+				err = nil
+			}
+			break loop
+		case <-s.doneCh:
+			err = s.stopSource(ctx, ch)
+			break loop
+		case <-s.stopCh:
+			s.stopSource(ctx, ch)
+			s.b.complete(errors.New("the backfill has been stopped"))
+			return nil
+		case <-ticker.C:
+			if s.exhausted() {
+				s.finish()
+			}
+		}
+	}
+
+	s.b.complete(err)
+	if err != nil {
+		ctx.ErrLog(err).WithField("stream", s.b.stream).
+			Error("Cannot replay the history of the source, switching to live tuples")
+	}
+	s.b.gate.open(ctx)
+	return nil
+}
+
+func (s *backfillSource) write(ctx *core.Context, w core.Writer, t *core.Tuple) error {
+	select {
+	case <-s.doneCh:
+		return core.ErrSourceStopped
+	default:
+	}
+
+	if s.extractor != nil {
+		if err := s.extractor.Extract(t); err != nil {
+			return err
+		}
+	}
+	if !t.Timestamp.Before(s.b.until) {
+		s.finish()
+		return core.ErrSourceStopped
+	}
+	if t.Timestamp.Before(s.b.since) {
+		s.m.Lock()
+		s.skipped++
+		s.m.Unlock()
+		return nil
+	}
+	if err := w.Write(ctx, t); err != nil {
+		return err
+	}
+	s.m.Lock()
+	s.replayed++
+	s.last = t.Timestamp
+	s.m.Unlock()
+	return nil
+}
+
+// exhausted returns true when the source is rewindable and waiting for
+// rewind, that is, it has generated all tuples in its history.
+func (s *backfillSource) exhausted() bool {
+	st, ok := s.source.(core.Statuser)
+	if !ok {
+		return false
+	}
+	v, ok := st.Status()["waiting_for_rewind"]
+	return ok && v == data.True
+}
+
+func (s *backfillSource) finish() {
+	s.doneOnce.Do(func() {
+		close(s.doneCh)
+	})
+}
+
+func (s *backfillSource) stopSource(ctx *core.Context, ch <-chan error) error {
+	if err := s.source.Stop(ctx); err != nil {
+		return err
+	}
+	if err := <-ch; err != nil && err != core.ErrSourceStopped {
+		return err
+	}
+	return nil
+}
+
+func (s *backfillSource) Stop(ctx *core.Context) error {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	return nil
+}
+
+func (s *backfillSource) progress() (replayed, skipped int64, last time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.replayed, s.skipped, s.last
+}
+
+// backfillGate is a box which buffers live tuples while the history is
+// being replayed. Live tuples having timestamps before the upper bound of
+// the time range of the backfill are discarded.
+type backfillGate struct {
+	until   time.Time
+	maxSize int
+
+	m        sync.Mutex
+	opened   bool
+	buf      []*core.Tuple
+	w        core.Writer
+	buffered int64
+	dropped  int64
+}
+
+func (g *backfillGate) Process(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+	if t.Timestamp.Before(g.until) {
+		return nil
+	}
+
+	g.m.Lock()
+	if g.opened {
+		g.m.Unlock()
+		return w.Write(ctx, t)
+	}
+	defer g.m.Unlock()
+	if len(g.buf) >= g.maxSize {
+		g.buf[0] = nil
+		g.buf = g.buf[1:]
+		g.dropped++
+	}
+	t.Flags.Set(core.TFShared)
+	g.buf = append(g.buf, t)
+	g.w = w
+	g.buffered++
+	return nil
+}
+
+// open writes buffered tuples and lets subsequent tuples pass through.
+func (g *backfillGate) open(ctx *core.Context) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if g.opened {
+		return
+	}
+	for _, t := range g.buf {
+		if err := g.w.Write(ctx, t); err != nil {
+			ctx.ErrLog(err).Warn("Cannot write a buffered live tuple")
+		}
+	}
+	g.buf = nil
+	g.opened = true
+}
+
+func (g *backfillGate) progress() (buffered, dropped int64) {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return g.buffered, g.dropped
+}
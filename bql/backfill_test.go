@@ -0,0 +1,139 @@
+package bql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestBackfill(t *testing.T) {
+	backfillPollInterval = time.Millisecond
+	ts := func(sec int) time.Time {
+		return time.Date(2015, time.April, 10, 10, 23, sec, 0, time.UTC)
+	}
+
+	Convey("Given a BQL TopologyBuilder with a paused rewindable source", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=6;`), ShouldBeNil)
+
+		backfill := func(bql string, conf *BackfillConfig) error {
+			stmt, _, err := parser.New().ParseStmt(bql)
+			So(err, ShouldBeNil)
+			s := stmt.(parser.CreateStreamAsSelectStmt)
+			_, err = tb.Backfill(&s, conf)
+			return err
+		}
+		waitForCompletion := func() data.Map {
+			var st data.Map
+			for i := 0; i < 1000; i++ {
+				st, err = tb.BackfillStatus("b")
+				So(err, ShouldBeNil)
+				if st["state"] != data.String("running") {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			return st
+		}
+
+		Convey("When backfilling a stream with a time range", func() {
+			So(backfill(`CREATE STREAM b AS SELECT RSTREAM count(*) AS c FROM s [RANGE 10 TUPLES]`, &BackfillConfig{
+				Since: ts(1),
+				Until: ts(4),
+			}), ShouldBeNil)
+			So(addBQLToTopology(tb, `CREATE SINK k TYPE collector;
+				INSERT INTO k FROM b;`), ShouldBeNil)
+			sn, err := dt.Sink("k")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			st := waitForCompletion()
+
+			Convey("Then it should replay tuples in the range", func() {
+				So(st["state"], ShouldEqual, data.String("completed"))
+				So(st["source"], ShouldEqual, data.String("s"))
+				So(st["num_replayed"], ShouldEqual, data.Int(3))
+				So(st["num_skipped"], ShouldEqual, data.Int(1))
+				So(st["last_timestamp"], ShouldResemble, data.Timestamp(ts(3)))
+			})
+
+			Convey("Then the stream should receive live tuples after the history", func() {
+				So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+				var last data.Value
+				for i := 0; i < 1000 && last != data.Int(5); i++ {
+					if n := si.len(); n > 0 {
+						last = si.get(n - 1).Data["c"]
+					}
+					time.Sleep(time.Millisecond)
+				}
+				// 3 tuples from the history and 2 live tuples
+				So(last, ShouldEqual, data.Int(5))
+				st, err := tb.BackfillStatus("b")
+				So(err, ShouldBeNil)
+				So(st["num_buffered"], ShouldEqual, data.Int(0))
+			})
+		})
+
+		Convey("When backfilling a stream without a time range", func() {
+			So(backfill(`CREATE STREAM b AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]`, nil), ShouldBeNil)
+			st := waitForCompletion()
+
+			Convey("Then it should replay the whole history", func() {
+				So(st["state"], ShouldEqual, data.String("completed"))
+				So(st["num_replayed"], ShouldEqual, data.Int(6))
+				So(st["since"], ShouldResemble, data.Null{})
+			})
+
+			Convey("Then it should be listed", func() {
+				sts := tb.BackfillStatuses()
+				So(len(sts), ShouldEqual, 1)
+				So(sts[0]["stream"], ShouldEqual, data.String("b"))
+			})
+
+			Convey("Then dropping the stream should remove the progress", func() {
+				So(addBQLToTopology(tb, `DROP STREAM b;`), ShouldBeNil)
+				_, err := tb.BackfillStatus("b")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When backfilling a stream reading from a non-rewindable source", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE n TYPE dummy WITH resumable=false;`), ShouldBeNil)
+			err := backfill(`CREATE STREAM b AS SELECT RSTREAM * FROM n [RANGE 1 TUPLES]`, nil)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				_, err := dt.Box("b")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When backfilling a stream reading from another stream", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES];`), ShouldBeNil)
+			err := backfill(`CREATE STREAM b AS SELECT RSTREAM * FROM t [RANGE 1 TUPLES]`, nil)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When backfilling with an empty time range", func() {
+			err := backfill(`CREATE STREAM b AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]`, &BackfillConfig{
+				Since: ts(2),
+				Until: ts(2),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
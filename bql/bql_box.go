@@ -1,11 +1,15 @@
 package bql
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
 	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
 	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"io"
+	"io/ioutil"
 	"math/rand"
 	"sync"
 	"time"
@@ -16,6 +20,11 @@ type bqlBox struct {
 	stmt *parser.SelectStmt
 	// reg holds functions that can be used in this box
 	reg udf.FunctionRegistry
+	// ctx is the Context given to UDFs in the execution plan. It
+	// follows the Context given to Process so that UDFs can observe
+	// the cancellation of each call. It's nil if reg doesn't have
+	// a Context.
+	ctx *core.Context
 	// plan is the execution plan for the SELECT statement in there
 	execPlan execution.PhysicalPlan
 	// mutex protects access to shared state
@@ -32,6 +41,16 @@ type bqlBox struct {
 	// emitterSamplingType holds a value different from
 	// parser.UnspecifiedSamplingType if output sampling is active
 	emitterSamplingType parser.EmitterSamplingType
+	// inputSampling holds a positive value if this box should only
+	// process a certain subset of input tuples (defined by
+	// inputSamplingType)
+	inputSampling float64
+	// inputSamplingType holds a value different from
+	// parser.UnspecifiedSamplingType if input sampling is active
+	inputSamplingType parser.EmitterSamplingType
+	// readCount holds the number of input tuples received so far.
+	// this is only used if the count-based input sampling is active.
+	readCount int64
 	// genCount holds the number of items generated so far
 	// (i.e. computed by the underlying execution plan). this is only
 	// used if the count-based sampling is active.
@@ -44,12 +63,61 @@ type bqlBox struct {
 	// lastWriter points to the last writer that was passed to
 	// `Process()`
 	lastWriter core.Writer
+	// writer is set by every call to `Process()`, unlike lastWriter which
+	// is only set for the time-based emitter. Results of TUMBLING windows
+	// closed by processing time are written to it. It's protected by mutex.
+	writer core.Writer
 	// stopped is an additional flag to signal the time-based emitter
 	// that it should stop emitting items.
 	stopped bool
 	// removeMe is a function to remove this bqlBox from its
 	// topology. A nil check must be done before calling.
 	removeMe func()
+	// metadata controls which metadata of an input tuple is propagated
+	// to output tuples. All metadata is propagated when it's nil.
+	metadata *metadataPropagation
+	// lineage records lineage of input and output tuples. It's nil when
+	// lineage isn't recorded.
+	lineage *boxLineage
+	// numeric is the policy of arithmetic operators of the statement.
+	numeric execution.NumericPolicy
+}
+
+// metadataPropagation has keys of core.Tuple.Metadata propagated from an
+// input tuple to output tuples. No metadata is propagated when keys is
+// empty.
+type metadataPropagation struct {
+	keys map[string]bool
+}
+
+// apply returns metadata of an output tuple. It doesn't modify the given
+// metadata because it may be shared with other tuples.
+func (p *metadataPropagation) apply(m data.Map) data.Map {
+	if p == nil {
+		return m
+	}
+	var res data.Map
+	for k, v := range m {
+		if !p.keys[k] {
+			continue
+		}
+		if res == nil {
+			res = data.Map{}
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// forkedFunctionRegistry is a udf.FunctionRegistry which returns a
+// forked Context instead of the one of the original registry.
+type forkedFunctionRegistry struct {
+	udf.FunctionRegistry
+	ctx *core.Context
+}
+
+func (r *forkedFunctionRegistry) Context() *core.Context {
+	return r.ctx
 }
 
 func NewBQLBox(stmt *parser.SelectStmt, reg udf.FunctionRegistry) *bqlBox {
@@ -65,23 +133,38 @@ func (b *bqlBox) Init(ctx *core.Context) error {
 	b.emitterLimit = analyzedPlan.EmitterLimit
 	b.emitterSampling = analyzedPlan.EmitterSampling
 	b.emitterSamplingType = analyzedPlan.EmitterSamplingType
+	b.inputSampling = analyzedPlan.InputSampling
+	b.inputSamplingType = analyzedPlan.InputSamplingType
 	optimizedPlan, err := analyzedPlan.LogicalOptimize()
 	if err != nil {
 		return err
 	}
-	b.execPlan, err = optimizedPlan.MakePhysicalPlan(b.reg)
+	reg := b.reg
+	if c := b.reg.Context(); c != nil {
+		b.ctx = c.Fork()
+		reg = &forkedFunctionRegistry{b.reg, b.ctx}
+	}
+	reg = execution.WithNumericPolicy(reg, b.numeric)
+	b.execPlan, err = optimizedPlan.MakePhysicalPlan(reg)
 	if err != nil {
 		return err
 	}
 	if b.emitterSamplingType == parser.TimeBasedSampling {
 		go b.timeEmitter(ctx)
 	}
+	if p, ok := b.execPlan.(execution.TimedPlan); ok && p.TickInterval() > 0 {
+		go b.ticker(ctx, p)
+	}
 	return nil
 }
 
 func (b *bqlBox) Process(ctx *core.Context, t *core.Tuple, s core.Writer) error {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
+	if b.ctx != nil {
+		b.ctx.Follow(ctx)
+		defer b.ctx.Follow(nil)
+	}
 
 	// deal with statements that have an emitter limit. in particular,
 	// if we are already over the limit, exit here
@@ -93,16 +176,54 @@ func (b *bqlBox) Process(ctx *core.Context, t *core.Tuple, s core.Writer) error
 		return nil
 	}
 
+	// skip the tuple if it isn't sampled. this is done before the
+	// tuple is fed into the plan so that sampled out tuples don't
+	// cost anything
+	if b.inputSamplingType == parser.CountBasedSampling {
+		sampled := b.readCount%int64(b.inputSampling) == 0
+		b.readCount++
+		if !sampled {
+			return nil
+		}
+	} else if b.inputSamplingType == parser.RandomizedSampling {
+		if rand.Float64() >= b.inputSampling {
+			return nil
+		}
+	}
+
+	if b.lineage != nil {
+		t = b.lineage.input(t)
+	}
+
 	// feed tuple into plan
 	resultData, err := b.execPlan.Process(t)
 	if err != nil {
 		return err
 	}
+	b.writer = s
+	if err := b.writeResults(ctx, t, resultData, s); err != nil {
+		return err
+	}
+	b.removeIfOverLimit()
+	return nil
+}
+
+// writeResults writes tuples having the results computed by the plan. t
+// is the input tuple which the output tuples are copied from.
+func (b *bqlBox) writeResults(ctx *core.Context, t *core.Tuple, resultData []data.Map, s core.Writer) error {
+	var lineageInputs []int64
+	if b.lineage != nil && len(resultData) > 0 {
+		lineageInputs = b.lineage.inputIDs(b.execPlan, t)
+	}
 
 	// emit result data as tuples
 	for _, data := range resultData {
 		tup := t.ShallowCopy()
 		tup.Data = data
+		tup.Metadata = b.metadata.apply(t.Metadata)
+		if b.lineage != nil {
+			b.lineage.output(tup, lineageInputs)
+		}
 		// This method can't tell if data was originally shared by some tuples.
 		// Therefore, TFSharedData flag cannot be cleared here. Data of some
 		// Tuples can be shared when they have reference types such as Blob,
@@ -146,8 +267,12 @@ func (b *bqlBox) Process(ctx *core.Context, t *core.Tuple, s core.Writer) error
 			break
 		}
 	}
+	return nil
+}
 
-	// remove this box if we are over the limit
+// removeIfOverLimit removes this box from the topology if it has emitted
+// as many tuples as the LIMIT of the emitter.
+func (b *bqlBox) removeIfOverLimit() {
 	b.timeEmitterMutex.Lock()
 	if b.emitterLimit >= 0 && b.emitCount >= b.emitterLimit {
 		// avoid conflict with the timeEmitter (which will also perform
@@ -159,7 +284,56 @@ func (b *bqlBox) Process(ctx *core.Context, t *core.Tuple, s core.Writer) error
 		}
 	}
 	b.timeEmitterMutex.Unlock()
+}
+
+// ticker calls Tick of the plan periodically and writes its results to the
+// writer given to the last call to Process. It stops when the box is
+// terminated.
+func (b *bqlBox) ticker(ctx *core.Context, p execution.TimedPlan) {
+	ticker := time.NewTicker(p.TickInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		b.timeEmitterMutex.Lock()
+		stopped := b.stopped
+		b.timeEmitterMutex.Unlock()
+		if stopped {
+			return
+		}
+
+		if err := b.tick(ctx, p); err != nil && ctx != nil {
+			ctx.ErrLog(err).WithField("node_type", "box").
+				Error("Cannot emit the result of a closed window")
+		}
+	}
+}
+
+func (b *bqlBox) tick(ctx *core.Context, p execution.TimedPlan) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.timeEmitterMutex.Lock()
+	overLimit := b.emitterLimit >= 0 && b.emitCount >= b.emitterLimit
+	b.timeEmitterMutex.Unlock()
+	if overLimit {
+		return nil
+	}
 
+	resultData, err := p.Tick()
+	if err != nil {
+		return err
+	}
+	if len(resultData) == 0 || b.writer == nil {
+		return nil
+	}
+	t := core.NewTuple(nil)
+	if ctx != nil {
+		t.Timestamp = ctx.Now()
+		t.ProcTimestamp = t.Timestamp
+	}
+	if err := b.writeResults(ctx, t, resultData, b.writer); err != nil {
+		return err
+	}
+	b.removeIfOverLimit()
 	return nil
 }
 
@@ -234,3 +408,89 @@ func (b *bqlBox) callRemoveMeIgnoringPanic() {
 	}()
 	b.removeMe()
 }
+
+// SaveCheckpoint writes the input tuples in the windows of the execution
+// plan. Other states such as counters of sampling aren't saved.
+func (b *bqlBox) SaveCheckpoint(ctx *core.Context, w io.Writer) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	tuples := data.Array{}
+	if p, ok := b.execPlan.(execution.BufferedPlan); ok {
+		for _, t := range p.BufferedTuples() {
+			m := data.Map{
+				"data":       t.Data,
+				"input_name": data.String(t.InputName),
+				"timestamp":  data.Int(t.Timestamp.UnixNano()),
+			}
+			if t.Metadata != nil {
+				m["metadata"] = t.Metadata
+			}
+			tuples = append(tuples, m)
+		}
+	}
+	d, err := data.MarshalMsgpack(data.Map{"tuples": tuples})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(d)
+	return err
+}
+
+// LoadCheckpoint restores the windows of the execution plan by processing
+// the saved tuples again. Results computed while restoring the windows are
+// discarded.
+func (b *bqlBox) LoadCheckpoint(ctx *core.Context, r io.Reader) error {
+	d, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m, err := data.UnmarshalMsgpack(d)
+	if err != nil {
+		return err
+	}
+	ts, err := data.AsArray(m["tuples"])
+	if err != nil {
+		return fmt.Errorf("the checkpoint has invalid tuples: %v", err)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if _, ok := b.execPlan.(execution.BufferedPlan); !ok {
+		return nil
+	}
+	if b.ctx != nil {
+		b.ctx.Follow(ctx)
+		defer b.ctx.Follow(nil)
+	}
+	for _, v := range ts {
+		t, err := decodeCheckpointTuple(v)
+		if err != nil {
+			return err
+		}
+		if _, err := b.execPlan.Process(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeCheckpointTuple(v data.Value) (*core.Tuple, error) {
+	m, err := data.AsMap(v)
+	if err != nil {
+		return nil, fmt.Errorf("a tuple in the checkpoint is broken: %v", err)
+	}
+	d, err := data.AsMap(m["data"])
+	if err != nil {
+		return nil, fmt.Errorf("the data of a tuple in the checkpoint is broken: %v", err)
+	}
+	t := core.NewTuple(d)
+	t.InputName, _ = data.AsString(m["input_name"])
+	ts, _ := data.AsInt(m["timestamp"])
+	t.Timestamp = time.Unix(0, ts)
+	if v, ok := m["metadata"]; ok {
+		if t.Metadata, err = data.AsMap(v); err != nil {
+			return nil, fmt.Errorf("the metadata of a tuple in the checkpoint is broken: %v", err)
+		}
+	}
+	return t, nil
+}
@@ -3,6 +3,7 @@ package bql
 import (
 	"fmt"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
 	_ "gopkg.in/sensorbee/sensorbee.v0/bql/udf/builtin"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
@@ -242,6 +243,70 @@ func TestBQLBoxEmitterParams(t *testing.T) {
 		})
 	})
 
+	Convey("Given a BQL statement with a READ EVERY k-TH TUPLE clause", t, func() {
+		s := "CREATE STREAM box AS SELECT " +
+			"RSTREAM [LIMIT 2] int FROM source [RANGE 1 TUPLES] READ EVERY 2ND TUPLE"
+		tb, err := setupTopology(s, false)
+		So(err, ShouldBeNil)
+		dt := tb.Topology()
+		Reset(func() {
+			dt.Stop()
+		})
+
+		sin, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sin.Sink().(*tupleCollectorSink)
+
+		Convey("When 4 tuples are emitted by the source", func() {
+
+			Convey("Then the sink receives only sampled tuples", func() {
+				si.Wait(2)
+				So(si.len(), ShouldEqual, 2)
+				// the second and the fourth tuples are sampled out before
+				// they are processed by the plan
+				So(si.get(0).Data["int"], ShouldEqual, data.Int(1))
+				So(si.get(1).Data["int"], ShouldEqual, data.Int(3))
+			})
+		})
+	})
+
+	Convey("Given a BQL statement with a READ SAMPLE 100% clause", t, func() {
+		s := "CREATE STREAM box AS SELECT " +
+			"RSTREAM int FROM source [RANGE 1 TUPLES] READ SAMPLE 100%"
+		tb, err := setupTopology(s, false)
+		So(err, ShouldBeNil)
+		dt := tb.Topology()
+		Reset(func() {
+			dt.Stop()
+		})
+
+		sin, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sin.Sink().(*tupleCollectorSink)
+
+		Convey("When 4 tuples are emitted by the source", func() {
+
+			Convey("Then the sink receives all tuples", func() {
+				si.Wait(4)
+				So(si.len(), ShouldEqual, 4)
+			})
+		})
+	})
+
+	Convey("Given a BQL statement with an invalid READ clause", t, func() {
+		s := "CREATE STREAM box AS SELECT " +
+			"RSTREAM int FROM source [RANGE 1 TUPLES] READ SAMPLE 120%"
+
+		Convey("When creating the stream", func() {
+			_, err := setupTopology(s, false)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "READ SAMPLE")
+			})
+		})
+	})
+
 	Convey("Given a BQL statement with an EVERY 10 MILLISECONDS clause", t, func() {
 		s := "CREATE STREAM box AS SELECT " +
 			"RSTREAM [EVERY 10 MILLISECONDS] int, str((int+1) % 3) AS x FROM source [RANGE 1 TUPLES] " +
@@ -655,6 +720,65 @@ func TestBQLBoxGroupByCapability(t *testing.T) {
 	})
 }
 
+func TestBQLBoxTumblingWindow(t *testing.T) {
+	Convey("Given an RSTREAM statement with a TUMBLING window of tuples", t, func() {
+		s := "CREATE STREAM box AS SELECT " +
+			"RSTREAM count(*) AS c, max(int) AS m FROM source [TUMBLING 2 TUPLES]"
+		tb, err := setupTopology(s, false)
+		So(err, ShouldBeNil)
+		dt := tb.Topology()
+		Reset(func() {
+			dt.Stop()
+		})
+
+		sin, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sin.Sink().(*tupleCollectorSink)
+
+		Convey("When 4 tuples are emitted by the source", func() {
+			Convey("Then the sink receives a tuple per window", func() {
+				si.Wait(2)
+				So(si.len(), ShouldEqual, 2)
+				So(si.get(0).Data, ShouldResemble, data.Map{"c": data.Int(2), "m": data.Int(2)})
+				So(si.get(1).Data, ShouldResemble, data.Map{"c": data.Int(2), "m": data.Int(4)})
+			})
+		})
+	})
+
+	Convey("Given an RSTREAM statement with a TUMBLING window by processing time", t, func() {
+		s := "CREATE STREAM box AS SELECT " +
+			"RSTREAM count(*) AS c FROM source [TUMBLING 50 MILLISECONDS BY PROCESSING TIME]"
+		tb, err := setupTopology(s, false)
+		So(err, ShouldBeNil)
+		dt := tb.Topology()
+		Reset(func() {
+			dt.Stop()
+		})
+
+		sin, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sin.Sink().(*tupleCollectorSink)
+
+		Convey("When 4 tuples are emitted by the source", func() {
+			Convey("Then windows should be closed without further input", func() {
+				// tuples can be split into multiple windows
+				total := int64(0)
+				for i := 0; i < 100 && total < 4; i++ {
+					time.Sleep(20 * time.Millisecond)
+					total = 0
+					for j := 0; j < si.len(); j++ {
+						c, err := data.AsInt(si.get(j).Data["c"])
+						So(err, ShouldBeNil)
+						So(c, ShouldBeGreaterThan, 0)
+						total += c
+					}
+				}
+				So(total, ShouldEqual, 4)
+			})
+		})
+	})
+}
+
 func TestBQLBoxUDSF(t *testing.T) {
 	Convey("Given a topology using UDSF", t, func() {
 		tb, err := setupTopology(`CREATE STREAM box AS SELECT RSTREAM duplicate:int FROM duplicate("source", 3) [RANGE 1 TUPLES]`, false)
@@ -710,3 +834,58 @@ func TestBQLBoxSourceUDSF(t *testing.T) {
 		})
 	})
 }
+
+func TestBQLBoxCheckpoint(t *testing.T) {
+	Convey("Given a topology having a window and a checkpoint storage", t, func() {
+		storage := udf.NewCheckpointStorage(udf.NewInMemoryUDSStorage())
+		newTopology := func(num int) (*TopologyBuilder, *tupleCollectorSink) {
+			dt, err := core.NewDefaultTopology(core.NewContext(&core.ContextConfig{
+				Checkpoint: &core.CheckpointConfig{
+					Storage: storage,
+				},
+			}), "test")
+			So(err, ShouldBeNil)
+			tb, err := NewTopologyBuilder(dt)
+			So(err, ShouldBeNil)
+			So(addBQLToTopology(tb, fmt.Sprintf(`
+				CREATE PAUSED SOURCE source TYPE dummy WITH num=%v;
+				CREATE STREAM box AS SELECT RSTREAM count(*) AS c, sum(int) AS s
+					FROM source [RANGE 3 TUPLES];
+				CREATE SINK snk TYPE collector;
+				INSERT INTO snk FROM box;`, num)), ShouldBeNil)
+			sin, err := dt.Sink("snk")
+			So(err, ShouldBeNil)
+			return tb, sin.Sink().(*tupleCollectorSink)
+		}
+		tb, si := newTopology(4)
+		Reset(func() {
+			tb.Topology().Stop()
+		})
+		So(addBQLToTopology(tb, `RESUME SOURCE source;`), ShouldBeNil)
+		si.Wait(4)
+
+		Convey("When taking a checkpoint", func() {
+			_, err := tb.Topology().Checkpoint()
+			So(err, ShouldBeNil)
+
+			Convey("And restoring it in a new topology", func() {
+				tb2, si2 := newTopology(1)
+				Reset(func() {
+					tb2.Topology().Stop()
+				})
+				info, err := tb2.Topology().RestoreCheckpoint()
+				So(err, ShouldBeNil)
+				So(info["boxes"], ShouldResemble, data.Array{data.String("box")})
+				So(addBQLToTopology(tb2, `RESUME SOURCE source;`), ShouldBeNil)
+
+				Convey("Then the window should have the restored tuples", func() {
+					si2.Wait(1)
+					So(si2.len(), ShouldEqual, 1)
+					// the window has 3, 4, and the new tuple 1
+					So(si2.get(0).Data["c"], ShouldEqual, data.Int(3))
+					So(si2.get(0).Data["s"], ShouldEqual, data.Int(8))
+				})
+			})
+		})
+	})
+}
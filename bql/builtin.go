@@ -1,9 +1,7 @@
 package bql
 
 import (
-	"bufio"
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -37,13 +35,25 @@ func createSharedStateSink(ctx *core.Context, ioParams *IOParams, params data.Ma
 }
 
 func init() {
-	MustRegisterGlobalSinkCreator("uds", SinkCreatorFunc(createSharedStateSink))
+	MustRegisterGlobalSinkCreator("uds", SinkCreatorWithParamSchema(SinkCreatorFunc(createSharedStateSink), &ParamSchema{
+		Description: "writes tuples to a shared state",
+		Params: []*ParamSpec{
+			{
+				Name:        "name",
+				Type:        data.TypeString,
+				Required:    true,
+				Description: "the name of the shared state",
+			},
+		},
+	}))
 }
 
 type readerSource struct {
-	filename string
-	tsField  data.Path
-	ioParams *IOParams
+	filename    string
+	compression string
+	newDecoder  recordDecoderCreator
+	tsField     data.Path
+	ioParams    *IOParams
 
 	// repeat is the number of times that the input data is read. When its value
 	// is less than 0, the source will read the input again and again until it's
@@ -79,27 +89,28 @@ func (s *readerSource) generateStream(ctx *core.Context, w core.Writer) error {
 		}
 	}()
 
-	r := bufio.NewReader(f)
-	next := time.Now()
-	for lineNumber := 0; ; lineNumber++ {
-		line, err := r.ReadBytes('\n')
-		if err != nil && err != io.EOF {
-			return err
-		}
+	r, err := NewDecompressingReader(s.compression, f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
 
-		line = bytes.TrimSpace(line)
-		if len(line) == 0 {
+	dec := s.newDecoder(r)
+	next := time.Now()
+	for {
+		m, err := dec.decode()
+		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			continue
-		}
-
-		m := data.Map{}
-		if err := json.Unmarshal(line, &m); err != nil {
-			ctx.ErrLog(err).WithField("node_name", s.ioParams.Name).
-				WithField("jsonl_line_number", lineNumber).
-				WithField("body", string(line)).Warning("Ignoring the line due to a json parse error")
+			e, ok := err.(*malformedRecordError)
+			if !ok {
+				return err
+			}
+			ctx.ErrLog(e.err).WithField("node_name", s.ioParams.Name).
+				WithField("format", e.format).WithField("line_number", e.line).
+				WithField("body", e.raw).Warning("Ignoring a malformed record")
+			ctx.DroppedTuple(core.NewTuple(e.toMap()), core.NTSource, s.ioParams.Name, core.ETOutput, e)
 			continue
 		}
 
@@ -113,7 +124,7 @@ func (s *readerSource) generateStream(ctx *core.Context, w core.Writer) error {
 			if v, err := t.Data.Get(s.tsField); err == nil {
 				if ts, err := data.ToTimestamp(v); err != nil {
 					ctx.ErrLog(err).WithField("node_name", s.ioParams.Name).
-						WithField("jsonl_line_number", lineNumber).
+						WithField("line_number", dec.lineNumber()).
 						WithField("timestamp_field", s.tsField).
 						WithField("timestamp_field_value", v).
 						Warning("Cannot convert a value in timestamp_field to a timestamp")
@@ -154,10 +165,10 @@ func (s *readerSource) Stop(ctx *core.Context) error {
 }
 
 func createFileSource(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Source, error) {
-	// TODO: add format parameter
-
 	v := &struct {
 		Path           string `bql:",required"`
+		Format         string
+		Compression    string
 		Rewindable     bool
 		TimestampField string
 		Repeat         int64
@@ -172,6 +183,14 @@ func createFileSource(ctx *core.Context, ioParams *IOParams, params data.Map) (c
 		return nil, err
 	}
 
+	newDecoder, err := newRecordDecoderCreator(v.Format, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateCompression(v.Compression, true); err != nil {
+		return nil, err
+	}
+
 	var tsField data.Path
 	if v.TimestampField != "" {
 		var err error
@@ -181,12 +200,14 @@ func createFileSource(ctx *core.Context, ioParams *IOParams, params data.Map) (c
 	}
 
 	s := &readerSource{
-		filename: v.Path,
-		tsField:  tsField,
-		ioParams: ioParams,
-		repeat:   v.Repeat,
-		interval: v.Interval,
-		stopCh:   make(chan struct{}),
+		filename:    v.Path,
+		compression: v.Compression,
+		newDecoder:  newDecoder,
+		tsField:     tsField,
+		ioParams:    ioParams,
+		repeat:      v.Repeat,
+		interval:    v.Interval,
+		stopCh:      make(chan struct{}),
 	}
 	if v.Rewindable {
 		return core.NewRewindableSource(s), nil
@@ -202,6 +223,15 @@ type writerSink struct {
 	m           sync.Mutex
 	w           io.Writer
 	shouldClose bool
+
+	// tmpl formats tuples when it's given by the format_template parameter.
+	// Tuples are written in JSON otherwise.
+	tmpl *TupleTemplate
+
+	// traceRecord is true when tuples are written as records of a trace,
+	// which have the input name and the timestamp of each tuple as well as
+	// its data.
+	traceRecord bool
 }
 
 func (s *writerSink) Write(ctx *core.Context, t *core.Tuple) error {
@@ -210,7 +240,23 @@ func (s *writerSink) Write(ctx *core.Context, t *core.Tuple) error {
 	// While encoding tuples outside the lock supports concurrent formatting,
 	// it makes it difficult to support zero-copy write.
 
-	js := t.Data.String() // Format this outside the lock
+	// Format this outside the lock
+	var out string
+	if s.tmpl != nil {
+		var b bytes.Buffer
+		if err := s.tmpl.Execute(&b, t); err != nil {
+			return err
+		}
+		out = b.String()
+	} else if s.traceRecord {
+		out = data.Map{
+			"stream":    data.String(t.InputName),
+			"timestamp": data.Timestamp(t.Timestamp),
+			"data":      t.Data,
+		}.String()
+	} else {
+		out = t.Data.String()
+	}
 
 	// This lock is required to avoid interleaving JSONs.
 	s.m.Lock()
@@ -218,7 +264,7 @@ func (s *writerSink) Write(ctx *core.Context, t *core.Tuple) error {
 	if s.w == nil {
 		return errors.New("the sink is already closed")
 	}
-	_, err := fmt.Fprintln(s.w, js)
+	_, err := fmt.Fprintln(s.w, out)
 	return err
 }
 
@@ -230,6 +276,7 @@ func (s *writerSink) Close(ctx *core.Context) error {
 	}
 	if s.shouldClose {
 		if c, ok := s.w.(io.Closer); ok {
+			s.w = nil
 			return c.Close()
 		}
 	}
@@ -237,17 +284,37 @@ func (s *writerSink) Close(ctx *core.Context) error {
 }
 
 func createStdoutSink(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Sink, error) {
+	var text string
+	if v, ok := params["format_template"]; ok {
+		s, err := data.AsString(v)
+		if err != nil {
+			return nil, fmt.Errorf("format_template must be a string: %v", err)
+		}
+		text = s
+	}
+	tmpl, err := newWriterSinkTemplate(text)
+	if err != nil {
+		return nil, err
+	}
 	return &writerSink{
-		w: os.Stdout,
+		w:    os.Stdout,
+		tmpl: tmpl,
 	}, nil
 }
 
+// newWriterSinkTemplate parses the format_template parameter of sinks writing
+// tuples to an io.Writer. It returns nil when the parameter isn't given. A
+// newline is appended to the output of the template for each tuple.
+func newWriterSinkTemplate(text string) (*TupleTemplate, error) {
+	if text == "" {
+		return nil, nil
+	}
+	return NewTupleTemplate(text)
+}
+
 func createFileSink(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Sink, error) {
 	// TODO: currently this sink isn't secure because it accepts any path.
 	// TODO: support buffering
-	// TODO: provide "format" parameter to support output formats other than "jsonl".
-	//       "jsonl" should be the default value.
-	// TODO: support "compression" parameter with values like "gz".
 
 	v := &struct {
 		Path     string `bql:",required"`
@@ -256,6 +323,9 @@ func createFileSink(ctx *core.Context, ioParams *IOParams, params data.Map) (cor
 		MaxSize    int
 		MaxAge     int
 		MaxBackups int
+		// FormatTemplate is a TupleTemplate used instead of JSON
+		FormatTemplate string
+		Compression    string
 	}{
 		Truncate: false,
 		MaxSize:  0,
@@ -264,6 +334,18 @@ func createFileSink(ctx *core.Context, ioParams *IOParams, params data.Map) (cor
 	if err := dec.Decode(params, v); err != nil {
 		return nil, err
 	}
+	tmpl, err := newWriterSinkTemplate(v.FormatTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateCompression(v.Compression, false); err != nil {
+		return nil, err
+	}
+	compressed := v.Compression != "" && v.Compression != CompressionNone
+	if compressed && v.MaxSize > 0 {
+		// Each rotated file would be a fragment of a compressed stream.
+		return nil, errors.New("compression cannot be used with rotation")
+	}
 
 	var w io.Writer
 	if v.MaxSize > 0 {
@@ -293,16 +375,59 @@ func createFileSink(ctx *core.Context, ioParams *IOParams, params data.Map) (cor
 			return nil, err
 		}
 		w = file
+		if compressed {
+			// Appending to an existing file is fine because all supported
+			// formats allow concatenated streams.
+			c, err := NewCompressingWriter(v.Compression, file)
+			if err != nil {
+				file.Close()
+				return nil, err
+			}
+			w = &compressedFile{
+				WriteCloser: c,
+				file:        file,
+			}
+		}
 	}
 	return &writerSink{
 		w:           w,
 		shouldClose: true,
+		tmpl:        tmpl,
+	}, nil
+}
+
+// createTraceRecorderSink creates a sink recording tuples as a trace, which
+// is a JSON Lines file having the name of the input stream, the timestamp,
+// and the data of each tuple. A trace of inputs of a topology can be replayed
+// by the testkit package to compare outputs with golden ones.
+func createTraceRecorderSink(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Sink, error) {
+	v := &struct {
+		Path     string `bql:",required"`
+		Truncate bool
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, v); err != nil {
+		return nil, err
+	}
+
+	flags := os.O_WRONLY | os.O_APPEND | os.O_CREATE
+	if v.Truncate {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(v.Path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &writerSink{
+		w:           file,
+		shouldClose: true,
+		traceRecord: true,
 	}, nil
 }
 
 func init() {
 	MustRegisterGlobalSinkCreator("stdout", SinkCreatorFunc(createStdoutSink))
 	MustRegisterGlobalSinkCreator("file", SinkCreatorFunc(createFileSink))
+	MustRegisterGlobalSinkCreator("trace_recorder", SinkCreatorFunc(createTraceRecorderSink))
 }
 
 func createDroppedTupleCollectorSource(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Source, error) {
@@ -304,6 +304,40 @@ func TestFileSink(t *testing.T) {
 			})
 		})
 
+		Convey("When create file sink with format_template", func() {
+			fn := filepath.Join(tdir, "file_sink_template.csv")
+			params := data.Map{
+				"path":            data.String(fn),
+				"format_template": data.String(`{{.Data.k}},{{json .Data.s}}`),
+			}
+			si, err := createFileSink(ctx, ioParams, params)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				si.Close(ctx)
+			})
+			Convey("And when write a tuple to the sink", func() {
+				tu := core.NewTuple(data.Map{"k": data.Int(-1), "s": data.String("a")})
+				So(si.Write(ctx, tu), ShouldBeNil)
+				Convey("Then the tuple should be formatted with the template", func() {
+					actualByte, err := ioutil.ReadFile(fn)
+					So(err, ShouldBeNil)
+					So(string(actualByte), ShouldEqual, `-1,"a"
+`)
+				})
+			})
+		})
+
+		Convey("When create file sink with an invalid format_template", func() {
+			params := data.Map{
+				"path":            data.String(filepath.Join(tdir, "invalid.txt")),
+				"format_template": data.String(`{{.Data.k`),
+			}
+			_, err := createFileSink(ctx, ioParams, params)
+			Convey("Then the sink should not be created", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
 		Convey("When create file sink with truncate flag", func() {
 			fn := filepath.Join(tdir, "file_sink2.jsonl")
 			So(ioutil.WriteFile(fn, []byte(`{"k":-2}
@@ -428,3 +462,38 @@ func TestFileSink(t *testing.T) {
 		})
 	})
 }
+
+func TestTraceRecorderSink(t *testing.T) {
+	ctx := core.NewContext(nil)
+	ioParams := &IOParams{}
+	Convey("Given a trace_recorder sink", t, func() {
+		tdir, err := ioutil.TempDir("", "test_sb_trace_recorder_sink")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(tdir)
+		})
+		fn := filepath.Join(tdir, "input.trace")
+		si, err := createTraceRecorderSink(ctx, ioParams, data.Map{
+			"path": data.String(fn),
+		})
+		So(err, ShouldBeNil)
+		Reset(func() {
+			si.Close(ctx)
+		})
+
+		Convey("When writing a tuple to the sink", func() {
+			tu := core.NewTuple(data.Map{"k": data.Int(-1)})
+			tu.InputName = "readings"
+			tu.Timestamp = time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+			So(si.Write(ctx, tu), ShouldBeNil)
+
+			Convey("Then the tuple should be recorded with its stream and timestamp", func() {
+				b, err := ioutil.ReadFile(fn)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual,
+					`{"data":{"k":-1},"stream":"readings","timestamp":"2016-01-02T03:04:05Z"}
+`)
+			})
+		})
+	})
+}
@@ -0,0 +1,178 @@
+package bql
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// recordDecoder reads records from a stream of bytes and decodes them into
+// maps. A decoder is created for each run of a source reading the stream.
+type recordDecoder interface {
+	// decode returns the next record. It returns io.EOF when all records
+	// have been read. It returns *malformedRecordError when the record
+	// cannot be decoded. decode can be called again to read the next record
+	// in that case.
+	decode() (data.Map, error)
+
+	// lineNumber returns the line number, starting from 1, at which the
+	// record most recently returned by decode starts. Decoders of binary
+	// formats return the number of the record instead.
+	lineNumber() int
+}
+
+// recordDecoderCreator creates a recordDecoder reading from r.
+type recordDecoderCreator func(r io.Reader) recordDecoder
+
+// newRecordDecoderCreator returns a recordDecoderCreator of the format.
+// params has format specific parameters.
+func newRecordDecoderCreator(format string, params data.Map) (recordDecoderCreator, error) {
+	switch format {
+	case "", "jsonl":
+		return newJSONLDecoder, nil
+	case "csv":
+		return newCSVDecoderCreator(params)
+	case "protobuf":
+		return newProtobufDecoderCreator(params)
+	case "avro":
+		return newAvroDecoderCreator(params)
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", format)
+	}
+}
+
+// malformedRecordError is returned from recordDecoder when a record cannot
+// be decoded.
+type malformedRecordError struct {
+	format string
+	line   int
+	raw    data.Value
+	err    error
+}
+
+func (e *malformedRecordError) Error() string {
+	return fmt.Sprintf("malformed %v record at line %v: %v", e.format, e.line, e.err)
+}
+
+// toMap returns information of the record, which is reported as a dropped
+// tuple.
+func (e *malformedRecordError) toMap() data.Map {
+	return data.Map{
+		"format":      data.String(e.format),
+		"line_number": data.Int(e.line),
+		"body":        e.raw,
+	}
+}
+
+type jsonlDecoder struct {
+	r    *bufio.Reader
+	line int
+	eof  bool
+}
+
+func newJSONLDecoder(r io.Reader) recordDecoder {
+	return &jsonlDecoder{
+		r: bufio.NewReader(r),
+	}
+}
+
+func (d *jsonlDecoder) decode() (data.Map, error) {
+	for !d.eof {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			d.eof = true
+		}
+		d.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		m := data.Map{}
+		if err := json.Unmarshal(line, &m); err != nil {
+			return nil, &malformedRecordError{
+				format: "jsonl",
+				line:   d.line,
+				raw:    data.String(line),
+				err:    err,
+			}
+		}
+		return m, nil
+	}
+	return nil, io.EOF
+}
+
+func (d *jsonlDecoder) lineNumber() int {
+	return d.line
+}
+
+// maxDelimitedRecordSize is the maximum size of a record accepted by
+// delimitedDecoder.
+const maxDelimitedRecordSize = math.MaxInt32
+
+// newDelimitedDecoderCreator returns a recordDecoderCreator decoding a stream
+// of binary records, each of which is prefixed by its size encoded as a
+// varint. This is the same framing as writeDelimitedTo of protobuf in Java
+// and protodelim in Go.
+func newDelimitedDecoderCreator(format string, dec func(b []byte) (data.Map, error)) recordDecoderCreator {
+	return func(r io.Reader) recordDecoder {
+		return &delimitedDecoder{
+			r:      bufio.NewReader(r),
+			format: format,
+			dec:    dec,
+		}
+	}
+}
+
+type delimitedDecoder struct {
+	r      *bufio.Reader
+	format string
+	dec    func(b []byte) (data.Map, error)
+	count  int
+}
+
+func (d *delimitedDecoder) decode() (data.Map, error) {
+	size, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("cannot read the size of a %v record: %v", d.format, err)
+	}
+	if size > maxDelimitedRecordSize {
+		return nil, fmt.Errorf("too large %v record: %v bytes", d.format, size)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("a %v record is truncated", d.format)
+		}
+		return nil, err
+	}
+	d.count++
+
+	m, err := d.dec(b)
+	if err != nil {
+		return nil, &malformedRecordError{
+			format: d.format,
+			line:   d.count,
+			raw:    data.Blob(b),
+			err:    err,
+		}
+	}
+	return m, nil
+}
+
+func (d *delimitedDecoder) lineNumber() int {
+	return d.count
+}
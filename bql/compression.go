@@ -0,0 +1,119 @@
+package bql
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported values of the "compression" parameter of sources and sinks.
+// Snappy uses the framing format, which is also used by the snappy command
+// line tools. Sources also accept "auto", which detects the algorithm from
+// the magic number at the beginning of the input and reads uncompressed
+// input as is.
+const (
+	CompressionNone   = "none"
+	CompressionGzip   = "gzip"
+	CompressionSnappy = "snappy"
+	CompressionZstd   = "zstd"
+	CompressionAuto   = "auto"
+)
+
+var compressionMagicNumbers = []struct {
+	compression string
+	magic       []byte
+}{
+	{CompressionGzip, []byte{0x1f, 0x8b}},
+	{CompressionZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{CompressionSnappy, []byte("\xff\x06\x00\x00sNaPpY")},
+}
+
+// ValidateCompression returns an error when the compression isn't supported.
+// "auto" is only valid when forReading is true.
+func ValidateCompression(compression string, forReading bool) error {
+	switch compression {
+	case "", CompressionNone, CompressionGzip, CompressionSnappy, CompressionZstd:
+		return nil
+	case CompressionAuto:
+		if forReading {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported compression: %v", compression)
+}
+
+// NewDecompressingReader returns a reader decompressing data read from r.
+// The returned reader doesn't close r.
+func NewDecompressingReader(compression string, r io.Reader) (io.ReadCloser, error) {
+	if compression == CompressionAuto {
+		br := bufio.NewReader(r)
+		compression = CompressionNone
+		for _, m := range compressionMagicNumbers {
+			if b, _ := br.Peek(len(m.magic)); bytes.Equal(b, m.magic) {
+				compression = m.compression
+				break
+			}
+		}
+		r = br
+	}
+
+	switch compression {
+	case "", CompressionNone:
+		return ioutil.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionSnappy:
+		return ioutil.NopCloser(snappy.NewReader(r)), nil
+	case CompressionZstd:
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	}
+	return nil, fmt.Errorf("unsupported compression: %v", compression)
+}
+
+// NewCompressingWriter returns a writer compressing data written to w.
+// Closing the returned writer flushes remaining data but doesn't close w.
+func NewCompressingWriter(compression string, w io.Writer) (io.WriteCloser, error) {
+	switch compression {
+	case "", CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported compression: %v", compression)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// compressedFile closes both of the compressing writer and the file.
+type compressedFile struct {
+	io.WriteCloser
+	file io.Closer
+}
+
+func (c *compressedFile) Close() error {
+	err := c.WriteCloser.Close()
+	if cerr := c.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
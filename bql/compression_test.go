@@ -0,0 +1,116 @@
+package bql
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestCompression(t *testing.T) {
+	input := []byte(`{"int":1}` + "\n" + `{"int":2}` + "\n")
+
+	for _, c := range []string{CompressionNone, CompressionGzip, CompressionSnappy, CompressionZstd} {
+		c := c
+		Convey("Given data compressed with "+c, t, func() {
+			buf := bytes.NewBuffer(nil)
+			w, err := NewCompressingWriter(c, buf)
+			So(err, ShouldBeNil)
+			_, err = w.Write(input)
+			So(err, ShouldBeNil)
+			So(w.Close(), ShouldBeNil)
+			compressed := buf.Bytes()
+
+			Convey("When decompressing it", func() {
+				r, err := NewDecompressingReader(c, bytes.NewReader(compressed))
+				So(err, ShouldBeNil)
+				b, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+
+				Convey("Then it should be the same as the original data", func() {
+					So(b, ShouldResemble, input)
+				})
+			})
+
+			Convey("When decompressing it with auto detection", func() {
+				r, err := NewDecompressingReader(CompressionAuto, bytes.NewReader(compressed))
+				So(err, ShouldBeNil)
+				b, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+
+				Convey("Then it should be the same as the original data", func() {
+					So(b, ShouldResemble, input)
+				})
+			})
+		})
+	}
+
+	Convey("Given an unsupported compression", t, func() {
+		Convey("Then it should be rejected", func() {
+			So(ValidateCompression("lz4", true), ShouldNotBeNil)
+			So(ValidateCompression(CompressionAuto, false), ShouldNotBeNil)
+			So(ValidateCompression(CompressionAuto, true), ShouldBeNil)
+		})
+	})
+}
+
+func TestCompressedFile(t *testing.T) {
+	ctx := core.NewContext(nil)
+
+	Convey("Given a file sink writing zstd compressed tuples", t, func() {
+		dir, err := ioutil.TempDir("", "sbtest_bql_compression")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "out.jsonl.zst")
+		params := data.Map{
+			"path":        data.String(path),
+			"compression": data.String("zstd"),
+		}
+
+		// Write twice to see concatenated streams can be read.
+		for i := 0; i < 2; i++ {
+			si, err := createFileSink(ctx, &IOParams{}, params)
+			So(err, ShouldBeNil)
+			So(si.Write(ctx, core.NewTuple(data.Map{"int": data.Int(i)})), ShouldBeNil)
+			So(si.Close(ctx), ShouldBeNil)
+		}
+
+		Convey("When reading the file by file source with compression", func() {
+			w := &testFileWriter{}
+			w.c = sync.NewCond(&w.m)
+			s, err := createFileSource(ctx, &IOParams{}, params)
+			So(err, ShouldBeNil)
+			So(s.GenerateStream(ctx, w), ShouldBeNil)
+
+			Convey("Then it should emit all tuples", func() {
+				So(w.cnt, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When reading the file without compression", func() {
+			b, err := ioutil.ReadFile(path)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be compressed", func() {
+				So(bytes.HasPrefix(b, []byte{0x28, 0xb5, 0x2f, 0xfd}), ShouldBeTrue)
+			})
+		})
+
+		Convey("When creating a file sink with compression and rotation", func() {
+			params["max_size"] = data.Int(1)
+			_, err := createFileSink(ctx, &IOParams{}, params)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
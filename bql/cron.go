@@ -0,0 +1,196 @@
+package bql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule computes the times at which a trigger runs.
+type schedule interface {
+	// next returns the first time after t.
+	next(t time.Time) time.Time
+}
+
+// intervalSchedule is a schedule created by "@every <duration>".
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s *intervalSchedule) next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSchedule is a schedule created by a cron expression having five
+// fields: minute, hour, day of month, month, and day of week. Each field
+// has a bit set of the values matching the field.
+type cronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domStar and dowStar are true when day of month or day of week is "*".
+	// As cron does, when both of them are restricted, a day matching either
+	// of them matches the schedule.
+	domStar bool
+	dowStar bool
+}
+
+var (
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	cronFieldBounds = []struct {
+		name     string
+		min, max int
+	}{
+		{"minute", 0, 59},
+		{"hour", 0, 23},
+		{"day of month", 1, 31},
+		{"month", 1, 12},
+		{"day of week", 0, 7}, // both 0 and 7 are Sunday
+	}
+)
+
+// parseSchedule parses a cron expression such as "0 2 * * *". It also
+// accepts descriptors like "@daily" and "@every <duration>" where the
+// duration is in the format of time.ParseDuration.
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%v': %v", spec, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid schedule '%v': the interval must be positive", spec)
+		}
+		return &intervalSchedule{d}, nil
+	}
+	if s, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = s
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFieldBounds) {
+		return nil, fmt.Errorf("invalid schedule '%v': a cron expression must have %v fields",
+			spec, len(cronFieldBounds))
+	}
+	bits := make([]uint64, len(fields))
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFieldBounds[i].min, cronFieldBounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v in schedule '%v': %v", cronFieldBounds[i].name, spec, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a comma separated list of "*", "n", "n-m", and
+// those followed by "/step".
+func parseCronField(f string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, r := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(r, "/"); i >= 0 {
+			s, err := strconv.Atoi(r[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step: %v", r)
+			}
+			step = s
+			r = r[:i]
+		}
+
+		begin, end := min, max
+		switch {
+		case r == "*":
+		case strings.Contains(r, "-"):
+			i := strings.Index(r, "-")
+			b, err := strconv.Atoi(r[:i])
+			if err != nil {
+				return 0, fmt.Errorf("invalid range: %v", r)
+			}
+			e, err := strconv.Atoi(r[i+1:])
+			if err != nil {
+				return 0, fmt.Errorf("invalid range: %v", r)
+			}
+			begin, end = b, e
+		default:
+			v, err := strconv.Atoi(r)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value: %v", r)
+			}
+			begin, end = v, v
+			if step != 1 {
+				end = max
+			}
+		}
+		if begin < min || end > max || begin > end {
+			return 0, fmt.Errorf("%v is out of range [%v, %v]", r, min, max)
+		}
+		for v := begin; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) next(t time.Time) time.Time {
+	// cron expressions have the resolution of a minute
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second -
+		time.Duration(t.Nanosecond()))
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	// the schedule never matches (e.g. "0 0 30 2 *")
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
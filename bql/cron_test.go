@@ -0,0 +1,67 @@
+package bql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2016, 1, 29, 10, 30, 15, 0, time.UTC) // Friday
+
+	Convey("Given cron expressions", t, func() {
+		for _, c := range []struct {
+			spec     string
+			expected time.Time
+		}{
+			{"* * * * *", time.Date(2016, 1, 29, 10, 31, 0, 0, time.UTC)},
+			{"0 2 * * *", time.Date(2016, 1, 30, 2, 0, 0, 0, time.UTC)},
+			{"*/20 * * * *", time.Date(2016, 1, 29, 10, 40, 0, 0, time.UTC)},
+			{"15,45 10-12 * * *", time.Date(2016, 1, 29, 10, 45, 0, 0, time.UTC)},
+			{"0 0 * * 1", time.Date(2016, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{"0 0 * * 7", time.Date(2016, 1, 31, 0, 0, 0, 0, time.UTC)},
+			{"0 0 29 2 *", time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC)},
+			{"0 0 1 * 6", time.Date(2016, 1, 30, 0, 0, 0, 0, time.UTC)}, // day of month or Saturday
+			{"@hourly", time.Date(2016, 1, 29, 11, 0, 0, 0, time.UTC)},
+			{"@MONTHLY", time.Date(2016, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{"@every 1m30s", time.Date(2016, 1, 29, 10, 31, 45, 0, time.UTC)},
+		} {
+			c := c
+			Convey("When parsing "+c.spec, func() {
+				s, err := parseSchedule(c.spec)
+				So(err, ShouldBeNil)
+
+				Convey("Then it should compute the next time", func() {
+					So(s.next(base), ShouldResemble, c.expected)
+				})
+			})
+		}
+
+		Convey("When parsing a schedule which never matches", func() {
+			s, err := parseSchedule("0 0 30 2 *")
+			So(err, ShouldBeNil)
+
+			Convey("Then the next time should be zero", func() {
+				So(s.next(base).IsZero(), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given invalid schedules", t, func() {
+		for _, spec := range []string{
+			"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+			"* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *",
+			"@every", "@every 1x", "@every -1s", "@sometimes",
+		} {
+			spec := spec
+			Convey("When parsing '"+spec+"'", func() {
+				_, err := parseSchedule(spec)
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
+	})
+}
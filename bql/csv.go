@@ -0,0 +1,398 @@
+package bql
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// csvConfig has parameters of the csv format.
+type csvConfig struct {
+	// Header is true when the first record of the input is a header having
+	// names of columns. When Columns is also given, the header is skipped.
+	Header bool
+
+	// Columns has names of columns. When neither of Header or Columns is
+	// given, columns are named column_1, column_2, and so on.
+	Columns []string
+
+	// ColumnTypes has types of columns. The key is the name of a column and
+	// the value is one of "string", "int", "float", "bool", "timestamp", and
+	// "infer". The type of a column not having an entry is inferred from each
+	// value when InferTypes is true. Otherwise, it's "string".
+	ColumnTypes map[string]string
+
+	// InferTypes enables type inference of columns whose types aren't
+	// declared in ColumnTypes.
+	InferTypes bool
+
+	// Delimiter is the field delimiter. "\t" (a backslash followed by t) can
+	// be used to specify a tab.
+	Delimiter string
+
+	// Quote is the character enclosing a field containing delimiters or
+	// newlines. A quote in a quoted field is escaped by doubling it. An empty
+	// string disables quoting.
+	Quote string
+
+	// Comment is the prefix of lines to be ignored. An empty string disables
+	// comments.
+	Comment string
+}
+
+// csvColumnType converts a field of a csv record to a value.
+type csvColumnType func(s string) (data.Value, error)
+
+var csvColumnTypes = map[string]csvColumnType{
+	"string": func(s string) (data.Value, error) {
+		return data.String(s), nil
+	},
+	"int": func(s string) (data.Value, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return data.Null{}, nil
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to an int", s)
+		}
+		return data.Int(i), nil
+	},
+	"float": func(s string) (data.Value, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return data.Null{}, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to a float", s)
+		}
+		return data.Float(f), nil
+	},
+	"bool": func(s string) (data.Value, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return data.Null{}, nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to a bool", s)
+		}
+		return data.Bool(b), nil
+	},
+	"timestamp": func(s string) (data.Value, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return data.Null{}, nil
+		}
+		var v data.Value = data.String(s)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			v = data.Int(i) // seconds since the Unix epoch
+		} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+			v = data.Float(f)
+		}
+		t, err := data.ToTimestamp(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to a timestamp", s)
+		}
+		return data.Timestamp(t), nil
+	},
+	"infer": inferCSVValue,
+}
+
+// inferCSVValue converts a field to an int, a float, or a bool when it has
+// a valid representation of the type. An empty field is converted to null.
+// Other fields are kept as strings.
+func inferCSVValue(s string) (data.Value, error) {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return data.Null{}, nil
+	}
+	if i, err := strconv.ParseInt(t, 10, 64); err == nil {
+		return data.Int(i), nil
+	}
+	if f, err := strconv.ParseFloat(t, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return data.Float(f), nil
+	}
+	switch strings.ToLower(t) {
+	case "true":
+		return data.Bool(true), nil
+	case "false":
+		return data.Bool(false), nil
+	}
+	return data.String(s), nil
+}
+
+// newCSVDecoderCreator validates csv parameters and returns a
+// recordDecoderCreator of the csv format.
+func newCSVDecoderCreator(params data.Map) (recordDecoderCreator, error) {
+	c := &csvConfig{
+		Header:     true,
+		InferTypes: true,
+		Delimiter:  ",",
+		Quote:      `"`,
+	}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+
+	if c.Delimiter == `\t` {
+		c.Delimiter = "\t"
+	}
+	delim, err := csvSpecialChar("delimiter", c.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+	if delim == 0 {
+		return nil, errors.New("'delimiter' parameter must not be empty")
+	}
+	quote, err := csvSpecialChar("quote", c.Quote)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := csvSpecialChar("comment", c.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if delim == quote || delim == comment || (quote != 0 && quote == comment) {
+		return nil, errors.New("'delimiter', 'quote', and 'comment' parameters must be different from each other")
+	}
+
+	columns := map[string]bool{}
+	for _, col := range c.Columns {
+		if col == "" {
+			return nil, errors.New("'columns' parameter must not have an empty name")
+		}
+		if columns[col] {
+			return nil, fmt.Errorf("'columns' parameter has a duplicated name: %v", col)
+		}
+		columns[col] = true
+	}
+	defaultType := csvColumnTypes["string"]
+	if c.InferTypes {
+		defaultType = csvColumnTypes["infer"]
+	}
+	types := map[string]csvColumnType{}
+	for col, name := range c.ColumnTypes {
+		t, ok := csvColumnTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("column '%v' has an unsupported type: %v", col, name)
+		}
+		if len(columns) > 0 && !columns[col] {
+			return nil, fmt.Errorf("'column_types' parameter has an undefined column: %v", col)
+		}
+		types[col] = t
+	}
+
+	return func(r io.Reader) recordDecoder {
+		return &csvDecoder{
+			r:           bufio.NewReader(r),
+			skipHeader:  c.Header,
+			columns:     c.Columns,
+			types:       types,
+			defaultType: defaultType,
+			delimiter:   c.Delimiter,
+			quote:       c.Quote,
+			comment:     c.Comment,
+		}
+	}, nil
+}
+
+func csvSpecialChar(name, s string) (rune, error) {
+	if s == "" {
+		return 0, nil
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if n != len(s) || r == utf8.RuneError || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("'%v' parameter must be a single character: %q", name, s)
+	}
+	return r, nil
+}
+
+type csvDecoder struct {
+	r           *bufio.Reader
+	skipHeader  bool
+	columns     []string
+	types       map[string]csvColumnType
+	defaultType csvColumnType
+	delimiter   string
+	quote       string
+	comment     string
+
+	// line is the number of lines read so far and start is the line number
+	// at which the last record starts.
+	line  int
+	start int
+	eof   bool
+}
+
+func (d *csvDecoder) decode() (data.Map, error) {
+	if d.skipHeader {
+		fields, _, err := d.readRecord()
+		if err != nil {
+			if err == io.EOF {
+				return nil, err
+			}
+			return nil, fmt.Errorf("cannot read the csv header: %v", err)
+		}
+		d.skipHeader = false
+		if d.columns == nil {
+			d.columns = make([]string, len(fields))
+			for i, f := range fields {
+				if f = strings.TrimSpace(f); f == "" {
+					f = fmt.Sprintf("column_%v", i+1)
+				}
+				d.columns[i] = f
+			}
+		}
+	}
+
+	fields, raw, err := d.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	malformed := func(err error) error {
+		return &malformedRecordError{
+			format: "csv",
+			line:   d.start,
+			raw:    data.String(raw),
+			err:    err,
+		}
+	}
+	if d.columns != nil && len(fields) != len(d.columns) {
+		return nil, malformed(fmt.Errorf("the record has %v fields but %v columns are defined",
+			len(fields), len(d.columns)))
+	}
+
+	m := make(data.Map, len(fields))
+	for i, f := range fields {
+		var name string
+		if d.columns != nil {
+			name = d.columns[i]
+		} else {
+			name = fmt.Sprintf("column_%v", i+1)
+		}
+		conv, ok := d.types[name]
+		if !ok {
+			conv = d.defaultType
+		}
+		v, err := conv(f)
+		if err != nil {
+			return nil, malformed(fmt.Errorf("column '%v': %v", name, err))
+		}
+		m[name] = v
+	}
+	return m, nil
+}
+
+func (d *csvDecoder) lineNumber() int {
+	return d.start
+}
+
+// readLine reads a line without a trailing newline.
+func (d *csvDecoder) readLine() (string, error) {
+	if d.eof {
+		return "", io.EOF
+	}
+	l, err := d.r.ReadString('\n')
+	if err != nil {
+		if err != io.EOF {
+			return "", err
+		}
+		d.eof = true
+		if l == "" {
+			return "", io.EOF
+		}
+	}
+	d.line++
+	l = strings.TrimSuffix(l, "\n")
+	return strings.TrimSuffix(l, "\r"), nil
+}
+
+// readRecord reads the next record, skipping empty lines and comments. It
+// returns fields of the record and its raw text. A record can span multiple
+// lines when a quoted field contains newlines.
+func (d *csvDecoder) readRecord() ([]string, string, error) {
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			return nil, "", err
+		}
+		d.start = d.line
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if d.comment != "" && strings.HasPrefix(line, d.comment) {
+			continue
+		}
+		return d.parseRecord(line)
+	}
+}
+
+func (d *csvDecoder) parseRecord(line string) ([]string, string, error) {
+	raw := line
+	malformed := func(err error) error {
+		return &malformedRecordError{
+			format: "csv",
+			line:   d.start,
+			raw:    data.String(raw),
+			err:    err,
+		}
+	}
+
+	var fields []string
+	buf := bytes.NewBuffer(nil)
+	i := 0
+	for {
+		if d.quote == "" || !strings.HasPrefix(line[i:], d.quote) {
+			j := strings.Index(line[i:], d.delimiter)
+			if j < 0 {
+				return append(fields, line[i:]), raw, nil
+			}
+			fields = append(fields, line[i:i+j])
+			i += j + len(d.delimiter)
+			continue
+		}
+
+		// quoted field
+		i += len(d.quote)
+		buf.Reset()
+		for {
+			j := strings.Index(line[i:], d.quote)
+			if j < 0 {
+				buf.WriteString(line[i:])
+				buf.WriteByte('\n')
+				next, err := d.readLine()
+				if err != nil {
+					if err == io.EOF {
+						return nil, raw, malformed(errors.New("a quoted field isn't terminated"))
+					}
+					return nil, raw, err
+				}
+				raw += "\n" + next
+				line, i = next, 0
+				continue
+			}
+			buf.WriteString(line[i : i+j])
+			i += j + len(d.quote)
+			if !strings.HasPrefix(line[i:], d.quote) {
+				break
+			}
+			buf.WriteString(d.quote) // escaped quote
+			i += len(d.quote)
+		}
+		fields = append(fields, buf.String())
+
+		if i == len(line) {
+			return fields, raw, nil
+		}
+		if !strings.HasPrefix(line[i:], d.delimiter) {
+			return nil, raw, malformed(fmt.Errorf("unexpected character after a closing quote at column %v", i+1))
+		}
+		i += len(d.delimiter)
+	}
+}
@@ -0,0 +1,209 @@
+package bql
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestCSVDecoder(t *testing.T) {
+	Convey("Given csv parameters", t, func() {
+		params := data.Map{}
+		decodeAll := func(input string) ([]data.Map, []*malformedRecordError) {
+			c, err := newCSVDecoderCreator(params)
+			So(err, ShouldBeNil)
+			dec := c(strings.NewReader(input))
+			var ms []data.Map
+			var errs []*malformedRecordError
+			for {
+				m, err := dec.decode()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					e, ok := err.(*malformedRecordError)
+					So(ok, ShouldBeTrue)
+					errs = append(errs, e)
+					continue
+				}
+				ms = append(ms, m)
+			}
+			return ms, errs
+		}
+
+		Convey("When decoding records with a header", func() {
+			ms, errs := decodeAll("id,temp,ok,name\r\n1,20.5,true,a\n\n2,,FALSE,\"b,\"\"c\"\"\"\n3,x,true,")
+
+			Convey("Then it should infer types of values", func() {
+				So(errs, ShouldBeEmpty)
+				So(ms, ShouldResemble, []data.Map{
+					{"id": data.Int(1), "temp": data.Float(20.5), "ok": data.True, "name": data.String("a")},
+					{"id": data.Int(2), "temp": data.Null{}, "ok": data.False, "name": data.String(`b,"c"`)},
+					{"id": data.Int(3), "temp": data.String("x"), "ok": data.True, "name": data.Null{}},
+				})
+			})
+		})
+
+		Convey("When decoding records with declared types", func() {
+			params["column_types"] = data.Map{
+				"id":   data.String("string"),
+				"temp": data.String("float"),
+				"ts":   data.String("timestamp"),
+			}
+			ms, errs := decodeAll("id,temp,ts\n001,1,2015-04-10T10:23:00Z\n002,abc,0\n003,2,1428661380")
+
+			Convey("Then values should be converted to the types", func() {
+				So(ms, ShouldResemble, []data.Map{
+					{"id": data.String("001"), "temp": data.Float(1),
+						"ts": data.Timestamp(time.Date(2015, time.April, 10, 10, 23, 0, 0, time.UTC))},
+					{"id": data.String("003"), "temp": data.Float(2),
+						"ts": data.Timestamp(time.Unix(1428661380, 0))},
+				})
+			})
+
+			Convey("Then a record having an invalid value should be reported", func() {
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].line, ShouldEqual, 3)
+				So(errs[0].raw, ShouldEqual, data.String("002,abc,0"))
+				So(errs[0].Error(), ShouldContainSubstring, "temp")
+			})
+		})
+
+		Convey("When decoding records without a header", func() {
+			params["header"] = data.False
+			params["infer_types"] = data.False
+
+			Convey("Then columns should be named by their positions", func() {
+				ms, errs := decodeAll("1,a\n2,b,c\n")
+				So(errs, ShouldBeEmpty)
+				So(ms, ShouldResemble, []data.Map{
+					{"column_1": data.String("1"), "column_2": data.String("a")},
+					{"column_1": data.String("2"), "column_2": data.String("b"), "column_3": data.String("c")},
+				})
+			})
+
+			Convey("Then given column names should be used", func() {
+				params["columns"] = data.Array{data.String("x"), data.String("y")}
+				ms, errs := decodeAll("1,a\n2,b,c\n3,c")
+				So(ms, ShouldResemble, []data.Map{
+					{"x": data.String("1"), "y": data.String("a")},
+					{"x": data.String("3"), "y": data.String("c")},
+				})
+				So(errs, ShouldHaveLength, 1)
+				So(errs[0].line, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When decoding records with a custom delimiter, quote, and comment", func() {
+			params["delimiter"] = data.String(`\t`)
+			params["quote"] = data.String("'")
+			params["comment"] = data.String("#")
+			ms, errs := decodeAll("# comment\nname\tnote\n'a\tb'\t'multi\nline'\n#x\ny\t'it''s'\n")
+
+			Convey("Then fields should be split correctly", func() {
+				So(errs, ShouldBeEmpty)
+				So(ms, ShouldResemble, []data.Map{
+					{"name": data.String("a\tb"), "note": data.String("multi\nline")},
+					{"name": data.String("y"), "note": data.String("it's")},
+				})
+			})
+		})
+
+		Convey("When decoding malformed quoted fields", func() {
+			ms, errs := decodeAll("a,b\n\"x\"y,1\n2,3\n\"4,5\n")
+
+			Convey("Then malformed records should be reported", func() {
+				So(ms, ShouldResemble, []data.Map{{"a": data.Int(2), "b": data.Int(3)}})
+				So(errs, ShouldHaveLength, 2)
+				So(errs[0].line, ShouldEqual, 2)
+				So(errs[1].line, ShouldEqual, 4)
+			})
+		})
+
+		Convey("When creating a decoder with invalid parameters", func() {
+			for k, v := range map[string]data.Value{
+				"delimiter":    data.String(""),
+				"quote":        data.String(",,"),
+				"comment":      data.String(","),
+				"column_types": data.Map{"a": data.String("date")},
+			} {
+				params[k] = v
+				_, err := newCSVDecoderCreator(params)
+				So(err, ShouldNotBeNil)
+				delete(params, k)
+			}
+
+			Convey("Then types of undefined columns should result in an error", func() {
+				params["columns"] = data.Array{data.String("a")}
+				params["column_types"] = data.Map{"b": data.String("int")}
+				_, err := newCSVDecoderCreator(params)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestCSVFileSource(t *testing.T) {
+	f, err := ioutil.TempFile("", "sbtest_bql_csv_file_source")
+	if err != nil {
+		t.Fatal("Cannot create a temp file:", err)
+	}
+	name := f.Name()
+	defer os.Remove(name)
+	_, err = io.WriteString(f, "id,value\n1,10\n2,bad\n3,30\n")
+	f.Close()
+	if err != nil {
+		t.Fatal("Cannot write to the temp file:", err)
+	}
+
+	Convey("Given a topology reading a csv file", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE SOURCE d TYPE dropped_tuples;
+			CREATE SINK dk TYPE collector;
+			INSERT INTO dk FROM d;
+			CREATE SINK k TYPE collector;
+			CREATE PAUSED SOURCE f TYPE file WITH path="`+name+`", format="csv",
+				column_types={"value": "int"};
+			INSERT INTO k FROM f;
+			RESUME SOURCE f;`), ShouldBeNil)
+
+		sn, err := dt.Sink("k")
+		So(err, ShouldBeNil)
+		si := sn.Sink().(*tupleCollectorSink)
+		dn, err := dt.Sink("dk")
+		So(err, ShouldBeNil)
+		di := dn.Sink().(*tupleCollectorSink)
+
+		Convey("When the source emits tuples", func() {
+			si.Wait(2)
+			di.Wait(1)
+
+			Convey("Then valid records should be emitted", func() {
+				So(si.get(0).Data, ShouldResemble, data.Map{"id": data.Int(1), "value": data.Int(10)})
+				So(si.get(1).Data, ShouldResemble, data.Map{"id": data.Int(3), "value": data.Int(30)})
+			})
+
+			Convey("Then the bad record should be routed to dropped tuples", func() {
+				d := di.get(0).Data
+				So(d["node_name"], ShouldEqual, data.String("f"))
+				So(d["data"], ShouldResemble, data.Map{
+					"format":      data.String("csv"),
+					"line_number": data.Int(3),
+					"body":        data.String("2,bad"),
+				})
+				So(d["error"], ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package bql
+
+import (
+	"errors"
+	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+// edgeTransformer evaluates the projections and the filter of an
+// INSERT INTO ... SELECT ... FROM ... WHERE statement on each tuple written
+// to the sink. It's equivalent to a stream created by
+// SELECT RSTREAM ... FROM input [RANGE 1 TUPLES] WHERE ..., but it doesn't
+// require a box between the input and the sink.
+type edgeTransformer struct {
+	// project is false when the statement only has a filter. In that case,
+	// the input tuple is written as is.
+	project bool
+
+	// m protects plan because PhysicalPlan.Process isn't thread-safe.
+	m    sync.Mutex
+	plan execution.PhysicalPlan
+}
+
+// newEdgeTransformer creates an EdgeTransformer from the statement. It
+// returns nil when the statement has neither projections nor a filter.
+func newEdgeTransformer(stmt *parser.InsertIntoFromStmt, reg udf.FunctionRegistry) (core.EdgeTransformer, error) {
+	if stmt.Projections == nil && stmt.Filter == nil {
+		return nil, nil
+	}
+
+	projs := stmt.Projections
+	if projs == nil {
+		projs = []parser.Expression{parser.Wildcard{}}
+	}
+	s := parser.SelectStmt{
+		EmitterAST:     parser.EmitterAST{EmitterType: parser.Rstream},
+		ProjectionsAST: parser.ProjectionsAST{Projections: projs},
+		WindowedFromAST: parser.WindowedFromAST{
+			Relations: []parser.AliasedStreamWindowAST{{
+				StreamWindowAST: parser.StreamWindowAST{
+					Stream: parser.NewStream(string(stmt.Input)),
+					IntervalAST: parser.IntervalAST{
+						FloatLiteral: parser.FloatLiteral{Value: 1},
+						Unit:         parser.Tuples,
+					},
+					Capacity: parser.UnspecifiedCapacity,
+				},
+			}},
+		},
+		FilterAST: stmt.FilterAST,
+	}
+
+	lp, err := execution.Analyze(s, reg)
+	if err != nil {
+		return nil, err
+	}
+	if !execution.CanBuildFilterPlan(lp, reg) {
+		return nil, errors.New("aggregate functions cannot be used in INSERT INTO ... SELECT")
+	}
+	plan, err := execution.NewFilterPlan(lp, reg)
+	if err != nil {
+		return nil, err
+	}
+	return &edgeTransformer{
+		project: stmt.Projections != nil,
+		plan:    plan,
+	}, nil
+}
+
+func (e *edgeTransformer) Transform(ctx *core.Context, t *core.Tuple) (*core.Tuple, error) {
+	e.m.Lock()
+	res, err := e.plan.Process(t)
+	e.m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	if !e.project {
+		return t, nil
+	}
+
+	out := t.ShallowCopy()
+	out.Data = res[0]
+	return out, nil
+}
@@ -6,6 +6,7 @@ import (
 	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"time"
 )
 
 type aliasedEvaluator struct {
@@ -19,9 +20,28 @@ type aliasedEvaluator struct {
 type commonExecutionPlan struct {
 	projections []aliasedEvaluator
 	groupList   []Evaluator
+	// groupPaths holds, for each item in groupList, the path where
+	// the value of that item is stored in the per-group data, or
+	// nil if it does not need to be stored there.
+	groupPaths []data.Path
+	// groupingSets holds the grouping sets as lists of indexes
+	// into groupList, or nil if there is only one (implicit)
+	// grouping set that contains all items.
+	groupingSets [][]int
 	// filter stores the evaluator of the filter condition,
 	// or nil if there is no WHERE clause.
 	filter Evaluator
+	// clock returns the time accessed by the now() function.
+	clock func() time.Time
+}
+
+// clockOf returns the clock of the Context of the registry. It returns the
+// wall clock when the registry doesn't have a Context.
+func clockOf(reg udf.FunctionRegistry) func() time.Time {
+	if ctx := reg.Context(); ctx != nil {
+		return ctx.Now
+	}
+	return time.Now
 }
 
 func prepareProjections(projections []aliasedExpression, reg udf.FunctionRegistry) ([]aliasedEvaluator, error) {
@@ -46,7 +66,8 @@ func prepareProjections(projections []aliasedExpression, reg udf.FunctionRegistr
 			}
 		}
 		var path data.Path
-		if proj.alias != "*" && proj.alias != ":having:" {
+		if proj.alias != "*" && proj.alias != ":having:" &&
+			proj.alias != ":emit_when:" {
 			path, err = data.CompilePath(proj.alias)
 			if err != nil {
 				return nil, err
@@ -77,17 +98,49 @@ func prepareGroupList(groupList []FlatExpression, reg udf.FunctionRegistry) ([]E
 	return output, nil
 }
 
+// prepareGroupPaths computes the paths where the values of the given
+// GROUP BY expressions are stored for evaluating projections. Single
+// columns can be read from the input row itself, so they only need to
+// be overwritten when grouping sets are used (in order to set columns
+// not contained in a grouping set to NULL).
+func prepareGroupPaths(groupList []FlatExpression, groupingSets [][]int) ([]data.Path, error) {
+	output := make([]data.Path, len(groupList))
+	for i, expr := range groupList {
+		var key string
+		if rv, ok := expr.(rowValue); ok {
+			if groupingSets == nil {
+				continue
+			}
+			key = rv.path()
+		} else {
+			key = groupExprRef(expr)
+		}
+		path, err := data.CompilePath(key)
+		if err != nil {
+			return nil, err
+		}
+		output[i] = path
+	}
+	return output, nil
+}
+
 // setMetadata adds the metadata contained in the given Tuple into the
 // given Map with a key constructed using the given alias string. For example,
 //   {"alias": {"col_0": ..., "col_1": ...}}
 // is transformed into
 //   {"alias": {"col_0": ..., "col_1": ...},
-//    "alias:meta:TS": (timestamp of the given tuple)}
-// so that the Evaluator created from a parser.RowMeta AST struct works correctly.
+//    "alias:meta:TS": (timestamp of the given tuple),
+//    "alias:meta:META": (metadata of the given tuple, if any)}
+// so that the Evaluator created from a parser.RowMeta or parser.RowMetadata
+// AST struct works correctly.
 func setMetadata(where data.Map, alias string, t *core.Tuple) {
 	// this key format is also used in ExpressionToEvaluator()
 	tsKey := fmt.Sprintf("%s:meta:%s", alias, parser.TimestampMeta)
 	where[tsKey] = data.Timestamp(t.Timestamp)
+	if t.Metadata != nil {
+		metaKey := fmt.Sprintf("%s:meta:%s", alias, parser.MetadataMeta)
+		where[metaKey] = t.Metadata
+	}
 }
 
 // assignOutputValue writes the given Value `value` to the given
@@ -44,6 +44,11 @@ func (ep *defaultSelectExecutionPlan) Process(input *core.Tuple) ([]data.Map, er
 	return ep.process(input, ep.performQueryOnBuffer)
 }
 
+// Tick returns the results of TUMBLING windows closed by processing time.
+func (ep *defaultSelectExecutionPlan) Tick() ([]data.Map, error) {
+	return ep.tick(ep.performQueryOnBuffer)
+}
+
 // performQueryOnBuffer computes the projections of a SELECT query on the data
 // stored in `ep.filteredInputRows`. The query results (which is a set of
 // data.Value, not core.Tuple) is stored in ep.curResults. The data
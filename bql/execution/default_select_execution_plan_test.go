@@ -214,6 +214,36 @@ func TestDefaultSelectExecutionPlan(t *testing.T) {
 		})
 	})
 
+	// Select the tuple's metadata
+	Convey("Given a SELECT clause with metadata", t, func() {
+		tuples := getTuples(4)
+		for i, t := range tuples {
+			if i%2 == 0 {
+				t.Metadata = data.Map{"offset": data.Int(i * 10)}
+			}
+		}
+		s := `CREATE STREAM box AS SELECT ISTREAM src:meta("offset"), src:meta("none") AS n FROM src [RANGE 2 SECONDS]`
+		plan, err := createDefaultSelectPlan(s, t)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			for idx, inTup := range tuples {
+				out, err := plan.Process(inTup)
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("Then those values should appear in %v", idx), func() {
+					var offset data.Value = data.Null{}
+					if idx%2 == 0 {
+						offset = data.Int(idx * 10)
+					}
+					So(len(out), ShouldEqual, 1)
+					So(out[0], ShouldResemble, data.Map{"offset": offset, "n": data.Null{}})
+				})
+			}
+
+		})
+	})
+
 	// Select a non-existing column
 	Convey("Given a SELECT clause with a non-existing column", t, func() {
 		tuples := getTuples(4)
@@ -2077,6 +2107,146 @@ func TestDefaultSelectExecutionPlanJoin(t *testing.T) {
 	})
 }
 
+type testKeyedState struct {
+	data map[string]data.Map
+}
+
+func (s *testKeyedState) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (s *testKeyedState) Lookup(ctx *core.Context, key data.Value) (data.Map, error) {
+	k, err := data.ToString(key)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := s.data[k]
+	if !ok {
+		return nil, core.NotExistError(fmt.Errorf("key '%v' was not found", k))
+	}
+	return m, nil
+}
+
+type testPlainState struct {
+}
+
+func (s *testPlainState) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func createStateJoinPlan(s string, ctx *core.Context) (PhysicalPlan, error) {
+	p := parser.New()
+	reg := udf.CopyGlobalUDFRegistry(ctx)
+	_stmt, _, err := p.ParseStmt(s)
+	if err != nil {
+		return nil, err
+	}
+	stmt := _stmt.(parser.CreateStreamAsSelectStmt).Select
+	logicalPlan, err := Analyze(stmt, reg)
+	if err != nil {
+		return nil, err
+	}
+	optimizedPlan, err := logicalPlan.LogicalOptimize()
+	if err != nil {
+		return nil, err
+	}
+	return optimizedPlan.MakePhysicalPlan(reg)
+}
+
+func TestDefaultSelectExecutionPlanStateJoin(t *testing.T) {
+	ctx := core.NewContext(nil)
+	devices := &testKeyedState{map[string]data.Map{
+		"1": {"name": data.String("d1")},
+		"3": {"name": data.String("d3")},
+	}}
+	if err := ctx.SharedStates.Add("devices", "test", devices); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.SharedStates.Add("plain", "test", &testPlainState{}); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given a SELECT statement joining a state", t, func() {
+		tuples := getTuples(4)
+		s := `CREATE STREAM box AS SELECT RSTREAM src:int, d:name FROM src [RANGE 1 TUPLES]
+			JOIN STATE devices AS d ON src:int = d:key`
+		plan, err := createStateJoinPlan(s, ctx)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			for idx, inTup := range tuples {
+				out, err := plan.Process(inTup)
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("Then only tuples with a key in the state should be enriched in %v", idx), func() {
+					if idx%2 == 0 {
+						So(len(out), ShouldEqual, 1)
+						So(out[0], ShouldResemble, data.Map{
+							"int":  data.Int(idx + 1),
+							"name": data.String(fmt.Sprintf("d%d", idx+1)),
+						})
+					} else {
+						So(out, ShouldBeEmpty)
+					}
+				})
+			}
+		})
+	})
+
+	Convey("Given a SELECT statement joining a state with aggregation", t, func() {
+		tuples := getTuples(4)
+		s := `CREATE STREAM box AS SELECT RSTREAM count(*) AS c FROM src [RANGE 4 TUPLES]
+			JOIN STATE devices ON devices:key = src:int`
+		plan, err := createStateJoinPlan(s, ctx)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			var out []data.Map
+			for _, inTup := range tuples {
+				out, err = plan.Process(inTup)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then only matching rows should be counted", func() {
+				So(out, ShouldResemble, []data.Map{{"c": data.Int(2)}})
+			})
+		})
+	})
+
+	Convey("Given a SELECT statement joining a state without key lookups", t, func() {
+		tuples := getTuples(1)
+		s := `CREATE STREAM box AS SELECT RSTREAM src:int FROM src [RANGE 1 TUPLES]
+			JOIN STATE plain ON src:int = plain:key`
+		plan, err := createStateJoinPlan(s, ctx)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with a tuple", func() {
+			_, err := plan.Process(tuples[0])
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "doesn't support key lookups")
+			})
+		})
+	})
+
+	Convey("Given SELECT statements with invalid JOIN STATE clauses", t, func() {
+		for stmt, msg := range map[string]string{
+			`src:int FROM src [RANGE 1 TUPLES] JOIN STATE devices ON src:int < devices:key`:        "must have the form 'expression = devices:key'",
+			`src:int FROM src [RANGE 1 TUPLES] JOIN STATE devices ON devices:name = devices:key`:   "cannot be computed from the state itself",
+			`src:int FROM src [RANGE 1 TUPLES] JOIN STATE devices AS src ON 1 = src:key`:           "cannot use relation 'src' and state 'devices' with the same alias 'src'",
+			`int FROM src [RANGE 1 TUPLES] JOIN STATE devices ON src:int = devices:key`:            "cannot reference relation ''",
+			`src:int FROM src [RANGE 1 TUPLES] JOIN STATE devices ON count(src:int) = devices:key`: "aggregates not allowed in JOIN STATE clause",
+		} {
+			Convey("Then creating a plan for "+stmt+" should fail", func() {
+				_, err := createStateJoinPlan("CREATE STREAM box AS SELECT RSTREAM "+stmt, ctx)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, msg)
+			})
+		}
+	})
+}
+
 func createDefaultSelectPlan2(s string) (PhysicalPlan, error) {
 	p := parser.New()
 	reg := udf.CopyGlobalUDFRegistry(core.NewContext(nil))
@@ -2097,6 +2267,65 @@ func createDefaultSelectPlan2(s string) (PhysicalPlan, error) {
 	return NewDefaultSelectExecutionPlan(logicalPlan, reg)
 }
 
+// sortedResults returns results of a plan as sorted strings so that results
+// whose order is undefined can be compared.
+func sortedResults(ms []data.Map) []string {
+	res := make([]string, len(ms))
+	for i, m := range ms {
+		res[i] = m.String()
+	}
+	sort.Strings(res)
+	return res
+}
+
+func TestDefaultSelectExecutionPlanBufferedTuples(t *testing.T) {
+	Convey("Given a self-join statement having windows of different sizes", t, func() {
+		s := `CREATE STREAM box AS SELECT ISTREAM l:int AS l, r:int AS r
+			FROM src [RANGE 2 TUPLES] AS l, src [RANGE 3 TUPLES] AS r`
+		plan, err := createDefaultSelectPlan(s, t)
+		So(err, ShouldBeNil)
+		bp, ok := plan.(BufferedPlan)
+		So(ok, ShouldBeTrue)
+
+		Convey("When feeding tuples", func() {
+			tuples := getTuples(4)
+			for _, inTup := range tuples {
+				_, err := plan.Process(inTup)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then it should return buffered tuples once in the arrival order", func() {
+				ts := bp.BufferedTuples()
+				So(len(ts), ShouldEqual, 3)
+				for i, tup := range ts {
+					So(tup.Data, ShouldResemble, tuples[i+1].Data)
+					So(tup.InputName, ShouldEqual, "src")
+					So(tup.Timestamp, ShouldResemble, tuples[i+1].Timestamp)
+				}
+			})
+
+			Convey("And feeding them to a new plan", func() {
+				plan2, err := createDefaultSelectPlan(s, t)
+				So(err, ShouldBeNil)
+				for _, tup := range bp.BufferedTuples() {
+					_, err := plan2.Process(tup)
+					So(err, ShouldBeNil)
+				}
+
+				Convey("Then both plans should return the same result", func() {
+					next := getTuples(5)[4]
+					out, err := plan.Process(next.Copy())
+					So(err, ShouldBeNil)
+					out2, err := plan2.Process(next.Copy())
+					So(err, ShouldBeNil)
+					// the order of results is undefined
+					So(sortedResults(out2), ShouldResemble, sortedResults(out))
+				})
+			})
+		})
+	})
+}
+
 func BenchmarkNormalExecution(b *testing.B) {
 	s := `CREATE STREAM box AS SELECT ISTREAM cast(3+4-6+1 as float), 3.0::int*4/2+1=7.0,
 			null, [2.0,3] = [2,3.0] FROM src [RANGE 5 TUPLES]`
@@ -85,6 +85,10 @@ func ExpressionToEvaluator(ast FlatExpression, reg udf.FunctionRegistry) (Evalua
 			}
 			return &timestampCast{pa}, nil
 		}
+	case rowMetadata:
+		// construct a key for reading as used in setMetadata() for writing
+		metaKey := fmt.Sprintf("%s:meta:%s", obj.Relation, parser.MetadataMeta)
+		return &metadataAccess{metaKey, obj.Key}, nil
 	case stmtMeta:
 		// construct a key for reading as used in setMetadata() for writing
 		metaKey := fmt.Sprintf(`[":meta:%s"]`, obj.MetaType)
@@ -96,17 +100,11 @@ func ExpressionToEvaluator(ast FlatExpression, reg udf.FunctionRegistry) (Evalua
 			return &timestampCast{pa}, nil
 		}
 	case rowValue:
-		path := obj.Column
-		if obj.Relation != "" {
-			if strings.HasPrefix(path, "[") {
-				path = obj.Relation + path
-			} else {
-				path = obj.Relation + "." + path
-			}
-		}
-		return newPathAccess(path)
+		return newPathAccess(obj.path())
 	case aggInputRef:
 		return newPathAccess(obj.Ref)
+	case groupInputRef:
+		return newPathAccess(obj.Ref)
 	case nullLiteral:
 		return &nullConstant{}, nil
 	case numericLiteral:
@@ -164,15 +162,15 @@ func ExpressionToEvaluator(ast FlatExpression, reg udf.FunctionRegistry) (Evalua
 				return newNot(newIsNull(left)), nil
 			}
 		case parser.Plus:
-			return newPlus(bo), nil
+			return newPlus(bo, numericPolicyOf(reg)), nil
 		case parser.Minus:
-			return newMinus(bo), nil
+			return newMinus(bo, numericPolicyOf(reg)), nil
 		case parser.Multiply:
-			return newMultiply(bo), nil
+			return newMultiply(bo, numericPolicyOf(reg)), nil
 		case parser.Divide:
-			return newDivide(bo), nil
+			return newDivide(bo, numericPolicyOf(reg)), nil
 		case parser.Modulo:
-			return newModulo(bo), nil
+			return newModulo(bo, numericPolicyOf(reg)), nil
 		}
 	case unaryOpAST:
 		// recurse
@@ -190,7 +188,7 @@ func ExpressionToEvaluator(ast FlatExpression, reg udf.FunctionRegistry) (Evalua
 		case parser.UnaryMinus:
 			// implement negation as multiplication with -1
 			bo := binOp{expr, &intConstant{-1}}
-			return newMultiply(bo), nil
+			return newMultiply(bo, numericPolicyOf(reg)), nil
 		}
 	case missing:
 		// recurse
@@ -364,6 +362,33 @@ func newPathAccess(s string) (Evaluator, error) {
 	return &pathAccess{path}, nil
 }
 
+// metadataAccess reads a value from the metadata of an input tuple stored
+// by setMetadata(). It returns Null when the tuple doesn't have metadata or
+// the key doesn't exist in it.
+type metadataAccess struct {
+	metaKey string
+	key     string
+}
+
+func (ma *metadataAccess) Eval(input data.Value) (data.Value, error) {
+	aMap, err := data.AsMap(input)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := aMap[ma.metaKey]
+	if !ok {
+		return data.Null{}, nil
+	}
+	meta, err := data.AsMap(v)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := meta[ma.key]; ok {
+		return v, nil
+	}
+	return data.Null{}, nil
+}
+
 type missingPathCheck struct {
 	eval   pathAccess
 	negate bool
@@ -775,6 +800,15 @@ type numBinOp struct {
 	verb    string
 	intOp   func(int64, int64) int64
 	floatOp func(float64, float64) float64
+
+	// intOverflow returns the saturated result and true when intOp
+	// overflows. It's nil when intOp never overflows.
+	intOverflow func(int64, int64) (int64, bool)
+	// divides is true when the right operand is a divisor, and modulo is
+	// true when the operator is %.
+	divides bool
+	modulo  bool
+	policy  NumericPolicy
 }
 
 func (nbo *numBinOp) Eval(input data.Value) (v data.Value, err error) {
@@ -797,6 +831,11 @@ func (nbo *numBinOp) Eval(input data.Value) (v data.Value, err error) {
 		return data.Null{}, nil
 	}
 	stdErr := fmt.Errorf("cannot %s %T and %T", nbo.verb, leftVal, rightVal)
+	if nbo.divides && isNumber(leftVal) && isZero(rightVal) {
+		if v, err := nbo.divisionByZero(leftVal); v != nil || err != nil {
+			return v, err
+		}
+	}
 	// if we have same types (both int64 or both float64, apply
 	// the corresponding operation)
 	if leftType == rightType {
@@ -806,80 +845,81 @@ func (nbo *numBinOp) Eval(input data.Value) (v data.Value, err error) {
 		case data.TypeInt:
 			l, _ := data.AsInt(leftVal)
 			r, _ := data.AsInt(rightVal)
-			return data.Int(nbo.intOp(l, r)), nil
+			return nbo.checkInt(l, r, nbo.intOp(l, r))
 		case data.TypeFloat:
 			l, _ := data.AsFloat(leftVal)
 			r, _ := data.AsFloat(rightVal)
-			return data.Float(nbo.floatOp(l, r)), nil
+			return nbo.checkFloat(l, r, nbo.floatOp(l, r))
 		}
 	} else if leftType == data.TypeInt && rightType == data.TypeFloat {
 		// left is integer
 		l, _ := data.AsInt(leftVal)
 		// right is float; also convert left to float, possibly losing precision
 		r, _ := data.AsFloat(rightVal)
-		return data.Float(nbo.floatOp(float64(l), r)), nil
+		return nbo.checkFloat(float64(l), r, nbo.floatOp(float64(l), r))
 	} else if leftType == data.TypeFloat && rightType == data.TypeInt {
 		// left is float
 		l, _ := data.AsFloat(leftVal)
 		// right is int; convert right to float, possibly losing precision
 		r, _ := data.AsInt(rightVal)
-		return data.Float(nbo.floatOp(l, float64(r))), nil
+		return nbo.checkFloat(l, float64(r), nbo.floatOp(l, float64(r)))
 	}
 	return nil, stdErr
 }
 
-func newPlus(bo binOp) Evaluator {
-	// we do not check for overflows
+func newPlus(bo binOp, p NumericPolicy) Evaluator {
 	intOp := func(a, b int64) int64 {
 		return a + b
 	}
 	floatOp := func(a, b float64) float64 {
 		return a + b
 	}
-	return &numBinOp{bo, "add", intOp, floatOp}
+	return &numBinOp{binOp: bo, verb: "add", intOp: intOp, floatOp: floatOp,
+		intOverflow: plusOverflow, policy: p}
 }
 
-func newMinus(bo binOp) Evaluator {
-	// we do not check for overflows
+func newMinus(bo binOp, p NumericPolicy) Evaluator {
 	intOp := func(a, b int64) int64 {
 		return a - b
 	}
 	floatOp := func(a, b float64) float64 {
 		return a - b
 	}
-	return &numBinOp{bo, "subtract", intOp, floatOp}
+	return &numBinOp{binOp: bo, verb: "subtract", intOp: intOp, floatOp: floatOp,
+		intOverflow: minusOverflow, policy: p}
 }
 
-func newMultiply(bo binOp) Evaluator {
-	// we do not check for overflows
+func newMultiply(bo binOp, p NumericPolicy) Evaluator {
 	intOp := func(a, b int64) int64 {
 		return a * b
 	}
 	floatOp := func(a, b float64) float64 {
 		return a * b
 	}
-	return &numBinOp{bo, "multiply", intOp, floatOp}
+	return &numBinOp{binOp: bo, verb: "multiply", intOp: intOp, floatOp: floatOp,
+		intOverflow: multiplyOverflow, policy: p}
 }
 
-func newDivide(bo binOp) Evaluator {
-	// we do not check for overflows
+func newDivide(bo binOp, p NumericPolicy) Evaluator {
 	intOp := func(a, b int64) int64 {
 		return a / b
 	}
 	floatOp := func(a, b float64) float64 {
 		return a / b
 	}
-	return &numBinOp{bo, "divide", intOp, floatOp}
+	return &numBinOp{binOp: bo, verb: "divide", intOp: intOp, floatOp: floatOp,
+		intOverflow: divideOverflow, divides: true, policy: p}
 }
 
-func newModulo(bo binOp) Evaluator {
+func newModulo(bo binOp, p NumericPolicy) Evaluator {
 	intOp := func(a, b int64) int64 {
 		return a % b
 	}
 	floatOp := func(a, b float64) float64 {
 		return math.Mod(a, b)
 	}
-	return &numBinOp{bo, "compute modulo for", intOp, floatOp}
+	return &numBinOp{binOp: bo, verb: "compute modulo for", intOp: intOp, floatOp: floatOp,
+		divides: true, modulo: true, policy: p}
 }
 
 /// Other Binary Operations
@@ -931,7 +971,7 @@ func (f *funcApp) Eval(input data.Value) (v data.Value, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			v = nil
-			err = fmt.Errorf("evaluating '%s' paniced: %s", f.name, r)
+			err = core.PanicError(fmt.Errorf("evaluating '%s' paniced: %s", f.name, r))
 		}
 	}()
 	// evaluate all the parameters and store the results
@@ -981,6 +1021,9 @@ func (f *funcApp) Eval(input data.Value) (v data.Value, err error) {
 // FuncApp represents evaluation of a function on a number
 // of parameters that are expressions over an input Value.
 func FuncApp(name string, f udf.UDF, ctx *core.Context, params []Evaluator) Evaluator {
+	if u, ok := f.(udf.UDAFUDF); ok {
+		f = &parallelUDAF{u}
+	}
 	fVal := reflect.ValueOf(f.Call)
 	paramValues := make([]reflect.Value, len(params)+1)
 	paramValues[0] = reflect.ValueOf(ctx)
@@ -1024,7 +1067,7 @@ func (s *sortedInputAggFuncApp) Eval(input data.Value) (v data.Value, err error)
 	defer func() {
 		if r := recover(); r != nil {
 			v = nil
-			err = fmt.Errorf("evaluating %v paniced: %s", s.f, r)
+			err = core.PanicError(fmt.Errorf("evaluating %v paniced: %s", s.f, r))
 		}
 	}()
 	inputMap, err := data.AsMap(input)
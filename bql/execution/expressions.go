@@ -88,6 +88,8 @@ func ParserExprToFlatExpr(e parser.Expression, reg udf.FunctionRegistry) (FlatEx
 	switch obj := e.(type) {
 	case parser.RowMeta:
 		return rowMeta{obj.Relation, obj.MetaType}, nil
+	case parser.RowMetadata:
+		return rowMetadata{obj.Relation, obj.Key}, nil
 	case parser.RowValue:
 		return rowValue{obj.Relation, obj.Column}, nil
 	case parser.AliasAST:
@@ -989,6 +991,88 @@ func (a aggInputRef) ContainsWildcard() bool {
 	return false
 }
 
+// groupInputRef references the value of a GROUP BY expression that
+// was computed once per group, e.g., `floor(x:ts/60)` in
+// `SELECT floor(x:ts/60), count(*) ... GROUP BY floor(x:ts/60)`.
+type groupInputRef struct {
+	Ref string
+}
+
+func (g groupInputRef) Repr() string {
+	return g.Ref
+}
+
+func (g groupInputRef) Columns() []rowValue {
+	// the columns used in the referenced expression are
+	// covered by the GROUP BY clause
+	return nil
+}
+
+func (g groupInputRef) Volatility() VolatilityType {
+	// the value is the same for all rows of a group
+	return Stable
+}
+
+func (g groupInputRef) ContainsWildcard() bool {
+	return false
+}
+
+// groupExprRef computes the key that is used to store the value of
+// the given GROUP BY expression in the per-group data.
+func groupExprRef(expr FlatExpression) string {
+	h := sha1.New()
+	h.Write([]byte(expr.Repr()))
+	return "k_" + hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+// replaceGroupExprs returns a copy of the given expression where all
+// sub-expressions whose Repr() is a key of refs are replaced by a
+// groupInputRef with the respective value. Inputs of aggregate
+// functions have already been replaced by aggInputRefs at this point
+// and are not affected.
+func replaceGroupExprs(expr FlatExpression, refs map[string]string) FlatExpression {
+	if ref, ok := refs[expr.Repr()]; ok {
+		return groupInputRef{ref}
+	}
+	replaceAll := func(exprs []FlatExpression) []FlatExpression {
+		newExprs := make([]FlatExpression, len(exprs))
+		for i, e := range exprs {
+			newExprs[i] = replaceGroupExprs(e, refs)
+		}
+		return newExprs
+	}
+	switch obj := expr.(type) {
+	case binaryOpAST:
+		return binaryOpAST{obj.Op, replaceGroupExprs(obj.Left, refs),
+			replaceGroupExprs(obj.Right, refs)}
+	case unaryOpAST:
+		return unaryOpAST{obj.Op, replaceGroupExprs(obj.Expr, refs)}
+	case typeCastAST:
+		return typeCastAST{replaceGroupExprs(obj.Expr, refs), obj.Target}
+	case funcAppAST:
+		return funcAppAST{obj.Function, replaceAll(obj.Expressions)}
+	case funcAppSelectorAST:
+		return funcAppSelectorAST{replaceGroupExprs(obj.Expr, refs), obj.Selector}
+	case arrayAST:
+		return arrayAST{replaceAll(obj.Expressions)}
+	case mapAST:
+		pairs := make([]keyValuePair, len(obj.Entries))
+		for i, p := range obj.Entries {
+			pairs[i] = keyValuePair{p.Key, replaceGroupExprs(p.Value, refs)}
+		}
+		return mapAST{pairs}
+	case caseAST:
+		checks := make([]whenThenPair, len(obj.Checks))
+		for i, p := range obj.Checks {
+			checks[i] = whenThenPair{replaceGroupExprs(p.When, refs),
+				replaceGroupExprs(p.Then, refs)}
+		}
+		return caseAST{replaceGroupExprs(obj.Reference, refs), checks,
+			replaceGroupExprs(obj.Default, refs)}
+	}
+	return expr
+}
+
 type rowValue struct {
 	Relation string
 	Column   string
@@ -998,6 +1082,20 @@ func (rv rowValue) Repr() string {
 	return fmt.Sprintf("%s:%s", rv.Relation, rv.Column)
 }
 
+// path returns the JSON Path that is used to access this column
+// in an input row.
+func (rv rowValue) path() string {
+	path := rv.Column
+	if rv.Relation != "" {
+		if strings.HasPrefix(path, "[") {
+			path = rv.Relation + path
+		} else {
+			path = rv.Relation + "." + path
+		}
+	}
+	return path
+}
+
 func (rv rowValue) Columns() []rowValue {
 	return []rowValue{rv}
 }
@@ -1051,6 +1149,27 @@ func (rm rowMeta) ContainsWildcard() bool {
 	return false
 }
 
+type rowMetadata struct {
+	Relation string
+	Key      string
+}
+
+func (rm rowMetadata) Repr() string {
+	return fmt.Sprintf("%#v", rm)
+}
+
+func (rm rowMetadata) Columns() []rowValue {
+	return nil
+}
+
+func (rm rowMetadata) Volatility() VolatilityType {
+	return Immutable
+}
+
+func (rm rowMetadata) ContainsWildcard() bool {
+	return false
+}
+
 type numericLiteral struct {
 	Value int64
 }
@@ -18,16 +18,17 @@ func TestFlatExpressionConverter(t *testing.T) {
 		r []rowValue
 	}{
 		// Base Expressions
-		"true":  {boolLiteral{true}, Immutable, false, nil},
-		"NULL":  {nullLiteral{}, Immutable, false, nil},
-		"a":     {rowValue{"", "a"}, Immutable, false, []rowValue{{"", "a"}}},
-		"ts()":  {rowMeta{"", parser.TimestampMeta}, Immutable, false, nil},
-		"now()": {stmtMeta{parser.NowMeta}, Stable, false, nil},
-		"2":     {numericLiteral{2}, Immutable, false, nil},
-		"1.2":   {floatLiteral{1.2}, Immutable, false, nil},
-		`"bql"`: {stringLiteral{"bql"}, Immutable, false, nil},
-		"*":     {wildcardAST{}, Stable, true, nil},
-		"x:*":   {wildcardAST{"x"}, Stable, true, nil},
+		"true":      {boolLiteral{true}, Immutable, false, nil},
+		"NULL":      {nullLiteral{}, Immutable, false, nil},
+		"a":         {rowValue{"", "a"}, Immutable, false, []rowValue{{"", "a"}}},
+		"ts()":      {rowMeta{"", parser.TimestampMeta}, Immutable, false, nil},
+		`meta("k")`: {rowMetadata{"", "k"}, Immutable, false, nil},
+		"now()":     {stmtMeta{parser.NowMeta}, Stable, false, nil},
+		"2":         {numericLiteral{2}, Immutable, false, nil},
+		"1.2":       {floatLiteral{1.2}, Immutable, false, nil},
+		`"bql"`:     {stringLiteral{"bql"}, Immutable, false, nil},
+		"*":         {wildcardAST{}, Stable, true, nil},
+		"x:*":       {wildcardAST{"x"}, Stable, true, nil},
 		// Type Cast
 		"CAST(2 AS FLOAT)": {typeCastAST{numericLiteral{2}, parser.Float}, Immutable, false, nil},
 		// Function Application
@@ -16,7 +16,7 @@ type filterPlan struct {
 // CanBuildFilterPlan checks whether the given statement
 // allows to use a filterPlan.
 func CanBuildFilterPlan(lp *LogicalPlan, reg udf.FunctionRegistry) bool {
-	if len(lp.Relations) != 1 {
+	if len(lp.Relations) != 1 || len(lp.StateJoins) > 0 {
 		return false
 	}
 	return !lp.GroupingStmt &&
@@ -43,6 +43,7 @@ func NewFilterPlan(lp *LogicalPlan, reg udf.FunctionRegistry) (PhysicalPlan, err
 	return &filterPlan{commonExecutionPlan{
 		projections: projs,
 		filter:      filter,
+		clock:       clockOf(reg),
 	}, lp.Relations[0].Alias}, nil
 }
 
@@ -55,7 +56,7 @@ func (ep *filterPlan) Process(input *core.Tuple) ([]data.Map, error) {
 
 	// add the information accessed by the now() function
 	// to each item
-	d[":meta:NOW"] = data.Timestamp(time.Now().In(time.UTC))
+	d[":meta:NOW"] = data.Timestamp(ep.clock().In(time.UTC))
 
 	// evaluate filter condition and convert to bool
 	if ep.filter != nil {
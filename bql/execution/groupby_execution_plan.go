@@ -9,6 +9,26 @@ import (
 
 type groupbyExecutionPlan struct {
 	streamRelationStreamExecutionPlan
+	// triggeredGroups holds the groups for which the EMIT WHEN
+	// condition was true in the previous run. A group is only
+	// emitted when the condition changes from false to true.
+	triggeredGroups groupSet
+}
+
+// groupSet is a set of group values (e.g. [1, "toy"]).
+type groupSet map[data.HashValue][]data.Array
+
+func (gs groupSet) contains(group data.Array, hash data.HashValue) bool {
+	for _, g := range gs[hash] {
+		if data.Equal(g, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func (gs groupSet) add(group data.Array, hash data.HashValue) {
+	gs[hash] = append(gs[hash], group)
 }
 
 // tmpGroupData is an intermediate data structure to represent
@@ -48,6 +68,7 @@ func NewGroupbyExecutionPlan(lp *LogicalPlan, reg udf.FunctionRegistry) (Physica
 	}
 	return &groupbyExecutionPlan{
 		*underlying,
+		groupSet{},
 	}, nil
 }
 
@@ -59,6 +80,11 @@ func (ep *groupbyExecutionPlan) Process(input *core.Tuple) ([]data.Map, error) {
 	return ep.process(input, ep.performQueryOnBuffer)
 }
 
+// Tick returns the results of TUMBLING windows closed by processing time.
+func (ep *groupbyExecutionPlan) Tick() ([]data.Map, error) {
+	return ep.tick(ep.performQueryOnBuffer)
+}
+
 // performQueryOnBuffer computes the projections of a SELECT query on the data
 // stored in `ep.filteredInputRows`. The query results (which is a set of
 // data.Value, not core.Tuple) is stored in ep.curResults. The data
@@ -100,6 +126,41 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 		}
 	}
 
+	// triggeredGroups collects the groups for which the EMIT WHEN
+	// condition is true in this run. it replaces ep.triggeredGroups
+	// only when the whole computation succeeds.
+	triggeredGroups := groupSet{}
+
+	// checkEmitWhen evaluates the EMIT WHEN condition, if there is
+	// one, on the given input and returns whether the group should
+	// be emitted, i.e., whether the condition became true.
+	checkEmitWhen := func(group data.Array, input data.Map) (bool, error) {
+		for _, proj := range ep.projections {
+			if proj.alias != ":emit_when:" {
+				continue
+			}
+			v, err := proj.evaluator.Eval(input)
+			if err != nil {
+				return false, err
+			}
+			// like in HAVING, NULL is treated as false
+			if v.Type() == data.TypeNull {
+				return false, nil
+			}
+			b, err := data.AsBool(v)
+			if err != nil {
+				return false, err
+			}
+			if !b {
+				return false, nil
+			}
+			h := data.Hash(group)
+			triggeredGroups.add(group, h)
+			return !ep.triggeredGroups.contains(group, h), nil
+		}
+		return true, nil
+	}
+
 	// groups holds one item for every combination of values that
 	// appear in the GROUP BY clause
 	groups := map[data.HashValue][]*tmpGroupData{}
@@ -187,20 +248,48 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 			io.hash = data.Hash(io.cache)
 		}
 
-		itemGroup, err := findOrCreateGroup(itemGroupValues, io.hash, *io.input)
-		if err != nil {
-			return err
-		}
-
 		// now compute all the input data for the aggregate functions,
 		// e.g. for `SELECT count(a) + max(b/2)`, compute `a` and `b/2`
+		aggValues := make(map[string]data.Value, len(allAggEvaluators))
 		for key, agg := range allAggEvaluators {
 			value, err := agg.Eval(*io.input)
 			if err != nil {
 				return err
 			}
-			// store this value in the output map
-			itemGroup.aggData[key] = append(itemGroup.aggData[key], value)
+			aggValues[key] = value
+		}
+
+		// addToGroup stores the aggregate input values in the given group
+		addToGroup := func(groupValues []data.Value, groupHash data.HashValue) error {
+			itemGroup, err := findOrCreateGroup(groupValues, groupHash, *io.input)
+			if err != nil {
+				return err
+			}
+			for key, value := range aggValues {
+				itemGroup.aggData[key] = append(itemGroup.aggData[key], value)
+			}
+			return nil
+		}
+
+		if ep.groupingSets == nil {
+			return addToGroup(itemGroupValues, io.hash)
+		}
+
+		// with grouping sets, every row belongs to one group per set.
+		// the group values are prefixed with the index of the set and
+		// values of expressions not contained in the set are NULL.
+		for setIdx, set := range ep.groupingSets {
+			setValues := make(data.Array, len(itemGroupValues)+1)
+			setValues[0] = data.Int(setIdx)
+			for i := range itemGroupValues {
+				setValues[i+1] = data.Null{}
+			}
+			for _, i := range set {
+				setValues[i+1] = itemGroupValues[i]
+			}
+			if err := addToGroup(setValues, data.Hash(setValues)); err != nil {
+				return err
+			}
 		}
 		return nil
 	}
@@ -213,6 +302,20 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 			group.nonAggData[key] = data.Array(group.aggData[key])
 			delete(group.aggData, key)
 		}
+		// store the values of the GROUP BY expressions where the
+		// projections will look for them
+		groupValues := group.group
+		if ep.groupingSets != nil {
+			groupValues = groupValues[1:]
+		}
+		for i, path := range ep.groupPaths {
+			if path == nil {
+				continue
+			}
+			if err := group.nonAggData.Set(path, groupValues[i]); err != nil {
+				return err
+			}
+		}
 		// evaluate HAVING condition, if there is one
 		for _, proj := range ep.projections {
 			if proj.alias == ":having:" {
@@ -237,9 +340,15 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 				break
 			}
 		}
+		// evaluate EMIT WHEN condition, if there is one
+		if emit, err := checkEmitWhen(group.group, group.nonAggData); err != nil {
+			return err
+		} else if !emit {
+			return nil
+		}
 		// now evaluate all other projections
 		for _, proj := range ep.projections {
-			if proj.alias == ":having:" {
+			if proj.alias == ":having:" || proj.alias == ":emit_when:" {
 				continue
 			}
 			// now evaluate this projection on the flattened data
@@ -265,7 +374,6 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 			return nil
 		}
 		input := data.Map{}
-		result := data.Map(make(map[string]data.Value, len(ep.projections)))
 		for _, proj := range ep.projections {
 			// collect input for aggregate functions
 			if proj.hasAggregate {
@@ -273,6 +381,17 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 					input[key] = data.Array{}
 				}
 			}
+		}
+		if emit, err := checkEmitWhen(data.Array{}, input); err != nil {
+			return err
+		} else if !emit {
+			return nil
+		}
+		result := data.Map(make(map[string]data.Value, len(ep.projections)))
+		for _, proj := range ep.projections {
+			if proj.alias == ":emit_when:" {
+				continue
+			}
 			// now evaluate this projection on the flattened data.
 			// note that input has *only* the keys of the empty
 			// arrays, no other columns, but we cannot have other
@@ -318,6 +437,7 @@ func (ep *groupbyExecutionPlan) performQueryOnBuffer() error {
 		}
 	}
 
+	ep.triggeredGroups = triggeredGroups
 	ep.curResults = output
 	return nil
 }
@@ -418,6 +418,60 @@ func TestGroupbyExecutionPlan(t *testing.T) {
 		})
 	})
 
+	Convey("Given a SELECT clause with GROUP BY and EMIT WHEN", t, func() {
+		tuples := getOtherTuples()
+		s := `CREATE STREAM box AS SELECT RSTREAM foo, count(*) AS c FROM src [RANGE 3 TUPLES] GROUP BY foo EMIT WHEN count(*) >= 2`
+		plan, err := createGroupbyPlan(s, t)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			for idx, inTup := range tuples {
+				out, err := plan.Process(inTup)
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("Then groups should only be emitted when the condition becomes true in %v", idx), func() {
+					if idx == 0 {
+						So(len(out), ShouldEqual, 0)
+					} else if idx == 1 {
+						So(len(out), ShouldEqual, 1)
+						So(out[0], ShouldResemble,
+							data.Map{"foo": data.Int(1), "c": data.Int(2)})
+					} else if idx == 2 {
+						// the condition is still true for foo=1
+						So(len(out), ShouldEqual, 0)
+					} else {
+						So(len(out), ShouldEqual, 1)
+						So(out[0], ShouldResemble,
+							data.Map{"foo": data.Int(2), "c": data.Int(2)})
+					}
+				})
+			}
+		})
+	})
+
+	Convey("Given a SELECT clause with aggregation and EMIT WHEN but no GROUP BY", t, func() {
+		tuples := getOtherTuples()
+		s := `CREATE STREAM box AS SELECT RSTREAM count(*) AS c FROM src [RANGE 2 TUPLES] EMIT WHEN count(*) >= 2 OR max(int) > 10`
+		plan, err := createGroupbyPlan(s, t)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			for idx, inTup := range tuples {
+				out, err := plan.Process(inTup)
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("Then the result should only be emitted once in %v", idx), func() {
+					if idx == 1 {
+						So(len(out), ShouldEqual, 1)
+						So(out[0], ShouldResemble, data.Map{"c": data.Int(2)})
+					} else {
+						So(len(out), ShouldEqual, 0)
+					}
+				})
+			}
+		})
+	})
+
 	Convey("Given a SELECT clause with two identical aggregations and GROUP BY", t, func() {
 		tuples := getOtherTuples()
 		tuples[3].Data["int"] = data.Null{} // NULL should not be counted
@@ -755,6 +809,101 @@ func TestGroupbyExecutionPlan(t *testing.T) {
 		})
 	})
 
+	Convey("Given a SELECT clause with an expression in the GROUP BY clause", t, func() {
+		tuples := getOtherTuples()
+		s := `CREATE STREAM box AS SELECT RSTREAM int % 2 AS parity, count(int) AS c FROM src [RANGE 3 TUPLES] GROUP BY int % 2`
+		plan, err := createGroupbyPlan(s, t)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			for idx, inTup := range tuples {
+				out, err := plan.Process(inTup)
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("Then those values should appear in %v", idx), func() {
+					if idx == 0 {
+						So(len(out), ShouldEqual, 1)
+						So(out[0], ShouldResemble,
+							data.Map{"parity": data.Int(1), "c": data.Int(1)})
+					} else if idx == 1 {
+						So(len(out), ShouldEqual, 2)
+						So(out[0], ShouldResemble,
+							data.Map{"parity": data.Int(1), "c": data.Int(1)})
+						So(out[1], ShouldResemble,
+							data.Map{"parity": data.Int(0), "c": data.Int(1)})
+					} else if idx == 2 {
+						So(len(out), ShouldEqual, 2)
+						So(out[0], ShouldResemble,
+							data.Map{"parity": data.Int(1), "c": data.Int(2)})
+						So(out[1], ShouldResemble,
+							data.Map{"parity": data.Int(0), "c": data.Int(1)})
+					} else {
+						So(len(out), ShouldEqual, 2)
+						So(out[0], ShouldResemble,
+							data.Map{"parity": data.Int(0), "c": data.Int(2)})
+						So(out[1], ShouldResemble,
+							data.Map{"parity": data.Int(1), "c": data.Int(1)})
+					}
+				})
+			}
+		})
+	})
+
+	Convey("Given a SELECT clause with GROUP BY ROLLUP", t, func() {
+		tuples := getOtherTuples()
+		tuples[0].Data["bar"] = data.Int(1)
+		tuples[1].Data["bar"] = data.Int(1)
+		tuples[2].Data["bar"] = data.Int(1)
+		tuples[3].Data["bar"] = data.Int(2)
+		s := `CREATE STREAM box AS SELECT RSTREAM foo, bar, count(int) AS c FROM src [RANGE 4 TUPLES] GROUP BY ROLLUP(foo, bar)`
+		plan, err := createGroupbyPlan(s, t)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			var out []data.Map
+			for _, inTup := range tuples {
+				out, err = plan.Process(inTup)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then there should be one row per group of each grouping set", func() {
+				So(out, ShouldResemble, []data.Map{
+					{"foo": data.Int(1), "bar": data.Int(1), "c": data.Int(2)},
+					{"foo": data.Int(1), "bar": data.Null{}, "c": data.Int(2)},
+					{"foo": data.Null{}, "bar": data.Null{}, "c": data.Int(4)},
+					{"foo": data.Int(2), "bar": data.Int(1), "c": data.Int(1)},
+					{"foo": data.Int(2), "bar": data.Null{}, "c": data.Int(2)},
+					{"foo": data.Int(2), "bar": data.Int(2), "c": data.Int(1)},
+				})
+			})
+		})
+	})
+
+	Convey("Given a SELECT clause with GROUP BY GROUPING SETS on expressions", t, func() {
+		tuples := getOtherTuples()
+		s := `CREATE STREAM box AS SELECT RSTREAM foo, int % 2 AS parity, count(int) AS c FROM src [RANGE 4 TUPLES]
+			GROUP BY GROUPING SETS ((foo), (int % 2))`
+		plan, err := createGroupbyPlan(s, t)
+		So(err, ShouldBeNil)
+
+		Convey("When feeding it with tuples", func() {
+			var out []data.Map
+			for _, inTup := range tuples {
+				out, err = plan.Process(inTup)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then there should be one row per group of each grouping set", func() {
+				So(out, ShouldResemble, []data.Map{
+					{"foo": data.Int(1), "parity": data.Null{}, "c": data.Int(2)},
+					{"foo": data.Null{}, "parity": data.Int(1), "c": data.Int(2)},
+					{"foo": data.Null{}, "parity": data.Int(0), "c": data.Int(2)},
+					{"foo": data.Int(2), "parity": data.Null{}, "c": data.Int(2)},
+				})
+			})
+		})
+	})
+
 	/// things that do not work
 
 	// limitations of this plan (must use default plan)
@@ -801,22 +950,27 @@ func TestGroupbyExecutionPlan(t *testing.T) {
 		So(err.Error(), ShouldEqual, `column "src:int" must appear in the GROUP BY clause or be used in an aggregate function`)
 	})
 
-	// BQL limitations (less features than SQL)
+	Convey("Given an SELECT statement with an invalid GROUP BY (6)", t, func() {
+		// not referencing the group-by column
+		s := `CREATE STREAM box AS SELECT RSTREAM foo, count(int) FROM src [RANGE 3 TUPLES] GROUP BY foo EMIT WHEN int=2`
+		_, err := createGroupbyPlan(s, t)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, `column "src:int" must appear in the GROUP BY clause or be used in an aggregate function`)
+	})
 
-	Convey("Given an SELECT statement with an invalid GROUP BY (3)", t, func() {
-		// using an expression in the GROUP BY clause
-		s := `CREATE STREAM box AS SELECT RSTREAM foo+2 FROM src [RANGE 3 TUPLES] GROUP BY foo+2`
+	Convey("Given an SELECT statement with EMIT WHEN but no aggregation", t, func() {
+		s := `CREATE STREAM box AS SELECT RSTREAM foo FROM src [RANGE 3 TUPLES] EMIT WHEN foo=2`
 		_, err := createGroupbyPlan(s, t)
 		So(err, ShouldNotBeNil)
-		So(err.Error(), ShouldEqual, `grouping by expressions is not supported yet`)
+		So(err.Error(), ShouldEqual, "EMIT WHEN clause can only be used with aggregate functions or GROUP BY")
 	})
 
-	Convey("Given an SELECT statement with an invalid GROUP BY (4)", t, func() {
-		// using an expression in the GROUP BY clause
+	Convey("Given an SELECT statement with an invalid GROUP BY (5)", t, func() {
+		// not referencing the group-by expression
 		s := `CREATE STREAM box AS SELECT RSTREAM foo, count(int) FROM src [RANGE 3 TUPLES] GROUP BY ts()`
 		_, err := createGroupbyPlan(s, t)
 		So(err, ShouldNotBeNil)
-		So(err.Error(), ShouldEqual, `grouping by expressions is not supported yet`)
+		So(err.Error(), ShouldEqual, `column "src:foo" must appear in the GROUP BY clause or be used in an aggregate function`)
 	})
 
 	Convey("Given an SELECT statement with an unknown UDAF", t, func() {
@@ -0,0 +1,262 @@
+package execution
+
+import (
+	"container/list"
+	"sort"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// EquiJoin is an equality condition `Left = Right` in the WHERE clause of
+// a statement reading from multiple relations, where Left only refers to
+// the relation LeftRelation and Right only refers to RightRelation. Tuples
+// of both relations can be matched by hash values of both sides instead of
+// evaluating the condition on the whole cartesian product of the windows.
+type EquiJoin struct {
+	LeftRelation  string
+	Left          FlatExpression
+	RightRelation string
+	Right         FlatExpression
+}
+
+// detectEquiJoins returns the equi-join conditions which are conjuncts of
+// the filter. Because the filter is still evaluated on the joined rows, an
+// equi-join only has to be a necessary condition of the filter.
+func detectEquiJoins(filter FlatExpression, relations []parser.AliasedStreamWindowAST) []EquiJoin {
+	if filter == nil || len(relations) < 2 {
+		return nil
+	}
+	aliases := make(map[string]bool, len(relations))
+	for _, rel := range relations {
+		aliases[rel.Alias] = true
+	}
+	// returns the only relation referred to by the expression
+	relationOf := func(e FlatExpression) (string, bool) {
+		// a key is computed once when the tuple arrives, so it must not
+		// change later
+		if e.Volatility() != Immutable {
+			return "", false
+		}
+		rels := map[string]bool{}
+		if !collectRelations(e, rels) || len(rels) != 1 {
+			return "", false
+		}
+		for rel := range rels {
+			return rel, aliases[rel]
+		}
+		return "", false
+	}
+
+	var joins []EquiJoin
+	for _, c := range filterConjuncts(filter) {
+		eq, ok := c.(binaryOpAST)
+		if !ok || eq.Op != parser.Equal {
+			continue
+		}
+		l, ok := relationOf(eq.Left)
+		if !ok {
+			continue
+		}
+		r, ok := relationOf(eq.Right)
+		if !ok || l == r {
+			continue
+		}
+		joins = append(joins, EquiJoin{l, eq.Left, r, eq.Right})
+	}
+	return joins
+}
+
+// filterConjuncts splits the expression at top-level AND operators.
+func filterConjuncts(e FlatExpression) []FlatExpression {
+	if b, ok := e.(binaryOpAST); ok && b.Op == parser.And {
+		return append(filterConjuncts(b.Left), filterConjuncts(b.Right)...)
+	}
+	return []FlatExpression{e}
+}
+
+// collectRelations adds aliases of relations referred to by the expression
+// to rels. It returns false when the expression has a sub-expression which
+// cannot be evaluated on a single input row, such as an aggregate.
+func collectRelations(e FlatExpression, rels map[string]bool) bool {
+	all := func(es ...FlatExpression) bool {
+		for _, e := range es {
+			if e != nil && !collectRelations(e, rels) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch obj := e.(type) {
+	case rowValue:
+		rels[obj.Relation] = true
+	case rowMeta:
+		rels[obj.Relation] = true
+	case rowMetadata:
+		rels[obj.Relation] = true
+	case missing:
+		rels[obj.Expr.Relation] = true
+	case nullLiteral, numericLiteral, floatLiteral, boolLiteral, stringLiteral:
+	case binaryOpAST:
+		return all(obj.Left, obj.Right)
+	case unaryOpAST:
+		return all(obj.Expr)
+	case typeCastAST:
+		return all(obj.Expr)
+	case funcAppSelectorAST:
+		return all(obj.Expr)
+	case funcAppAST:
+		return all(obj.Expressions...)
+	case arrayAST:
+		return all(obj.Expressions...)
+	case mapAST:
+		for _, p := range obj.Entries {
+			if !all(p.Value) {
+				return false
+			}
+		}
+	case caseAST:
+		if !all(obj.Reference, obj.Default) {
+			return false
+		}
+		for _, c := range obj.Checks {
+			if !all(c.When, c.Then) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// hashJoin is an EquiJoin prepared for execution. relations and keys have
+// the left and the right side of the condition.
+type hashJoin struct {
+	relations [2]string
+	keys      [2]Evaluator
+}
+
+// joinKey is the value of one side of a hashJoin computed from a tuple.
+type joinKey struct {
+	value data.Value
+	hash  data.HashValue
+
+	// ok is false when the key couldn't be computed, e.g. when the tuple
+	// doesn't have the column. Such a tuple is joined with all tuples of
+	// the other relation so that the filter reports the error as it does
+	// without the hash join.
+	ok bool
+}
+
+// joinIndex indexes tuples in an inputBuffer by their keys of a hashJoin.
+type joinIndex struct {
+	key     Evaluator
+	buckets map[data.HashValue][]*list.Element
+	unkeyed []*list.Element
+}
+
+func newJoinIndex(key Evaluator) *joinIndex {
+	return &joinIndex{
+		key:     key,
+		buckets: map[data.HashValue][]*list.Element{},
+	}
+}
+
+// computeKey evaluates the key of the tuple nested under the alias.
+func (idx *joinIndex) computeKey(alias string, t *core.Tuple) joinKey {
+	row := data.Map{alias: t.Data[alias]}
+	setMetadata(row, alias, t)
+	v, err := idx.key.Eval(row)
+	if err != nil {
+		return joinKey{}
+	}
+	return joinKey{
+		value: v,
+		hash:  data.Hash(v),
+		ok:    true,
+	}
+}
+
+func (idx *joinIndex) add(e *list.Element, k joinKey) {
+	switch {
+	case !k.ok:
+		idx.unkeyed = append(idx.unkeyed, e)
+	case k.value.Type() == data.TypeNull:
+		// NULL never equals to anything
+	default:
+		idx.buckets[k.hash] = append(idx.buckets[k.hash], e)
+	}
+}
+
+func (idx *joinIndex) remove(e *list.Element, k joinKey) {
+	switch {
+	case !k.ok:
+		idx.unkeyed = removeElement(idx.unkeyed, e)
+	case k.value.Type() == data.TypeNull:
+	default:
+		b := removeElement(idx.buckets[k.hash], e)
+		if len(b) == 0 {
+			delete(idx.buckets, k.hash)
+		} else {
+			idx.buckets[k.hash] = b
+		}
+	}
+}
+
+// removeElement removes e from es. Since tuples expire in the order they
+// arrived, e is usually the first element.
+func removeElement(es []*list.Element, e *list.Element) []*list.Element {
+	for i, x := range es {
+		if x == e {
+			return append(es[:i], es[i+1:]...)
+		}
+	}
+	return es
+}
+
+// candidates returns the tuples which may have the same key as k, in the
+// order they arrived. It returns false when all tuples are candidates.
+func (idx *joinIndex) candidates(k joinKey) ([]*list.Element, bool) {
+	if !k.ok {
+		return nil, false
+	}
+	if k.value.Type() == data.TypeNull {
+		return nil, true
+	}
+	b := idx.buckets[k.hash]
+	if len(idx.unkeyed) == 0 {
+		return b, true
+	}
+	es := make([]*list.Element, 0, len(b)+len(idx.unkeyed))
+	es = append(es, b...)
+	es = append(es, idx.unkeyed...)
+	sort.Sort(elementsBySeq(es))
+	return es, true
+}
+
+type elementsBySeq []*list.Element
+
+func (s elementsBySeq) Len() int           { return len(s) }
+func (s elementsBySeq) Less(i, j int) bool { return seqOf(s[i]) < seqOf(s[j]) }
+func (s elementsBySeq) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func seqOf(e *list.Element) int64 {
+	return e.Value.(*tupleWithDerivedInputRows).seq
+}
+
+// contains returns true when the element is in the sublist. Because tuples
+// in a buffer are sorted by their seq, it's checked without iterating the
+// sublist.
+func (p partialList) contains(e *list.Element) bool {
+	if p.start == nil {
+		return false
+	}
+	seq := seqOf(e)
+	if seq < seqOf(p.start) {
+		return false
+	}
+	return p.end == nil || seq < seqOf(p.end)
+}
@@ -0,0 +1,219 @@
+package execution
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// analyzeSelect returns the logical plan of the statement. When optimize is
+// false, LogicalOptimize isn't applied and no hash join is used.
+func analyzeSelect(s string, optimize bool) (*LogicalPlan, udf.FunctionRegistry, error) {
+	reg := udf.CopyGlobalUDFRegistry(core.NewContext(nil))
+	stmt, _, err := parser.New().ParseStmt(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	lp, err := Analyze(stmt.(parser.CreateStreamAsSelectStmt).Select, reg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if optimize {
+		if lp, err = lp.LogicalOptimize(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return lp, reg, nil
+}
+
+func TestDetectEquiJoins(t *testing.T) {
+	Convey("Given SELECT statements joining streams", t, func() {
+		for _, c := range []struct {
+			where    string
+			expected []string
+		}{
+			{"a:x = b:y", []string{"a:x = b:y"}},
+			{"b:y = a:x", []string{"b:y = a:x"}},
+			{"a:x + 1 = b:y AND a:z > 3 AND (b:w = c:v AND c:ts() > now())",
+				[]string{"(a:x)+(1) = b:y", "b:w = c:v"}},
+			{"a:x = c:v::int", []string{"a:x = CAST(c:v AS INT)"}},
+			// UDFs can be volatile
+			{"abs(a:x) = c:v", nil},
+			{"a:x = b:y OR a:z = b:w", nil},
+			{"NOT (a:x = b:y)", nil},
+			{"a:x = a:y", nil},
+			{"a:x + b:y = c:z", nil},
+			{"a:x = 3", nil},
+			{"a:x = b:y + random()", nil},
+			{"a:ts() = now()", nil},
+		} {
+			c := c
+			Convey("When the condition is "+c.where, func() {
+				s := `CREATE STREAM box AS SELECT RSTREAM a:x FROM a [RANGE 1 TUPLES],
+					b [RANGE 2 TUPLES], c [RANGE 3 SECONDS] WHERE ` + c.where
+				lp, _, err := analyzeSelect(s, true)
+				So(err, ShouldBeNil)
+
+				Convey("Then equi-joins should be detected", func() {
+					var actual []string
+					for _, j := range lp.EquiJoins {
+						actual = append(actual, fmt.Sprintf("%s = %s", j.Left.Repr(), j.Right.Repr()))
+					}
+					So(actual, ShouldResemble, c.expected)
+				})
+			})
+		}
+
+		Convey("When the statement has a single relation", func() {
+			lp, _, err := analyzeSelect(`CREATE STREAM box AS SELECT RSTREAM x
+				FROM a [RANGE 1 TUPLES] WHERE x = y`, true)
+			So(err, ShouldBeNil)
+
+			Convey("Then no equi-join should be detected", func() {
+				So(lp.EquiJoins, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+// joinTestInputs returns tuples of streams a, b, and c having keys in a
+// small range so that each tuple matches some tuples in other streams. Some
+// keys are NULL or floats having integer values.
+func joinTestInputs(n int) []*core.Tuple {
+	r := rand.New(rand.NewSource(1))
+	streams := []string{"a", "b", "c"}
+	ts := make([]*core.Tuple, n)
+	for i := range ts {
+		var k data.Value = data.Int(r.Intn(5))
+		switch r.Intn(10) {
+		case 0:
+			k = data.Null{}
+		case 1:
+			k = data.Float(r.Intn(5))
+		}
+		ts[i] = &core.Tuple{
+			Data: data.Map{
+				"k": k,
+				"v": data.Int(i),
+			},
+			InputName:     streams[r.Intn(len(streams))],
+			Timestamp:     time.Date(2015, time.April, 10, 10, 23, 0, 0, time.UTC).Add(time.Duration(i) * 300 * time.Millisecond),
+			ProcTimestamp: time.Date(2015, time.April, 10, 10, 24, 0, 0, time.UTC),
+		}
+	}
+	return ts
+}
+
+func TestHashJoin(t *testing.T) {
+	stmts := map[string]string{
+		"two streams having different windows": `CREATE STREAM box AS SELECT ISTREAM a:v AS av, b:v AS bv
+			FROM a [RANGE 3 TUPLES], b [RANGE 2 SECONDS] WHERE a:k = b:k`,
+		"three streams": `CREATE STREAM box AS SELECT RSTREAM a:v AS av, b:v AS bv, c:v AS cv
+			FROM a [RANGE 4 TUPLES], b [RANGE 5 TUPLES], c [RANGE 1500 MILLISECONDS]
+			WHERE a:k = b:k AND c:k + 1 = b:k + 1 AND a:v < c:v`,
+		"streams joined by a part of conditions": `CREATE STREAM box AS SELECT DSTREAM a:v AS av, c:v AS cv
+			FROM a [RANGE 5 TUPLES], b [RANGE 2 TUPLES], c [RANGE 5 TUPLES]
+			WHERE a:k = c:k AND b:v > a:v`,
+		"a self-join": `CREATE STREAM box AS SELECT ISTREAM l:v AS lv, r:v AS rv
+			FROM a [RANGE 4 TUPLES] AS l, a [RANGE 2 TUPLES] AS r WHERE l:k = r:k`,
+	}
+
+	for name, s := range stmts {
+		s := s
+		Convey("Given "+name, t, func() {
+			lp, reg, err := analyzeSelect(s, true)
+			So(err, ShouldBeNil)
+			So(lp.EquiJoins, ShouldNotBeEmpty)
+			hashPlan, err := NewDefaultSelectExecutionPlan(lp, reg)
+			So(err, ShouldBeNil)
+			lp2, reg2, err := analyzeSelect(s, false)
+			So(err, ShouldBeNil)
+			crossPlan, err := NewDefaultSelectExecutionPlan(lp2, reg2)
+			So(err, ShouldBeNil)
+
+			inputs := map[string]bool{}
+			for _, rel := range lp.Relations {
+				inputs[rel.Name] = true
+			}
+
+			Convey("When feeding tuples", func() {
+				Convey("Then the hash join should have the same results as the cartesian product", func() {
+					for i, in := range joinTestInputs(200) {
+						if !inputs[in.InputName] {
+							continue
+						}
+						expected, err := crossPlan.Process(in.Copy())
+						So(err, ShouldBeNil)
+						actual, err := hashPlan.Process(in.Copy())
+						So(err, ShouldBeNil)
+						So(fmt.Sprint(i, sortedResults(actual)), ShouldResemble, fmt.Sprint(i, sortedResults(expected)))
+					}
+				})
+			})
+		})
+	}
+
+	Convey("Given a join of two streams", t, func() {
+		lp, reg, err := analyzeSelect(`CREATE STREAM box AS SELECT RSTREAM a:v AS av, b:v AS bv
+			FROM a [RANGE 2 TUPLES], b [RANGE 2 TUPLES] WHERE a:k = b:k`, true)
+		So(err, ShouldBeNil)
+		plan, err := NewDefaultSelectExecutionPlan(lp, reg)
+		So(err, ShouldBeNil)
+		buffers := plan.(*defaultSelectExecutionPlan).buffers
+		process := func(stream string, k data.Value) ([]data.Map, error) {
+			t := &core.Tuple{
+				Data:      data.Map{"k": k, "v": data.String(fmt.Sprint(stream, k))},
+				InputName: stream,
+			}
+			if k == nil {
+				delete(t.Data, "k")
+			}
+			return plan.Process(t)
+		}
+
+		Convey("When tuples expire", func() {
+			for i := 0; i < 10; i++ {
+				_, err := process("a", data.Int(i))
+				So(err, ShouldBeNil)
+				_, err = process("b", data.Int(i))
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then they should be removed from indexes", func() {
+				for _, b := range buffers {
+					for _, idx := range b.indexes {
+						So(len(idx.buckets), ShouldEqual, 2)
+					}
+				}
+			})
+		})
+
+		Convey("When a tuple doesn't have the key", func() {
+			_, err := process("a", data.Int(1))
+			So(err, ShouldBeNil)
+			_, err = process("b", nil)
+
+			Convey("Then the filter should fail as it does without the hash join", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When keys are NULL", func() {
+			_, err := process("a", data.Null{})
+			So(err, ShouldBeNil)
+			res, err := process("b", data.Null{})
+
+			Convey("Then they shouldn't match", func() {
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+			})
+		})
+	})
+}
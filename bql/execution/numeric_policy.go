@@ -0,0 +1,238 @@
+package execution
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// NumericErrorMode is how arithmetic operators handle a result which
+// cannot be represented as a usual number.
+type NumericErrorMode int
+
+const (
+	// NumericUnchecked keeps the behavior of Go's arithmetic: integers wrap
+	// around on overflow, integer division by zero fails, and float
+	// operations can result in NaN or infinity.
+	NumericUnchecked NumericErrorMode = iota
+
+	// NumericError makes the operator fail, so that the tuple is handled
+	// by the error policy of the statement.
+	NumericError
+
+	// NumericNull makes the result NULL, which is ignored by most aggregate
+	// functions.
+	NumericNull
+
+	// NumericSaturate makes the result the closest representable value,
+	// e.g. the maximum integer on positive overflow. Division by zero
+	// results in the maximum value having the sign of the dividend (or 0
+	// when the dividend is 0), and modulo by zero results in the dividend.
+	// NaN becomes NULL because it doesn't have the closest value.
+	NumericSaturate
+)
+
+func (m NumericErrorMode) String() string {
+	switch m {
+	case NumericUnchecked:
+		return "unchecked"
+	case NumericError:
+		return "error"
+	case NumericNull:
+		return "null"
+	case NumericSaturate:
+		return "saturate"
+	}
+	return "unknown"
+}
+
+// ParseNumericErrorMode parses the name of a NumericErrorMode, which is
+// one of "unchecked", "error", "null", or "saturate".
+func ParseNumericErrorMode(s string) (NumericErrorMode, error) {
+	switch strings.ToLower(s) {
+	case "unchecked":
+		return NumericUnchecked, nil
+	case "error":
+		return NumericError, nil
+	case "null":
+		return NumericNull, nil
+	case "saturate":
+		return NumericSaturate, nil
+	}
+	return 0, fmt.Errorf(`numeric error mode must be "unchecked", "error", "null", or "saturate": %v`, s)
+}
+
+// NumericPolicy has NumericErrorModes of arithmetic operators (+, -, *, /,
+// %, and unary -) for each kind of results. Its zero value keeps the
+// behavior of Go's arithmetic. Results of functions aren't affected.
+type NumericPolicy struct {
+	// Overflow is applied when the result of an integer operation
+	// overflows.
+	Overflow NumericErrorMode
+
+	// DivisionByZero is applied when the divisor of / or % is zero. When
+	// it's NumericUnchecked, a float division by zero results in NaN or
+	// infinity, to which NonFinite is applied.
+	DivisionByZero NumericErrorMode
+
+	// NonFinite is applied when the result of a float operation is NaN or
+	// infinity.
+	NonFinite NumericErrorMode
+}
+
+// WithNumericPolicy returns a FunctionRegistry which behaves as reg and
+// makes evaluators created with it follow the policy.
+func WithNumericPolicy(reg udf.FunctionRegistry, p NumericPolicy) udf.FunctionRegistry {
+	if r, ok := reg.(*numericPolicyRegistry); ok {
+		reg = r.FunctionRegistry
+	}
+	return &numericPolicyRegistry{reg, p}
+}
+
+type numericPolicyRegistry struct {
+	udf.FunctionRegistry
+	policy NumericPolicy
+}
+
+func numericPolicyOf(reg udf.FunctionRegistry) NumericPolicy {
+	if r, ok := reg.(*numericPolicyRegistry); ok {
+		return r.policy
+	}
+	return NumericPolicy{}
+}
+
+// checkInt applies the policy to the result v of an integer operation.
+func (nbo *numBinOp) checkInt(l, r, v int64) (data.Value, error) {
+	if nbo.policy.Overflow == NumericUnchecked || nbo.intOverflow == nil {
+		return data.Int(v), nil
+	}
+	sat, overflow := nbo.intOverflow(l, r)
+	if !overflow {
+		return data.Int(v), nil
+	}
+	switch nbo.policy.Overflow {
+	case NumericError:
+		return nil, fmt.Errorf("cannot %s %v and %v: integer overflow", nbo.verb, l, r)
+	case NumericNull:
+		return data.Null{}, nil
+	}
+	return data.Int(sat), nil
+}
+
+// checkFloat applies the policy to the result of a float operation.
+func (nbo *numBinOp) checkFloat(l, r, v float64) (data.Value, error) {
+	if nbo.policy.NonFinite == NumericUnchecked || !(math.IsNaN(v) || math.IsInf(v, 0)) {
+		return data.Float(v), nil
+	}
+	switch nbo.policy.NonFinite {
+	case NumericError:
+		return nil, fmt.Errorf("cannot %s %v and %v: the result is %v", nbo.verb, l, r, v)
+	case NumericNull:
+		return data.Null{}, nil
+	}
+	switch {
+	case math.IsInf(v, 1):
+		return data.Float(math.MaxFloat64), nil
+	case math.IsInf(v, -1):
+		return data.Float(-math.MaxFloat64), nil
+	}
+	return data.Null{}, nil
+}
+
+// divisionByZero returns the result of dividing l by zero. It returns nil
+// when the result should be computed as usual.
+func (nbo *numBinOp) divisionByZero(l data.Value) (data.Value, error) {
+	switch nbo.policy.DivisionByZero {
+	case NumericUnchecked:
+		return nil, nil
+	case NumericError:
+		return nil, fmt.Errorf("cannot %s %v and 0: division by zero", nbo.verb, l)
+	case NumericNull:
+		return data.Null{}, nil
+	}
+	if nbo.modulo {
+		return l, nil
+	}
+	switch l := l.(type) {
+	case data.Int:
+		switch {
+		case l > 0:
+			return data.Int(math.MaxInt64), nil
+		case l < 0:
+			return data.Int(math.MinInt64), nil
+		}
+		return data.Int(0), nil
+	case data.Float:
+		switch {
+		case l > 0:
+			return data.Float(math.MaxFloat64), nil
+		case l < 0:
+			return data.Float(-math.MaxFloat64), nil
+		case l == 0:
+			return data.Float(0), nil
+		}
+	}
+	// NaN is computed as usual
+	return nil, nil
+}
+
+func isNumber(v data.Value) bool {
+	t := v.Type()
+	return t == data.TypeInt || t == data.TypeFloat
+}
+
+func isZero(v data.Value) bool {
+	switch v := v.(type) {
+	case data.Int:
+		return v == 0
+	case data.Float:
+		return v == 0
+	}
+	return false
+}
+
+func plusOverflow(a, b int64) (int64, bool) {
+	s := a + b
+	if a > 0 && b > 0 && s < 0 {
+		return math.MaxInt64, true
+	}
+	if a < 0 && b < 0 && s >= 0 {
+		return math.MinInt64, true
+	}
+	return 0, false
+}
+
+func minusOverflow(a, b int64) (int64, bool) {
+	s := a - b
+	if a >= 0 && b < 0 && s < 0 {
+		return math.MaxInt64, true
+	}
+	if a < 0 && b > 0 && s >= 0 {
+		return math.MinInt64, true
+	}
+	return 0, false
+}
+
+func multiplyOverflow(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, false
+	}
+	p := a * b
+	if p/b == a && !(a == -1 && b == math.MinInt64) && !(b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	if (a < 0) != (b < 0) {
+		return math.MinInt64, true
+	}
+	return math.MaxInt64, true
+}
+
+func divideOverflow(a, b int64) (int64, bool) {
+	if a == math.MinInt64 && b == -1 {
+		return math.MaxInt64, true
+	}
+	return 0, false
+}
@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestNumericPolicy(t *testing.T) {
+	const (
+		maxInt = math.MaxInt64
+		minInt = math.MinInt64
+	)
+	binary := func(op parser.Operator) parser.Expression {
+		return parser.BinaryOpAST{op, parser.RowValue{"", "a"}, parser.RowValue{"", "b"}}
+	}
+	nan := data.Float(math.NaN())
+	inf := data.Float(math.Inf(1))
+
+	cases := []struct {
+		expr     parser.Expression
+		policy   NumericPolicy
+		a, b     data.Value
+		expected data.Value // nil means an error
+	}{
+		// integer overflow
+		{binary(parser.Plus), NumericPolicy{}, data.Int(maxInt), data.Int(1), data.Int(minInt)},
+		{binary(parser.Plus), NumericPolicy{Overflow: NumericError}, data.Int(maxInt), data.Int(1), nil},
+		{binary(parser.Plus), NumericPolicy{Overflow: NumericError}, data.Int(maxInt), data.Int(-1), data.Int(maxInt - 1)},
+		{binary(parser.Plus), NumericPolicy{Overflow: NumericNull}, data.Int(maxInt), data.Int(1), data.Null{}},
+		{binary(parser.Plus), NumericPolicy{Overflow: NumericSaturate}, data.Int(maxInt), data.Int(1), data.Int(maxInt)},
+		{binary(parser.Plus), NumericPolicy{Overflow: NumericSaturate}, data.Int(minInt), data.Int(-1), data.Int(minInt)},
+		{binary(parser.Minus), NumericPolicy{Overflow: NumericSaturate}, data.Int(minInt), data.Int(1), data.Int(minInt)},
+		{binary(parser.Minus), NumericPolicy{Overflow: NumericSaturate}, data.Int(0), data.Int(minInt), data.Int(maxInt)},
+		{binary(parser.Minus), NumericPolicy{Overflow: NumericError}, data.Int(-1), data.Int(minInt), data.Int(maxInt)},
+		{binary(parser.Multiply), NumericPolicy{Overflow: NumericSaturate}, data.Int(maxInt / 2), data.Int(3), data.Int(maxInt)},
+		{binary(parser.Multiply), NumericPolicy{Overflow: NumericSaturate}, data.Int(maxInt / 2), data.Int(-3), data.Int(minInt)},
+		{binary(parser.Multiply), NumericPolicy{Overflow: NumericSaturate}, data.Int(minInt), data.Int(-1), data.Int(maxInt)},
+		{binary(parser.Multiply), NumericPolicy{Overflow: NumericError}, data.Int(minInt), data.Int(1), data.Int(minInt)},
+		{binary(parser.Multiply), NumericPolicy{Overflow: NumericError}, data.Int(-3), data.Int(4), data.Int(-12)},
+		{binary(parser.Divide), NumericPolicy{Overflow: NumericSaturate}, data.Int(minInt), data.Int(-1), data.Int(maxInt)},
+		{binary(parser.Modulo), NumericPolicy{Overflow: NumericError}, data.Int(minInt), data.Int(-1), data.Int(0)},
+		{parser.UnaryOpAST{parser.UnaryMinus, parser.RowValue{"", "a"}},
+			NumericPolicy{Overflow: NumericError}, data.Int(minInt), data.Int(0), nil},
+		// overflow of floats is checked as a non-finite value
+		{binary(parser.Plus), NumericPolicy{Overflow: NumericError}, data.Float(math.MaxFloat64), data.Float(math.MaxFloat64), inf},
+
+		// division by zero
+		{binary(parser.Divide), NumericPolicy{}, data.Int(1), data.Int(0), nil},
+		{binary(parser.Divide), NumericPolicy{}, data.Int(1), data.Float(0), inf},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericError}, data.Float(1), data.Int(0), nil},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericNull}, data.Int(1), data.Int(0), data.Null{}},
+		{binary(parser.Modulo), NumericPolicy{DivisionByZero: NumericNull}, data.Float(1), data.Float(0), data.Null{}},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericSaturate}, data.Int(-5), data.Int(0), data.Int(minInt)},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericSaturate}, data.Int(0), data.Int(0), data.Int(0)},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericSaturate}, data.Float(2), data.Int(0), data.Float(math.MaxFloat64)},
+		{binary(parser.Modulo), NumericPolicy{DivisionByZero: NumericSaturate}, data.Int(7), data.Int(0), data.Int(7)},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericNull}, data.String("a"), data.Int(0), nil},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericNull}, data.Null{}, data.Int(0), data.Null{}},
+		{binary(parser.Multiply), NumericPolicy{DivisionByZero: NumericError}, data.Int(1), data.Int(0), data.Int(0)},
+		// an unchecked division by zero is checked as a non-finite value
+		{binary(parser.Divide), NumericPolicy{NonFinite: NumericNull}, data.Float(1), data.Float(0), data.Null{}},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericSaturate, NonFinite: NumericError},
+			data.Float(-1), data.Float(0), data.Float(-math.MaxFloat64)},
+
+		// non-finite values
+		{binary(parser.Multiply), NumericPolicy{NonFinite: NumericError}, data.Float(1e300), data.Float(1e300), nil},
+		{binary(parser.Multiply), NumericPolicy{NonFinite: NumericNull}, data.Float(1e300), data.Float(1e300), data.Null{}},
+		{binary(parser.Multiply), NumericPolicy{NonFinite: NumericSaturate}, data.Float(-1e300), data.Int(maxInt), data.Float(-math.MaxFloat64)},
+		{binary(parser.Minus), NumericPolicy{NonFinite: NumericSaturate}, inf, inf, data.Null{}},
+		{binary(parser.Plus), NumericPolicy{NonFinite: NumericError}, nan, data.Float(1), nil},
+		{binary(parser.Plus), NumericPolicy{NonFinite: NumericError}, data.Float(1.5), data.Int(1), data.Float(2.5)},
+		{binary(parser.Divide), NumericPolicy{DivisionByZero: NumericSaturate, NonFinite: NumericNull},
+			nan, data.Float(0), data.Null{}},
+	}
+
+	for _, c := range cases {
+		c := c
+		Convey(fmt.Sprintf("Given %v with %+v", c.expr, c.policy), t, func() {
+			reg := WithNumericPolicy(&testFuncRegistry{ctx: core.NewContext(nil)}, c.policy)
+			flatExpr, err := ParserExprToFlatExpr(c.expr, reg)
+			So(err, ShouldBeNil)
+			eval, err := ExpressionToEvaluator(flatExpr, reg)
+			So(err, ShouldBeNil)
+
+			Convey(fmt.Sprintf("When evaluating it on %v and %v", c.a, c.b), func() {
+				actual, err := eval.Eval(data.Map{"a": c.a, "b": c.b})
+
+				Convey("Then the policy should be applied", func() {
+					if c.expected == nil {
+						So(err, ShouldNotBeNil)
+					} else {
+						So(err, ShouldBeNil)
+						So(actual, ShouldResemble, c.expected)
+					}
+				})
+			})
+		})
+	}
+
+	Convey("Given a registry having a numeric policy", t, func() {
+		reg := WithNumericPolicy(&testFuncRegistry{ctx: core.NewContext(nil)}, NumericPolicy{Overflow: NumericNull})
+
+		Convey("When overriding the policy", func() {
+			reg2 := WithNumericPolicy(reg, NumericPolicy{NonFinite: NumericError})
+
+			Convey("Then only the new policy should be used", func() {
+				So(numericPolicyOf(reg2), ShouldResemble, NumericPolicy{NonFinite: NumericError})
+				So(reg2.Context(), ShouldEqual, reg.Context())
+			})
+		})
+	})
+
+	Convey("Given names of numeric error modes", t, func() {
+		Convey("Then they should be parsed", func() {
+			for _, m := range []NumericErrorMode{NumericUnchecked, NumericError, NumericNull, NumericSaturate} {
+				p, err := ParseNumericErrorMode(m.String())
+				So(err, ShouldBeNil)
+				So(p, ShouldEqual, m)
+			}
+			p, err := ParseNumericErrorMode("SATURATE")
+			So(err, ShouldBeNil)
+			So(p, ShouldEqual, NumericSaturate)
+			_, err = ParseNumericErrorMode("wrap")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// sessionWindow holds the open sessions of a SESSION window. A session
+// starts when a tuple having a key arrives while the key doesn't have a
+// session, and it's extended by each tuple having the key. It closes when
+// no tuple having the key arrives for the gap, which is the size of the
+// tumblingWindow.
+//
+// With event time, a session closes when a tuple (having any key) whose
+// timestamp is after the end of the session arrives. With processing time,
+// it closes when the clock reaches the end.
+type sessionWindow struct {
+	alias string
+	key   Evaluator
+
+	// sessions has the open sessions by the hash values of their keys.
+	sessions map[data.HashValue]*session
+}
+
+type session struct {
+	key data.HashValue
+	// start is the time of the earliest tuple in the session and end is
+	// the time of the latest tuple plus the gap.
+	start time.Time
+	end   time.Time
+}
+
+func newSessionWindow(rel *parser.AliasedStreamWindowAST, reg udf.FunctionRegistry) (*sessionWindow, error) {
+	key, err := ParserExprToFlatExpr(rel.SessionKey.RenameReferencedRelation("", rel.Alias), reg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key of the SESSION window: %v", err)
+	}
+	eval, err := ExpressionToEvaluator(key, reg)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionWindow{
+		alias:    rel.Alias,
+		key:      eval,
+		sessions: map[data.HashValue]*session{},
+	}, nil
+}
+
+// keyOf computes the hash value of the key of the tuple.
+func (w *sessionWindow) keyOf(t *core.Tuple) (data.HashValue, error) {
+	row := data.Map{w.alias: t.Data}
+	setMetadata(row, w.alias, t)
+	k, err := w.key.Eval(row)
+	if err != nil {
+		return 0, fmt.Errorf("cannot compute the key of the SESSION window: %v", err)
+	}
+	return data.Hash(k), nil
+}
+
+// remove removes the session from the open sessions.
+func (w *sessionWindow) remove(s *session) {
+	if w.sessions[s.key] == s {
+		delete(w.sessions, s.key)
+	}
+}
+
+// processSession adds the input tuple to the session of its key and returns
+// the results of the sessions closed by the tuple.
+func (ep *streamRelationStreamExecutionPlan) processSession(input *core.Tuple, performQueryOnBuffer func() error) ([]data.Map, error) {
+	w := ep.tumbling
+	k, err := w.session.keyOf(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := input.Timestamp
+	if w.processingTime {
+		ts = ep.now
+	}
+	// close sessions which ended before the tuple first so that the
+	// tuple starts a new session if the session of its key has ended
+	output, err := ep.closeTumblingWindows(ts, performQueryOnBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	end := ts.Add(w.size)
+	s := w.session.sessions[k]
+	if s == nil || !end.After(s.start) {
+		if !end.After(w.watermark) {
+			// an out-of-order tuple whose session would have been closed
+			return output, nil
+		}
+		s = &session{
+			key:   k,
+			start: ts,
+			end:   end,
+		}
+		w.session.sessions[k] = s
+	}
+	if ts.Before(s.start) {
+		s.start = ts
+	}
+	if end.After(s.end) {
+		s.end = end
+	}
+	w.pending = append(w.pending, pendingTuple{tuple: input, session: s})
+	return output, nil
+}
@@ -0,0 +1,185 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSessionWindow(t *testing.T) {
+	base := time.Date(2015, time.April, 10, 10, 23, 0, 0, time.UTC)
+	tuple := func(sec float64, device string, v int) *core.Tuple {
+		ts := base.Add(time.Duration(sec * float64(time.Second)))
+		return &core.Tuple{
+			Data:          data.Map{"device_id": data.String(device), "v": data.Int(v)},
+			InputName:     "src",
+			Timestamp:     ts,
+			ProcTimestamp: ts,
+		}
+	}
+	process := func(p PhysicalPlan, ts ...*core.Tuple) []data.Map {
+		var res []data.Map
+		for _, t := range ts {
+			r, err := p.Process(t)
+			So(err, ShouldBeNil)
+			res = append(res, r...)
+		}
+		return res
+	}
+
+	Convey("Given a statement having a SESSION window", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM
+			device_id, count(*) AS c, sum(v) AS s
+			FROM src [RANGE SESSION 10 SECONDS ON device_id] GROUP BY device_id`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When tuples of a key arrive within the gap", func() {
+			res := process(p, tuple(0, "a", 1), tuple(8, "a", 2), tuple(16, "a", 3))
+
+			Convey("Then the session shouldn't be closed", func() {
+				So(res, ShouldBeEmpty)
+			})
+
+			Convey("Then the session should be closed after the gap", func() {
+				res := process(p, tuple(26, "a", 4))
+				So(res, ShouldResemble, []data.Map{
+					{"device_id": data.String("a"), "c": data.Int(3), "s": data.Int(6)},
+				})
+
+				Convey("And a new session of the key should be started", func() {
+					res := process(p, tuple(30, "a", 5), tuple(40, "b", 1))
+					So(res, ShouldResemble, []data.Map{
+						{"device_id": data.String("a"), "c": data.Int(2), "s": data.Int(9)},
+					})
+				})
+
+				Convey("And buffered tuples should only have the open session", func() {
+					ts := p.(BufferedPlan).BufferedTuples()
+					So(len(ts), ShouldEqual, 1)
+					So(ts[0].Data["v"], ShouldEqual, data.Int(4))
+				})
+			})
+		})
+
+		Convey("When tuples of different keys are interleaved", func() {
+			res := process(p,
+				tuple(0, "a", 1), tuple(1, "b", 10), tuple(5, "a", 2),
+				tuple(12, "b", 20), tuple(14, "a", 3), tuple(16, "c", 100),
+				tuple(30, "b", 30))
+
+			Convey("Then each key should have its own sessions", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"device_id": data.String("b"), "c": data.Int(1), "s": data.Int(10)},
+					{"device_id": data.String("b"), "c": data.Int(1), "s": data.Int(20)},
+					{"device_id": data.String("a"), "c": data.Int(3), "s": data.Int(6)},
+					{"device_id": data.String("c"), "c": data.Int(1), "s": data.Int(100)},
+				})
+			})
+		})
+
+		Convey("When sessions of different keys end at the same time", func() {
+			res := process(p, tuple(0, "a", 1), tuple(0, "b", 2), tuple(10, "c", 3))
+
+			Convey("Then they should be emitted separately", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"device_id": data.String("a"), "c": data.Int(1), "s": data.Int(1)},
+					{"device_id": data.String("b"), "c": data.Int(1), "s": data.Int(2)},
+				})
+			})
+		})
+
+		Convey("When tuples arrive out of order", func() {
+			res := process(p, tuple(0, "a", 1), tuple(20, "b", 2),
+				// joins the open session of b
+				tuple(12, "b", 3),
+				// its session would have been closed
+				tuple(5, "a", 4),
+				// extends the session of b to the past
+				tuple(3, "b", 5),
+				tuple(40, "c", 6))
+
+			Convey("Then tuples within open sessions should be included", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"device_id": data.String("a"), "c": data.Int(1), "s": data.Int(1)},
+					{"device_id": data.String("b"), "c": data.Int(3), "s": data.Int(10)},
+				})
+			})
+		})
+
+		Convey("When a tuple doesn't have the key", func() {
+			_, err := p.Process(&core.Tuple{
+				Data:      data.Map{"v": data.Int(1)},
+				InputName: "src",
+				Timestamp: base,
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a statement having a SESSION window without GROUP BY", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM count(*) AS c
+			FROM src [RANGE SESSION 1 SECONDS ON src:device_id || "x"] AS src`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When sessions close", func() {
+			res := process(p, tuple(0, "a", 1), tuple(0.5, "a", 1),
+				tuple(0.7, "b", 1), tuple(10, "a", 1))
+
+			Convey("Then each session should have its own result", func() {
+				So(res, ShouldResemble, []data.Map{{"c": data.Int(2)}, {"c": data.Int(1)}})
+			})
+		})
+	})
+
+	Convey("Given a statement having a SESSION window by processing time", t, func() {
+		now := base
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM device_id, count(*) AS c
+			FROM src [RANGE SESSION 2 SECONDS ON device_id BY PROCESSING TIME]
+			GROUP BY device_id`, func() time.Time { return now })
+		So(err, ShouldBeNil)
+		tp := p.(TimedPlan)
+		So(tp.TickInterval(), ShouldEqual, 200*time.Millisecond)
+
+		Convey("When tuples arrive", func() {
+			for _, d := range []string{"a", "b", "a"} {
+				// timestamps of tuples are ignored
+				res := process(p, tuple(100, d, 1))
+				So(res, ShouldBeEmpty)
+				now = now.Add(500 * time.Millisecond)
+			}
+
+			Convey("Then sessions should be closed by the clock", func() {
+				now = now.Add(time.Second)
+				res, err := tp.Tick()
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, []data.Map{{"device_id": data.String("b"), "c": data.Int(1)}})
+
+				now = now.Add(500 * time.Millisecond)
+				res, err = tp.Tick()
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, []data.Map{{"device_id": data.String("a"), "c": data.Int(2)}})
+			})
+		})
+	})
+
+	Convey("Given invalid SESSION windows", t, func() {
+		for _, s := range []string{
+			"FROM a [RANGE SESSION 2 SECONDS ON x], b [RANGE SESSION 2 SECONDS ON x]",
+			"FROM a [RANGE SESSION 2 SECONDS ON x], b [RANGE 2 SECONDS]",
+			"FROM a [RANGE SESSION 2 SECONDS ON b:x]",
+			"FROM a [RANGE SESSION 2 SECONDS ON count(x)]",
+			"FROM a [RANGE SESSION 0 SECONDS ON x]",
+		} {
+			Convey("Then "+s+" should be rejected", func() {
+				_, err := createTumblingPlan("CREATE STREAM box AS SELECT RSTREAM count(*) "+s, nil)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"sort"
 	"time"
 )
 
@@ -14,11 +15,21 @@ type inputBuffer struct {
 	tuples     *list.List
 	windowSize float64
 	windowType parser.IntervalUnit
+	// indexes holds indexes of the tuples for hash joins in which the
+	// relation takes part, keyed by the position of the join in
+	// streamRelationStreamExecutionPlan.hashJoins.
+	indexes map[int]*joinIndex
 }
 
 type tupleWithDerivedInputRows struct {
 	tuple *core.Tuple
 	rows  []*inputRowWithCachedResult
+	// seq is the sequential number of the input tuple. Copies of the same
+	// input tuple appended to multiple buffers on self-join have the same
+	// seq.
+	seq int64
+	// joinKeys holds the keys of the tuple for the indexes of the buffer.
+	joinKeys map[int]joinKey
 }
 
 func (i *inputBuffer) isTimeBased() bool {
@@ -26,6 +37,28 @@ func (i *inputBuffer) isTimeBased() bool {
 		i.windowType == parser.Milliseconds
 }
 
+// index adds the tuple just appended to the buffer to its join indexes.
+func (i *inputBuffer) index(alias string, e *list.Element) {
+	if len(i.indexes) == 0 {
+		return
+	}
+	tupCont := e.Value.(*tupleWithDerivedInputRows)
+	tupCont.joinKeys = make(map[int]joinKey, len(i.indexes))
+	for j, idx := range i.indexes {
+		k := idx.computeKey(alias, tupCont.tuple)
+		tupCont.joinKeys[j] = k
+		idx.add(e, k)
+	}
+}
+
+// unindex removes the tuple from the join indexes of the buffer.
+func (i *inputBuffer) unindex(e *list.Element) {
+	tupCont := e.Value.(*tupleWithDerivedInputRows)
+	for j, idx := range i.indexes {
+		idx.remove(e, tupCont.joinKeys[j])
+	}
+}
+
 // inputRowWithCachedResult holds an input tuple plus space for
 // cached data and a hash value that every plan can use internally.
 type inputRowWithCachedResult struct {
@@ -34,6 +67,14 @@ type inputRowWithCachedResult struct {
 	hash  data.HashValue
 }
 
+// stateJoinEvaluator holds the information required to look up the
+// data of a shared state joined by a JOIN STATE clause.
+type stateJoinEvaluator struct {
+	state string
+	alias string
+	key   Evaluator
+}
+
 // resultRow holds data for a tuple to be emitted (sooner or later)
 // plus a hash of the data (so that it does not need to be computed
 // again every time). After `performQueryOnBuffer` is complete, it
@@ -74,6 +115,15 @@ type streamRelationStreamExecutionPlan struct {
 	commonExecutionPlan
 	// store name->alias mapping
 	relations []parser.AliasedStreamWindowAST
+	// stateJoins holds the shared states that are joined with
+	// every item of the cartesian product of the input buffers.
+	stateJoins []stateJoinEvaluator
+	// hashJoins holds the equi-join conditions used to find matching
+	// tuples in the input buffers without computing the whole cartesian
+	// product.
+	hashJoins []hashJoin
+	// ctx is used to access the shared states in stateJoins.
+	ctx *core.Context
 	// buffers holds data of a single stream window, keyed by the
 	// alias (!) of the respective input stream. It will be
 	// updated (appended and possibly truncated) whenever
@@ -104,6 +154,11 @@ type streamRelationStreamExecutionPlan struct {
 	// the last tuple was appended to. this is valid after
 	// `addTupleToBuffer` has returned.
 	lastTupleBuffers map[string]bool
+	// nextSeq is the sequential number assigned to the next input tuple.
+	nextSeq int64
+	// tumbling holds the state of the TUMBLING window of the relations.
+	// It's nil when the relations have RANGE windows.
+	tumbling *tumblingWindow
 }
 
 func newStreamRelationStreamExecutionPlan(lp *LogicalPlan, reg udf.FunctionRegistry) (*streamRelationStreamExecutionPlan, error) {
@@ -122,6 +177,19 @@ func newStreamRelationStreamExecutionPlan(lp *LogicalPlan, reg udf.FunctionRegis
 	if err != nil {
 		return nil, err
 	}
+	groupPaths, err := prepareGroupPaths(lp.GroupList, lp.GroupingSets)
+	if err != nil {
+		return nil, err
+	}
+	// compute evaluators for the keys of joined states
+	stateJoins := make([]stateJoinEvaluator, len(lp.StateJoins))
+	for i, join := range lp.StateJoins {
+		key, err := ExpressionToEvaluator(lp.StateJoinKeys[i], reg)
+		if err != nil {
+			return nil, err
+		}
+		stateJoins[i] = stateJoinEvaluator{join.State, join.Alias, key}
+	}
 	// for compatibility with the old syntax, take the last RANGE
 	// specification as valid for all buffers
 
@@ -133,23 +201,59 @@ func newStreamRelationStreamExecutionPlan(lp *LogicalPlan, reg udf.FunctionRegis
 		rangeUnit := rel.Unit
 		// the alias of the relation is the key of the buffer
 		buffers[rel.Alias] = &inputBuffer{
-			tuples, rangeValue, rangeUnit,
+			tuples, rangeValue, rangeUnit, nil,
+		}
+	}
+	// compute evaluators for the keys of equi-joins and index the
+	// buffers by them
+	hashJoins := make([]hashJoin, len(lp.EquiJoins))
+	for i, join := range lp.EquiJoins {
+		hj := hashJoin{relations: [2]string{join.LeftRelation, join.RightRelation}}
+		for j, expr := range []FlatExpression{join.Left, join.Right} {
+			key, err := ExpressionToEvaluator(expr, reg)
+			if err != nil {
+				return nil, err
+			}
+			hj.keys[j] = key
+			buffer := buffers[hj.relations[j]]
+			if buffer.indexes == nil {
+				buffer.indexes = map[int]*joinIndex{}
+			}
+			buffer.indexes[i] = newJoinIndex(key)
+		}
+		hashJoins[i] = hj
+	}
+
+	// all relations have the same TUMBLING window if any
+	var tumbling *tumblingWindow
+	if len(lp.Relations) > 0 && lp.Relations[0].Tumbling {
+		t, err := newTumblingWindow(&lp.Relations[0], reg)
+		if err != nil {
+			return nil, err
 		}
+		tumbling = t
 	}
 
 	return &streamRelationStreamExecutionPlan{
 		commonExecutionPlan: commonExecutionPlan{
-			projections: projs,
-			groupList:   groupList,
-			filter:      filter,
+			projections:  projs,
+			groupList:    groupList,
+			groupPaths:   groupPaths,
+			groupingSets: lp.GroupingSets,
+			filter:       filter,
+			clock:        clockOf(reg),
 		},
 		relations:            lp.Relations,
+		stateJoins:           stateJoins,
+		hashJoins:            hashJoins,
+		ctx:                  reg.Context(),
 		buffers:              buffers,
 		emitterType:          lp.EmitterType,
 		curResults:           []resultRow{},
 		prevResults:          []resultRow{},
 		prevHashesForIstream: map[data.HashValue][]resultRowCount{},
 		filteredInputRows:    list.New(),
+		tumbling:             tumbling,
 	}, nil
 }
 
@@ -170,30 +274,16 @@ func (ep *streamRelationStreamExecutionPlan) relationKey(rel *parser.AliasedStre
 // items than allowed by the window specification, so a call to
 // removeOutdatedTuplesFromBuffer is necessary afterwards.
 func (ep *streamRelationStreamExecutionPlan) addTupleToBuffer(t *core.Tuple) error {
-	// we need to append this tuple to all buffers where the input name
-	// matches the relation name, so first we count the those buffers
-	// (for `FROM a AS left, a AS right`, this tuple will be
-	// appended to the two buffers for `left` and `right`)
-	numAppends := 0
-	for _, rel := range ep.relations {
-		if t.InputName == ep.relationKey(&rel) {
-			numAppends++
-		}
-	}
-	// if the tuple's input name didn't match any known relation,
-	// something is wrong in the topology and we should return an error
-	if numAppends == 0 {
-		knownRelNames := make([]string, 0, len(ep.relations))
-		for _, rel := range ep.relations {
-			knownRelNames = append(knownRelNames, rel.Name)
-		}
-		return fmt.Errorf("tuple has input name '%s' set, but we "+
-			"can only deal with %v", t.InputName, knownRelNames)
+	numAppends, err := ep.countBuffers(t)
+	if err != nil {
+		return err
 	}
 
 	// core.TFSharedData is set by t.ShallowCopy() below.
 
 	ep.lastTupleBuffers = make(map[string]bool, numAppends)
+	seq := ep.nextSeq
+	ep.nextSeq++
 	for _, rel := range ep.relations {
 		if t.InputName == ep.relationKey(&rel) {
 			// because the tuple is always cached, ShallowCopy is required here.
@@ -203,9 +293,11 @@ func (ep *streamRelationStreamExecutionPlan) addTupleToBuffer(t *core.Tuple) err
 			// wrap this in a container struct
 			editTupleCont := tupleWithDerivedInputRows{
 				tuple: editTuple,
+				seq:   seq,
 			}
 			buffer := ep.buffers[rel.Alias]
-			buffer.tuples.PushBack(&editTupleCont)
+			e := buffer.tuples.PushBack(&editTupleCont)
+			buffer.index(rel.Alias, e)
 			ep.lastTupleBuffers[rel.Alias] = true
 		}
 	}
@@ -213,6 +305,31 @@ func (ep *streamRelationStreamExecutionPlan) addTupleToBuffer(t *core.Tuple) err
 	return nil
 }
 
+// countBuffers returns the number of buffers the tuple is appended to.
+func (ep *streamRelationStreamExecutionPlan) countBuffers(t *core.Tuple) (int, error) {
+	// we need to append this tuple to all buffers where the input name
+	// matches the relation name, so first we count the those buffers
+	// (for `FROM a AS left, a AS right`, this tuple will be
+	// appended to the two buffers for `left` and `right`)
+	numAppends := 0
+	for _, rel := range ep.relations {
+		if t.InputName == ep.relationKey(&rel) {
+			numAppends++
+		}
+	}
+	// if the tuple's input name didn't match any known relation,
+	// something is wrong in the topology and we should return an error
+	if numAppends == 0 {
+		knownRelNames := make([]string, 0, len(ep.relations))
+		for _, rel := range ep.relations {
+			knownRelNames = append(knownRelNames, rel.Name)
+		}
+		return 0, fmt.Errorf("tuple has input name '%s' set, but we "+
+			"can only deal with %v", t.InputName, knownRelNames)
+	}
+	return numAppends, nil
+}
+
 // removeOutdatedTuplesFromBuffer removes tuples from the buffer that
 // lie outside the current window as per the statement's window
 // specification.
@@ -237,6 +354,7 @@ func (ep *streamRelationStreamExecutionPlan) removeOutdatedTuplesFromBuffer(curT
 					for _, inputRow := range tupCont.rows {
 						expiredInputRows[inputRow] = true
 					}
+					buffer.unindex(e)
 					buffer.tuples.Remove(e)
 				}
 			}
@@ -259,6 +377,7 @@ func (ep *streamRelationStreamExecutionPlan) removeOutdatedTuplesFromBuffer(curT
 					for _, inputRow := range tupCont.rows {
 						expiredInputRows[inputRow] = true
 					}
+					buffer.unindex(e)
 					buffer.tuples.Remove(e)
 				}
 			}
@@ -279,6 +398,64 @@ func (ep *streamRelationStreamExecutionPlan) removeOutdatedTuplesFromBuffer(curT
 	return nil
 }
 
+// InputLineageIDs returns lineage IDs of the tuples currently in the
+// windows, which are all the tuples the last result was computed from.
+func (ep *streamRelationStreamExecutionPlan) InputLineageIDs() []int64 {
+	var ids []int64
+	seen := map[int64]bool{}
+	for _, rel := range ep.relations {
+		buffer := ep.buffers[rel.Alias]
+		for e := buffer.tuples.Front(); e != nil; e = e.Next() {
+			id := e.Value.(*tupleWithDerivedInputRows).tuple.LineageID
+			if id == 0 || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// BufferedTuples returns the input tuples currently in the windows in the
+// order in which they arrived.
+func (ep *streamRelationStreamExecutionPlan) BufferedTuples() []*core.Tuple {
+	if ep.tumbling != nil {
+		return ep.tumbling.bufferedTuples()
+	}
+	var conts []*tupleWithDerivedInputRows
+	seen := map[int64]bool{}
+	for _, rel := range ep.relations {
+		buffer := ep.buffers[rel.Alias]
+		for e := buffer.tuples.Front(); e != nil; e = e.Next() {
+			cont := e.Value.(*tupleWithDerivedInputRows)
+			if seen[cont.seq] {
+				continue
+			}
+			seen[cont.seq] = true
+			conts = append(conts, cont)
+		}
+	}
+	sort.Sort(tuplesBySeq(conts))
+
+	ts := make([]*core.Tuple, len(conts))
+	for i, cont := range conts {
+		// the data was nested with the alias in addTupleToBuffer
+		t := cont.tuple.ShallowCopy()
+		for _, d := range cont.tuple.Data {
+			t.Data, _ = data.AsMap(d)
+		}
+		ts[i] = t
+	}
+	return ts
+}
+
+type tuplesBySeq []*tupleWithDerivedInputRows
+
+func (s tuplesBySeq) Len() int           { return len(s) }
+func (s tuplesBySeq) Less(i, j int) bool { return s[i].seq < s[j].seq }
+func (s tuplesBySeq) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
 // previousMultiplicity returns how often the given map was emitted
 // in the previous run. This is required for an ISTREAM emitter.
 func (ep *streamRelationStreamExecutionPlan) previousMultiplicity(r *resultRow) int {
@@ -408,7 +585,10 @@ func (ep *streamRelationStreamExecutionPlan) computeResultTuples() ([]data.Map,
 // to the results of the query represented by this execution plan. Note that the
 // order of items in the returned slice is undefined and cannot be relied on.
 func (ep *streamRelationStreamExecutionPlan) process(input *core.Tuple, performQueryOnBuffer func() error) ([]data.Map, error) {
-	ep.now = time.Now().In(time.UTC)
+	ep.now = ep.clock().In(time.UTC)
+	if ep.tumbling != nil {
+		return ep.processTumbling(input, performQueryOnBuffer)
+	}
 
 	// stream-to-relation:
 	// updates the internal buffer with correct window data
@@ -535,11 +715,7 @@ func (ep *streamRelationStreamExecutionPlan) preprocessCartesianProduct(dataHold
 func (ep *streamRelationStreamExecutionPlan) preprocCartProdInt(dataHolder data.Map, remainingBuffers map[string]partialList, origin map[string]*tupleWithDerivedInputRows) error {
 	if len(remainingBuffers) > 0 {
 		// not all buffers have been visited yet
-		var myKey string
-		for key := range remainingBuffers {
-			myKey = key
-			break
-		}
+		myKey, candidates, indexed := ep.nextBuffer(remainingBuffers, origin)
 		myBuffer := remainingBuffers[myKey]
 		// compile a dictionary with the rest of the unvisited streams
 		// (do NOT modify remainingBuffers directly!)
@@ -549,16 +725,32 @@ func (ep *streamRelationStreamExecutionPlan) preprocCartProdInt(dataHolder data.
 				rest[key] = buffer
 			}
 		}
-		for e := myBuffer.start; e != myBuffer.end; e = e.Next() {
+		visit := func(e *list.Element) error {
 			t := e.Value.(*tupleWithDerivedInputRows)
 			// add the data of this tuple to dataHolder and recurse
 			dataHolder[myKey] = t.tuple.Data[myKey]
 			origin[myKey] = t
 			setMetadata(dataHolder, myKey, t.tuple)
-			if err := ep.preprocCartProdInt(dataHolder, rest, origin); err != nil {
-				return err
+			return ep.preprocCartProdInt(dataHolder, rest, origin)
+		}
+		if indexed {
+			// only visit tuples having the same join key
+			for _, e := range candidates {
+				if !myBuffer.contains(e) {
+					continue
+				}
+				if err := visit(e); err != nil {
+					return err
+				}
+			}
+		} else {
+			for e := myBuffer.start; e != myBuffer.end; e = e.Next() {
+				if err := visit(e); err != nil {
+					return err
+				}
 			}
 		}
+		delete(origin, myKey)
 
 	} else {
 		// all tuples have been visited and we should now have the data
@@ -568,6 +760,18 @@ func (ep *streamRelationStreamExecutionPlan) preprocCartProdInt(dataHolder data.
 		// to each item
 		dataHolder[":meta:NOW"] = data.Timestamp(ep.now)
 
+		// add the data of joined states. if there is no data for
+		// the key in one of the states, the item is dropped
+		for _, join := range ep.stateJoins {
+			found, err := ep.lookupState(dataHolder, &join)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return nil
+			}
+		}
+
 		// evaluate filter condition
 		if ep.filter != nil {
 			filterResult, err := ep.filter.Eval(dataHolder)
@@ -609,3 +813,75 @@ func (ep *streamRelationStreamExecutionPlan) preprocCartProdInt(dataHolder data.
 	}
 	return nil
 }
+
+// nextBuffer chooses the buffer to be visited next in the computation of
+// the cartesian product. A buffer joined with an already visited buffer by
+// a hash join is preferred, and then candidates has the tuples which may
+// match the visited tuple. Otherwise, indexed is false and all tuples in
+// the buffer have to be visited.
+func (ep *streamRelationStreamExecutionPlan) nextBuffer(remainingBuffers map[string]partialList,
+	origin map[string]*tupleWithDerivedInputRows) (key string, candidates []*list.Element, indexed bool) {
+	for i, join := range ep.hashJoins {
+		for side, alias := range join.relations {
+			if _, ok := remainingBuffers[alias]; !ok {
+				continue
+			}
+			other, ok := origin[join.relations[1-side]]
+			if !ok {
+				continue
+			}
+			es, ok := ep.buffers[alias].indexes[i].candidates(other.joinKeys[i])
+			if !ok {
+				// the key of the visited tuple couldn't be computed
+				continue
+			}
+			return alias, es, true
+		}
+	}
+
+	// start from the buffer only having the new tuple, if any, so that
+	// indexes can be used for the remaining buffers
+	for _, rel := range ep.relations {
+		if p, ok := remainingBuffers[rel.Alias]; ok && p.start != nil && p.start.Next() == nil && p.end == nil {
+			return rel.Alias, nil, false
+		}
+	}
+	for _, rel := range ep.relations {
+		if _, ok := remainingBuffers[rel.Alias]; ok {
+			return rel.Alias, nil, false
+		}
+	}
+	return "", nil, false
+}
+
+// lookupState computes the key of the given joined state from the
+// data in dataHolder and stores the data found in the state under the
+// state's alias. It returns false if the state doesn't have the key.
+func (ep *streamRelationStreamExecutionPlan) lookupState(dataHolder data.Map, join *stateJoinEvaluator) (bool, error) {
+	key, err := join.key.Eval(dataHolder)
+	if err != nil {
+		return false, err
+	}
+	if key.Type() == data.TypeNull {
+		// NULL never matches a key, just as in a comparison
+		return false, nil
+	}
+	s, err := ep.ctx.SharedStates.Get(join.state)
+	if err != nil {
+		return false, err
+	}
+	keyed, ok := s.(core.KeyedState)
+	if !ok {
+		return false, fmt.Errorf("state '%s' cannot be used in JOIN STATE "+
+			"because it doesn't support key lookups", join.state)
+	}
+	v, err := keyed.Lookup(ep.ctx, key)
+	if err != nil {
+		if core.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	dataHolder[join.alias] = v
+	return true, nil
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
 	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
@@ -43,11 +44,27 @@ type LogicalPlan struct {
 	EmitterLimit        int64
 	EmitterSampling     float64
 	EmitterSamplingType parser.EmitterSamplingType
-	Projections         []aliasedExpression
+	// InputSampling and InputSamplingType describe how input tuples
+	// are sampled before they are processed, like EmitterSampling and
+	// EmitterSamplingType do for output tuples.
+	InputSampling     float64
+	InputSamplingType parser.EmitterSamplingType
+	Projections       []aliasedExpression
 	parser.WindowedFromAST
-	Filter    FlatExpression
-	GroupList []FlatExpression
+	// StateJoinKeys holds, for each item in StateJoins, the expression
+	// that computes the key to look up in the joined state.
+	StateJoinKeys []FlatExpression
+	Filter        FlatExpression
+	GroupList     []FlatExpression
+	// GroupingSets holds the grouping sets of a GROUPING SETS or
+	// ROLLUP clause as lists of indexes into GroupList, or nil if
+	// there was an ordinary GROUP BY clause.
+	GroupingSets [][]int
 	parser.HavingAST
+	parser.EmitWhenAST
+	// EquiJoins holds the equality conditions between two relations in
+	// Filter. It's computed by LogicalOptimize.
+	EquiJoins []EquiJoin
 }
 
 // PhysicalPlan is a physical interface that is capable of
@@ -71,6 +88,47 @@ type PhysicalPlan interface {
 	Process(input *core.Tuple) ([]data.Map, error)
 }
 
+// LineagePlan is a PhysicalPlan which can tell which input tuples the
+// result of the last call to Process was computed from. A plan not
+// implementing this interface computes its result only from the input tuple.
+type LineagePlan interface {
+	PhysicalPlan
+
+	// InputLineageIDs returns core.Tuple.LineageID of the input tuples
+	// which the result of the last call to Process was computed from. Tuples
+	// without a lineage ID are omitted.
+	InputLineageIDs() []int64
+}
+
+// BufferedPlan is a PhysicalPlan which keeps input tuples in windows. The
+// state of the plan can be restored by passing the buffered tuples to
+// Process of a new plan created from the same statement.
+type BufferedPlan interface {
+	PhysicalPlan
+
+	// BufferedTuples returns the input tuples currently in the windows in
+	// the order in which they arrived. A tuple appended to multiple windows
+	// on self-join is only returned once. The returned tuples have the same
+	// InputName as the original input tuples and can be passed to Process.
+	BufferedTuples() []*core.Tuple
+}
+
+// TimedPlan is a PhysicalPlan which computes results as time passes even
+// when no tuple arrives, e.g. a plan having TUMBLING windows by processing
+// time.
+type TimedPlan interface {
+	PhysicalPlan
+
+	// TickInterval returns how often Tick should be called. It returns 0
+	// when Tick doesn't have to be called.
+	TickInterval() time.Duration
+
+	// Tick returns the results computed because time has passed since the
+	// last call to Process or Tick. Like results of Process, it's the
+	// caller's task to create tuples from them.
+	Tick() ([]data.Map, error)
+}
+
 // Analyze checks the given SELECT statement for logical errors
 // (references to unknown tables etc.) and creates a LogicalPlan
 // that is internally consistent.
@@ -90,6 +148,10 @@ func Analyze(s parser.SelectStmt, reg udf.FunctionRegistry) (*LogicalPlan, error
 	   >   compatible types.
 	*/
 
+	if err := validateImplicitClauses(&s); err != nil {
+		return nil, err
+	}
+
 	if err := makeRelationAliases(&s); err != nil {
 		return nil, err
 	}
@@ -98,6 +160,10 @@ func Analyze(s parser.SelectStmt, reg udf.FunctionRegistry) (*LogicalPlan, error
 		return nil, err
 	}
 
+	if err := validateTumblingWindows(&s); err != nil {
+		return nil, err
+	}
+
 	return flattenExpressions(&s, reg)
 }
 
@@ -142,6 +208,10 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 			if projType.MetaType == parser.TimestampMeta {
 				colHeader = "ts"
 			}
+		case parser.RowMetadata:
+			if simpleColumnNameRe.MatchString(projType.Key) {
+				colHeader = projType.Key
+			}
 		case parser.RowValue:
 			// We can only use the column name as an alias if it is not
 			// a complex JSON Path. For example, `SELECT a` will be treated
@@ -183,6 +253,7 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 		if err != nil {
 			return nil, err
 		}
+		numAggParams += len(aggrs)
 		// use a special column name
 		colHeader := ":having:"
 		flatProjExprs = append(flatProjExprs,
@@ -191,6 +262,46 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 		groupingMode = true
 	}
 
+	if s.EmitWhen != nil {
+		// convert the parser Expression to a FlatExpression
+		flatExpr, aggrs, err := ParserExprToMaybeAggregate(s.EmitWhen, numAggParams, reg)
+		if err != nil {
+			return nil, err
+		}
+		numAggParams += len(aggrs)
+		// use a special column name
+		colHeader := ":emit_when:"
+		flatProjExprs = append(flatProjExprs,
+			aliasedExpression{colHeader, flatExpr, aggrs})
+		// EMIT WHEN does not enable grouping mode by itself because
+		// it is only meaningful for statements computing aggregates
+		if len(aggrs) > 0 {
+			groupingMode = true
+		}
+	}
+
+	stateJoinKeys := make([]FlatExpression, len(s.StateJoins))
+	for i, join := range s.StateJoins {
+		keyExpr, err := stateJoinKey(join)
+		if err != nil {
+			return nil, err
+		}
+		// convert the parser Expression to a FlatExpression
+		flatExpr, err := ParserExprToFlatExpr(keyExpr, reg)
+		if err != nil {
+			// return a prettier error message
+			if strings.HasPrefix(err.Error(), "you cannot use aggregate") {
+				err = fmt.Errorf("aggregates not allowed in JOIN STATE clause")
+			}
+			return nil, err
+		}
+		if flatExpr.ContainsWildcard() {
+			err := fmt.Errorf("* cannot be used in JOIN STATE clause")
+			return nil, err
+		}
+		stateJoinKeys[i] = flatExpr
+	}
+
 	var filterExpr FlatExpression
 	if s.Filter != nil {
 		filterFlatExpr, err := ParserExprToFlatExpr(s.Filter, reg)
@@ -204,7 +315,8 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 		filterExpr = filterFlatExpr
 	}
 
-	groupCols := make([]rowValue, len(s.GroupList))
+	groupCols := make([]rowValue, 0, len(s.GroupList))
+	groupRefs := map[string]string{}
 	flatGroupExprs := make([]FlatExpression, len(s.GroupList))
 	for i, expr := range s.GroupList {
 		// convert the parser Expression to a FlatExpression
@@ -216,17 +328,36 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 			}
 			return nil, err
 		}
-		// at the moment we only support grouping by single columns,
-		// not expressions
-		col, ok := flatExpr.(rowValue)
-		if !ok {
-			err := fmt.Errorf("grouping by expressions is not supported yet")
+		if flatExpr.ContainsWildcard() {
+			err := fmt.Errorf("* cannot be used in GROUP BY clause")
 			return nil, err
 		}
-		groupCols[i] = col
+		// single columns can be read from the input rows directly,
+		// all other expressions are evaluated once per group and
+		// referenced by their key
+		if col, ok := flatExpr.(rowValue); ok {
+			groupCols = append(groupCols, col)
+		} else {
+			groupRefs[flatExpr.Repr()] = groupExprRef(flatExpr)
+		}
 		flatGroupExprs[i] = flatExpr
 	}
-	groupingMode = groupingMode || len(flatGroupExprs) > 0
+	groupingMode = groupingMode || len(flatGroupExprs) > 0 ||
+		s.GroupingSets != nil
+
+	if s.EmitWhen != nil && !groupingMode {
+		err := fmt.Errorf("EMIT WHEN clause can only be used with " +
+			"aggregate functions or GROUP BY")
+		return nil, err
+	}
+
+	// replace occurrences of grouping expressions (outside of aggregate
+	// functions) by references to the per-group value
+	if len(groupRefs) > 0 {
+		for i, expr := range flatProjExprs {
+			flatProjExprs[i].expr = replaceGroupExprs(expr.expr, groupRefs)
+		}
+	}
 
 	// check if grouping is done correctly
 	if groupingMode {
@@ -243,6 +374,11 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 					"not supported yet", rm.MetaType)
 				return nil, err
 			}
+			if rm, ok := expr.expr.(rowMetadata); ok {
+				err := fmt.Errorf("using metadata '%s' in GROUP BY statements is "+
+					"not supported yet", rm.Key)
+				return nil, err
+			}
 			usedCols := expr.expr.Columns()
 			for _, usedCol := range usedCols {
 				// look for this col in the GROUP BY clause
@@ -311,20 +447,116 @@ func flattenExpressions(s *parser.SelectStmt, reg udf.FunctionRegistry) (*Logica
 		}
 	}
 
+	// validate the input sampling parameters
+	inputSampling := float64(-1)
+	inputSamplingType := parser.UnspecifiedSamplingType
+	if obj := s.InputSamplingAST.InputSampling; obj.Type != parser.UnspecifiedSamplingType {
+		v := obj.Value
+		switch obj.Type {
+		default:
+			return nil, fmt.Errorf("unknown input sampling type: %+v", obj.Type)
+		case parser.CountBasedSampling:
+			if v <= 0 || math.Trunc(v) != v {
+				return nil, fmt.Errorf("READ EVERY parameter must have a "+
+					"positive integral value, not %v", v)
+			}
+			inputSampling = v
+		case parser.RandomizedSampling:
+			if v < 0 || v > 100 {
+				return nil, fmt.Errorf("READ SAMPLE parameter must have a "+
+					"value between 0 and 100, not %v", v)
+			}
+			inputSampling = v / 100 // project to [0,1] interval
+		}
+		inputSamplingType = obj.Type
+	}
+
 	return &LogicalPlan{
 		groupingMode,
 		s.EmitterAST.EmitterType,
 		emitLimit,
 		emitSampling,
 		emitSamplingType,
+		inputSampling,
+		inputSamplingType,
 		flatProjExprs,
 		s.WindowedFromAST,
+		stateJoinKeys,
 		filterExpr,
 		flatGroupExprs,
+		s.GroupingSets,
 		s.HavingAST,
+		s.EmitWhenAST,
+		nil,
 	}, nil
 }
 
+// ReferencedColumns returns the paths of the columns of input relations
+// used in the projections, the WHERE clause, and the GROUP BY clause,
+// keyed by the alias of the relation. Columns of the only input relation
+// of a statement are stored with an empty string as the key.
+func (lp *LogicalPlan) ReferencedColumns() map[string][]string {
+	cols := map[string][]string{}
+	add := func(e FlatExpression) {
+		if e == nil {
+			return
+		}
+		for _, c := range e.Columns() {
+			cols[c.Relation] = append(cols[c.Relation], c.Column)
+		}
+	}
+	for _, proj := range lp.Projections {
+		add(proj.expr)
+		for _, e := range proj.aggrInputs {
+			add(e)
+		}
+	}
+	add(lp.Filter)
+	for _, e := range lp.GroupList {
+		add(e)
+	}
+	return cols
+}
+
+// stateJoinKey extracts the expression that computes the lookup key
+// from the ON condition of the given JOIN STATE clause, which must
+// have the form `expr = alias:key` (or `alias:key = expr`).
+func stateJoinKey(join parser.StateJoinAST) (parser.Expression, error) {
+	keyCol := parser.RowValue{Relation: join.Alias, Column: "key"}
+	if cond, ok := join.On.(parser.BinaryOpAST); ok && cond.Op == parser.Equal {
+		var keyExpr parser.Expression
+		if cond.Right == keyCol {
+			keyExpr = cond.Left
+		} else if cond.Left == keyCol {
+			keyExpr = cond.Right
+		}
+		if keyExpr != nil {
+			if keyExpr.ReferencedRelations()[join.Alias] {
+				return nil, fmt.Errorf("the key of state '%s' cannot be "+
+					"computed from the state itself", join.State)
+			}
+			return keyExpr, nil
+		}
+	}
+	return nil, fmt.Errorf("the condition of JOIN STATE %s must have "+
+		"the form 'expression = %s:key'", join.State, join.Alias)
+}
+
+// validateImplicitClauses returns an error if the emitter or a window was
+// omitted in the statement. They have to be filled with defaults before the
+// statement is analyzed.
+func validateImplicitClauses(s *parser.SelectStmt) error {
+	if s.EmitterType == parser.UnspecifiedEmitter {
+		return fmt.Errorf("the emitter (ISTREAM, DSTREAM, or RSTREAM) must be specified")
+	}
+	for _, rel := range s.Relations {
+		if rel.Unit == parser.UnspecifiedIntervalUnit {
+			return fmt.Errorf("the window of '%s' must be specified with a RANGE clause", rel.Name)
+		}
+	}
+	return nil
+}
+
 // makeRelationAliases will assign an internal alias to every relation
 // does not yet have one (given by the user). It will also detect if
 // there is a conflict between aliases.
@@ -346,11 +578,31 @@ func makeRelationAliases(s *parser.SelectStmt) error {
 		newRels[i] = aliasedRel
 	}
 	s.Relations = newRels
+
+	// joined states share the namespace of the input relations
+	stateNames := make(map[string]string, len(s.StateJoins))
+	newJoins := make([]parser.StateJoinAST, len(s.StateJoins))
+	for i, join := range s.StateJoins {
+		if join.Alias == "" {
+			join.Alias = join.State
+		}
+		if otherRel, exists := relNames[join.Alias]; exists {
+			return fmt.Errorf("cannot use relation '%s' and state '%s' with the "+
+				"same alias '%s'", otherRel.Name, join.State, join.Alias)
+		}
+		if otherState, exists := stateNames[join.Alias]; exists {
+			return fmt.Errorf("cannot use states '%s' and '%s' with the "+
+				"same alias '%s'", join.State, otherState, join.Alias)
+		}
+		stateNames[join.Alias] = join.State
+		newJoins[i] = join
+	}
+	s.StateJoins = newJoins
 	return nil
 }
 
 // validateReferences checks if the references to input relations
-// in SELECT, WHERE, GROUP BY and HAVING clauses of the given
+// in SELECT, WHERE, GROUP BY, HAVING and EMIT WHEN clauses of the given
 // statement are matching the relations mentioned in the FROM
 // clause.
 func validateReferences(s *parser.SelectStmt) error {
@@ -387,6 +639,16 @@ func validateReferences(s *parser.SelectStmt) error {
 			refRels[rel] = true
 		}
 	}
+	if s.EmitWhen != nil {
+		for rel := range s.EmitWhen.ReferencedRelations() {
+			refRels[rel] = true
+		}
+	}
+	for _, join := range s.StateJoins {
+		for rel := range join.On.ReferencedRelations() {
+			refRels[rel] = true
+		}
+	}
 
 	// do the correctness check for SELECT, WHERE, GROUP BY clauses
 	if len(s.Relations) == 0 {
@@ -394,7 +656,7 @@ func validateReferences(s *parser.SelectStmt) error {
 		// this case should never happen due to parser setup
 		return fmt.Errorf("need at least one relation to select from")
 
-	} else if len(s.Relations) == 1 {
+	} else if len(s.Relations) == 1 && len(s.StateJoins) == 0 {
 		inputRel := s.Relations[0].Alias
 		if len(refRels) == 1 {
 			// Sample: SELECT a FROM b // SELECT b.a FROM b
@@ -426,6 +688,9 @@ func validateReferences(s *parser.SelectStmt) error {
 			if s.Having != nil {
 				s.Having = s.Having.RenameReferencedRelation("", inputRel)
 			}
+			if s.EmitWhen != nil {
+				s.EmitWhen = s.EmitWhen.RenameReferencedRelation("", inputRel)
+			}
 
 		} else if len(refRels) > 1 {
 			// Sample: SELECT a, b.a FROM b // SELECT b.a, x.a FROM b
@@ -442,21 +707,29 @@ func validateReferences(s *parser.SelectStmt) error {
 		// if we arrive here, the only referenced relation is valid or
 		// we do not actually reference anything
 
-	} else if len(s.Relations) > 1 {
+	} else {
 		// Sample: SELECT b.a, c.d FROM b, c
 		// check if all referenced relations are actually listed in FROM
+		// (joined states are treated like additional input relations)
+		inputAliases := make([]string, 0, len(s.Relations)+len(s.StateJoins))
+		for _, inputRel := range s.Relations {
+			inputAliases = append(inputAliases, inputRel.Alias)
+		}
+		for _, join := range s.StateJoins {
+			inputAliases = append(inputAliases, join.Alias)
+		}
 		for rel := range refRels {
 			found := false
-			for _, inputRel := range s.Relations {
-				if rel == inputRel.Alias {
+			for _, alias := range inputAliases {
+				if rel == alias {
 					found = true
 					break
 				}
 			}
 			if !found {
-				prettyRels := make([]string, 0, len(s.Relations))
-				for _, inRel := range s.Relations {
-					prettyRels = append(prettyRels, fmt.Sprintf("'%s'", inRel.Alias))
+				prettyRels := make([]string, 0, len(inputAliases))
+				for _, alias := range inputAliases {
+					prettyRels = append(prettyRels, fmt.Sprintf("'%s'", alias))
 				}
 				prettyRelsStr := strings.Join(prettyRels, ", ")
 				err := fmt.Errorf("cannot reference relation '%s' "+
@@ -505,6 +778,52 @@ func validateReferences(s *parser.SelectStmt) error {
 	return nil
 }
 
+// validateTumblingWindows returns an error if TUMBLING or SESSION windows
+// in the statement cannot be executed. Because all relations of a statement
+// emit the result at the same time, they must have the same TUMBLING window.
+func validateTumblingWindows(s *parser.SelectStmt) error {
+	var tumbling *parser.StreamWindowAST
+	for i, rel := range s.Relations {
+		if rel.Tumbling {
+			tumbling = &s.Relations[i].StreamWindowAST
+			break
+		}
+	}
+	if tumbling == nil {
+		return nil
+	}
+	if tumbling.SessionKey != nil {
+		// sessions of different relations cannot be matched by their keys
+		if len(s.Relations) != 1 {
+			return fmt.Errorf("a SESSION window cannot be used with other relations")
+		}
+		for rel := range tumbling.SessionKey.ReferencedRelations() {
+			if rel != "" && rel != s.Relations[0].Alias {
+				return fmt.Errorf("the key of the SESSION window of '%s' "+
+					"cannot refer to relation '%s'", s.Relations[0].Alias, rel)
+			}
+		}
+		return nil
+	}
+	for _, rel := range s.Relations {
+		if rel.IntervalAST != tumbling.IntervalAST || rel.TumblingAST != tumbling.TumblingAST {
+			return fmt.Errorf("all relations must have the same TUMBLING window "+
+				"when one of them has it: '%s' and '%s' have different windows",
+				tumbling.Name, rel.Name)
+		}
+	}
+	if tumbling.Unit == parser.Tuples &&
+		(tumbling.ProcessingTime || tumbling.Delay.Unit != parser.UnspecifiedIntervalUnit) {
+		return fmt.Errorf("a TUMBLING window of TUPLES cannot have " +
+			"BY PROCESSING TIME or WATERMARK DELAY")
+	}
+	if tumbling.Delay.Value < 0 {
+		return fmt.Errorf("WATERMARK DELAY must not be negative, not %v",
+			tumbling.Delay.Value)
+	}
+	return nil
+}
+
 // LogicalOptimize does nothing at the moment. In the future, logical
 // optimizations (evaluation of foldable terms etc.) can be added here.
 func (lp *LogicalPlan) LogicalOptimize() (*LogicalPlan, error) {
@@ -515,6 +834,7 @@ func (lp *LogicalPlan) LogicalOptimize() (*LogicalPlan, error) {
 	   > pruning, null propagation, Boolean expression simplification,
 	   > and other rules.
 	*/
+	lp.EquiJoins = detectEquiJoins(lp.Filter, lp.Relations)
 	return lp, nil
 }
 
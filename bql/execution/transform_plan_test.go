@@ -48,13 +48,15 @@ func TestRelationChecker(t *testing.T) {
 	r := parser.IntervalAST{parser.FloatLiteral{2}, parser.Tuples}
 	singleFrom := parser.WindowedFromAST{
 		[]parser.AliasedStreamWindowAST{
-			{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "t", nil}, r, 0, parser.Wait}, ""},
+			{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "t", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, ""},
 		},
+		nil,
 	}
 	singleFromAlias := parser.WindowedFromAST{
 		[]parser.AliasedStreamWindowAST{
-			{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "s", nil}, r, 0, parser.Wait}, "t"},
+			{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "s", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, "t"},
 		},
+		nil,
 	}
 	two := parser.NumericLiteral{2}
 	a := parser.RowValue{"", "a"}
@@ -299,79 +301,79 @@ func TestRelationChecker(t *testing.T) {
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{a}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{two}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{two}, nil, false},
 		}, ""},
 		// SELECT 2   FROM t GROUP BY 2        -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{two}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{two}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{two}, nil, false},
 		}, ""},
 		// SELECT t:a FROM t GROUP BY 2        -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{tA}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{two}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{two}, nil, false},
 		}, ""},
 		// SELECT a   FROM t GROUP BY b        -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{a}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{b}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{b}, nil, false},
 		}, ""},
 		// SELECT a   FROM t GROUP BY b, c     -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{a}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{b, c}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{b, c}, nil, false},
 		}, ""},
 		// SELECT 2   FROM t GROUP BY b        -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{two}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{b}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{b}, nil, false},
 		}, ""},
 		// SELECT t:a FROM t GROUP BY b        -> NG
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{tA}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{b}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{b}, nil, false},
 		}, "cannot refer to relations"},
 		// SELECT a   FROM t GROUP BY t:b      -> NG
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{a}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB}, nil, false},
 		}, "cannot refer to relations"},
 		// SELECT 2   FROM t GROUP BY t:b      -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{two}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB}, nil, false},
 		}, ""},
 		// SELECT t:a FROM t GROUP BY t:b      -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{tA}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB}, nil, false},
 		}, ""},
 		// SELECT t:a FROM t GROUP BY t:b, t:c -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{tA}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB, tC}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{tB, tC}, nil, false},
 		}, ""},
 		// SELECT t:a FROM t GROUP BY b, t:b   -> NG (same table with multiple aliases)
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{tA}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{b, tB}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{b, tB}, nil, false},
 		}, "cannot refer to relations"},
 		// SELECT 2   FROM t GROUP BY x:b      -> NG
 		{&parser.SelectStmt{
 			ProjectionsAST:  parser.ProjectionsAST{[]parser.Expression{two}},
 			WindowedFromAST: singleFrom,
-			GroupingAST:     parser.GroupingAST{[]parser.Expression{xB}},
+			GroupingAST:     parser.GroupingAST{[]parser.Expression{xB}, nil, false},
 		}, "cannot refer to relation 'x' when using only 't'"},
 
 		////////// HAVING //////////////
@@ -534,52 +536,52 @@ func TestRelationAliasing(t *testing.T) {
 			ProjectionsAST: proj,
 			WindowedFromAST: parser.WindowedFromAST{
 				[]parser.AliasedStreamWindowAST{
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, ""},
-				}},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, ""},
+				}, nil},
 		}, ""},
 		// SELECT 2 FROM a AS b         -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST: proj,
 			WindowedFromAST: parser.WindowedFromAST{
 				[]parser.AliasedStreamWindowAST{
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, "b"},
-				}},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, "b"},
+				}, nil},
 		}, ""},
 		// SELECT 2 FROM a AS b, a      -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST: proj,
 			WindowedFromAST: parser.WindowedFromAST{
 				[]parser.AliasedStreamWindowAST{
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, "b"},
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, ""},
-				}},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, "b"},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, ""},
+				}, nil},
 		}, ""},
 		// SELECT 2 FROM a AS b, c AS a -> OK
 		{&parser.SelectStmt{
 			ProjectionsAST: proj,
 			WindowedFromAST: parser.WindowedFromAST{
 				[]parser.AliasedStreamWindowAST{
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, "b"},
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "c", nil}, r, 0, parser.Wait}, "a"},
-				}},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, "b"},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "c", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, "a"},
+				}, nil},
 		}, ""},
 		// SELECT 2 FROM a, a           -> NG
 		{&parser.SelectStmt{
 			ProjectionsAST: proj,
 			WindowedFromAST: parser.WindowedFromAST{
 				[]parser.AliasedStreamWindowAST{
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, ""},
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, ""},
-				}},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, ""},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, ""},
+				}, nil},
 		}, "cannot use relations"},
 		// SELECT 2 FROM a, b AS a      -> NG
 		{&parser.SelectStmt{
 			ProjectionsAST: proj,
 			WindowedFromAST: parser.WindowedFromAST{
 				[]parser.AliasedStreamWindowAST{
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait}, ""},
-					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "b", nil}, r, 0, parser.Wait}, "a"},
-				}},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "a", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, ""},
+					{parser.StreamWindowAST{parser.Stream{parser.ActualStream, "b", nil}, r, 0, parser.Wait, parser.TumblingAST{}}, "a"},
+				}, nil},
 		}, "cannot use relations"},
 	}
 
@@ -867,7 +869,14 @@ func TestAggregateChecker(t *testing.T) {
 			"* cannot be used in GROUP BY statements", nil, nil},
 
 		{"a FROM x [RANGE 1 TUPLES] GROUP BY a + 2",
-			"grouping by expressions is not supported yet", nil, nil},
+			`column "x:a" must appear in the GROUP BY clause or be used in an aggregate function`, nil, nil},
+
+		{"(a + 2) * 3 FROM x [RANGE 1 TUPLES] GROUP BY a + 2", "",
+			binaryOpAST{parser.Multiply, groupInputRef{"k_36f42f92"}, numericLiteral{3}},
+			nil},
+
+		{"count(a) FROM x [RANGE 1 TUPLES] GROUP BY f(*)",
+			"* cannot be used in GROUP BY clause", nil, nil},
 
 		// various grouping checks
 		{"a FROM x [RANGE 1 TUPLES] GROUP BY a", "",
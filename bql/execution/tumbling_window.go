@@ -0,0 +1,255 @@
+package execution
+
+import (
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	minTumblingTickInterval = 10 * time.Millisecond
+	maxTumblingTickInterval = time.Second
+)
+
+// tumblingWindow holds the state of a TUMBLING window shared by all
+// relations of a statement. Unlike a RANGE window, input tuples are kept
+// in pending until their window closes, and then the query is performed
+// only once on the tuples of the window.
+//
+// A window of time covers [start, start+size) where start is a multiple of
+// size. With event time, a window closes when the watermark, which is the
+// largest timestamp of the input tuples minus the delay, reaches the end of
+// the window. Tuples arriving after their window has been closed are
+// dropped. With processing time, tuples are assigned to windows by the time
+// they arrive and a window closes when the clock reaches its end.
+//
+// A SESSION window is also handled by tumblingWindow, but the range of
+// each window is determined by sessionWindow.
+type tumblingWindow struct {
+	// size is the length of a window of time and tuples is the number of
+	// tuples in a tuple-based window.
+	size           time.Duration
+	tuples         int
+	tupleBased     bool
+	processingTime bool
+	// delay is the delay of the watermark of event time.
+	delay time.Duration
+	// session is non-nil when the window is a SESSION window.
+	session *sessionWindow
+
+	// pending holds the input tuples of the windows that haven't been
+	// closed yet in the order in which they arrived.
+	pending []pendingTuple
+	// watermark is the time until which windows have been closed.
+	watermark time.Time
+}
+
+type pendingTuple struct {
+	tuple *core.Tuple
+	// end is the end of the window the tuple belongs to. It's the zero
+	// time when the window is tuple-based.
+	end time.Time
+	// session is the session the tuple belongs to in a SESSION window.
+	// Because the end of a session is extended by tuples, end isn't used.
+	session *session
+}
+
+// windowEnd returns the end of the window the tuple belongs to.
+func (p *pendingTuple) windowEnd() time.Time {
+	if p.session != nil {
+		return p.session.end
+	}
+	return p.end
+}
+
+// sameWindow returns true when both tuples belong to the same window.
+// Different sessions can have the same end.
+func (p *pendingTuple) sameWindow(q *pendingTuple) bool {
+	if p.session != nil {
+		return p.session == q.session
+	}
+	return p.end.Equal(q.end)
+}
+
+func newTumblingWindow(rel *parser.AliasedStreamWindowAST, reg udf.FunctionRegistry) (*tumblingWindow, error) {
+	w := &rel.StreamWindowAST
+	t := &tumblingWindow{
+		processingTime: w.ProcessingTime,
+	}
+	switch w.Unit {
+	case parser.Tuples:
+		t.tupleBased = true
+		t.tuples = int(w.Value)
+	case parser.Seconds:
+		t.size = time.Duration(w.Value * float64(time.Second))
+	case parser.Milliseconds:
+		t.size = time.Duration(w.Value * float64(time.Millisecond))
+	}
+	switch w.Delay.Unit {
+	case parser.Seconds:
+		t.delay = time.Duration(w.Delay.Value * float64(time.Second))
+	case parser.Milliseconds:
+		t.delay = time.Duration(w.Delay.Value * float64(time.Millisecond))
+	}
+	if w.SessionKey != nil {
+		s, err := newSessionWindow(rel, reg)
+		if err != nil {
+			return nil, err
+		}
+		t.session = s
+	}
+	return t, nil
+}
+
+// windowEnd returns the end of the window containing the time.
+func (w *tumblingWindow) windowEnd(t time.Time) time.Time {
+	return t.Truncate(w.size).Add(w.size)
+}
+
+func (w *tumblingWindow) bufferedTuples() []*core.Tuple {
+	ts := make([]*core.Tuple, len(w.pending))
+	for i, p := range w.pending {
+		ts[i] = p.tuple.ShallowCopy()
+	}
+	return ts
+}
+
+// processTumbling adds the input tuple to its window and returns the
+// results of the windows closed by the tuple.
+func (ep *streamRelationStreamExecutionPlan) processTumbling(input *core.Tuple, performQueryOnBuffer func() error) ([]data.Map, error) {
+	w := ep.tumbling
+	if _, err := ep.countBuffers(input); err != nil {
+		return nil, err
+	}
+	// because the tuple is kept until the window closes, ShallowCopy is
+	// required here.
+	input = input.ShallowCopy()
+
+	if w.session != nil {
+		return ep.processSession(input, performQueryOnBuffer)
+	}
+
+	if w.tupleBased {
+		w.pending = append(w.pending, pendingTuple{tuple: input})
+		if len(w.pending) < w.tuples {
+			return nil, nil
+		}
+		tuples := w.pending
+		w.pending = nil
+		return ep.closeTumblingWindow(tuples, performQueryOnBuffer)
+	}
+
+	if w.processingTime {
+		// close windows which ended before the tuple arrived first so
+		// that the tuple isn't included in them
+		output, err := ep.closeTumblingWindows(ep.now, performQueryOnBuffer)
+		if err != nil {
+			return nil, err
+		}
+		w.pending = append(w.pending, pendingTuple{tuple: input, end: w.windowEnd(ep.now)})
+		return output, nil
+	}
+
+	end := w.windowEnd(input.Timestamp)
+	if !end.After(w.watermark) {
+		// the window has already been closed
+		return nil, nil
+	}
+	w.pending = append(w.pending, pendingTuple{tuple: input, end: end})
+	return ep.closeTumblingWindows(input.Timestamp.Add(-w.delay), performQueryOnBuffer)
+}
+
+// tick returns the results of the windows closed by processing time.
+func (ep *streamRelationStreamExecutionPlan) tick(performQueryOnBuffer func() error) ([]data.Map, error) {
+	if ep.tumbling == nil || !ep.tumbling.processingTime {
+		return nil, nil
+	}
+	ep.now = ep.clock().In(time.UTC)
+	return ep.closeTumblingWindows(ep.now, performQueryOnBuffer)
+}
+
+// TickInterval returns a tenth of the size of a TUMBLING window by
+// processing time so that a window is closed soon after it ends.
+func (ep *streamRelationStreamExecutionPlan) TickInterval() time.Duration {
+	if ep.tumbling == nil || !ep.tumbling.processingTime {
+		return 0
+	}
+	d := ep.tumbling.size / 10
+	if d < minTumblingTickInterval {
+		d = minTumblingTickInterval
+	} else if d > maxTumblingTickInterval {
+		d = maxTumblingTickInterval
+	}
+	return d
+}
+
+// closeTumblingWindows closes all windows ending at or before the
+// watermark in the order of time and returns their results.
+func (ep *streamRelationStreamExecutionPlan) closeTumblingWindows(watermark time.Time, performQueryOnBuffer func() error) ([]data.Map, error) {
+	w := ep.tumbling
+	if !watermark.After(w.watermark) {
+		return nil, nil
+	}
+	w.watermark = watermark
+
+	var output []data.Map
+	for len(w.pending) > 0 {
+		first := &w.pending[0]
+		for i := range w.pending {
+			if w.pending[i].windowEnd().Before(first.windowEnd()) {
+				first = &w.pending[i]
+			}
+		}
+		if first.windowEnd().After(watermark) {
+			break
+		}
+
+		var tuples, rest []pendingTuple
+		for _, p := range w.pending {
+			if p.sameWindow(first) {
+				tuples = append(tuples, p)
+			} else {
+				rest = append(rest, p)
+			}
+		}
+		if w.session != nil {
+			w.session.remove(first.session)
+		}
+		w.pending = rest
+		res, err := ep.closeTumblingWindow(tuples, performQueryOnBuffer)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, res...)
+	}
+	return output, nil
+}
+
+// closeTumblingWindow performs the query on the tuples of a window. The
+// tuples are left in the buffers until the next window closes so that
+// InputLineageIDs can return them.
+func (ep *streamRelationStreamExecutionPlan) closeTumblingWindow(tuples []pendingTuple, performQueryOnBuffer func() error) ([]data.Map, error) {
+	for _, buffer := range ep.buffers {
+		buffer.tuples.Init()
+		for i, idx := range buffer.indexes {
+			buffer.indexes[i] = newJoinIndex(idx.key)
+		}
+	}
+	ep.filteredInputRows.Init()
+
+	for _, p := range tuples {
+		if err := ep.addTupleToBuffer(p.tuple); err != nil {
+			return nil, err
+		}
+		if err := ep.filterInputTuples(); err != nil {
+			return nil, err
+		}
+	}
+	if err := performQueryOnBuffer(); err != nil {
+		return nil, err
+	}
+	return ep.computeResultTuples()
+}
@@ -0,0 +1,235 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func createTumblingPlan(s string, clock func() time.Time) (PhysicalPlan, error) {
+	reg := udf.CopyGlobalUDFRegistry(core.NewContext(&core.ContextConfig{Clock: clock}))
+	stmt, _, err := parser.New().ParseStmt(s)
+	if err != nil {
+		return nil, err
+	}
+	lp, err := Analyze(stmt.(parser.CreateStreamAsSelectStmt).Select, reg)
+	if err != nil {
+		return nil, err
+	}
+	if lp, err = lp.LogicalOptimize(); err != nil {
+		return nil, err
+	}
+	return lp.MakePhysicalPlan(reg)
+}
+
+func TestTumblingWindow(t *testing.T) {
+	base := time.Date(2015, time.April, 10, 10, 23, 0, 0, time.UTC)
+	tuple := func(input string, sec float64, v int) *core.Tuple {
+		ts := base.Add(time.Duration(sec * float64(time.Second)))
+		return &core.Tuple{
+			Data:          data.Map{"v": data.Int(v)},
+			InputName:     input,
+			Timestamp:     ts,
+			ProcTimestamp: ts,
+		}
+	}
+
+	Convey("Given a statement having a TUMBLING window by event time", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM count(*) AS c, sum(v) AS s
+			FROM src [TUMBLING 10 SECONDS]`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When tuples arrive in a window", func() {
+			for i, sec := range []float64{0, 3, 9.5} {
+				res, err := p.Process(tuple("src", sec, i+1))
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+			}
+
+			Convey("Then the window should emit its result once when it closes", func() {
+				res, err := p.Process(tuple("src", 12, 10))
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, []data.Map{{"c": data.Int(3), "s": data.Int(6)}})
+
+				res, err = p.Process(tuple("src", 15, 20))
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+
+				Convey("And a late tuple should be dropped", func() {
+					res, err := p.Process(tuple("src", 5, 100))
+					So(err, ShouldBeNil)
+					So(res, ShouldBeEmpty)
+
+					res, err = p.Process(tuple("src", 31, 40))
+					So(err, ShouldBeNil)
+					So(res, ShouldResemble, []data.Map{{"c": data.Int(2), "s": data.Int(30)}})
+				})
+
+				Convey("And buffered tuples should only have the open window", func() {
+					ts := p.(BufferedPlan).BufferedTuples()
+					So(len(ts), ShouldEqual, 2)
+					So(ts[0].Data, ShouldResemble, data.Map{"v": data.Int(10)})
+					So(ts[1].Data, ShouldResemble, data.Map{"v": data.Int(20)})
+				})
+			})
+
+			Convey("Then a tuple skipping windows should close the open window only", func() {
+				res, err := p.Process(tuple("src", 100, 10))
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, []data.Map{{"c": data.Int(3), "s": data.Int(6)}})
+			})
+		})
+	})
+
+	Convey("Given a statement having a TUMBLING window with a watermark delay", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM count(*) AS c, sum(v) AS s
+			FROM src [TUMBLING 10 SECONDS, WATERMARK DELAY 5 SECONDS]`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When tuples arrive out of order", func() {
+			var res []data.Map
+			for _, in := range []*core.Tuple{
+				tuple("src", 1, 1), tuple("src", 11, 2), tuple("src", 8, 3),
+				tuple("src", 14, 4), tuple("src", 9, 5), tuple("src", 22, 6),
+				tuple("src", 15.5, 7), tuple("src", 17, 8), tuple("src", 40, 9),
+			} {
+				r, err := p.Process(in)
+				So(err, ShouldBeNil)
+				res = append(res, r...)
+			}
+
+			Convey("Then tuples within the delay should be in their windows", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"c": data.Int(3), "s": data.Int(9)},
+					{"c": data.Int(4), "s": data.Int(21)},
+					{"c": data.Int(1), "s": data.Int(6)},
+				})
+			})
+		})
+	})
+
+	Convey("Given a statement having a TUMBLING window of tuples", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT ISTREAM v
+			FROM src [TUMBLING 2 TUPLES] WHERE v > 0`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When tuples arrive", func() {
+			var res [][]data.Map
+			for i, v := range []int{1, 2, -3, 4, 1, 2} {
+				r, err := p.Process(tuple("src", float64(i), v))
+				So(err, ShouldBeNil)
+				res = append(res, r)
+			}
+
+			Convey("Then each window should be compared with the previous one", func() {
+				So(res, ShouldResemble, [][]data.Map{
+					nil, {{"v": data.Int(1)}, {"v": data.Int(2)}},
+					nil, {{"v": data.Int(4)}},
+					nil, {{"v": data.Int(1)}, {"v": data.Int(2)}},
+				})
+			})
+		})
+	})
+
+	Convey("Given a statement joining streams in a TUMBLING window", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM l:v AS l, r:v AS r
+			FROM a [TUMBLING 10 SECONDS] AS l, b [TUMBLING 10 SECONDS] AS r
+			WHERE l:v = r:v`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("When tuples arrive from both streams", func() {
+			var res []data.Map
+			for _, in := range []*core.Tuple{
+				tuple("a", 1, 1), tuple("b", 2, 2), tuple("b", 3, 1),
+				tuple("a", 11, 2), tuple("b", 12, 2), tuple("a", 25, 1),
+			} {
+				r, err := p.Process(in)
+				So(err, ShouldBeNil)
+				res = append(res, r...)
+			}
+
+			Convey("Then only tuples in the same window should be joined", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"l": data.Int(1), "r": data.Int(1)},
+					{"l": data.Int(2), "r": data.Int(2)},
+				})
+			})
+		})
+	})
+
+	Convey("Given a statement having a TUMBLING window by processing time", t, func() {
+		now := base
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM count(*) AS c
+			FROM src [TUMBLING 2 SECONDS BY PROCESSING TIME]`, func() time.Time { return now })
+		So(err, ShouldBeNil)
+		tp := p.(TimedPlan)
+
+		Convey("Then it should be ticked", func() {
+			So(tp.TickInterval(), ShouldEqual, 200*time.Millisecond)
+		})
+
+		Convey("When tuples arrive", func() {
+			for i := 0; i < 3; i++ {
+				res, err := p.Process(tuple("src", 100, i))
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+				now = now.Add(500 * time.Millisecond)
+			}
+
+			Convey("Then the window should be closed by the clock", func() {
+				res, err := tp.Tick()
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+
+				now = now.Add(500 * time.Millisecond)
+				res, err = tp.Tick()
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, []data.Map{{"c": data.Int(3)}})
+
+				now = now.Add(10 * time.Second)
+				res, err = tp.Tick()
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+			})
+
+			Convey("Then a tuple arriving after the window ends should close it", func() {
+				now = now.Add(500 * time.Millisecond)
+				res, err := p.Process(tuple("src", 0, 3))
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, []data.Map{{"c": data.Int(3)}})
+			})
+		})
+	})
+
+	Convey("Given a statement having a TUMBLING window by event time", t, func() {
+		p, err := createTumblingPlan(`CREATE STREAM box AS SELECT RSTREAM count(*) AS c
+			FROM src [TUMBLING 2 SECONDS]`, nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then it shouldn't be ticked", func() {
+			So(p.(TimedPlan).TickInterval(), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given invalid TUMBLING windows", t, func() {
+		for _, s := range []string{
+			"FROM a [TUMBLING 2 SECONDS], b [RANGE 2 SECONDS]",
+			"FROM a [TUMBLING 2 SECONDS], b [TUMBLING 3 SECONDS]",
+			"FROM a [TUMBLING 2 SECONDS], b [TUMBLING 2 SECONDS BY PROCESSING TIME]",
+			"FROM a [TUMBLING 2 TUPLES, WATERMARK DELAY 1 SECONDS]",
+			"FROM a [TUMBLING 2 TUPLES BY PROCESSING TIME]",
+			"FROM a [TUMBLING 0 SECONDS]",
+			"FROM a [TUMBLING 2 SECONDS, WATERMARK DELAY -1 SECONDS]",
+		} {
+			Convey("Then "+s+" should be rejected", func() {
+				_, err := createTumblingPlan("CREATE STREAM box AS SELECT RSTREAM count(*) "+s, nil)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
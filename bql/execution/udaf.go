@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// udafParallelThreshold is the minimum number of rows in a group for which
+// a UDAF is computed in parallel. Smaller groups are computed sequentially
+// because the overhead of goroutines and merging exceeds the gain.
+var udafParallelThreshold = 8192
+
+// udafParallelism returns the maximum number of goroutines computing a
+// UDAF for a group.
+var udafParallelism = func() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// parallelUDAF computes a UDAF for a group. A large group is split into
+// chunks of consecutive rows, which are accumulated into different states
+// in parallel and merged in order.
+type parallelUDAF struct {
+	udf.UDAFUDF
+}
+
+func (p *parallelUDAF) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	f := p.UDAF()
+	n, err := udf.NumUDAFRows(f, args)
+	if err != nil {
+		return nil, err
+	}
+	chunks := udafParallelism()
+	if n < udafParallelThreshold || chunks <= 1 {
+		return p.UDAFUDF.Call(ctx, args...)
+	}
+	if chunks > n {
+		chunks = n
+	}
+
+	states := make([]udf.UDAFState, chunks)
+	for i := range states {
+		s, err := f.Init(ctx)
+		if err != nil {
+			return nil, err
+		}
+		states[i] = s
+	}
+	errs := make([]error, chunks)
+	var wg sync.WaitGroup
+	for i := range states {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = core.PanicError(fmt.Errorf("aggregating rows paniced: %s", r))
+				}
+			}()
+			errs[i] = udf.AccumulateUDAF(ctx, f, states[i], args, n*i/chunks, n*(i+1)/chunks)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range states[1:] {
+		if err := states[0].Merge(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return states[0].Result(ctx)
+}
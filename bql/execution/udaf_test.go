@@ -0,0 +1,174 @@
+package execution
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// joinUDAF joins string representations of values with a separator, so
+// that the order of rows can be checked.
+type joinUDAF struct {
+	merged int32
+}
+
+func (f *joinUDAF) Accept(arity int) bool {
+	return arity == 2
+}
+
+func (f *joinUDAF) IsAggregationParameter(k int) bool {
+	return k == 0
+}
+
+func (f *joinUDAF) Init(ctx *core.Context) (udf.UDAFState, error) {
+	return &joinState{f: f}, nil
+}
+
+type joinState struct {
+	f   *joinUDAF
+	s   string
+	sep string
+}
+
+func (s *joinState) Accumulate(ctx *core.Context, args ...data.Value) error {
+	if args[0].Type() == data.TypeString && args[0].String() == `"panic"` {
+		panic("test")
+	}
+	sep, err := data.AsString(args[1])
+	if err != nil {
+		return err
+	}
+	if s.s != "" {
+		s.s += sep
+	}
+	s.s += args[0].String()
+	s.sep = sep
+	return nil
+}
+
+func (s *joinState) Merge(ctx *core.Context, other udf.UDAFState) error {
+	atomic.AddInt32(&s.f.merged, 1)
+	o := other.(*joinState)
+	if s.s != "" && o.s != "" {
+		s.s += o.sep
+	}
+	s.s += o.s
+	return nil
+}
+
+func (s *joinState) Result(ctx *core.Context) (data.Value, error) {
+	return data.String(s.s), nil
+}
+
+func TestUDAF(t *testing.T) {
+	Convey("Given a statement using a UDAF", t, func() {
+		f := &joinUDAF{}
+		reg := udf.CopyGlobalUDFRegistry(core.NewContext(nil))
+		So(reg.Register("join_rows", udf.UDAFToUDF(f)), ShouldBeNil)
+		stmt, _, err := parser.New().ParseStmt(`CREATE STREAM box AS SELECT RSTREAM
+			k, join_rows(v, ",") AS j, join_rows(v + 1, "-" ORDER BY v DESC) AS r, count(*) AS c
+			FROM src [RANGE 100 TUPLES] GROUP BY k`)
+		So(err, ShouldBeNil)
+		lp, err := Analyze(stmt.(parser.CreateStreamAsSelectStmt).Select, reg)
+		So(err, ShouldBeNil)
+		p, err := lp.MakePhysicalPlan(reg)
+		So(err, ShouldBeNil)
+
+		process := func(n int) []data.Map {
+			var res []data.Map
+			for i := 0; i < n; i++ {
+				r, err := p.Process(&core.Tuple{
+					Data:      data.Map{"k": data.Int(i % 2), "v": data.Int(i)},
+					InputName: "src",
+				})
+				So(err, ShouldBeNil)
+				res = r
+			}
+			return res
+		}
+
+		Convey("When tuples are processed", func() {
+			res := process(6)
+
+			Convey("Then the UDAF should aggregate each group in order", func() {
+				So(sortedResults(res), ShouldResemble, sortedResults([]data.Map{
+					{"k": data.Int(0), "j": data.String("0,2,4"), "r": data.String("5-3-1"), "c": data.Int(3)},
+					{"k": data.Int(1), "j": data.String("1,3,5"), "r": data.String("6-4-2"), "c": data.Int(3)},
+				}))
+				So(atomic.LoadInt32(&f.merged), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When large groups are aggregated in parallel", func() {
+			threshold, parallelism := udafParallelThreshold, udafParallelism
+			udafParallelThreshold = 4
+			udafParallelism = func() int { return 3 }
+			Reset(func() {
+				udafParallelThreshold, udafParallelism = threshold, parallelism
+			})
+			res := process(10)
+
+			Convey("Then states should be merged in order", func() {
+				So(sortedResults(res), ShouldResemble, sortedResults([]data.Map{
+					{"k": data.Int(0), "j": data.String("0,2,4,6,8"), "r": data.String("9-7-5-3-1"), "c": data.Int(5)},
+					{"k": data.Int(1), "j": data.String("1,3,5,7,9"), "r": data.String("10-8-6-4-2"), "c": data.Int(5)},
+				}))
+				So(atomic.LoadInt32(&f.merged), ShouldBeGreaterThan, 0)
+			})
+		})
+	})
+
+	Convey("Given a UDAF converted to a UDF", t, func() {
+		a, err := newPathAccess("a")
+		So(err, ShouldBeNil)
+		b, err := newPathAccess("b")
+		So(err, ShouldBeNil)
+		fun := FuncApp("join_rows", udf.UDAFToUDF(&joinUDAF{}), core.NewContext(nil), []Evaluator{a, b})
+
+		for _, p := range []int{1, 2, 4} {
+			p := p
+			Convey(fmt.Sprintf("When evaluating it with parallelism %v", p), func() {
+				threshold, parallelism := udafParallelThreshold, udafParallelism
+				udafParallelThreshold = 1
+				udafParallelism = func() int { return p }
+				Reset(func() {
+					udafParallelThreshold, udafParallelism = threshold, parallelism
+				})
+
+				Convey("Then it should aggregate all rows", func() {
+					v, err := fun.Eval(data.Map{"a": data.Array{data.Int(1), data.Int(2), data.Int(3)}, "b": data.String(":")})
+					So(err, ShouldBeNil)
+					So(v, ShouldEqual, data.String("1:2:3"))
+				})
+
+				Convey("Then it should handle an empty group", func() {
+					v, err := fun.Eval(data.Map{"a": data.Array{}, "b": data.String(":")})
+					So(err, ShouldBeNil)
+					So(v, ShouldEqual, data.String(""))
+				})
+
+				Convey("Then it should fail on an error in a state", func() {
+					_, err := fun.Eval(data.Map{"a": data.Array{data.Int(1), data.Int(2)}, "b": data.Int(1)})
+					So(err, ShouldNotBeNil)
+				})
+
+				Convey("Then it should fail on a panic in a state", func() {
+					_, err := fun.Eval(data.Map{"a": data.Array{data.Int(1), data.String("panic")}, "b": data.String(":")})
+					So(err, ShouldNotBeNil)
+					So(core.IsPanicError(err), ShouldBeTrue)
+				})
+
+				Convey("Then it should fail when the input isn't an array", func() {
+					_, err := fun.Eval(data.Map{"a": data.Int(1), "b": data.String(":")})
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package bql
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// labelsParam is the name of the parameter which every CREATE statement
+// accepts in its WITH clause to attach labels to nodes and states:
+//
+//	CREATE SOURCE s TYPE fluentd WITH labels = {"team": "sensing"};
+//	CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]
+//	  WITH labels = {"team": "sensing", "cost_center": "cc123"};
+//
+// The parameter isn't passed to creators of sources, sinks, and states.
+const labelsParam = "labels"
+
+// extractLabels removes the labels parameter from params and returns labels
+// given by it. It returns nil when params doesn't have labels.
+func extractLabels(params data.Map) (map[string]string, error) {
+	v, ok := params[labelsParam]
+	if !ok {
+		return nil, nil
+	}
+	delete(params, labelsParam)
+
+	m, err := data.AsMap(v)
+	if err != nil {
+		return nil, fmt.Errorf("'%v' parameter must be a map: %v", labelsParam, err)
+	}
+	labels := make(map[string]string, len(m))
+	for k, l := range m {
+		s, err := data.AsString(l)
+		if err != nil {
+			return nil, fmt.Errorf("the value of label '%v' must be a string: %v", k, err)
+		}
+		labels[k] = s
+	}
+	if err := core.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// streamLabels returns labels given by the WITH clause of a CREATE STREAM
+// statement, which only supports the labels and protected parameters.
+func (tb *TopologyBuilder) streamLabels(params []parser.SourceSinkParamAST) (map[string]string, error) {
+	m, err := tb.mkParamsMap(params)
+	if err != nil {
+		return nil, err
+	}
+	delete(m, protectedParam)
+	labels, err := extractLabels(m)
+	if err != nil {
+		return nil, err
+	}
+	for k := range m {
+		return nil, fmt.Errorf("CREATE STREAM doesn't support '%v' parameter", k)
+	}
+	return labels, nil
+}
+
+// setStateLabels records labels of a state created by CREATE STATE.
+func (tb *TopologyBuilder) setStateLabels(name string, labels map[string]string) {
+	tb.stateLabelMutex.Lock()
+	defer tb.stateLabelMutex.Unlock()
+	if len(labels) == 0 {
+		delete(tb.stateLabels, strings.ToLower(name))
+		return
+	}
+	tb.stateLabels[strings.ToLower(name)] = labels
+}
+
+// StateLabels returns labels given to the state by the WITH clause of the
+// CREATE STATE statement which created it. It returns nil when the state
+// doesn't exist or doesn't have labels.
+func (tb *TopologyBuilder) StateLabels(name string) map[string]string {
+	if _, err := tb.topology.Context().SharedStates.Get(name); err != nil {
+		// The state has been removed by other than DROP STATE.
+		tb.setStateLabels(name, nil)
+		return nil
+	}
+	tb.stateLabelMutex.Lock()
+	defer tb.stateLabelMutex.Unlock()
+	return tb.stateLabels[strings.ToLower(name)]
+}
+
+// labelsToMap converts labels to data.Map so that they can be a part of
+// tuples emitted by system streams.
+func labelsToMap(labels map[string]string) data.Map {
+	m := make(data.Map, len(labels))
+	for k, v := range labels {
+		m[k] = data.String(v)
+	}
+	return m
+}
@@ -0,0 +1,109 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestLabels(t *testing.T) {
+	Convey("Given a topology builder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		labels := map[string]string{"team": "sensing", "cost_center": "cc123"}
+
+		Convey("When creating nodes and a state with labels", func() {
+			So(addBQLToTopology(tb, `
+				CREATE PAUSED SOURCE s TYPE dummy WITH num=4, labels={"team":"sensing", "cost_center":"cc123"};
+				CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]
+					WITH labels={"team":"sensing", "cost_center":"cc123"};
+				CREATE STREAM u AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]
+					UNION ALL SELECT RSTREAM * FROM t [RANGE 1 TUPLES]
+					WITH labels={"team":"sensing", "cost_center":"cc123"};
+				CREATE SINK k TYPE collector WITH labels={"team":"sensing", "cost_center":"cc123"};
+				CREATE STATE st TYPE dummy_uds WITH num=1, labels={"team":"sensing", "cost_center":"cc123"};`), ShouldBeNil)
+
+			Convey("Then the nodes should have the labels", func() {
+				for _, name := range []string{"s", "t", "u", "k"} {
+					n, err := dt.Node(name)
+					So(err, ShouldBeNil)
+					So(n.Labels(), ShouldResemble, labels)
+					So(n.Status()["labels"], ShouldResemble, data.Map{
+						"team":        data.String("sensing"),
+						"cost_center": data.String("cc123"),
+					})
+				}
+			})
+
+			Convey("Then the state should have the labels", func() {
+				So(tb.StateLabels("st"), ShouldResemble, labels)
+			})
+
+			Convey("Then the labels of the state should be removed when it's dropped", func() {
+				So(addBQLToTopology(tb, `DROP STATE st;`), ShouldBeNil)
+				So(tb.StateLabels("st"), ShouldBeNil)
+			})
+
+			Convey("Then the statement should have the labels", func() {
+				stmt := ""
+				for _, m := range tb.statementSnapshot() {
+					if m["node_name"] == data.String("t") {
+						stmt, _ = data.AsString(m["statement"])
+					}
+				}
+				So(stmt, ShouldContainSubstring, `WITH labels={`)
+			})
+
+			Convey("Then system.stats should have the labels", func() {
+				var ls data.Value
+				for _, m := range tb.statsSnapshot() {
+					if m["node_name"] == data.String("k") {
+						ls = m["labels"]
+					}
+				}
+				So(ls, ShouldResemble, data.Map{
+					"team":        data.String("sensing"),
+					"cost_center": data.String("cc123"),
+				})
+			})
+		})
+
+		Convey("When creating a source without labels", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;`), ShouldBeNil)
+
+			Convey("Then the node shouldn't have labels", func() {
+				n, err := dt.Node("s")
+				So(err, ShouldBeNil)
+				So(n.Labels(), ShouldBeEmpty)
+				So(n.Status(), ShouldNotContainKey, "labels")
+			})
+		})
+
+		Convey("When creating nodes with invalid labels", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;`), ShouldBeNil)
+
+			Convey("Then labels which isn't a map should be rejected", func() {
+				So(addBQLToTopology(tb, `CREATE SINK k TYPE collector WITH labels="team";`), ShouldNotBeNil)
+			})
+
+			Convey("Then labels having a non-string value should be rejected", func() {
+				So(addBQLToTopology(tb, `CREATE SINK k TYPE collector WITH labels={"team":1};`), ShouldNotBeNil)
+			})
+
+			Convey("Then labels having an invalid key should be rejected", func() {
+				So(addBQLToTopology(tb, `CREATE STATE st TYPE dummy_uds WITH num=1, labels={"my-team":"a"};`), ShouldNotBeNil)
+			})
+
+			Convey("Then a stream having other parameters should be rejected", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES] WITH num=1;`), ShouldNotBeNil)
+				_, err := dt.Node("t")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,268 @@
+package bql
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// defaultLatestByMaxKeys is the default maximum number of keys a
+	// latest_by state keeps at once.
+	defaultLatestByMaxKeys = 100000
+)
+
+// latestBy is a UDS keeping the latest tuple of each key in a stream. It
+// implements core.KeyedState so that another stream can be enriched with the
+// latest tuple by a JOIN STATE clause or the enrich function without joining
+// windows of both streams:
+//
+//	CREATE STATE device_status TYPE latest_by
+//	  WITH stream="statuses", key="device_id";
+//	SELECT RSTREAM r:device_id, r:value, d:status
+//	  FROM readings [RANGE 1 TUPLES] AS r
+//	  JOIN STATE device_status AS d ON r:device_id = d:key;
+//
+// It has following parameters:
+//
+//	* key: a JSON Path of the key in each tuple (required)
+//	* stream: the name of a source or a stream whose tuples update the
+//	  state. When it's omitted, the state can be updated by a uds sink.
+//	* max_keys: the maximum number of keys to be kept. When the number of
+//	  keys exceeds this limit, the key updated least recently is removed.
+//	  The default value is 100000.
+//	* ttl: a duration for which a tuple is kept after it arrived. A key is
+//	  removed when no tuple having the key arrives within the duration. It's
+//	  in seconds when the value is a number. Tuples are kept until they're
+//	  evicted by max_keys when it's omitted.
+//
+// Tuples which don't have the key are ignored. When stream is given, the
+// state is updated by a sink which is removed with the state or the stream.
+type latestBy struct {
+	key     data.Path
+	stream  string
+	maxKeys int
+	ttl     time.Duration
+
+	m      sync.RWMutex
+	order  *list.List // of *latestByEntry in the order they were updated
+	index  map[data.HashValue][]*list.Element
+	closed bool
+
+	numUpdates int64
+	numEvicted int64
+	numExpired int64
+}
+
+type latestByEntry struct {
+	key       data.Value
+	hash      data.HashValue
+	row       data.Map
+	updatedAt time.Time
+}
+
+var (
+	_ core.KeyedState = &latestBy{}
+	_ core.Writer     = &latestBy{}
+	_ core.Statuser   = &latestBy{}
+)
+
+func createLatestBy(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Key     string `bql:",required"`
+		Stream  string
+		MaxKeys int
+		TTL     time.Duration `bql:"ttl"`
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	key, err := data.CompilePath(c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("'key' parameter doesn't have a valid path: %v", err)
+	}
+	if c.MaxKeys < 0 {
+		return nil, errors.New("'max_keys' parameter must be positive")
+	}
+	if c.MaxKeys == 0 {
+		c.MaxKeys = defaultLatestByMaxKeys
+	}
+	if c.TTL < 0 {
+		return nil, errors.New("'ttl' parameter must not be negative")
+	}
+	return &latestBy{
+		key:     key,
+		stream:  c.Stream,
+		maxKeys: c.MaxKeys,
+		ttl:     c.TTL,
+		order:   list.New(),
+		index:   map[data.HashValue][]*list.Element{},
+	}, nil
+}
+
+// Write updates the latest tuple of the key.
+func (s *latestBy) Write(ctx *core.Context, t *core.Tuple) error {
+	k, err := t.Data.Get(s.key)
+	if err != nil {
+		return nil
+	}
+	h := data.Hash(k)
+	row := t.Data.Copy()
+	now := time.Now()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return errors.New("the state is already terminated")
+	}
+	s.numUpdates++
+	s.expire(now)
+	for _, e := range s.index[h] {
+		if le := e.Value.(*latestByEntry); data.Equal(le.key, k) {
+			le.row = row
+			le.updatedAt = now
+			s.order.MoveToBack(e)
+			return nil
+		}
+	}
+	s.index[h] = append(s.index[h], s.order.PushBack(&latestByEntry{
+		key:       k,
+		hash:      h,
+		row:       row,
+		updatedAt: now,
+	}))
+	for s.order.Len() > s.maxKeys {
+		s.removeOldest()
+		s.numEvicted++
+	}
+	return nil
+}
+
+// expire removes tuples older than ttl. The caller must hold the write lock.
+func (s *latestBy) expire(now time.Time) {
+	if s.ttl <= 0 {
+		return
+	}
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		if now.Sub(e.Value.(*latestByEntry).updatedAt) <= s.ttl {
+			return
+		}
+		s.removeOldest()
+		s.numExpired++
+	}
+}
+
+// removeOldest removes the key updated least recently. The caller must hold
+// the write lock.
+func (s *latestBy) removeOldest() {
+	e := s.order.Front()
+	s.order.Remove(e)
+	le := e.Value.(*latestByEntry)
+
+	es := s.index[le.hash]
+	for i, x := range es {
+		if x == e {
+			es = append(es[:i], es[i+1:]...)
+			break
+		}
+	}
+	if len(es) == 0 {
+		delete(s.index, le.hash)
+	} else {
+		s.index[le.hash] = es
+	}
+}
+
+// Lookup returns the latest tuple having the key.
+func (s *latestBy) Lookup(ctx *core.Context, key data.Value) (data.Map, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	if s.closed {
+		return nil, errors.New("the state is already terminated")
+	}
+	for _, e := range s.index[data.Hash(key)] {
+		le := e.Value.(*latestByEntry)
+		if !data.Equal(le.key, key) {
+			continue
+		}
+		if s.ttl > 0 && time.Now().Sub(le.updatedAt) > s.ttl {
+			break
+		}
+		return le.row, nil
+	}
+	return nil, core.NotExistError(fmt.Errorf("the key %v doesn't exist", key))
+}
+
+// Status returns the number of keys and statistics of updates.
+func (s *latestBy) Status() data.Map {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	st := data.Map{
+		"num_keys":    data.Int(s.order.Len()),
+		"num_updates": data.Int(s.numUpdates),
+		"num_evicted": data.Int(s.numEvicted),
+		"num_expired": data.Int(s.numExpired),
+	}
+	if s.stream != "" {
+		st["stream"] = data.String(s.stream)
+	}
+	return st
+}
+
+func (s *latestBy) Terminate(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.closed = true
+	s.order.Init()
+	s.index = nil
+	return nil
+}
+
+// latestByFeederName returns the name of the sink updating the state.
+func latestByFeederName(state string) string {
+	return "sensorbee_latest_by_" + strings.ToLower(state)
+}
+
+// feedLatestBy connects the stream given to the latest_by state to the state.
+// It doesn't do anything when the state isn't a latest_by state or doesn't
+// have a stream.
+func (tb *TopologyBuilder) feedLatestBy(name string, state core.SharedState) error {
+	s, ok := state.(*latestBy)
+	if !ok || s.stream == "" {
+		return nil
+	}
+	ctx := tb.topology.Context()
+	sink, err := core.NewSharedStateSink(ctx, name)
+	if err != nil {
+		return err
+	}
+	sn, err := tb.topology.AddSink(latestByFeederName(name), sink, nil)
+	if err != nil {
+		return err
+	}
+	if err := sn.Input(s.stream, nil); err != nil {
+		tb.topology.Remove(sn.Name())
+		return err
+	}
+	sn.StopOnDisconnect()
+	sn.RemoveOnStop()
+	return nil
+}
+
+// removeLatestByFeeder removes the sink updating the state if it exists.
+func (tb *TopologyBuilder) removeLatestByFeeder(name string) {
+	if _, err := tb.topology.Sink(latestByFeederName(name)); err == nil {
+		tb.topology.Remove(latestByFeederName(name))
+	}
+}
+
+func init() {
+	udf.MustRegisterGlobalUDSCreator("latest_by", udf.UDSCreatorFunc(createLatestBy))
+}
@@ -0,0 +1,155 @@
+package bql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestLatestBy(t *testing.T) {
+	ctx := core.NewContext(nil)
+
+	Convey("Given a latest_by state", t, func() {
+		s, err := createLatestBy(ctx, data.Map{
+			"key":      data.String("id"),
+			"max_keys": data.Int(2),
+		})
+		So(err, ShouldBeNil)
+		Reset(func() {
+			s.Terminate(ctx)
+		})
+		lb := s.(*latestBy)
+		write := func(m data.Map) {
+			So(lb.Write(ctx, core.NewTuple(m)), ShouldBeNil)
+		}
+
+		Convey("When writing tuples", func() {
+			write(data.Map{"id": data.Int(1), "status": data.String("a")})
+			write(data.Map{"id": data.Int(2), "status": data.String("b")})
+			write(data.Map{"id": data.Int(1), "status": data.String("c")})
+			write(data.Map{"status": data.String("d")})
+
+			Convey("Then the latest tuple of each key should be looked up", func() {
+				m, err := lb.Lookup(ctx, data.Float(1))
+				So(err, ShouldBeNil)
+				So(m, ShouldResemble, data.Map{"id": data.Int(1), "status": data.String("c")})
+				m, err = lb.Lookup(ctx, data.Int(2))
+				So(err, ShouldBeNil)
+				So(m["status"], ShouldEqual, data.String("b"))
+			})
+
+			Convey("Then a missing key should result in NotExistError", func() {
+				_, err := lb.Lookup(ctx, data.Int(3))
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+
+			Convey("Then the key updated least recently should be evicted by max_keys", func() {
+				write(data.Map{"id": data.Int(3)})
+				_, err := lb.Lookup(ctx, data.Int(2))
+				So(core.IsNotExist(err), ShouldBeTrue)
+				_, err = lb.Lookup(ctx, data.Int(1))
+				So(err, ShouldBeNil)
+				So(lb.Status(), ShouldResemble, data.Map{
+					"num_keys":    data.Int(2),
+					"num_updates": data.Int(4),
+					"num_evicted": data.Int(1),
+					"num_expired": data.Int(0),
+				})
+			})
+		})
+
+		Convey("When the state is terminated", func() {
+			So(lb.Terminate(ctx), ShouldBeNil)
+
+			Convey("Then it cannot be written", func() {
+				So(lb.Write(ctx, core.NewTuple(data.Map{"id": data.Int(1)})), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a latest_by state having ttl", t, func() {
+		s, err := createLatestBy(ctx, data.Map{
+			"key": data.String("id"),
+			"ttl": data.String("10ms"),
+		})
+		So(err, ShouldBeNil)
+		lb := s.(*latestBy)
+
+		Convey("When a tuple gets older than ttl", func() {
+			So(lb.Write(ctx, core.NewTuple(data.Map{"id": data.Int(1)})), ShouldBeNil)
+			time.Sleep(20 * time.Millisecond)
+
+			Convey("Then it shouldn't be looked up", func() {
+				_, err := lb.Lookup(ctx, data.Int(1))
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+
+			Convey("Then it should be removed by the next write", func() {
+				So(lb.Write(ctx, core.NewTuple(data.Map{"id": data.Int(2)})), ShouldBeNil)
+				So(lb.Status()["num_keys"], ShouldEqual, data.Int(1))
+				So(lb.Status()["num_expired"], ShouldEqual, data.Int(1))
+			})
+		})
+	})
+
+	Convey("Given invalid parameters", t, func() {
+		for _, p := range []data.Map{
+			{},
+			{"key": data.String("id[")},
+			{"key": data.String("id"), "max_keys": data.Int(-1)},
+			{"key": data.String("id"), "ttl": data.Int(-1)},
+		} {
+			Convey("Then creating the state should fail: "+p.String(), func() {
+				_, err := createLatestBy(ctx, p)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+
+	Convey("Given a topology builder with a source", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy`), ShouldBeNil)
+
+		Convey("When creating a latest_by state fed by the source", func() {
+			So(addBQLToTopology(tb, `CREATE STATE latest TYPE latest_by WITH stream="s", key="int";`), ShouldBeNil)
+			_, err := dt.Sink(latestByFeederName("latest"))
+			So(err, ShouldBeNil)
+
+			Convey("Then the state should be updated by the source", func() {
+				So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+				var m data.Value
+				for i := 0; i < 500; i++ {
+					if m, err = enrichFunc(dt.Context(), "latest", data.Int(4)); err == nil && m.Type() == data.TypeMap {
+						break
+					}
+					time.Sleep(time.Millisecond)
+				}
+				So(m, ShouldResemble, data.Map{"int": data.Int(4)})
+			})
+
+			Convey("Then dropping the state should remove the feeder", func() {
+				So(addBQLToTopology(tb, `DROP STATE latest;`), ShouldBeNil)
+				_, err := dt.Sink(latestByFeederName("latest"))
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When creating a latest_by state fed by a missing stream", func() {
+			err := addBQLToTopology(tb, `CREATE STATE latest TYPE latest_by WITH stream="x", key="int";`)
+
+			Convey("Then it should fail and the state shouldn't be created", func() {
+				So(err, ShouldNotBeNil)
+				_, err := dt.Context().SharedStates.Get("latest")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,307 @@
+package bql
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// lineageIDMetadataKey is the key of core.Tuple.Metadata having the
+	// lineage ID of a tuple emitted by a BQL statement. BQL statements can
+	// read it with meta("lineage_id").
+	lineageIDMetadataKey = "lineage_id"
+)
+
+// lineageRecord is a record of a tuple. A tuple emitted by a BQL statement
+// has the statement and IDs of input tuples the tuple was computed from.
+// A tuple coming from other nodes only has the name of the stream.
+type lineageRecord struct {
+	id        int64
+	node      string
+	stmt      string
+	inputs    []int64
+	data      data.Map
+	timestamp time.Time
+}
+
+func (r *lineageRecord) toMap() data.Map {
+	m := data.Map{
+		"id":        data.Int(r.id),
+		"node":      data.String(r.node),
+		"timestamp": data.Timestamp(r.timestamp),
+		"data":      r.data,
+	}
+	if r.stmt != "" {
+		m["statement"] = data.String(r.stmt)
+	}
+	return m
+}
+
+// lineageRecorder keeps records of the latest tuples processed by BQL
+// statements. The oldest record is evicted when the number of records exceeds
+// the limit.
+type lineageRecorder struct {
+	m          sync.Mutex
+	maxRecords int
+	records    map[int64]*lineageRecord
+	// order has IDs of records from the oldest one.
+	order *list.List
+}
+
+func newLineageRecorder(maxRecords int) *lineageRecorder {
+	return &lineageRecorder{
+		maxRecords: maxRecords,
+		records:    map[int64]*lineageRecord{},
+		order:      list.New(),
+	}
+}
+
+// setMaxRecords changes the limit of the number of records. Old records are
+// evicted when there're more records than the new limit.
+func (r *lineageRecorder) setMaxRecords(n int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.maxRecords = n
+	r.evict()
+}
+
+func (r *lineageRecorder) add(rec *lineageRecord) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.records[rec.id] = rec
+	r.order.PushBack(rec.id)
+	r.evict()
+}
+
+// evict removes the oldest records exceeding the limit. The caller must
+// hold the lock.
+func (r *lineageRecorder) evict() {
+	for r.order.Len() > r.maxRecords {
+		delete(r.records, r.order.Remove(r.order.Front()).(int64))
+	}
+}
+
+// lineage returns the record of the tuple having the ID. Records of input
+// tuples are nested in "inputs" up to depth levels. All levels are expanded
+// when depth is 0 or negative. An input tuple whose record has been evicted
+// only has "id" and "evicted" fields. It returns false when the record of the
+// tuple doesn't exist.
+func (r *lineageRecorder) lineage(id int64, depth int) (data.Map, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	rec, ok := r.records[id]
+	if !ok {
+		return nil, false
+	}
+	return r.expand(rec, depth), true
+}
+
+func (r *lineageRecorder) expand(rec *lineageRecord, depth int) data.Map {
+	m := rec.toMap()
+	if rec.stmt == "" {
+		return m
+	}
+	if depth == 1 {
+		ids := make(data.Array, len(rec.inputs))
+		for i, id := range rec.inputs {
+			ids[i] = data.Int(id)
+		}
+		m["input_ids"] = ids
+		return m
+	}
+	inputs := make(data.Array, len(rec.inputs))
+	for i, id := range rec.inputs {
+		// IDs of inputs are always smaller than the ID of the output, so
+		// the recursion terminates.
+		in, ok := r.records[id]
+		if !ok {
+			inputs[i] = data.Map{
+				"id":      data.Int(id),
+				"evicted": data.True,
+			}
+			continue
+		}
+		inputs[i] = r.expand(in, depth-1)
+	}
+	m["inputs"] = inputs
+	return m
+}
+
+// recent returns records of the latest n tuples emitted by the node from the
+// newest one. Input tuples aren't expanded and only their IDs are included.
+func (r *lineageRecorder) recent(node string, n int) []data.Map {
+	r.m.Lock()
+	defer r.m.Unlock()
+	res := []data.Map{}
+	for e := r.order.Back(); e != nil && len(res) < n; e = e.Prev() {
+		rec := r.records[e.Value.(int64)]
+		if rec.node != node || rec.stmt == "" {
+			continue
+		}
+		res = append(res, r.expand(rec, 1))
+	}
+	return res
+}
+
+// boxLineage records lineage of tuples processed by a bqlBox.
+type boxLineage struct {
+	recorder *lineageRecorder
+	node     string
+	stmt     string
+}
+
+// input assigns a lineage ID to the input tuple when it doesn't have one yet,
+// which means it comes from a node other than BQL statements. The tuple is
+// copied when it's shared.
+func (l *boxLineage) input(t *core.Tuple) *core.Tuple {
+	if t.LineageID != 0 {
+		return t
+	}
+	if t.Flags.IsSet(core.TFShared) {
+		t = t.ShallowCopy()
+	}
+	t.LineageID = core.NewTemporaryID()
+	l.recorder.add(&lineageRecord{
+		id:        t.LineageID,
+		node:      t.InputName,
+		data:      t.Data.Copy(),
+		timestamp: t.Timestamp,
+	})
+	return t
+}
+
+// inputIDs returns lineage IDs of the input tuples the last result of the
+// plan was computed from.
+func (l *boxLineage) inputIDs(plan execution.PhysicalPlan, t *core.Tuple) []int64 {
+	if p, ok := plan.(execution.LineagePlan); ok {
+		return p.InputLineageIDs()
+	}
+	return []int64{t.LineageID}
+}
+
+// output assigns a new lineage ID to the output tuple and records it. The ID
+// is also written to the metadata of the tuple.
+func (l *boxLineage) output(t *core.Tuple, inputs []int64) {
+	t.LineageID = core.NewTemporaryID()
+	l.recorder.add(&lineageRecord{
+		id:        t.LineageID,
+		node:      l.node,
+		stmt:      l.stmt,
+		inputs:    inputs,
+		data:      t.Data,
+		timestamp: t.Timestamp,
+	})
+
+	// metadata may be shared with the input tuple
+	m := make(data.Map, len(t.Metadata)+1)
+	for k, v := range t.Metadata {
+		m[k] = v
+	}
+	m[lineageIDMetadataKey] = data.Int(t.LineageID)
+	t.Metadata = m
+}
+
+// boxLineage returns a boxLineage of the node created by the statement. It
+// returns nil when "lineage_max_records" isn't set in the config store of the
+// topology or it's not positive. All statements in the topology share the
+// records and changes of "lineage_max_records" are applied when a statement
+// is created.
+func (tb *TopologyBuilder) boxLineage(node, stmt string) (*boxLineage, error) {
+	n, err := tb.topology.Context().Config.GetInt("lineage_max_records")
+	if err != nil {
+		if core.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lineage_max_records must be an integer: %v", err)
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	tb.lineageMutex.Lock()
+	defer tb.lineageMutex.Unlock()
+	if tb.lineage == nil {
+		tb.lineage = newLineageRecorder(int(n))
+	} else {
+		tb.lineage.setMaxRecords(int(n))
+	}
+	return &boxLineage{
+		recorder: tb.lineage,
+		node:     node,
+		stmt:     stmt,
+	}, nil
+}
+
+func (tb *TopologyBuilder) lineageRecorder() *lineageRecorder {
+	tb.lineageMutex.Lock()
+	defer tb.lineageMutex.Unlock()
+	return tb.lineage
+}
+
+// Lineage returns the lineage of the tuple having the lineage ID. The result
+// has the following fields:
+//
+//	* id: the lineage ID
+//	* node: the name of the stream which emitted the tuple
+//	* statement: the BQL statement which emitted the tuple. It's missing
+//	  when the tuple came from a node other than BQL statements.
+//	* timestamp: the timestamp of the tuple
+//	* data: the data of the tuple
+//	* inputs: the lineage of the input tuples the tuple was computed from,
+//	  which are all tuples in the windows of the statement at that time
+//
+// inputs are expanded up to depth levels and all levels are expanded when
+// depth isn't positive. When inputs aren't expanded, input_ids has their IDs
+// instead. It returns NotExistError when lineage isn't recorded or the record
+// has already been evicted.
+func (tb *TopologyBuilder) Lineage(id int64, depth int) (data.Map, error) {
+	if r := tb.lineageRecorder(); r != nil {
+		if m, ok := r.lineage(id, depth); ok {
+			return m, nil
+		}
+	}
+	return nil, core.NotExistError(fmt.Errorf("lineage of the tuple %v was not found", id))
+}
+
+// RecentLineage returns lineage records of the latest n tuples emitted by the
+// stream from the newest one. Input tuples of each record are given as
+// input_ids. See Lineage for the format of records.
+func (tb *TopologyBuilder) RecentLineage(node string, n int) []data.Map {
+	r := tb.lineageRecorder()
+	if r == nil {
+		return []data.Map{}
+	}
+	return r.recent(node, n)
+}
+
+// createLineageUDF creates lineage(id[, depth]) UDF returning the lineage of
+// the tuple having the ID as TopologyBuilder.Lineage does. The ID of a tuple
+// can be obtained by meta("lineage_id"). It returns NULL when the record isn't
+// found.
+func createLineageUDF(tb *TopologyBuilder) udf.UDF {
+	return udf.MustConvertGeneric(func(id int64, depth ...int) (data.Value, error) {
+		d := 0
+		switch len(depth) {
+		case 0:
+		case 1:
+			d = depth[0]
+		default:
+			return nil, fmt.Errorf("lineage takes at most two arguments")
+		}
+		m, err := tb.Lineage(id, d)
+		if err != nil {
+			if core.IsNotExist(err) {
+				return data.Null{}, nil
+			}
+			return nil, err
+		}
+		return m, nil
+	})
+}
@@ -0,0 +1,153 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestLineage(t *testing.T) {
+	Convey("Given a topology recording lineage", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `SET CONFIG WITH lineage_max_records=100;
+			CREATE PAUSED SOURCE s TYPE dummy;
+			CREATE STREAM f AS SELECT RSTREAM int * 10 AS x FROM s [RANGE 1 TUPLES];
+			CREATE STREAM a AS SELECT RSTREAM sum(x) AS total FROM f [RANGE 2 TUPLES];
+			CREATE SINK k TYPE collector;
+			INSERT INTO k FROM a;`), ShouldBeNil)
+		sn, err := dt.Sink("k")
+		So(err, ShouldBeNil)
+		si := sn.Sink().(*tupleCollectorSink)
+		So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+		si.Wait(4)
+		last := si.get(3)
+		So(last.Data, ShouldResemble, data.Map{"total": data.Int(70)})
+
+		Convey("Then output tuples should have lineage IDs in metadata", func() {
+			So(last.LineageID, ShouldNotEqual, 0)
+			So(last.Metadata[lineageIDMetadataKey], ShouldEqual, data.Int(last.LineageID))
+		})
+
+		Convey("Then the lineage of the aggregation should have all input tuples", func() {
+			l, err := tb.Lineage(last.LineageID, 0)
+			So(err, ShouldBeNil)
+			So(l["node"], ShouldEqual, data.String("a"))
+			So(l["statement"], ShouldEqual,
+				data.String("CREATE STREAM a AS SELECT RSTREAM sum(x) AS total FROM f [RANGE 2 TUPLES]"))
+
+			inputs, err := data.AsArray(l["inputs"])
+			So(err, ShouldBeNil)
+			So(len(inputs), ShouldEqual, 2)
+			for i, v := range inputs {
+				in, err := data.AsMap(v)
+				So(err, ShouldBeNil)
+				So(in["node"], ShouldEqual, data.String("f"))
+				So(in["data"], ShouldResemble, data.Map{"x": data.Int(30 + 10*i)})
+
+				src, err := in.Get(data.MustCompilePath("inputs[0]"))
+				So(err, ShouldBeNil)
+				srcMap, _ := data.AsMap(src)
+				So(srcMap["node"], ShouldEqual, data.String("s"))
+				So(srcMap["data"], ShouldResemble, data.Map{"int": data.Int(3 + i)})
+				So(srcMap, ShouldNotContainKey, "statement")
+			}
+		})
+
+		Convey("Then lineage should only expand the given depth", func() {
+			l, err := tb.Lineage(last.LineageID, 1)
+			So(err, ShouldBeNil)
+			So(l, ShouldNotContainKey, "inputs")
+			ids, err := data.AsArray(l["input_ids"])
+			So(err, ShouldBeNil)
+			So(len(ids), ShouldEqual, 2)
+		})
+
+		Convey("Then recent lineage of the stream should be returned", func() {
+			ls := tb.RecentLineage("a", 2)
+			So(len(ls), ShouldEqual, 2)
+			So(ls[0]["id"], ShouldEqual, data.Int(last.LineageID))
+			So(ls[1]["id"], ShouldEqual, data.Int(si.get(2).LineageID))
+		})
+
+		Convey("Then lineage function should return the lineage", func() {
+			f, err := tb.Reg.Lookup("lineage", 2)
+			So(err, ShouldBeNil)
+			v, err := f.Call(dt.Context(), data.Int(last.LineageID), data.Int(1))
+			So(err, ShouldBeNil)
+			m, err := data.AsMap(v)
+			So(err, ShouldBeNil)
+			So(m["node"], ShouldEqual, data.String("a"))
+
+			Convey("And it should return null for an unknown ID", func() {
+				v, err := f.Call(dt.Context(), data.Int(-1), data.Int(1))
+				So(err, ShouldBeNil)
+				So(v.Type(), ShouldEqual, data.TypeNull)
+			})
+		})
+
+		Convey("Then an unknown ID should not be found", func() {
+			_, err := tb.Lineage(-1, 0)
+			So(core.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a lineage recorder", t, func() {
+		r := newLineageRecorder(2)
+
+		Convey("When adding more records than the limit", func() {
+			for i := int64(1); i <= 3; i++ {
+				r.add(&lineageRecord{id: i, node: "s"})
+			}
+			r.add(&lineageRecord{id: 4, node: "t", stmt: "stmt", inputs: []int64{2, 3}})
+
+			Convey("Then old records should be evicted", func() {
+				_, ok := r.lineage(2, 0)
+				So(ok, ShouldBeFalse)
+				l, ok := r.lineage(4, 0)
+				So(ok, ShouldBeTrue)
+				inputs := l["inputs"].(data.Array)
+				So(inputs[0], ShouldResemble, data.Map{"id": data.Int(2), "evicted": data.True})
+				So(inputs[1].(data.Map)["id"], ShouldEqual, data.Int(3))
+			})
+
+			Convey("Then shrinking the limit should evict records", func() {
+				r.setMaxRecords(1)
+				_, ok := r.lineage(3, 0)
+				So(ok, ShouldBeFalse)
+				_, ok = r.lineage(4, 0)
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a topology not recording lineage", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy;
+			CREATE STREAM f AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES];
+			CREATE SINK k TYPE collector;
+			INSERT INTO k FROM f;
+			RESUME SOURCE s;`), ShouldBeNil)
+		sn, err := dt.Sink("k")
+		So(err, ShouldBeNil)
+		si := sn.Sink().(*tupleCollectorSink)
+		si.Wait(1)
+
+		Convey("Then tuples should not have lineage IDs", func() {
+			So(si.get(0).LineageID, ShouldEqual, 0)
+			So(si.get(0).Metadata, ShouldNotContainKey, lineageIDMetadataKey)
+			So(tb.RecentLineage("f", 10), ShouldBeEmpty)
+		})
+	})
+}
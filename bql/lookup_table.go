@@ -0,0 +1,342 @@
+package bql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// lookupTable is a UDS having reference data such as a device registry. Rows
+// are loaded from a file or a SQL database and looked up by a key field. It
+// implements core.KeyedState so that a stream can be enriched with it by a
+// JOIN STATE clause or the enrich function:
+//
+//	CREATE STATE devices TYPE lookup_table
+//	  WITH path="devices.csv", format="csv", key="id", refresh_interval="5m";
+//	SELECT RSTREAM s:id, d:name FROM s [RANGE 1 TUPLES]
+//	  JOIN STATE devices AS d ON s:id = d:key;
+//	SELECT RSTREAM *, enrich("devices", id) AS device FROM s [RANGE 1 TUPLES];
+//
+// It has following parameters:
+//
+//	* key: a JSON Path of the key in each row (required)
+//	* path: the path of a file having rows
+//	* format: the format of the file such as "jsonl" or "csv". Parameters of
+//	  the format like "column_types" can also be given. The default is "jsonl".
+//	* compression: the compression of the file
+//	* driver: the name of the database/sql driver. The driver has to be
+//	  registered by a plugin.
+//	* data_source: the driver specific data source name
+//	* query: a SELECT statement returning rows
+//	* refresh_interval: the interval of reloading rows. Rows aren't reloaded
+//	  when it's not given.
+//
+// Either path or query must be given. When reloading fails, the table keeps
+// rows previously loaded. A row whose key is the same as a previous row's
+// overwrites it.
+type lookupTable struct {
+	key  data.Path
+	load func() ([]data.Map, error)
+
+	m        sync.RWMutex
+	rows     map[data.HashValue][]*lookupTableRow
+	numRows  int
+	loadedAt time.Time
+	lastErr  error
+	closed   bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+type lookupTableRow struct {
+	key data.Value
+	row data.Map
+}
+
+var (
+	_ core.KeyedState = &lookupTable{}
+	_ core.Statuser   = &lookupTable{}
+)
+
+func createLookupTable(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Key             string `bql:",required"`
+		Path            string
+		Format          string
+		Compression     string
+		Driver          string
+		DataSource      string
+		Query           string
+		RefreshInterval time.Duration
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	key, err := data.CompilePath(c.Key)
+	if err != nil {
+		return nil, fmt.Errorf("'key' parameter doesn't have a valid path: %v", err)
+	}
+	if c.RefreshInterval < 0 {
+		return nil, errors.New("'refresh_interval' parameter must not be negative")
+	}
+
+	t := &lookupTable{
+		key:    key,
+		stopCh: make(chan struct{}),
+	}
+	switch {
+	case c.Path != "" && c.Query != "":
+		return nil, errors.New("only one of 'path' and 'query' parameters can be given")
+
+	case c.Path != "":
+		newDecoder, err := newRecordDecoderCreator(c.Format, params)
+		if err != nil {
+			return nil, err
+		}
+		if err := ValidateCompression(c.Compression, true); err != nil {
+			return nil, err
+		}
+		t.load = func() ([]data.Map, error) {
+			return loadLookupTableFile(c.Path, c.Compression, newDecoder)
+		}
+
+	case c.Query != "":
+		if c.Driver == "" {
+			return nil, errors.New("'driver' parameter is required with 'query' parameter")
+		}
+		db, err := sql.Open(c.Driver, c.DataSource)
+		if err != nil {
+			return nil, err
+		}
+		t.load = func() ([]data.Map, error) {
+			return loadLookupTableSQL(db, c.Query)
+		}
+		go func() { // close the database after the table is terminated
+			<-t.stopCh
+			db.Close()
+		}()
+
+	default:
+		return nil, errors.New("either 'path' or 'query' parameter is required")
+	}
+
+	if err := t.refresh(); err != nil {
+		close(t.stopCh)
+		return nil, err
+	}
+	if c.RefreshInterval > 0 {
+		t.wg.Add(1)
+		go t.refreshPeriodically(ctx, c.RefreshInterval)
+	}
+	return t, nil
+}
+
+func loadLookupTableFile(path, compression string, newDecoder recordDecoderCreator) ([]data.Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, err := NewDecompressingReader(compression, f)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var rows []data.Map
+	dec := newDecoder(r)
+	for {
+		m, err := dec.decode()
+		if err != nil {
+			if err == io.EOF {
+				return rows, nil
+			}
+			return nil, err // a malformed row fails the whole load
+		}
+		rows = append(rows, m)
+	}
+}
+
+func loadLookupTableSQL(db *sql.DB, query string) ([]data.Map, error) {
+	rs, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+	cols, err := rs.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []data.Map
+	vs := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range vs {
+		ptrs[i] = &vs[i]
+	}
+	for rs.Next() {
+		if err := rs.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		m := make(data.Map, len(cols))
+		for i, c := range cols {
+			switch v := vs[i].(type) {
+			case []byte:
+				m[c] = data.String(v) // most drivers return text as []byte
+			case nil:
+				m[c] = data.Null{}
+			default:
+				dv, err := data.NewValue(v)
+				if err != nil {
+					return nil, fmt.Errorf("column %v has an unsupported value: %v", c, err)
+				}
+				m[c] = dv
+			}
+		}
+		rows = append(rows, m)
+	}
+	return rows, rs.Err()
+}
+
+// refresh reloads rows. The previous rows are kept when it fails.
+func (t *lookupTable) refresh() error {
+	rows, err := t.load()
+	if err != nil {
+		t.m.Lock()
+		t.lastErr = err
+		t.m.Unlock()
+		return err
+	}
+
+	index := make(map[data.HashValue][]*lookupTableRow, len(rows))
+	num := 0
+	for _, r := range rows {
+		k, err := r.Get(t.key)
+		if err != nil || k.Type() == data.TypeNull {
+			continue // rows without keys can never be looked up
+		}
+		h := data.Hash(k)
+		replaced := false
+		for _, e := range index[h] {
+			if data.Equal(e.key, k) {
+				e.row = r
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			index[h] = append(index[h], &lookupTableRow{key: k, row: r})
+			num++
+		}
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.rows = index
+	t.numRows = num
+	t.loadedAt = time.Now()
+	t.lastErr = nil
+	return nil
+}
+
+func (t *lookupTable) refreshPeriodically(ctx *core.Context, interval time.Duration) {
+	defer t.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if err := t.refresh(); err != nil {
+			ctx.ErrLog(err).Error("Cannot refresh the lookup table")
+		}
+	}
+}
+
+// Lookup returns the row having the key.
+func (t *lookupTable) Lookup(ctx *core.Context, key data.Value) (data.Map, error) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	if t.closed {
+		return nil, errors.New("the lookup table is already terminated")
+	}
+	for _, e := range t.rows[data.Hash(key)] {
+		if data.Equal(e.key, key) {
+			return e.row, nil
+		}
+	}
+	return nil, core.NotExistError(fmt.Errorf("the key %v doesn't exist", key))
+}
+
+// Status returns the number of rows and the result of the last refresh.
+func (t *lookupTable) Status() data.Map {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	st := data.Map{
+		"num_rows":  data.Int(t.numRows),
+		"loaded_at": data.Timestamp(t.loadedAt),
+	}
+	if t.lastErr != nil {
+		st["last_error"] = data.String(t.lastErr.Error())
+	}
+	return st
+}
+
+func (t *lookupTable) Terminate(ctx *core.Context) error {
+	t.m.Lock()
+	if t.closed {
+		t.m.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.rows = nil
+	t.m.Unlock()
+
+	close(t.stopCh)
+	t.wg.Wait()
+	return nil
+}
+
+// enrichFunc returns the data associated with the key in a keyed state such as
+// lookup_table. It returns NULL when the state doesn't have the key.
+//
+// It can be used in BQL as `enrich`:
+//
+//	enrich(state, key)
+func enrichFunc(ctx *core.Context, state string, key data.Value) (data.Value, error) {
+	if key.Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	s, err := ctx.SharedStates.Get(state)
+	if err != nil {
+		return nil, err
+	}
+	keyed, ok := s.(core.KeyedState)
+	if !ok {
+		return nil, fmt.Errorf("state '%v' doesn't support key lookups", state)
+	}
+	m, err := keyed.Lookup(ctx, key)
+	if err != nil {
+		if core.IsNotExist(err) {
+			return data.Null{}, nil
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func init() {
+	udf.MustRegisterGlobalUDSCreator("lookup_table", udf.UDSCreatorFunc(createLookupTable))
+	udf.MustRegisterGlobalUDF("enrich", udf.MustConvertGeneric(enrichFunc))
+}
@@ -0,0 +1,200 @@
+package bql
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestLookupTable(t *testing.T) {
+	Convey("Given a csv file having reference data", t, func() {
+		dir, err := ioutil.TempDir("", "sbtest_bql_lookup_table")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "devices.csv")
+		So(ioutil.WriteFile(path, []byte("id,name\n1,a\n2,b\n2,c\n,d\n"), 0644), ShouldBeNil)
+		ctx := core.NewContext(nil)
+		params := data.Map{
+			"path":   data.String(path),
+			"format": data.String("csv"),
+			"key":    data.String("id"),
+		}
+
+		Convey("When creating a lookup table", func() {
+			s, err := createLookupTable(ctx, params)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				s.Terminate(ctx)
+			})
+			lt := s.(*lookupTable)
+
+			Convey("Then rows should be looked up by the key", func() {
+				m, err := lt.Lookup(ctx, data.Int(1))
+				So(err, ShouldBeNil)
+				So(m, ShouldResemble, data.Map{"id": data.Int(1), "name": data.String("a")})
+				m, err = lt.Lookup(ctx, data.Float(2))
+				So(err, ShouldBeNil)
+				So(m["name"], ShouldEqual, data.String("c"))
+			})
+
+			Convey("Then a missing key should result in NotExistError", func() {
+				_, err := lt.Lookup(ctx, data.Int(3))
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+
+			Convey("Then rows without keys should be ignored", func() {
+				So(lt.Status()["num_rows"], ShouldEqual, data.Int(2))
+			})
+		})
+
+		Convey("When creating a lookup table with refresh_interval", func() {
+			params["refresh_interval"] = data.String("10ms")
+			s, err := createLookupTable(ctx, params)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				s.Terminate(ctx)
+			})
+			lt := s.(*lookupTable)
+
+			Convey("Then it should reload the updated file", func() {
+				So(ioutil.WriteFile(path, []byte("id,name\n3,e\n"), 0644), ShouldBeNil)
+				var err error
+				for i := 0; i < 500; i++ {
+					if _, err = lt.Lookup(ctx, data.Int(3)); err == nil {
+						break
+					}
+					time.Sleep(time.Millisecond)
+				}
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then it should keep the previous rows when reloading fails", func() {
+				So(os.Remove(path), ShouldBeNil)
+				for i := 0; i < 500 && lt.Status()["last_error"] == nil; i++ {
+					time.Sleep(time.Millisecond)
+				}
+				So(lt.Status()["last_error"], ShouldNotBeNil)
+				_, err := lt.Lookup(ctx, data.Int(1))
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When creating a lookup table with invalid parameters", func() {
+			Convey("Then a missing key parameter should result in an error", func() {
+				delete(params, "key")
+				_, err := createLookupTable(ctx, params)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then a missing file should result in an error", func() {
+				params["path"] = data.String(filepath.Join(dir, "no_such_file"))
+				_, err := createLookupTable(ctx, params)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then missing path and query parameters should result in an error", func() {
+				delete(params, "path")
+				_, err := createLookupTable(ctx, params)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a SQL database having reference data", t, func() {
+		dir, err := ioutil.TempDir("", "sbtest_bql_lookup_table")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		dsn := filepath.Join(dir, "devices.db")
+		db, err := sql.Open("sqlite3", dsn)
+		So(err, ShouldBeNil)
+		_, err = db.Exec(`CREATE TABLE devices (id INTEGER, name TEXT, lat REAL);
+			INSERT INTO devices VALUES (1, 'a', 35.5), (2, 'b', NULL);`)
+		So(err, ShouldBeNil)
+		db.Close()
+
+		Convey("When creating a lookup table from a query", func() {
+			ctx := core.NewContext(nil)
+			s, err := createLookupTable(ctx, data.Map{
+				"driver":      data.String("sqlite3"),
+				"data_source": data.String(dsn),
+				"query":       data.String("SELECT id, name, lat FROM devices"),
+				"key":         data.String("id"),
+			})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				s.Terminate(ctx)
+			})
+
+			Convey("Then rows should be looked up by the key", func() {
+				m, err := s.(*lookupTable).Lookup(ctx, data.Int(2))
+				So(err, ShouldBeNil)
+				So(m, ShouldResemble, data.Map{"id": data.Int(2), "name": data.String("b"), "lat": data.Null{}})
+			})
+		})
+	})
+
+	Convey("Given a topology with a lookup table", t, func() {
+		dir, err := ioutil.TempDir("", "sbtest_bql_lookup_table")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "devices.jsonl")
+		So(ioutil.WriteFile(path, []byte(`{"id":1,"name":"a"}`+"\n"+`{"id":3,"name":"c"}`+"\n"), 0644), ShouldBeNil)
+
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;
+			CREATE STATE devices TYPE lookup_table WITH path="`+path+`", key="id";`), ShouldBeNil)
+
+		Convey("When enriching a stream with JOIN STATE", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM e AS SELECT RSTREAM s:int, d:name
+				FROM s [RANGE 1 TUPLES] JOIN STATE devices AS d ON s:int = d:key;
+				CREATE SINK k TYPE collector;
+				INSERT INTO k FROM e;
+				RESUME SOURCE s;`), ShouldBeNil)
+			sn, err := dt.Sink("k")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			si.Wait(2)
+
+			Convey("Then only tuples having keys should be enriched", func() {
+				So(si.get(0).Data, ShouldResemble, data.Map{"int": data.Int(1), "name": data.String("a")})
+				So(si.get(1).Data, ShouldResemble, data.Map{"int": data.Int(3), "name": data.String("c")})
+			})
+		})
+
+		Convey("When enriching a stream with the enrich function", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM e AS SELECT RSTREAM int, enrich("devices", int) AS device
+				FROM s [RANGE 1 TUPLES];
+				CREATE SINK k TYPE collector;
+				INSERT INTO k FROM e;
+				RESUME SOURCE s;`), ShouldBeNil)
+			sn, err := dt.Sink("k")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			si.Wait(4)
+
+			Convey("Then missing keys should result in null", func() {
+				So(si.get(0).Data["device"], ShouldResemble, data.Map{"id": data.Int(1), "name": data.String("a")})
+				So(si.get(1).Data["device"], ShouldResemble, data.Null{})
+			})
+		})
+	})
+}
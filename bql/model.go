@@ -0,0 +1,473 @@
+package bql
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// maxModelSize is the maximum size of a serialized model.
+const maxModelSize = 64 * 1024 * 1024
+
+// modelState is a UDS having a machine learning model which scores tuples
+// with the predict function:
+//
+//	CREATE STATE churn TYPE model
+//	  WITH path="/models/churn.json", reload_interval="1m";
+//	SELECT RSTREAM *, predict("churn", {"age": age, "visits": visits}) AS score
+//	  FROM users [RANGE 1 TUPLES];
+//
+// It has following parameters:
+//
+//	* path: the path of a file or an http(s) URL having the model (required)
+//	* reload_interval: the interval of checking whether the model has been
+//	  changed. The model isn't reloaded when it's not given.
+//
+// The model is reloaded only when the content of the file has been changed.
+// When reloading fails, the state keeps the model previously loaded. The
+// path can also be changed by UPDATE STATE, which loads the model
+// immediately.
+//
+// The model is a JSON object having "type" and parameters of the type:
+//
+//	* "linear": {"type": "linear", "weights": {"x": 0.5}, "bias": 1}.
+//	  It predicts bias + sum(weights[f] * features[f]).
+//	* "logistic": the same as linear except that it predicts the sigmoid of
+//	  the value, which is the probability of the positive class.
+//	* "tree": {"type": "tree", "root": node}. A node is either a leaf,
+//	  {"value": v}, or a split, {"feature": "x", "threshold": 1.5,
+//	  "left": node, "right": node, "missing": "left"}. A feature less than or
+//	  equal to threshold goes to left. "missing" is the branch taken when the
+//	  feature doesn't exist, and such a feature fails the prediction when
+//	  it's omitted. It predicts the value of the leaf, which can be any value.
+//
+// Missing features are regarded as 0 by linear and logistic models. Features
+// have to be numeric.
+type modelState struct {
+	client *http.Client
+
+	m        sync.RWMutex
+	path     string
+	model    model
+	digest   [sha256.Size]byte
+	loadedAt time.Time
+	numLoads int64
+	lastErr  error
+	closed   bool
+
+	// reloadMutex serializes reloads.
+	reloadMutex sync.Mutex
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// model predicts a value from features.
+type model interface {
+	predict(features data.Map) (data.Value, error)
+	typeName() string
+}
+
+var (
+	_ core.SharedState = &modelState{}
+	_ core.Updater     = &modelState{}
+	_ core.Statuser    = &modelState{}
+)
+
+func createModelState(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Path           string `bql:",required"`
+		ReloadInterval time.Duration
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if c.ReloadInterval < 0 {
+		return nil, errors.New("'reload_interval' parameter must not be negative")
+	}
+
+	s := &modelState{
+		client: &http.Client{Timeout: 30 * time.Second},
+		path:   c.Path,
+		stopCh: make(chan struct{}),
+	}
+	if err := s.reload(true); err != nil {
+		return nil, err
+	}
+	if c.ReloadInterval > 0 {
+		s.wg.Add(1)
+		go s.reloadPeriodically(ctx, c.ReloadInterval)
+	}
+	return s, nil
+}
+
+// reload loads the model from the path. The model isn't replaced when the
+// content hasn't been changed unless force is true. The previous model is
+// kept when it fails.
+func (s *modelState) reload(force bool) error {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	s.m.RLock()
+	path := s.path
+	s.m.RUnlock()
+
+	b, err := s.read(path)
+	var (
+		m      model
+		digest [sha256.Size]byte
+	)
+	if err == nil {
+		digest = sha256.Sum256(b)
+		s.m.RLock()
+		unchanged := !force && s.model != nil && digest == s.digest
+		s.m.RUnlock()
+		if unchanged {
+			return nil
+		}
+		m, err = parseModel(b)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	if err != nil {
+		s.lastErr = err
+		return err
+	}
+	s.model = m
+	s.digest = digest
+	s.loadedAt = time.Now()
+	s.numLoads++
+	s.lastErr = nil
+	return nil
+}
+
+// read reads the serialized model from a file or a URL.
+func (s *modelState) read(path string) ([]byte, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		res, err := s.client.Get(path)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return nil, fmt.Errorf("cannot fetch the model: %v", res.Status)
+		}
+		r = res.Body
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		r = f
+	}
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(r, maxModelSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxModelSize {
+		return nil, fmt.Errorf("the model is larger than %v bytes", maxModelSize)
+	}
+	return b, nil
+}
+
+func (s *modelState) reloadPeriodically(ctx *core.Context, interval time.Duration) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if err := s.reload(false); err != nil {
+			ctx.ErrLog(err).Error("Cannot reload the model")
+		}
+	}
+}
+
+// Update changes the path of the model and loads it. The path isn't changed
+// when the new model cannot be loaded.
+func (s *modelState) Update(ctx *core.Context, params data.Map) error {
+	for k := range params {
+		if k != "path" {
+			return fmt.Errorf("unknown parameter: %v", k)
+		}
+	}
+	v, ok := params["path"]
+	if !ok {
+		return s.reload(true)
+	}
+	path, err := data.AsString(v)
+	if err != nil {
+		return fmt.Errorf("'path' parameter must be a string: %v", err)
+	}
+
+	s.m.Lock()
+	prev := s.path
+	s.path = path
+	s.m.Unlock()
+	if err := s.reload(true); err != nil {
+		s.m.Lock()
+		s.path = prev
+		s.m.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Predict predicts a value from the features with the current model.
+func (s *modelState) Predict(features data.Map) (data.Value, error) {
+	s.m.RLock()
+	m, closed := s.model, s.closed
+	s.m.RUnlock()
+	if closed {
+		return nil, errors.New("the model is already terminated")
+	}
+	return m.predict(features)
+}
+
+// Status returns the type of the model and the result of the last reload.
+func (s *modelState) Status() data.Map {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	st := data.Map{
+		"path":      data.String(s.path),
+		"loaded_at": data.Timestamp(s.loadedAt),
+		"num_loads": data.Int(s.numLoads),
+	}
+	if s.model != nil {
+		st["model_type"] = data.String(s.model.typeName())
+	}
+	if s.lastErr != nil {
+		st["last_error"] = data.String(s.lastErr.Error())
+	}
+	return st
+}
+
+func (s *modelState) Terminate(ctx *core.Context) error {
+	s.m.Lock()
+	if s.closed {
+		s.m.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.m.Unlock()
+
+	close(s.stopCh)
+	s.wg.Wait()
+	return nil
+}
+
+// parseModel parses a model serialized in JSON.
+func parseModel(b []byte) (model, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return nil, fmt.Errorf("the model isn't a valid JSON object: %v", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	switch header.Type {
+	case "linear", "logistic":
+		m := &linearModel{logistic: header.Type == "logistic"}
+		if err := json.Unmarshal(b, m); err != nil {
+			return nil, fmt.Errorf("invalid %v model: %v", header.Type, err)
+		}
+		if len(m.Weights) == 0 {
+			return nil, fmt.Errorf("the %v model doesn't have weights", header.Type)
+		}
+		return m, nil
+	case "tree":
+		var js map[string]interface{}
+		if err := dec.Decode(&js); err != nil {
+			return nil, fmt.Errorf("invalid tree model: %v", err)
+		}
+		root, ok := js["root"]
+		if !ok {
+			return nil, errors.New("the tree model doesn't have the root")
+		}
+		n, err := parseTreeNode(root, "root")
+		if err != nil {
+			return nil, fmt.Errorf("invalid tree model: %v", err)
+		}
+		return &treeModel{root: n}, nil
+	case "":
+		return nil, errors.New("the model doesn't have the type")
+	default:
+		return nil, fmt.Errorf("unsupported model type: %v", header.Type)
+	}
+}
+
+// linearModel is a linear or logistic regression model.
+type linearModel struct {
+	Weights  map[string]float64 `json:"weights"`
+	Bias     float64            `json:"bias"`
+	logistic bool
+}
+
+func (m *linearModel) predict(features data.Map) (data.Value, error) {
+	y := m.Bias
+	for f, w := range m.Weights {
+		v, ok := features[f]
+		if !ok || v.Type() == data.TypeNull {
+			continue
+		}
+		x, err := modelFeature(f, v)
+		if err != nil {
+			return nil, err
+		}
+		y += w * x
+	}
+	if m.logistic {
+		y = 1 / (1 + math.Exp(-y))
+	}
+	return data.Float(y), nil
+}
+
+func (m *linearModel) typeName() string {
+	if m.logistic {
+		return "logistic"
+	}
+	return "linear"
+}
+
+// treeModel is a decision tree.
+type treeModel struct {
+	root *treeNode
+}
+
+type treeNode struct {
+	// value is the prediction of a leaf. It's nil when the node is a split.
+	value data.Value
+
+	feature     string
+	threshold   float64
+	left, right *treeNode
+
+	// missing is the branch taken when the feature doesn't exist. It's nil
+	// when missing features aren't allowed.
+	missing *treeNode
+}
+
+func parseTreeNode(v interface{}, path string) (*treeNode, error) {
+	js, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v must be an object", path)
+	}
+	if leaf, ok := js["value"]; ok {
+		dv, err := data.NewValue(leaf)
+		if err != nil {
+			return nil, fmt.Errorf("%v has an invalid value: %v", path, err)
+		}
+		return &treeNode{value: dv}, nil
+	}
+
+	n := &treeNode{}
+	if n.feature, ok = js["feature"].(string); !ok || n.feature == "" {
+		return nil, fmt.Errorf("%v must have a feature or a value", path)
+	}
+	t, ok := js["threshold"].(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%v must have a numeric threshold", path)
+	}
+	th, err := t.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("%v has an invalid threshold: %v", path, err)
+	}
+	n.threshold = th
+	if n.left, err = parseTreeNode(js["left"], path+".left"); err != nil {
+		return nil, err
+	}
+	if n.right, err = parseTreeNode(js["right"], path+".right"); err != nil {
+		return nil, err
+	}
+	switch js["missing"] {
+	case nil:
+	case "left":
+		n.missing = n.left
+	case "right":
+		n.missing = n.right
+	default:
+		return nil, fmt.Errorf(`missing of %v must be "left" or "right"`, path)
+	}
+	return n, nil
+}
+
+func (m *treeModel) predict(features data.Map) (data.Value, error) {
+	n := m.root
+	for n.value == nil {
+		v, ok := features[n.feature]
+		if !ok || v.Type() == data.TypeNull {
+			if n.missing == nil {
+				return nil, fmt.Errorf("feature '%v' is missing", n.feature)
+			}
+			n = n.missing
+			continue
+		}
+		x, err := modelFeature(n.feature, v)
+		if err != nil {
+			return nil, err
+		}
+		if x <= n.threshold {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n.value, nil
+}
+
+func (m *treeModel) typeName() string {
+	return "tree"
+}
+
+func modelFeature(name string, v data.Value) (float64, error) {
+	switch v.Type() {
+	case data.TypeInt, data.TypeFloat, data.TypeBool:
+		return data.ToFloat(v)
+	}
+	return 0, fmt.Errorf("feature '%v' must be numeric: %v", name, v)
+}
+
+// predictFunc predicts a value from features with a model state.
+//
+// It can be used in BQL as `predict`:
+//
+//	predict(state, features)
+func predictFunc(ctx *core.Context, state string, features data.Map) (data.Value, error) {
+	s, err := ctx.SharedStates.Get(state)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := s.(*modelState)
+	if !ok {
+		return nil, fmt.Errorf("state '%v' isn't a model", state)
+	}
+	return m.Predict(features)
+}
+
+func init() {
+	udf.MustRegisterGlobalUDSCreator("model", udf.UDSCreatorFunc(createModelState))
+	udf.MustRegisterGlobalUDF("predict", udf.MustConvertGeneric(predictFunc))
+}
@@ -0,0 +1,156 @@
+package bql
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestModelState(t *testing.T) {
+	Convey("Given a file having a linear model", t, func() {
+		dir, err := ioutil.TempDir("", "sbtest_bql_model")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "model.json")
+		So(ioutil.WriteFile(path, []byte(`{"type":"linear","weights":{"x":2,"y":-1},"bias":0.5}`), 0644), ShouldBeNil)
+		ctx := core.NewContext(nil)
+
+		Convey("When creating a model state", func() {
+			s, err := createModelState(ctx, data.Map{"path": data.String(path)})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				s.Terminate(ctx)
+			})
+			m := s.(*modelState)
+			So(ctx.SharedStates.Add("m", "model", s), ShouldBeNil)
+
+			Convey("Then predict should score features", func() {
+				v, err := predictFunc(ctx, "m", data.Map{"x": data.Int(3), "y": data.Float(1.5)})
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Float(5))
+			})
+
+			Convey("Then missing features should be regarded as 0", func() {
+				v, err := m.Predict(data.Map{"x": data.Int(1)})
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Float(2.5))
+			})
+
+			Convey("Then non-numeric features should fail", func() {
+				_, err := m.Predict(data.Map{"x": data.String("a")})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then updating it with another model should replace the model", func() {
+				p2 := filepath.Join(dir, "model2.json")
+				So(ioutil.WriteFile(p2, []byte(`{"type":"logistic","weights":{"x":1}}`), 0644), ShouldBeNil)
+				So(m.Update(ctx, data.Map{"path": data.String(p2)}), ShouldBeNil)
+				v, err := m.Predict(data.Map{"x": data.Int(0)})
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Float(0.5))
+				So(m.Status()["model_type"], ShouldEqual, data.String("logistic"))
+			})
+
+			Convey("Then updating it with an invalid model should keep the model", func() {
+				p2 := filepath.Join(dir, "model2.json")
+				So(ioutil.WriteFile(p2, []byte(`{"type":"unknown"}`), 0644), ShouldBeNil)
+				So(m.Update(ctx, data.Map{"path": data.String(p2)}), ShouldNotBeNil)
+				So(m.Status()["path"], ShouldEqual, data.String(path))
+				So(m.Status()["model_type"], ShouldEqual, data.String("linear"))
+			})
+		})
+
+		Convey("When creating a model state with reload_interval", func() {
+			s, err := createModelState(ctx, data.Map{
+				"path":            data.String(path),
+				"reload_interval": data.String("10ms"),
+			})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				s.Terminate(ctx)
+			})
+			m := s.(*modelState)
+
+			Convey("Then it should reload the updated file", func() {
+				So(ioutil.WriteFile(path, []byte(`{"type":"linear","weights":{"x":1}}`), 0644), ShouldBeNil)
+				var v data.Value
+				for i := 0; i < 500; i++ {
+					if v, err = m.Predict(data.Map{"x": data.Int(1)}); err == nil && v == data.Float(1) {
+						break
+					}
+					time.Sleep(time.Millisecond)
+				}
+				So(v, ShouldEqual, data.Float(1))
+				So(m.Status()["num_loads"], ShouldEqual, data.Int(2))
+			})
+		})
+	})
+
+	Convey("Given an HTTP server serving a decision tree", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"type":"tree","root":{"feature":"x","threshold":1.5,"missing":"right",
+				"left":{"value":"low"},
+				"right":{"feature":"y","threshold":0,"left":{"value":1},"right":{"value":2.5}}}}`))
+		}))
+		Reset(ts.Close)
+		ctx := core.NewContext(nil)
+
+		Convey("When creating a model state from the URL", func() {
+			s, err := createModelState(ctx, data.Map{"path": data.String(ts.URL)})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				s.Terminate(ctx)
+			})
+			m := s.(*modelState)
+
+			Convey("Then it should predict values of leaves", func() {
+				for _, c := range []struct {
+					features data.Map
+					expected data.Value
+				}{
+					{data.Map{"x": data.Int(1), "y": data.Int(1)}, data.String("low")},
+					{data.Map{"x": data.Int(2), "y": data.Int(0)}, data.Int(1)},
+					{data.Map{"x": data.Int(2), "y": data.Int(1)}, data.Float(2.5)},
+					{data.Map{"y": data.Int(-1)}, data.Int(1)},
+				} {
+					v, err := m.Predict(c.features)
+					So(err, ShouldBeNil)
+					So(v, ShouldResemble, c.expected)
+				}
+			})
+
+			Convey("Then a missing feature without a default branch should fail", func() {
+				_, err := m.Predict(data.Map{"x": data.Int(2)})
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given invalid models", t, func() {
+		for _, js := range []string{
+			`[]`,
+			`{}`,
+			`{"type":"svm"}`,
+			`{"type":"linear"}`,
+			`{"type":"tree"}`,
+			`{"type":"tree","root":{"feature":"x"}}`,
+			`{"type":"tree","root":{"feature":"x","threshold":1,"left":{"value":1}}}`,
+			`{"type":"tree","root":{"feature":"x","threshold":1,"left":{"value":1},"right":{"value":2},"missing":"up"}}`,
+		} {
+			Convey("Then parsing it should fail: "+js, func() {
+				_, err := parseModel([]byte(js))
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
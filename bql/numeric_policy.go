@@ -0,0 +1,89 @@
+package bql
+
+import (
+	"fmt"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Keys controlling how arithmetic operators in BQL statements handle
+// results which cannot be represented as usual numbers:
+//
+//   - numeric_overflow: integer overflow
+//   - numeric_division_by_zero: division or modulo by zero
+//   - numeric_non_finite: NaN or infinity resulting from float operations
+//
+// Each of them is one of "unchecked", "error", "null", or "saturate" (see
+// execution.NumericErrorMode). They can be set in the config store of a
+// topology, and the WITH clause of CREATE STREAM overrides them for the
+// statement:
+//
+//	CREATE STREAM s AS SELECT RSTREAM a / b AS r FROM t [RANGE 1 TUPLES]
+//	  WITH numeric_division_by_zero = "null";
+//
+// They're "unchecked" by default.
+const (
+	numericOverflowConfig       = "numeric_overflow"
+	numericDivisionByZeroConfig = "numeric_division_by_zero"
+	numericNonFiniteConfig      = "numeric_non_finite"
+)
+
+func isNumericPolicyKey(k string) bool {
+	switch k {
+	case numericOverflowConfig, numericDivisionByZeroConfig, numericNonFiniteConfig:
+		return true
+	}
+	return false
+}
+
+// numericPolicy returns the numeric policy of a statement having the
+// parameters given by the WITH clause. It also returns the parameters
+// other than the ones of the policy.
+func (tb *TopologyBuilder) numericPolicy(params []parser.SourceSinkParamAST) (execution.NumericPolicy, []parser.SourceSinkParamAST, error) {
+	var p execution.NumericPolicy
+	var policyParams, rest []parser.SourceSinkParamAST
+	for _, param := range params {
+		if isNumericPolicyKey(string(param.Key)) {
+			policyParams = append(policyParams, param)
+		} else {
+			rest = append(rest, param)
+		}
+	}
+	m, err := tb.mkParamsMap(policyParams)
+	if err != nil {
+		return p, nil, err
+	}
+
+	conf := tb.topology.Context().Config
+	for _, c := range []struct {
+		key  string
+		mode *execution.NumericErrorMode
+	}{
+		{numericOverflowConfig, &p.Overflow},
+		{numericDivisionByZeroConfig, &p.DivisionByZero},
+		{numericNonFiniteConfig, &p.NonFinite},
+	} {
+		v, ok := m[c.key]
+		if !ok {
+			if v, err = conf.Get(c.key); err != nil {
+				if core.IsNotExist(err) {
+					continue
+				}
+				return p, nil, err
+			}
+		}
+		s, err := data.AsString(v)
+		if err != nil {
+			return p, nil, fmt.Errorf("%v must be a string: %v", c.key, v)
+		}
+		mode, err := execution.ParseNumericErrorMode(s)
+		if err != nil {
+			return p, nil, fmt.Errorf("invalid %v: %v", c.key, err)
+		}
+		*c.mode = mode
+	}
+	return p, rest, nil
+}
@@ -0,0 +1,114 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestNumericPolicy(t *testing.T) {
+	Convey("Given a topology builder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE source TYPE dummy WITH num=4`), ShouldBeNil)
+
+		run := func(stmt string, n int) []data.Map {
+			So(addBQLToTopology(tb, stmt), ShouldBeNil)
+			So(addBQLToTopology(tb, `
+				CREATE SINK snk TYPE collector;
+				INSERT INTO snk FROM box;
+				RESUME SOURCE source;`), ShouldBeNil)
+			sin, err := dt.Sink("snk")
+			So(err, ShouldBeNil)
+			si := sin.Sink().(*tupleCollectorSink)
+			si.Wait(n)
+			var res []data.Map
+			for i := 0; i < si.len(); i++ {
+				res = append(res, si.get(i).Data)
+			}
+			return res
+		}
+
+		Convey("When dividing by zero without the policy", func() {
+			res := run(`CREATE STREAM box AS SELECT RSTREAM int, 4 / (int - 2) AS r
+				FROM source [RANGE 1 TUPLES]`, 3)
+
+			Convey("Then the tuple causing the error should be dropped", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"int": data.Int(1), "r": data.Int(-4)},
+					{"int": data.Int(3), "r": data.Int(4)},
+					{"int": data.Int(4), "r": data.Int(2)},
+				})
+			})
+		})
+
+		Convey("When dividing by zero with the policy given by WITH", func() {
+			res := run(`CREATE STREAM box AS SELECT RSTREAM int, 4 / (int - 2) AS r
+				FROM source [RANGE 1 TUPLES] WITH numeric_division_by_zero="null"`, 4)
+
+			Convey("Then the result should be NULL", func() {
+				So(res, ShouldResemble, []data.Map{
+					{"int": data.Int(1), "r": data.Int(-4)},
+					{"int": data.Int(2), "r": data.Null{}},
+					{"int": data.Int(3), "r": data.Int(4)},
+					{"int": data.Int(4), "r": data.Int(2)},
+				})
+			})
+		})
+
+		Convey("When the topology has the policy", func() {
+			So(addBQLToTopology(tb, `SET CONFIG WITH numeric_division_by_zero="saturate",
+				numeric_overflow="error"`), ShouldBeNil)
+
+			Convey("Then it should be applied to statements", func() {
+				res := run(`CREATE STREAM box AS SELECT RSTREAM int, (int - 3) / (int - 2) AS r
+					FROM source [RANGE 1 TUPLES]`, 4)
+				So(res[1], ShouldResemble, data.Map{"int": data.Int(2), "r": data.Int(-9223372036854775808)})
+			})
+
+			Convey("Then WITH should override it", func() {
+				res := run(`CREATE STREAM box AS SELECT RSTREAM int, 4 / (int - 2) AS r
+					FROM source [RANGE 1 TUPLES] WHERE int < 3
+					UNION ALL SELECT RSTREAM int, 4 / (int - 2) AS r
+					FROM source [RANGE 1 TUPLES] WHERE int >= 3
+					WITH numeric_division_by_zero="null"`, 4)
+				// the order of the results isn't guaranteed
+				rs := data.Map{}
+				for _, r := range res {
+					i, err := data.ToString(r["int"])
+					So(err, ShouldBeNil)
+					rs[i] = r["r"]
+				}
+				So(rs, ShouldResemble, data.Map{
+					"1": data.Int(-4),
+					"2": data.Null{},
+					"3": data.Int(4),
+					"4": data.Int(2),
+				})
+			})
+		})
+
+		Convey("When the topology has an invalid policy", func() {
+			So(addBQLToTopology(tb, `SET CONFIG WITH numeric_overflow="wrap"`), ShouldBeNil)
+
+			Convey("Then creating a stream should fail", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM box AS SELECT RSTREAM int + 1 AS r
+					FROM source [RANGE 1 TUPLES]`), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a stream with an invalid policy", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM box AS SELECT RSTREAM int + 1 AS r
+				FROM source [RANGE 1 TUPLES] WITH numeric_non_finite=1`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
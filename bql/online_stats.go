@@ -0,0 +1,370 @@
+package bql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	defaultCountMinSketchWidth  = 2048
+	defaultCountMinSketchDepth  = 5
+	defaultTDigestCompression   = 100
+	defaultHyperLogLogPrecision = 14
+)
+
+// onlineStats is a UDS maintaining a summary of a field in tuples written to
+// it. Following types are provided:
+//
+//	* running_stats: the count, mean, variance, min, max, and sum of numeric
+//	  values. They can be obtained by stats_summary(state).
+//	* count_min_sketch: approximate frequencies of values. The frequency of
+//	  a value can be obtained by cms_count(state, value). It has width
+//	  (default 2048) and depth (default 5) parameters.
+//	* tdigest: approximate quantiles of numeric values. A quantile can be
+//	  obtained by tdigest_quantile(state, q). It has compression parameter
+//	  (default 100) trading accuracy for memory.
+//	* hyperloglog: the approximate number of distinct values, which can be
+//	  obtained by hll_cardinality(state). It has precision parameter from 4
+//	  to 18 (default 14).
+//
+// All types have the required field parameter, which is a JSON Path of the
+// value to be summarized. Tuples are written by a uds sink:
+//
+//	CREATE STATE latency TYPE tdigest WITH field="latency";
+//	CREATE SINK latency_sink TYPE uds WITH name="latency";
+//	INSERT INTO latency_sink FROM requests;
+//	SELECT RSTREAM tdigest_quantile("latency", 0.99) AS p99 FROM ...;
+//
+// Tuples not having the field, or having a non-numeric value for numeric
+// summaries, are ignored and counted as skipped.
+type onlineStats struct {
+	field data.Path
+
+	m          sync.RWMutex
+	summary    streamSummary
+	closed     bool
+	numSkipped int64
+}
+
+// streamSummary is a summary of values updated by onlineStats. The caller
+// must hold the lock of onlineStats.
+type streamSummary interface {
+	// add adds a value to the summary. It returns false when the value
+	// cannot be summarized.
+	add(v data.Value) bool
+
+	// status returns the statistics of the summary.
+	status() data.Map
+}
+
+var (
+	_ core.Writer   = &onlineStats{}
+	_ core.Statuser = &onlineStats{}
+)
+
+func newOnlineStats(field string, s streamSummary) (*onlineStats, error) {
+	p, err := data.CompilePath(field)
+	if err != nil {
+		return nil, fmt.Errorf("'field' parameter doesn't have a valid path: %v", err)
+	}
+	return &onlineStats{
+		field:   p,
+		summary: s,
+	}, nil
+}
+
+// Write adds the field in the tuple to the summary.
+func (s *onlineStats) Write(ctx *core.Context, t *core.Tuple) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return errors.New("the state is already terminated")
+	}
+	v, err := t.Data.Get(s.field)
+	if err != nil || v.Type() == data.TypeNull || !s.summary.add(v) {
+		s.numSkipped++
+	}
+	return nil
+}
+
+// Status returns the statistics of the summary and the number of skipped
+// tuples.
+func (s *onlineStats) Status() data.Map {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	st := s.summary.status()
+	st["num_skipped"] = data.Int(s.numSkipped)
+	return st
+}
+
+func (s *onlineStats) Terminate(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.closed = true
+	return nil
+}
+
+// read calls f with the summary while holding the lock.
+func (s *onlineStats) read(f func(streamSummary) (data.Value, error)) (data.Value, error) {
+	s.m.Lock() // some summaries update themselves on read
+	defer s.m.Unlock()
+	if s.closed {
+		return nil, errors.New("the state is already terminated")
+	}
+	return f(s.summary)
+}
+
+// toFiniteFloat converts a numeric value to float64. It returns false when
+// the value isn't a finite number.
+func toFiniteFloat(v data.Value) (float64, bool) {
+	switch v.Type() {
+	case data.TypeInt, data.TypeFloat:
+	default:
+		return 0, false
+	}
+	f, err := data.ToFloat(v)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+func (s *runningStats) add(v data.Value) bool {
+	f, ok := toFiniteFloat(v)
+	if ok {
+		s.addFloat(f)
+	}
+	return ok
+}
+
+func (s *runningStats) status() data.Map {
+	return data.Map{"count": data.Int(s.count)}
+}
+
+func (s *runningStats) summarize() data.Map {
+	m := data.Map{
+		"count":    data.Int(s.count),
+		"sum":      data.Float(s.sum),
+		"variance": data.Float(s.variance()),
+		"stddev":   data.Float(math.Sqrt(s.variance())),
+	}
+	if s.count == 0 {
+		m["mean"] = data.Null{}
+		m["min"] = data.Null{}
+		m["max"] = data.Null{}
+	} else {
+		m["mean"] = data.Float(s.mean)
+		m["min"] = data.Float(s.min)
+		m["max"] = data.Float(s.max)
+	}
+	return m
+}
+
+func (s *countMinSketch) add(v data.Value) bool {
+	s.addCount(v, 1)
+	return true
+}
+
+func (s *countMinSketch) status() data.Map {
+	return data.Map{
+		"width": data.Int(s.width),
+		"depth": data.Int(s.depth),
+		"total": data.Int(s.total),
+	}
+}
+
+func (t *tDigest) add(v data.Value) bool {
+	f, ok := toFiniteFloat(v)
+	if ok {
+		t.addFloat(f)
+	}
+	return ok
+}
+
+func (t *tDigest) status() data.Map {
+	t.compress()
+	return data.Map{
+		"count":         data.Int(t.count),
+		"compression":   data.Float(t.compression),
+		"num_centroids": data.Int(len(t.centroids)),
+	}
+}
+
+func (h *hyperLogLog) add(v data.Value) bool {
+	h.addValue(v)
+	return true
+}
+
+func (h *hyperLogLog) status() data.Map {
+	return data.Map{
+		"precision": data.Int(h.precision),
+	}
+}
+
+func createRunningStats(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Field string `bql:",required"`
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	return newOnlineStats(c.Field, &runningStats{})
+}
+
+func createCountMinSketch(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Field string `bql:",required"`
+		Width int
+		Depth int
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if _, ok := params["width"]; !ok {
+		c.Width = defaultCountMinSketchWidth
+	}
+	if _, ok := params["depth"]; !ok {
+		c.Depth = defaultCountMinSketchDepth
+	}
+	s, err := newCountMinSketch(c.Width, c.Depth)
+	if err != nil {
+		return nil, err
+	}
+	return newOnlineStats(c.Field, s)
+}
+
+func createTDigest(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Field       string `bql:",required"`
+		Compression float64
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if _, ok := params["compression"]; !ok {
+		c.Compression = defaultTDigestCompression
+	}
+	t, err := newTDigest(c.Compression)
+	if err != nil {
+		return nil, err
+	}
+	return newOnlineStats(c.Field, t)
+}
+
+func createHyperLogLog(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	c := &struct {
+		Field     string `bql:",required"`
+		Precision int
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if _, ok := params["precision"]; !ok {
+		c.Precision = defaultHyperLogLogPrecision
+	}
+	h, err := newHyperLogLog(c.Precision)
+	if err != nil {
+		return nil, err
+	}
+	return newOnlineStats(c.Field, h)
+}
+
+// lookupOnlineStats returns the onlineStats state having the name.
+func lookupOnlineStats(ctx *core.Context, state string) (*onlineStats, error) {
+	s, err := ctx.SharedStates.Get(state)
+	if err != nil {
+		return nil, err
+	}
+	st, ok := s.(*onlineStats)
+	if !ok {
+		return nil, fmt.Errorf("state '%v' isn't an online statistics state", state)
+	}
+	return st, nil
+}
+
+// readSummary calls f with the summary of the state if the summary has the
+// type of f's argument.
+func readSummary(ctx *core.Context, state, typeName string, f func(streamSummary) (data.Value, bool)) (data.Value, error) {
+	s, err := lookupOnlineStats(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	return s.read(func(sum streamSummary) (data.Value, error) {
+		v, ok := f(sum)
+		if !ok {
+			return nil, fmt.Errorf("state '%v' isn't a %v state", state, typeName)
+		}
+		return v, nil
+	})
+}
+
+// statsSummaryFunc returns the summary of a running_stats state.
+func statsSummaryFunc(ctx *core.Context, state string) (data.Value, error) {
+	return readSummary(ctx, state, "running_stats", func(sum streamSummary) (data.Value, bool) {
+		s, ok := sum.(*runningStats)
+		if !ok {
+			return nil, false
+		}
+		return s.summarize(), true
+	})
+}
+
+// cmsCountFunc returns the estimated frequency of the value in a
+// count_min_sketch state.
+func cmsCountFunc(ctx *core.Context, state string, v data.Value) (data.Value, error) {
+	return readSummary(ctx, state, "count_min_sketch", func(sum streamSummary) (data.Value, bool) {
+		s, ok := sum.(*countMinSketch)
+		if !ok {
+			return nil, false
+		}
+		return data.Int(s.estimate(v)), true
+	})
+}
+
+// tdigestQuantileFunc returns the estimated q-quantile in a tdigest state.
+// It returns NULL when the state doesn't have any value.
+func tdigestQuantileFunc(ctx *core.Context, state string, q float64) (data.Value, error) {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return nil, fmt.Errorf("quantile must be from 0 to 1: %v", q)
+	}
+	return readSummary(ctx, state, "tdigest", func(sum streamSummary) (data.Value, bool) {
+		t, ok := sum.(*tDigest)
+		if !ok {
+			return nil, false
+		}
+		if v := t.quantile(q); !math.IsNaN(v) {
+			return data.Float(v), true
+		}
+		return data.Null{}, true
+	})
+}
+
+// hllCardinalityFunc returns the estimated number of distinct values in a
+// hyperloglog state.
+func hllCardinalityFunc(ctx *core.Context, state string) (data.Value, error) {
+	return readSummary(ctx, state, "hyperloglog", func(sum streamSummary) (data.Value, bool) {
+		h, ok := sum.(*hyperLogLog)
+		if !ok {
+			return nil, false
+		}
+		return data.Int(h.estimate()), true
+	})
+}
+
+func init() {
+	udf.MustRegisterGlobalUDSCreator("running_stats", udf.UDSCreatorFunc(createRunningStats))
+	udf.MustRegisterGlobalUDSCreator("count_min_sketch", udf.UDSCreatorFunc(createCountMinSketch))
+	udf.MustRegisterGlobalUDSCreator("tdigest", udf.UDSCreatorFunc(createTDigest))
+	udf.MustRegisterGlobalUDSCreator("hyperloglog", udf.UDSCreatorFunc(createHyperLogLog))
+
+	udf.MustRegisterGlobalUDF("stats_summary", udf.MustConvertGeneric(statsSummaryFunc))
+	udf.MustRegisterGlobalUDF("cms_count", udf.MustConvertGeneric(cmsCountFunc))
+	udf.MustRegisterGlobalUDF("tdigest_quantile", udf.MustConvertGeneric(tdigestQuantileFunc))
+	udf.MustRegisterGlobalUDF("hll_cardinality", udf.MustConvertGeneric(hllCardinalityFunc))
+}
@@ -0,0 +1,190 @@
+package bql
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestOnlineStats(t *testing.T) {
+	ctx := core.NewContext(nil)
+	write := func(s core.SharedState, v data.Value) {
+		So(s.(core.Writer).Write(ctx, core.NewTuple(data.Map{"x": v})), ShouldBeNil)
+	}
+	addState := func(name string, s core.SharedState) {
+		So(ctx.SharedStates.Add(name, "test", s), ShouldBeNil)
+		Reset(func() {
+			ctx.SharedStates.Remove(name)
+		})
+	}
+
+	Convey("Given a running_stats state", t, func() {
+		s, err := createRunningStats(ctx, data.Map{"field": data.String("x")})
+		So(err, ShouldBeNil)
+		addState("rs", s)
+
+		Convey("When no value is written", func() {
+			Convey("Then the summary should have NULL mean", func() {
+				v, err := statsSummaryFunc(ctx, "rs")
+				So(err, ShouldBeNil)
+				m := v.(data.Map)
+				So(m["count"], ShouldEqual, data.Int(0))
+				So(m["mean"], ShouldResemble, data.Null{})
+			})
+		})
+
+		Convey("When values are written", func() {
+			for _, x := range []data.Value{data.Int(2), data.Int(4), data.Float(4), data.Int(4),
+				data.Int(5), data.Int(5), data.Int(7), data.Int(9), data.String("a"), data.Null{}} {
+				write(s, x)
+			}
+			So(s.(core.Writer).Write(ctx, core.NewTuple(data.Map{"y": data.Int(1)})), ShouldBeNil)
+
+			Convey("Then the summary should have statistics of numeric values", func() {
+				v, err := statsSummaryFunc(ctx, "rs")
+				So(err, ShouldBeNil)
+				m := v.(data.Map)
+				So(m["count"], ShouldEqual, data.Int(8))
+				So(m["sum"], ShouldEqual, data.Float(40))
+				So(m["mean"], ShouldEqual, data.Float(5))
+				So(m["min"], ShouldEqual, data.Float(2))
+				So(m["max"], ShouldEqual, data.Float(9))
+				So(float64(m["variance"].(data.Float)), ShouldAlmostEqual, 32.0/7)
+				So(s.(core.Statuser).Status()["num_skipped"], ShouldEqual, data.Int(3))
+			})
+
+			Convey("Then other summary functions should fail", func() {
+				_, err := hllCardinalityFunc(ctx, "rs")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a count_min_sketch state", t, func() {
+		s, err := createCountMinSketch(ctx, data.Map{"field": data.String("x")})
+		So(err, ShouldBeNil)
+		addState("cms", s)
+
+		Convey("When values are written", func() {
+			for i := 0; i < 1000; i++ {
+				write(s, data.Int(i%10))
+			}
+			write(s, data.String("a"))
+
+			Convey("Then the frequency of each value should be estimated", func() {
+				for _, c := range []struct {
+					v        data.Value
+					expected int64
+				}{
+					{data.Int(3), 100},
+					{data.Float(3), 100},
+					{data.String("a"), 1},
+					{data.String("b"), 0},
+				} {
+					v, err := cmsCountFunc(ctx, "cms", c.v)
+					So(err, ShouldBeNil)
+					So(v, ShouldEqual, data.Int(c.expected))
+				}
+				So(s.(core.Statuser).Status()["total"], ShouldEqual, data.Int(1001))
+			})
+		})
+	})
+
+	Convey("Given a tdigest state", t, func() {
+		s, err := createTDigest(ctx, data.Map{"field": data.String("x")})
+		So(err, ShouldBeNil)
+		addState("td", s)
+
+		Convey("When no value is written", func() {
+			Convey("Then the quantile should be NULL", func() {
+				v, err := tdigestQuantileFunc(ctx, "td", 0.5)
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, data.Null{})
+			})
+		})
+
+		Convey("When values are written", func() {
+			for i := 0; i < 10000; i++ {
+				// permute values so that they aren't sorted
+				write(s, data.Int((i*7919)%10000))
+			}
+
+			Convey("Then quantiles should be estimated", func() {
+				for _, q := range []float64{0, 0.01, 0.5, 0.99, 1} {
+					v, err := tdigestQuantileFunc(ctx, "td", q)
+					So(err, ShouldBeNil)
+					So(math.Abs(float64(v.(data.Float))-q*9999), ShouldBeLessThan, 50)
+				}
+				So(s.(core.Statuser).Status()["num_centroids"], ShouldBeLessThan, 500)
+			})
+
+			Convey("Then an invalid quantile should fail", func() {
+				_, err := tdigestQuantileFunc(ctx, "td", 1.5)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a hyperloglog state", t, func() {
+		s, err := createHyperLogLog(ctx, data.Map{"field": data.String("x")})
+		So(err, ShouldBeNil)
+		addState("hll", s)
+
+		Convey("When a few distinct values are written", func() {
+			for i := 0; i < 100; i++ {
+				write(s, data.Int(i%10))
+			}
+
+			Convey("Then the cardinality should be exact", func() {
+				v, err := hllCardinalityFunc(ctx, "hll")
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Int(10))
+			})
+		})
+
+		Convey("When many distinct values are written", func() {
+			for i := 0; i < 100000; i++ {
+				write(s, data.String(fmt.Sprint("v", i)))
+			}
+
+			Convey("Then the cardinality should be estimated", func() {
+				v, err := hllCardinalityFunc(ctx, "hll")
+				So(err, ShouldBeNil)
+				So(math.Abs(float64(v.(data.Int))-100000), ShouldBeLessThan, 3000)
+			})
+		})
+	})
+
+	Convey("Given invalid parameters", t, func() {
+		for _, c := range []struct {
+			create func(*core.Context, data.Map) (core.SharedState, error)
+			params data.Map
+		}{
+			{createRunningStats, data.Map{}},
+			{createRunningStats, data.Map{"field": data.String("x[")}},
+			{createCountMinSketch, data.Map{"field": data.String("x"), "width": data.Int(0)}},
+			{createTDigest, data.Map{"field": data.String("x"), "compression": data.Int(1)}},
+			{createHyperLogLog, data.Map{"field": data.String("x"), "precision": data.Int(20)}},
+		} {
+			Convey("Then creating the state should fail: "+c.params.String(), func() {
+				_, err := c.create(ctx, c.params)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+
+	Convey("Given a state other than online statistics", t, func() {
+		s, err := createLatestBy(ctx, data.Map{"key": data.String("x")})
+		So(err, ShouldBeNil)
+		addState("lb", s)
+
+		Convey("Then summary functions should fail", func() {
+			_, err := statsSummaryFunc(ctx, "lb")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
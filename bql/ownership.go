@@ -0,0 +1,203 @@
+package bql
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// protectedParam is the name of the parameter which marks a node or a state
+// created by a CREATE statement as protected:
+//
+//	CREATE SINK k TYPE fluentd WITH protected=true;
+//
+// Only the owner who created a protected node or state can drop or alter
+// it. An owner is an identity of a client such as a hash of its API token
+// and it's given by TopologyBuilder.AddStmtAs or
+// TopologyBuilder.NewSessionAs. Statements added by AddStmt don't have an
+// owner and cannot create protected nodes.
+//
+// The parameter isn't passed to creators of sources, sinks, and states.
+const protectedParam = "protected"
+
+// ProtectedError is returned when a statement tries to drop or alter a node
+// or a state protected by another owner.
+type ProtectedError struct {
+	// Kind is "node" or "state".
+	Kind string
+	Name string
+}
+
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("%v '%v' is protected by its owner", e.Kind, e.Name)
+}
+
+// IsProtectedError returns true when the error is a ProtectedError.
+func IsProtectedError(err error) bool {
+	_, ok := err.(*ProtectedError)
+	return ok
+}
+
+// ownership has the owner of a node or a state.
+type ownership struct {
+	owner     string
+	protected bool
+}
+
+// ownershipTarget returns the node or the state which the statement drops
+// or alters. kind is empty when the statement doesn't modify existing ones.
+func ownershipTarget(stmt interface{}) (kind, name string) {
+	switch stmt := stmt.(type) {
+	case parser.DropSourceStmt:
+		return "node", string(stmt.Source)
+	case parser.DropStreamStmt:
+		return "node", string(stmt.Stream)
+	case parser.DropSinkStmt:
+		return "node", string(stmt.Sink)
+	case parser.UpdateSourceStmt:
+		return "node", string(stmt.Name)
+	case parser.UpdateSinkStmt:
+		return "node", string(stmt.Name)
+	case parser.PauseSourceStmt:
+		return "node", string(stmt.Source)
+	case parser.ResumeSourceStmt:
+		return "node", string(stmt.Source)
+	case parser.RewindSourceStmt:
+		return "node", string(stmt.Source)
+	case parser.ResumeStreamStmt:
+		return "node", string(stmt.Stream)
+	case parser.ReplaySinkStmt:
+		return "node", string(stmt.Sink)
+	case parser.InsertIntoFromStmt:
+		return "node", string(stmt.Sink)
+	case parser.DropStateStmt:
+		return "state", string(stmt.State)
+	case parser.UpdateStateStmt:
+		return "state", string(stmt.Name)
+	case parser.LoadStateStmt:
+		return "state", string(stmt.Name)
+	case parser.LoadStateOrCreateStmt:
+		return "state", string(stmt.Name)
+	}
+	return "", ""
+}
+
+// ownershipCreated returns nodes or states created by the statement and
+// whether they're protected.
+func ownershipCreated(stmt interface{}) (kind string, names []string, protected bool, err error) {
+	var params []parser.SourceSinkParamAST
+	switch stmt := stmt.(type) {
+	case parser.CreateSourceStmt:
+		kind, names, params = "node", []string{string(stmt.Name)}, stmt.Params
+	case parser.CreateStreamAsSelectStmt:
+		kind, names, params = "node", []string{string(stmt.Name)}, stmt.Params
+	case parser.CreateStreamAsSelectUnionStmt:
+		kind, names, params = "node", []string{string(stmt.Name)}, stmt.Params
+	case parser.CreateSinkStmt:
+		kind, names, params = "node", []string{string(stmt.Name)}, stmt.Params
+	case parser.CreateStreamRoutesStmt:
+		kind = "node"
+		for _, r := range stmt.Routes {
+			names = append(names, string(r.Name))
+		}
+	case parser.CreateStateStmt:
+		kind, names, params = "state", []string{string(stmt.Name)}, stmt.Params
+	default:
+		return "", nil, false, nil
+	}
+
+	for _, p := range params {
+		if string(p.Key) != protectedParam {
+			continue
+		}
+		b, err := data.AsBool(p.Value)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("'%v' parameter must be a bool: %v", protectedParam, err)
+		}
+		protected = b
+	}
+	return kind, names, protected, nil
+}
+
+// isDropStmt returns true when the statement removes a node or a state.
+func isDropStmt(stmt interface{}) bool {
+	switch stmt.(type) {
+	case parser.DropSourceStmt, parser.DropStreamStmt, parser.DropSinkStmt,
+		parser.DropStateStmt:
+		return true
+	}
+	return false
+}
+
+func (tb *TopologyBuilder) ownerships(kind string) map[string]*ownership {
+	if kind == "state" {
+		return tb.stateOwners
+	}
+	return tb.nodeOwners
+}
+
+// checkOwnership returns an error when the statement drops or alters a node
+// or a state which the owner cannot modify.
+func (tb *TopologyBuilder) checkOwnership(owner string, stmt interface{}) error {
+	kind, name := ownershipTarget(stmt)
+	if kind == "" {
+		return nil
+	}
+	return tb.checkOwnershipOf(owner, kind, name)
+}
+
+// checkOwnershipOf returns ProtectedError when the node or the state is
+// protected by an owner other than the given one.
+func (tb *TopologyBuilder) checkOwnershipOf(owner, kind, name string) error {
+	tb.ownerMutex.Lock()
+	defer tb.ownerMutex.Unlock()
+	if o, ok := tb.ownerships(kind)[strings.ToLower(name)]; ok && o.protected && o.owner != owner {
+		return &ProtectedError{Kind: kind, Name: name}
+	}
+	return nil
+}
+
+// setOwnership records the owner of nodes or states. Ownership of removed
+// nodes and states is overwritten when new ones having the same names are
+// created.
+func (tb *TopologyBuilder) setOwnership(kind string, names []string, o *ownership) {
+	tb.ownerMutex.Lock()
+	defer tb.ownerMutex.Unlock()
+	m := tb.ownerships(kind)
+	for _, n := range names {
+		if o == nil {
+			delete(m, strings.ToLower(n))
+		} else {
+			m[strings.ToLower(n)] = o
+		}
+	}
+}
+
+// HasProtected returns true when the topology has a protected node or state
+// created by the TopologyBuilder.
+func (tb *TopologyBuilder) HasProtected() bool {
+	tb.ownerMutex.Lock()
+	defer tb.ownerMutex.Unlock()
+	for _, m := range []map[string]*ownership{tb.nodeOwners, tb.stateOwners} {
+		for _, o := range m {
+			if o.protected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Ownership returns the owner of a node or a state created by a CREATE
+// statement and whether it's protected. kind is "node" or "state". owner is
+// empty when it was created without an owner or doesn't exist.
+func (tb *TopologyBuilder) Ownership(kind, name string) (owner string, protected bool) {
+	tb.ownerMutex.Lock()
+	defer tb.ownerMutex.Unlock()
+	if o, ok := tb.ownerships(kind)[strings.ToLower(name)]; ok {
+		return o.owner, o.protected
+	}
+	return "", false
+}
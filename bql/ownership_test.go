@@ -0,0 +1,119 @@
+package bql
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func addBQLToTopologyAs(tb *TopologyBuilder, owner, bql string) error {
+	stmts, err := parser.New().ParseStmts(bql)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := tb.AddStmtAs(owner, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestOwnership(t *testing.T) {
+	Convey("Given a topology builder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		Convey("When creating protected nodes and a state as an owner", func() {
+			So(addBQLToTopologyAs(tb, "alice", `
+				CREATE PAUSED SOURCE s TYPE dummy WITH num=4, protected=true;
+				CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES] WITH protected=true;
+				CREATE SINK k TYPE collector WITH protected=true;
+				CREATE STATE st TYPE dummy_uds WITH num=1, protected=true;`), ShouldBeNil)
+
+			Convey("Then the ownership should be recorded", func() {
+				owner, protected := tb.Ownership("node", "S")
+				So(owner, ShouldEqual, "alice")
+				So(protected, ShouldBeTrue)
+				owner, protected = tb.Ownership("state", "st")
+				So(owner, ShouldEqual, "alice")
+				So(protected, ShouldBeTrue)
+			})
+
+			Convey("Then another owner shouldn't drop or alter them", func() {
+				for _, q := range []string{
+					`DROP SOURCE s;`,
+					`RESUME SOURCE s;`,
+					`DROP STREAM t;`,
+					`DROP SINK k;`,
+					`INSERT INTO k FROM t;`,
+					`DROP STATE st;`,
+					`UPDATE STATE st SET num=2;`,
+				} {
+					err := addBQLToTopologyAs(tb, "bob", q)
+					So(IsProtectedError(err), ShouldBeTrue)
+				}
+				_, err := dt.Source("s")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then another owner shouldn't load the state directly", func() {
+				err := tb.LoadStateAs("bob", "dummy_uds", "st", bytes.NewReader(nil), data.Map{})
+				So(IsProtectedError(err), ShouldBeTrue)
+			})
+
+			Convey("Then an anonymous client shouldn't drop them", func() {
+				So(IsProtectedError(addBQLToTopology(tb, `DROP SINK k;`)), ShouldBeTrue)
+
+				sess := tb.NewSession()
+				defer sess.Close()
+				_, err := sess.AddStmt(parser.DropStreamStmt{Stream: "t"})
+				So(IsProtectedError(err), ShouldBeTrue)
+			})
+
+			Convey("Then the owner should drop them", func() {
+				So(addBQLToTopologyAs(tb, "alice", `DROP SINK k; DROP STATE st;`), ShouldBeNil)
+				_, err := dt.Sink("k")
+				So(err, ShouldNotBeNil)
+
+				Convey("And others should create new ones having the same names", func() {
+					So(addBQLToTopologyAs(tb, "bob", `CREATE SINK k TYPE collector;`), ShouldBeNil)
+					So(addBQLToTopology(tb, `DROP SINK k;`), ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("When creating a node without protection as an owner", func() {
+			So(addBQLToTopologyAs(tb, "alice", `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;`), ShouldBeNil)
+
+			Convey("Then others should drop it", func() {
+				So(addBQLToTopology(tb, `DROP SOURCE s;`), ShouldBeNil)
+			})
+		})
+
+		Convey("When creating a protected node anonymously", func() {
+			err := addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4, protected=true;`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				_, err := dt.Source("s")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a node with an invalid protected parameter", func() {
+			err := addBQLToTopologyAs(tb, "alice", `CREATE PAUSED SOURCE s TYPE dummy WITH num=4, protected="yes";`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,154 @@
+package bql
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// expandParamEnv resolves references to environment variables in all string
+// values in params including ones in arrays and maps.
+func (tb *TopologyBuilder) expandParamEnv(params data.Map) (data.Map, error) {
+	if len(tb.ParamEnvAllowlist) == 0 {
+		return params, nil
+	}
+	res := make(data.Map, len(params))
+	for k, v := range params {
+		e, err := tb.expandParamEnvValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve parameter '%v': %v", k, err)
+		}
+		res[k] = e
+	}
+	return res, nil
+}
+
+func (tb *TopologyBuilder) expandParamEnvValue(v data.Value) (data.Value, error) {
+	switch v := v.(type) {
+	case data.String:
+		s, err := tb.expandEnv(string(v))
+		if err != nil {
+			return nil, err
+		}
+		return data.String(s), nil
+	case data.Array:
+		a := make(data.Array, len(v))
+		for i, e := range v {
+			r, err := tb.expandParamEnvValue(e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = r
+		}
+		return a, nil
+	case data.Map:
+		m := make(data.Map, len(v))
+		for k, e := range v {
+			r, err := tb.expandParamEnvValue(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = r
+		}
+		return m, nil
+	}
+	return v, nil
+}
+
+// expandEnv replaces references to environment variables in s.
+func (tb *TopologyBuilder) expandEnv(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		b.WriteString(s[:i])
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("a reference to an environment variable isn't closed: %v", s[i:])
+		}
+		v, err := tb.resolveEnvRef(s[i+2 : i+end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+		s = s[i+end+1:]
+	}
+}
+
+// resolveEnvRef returns the value of a reference, which is either NAME or
+// default(NAME, fallback).
+func (tb *TopologyBuilder) resolveEnvRef(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	name, fallback, hasDefault := ref, "", false
+	if strings.HasPrefix(ref, "default(") {
+		if !strings.HasSuffix(ref, ")") {
+			return "", fmt.Errorf("default must be closed by ')': ${%v}", ref)
+		}
+		args := strings.SplitN(ref[len("default("):len(ref)-1], ",", 2)
+		if len(args) != 2 {
+			return "", fmt.Errorf("default must have a name and a fallback value: ${%v}", ref)
+		}
+		name, fallback, hasDefault = strings.TrimSpace(args[0]), strings.TrimSpace(args[1]), true
+	}
+	if !isEnvName(name) {
+		return "", fmt.Errorf("invalid environment variable name: %v", name)
+	}
+	if !tb.envAllowed(name) {
+		return "", fmt.Errorf("environment variable %v isn't allowed to be referred", name)
+	}
+
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		if !hasDefault {
+			return "", fmt.Errorf("environment variable %v isn't set", name)
+		}
+		return fallback, nil
+	}
+	return v, nil
+}
+
+// envAllowed returns true when the name matches one of the allowlist. An
+// entry ending with '*' matches names having the prefix.
+func (tb *TopologyBuilder) envAllowed(name string) bool {
+	for _, a := range tb.ParamEnvAllowlist {
+		if strings.HasSuffix(a, "*") {
+			if strings.HasPrefix(name, a[:len(a)-1]) {
+				return true
+			}
+		} else if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isEnvName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package bql
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestParamEnv(t *testing.T) {
+	Convey("Given a topology builder having a sink receiving parameters", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		var given data.Map
+		So(tb.SinkCreators.Register("params", SinkCreatorFunc(func(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Sink, error) {
+			given = params
+			return &tupleCollectorSink{}, nil
+		})), ShouldBeNil)
+
+		So(os.Setenv("SB_TEST_PARAM_HOST", "example.com"), ShouldBeNil)
+		So(os.Unsetenv("SB_TEST_PARAM_UNSET"), ShouldBeNil)
+		Reset(func() {
+			os.Unsetenv("SB_TEST_PARAM_HOST")
+		})
+
+		Convey("When the allowlist is empty", func() {
+			err := addBQLToTopology(tb, `CREATE SINK k TYPE params WITH host="${SB_TEST_PARAM_HOST}";`)
+			So(err, ShouldBeNil)
+
+			Convey("Then references should be left as they are", func() {
+				So(given["host"], ShouldEqual, data.String("${SB_TEST_PARAM_HOST}"))
+			})
+		})
+
+		Convey("When the allowlist has the variables", func() {
+			tb.ParamEnvAllowlist = []string{"SB_TEST_PARAM_*"}
+
+			Convey("Then references should be resolved", func() {
+				So(addBQLToTopology(tb, `CREATE SINK k TYPE params WITH
+					url="http://${SB_TEST_PARAM_HOST}:${default(SB_TEST_PARAM_UNSET, 8080)}/",
+					hosts=["${SB_TEST_PARAM_HOST}"], opts={"h":"${ SB_TEST_PARAM_HOST }"},
+					raw="$${SB_TEST_PARAM_HOST}", n=1;`), ShouldBeNil)
+				So(given, ShouldResemble, data.Map{
+					"url":   data.String("http://example.com:8080/"),
+					"hosts": data.Array{data.String("example.com")},
+					"opts":  data.Map{"h": data.String("example.com")},
+					"raw":   data.String("${SB_TEST_PARAM_HOST}"),
+					"n":     data.Int(1),
+				})
+			})
+
+			Convey("Then a default should be ignored when the variable is set", func() {
+				So(addBQLToTopology(tb, `CREATE SINK k TYPE params WITH host="${default(SB_TEST_PARAM_HOST, localhost)}";`), ShouldBeNil)
+				So(given["host"], ShouldEqual, data.String("example.com"))
+			})
+
+			Convey("Then referring to an unset variable without a default should fail", func() {
+				err := addBQLToTopology(tb, `CREATE SINK k TYPE params WITH host="${SB_TEST_PARAM_UNSET}";`)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "isn't set")
+			})
+
+			Convey("Then referring to a variable which isn't allowed should fail", func() {
+				err := addBQLToTopology(tb, `CREATE SINK k TYPE params WITH home="${HOME}";`)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "isn't allowed")
+			})
+
+			Convey("Then invalid references should fail", func() {
+				for _, v := range []string{
+					"${SB_TEST_PARAM_HOST",
+					"${}",
+					"${default(SB_TEST_PARAM_HOST)}",
+					"${default(SB_TEST_PARAM_HOST, a}",
+				} {
+					err := addBQLToTopology(tb, `CREATE SINK k TYPE params WITH host="`+v+`";`)
+					So(err, ShouldNotBeNil)
+				}
+			})
+
+			Convey("Then references in SET CONFIG statements should be resolved", func() {
+				So(addBQLToTopology(tb, `SET CONFIG WITH x="${SB_TEST_PARAM_HOST}";`), ShouldBeNil)
+				v, err := dt.Context().Config.GetString("x")
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, "example.com")
+			})
+		})
+	})
+}
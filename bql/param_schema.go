@@ -0,0 +1,181 @@
+package bql
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// ParamSchema declares parameters which a source or a sink accepts in the
+// WITH clause of its CREATE statement. When a creator declares a schema,
+// TopologyBuilder validates parameters against it before calling the
+// creator, so the creator receives parameters having declared types and
+// defaults.
+type ParamSchema struct {
+	// Description describes the source or the sink.
+	Description string
+
+	// Params has declared parameters.
+	Params []*ParamSpec
+
+	// AllowUndeclared allows parameters which aren't declared in Params.
+	// They're passed to the creator without being validated. When it's
+	// false, such parameters are rejected.
+	AllowUndeclared bool
+}
+
+// ParamSpec declares a parameter.
+type ParamSpec struct {
+	// Name is the name of the parameter.
+	Name string
+
+	// Type is the type of the parameter. A parameter can have any type when
+	// it's the zero value. An integer is converted to a float when Type is
+	// data.TypeFloat.
+	Type data.TypeID
+
+	// Required is true when the parameter must be given.
+	Required bool
+
+	// Default is used when the parameter isn't given. It can be nil.
+	Default data.Value
+
+	// Description describes the parameter.
+	Description string
+}
+
+// ParamSchemaProvider is implemented by SourceCreators and SinkCreators
+// declaring their parameters.
+type ParamSchemaProvider interface {
+	// ParamSchema returns the schema of parameters.
+	ParamSchema() *ParamSchema
+}
+
+// ParamSchemaOf returns the schema of parameters declared by a SourceCreator
+// or a SinkCreator. It returns nil when the creator doesn't declare it.
+func ParamSchemaOf(creator interface{}) *ParamSchema {
+	p, ok := creator.(ParamSchemaProvider)
+	if !ok {
+		return nil
+	}
+	return p.ParamSchema()
+}
+
+// Validate validates parameters against the schema. It returns a new map
+// having converted values and defaults of parameters which aren't given.
+func (s *ParamSchema) Validate(params data.Map) (data.Map, error) {
+	res := make(data.Map, len(params))
+	declared := make(map[string]bool, len(s.Params))
+	for _, p := range s.Params {
+		declared[p.Name] = true
+		v, ok := params[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("parameter '%v' is required", p.Name)
+			}
+			if p.Default != nil {
+				res[p.Name] = copyParamDefault(p.Default)
+			}
+			continue
+		}
+		c, err := p.convert(v)
+		if err != nil {
+			return nil, err
+		}
+		res[p.Name] = c
+	}
+
+	var undeclared []string
+	for k, v := range params {
+		if declared[k] {
+			continue
+		}
+		if !s.AllowUndeclared {
+			undeclared = append(undeclared, k)
+			continue
+		}
+		res[k] = v
+	}
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return nil, fmt.Errorf("unknown parameters: %v", undeclared)
+	}
+	return res, nil
+}
+
+func (p *ParamSpec) convert(v data.Value) (data.Value, error) {
+	if p.Type == 0 || v.Type() == p.Type {
+		return v, nil
+	}
+	if p.Type == data.TypeFloat && v.Type() == data.TypeInt {
+		f, err := data.ToFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		return data.Float(f), nil
+	}
+	return nil, fmt.Errorf("parameter '%v' must be %v but is %v", p.Name, p.Type, v.Type())
+}
+
+// copyParamDefault copies the default value so that creators can modify
+// parameters given to them.
+func copyParamDefault(v data.Value) data.Value {
+	switch d := v.(type) {
+	case data.Map:
+		return d.Copy()
+	case data.Array:
+		return d.Copy()
+	}
+	return v
+}
+
+// validateCreatorParams validates parameters given to the creator when it
+// declares their schema. Otherwise, it returns the parameters as they are.
+func validateCreatorParams(creator interface{}, typeName string, params data.Map) (data.Map, error) {
+	s := ParamSchemaOf(creator)
+	if s == nil {
+		return params, nil
+	}
+	res, err := s.Validate(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters of type '%v': %v", typeName, err)
+	}
+	return res, nil
+}
+
+type sourceCreatorWithParamSchema struct {
+	SourceCreator
+	schema *ParamSchema
+}
+
+func (c *sourceCreatorWithParamSchema) ParamSchema() *ParamSchema {
+	return c.schema
+}
+
+// SourceCreatorWithParamSchema returns a SourceCreator which declares the
+// schema of parameters of the given creator.
+func SourceCreatorWithParamSchema(c SourceCreator, s *ParamSchema) SourceCreator {
+	return &sourceCreatorWithParamSchema{
+		SourceCreator: c,
+		schema:        s,
+	}
+}
+
+type sinkCreatorWithParamSchema struct {
+	SinkCreator
+	schema *ParamSchema
+}
+
+func (c *sinkCreatorWithParamSchema) ParamSchema() *ParamSchema {
+	return c.schema
+}
+
+// SinkCreatorWithParamSchema returns a SinkCreator which declares the schema
+// of parameters of the given creator.
+func SinkCreatorWithParamSchema(c SinkCreator, s *ParamSchema) SinkCreator {
+	return &sinkCreatorWithParamSchema{
+		SinkCreator: c,
+		schema:      s,
+	}
+}
@@ -0,0 +1,125 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestParamSchema(t *testing.T) {
+	Convey("Given a parameter schema", t, func() {
+		s := &ParamSchema{
+			Params: []*ParamSpec{
+				{Name: "path", Type: data.TypeString, Required: true},
+				{Name: "rate", Type: data.TypeFloat, Default: data.Float(1)},
+				{Name: "opts", Type: data.TypeMap, Default: data.Map{"a": data.Int(1)}},
+				{Name: "any"},
+			},
+		}
+
+		Convey("When validating valid parameters", func() {
+			p, err := s.Validate(data.Map{
+				"path": data.String("/tmp/a"),
+				"rate": data.Int(2),
+				"any":  data.True,
+			})
+			So(err, ShouldBeNil)
+
+			Convey("Then values should be converted and defaults should be filled", func() {
+				So(p, ShouldResemble, data.Map{
+					"path": data.String("/tmp/a"),
+					"rate": data.Float(2),
+					"opts": data.Map{"a": data.Int(1)},
+					"any":  data.True,
+				})
+			})
+
+			Convey("Then defaults should be copied", func() {
+				p["opts"].(data.Map)["a"] = data.Int(2)
+				So(s.Params[2].Default, ShouldResemble, data.Map{"a": data.Int(1)})
+			})
+		})
+
+		Convey("When a required parameter is missing", func() {
+			_, err := s.Validate(data.Map{})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "path")
+			})
+		})
+
+		Convey("When a parameter has a wrong type", func() {
+			_, err := s.Validate(data.Map{"path": data.Int(1)})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When an undeclared parameter is given", func() {
+			params := data.Map{"path": data.String("a"), "unknown": data.Int(1)}
+
+			Convey("Then it should fail", func() {
+				_, err := s.Validate(params)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "unknown")
+			})
+
+			Convey("Then it should be passed when the schema allows it", func() {
+				s.AllowUndeclared = true
+				p, err := s.Validate(params)
+				So(err, ShouldBeNil)
+				So(p["unknown"], ShouldEqual, data.Int(1))
+			})
+		})
+	})
+
+	Convey("Given a topology builder having a sink declaring its parameters", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		var given data.Map
+		c := SinkCreatorWithParamSchema(SinkCreatorFunc(func(ctx *core.Context, ioParams *IOParams, params data.Map) (core.Sink, error) {
+			given = params
+			return &tupleCollectorSink{}, nil
+		}), &ParamSchema{
+			Params: []*ParamSpec{
+				{Name: "n", Type: data.TypeInt, Default: data.Int(10)},
+			},
+		})
+		So(tb.SinkCreators.Register("declared", c), ShouldBeNil)
+		So(ParamSchemaOf(c), ShouldNotBeNil)
+
+		Convey("When creating a sink with valid parameters", func() {
+			So(addBQLToTopology(tb, `CREATE SINK k TYPE declared WITH labels={"a":"b"};`), ShouldBeNil)
+
+			Convey("Then the creator should receive defaults", func() {
+				So(given, ShouldResemble, data.Map{"n": data.Int(10)})
+			})
+		})
+
+		Convey("When creating a sink with invalid parameters", func() {
+			err := addBQLToTopology(tb, `CREATE SINK k TYPE declared WITH n="a";`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a uds sink without a name", func() {
+			err := addBQLToTopology(tb, `CREATE SINK k TYPE uds;`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "required")
+			})
+		})
+	})
+}
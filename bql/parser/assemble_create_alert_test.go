@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestAssembleCreateAlert(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains the correct CREATE ALERT items", func() {
+			ps.PushComponent(13, 18, StreamIdentifier("a"))
+			ps.PushComponent(24, 29, StreamIdentifier("b"))
+			ps.PushComponent(30, 40, RowValue{"", "x"})
+			ps.AssembleFilter(30, 40)
+			ps.PushComponent(45, 50, SourceSinkParamKey("key"))
+			ps.PushComponent(51, 55, StringLiteral{"id"})
+			ps.AssembleSourceSinkParam()
+			ps.AssembleSourceSinkSpecs(41, 55)
+			ps.AssembleCreateAlert()
+
+			Convey("Then AssembleCreateAlert transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 13)
+				So(top.end, ShouldEqual, 55)
+				So(top.comp, ShouldResemble, CreateAlertStmt{"a", "b",
+					FilterAST{RowValue{"", "x"}},
+					SourceSinkSpecsAST{[]SourceSinkParamAST{{"key", data.String("id")}}}})
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, stmt := range []string{
+			`CREATE ALERT overheat FROM readings WHERE temperature > 80 WITH key="device_id", resolve_after=60`,
+			`CREATE ALERT failures FROM errors`,
+		} {
+			stmt := stmt
+			Convey("When parsing "+stmt, func() {
+				p.Buffer = stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, CreateAlertStmt{})
+
+					Convey("And String() should return the original statement", func() {
+						So(top.(CreateAlertStmt).String(), ShouldEqual, p.Buffer)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing DROP ALERT", func() {
+			p.Buffer = "DROP ALERT overheat"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldResemble, DropAlertStmt{"overheat"})
+				So(top.(DropAlertStmt).String(), ShouldEqual, p.Buffer)
+			})
+		})
+	})
+}
@@ -11,11 +11,13 @@ func TestAssembleCreateSink(t *testing.T) {
 	Convey("Given a parseStack", t, func() {
 		ps := parseStack{}
 		Convey("When the stack contains the correct CREATE SINK items", func() {
+			ps.PushComponent(2, 2, UnspecifiedKeyword)
 			ps.PushComponent(2, 4, StreamIdentifier("a"))
 			ps.PushComponent(4, 6, SourceSinkType("b"))
 			ps.PushComponent(6, 8, SourceSinkParamAST{"c", data.String("d")})
 			ps.PushComponent(8, 10, SourceSinkParamAST{"e", data.String("f")})
 			ps.AssembleSourceSinkSpecs(6, 10)
+			ps.AssembleSinkOrdering(10, 10)
 			ps.AssembleCreateSink()
 
 			Convey("Then AssembleCreateSink transforms them into one item", func() {
@@ -37,6 +39,8 @@ func TestAssembleCreateSink(t *testing.T) {
 						So(comp.Params[0].Value, ShouldEqual, data.String("d"))
 						So(comp.Params[1].Key, ShouldEqual, "e")
 						So(comp.Params[1].Value, ShouldEqual, data.String("f"))
+						So(comp.Ordering, ShouldEqual, UnspecifiedSinkOrdering)
+						So(comp.BufferSize, ShouldEqual, UnspecifiedCapacity)
 					})
 				})
 			})
@@ -51,11 +55,13 @@ func TestAssembleCreateSink(t *testing.T) {
 		})
 
 		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 2, UnspecifiedKeyword)
 			ps.PushComponent(2, 4, Raw{"a"}) // must be StreamIdentifier
 			ps.PushComponent(4, 6, SourceSinkType("b"))
 			ps.PushComponent(6, 8, SourceSinkParamAST{"c", data.String("d")})
 			ps.PushComponent(8, 10, SourceSinkParamAST{"e", data.String("f")})
 			ps.AssembleSourceSinkSpecs(6, 10)
+			ps.AssembleSinkOrdering(10, 10)
 
 			Convey("Then AssembleCreateSink panics", func() {
 				So(ps.AssembleCreateSink, ShouldPanic)
@@ -88,6 +94,53 @@ func TestAssembleCreateSink(t *testing.T) {
 				So(comp.Params[0].Value, ShouldEqual, data.Int(27))
 				So(comp.Params[1].Key, ShouldEqual, "e_")
 				So(comp.Params[1].Value, ShouldEqual, data.String("f_1"))
+				So(comp.Ordering, ShouldEqual, UnspecifiedSinkOrdering)
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing a CREATE SINK with an ORDER BY clause", func() {
+			p.Buffer = `CREATE SINK a TYPE b WITH c=1 ORDER BY TIMESTAMP BUFFER SIZE 100`
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				comp := ps.Peek().comp.(CreateSinkStmt)
+
+				So(comp.Name, ShouldEqual, "a")
+				So(len(comp.Params), ShouldEqual, 1)
+				So(comp.Ordering, ShouldEqual, TimestampOrder)
+				So(comp.BufferSize, ShouldEqual, 100)
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing a CREATE SINK with ORDER BY ROUND ROBIN", func() {
+			p.Buffer = `CREATE SINK a TYPE b ORDER BY ROUND ROBIN`
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				comp := ps.Peek().comp.(CreateSinkStmt)
+
+				So(comp.Ordering, ShouldEqual, RoundRobinOrder)
+				So(comp.BufferSize, ShouldEqual, UnspecifiedCapacity)
 
 				Convey("And String() should return the original statement", func() {
 					So(comp.String(), ShouldEqual, p.Buffer)
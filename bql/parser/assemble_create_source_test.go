@@ -11,12 +11,16 @@ func TestAssembleCreateSource(t *testing.T) {
 	Convey("Given a parseStack", t, func() {
 		ps := parseStack{}
 		Convey("When the stack contains the correct CREATE SOURCE items", func() {
+			ps.PushComponent(0, 0, UnspecifiedKeyword)
 			ps.PushComponent(0, 2, Yes)
 			ps.PushComponent(2, 4, StreamIdentifier("a"))
 			ps.PushComponent(4, 6, SourceSinkType("b"))
+			ps.AssembleSchema(6, 6)
+			ps.AssembleInstances(6, 6)
 			ps.PushComponent(6, 8, SourceSinkParamAST{"c", data.String("d")})
 			ps.PushComponent(8, 10, SourceSinkParamAST{"e", data.String("f")})
 			ps.AssembleSourceSinkSpecs(6, 10)
+			ps.AssembleTimestampBy(10, 10)
 			ps.AssembleCreateSource()
 
 			Convey("Then AssembleCreateSource transforms them into one item", func() {
@@ -32,6 +36,7 @@ func TestAssembleCreateSource(t *testing.T) {
 					Convey("And it contains the previously pushed data", func() {
 						comp := top.comp.(CreateSourceStmt)
 						So(comp.Paused, ShouldEqual, Yes)
+						So(comp.Instances, ShouldEqual, UnspecifiedInstances)
 						So(comp.Name, ShouldEqual, "a")
 						So(comp.Type, ShouldEqual, "b")
 						So(len(comp.Params), ShouldEqual, 2)
@@ -53,12 +58,16 @@ func TestAssembleCreateSource(t *testing.T) {
 		})
 
 		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(0, 0, UnspecifiedKeyword)
 			ps.PushComponent(0, 2, Yes)
 			ps.PushComponent(2, 4, Raw{"a"}) // must be StreamIdentifier
 			ps.PushComponent(4, 6, SourceSinkType("b"))
+			ps.AssembleSchema(6, 6)
+			ps.AssembleInstances(6, 6)
 			ps.PushComponent(6, 8, SourceSinkParamAST{"c", data.String("d")})
 			ps.PushComponent(8, 10, SourceSinkParamAST{"e", data.String("f")})
 			ps.AssembleSourceSinkSpecs(6, 10)
+			ps.AssembleTimestampBy(10, 10)
 
 			Convey("Then AssembleCreateSource panics", func() {
 				So(ps.AssembleCreateSource, ShouldPanic)
@@ -98,5 +107,26 @@ func TestAssembleCreateSource(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("When doing a CREATE SOURCE with INSTANCES", func() {
+			p.Buffer = `CREATE SOURCE a TYPE b INSTANCES 4 WITH c=27`
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				comp := ps.Peek().comp.(CreateSourceStmt)
+				So(comp.Instances, ShouldEqual, 4)
+				So(len(comp.Params), ShouldEqual, 1)
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
 	})
 }
@@ -2,6 +2,7 @@ package parser
 
 import (
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"testing"
 )
 
@@ -9,7 +10,9 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 	Convey("Given a parseStack", t, func() {
 		ps := parseStack{}
 		Convey("When the stack contains the correct CREATE STREAM items", func() {
+			ps.PushComponent(2, 2, UnspecifiedKeyword)
 			ps.PushComponent(2, 4, StreamIdentifier("x"))
+			ps.AssembleSchema(4, 4)
 			ps.PushComponent(4, 6, Istream)
 			ps.AssembleEmitterOptions(6, 6)
 			ps.AssembleEmitter()
@@ -20,6 +23,7 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 			ps.AssembleProjections(6, 9)
 			ps.PushComponent(10, 11, Stream{ActualStream, "c", nil})
 			ps.PushComponent(11, 12, IntervalAST{FloatLiteral{3}, Tuples})
+			ps.PushComponent(12, 12, TumblingAST{})
 			ps.PushComponent(12, 13, NumericLiteral{2})
 			ps.EnsureCapacitySpec(12, 13)
 			ps.PushComponent(13, 14, DropOldest)
@@ -30,6 +34,7 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 			ps.PushComponent(16, 17, NumericLiteral{2})
 			ps.PushComponent(17, 18, Seconds)
 			ps.AssembleInterval()
+			ps.PushComponent(18, 18, TumblingAST{})
 			ps.EnsureCapacitySpec(18, 18)
 			ps.EnsureSheddingSpec(18, 18)
 			ps.AssembleStreamWindow()
@@ -37,6 +42,7 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 			ps.AssembleAliasedStreamWindow()
 			ps.EnsureAliasedStreamWindow()
 			ps.AssembleWindowedFrom(10, 20)
+			ps.AssembleInputSampling(20, 20)
 			ps.PushComponent(20, 21, RowValue{"", "e"})
 			ps.AssembleFilter(20, 21)
 			ps.PushComponent(21, 22, RowValue{"", "f"})
@@ -44,7 +50,12 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 			ps.AssembleGrouping(21, 23)
 			ps.PushComponent(23, 24, RowValue{"", "h"})
 			ps.AssembleHaving(23, 24)
+			ps.AssembleEmitWhen(24, 24)
 			ps.AssembleSelect()
+			ps.AssembleTimestampBy(24, 24)
+			ps.AssembleOnError(24, 24)
+			ps.AssembleTimeout(24, 24)
+			ps.AssembleSourceSinkSpecs(24, 24)
 			ps.AssembleCreateStreamAsSelect()
 
 			Convey("Then AssembleCreateStreamAsSelect transforms them into one item", func() {
@@ -97,7 +108,9 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 		})
 
 		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 2, UnspecifiedKeyword)
 			ps.PushComponent(2, 4, StreamIdentifier("x"))
+			ps.AssembleSchema(4, 4)
 			ps.PushComponent(4, 6, Istream) // must be SELECT in correct stmt
 
 			Convey("Then AssembleCreateStreamAsSelect panics", func() {
@@ -155,5 +168,29 @@ func TestAssembleCreateStreamAsSelect(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("When doing a SELECT with labels", func() {
+			p.Buffer = `CREATE STREAM x AS SELECT ISTREAM a FROM c [RANGE 1 TUPLES] TIMEOUT 1 SECONDS WITH labels={"team":"sensing"}`
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectStmt{})
+				cssComp := top.(CreateStreamAsSelectStmt)
+				So(cssComp.Params, ShouldResemble, []SourceSinkParamAST{
+					{"labels", data.Map{"team": data.String("sensing")}},
+				})
+
+				Convey("And String() should return the original statement", func() {
+					So(cssComp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
 	})
 }
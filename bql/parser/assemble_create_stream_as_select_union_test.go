@@ -9,7 +9,9 @@ func TestAssembleCreateStreamAsSelectUnion(t *testing.T) {
 	Convey("Given a parseStack", t, func() {
 		ps := parseStack{}
 		Convey("When the stack contains the correct CREATE STREAM items", func() {
+			ps.PushComponent(2, 2, UnspecifiedKeyword)
 			ps.PushComponent(2, 4, StreamIdentifier("x"))
+			ps.AssembleSchema(4, 4)
 			ps.PushComponent(4, 6, Istream)
 			ps.AssembleEmitterOptions(6, 6)
 			ps.AssembleEmitter()
@@ -20,6 +22,7 @@ func TestAssembleCreateStreamAsSelectUnion(t *testing.T) {
 			ps.AssembleProjections(6, 9)
 			ps.PushComponent(10, 11, Stream{ActualStream, "c", nil})
 			ps.PushComponent(11, 12, IntervalAST{FloatLiteral{3}, Tuples})
+			ps.PushComponent(12, 12, TumblingAST{})
 			ps.PushComponent(12, 13, NumericLiteral{2})
 			ps.EnsureCapacitySpec(12, 13)
 			ps.PushComponent(13, 14, DropOldest)
@@ -30,6 +33,7 @@ func TestAssembleCreateStreamAsSelectUnion(t *testing.T) {
 			ps.PushComponent(16, 17, NumericLiteral{2})
 			ps.PushComponent(17, 18, Seconds)
 			ps.AssembleInterval()
+			ps.PushComponent(18, 18, TumblingAST{})
 			ps.EnsureCapacitySpec(18, 18)
 			ps.EnsureSheddingSpec(18, 18)
 			ps.AssembleStreamWindow()
@@ -37,6 +41,7 @@ func TestAssembleCreateStreamAsSelectUnion(t *testing.T) {
 			ps.AssembleAliasedStreamWindow()
 			ps.EnsureAliasedStreamWindow()
 			ps.AssembleWindowedFrom(10, 20)
+			ps.AssembleInputSampling(20, 20)
 			ps.PushComponent(20, 21, RowValue{"", "e"})
 			ps.AssembleFilter(20, 21)
 			ps.PushComponent(21, 22, RowValue{"", "f"})
@@ -44,8 +49,13 @@ func TestAssembleCreateStreamAsSelectUnion(t *testing.T) {
 			ps.AssembleGrouping(21, 23)
 			ps.PushComponent(23, 24, RowValue{"", "h"})
 			ps.AssembleHaving(23, 24)
+			ps.AssembleEmitWhen(24, 24)
 			ps.AssembleSelect()
 			ps.AssembleSelectUnion(4, 24)
+			ps.AssembleTimestampBy(24, 24)
+			ps.AssembleOnError(24, 24)
+			ps.AssembleTimeout(24, 24)
+			ps.AssembleSourceSinkSpecs(24, 24)
 			ps.AssembleCreateStreamAsSelectUnion()
 
 			Convey("Then AssembleCreateStreamAsSelectUnion transforms them into one item", func() {
@@ -99,7 +109,9 @@ func TestAssembleCreateStreamAsSelectUnion(t *testing.T) {
 		})
 
 		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 2, UnspecifiedKeyword)
 			ps.PushComponent(2, 4, StreamIdentifier("x"))
+			ps.AssembleSchema(4, 4)
 			ps.PushComponent(4, 6, Istream) // must be SELECT in correct stmt
 
 			Convey("Then AssembleCreateStreamAsSelectUnion panics", func() {
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestAssembleCreateStreamRoutes(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+		Convey("When the stack contains the correct CREATE STREAM ROUTES items", func() {
+			ps.PushComponent(2, 4, StreamIdentifier("x"))
+			ps.PushComponent(4, 10, RoutesAST{nil, []RouteAST{
+				{StreamIdentifier("a"), RowValue{"", "b"}},
+				{StreamIdentifier("c"), nil},
+			}})
+			ps.AssembleCreateStreamRoutes()
+
+			Convey("Then AssembleCreateStreamRoutes transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+
+				Convey("And that item is a CreateStreamRoutesStmt", func() {
+					top := ps.Peek()
+					So(top, ShouldNotBeNil)
+					So(top.begin, ShouldEqual, 2)
+					So(top.end, ShouldEqual, 10)
+					So(top.comp, ShouldHaveSameTypeAs, CreateStreamRoutesStmt{})
+
+					Convey("And it contains the previously pushed data", func() {
+						comp := top.comp.(CreateStreamRoutesStmt)
+						So(comp.Input, ShouldEqual, "x")
+						So(comp.PartitionKey, ShouldBeNil)
+						So(len(comp.Routes), ShouldEqual, 2)
+						So(comp.Routes[0].Name, ShouldEqual, "a")
+						So(comp.Routes[0].Condition, ShouldResemble, RowValue{"", "b"})
+						So(comp.Routes[1].Name, ShouldEqual, "c")
+						So(comp.Routes[1].Condition, ShouldBeNil)
+					})
+				})
+			})
+		})
+
+		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 4, StreamIdentifier("x"))
+			ps.PushComponent(4, 10, StreamIdentifier("y")) // must be RoutesAST
+			Convey("Then AssembleCreateStreamRoutes panics", func() {
+				So(ps.AssembleCreateStreamRoutes, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When doing a CREATE STREAM ROUTES with conditions", func() {
+			p.Buffer = "CREATE STREAM ROUTES FROM src WHEN a > 10 INTO big WHEN a < 0 INTO negative ELSE INTO rest"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamRoutesStmt{})
+				comp := top.(CreateStreamRoutesStmt)
+
+				So(comp.Input, ShouldEqual, "src")
+				So(comp.PartitionKey, ShouldBeNil)
+				So(comp.Routes, ShouldResemble, []RouteAST{
+					{StreamIdentifier("big"), BinaryOpAST{Greater, RowValue{"", "a"}, NumericLiteral{10}}},
+					{StreamIdentifier("negative"), BinaryOpAST{Less, RowValue{"", "a"}, NumericLiteral{0}}},
+					{StreamIdentifier("rest"), nil},
+				})
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing a CREATE STREAM ROUTES with a partition key", func() {
+			p.Buffer = "CREATE STREAM ROUTES FROM src PARTITIONED BY id % 7 INTO p0, p1, p2"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamRoutesStmt{})
+				comp := top.(CreateStreamRoutesStmt)
+
+				So(comp.Input, ShouldEqual, "src")
+				So(comp.PartitionKey, ShouldResemble, BinaryOpAST{Modulo, RowValue{"", "id"}, NumericLiteral{7}})
+				So(comp.Routes, ShouldResemble, []RouteAST{
+					{StreamIdentifier("p0"), nil},
+					{StreamIdentifier("p1"), nil},
+					{StreamIdentifier("p2"), nil},
+				})
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing a CREATE STREAM ROUTES with ELSE only", func() {
+			p.Buffer = "CREATE STREAM ROUTES FROM src ELSE INTO rest"
+			p.Init()
+
+			Convey("Then parsing should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestAssembleCreateTemplate(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains the correct CREATE TEMPLATE items", func() {
+			ps.PushComponent(16, 24, StreamIdentifier("pipeline"))
+			ps.PushComponent(25, 27, Identifier("id"))
+			ps.PushComponent(29, 30, Identifier("n"))
+			ps.PushComponent(37, 60, StringLiteral{"CREATE SINK s TYPE x"})
+			ps.AssembleCreateTemplate(0, 62)
+
+			Convey("Then AssembleCreateTemplate transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 0)
+				So(top.end, ShouldEqual, 62)
+				So(top.comp, ShouldResemble, CreateTemplateStmt{"pipeline",
+					[]string{"id", "n"}, "CREATE SINK s TYPE x"})
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt     string
+			expected interface{}
+		}{
+			{`CREATE TEMPLATE pipeline(id, n) AS $$
+CREATE SOURCE src_${id} TYPE dummy WITH num=${n};
+CREATE SINK snk_${id} TYPE stdout WITH name="${id}";
+$$`, CreateTemplateStmt{"pipeline", []string{"id", "n"}, `
+CREATE SOURCE src_${id} TYPE dummy WITH num=${n};
+CREATE SINK snk_${id} TYPE stdout WITH name="${id}";
+`}},
+			{`CREATE TEMPLATE pipeline AS $$CREATE SINK s TYPE stdout$$`,
+				CreateTemplateStmt{"pipeline", nil, "CREATE SINK s TYPE stdout"}},
+			{`INSTANTIATE TEMPLATE pipeline`,
+				InstantiateTemplateStmt{"pipeline", "", SourceSinkSpecsAST{}}},
+			{`INSTANTIATE TEMPLATE pipeline AS dev1 WITH id="x", n=3`,
+				InstantiateTemplateStmt{"pipeline", "dev1", SourceSinkSpecsAST{[]SourceSinkParamAST{
+					{"id", data.String("x")},
+					{"n", data.Int(3)},
+				}}}},
+			{`DROP TEMPLATE pipeline`, DropTemplateStmt{"pipeline"}},
+			{`DROP TEMPLATE INSTANCE dev1`, DropTemplateInstanceStmt{"dev1"}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldResemble, c.expected)
+
+					Convey("And String() should return the original statement", func() {
+						So(top.(fmt.Stringer).String(), ShouldEqual, p.Buffer)
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleCreateTrigger(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains the correct CREATE TRIGGER items", func() {
+			ps.PushComponent(15, 20, StreamIdentifier("a"))
+			ps.PushComponent(27, 36, StringLiteral{"0 2 * * *"})
+			ps.PushComponent(40, 52, StreamIdentifier("b"))
+			ps.PushComponent(60, 61, NumericLiteral{1})
+			ps.AssembleProjections(60, 61)
+			ps.AssembleTriggerInsert()
+			ps.AssembleCreateTrigger()
+
+			Convey("Then AssembleCreateTrigger transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 15)
+				So(top.end, ShouldEqual, 61)
+				So(top.comp, ShouldResemble, CreateTriggerStmt{"a", "0 2 * * *",
+					TriggerInsertStmt{"b", ProjectionsAST{[]Expression{NumericLiteral{1}}}}})
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt   string
+			action interface{}
+		}{
+			{`CREATE TRIGGER nightly EVERY "0 2 * * *" DO INSERT INTO report SELECT count(st) AS n, 1`,
+				TriggerInsertStmt{"report", ProjectionsAST{[]Expression{
+					AliasAST{FuncAppAST{"count", ExpressionsAST{[]Expression{RowValue{"", "st"}}}, nil}, "n"},
+					NumericLiteral{1},
+				}}}},
+			{`CREATE TRIGGER nightly EVERY "@daily" DO SAVE STATE st`,
+				SaveStateStmt{"st", ""}},
+			{`CREATE TRIGGER nightly EVERY "@every 1h" DO REWIND SOURCE s`,
+				RewindSourceStmt{"s"}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, CreateTriggerStmt{})
+					s := top.(CreateTriggerStmt)
+					So(s.Name, ShouldEqual, "nightly")
+					So(s.Action, ShouldResemble, c.action)
+
+					Convey("And String() should return the original statement", func() {
+						So(s.String(), ShouldEqual, p.Buffer)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing a trigger with an unsupported action", func() {
+			p.Buffer = `CREATE TRIGGER nightly EVERY "@daily" DO DROP SOURCE s`
+			p.Init()
+
+			Convey("Then parsing should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When parsing DROP TRIGGER", func() {
+			p.Buffer = "DROP TRIGGER nightly"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldResemble, DropTriggerStmt{"nightly"})
+				So(top.(DropTriggerStmt).String(), ShouldEqual, p.Buffer)
+			})
+		})
+	})
+}
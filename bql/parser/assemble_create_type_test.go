@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleCreateType(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains the correct CREATE TYPE items", func() {
+			ps.PushComponent(12, 13, StreamIdentifier("a"))
+			ps.PushComponent(18, 19, Identifier("b"))
+			ps.PushComponent(20, 23, Int)
+			ps.AssembleTypeField()
+			ps.PushComponent(25, 26, Identifier("c"))
+			ps.PushComponent(27, 33, String)
+			ps.AssembleTypeField()
+			ps.AssembleCreateType(0, 34)
+
+			Convey("Then AssembleCreateType transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 0)
+				So(top.end, ShouldEqual, 34)
+				So(top.comp, ShouldResemble, CreateTypeStmt{"a", []TypeFieldAST{
+					{"b", Int}, {"c", String},
+				}})
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When parsing CREATE TYPE", func() {
+			p.Buffer = "CREATE TYPE reading AS (id STRING, value FLOAT, tags ARRAY)"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldResemble, CreateTypeStmt{"reading", []TypeFieldAST{
+					{"id", String}, {"value", Float}, {"tags", Array},
+				}})
+
+				Convey("And String() should return the original statement", func() {
+					So(top.(CreateTypeStmt).String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing CREATE TYPE without fields", func() {
+			p.Buffer = "CREATE TYPE reading AS ()"
+			p.Init()
+
+			Convey("Then parsing should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When parsing DROP TYPE", func() {
+			p.Buffer = "DROP TYPE reading"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldResemble, DropTypeStmt{"reading"})
+				So(top.(DropTypeStmt).String(), ShouldEqual, p.Buffer)
+			})
+		})
+
+		for _, c := range []struct {
+			stmt     string
+			expected SchemaAST
+		}{
+			{"CREATE SOURCE s TYPE fluentd SCHEMA reading", SchemaAST{"reading"}},
+			{`CREATE PAUSED SOURCE s TYPE fluentd SCHEMA reading WITH port=24224`, SchemaAST{"reading"}},
+			{"CREATE SOURCE s TYPE fluentd WITH port=24224", SchemaAST{}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					top := p.parseStack.Peek().comp
+					So(top, ShouldHaveSameTypeAs, CreateSourceStmt{})
+					s := top.(CreateSourceStmt)
+					So(s.SchemaAST, ShouldResemble, c.expected)
+					So(s.String(), ShouldEqual, c.stmt)
+				})
+			})
+		}
+
+		Convey("When parsing CREATE STREAM with SCHEMA", func() {
+			p.Buffer = "CREATE STREAM t SCHEMA reading AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES]"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectStmt{})
+				s := top.(CreateStreamAsSelectStmt)
+				So(s.SchemaAST, ShouldResemble, SchemaAST{"reading"})
+				So(s.String(), ShouldEqual, p.Buffer)
+			})
+		})
+
+		Convey("When parsing CREATE STREAM AS SELECT UNION ALL with SCHEMA", func() {
+			p.Buffer = "CREATE STREAM t SCHEMA reading AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] UNION ALL SELECT ISTREAM a FROM u [RANGE 1 TUPLES]"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectUnionStmt{})
+				s := top.(CreateStreamAsSelectUnionStmt)
+				So(s.SchemaAST, ShouldResemble, SchemaAST{"reading"})
+				So(s.String(), ShouldEqual, p.Buffer)
+			})
+		})
+	})
+}
@@ -123,6 +123,57 @@ func TestAssembleGrouping(t *testing.T) {
 				So(s.GroupList[0], ShouldResemble, RowValue{"", "c"})
 				So(s.GroupList[1], ShouldResemble, RowValue{"", "d"})
 
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+		Convey("When selecting with a GROUP BY ROLLUP", func() {
+			p.Buffer = "SELECT ISTREAM a, b GROUP BY ROLLUP(c, d)"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, SelectStmt{})
+				s := top.(SelectStmt)
+				So(len(s.GroupList), ShouldEqual, 2)
+				So(s.GroupList[0], ShouldResemble, RowValue{"", "c"})
+				So(s.GroupList[1], ShouldResemble, RowValue{"", "d"})
+				So(s.GroupingSets, ShouldResemble, [][]int{{0, 1}, {0}, {}})
+				So(s.Rollup, ShouldBeTrue)
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When selecting with a GROUP BY GROUPING SETS", func() {
+			p.Buffer = "SELECT ISTREAM a, b GROUP BY GROUPING SETS ((c, d), (d), ())"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, SelectStmt{})
+				s := top.(SelectStmt)
+				So(len(s.GroupList), ShouldEqual, 2)
+				So(s.GroupList[0], ShouldResemble, RowValue{"", "c"})
+				So(s.GroupList[1], ShouldResemble, RowValue{"", "d"})
+				So(s.GroupingSets, ShouldResemble, [][]int{{0, 1}, {1}, {}})
+				So(s.Rollup, ShouldBeFalse)
+
 				Convey("And String() should return the original statement", func() {
 					So(s.String(), ShouldEqual, p.Buffer)
 				})
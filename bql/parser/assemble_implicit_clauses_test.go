@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleImplicitClauses(t *testing.T) {
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt    string
+			emitter Emitter
+			units   []IntervalUnit
+		}{
+			{"SELECT a FROM s", UnspecifiedEmitter, []IntervalUnit{UnspecifiedIntervalUnit}},
+			{"SELECT * FROM s AS x, t [RANGE 2 SECONDS] AS y WHERE x:a = y:a",
+				UnspecifiedEmitter, []IntervalUnit{UnspecifiedIntervalUnit, Seconds}},
+			{"SELECT ISTREAM a FROM s", Istream, []IntervalUnit{UnspecifiedIntervalUnit}},
+			{"SELECT RSTREAM a FROM f(1) [RANGE 1 TUPLES]", Rstream, []IntervalUnit{Tuples}},
+			{"SELECT istream_count FROM s", UnspecifiedEmitter, []IntervalUnit{UnspecifiedIntervalUnit}},
+			{"SELECT 1", UnspecifiedEmitter, nil},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, SelectStmt{})
+					s := top.(SelectStmt)
+					So(s.EmitterType, ShouldEqual, c.emitter)
+					So(len(s.Relations), ShouldEqual, len(c.units))
+					for i, u := range c.units {
+						So(s.Relations[i].Unit, ShouldEqual, u)
+					}
+
+					Convey("And String() should return the original statement", func() {
+						So(s.String(), ShouldEqual, c.stmt)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing SELECT UNION ALL without emitters and windows", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT a FROM s UNION ALL SELECT a FROM t"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectUnionStmt{})
+				s := top.(CreateStreamAsSelectUnionStmt)
+				So(len(s.Selects), ShouldEqual, 2)
+				So(s.Selects[1].EmitterType, ShouldEqual, UnspecifiedEmitter)
+				So(s.Selects[1].Relations[0].Name, ShouldEqual, "t")
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleInputSampling(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains an EmitterSampling in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(11, 14, EmitterSampling{10, RandomizedSampling})
+			ps.AssembleInputSampling(6, 14)
+
+			Convey("Then AssembleInputSampling replaces it with an InputSamplingAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 6)
+				So(top.end, ShouldEqual, 14)
+				So(top.comp, ShouldResemble, InputSamplingAST{EmitterSampling{10, RandomizedSampling}})
+			})
+		})
+
+		Convey("When the given range is empty", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.AssembleInputSampling(6, 6)
+
+			Convey("Then AssembleInputSampling pushes an empty InputSamplingAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				So(ps.Peek().comp, ShouldResemble, InputSamplingAST{})
+			})
+		})
+
+		Convey("When the stack contains one item not in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(6, 7, EmitterSampling{10, RandomizedSampling})
+			f := func() {
+				ps.AssembleInputSampling(7, 10)
+			}
+			Convey("Then AssembleInputSampling panics", func() {
+				So(f, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt     string
+			expected InputSamplingAST
+		}{
+			{"SELECT ISTREAM a FROM s [RANGE 1 TUPLES]",
+				InputSamplingAST{}},
+			{"SELECT ISTREAM a FROM s [RANGE 1 TUPLES] READ SAMPLE 10%",
+				InputSamplingAST{EmitterSampling{10, RandomizedSampling}}},
+			{"SELECT ISTREAM a FROM s [RANGE 1 TUPLES] READ SAMPLE 0.5%",
+				InputSamplingAST{EmitterSampling{0.5, RandomizedSampling}}},
+			{"SELECT ISTREAM a FROM s [RANGE 1 TUPLES] READ EVERY 3-RD TUPLE WHERE b",
+				InputSamplingAST{EmitterSampling{3, CountBasedSampling}}},
+			{"SELECT ISTREAM [SAMPLE 50%] a FROM s [RANGE 1 TUPLES] READ EVERY 10-TH TUPLE GROUP BY a",
+				InputSamplingAST{EmitterSampling{10, CountBasedSampling}}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, SelectStmt{})
+					s := top.(SelectStmt)
+					So(s.InputSamplingAST, ShouldResemble, c.expected)
+
+					Convey("And String() should return the original statement", func() {
+						So(s.String(), ShouldEqual, c.stmt)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing a time-based READ clause", func() {
+			p.Buffer = "SELECT ISTREAM a FROM s [RANGE 1 TUPLES] READ EVERY 2 SECONDS"
+			p.Init()
+
+			Convey("Then parsing should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+	})
+}
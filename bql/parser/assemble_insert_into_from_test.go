@@ -10,7 +10,10 @@ func TestAssembleInsertIntoFrom(t *testing.T) {
 		ps := parseStack{}
 		Convey("When the stack contains the correct SELECT items with a Interval specification", func() {
 			ps.PushComponent(4, 5, StreamIdentifier("x"))
+			ps.EnsureProjections(5, 5)
 			ps.PushComponent(5, 6, StreamIdentifier("y"))
+			ps.AssembleFilter(6, 6)
+			ps.AssembleOnError(6, 6)
 			ps.AssembleInsertIntoFrom()
 
 			Convey("Then AssembleInsertIntoFrom transforms them into one item", func() {
@@ -26,7 +29,9 @@ func TestAssembleInsertIntoFrom(t *testing.T) {
 					Convey("And it contains the previously pushed data", func() {
 						comp := top.comp.(InsertIntoFromStmt)
 						So(comp.Sink, ShouldEqual, "x")
+						So(comp.Projections, ShouldBeNil)
 						So(comp.Input, ShouldEqual, "y")
+						So(comp.Filter, ShouldBeNil)
 					})
 				})
 			})
@@ -70,6 +75,59 @@ func TestAssembleInsertIntoFrom(t *testing.T) {
 
 				So(comp.Sink, ShouldEqual, "x")
 				So(comp.Input, ShouldEqual, "y")
+				So(comp.Projections, ShouldBeNil)
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing an INSERT INTO SELECT FROM with WHERE", func() {
+			p.Buffer = "INSERT INTO x SELECT a, b:c AS d FROM y WHERE a > 1 ON ERROR DROP"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, InsertIntoFromStmt{})
+				comp := top.(InsertIntoFromStmt)
+
+				So(comp.Sink, ShouldEqual, "x")
+				So(comp.Input, ShouldEqual, "y")
+				So(len(comp.Projections), ShouldEqual, 2)
+				So(comp.Projections[0], ShouldResemble, RowValue{"", "a"})
+				So(comp.Projections[1], ShouldResemble, AliasAST{RowValue{"b", "c"}, "d"})
+				So(comp.Filter, ShouldResemble, BinaryOpAST{Greater, RowValue{"", "a"}, NumericLiteral{1}})
+				So(comp.ErrorPolicy, ShouldEqual, DropOnError)
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing an INSERT INTO FROM with only WHERE", func() {
+			p.Buffer = "INSERT INTO x FROM y WHERE a = 1"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				comp := p.parseStack.Peek().comp.(InsertIntoFromStmt)
+				So(comp.Projections, ShouldBeNil)
+				So(comp.Filter, ShouldNotBeNil)
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
 			})
 		})
 	})
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleOnError(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains an ErrorPolicy in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(16, 20, DropOnError)
+			ps.AssembleOnError(6, 20)
+
+			Convey("Then AssembleOnError replaces it with an OnErrorAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 6)
+				So(top.end, ShouldEqual, 20)
+				So(top.comp, ShouldResemble, OnErrorAST{DropOnError, UnspecifiedRetries})
+			})
+		})
+
+		Convey("When the stack contains RETRY and a number in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(16, 21, RetryOnError)
+			ps.PushComponent(22, 23, NumericLiteral{5})
+			ps.AssembleOnError(6, 29)
+
+			Convey("Then AssembleOnError replaces them with an OnErrorAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 6)
+				So(top.end, ShouldEqual, 29)
+				So(top.comp, ShouldResemble, OnErrorAST{RetryOnError, 5})
+			})
+		})
+
+		Convey("When the given range is empty", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.AssembleOnError(6, 6)
+
+			Convey("Then AssembleOnError pushes an unspecified policy", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				So(ps.Peek().comp, ShouldResemble,
+					OnErrorAST{UnspecifiedErrorPolicy, UnspecifiedRetries})
+			})
+		})
+
+		Convey("When the stack contains one item not in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(6, 7, DropOnError)
+			f := func() {
+				ps.AssembleOnError(7, 10)
+			}
+			Convey("Then AssembleOnError panics", func() {
+				So(f, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt     string
+			expected OnErrorAST
+		}{
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES]",
+				OnErrorAST{UnspecifiedErrorPolicy, UnspecifiedRetries}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] ON ERROR DROP",
+				OnErrorAST{DropOnError, UnspecifiedRetries}},
+			{"CREATE STREAM x AS SELECT ISTREAM count(*) FROM s [RANGE 1 TUPLES] GROUP BY a HAVING b ON ERROR STOP",
+				OnErrorAST{StopOnError, UnspecifiedRetries}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] WHERE b ON ERROR RETRY",
+				OnErrorAST{RetryOnError, UnspecifiedRetries}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] ON ERROR RETRY 5 TIMES",
+				OnErrorAST{RetryOnError, 5}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] ON ERROR DLQ",
+				OnErrorAST{DLQOnError, UnspecifiedRetries}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectStmt{})
+					s := top.(CreateStreamAsSelectStmt)
+					So(s.OnErrorAST, ShouldResemble, c.expected)
+
+					Convey("And String() should return the original statement", func() {
+						So(s.String(), ShouldEqual, c.stmt)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing CREATE STREAM AS SELECT UNION ALL with ON ERROR", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] UNION ALL SELECT ISTREAM a FROM t [RANGE 1 TUPLES] ON ERROR DROP"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectUnionStmt{})
+				s := top.(CreateStreamAsSelectUnionStmt)
+				So(len(s.Selects), ShouldEqual, 2)
+				So(s.OnErrorAST, ShouldResemble, OnErrorAST{DropOnError, UnspecifiedRetries})
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing INSERT INTO with ON ERROR", func() {
+			p.Buffer = "INSERT INTO x FROM y ON ERROR RETRY 2 TIMES"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, InsertIntoFromStmt{})
+				s := top.(InsertIntoFromStmt)
+				So(s.OnErrorAST, ShouldResemble, OnErrorAST{RetryOnError, 2})
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing an unknown error policy", func() {
+			p.Buffer = "INSERT INTO x FROM y ON ERROR IGNORE"
+			p.Init()
+
+			Convey("Then parsing should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestAssembleProfileStream(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+		Convey("When the stack contains the correct PROFILE STREAM items", func() {
+			ps.PushComponent(2, 4, StreamIdentifier("a"))
+			ps.PushComponent(6, 8, SourceSinkParamAST{"c", data.Float(0.5)})
+			ps.AssembleSourceSinkSpecs(6, 8)
+			ps.AssembleProfileStream()
+
+			Convey("Then AssembleProfileStream transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+
+				Convey("And that item is a ProfileStreamStmt", func() {
+					top := ps.Peek()
+					So(top, ShouldNotBeNil)
+					So(top.begin, ShouldEqual, 2)
+					So(top.end, ShouldEqual, 8)
+					So(top.comp, ShouldHaveSameTypeAs, ProfileStreamStmt{})
+
+					Convey("And it contains the previously pushed data", func() {
+						comp := top.comp.(ProfileStreamStmt)
+						So(comp.Name, ShouldEqual, "a")
+						So(len(comp.Params), ShouldEqual, 1)
+						So(comp.Params[0].Key, ShouldEqual, "c")
+						So(comp.Params[0].Value, ShouldEqual, data.Float(0.5))
+					})
+				})
+			})
+		})
+
+		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 4, Raw{"a"}) // must be StreamIdentifier
+			ps.PushComponent(6, 8, SourceSinkParamAST{"c", data.Float(0.5)})
+			ps.AssembleSourceSinkSpecs(6, 8)
+
+			Convey("Then AssembleProfileStream panics", func() {
+				So(ps.AssembleProfileStream, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When doing a full PROFILE STREAM", func() {
+			p.Buffer = "PROFILE STREAM a_1 WITH sampling_rate=0.5"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, ProfileStreamStmt{})
+				comp := top.(ProfileStreamStmt)
+
+				So(comp.Name, ShouldEqual, "a_1")
+				So(len(comp.Params), ShouldEqual, 1)
+				So(comp.Params[0].Key, ShouldEqual, "sampling_rate")
+				So(comp.Params[0].Value, ShouldEqual, data.Float(0.5))
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing a PROFILE STREAM without parameters", func() {
+			p.Buffer = "PROFILE STREAM a_1"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				comp := ps.Peek().comp.(ProfileStreamStmt)
+				So(comp.Name, ShouldEqual, "a_1")
+				So(comp.Params, ShouldBeEmpty)
+				So(comp.String(), ShouldEqual, p.Buffer)
+			})
+		})
+	})
+}
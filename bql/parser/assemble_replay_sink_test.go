@@ -0,0 +1,68 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestAssembleReplaySink(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+		Convey("When the stack contains the correct REPLAY SINK items", func() {
+			ps.PushComponent(2, 4, StreamIdentifier("a"))
+			ps.AssembleReplaySink()
+
+			Convey("Then AssembleReplaySink transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+
+				Convey("And that item is a ReplaySinkStmt", func() {
+					top := ps.Peek()
+					So(top, ShouldNotBeNil)
+					So(top.begin, ShouldEqual, 2)
+					So(top.end, ShouldEqual, 4)
+					So(top.comp, ShouldHaveSameTypeAs, ReplaySinkStmt{})
+
+					Convey("And it contains the previously pushed data", func() {
+						comp := top.comp.(ReplaySinkStmt)
+						So(comp.Sink, ShouldEqual, "a")
+					})
+				})
+			})
+		})
+
+		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 4, Raw{"a"}) // must be StreamIdentifier
+
+			Convey("Then AssembleReplaySink panics", func() {
+				So(ps.AssembleReplaySink, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When doing a full REPLAY SINK", func() {
+			p.Buffer = "REPLAY SINK a_1"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, ReplaySinkStmt{})
+				comp := top.(ReplaySinkStmt)
+
+				So(comp.Sink, ShouldEqual, "a_1")
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+	})
+}
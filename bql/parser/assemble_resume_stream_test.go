@@ -0,0 +1,68 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestAssembleResumeStream(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+		Convey("When the stack contains the correct RESUME STREAM items", func() {
+			ps.PushComponent(2, 4, StreamIdentifier("a"))
+			ps.AssembleResumeStream()
+
+			Convey("Then AssembleResumeStream transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+
+				Convey("And that item is a ResumeStreamStmt", func() {
+					top := ps.Peek()
+					So(top, ShouldNotBeNil)
+					So(top.begin, ShouldEqual, 2)
+					So(top.end, ShouldEqual, 4)
+					So(top.comp, ShouldHaveSameTypeAs, ResumeStreamStmt{})
+
+					Convey("And it contains the previously pushed data", func() {
+						comp := top.comp.(ResumeStreamStmt)
+						So(comp.Stream, ShouldEqual, "a")
+					})
+				})
+			})
+		})
+
+		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 4, Raw{"a"}) // must be StreamIdentifier
+
+			Convey("Then AssembleResumeStream panics", func() {
+				So(ps.AssembleResumeStream, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When doing a full RESUME STREAM", func() {
+			p.Buffer = "RESUME STREAM a_1"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, ResumeStreamStmt{})
+				comp := top.(ResumeStreamStmt)
+
+				So(comp.Stream, ShouldEqual, "a_1")
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+	})
+}
@@ -18,6 +18,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.AssembleProjections(6, 8)
 			ps.PushComponent(10, 11, Stream{ActualStream, "c", nil})
 			ps.PushComponent(11, 12, IntervalAST{FloatLiteral{3}, Tuples})
+			ps.PushComponent(12, 12, TumblingAST{})
 			ps.PushComponent(12, 13, NumericLiteral{2})
 			ps.EnsureCapacitySpec(12, 13)
 			ps.PushComponent(13, 14, DropOldest)
@@ -28,6 +29,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.PushComponent(16, 17, NumericLiteral{2})
 			ps.PushComponent(17, 18, Seconds)
 			ps.AssembleInterval()
+			ps.PushComponent(18, 18, TumblingAST{})
 			ps.EnsureCapacitySpec(18, 18)
 			ps.EnsureSheddingSpec(18, 18)
 			ps.AssembleStreamWindow()
@@ -35,6 +37,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.AssembleAliasedStreamWindow()
 			ps.EnsureAliasedStreamWindow()
 			ps.AssembleWindowedFrom(10, 20)
+			ps.AssembleInputSampling(20, 20)
 			ps.PushComponent(22, 24, RowValue{"", "e"})
 			ps.AssembleFilter(22, 24)
 			ps.PushComponent(24, 26, RowValue{"", "f"})
@@ -42,6 +45,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.AssembleGrouping(24, 28)
 			ps.PushComponent(28, 30, RowValue{"", "h"})
 			ps.AssembleHaving(28, 30)
+			ps.AssembleEmitWhen(30, 30)
 			ps.AssembleSelect()
 
 			Convey("Then AssembleSelect transforms them into one item", func() {
@@ -101,6 +105,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.AssembleProjections(6, 8)
 			ps.PushComponent(10, 11, Stream{ActualStream, "c", nil})
 			ps.PushComponent(11, 12, IntervalAST{FloatLiteral{3}, Tuples})
+			ps.PushComponent(12, 12, TumblingAST{})
 			ps.PushComponent(12, 13, NumericLiteral{2})
 			ps.EnsureCapacitySpec(12, 13)
 			ps.PushComponent(13, 14, DropOldest)
@@ -111,6 +116,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.PushComponent(16, 17, NumericLiteral{2})
 			ps.PushComponent(17, 18, Seconds)
 			ps.AssembleInterval()
+			ps.PushComponent(18, 18, TumblingAST{})
 			ps.EnsureCapacitySpec(18, 18)
 			ps.EnsureSheddingSpec(18, 18)
 			ps.AssembleStreamWindow()
@@ -118,6 +124,7 @@ func TestAssembleSelect(t *testing.T) {
 			ps.AssembleAliasedStreamWindow()
 			ps.EnsureAliasedStreamWindow()
 			ps.AssembleWindowedFrom(10, 20)
+			ps.AssembleInputSampling(20, 20)
 			ps.PushComponent(22, 24, RowValue{"", "e"})
 			ps.AssembleFilter(22, 24)
 			ps.PushComponent(24, 26, RowValue{"", "f"})
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"testing"
+)
+
+func TestAssembleSetConfig(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+		Convey("When the stack contains the correct SET CONFIG items", func() {
+			ps.PushComponent(11, 14, SourceSinkParamAST{"c", data.String("d")})
+			ps.PushComponent(16, 19, SourceSinkParamAST{"e", data.String("f")})
+			ps.AssembleSourceSinkSpecs(10, 19)
+			ps.AssembleSetConfig()
+
+			Convey("Then AssembleSetConfig transforms them into one item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+
+				Convey("And that item is a SetConfigStmt", func() {
+					top := ps.Peek()
+					So(top, ShouldNotBeNil)
+					So(top.begin, ShouldEqual, 10)
+					So(top.end, ShouldEqual, 19)
+					So(top.comp, ShouldHaveSameTypeAs, SetConfigStmt{})
+
+					Convey("And it contains the previously pushed data", func() {
+						comp := top.comp.(SetConfigStmt)
+						So(len(comp.Params), ShouldEqual, 2)
+						So(comp.Params[0].Key, ShouldEqual, "c")
+						So(comp.Params[0].Value, ShouldEqual, data.String("d"))
+						So(comp.Params[1].Key, ShouldEqual, "e")
+						So(comp.Params[1].Value, ShouldEqual, data.String("f"))
+					})
+				})
+			})
+		})
+
+		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(2, 4, Raw{"a"}) // must be SourceSinkSpecsAST
+
+			Convey("Then AssembleSetConfig panics", func() {
+				So(ps.AssembleSetConfig, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When doing a full SET CONFIG", func() {
+			p.Buffer = `SET CONFIG WITH c=27, e_="f_1"`
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, SetConfigStmt{})
+				comp := top.(SetConfigStmt)
+
+				So(len(comp.Params), ShouldEqual, 2)
+				So(comp.Params[0].Key, ShouldEqual, "c")
+				So(comp.Params[0].Value, ShouldEqual, data.Int(27))
+				So(comp.Params[1].Key, ShouldEqual, "e_")
+				So(comp.Params[1].Value, ShouldEqual, data.String("f_1"))
+
+				Convey("And String() should return the original statement", func() {
+					So(comp.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When doing SET CONFIG without parameters", func() {
+			p.Buffer = `SET CONFIG`
+			p.Init()
+
+			Convey("Then the statement should be rejected", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+	})
+}
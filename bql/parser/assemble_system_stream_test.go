@@ -0,0 +1,62 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSystemStream(t *testing.T) {
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When parsing a SELECT statement with a system stream", func() {
+			p.Buffer = "SELECT ISTREAM node_name FROM system.nodes [RANGE 1 TUPLES]"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, SelectStmt{})
+				s := top.(SelectStmt)
+				So(len(s.WindowedFromAST.Relations), ShouldEqual, 1)
+				comp := s.WindowedFromAST.Relations[0]
+				So(comp.Type, ShouldEqual, SystemStream)
+				So(comp.Name, ShouldEqual, "nodes")
+				So(comp.Alias, ShouldEqual, "")
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing a SELECT statement joining a system stream with an alias", func() {
+			p.Buffer = "SELECT ISTREAM n:node_name FROM SYSTEM.stats [RANGE 1 TUPLES] AS n, system [RANGE 1 TUPLES]"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				s := p.parseStack.Peek().comp.(SelectStmt)
+				So(len(s.WindowedFromAST.Relations), ShouldEqual, 2)
+				comp := s.WindowedFromAST.Relations[0]
+				So(comp.Type, ShouldEqual, SystemStream)
+				So(comp.Name, ShouldEqual, "stats")
+				So(comp.Alias, ShouldEqual, "n")
+
+				Convey("And a stream named system should be an actual stream", func() {
+					comp := s.WindowedFromAST.Relations[1]
+					So(comp.Type, ShouldEqual, ActualStream)
+					So(comp.Name, ShouldEqual, "system")
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTemporaryKeyword(t *testing.T) {
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt      string
+			temporary BinaryKeyword
+		}{
+			{"CREATE TEMPORARY SOURCE s TYPE dummy", Yes},
+			{"CREATE TEMPORARY PAUSED SOURCE s TYPE dummy", Yes},
+			{"CREATE PAUSED SOURCE s TYPE dummy", UnspecifiedKeyword},
+			{"CREATE TEMPORARY STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES]", Yes},
+			{"CREATE STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES]", UnspecifiedKeyword},
+			{"CREATE TEMPORARY STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES] UNION ALL SELECT ISTREAM * FROM u [RANGE 1 TUPLES]", Yes},
+			{"CREATE TEMPORARY SINK snk TYPE stdout", Yes},
+			{"CREATE SINK snk TYPE stdout", UnspecifiedKeyword},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek()
+					var temporary BinaryKeyword
+					switch s := top.comp.(type) {
+					case CreateSourceStmt:
+						temporary = s.Temporary
+					case CreateStreamAsSelectStmt:
+						temporary = s.Temporary
+					case CreateStreamAsSelectUnionStmt:
+						temporary = s.Temporary
+					case CreateSinkStmt:
+						temporary = s.Temporary
+					}
+					So(temporary, ShouldEqual, c.temporary)
+
+					Convey("And String() should return the original statement", func() {
+						So(top.comp.(fmt.Stringer).String(), ShouldEqual, c.stmt)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing CREATE PAUSED TEMPORARY SOURCE", func() {
+			p.Buffer = "CREATE PAUSED TEMPORARY SOURCE s TYPE dummy"
+			p.Init()
+
+			Convey("Then parsing should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleTimeout(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains an IntervalAST in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(15, 30, IntervalAST{FloatLiteral{100}, Milliseconds})
+			ps.AssembleTimeout(6, 30)
+
+			Convey("Then AssembleTimeout replaces it with a TimeoutAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 6)
+				So(top.end, ShouldEqual, 30)
+				So(top.comp, ShouldResemble, TimeoutAST{IntervalAST{FloatLiteral{100}, Milliseconds}})
+			})
+		})
+
+		Convey("When the given range is empty", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.AssembleTimeout(6, 6)
+
+			Convey("Then AssembleTimeout pushes an unspecified timeout", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				So(ps.Peek().comp, ShouldResemble, TimeoutAST{})
+			})
+		})
+
+		Convey("When the stack contains one item not in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(6, 7, IntervalAST{FloatLiteral{1}, Seconds})
+			f := func() {
+				ps.AssembleTimeout(7, 10)
+			}
+
+			Convey("Then AssembleTimeout panics", func() {
+				So(f, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt     string
+			expected TimeoutAST
+		}{
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES]",
+				TimeoutAST{}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] TIMEOUT 2 SECONDS",
+				TimeoutAST{IntervalAST{FloatLiteral{2}, Seconds}}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] ON ERROR DROP TIMEOUT 0.5 MILLISECONDS",
+				TimeoutAST{IntervalAST{FloatLiteral{0.5}, Milliseconds}}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectStmt{})
+					s := top.(CreateStreamAsSelectStmt)
+					So(s.TimeoutAST, ShouldResemble, c.expected)
+
+					Convey("And String() should return the original statement", func() {
+						So(s.String(), ShouldEqual, c.stmt)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing CREATE STREAM AS SELECT UNION ALL with TIMEOUT", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] UNION ALL SELECT ISTREAM a FROM t [RANGE 1 TUPLES] TIMEOUT 1 SECONDS"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectUnionStmt{})
+				s := top.(CreateStreamAsSelectUnionStmt)
+				So(s.TimeoutAST, ShouldResemble, TimeoutAST{IntervalAST{FloatLiteral{1}, Seconds}})
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing TIMEOUT with TUPLES", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] TIMEOUT 1 TUPLES"
+			p.Init()
+
+			Convey("Then the statement should be rejected", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAssembleTimestampBy(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains a field in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(19, 21, Raw{"ts"})
+			ps.AssembleTimestampBy(6, 21)
+
+			Convey("Then AssembleTimestampBy replaces it with a TimestampByAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 6)
+				So(top.end, ShouldEqual, 21)
+				So(top.comp, ShouldResemble, TimestampByAST{"ts", ""})
+			})
+		})
+
+		Convey("When the stack contains a field and a format in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(19, 21, Raw{"ts"})
+			ps.PushComponent(29, 41, StringLiteral{"unix_milli"})
+			ps.AssembleTimestampBy(6, 41)
+
+			Convey("Then AssembleTimestampBy replaces them with a TimestampByAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				top := ps.Peek()
+				So(top.begin, ShouldEqual, 6)
+				So(top.end, ShouldEqual, 41)
+				So(top.comp, ShouldResemble, TimestampByAST{"ts", "unix_milli"})
+			})
+		})
+
+		Convey("When the given range is empty", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.AssembleTimestampBy(6, 6)
+
+			Convey("Then AssembleTimestampBy pushes an empty TimestampByAST", func() {
+				So(ps.Len(), ShouldEqual, 2)
+				So(ps.Peek().comp, ShouldResemble, TimestampByAST{})
+			})
+		})
+
+		Convey("When the stack contains one item not in the given range", func() {
+			ps.PushComponent(0, 6, Raw{"PRE"})
+			ps.PushComponent(6, 7, Raw{"ts"})
+			f := func() {
+				ps.AssembleTimestampBy(7, 10)
+			}
+			Convey("Then AssembleTimestampBy panics", func() {
+				So(f, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		for _, c := range []struct {
+			stmt     string
+			expected TimestampByAST
+		}{
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES]",
+				TimestampByAST{}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] TIMESTAMP BY a",
+				TimestampByAST{"a", ""}},
+			{"CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] WHERE b TIMESTAMP BY a.b[0]",
+				TimestampByAST{"a.b[0]", ""}},
+			{`CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] TIMESTAMP BY a FORMAT "2006-01-02 15:04:05" ON ERROR DROP`,
+				TimestampByAST{"a", "2006-01-02 15:04:05"}},
+		} {
+			c := c
+			Convey("When parsing "+c.stmt, func() {
+				p.Buffer = c.stmt
+				p.Init()
+
+				Convey("Then the statement should be parsed correctly", func() {
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					ps := p.parseStack
+					So(ps.Len(), ShouldEqual, 1)
+					top := ps.Peek().comp
+					So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectStmt{})
+					s := top.(CreateStreamAsSelectStmt)
+					So(s.TimestampByAST, ShouldResemble, c.expected)
+
+					Convey("And String() should return the original statement", func() {
+						So(s.String(), ShouldEqual, c.stmt)
+					})
+				})
+			})
+		}
+
+		Convey("When parsing CREATE SOURCE with TIMESTAMP BY", func() {
+			p.Buffer = `CREATE PAUSED SOURCE x TYPE y WITH a=1 TIMESTAMP BY ts FORMAT "unix_milli"`
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateSourceStmt{})
+				s := top.(CreateSourceStmt)
+				So(len(s.Params), ShouldEqual, 1)
+				So(s.TimestampByAST, ShouldResemble, TimestampByAST{"ts", "unix_milli"})
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing CREATE STREAM AS SELECT UNION ALL with TIMESTAMP BY", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT ISTREAM a FROM s [RANGE 1 TUPLES] UNION ALL SELECT ISTREAM a FROM t [RANGE 1 TUPLES] TIMESTAMP BY a"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				top := p.parseStack.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectUnionStmt{})
+				s := top.(CreateStreamAsSelectUnionStmt)
+				So(s.TimestampByAST, ShouldResemble, TimestampByAST{"a", ""})
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+	})
+}
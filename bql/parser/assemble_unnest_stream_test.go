@@ -0,0 +1,104 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestAssembleUnnestStream(t *testing.T) {
+	Convey("Given a parseStack", t, func() {
+		ps := parseStack{}
+
+		Convey("When the stack contains the correct UNNEST items", func() {
+			ps.PushComponent(7, 10, NewStream("src"))
+			ps.PushComponent(12, 17, RowValue{"", "a.b"})
+			ps.AssembleUnnestStream(0, 18)
+
+			Convey("Then AssembleUnnestStream replaces them with a new item", func() {
+				So(ps.Len(), ShouldEqual, 1)
+
+				Convey("And that item is a Stream", func() {
+					top := ps.Peek()
+					So(top, ShouldNotBeNil)
+					So(top.begin, ShouldEqual, 0)
+					So(top.end, ShouldEqual, 18)
+					So(top.comp, ShouldResemble, Stream{UnnestStream, "src",
+						[]Expression{RowValue{"", "a.b"}}})
+				})
+			})
+		})
+
+		Convey("When the stack contains a wrong item", func() {
+			ps.PushComponent(7, 10, NewStream("src"))
+			ps.PushComponent(12, 17, NumericLiteral{2}) // must be RowValue
+
+			Convey("Then AssembleUnnestStream panics", func() {
+				So(func() { ps.AssembleUnnestStream(0, 18) }, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a parser", t, func() {
+		p := &bqlPeg{}
+
+		Convey("When parsing a SELECT statement with UNNEST", func() {
+			p.Buffer = "SELECT ISTREAM src:items FROM UNNEST(src, src:items) [RANGE 1 TUPLES]"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, SelectStmt{})
+				s := top.(SelectStmt)
+				So(len(s.WindowedFromAST.Relations), ShouldEqual, 1)
+				comp := s.WindowedFromAST.Relations[0]
+				So(comp.Type, ShouldEqual, UnnestStream)
+				So(comp.Name, ShouldEqual, "src")
+				So(comp.Params, ShouldResemble, []Expression{RowValue{"src", "items"}})
+				So(comp.Alias, ShouldEqual, "")
+
+				Convey("And String() should return the original statement", func() {
+					So(s.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+
+		Convey("When parsing a SELECT statement with UNNEST and an alias", func() {
+			p.Buffer = "SELECT ISTREAM x:a[0] FROM unnest ( src , a[0] ) [RANGE 1 TUPLES] AS x"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				s := ps.Peek().comp.(SelectStmt)
+				comp := s.WindowedFromAST.Relations[0]
+				So(comp.Type, ShouldEqual, UnnestStream)
+				So(comp.Name, ShouldEqual, "src")
+				So(comp.Params, ShouldResemble, []Expression{RowValue{"", "a[0]"}})
+				So(comp.Alias, ShouldEqual, "x")
+			})
+		})
+
+		Convey("When parsing a SELECT statement with UNNEST having a non-column path", func() {
+			p.Buffer = "SELECT ISTREAM * FROM UNNEST(src, \"items\") [RANGE 1 TUPLES]"
+			p.Init()
+
+			Convey("Then it should be parsed as a UDSF", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				s := p.parseStack.Peek().comp.(SelectStmt)
+				So(s.WindowedFromAST.Relations[0].Type, ShouldEqual, UDSFStream)
+			})
+		})
+	})
+}
@@ -14,11 +14,11 @@ func TestAssembleWindowedFrom(t *testing.T) {
 			ps.PushComponent(0, 6, Raw{"PRE"})
 			ps.PushComponent(6, 8, AliasedStreamWindowAST{
 				StreamWindowAST{Stream{ActualStream, "a", nil}, IntervalAST{FloatLiteral{3}, Tuples},
-					2, UnspecifiedSheddingOption}, "",
+					2, UnspecifiedSheddingOption, TumblingAST{}}, "",
 			})
 			ps.PushComponent(8, 10, AliasedStreamWindowAST{
 				StreamWindowAST{Stream{ActualStream, "b", nil}, IntervalAST{FloatLiteral{2}, Seconds},
-					UnspecifiedCapacity, Wait}, "",
+					UnspecifiedCapacity, Wait, TumblingAST{}}, "",
 			})
 			ps.AssembleWindowedFrom(6, 10)
 
@@ -154,6 +154,34 @@ func TestAssembleWindowedFrom(t *testing.T) {
 				So(comp.Relations[1].Shedding, ShouldEqual, UnspecifiedSheddingOption)
 				So(comp.Relations[1].Alias, ShouldEqual, "")
 
+				Convey("And String() should return the original statement", func() {
+					stmt := top.(CreateStreamAsSelectStmt)
+					So(stmt.String(), ShouldEqual, p.Buffer)
+				})
+			})
+		})
+		Convey("When selecting with a FROM and JOIN STATE", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT ISTREAM s:a, d:b FROM s [RANGE 1 TUPLES] JOIN STATE devices AS d ON s:id = d:key JOIN STATE users ON users:key = s:user"
+			p.Init()
+
+			Convey("Then the statement should be parsed correctly", func() {
+				err := p.Parse()
+				So(err, ShouldBeNil)
+				p.Execute()
+
+				ps := p.parseStack
+				So(ps.Len(), ShouldEqual, 1)
+				top := ps.Peek().comp
+				So(top, ShouldHaveSameTypeAs, CreateStreamAsSelectStmt{})
+				comp := top.(CreateStreamAsSelectStmt).Select
+				So(len(comp.Relations), ShouldEqual, 1)
+				So(comp.Relations[0].Name, ShouldEqual, "s")
+				So(len(comp.StateJoins), ShouldEqual, 2)
+				So(comp.StateJoins[0], ShouldResemble, StateJoinAST{"devices", "d",
+					BinaryOpAST{Equal, RowValue{"s", "id"}, RowValue{"d", "key"}}})
+				So(comp.StateJoins[1], ShouldResemble, StateJoinAST{"users", "",
+					BinaryOpAST{Equal, RowValue{"users", "key"}, RowValue{"s", "user"}}})
+
 				Convey("And String() should return the original statement", func() {
 					stmt := top.(CreateStreamAsSelectStmt)
 					So(stmt.String(), ShouldEqual, p.Buffer)
@@ -14,6 +14,7 @@ func TestAssembleStreamWindow(t *testing.T) {
 			ps.PushComponent(0, 6, Raw{"PRE"})
 			ps.PushComponent(6, 8, Stream{ActualStream, "a", nil})
 			ps.PushComponent(8, 10, IntervalAST{FloatLiteral{2}, Seconds})
+			ps.PushComponent(10, 10, TumblingAST{})
 			ps.PushComponent(10, 12, NumericLiteral{2})
 			ps.EnsureCapacitySpec(10, 12)
 			ps.PushComponent(12, 14, DropOldest)
@@ -46,6 +47,7 @@ func TestAssembleStreamWindow(t *testing.T) {
 			ps.PushComponent(0, 6, Raw{"PRE"})
 			ps.PushComponent(6, 8, Stream{ActualStream, "a", nil})
 			ps.PushComponent(8, 10, IntervalAST{FloatLiteral{0.2}, Seconds})
+			ps.PushComponent(10, 10, TumblingAST{})
 			ps.PushComponent(10, 12, NumericLiteral{2})
 			ps.EnsureCapacitySpec(10, 12)
 			ps.PushComponent(12, 14, DropNewest)
@@ -186,5 +188,89 @@ func TestAssembleStreamWindow(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("When selecting with TUMBLING windows", func() {
+			for _, c := range []struct {
+				window   string
+				expected TumblingAST
+			}{
+				{"TUMBLING 60 SECONDS", TumblingAST{Tumbling: true}},
+				{"TUMBLING 100 TUPLES", TumblingAST{Tumbling: true}},
+				{"TUMBLING 60 SECONDS BY PROCESSING TIME",
+					TumblingAST{Tumbling: true, ProcessingTime: true}},
+				{"TUMBLING 60 SECONDS, WATERMARK DELAY 500 MILLISECONDS",
+					TumblingAST{Tumbling: true, Delay: IntervalAST{FloatLiteral{500}, Milliseconds}}},
+				{"TUMBLING 1.5 SECONDS BY PROCESSING TIME, BUFFER SIZE 5, WAIT IF FULL",
+					TumblingAST{Tumbling: true, ProcessingTime: true}},
+			} {
+				c := c
+				Convey("Then "+c.window+" should be parsed correctly", func() {
+					p.Buffer = "CREATE STREAM x AS SELECT RSTREAM count(*) FROM c [" + c.window + "]"
+					p.Init()
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					top := p.parseStack.Peek().comp
+					stmt := top.(CreateStreamAsSelectStmt)
+					So(stmt.Select.Relations[0].TumblingAST, ShouldResemble, c.expected)
+					So(stmt.String(), ShouldEqual, p.Buffer)
+				})
+			}
+		})
+
+		Convey("When selecting with SESSION windows", func() {
+			for _, c := range []struct {
+				window   string
+				expected StreamWindowAST
+			}{
+				{"RANGE SESSION 30 SECONDS ON device_id", StreamWindowAST{
+					IntervalAST: IntervalAST{FloatLiteral{30}, Seconds},
+					TumblingAST: TumblingAST{Tumbling: true, SessionKey: RowValue{"", "device_id"}},
+				}},
+				{"RANGE SESSION 500 MILLISECONDS ON c:a.b BY PROCESSING TIME", StreamWindowAST{
+					IntervalAST: IntervalAST{FloatLiteral{500}, Milliseconds},
+					TumblingAST: TumblingAST{Tumbling: true, ProcessingTime: true,
+						SessionKey: RowValue{"c", "a.b"}},
+				}},
+				{"RANGE SESSION 1.5 SECONDS ON str(x) || y, BUFFER SIZE 5, WAIT IF FULL", StreamWindowAST{
+					IntervalAST: IntervalAST{FloatLiteral{1.5}, Seconds},
+					TumblingAST: TumblingAST{Tumbling: true, SessionKey: BinaryOpAST{Concat,
+						FuncAppAST{"str", ExpressionsAST{[]Expression{RowValue{"", "x"}}}, nil},
+						RowValue{"", "y"}}},
+				}},
+			} {
+				c := c
+				Convey("Then "+c.window+" should be parsed correctly", func() {
+					p.Buffer = "CREATE STREAM x AS SELECT RSTREAM count(*) FROM c [" + c.window + "]"
+					p.Init()
+					err := p.Parse()
+					So(err, ShouldBeNil)
+					p.Execute()
+
+					top := p.parseStack.Peek().comp
+					stmt := top.(CreateStreamAsSelectStmt)
+					rel := stmt.Select.Relations[0]
+					So(rel.IntervalAST, ShouldResemble, c.expected.IntervalAST)
+					So(rel.TumblingAST, ShouldResemble, c.expected.TumblingAST)
+					So(stmt.String(), ShouldEqual, p.Buffer)
+				})
+			}
+
+			Convey("Then a window of tuples should be rejected", func() {
+				p.Buffer = "CREATE STREAM x AS SELECT RSTREAM a FROM c [RANGE SESSION 3 TUPLES ON a]"
+				p.Init()
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a TUMBLING window has both options", func() {
+			p.Buffer = "CREATE STREAM x AS SELECT RSTREAM a FROM c [TUMBLING 1 SECONDS BY PROCESSING TIME, WATERMARK DELAY 1 SECONDS]"
+			p.Init()
+
+			Convey("Then parsing the statement should fail", func() {
+				So(p.Parse(), ShouldNotBeNil)
+			})
+		})
 	})
 }
@@ -26,18 +26,22 @@ type SelectStmt struct {
 	EmitterAST
 	ProjectionsAST
 	WindowedFromAST
+	InputSamplingAST
 	FilterAST
 	GroupingAST
 	HavingAST
+	EmitWhenAST
 }
 
 func (s SelectStmt) String() string {
 	str := []string{"SELECT", s.EmitterAST.string()}
 	str = append(str, s.ProjectionsAST.string())
 	str = append(str, s.WindowedFromAST.string())
+	str = append(str, s.InputSamplingAST.string())
 	str = append(str, s.FilterAST.string())
 	str = append(str, s.GroupingAST.string())
 	str = append(str, s.HavingAST.string())
+	str = append(str, s.EmitWhenAST.string())
 
 	st := []string{}
 	for _, s := range str {
@@ -61,30 +65,137 @@ func (s SelectUnionStmt) String() string {
 }
 
 type CreateStreamAsSelectStmt struct {
-	Name   StreamIdentifier
+	Temporary BinaryKeyword
+	Name      StreamIdentifier
+	SchemaAST
 	Select SelectStmt
+	TimestampByAST
+	OnErrorAST
+	TimeoutAST
+	// SourceSinkSpecsAST has parameters given by a WITH clause. Only
+	// "labels" is supported.
+	SourceSinkSpecsAST
 }
 
 func (s CreateStreamAsSelectStmt) String() string {
-	str := []string{"CREATE", "STREAM", string(s.Name), "AS", s.Select.String()}
+	str := []string{"CREATE", "STREAM", string(s.Name)}
+	if t := s.Temporary.string("TEMPORARY", ""); t != "" {
+		str = append(str[:1], append([]string{t}, str[1:]...)...)
+	}
+	if sc := s.SchemaAST.string(); sc != "" {
+		str = append(str, sc)
+	}
+	str = append(str, "AS", s.Select.String())
+	if t := s.TimestampByAST.string(); t != "" {
+		str = append(str, t)
+	}
+	if e := s.OnErrorAST.string(); e != "" {
+		str = append(str, e)
+	}
+	if t := s.TimeoutAST.string(); t != "" {
+		str = append(str, t)
+	}
+	if specs := s.SourceSinkSpecsAST.string("WITH"); specs != "" {
+		str = append(str, specs)
+	}
 	return strings.Join(str, " ")
 }
 
 type CreateStreamAsSelectUnionStmt struct {
-	Name StreamIdentifier
+	Temporary BinaryKeyword
+	Name      StreamIdentifier
+	SchemaAST
 	SelectUnionStmt
+	TimestampByAST
+	OnErrorAST
+	TimeoutAST
+	// SourceSinkSpecsAST has parameters given by a WITH clause. Only
+	// "labels" is supported.
+	SourceSinkSpecsAST
 }
 
 func (s CreateStreamAsSelectUnionStmt) String() string {
-	str := []string{"CREATE", "STREAM", string(s.Name), "AS", s.SelectUnionStmt.String()}
+	str := []string{"CREATE", "STREAM", string(s.Name)}
+	if t := s.Temporary.string("TEMPORARY", ""); t != "" {
+		str = append(str[:1], append([]string{t}, str[1:]...)...)
+	}
+	if sc := s.SchemaAST.string(); sc != "" {
+		str = append(str, sc)
+	}
+	str = append(str, "AS", s.SelectUnionStmt.String())
+	if t := s.TimestampByAST.string(); t != "" {
+		str = append(str, t)
+	}
+	if e := s.OnErrorAST.string(); e != "" {
+		str = append(str, e)
+	}
+	if t := s.TimeoutAST.string(); t != "" {
+		str = append(str, t)
+	}
+	if specs := s.SourceSinkSpecsAST.string("WITH"); specs != "" {
+		str = append(str, specs)
+	}
 	return strings.Join(str, " ")
 }
 
+type CreateStreamRoutesStmt struct {
+	Input StreamIdentifier
+	RoutesAST
+}
+
+func (s CreateStreamRoutesStmt) String() string {
+	str := []string{"CREATE", "STREAM", "ROUTES", "FROM", string(s.Input), s.RoutesAST.string()}
+	return strings.Join(str, " ")
+}
+
+// RoutesAST represents the output streams of a CREATE STREAM ROUTES
+// statement. Either all routes have a condition (except for an ELSE
+// route, which must be the last one) or PartitionKey is set.
+type RoutesAST struct {
+	// PartitionKey is used to distribute tuples over the routes by
+	// its hash value. It is nil if the routes have conditions.
+	PartitionKey Expression
+	Routes       []RouteAST
+}
+
+func (a RoutesAST) string() string {
+	if a.PartitionKey != nil {
+		names := make([]string, len(a.Routes))
+		for i, r := range a.Routes {
+			names[i] = string(r.Name)
+		}
+		return "PARTITIONED BY " + a.PartitionKey.String() +
+			" INTO " + strings.Join(names, ", ")
+	}
+	str := make([]string, len(a.Routes))
+	for i, r := range a.Routes {
+		str[i] = r.string()
+	}
+	return strings.Join(str, " ")
+}
+
+type RouteAST struct {
+	Name StreamIdentifier
+	// Condition is nil for an ELSE route or a partitioned route.
+	Condition Expression
+}
+
+func (a RouteAST) string() string {
+	if a.Condition == nil {
+		return "ELSE INTO " + string(a.Name)
+	}
+	return "WHEN " + a.Condition.String() + " INTO " + string(a.Name)
+}
+
 type CreateSourceStmt struct {
-	Paused BinaryKeyword
-	Name   StreamIdentifier
-	Type   SourceSinkType
+	Temporary BinaryKeyword
+	Paused    BinaryKeyword
+	Name      StreamIdentifier
+	Type      SourceSinkType
+	SchemaAST
+	InstancesAST
 	SourceSinkSpecsAST
+	TimestampByAST
 }
 
 func (s CreateSourceStmt) String() string {
@@ -93,25 +204,45 @@ func (s CreateSourceStmt) String() string {
 	if paused != "" {
 		str = append(str[:1], append([]string{paused}, str[1:]...)...)
 	}
+	if t := s.Temporary.string("TEMPORARY", ""); t != "" {
+		str = append(str[:1], append([]string{t}, str[1:]...)...)
+	}
+	if sc := s.SchemaAST.string(); sc != "" {
+		str = append(str, sc)
+	}
+	if i := s.InstancesAST.string(); i != "" {
+		str = append(str, i)
+	}
 	specs := s.SourceSinkSpecsAST.string("WITH")
 	if specs != "" {
 		str = append(str, specs)
 	}
+	if t := s.TimestampByAST.string(); t != "" {
+		str = append(str, t)
+	}
 	return strings.Join(str, " ")
 }
 
 type CreateSinkStmt struct {
-	Name StreamIdentifier
-	Type SourceSinkType
+	Temporary BinaryKeyword
+	Name      StreamIdentifier
+	Type      SourceSinkType
 	SourceSinkSpecsAST
+	SinkOrderingAST
 }
 
 func (s CreateSinkStmt) String() string {
 	str := []string{"CREATE", "SINK", string(s.Name), "TYPE", string(s.Type)}
+	if t := s.Temporary.string("TEMPORARY", ""); t != "" {
+		str = append(str[:1], append([]string{t}, str[1:]...)...)
+	}
 	specs := s.SourceSinkSpecsAST.string("WITH")
 	if specs != "" {
 		str = append(str, specs)
 	}
+	if o := s.SinkOrderingAST.string(); o != "" {
+		str = append(str, o)
+	}
 	return strings.Join(str, " ")
 }
 
@@ -158,6 +289,16 @@ func (s UpdateSourceStmt) String() string {
 	return strings.Join(str, " ")
 }
 
+// SetConfigStmt sets configuration parameters of the topology. Parameters
+// are stored in core.Context.Config.
+type SetConfigStmt struct {
+	SourceSinkSpecsAST
+}
+
+func (s SetConfigStmt) String() string {
+	return "SET CONFIG " + s.SourceSinkSpecsAST.string("WITH")
+}
+
 type UpdateSinkStmt struct {
 	Name StreamIdentifier
 	SourceSinkSpecsAST
@@ -173,12 +314,27 @@ func (s UpdateSinkStmt) String() string {
 }
 
 type InsertIntoFromStmt struct {
-	Sink  StreamIdentifier
+	Sink StreamIdentifier
+	// ProjectionsAST has nil Projections when tuples are inserted
+	// without being transformed.
+	ProjectionsAST
 	Input StreamIdentifier
+	FilterAST
+	OnErrorAST
 }
 
 func (s InsertIntoFromStmt) String() string {
-	str := []string{"INSERT", "INTO", string(s.Sink), "FROM", string(s.Input)}
+	str := []string{"INSERT", "INTO", string(s.Sink)}
+	if s.Projections != nil {
+		str = append(str, "SELECT", s.ProjectionsAST.string())
+	}
+	str = append(str, "FROM", string(s.Input))
+	if f := s.FilterAST.string(); f != "" {
+		str = append(str, f)
+	}
+	if e := s.OnErrorAST.string(); e != "" {
+		str = append(str, e)
+	}
 	return strings.Join(str, " ")
 }
 
@@ -227,6 +383,24 @@ func (s DropStreamStmt) String() string {
 	return strings.Join(str, " ")
 }
 
+type ResumeStreamStmt struct {
+	Stream StreamIdentifier
+}
+
+func (s ResumeStreamStmt) String() string {
+	str := []string{"RESUME", "STREAM", string(s.Stream)}
+	return strings.Join(str, " ")
+}
+
+type ReplaySinkStmt struct {
+	Sink StreamIdentifier
+}
+
+func (s ReplaySinkStmt) String() string {
+	str := []string{"REPLAY", "SINK", string(s.Sink)}
+	return strings.Join(str, " ")
+}
+
 type DropSinkStmt struct {
 	Sink StreamIdentifier
 }
@@ -304,6 +478,180 @@ func (s SaveStateStmt) String() string {
 	return strings.Join(str, " ")
 }
 
+// CreateTypeStmt defines a named record schema which sources and
+// streams can refer to with a SCHEMA clause.
+type CreateTypeStmt struct {
+	Name   StreamIdentifier
+	Fields []TypeFieldAST
+}
+
+func (s CreateTypeStmt) String() string {
+	fields := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		fields[i] = f.string()
+	}
+	str := []string{"CREATE", "TYPE", string(s.Name), "AS",
+		"(" + strings.Join(fields, ", ") + ")"}
+	return strings.Join(str, " ")
+}
+
+type DropTypeStmt struct {
+	Type StreamIdentifier
+}
+
+func (s DropTypeStmt) String() string {
+	str := []string{"DROP", "TYPE", string(s.Type)}
+	return strings.Join(str, " ")
+}
+
+// CreateTriggerStmt is a statement creating a trigger which runs Action
+// on Schedule. Action is one of TriggerInsertStmt, UpdateStateStmt,
+// SaveStateStmt, UpdateSourceStmt, UpdateSinkStmt, PauseSourceStmt,
+// ResumeSourceStmt, and RewindSourceStmt.
+type CreateTriggerStmt struct {
+	Name     StreamIdentifier
+	Schedule string
+	Action   fmt.Stringer
+}
+
+func (s CreateTriggerStmt) String() string {
+	str := []string{"CREATE", "TRIGGER", string(s.Name), "EVERY",
+		StringLiteral{s.Schedule}.String(), "DO", s.Action.String()}
+	return strings.Join(str, " ")
+}
+
+// TriggerInsertStmt is an action of a trigger which inserts a tuple
+// computed from the projections into a sink.
+type TriggerInsertStmt struct {
+	Sink StreamIdentifier
+	ProjectionsAST
+}
+
+func (s TriggerInsertStmt) String() string {
+	str := []string{"INSERT", "INTO", string(s.Sink), "SELECT", s.ProjectionsAST.string()}
+	return strings.Join(str, " ")
+}
+
+type DropTriggerStmt struct {
+	Trigger StreamIdentifier
+}
+
+func (s DropTriggerStmt) String() string {
+	str := []string{"DROP", "TRIGGER", string(s.Trigger)}
+	return strings.Join(str, " ")
+}
+
+// CreateTemplateStmt is a statement creating a template of statements.
+// Body has statements in which ${param} is replaced with the value of
+// the parameter when the template is instantiated.
+type CreateTemplateStmt struct {
+	Name   StreamIdentifier
+	Params []string
+	Body   string
+}
+
+func (s CreateTemplateStmt) String() string {
+	str := []string{"CREATE", "TEMPLATE", string(s.Name)}
+	if len(s.Params) > 0 {
+		str[2] += "(" + strings.Join(s.Params, ", ") + ")"
+	}
+	str = append(str, "AS", "$$"+s.Body+"$$")
+	return strings.Join(str, " ")
+}
+
+// InstantiateTemplateStmt is a statement running statements of a
+// template with parameters given in the WITH clause. Name is the name of
+// the instance, or an empty string if it isn't given.
+type InstantiateTemplateStmt struct {
+	Template StreamIdentifier
+	Name     StreamIdentifier
+	SourceSinkSpecsAST
+}
+
+func (s InstantiateTemplateStmt) String() string {
+	str := []string{"INSTANTIATE", "TEMPLATE", string(s.Template)}
+	if s.Name != "" {
+		str = append(str, "AS", string(s.Name))
+	}
+	if specs := s.SourceSinkSpecsAST.string("WITH"); specs != "" {
+		str = append(str, specs)
+	}
+	return strings.Join(str, " ")
+}
+
+type DropTemplateStmt struct {
+	Template StreamIdentifier
+}
+
+func (s DropTemplateStmt) String() string {
+	str := []string{"DROP", "TEMPLATE", string(s.Template)}
+	return strings.Join(str, " ")
+}
+
+type DropTemplateInstanceStmt struct {
+	Instance StreamIdentifier
+}
+
+func (s DropTemplateInstanceStmt) String() string {
+	str := []string{"DROP", "TEMPLATE", "INSTANCE", string(s.Instance)}
+	return strings.Join(str, " ")
+}
+
+// ProfileStreamStmt starts the schema profiler of a source or a stream.
+type ProfileStreamStmt struct {
+	Name StreamIdentifier
+	SourceSinkSpecsAST
+}
+
+func (s ProfileStreamStmt) String() string {
+	str := []string{"PROFILE", "STREAM", string(s.Name)}
+	specs := s.SourceSinkSpecsAST.string("WITH")
+	if specs != "" {
+		str = append(str, specs)
+	}
+	return strings.Join(str, " ")
+}
+
+// DescribeStmt returns information of a node including the schema inferred
+// by its profiler.
+type DescribeStmt struct {
+	Name StreamIdentifier
+}
+
+func (s DescribeStmt) String() string {
+	return "DESCRIBE " + string(s.Name)
+}
+
+// CreateAlertStmt is a statement creating an alert which fires for
+// tuples in Input satisfying the filter. When the filter is omitted, all
+// tuples in Input fire the alert.
+type CreateAlertStmt struct {
+	Name  StreamIdentifier
+	Input StreamIdentifier
+	FilterAST
+	SourceSinkSpecsAST
+}
+
+func (s CreateAlertStmt) String() string {
+	str := []string{"CREATE", "ALERT", string(s.Name), "FROM", string(s.Input)}
+	if f := s.FilterAST.string(); f != "" {
+		str = append(str, f)
+	}
+	if specs := s.SourceSinkSpecsAST.string("WITH"); specs != "" {
+		str = append(str, specs)
+	}
+	return strings.Join(str, " ")
+}
+
+type DropAlertStmt struct {
+	Alert StreamIdentifier
+}
+
+func (s DropAlertStmt) String() string {
+	str := []string{"DROP", "ALERT", string(s.Alert)}
+	return strings.Join(str, " ")
+}
+
 type EvalStmt struct {
 	Expr  Expression
 	Input *MapAST
@@ -323,6 +671,10 @@ type EmitterAST struct {
 }
 
 func (a EmitterAST) string() string {
+	if a.EmitterType == UnspecifiedEmitter {
+		// the emitter was omitted
+		return ""
+	}
 	s := a.EmitterType.String()
 	if len(a.EmitterOptions) > 0 {
 		optStrings := make([]string, len(a.EmitterOptions))
@@ -406,6 +758,9 @@ func (a AliasAST) String() string {
 
 type WindowedFromAST struct {
 	Relations []AliasedStreamWindowAST
+	// StateJoins holds the shared states that are joined with the
+	// input relations using JOIN STATE clauses.
+	StateJoins []StateJoinAST
 }
 
 func (a WindowedFromAST) string() string {
@@ -417,7 +772,27 @@ func (a WindowedFromAST) string() string {
 	for _, r := range a.Relations {
 		str = append(str, r.string())
 	}
-	return "FROM " + strings.Join(str, ", ")
+	joins := ""
+	for _, j := range a.StateJoins {
+		joins += " " + j.string()
+	}
+	return "FROM " + strings.Join(str, ", ") + joins
+}
+
+// StateJoinAST represents a JOIN STATE clause that enriches the rows
+// of the input relations with data looked up from a shared state.
+type StateJoinAST struct {
+	State string
+	Alias string
+	On    Expression
+}
+
+func (a StateJoinAST) string() string {
+	str := "JOIN STATE " + a.State
+	if a.Alias != "" {
+		str += " AS " + a.Alias
+	}
+	return str + " ON " + a.On.String()
 }
 
 type AliasedStreamWindowAST struct {
@@ -440,10 +815,25 @@ type StreamWindowAST struct {
 	IntervalAST
 	Capacity int64
 	Shedding SheddingOption
+	TumblingAST
+}
+
+// window returns the window specification without the brackets, such as
+// "RANGE 5 TUPLES", "TUMBLING 60 SECONDS", or "RANGE SESSION 30 SECONDS ON
+// device_id".
+func (a StreamWindowAST) window() string {
+	if !a.Tumbling {
+		return a.IntervalAST.string()
+	}
+	size := a.FloatLiteral.String() + " " + a.Unit.String()
+	if a.SessionKey != nil {
+		return "RANGE SESSION " + size + " ON " + a.SessionKey.String() + a.TumblingAST.string()
+	}
+	return "TUMBLING " + size + a.TumblingAST.string()
 }
 
 func (a StreamWindowAST) string() string {
-	interval := a.IntervalAST.string()
+	interval := a.window()
 	capacity := ""
 	if a.Capacity != UnspecifiedCapacity {
 		capacity = fmt.Sprintf(", BUFFER SIZE %d", a.Capacity)
@@ -452,23 +842,65 @@ func (a StreamWindowAST) string() string {
 	if a.Shedding != UnspecifiedSheddingOption {
 		shedding = fmt.Sprintf(", %s IF FULL", a.Shedding.String())
 	}
-	suffix := "[" + interval + capacity + shedding + "]"
+	suffix := " [" + interval + capacity + shedding + "]"
+	if a.Unit == UnspecifiedIntervalUnit {
+		// the window was omitted
+		suffix = ""
+	}
 
 	switch a.Stream.Type {
 	case ActualStream:
-		return a.Stream.Name + " " + suffix
+		return a.Stream.Name + suffix
 
 	case UDSFStream:
 		ps := []string{}
 		for _, p := range a.Stream.Params {
 			ps = append(ps, p.String())
 		}
-		return a.Stream.Name + "(" + strings.Join(ps, ", ") + ") " + suffix
+		return a.Stream.Name + "(" + strings.Join(ps, ", ") + ")" + suffix
+
+	case UnnestStream:
+		return "UNNEST(" + a.Stream.Name + ", " + a.Stream.Params[0].String() + ")" + suffix
+
+	case SystemStream:
+		return "system." + a.Stream.Name + suffix
 	}
 
 	return "UnknownStreamType"
 }
 
+// TumblingAST represents the options of a TUMBLING window. A tumbling
+// window covers a fixed range of time (or a fixed number of tuples) and
+// doesn't overlap the next window. The result of the statement is computed
+// once when the window closes. A SESSION window is also represented by
+// TumblingAST having SessionKey because it's computed in the same way
+// except that the range of a window depends on input tuples.
+type TumblingAST struct {
+	// Tumbling is false when the window is a RANGE window.
+	Tumbling bool
+	// ProcessingTime is true when tuples are assigned to windows by the
+	// time they arrive instead of their timestamps.
+	ProcessingTime bool
+	// Delay is the delay of the watermark of an event time window, i.e.
+	// how long a window waits for out-of-order tuples after it ends. Its
+	// Unit is UnspecifiedIntervalUnit when the delay is omitted.
+	Delay IntervalAST
+	// SessionKey is the key of a SESSION window. Tuples having the same
+	// key belong to the same session until no tuple having the key
+	// arrives for the length of the window. It's nil for other windows.
+	SessionKey Expression
+}
+
+func (a TumblingAST) string() string {
+	if a.ProcessingTime {
+		return " BY PROCESSING TIME"
+	}
+	if a.Delay.Unit != UnspecifiedIntervalUnit {
+		return ", WATERMARK DELAY " + a.Delay.FloatLiteral.String() + " " + a.Delay.Unit.String()
+	}
+	return ""
+}
+
 type IntervalAST struct {
 	FloatLiteral
 	Unit IntervalUnit
@@ -491,10 +923,16 @@ func (a FilterAST) string() string {
 
 type GroupingAST struct {
 	GroupList []Expression
+	// GroupingSets holds the sets given in a GROUPING SETS or ROLLUP
+	// clause as lists of indexes into GroupList. It is nil for an
+	// ordinary GROUP BY clause.
+	GroupingSets [][]int
+	// Rollup is true if the grouping sets were given as ROLLUP(...).
+	Rollup bool
 }
 
 func (a GroupingAST) string() string {
-	if len(a.GroupList) == 0 {
+	if len(a.GroupList) == 0 && a.GroupingSets == nil {
 		return ""
 	}
 
@@ -502,6 +940,19 @@ func (a GroupingAST) string() string {
 	for _, e := range a.GroupList {
 		str = append(str, e.String())
 	}
+	if a.Rollup {
+		return "GROUP BY ROLLUP(" + strings.Join(str, ", ") + ")"
+	} else if a.GroupingSets != nil {
+		sets := make([]string, len(a.GroupingSets))
+		for i, set := range a.GroupingSets {
+			exprs := make([]string, len(set))
+			for j, idx := range set {
+				exprs[j] = str[idx]
+			}
+			sets[i] = "(" + strings.Join(exprs, ", ") + ")"
+		}
+		return "GROUP BY GROUPING SETS (" + strings.Join(sets, ", ") + ")"
+	}
 	return "GROUP BY " + strings.Join(str, ", ")
 }
 
@@ -516,6 +967,153 @@ func (a HavingAST) string() string {
 	return "HAVING " + a.Having.String()
 }
 
+// InputSamplingAST represents a READ clause which samples the input
+// tuples of a statement before they are processed.
+type InputSamplingAST struct {
+	// InputSampling has UnspecifiedSamplingType as its Type if there
+	// is no READ clause.
+	InputSampling EmitterSampling
+}
+
+func (a InputSamplingAST) string() string {
+	if a.InputSampling.Type == UnspecifiedSamplingType {
+		return ""
+	}
+	return "READ " + a.InputSampling.string()
+}
+
+type EmitWhenAST struct {
+	EmitWhen Expression
+}
+
+func (a EmitWhenAST) string() string {
+	if a.EmitWhen == nil {
+		return ""
+	}
+	return "EMIT WHEN " + a.EmitWhen.String()
+}
+
+// OnErrorAST represents an ON ERROR clause which controls what happens
+// when processing a tuple fails.
+type OnErrorAST struct {
+	ErrorPolicy ErrorPolicy
+	// Retries is the maximum number of retries of RETRY, or
+	// UnspecifiedRetries if it isn't given.
+	Retries int64
+}
+
+const UnspecifiedRetries int64 = -1
+
+func (a OnErrorAST) string() string {
+	switch a.ErrorPolicy {
+	case UnspecifiedErrorPolicy:
+		return ""
+	case RetryOnError:
+		if a.Retries != UnspecifiedRetries {
+			return fmt.Sprintf("ON ERROR RETRY %d TIMES", a.Retries)
+		}
+	}
+	return "ON ERROR " + a.ErrorPolicy.String()
+}
+
+// TimeoutAST represents a TIMEOUT clause which limits the time to process
+// a tuple.
+type TimeoutAST struct {
+	// Timeout has UnspecifiedIntervalUnit as its Unit if there is no
+	// TIMEOUT clause.
+	Timeout IntervalAST
+}
+
+func (a TimeoutAST) string() string {
+	if a.Timeout.Unit == UnspecifiedIntervalUnit {
+		return ""
+	}
+	return "TIMEOUT " + a.Timeout.FloatLiteral.String() + " " + a.Timeout.Unit.String()
+}
+
+// TypeFieldAST represents a field of a CREATE TYPE statement.
+type TypeFieldAST struct {
+	Name string
+	Type Type
+}
+
+func (a TypeFieldAST) string() string {
+	return a.Name + " " + a.Type.String()
+}
+
+// SchemaAST represents a SCHEMA clause which refers to a type created
+// by CREATE TYPE.
+type SchemaAST struct {
+	// Schema is the name of the type, or an empty string if there is
+	// no SCHEMA clause.
+	Schema string
+}
+
+func (a SchemaAST) string() string {
+	if a.Schema == "" {
+		return ""
+	}
+	return "SCHEMA " + a.Schema
+}
+
+// InstancesAST represents an INSTANCES clause which specifies the number
+// of readers of a source running in parallel.
+type InstancesAST struct {
+	// Instances is UnspecifiedInstances if there is no INSTANCES clause.
+	Instances int64
+}
+
+const UnspecifiedInstances int64 = -1
+
+func (a InstancesAST) string() string {
+	if a.Instances == UnspecifiedInstances {
+		return ""
+	}
+	return fmt.Sprintf("INSTANCES %d", a.Instances)
+}
+
+// SinkOrderingAST represents an ORDER BY clause of a sink which specifies
+// the order in which tuples from multiple inputs are written to the sink.
+type SinkOrderingAST struct {
+	// Ordering is UnspecifiedSinkOrdering if there is no ORDER BY clause.
+	Ordering SinkOrdering
+	// BufferSize is UnspecifiedCapacity if there is no BUFFER SIZE clause.
+	BufferSize int64
+}
+
+func (a SinkOrderingAST) string() string {
+	if a.Ordering == UnspecifiedSinkOrdering {
+		return ""
+	}
+	s := "ORDER BY " + a.Ordering.String()
+	if a.BufferSize != UnspecifiedCapacity {
+		s += fmt.Sprintf(" BUFFER SIZE %d", a.BufferSize)
+	}
+	return s
+}
+
+// TimestampByAST represents a TIMESTAMP BY clause which specifies the
+// field having the timestamp of a tuple.
+type TimestampByAST struct {
+	// Field is the path to the field, or an empty string if there is
+	// no TIMESTAMP BY clause.
+	Field string
+	// Format is the format of the field, or an empty string if it
+	// isn't given.
+	Format string
+}
+
+func (a TimestampByAST) string() string {
+	if a.Field == "" {
+		return ""
+	}
+	str := "TIMESTAMP BY " + a.Field
+	if a.Format != "" {
+		str += " FORMAT " + StringLiteral{a.Format}.String()
+	}
+	return str
+}
+
 type SourceSinkSpecsAST struct {
 	Params []SourceSinkParamAST
 }
@@ -700,6 +1298,10 @@ func (u TypeCastAST) String() string {
 		return rm.String() + "::" + u.Target.String()
 	}
 
+	if rm, ok := u.Expr.(RowMetadata); ok {
+		return rm.String() + "::" + u.Target.String()
+	}
+
 	return "CAST(" + u.Expr.String() + " AS " + u.Target.String() + ")"
 }
 
@@ -1174,6 +1776,36 @@ func NewRowMeta(s string, t MetaInformation) RowMeta {
 	return RowMeta{components[0], t}
 }
 
+// RowMetadata is a reference to a value in core.Tuple.Metadata of the
+// input tuple, written as meta("key") or rel:meta("key").
+type RowMetadata struct {
+	Relation string
+	Key      string
+}
+
+func (rm RowMetadata) ReferencedRelations() map[string]bool {
+	return map[string]bool{rm.Relation: true}
+}
+
+func (rm RowMetadata) RenameReferencedRelation(from, to string) Expression {
+	if rm.Relation == from {
+		return RowMetadata{to, rm.Key}
+	}
+	return rm
+}
+
+func (rm RowMetadata) Foldable() bool {
+	return false
+}
+
+func (rm RowMetadata) String() string {
+	s := "meta(" + StringLiteral{rm.Key}.String() + ")"
+	if rm.Relation != "" {
+		return rm.Relation + ":" + s
+	}
+	return s
+}
+
 type Raw struct {
 	Expr string
 }
@@ -1396,6 +2028,13 @@ const (
 	UnknownStreamType StreamType = iota
 	ActualStream
 	UDSFStream
+	// UnnestStream is a stream created by UNNEST(s, path). Its Name is the
+	// name of the input stream and its Params only has the RowValue of the
+	// array.
+	UnnestStream
+	// SystemStream is a virtual stream referred to as system.name. Its Name
+	// doesn't have the "system." prefix.
+	SystemStream
 )
 
 func (st StreamType) String() string {
@@ -1405,6 +2044,10 @@ func (st StreamType) String() string {
 		s = "ActualStream"
 	case UDSFStream:
 		s = "UDSFStream"
+	case UnnestStream:
+		s = "UnnestStream"
+	case SystemStream:
+		s = "SystemStream"
 	}
 	return s
 }
@@ -1437,6 +2080,7 @@ const (
 	UnknownMeta MetaInformation = iota
 	TimestampMeta
 	NowMeta
+	MetadataMeta
 )
 
 func (m MetaInformation) String() string {
@@ -1446,6 +2090,8 @@ func (m MetaInformation) String() string {
 		s = "TS"
 	case NowMeta:
 		s = "NOW"
+	case MetadataMeta:
+		s = "META"
 	}
 	return s
 }
@@ -1457,6 +2103,8 @@ func (m MetaInformation) string() string {
 		s = "ts()"
 	case NowMeta:
 		s = "now()"
+	case MetadataMeta:
+		s = "meta()"
 	}
 	return s
 }
@@ -1490,6 +2138,31 @@ func (k BinaryKeyword) string(yes, no string) string {
 	return ""
 }
 
+type ErrorPolicy int
+
+const (
+	UnspecifiedErrorPolicy ErrorPolicy = iota
+	DropOnError
+	StopOnError
+	RetryOnError
+	DLQOnError
+)
+
+func (e ErrorPolicy) String() string {
+	s := "UnspecifiedErrorPolicy"
+	switch e {
+	case DropOnError:
+		s = "DROP"
+	case StopOnError:
+		s = "STOP"
+	case RetryOnError:
+		s = "RETRY"
+	case DLQOnError:
+		s = "DLQ"
+	}
+	return s
+}
+
 type SheddingOption int
 
 const (
@@ -1512,6 +2185,28 @@ func (t SheddingOption) String() string {
 	return s
 }
 
+type SinkOrdering int
+
+const (
+	UnspecifiedSinkOrdering SinkOrdering = iota
+	ArrivalOrder
+	TimestampOrder
+	RoundRobinOrder
+)
+
+func (o SinkOrdering) String() string {
+	s := "UnspecifiedSinkOrdering"
+	switch o {
+	case ArrivalOrder:
+		s = "ARRIVAL"
+	case TimestampOrder:
+		s = "TIMESTAMP"
+	case RoundRobinOrder:
+		s = "ROUND ROBIN"
+	}
+	return s
+}
+
 type Type int
 
 const (
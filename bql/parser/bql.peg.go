@@ -22,10 +22,19 @@ const (
 	ruleSinkStmt
 	ruleStateStmt
 	ruleStreamStmt
+	ruleTypeStmt
+	ruleTriggerStmt
+	ruleAlertStmt
+	ruleTemplateStmt
 	ruleSelectStmt
 	ruleSelectUnionStmt
 	ruleCreateStreamAsSelectStmt
 	ruleCreateStreamAsSelectUnionStmt
+	ruleCreateStreamRoutesStmt
+	rulePartitionedRoutes
+	ruleConditionalRoutes
+	ruleConditionalRoute
+	ruleElseRoute
 	ruleCreateSourceStmt
 	ruleCreateSinkStmt
 	ruleCreateStateStmt
@@ -33,17 +42,38 @@ const (
 	ruleUpdateSourceStmt
 	ruleUpdateSinkStmt
 	ruleInsertIntoFromStmt
+	ruleEdgeProjectionsOpt
 	rulePauseSourceStmt
 	ruleResumeSourceStmt
 	ruleRewindSourceStmt
 	ruleDropSourceStmt
 	ruleDropStreamStmt
+	ruleResumeStreamStmt
 	ruleDropSinkStmt
+	ruleProfileStreamStmt
+	ruleReplaySinkStmt
 	ruleDropStateStmt
+	ruleCreateTypeStmt
+	ruleTypeField
+	ruleDropTypeStmt
+	ruleCreateTriggerStmt
+	ruleTriggerAction
+	ruleTriggerInsertStmt
+	ruleDropTriggerStmt
+	ruleCreateAlertStmt
+	ruleDropAlertStmt
+	ruleCreateTemplateStmt
+	ruleTemplateBody
+	ruleInstantiateTemplateStmt
+	ruleTemplateInstanceOpt
+	ruleDropTemplateInstanceStmt
+	ruleDropTemplateStmt
 	ruleLoadStateStmt
 	ruleLoadStateOrCreateStmt
 	ruleSaveStateStmt
+	ruleSetConfigStmt
 	ruleEvalStmt
+	ruleDescribeStmt
 	ruleEmitter
 	ruleEmitterOptions
 	ruleEmitterOptionCombinations
@@ -58,6 +88,7 @@ const (
 	ruleProjection
 	ruleAliasExpression
 	ruleWindowedFrom
+	ruleInputSampling
 	ruleInterval
 	ruleTimeInterval
 	ruleTuplesInterval
@@ -65,17 +96,41 @@ const (
 	ruleFilter
 	ruleGrouping
 	ruleGroupList
+	ruleGroupingSets
+	ruleRollup
+	ruleGroupingSetList
+	ruleGroupingSet
 	ruleHaving
+	ruleEmitWhen
+	ruleStateJoin
 	ruleRelationLike
 	ruleAliasedStreamWindow
 	ruleStreamWindow
+	ruleWindowSpec
+	ruleRangeWindow
+	ruleTumblingWindow
+	ruleProcessingTime
+	ruleSessionWindow
+	ruleWatermarkDelay
 	ruleStreamLike
+	ruleUnnestStream
+	ruleSystemStream
 	ruleUDSFFuncApp
 	ruleCapacitySpecOpt
 	ruleSheddingSpecOpt
 	ruleSheddingOption
+	ruleSchemaOpt
+	ruleInstancesOpt
+	ruleSinkOrderingOpt
+	ruleSinkOrdering
+	ruleTimestampByOpt
+	ruleTimestampField
+	ruleOnErrorOpt
+	ruleTimeoutOpt
+	ruleErrorPolicy
 	ruleSourceSinkSpecs
 	ruleUpdateSourceSinkSpecs
+	ruleConfigSpecs
 	ruleSetOptSpecs
 	ruleStateTagOpt
 	ruleSourceSinkParam
@@ -85,6 +140,7 @@ const (
 	ruleParamMapExpr
 	ruleParamKeyValuePair
 	rulePausedOpt
+	ruleTemporaryOpt
 	ruleExpressionOrWildcard
 	ruleExpression
 	ruleorExpr
@@ -124,6 +180,7 @@ const (
 	ruleStream
 	ruleRowMeta
 	ruleRowTimestamp
+	ruleRowMetadata
 	ruleRowValue
 	ruleNumericLiteral
 	ruleNonNegativeNumericLiteral
@@ -142,14 +199,22 @@ const (
 	ruleTUPLES
 	ruleSECONDS
 	ruleMILLISECONDS
+	ruleDropOnError
+	ruleStopOnError
+	ruleDLQOnError
+	ruleRetryOnError
 	ruleWait
 	ruleDropOldest
 	ruleDropNewest
+	ruleArrivalOrder
+	ruleTimestampOrder
+	ruleRoundRobinOrder
 	ruleStreamIdentifier
 	ruleSourceSinkType
 	ruleSourceSinkParamKey
 	rulePaused
 	ruleUnpaused
+	ruleTemporary
 	ruleAscending
 	ruleDescending
 	ruleType
@@ -339,6 +404,64 @@ const (
 	ruleAction133
 	ruleAction134
 	ruleAction135
+	ruleAction136
+	ruleAction137
+	ruleAction138
+	ruleAction139
+	ruleAction140
+	ruleAction141
+	ruleAction142
+	ruleAction143
+	ruleAction144
+	ruleAction145
+	ruleAction146
+	ruleAction147
+	ruleAction148
+	ruleAction149
+	ruleAction150
+	ruleAction151
+	ruleAction152
+	ruleAction153
+	ruleAction154
+	ruleAction155
+	ruleAction156
+	ruleAction157
+	ruleAction158
+	ruleAction159
+	ruleAction160
+	ruleAction161
+	ruleAction162
+	ruleAction163
+	ruleAction164
+	ruleAction165
+	ruleAction166
+	ruleAction167
+	ruleAction168
+	ruleAction169
+	ruleAction170
+	ruleAction171
+	ruleAction172
+	ruleAction173
+	ruleAction174
+	ruleAction175
+	ruleAction176
+	ruleAction177
+	ruleAction178
+	ruleAction179
+	ruleAction180
+	ruleAction181
+	ruleAction182
+	ruleAction183
+	ruleAction184
+	ruleAction185
+	ruleAction186
+	ruleAction187
+	ruleAction188
+	ruleAction189
+	ruleAction190
+	ruleAction191
+	ruleAction192
+	ruleAction193
 )
 
 var rul3s = [...]string{
@@ -351,10 +474,19 @@ var rul3s = [...]string{
 	"SinkStmt",
 	"StateStmt",
 	"StreamStmt",
+	"TypeStmt",
+	"TriggerStmt",
+	"AlertStmt",
+	"TemplateStmt",
 	"SelectStmt",
 	"SelectUnionStmt",
 	"CreateStreamAsSelectStmt",
 	"CreateStreamAsSelectUnionStmt",
+	"CreateStreamRoutesStmt",
+	"PartitionedRoutes",
+	"ConditionalRoutes",
+	"ConditionalRoute",
+	"ElseRoute",
 	"CreateSourceStmt",
 	"CreateSinkStmt",
 	"CreateStateStmt",
@@ -362,17 +494,38 @@ var rul3s = [...]string{
 	"UpdateSourceStmt",
 	"UpdateSinkStmt",
 	"InsertIntoFromStmt",
+	"EdgeProjectionsOpt",
 	"PauseSourceStmt",
 	"ResumeSourceStmt",
 	"RewindSourceStmt",
 	"DropSourceStmt",
 	"DropStreamStmt",
+	"ResumeStreamStmt",
 	"DropSinkStmt",
+	"ProfileStreamStmt",
+	"ReplaySinkStmt",
 	"DropStateStmt",
+	"CreateTypeStmt",
+	"TypeField",
+	"DropTypeStmt",
+	"CreateTriggerStmt",
+	"TriggerAction",
+	"TriggerInsertStmt",
+	"DropTriggerStmt",
+	"CreateAlertStmt",
+	"DropAlertStmt",
+	"CreateTemplateStmt",
+	"TemplateBody",
+	"InstantiateTemplateStmt",
+	"TemplateInstanceOpt",
+	"DropTemplateInstanceStmt",
+	"DropTemplateStmt",
 	"LoadStateStmt",
 	"LoadStateOrCreateStmt",
 	"SaveStateStmt",
+	"SetConfigStmt",
 	"EvalStmt",
+	"DescribeStmt",
 	"Emitter",
 	"EmitterOptions",
 	"EmitterOptionCombinations",
@@ -387,6 +540,7 @@ var rul3s = [...]string{
 	"Projection",
 	"AliasExpression",
 	"WindowedFrom",
+	"InputSampling",
 	"Interval",
 	"TimeInterval",
 	"TuplesInterval",
@@ -394,17 +548,41 @@ var rul3s = [...]string{
 	"Filter",
 	"Grouping",
 	"GroupList",
+	"GroupingSets",
+	"Rollup",
+	"GroupingSetList",
+	"GroupingSet",
 	"Having",
+	"EmitWhen",
+	"StateJoin",
 	"RelationLike",
 	"AliasedStreamWindow",
 	"StreamWindow",
+	"WindowSpec",
+	"RangeWindow",
+	"TumblingWindow",
+	"ProcessingTime",
+	"SessionWindow",
+	"WatermarkDelay",
 	"StreamLike",
+	"UnnestStream",
+	"SystemStream",
 	"UDSFFuncApp",
 	"CapacitySpecOpt",
 	"SheddingSpecOpt",
 	"SheddingOption",
+	"SchemaOpt",
+	"InstancesOpt",
+	"SinkOrderingOpt",
+	"SinkOrdering",
+	"TimestampByOpt",
+	"TimestampField",
+	"OnErrorOpt",
+	"TimeoutOpt",
+	"ErrorPolicy",
 	"SourceSinkSpecs",
 	"UpdateSourceSinkSpecs",
+	"ConfigSpecs",
 	"SetOptSpecs",
 	"StateTagOpt",
 	"SourceSinkParam",
@@ -414,6 +592,7 @@ var rul3s = [...]string{
 	"ParamMapExpr",
 	"ParamKeyValuePair",
 	"PausedOpt",
+	"TemporaryOpt",
 	"ExpressionOrWildcard",
 	"Expression",
 	"orExpr",
@@ -453,6 +632,7 @@ var rul3s = [...]string{
 	"Stream",
 	"RowMeta",
 	"RowTimestamp",
+	"RowMetadata",
 	"RowValue",
 	"NumericLiteral",
 	"NonNegativeNumericLiteral",
@@ -471,14 +651,22 @@ var rul3s = [...]string{
 	"TUPLES",
 	"SECONDS",
 	"MILLISECONDS",
+	"DropOnError",
+	"StopOnError",
+	"DLQOnError",
+	"RetryOnError",
 	"Wait",
 	"DropOldest",
 	"DropNewest",
+	"ArrivalOrder",
+	"TimestampOrder",
+	"RoundRobinOrder",
 	"StreamIdentifier",
 	"SourceSinkType",
 	"SourceSinkParamKey",
 	"Paused",
 	"Unpaused",
+	"Temporary",
 	"Ascending",
 	"Descending",
 	"Type",
@@ -668,6 +856,64 @@ var rul3s = [...]string{
 	"Action133",
 	"Action134",
 	"Action135",
+	"Action136",
+	"Action137",
+	"Action138",
+	"Action139",
+	"Action140",
+	"Action141",
+	"Action142",
+	"Action143",
+	"Action144",
+	"Action145",
+	"Action146",
+	"Action147",
+	"Action148",
+	"Action149",
+	"Action150",
+	"Action151",
+	"Action152",
+	"Action153",
+	"Action154",
+	"Action155",
+	"Action156",
+	"Action157",
+	"Action158",
+	"Action159",
+	"Action160",
+	"Action161",
+	"Action162",
+	"Action163",
+	"Action164",
+	"Action165",
+	"Action166",
+	"Action167",
+	"Action168",
+	"Action169",
+	"Action170",
+	"Action171",
+	"Action172",
+	"Action173",
+	"Action174",
+	"Action175",
+	"Action176",
+	"Action177",
+	"Action178",
+	"Action179",
+	"Action180",
+	"Action181",
+	"Action182",
+	"Action183",
+	"Action184",
+	"Action185",
+	"Action186",
+	"Action187",
+	"Action188",
+	"Action189",
+	"Action190",
+	"Action191",
+	"Action192",
+	"Action193",
 }
 
 type token32 struct {
@@ -784,7 +1030,7 @@ type bqlPegBackend struct {
 
 	Buffer string
 	buffer []rune
-	rules  [326]func() bool
+	rules  [449]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
@@ -902,547 +1148,787 @@ func (p *bqlPegBackend) Execute() {
 
 		case ruleAction6:
 
-			p.AssembleCreateSource()
+			p.AssembleCreateStreamRoutes()
 
 		case ruleAction7:
 
-			p.AssembleCreateSink()
+			p.AssemblePartitionedRoutes(begin, end)
 
 		case ruleAction8:
 
-			p.AssembleCreateState()
+			p.AssembleConditionalRoutes(begin, end)
 
 		case ruleAction9:
 
-			p.AssembleUpdateState()
+			p.AssembleConditionalRoute()
 
 		case ruleAction10:
 
-			p.AssembleUpdateSource()
+			p.AssembleElseRoute()
 
 		case ruleAction11:
 
-			p.AssembleUpdateSink()
+			p.AssembleCreateSource()
 
 		case ruleAction12:
 
-			p.AssembleInsertIntoFrom()
+			p.AssembleCreateSink()
 
 		case ruleAction13:
 
-			p.AssemblePauseSource()
+			p.AssembleCreateState()
 
 		case ruleAction14:
 
-			p.AssembleResumeSource()
+			p.AssembleUpdateState()
 
 		case ruleAction15:
 
-			p.AssembleRewindSource()
+			p.AssembleUpdateSource()
 
 		case ruleAction16:
 
-			p.AssembleDropSource()
+			p.AssembleUpdateSink()
 
 		case ruleAction17:
 
-			p.AssembleDropStream()
+			p.AssembleInsertIntoFrom()
 
 		case ruleAction18:
 
-			p.AssembleDropSink()
+			p.EnsureProjections(begin, end)
 
 		case ruleAction19:
 
-			p.AssembleDropState()
+			p.AssemblePauseSource()
 
 		case ruleAction20:
 
-			p.AssembleLoadState()
+			p.AssembleResumeSource()
 
 		case ruleAction21:
 
-			p.AssembleLoadStateOrCreate()
+			p.AssembleRewindSource()
 
 		case ruleAction22:
 
-			p.AssembleSaveState()
+			p.AssembleDropSource()
 
 		case ruleAction23:
 
-			p.AssembleEval(begin, end)
+			p.AssembleDropStream()
 
 		case ruleAction24:
 
-			p.AssembleEmitter()
+			p.AssembleResumeStream()
 
 		case ruleAction25:
 
-			p.AssembleEmitterOptions(begin, end)
+			p.AssembleDropSink()
 
 		case ruleAction26:
 
-			p.AssembleEmitterLimit()
+			p.AssembleProfileStream()
 
 		case ruleAction27:
 
-			p.AssembleEmitterSampling(CountBasedSampling, 1)
+			p.AssembleReplaySink()
 
 		case ruleAction28:
 
-			p.AssembleEmitterSampling(RandomizedSampling, 1)
+			p.AssembleDropState()
 
 		case ruleAction29:
 
-			p.AssembleEmitterSampling(TimeBasedSampling, 1)
+			p.AssembleCreateType(begin, end)
 
 		case ruleAction30:
 
-			p.AssembleEmitterSampling(TimeBasedSampling, 0.001)
+			p.AssembleTypeField()
 
 		case ruleAction31:
 
-			p.AssembleProjections(begin, end)
+			p.AssembleDropType()
 
 		case ruleAction32:
 
-			p.AssembleAlias()
+			p.AssembleCreateTrigger()
 
 		case ruleAction33:
 
-			// This is *always* executed, even if there is no
-			// FROM clause present in the statement.
-			p.AssembleWindowedFrom(begin, end)
+			p.AssembleTriggerInsert()
 
 		case ruleAction34:
 
-			p.AssembleInterval()
+			p.AssembleDropTrigger()
 
 		case ruleAction35:
 
-			p.AssembleInterval()
+			p.AssembleCreateAlert()
 
 		case ruleAction36:
 
-			// This is *always* executed, even if there is no
-			// WHERE clause present in the statement.
-			p.AssembleFilter(begin, end)
+			p.AssembleDropAlert()
 
 		case ruleAction37:
 
-			// This is *always* executed, even if there is no
-			// GROUP BY clause present in the statement.
-			p.AssembleGrouping(begin, end)
+			p.AssembleCreateTemplate(begin, end)
 
 		case ruleAction38:
 
-			// This is *always* executed, even if there is no
-			// HAVING clause present in the statement.
-			p.AssembleHaving(begin, end)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, StringLiteral{substr})
 
 		case ruleAction39:
 
-			p.EnsureAliasedStreamWindow()
+			p.AssembleInstantiateTemplate()
 
 		case ruleAction40:
 
-			p.AssembleAliasedStreamWindow()
+			p.EnsureIdentifier(begin, end)
 
 		case ruleAction41:
 
-			p.AssembleStreamWindow()
+			p.AssembleDropTemplateInstance()
 
 		case ruleAction42:
 
-			p.AssembleUDSFFuncApp()
+			p.AssembleDropTemplate()
 
 		case ruleAction43:
 
-			p.EnsureCapacitySpec(begin, end)
+			p.AssembleLoadState()
 
 		case ruleAction44:
 
-			p.EnsureSheddingSpec(begin, end)
+			p.AssembleLoadStateOrCreate()
 
 		case ruleAction45:
 
-			p.AssembleSourceSinkSpecs(begin, end)
+			p.AssembleSaveState()
 
 		case ruleAction46:
 
-			p.AssembleSourceSinkSpecs(begin, end)
+			p.AssembleSetConfig()
 
 		case ruleAction47:
 
-			p.AssembleSourceSinkSpecs(begin, end)
+			p.AssembleEval(begin, end)
 
 		case ruleAction48:
 
-			p.EnsureIdentifier(begin, end)
+			p.AssembleDescribe()
 
 		case ruleAction49:
 
-			p.AssembleSourceSinkParam()
+			p.AssembleEmitter()
 
 		case ruleAction50:
 
-			p.AssembleExpressions(begin, end)
-			p.AssembleArray()
+			p.AssembleImplicitEmitter(begin)
 
 		case ruleAction51:
 
-			p.AssembleMap(begin, end)
+			p.AssembleEmitterOptions(begin, end)
 
 		case ruleAction52:
 
-			p.AssembleKeyValuePair()
+			p.AssembleEmitterLimit()
 
 		case ruleAction53:
 
-			p.EnsureKeywordPresent(begin, end)
+			p.AssembleEmitterSampling(CountBasedSampling, 1)
 
 		case ruleAction54:
 
-			p.AssembleBinaryOperation(begin, end)
+			p.AssembleEmitterSampling(RandomizedSampling, 1)
 
 		case ruleAction55:
 
-			p.AssembleBinaryOperation(begin, end)
+			p.AssembleEmitterSampling(TimeBasedSampling, 1)
 
 		case ruleAction56:
 
-			p.AssembleUnaryPrefixOperation(begin, end)
+			p.AssembleEmitterSampling(TimeBasedSampling, 0.001)
 
 		case ruleAction57:
 
-			p.AssembleBinaryOperation(begin, end)
+			p.AssembleProjections(begin, end)
 
 		case ruleAction58:
 
-			p.AssembleBinaryOperation(begin, end)
+			p.AssembleAlias()
 
 		case ruleAction59:
 
-			p.AssembleBinaryOperation(begin, end)
+			// This is *always* executed, even if there is no
+			// FROM clause present in the statement.
+			p.AssembleWindowedFrom(begin, end)
 
 		case ruleAction60:
 
-			p.AssembleBinaryOperation(begin, end)
+			// This is *always* executed, even if there is no
+			// READ clause present in the statement.
+			p.AssembleInputSampling(begin, end)
 
 		case ruleAction61:
 
-			p.AssembleBinaryOperation(begin, end)
+			p.AssembleInterval()
 
 		case ruleAction62:
 
-			p.AssembleUnaryPrefixOperation(begin, end)
+			p.AssembleInterval()
 
 		case ruleAction63:
 
-			p.AssembleTypeCast(begin, end)
+			// This is *always* executed, even if there is no
+			// WHERE clause present in the statement.
+			p.AssembleFilter(begin, end)
 
 		case ruleAction64:
 
-			p.AssembleTypeCast(begin, end)
+			// This is *always* executed, even if there is no
+			// GROUP BY clause present in the statement.
+			p.AssembleGrouping(begin, end)
 
 		case ruleAction65:
 
-			p.AssembleFuncAppSelector()
+			p.AssembleRollup(begin, end)
 
 		case ruleAction66:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewRaw(substr))
+			p.AssembleGroupingSets(begin, end)
 
 		case ruleAction67:
 
-			p.AssembleFuncApp()
+			p.AssembleExpressions(begin, end)
 
 		case ruleAction68:
 
-			p.AssembleExpressions(begin, end)
-			p.AssembleFuncApp()
+			// This is *always* executed, even if there is no
+			// HAVING clause present in the statement.
+			p.AssembleHaving(begin, end)
 
 		case ruleAction69:
 
-			p.AssembleExpressions(begin, end)
+			// This is *always* executed, even if there is no
+			// EMIT WHEN clause present in the statement.
+			p.AssembleEmitWhen(begin, end)
 
 		case ruleAction70:
 
-			p.AssembleExpressions(begin, end)
+			p.AssembleStateJoin(begin, end)
 
 		case ruleAction71:
 
-			p.AssembleSortedExpression()
+			p.EnsureAliasedStreamWindow()
 
 		case ruleAction72:
 
-			p.EnsureKeywordPresent(begin, end)
+			p.AssembleAliasedStreamWindow()
 
 		case ruleAction73:
 
-			p.AssembleExpressions(begin, end)
-			p.AssembleArray()
+			p.AssembleStreamWindow()
 
 		case ruleAction74:
 
-			p.AssembleMap(begin, end)
+			p.AssembleImplicitStreamWindow()
 
 		case ruleAction75:
 
-			p.AssembleKeyValuePair()
+			p.PushComponent(end, end, TumblingAST{})
 
 		case ruleAction76:
 
-			p.AssembleConditionCase(begin, end)
+			p.AssembleTumblingWindow(begin, end)
 
 		case ruleAction77:
 
-			p.AssembleExpressionCase(begin, end)
+			p.PushComponent(begin, end, TumblingAST{Tumbling: true, ProcessingTime: true})
 
 		case ruleAction78:
 
-			p.AssembleWhenThenPair()
+			p.AssembleSessionWindow(begin, end)
 
 		case ruleAction79:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewStream(substr))
+			p.AssembleWatermarkDelay(begin, end)
 
 		case ruleAction80:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewRowMeta(substr, TimestampMeta))
+			p.AssembleUnnestStream(begin, end)
 
 		case ruleAction81:
 
 			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewRowValue(substr))
+			p.PushComponent(begin, end, Stream{SystemStream, substr, nil})
 
 		case ruleAction82:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewNumericLiteral(substr))
+			p.AssembleUDSFFuncApp()
 
 		case ruleAction83:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewNumericLiteral(substr))
+			p.EnsureCapacitySpec(begin, end)
 
 		case ruleAction84:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewFloatLiteral(substr))
+			p.EnsureSheddingSpec(begin, end)
 
 		case ruleAction85:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, FuncName(substr))
+			p.AssembleSchema(begin, end)
 
 		case ruleAction86:
 
-			p.PushComponent(begin, end, NewNullLiteral())
+			p.AssembleInstances(begin, end)
 
 		case ruleAction87:
 
-			p.PushComponent(begin, end, NewMissing())
+			p.AssembleSinkOrdering(begin, end)
 
 		case ruleAction88:
 
-			p.PushComponent(begin, end, NewBoolLiteral(true))
+			p.AssembleTimestampBy(begin, end)
 
 		case ruleAction89:
 
-			p.PushComponent(begin, end, NewBoolLiteral(false))
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, Raw{substr})
 
 		case ruleAction90:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewWildcard(substr))
+			p.AssembleOnError(begin, end)
 
 		case ruleAction91:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, NewStringLiteral(substr))
+			p.AssembleTimeout(begin, end)
 
 		case ruleAction92:
 
-			p.PushComponent(begin, end, Istream)
+			p.AssembleSourceSinkSpecs(begin, end)
 
 		case ruleAction93:
 
-			p.PushComponent(begin, end, Dstream)
+			p.AssembleSourceSinkSpecs(begin, end)
 
 		case ruleAction94:
 
-			p.PushComponent(begin, end, Rstream)
+			p.AssembleSourceSinkSpecs(begin, end)
 
 		case ruleAction95:
 
-			p.PushComponent(begin, end, Tuples)
+			p.AssembleSourceSinkSpecs(begin, end)
 
 		case ruleAction96:
 
-			p.PushComponent(begin, end, Seconds)
+			p.EnsureIdentifier(begin, end)
 
 		case ruleAction97:
 
-			p.PushComponent(begin, end, Milliseconds)
+			p.AssembleSourceSinkParam()
 
 		case ruleAction98:
 
-			p.PushComponent(begin, end, Wait)
+			p.AssembleExpressions(begin, end)
+			p.AssembleArray()
 
 		case ruleAction99:
 
-			p.PushComponent(begin, end, DropOldest)
+			p.AssembleMap(begin, end)
 
 		case ruleAction100:
 
-			p.PushComponent(begin, end, DropNewest)
+			p.AssembleKeyValuePair()
 
 		case ruleAction101:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, StreamIdentifier(substr))
+			p.EnsureKeywordPresent(begin, end)
 
 		case ruleAction102:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, SourceSinkType(substr))
+			p.EnsureKeywordPresent(begin, end)
 
 		case ruleAction103:
 
-			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, SourceSinkParamKey(substr))
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction104:
 
-			p.PushComponent(begin, end, Yes)
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction105:
 
-			p.PushComponent(begin, end, No)
+			p.AssembleUnaryPrefixOperation(begin, end)
 
 		case ruleAction106:
 
-			p.PushComponent(begin, end, Yes)
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction107:
 
-			p.PushComponent(begin, end, No)
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction108:
 
-			p.PushComponent(begin, end, Bool)
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction109:
 
-			p.PushComponent(begin, end, Int)
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction110:
 
-			p.PushComponent(begin, end, Float)
+			p.AssembleBinaryOperation(begin, end)
 
 		case ruleAction111:
 
-			p.PushComponent(begin, end, String)
+			p.AssembleUnaryPrefixOperation(begin, end)
 
 		case ruleAction112:
 
-			p.PushComponent(begin, end, Blob)
+			p.AssembleTypeCast(begin, end)
 
 		case ruleAction113:
 
-			p.PushComponent(begin, end, Timestamp)
+			p.AssembleTypeCast(begin, end)
 
 		case ruleAction114:
 
-			p.PushComponent(begin, end, Array)
+			p.AssembleFuncAppSelector()
 
 		case ruleAction115:
 
-			p.PushComponent(begin, end, Map)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewRaw(substr))
 
 		case ruleAction116:
 
-			p.PushComponent(begin, end, Or)
+			p.AssembleFuncApp()
 
 		case ruleAction117:
 
-			p.PushComponent(begin, end, And)
+			p.AssembleExpressions(begin, end)
+			p.AssembleFuncApp()
 
 		case ruleAction118:
 
-			p.PushComponent(begin, end, Not)
+			p.AssembleExpressions(begin, end)
 
 		case ruleAction119:
 
-			p.PushComponent(begin, end, Equal)
+			p.AssembleExpressions(begin, end)
 
 		case ruleAction120:
 
-			p.PushComponent(begin, end, Less)
+			p.AssembleSortedExpression()
 
 		case ruleAction121:
 
-			p.PushComponent(begin, end, LessOrEqual)
+			p.EnsureKeywordPresent(begin, end)
 
 		case ruleAction122:
 
-			p.PushComponent(begin, end, Greater)
+			p.AssembleExpressions(begin, end)
+			p.AssembleArray()
 
 		case ruleAction123:
 
-			p.PushComponent(begin, end, GreaterOrEqual)
+			p.AssembleMap(begin, end)
 
 		case ruleAction124:
 
-			p.PushComponent(begin, end, NotEqual)
+			p.AssembleKeyValuePair()
 
 		case ruleAction125:
 
-			p.PushComponent(begin, end, Concat)
+			p.AssembleConditionCase(begin, end)
 
 		case ruleAction126:
 
-			p.PushComponent(begin, end, Is)
+			p.AssembleExpressionCase(begin, end)
 
 		case ruleAction127:
 
-			p.PushComponent(begin, end, IsNot)
+			p.AssembleWhenThenPair()
 
 		case ruleAction128:
 
-			p.PushComponent(begin, end, Plus)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewStream(substr))
 
 		case ruleAction129:
 
-			p.PushComponent(begin, end, Minus)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewRowMeta(substr, TimestampMeta))
 
 		case ruleAction130:
 
-			p.PushComponent(begin, end, Multiply)
+			substr := string([]rune(buffer)[begin:end])
+			p.AssembleRowMetadata(begin, end, substr)
 
 		case ruleAction131:
 
-			p.PushComponent(begin, end, Divide)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewRowValue(substr))
 
 		case ruleAction132:
 
-			p.PushComponent(begin, end, Modulo)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewNumericLiteral(substr))
 
 		case ruleAction133:
 
-			p.PushComponent(begin, end, UnaryMinus)
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewNumericLiteral(substr))
 
 		case ruleAction134:
 
 			substr := string([]rune(buffer)[begin:end])
-			p.PushComponent(begin, end, Identifier(substr))
+			p.PushComponent(begin, end, NewFloatLiteral(substr))
 
 		case ruleAction135:
 
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, FuncName(substr))
+
+		case ruleAction136:
+
+			p.PushComponent(begin, end, NewNullLiteral())
+
+		case ruleAction137:
+
+			p.PushComponent(begin, end, NewMissing())
+
+		case ruleAction138:
+
+			p.PushComponent(begin, end, NewBoolLiteral(true))
+
+		case ruleAction139:
+
+			p.PushComponent(begin, end, NewBoolLiteral(false))
+
+		case ruleAction140:
+
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewWildcard(substr))
+
+		case ruleAction141:
+
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, NewStringLiteral(substr))
+
+		case ruleAction142:
+
+			p.PushComponent(begin, end, Istream)
+
+		case ruleAction143:
+
+			p.PushComponent(begin, end, Dstream)
+
+		case ruleAction144:
+
+			p.PushComponent(begin, end, Rstream)
+
+		case ruleAction145:
+
+			p.PushComponent(begin, end, Tuples)
+
+		case ruleAction146:
+
+			p.PushComponent(begin, end, Seconds)
+
+		case ruleAction147:
+
+			p.PushComponent(begin, end, Milliseconds)
+
+		case ruleAction148:
+
+			p.PushComponent(begin, end, DropOnError)
+
+		case ruleAction149:
+
+			p.PushComponent(begin, end, StopOnError)
+
+		case ruleAction150:
+
+			p.PushComponent(begin, end, DLQOnError)
+
+		case ruleAction151:
+
+			p.PushComponent(begin, end, RetryOnError)
+
+		case ruleAction152:
+
+			p.PushComponent(begin, end, Wait)
+
+		case ruleAction153:
+
+			p.PushComponent(begin, end, DropOldest)
+
+		case ruleAction154:
+
+			p.PushComponent(begin, end, DropNewest)
+
+		case ruleAction155:
+
+			p.PushComponent(begin, end, ArrivalOrder)
+
+		case ruleAction156:
+
+			p.PushComponent(begin, end, TimestampOrder)
+
+		case ruleAction157:
+
+			p.PushComponent(begin, end, RoundRobinOrder)
+
+		case ruleAction158:
+
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, StreamIdentifier(substr))
+
+		case ruleAction159:
+
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, SourceSinkType(substr))
+
+		case ruleAction160:
+
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, SourceSinkParamKey(substr))
+
+		case ruleAction161:
+
+			p.PushComponent(begin, end, Yes)
+
+		case ruleAction162:
+
+			p.PushComponent(begin, end, No)
+
+		case ruleAction163:
+
+			p.PushComponent(begin, end, Yes)
+
+		case ruleAction164:
+
+			p.PushComponent(begin, end, Yes)
+
+		case ruleAction165:
+
+			p.PushComponent(begin, end, No)
+
+		case ruleAction166:
+
+			p.PushComponent(begin, end, Bool)
+
+		case ruleAction167:
+
+			p.PushComponent(begin, end, Int)
+
+		case ruleAction168:
+
+			p.PushComponent(begin, end, Float)
+
+		case ruleAction169:
+
+			p.PushComponent(begin, end, String)
+
+		case ruleAction170:
+
+			p.PushComponent(begin, end, Blob)
+
+		case ruleAction171:
+
+			p.PushComponent(begin, end, Timestamp)
+
+		case ruleAction172:
+
+			p.PushComponent(begin, end, Array)
+
+		case ruleAction173:
+
+			p.PushComponent(begin, end, Map)
+
+		case ruleAction174:
+
+			p.PushComponent(begin, end, Or)
+
+		case ruleAction175:
+
+			p.PushComponent(begin, end, And)
+
+		case ruleAction176:
+
+			p.PushComponent(begin, end, Not)
+
+		case ruleAction177:
+
+			p.PushComponent(begin, end, Equal)
+
+		case ruleAction178:
+
+			p.PushComponent(begin, end, Less)
+
+		case ruleAction179:
+
+			p.PushComponent(begin, end, LessOrEqual)
+
+		case ruleAction180:
+
+			p.PushComponent(begin, end, Greater)
+
+		case ruleAction181:
+
+			p.PushComponent(begin, end, GreaterOrEqual)
+
+		case ruleAction182:
+
+			p.PushComponent(begin, end, NotEqual)
+
+		case ruleAction183:
+
+			p.PushComponent(begin, end, Concat)
+
+		case ruleAction184:
+
+			p.PushComponent(begin, end, Is)
+
+		case ruleAction185:
+
+			p.PushComponent(begin, end, IsNot)
+
+		case ruleAction186:
+
+			p.PushComponent(begin, end, Plus)
+
+		case ruleAction187:
+
+			p.PushComponent(begin, end, Minus)
+
+		case ruleAction188:
+
+			p.PushComponent(begin, end, Multiply)
+
+		case ruleAction189:
+
+			p.PushComponent(begin, end, Divide)
+
+		case ruleAction190:
+
+			p.PushComponent(begin, end, Modulo)
+
+		case ruleAction191:
+
+			p.PushComponent(begin, end, UnaryMinus)
+
+		case ruleAction192:
+
+			substr := string([]rune(buffer)[begin:end])
+			p.PushComponent(begin, end, Identifier(substr))
+
+		case ruleAction193:
+
 			substr := string([]rune(buffer)[begin:end])
 			p.PushComponent(begin, end, Identifier(substr))
 
@@ -1623,7 +2109,7 @@ func (p *bqlPegBackend) Init() {
 			position, tokenIndex = position10, tokenIndex10
 			return false
 		},
-		/* 3 Statement <- <(SelectUnionStmt / SelectStmt / SourceStmt / SinkStmt / StateStmt / StreamStmt / EvalStmt)> */
+		/* 3 Statement <- <(SelectUnionStmt / SelectStmt / SourceStmt / SinkStmt / StateStmt / StreamStmt / TypeStmt / TriggerStmt / AlertStmt / TemplateStmt / SetConfigStmt / EvalStmt / DescribeStmt)> */
 		func() bool {
 			position13, tokenIndex13 := position, tokenIndex
 			{
@@ -1665,8 +2151,44 @@ func (p *bqlPegBackend) Init() {
 					}
 					goto l15
 				l21:
+					position, tokenIndex = position15, tokenIndex15
+					if !_rules[ruleTypeStmt]() {
+						goto l22
+					}
+					goto l15
+				l22:
+					position, tokenIndex = position15, tokenIndex15
+					if !_rules[ruleTriggerStmt]() {
+						goto l23
+					}
+					goto l15
+				l23:
+					position, tokenIndex = position15, tokenIndex15
+					if !_rules[ruleAlertStmt]() {
+						goto l24
+					}
+					goto l15
+				l24:
+					position, tokenIndex = position15, tokenIndex15
+					if !_rules[ruleTemplateStmt]() {
+						goto l25
+					}
+					goto l15
+				l25:
+					position, tokenIndex = position15, tokenIndex15
+					if !_rules[ruleSetConfigStmt]() {
+						goto l26
+					}
+					goto l15
+				l26:
 					position, tokenIndex = position15, tokenIndex15
 					if !_rules[ruleEvalStmt]() {
+						goto l27
+					}
+					goto l15
+				l27:
+					position, tokenIndex = position15, tokenIndex15
+					if !_rules[ruleDescribeStmt]() {
 						goto l13
 					}
 				}
@@ -1680,1559 +2202,1497 @@ func (p *bqlPegBackend) Init() {
 		},
 		/* 4 SourceStmt <- <(CreateSourceStmt / UpdateSourceStmt / DropSourceStmt / PauseSourceStmt / ResumeSourceStmt / RewindSourceStmt)> */
 		func() bool {
-			position22, tokenIndex22 := position, tokenIndex
+			position28, tokenIndex28 := position, tokenIndex
 			{
-				position23 := position
+				position29 := position
 				{
-					position24, tokenIndex24 := position, tokenIndex
+					position30, tokenIndex30 := position, tokenIndex
 					if !_rules[ruleCreateSourceStmt]() {
-						goto l25
+						goto l31
 					}
-					goto l24
-				l25:
-					position, tokenIndex = position24, tokenIndex24
+					goto l30
+				l31:
+					position, tokenIndex = position30, tokenIndex30
 					if !_rules[ruleUpdateSourceStmt]() {
-						goto l26
+						goto l32
 					}
-					goto l24
-				l26:
-					position, tokenIndex = position24, tokenIndex24
+					goto l30
+				l32:
+					position, tokenIndex = position30, tokenIndex30
 					if !_rules[ruleDropSourceStmt]() {
-						goto l27
+						goto l33
 					}
-					goto l24
-				l27:
-					position, tokenIndex = position24, tokenIndex24
+					goto l30
+				l33:
+					position, tokenIndex = position30, tokenIndex30
 					if !_rules[rulePauseSourceStmt]() {
-						goto l28
+						goto l34
 					}
-					goto l24
-				l28:
-					position, tokenIndex = position24, tokenIndex24
+					goto l30
+				l34:
+					position, tokenIndex = position30, tokenIndex30
 					if !_rules[ruleResumeSourceStmt]() {
-						goto l29
+						goto l35
 					}
-					goto l24
-				l29:
-					position, tokenIndex = position24, tokenIndex24
+					goto l30
+				l35:
+					position, tokenIndex = position30, tokenIndex30
 					if !_rules[ruleRewindSourceStmt]() {
-						goto l22
+						goto l28
 					}
 				}
-			l24:
-				add(ruleSourceStmt, position23)
+			l30:
+				add(ruleSourceStmt, position29)
 			}
 			return true
-		l22:
-			position, tokenIndex = position22, tokenIndex22
+		l28:
+			position, tokenIndex = position28, tokenIndex28
 			return false
 		},
-		/* 5 SinkStmt <- <(CreateSinkStmt / UpdateSinkStmt / DropSinkStmt)> */
+		/* 5 SinkStmt <- <(CreateSinkStmt / UpdateSinkStmt / DropSinkStmt / ReplaySinkStmt)> */
 		func() bool {
-			position30, tokenIndex30 := position, tokenIndex
+			position36, tokenIndex36 := position, tokenIndex
 			{
-				position31 := position
+				position37 := position
 				{
-					position32, tokenIndex32 := position, tokenIndex
+					position38, tokenIndex38 := position, tokenIndex
 					if !_rules[ruleCreateSinkStmt]() {
-						goto l33
+						goto l39
 					}
-					goto l32
-				l33:
-					position, tokenIndex = position32, tokenIndex32
+					goto l38
+				l39:
+					position, tokenIndex = position38, tokenIndex38
 					if !_rules[ruleUpdateSinkStmt]() {
-						goto l34
+						goto l40
 					}
-					goto l32
-				l34:
-					position, tokenIndex = position32, tokenIndex32
+					goto l38
+				l40:
+					position, tokenIndex = position38, tokenIndex38
 					if !_rules[ruleDropSinkStmt]() {
-						goto l30
+						goto l41
+					}
+					goto l38
+				l41:
+					position, tokenIndex = position38, tokenIndex38
+					if !_rules[ruleReplaySinkStmt]() {
+						goto l36
 					}
 				}
-			l32:
-				add(ruleSinkStmt, position31)
+			l38:
+				add(ruleSinkStmt, position37)
 			}
 			return true
-		l30:
-			position, tokenIndex = position30, tokenIndex30
+		l36:
+			position, tokenIndex = position36, tokenIndex36
 			return false
 		},
 		/* 6 StateStmt <- <(CreateStateStmt / UpdateStateStmt / DropStateStmt / LoadStateOrCreateStmt / LoadStateStmt / SaveStateStmt)> */
 		func() bool {
-			position35, tokenIndex35 := position, tokenIndex
+			position42, tokenIndex42 := position, tokenIndex
 			{
-				position36 := position
+				position43 := position
 				{
-					position37, tokenIndex37 := position, tokenIndex
+					position44, tokenIndex44 := position, tokenIndex
 					if !_rules[ruleCreateStateStmt]() {
-						goto l38
+						goto l45
 					}
-					goto l37
-				l38:
-					position, tokenIndex = position37, tokenIndex37
+					goto l44
+				l45:
+					position, tokenIndex = position44, tokenIndex44
 					if !_rules[ruleUpdateStateStmt]() {
-						goto l39
+						goto l46
 					}
-					goto l37
-				l39:
-					position, tokenIndex = position37, tokenIndex37
+					goto l44
+				l46:
+					position, tokenIndex = position44, tokenIndex44
 					if !_rules[ruleDropStateStmt]() {
-						goto l40
+						goto l47
 					}
-					goto l37
-				l40:
-					position, tokenIndex = position37, tokenIndex37
+					goto l44
+				l47:
+					position, tokenIndex = position44, tokenIndex44
 					if !_rules[ruleLoadStateOrCreateStmt]() {
-						goto l41
+						goto l48
 					}
-					goto l37
-				l41:
-					position, tokenIndex = position37, tokenIndex37
+					goto l44
+				l48:
+					position, tokenIndex = position44, tokenIndex44
 					if !_rules[ruleLoadStateStmt]() {
-						goto l42
+						goto l49
 					}
-					goto l37
-				l42:
-					position, tokenIndex = position37, tokenIndex37
+					goto l44
+				l49:
+					position, tokenIndex = position44, tokenIndex44
 					if !_rules[ruleSaveStateStmt]() {
-						goto l35
+						goto l42
 					}
 				}
-			l37:
-				add(ruleStateStmt, position36)
+			l44:
+				add(ruleStateStmt, position43)
 			}
 			return true
-		l35:
-			position, tokenIndex = position35, tokenIndex35
+		l42:
+			position, tokenIndex = position42, tokenIndex42
 			return false
 		},
-		/* 7 StreamStmt <- <(CreateStreamAsSelectUnionStmt / CreateStreamAsSelectStmt / DropStreamStmt / InsertIntoFromStmt)> */
+		/* 7 StreamStmt <- <(CreateStreamAsSelectUnionStmt / CreateStreamAsSelectStmt / CreateStreamRoutesStmt / DropStreamStmt / ResumeStreamStmt / InsertIntoFromStmt / ProfileStreamStmt)> */
 		func() bool {
-			position43, tokenIndex43 := position, tokenIndex
+			position50, tokenIndex50 := position, tokenIndex
 			{
-				position44 := position
+				position51 := position
 				{
-					position45, tokenIndex45 := position, tokenIndex
+					position52, tokenIndex52 := position, tokenIndex
 					if !_rules[ruleCreateStreamAsSelectUnionStmt]() {
-						goto l46
+						goto l53
 					}
-					goto l45
-				l46:
-					position, tokenIndex = position45, tokenIndex45
+					goto l52
+				l53:
+					position, tokenIndex = position52, tokenIndex52
 					if !_rules[ruleCreateStreamAsSelectStmt]() {
-						goto l47
+						goto l54
 					}
-					goto l45
-				l47:
-					position, tokenIndex = position45, tokenIndex45
+					goto l52
+				l54:
+					position, tokenIndex = position52, tokenIndex52
+					if !_rules[ruleCreateStreamRoutesStmt]() {
+						goto l55
+					}
+					goto l52
+				l55:
+					position, tokenIndex = position52, tokenIndex52
 					if !_rules[ruleDropStreamStmt]() {
-						goto l48
+						goto l56
 					}
-					goto l45
-				l48:
-					position, tokenIndex = position45, tokenIndex45
+					goto l52
+				l56:
+					position, tokenIndex = position52, tokenIndex52
+					if !_rules[ruleResumeStreamStmt]() {
+						goto l57
+					}
+					goto l52
+				l57:
+					position, tokenIndex = position52, tokenIndex52
 					if !_rules[ruleInsertIntoFromStmt]() {
-						goto l43
+						goto l58
+					}
+					goto l52
+				l58:
+					position, tokenIndex = position52, tokenIndex52
+					if !_rules[ruleProfileStreamStmt]() {
+						goto l50
 					}
 				}
-			l45:
-				add(ruleStreamStmt, position44)
+			l52:
+				add(ruleStreamStmt, position51)
 			}
 			return true
-		l43:
-			position, tokenIndex = position43, tokenIndex43
+		l50:
+			position, tokenIndex = position50, tokenIndex50
 			return false
 		},
-		/* 8 SelectStmt <- <(('s' / 'S') ('e' / 'E') ('l' / 'L') ('e' / 'E') ('c' / 'C') ('t' / 'T') Emitter Projections WindowedFrom Filter Grouping Having Action2)> */
+		/* 8 TypeStmt <- <(CreateTypeStmt / DropTypeStmt)> */
 		func() bool {
-			position49, tokenIndex49 := position, tokenIndex
+			position59, tokenIndex59 := position, tokenIndex
 			{
-				position50 := position
+				position60 := position
 				{
-					position51, tokenIndex51 := position, tokenIndex
+					position61, tokenIndex61 := position, tokenIndex
+					if !_rules[ruleCreateTypeStmt]() {
+						goto l62
+					}
+					goto l61
+				l62:
+					position, tokenIndex = position61, tokenIndex61
+					if !_rules[ruleDropTypeStmt]() {
+						goto l59
+					}
+				}
+			l61:
+				add(ruleTypeStmt, position60)
+			}
+			return true
+		l59:
+			position, tokenIndex = position59, tokenIndex59
+			return false
+		},
+		/* 9 TriggerStmt <- <(CreateTriggerStmt / DropTriggerStmt)> */
+		func() bool {
+			position63, tokenIndex63 := position, tokenIndex
+			{
+				position64 := position
+				{
+					position65, tokenIndex65 := position, tokenIndex
+					if !_rules[ruleCreateTriggerStmt]() {
+						goto l66
+					}
+					goto l65
+				l66:
+					position, tokenIndex = position65, tokenIndex65
+					if !_rules[ruleDropTriggerStmt]() {
+						goto l63
+					}
+				}
+			l65:
+				add(ruleTriggerStmt, position64)
+			}
+			return true
+		l63:
+			position, tokenIndex = position63, tokenIndex63
+			return false
+		},
+		/* 10 AlertStmt <- <(CreateAlertStmt / DropAlertStmt)> */
+		func() bool {
+			position67, tokenIndex67 := position, tokenIndex
+			{
+				position68 := position
+				{
+					position69, tokenIndex69 := position, tokenIndex
+					if !_rules[ruleCreateAlertStmt]() {
+						goto l70
+					}
+					goto l69
+				l70:
+					position, tokenIndex = position69, tokenIndex69
+					if !_rules[ruleDropAlertStmt]() {
+						goto l67
+					}
+				}
+			l69:
+				add(ruleAlertStmt, position68)
+			}
+			return true
+		l67:
+			position, tokenIndex = position67, tokenIndex67
+			return false
+		},
+		/* 11 TemplateStmt <- <(CreateTemplateStmt / InstantiateTemplateStmt / DropTemplateInstanceStmt / DropTemplateStmt)> */
+		func() bool {
+			position71, tokenIndex71 := position, tokenIndex
+			{
+				position72 := position
+				{
+					position73, tokenIndex73 := position, tokenIndex
+					if !_rules[ruleCreateTemplateStmt]() {
+						goto l74
+					}
+					goto l73
+				l74:
+					position, tokenIndex = position73, tokenIndex73
+					if !_rules[ruleInstantiateTemplateStmt]() {
+						goto l75
+					}
+					goto l73
+				l75:
+					position, tokenIndex = position73, tokenIndex73
+					if !_rules[ruleDropTemplateInstanceStmt]() {
+						goto l76
+					}
+					goto l73
+				l76:
+					position, tokenIndex = position73, tokenIndex73
+					if !_rules[ruleDropTemplateStmt]() {
+						goto l71
+					}
+				}
+			l73:
+				add(ruleTemplateStmt, position72)
+			}
+			return true
+		l71:
+			position, tokenIndex = position71, tokenIndex71
+			return false
+		},
+		/* 12 SelectStmt <- <(('s' / 'S') ('e' / 'E') ('l' / 'L') ('e' / 'E') ('c' / 'C') ('t' / 'T') Emitter Projections WindowedFrom InputSampling Filter Grouping Having EmitWhen Action2)> */
+		func() bool {
+			position77, tokenIndex77 := position, tokenIndex
+			{
+				position78 := position
+				{
+					position79, tokenIndex79 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l52
+						goto l80
 					}
 					position++
-					goto l51
-				l52:
-					position, tokenIndex = position51, tokenIndex51
+					goto l79
+				l80:
+					position, tokenIndex = position79, tokenIndex79
 					if buffer[position] != rune('S') {
-						goto l49
+						goto l77
 					}
 					position++
 				}
-			l51:
+			l79:
 				{
-					position53, tokenIndex53 := position, tokenIndex
+					position81, tokenIndex81 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l54
+						goto l82
 					}
 					position++
-					goto l53
-				l54:
-					position, tokenIndex = position53, tokenIndex53
+					goto l81
+				l82:
+					position, tokenIndex = position81, tokenIndex81
 					if buffer[position] != rune('E') {
-						goto l49
+						goto l77
 					}
 					position++
 				}
-			l53:
+			l81:
 				{
-					position55, tokenIndex55 := position, tokenIndex
+					position83, tokenIndex83 := position, tokenIndex
 					if buffer[position] != rune('l') {
-						goto l56
+						goto l84
 					}
 					position++
-					goto l55
-				l56:
-					position, tokenIndex = position55, tokenIndex55
+					goto l83
+				l84:
+					position, tokenIndex = position83, tokenIndex83
 					if buffer[position] != rune('L') {
-						goto l49
+						goto l77
 					}
 					position++
 				}
-			l55:
+			l83:
 				{
-					position57, tokenIndex57 := position, tokenIndex
+					position85, tokenIndex85 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l58
+						goto l86
 					}
 					position++
-					goto l57
-				l58:
-					position, tokenIndex = position57, tokenIndex57
+					goto l85
+				l86:
+					position, tokenIndex = position85, tokenIndex85
 					if buffer[position] != rune('E') {
-						goto l49
+						goto l77
 					}
 					position++
 				}
-			l57:
+			l85:
 				{
-					position59, tokenIndex59 := position, tokenIndex
+					position87, tokenIndex87 := position, tokenIndex
 					if buffer[position] != rune('c') {
-						goto l60
+						goto l88
 					}
 					position++
-					goto l59
-				l60:
-					position, tokenIndex = position59, tokenIndex59
+					goto l87
+				l88:
+					position, tokenIndex = position87, tokenIndex87
 					if buffer[position] != rune('C') {
-						goto l49
+						goto l77
 					}
 					position++
 				}
-			l59:
+			l87:
 				{
-					position61, tokenIndex61 := position, tokenIndex
+					position89, tokenIndex89 := position, tokenIndex
 					if buffer[position] != rune('t') {
-						goto l62
+						goto l90
 					}
 					position++
-					goto l61
-				l62:
-					position, tokenIndex = position61, tokenIndex61
+					goto l89
+				l90:
+					position, tokenIndex = position89, tokenIndex89
 					if buffer[position] != rune('T') {
-						goto l49
+						goto l77
 					}
 					position++
 				}
-			l61:
+			l89:
 				if !_rules[ruleEmitter]() {
-					goto l49
+					goto l77
 				}
 				if !_rules[ruleProjections]() {
-					goto l49
+					goto l77
 				}
 				if !_rules[ruleWindowedFrom]() {
-					goto l49
+					goto l77
+				}
+				if !_rules[ruleInputSampling]() {
+					goto l77
 				}
 				if !_rules[ruleFilter]() {
-					goto l49
+					goto l77
 				}
 				if !_rules[ruleGrouping]() {
-					goto l49
+					goto l77
 				}
 				if !_rules[ruleHaving]() {
-					goto l49
+					goto l77
+				}
+				if !_rules[ruleEmitWhen]() {
+					goto l77
 				}
 				if !_rules[ruleAction2]() {
-					goto l49
+					goto l77
 				}
-				add(ruleSelectStmt, position50)
+				add(ruleSelectStmt, position78)
 			}
 			return true
-		l49:
-			position, tokenIndex = position49, tokenIndex49
+		l77:
+			position, tokenIndex = position77, tokenIndex77
 			return false
 		},
-		/* 9 SelectUnionStmt <- <(<(SelectStmt (sp (('u' / 'U') ('n' / 'N') ('i' / 'I') ('o' / 'O') ('n' / 'N')) sp (('a' / 'A') ('l' / 'L') ('l' / 'L')) sp SelectStmt)+)> Action3)> */
+		/* 13 SelectUnionStmt <- <(<(SelectStmt (sp (('u' / 'U') ('n' / 'N') ('i' / 'I') ('o' / 'O') ('n' / 'N')) sp (('a' / 'A') ('l' / 'L') ('l' / 'L')) sp SelectStmt)+)> Action3)> */
 		func() bool {
-			position63, tokenIndex63 := position, tokenIndex
+			position91, tokenIndex91 := position, tokenIndex
 			{
-				position64 := position
+				position92 := position
 				{
-					position65 := position
+					position93 := position
 					if !_rules[ruleSelectStmt]() {
-						goto l63
+						goto l91
 					}
 					if !_rules[rulesp]() {
-						goto l63
+						goto l91
 					}
 					{
-						position68, tokenIndex68 := position, tokenIndex
+						position96, tokenIndex96 := position, tokenIndex
 						if buffer[position] != rune('u') {
-							goto l69
+							goto l97
 						}
 						position++
-						goto l68
-					l69:
-						position, tokenIndex = position68, tokenIndex68
+						goto l96
+					l97:
+						position, tokenIndex = position96, tokenIndex96
 						if buffer[position] != rune('U') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l68:
+				l96:
 					{
-						position70, tokenIndex70 := position, tokenIndex
+						position98, tokenIndex98 := position, tokenIndex
 						if buffer[position] != rune('n') {
-							goto l71
+							goto l99
 						}
 						position++
-						goto l70
-					l71:
-						position, tokenIndex = position70, tokenIndex70
+						goto l98
+					l99:
+						position, tokenIndex = position98, tokenIndex98
 						if buffer[position] != rune('N') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l70:
+				l98:
 					{
-						position72, tokenIndex72 := position, tokenIndex
+						position100, tokenIndex100 := position, tokenIndex
 						if buffer[position] != rune('i') {
-							goto l73
+							goto l101
 						}
 						position++
-						goto l72
-					l73:
-						position, tokenIndex = position72, tokenIndex72
+						goto l100
+					l101:
+						position, tokenIndex = position100, tokenIndex100
 						if buffer[position] != rune('I') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l72:
+				l100:
 					{
-						position74, tokenIndex74 := position, tokenIndex
+						position102, tokenIndex102 := position, tokenIndex
 						if buffer[position] != rune('o') {
-							goto l75
+							goto l103
 						}
 						position++
-						goto l74
-					l75:
-						position, tokenIndex = position74, tokenIndex74
+						goto l102
+					l103:
+						position, tokenIndex = position102, tokenIndex102
 						if buffer[position] != rune('O') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l74:
+				l102:
 					{
-						position76, tokenIndex76 := position, tokenIndex
+						position104, tokenIndex104 := position, tokenIndex
 						if buffer[position] != rune('n') {
-							goto l77
+							goto l105
 						}
 						position++
-						goto l76
-					l77:
-						position, tokenIndex = position76, tokenIndex76
+						goto l104
+					l105:
+						position, tokenIndex = position104, tokenIndex104
 						if buffer[position] != rune('N') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l76:
+				l104:
 					if !_rules[rulesp]() {
-						goto l63
+						goto l91
 					}
 					{
-						position78, tokenIndex78 := position, tokenIndex
+						position106, tokenIndex106 := position, tokenIndex
 						if buffer[position] != rune('a') {
-							goto l79
+							goto l107
 						}
 						position++
-						goto l78
-					l79:
-						position, tokenIndex = position78, tokenIndex78
+						goto l106
+					l107:
+						position, tokenIndex = position106, tokenIndex106
 						if buffer[position] != rune('A') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l78:
+				l106:
 					{
-						position80, tokenIndex80 := position, tokenIndex
+						position108, tokenIndex108 := position, tokenIndex
 						if buffer[position] != rune('l') {
-							goto l81
+							goto l109
 						}
 						position++
-						goto l80
-					l81:
-						position, tokenIndex = position80, tokenIndex80
+						goto l108
+					l109:
+						position, tokenIndex = position108, tokenIndex108
 						if buffer[position] != rune('L') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l80:
+				l108:
 					{
-						position82, tokenIndex82 := position, tokenIndex
+						position110, tokenIndex110 := position, tokenIndex
 						if buffer[position] != rune('l') {
-							goto l83
+							goto l111
 						}
 						position++
-						goto l82
-					l83:
-						position, tokenIndex = position82, tokenIndex82
+						goto l110
+					l111:
+						position, tokenIndex = position110, tokenIndex110
 						if buffer[position] != rune('L') {
-							goto l63
+							goto l91
 						}
 						position++
 					}
-				l82:
+				l110:
 					if !_rules[rulesp]() {
-						goto l63
+						goto l91
 					}
 					if !_rules[ruleSelectStmt]() {
-						goto l63
+						goto l91
 					}
-				l66:
+				l94:
 					{
-						position67, tokenIndex67 := position, tokenIndex
+						position95, tokenIndex95 := position, tokenIndex
 						if !_rules[rulesp]() {
-							goto l67
+							goto l95
 						}
 						{
-							position84, tokenIndex84 := position, tokenIndex
+							position112, tokenIndex112 := position, tokenIndex
 							if buffer[position] != rune('u') {
-								goto l85
+								goto l113
 							}
 							position++
-							goto l84
-						l85:
-							position, tokenIndex = position84, tokenIndex84
+							goto l112
+						l113:
+							position, tokenIndex = position112, tokenIndex112
 							if buffer[position] != rune('U') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l84:
+					l112:
 						{
-							position86, tokenIndex86 := position, tokenIndex
+							position114, tokenIndex114 := position, tokenIndex
 							if buffer[position] != rune('n') {
-								goto l87
+								goto l115
 							}
 							position++
-							goto l86
-						l87:
-							position, tokenIndex = position86, tokenIndex86
+							goto l114
+						l115:
+							position, tokenIndex = position114, tokenIndex114
 							if buffer[position] != rune('N') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l86:
+					l114:
 						{
-							position88, tokenIndex88 := position, tokenIndex
+							position116, tokenIndex116 := position, tokenIndex
 							if buffer[position] != rune('i') {
-								goto l89
+								goto l117
 							}
 							position++
-							goto l88
-						l89:
-							position, tokenIndex = position88, tokenIndex88
+							goto l116
+						l117:
+							position, tokenIndex = position116, tokenIndex116
 							if buffer[position] != rune('I') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l88:
+					l116:
 						{
-							position90, tokenIndex90 := position, tokenIndex
+							position118, tokenIndex118 := position, tokenIndex
 							if buffer[position] != rune('o') {
-								goto l91
+								goto l119
 							}
 							position++
-							goto l90
-						l91:
-							position, tokenIndex = position90, tokenIndex90
+							goto l118
+						l119:
+							position, tokenIndex = position118, tokenIndex118
 							if buffer[position] != rune('O') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l90:
+					l118:
 						{
-							position92, tokenIndex92 := position, tokenIndex
+							position120, tokenIndex120 := position, tokenIndex
 							if buffer[position] != rune('n') {
-								goto l93
+								goto l121
 							}
 							position++
-							goto l92
-						l93:
-							position, tokenIndex = position92, tokenIndex92
+							goto l120
+						l121:
+							position, tokenIndex = position120, tokenIndex120
 							if buffer[position] != rune('N') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l92:
+					l120:
 						if !_rules[rulesp]() {
-							goto l67
+							goto l95
 						}
 						{
-							position94, tokenIndex94 := position, tokenIndex
+							position122, tokenIndex122 := position, tokenIndex
 							if buffer[position] != rune('a') {
-								goto l95
+								goto l123
 							}
 							position++
-							goto l94
-						l95:
-							position, tokenIndex = position94, tokenIndex94
+							goto l122
+						l123:
+							position, tokenIndex = position122, tokenIndex122
 							if buffer[position] != rune('A') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l94:
+					l122:
 						{
-							position96, tokenIndex96 := position, tokenIndex
+							position124, tokenIndex124 := position, tokenIndex
 							if buffer[position] != rune('l') {
-								goto l97
+								goto l125
 							}
 							position++
-							goto l96
-						l97:
-							position, tokenIndex = position96, tokenIndex96
+							goto l124
+						l125:
+							position, tokenIndex = position124, tokenIndex124
 							if buffer[position] != rune('L') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l96:
+					l124:
 						{
-							position98, tokenIndex98 := position, tokenIndex
+							position126, tokenIndex126 := position, tokenIndex
 							if buffer[position] != rune('l') {
-								goto l99
+								goto l127
 							}
 							position++
-							goto l98
-						l99:
-							position, tokenIndex = position98, tokenIndex98
+							goto l126
+						l127:
+							position, tokenIndex = position126, tokenIndex126
 							if buffer[position] != rune('L') {
-								goto l67
+								goto l95
 							}
 							position++
 						}
-					l98:
+					l126:
 						if !_rules[rulesp]() {
-							goto l67
+							goto l95
 						}
 						if !_rules[ruleSelectStmt]() {
-							goto l67
+							goto l95
 						}
-						goto l66
-					l67:
-						position, tokenIndex = position67, tokenIndex67
+						goto l94
+					l95:
+						position, tokenIndex = position95, tokenIndex95
 					}
-					add(rulePegText, position65)
+					add(rulePegText, position93)
 				}
 				if !_rules[ruleAction3]() {
-					goto l63
+					goto l91
 				}
-				add(ruleSelectUnionStmt, position64)
+				add(ruleSelectUnionStmt, position92)
 			}
 			return true
-		l63:
-			position, tokenIndex = position63, tokenIndex63
+		l91:
+			position, tokenIndex = position91, tokenIndex91
 			return false
 		},
-		/* 10 CreateStreamAsSelectStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier sp (('a' / 'A') ('s' / 'S')) sp SelectStmt Action4)> */
+		/* 14 CreateStreamAsSelectStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') TemporaryOpt sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier SchemaOpt sp (('a' / 'A') ('s' / 'S')) sp SelectStmt TimestampByOpt OnErrorOpt TimeoutOpt SourceSinkSpecs Action4)> */
 		func() bool {
-			position100, tokenIndex100 := position, tokenIndex
+			position128, tokenIndex128 := position, tokenIndex
 			{
-				position101 := position
+				position129 := position
 				{
-					position102, tokenIndex102 := position, tokenIndex
+					position130, tokenIndex130 := position, tokenIndex
 					if buffer[position] != rune('c') {
-						goto l103
+						goto l131
 					}
 					position++
-					goto l102
-				l103:
-					position, tokenIndex = position102, tokenIndex102
+					goto l130
+				l131:
+					position, tokenIndex = position130, tokenIndex130
 					if buffer[position] != rune('C') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l102:
+			l130:
 				{
-					position104, tokenIndex104 := position, tokenIndex
+					position132, tokenIndex132 := position, tokenIndex
 					if buffer[position] != rune('r') {
-						goto l105
+						goto l133
 					}
 					position++
-					goto l104
-				l105:
-					position, tokenIndex = position104, tokenIndex104
+					goto l132
+				l133:
+					position, tokenIndex = position132, tokenIndex132
 					if buffer[position] != rune('R') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l104:
+			l132:
 				{
-					position106, tokenIndex106 := position, tokenIndex
+					position134, tokenIndex134 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l107
+						goto l135
 					}
 					position++
-					goto l106
-				l107:
-					position, tokenIndex = position106, tokenIndex106
+					goto l134
+				l135:
+					position, tokenIndex = position134, tokenIndex134
 					if buffer[position] != rune('E') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l106:
+			l134:
 				{
-					position108, tokenIndex108 := position, tokenIndex
+					position136, tokenIndex136 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l109
+						goto l137
 					}
 					position++
-					goto l108
-				l109:
-					position, tokenIndex = position108, tokenIndex108
+					goto l136
+				l137:
+					position, tokenIndex = position136, tokenIndex136
 					if buffer[position] != rune('A') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l108:
+			l136:
 				{
-					position110, tokenIndex110 := position, tokenIndex
+					position138, tokenIndex138 := position, tokenIndex
 					if buffer[position] != rune('t') {
-						goto l111
+						goto l139
 					}
 					position++
-					goto l110
-				l111:
-					position, tokenIndex = position110, tokenIndex110
+					goto l138
+				l139:
+					position, tokenIndex = position138, tokenIndex138
 					if buffer[position] != rune('T') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l110:
+			l138:
 				{
-					position112, tokenIndex112 := position, tokenIndex
+					position140, tokenIndex140 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l113
+						goto l141
 					}
 					position++
-					goto l112
-				l113:
-					position, tokenIndex = position112, tokenIndex112
+					goto l140
+				l141:
+					position, tokenIndex = position140, tokenIndex140
 					if buffer[position] != rune('E') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l112:
+			l140:
+				if !_rules[ruleTemporaryOpt]() {
+					goto l128
+				}
 				if !_rules[rulesp]() {
-					goto l100
+					goto l128
 				}
 				{
-					position114, tokenIndex114 := position, tokenIndex
+					position142, tokenIndex142 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l115
+						goto l143
 					}
 					position++
-					goto l114
-				l115:
-					position, tokenIndex = position114, tokenIndex114
+					goto l142
+				l143:
+					position, tokenIndex = position142, tokenIndex142
 					if buffer[position] != rune('S') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l114:
+			l142:
 				{
-					position116, tokenIndex116 := position, tokenIndex
+					position144, tokenIndex144 := position, tokenIndex
 					if buffer[position] != rune('t') {
-						goto l117
+						goto l145
 					}
 					position++
-					goto l116
-				l117:
-					position, tokenIndex = position116, tokenIndex116
+					goto l144
+				l145:
+					position, tokenIndex = position144, tokenIndex144
 					if buffer[position] != rune('T') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l116:
+			l144:
 				{
-					position118, tokenIndex118 := position, tokenIndex
+					position146, tokenIndex146 := position, tokenIndex
 					if buffer[position] != rune('r') {
-						goto l119
+						goto l147
 					}
 					position++
-					goto l118
-				l119:
-					position, tokenIndex = position118, tokenIndex118
+					goto l146
+				l147:
+					position, tokenIndex = position146, tokenIndex146
 					if buffer[position] != rune('R') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l118:
+			l146:
 				{
-					position120, tokenIndex120 := position, tokenIndex
+					position148, tokenIndex148 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l121
+						goto l149
 					}
 					position++
-					goto l120
-				l121:
-					position, tokenIndex = position120, tokenIndex120
+					goto l148
+				l149:
+					position, tokenIndex = position148, tokenIndex148
 					if buffer[position] != rune('E') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l120:
+			l148:
 				{
-					position122, tokenIndex122 := position, tokenIndex
+					position150, tokenIndex150 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l123
+						goto l151
 					}
 					position++
-					goto l122
-				l123:
-					position, tokenIndex = position122, tokenIndex122
+					goto l150
+				l151:
+					position, tokenIndex = position150, tokenIndex150
 					if buffer[position] != rune('A') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l122:
+			l150:
 				{
-					position124, tokenIndex124 := position, tokenIndex
+					position152, tokenIndex152 := position, tokenIndex
 					if buffer[position] != rune('m') {
-						goto l125
+						goto l153
 					}
 					position++
-					goto l124
-				l125:
-					position, tokenIndex = position124, tokenIndex124
+					goto l152
+				l153:
+					position, tokenIndex = position152, tokenIndex152
 					if buffer[position] != rune('M') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l124:
+			l152:
 				if !_rules[rulesp]() {
-					goto l100
+					goto l128
 				}
 				if !_rules[ruleStreamIdentifier]() {
-					goto l100
+					goto l128
+				}
+				if !_rules[ruleSchemaOpt]() {
+					goto l128
 				}
 				if !_rules[rulesp]() {
-					goto l100
+					goto l128
 				}
 				{
-					position126, tokenIndex126 := position, tokenIndex
+					position154, tokenIndex154 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l127
+						goto l155
 					}
 					position++
-					goto l126
-				l127:
-					position, tokenIndex = position126, tokenIndex126
+					goto l154
+				l155:
+					position, tokenIndex = position154, tokenIndex154
 					if buffer[position] != rune('A') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l126:
+			l154:
 				{
-					position128, tokenIndex128 := position, tokenIndex
+					position156, tokenIndex156 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l129
+						goto l157
 					}
 					position++
-					goto l128
-				l129:
-					position, tokenIndex = position128, tokenIndex128
+					goto l156
+				l157:
+					position, tokenIndex = position156, tokenIndex156
 					if buffer[position] != rune('S') {
-						goto l100
+						goto l128
 					}
 					position++
 				}
-			l128:
+			l156:
 				if !_rules[rulesp]() {
-					goto l100
+					goto l128
 				}
 				if !_rules[ruleSelectStmt]() {
-					goto l100
+					goto l128
+				}
+				if !_rules[ruleTimestampByOpt]() {
+					goto l128
+				}
+				if !_rules[ruleOnErrorOpt]() {
+					goto l128
+				}
+				if !_rules[ruleTimeoutOpt]() {
+					goto l128
+				}
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l128
 				}
 				if !_rules[ruleAction4]() {
-					goto l100
+					goto l128
 				}
-				add(ruleCreateStreamAsSelectStmt, position101)
+				add(ruleCreateStreamAsSelectStmt, position129)
 			}
 			return true
-		l100:
-			position, tokenIndex = position100, tokenIndex100
+		l128:
+			position, tokenIndex = position128, tokenIndex128
 			return false
 		},
-		/* 11 CreateStreamAsSelectUnionStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier sp (('a' / 'A') ('s' / 'S')) sp SelectUnionStmt Action5)> */
+		/* 15 CreateStreamAsSelectUnionStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') TemporaryOpt sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier SchemaOpt sp (('a' / 'A') ('s' / 'S')) sp SelectUnionStmt TimestampByOpt OnErrorOpt TimeoutOpt SourceSinkSpecs Action5)> */
 		func() bool {
-			position130, tokenIndex130 := position, tokenIndex
+			position158, tokenIndex158 := position, tokenIndex
 			{
-				position131 := position
+				position159 := position
 				{
-					position132, tokenIndex132 := position, tokenIndex
+					position160, tokenIndex160 := position, tokenIndex
 					if buffer[position] != rune('c') {
-						goto l133
+						goto l161
 					}
 					position++
-					goto l132
-				l133:
-					position, tokenIndex = position132, tokenIndex132
+					goto l160
+				l161:
+					position, tokenIndex = position160, tokenIndex160
 					if buffer[position] != rune('C') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l132:
+			l160:
 				{
-					position134, tokenIndex134 := position, tokenIndex
+					position162, tokenIndex162 := position, tokenIndex
 					if buffer[position] != rune('r') {
-						goto l135
+						goto l163
 					}
 					position++
-					goto l134
-				l135:
-					position, tokenIndex = position134, tokenIndex134
+					goto l162
+				l163:
+					position, tokenIndex = position162, tokenIndex162
 					if buffer[position] != rune('R') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l134:
+			l162:
 				{
-					position136, tokenIndex136 := position, tokenIndex
+					position164, tokenIndex164 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l137
+						goto l165
 					}
 					position++
-					goto l136
-				l137:
-					position, tokenIndex = position136, tokenIndex136
-					if buffer[position] != rune('E') {
-						goto l130
+					goto l164
+				l165:
+					position, tokenIndex = position164, tokenIndex164
+					if buffer[position] != rune('E') {
+						goto l158
 					}
 					position++
 				}
-			l136:
+			l164:
 				{
-					position138, tokenIndex138 := position, tokenIndex
+					position166, tokenIndex166 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l139
+						goto l167
 					}
 					position++
-					goto l138
-				l139:
-					position, tokenIndex = position138, tokenIndex138
+					goto l166
+				l167:
+					position, tokenIndex = position166, tokenIndex166
 					if buffer[position] != rune('A') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l138:
+			l166:
 				{
-					position140, tokenIndex140 := position, tokenIndex
+					position168, tokenIndex168 := position, tokenIndex
 					if buffer[position] != rune('t') {
-						goto l141
+						goto l169
 					}
 					position++
-					goto l140
-				l141:
-					position, tokenIndex = position140, tokenIndex140
+					goto l168
+				l169:
+					position, tokenIndex = position168, tokenIndex168
 					if buffer[position] != rune('T') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l140:
+			l168:
 				{
-					position142, tokenIndex142 := position, tokenIndex
+					position170, tokenIndex170 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l143
+						goto l171
 					}
 					position++
-					goto l142
-				l143:
-					position, tokenIndex = position142, tokenIndex142
+					goto l170
+				l171:
+					position, tokenIndex = position170, tokenIndex170
 					if buffer[position] != rune('E') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l142:
+			l170:
+				if !_rules[ruleTemporaryOpt]() {
+					goto l158
+				}
 				if !_rules[rulesp]() {
-					goto l130
+					goto l158
 				}
 				{
-					position144, tokenIndex144 := position, tokenIndex
+					position172, tokenIndex172 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l145
+						goto l173
 					}
 					position++
-					goto l144
-				l145:
-					position, tokenIndex = position144, tokenIndex144
+					goto l172
+				l173:
+					position, tokenIndex = position172, tokenIndex172
 					if buffer[position] != rune('S') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l144:
+			l172:
 				{
-					position146, tokenIndex146 := position, tokenIndex
+					position174, tokenIndex174 := position, tokenIndex
 					if buffer[position] != rune('t') {
-						goto l147
+						goto l175
 					}
 					position++
-					goto l146
-				l147:
-					position, tokenIndex = position146, tokenIndex146
+					goto l174
+				l175:
+					position, tokenIndex = position174, tokenIndex174
 					if buffer[position] != rune('T') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l146:
+			l174:
 				{
-					position148, tokenIndex148 := position, tokenIndex
+					position176, tokenIndex176 := position, tokenIndex
 					if buffer[position] != rune('r') {
-						goto l149
+						goto l177
 					}
 					position++
-					goto l148
-				l149:
-					position, tokenIndex = position148, tokenIndex148
+					goto l176
+				l177:
+					position, tokenIndex = position176, tokenIndex176
 					if buffer[position] != rune('R') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l148:
+			l176:
 				{
-					position150, tokenIndex150 := position, tokenIndex
+					position178, tokenIndex178 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l151
+						goto l179
 					}
 					position++
-					goto l150
-				l151:
-					position, tokenIndex = position150, tokenIndex150
+					goto l178
+				l179:
+					position, tokenIndex = position178, tokenIndex178
 					if buffer[position] != rune('E') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l150:
+			l178:
 				{
-					position152, tokenIndex152 := position, tokenIndex
+					position180, tokenIndex180 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l153
+						goto l181
 					}
 					position++
-					goto l152
-				l153:
-					position, tokenIndex = position152, tokenIndex152
+					goto l180
+				l181:
+					position, tokenIndex = position180, tokenIndex180
 					if buffer[position] != rune('A') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l152:
+			l180:
 				{
-					position154, tokenIndex154 := position, tokenIndex
+					position182, tokenIndex182 := position, tokenIndex
 					if buffer[position] != rune('m') {
-						goto l155
+						goto l183
 					}
 					position++
-					goto l154
-				l155:
-					position, tokenIndex = position154, tokenIndex154
+					goto l182
+				l183:
+					position, tokenIndex = position182, tokenIndex182
 					if buffer[position] != rune('M') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l154:
+			l182:
 				if !_rules[rulesp]() {
-					goto l130
+					goto l158
 				}
 				if !_rules[ruleStreamIdentifier]() {
-					goto l130
+					goto l158
+				}
+				if !_rules[ruleSchemaOpt]() {
+					goto l158
 				}
 				if !_rules[rulesp]() {
-					goto l130
+					goto l158
 				}
 				{
-					position156, tokenIndex156 := position, tokenIndex
+					position184, tokenIndex184 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l157
+						goto l185
 					}
 					position++
-					goto l156
-				l157:
-					position, tokenIndex = position156, tokenIndex156
+					goto l184
+				l185:
+					position, tokenIndex = position184, tokenIndex184
 					if buffer[position] != rune('A') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l156:
+			l184:
 				{
-					position158, tokenIndex158 := position, tokenIndex
+					position186, tokenIndex186 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l159
+						goto l187
 					}
 					position++
-					goto l158
-				l159:
-					position, tokenIndex = position158, tokenIndex158
+					goto l186
+				l187:
+					position, tokenIndex = position186, tokenIndex186
 					if buffer[position] != rune('S') {
-						goto l130
+						goto l158
 					}
 					position++
 				}
-			l158:
+			l186:
 				if !_rules[rulesp]() {
-					goto l130
+					goto l158
 				}
 				if !_rules[ruleSelectUnionStmt]() {
-					goto l130
+					goto l158
+				}
+				if !_rules[ruleTimestampByOpt]() {
+					goto l158
+				}
+				if !_rules[ruleOnErrorOpt]() {
+					goto l158
+				}
+				if !_rules[ruleTimeoutOpt]() {
+					goto l158
+				}
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l158
 				}
 				if !_rules[ruleAction5]() {
-					goto l130
+					goto l158
 				}
-				add(ruleCreateStreamAsSelectUnionStmt, position131)
+				add(ruleCreateStreamAsSelectUnionStmt, position159)
 			}
 			return true
-		l130:
-			position, tokenIndex = position130, tokenIndex130
+		l158:
+			position, tokenIndex = position158, tokenIndex158
 			return false
 		},
-		/* 12 CreateSourceStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') PausedOpt sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType SourceSinkSpecs Action6)> */
+		/* 16 CreateStreamRoutesStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp (('r' / 'R') ('o' / 'O') ('u' / 'U') ('t' / 'T') ('e' / 'E') ('s' / 'S')) sp (('f' / 'F') ('r' / 'R') ('o' / 'O') ('m' / 'M')) sp StreamIdentifier sp (PartitionedRoutes / ConditionalRoutes) Action6)> */
 		func() bool {
-			position160, tokenIndex160 := position, tokenIndex
+			position188, tokenIndex188 := position, tokenIndex
 			{
-				position161 := position
+				position189 := position
 				{
-					position162, tokenIndex162 := position, tokenIndex
+					position190, tokenIndex190 := position, tokenIndex
 					if buffer[position] != rune('c') {
-						goto l163
+						goto l191
 					}
 					position++
-					goto l162
-				l163:
-					position, tokenIndex = position162, tokenIndex162
+					goto l190
+				l191:
+					position, tokenIndex = position190, tokenIndex190
 					if buffer[position] != rune('C') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l162:
+			l190:
 				{
-					position164, tokenIndex164 := position, tokenIndex
+					position192, tokenIndex192 := position, tokenIndex
 					if buffer[position] != rune('r') {
-						goto l165
+						goto l193
 					}
 					position++
-					goto l164
-				l165:
-					position, tokenIndex = position164, tokenIndex164
+					goto l192
+				l193:
+					position, tokenIndex = position192, tokenIndex192
 					if buffer[position] != rune('R') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l164:
+			l192:
 				{
-					position166, tokenIndex166 := position, tokenIndex
+					position194, tokenIndex194 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l167
+						goto l195
 					}
 					position++
-					goto l166
-				l167:
-					position, tokenIndex = position166, tokenIndex166
+					goto l194
+				l195:
+					position, tokenIndex = position194, tokenIndex194
 					if buffer[position] != rune('E') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l166:
+			l194:
 				{
-					position168, tokenIndex168 := position, tokenIndex
+					position196, tokenIndex196 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l169
+						goto l197
 					}
 					position++
-					goto l168
-				l169:
-					position, tokenIndex = position168, tokenIndex168
+					goto l196
+				l197:
+					position, tokenIndex = position196, tokenIndex196
 					if buffer[position] != rune('A') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l168:
+			l196:
 				{
-					position170, tokenIndex170 := position, tokenIndex
+					position198, tokenIndex198 := position, tokenIndex
 					if buffer[position] != rune('t') {
-						goto l171
+						goto l199
 					}
 					position++
-					goto l170
-				l171:
-					position, tokenIndex = position170, tokenIndex170
+					goto l198
+				l199:
+					position, tokenIndex = position198, tokenIndex198
 					if buffer[position] != rune('T') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l170:
+			l198:
 				{
-					position172, tokenIndex172 := position, tokenIndex
+					position200, tokenIndex200 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l173
+						goto l201
 					}
 					position++
-					goto l172
-				l173:
-					position, tokenIndex = position172, tokenIndex172
+					goto l200
+				l201:
+					position, tokenIndex = position200, tokenIndex200
 					if buffer[position] != rune('E') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l172:
-				if !_rules[rulePausedOpt]() {
-					goto l160
-				}
+			l200:
 				if !_rules[rulesp]() {
-					goto l160
+					goto l188
 				}
 				{
-					position174, tokenIndex174 := position, tokenIndex
+					position202, tokenIndex202 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l175
+						goto l203
 					}
 					position++
-					goto l174
-				l175:
-					position, tokenIndex = position174, tokenIndex174
+					goto l202
+				l203:
+					position, tokenIndex = position202, tokenIndex202
 					if buffer[position] != rune('S') {
-						goto l160
+						goto l188
 					}
 					position++
 				}
-			l174:
+			l202:
 				{
-					position176, tokenIndex176 := position, tokenIndex
-					if buffer[position] != rune('o') {
-						goto l177
-					}
-					position++
-					goto l176
-				l177:
-					position, tokenIndex = position176, tokenIndex176
-					if buffer[position] != rune('O') {
-						goto l160
-					}
-					position++
-				}
-			l176:
-				{
-					position178, tokenIndex178 := position, tokenIndex
-					if buffer[position] != rune('u') {
-						goto l179
-					}
-					position++
-					goto l178
-				l179:
-					position, tokenIndex = position178, tokenIndex178
-					if buffer[position] != rune('U') {
-						goto l160
-					}
-					position++
-				}
-			l178:
-				{
-					position180, tokenIndex180 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l181
-					}
-					position++
-					goto l180
-				l181:
-					position, tokenIndex = position180, tokenIndex180
-					if buffer[position] != rune('R') {
-						goto l160
-					}
-					position++
-				}
-			l180:
-				{
-					position182, tokenIndex182 := position, tokenIndex
-					if buffer[position] != rune('c') {
-						goto l183
-					}
-					position++
-					goto l182
-				l183:
-					position, tokenIndex = position182, tokenIndex182
-					if buffer[position] != rune('C') {
-						goto l160
-					}
-					position++
-				}
-			l182:
-				{
-					position184, tokenIndex184 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l185
-					}
-					position++
-					goto l184
-				l185:
-					position, tokenIndex = position184, tokenIndex184
-					if buffer[position] != rune('E') {
-						goto l160
-					}
-					position++
-				}
-			l184:
-				if !_rules[rulesp]() {
-					goto l160
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l160
-				}
-				if !_rules[rulesp]() {
-					goto l160
-				}
-				{
-					position186, tokenIndex186 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l187
-					}
-					position++
-					goto l186
-				l187:
-					position, tokenIndex = position186, tokenIndex186
-					if buffer[position] != rune('T') {
-						goto l160
-					}
-					position++
-				}
-			l186:
-				{
-					position188, tokenIndex188 := position, tokenIndex
-					if buffer[position] != rune('y') {
-						goto l189
-					}
-					position++
-					goto l188
-				l189:
-					position, tokenIndex = position188, tokenIndex188
-					if buffer[position] != rune('Y') {
-						goto l160
-					}
-					position++
-				}
-			l188:
-				{
-					position190, tokenIndex190 := position, tokenIndex
-					if buffer[position] != rune('p') {
-						goto l191
-					}
-					position++
-					goto l190
-				l191:
-					position, tokenIndex = position190, tokenIndex190
-					if buffer[position] != rune('P') {
-						goto l160
-					}
-					position++
-				}
-			l190:
-				{
-					position192, tokenIndex192 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l193
-					}
-					position++
-					goto l192
-				l193:
-					position, tokenIndex = position192, tokenIndex192
-					if buffer[position] != rune('E') {
-						goto l160
-					}
-					position++
-				}
-			l192:
-				if !_rules[rulesp]() {
-					goto l160
-				}
-				if !_rules[ruleSourceSinkType]() {
-					goto l160
-				}
-				if !_rules[ruleSourceSinkSpecs]() {
-					goto l160
-				}
-				if !_rules[ruleAction6]() {
-					goto l160
-				}
-				add(ruleCreateSourceStmt, position161)
-			}
-			return true
-		l160:
-			position, tokenIndex = position160, tokenIndex160
-			return false
-		},
-		/* 13 CreateSinkStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType SourceSinkSpecs Action7)> */
-		func() bool {
-			position194, tokenIndex194 := position, tokenIndex
-			{
-				position195 := position
-				{
-					position196, tokenIndex196 := position, tokenIndex
-					if buffer[position] != rune('c') {
-						goto l197
-					}
-					position++
-					goto l196
-				l197:
-					position, tokenIndex = position196, tokenIndex196
-					if buffer[position] != rune('C') {
-						goto l194
-					}
-					position++
-				}
-			l196:
-				{
-					position198, tokenIndex198 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l199
-					}
-					position++
-					goto l198
-				l199:
-					position, tokenIndex = position198, tokenIndex198
-					if buffer[position] != rune('R') {
-						goto l194
-					}
-					position++
-				}
-			l198:
-				{
-					position200, tokenIndex200 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l201
-					}
-					position++
-					goto l200
-				l201:
-					position, tokenIndex = position200, tokenIndex200
-					if buffer[position] != rune('E') {
-						goto l194
-					}
-					position++
-				}
-			l200:
-				{
-					position202, tokenIndex202 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l203
-					}
-					position++
-					goto l202
-				l203:
-					position, tokenIndex = position202, tokenIndex202
-					if buffer[position] != rune('A') {
-						goto l194
-					}
-					position++
-				}
-			l202:
-				{
-					position204, tokenIndex204 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l205
+					position204, tokenIndex204 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l205
 					}
 					position++
 					goto l204
 				l205:
 					position, tokenIndex = position204, tokenIndex204
 					if buffer[position] != rune('T') {
-						goto l194
+						goto l188
 					}
 					position++
 				}
 			l204:
 				{
 					position206, tokenIndex206 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('r') {
 						goto l207
 					}
 					position++
 					goto l206
 				l207:
 					position, tokenIndex = position206, tokenIndex206
-					if buffer[position] != rune('E') {
-						goto l194
+					if buffer[position] != rune('R') {
+						goto l188
 					}
 					position++
 				}
 			l206:
-				if !_rules[rulesp]() {
-					goto l194
-				}
 				{
 					position208, tokenIndex208 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('e') {
 						goto l209
 					}
 					position++
 					goto l208
 				l209:
 					position, tokenIndex = position208, tokenIndex208
-					if buffer[position] != rune('S') {
-						goto l194
+					if buffer[position] != rune('E') {
+						goto l188
 					}
 					position++
 				}
 			l208:
 				{
 					position210, tokenIndex210 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					if buffer[position] != rune('a') {
 						goto l211
 					}
 					position++
 					goto l210
 				l211:
 					position, tokenIndex = position210, tokenIndex210
-					if buffer[position] != rune('I') {
-						goto l194
+					if buffer[position] != rune('A') {
+						goto l188
 					}
 					position++
 				}
 			l210:
 				{
 					position212, tokenIndex212 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('m') {
 						goto l213
 					}
 					position++
 					goto l212
 				l213:
 					position, tokenIndex = position212, tokenIndex212
-					if buffer[position] != rune('N') {
-						goto l194
+					if buffer[position] != rune('M') {
+						goto l188
 					}
 					position++
 				}
 			l212:
+				if !_rules[rulesp]() {
+					goto l188
+				}
 				{
 					position214, tokenIndex214 := position, tokenIndex
-					if buffer[position] != rune('k') {
+					if buffer[position] != rune('r') {
 						goto l215
 					}
 					position++
 					goto l214
 				l215:
 					position, tokenIndex = position214, tokenIndex214
-					if buffer[position] != rune('K') {
-						goto l194
+					if buffer[position] != rune('R') {
+						goto l188
 					}
 					position++
 				}
 			l214:
-				if !_rules[rulesp]() {
-					goto l194
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l194
-				}
-				if !_rules[rulesp]() {
-					goto l194
-				}
 				{
 					position216, tokenIndex216 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('o') {
 						goto l217
 					}
 					position++
 					goto l216
 				l217:
 					position, tokenIndex = position216, tokenIndex216
-					if buffer[position] != rune('T') {
-						goto l194
+					if buffer[position] != rune('O') {
+						goto l188
 					}
 					position++
 				}
 			l216:
 				{
 					position218, tokenIndex218 := position, tokenIndex
-					if buffer[position] != rune('y') {
+					if buffer[position] != rune('u') {
 						goto l219
 					}
 					position++
 					goto l218
 				l219:
 					position, tokenIndex = position218, tokenIndex218
-					if buffer[position] != rune('Y') {
-						goto l194
+					if buffer[position] != rune('U') {
+						goto l188
 					}
 					position++
 				}
 			l218:
 				{
 					position220, tokenIndex220 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('t') {
 						goto l221
 					}
 					position++
 					goto l220
 				l221:
 					position, tokenIndex = position220, tokenIndex220
-					if buffer[position] != rune('P') {
-						goto l194
+					if buffer[position] != rune('T') {
+						goto l188
 					}
 					position++
 				}
@@ -3247,46 +3707,40 @@ func (p *bqlPegBackend) Init() {
 				l223:
 					position, tokenIndex = position222, tokenIndex222
 					if buffer[position] != rune('E') {
-						goto l194
+						goto l188
 					}
 					position++
 				}
 			l222:
+				{
+					position224, tokenIndex224 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l225
+					}
+					position++
+					goto l224
+				l225:
+					position, tokenIndex = position224, tokenIndex224
+					if buffer[position] != rune('S') {
+						goto l188
+					}
+					position++
+				}
+			l224:
 				if !_rules[rulesp]() {
-					goto l194
+					goto l188
 				}
-				if !_rules[ruleSourceSinkType]() {
-					goto l194
-				}
-				if !_rules[ruleSourceSinkSpecs]() {
-					goto l194
-				}
-				if !_rules[ruleAction7]() {
-					goto l194
-				}
-				add(ruleCreateSinkStmt, position195)
-			}
-			return true
-		l194:
-			position, tokenIndex = position194, tokenIndex194
-			return false
-		},
-		/* 14 CreateStateStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType SourceSinkSpecs Action8)> */
-		func() bool {
-			position224, tokenIndex224 := position, tokenIndex
-			{
-				position225 := position
 				{
 					position226, tokenIndex226 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					if buffer[position] != rune('f') {
 						goto l227
 					}
 					position++
 					goto l226
 				l227:
 					position, tokenIndex = position226, tokenIndex226
-					if buffer[position] != rune('C') {
-						goto l224
+					if buffer[position] != rune('F') {
+						goto l188
 					}
 					position++
 				}
@@ -3301,874 +3755,872 @@ func (p *bqlPegBackend) Init() {
 				l229:
 					position, tokenIndex = position228, tokenIndex228
 					if buffer[position] != rune('R') {
-						goto l224
+						goto l188
 					}
 					position++
 				}
 			l228:
 				{
 					position230, tokenIndex230 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('o') {
 						goto l231
 					}
 					position++
 					goto l230
 				l231:
 					position, tokenIndex = position230, tokenIndex230
-					if buffer[position] != rune('E') {
-						goto l224
+					if buffer[position] != rune('O') {
+						goto l188
 					}
 					position++
 				}
 			l230:
 				{
 					position232, tokenIndex232 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('m') {
 						goto l233
 					}
 					position++
 					goto l232
 				l233:
 					position, tokenIndex = position232, tokenIndex232
-					if buffer[position] != rune('A') {
-						goto l224
+					if buffer[position] != rune('M') {
+						goto l188
 					}
 					position++
 				}
 			l232:
+				if !_rules[rulesp]() {
+					goto l188
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l188
+				}
+				if !_rules[rulesp]() {
+					goto l188
+				}
 				{
 					position234, tokenIndex234 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if !_rules[rulePartitionedRoutes]() {
 						goto l235
 					}
-					position++
 					goto l234
 				l235:
 					position, tokenIndex = position234, tokenIndex234
-					if buffer[position] != rune('T') {
-						goto l224
+					if !_rules[ruleConditionalRoutes]() {
+						goto l188
 					}
-					position++
 				}
 			l234:
-				{
-					position236, tokenIndex236 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l237
-					}
-					position++
-					goto l236
-				l237:
-					position, tokenIndex = position236, tokenIndex236
-					if buffer[position] != rune('E') {
-						goto l224
-					}
-					position++
-				}
-			l236:
-				if !_rules[rulesp]() {
-					goto l224
+				if !_rules[ruleAction6]() {
+					goto l188
 				}
+				add(ruleCreateStreamRoutesStmt, position189)
+			}
+			return true
+		l188:
+			position, tokenIndex = position188, tokenIndex188
+			return false
+		},
+		/* 17 PartitionedRoutes <- <(<(('p' / 'P') ('a' / 'A') ('r' / 'R') ('t' / 'T') ('i' / 'I') ('t' / 'T') ('i' / 'I') ('o' / 'O') ('n' / 'N') ('e' / 'E') ('d' / 'D') sp (('b' / 'B') ('y' / 'Y')) sp Expression sp (('i' / 'I') ('n' / 'N') ('t' / 'T') ('o' / 'O')) sp StreamIdentifier (spOpt ',' spOpt StreamIdentifier)*)> Action7)> */
+		func() bool {
+			position236, tokenIndex236 := position, tokenIndex
+			{
+				position237 := position
 				{
-					position238, tokenIndex238 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					position238 := position
+					{
+						position239, tokenIndex239 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l240
+						}
+						position++
 						goto l239
+					l240:
+						position, tokenIndex = position239, tokenIndex239
+						if buffer[position] != rune('P') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l238
 				l239:
-					position, tokenIndex = position238, tokenIndex238
-					if buffer[position] != rune('S') {
-						goto l224
-					}
-					position++
-				}
-			l238:
-				{
-					position240, tokenIndex240 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					{
+						position241, tokenIndex241 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l242
+						}
+						position++
 						goto l241
+					l242:
+						position, tokenIndex = position241, tokenIndex241
+						if buffer[position] != rune('A') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l240
 				l241:
-					position, tokenIndex = position240, tokenIndex240
-					if buffer[position] != rune('T') {
-						goto l224
-					}
-					position++
-				}
-			l240:
-				{
-					position242, tokenIndex242 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					{
+						position243, tokenIndex243 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l244
+						}
+						position++
 						goto l243
+					l244:
+						position, tokenIndex = position243, tokenIndex243
+						if buffer[position] != rune('R') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l242
 				l243:
-					position, tokenIndex = position242, tokenIndex242
-					if buffer[position] != rune('A') {
-						goto l224
-					}
-					position++
-				}
-			l242:
-				{
-					position244, tokenIndex244 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					{
+						position245, tokenIndex245 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l246
+						}
+						position++
 						goto l245
+					l246:
+						position, tokenIndex = position245, tokenIndex245
+						if buffer[position] != rune('T') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l244
 				l245:
-					position, tokenIndex = position244, tokenIndex244
-					if buffer[position] != rune('T') {
-						goto l224
-					}
-					position++
-				}
-			l244:
-				{
-					position246, tokenIndex246 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					{
+						position247, tokenIndex247 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l248
+						}
+						position++
 						goto l247
+					l248:
+						position, tokenIndex = position247, tokenIndex247
+						if buffer[position] != rune('I') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l246
 				l247:
-					position, tokenIndex = position246, tokenIndex246
-					if buffer[position] != rune('E') {
-						goto l224
-					}
-					position++
-				}
-			l246:
-				if !_rules[rulesp]() {
-					goto l224
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l224
-				}
-				if !_rules[rulesp]() {
-					goto l224
-				}
-				{
-					position248, tokenIndex248 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					{
+						position249, tokenIndex249 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l250
+						}
+						position++
 						goto l249
+					l250:
+						position, tokenIndex = position249, tokenIndex249
+						if buffer[position] != rune('T') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l248
 				l249:
-					position, tokenIndex = position248, tokenIndex248
-					if buffer[position] != rune('T') {
-						goto l224
-					}
-					position++
-				}
-			l248:
-				{
-					position250, tokenIndex250 := position, tokenIndex
-					if buffer[position] != rune('y') {
+					{
+						position251, tokenIndex251 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l252
+						}
+						position++
 						goto l251
+					l252:
+						position, tokenIndex = position251, tokenIndex251
+						if buffer[position] != rune('I') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l250
 				l251:
-					position, tokenIndex = position250, tokenIndex250
-					if buffer[position] != rune('Y') {
-						goto l224
-					}
-					position++
-				}
-			l250:
-				{
-					position252, tokenIndex252 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					{
+						position253, tokenIndex253 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l254
+						}
+						position++
 						goto l253
+					l254:
+						position, tokenIndex = position253, tokenIndex253
+						if buffer[position] != rune('O') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l252
 				l253:
-					position, tokenIndex = position252, tokenIndex252
-					if buffer[position] != rune('P') {
-						goto l224
-					}
-					position++
-				}
-			l252:
-				{
-					position254, tokenIndex254 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					{
+						position255, tokenIndex255 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l256
+						}
+						position++
 						goto l255
+					l256:
+						position, tokenIndex = position255, tokenIndex255
+						if buffer[position] != rune('N') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l254
 				l255:
-					position, tokenIndex = position254, tokenIndex254
-					if buffer[position] != rune('E') {
-						goto l224
+					{
+						position257, tokenIndex257 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l258
+						}
+						position++
+						goto l257
+					l258:
+						position, tokenIndex = position257, tokenIndex257
+						if buffer[position] != rune('E') {
+							goto l236
+						}
+						position++
 					}
-					position++
-				}
-			l254:
-				if !_rules[rulesp]() {
-					goto l224
-				}
-				if !_rules[ruleSourceSinkType]() {
-					goto l224
-				}
-				if !_rules[ruleSourceSinkSpecs]() {
-					goto l224
-				}
-				if !_rules[ruleAction8]() {
-					goto l224
-				}
-				add(ruleCreateStateStmt, position225)
-			}
-			return true
-		l224:
-			position, tokenIndex = position224, tokenIndex224
-			return false
-		},
-		/* 15 UpdateStateStmt <- <(('u' / 'U') ('p' / 'P') ('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier UpdateSourceSinkSpecs Action9)> */
-		func() bool {
-			position256, tokenIndex256 := position, tokenIndex
-			{
-				position257 := position
-				{
-					position258, tokenIndex258 := position, tokenIndex
-					if buffer[position] != rune('u') {
+				l257:
+					{
+						position259, tokenIndex259 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l260
+						}
+						position++
 						goto l259
+					l260:
+						position, tokenIndex = position259, tokenIndex259
+						if buffer[position] != rune('D') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l258
 				l259:
-					position, tokenIndex = position258, tokenIndex258
-					if buffer[position] != rune('U') {
-						goto l256
+					if !_rules[rulesp]() {
+						goto l236
 					}
-					position++
-				}
-			l258:
-				{
-					position260, tokenIndex260 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					{
+						position261, tokenIndex261 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l262
+						}
+						position++
 						goto l261
+					l262:
+						position, tokenIndex = position261, tokenIndex261
+						if buffer[position] != rune('B') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l260
 				l261:
-					position, tokenIndex = position260, tokenIndex260
-					if buffer[position] != rune('P') {
-						goto l256
-					}
-					position++
-				}
-			l260:
-				{
-					position262, tokenIndex262 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					{
+						position263, tokenIndex263 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l264
+						}
+						position++
 						goto l263
+					l264:
+						position, tokenIndex = position263, tokenIndex263
+						if buffer[position] != rune('Y') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l262
 				l263:
-					position, tokenIndex = position262, tokenIndex262
-					if buffer[position] != rune('D') {
-						goto l256
+					if !_rules[rulesp]() {
+						goto l236
 					}
-					position++
-				}
-			l262:
-				{
-					position264, tokenIndex264 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if !_rules[ruleExpression]() {
+						goto l236
+					}
+					if !_rules[rulesp]() {
+						goto l236
+					}
+					{
+						position265, tokenIndex265 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l266
+						}
+						position++
 						goto l265
+					l266:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('I') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l264
 				l265:
-					position, tokenIndex = position264, tokenIndex264
-					if buffer[position] != rune('A') {
-						goto l256
-					}
-					position++
-				}
-			l264:
-				{
-					position266, tokenIndex266 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					{
+						position267, tokenIndex267 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l268
+						}
+						position++
 						goto l267
+					l268:
+						position, tokenIndex = position267, tokenIndex267
+						if buffer[position] != rune('N') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l266
 				l267:
-					position, tokenIndex = position266, tokenIndex266
-					if buffer[position] != rune('T') {
-						goto l256
-					}
-					position++
-				}
-			l266:
-				{
-					position268, tokenIndex268 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					{
+						position269, tokenIndex269 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l270
+						}
+						position++
 						goto l269
+					l270:
+						position, tokenIndex = position269, tokenIndex269
+						if buffer[position] != rune('T') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l268
 				l269:
-					position, tokenIndex = position268, tokenIndex268
-					if buffer[position] != rune('E') {
-						goto l256
-					}
-					position++
-				}
-			l268:
-				if !_rules[rulesp]() {
-					goto l256
-				}
-				{
-					position270, tokenIndex270 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					{
+						position271, tokenIndex271 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l272
+						}
+						position++
 						goto l271
+					l272:
+						position, tokenIndex = position271, tokenIndex271
+						if buffer[position] != rune('O') {
+							goto l236
+						}
+						position++
 					}
-					position++
-					goto l270
 				l271:
-					position, tokenIndex = position270, tokenIndex270
-					if buffer[position] != rune('S') {
-						goto l256
+					if !_rules[rulesp]() {
+						goto l236
 					}
-					position++
-				}
-			l270:
-				{
-					position272, tokenIndex272 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l273
+					if !_rules[ruleStreamIdentifier]() {
+						goto l236
 					}
-					position++
-					goto l272
 				l273:
-					position, tokenIndex = position272, tokenIndex272
-					if buffer[position] != rune('T') {
-						goto l256
+					{
+						position274, tokenIndex274 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l274
+						}
+						if buffer[position] != rune(',') {
+							goto l274
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l274
+						}
+						if !_rules[ruleStreamIdentifier]() {
+							goto l274
+						}
+						goto l273
+					l274:
+						position, tokenIndex = position274, tokenIndex274
 					}
-					position++
+					add(rulePegText, position238)
 				}
-			l272:
-				{
-					position274, tokenIndex274 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l275
-					}
-					position++
-					goto l274
-				l275:
-					position, tokenIndex = position274, tokenIndex274
-					if buffer[position] != rune('A') {
-						goto l256
-					}
-					position++
+				if !_rules[ruleAction7]() {
+					goto l236
 				}
-			l274:
+				add(rulePartitionedRoutes, position237)
+			}
+			return true
+		l236:
+			position, tokenIndex = position236, tokenIndex236
+			return false
+		},
+		/* 18 ConditionalRoutes <- <(<(ConditionalRoute (sp ConditionalRoute)* (sp ElseRoute)?)> Action8)> */
+		func() bool {
+			position275, tokenIndex275 := position, tokenIndex
+			{
+				position276 := position
 				{
-					position276, tokenIndex276 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l277
-					}
-					position++
-					goto l276
-				l277:
-					position, tokenIndex = position276, tokenIndex276
-					if buffer[position] != rune('T') {
-						goto l256
+					position277 := position
+					if !_rules[ruleConditionalRoute]() {
+						goto l275
 					}
-					position++
-				}
-			l276:
-				{
-					position278, tokenIndex278 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l279
+				l278:
+					{
+						position279, tokenIndex279 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l279
+						}
+						if !_rules[ruleConditionalRoute]() {
+							goto l279
+						}
+						goto l278
+					l279:
+						position, tokenIndex = position279, tokenIndex279
 					}
-					position++
-					goto l278
-				l279:
-					position, tokenIndex = position278, tokenIndex278
-					if buffer[position] != rune('E') {
-						goto l256
+					{
+						position280, tokenIndex280 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l280
+						}
+						if !_rules[ruleElseRoute]() {
+							goto l280
+						}
+						goto l281
+					l280:
+						position, tokenIndex = position280, tokenIndex280
 					}
-					position++
-				}
-			l278:
-				if !_rules[rulesp]() {
-					goto l256
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l256
-				}
-				if !_rules[ruleUpdateSourceSinkSpecs]() {
-					goto l256
+				l281:
+					add(rulePegText, position277)
 				}
-				if !_rules[ruleAction9]() {
-					goto l256
+				if !_rules[ruleAction8]() {
+					goto l275
 				}
-				add(ruleUpdateStateStmt, position257)
+				add(ruleConditionalRoutes, position276)
 			}
 			return true
-		l256:
-			position, tokenIndex = position256, tokenIndex256
+		l275:
+			position, tokenIndex = position275, tokenIndex275
 			return false
 		},
-		/* 16 UpdateSourceStmt <- <(('u' / 'U') ('p' / 'P') ('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier UpdateSourceSinkSpecs Action10)> */
+		/* 19 ConditionalRoute <- <(('w' / 'W') ('h' / 'H') ('e' / 'E') ('n' / 'N') sp Expression sp (('i' / 'I') ('n' / 'N') ('t' / 'T') ('o' / 'O')) sp StreamIdentifier Action9)> */
 		func() bool {
-			position280, tokenIndex280 := position, tokenIndex
+			position282, tokenIndex282 := position, tokenIndex
 			{
-				position281 := position
-				{
-					position282, tokenIndex282 := position, tokenIndex
-					if buffer[position] != rune('u') {
-						goto l283
-					}
-					position++
-					goto l282
-				l283:
-					position, tokenIndex = position282, tokenIndex282
-					if buffer[position] != rune('U') {
-						goto l280
-					}
-					position++
-				}
-			l282:
+				position283 := position
 				{
 					position284, tokenIndex284 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('w') {
 						goto l285
 					}
 					position++
 					goto l284
 				l285:
 					position, tokenIndex = position284, tokenIndex284
-					if buffer[position] != rune('P') {
-						goto l280
+					if buffer[position] != rune('W') {
+						goto l282
 					}
 					position++
 				}
 			l284:
 				{
 					position286, tokenIndex286 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					if buffer[position] != rune('h') {
 						goto l287
 					}
 					position++
 					goto l286
 				l287:
 					position, tokenIndex = position286, tokenIndex286
-					if buffer[position] != rune('D') {
-						goto l280
+					if buffer[position] != rune('H') {
+						goto l282
 					}
 					position++
 				}
 			l286:
 				{
 					position288, tokenIndex288 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('e') {
 						goto l289
 					}
 					position++
 					goto l288
 				l289:
 					position, tokenIndex = position288, tokenIndex288
-					if buffer[position] != rune('A') {
-						goto l280
+					if buffer[position] != rune('E') {
+						goto l282
 					}
 					position++
 				}
 			l288:
 				{
 					position290, tokenIndex290 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('n') {
 						goto l291
 					}
 					position++
 					goto l290
 				l291:
 					position, tokenIndex = position290, tokenIndex290
-					if buffer[position] != rune('T') {
-						goto l280
+					if buffer[position] != rune('N') {
+						goto l282
 					}
 					position++
 				}
 			l290:
+				if !_rules[rulesp]() {
+					goto l282
+				}
+				if !_rules[ruleExpression]() {
+					goto l282
+				}
+				if !_rules[rulesp]() {
+					goto l282
+				}
 				{
 					position292, tokenIndex292 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('i') {
 						goto l293
 					}
 					position++
 					goto l292
 				l293:
 					position, tokenIndex = position292, tokenIndex292
-					if buffer[position] != rune('E') {
-						goto l280
+					if buffer[position] != rune('I') {
+						goto l282
 					}
 					position++
 				}
 			l292:
-				if !_rules[rulesp]() {
-					goto l280
-				}
 				{
 					position294, tokenIndex294 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('n') {
 						goto l295
 					}
 					position++
 					goto l294
 				l295:
 					position, tokenIndex = position294, tokenIndex294
-					if buffer[position] != rune('S') {
-						goto l280
+					if buffer[position] != rune('N') {
+						goto l282
 					}
 					position++
 				}
 			l294:
 				{
 					position296, tokenIndex296 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('t') {
 						goto l297
 					}
 					position++
 					goto l296
 				l297:
 					position, tokenIndex = position296, tokenIndex296
-					if buffer[position] != rune('O') {
-						goto l280
+					if buffer[position] != rune('T') {
+						goto l282
 					}
 					position++
 				}
 			l296:
 				{
 					position298, tokenIndex298 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('o') {
 						goto l299
 					}
 					position++
 					goto l298
 				l299:
 					position, tokenIndex = position298, tokenIndex298
-					if buffer[position] != rune('U') {
-						goto l280
+					if buffer[position] != rune('O') {
+						goto l282
 					}
 					position++
 				}
 			l298:
-				{
-					position300, tokenIndex300 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l301
-					}
-					position++
-					goto l300
-				l301:
-					position, tokenIndex = position300, tokenIndex300
-					if buffer[position] != rune('R') {
-						goto l280
-					}
-					position++
+				if !_rules[rulesp]() {
+					goto l282
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l282
+				}
+				if !_rules[ruleAction9]() {
+					goto l282
 				}
-			l300:
+				add(ruleConditionalRoute, position283)
+			}
+			return true
+		l282:
+			position, tokenIndex = position282, tokenIndex282
+			return false
+		},
+		/* 20 ElseRoute <- <(('e' / 'E') ('l' / 'L') ('s' / 'S') ('e' / 'E') sp (('i' / 'I') ('n' / 'N') ('t' / 'T') ('o' / 'O')) sp StreamIdentifier Action10)> */
+		func() bool {
+			position300, tokenIndex300 := position, tokenIndex
+			{
+				position301 := position
 				{
 					position302, tokenIndex302 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					if buffer[position] != rune('e') {
 						goto l303
 					}
 					position++
 					goto l302
 				l303:
 					position, tokenIndex = position302, tokenIndex302
-					if buffer[position] != rune('C') {
-						goto l280
+					if buffer[position] != rune('E') {
+						goto l300
 					}
 					position++
 				}
 			l302:
 				{
 					position304, tokenIndex304 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('l') {
 						goto l305
 					}
 					position++
 					goto l304
 				l305:
 					position, tokenIndex = position304, tokenIndex304
-					if buffer[position] != rune('E') {
-						goto l280
+					if buffer[position] != rune('L') {
+						goto l300
 					}
 					position++
 				}
 			l304:
-				if !_rules[rulesp]() {
-					goto l280
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l280
-				}
-				if !_rules[ruleUpdateSourceSinkSpecs]() {
-					goto l280
-				}
-				if !_rules[ruleAction10]() {
-					goto l280
+				{
+					position306, tokenIndex306 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l307
+					}
+					position++
+					goto l306
+				l307:
+					position, tokenIndex = position306, tokenIndex306
+					if buffer[position] != rune('S') {
+						goto l300
+					}
+					position++
 				}
-				add(ruleUpdateSourceStmt, position281)
-			}
-			return true
-		l280:
-			position, tokenIndex = position280, tokenIndex280
-			return false
-		},
-		/* 17 UpdateSinkStmt <- <(('u' / 'U') ('p' / 'P') ('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier UpdateSourceSinkSpecs Action11)> */
-		func() bool {
-			position306, tokenIndex306 := position, tokenIndex
-			{
-				position307 := position
+			l306:
 				{
 					position308, tokenIndex308 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('e') {
 						goto l309
 					}
 					position++
 					goto l308
 				l309:
 					position, tokenIndex = position308, tokenIndex308
-					if buffer[position] != rune('U') {
-						goto l306
+					if buffer[position] != rune('E') {
+						goto l300
 					}
 					position++
 				}
 			l308:
+				if !_rules[rulesp]() {
+					goto l300
+				}
 				{
 					position310, tokenIndex310 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('i') {
 						goto l311
 					}
 					position++
 					goto l310
 				l311:
 					position, tokenIndex = position310, tokenIndex310
-					if buffer[position] != rune('P') {
-						goto l306
+					if buffer[position] != rune('I') {
+						goto l300
 					}
 					position++
 				}
 			l310:
 				{
 					position312, tokenIndex312 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					if buffer[position] != rune('n') {
 						goto l313
 					}
 					position++
 					goto l312
 				l313:
 					position, tokenIndex = position312, tokenIndex312
-					if buffer[position] != rune('D') {
-						goto l306
+					if buffer[position] != rune('N') {
+						goto l300
 					}
 					position++
 				}
 			l312:
 				{
 					position314, tokenIndex314 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('t') {
 						goto l315
 					}
 					position++
 					goto l314
 				l315:
 					position, tokenIndex = position314, tokenIndex314
-					if buffer[position] != rune('A') {
-						goto l306
+					if buffer[position] != rune('T') {
+						goto l300
 					}
 					position++
 				}
 			l314:
 				{
 					position316, tokenIndex316 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('o') {
 						goto l317
 					}
 					position++
 					goto l316
 				l317:
 					position, tokenIndex = position316, tokenIndex316
-					if buffer[position] != rune('T') {
-						goto l306
+					if buffer[position] != rune('O') {
+						goto l300
 					}
 					position++
 				}
 			l316:
-				{
-					position318, tokenIndex318 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l319
-					}
-					position++
-					goto l318
-				l319:
-					position, tokenIndex = position318, tokenIndex318
-					if buffer[position] != rune('E') {
-						goto l306
-					}
-					position++
-				}
-			l318:
 				if !_rules[rulesp]() {
-					goto l306
+					goto l300
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l300
+				}
+				if !_rules[ruleAction10]() {
+					goto l300
 				}
+				add(ruleElseRoute, position301)
+			}
+			return true
+		l300:
+			position, tokenIndex = position300, tokenIndex300
+			return false
+		},
+		/* 21 CreateSourceStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') TemporaryOpt PausedOpt sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType SchemaOpt InstancesOpt SourceSinkSpecs TimestampByOpt Action11)> */
+		func() bool {
+			position318, tokenIndex318 := position, tokenIndex
+			{
+				position319 := position
 				{
 					position320, tokenIndex320 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('c') {
 						goto l321
 					}
 					position++
 					goto l320
 				l321:
 					position, tokenIndex = position320, tokenIndex320
-					if buffer[position] != rune('S') {
-						goto l306
+					if buffer[position] != rune('C') {
+						goto l318
 					}
 					position++
 				}
 			l320:
 				{
 					position322, tokenIndex322 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					if buffer[position] != rune('r') {
 						goto l323
 					}
 					position++
 					goto l322
 				l323:
 					position, tokenIndex = position322, tokenIndex322
-					if buffer[position] != rune('I') {
-						goto l306
+					if buffer[position] != rune('R') {
+						goto l318
 					}
 					position++
 				}
 			l322:
 				{
 					position324, tokenIndex324 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('e') {
 						goto l325
 					}
 					position++
 					goto l324
 				l325:
 					position, tokenIndex = position324, tokenIndex324
-					if buffer[position] != rune('N') {
-						goto l306
+					if buffer[position] != rune('E') {
+						goto l318
 					}
 					position++
 				}
 			l324:
 				{
 					position326, tokenIndex326 := position, tokenIndex
-					if buffer[position] != rune('k') {
+					if buffer[position] != rune('a') {
 						goto l327
 					}
 					position++
 					goto l326
 				l327:
 					position, tokenIndex = position326, tokenIndex326
-					if buffer[position] != rune('K') {
-						goto l306
+					if buffer[position] != rune('A') {
+						goto l318
 					}
 					position++
 				}
 			l326:
-				if !_rules[rulesp]() {
-					goto l306
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l306
-				}
-				if !_rules[ruleUpdateSourceSinkSpecs]() {
-					goto l306
-				}
-				if !_rules[ruleAction11]() {
-					goto l306
+				{
+					position328, tokenIndex328 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l329
+					}
+					position++
+					goto l328
+				l329:
+					position, tokenIndex = position328, tokenIndex328
+					if buffer[position] != rune('T') {
+						goto l318
+					}
+					position++
 				}
-				add(ruleUpdateSinkStmt, position307)
-			}
-			return true
-		l306:
-			position, tokenIndex = position306, tokenIndex306
-			return false
-		},
-		/* 18 InsertIntoFromStmt <- <(('i' / 'I') ('n' / 'N') ('s' / 'S') ('e' / 'E') ('r' / 'R') ('t' / 'T') sp (('i' / 'I') ('n' / 'N') ('t' / 'T') ('o' / 'O')) sp StreamIdentifier sp (('f' / 'F') ('r' / 'R') ('o' / 'O') ('m' / 'M')) sp StreamIdentifier Action12)> */
-		func() bool {
-			position328, tokenIndex328 := position, tokenIndex
-			{
-				position329 := position
+			l328:
 				{
 					position330, tokenIndex330 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					if buffer[position] != rune('e') {
 						goto l331
 					}
 					position++
 					goto l330
 				l331:
 					position, tokenIndex = position330, tokenIndex330
-					if buffer[position] != rune('I') {
-						goto l328
+					if buffer[position] != rune('E') {
+						goto l318
 					}
 					position++
 				}
 			l330:
+				if !_rules[ruleTemporaryOpt]() {
+					goto l318
+				}
+				if !_rules[rulePausedOpt]() {
+					goto l318
+				}
+				if !_rules[rulesp]() {
+					goto l318
+				}
 				{
 					position332, tokenIndex332 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('s') {
 						goto l333
 					}
 					position++
 					goto l332
 				l333:
 					position, tokenIndex = position332, tokenIndex332
-					if buffer[position] != rune('N') {
-						goto l328
+					if buffer[position] != rune('S') {
+						goto l318
 					}
 					position++
 				}
 			l332:
 				{
 					position334, tokenIndex334 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('o') {
 						goto l335
 					}
 					position++
 					goto l334
 				l335:
 					position, tokenIndex = position334, tokenIndex334
-					if buffer[position] != rune('S') {
-						goto l328
+					if buffer[position] != rune('O') {
+						goto l318
 					}
 					position++
 				}
 			l334:
 				{
 					position336, tokenIndex336 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('u') {
 						goto l337
 					}
 					position++
 					goto l336
 				l337:
 					position, tokenIndex = position336, tokenIndex336
-					if buffer[position] != rune('E') {
-						goto l328
+					if buffer[position] != rune('U') {
+						goto l318
 					}
 					position++
 				}
@@ -4183,224 +4635,239 @@ func (p *bqlPegBackend) Init() {
 				l339:
 					position, tokenIndex = position338, tokenIndex338
 					if buffer[position] != rune('R') {
-						goto l328
+						goto l318
 					}
 					position++
 				}
 			l338:
 				{
 					position340, tokenIndex340 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('c') {
 						goto l341
 					}
 					position++
 					goto l340
 				l341:
 					position, tokenIndex = position340, tokenIndex340
-					if buffer[position] != rune('T') {
-						goto l328
+					if buffer[position] != rune('C') {
+						goto l318
 					}
 					position++
 				}
 			l340:
-				if !_rules[rulesp]() {
-					goto l328
-				}
 				{
 					position342, tokenIndex342 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					if buffer[position] != rune('e') {
 						goto l343
 					}
 					position++
 					goto l342
 				l343:
 					position, tokenIndex = position342, tokenIndex342
-					if buffer[position] != rune('I') {
-						goto l328
+					if buffer[position] != rune('E') {
+						goto l318
 					}
 					position++
 				}
 			l342:
+				if !_rules[rulesp]() {
+					goto l318
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l318
+				}
+				if !_rules[rulesp]() {
+					goto l318
+				}
 				{
 					position344, tokenIndex344 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('t') {
 						goto l345
 					}
 					position++
 					goto l344
 				l345:
 					position, tokenIndex = position344, tokenIndex344
-					if buffer[position] != rune('N') {
-						goto l328
+					if buffer[position] != rune('T') {
+						goto l318
 					}
 					position++
 				}
 			l344:
 				{
 					position346, tokenIndex346 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('y') {
 						goto l347
 					}
 					position++
 					goto l346
 				l347:
 					position, tokenIndex = position346, tokenIndex346
-					if buffer[position] != rune('T') {
-						goto l328
+					if buffer[position] != rune('Y') {
+						goto l318
 					}
 					position++
 				}
 			l346:
 				{
 					position348, tokenIndex348 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('p') {
 						goto l349
 					}
 					position++
 					goto l348
 				l349:
 					position, tokenIndex = position348, tokenIndex348
-					if buffer[position] != rune('O') {
-						goto l328
+					if buffer[position] != rune('P') {
+						goto l318
 					}
 					position++
 				}
 			l348:
-				if !_rules[rulesp]() {
-					goto l328
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l328
-				}
-				if !_rules[rulesp]() {
-					goto l328
-				}
 				{
 					position350, tokenIndex350 := position, tokenIndex
-					if buffer[position] != rune('f') {
+					if buffer[position] != rune('e') {
 						goto l351
 					}
 					position++
 					goto l350
 				l351:
 					position, tokenIndex = position350, tokenIndex350
-					if buffer[position] != rune('F') {
-						goto l328
+					if buffer[position] != rune('E') {
+						goto l318
 					}
 					position++
 				}
 			l350:
-				{
-					position352, tokenIndex352 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l353
-					}
-					position++
-					goto l352
-				l353:
-					position, tokenIndex = position352, tokenIndex352
-					if buffer[position] != rune('R') {
-						goto l328
-					}
-					position++
+				if !_rules[rulesp]() {
+					goto l318
+				}
+				if !_rules[ruleSourceSinkType]() {
+					goto l318
+				}
+				if !_rules[ruleSchemaOpt]() {
+					goto l318
+				}
+				if !_rules[ruleInstancesOpt]() {
+					goto l318
+				}
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l318
+				}
+				if !_rules[ruleTimestampByOpt]() {
+					goto l318
+				}
+				if !_rules[ruleAction11]() {
+					goto l318
 				}
-			l352:
+				add(ruleCreateSourceStmt, position319)
+			}
+			return true
+		l318:
+			position, tokenIndex = position318, tokenIndex318
+			return false
+		},
+		/* 22 CreateSinkStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') TemporaryOpt sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType SourceSinkSpecs SinkOrderingOpt Action12)> */
+		func() bool {
+			position352, tokenIndex352 := position, tokenIndex
+			{
+				position353 := position
 				{
 					position354, tokenIndex354 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('c') {
 						goto l355
 					}
 					position++
 					goto l354
 				l355:
 					position, tokenIndex = position354, tokenIndex354
-					if buffer[position] != rune('O') {
-						goto l328
+					if buffer[position] != rune('C') {
+						goto l352
 					}
 					position++
 				}
 			l354:
 				{
 					position356, tokenIndex356 := position, tokenIndex
-					if buffer[position] != rune('m') {
+					if buffer[position] != rune('r') {
 						goto l357
 					}
 					position++
 					goto l356
 				l357:
 					position, tokenIndex = position356, tokenIndex356
-					if buffer[position] != rune('M') {
-						goto l328
+					if buffer[position] != rune('R') {
+						goto l352
 					}
 					position++
 				}
 			l356:
-				if !_rules[rulesp]() {
-					goto l328
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l328
-				}
-				if !_rules[ruleAction12]() {
-					goto l328
+				{
+					position358, tokenIndex358 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l359
+					}
+					position++
+					goto l358
+				l359:
+					position, tokenIndex = position358, tokenIndex358
+					if buffer[position] != rune('E') {
+						goto l352
+					}
+					position++
 				}
-				add(ruleInsertIntoFromStmt, position329)
-			}
-			return true
-		l328:
-			position, tokenIndex = position328, tokenIndex328
-			return false
-		},
-		/* 19 PauseSourceStmt <- <(('p' / 'P') ('a' / 'A') ('u' / 'U') ('s' / 'S') ('e' / 'E') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action13)> */
-		func() bool {
-			position358, tokenIndex358 := position, tokenIndex
-			{
-				position359 := position
+			l358:
 				{
 					position360, tokenIndex360 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('a') {
 						goto l361
 					}
 					position++
 					goto l360
 				l361:
 					position, tokenIndex = position360, tokenIndex360
-					if buffer[position] != rune('P') {
-						goto l358
+					if buffer[position] != rune('A') {
+						goto l352
 					}
 					position++
 				}
 			l360:
 				{
 					position362, tokenIndex362 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('t') {
 						goto l363
 					}
 					position++
 					goto l362
 				l363:
 					position, tokenIndex = position362, tokenIndex362
-					if buffer[position] != rune('A') {
-						goto l358
+					if buffer[position] != rune('T') {
+						goto l352
 					}
 					position++
 				}
 			l362:
 				{
 					position364, tokenIndex364 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('e') {
 						goto l365
 					}
 					position++
 					goto l364
 				l365:
 					position, tokenIndex = position364, tokenIndex364
-					if buffer[position] != rune('U') {
-						goto l358
+					if buffer[position] != rune('E') {
+						goto l352
 					}
 					position++
 				}
 			l364:
+				if !_rules[ruleTemporaryOpt]() {
+					goto l352
+				}
+				if !_rules[rulesp]() {
+					goto l352
+				}
 				{
 					position366, tokenIndex366 := position, tokenIndex
 					if buffer[position] != rune('s') {
@@ -4411,100 +4878,106 @@ func (p *bqlPegBackend) Init() {
 				l367:
 					position, tokenIndex = position366, tokenIndex366
 					if buffer[position] != rune('S') {
-						goto l358
+						goto l352
 					}
 					position++
 				}
 			l366:
 				{
 					position368, tokenIndex368 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('i') {
 						goto l369
 					}
 					position++
 					goto l368
 				l369:
 					position, tokenIndex = position368, tokenIndex368
-					if buffer[position] != rune('E') {
-						goto l358
+					if buffer[position] != rune('I') {
+						goto l352
 					}
 					position++
 				}
 			l368:
-				if !_rules[rulesp]() {
-					goto l358
-				}
 				{
 					position370, tokenIndex370 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('n') {
 						goto l371
 					}
 					position++
 					goto l370
 				l371:
 					position, tokenIndex = position370, tokenIndex370
-					if buffer[position] != rune('S') {
-						goto l358
+					if buffer[position] != rune('N') {
+						goto l352
 					}
 					position++
 				}
 			l370:
 				{
 					position372, tokenIndex372 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('k') {
 						goto l373
 					}
 					position++
 					goto l372
 				l373:
 					position, tokenIndex = position372, tokenIndex372
-					if buffer[position] != rune('O') {
-						goto l358
+					if buffer[position] != rune('K') {
+						goto l352
 					}
 					position++
 				}
 			l372:
+				if !_rules[rulesp]() {
+					goto l352
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l352
+				}
+				if !_rules[rulesp]() {
+					goto l352
+				}
 				{
 					position374, tokenIndex374 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('t') {
 						goto l375
 					}
 					position++
 					goto l374
 				l375:
 					position, tokenIndex = position374, tokenIndex374
-					if buffer[position] != rune('U') {
-						goto l358
+					if buffer[position] != rune('T') {
+						goto l352
 					}
 					position++
 				}
 			l374:
 				{
 					position376, tokenIndex376 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('y') {
 						goto l377
 					}
 					position++
 					goto l376
 				l377:
 					position, tokenIndex = position376, tokenIndex376
-					if buffer[position] != rune('R') {
-						goto l358
+					if buffer[position] != rune('Y') {
+						goto l352
 					}
 					position++
 				}
 			l376:
 				{
 					position378, tokenIndex378 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					if buffer[position] != rune('p') {
 						goto l379
 					}
 					position++
 					goto l378
 				l379:
 					position, tokenIndex = position378, tokenIndex378
-					if buffer[position] != rune('C') {
-						goto l358
+					if buffer[position] != rune('P') {
+						goto l352
 					}
 					position++
 				}
@@ -4519,42 +4992,48 @@ func (p *bqlPegBackend) Init() {
 				l381:
 					position, tokenIndex = position380, tokenIndex380
 					if buffer[position] != rune('E') {
-						goto l358
+						goto l352
 					}
 					position++
 				}
 			l380:
 				if !_rules[rulesp]() {
-					goto l358
+					goto l352
 				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l358
+				if !_rules[ruleSourceSinkType]() {
+					goto l352
 				}
-				if !_rules[ruleAction13]() {
-					goto l358
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l352
 				}
-				add(rulePauseSourceStmt, position359)
-			}
-			return true
-		l358:
-			position, tokenIndex = position358, tokenIndex358
-			return false
-		},
-		/* 20 ResumeSourceStmt <- <(('r' / 'R') ('e' / 'E') ('s' / 'S') ('u' / 'U') ('m' / 'M') ('e' / 'E') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action14)> */
+				if !_rules[ruleSinkOrderingOpt]() {
+					goto l352
+				}
+				if !_rules[ruleAction12]() {
+					goto l352
+				}
+				add(ruleCreateSinkStmt, position353)
+			}
+			return true
+		l352:
+			position, tokenIndex = position352, tokenIndex352
+			return false
+		},
+		/* 23 CreateStateStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType SourceSinkSpecs Action13)> */
 		func() bool {
 			position382, tokenIndex382 := position, tokenIndex
 			{
 				position383 := position
 				{
 					position384, tokenIndex384 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('c') {
 						goto l385
 					}
 					position++
 					goto l384
 				l385:
 					position, tokenIndex = position384, tokenIndex384
-					if buffer[position] != rune('R') {
+					if buffer[position] != rune('C') {
 						goto l382
 					}
 					position++
@@ -4562,14 +5041,14 @@ func (p *bqlPegBackend) Init() {
 			l384:
 				{
 					position386, tokenIndex386 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('r') {
 						goto l387
 					}
 					position++
 					goto l386
 				l387:
 					position, tokenIndex = position386, tokenIndex386
-					if buffer[position] != rune('E') {
+					if buffer[position] != rune('R') {
 						goto l382
 					}
 					position++
@@ -4577,14 +5056,14 @@ func (p *bqlPegBackend) Init() {
 			l386:
 				{
 					position388, tokenIndex388 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('e') {
 						goto l389
 					}
 					position++
 					goto l388
 				l389:
 					position, tokenIndex = position388, tokenIndex388
-					if buffer[position] != rune('S') {
+					if buffer[position] != rune('E') {
 						goto l382
 					}
 					position++
@@ -4592,14 +5071,14 @@ func (p *bqlPegBackend) Init() {
 			l388:
 				{
 					position390, tokenIndex390 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('a') {
 						goto l391
 					}
 					position++
 					goto l390
 				l391:
 					position, tokenIndex = position390, tokenIndex390
-					if buffer[position] != rune('U') {
+					if buffer[position] != rune('A') {
 						goto l382
 					}
 					position++
@@ -4607,14 +5086,14 @@ func (p *bqlPegBackend) Init() {
 			l390:
 				{
 					position392, tokenIndex392 := position, tokenIndex
-					if buffer[position] != rune('m') {
+					if buffer[position] != rune('t') {
 						goto l393
 					}
 					position++
 					goto l392
 				l393:
 					position, tokenIndex = position392, tokenIndex392
-					if buffer[position] != rune('M') {
+					if buffer[position] != rune('T') {
 						goto l382
 					}
 					position++
@@ -4655,14 +5134,14 @@ func (p *bqlPegBackend) Init() {
 			l396:
 				{
 					position398, tokenIndex398 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('t') {
 						goto l399
 					}
 					position++
 					goto l398
 				l399:
 					position, tokenIndex = position398, tokenIndex398
-					if buffer[position] != rune('O') {
+					if buffer[position] != rune('T') {
 						goto l382
 					}
 					position++
@@ -4670,14 +5149,14 @@ func (p *bqlPegBackend) Init() {
 			l398:
 				{
 					position400, tokenIndex400 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('a') {
 						goto l401
 					}
 					position++
 					goto l400
 				l401:
 					position, tokenIndex = position400, tokenIndex400
-					if buffer[position] != rune('U') {
+					if buffer[position] != rune('A') {
 						goto l382
 					}
 					position++
@@ -4685,14 +5164,14 @@ func (p *bqlPegBackend) Init() {
 			l400:
 				{
 					position402, tokenIndex402 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('t') {
 						goto l403
 					}
 					position++
 					goto l402
 				l403:
 					position, tokenIndex = position402, tokenIndex402
-					if buffer[position] != rune('R') {
+					if buffer[position] != rune('T') {
 						goto l382
 					}
 					position++
@@ -4700,66 +5179,69 @@ func (p *bqlPegBackend) Init() {
 			l402:
 				{
 					position404, tokenIndex404 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					if buffer[position] != rune('e') {
 						goto l405
 					}
 					position++
 					goto l404
 				l405:
 					position, tokenIndex = position404, tokenIndex404
-					if buffer[position] != rune('C') {
+					if buffer[position] != rune('E') {
 						goto l382
 					}
 					position++
 				}
 			l404:
+				if !_rules[rulesp]() {
+					goto l382
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l382
+				}
+				if !_rules[rulesp]() {
+					goto l382
+				}
 				{
 					position406, tokenIndex406 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('t') {
 						goto l407
 					}
 					position++
 					goto l406
 				l407:
 					position, tokenIndex = position406, tokenIndex406
-					if buffer[position] != rune('E') {
+					if buffer[position] != rune('T') {
 						goto l382
 					}
 					position++
 				}
 			l406:
-				if !_rules[rulesp]() {
-					goto l382
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l382
-				}
-				if !_rules[ruleAction14]() {
-					goto l382
+				{
+					position408, tokenIndex408 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l409
+					}
+					position++
+					goto l408
+				l409:
+					position, tokenIndex = position408, tokenIndex408
+					if buffer[position] != rune('Y') {
+						goto l382
+					}
+					position++
 				}
-				add(ruleResumeSourceStmt, position383)
-			}
-			return true
-		l382:
-			position, tokenIndex = position382, tokenIndex382
-			return false
-		},
-		/* 21 RewindSourceStmt <- <(('r' / 'R') ('e' / 'E') ('w' / 'W') ('i' / 'I') ('n' / 'N') ('d' / 'D') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action15)> */
-		func() bool {
-			position408, tokenIndex408 := position, tokenIndex
-			{
-				position409 := position
+			l408:
 				{
 					position410, tokenIndex410 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('p') {
 						goto l411
 					}
 					position++
 					goto l410
 				l411:
 					position, tokenIndex = position410, tokenIndex410
-					if buffer[position] != rune('R') {
-						goto l408
+					if buffer[position] != rune('P') {
+						goto l382
 					}
 					position++
 				}
@@ -4774,52 +5256,61 @@ func (p *bqlPegBackend) Init() {
 				l413:
 					position, tokenIndex = position412, tokenIndex412
 					if buffer[position] != rune('E') {
-						goto l408
+						goto l382
 					}
 					position++
 				}
 			l412:
-				{
-					position414, tokenIndex414 := position, tokenIndex
-					if buffer[position] != rune('w') {
-						goto l415
-					}
-					position++
-					goto l414
-				l415:
-					position, tokenIndex = position414, tokenIndex414
-					if buffer[position] != rune('W') {
-						goto l408
-					}
-					position++
+				if !_rules[rulesp]() {
+					goto l382
+				}
+				if !_rules[ruleSourceSinkType]() {
+					goto l382
+				}
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l382
+				}
+				if !_rules[ruleAction13]() {
+					goto l382
 				}
-			l414:
+				add(ruleCreateStateStmt, position383)
+			}
+			return true
+		l382:
+			position, tokenIndex = position382, tokenIndex382
+			return false
+		},
+		/* 24 UpdateStateStmt <- <(('u' / 'U') ('p' / 'P') ('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier UpdateSourceSinkSpecs Action14)> */
+		func() bool {
+			position414, tokenIndex414 := position, tokenIndex
+			{
+				position415 := position
 				{
 					position416, tokenIndex416 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					if buffer[position] != rune('u') {
 						goto l417
 					}
 					position++
 					goto l416
 				l417:
 					position, tokenIndex = position416, tokenIndex416
-					if buffer[position] != rune('I') {
-						goto l408
+					if buffer[position] != rune('U') {
+						goto l414
 					}
 					position++
 				}
 			l416:
 				{
 					position418, tokenIndex418 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('p') {
 						goto l419
 					}
 					position++
 					goto l418
 				l419:
 					position, tokenIndex = position418, tokenIndex418
-					if buffer[position] != rune('N') {
-						goto l408
+					if buffer[position] != rune('P') {
+						goto l414
 					}
 					position++
 				}
@@ -4834,166 +5325,169 @@ func (p *bqlPegBackend) Init() {
 				l421:
 					position, tokenIndex = position420, tokenIndex420
 					if buffer[position] != rune('D') {
-						goto l408
+						goto l414
 					}
 					position++
 				}
 			l420:
-				if !_rules[rulesp]() {
-					goto l408
-				}
 				{
 					position422, tokenIndex422 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('a') {
 						goto l423
 					}
 					position++
 					goto l422
 				l423:
 					position, tokenIndex = position422, tokenIndex422
-					if buffer[position] != rune('S') {
-						goto l408
+					if buffer[position] != rune('A') {
+						goto l414
 					}
 					position++
 				}
 			l422:
 				{
 					position424, tokenIndex424 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('t') {
 						goto l425
 					}
 					position++
 					goto l424
 				l425:
 					position, tokenIndex = position424, tokenIndex424
-					if buffer[position] != rune('O') {
-						goto l408
+					if buffer[position] != rune('T') {
+						goto l414
 					}
 					position++
 				}
 			l424:
 				{
 					position426, tokenIndex426 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('e') {
 						goto l427
 					}
 					position++
 					goto l426
 				l427:
 					position, tokenIndex = position426, tokenIndex426
-					if buffer[position] != rune('U') {
-						goto l408
+					if buffer[position] != rune('E') {
+						goto l414
 					}
 					position++
 				}
 			l426:
+				if !_rules[rulesp]() {
+					goto l414
+				}
 				{
 					position428, tokenIndex428 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('s') {
 						goto l429
 					}
 					position++
 					goto l428
 				l429:
 					position, tokenIndex = position428, tokenIndex428
-					if buffer[position] != rune('R') {
-						goto l408
+					if buffer[position] != rune('S') {
+						goto l414
 					}
 					position++
 				}
 			l428:
 				{
 					position430, tokenIndex430 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					if buffer[position] != rune('t') {
 						goto l431
 					}
 					position++
 					goto l430
 				l431:
 					position, tokenIndex = position430, tokenIndex430
-					if buffer[position] != rune('C') {
-						goto l408
+					if buffer[position] != rune('T') {
+						goto l414
 					}
 					position++
 				}
 			l430:
 				{
 					position432, tokenIndex432 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('a') {
 						goto l433
 					}
 					position++
 					goto l432
 				l433:
 					position, tokenIndex = position432, tokenIndex432
-					if buffer[position] != rune('E') {
-						goto l408
+					if buffer[position] != rune('A') {
+						goto l414
 					}
 					position++
 				}
 			l432:
-				if !_rules[rulesp]() {
-					goto l408
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l408
-				}
-				if !_rules[ruleAction15]() {
-					goto l408
+				{
+					position434, tokenIndex434 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l435
+					}
+					position++
+					goto l434
+				l435:
+					position, tokenIndex = position434, tokenIndex434
+					if buffer[position] != rune('T') {
+						goto l414
+					}
+					position++
 				}
-				add(ruleRewindSourceStmt, position409)
-			}
-			return true
-		l408:
-			position, tokenIndex = position408, tokenIndex408
-			return false
-		},
-		/* 22 DropSourceStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action16)> */
-		func() bool {
-			position434, tokenIndex434 := position, tokenIndex
-			{
-				position435 := position
+			l434:
 				{
 					position436, tokenIndex436 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					if buffer[position] != rune('e') {
 						goto l437
 					}
 					position++
 					goto l436
 				l437:
 					position, tokenIndex = position436, tokenIndex436
-					if buffer[position] != rune('D') {
-						goto l434
+					if buffer[position] != rune('E') {
+						goto l414
 					}
 					position++
 				}
 			l436:
-				{
-					position438, tokenIndex438 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l439
-					}
-					position++
-					goto l438
-				l439:
-					position, tokenIndex = position438, tokenIndex438
-					if buffer[position] != rune('R') {
-						goto l434
-					}
-					position++
+				if !_rules[rulesp]() {
+					goto l414
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l414
+				}
+				if !_rules[ruleUpdateSourceSinkSpecs]() {
+					goto l414
 				}
-			l438:
+				if !_rules[ruleAction14]() {
+					goto l414
+				}
+				add(ruleUpdateStateStmt, position415)
+			}
+			return true
+		l414:
+			position, tokenIndex = position414, tokenIndex414
+			return false
+		},
+		/* 25 UpdateSourceStmt <- <(('u' / 'U') ('p' / 'P') ('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier UpdateSourceSinkSpecs Action15)> */
+		func() bool {
+			position438, tokenIndex438 := position, tokenIndex
+			{
+				position439 := position
 				{
 					position440, tokenIndex440 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('u') {
 						goto l441
 					}
 					position++
 					goto l440
 				l441:
 					position, tokenIndex = position440, tokenIndex440
-					if buffer[position] != rune('O') {
-						goto l434
+					if buffer[position] != rune('U') {
+						goto l438
 					}
 					position++
 				}
@@ -5008,3626 +5502,3697 @@ func (p *bqlPegBackend) Init() {
 				l443:
 					position, tokenIndex = position442, tokenIndex442
 					if buffer[position] != rune('P') {
-						goto l434
+						goto l438
 					}
 					position++
 				}
 			l442:
-				if !_rules[rulesp]() {
-					goto l434
-				}
 				{
 					position444, tokenIndex444 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('d') {
 						goto l445
 					}
 					position++
 					goto l444
 				l445:
 					position, tokenIndex = position444, tokenIndex444
-					if buffer[position] != rune('S') {
-						goto l434
+					if buffer[position] != rune('D') {
+						goto l438
 					}
 					position++
 				}
 			l444:
 				{
 					position446, tokenIndex446 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('a') {
 						goto l447
 					}
 					position++
 					goto l446
 				l447:
 					position, tokenIndex = position446, tokenIndex446
-					if buffer[position] != rune('O') {
-						goto l434
+					if buffer[position] != rune('A') {
+						goto l438
 					}
 					position++
 				}
 			l446:
 				{
 					position448, tokenIndex448 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('t') {
 						goto l449
 					}
 					position++
 					goto l448
 				l449:
 					position, tokenIndex = position448, tokenIndex448
-					if buffer[position] != rune('U') {
-						goto l434
+					if buffer[position] != rune('T') {
+						goto l438
 					}
 					position++
 				}
 			l448:
 				{
 					position450, tokenIndex450 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('e') {
 						goto l451
 					}
 					position++
 					goto l450
 				l451:
 					position, tokenIndex = position450, tokenIndex450
-					if buffer[position] != rune('R') {
-						goto l434
+					if buffer[position] != rune('E') {
+						goto l438
 					}
 					position++
 				}
 			l450:
+				if !_rules[rulesp]() {
+					goto l438
+				}
 				{
 					position452, tokenIndex452 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					if buffer[position] != rune('s') {
 						goto l453
 					}
 					position++
 					goto l452
 				l453:
 					position, tokenIndex = position452, tokenIndex452
-					if buffer[position] != rune('C') {
-						goto l434
+					if buffer[position] != rune('S') {
+						goto l438
 					}
 					position++
 				}
 			l452:
 				{
 					position454, tokenIndex454 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('o') {
 						goto l455
 					}
 					position++
 					goto l454
 				l455:
 					position, tokenIndex = position454, tokenIndex454
-					if buffer[position] != rune('E') {
-						goto l434
+					if buffer[position] != rune('O') {
+						goto l438
 					}
 					position++
 				}
 			l454:
-				if !_rules[rulesp]() {
-					goto l434
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l434
-				}
-				if !_rules[ruleAction16]() {
-					goto l434
-				}
-				add(ruleDropSourceStmt, position435)
-			}
-			return true
-		l434:
-			position, tokenIndex = position434, tokenIndex434
-			return false
-		},
-		/* 23 DropStreamStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier Action17)> */
-		func() bool {
-			position456, tokenIndex456 := position, tokenIndex
-			{
-				position457 := position
 				{
-					position458, tokenIndex458 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					position456, tokenIndex456 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l457
+					}
+					position++
+					goto l456
+				l457:
+					position, tokenIndex = position456, tokenIndex456
+					if buffer[position] != rune('U') {
+						goto l438
+					}
+					position++
+				}
+			l456:
+				{
+					position458, tokenIndex458 := position, tokenIndex
+					if buffer[position] != rune('r') {
 						goto l459
 					}
 					position++
 					goto l458
 				l459:
 					position, tokenIndex = position458, tokenIndex458
-					if buffer[position] != rune('D') {
-						goto l456
+					if buffer[position] != rune('R') {
+						goto l438
 					}
 					position++
 				}
 			l458:
 				{
 					position460, tokenIndex460 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('c') {
 						goto l461
 					}
 					position++
 					goto l460
 				l461:
 					position, tokenIndex = position460, tokenIndex460
-					if buffer[position] != rune('R') {
-						goto l456
+					if buffer[position] != rune('C') {
+						goto l438
 					}
 					position++
 				}
 			l460:
 				{
 					position462, tokenIndex462 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('e') {
 						goto l463
 					}
 					position++
 					goto l462
 				l463:
 					position, tokenIndex = position462, tokenIndex462
-					if buffer[position] != rune('O') {
-						goto l456
+					if buffer[position] != rune('E') {
+						goto l438
 					}
 					position++
 				}
 			l462:
-				{
-					position464, tokenIndex464 := position, tokenIndex
-					if buffer[position] != rune('p') {
-						goto l465
-					}
-					position++
-					goto l464
-				l465:
-					position, tokenIndex = position464, tokenIndex464
-					if buffer[position] != rune('P') {
-						goto l456
-					}
-					position++
-				}
-			l464:
 				if !_rules[rulesp]() {
-					goto l456
+					goto l438
 				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l438
+				}
+				if !_rules[ruleUpdateSourceSinkSpecs]() {
+					goto l438
+				}
+				if !_rules[ruleAction15]() {
+					goto l438
+				}
+				add(ruleUpdateSourceStmt, position439)
+			}
+			return true
+		l438:
+			position, tokenIndex = position438, tokenIndex438
+			return false
+		},
+		/* 26 UpdateSinkStmt <- <(('u' / 'U') ('p' / 'P') ('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier UpdateSourceSinkSpecs Action16)> */
+		func() bool {
+			position464, tokenIndex464 := position, tokenIndex
+			{
+				position465 := position
 				{
 					position466, tokenIndex466 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('u') {
 						goto l467
 					}
 					position++
 					goto l466
 				l467:
 					position, tokenIndex = position466, tokenIndex466
-					if buffer[position] != rune('S') {
-						goto l456
+					if buffer[position] != rune('U') {
+						goto l464
 					}
 					position++
 				}
 			l466:
 				{
 					position468, tokenIndex468 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('p') {
 						goto l469
 					}
 					position++
 					goto l468
 				l469:
 					position, tokenIndex = position468, tokenIndex468
-					if buffer[position] != rune('T') {
-						goto l456
+					if buffer[position] != rune('P') {
+						goto l464
 					}
 					position++
 				}
 			l468:
 				{
 					position470, tokenIndex470 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('d') {
 						goto l471
 					}
 					position++
 					goto l470
 				l471:
 					position, tokenIndex = position470, tokenIndex470
-					if buffer[position] != rune('R') {
-						goto l456
+					if buffer[position] != rune('D') {
+						goto l464
 					}
 					position++
 				}
 			l470:
 				{
 					position472, tokenIndex472 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('a') {
 						goto l473
 					}
 					position++
 					goto l472
 				l473:
 					position, tokenIndex = position472, tokenIndex472
-					if buffer[position] != rune('E') {
-						goto l456
+					if buffer[position] != rune('A') {
+						goto l464
 					}
 					position++
 				}
 			l472:
 				{
 					position474, tokenIndex474 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('t') {
 						goto l475
 					}
 					position++
 					goto l474
 				l475:
 					position, tokenIndex = position474, tokenIndex474
-					if buffer[position] != rune('A') {
-						goto l456
+					if buffer[position] != rune('T') {
+						goto l464
 					}
 					position++
 				}
 			l474:
 				{
 					position476, tokenIndex476 := position, tokenIndex
-					if buffer[position] != rune('m') {
+					if buffer[position] != rune('e') {
 						goto l477
 					}
 					position++
 					goto l476
 				l477:
 					position, tokenIndex = position476, tokenIndex476
-					if buffer[position] != rune('M') {
-						goto l456
+					if buffer[position] != rune('E') {
+						goto l464
 					}
 					position++
 				}
 			l476:
 				if !_rules[rulesp]() {
-					goto l456
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l456
+					goto l464
 				}
-				if !_rules[ruleAction17]() {
-					goto l456
+				{
+					position478, tokenIndex478 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l479
+					}
+					position++
+					goto l478
+				l479:
+					position, tokenIndex = position478, tokenIndex478
+					if buffer[position] != rune('S') {
+						goto l464
+					}
+					position++
 				}
-				add(ruleDropStreamStmt, position457)
-			}
-			return true
-		l456:
-			position, tokenIndex = position456, tokenIndex456
-			return false
-		},
-		/* 24 DropSinkStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier Action18)> */
-		func() bool {
-			position478, tokenIndex478 := position, tokenIndex
-			{
-				position479 := position
+			l478:
 				{
 					position480, tokenIndex480 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					if buffer[position] != rune('i') {
 						goto l481
 					}
 					position++
 					goto l480
 				l481:
 					position, tokenIndex = position480, tokenIndex480
-					if buffer[position] != rune('D') {
-						goto l478
+					if buffer[position] != rune('I') {
+						goto l464
 					}
 					position++
 				}
 			l480:
 				{
 					position482, tokenIndex482 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('n') {
 						goto l483
 					}
 					position++
 					goto l482
 				l483:
 					position, tokenIndex = position482, tokenIndex482
-					if buffer[position] != rune('R') {
-						goto l478
+					if buffer[position] != rune('N') {
+						goto l464
 					}
 					position++
 				}
 			l482:
 				{
 					position484, tokenIndex484 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('k') {
 						goto l485
 					}
 					position++
 					goto l484
 				l485:
 					position, tokenIndex = position484, tokenIndex484
-					if buffer[position] != rune('O') {
-						goto l478
+					if buffer[position] != rune('K') {
+						goto l464
 					}
 					position++
 				}
 			l484:
-				{
-					position486, tokenIndex486 := position, tokenIndex
-					if buffer[position] != rune('p') {
-						goto l487
-					}
-					position++
-					goto l486
-				l487:
-					position, tokenIndex = position486, tokenIndex486
-					if buffer[position] != rune('P') {
-						goto l478
-					}
-					position++
-				}
-			l486:
 				if !_rules[rulesp]() {
-					goto l478
+					goto l464
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l464
+				}
+				if !_rules[ruleUpdateSourceSinkSpecs]() {
+					goto l464
+				}
+				if !_rules[ruleAction16]() {
+					goto l464
 				}
+				add(ruleUpdateSinkStmt, position465)
+			}
+			return true
+		l464:
+			position, tokenIndex = position464, tokenIndex464
+			return false
+		},
+		/* 27 InsertIntoFromStmt <- <(('i' / 'I') ('n' / 'N') ('s' / 'S') ('e' / 'E') ('r' / 'R') ('t' / 'T') sp (('i' / 'I') ('n' / 'N') ('t' / 'T') ('o' / 'O')) sp StreamIdentifier sp EdgeProjectionsOpt (('f' / 'F') ('r' / 'R') ('o' / 'O') ('m' / 'M')) sp StreamIdentifier Filter OnErrorOpt Action17)> */
+		func() bool {
+			position486, tokenIndex486 := position, tokenIndex
+			{
+				position487 := position
 				{
 					position488, tokenIndex488 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('i') {
 						goto l489
 					}
 					position++
 					goto l488
 				l489:
 					position, tokenIndex = position488, tokenIndex488
-					if buffer[position] != rune('S') {
-						goto l478
+					if buffer[position] != rune('I') {
+						goto l486
 					}
 					position++
 				}
 			l488:
 				{
 					position490, tokenIndex490 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					if buffer[position] != rune('n') {
 						goto l491
 					}
 					position++
 					goto l490
 				l491:
 					position, tokenIndex = position490, tokenIndex490
-					if buffer[position] != rune('I') {
-						goto l478
+					if buffer[position] != rune('N') {
+						goto l486
 					}
 					position++
 				}
 			l490:
 				{
 					position492, tokenIndex492 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('s') {
 						goto l493
 					}
 					position++
 					goto l492
 				l493:
 					position, tokenIndex = position492, tokenIndex492
-					if buffer[position] != rune('N') {
-						goto l478
+					if buffer[position] != rune('S') {
+						goto l486
 					}
 					position++
 				}
 			l492:
 				{
 					position494, tokenIndex494 := position, tokenIndex
-					if buffer[position] != rune('k') {
+					if buffer[position] != rune('e') {
 						goto l495
 					}
 					position++
 					goto l494
 				l495:
 					position, tokenIndex = position494, tokenIndex494
-					if buffer[position] != rune('K') {
-						goto l478
+					if buffer[position] != rune('E') {
+						goto l486
 					}
 					position++
 				}
 			l494:
-				if !_rules[rulesp]() {
-					goto l478
-				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l478
-				}
-				if !_rules[ruleAction18]() {
-					goto l478
+				{
+					position496, tokenIndex496 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l497
+					}
+					position++
+					goto l496
+				l497:
+					position, tokenIndex = position496, tokenIndex496
+					if buffer[position] != rune('R') {
+						goto l486
+					}
+					position++
 				}
-				add(ruleDropSinkStmt, position479)
-			}
-			return true
-		l478:
-			position, tokenIndex = position478, tokenIndex478
-			return false
-		},
-		/* 25 DropStateStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier Action19)> */
-		func() bool {
-			position496, tokenIndex496 := position, tokenIndex
-			{
-				position497 := position
+			l496:
 				{
 					position498, tokenIndex498 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					if buffer[position] != rune('t') {
 						goto l499
 					}
 					position++
 					goto l498
 				l499:
 					position, tokenIndex = position498, tokenIndex498
-					if buffer[position] != rune('D') {
-						goto l496
+					if buffer[position] != rune('T') {
+						goto l486
 					}
 					position++
 				}
 			l498:
+				if !_rules[rulesp]() {
+					goto l486
+				}
 				{
 					position500, tokenIndex500 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('i') {
 						goto l501
 					}
 					position++
 					goto l500
 				l501:
 					position, tokenIndex = position500, tokenIndex500
-					if buffer[position] != rune('R') {
-						goto l496
+					if buffer[position] != rune('I') {
+						goto l486
 					}
 					position++
 				}
 			l500:
 				{
 					position502, tokenIndex502 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('n') {
 						goto l503
 					}
 					position++
 					goto l502
 				l503:
 					position, tokenIndex = position502, tokenIndex502
-					if buffer[position] != rune('O') {
-						goto l496
+					if buffer[position] != rune('N') {
+						goto l486
 					}
 					position++
 				}
 			l502:
 				{
 					position504, tokenIndex504 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('t') {
 						goto l505
 					}
 					position++
 					goto l504
 				l505:
 					position, tokenIndex = position504, tokenIndex504
-					if buffer[position] != rune('P') {
-						goto l496
+					if buffer[position] != rune('T') {
+						goto l486
 					}
 					position++
 				}
 			l504:
-				if !_rules[rulesp]() {
-					goto l496
-				}
 				{
 					position506, tokenIndex506 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('o') {
 						goto l507
 					}
 					position++
 					goto l506
 				l507:
 					position, tokenIndex = position506, tokenIndex506
-					if buffer[position] != rune('S') {
-						goto l496
+					if buffer[position] != rune('O') {
+						goto l486
 					}
 					position++
 				}
 			l506:
+				if !_rules[rulesp]() {
+					goto l486
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l486
+				}
+				if !_rules[rulesp]() {
+					goto l486
+				}
+				if !_rules[ruleEdgeProjectionsOpt]() {
+					goto l486
+				}
 				{
 					position508, tokenIndex508 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('f') {
 						goto l509
 					}
 					position++
 					goto l508
 				l509:
 					position, tokenIndex = position508, tokenIndex508
-					if buffer[position] != rune('T') {
-						goto l496
+					if buffer[position] != rune('F') {
+						goto l486
 					}
 					position++
 				}
 			l508:
 				{
 					position510, tokenIndex510 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('r') {
 						goto l511
 					}
 					position++
 					goto l510
 				l511:
 					position, tokenIndex = position510, tokenIndex510
-					if buffer[position] != rune('A') {
-						goto l496
+					if buffer[position] != rune('R') {
+						goto l486
 					}
 					position++
 				}
 			l510:
 				{
 					position512, tokenIndex512 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('o') {
 						goto l513
 					}
 					position++
 					goto l512
 				l513:
 					position, tokenIndex = position512, tokenIndex512
-					if buffer[position] != rune('T') {
-						goto l496
+					if buffer[position] != rune('O') {
+						goto l486
 					}
 					position++
 				}
 			l512:
 				{
 					position514, tokenIndex514 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('m') {
 						goto l515
 					}
 					position++
 					goto l514
 				l515:
 					position, tokenIndex = position514, tokenIndex514
-					if buffer[position] != rune('E') {
-						goto l496
+					if buffer[position] != rune('M') {
+						goto l486
 					}
 					position++
 				}
 			l514:
 				if !_rules[rulesp]() {
-					goto l496
+					goto l486
 				}
 				if !_rules[ruleStreamIdentifier]() {
-					goto l496
+					goto l486
 				}
-				if !_rules[ruleAction19]() {
-					goto l496
+				if !_rules[ruleFilter]() {
+					goto l486
+				}
+				if !_rules[ruleOnErrorOpt]() {
+					goto l486
+				}
+				if !_rules[ruleAction17]() {
+					goto l486
 				}
-				add(ruleDropStateStmt, position497)
+				add(ruleInsertIntoFromStmt, position487)
 			}
 			return true
-		l496:
-			position, tokenIndex = position496, tokenIndex496
+		l486:
+			position, tokenIndex = position486, tokenIndex486
 			return false
 		},
-		/* 26 LoadStateStmt <- <(('l' / 'L') ('o' / 'O') ('a' / 'A') ('d' / 'D') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType StateTagOpt SetOptSpecs Action20)> */
+		/* 28 EdgeProjectionsOpt <- <(<(('s' / 'S') ('e' / 'E') ('l' / 'L') ('e' / 'E') ('c' / 'C') ('t' / 'T') Projections sp)?> Action18)> */
 		func() bool {
 			position516, tokenIndex516 := position, tokenIndex
 			{
 				position517 := position
 				{
-					position518, tokenIndex518 := position, tokenIndex
-					if buffer[position] != rune('l') {
-						goto l519
-					}
-					position++
-					goto l518
-				l519:
-					position, tokenIndex = position518, tokenIndex518
-					if buffer[position] != rune('L') {
-						goto l516
-					}
-					position++
-				}
-			l518:
-				{
-					position520, tokenIndex520 := position, tokenIndex
-					if buffer[position] != rune('o') {
-						goto l521
-					}
-					position++
-					goto l520
-				l521:
-					position, tokenIndex = position520, tokenIndex520
-					if buffer[position] != rune('O') {
-						goto l516
-					}
-					position++
-				}
-			l520:
-				{
-					position522, tokenIndex522 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l523
-					}
-					position++
-					goto l522
-				l523:
-					position, tokenIndex = position522, tokenIndex522
-					if buffer[position] != rune('A') {
-						goto l516
-					}
-					position++
-				}
-			l522:
-				{
-					position524, tokenIndex524 := position, tokenIndex
-					if buffer[position] != rune('d') {
-						goto l525
-					}
-					position++
-					goto l524
-				l525:
-					position, tokenIndex = position524, tokenIndex524
-					if buffer[position] != rune('D') {
-						goto l516
+					position518 := position
+					{
+						position519, tokenIndex519 := position, tokenIndex
+						{
+							position521, tokenIndex521 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l522
+							}
+							position++
+							goto l521
+						l522:
+							position, tokenIndex = position521, tokenIndex521
+							if buffer[position] != rune('S') {
+								goto l519
+							}
+							position++
+						}
+					l521:
+						{
+							position523, tokenIndex523 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l524
+							}
+							position++
+							goto l523
+						l524:
+							position, tokenIndex = position523, tokenIndex523
+							if buffer[position] != rune('E') {
+								goto l519
+							}
+							position++
+						}
+					l523:
+						{
+							position525, tokenIndex525 := position, tokenIndex
+							if buffer[position] != rune('l') {
+								goto l526
+							}
+							position++
+							goto l525
+						l526:
+							position, tokenIndex = position525, tokenIndex525
+							if buffer[position] != rune('L') {
+								goto l519
+							}
+							position++
+						}
+					l525:
+						{
+							position527, tokenIndex527 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l528
+							}
+							position++
+							goto l527
+						l528:
+							position, tokenIndex = position527, tokenIndex527
+							if buffer[position] != rune('E') {
+								goto l519
+							}
+							position++
+						}
+					l527:
+						{
+							position529, tokenIndex529 := position, tokenIndex
+							if buffer[position] != rune('c') {
+								goto l530
+							}
+							position++
+							goto l529
+						l530:
+							position, tokenIndex = position529, tokenIndex529
+							if buffer[position] != rune('C') {
+								goto l519
+							}
+							position++
+						}
+					l529:
+						{
+							position531, tokenIndex531 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l532
+							}
+							position++
+							goto l531
+						l532:
+							position, tokenIndex = position531, tokenIndex531
+							if buffer[position] != rune('T') {
+								goto l519
+							}
+							position++
+						}
+					l531:
+						if !_rules[ruleProjections]() {
+							goto l519
+						}
+						if !_rules[rulesp]() {
+							goto l519
+						}
+						goto l520
+					l519:
+						position, tokenIndex = position519, tokenIndex519
 					}
-					position++
+				l520:
+					add(rulePegText, position518)
 				}
-			l524:
-				if !_rules[rulesp]() {
+				if !_rules[ruleAction18]() {
 					goto l516
 				}
+				add(ruleEdgeProjectionsOpt, position517)
+			}
+			return true
+		l516:
+			position, tokenIndex = position516, tokenIndex516
+			return false
+		},
+		/* 29 PauseSourceStmt <- <(('p' / 'P') ('a' / 'A') ('u' / 'U') ('s' / 'S') ('e' / 'E') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action19)> */
+		func() bool {
+			position533, tokenIndex533 := position, tokenIndex
+			{
+				position534 := position
 				{
-					position526, tokenIndex526 := position, tokenIndex
-					if buffer[position] != rune('s') {
-						goto l527
+					position535, tokenIndex535 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l536
 					}
 					position++
-					goto l526
-				l527:
-					position, tokenIndex = position526, tokenIndex526
-					if buffer[position] != rune('S') {
-						goto l516
+					goto l535
+				l536:
+					position, tokenIndex = position535, tokenIndex535
+					if buffer[position] != rune('P') {
+						goto l533
 					}
 					position++
 				}
-			l526:
+			l535:
 				{
-					position528, tokenIndex528 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l529
+					position537, tokenIndex537 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l538
 					}
 					position++
-					goto l528
-				l529:
-					position, tokenIndex = position528, tokenIndex528
-					if buffer[position] != rune('T') {
-						goto l516
+					goto l537
+				l538:
+					position, tokenIndex = position537, tokenIndex537
+					if buffer[position] != rune('A') {
+						goto l533
 					}
 					position++
 				}
-			l528:
+			l537:
 				{
-					position530, tokenIndex530 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l531
+					position539, tokenIndex539 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l540
 					}
 					position++
-					goto l530
-				l531:
-					position, tokenIndex = position530, tokenIndex530
-					if buffer[position] != rune('A') {
-						goto l516
+					goto l539
+				l540:
+					position, tokenIndex = position539, tokenIndex539
+					if buffer[position] != rune('U') {
+						goto l533
 					}
 					position++
 				}
-			l530:
+			l539:
 				{
-					position532, tokenIndex532 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l533
+					position541, tokenIndex541 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l542
 					}
 					position++
-					goto l532
-				l533:
-					position, tokenIndex = position532, tokenIndex532
-					if buffer[position] != rune('T') {
-						goto l516
+					goto l541
+				l542:
+					position, tokenIndex = position541, tokenIndex541
+					if buffer[position] != rune('S') {
+						goto l533
 					}
 					position++
 				}
-			l532:
+			l541:
 				{
-					position534, tokenIndex534 := position, tokenIndex
+					position543, tokenIndex543 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l535
+						goto l544
 					}
 					position++
-					goto l534
-				l535:
-					position, tokenIndex = position534, tokenIndex534
+					goto l543
+				l544:
+					position, tokenIndex = position543, tokenIndex543
 					if buffer[position] != rune('E') {
-						goto l516
+						goto l533
 					}
 					position++
 				}
-			l534:
+			l543:
 				if !_rules[rulesp]() {
-					goto l516
+					goto l533
 				}
-				if !_rules[ruleStreamIdentifier]() {
-					goto l516
+				{
+					position545, tokenIndex545 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l546
+					}
+					position++
+					goto l545
+				l546:
+					position, tokenIndex = position545, tokenIndex545
+					if buffer[position] != rune('S') {
+						goto l533
+					}
+					position++
 				}
-				if !_rules[rulesp]() {
-					goto l516
+			l545:
+				{
+					position547, tokenIndex547 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l548
+					}
+					position++
+					goto l547
+				l548:
+					position, tokenIndex = position547, tokenIndex547
+					if buffer[position] != rune('O') {
+						goto l533
+					}
+					position++
 				}
+			l547:
 				{
-					position536, tokenIndex536 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l537
+					position549, tokenIndex549 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l550
 					}
 					position++
-					goto l536
-				l537:
-					position, tokenIndex = position536, tokenIndex536
-					if buffer[position] != rune('T') {
-						goto l516
+					goto l549
+				l550:
+					position, tokenIndex = position549, tokenIndex549
+					if buffer[position] != rune('U') {
+						goto l533
 					}
 					position++
 				}
-			l536:
+			l549:
 				{
-					position538, tokenIndex538 := position, tokenIndex
-					if buffer[position] != rune('y') {
-						goto l539
+					position551, tokenIndex551 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l552
 					}
 					position++
-					goto l538
-				l539:
-					position, tokenIndex = position538, tokenIndex538
-					if buffer[position] != rune('Y') {
-						goto l516
+					goto l551
+				l552:
+					position, tokenIndex = position551, tokenIndex551
+					if buffer[position] != rune('R') {
+						goto l533
 					}
 					position++
 				}
-			l538:
+			l551:
 				{
-					position540, tokenIndex540 := position, tokenIndex
-					if buffer[position] != rune('p') {
-						goto l541
+					position553, tokenIndex553 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l554
 					}
 					position++
-					goto l540
-				l541:
-					position, tokenIndex = position540, tokenIndex540
-					if buffer[position] != rune('P') {
-						goto l516
+					goto l553
+				l554:
+					position, tokenIndex = position553, tokenIndex553
+					if buffer[position] != rune('C') {
+						goto l533
 					}
 					position++
 				}
-			l540:
+			l553:
 				{
-					position542, tokenIndex542 := position, tokenIndex
+					position555, tokenIndex555 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l543
+						goto l556
 					}
 					position++
-					goto l542
-				l543:
-					position, tokenIndex = position542, tokenIndex542
+					goto l555
+				l556:
+					position, tokenIndex = position555, tokenIndex555
 					if buffer[position] != rune('E') {
-						goto l516
+						goto l533
 					}
 					position++
 				}
-			l542:
+			l555:
 				if !_rules[rulesp]() {
-					goto l516
+					goto l533
 				}
-				if !_rules[ruleSourceSinkType]() {
-					goto l516
-				}
-				if !_rules[ruleStateTagOpt]() {
-					goto l516
-				}
-				if !_rules[ruleSetOptSpecs]() {
-					goto l516
+				if !_rules[ruleStreamIdentifier]() {
+					goto l533
 				}
-				if !_rules[ruleAction20]() {
-					goto l516
+				if !_rules[ruleAction19]() {
+					goto l533
 				}
-				add(ruleLoadStateStmt, position517)
+				add(rulePauseSourceStmt, position534)
 			}
 			return true
-		l516:
-			position, tokenIndex = position516, tokenIndex516
+		l533:
+			position, tokenIndex = position533, tokenIndex533
 			return false
 		},
-		/* 27 LoadStateOrCreateStmt <- <(LoadStateStmt sp (('o' / 'O') ('r' / 'R')) sp (('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp (('i' / 'I') ('f' / 'F')) sp (('n' / 'N') ('o' / 'O') ('t' / 'T')) sp ((('s' / 'S') ('a' / 'A') ('v' / 'V') ('e' / 'E') ('d' / 'D')) / (('e' / 'E') ('x' / 'X') ('i' / 'I') ('s' / 'S') ('t' / 'T') ('s' / 'S'))) SourceSinkSpecs Action21)> */
+		/* 30 ResumeSourceStmt <- <(('r' / 'R') ('e' / 'E') ('s' / 'S') ('u' / 'U') ('m' / 'M') ('e' / 'E') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action20)> */
 		func() bool {
-			position544, tokenIndex544 := position, tokenIndex
+			position557, tokenIndex557 := position, tokenIndex
 			{
-				position545 := position
-				if !_rules[ruleLoadStateStmt]() {
-					goto l544
-				}
-				if !_rules[rulesp]() {
-					goto l544
-				}
+				position558 := position
 				{
-					position546, tokenIndex546 := position, tokenIndex
-					if buffer[position] != rune('o') {
-						goto l547
+					position559, tokenIndex559 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l560
 					}
 					position++
-					goto l546
-				l547:
-					position, tokenIndex = position546, tokenIndex546
-					if buffer[position] != rune('O') {
-						goto l544
+					goto l559
+				l560:
+					position, tokenIndex = position559, tokenIndex559
+					if buffer[position] != rune('R') {
+						goto l557
 					}
 					position++
 				}
-			l546:
+			l559:
 				{
-					position548, tokenIndex548 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l549
+					position561, tokenIndex561 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l562
 					}
 					position++
-					goto l548
-				l549:
-					position, tokenIndex = position548, tokenIndex548
-					if buffer[position] != rune('R') {
-						goto l544
+					goto l561
+				l562:
+					position, tokenIndex = position561, tokenIndex561
+					if buffer[position] != rune('E') {
+						goto l557
 					}
 					position++
 				}
-			l548:
-				if !_rules[rulesp]() {
-					goto l544
-				}
+			l561:
 				{
-					position550, tokenIndex550 := position, tokenIndex
-					if buffer[position] != rune('c') {
-						goto l551
+					position563, tokenIndex563 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l564
 					}
 					position++
-					goto l550
-				l551:
-					position, tokenIndex = position550, tokenIndex550
-					if buffer[position] != rune('C') {
-						goto l544
+					goto l563
+				l564:
+					position, tokenIndex = position563, tokenIndex563
+					if buffer[position] != rune('S') {
+						goto l557
 					}
 					position++
 				}
-			l550:
+			l563:
 				{
-					position552, tokenIndex552 := position, tokenIndex
-					if buffer[position] != rune('r') {
-						goto l553
+					position565, tokenIndex565 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l566
 					}
 					position++
-					goto l552
-				l553:
-					position, tokenIndex = position552, tokenIndex552
-					if buffer[position] != rune('R') {
-						goto l544
+					goto l565
+				l566:
+					position, tokenIndex = position565, tokenIndex565
+					if buffer[position] != rune('U') {
+						goto l557
 					}
 					position++
 				}
-			l552:
+			l565:
 				{
-					position554, tokenIndex554 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l555
+					position567, tokenIndex567 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l568
 					}
 					position++
-					goto l554
-				l555:
-					position, tokenIndex = position554, tokenIndex554
-					if buffer[position] != rune('E') {
-						goto l544
+					goto l567
+				l568:
+					position, tokenIndex = position567, tokenIndex567
+					if buffer[position] != rune('M') {
+						goto l557
 					}
 					position++
 				}
-			l554:
+			l567:
 				{
-					position556, tokenIndex556 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l557
+					position569, tokenIndex569 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l570
 					}
 					position++
-					goto l556
-				l557:
-					position, tokenIndex = position556, tokenIndex556
-					if buffer[position] != rune('A') {
-						goto l544
+					goto l569
+				l570:
+					position, tokenIndex = position569, tokenIndex569
+					if buffer[position] != rune('E') {
+						goto l557
 					}
 					position++
 				}
-			l556:
+			l569:
+				if !_rules[rulesp]() {
+					goto l557
+				}
 				{
-					position558, tokenIndex558 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l559
+					position571, tokenIndex571 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l572
 					}
 					position++
-					goto l558
-				l559:
-					position, tokenIndex = position558, tokenIndex558
-					if buffer[position] != rune('T') {
-						goto l544
+					goto l571
+				l572:
+					position, tokenIndex = position571, tokenIndex571
+					if buffer[position] != rune('S') {
+						goto l557
 					}
 					position++
 				}
-			l558:
+			l571:
 				{
-					position560, tokenIndex560 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l561
+					position573, tokenIndex573 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l574
 					}
 					position++
-					goto l560
-				l561:
-					position, tokenIndex = position560, tokenIndex560
-					if buffer[position] != rune('E') {
-						goto l544
+					goto l573
+				l574:
+					position, tokenIndex = position573, tokenIndex573
+					if buffer[position] != rune('O') {
+						goto l557
 					}
 					position++
 				}
-			l560:
-				if !_rules[rulesp]() {
-					goto l544
-				}
+			l573:
 				{
-					position562, tokenIndex562 := position, tokenIndex
-					if buffer[position] != rune('i') {
-						goto l563
+					position575, tokenIndex575 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l576
 					}
 					position++
-					goto l562
-				l563:
-					position, tokenIndex = position562, tokenIndex562
-					if buffer[position] != rune('I') {
-						goto l544
+					goto l575
+				l576:
+					position, tokenIndex = position575, tokenIndex575
+					if buffer[position] != rune('U') {
+						goto l557
 					}
 					position++
 				}
-			l562:
+			l575:
 				{
-					position564, tokenIndex564 := position, tokenIndex
-					if buffer[position] != rune('f') {
-						goto l565
+					position577, tokenIndex577 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l578
 					}
 					position++
-					goto l564
-				l565:
-					position, tokenIndex = position564, tokenIndex564
-					if buffer[position] != rune('F') {
-						goto l544
+					goto l577
+				l578:
+					position, tokenIndex = position577, tokenIndex577
+					if buffer[position] != rune('R') {
+						goto l557
 					}
 					position++
 				}
-			l564:
-				if !_rules[rulesp]() {
-					goto l544
-				}
+			l577:
 				{
-					position566, tokenIndex566 := position, tokenIndex
-					if buffer[position] != rune('n') {
-						goto l567
+					position579, tokenIndex579 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l580
 					}
 					position++
-					goto l566
-				l567:
-					position, tokenIndex = position566, tokenIndex566
-					if buffer[position] != rune('N') {
-						goto l544
+					goto l579
+				l580:
+					position, tokenIndex = position579, tokenIndex579
+					if buffer[position] != rune('C') {
+						goto l557
 					}
 					position++
 				}
-			l566:
+			l579:
 				{
-					position568, tokenIndex568 := position, tokenIndex
-					if buffer[position] != rune('o') {
-						goto l569
+					position581, tokenIndex581 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l582
 					}
 					position++
-					goto l568
-				l569:
-					position, tokenIndex = position568, tokenIndex568
-					if buffer[position] != rune('O') {
-						goto l544
+					goto l581
+				l582:
+					position, tokenIndex = position581, tokenIndex581
+					if buffer[position] != rune('E') {
+						goto l557
 					}
 					position++
 				}
-			l568:
+			l581:
+				if !_rules[rulesp]() {
+					goto l557
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l557
+				}
+				if !_rules[ruleAction20]() {
+					goto l557
+				}
+				add(ruleResumeSourceStmt, position558)
+			}
+			return true
+		l557:
+			position, tokenIndex = position557, tokenIndex557
+			return false
+		},
+		/* 31 RewindSourceStmt <- <(('r' / 'R') ('e' / 'E') ('w' / 'W') ('i' / 'I') ('n' / 'N') ('d' / 'D') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action21)> */
+		func() bool {
+			position583, tokenIndex583 := position, tokenIndex
+			{
+				position584 := position
 				{
-					position570, tokenIndex570 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l571
+					position585, tokenIndex585 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l586
 					}
 					position++
-					goto l570
-				l571:
-					position, tokenIndex = position570, tokenIndex570
-					if buffer[position] != rune('T') {
-						goto l544
+					goto l585
+				l586:
+					position, tokenIndex = position585, tokenIndex585
+					if buffer[position] != rune('R') {
+						goto l583
 					}
 					position++
 				}
-			l570:
-				if !_rules[rulesp]() {
-					goto l544
-				}
+			l585:
 				{
-					position572, tokenIndex572 := position, tokenIndex
-					{
-						position574, tokenIndex574 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l575
-						}
-						position++
-						goto l574
-					l575:
-						position, tokenIndex = position574, tokenIndex574
-						if buffer[position] != rune('S') {
-							goto l573
-						}
-						position++
-					}
-				l574:
-					{
-						position576, tokenIndex576 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l577
-						}
-						position++
-						goto l576
-					l577:
-						position, tokenIndex = position576, tokenIndex576
-						if buffer[position] != rune('A') {
-							goto l573
-						}
-						position++
-					}
-				l576:
-					{
-						position578, tokenIndex578 := position, tokenIndex
-						if buffer[position] != rune('v') {
-							goto l579
-						}
-						position++
-						goto l578
-					l579:
-						position, tokenIndex = position578, tokenIndex578
-						if buffer[position] != rune('V') {
-							goto l573
-						}
-						position++
-					}
-				l578:
-					{
-						position580, tokenIndex580 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l581
-						}
-						position++
-						goto l580
-					l581:
-						position, tokenIndex = position580, tokenIndex580
-						if buffer[position] != rune('E') {
-							goto l573
-						}
-						position++
-					}
-				l580:
-					{
-						position582, tokenIndex582 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l583
-						}
-						position++
-						goto l582
-					l583:
-						position, tokenIndex = position582, tokenIndex582
-						if buffer[position] != rune('D') {
-							goto l573
-						}
-						position++
-					}
-				l582:
-					goto l572
-				l573:
-					position, tokenIndex = position572, tokenIndex572
-					{
-						position584, tokenIndex584 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l585
-						}
-						position++
-						goto l584
-					l585:
-						position, tokenIndex = position584, tokenIndex584
-						if buffer[position] != rune('E') {
-							goto l544
-						}
-						position++
-					}
-				l584:
-					{
-						position586, tokenIndex586 := position, tokenIndex
-						if buffer[position] != rune('x') {
-							goto l587
-						}
-						position++
-						goto l586
-					l587:
-						position, tokenIndex = position586, tokenIndex586
-						if buffer[position] != rune('X') {
-							goto l544
-						}
-						position++
-					}
-				l586:
-					{
-						position588, tokenIndex588 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l589
-						}
-						position++
+					position587, tokenIndex587 := position, tokenIndex
+					if buffer[position] != rune('e') {
 						goto l588
-					l589:
-						position, tokenIndex = position588, tokenIndex588
-						if buffer[position] != rune('I') {
-							goto l544
-						}
-						position++
 					}
+					position++
+					goto l587
 				l588:
-					{
-						position590, tokenIndex590 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l591
-						}
-						position++
-						goto l590
-					l591:
-						position, tokenIndex = position590, tokenIndex590
-						if buffer[position] != rune('S') {
-							goto l544
-						}
-						position++
-					}
-				l590:
-					{
-						position592, tokenIndex592 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l593
-						}
-						position++
-						goto l592
-					l593:
-						position, tokenIndex = position592, tokenIndex592
-						if buffer[position] != rune('T') {
-							goto l544
-						}
-						position++
-					}
-				l592:
-					{
-						position594, tokenIndex594 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l595
-						}
-						position++
-						goto l594
-					l595:
-						position, tokenIndex = position594, tokenIndex594
-						if buffer[position] != rune('S') {
-							goto l544
-						}
-						position++
+					position, tokenIndex = position587, tokenIndex587
+					if buffer[position] != rune('E') {
+						goto l583
 					}
-				l594:
-				}
-			l572:
-				if !_rules[ruleSourceSinkSpecs]() {
-					goto l544
-				}
-				if !_rules[ruleAction21]() {
-					goto l544
+					position++
 				}
-				add(ruleLoadStateOrCreateStmt, position545)
-			}
-			return true
-		l544:
-			position, tokenIndex = position544, tokenIndex544
-			return false
-		},
-		/* 28 SaveStateStmt <- <(('s' / 'S') ('a' / 'A') ('v' / 'V') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier StateTagOpt Action22)> */
-		func() bool {
-			position596, tokenIndex596 := position, tokenIndex
-			{
-				position597 := position
+			l587:
 				{
-					position598, tokenIndex598 := position, tokenIndex
-					if buffer[position] != rune('s') {
-						goto l599
+					position589, tokenIndex589 := position, tokenIndex
+					if buffer[position] != rune('w') {
+						goto l590
 					}
 					position++
-					goto l598
-				l599:
-					position, tokenIndex = position598, tokenIndex598
-					if buffer[position] != rune('S') {
-						goto l596
+					goto l589
+				l590:
+					position, tokenIndex = position589, tokenIndex589
+					if buffer[position] != rune('W') {
+						goto l583
 					}
 					position++
 				}
-			l598:
+			l589:
 				{
-					position600, tokenIndex600 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l601
+					position591, tokenIndex591 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l592
 					}
 					position++
-					goto l600
-				l601:
-					position, tokenIndex = position600, tokenIndex600
-					if buffer[position] != rune('A') {
-						goto l596
+					goto l591
+				l592:
+					position, tokenIndex = position591, tokenIndex591
+					if buffer[position] != rune('I') {
+						goto l583
 					}
 					position++
 				}
-			l600:
+			l591:
 				{
-					position602, tokenIndex602 := position, tokenIndex
-					if buffer[position] != rune('v') {
-						goto l603
+					position593, tokenIndex593 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l594
 					}
 					position++
-					goto l602
-				l603:
-					position, tokenIndex = position602, tokenIndex602
-					if buffer[position] != rune('V') {
-						goto l596
+					goto l593
+				l594:
+					position, tokenIndex = position593, tokenIndex593
+					if buffer[position] != rune('N') {
+						goto l583
 					}
 					position++
 				}
-			l602:
+			l593:
 				{
-					position604, tokenIndex604 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l605
+					position595, tokenIndex595 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l596
 					}
 					position++
-					goto l604
-				l605:
-					position, tokenIndex = position604, tokenIndex604
-					if buffer[position] != rune('E') {
-						goto l596
+					goto l595
+				l596:
+					position, tokenIndex = position595, tokenIndex595
+					if buffer[position] != rune('D') {
+						goto l583
 					}
 					position++
 				}
-			l604:
+			l595:
 				if !_rules[rulesp]() {
-					goto l596
+					goto l583
 				}
 				{
-					position606, tokenIndex606 := position, tokenIndex
+					position597, tokenIndex597 := position, tokenIndex
 					if buffer[position] != rune('s') {
-						goto l607
+						goto l598
 					}
 					position++
-					goto l606
-				l607:
-					position, tokenIndex = position606, tokenIndex606
+					goto l597
+				l598:
+					position, tokenIndex = position597, tokenIndex597
 					if buffer[position] != rune('S') {
-						goto l596
+						goto l583
 					}
 					position++
 				}
-			l606:
+			l597:
 				{
-					position608, tokenIndex608 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l609
+					position599, tokenIndex599 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l600
 					}
 					position++
-					goto l608
-				l609:
-					position, tokenIndex = position608, tokenIndex608
-					if buffer[position] != rune('T') {
-						goto l596
+					goto l599
+				l600:
+					position, tokenIndex = position599, tokenIndex599
+					if buffer[position] != rune('O') {
+						goto l583
 					}
 					position++
 				}
-			l608:
+			l599:
 				{
-					position610, tokenIndex610 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l611
+					position601, tokenIndex601 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l602
 					}
 					position++
-					goto l610
-				l611:
-					position, tokenIndex = position610, tokenIndex610
-					if buffer[position] != rune('A') {
-						goto l596
+					goto l601
+				l602:
+					position, tokenIndex = position601, tokenIndex601
+					if buffer[position] != rune('U') {
+						goto l583
 					}
 					position++
 				}
-			l610:
+			l601:
 				{
-					position612, tokenIndex612 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l613
+					position603, tokenIndex603 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l604
 					}
 					position++
-					goto l612
-				l613:
-					position, tokenIndex = position612, tokenIndex612
-					if buffer[position] != rune('T') {
-						goto l596
+					goto l603
+				l604:
+					position, tokenIndex = position603, tokenIndex603
+					if buffer[position] != rune('R') {
+						goto l583
+					}
+					position++
+				}
+			l603:
+				{
+					position605, tokenIndex605 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l606
+					}
+					position++
+					goto l605
+				l606:
+					position, tokenIndex = position605, tokenIndex605
+					if buffer[position] != rune('C') {
+						goto l583
 					}
 					position++
 				}
-			l612:
+			l605:
 				{
-					position614, tokenIndex614 := position, tokenIndex
+					position607, tokenIndex607 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l615
+						goto l608
 					}
 					position++
-					goto l614
-				l615:
-					position, tokenIndex = position614, tokenIndex614
+					goto l607
+				l608:
+					position, tokenIndex = position607, tokenIndex607
 					if buffer[position] != rune('E') {
-						goto l596
+						goto l583
 					}
 					position++
 				}
-			l614:
+			l607:
 				if !_rules[rulesp]() {
-					goto l596
+					goto l583
 				}
 				if !_rules[ruleStreamIdentifier]() {
-					goto l596
-				}
-				if !_rules[ruleStateTagOpt]() {
-					goto l596
+					goto l583
 				}
-				if !_rules[ruleAction22]() {
-					goto l596
+				if !_rules[ruleAction21]() {
+					goto l583
 				}
-				add(ruleSaveStateStmt, position597)
+				add(ruleRewindSourceStmt, position584)
 			}
 			return true
-		l596:
-			position, tokenIndex = position596, tokenIndex596
+		l583:
+			position, tokenIndex = position583, tokenIndex583
 			return false
 		},
-		/* 29 EvalStmt <- <(('e' / 'E') ('v' / 'V') ('a' / 'A') ('l' / 'L') sp Expression <(sp (('o' / 'O') ('n' / 'N')) sp MapExpr)?> Action23)> */
+		/* 32 DropSourceStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('o' / 'O') ('u' / 'U') ('r' / 'R') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action22)> */
 		func() bool {
-			position616, tokenIndex616 := position, tokenIndex
+			position609, tokenIndex609 := position, tokenIndex
 			{
-				position617 := position
+				position610 := position
 				{
-					position618, tokenIndex618 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l619
+					position611, tokenIndex611 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l612
 					}
 					position++
-					goto l618
-				l619:
-					position, tokenIndex = position618, tokenIndex618
-					if buffer[position] != rune('E') {
-						goto l616
+					goto l611
+				l612:
+					position, tokenIndex = position611, tokenIndex611
+					if buffer[position] != rune('D') {
+						goto l609
 					}
 					position++
 				}
-			l618:
+			l611:
 				{
-					position620, tokenIndex620 := position, tokenIndex
-					if buffer[position] != rune('v') {
-						goto l621
+					position613, tokenIndex613 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l614
 					}
 					position++
-					goto l620
-				l621:
-					position, tokenIndex = position620, tokenIndex620
-					if buffer[position] != rune('V') {
-						goto l616
+					goto l613
+				l614:
+					position, tokenIndex = position613, tokenIndex613
+					if buffer[position] != rune('R') {
+						goto l609
 					}
 					position++
 				}
-			l620:
+			l613:
 				{
-					position622, tokenIndex622 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l623
+					position615, tokenIndex615 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l616
 					}
 					position++
-					goto l622
-				l623:
-					position, tokenIndex = position622, tokenIndex622
-					if buffer[position] != rune('A') {
-						goto l616
+					goto l615
+				l616:
+					position, tokenIndex = position615, tokenIndex615
+					if buffer[position] != rune('O') {
+						goto l609
 					}
 					position++
 				}
-			l622:
+			l615:
 				{
-					position624, tokenIndex624 := position, tokenIndex
-					if buffer[position] != rune('l') {
-						goto l625
+					position617, tokenIndex617 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l618
 					}
 					position++
-					goto l624
-				l625:
-					position, tokenIndex = position624, tokenIndex624
-					if buffer[position] != rune('L') {
-						goto l616
+					goto l617
+				l618:
+					position, tokenIndex = position617, tokenIndex617
+					if buffer[position] != rune('P') {
+						goto l609
 					}
 					position++
 				}
-			l624:
+			l617:
 				if !_rules[rulesp]() {
-					goto l616
-				}
-				if !_rules[ruleExpression]() {
-					goto l616
+					goto l609
 				}
 				{
-					position626 := position
-					{
-						position627, tokenIndex627 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l627
-						}
-						{
-							position629, tokenIndex629 := position, tokenIndex
-							if buffer[position] != rune('o') {
-								goto l630
-							}
-							position++
-							goto l629
-						l630:
-							position, tokenIndex = position629, tokenIndex629
-							if buffer[position] != rune('O') {
-								goto l627
-							}
-							position++
-						}
-					l629:
-						{
-							position631, tokenIndex631 := position, tokenIndex
-							if buffer[position] != rune('n') {
-								goto l632
-							}
-							position++
-							goto l631
-						l632:
-							position, tokenIndex = position631, tokenIndex631
-							if buffer[position] != rune('N') {
-								goto l627
-							}
-							position++
-						}
-					l631:
-						if !_rules[rulesp]() {
-							goto l627
-						}
-						if !_rules[ruleMapExpr]() {
-							goto l627
-						}
-						goto l628
-					l627:
-						position, tokenIndex = position627, tokenIndex627
+					position619, tokenIndex619 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l620
 					}
-				l628:
-					add(rulePegText, position626)
-				}
-				if !_rules[ruleAction23]() {
-					goto l616
-				}
-				add(ruleEvalStmt, position617)
-			}
-			return true
-		l616:
-			position, tokenIndex = position616, tokenIndex616
-			return false
-		},
-		/* 30 Emitter <- <(sp (ISTREAM / DSTREAM / RSTREAM) EmitterOptions Action24)> */
-		func() bool {
-			position633, tokenIndex633 := position, tokenIndex
-			{
-				position634 := position
-				if !_rules[rulesp]() {
-					goto l633
+					position++
+					goto l619
+				l620:
+					position, tokenIndex = position619, tokenIndex619
+					if buffer[position] != rune('S') {
+						goto l609
+					}
+					position++
 				}
+			l619:
 				{
-					position635, tokenIndex635 := position, tokenIndex
-					if !_rules[ruleISTREAM]() {
-						goto l636
+					position621, tokenIndex621 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l622
 					}
-					goto l635
-				l636:
-					position, tokenIndex = position635, tokenIndex635
-					if !_rules[ruleDSTREAM]() {
-						goto l637
+					position++
+					goto l621
+				l622:
+					position, tokenIndex = position621, tokenIndex621
+					if buffer[position] != rune('O') {
+						goto l609
 					}
-					goto l635
-				l637:
-					position, tokenIndex = position635, tokenIndex635
-					if !_rules[ruleRSTREAM]() {
-						goto l633
+					position++
+				}
+			l621:
+				{
+					position623, tokenIndex623 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l624
+					}
+					position++
+					goto l623
+				l624:
+					position, tokenIndex = position623, tokenIndex623
+					if buffer[position] != rune('U') {
+						goto l609
 					}
+					position++
 				}
-			l635:
-				if !_rules[ruleEmitterOptions]() {
-					goto l633
+			l623:
+				{
+					position625, tokenIndex625 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l626
+					}
+					position++
+					goto l625
+				l626:
+					position, tokenIndex = position625, tokenIndex625
+					if buffer[position] != rune('R') {
+						goto l609
+					}
+					position++
 				}
-				if !_rules[ruleAction24]() {
-					goto l633
+			l625:
+				{
+					position627, tokenIndex627 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l628
+					}
+					position++
+					goto l627
+				l628:
+					position, tokenIndex = position627, tokenIndex627
+					if buffer[position] != rune('C') {
+						goto l609
+					}
+					position++
 				}
-				add(ruleEmitter, position634)
-			}
-			return true
-		l633:
-			position, tokenIndex = position633, tokenIndex633
-			return false
-		},
-		/* 31 EmitterOptions <- <(<(spOpt '[' spOpt EmitterOptionCombinations spOpt ']')?> Action25)> */
-		func() bool {
-			position638, tokenIndex638 := position, tokenIndex
-			{
-				position639 := position
+			l627:
 				{
-					position640 := position
-					{
-						position641, tokenIndex641 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l641
-						}
-						if buffer[position] != rune('[') {
-							goto l641
-						}
-						position++
-						if !_rules[rulespOpt]() {
-							goto l641
-						}
-						if !_rules[ruleEmitterOptionCombinations]() {
-							goto l641
-						}
-						if !_rules[rulespOpt]() {
-							goto l641
-						}
-						if buffer[position] != rune(']') {
-							goto l641
-						}
-						position++
-						goto l642
-					l641:
-						position, tokenIndex = position641, tokenIndex641
+					position629, tokenIndex629 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l630
 					}
-				l642:
-					add(rulePegText, position640)
+					position++
+					goto l629
+				l630:
+					position, tokenIndex = position629, tokenIndex629
+					if buffer[position] != rune('E') {
+						goto l609
+					}
+					position++
 				}
-				if !_rules[ruleAction25]() {
-					goto l638
+			l629:
+				if !_rules[rulesp]() {
+					goto l609
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l609
+				}
+				if !_rules[ruleAction22]() {
+					goto l609
 				}
-				add(ruleEmitterOptions, position639)
+				add(ruleDropSourceStmt, position610)
 			}
 			return true
-		l638:
-			position, tokenIndex = position638, tokenIndex638
+		l609:
+			position, tokenIndex = position609, tokenIndex609
 			return false
 		},
-		/* 32 EmitterOptionCombinations <- <(EmitterLimit / (EmitterSample sp EmitterLimit) / EmitterSample)> */
+		/* 33 DropStreamStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier Action23)> */
 		func() bool {
-			position643, tokenIndex643 := position, tokenIndex
+			position631, tokenIndex631 := position, tokenIndex
 			{
-				position644 := position
+				position632 := position
 				{
-					position645, tokenIndex645 := position, tokenIndex
-					if !_rules[ruleEmitterLimit]() {
-						goto l646
+					position633, tokenIndex633 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l634
 					}
-					goto l645
-				l646:
-					position, tokenIndex = position645, tokenIndex645
-					if !_rules[ruleEmitterSample]() {
-						goto l647
+					position++
+					goto l633
+				l634:
+					position, tokenIndex = position633, tokenIndex633
+					if buffer[position] != rune('D') {
+						goto l631
 					}
-					if !_rules[rulesp]() {
-						goto l647
+					position++
+				}
+			l633:
+				{
+					position635, tokenIndex635 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l636
 					}
-					if !_rules[ruleEmitterLimit]() {
-						goto l647
+					position++
+					goto l635
+				l636:
+					position, tokenIndex = position635, tokenIndex635
+					if buffer[position] != rune('R') {
+						goto l631
 					}
-					goto l645
-				l647:
-					position, tokenIndex = position645, tokenIndex645
-					if !_rules[ruleEmitterSample]() {
-						goto l643
+					position++
+				}
+			l635:
+				{
+					position637, tokenIndex637 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l638
+					}
+					position++
+					goto l637
+				l638:
+					position, tokenIndex = position637, tokenIndex637
+					if buffer[position] != rune('O') {
+						goto l631
 					}
+					position++
 				}
-			l645:
-				add(ruleEmitterOptionCombinations, position644)
-			}
-			return true
-		l643:
-			position, tokenIndex = position643, tokenIndex643
-			return false
-		},
-		/* 33 EmitterLimit <- <(('l' / 'L') ('i' / 'I') ('m' / 'M') ('i' / 'I') ('t' / 'T') sp NumericLiteral Action26)> */
-		func() bool {
-			position648, tokenIndex648 := position, tokenIndex
-			{
-				position649 := position
+			l637:
 				{
-					position650, tokenIndex650 := position, tokenIndex
-					if buffer[position] != rune('l') {
-						goto l651
+					position639, tokenIndex639 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l640
 					}
 					position++
-					goto l650
-				l651:
-					position, tokenIndex = position650, tokenIndex650
-					if buffer[position] != rune('L') {
-						goto l648
+					goto l639
+				l640:
+					position, tokenIndex = position639, tokenIndex639
+					if buffer[position] != rune('P') {
+						goto l631
 					}
 					position++
 				}
-			l650:
+			l639:
+				if !_rules[rulesp]() {
+					goto l631
+				}
 				{
-					position652, tokenIndex652 := position, tokenIndex
-					if buffer[position] != rune('i') {
-						goto l653
+					position641, tokenIndex641 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l642
 					}
 					position++
-					goto l652
-				l653:
-					position, tokenIndex = position652, tokenIndex652
-					if buffer[position] != rune('I') {
-						goto l648
+					goto l641
+				l642:
+					position, tokenIndex = position641, tokenIndex641
+					if buffer[position] != rune('S') {
+						goto l631
 					}
 					position++
 				}
-			l652:
+			l641:
 				{
-					position654, tokenIndex654 := position, tokenIndex
-					if buffer[position] != rune('m') {
-						goto l655
+					position643, tokenIndex643 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l644
 					}
 					position++
-					goto l654
-				l655:
-					position, tokenIndex = position654, tokenIndex654
-					if buffer[position] != rune('M') {
-						goto l648
+					goto l643
+				l644:
+					position, tokenIndex = position643, tokenIndex643
+					if buffer[position] != rune('T') {
+						goto l631
 					}
 					position++
 				}
-			l654:
+			l643:
 				{
-					position656, tokenIndex656 := position, tokenIndex
-					if buffer[position] != rune('i') {
-						goto l657
+					position645, tokenIndex645 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l646
 					}
 					position++
-					goto l656
-				l657:
-					position, tokenIndex = position656, tokenIndex656
-					if buffer[position] != rune('I') {
+					goto l645
+				l646:
+					position, tokenIndex = position645, tokenIndex645
+					if buffer[position] != rune('R') {
+						goto l631
+					}
+					position++
+				}
+			l645:
+				{
+					position647, tokenIndex647 := position, tokenIndex
+					if buffer[position] != rune('e') {
 						goto l648
 					}
 					position++
+					goto l647
+				l648:
+					position, tokenIndex = position647, tokenIndex647
+					if buffer[position] != rune('E') {
+						goto l631
+					}
+					position++
 				}
-			l656:
+			l647:
 				{
-					position658, tokenIndex658 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l659
+					position649, tokenIndex649 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l650
 					}
 					position++
-					goto l658
-				l659:
-					position, tokenIndex = position658, tokenIndex658
-					if buffer[position] != rune('T') {
-						goto l648
+					goto l649
+				l650:
+					position, tokenIndex = position649, tokenIndex649
+					if buffer[position] != rune('A') {
+						goto l631
+					}
+					position++
+				}
+			l649:
+				{
+					position651, tokenIndex651 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l652
+					}
+					position++
+					goto l651
+				l652:
+					position, tokenIndex = position651, tokenIndex651
+					if buffer[position] != rune('M') {
+						goto l631
 					}
 					position++
 				}
-			l658:
+			l651:
 				if !_rules[rulesp]() {
-					goto l648
+					goto l631
 				}
-				if !_rules[ruleNumericLiteral]() {
-					goto l648
+				if !_rules[ruleStreamIdentifier]() {
+					goto l631
 				}
-				if !_rules[ruleAction26]() {
-					goto l648
+				if !_rules[ruleAction23]() {
+					goto l631
 				}
-				add(ruleEmitterLimit, position649)
+				add(ruleDropStreamStmt, position632)
 			}
 			return true
-		l648:
-			position, tokenIndex = position648, tokenIndex648
+		l631:
+			position, tokenIndex = position631, tokenIndex631
 			return false
 		},
-		/* 34 EmitterSample <- <(CountBasedSampling / RandomizedSampling / TimeBasedSampling)> */
+		/* 34 ResumeStreamStmt <- <(('r' / 'R') ('e' / 'E') ('s' / 'S') ('u' / 'U') ('m' / 'M') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier Action24)> */
 		func() bool {
-			position660, tokenIndex660 := position, tokenIndex
+			position653, tokenIndex653 := position, tokenIndex
 			{
-				position661 := position
+				position654 := position
 				{
-					position662, tokenIndex662 := position, tokenIndex
-					if !_rules[ruleCountBasedSampling]() {
-						goto l663
+					position655, tokenIndex655 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l656
 					}
-					goto l662
-				l663:
-					position, tokenIndex = position662, tokenIndex662
-					if !_rules[ruleRandomizedSampling]() {
+					position++
+					goto l655
+				l656:
+					position, tokenIndex = position655, tokenIndex655
+					if buffer[position] != rune('R') {
+						goto l653
+					}
+					position++
+				}
+			l655:
+				{
+					position657, tokenIndex657 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l658
+					}
+					position++
+					goto l657
+				l658:
+					position, tokenIndex = position657, tokenIndex657
+					if buffer[position] != rune('E') {
+						goto l653
+					}
+					position++
+				}
+			l657:
+				{
+					position659, tokenIndex659 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l660
+					}
+					position++
+					goto l659
+				l660:
+					position, tokenIndex = position659, tokenIndex659
+					if buffer[position] != rune('S') {
+						goto l653
+					}
+					position++
+				}
+			l659:
+				{
+					position661, tokenIndex661 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l662
+					}
+					position++
+					goto l661
+				l662:
+					position, tokenIndex = position661, tokenIndex661
+					if buffer[position] != rune('U') {
+						goto l653
+					}
+					position++
+				}
+			l661:
+				{
+					position663, tokenIndex663 := position, tokenIndex
+					if buffer[position] != rune('m') {
 						goto l664
 					}
-					goto l662
+					position++
+					goto l663
 				l664:
-					position, tokenIndex = position662, tokenIndex662
-					if !_rules[ruleTimeBasedSampling]() {
-						goto l660
+					position, tokenIndex = position663, tokenIndex663
+					if buffer[position] != rune('M') {
+						goto l653
 					}
+					position++
 				}
-			l662:
-				add(ruleEmitterSample, position661)
-			}
-			return true
-		l660:
-			position, tokenIndex = position660, tokenIndex660
-			return false
-		},
-		/* 35 CountBasedSampling <- <(('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y') sp NumericLiteral spOpt '-'? spOpt ((('s' / 'S') ('t' / 'T')) / (('n' / 'N') ('d' / 'D')) / (('r' / 'R') ('d' / 'D')) / (('t' / 'T') ('h' / 'H'))) sp (('t' / 'T') ('u' / 'U') ('p' / 'P') ('l' / 'L') ('e' / 'E')) Action27)> */
-		func() bool {
-			position665, tokenIndex665 := position, tokenIndex
-			{
-				position666 := position
+			l663:
 				{
-					position667, tokenIndex667 := position, tokenIndex
+					position665, tokenIndex665 := position, tokenIndex
 					if buffer[position] != rune('e') {
+						goto l666
+					}
+					position++
+					goto l665
+				l666:
+					position, tokenIndex = position665, tokenIndex665
+					if buffer[position] != rune('E') {
+						goto l653
+					}
+					position++
+				}
+			l665:
+				if !_rules[rulesp]() {
+					goto l653
+				}
+				{
+					position667, tokenIndex667 := position, tokenIndex
+					if buffer[position] != rune('s') {
 						goto l668
 					}
 					position++
 					goto l667
 				l668:
 					position, tokenIndex = position667, tokenIndex667
-					if buffer[position] != rune('E') {
-						goto l665
+					if buffer[position] != rune('S') {
+						goto l653
 					}
 					position++
 				}
 			l667:
 				{
 					position669, tokenIndex669 := position, tokenIndex
-					if buffer[position] != rune('v') {
+					if buffer[position] != rune('t') {
 						goto l670
 					}
 					position++
 					goto l669
 				l670:
 					position, tokenIndex = position669, tokenIndex669
-					if buffer[position] != rune('V') {
-						goto l665
+					if buffer[position] != rune('T') {
+						goto l653
 					}
 					position++
 				}
 			l669:
 				{
 					position671, tokenIndex671 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('r') {
 						goto l672
 					}
 					position++
 					goto l671
 				l672:
 					position, tokenIndex = position671, tokenIndex671
-					if buffer[position] != rune('E') {
-						goto l665
+					if buffer[position] != rune('R') {
+						goto l653
 					}
 					position++
 				}
 			l671:
 				{
 					position673, tokenIndex673 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('e') {
 						goto l674
 					}
 					position++
 					goto l673
 				l674:
 					position, tokenIndex = position673, tokenIndex673
-					if buffer[position] != rune('R') {
-						goto l665
+					if buffer[position] != rune('E') {
+						goto l653
 					}
 					position++
 				}
 			l673:
 				{
 					position675, tokenIndex675 := position, tokenIndex
-					if buffer[position] != rune('y') {
+					if buffer[position] != rune('a') {
 						goto l676
 					}
 					position++
 					goto l675
 				l676:
 					position, tokenIndex = position675, tokenIndex675
-					if buffer[position] != rune('Y') {
-						goto l665
+					if buffer[position] != rune('A') {
+						goto l653
 					}
 					position++
 				}
 			l675:
-				if !_rules[rulesp]() {
-					goto l665
-				}
-				if !_rules[ruleNumericLiteral]() {
-					goto l665
-				}
-				if !_rules[rulespOpt]() {
-					goto l665
-				}
 				{
 					position677, tokenIndex677 := position, tokenIndex
-					if buffer[position] != rune('-') {
-						goto l677
+					if buffer[position] != rune('m') {
+						goto l678
 					}
 					position++
-					goto l678
-				l677:
+					goto l677
+				l678:
 					position, tokenIndex = position677, tokenIndex677
+					if buffer[position] != rune('M') {
+						goto l653
+					}
+					position++
 				}
-			l678:
-				if !_rules[rulespOpt]() {
-					goto l665
+			l677:
+				if !_rules[rulesp]() {
+					goto l653
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l653
+				}
+				if !_rules[ruleAction24]() {
+					goto l653
 				}
+				add(ruleResumeStreamStmt, position654)
+			}
+			return true
+		l653:
+			position, tokenIndex = position653, tokenIndex653
+			return false
+		},
+		/* 35 DropSinkStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier Action25)> */
+		func() bool {
+			position679, tokenIndex679 := position, tokenIndex
+			{
+				position680 := position
 				{
-					position679, tokenIndex679 := position, tokenIndex
-					{
-						position681, tokenIndex681 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l682
-						}
-						position++
-						goto l681
-					l682:
-						position, tokenIndex = position681, tokenIndex681
-						if buffer[position] != rune('S') {
-							goto l680
-						}
-						position++
+					position681, tokenIndex681 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l682
 					}
-				l681:
-					{
-						position683, tokenIndex683 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l684
-						}
-						position++
-						goto l683
-					l684:
-						position, tokenIndex = position683, tokenIndex683
-						if buffer[position] != rune('T') {
-							goto l680
-						}
-						position++
+					position++
+					goto l681
+				l682:
+					position, tokenIndex = position681, tokenIndex681
+					if buffer[position] != rune('D') {
+						goto l679
 					}
-				l683:
-					goto l679
-				l680:
-					position, tokenIndex = position679, tokenIndex679
-					{
-						position686, tokenIndex686 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l687
-						}
-						position++
+					position++
+				}
+			l681:
+				{
+					position683, tokenIndex683 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l684
+					}
+					position++
+					goto l683
+				l684:
+					position, tokenIndex = position683, tokenIndex683
+					if buffer[position] != rune('R') {
+						goto l679
+					}
+					position++
+				}
+			l683:
+				{
+					position685, tokenIndex685 := position, tokenIndex
+					if buffer[position] != rune('o') {
 						goto l686
-					l687:
-						position, tokenIndex = position686, tokenIndex686
-						if buffer[position] != rune('N') {
-							goto l685
-						}
-						position++
 					}
+					position++
+					goto l685
 				l686:
-					{
-						position688, tokenIndex688 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l689
-						}
-						position++
+					position, tokenIndex = position685, tokenIndex685
+					if buffer[position] != rune('O') {
+						goto l679
+					}
+					position++
+				}
+			l685:
+				{
+					position687, tokenIndex687 := position, tokenIndex
+					if buffer[position] != rune('p') {
 						goto l688
-					l689:
-						position, tokenIndex = position688, tokenIndex688
-						if buffer[position] != rune('D') {
-							goto l685
-						}
-						position++
 					}
+					position++
+					goto l687
 				l688:
-					goto l679
-				l685:
-					position, tokenIndex = position679, tokenIndex679
-					{
-						position691, tokenIndex691 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l692
-						}
-						position++
-						goto l691
-					l692:
-						position, tokenIndex = position691, tokenIndex691
-						if buffer[position] != rune('R') {
-							goto l690
-						}
-						position++
-					}
-				l691:
-					{
-						position693, tokenIndex693 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l694
-						}
-						position++
-						goto l693
-					l694:
-						position, tokenIndex = position693, tokenIndex693
-						if buffer[position] != rune('D') {
-							goto l690
-						}
-						position++
+					position, tokenIndex = position687, tokenIndex687
+					if buffer[position] != rune('P') {
+						goto l679
 					}
-				l693:
+					position++
+				}
+			l687:
+				if !_rules[rulesp]() {
 					goto l679
+				}
+				{
+					position689, tokenIndex689 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l690
+					}
+					position++
+					goto l689
 				l690:
-					position, tokenIndex = position679, tokenIndex679
-					{
-						position695, tokenIndex695 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l696
-						}
-						position++
-						goto l695
-					l696:
-						position, tokenIndex = position695, tokenIndex695
-						if buffer[position] != rune('T') {
-							goto l665
-						}
-						position++
+					position, tokenIndex = position689, tokenIndex689
+					if buffer[position] != rune('S') {
+						goto l679
 					}
-				l695:
-					{
-						position697, tokenIndex697 := position, tokenIndex
-						if buffer[position] != rune('h') {
-							goto l698
-						}
-						position++
-						goto l697
-					l698:
-						position, tokenIndex = position697, tokenIndex697
-						if buffer[position] != rune('H') {
-							goto l665
-						}
-						position++
+					position++
+				}
+			l689:
+				{
+					position691, tokenIndex691 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l692
+					}
+					position++
+					goto l691
+				l692:
+					position, tokenIndex = position691, tokenIndex691
+					if buffer[position] != rune('I') {
+						goto l679
+					}
+					position++
+				}
+			l691:
+				{
+					position693, tokenIndex693 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l694
+					}
+					position++
+					goto l693
+				l694:
+					position, tokenIndex = position693, tokenIndex693
+					if buffer[position] != rune('N') {
+						goto l679
+					}
+					position++
+				}
+			l693:
+				{
+					position695, tokenIndex695 := position, tokenIndex
+					if buffer[position] != rune('k') {
+						goto l696
+					}
+					position++
+					goto l695
+				l696:
+					position, tokenIndex = position695, tokenIndex695
+					if buffer[position] != rune('K') {
+						goto l679
 					}
-				l697:
+					position++
 				}
-			l679:
+			l695:
 				if !_rules[rulesp]() {
-					goto l665
+					goto l679
 				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l679
+				}
+				if !_rules[ruleAction25]() {
+					goto l679
+				}
+				add(ruleDropSinkStmt, position680)
+			}
+			return true
+		l679:
+			position, tokenIndex = position679, tokenIndex679
+			return false
+		},
+		/* 36 ProfileStreamStmt <- <(('p' / 'P') ('r' / 'R') ('o' / 'O') ('f' / 'F') ('i' / 'I') ('l' / 'L') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M')) sp StreamIdentifier SourceSinkSpecs Action26)> */
+		func() bool {
+			position697, tokenIndex697 := position, tokenIndex
+			{
+				position698 := position
 				{
 					position699, tokenIndex699 := position, tokenIndex
-					if buffer[position] != rune('t') {
+					if buffer[position] != rune('p') {
 						goto l700
 					}
 					position++
 					goto l699
 				l700:
 					position, tokenIndex = position699, tokenIndex699
-					if buffer[position] != rune('T') {
-						goto l665
+					if buffer[position] != rune('P') {
+						goto l697
 					}
 					position++
 				}
 			l699:
 				{
 					position701, tokenIndex701 := position, tokenIndex
-					if buffer[position] != rune('u') {
+					if buffer[position] != rune('r') {
 						goto l702
 					}
 					position++
 					goto l701
 				l702:
 					position, tokenIndex = position701, tokenIndex701
-					if buffer[position] != rune('U') {
-						goto l665
+					if buffer[position] != rune('R') {
+						goto l697
 					}
 					position++
 				}
 			l701:
 				{
 					position703, tokenIndex703 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('o') {
 						goto l704
 					}
 					position++
 					goto l703
 				l704:
 					position, tokenIndex = position703, tokenIndex703
-					if buffer[position] != rune('P') {
-						goto l665
+					if buffer[position] != rune('O') {
+						goto l697
 					}
 					position++
 				}
 			l703:
 				{
 					position705, tokenIndex705 := position, tokenIndex
-					if buffer[position] != rune('l') {
+					if buffer[position] != rune('f') {
 						goto l706
 					}
 					position++
 					goto l705
 				l706:
 					position, tokenIndex = position705, tokenIndex705
-					if buffer[position] != rune('L') {
-						goto l665
+					if buffer[position] != rune('F') {
+						goto l697
 					}
 					position++
 				}
 			l705:
 				{
 					position707, tokenIndex707 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('i') {
 						goto l708
 					}
 					position++
 					goto l707
 				l708:
 					position, tokenIndex = position707, tokenIndex707
-					if buffer[position] != rune('E') {
-						goto l665
+					if buffer[position] != rune('I') {
+						goto l697
 					}
 					position++
 				}
 			l707:
-				if !_rules[ruleAction27]() {
-					goto l665
+				{
+					position709, tokenIndex709 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l710
+					}
+					position++
+					goto l709
+				l710:
+					position, tokenIndex = position709, tokenIndex709
+					if buffer[position] != rune('L') {
+						goto l697
+					}
+					position++
 				}
-				add(ruleCountBasedSampling, position666)
-			}
-			return true
-		l665:
-			position, tokenIndex = position665, tokenIndex665
-			return false
-		},
-		/* 36 RandomizedSampling <- <(('s' / 'S') ('a' / 'A') ('m' / 'M') ('p' / 'P') ('l' / 'L') ('e' / 'E') sp (FloatLiteral / NumericLiteral) spOpt '%' Action28)> */
-		func() bool {
-			position709, tokenIndex709 := position, tokenIndex
-			{
-				position710 := position
+			l709:
 				{
 					position711, tokenIndex711 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('e') {
 						goto l712
 					}
 					position++
 					goto l711
 				l712:
 					position, tokenIndex = position711, tokenIndex711
-					if buffer[position] != rune('S') {
-						goto l709
+					if buffer[position] != rune('E') {
+						goto l697
 					}
 					position++
 				}
 			l711:
+				if !_rules[rulesp]() {
+					goto l697
+				}
 				{
 					position713, tokenIndex713 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('s') {
 						goto l714
 					}
 					position++
 					goto l713
 				l714:
 					position, tokenIndex = position713, tokenIndex713
-					if buffer[position] != rune('A') {
-						goto l709
+					if buffer[position] != rune('S') {
+						goto l697
 					}
 					position++
 				}
 			l713:
 				{
 					position715, tokenIndex715 := position, tokenIndex
-					if buffer[position] != rune('m') {
+					if buffer[position] != rune('t') {
 						goto l716
 					}
 					position++
 					goto l715
 				l716:
 					position, tokenIndex = position715, tokenIndex715
-					if buffer[position] != rune('M') {
-						goto l709
+					if buffer[position] != rune('T') {
+						goto l697
 					}
 					position++
 				}
 			l715:
 				{
 					position717, tokenIndex717 := position, tokenIndex
-					if buffer[position] != rune('p') {
+					if buffer[position] != rune('r') {
 						goto l718
 					}
 					position++
 					goto l717
 				l718:
 					position, tokenIndex = position717, tokenIndex717
-					if buffer[position] != rune('P') {
-						goto l709
+					if buffer[position] != rune('R') {
+						goto l697
 					}
 					position++
 				}
 			l717:
 				{
 					position719, tokenIndex719 := position, tokenIndex
-					if buffer[position] != rune('l') {
+					if buffer[position] != rune('e') {
 						goto l720
 					}
 					position++
 					goto l719
 				l720:
 					position, tokenIndex = position719, tokenIndex719
-					if buffer[position] != rune('L') {
-						goto l709
+					if buffer[position] != rune('E') {
+						goto l697
 					}
 					position++
 				}
 			l719:
 				{
 					position721, tokenIndex721 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('a') {
 						goto l722
 					}
 					position++
 					goto l721
 				l722:
 					position, tokenIndex = position721, tokenIndex721
-					if buffer[position] != rune('E') {
-						goto l709
+					if buffer[position] != rune('A') {
+						goto l697
 					}
 					position++
 				}
 			l721:
-				if !_rules[rulesp]() {
-					goto l709
-				}
 				{
 					position723, tokenIndex723 := position, tokenIndex
-					if !_rules[ruleFloatLiteral]() {
+					if buffer[position] != rune('m') {
 						goto l724
 					}
+					position++
 					goto l723
 				l724:
 					position, tokenIndex = position723, tokenIndex723
-					if !_rules[ruleNumericLiteral]() {
-						goto l709
+					if buffer[position] != rune('M') {
+						goto l697
 					}
+					position++
 				}
 			l723:
-				if !_rules[rulespOpt]() {
-					goto l709
+				if !_rules[rulesp]() {
+					goto l697
 				}
-				if buffer[position] != rune('%') {
-					goto l709
+				if !_rules[ruleStreamIdentifier]() {
+					goto l697
 				}
-				position++
-				if !_rules[ruleAction28]() {
-					goto l709
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l697
+				}
+				if !_rules[ruleAction26]() {
+					goto l697
 				}
-				add(ruleRandomizedSampling, position710)
+				add(ruleProfileStreamStmt, position698)
 			}
 			return true
-		l709:
-			position, tokenIndex = position709, tokenIndex709
+		l697:
+			position, tokenIndex = position697, tokenIndex697
 			return false
 		},
-		/* 37 TimeBasedSampling <- <(TimeBasedSamplingSeconds / TimeBasedSamplingMilliseconds)> */
+		/* 37 ReplaySinkStmt <- <(('r' / 'R') ('e' / 'E') ('p' / 'P') ('l' / 'L') ('a' / 'A') ('y' / 'Y') sp (('s' / 'S') ('i' / 'I') ('n' / 'N') ('k' / 'K')) sp StreamIdentifier Action27)> */
 		func() bool {
 			position725, tokenIndex725 := position, tokenIndex
 			{
 				position726 := position
 				{
 					position727, tokenIndex727 := position, tokenIndex
-					if !_rules[ruleTimeBasedSamplingSeconds]() {
+					if buffer[position] != rune('r') {
 						goto l728
 					}
+					position++
 					goto l727
 				l728:
 					position, tokenIndex = position727, tokenIndex727
-					if !_rules[ruleTimeBasedSamplingMilliseconds]() {
+					if buffer[position] != rune('R') {
 						goto l725
 					}
+					position++
 				}
 			l727:
-				add(ruleTimeBasedSampling, position726)
-			}
-			return true
-		l725:
-			position, tokenIndex = position725, tokenIndex725
-			return false
-		},
-		/* 38 TimeBasedSamplingSeconds <- <(('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y') sp (FloatLiteral / NumericLiteral) sp (('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S')) Action29)> */
-		func() bool {
-			position729, tokenIndex729 := position, tokenIndex
-			{
-				position730 := position
 				{
-					position731, tokenIndex731 := position, tokenIndex
+					position729, tokenIndex729 := position, tokenIndex
 					if buffer[position] != rune('e') {
+						goto l730
+					}
+					position++
+					goto l729
+				l730:
+					position, tokenIndex = position729, tokenIndex729
+					if buffer[position] != rune('E') {
+						goto l725
+					}
+					position++
+				}
+			l729:
+				{
+					position731, tokenIndex731 := position, tokenIndex
+					if buffer[position] != rune('p') {
 						goto l732
 					}
 					position++
 					goto l731
 				l732:
 					position, tokenIndex = position731, tokenIndex731
-					if buffer[position] != rune('E') {
-						goto l729
+					if buffer[position] != rune('P') {
+						goto l725
 					}
 					position++
 				}
 			l731:
 				{
 					position733, tokenIndex733 := position, tokenIndex
-					if buffer[position] != rune('v') {
+					if buffer[position] != rune('l') {
 						goto l734
 					}
 					position++
 					goto l733
 				l734:
 					position, tokenIndex = position733, tokenIndex733
-					if buffer[position] != rune('V') {
-						goto l729
+					if buffer[position] != rune('L') {
+						goto l725
 					}
 					position++
 				}
 			l733:
 				{
 					position735, tokenIndex735 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('a') {
 						goto l736
 					}
 					position++
 					goto l735
 				l736:
 					position, tokenIndex = position735, tokenIndex735
-					if buffer[position] != rune('E') {
-						goto l729
+					if buffer[position] != rune('A') {
+						goto l725
 					}
 					position++
 				}
 			l735:
 				{
 					position737, tokenIndex737 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('y') {
 						goto l738
 					}
 					position++
 					goto l737
 				l738:
 					position, tokenIndex = position737, tokenIndex737
-					if buffer[position] != rune('R') {
-						goto l729
+					if buffer[position] != rune('Y') {
+						goto l725
 					}
 					position++
 				}
 			l737:
+				if !_rules[rulesp]() {
+					goto l725
+				}
 				{
 					position739, tokenIndex739 := position, tokenIndex
-					if buffer[position] != rune('y') {
+					if buffer[position] != rune('s') {
 						goto l740
 					}
 					position++
 					goto l739
 				l740:
 					position, tokenIndex = position739, tokenIndex739
-					if buffer[position] != rune('Y') {
-						goto l729
+					if buffer[position] != rune('S') {
+						goto l725
 					}
 					position++
 				}
 			l739:
-				if !_rules[rulesp]() {
-					goto l729
-				}
 				{
 					position741, tokenIndex741 := position, tokenIndex
-					if !_rules[ruleFloatLiteral]() {
+					if buffer[position] != rune('i') {
 						goto l742
 					}
+					position++
 					goto l741
 				l742:
 					position, tokenIndex = position741, tokenIndex741
-					if !_rules[ruleNumericLiteral]() {
-						goto l729
+					if buffer[position] != rune('I') {
+						goto l725
 					}
+					position++
 				}
 			l741:
-				if !_rules[rulesp]() {
-					goto l729
-				}
 				{
 					position743, tokenIndex743 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('n') {
 						goto l744
 					}
 					position++
 					goto l743
 				l744:
 					position, tokenIndex = position743, tokenIndex743
-					if buffer[position] != rune('S') {
-						goto l729
+					if buffer[position] != rune('N') {
+						goto l725
 					}
 					position++
 				}
 			l743:
 				{
 					position745, tokenIndex745 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('k') {
 						goto l746
 					}
 					position++
 					goto l745
 				l746:
 					position, tokenIndex = position745, tokenIndex745
-					if buffer[position] != rune('E') {
-						goto l729
+					if buffer[position] != rune('K') {
+						goto l725
 					}
 					position++
 				}
 			l745:
-				{
-					position747, tokenIndex747 := position, tokenIndex
-					if buffer[position] != rune('c') {
-						goto l748
-					}
-					position++
-					goto l747
-				l748:
-					position, tokenIndex = position747, tokenIndex747
-					if buffer[position] != rune('C') {
-						goto l729
-					}
-					position++
+				if !_rules[rulesp]() {
+					goto l725
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l725
+				}
+				if !_rules[ruleAction27]() {
+					goto l725
 				}
-			l747:
+				add(ruleReplaySinkStmt, position726)
+			}
+			return true
+		l725:
+			position, tokenIndex = position725, tokenIndex725
+			return false
+		},
+		/* 38 DropStateStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier Action28)> */
+		func() bool {
+			position747, tokenIndex747 := position, tokenIndex
+			{
+				position748 := position
 				{
 					position749, tokenIndex749 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					if buffer[position] != rune('d') {
 						goto l750
 					}
 					position++
 					goto l749
 				l750:
 					position, tokenIndex = position749, tokenIndex749
-					if buffer[position] != rune('O') {
-						goto l729
+					if buffer[position] != rune('D') {
+						goto l747
 					}
 					position++
 				}
 			l749:
 				{
 					position751, tokenIndex751 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('r') {
 						goto l752
 					}
 					position++
 					goto l751
 				l752:
 					position, tokenIndex = position751, tokenIndex751
-					if buffer[position] != rune('N') {
-						goto l729
+					if buffer[position] != rune('R') {
+						goto l747
 					}
 					position++
 				}
 			l751:
 				{
 					position753, tokenIndex753 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					if buffer[position] != rune('o') {
 						goto l754
 					}
 					position++
 					goto l753
 				l754:
 					position, tokenIndex = position753, tokenIndex753
-					if buffer[position] != rune('D') {
-						goto l729
+					if buffer[position] != rune('O') {
+						goto l747
 					}
 					position++
 				}
 			l753:
 				{
 					position755, tokenIndex755 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('p') {
 						goto l756
 					}
 					position++
 					goto l755
 				l756:
 					position, tokenIndex = position755, tokenIndex755
-					if buffer[position] != rune('S') {
-						goto l729
+					if buffer[position] != rune('P') {
+						goto l747
 					}
 					position++
 				}
 			l755:
-				if !_rules[ruleAction29]() {
-					goto l729
+				if !_rules[rulesp]() {
+					goto l747
 				}
-				add(ruleTimeBasedSamplingSeconds, position730)
-			}
-			return true
-		l729:
-			position, tokenIndex = position729, tokenIndex729
-			return false
-		},
-		/* 39 TimeBasedSamplingMilliseconds <- <(('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y') sp (FloatLiteral / NumericLiteral) sp (('m' / 'M') ('i' / 'I') ('l' / 'L') ('l' / 'L') ('i' / 'I') ('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S')) Action30)> */
-		func() bool {
-			position757, tokenIndex757 := position, tokenIndex
-			{
-				position758 := position
+				{
+					position757, tokenIndex757 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l758
+					}
+					position++
+					goto l757
+				l758:
+					position, tokenIndex = position757, tokenIndex757
+					if buffer[position] != rune('S') {
+						goto l747
+					}
+					position++
+				}
+			l757:
 				{
 					position759, tokenIndex759 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('t') {
 						goto l760
 					}
 					position++
 					goto l759
 				l760:
 					position, tokenIndex = position759, tokenIndex759
-					if buffer[position] != rune('E') {
-						goto l757
+					if buffer[position] != rune('T') {
+						goto l747
 					}
 					position++
 				}
 			l759:
 				{
 					position761, tokenIndex761 := position, tokenIndex
-					if buffer[position] != rune('v') {
+					if buffer[position] != rune('a') {
 						goto l762
 					}
 					position++
 					goto l761
 				l762:
 					position, tokenIndex = position761, tokenIndex761
-					if buffer[position] != rune('V') {
-						goto l757
+					if buffer[position] != rune('A') {
+						goto l747
 					}
 					position++
 				}
 			l761:
 				{
 					position763, tokenIndex763 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('t') {
 						goto l764
 					}
 					position++
 					goto l763
 				l764:
 					position, tokenIndex = position763, tokenIndex763
-					if buffer[position] != rune('E') {
-						goto l757
+					if buffer[position] != rune('T') {
+						goto l747
 					}
 					position++
 				}
 			l763:
 				{
 					position765, tokenIndex765 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					if buffer[position] != rune('e') {
 						goto l766
 					}
 					position++
 					goto l765
 				l766:
 					position, tokenIndex = position765, tokenIndex765
-					if buffer[position] != rune('R') {
-						goto l757
+					if buffer[position] != rune('E') {
+						goto l747
 					}
 					position++
 				}
 			l765:
-				{
-					position767, tokenIndex767 := position, tokenIndex
-					if buffer[position] != rune('y') {
-						goto l768
-					}
-					position++
-					goto l767
-				l768:
-					position, tokenIndex = position767, tokenIndex767
-					if buffer[position] != rune('Y') {
-						goto l757
-					}
-					position++
-				}
-			l767:
 				if !_rules[rulesp]() {
-					goto l757
+					goto l747
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l747
 				}
+				if !_rules[ruleAction28]() {
+					goto l747
+				}
+				add(ruleDropStateStmt, position748)
+			}
+			return true
+		l747:
+			position, tokenIndex = position747, tokenIndex747
+			return false
+		},
+		/* 39 CreateTypeStmt <- <(<(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp StreamIdentifier sp (('a' / 'A') ('s' / 'S')) spOpt '(' spOpt TypeField (spOpt ',' spOpt TypeField)* spOpt ')')> Action29)> */
+		func() bool {
+			position767, tokenIndex767 := position, tokenIndex
+			{
+				position768 := position
 				{
-					position769, tokenIndex769 := position, tokenIndex
-					if !_rules[ruleFloatLiteral]() {
+					position769 := position
+					{
+						position770, tokenIndex770 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l771
+						}
+						position++
 						goto l770
+					l771:
+						position, tokenIndex = position770, tokenIndex770
+						if buffer[position] != rune('C') {
+							goto l767
+						}
+						position++
 					}
-					goto l769
 				l770:
-					position, tokenIndex = position769, tokenIndex769
-					if !_rules[ruleNumericLiteral]() {
-						goto l757
-					}
-				}
-			l769:
-				if !_rules[rulesp]() {
-					goto l757
-				}
-				{
-					position771, tokenIndex771 := position, tokenIndex
-					if buffer[position] != rune('m') {
+					{
+						position772, tokenIndex772 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l773
+						}
+						position++
 						goto l772
+					l773:
+						position, tokenIndex = position772, tokenIndex772
+						if buffer[position] != rune('R') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l771
 				l772:
-					position, tokenIndex = position771, tokenIndex771
-					if buffer[position] != rune('M') {
-						goto l757
-					}
-					position++
-				}
-			l771:
-				{
-					position773, tokenIndex773 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					{
+						position774, tokenIndex774 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l775
+						}
+						position++
 						goto l774
+					l775:
+						position, tokenIndex = position774, tokenIndex774
+						if buffer[position] != rune('E') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l773
 				l774:
-					position, tokenIndex = position773, tokenIndex773
-					if buffer[position] != rune('I') {
-						goto l757
-					}
-					position++
-				}
-			l773:
-				{
-					position775, tokenIndex775 := position, tokenIndex
-					if buffer[position] != rune('l') {
+					{
+						position776, tokenIndex776 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l777
+						}
+						position++
 						goto l776
+					l777:
+						position, tokenIndex = position776, tokenIndex776
+						if buffer[position] != rune('A') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l775
 				l776:
-					position, tokenIndex = position775, tokenIndex775
-					if buffer[position] != rune('L') {
-						goto l757
-					}
-					position++
-				}
-			l775:
-				{
-					position777, tokenIndex777 := position, tokenIndex
-					if buffer[position] != rune('l') {
+					{
+						position778, tokenIndex778 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l779
+						}
+						position++
 						goto l778
+					l779:
+						position, tokenIndex = position778, tokenIndex778
+						if buffer[position] != rune('T') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l777
 				l778:
-					position, tokenIndex = position777, tokenIndex777
-					if buffer[position] != rune('L') {
-						goto l757
-					}
-					position++
-				}
-			l777:
-				{
-					position779, tokenIndex779 := position, tokenIndex
-					if buffer[position] != rune('i') {
+					{
+						position780, tokenIndex780 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l781
+						}
+						position++
 						goto l780
+					l781:
+						position, tokenIndex = position780, tokenIndex780
+						if buffer[position] != rune('E') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l779
 				l780:
-					position, tokenIndex = position779, tokenIndex779
-					if buffer[position] != rune('I') {
-						goto l757
+					if !_rules[rulesp]() {
+						goto l767
 					}
-					position++
-				}
-			l779:
-				{
-					position781, tokenIndex781 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					{
+						position782, tokenIndex782 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l783
+						}
+						position++
 						goto l782
+					l783:
+						position, tokenIndex = position782, tokenIndex782
+						if buffer[position] != rune('T') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l781
-				l782:
-					position, tokenIndex = position781, tokenIndex781
-					if buffer[position] != rune('S') {
-						goto l757
-					}
-					position++
-				}
-			l781:
-				{
-					position783, tokenIndex783 := position, tokenIndex
-					if buffer[position] != rune('e') {
+				l782:
+					{
+						position784, tokenIndex784 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l785
+						}
+						position++
 						goto l784
+					l785:
+						position, tokenIndex = position784, tokenIndex784
+						if buffer[position] != rune('Y') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l783
 				l784:
-					position, tokenIndex = position783, tokenIndex783
-					if buffer[position] != rune('E') {
-						goto l757
-					}
-					position++
-				}
-			l783:
-				{
-					position785, tokenIndex785 := position, tokenIndex
-					if buffer[position] != rune('c') {
+					{
+						position786, tokenIndex786 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l787
+						}
+						position++
 						goto l786
+					l787:
+						position, tokenIndex = position786, tokenIndex786
+						if buffer[position] != rune('P') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l785
 				l786:
-					position, tokenIndex = position785, tokenIndex785
-					if buffer[position] != rune('C') {
-						goto l757
-					}
-					position++
-				}
-			l785:
-				{
-					position787, tokenIndex787 := position, tokenIndex
-					if buffer[position] != rune('o') {
+					{
+						position788, tokenIndex788 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l789
+						}
+						position++
 						goto l788
+					l789:
+						position, tokenIndex = position788, tokenIndex788
+						if buffer[position] != rune('E') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l787
 				l788:
-					position, tokenIndex = position787, tokenIndex787
-					if buffer[position] != rune('O') {
-						goto l757
+					if !_rules[rulesp]() {
+						goto l767
 					}
-					position++
-				}
-			l787:
-				{
-					position789, tokenIndex789 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if !_rules[ruleStreamIdentifier]() {
+						goto l767
+					}
+					if !_rules[rulesp]() {
+						goto l767
+					}
+					{
+						position790, tokenIndex790 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l791
+						}
+						position++
 						goto l790
+					l791:
+						position, tokenIndex = position790, tokenIndex790
+						if buffer[position] != rune('A') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l789
 				l790:
-					position, tokenIndex = position789, tokenIndex789
-					if buffer[position] != rune('N') {
-						goto l757
-					}
-					position++
-				}
-			l789:
-				{
-					position791, tokenIndex791 := position, tokenIndex
-					if buffer[position] != rune('d') {
+					{
+						position792, tokenIndex792 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l793
+						}
+						position++
 						goto l792
+					l793:
+						position, tokenIndex = position792, tokenIndex792
+						if buffer[position] != rune('S') {
+							goto l767
+						}
+						position++
 					}
-					position++
-					goto l791
 				l792:
-					position, tokenIndex = position791, tokenIndex791
-					if buffer[position] != rune('D') {
-						goto l757
-					}
-					position++
-				}
-			l791:
-				{
-					position793, tokenIndex793 := position, tokenIndex
-					if buffer[position] != rune('s') {
-						goto l794
+					if !_rules[rulespOpt]() {
+						goto l767
 					}
-					position++
-					goto l793
-				l794:
-					position, tokenIndex = position793, tokenIndex793
-					if buffer[position] != rune('S') {
-						goto l757
+					if buffer[position] != rune('(') {
+						goto l767
 					}
 					position++
-				}
-			l793:
-				if !_rules[ruleAction30]() {
-					goto l757
-				}
-				add(ruleTimeBasedSamplingMilliseconds, position758)
-			}
-			return true
-		l757:
-			position, tokenIndex = position757, tokenIndex757
-			return false
-		},
-		/* 40 Projections <- <(<(sp Projection (spOpt ',' spOpt Projection)*)> Action31)> */
-		func() bool {
-			position795, tokenIndex795 := position, tokenIndex
-			{
-				position796 := position
-				{
-					position797 := position
-					if !_rules[rulesp]() {
-						goto l795
+					if !_rules[rulespOpt]() {
+						goto l767
 					}
-					if !_rules[ruleProjection]() {
-						goto l795
+					if !_rules[ruleTypeField]() {
+						goto l767
 					}
-				l798:
+				l794:
 					{
-						position799, tokenIndex799 := position, tokenIndex
+						position795, tokenIndex795 := position, tokenIndex
 						if !_rules[rulespOpt]() {
-							goto l799
+							goto l795
 						}
 						if buffer[position] != rune(',') {
-							goto l799
+							goto l795
 						}
 						position++
 						if !_rules[rulespOpt]() {
-							goto l799
+							goto l795
 						}
-						if !_rules[ruleProjection]() {
-							goto l799
+						if !_rules[ruleTypeField]() {
+							goto l795
 						}
-						goto l798
-					l799:
-						position, tokenIndex = position799, tokenIndex799
+						goto l794
+					l795:
+						position, tokenIndex = position795, tokenIndex795
+					}
+					if !_rules[rulespOpt]() {
+						goto l767
+					}
+					if buffer[position] != rune(')') {
+						goto l767
 					}
-					add(rulePegText, position797)
+					position++
+					add(rulePegText, position769)
 				}
-				if !_rules[ruleAction31]() {
-					goto l795
+				if !_rules[ruleAction29]() {
+					goto l767
+				}
+				add(ruleCreateTypeStmt, position768)
+			}
+			return true
+		l767:
+			position, tokenIndex = position767, tokenIndex767
+			return false
+		},
+		/* 40 TypeField <- <(Identifier sp Type Action30)> */
+		func() bool {
+			position796, tokenIndex796 := position, tokenIndex
+			{
+				position797 := position
+				if !_rules[ruleIdentifier]() {
+					goto l796
+				}
+				if !_rules[rulesp]() {
+					goto l796
+				}
+				if !_rules[ruleType]() {
+					goto l796
+				}
+				if !_rules[ruleAction30]() {
+					goto l796
 				}
-				add(ruleProjections, position796)
+				add(ruleTypeField, position797)
 			}
 			return true
-		l795:
-			position, tokenIndex = position795, tokenIndex795
+		l796:
+			position, tokenIndex = position796, tokenIndex796
 			return false
 		},
-		/* 41 Projection <- <(AliasExpression / ExpressionOrWildcard)> */
+		/* 41 DropTypeStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp StreamIdentifier Action31)> */
 		func() bool {
-			position800, tokenIndex800 := position, tokenIndex
+			position798, tokenIndex798 := position, tokenIndex
 			{
-				position801 := position
+				position799 := position
+				{
+					position800, tokenIndex800 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l801
+					}
+					position++
+					goto l800
+				l801:
+					position, tokenIndex = position800, tokenIndex800
+					if buffer[position] != rune('D') {
+						goto l798
+					}
+					position++
+				}
+			l800:
 				{
 					position802, tokenIndex802 := position, tokenIndex
-					if !_rules[ruleAliasExpression]() {
+					if buffer[position] != rune('r') {
 						goto l803
 					}
+					position++
 					goto l802
 				l803:
 					position, tokenIndex = position802, tokenIndex802
-					if !_rules[ruleExpressionOrWildcard]() {
-						goto l800
+					if buffer[position] != rune('R') {
+						goto l798
 					}
+					position++
 				}
 			l802:
-				add(ruleProjection, position801)
-			}
-			return true
-		l800:
-			position, tokenIndex = position800, tokenIndex800
-			return false
-		},
-		/* 42 AliasExpression <- <(ExpressionOrWildcard sp (('a' / 'A') ('s' / 'S')) sp TargetIdentifier Action32)> */
-		func() bool {
-			position804, tokenIndex804 := position, tokenIndex
-			{
-				position805 := position
-				if !_rules[ruleExpressionOrWildcard]() {
-					goto l804
-				}
-				if !_rules[rulesp]() {
+				{
+					position804, tokenIndex804 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l805
+					}
+					position++
 					goto l804
+				l805:
+					position, tokenIndex = position804, tokenIndex804
+					if buffer[position] != rune('O') {
+						goto l798
+					}
+					position++
 				}
+			l804:
 				{
 					position806, tokenIndex806 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('p') {
 						goto l807
 					}
 					position++
 					goto l806
 				l807:
 					position, tokenIndex = position806, tokenIndex806
-					if buffer[position] != rune('A') {
-						goto l804
+					if buffer[position] != rune('P') {
+						goto l798
 					}
 					position++
 				}
 			l806:
+				if !_rules[rulesp]() {
+					goto l798
+				}
 				{
 					position808, tokenIndex808 := position, tokenIndex
-					if buffer[position] != rune('s') {
+					if buffer[position] != rune('t') {
 						goto l809
 					}
 					position++
 					goto l808
 				l809:
 					position, tokenIndex = position808, tokenIndex808
-					if buffer[position] != rune('S') {
-						goto l804
+					if buffer[position] != rune('T') {
+						goto l798
 					}
 					position++
 				}
 			l808:
+				{
+					position810, tokenIndex810 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l811
+					}
+					position++
+					goto l810
+				l811:
+					position, tokenIndex = position810, tokenIndex810
+					if buffer[position] != rune('Y') {
+						goto l798
+					}
+					position++
+				}
+			l810:
+				{
+					position812, tokenIndex812 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l813
+					}
+					position++
+					goto l812
+				l813:
+					position, tokenIndex = position812, tokenIndex812
+					if buffer[position] != rune('P') {
+						goto l798
+					}
+					position++
+				}
+			l812:
+				{
+					position814, tokenIndex814 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l815
+					}
+					position++
+					goto l814
+				l815:
+					position, tokenIndex = position814, tokenIndex814
+					if buffer[position] != rune('E') {
+						goto l798
+					}
+					position++
+				}
+			l814:
 				if !_rules[rulesp]() {
-					goto l804
+					goto l798
 				}
-				if !_rules[ruleTargetIdentifier]() {
-					goto l804
+				if !_rules[ruleStreamIdentifier]() {
+					goto l798
 				}
-				if !_rules[ruleAction32]() {
-					goto l804
+				if !_rules[ruleAction31]() {
+					goto l798
 				}
-				add(ruleAliasExpression, position805)
+				add(ruleDropTypeStmt, position799)
 			}
 			return true
-		l804:
-			position, tokenIndex = position804, tokenIndex804
+		l798:
+			position, tokenIndex = position798, tokenIndex798
 			return false
 		},
-		/* 43 WindowedFrom <- <(<(sp (('f' / 'F') ('r' / 'R') ('o' / 'O') ('m' / 'M')) sp Relations)?> Action33)> */
+		/* 42 CreateTriggerStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('t' / 'T') ('r' / 'R') ('i' / 'I') ('g' / 'G') ('g' / 'G') ('e' / 'E') ('r' / 'R')) sp StreamIdentifier sp (('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y')) sp StringLiteral sp (('d' / 'D') ('o' / 'O')) sp TriggerAction Action32)> */
 		func() bool {
-			position810, tokenIndex810 := position, tokenIndex
+			position816, tokenIndex816 := position, tokenIndex
 			{
-				position811 := position
+				position817 := position
 				{
-					position812 := position
-					{
-						position813, tokenIndex813 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l813
-						}
-						{
-							position815, tokenIndex815 := position, tokenIndex
-							if buffer[position] != rune('f') {
-								goto l816
-							}
-							position++
-							goto l815
-						l816:
-							position, tokenIndex = position815, tokenIndex815
-							if buffer[position] != rune('F') {
-								goto l813
-							}
-							position++
-						}
-					l815:
-						{
-							position817, tokenIndex817 := position, tokenIndex
-							if buffer[position] != rune('r') {
-								goto l818
-							}
-							position++
-							goto l817
-						l818:
-							position, tokenIndex = position817, tokenIndex817
-							if buffer[position] != rune('R') {
-								goto l813
-							}
-							position++
-						}
-					l817:
-						{
-							position819, tokenIndex819 := position, tokenIndex
-							if buffer[position] != rune('o') {
-								goto l820
-							}
-							position++
-							goto l819
-						l820:
-							position, tokenIndex = position819, tokenIndex819
-							if buffer[position] != rune('O') {
-								goto l813
-							}
-							position++
-						}
-					l819:
-						{
-							position821, tokenIndex821 := position, tokenIndex
-							if buffer[position] != rune('m') {
-								goto l822
-							}
-							position++
-							goto l821
-						l822:
-							position, tokenIndex = position821, tokenIndex821
-							if buffer[position] != rune('M') {
-								goto l813
-							}
-							position++
-						}
-					l821:
-						if !_rules[rulesp]() {
-							goto l813
-						}
-						if !_rules[ruleRelations]() {
-							goto l813
-						}
-						goto l814
-					l813:
-						position, tokenIndex = position813, tokenIndex813
+					position818, tokenIndex818 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l819
+					}
+					position++
+					goto l818
+				l819:
+					position, tokenIndex = position818, tokenIndex818
+					if buffer[position] != rune('C') {
+						goto l816
 					}
-				l814:
-					add(rulePegText, position812)
+					position++
 				}
-				if !_rules[ruleAction33]() {
-					goto l810
-				}
-				add(ruleWindowedFrom, position811)
-			}
-			return true
-		l810:
-			position, tokenIndex = position810, tokenIndex810
-			return false
-		},
-		/* 44 Interval <- <(TimeInterval / TuplesInterval)> */
-		func() bool {
-			position823, tokenIndex823 := position, tokenIndex
-			{
-				position824 := position
+			l818:
 				{
-					position825, tokenIndex825 := position, tokenIndex
-					if !_rules[ruleTimeInterval]() {
-						goto l826
+					position820, tokenIndex820 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l821
 					}
-					goto l825
-				l826:
-					position, tokenIndex = position825, tokenIndex825
-					if !_rules[ruleTuplesInterval]() {
+					position++
+					goto l820
+				l821:
+					position, tokenIndex = position820, tokenIndex820
+					if buffer[position] != rune('R') {
+						goto l816
+					}
+					position++
+				}
+			l820:
+				{
+					position822, tokenIndex822 := position, tokenIndex
+					if buffer[position] != rune('e') {
 						goto l823
 					}
+					position++
+					goto l822
+				l823:
+					position, tokenIndex = position822, tokenIndex822
+					if buffer[position] != rune('E') {
+						goto l816
+					}
+					position++
 				}
-			l825:
-				add(ruleInterval, position824)
-			}
-			return true
-		l823:
-			position, tokenIndex = position823, tokenIndex823
-			return false
-		},
-		/* 45 TimeInterval <- <((FloatLiteral / NumericLiteral) sp (SECONDS / MILLISECONDS) Action34)> */
-		func() bool {
-			position827, tokenIndex827 := position, tokenIndex
-			{
-				position828 := position
+			l822:
 				{
-					position829, tokenIndex829 := position, tokenIndex
-					if !_rules[ruleFloatLiteral]() {
-						goto l830
+					position824, tokenIndex824 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l825
 					}
-					goto l829
-				l830:
-					position, tokenIndex = position829, tokenIndex829
-					if !_rules[ruleNumericLiteral]() {
+					position++
+					goto l824
+				l825:
+					position, tokenIndex = position824, tokenIndex824
+					if buffer[position] != rune('A') {
+						goto l816
+					}
+					position++
+				}
+			l824:
+				{
+					position826, tokenIndex826 := position, tokenIndex
+					if buffer[position] != rune('t') {
 						goto l827
 					}
+					position++
+					goto l826
+				l827:
+					position, tokenIndex = position826, tokenIndex826
+					if buffer[position] != rune('T') {
+						goto l816
+					}
+					position++
+				}
+			l826:
+				{
+					position828, tokenIndex828 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l829
+					}
+					position++
+					goto l828
+				l829:
+					position, tokenIndex = position828, tokenIndex828
+					if buffer[position] != rune('E') {
+						goto l816
+					}
+					position++
 				}
-			l829:
+			l828:
 				if !_rules[rulesp]() {
-					goto l827
+					goto l816
 				}
 				{
-					position831, tokenIndex831 := position, tokenIndex
-					if !_rules[ruleSECONDS]() {
-						goto l832
+					position830, tokenIndex830 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l831
 					}
-					goto l831
-				l832:
-					position, tokenIndex = position831, tokenIndex831
-					if !_rules[ruleMILLISECONDS]() {
-						goto l827
+					position++
+					goto l830
+				l831:
+					position, tokenIndex = position830, tokenIndex830
+					if buffer[position] != rune('T') {
+						goto l816
 					}
+					position++
 				}
-			l831:
-				if !_rules[ruleAction34]() {
-					goto l827
+			l830:
+				{
+					position832, tokenIndex832 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l833
+					}
+					position++
+					goto l832
+				l833:
+					position, tokenIndex = position832, tokenIndex832
+					if buffer[position] != rune('R') {
+						goto l816
+					}
+					position++
 				}
-				add(ruleTimeInterval, position828)
-			}
-			return true
-		l827:
-			position, tokenIndex = position827, tokenIndex827
-			return false
-		},
-		/* 46 TuplesInterval <- <(NumericLiteral sp TUPLES Action35)> */
-		func() bool {
-			position833, tokenIndex833 := position, tokenIndex
-			{
-				position834 := position
-				if !_rules[ruleNumericLiteral]() {
-					goto l833
+			l832:
+				{
+					position834, tokenIndex834 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l835
+					}
+					position++
+					goto l834
+				l835:
+					position, tokenIndex = position834, tokenIndex834
+					if buffer[position] != rune('I') {
+						goto l816
+					}
+					position++
+				}
+			l834:
+				{
+					position836, tokenIndex836 := position, tokenIndex
+					if buffer[position] != rune('g') {
+						goto l837
+					}
+					position++
+					goto l836
+				l837:
+					position, tokenIndex = position836, tokenIndex836
+					if buffer[position] != rune('G') {
+						goto l816
+					}
+					position++
+				}
+			l836:
+				{
+					position838, tokenIndex838 := position, tokenIndex
+					if buffer[position] != rune('g') {
+						goto l839
+					}
+					position++
+					goto l838
+				l839:
+					position, tokenIndex = position838, tokenIndex838
+					if buffer[position] != rune('G') {
+						goto l816
+					}
+					position++
 				}
+			l838:
+				{
+					position840, tokenIndex840 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l841
+					}
+					position++
+					goto l840
+				l841:
+					position, tokenIndex = position840, tokenIndex840
+					if buffer[position] != rune('E') {
+						goto l816
+					}
+					position++
+				}
+			l840:
+				{
+					position842, tokenIndex842 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l843
+					}
+					position++
+					goto l842
+				l843:
+					position, tokenIndex = position842, tokenIndex842
+					if buffer[position] != rune('R') {
+						goto l816
+					}
+					position++
+				}
+			l842:
 				if !_rules[rulesp]() {
-					goto l833
+					goto l816
 				}
-				if !_rules[ruleTUPLES]() {
-					goto l833
+				if !_rules[ruleStreamIdentifier]() {
+					goto l816
 				}
-				if !_rules[ruleAction35]() {
-					goto l833
+				if !_rules[rulesp]() {
+					goto l816
 				}
-				add(ruleTuplesInterval, position834)
-			}
-			return true
-		l833:
-			position, tokenIndex = position833, tokenIndex833
-			return false
-		},
-		/* 47 Relations <- <(RelationLike (spOpt ',' spOpt RelationLike)*)> */
-		func() bool {
-			position835, tokenIndex835 := position, tokenIndex
-			{
-				position836 := position
-				if !_rules[ruleRelationLike]() {
-					goto l835
+				{
+					position844, tokenIndex844 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l845
+					}
+					position++
+					goto l844
+				l845:
+					position, tokenIndex = position844, tokenIndex844
+					if buffer[position] != rune('E') {
+						goto l816
+					}
+					position++
 				}
-			l837:
+			l844:
 				{
-					position838, tokenIndex838 := position, tokenIndex
-					if !_rules[rulespOpt]() {
-						goto l838
+					position846, tokenIndex846 := position, tokenIndex
+					if buffer[position] != rune('v') {
+						goto l847
 					}
-					if buffer[position] != rune(',') {
-						goto l838
+					position++
+					goto l846
+				l847:
+					position, tokenIndex = position846, tokenIndex846
+					if buffer[position] != rune('V') {
+						goto l816
 					}
 					position++
-					if !_rules[rulespOpt]() {
-						goto l838
+				}
+			l846:
+				{
+					position848, tokenIndex848 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l849
 					}
-					if !_rules[ruleRelationLike]() {
-						goto l838
+					position++
+					goto l848
+				l849:
+					position, tokenIndex = position848, tokenIndex848
+					if buffer[position] != rune('E') {
+						goto l816
 					}
-					goto l837
-				l838:
-					position, tokenIndex = position838, tokenIndex838
+					position++
+				}
+			l848:
+				{
+					position850, tokenIndex850 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l851
+					}
+					position++
+					goto l850
+				l851:
+					position, tokenIndex = position850, tokenIndex850
+					if buffer[position] != rune('R') {
+						goto l816
+					}
+					position++
+				}
+			l850:
+				{
+					position852, tokenIndex852 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l853
+					}
+					position++
+					goto l852
+				l853:
+					position, tokenIndex = position852, tokenIndex852
+					if buffer[position] != rune('Y') {
+						goto l816
+					}
+					position++
+				}
+			l852:
+				if !_rules[rulesp]() {
+					goto l816
+				}
+				if !_rules[ruleStringLiteral]() {
+					goto l816
+				}
+				if !_rules[rulesp]() {
+					goto l816
+				}
+				{
+					position854, tokenIndex854 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l855
+					}
+					position++
+					goto l854
+				l855:
+					position, tokenIndex = position854, tokenIndex854
+					if buffer[position] != rune('D') {
+						goto l816
+					}
+					position++
+				}
+			l854:
+				{
+					position856, tokenIndex856 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l857
+					}
+					position++
+					goto l856
+				l857:
+					position, tokenIndex = position856, tokenIndex856
+					if buffer[position] != rune('O') {
+						goto l816
+					}
+					position++
+				}
+			l856:
+				if !_rules[rulesp]() {
+					goto l816
+				}
+				if !_rules[ruleTriggerAction]() {
+					goto l816
 				}
-				add(ruleRelations, position836)
+				if !_rules[ruleAction32]() {
+					goto l816
+				}
+				add(ruleCreateTriggerStmt, position817)
 			}
 			return true
-		l835:
-			position, tokenIndex = position835, tokenIndex835
+		l816:
+			position, tokenIndex = position816, tokenIndex816
 			return false
 		},
-		/* 48 Filter <- <(<(sp (('w' / 'W') ('h' / 'H') ('e' / 'E') ('r' / 'R') ('e' / 'E')) sp Expression)?> Action36)> */
+		/* 43 TriggerAction <- <(TriggerInsertStmt / UpdateStateStmt / SaveStateStmt / UpdateSourceStmt / UpdateSinkStmt / PauseSourceStmt / ResumeSourceStmt / RewindSourceStmt)> */
 		func() bool {
-			position839, tokenIndex839 := position, tokenIndex
+			position858, tokenIndex858 := position, tokenIndex
 			{
-				position840 := position
+				position859 := position
 				{
-					position841 := position
-					{
-						position842, tokenIndex842 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l842
-						}
-						{
-							position844, tokenIndex844 := position, tokenIndex
-							if buffer[position] != rune('w') {
-								goto l845
-							}
-							position++
-							goto l844
-						l845:
-							position, tokenIndex = position844, tokenIndex844
-							if buffer[position] != rune('W') {
-								goto l842
-							}
-							position++
-						}
-					l844:
-						{
-							position846, tokenIndex846 := position, tokenIndex
-							if buffer[position] != rune('h') {
-								goto l847
-							}
-							position++
-							goto l846
-						l847:
-							position, tokenIndex = position846, tokenIndex846
-							if buffer[position] != rune('H') {
-								goto l842
-							}
-							position++
-						}
-					l846:
-						{
-							position848, tokenIndex848 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l849
-							}
-							position++
-							goto l848
-						l849:
-							position, tokenIndex = position848, tokenIndex848
-							if buffer[position] != rune('E') {
-								goto l842
-							}
-							position++
-						}
-					l848:
-						{
-							position850, tokenIndex850 := position, tokenIndex
-							if buffer[position] != rune('r') {
-								goto l851
-							}
-							position++
-							goto l850
-						l851:
-							position, tokenIndex = position850, tokenIndex850
-							if buffer[position] != rune('R') {
-								goto l842
-							}
-							position++
-						}
-					l850:
-						{
-							position852, tokenIndex852 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l853
-							}
-							position++
-							goto l852
-						l853:
-							position, tokenIndex = position852, tokenIndex852
-							if buffer[position] != rune('E') {
-								goto l842
-							}
-							position++
-						}
-					l852:
-						if !_rules[rulesp]() {
-							goto l842
-						}
-						if !_rules[ruleExpression]() {
-							goto l842
-						}
-						goto l843
-					l842:
-						position, tokenIndex = position842, tokenIndex842
+					position860, tokenIndex860 := position, tokenIndex
+					if !_rules[ruleTriggerInsertStmt]() {
+						goto l861
+					}
+					goto l860
+				l861:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[ruleUpdateStateStmt]() {
+						goto l862
+					}
+					goto l860
+				l862:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[ruleSaveStateStmt]() {
+						goto l863
+					}
+					goto l860
+				l863:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[ruleUpdateSourceStmt]() {
+						goto l864
+					}
+					goto l860
+				l864:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[ruleUpdateSinkStmt]() {
+						goto l865
+					}
+					goto l860
+				l865:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[rulePauseSourceStmt]() {
+						goto l866
+					}
+					goto l860
+				l866:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[ruleResumeSourceStmt]() {
+						goto l867
+					}
+					goto l860
+				l867:
+					position, tokenIndex = position860, tokenIndex860
+					if !_rules[ruleRewindSourceStmt]() {
+						goto l858
 					}
-				l843:
-					add(rulePegText, position841)
-				}
-				if !_rules[ruleAction36]() {
-					goto l839
 				}
-				add(ruleFilter, position840)
+			l860:
+				add(ruleTriggerAction, position859)
 			}
 			return true
-		l839:
-			position, tokenIndex = position839, tokenIndex839
+		l858:
+			position, tokenIndex = position858, tokenIndex858
 			return false
 		},
-		/* 49 Grouping <- <(<(sp (('g' / 'G') ('r' / 'R') ('o' / 'O') ('u' / 'U') ('p' / 'P')) sp (('b' / 'B') ('y' / 'Y')) sp GroupList)?> Action37)> */
+		/* 44 TriggerInsertStmt <- <(('i' / 'I') ('n' / 'N') ('s' / 'S') ('e' / 'E') ('r' / 'R') ('t' / 'T') sp (('i' / 'I') ('n' / 'N') ('t' / 'T') ('o' / 'O')) sp StreamIdentifier sp (('s' / 'S') ('e' / 'E') ('l' / 'L') ('e' / 'E') ('c' / 'C') ('t' / 'T')) Projections Action33)> */
 		func() bool {
-			position854, tokenIndex854 := position, tokenIndex
+			position868, tokenIndex868 := position, tokenIndex
 			{
-				position855 := position
+				position869 := position
 				{
-					position856 := position
-					{
-						position857, tokenIndex857 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l857
-						}
-						{
-							position859, tokenIndex859 := position, tokenIndex
-							if buffer[position] != rune('g') {
-								goto l860
-							}
-							position++
-							goto l859
-						l860:
-							position, tokenIndex = position859, tokenIndex859
-							if buffer[position] != rune('G') {
-								goto l857
-							}
-							position++
-						}
-					l859:
-						{
-							position861, tokenIndex861 := position, tokenIndex
-							if buffer[position] != rune('r') {
-								goto l862
-							}
-							position++
-							goto l861
-						l862:
-							position, tokenIndex = position861, tokenIndex861
-							if buffer[position] != rune('R') {
-								goto l857
-							}
-							position++
-						}
-					l861:
-						{
-							position863, tokenIndex863 := position, tokenIndex
-							if buffer[position] != rune('o') {
-								goto l864
-							}
-							position++
-							goto l863
-						l864:
-							position, tokenIndex = position863, tokenIndex863
-							if buffer[position] != rune('O') {
-								goto l857
-							}
-							position++
-						}
-					l863:
-						{
-							position865, tokenIndex865 := position, tokenIndex
-							if buffer[position] != rune('u') {
-								goto l866
-							}
-							position++
-							goto l865
-						l866:
-							position, tokenIndex = position865, tokenIndex865
-							if buffer[position] != rune('U') {
-								goto l857
-							}
-							position++
-						}
-					l865:
-						{
-							position867, tokenIndex867 := position, tokenIndex
-							if buffer[position] != rune('p') {
-								goto l868
-							}
-							position++
-							goto l867
-						l868:
-							position, tokenIndex = position867, tokenIndex867
-							if buffer[position] != rune('P') {
-								goto l857
-							}
-							position++
-						}
-					l867:
-						if !_rules[rulesp]() {
-							goto l857
-						}
-						{
-							position869, tokenIndex869 := position, tokenIndex
-							if buffer[position] != rune('b') {
-								goto l870
-							}
-							position++
-							goto l869
-						l870:
-							position, tokenIndex = position869, tokenIndex869
-							if buffer[position] != rune('B') {
-								goto l857
-							}
-							position++
-						}
-					l869:
-						{
-							position871, tokenIndex871 := position, tokenIndex
-							if buffer[position] != rune('y') {
-								goto l872
-							}
-							position++
-							goto l871
-						l872:
-							position, tokenIndex = position871, tokenIndex871
-							if buffer[position] != rune('Y') {
-								goto l857
-							}
-							position++
-						}
-					l871:
-						if !_rules[rulesp]() {
-							goto l857
-						}
-						if !_rules[ruleGroupList]() {
-							goto l857
-						}
-						goto l858
-					l857:
-						position, tokenIndex = position857, tokenIndex857
+					position870, tokenIndex870 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l871
+					}
+					position++
+					goto l870
+				l871:
+					position, tokenIndex = position870, tokenIndex870
+					if buffer[position] != rune('I') {
+						goto l868
 					}
-				l858:
-					add(rulePegText, position856)
+					position++
 				}
-				if !_rules[ruleAction37]() {
-					goto l854
+			l870:
+				{
+					position872, tokenIndex872 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l873
+					}
+					position++
+					goto l872
+				l873:
+					position, tokenIndex = position872, tokenIndex872
+					if buffer[position] != rune('N') {
+						goto l868
+					}
+					position++
 				}
-				add(ruleGrouping, position855)
-			}
-			return true
-		l854:
-			position, tokenIndex = position854, tokenIndex854
-			return false
-		},
-		/* 50 GroupList <- <(Expression (spOpt ',' spOpt Expression)*)> */
-		func() bool {
-			position873, tokenIndex873 := position, tokenIndex
-			{
-				position874 := position
-				if !_rules[ruleExpression]() {
-					goto l873
+			l872:
+				{
+					position874, tokenIndex874 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l875
+					}
+					position++
+					goto l874
+				l875:
+					position, tokenIndex = position874, tokenIndex874
+					if buffer[position] != rune('S') {
+						goto l868
+					}
+					position++
 				}
-			l875:
+			l874:
 				{
 					position876, tokenIndex876 := position, tokenIndex
-					if !_rules[rulespOpt]() {
-						goto l876
+					if buffer[position] != rune('e') {
+						goto l877
 					}
-					if buffer[position] != rune(',') {
-						goto l876
+					position++
+					goto l876
+				l877:
+					position, tokenIndex = position876, tokenIndex876
+					if buffer[position] != rune('E') {
+						goto l868
 					}
 					position++
-					if !_rules[rulespOpt]() {
-						goto l876
+				}
+			l876:
+				{
+					position878, tokenIndex878 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l879
 					}
-					if !_rules[ruleExpression]() {
-						goto l876
+					position++
+					goto l878
+				l879:
+					position, tokenIndex = position878, tokenIndex878
+					if buffer[position] != rune('R') {
+						goto l868
 					}
-					goto l875
-				l876:
-					position, tokenIndex = position876, tokenIndex876
+					position++
 				}
-				add(ruleGroupList, position874)
-			}
-			return true
-		l873:
-			position, tokenIndex = position873, tokenIndex873
-			return false
-		},
-		/* 51 Having <- <(<(sp (('h' / 'H') ('a' / 'A') ('v' / 'V') ('i' / 'I') ('n' / 'N') ('g' / 'G')) sp Expression)?> Action38)> */
-		func() bool {
-			position877, tokenIndex877 := position, tokenIndex
-			{
-				position878 := position
+			l878:
 				{
-					position879 := position
-					{
-						position880, tokenIndex880 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l880
-						}
-						{
-							position882, tokenIndex882 := position, tokenIndex
-							if buffer[position] != rune('h') {
-								goto l883
-							}
-							position++
-							goto l882
-						l883:
-							position, tokenIndex = position882, tokenIndex882
-							if buffer[position] != rune('H') {
-								goto l880
-							}
-							position++
-						}
-					l882:
-						{
-							position884, tokenIndex884 := position, tokenIndex
-							if buffer[position] != rune('a') {
-								goto l885
-							}
-							position++
-							goto l884
-						l885:
-							position, tokenIndex = position884, tokenIndex884
-							if buffer[position] != rune('A') {
-								goto l880
-							}
-							position++
-						}
-					l884:
-						{
-							position886, tokenIndex886 := position, tokenIndex
-							if buffer[position] != rune('v') {
-								goto l887
-							}
-							position++
-							goto l886
-						l887:
-							position, tokenIndex = position886, tokenIndex886
-							if buffer[position] != rune('V') {
-								goto l880
-							}
-							position++
-						}
-					l886:
-						{
-							position888, tokenIndex888 := position, tokenIndex
-							if buffer[position] != rune('i') {
-								goto l889
-							}
-							position++
-							goto l888
-						l889:
-							position, tokenIndex = position888, tokenIndex888
-							if buffer[position] != rune('I') {
-								goto l880
-							}
-							position++
-						}
-					l888:
-						{
-							position890, tokenIndex890 := position, tokenIndex
-							if buffer[position] != rune('n') {
-								goto l891
-							}
-							position++
-							goto l890
-						l891:
-							position, tokenIndex = position890, tokenIndex890
-							if buffer[position] != rune('N') {
-								goto l880
-							}
-							position++
-						}
-					l890:
-						{
-							position892, tokenIndex892 := position, tokenIndex
-							if buffer[position] != rune('g') {
-								goto l893
-							}
-							position++
-							goto l892
-						l893:
-							position, tokenIndex = position892, tokenIndex892
-							if buffer[position] != rune('G') {
-								goto l880
-							}
-							position++
-						}
-					l892:
-						if !_rules[rulesp]() {
-							goto l880
-						}
-						if !_rules[ruleExpression]() {
-							goto l880
-						}
+					position880, tokenIndex880 := position, tokenIndex
+					if buffer[position] != rune('t') {
 						goto l881
-					l880:
-						position, tokenIndex = position880, tokenIndex880
 					}
+					position++
+					goto l880
 				l881:
-					add(rulePegText, position879)
+					position, tokenIndex = position880, tokenIndex880
+					if buffer[position] != rune('T') {
+						goto l868
+					}
+					position++
 				}
-				if !_rules[ruleAction38]() {
-					goto l877
+			l880:
+				if !_rules[rulesp]() {
+					goto l868
 				}
-				add(ruleHaving, position878)
-			}
-			return true
-		l877:
-			position, tokenIndex = position877, tokenIndex877
-			return false
-		},
-		/* 52 RelationLike <- <(AliasedStreamWindow / (StreamWindow Action39))> */
-		func() bool {
-			position894, tokenIndex894 := position, tokenIndex
-			{
-				position895 := position
 				{
-					position896, tokenIndex896 := position, tokenIndex
-					if !_rules[ruleAliasedStreamWindow]() {
-						goto l897
-					}
-					goto l896
-				l897:
-					position, tokenIndex = position896, tokenIndex896
-					if !_rules[ruleStreamWindow]() {
-						goto l894
+					position882, tokenIndex882 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l883
 					}
-					if !_rules[ruleAction39]() {
-						goto l894
+					position++
+					goto l882
+				l883:
+					position, tokenIndex = position882, tokenIndex882
+					if buffer[position] != rune('I') {
+						goto l868
 					}
+					position++
 				}
-			l896:
-				add(ruleRelationLike, position895)
-			}
-			return true
-		l894:
-			position, tokenIndex = position894, tokenIndex894
-			return false
-		},
-		/* 53 AliasedStreamWindow <- <(StreamWindow sp (('a' / 'A') ('s' / 'S')) sp Identifier Action40)> */
-		func() bool {
-			position898, tokenIndex898 := position, tokenIndex
-			{
-				position899 := position
-				if !_rules[ruleStreamWindow]() {
-					goto l898
-				}
-				if !_rules[rulesp]() {
-					goto l898
+			l882:
+				{
+					position884, tokenIndex884 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l885
+					}
+					position++
+					goto l884
+				l885:
+					position, tokenIndex = position884, tokenIndex884
+					if buffer[position] != rune('N') {
+						goto l868
+					}
+					position++
 				}
+			l884:
 				{
-					position900, tokenIndex900 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l901
+					position886, tokenIndex886 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l887
 					}
 					position++
-					goto l900
-				l901:
-					position, tokenIndex = position900, tokenIndex900
-					if buffer[position] != rune('A') {
-						goto l898
+					goto l886
+				l887:
+					position, tokenIndex = position886, tokenIndex886
+					if buffer[position] != rune('T') {
+						goto l868
 					}
 					position++
 				}
-			l900:
+			l886:
 				{
-					position902, tokenIndex902 := position, tokenIndex
-					if buffer[position] != rune('s') {
-						goto l903
+					position888, tokenIndex888 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l889
 					}
 					position++
-					goto l902
-				l903:
-					position, tokenIndex = position902, tokenIndex902
-					if buffer[position] != rune('S') {
-						goto l898
+					goto l888
+				l889:
+					position, tokenIndex = position888, tokenIndex888
+					if buffer[position] != rune('O') {
+						goto l868
 					}
 					position++
 				}
-			l902:
+			l888:
 				if !_rules[rulesp]() {
-					goto l898
-				}
-				if !_rules[ruleIdentifier]() {
-					goto l898
-				}
-				if !_rules[ruleAction40]() {
-					goto l898
-				}
-				add(ruleAliasedStreamWindow, position899)
-			}
-			return true
-		l898:
-			position, tokenIndex = position898, tokenIndex898
-			return false
-		},
-		/* 54 StreamWindow <- <(StreamLike spOpt '[' spOpt (('r' / 'R') ('a' / 'A') ('n' / 'N') ('g' / 'G') ('e' / 'E')) sp Interval CapacitySpecOpt SheddingSpecOpt spOpt ']' Action41)> */
-		func() bool {
-			position904, tokenIndex904 := position, tokenIndex
-			{
-				position905 := position
-				if !_rules[ruleStreamLike]() {
-					goto l904
+					goto l868
 				}
-				if !_rules[rulespOpt]() {
-					goto l904
-				}
-				if buffer[position] != rune('[') {
-					goto l904
+				if !_rules[ruleStreamIdentifier]() {
+					goto l868
 				}
-				position++
-				if !_rules[rulespOpt]() {
-					goto l904
+				if !_rules[rulesp]() {
+					goto l868
 				}
 				{
-					position906, tokenIndex906 := position, tokenIndex
-					if buffer[position] != rune('r') {
+					position890, tokenIndex890 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l891
+					}
+					position++
+					goto l890
+				l891:
+					position, tokenIndex = position890, tokenIndex890
+					if buffer[position] != rune('S') {
+						goto l868
+					}
+					position++
+				}
+			l890:
+				{
+					position892, tokenIndex892 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l893
+					}
+					position++
+					goto l892
+				l893:
+					position, tokenIndex = position892, tokenIndex892
+					if buffer[position] != rune('E') {
+						goto l868
+					}
+					position++
+				}
+			l892:
+				{
+					position894, tokenIndex894 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l895
+					}
+					position++
+					goto l894
+				l895:
+					position, tokenIndex = position894, tokenIndex894
+					if buffer[position] != rune('L') {
+						goto l868
+					}
+					position++
+				}
+			l894:
+				{
+					position896, tokenIndex896 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l897
+					}
+					position++
+					goto l896
+				l897:
+					position, tokenIndex = position896, tokenIndex896
+					if buffer[position] != rune('E') {
+						goto l868
+					}
+					position++
+				}
+			l896:
+				{
+					position898, tokenIndex898 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l899
+					}
+					position++
+					goto l898
+				l899:
+					position, tokenIndex = position898, tokenIndex898
+					if buffer[position] != rune('C') {
+						goto l868
+					}
+					position++
+				}
+			l898:
+				{
+					position900, tokenIndex900 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l901
+					}
+					position++
+					goto l900
+				l901:
+					position, tokenIndex = position900, tokenIndex900
+					if buffer[position] != rune('T') {
+						goto l868
+					}
+					position++
+				}
+			l900:
+				if !_rules[ruleProjections]() {
+					goto l868
+				}
+				if !_rules[ruleAction33]() {
+					goto l868
+				}
+				add(ruleTriggerInsertStmt, position869)
+			}
+			return true
+		l868:
+			position, tokenIndex = position868, tokenIndex868
+			return false
+		},
+		/* 45 DropTriggerStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('t' / 'T') ('r' / 'R') ('i' / 'I') ('g' / 'G') ('g' / 'G') ('e' / 'E') ('r' / 'R')) sp StreamIdentifier Action34)> */
+		func() bool {
+			position902, tokenIndex902 := position, tokenIndex
+			{
+				position903 := position
+				{
+					position904, tokenIndex904 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l905
+					}
+					position++
+					goto l904
+				l905:
+					position, tokenIndex = position904, tokenIndex904
+					if buffer[position] != rune('D') {
+						goto l902
+					}
+					position++
+				}
+			l904:
+				{
+					position906, tokenIndex906 := position, tokenIndex
+					if buffer[position] != rune('r') {
 						goto l907
 					}
 					position++
@@ -8635,9513 +9200,20074 @@ func (p *bqlPegBackend) Init() {
 				l907:
 					position, tokenIndex = position906, tokenIndex906
 					if buffer[position] != rune('R') {
-						goto l904
+						goto l902
 					}
 					position++
 				}
 			l906:
 				{
 					position908, tokenIndex908 := position, tokenIndex
-					if buffer[position] != rune('a') {
+					if buffer[position] != rune('o') {
 						goto l909
 					}
 					position++
 					goto l908
 				l909:
 					position, tokenIndex = position908, tokenIndex908
-					if buffer[position] != rune('A') {
-						goto l904
+					if buffer[position] != rune('O') {
+						goto l902
 					}
 					position++
 				}
 			l908:
 				{
 					position910, tokenIndex910 := position, tokenIndex
-					if buffer[position] != rune('n') {
+					if buffer[position] != rune('p') {
 						goto l911
 					}
 					position++
 					goto l910
 				l911:
 					position, tokenIndex = position910, tokenIndex910
-					if buffer[position] != rune('N') {
-						goto l904
+					if buffer[position] != rune('P') {
+						goto l902
 					}
 					position++
 				}
 			l910:
+				if !_rules[rulesp]() {
+					goto l902
+				}
 				{
 					position912, tokenIndex912 := position, tokenIndex
-					if buffer[position] != rune('g') {
+					if buffer[position] != rune('t') {
 						goto l913
 					}
 					position++
 					goto l912
 				l913:
 					position, tokenIndex = position912, tokenIndex912
-					if buffer[position] != rune('G') {
-						goto l904
+					if buffer[position] != rune('T') {
+						goto l902
 					}
 					position++
 				}
 			l912:
 				{
 					position914, tokenIndex914 := position, tokenIndex
-					if buffer[position] != rune('e') {
+					if buffer[position] != rune('r') {
 						goto l915
 					}
 					position++
 					goto l914
 				l915:
 					position, tokenIndex = position914, tokenIndex914
-					if buffer[position] != rune('E') {
-						goto l904
+					if buffer[position] != rune('R') {
+						goto l902
 					}
 					position++
 				}
 			l914:
-				if !_rules[rulesp]() {
-					goto l904
-				}
-				if !_rules[ruleInterval]() {
-					goto l904
-				}
-				if !_rules[ruleCapacitySpecOpt]() {
-					goto l904
-				}
-				if !_rules[ruleSheddingSpecOpt]() {
-					goto l904
-				}
-				if !_rules[rulespOpt]() {
-					goto l904
-				}
-				if buffer[position] != rune(']') {
-					goto l904
-				}
-				position++
-				if !_rules[ruleAction41]() {
-					goto l904
+				{
+					position916, tokenIndex916 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l917
+					}
+					position++
+					goto l916
+				l917:
+					position, tokenIndex = position916, tokenIndex916
+					if buffer[position] != rune('I') {
+						goto l902
+					}
+					position++
 				}
-				add(ruleStreamWindow, position905)
-			}
-			return true
-		l904:
-			position, tokenIndex = position904, tokenIndex904
-			return false
-		},
-		/* 55 StreamLike <- <(UDSFFuncApp / Stream)> */
-		func() bool {
-			position916, tokenIndex916 := position, tokenIndex
-			{
-				position917 := position
+			l916:
 				{
 					position918, tokenIndex918 := position, tokenIndex
-					if !_rules[ruleUDSFFuncApp]() {
+					if buffer[position] != rune('g') {
 						goto l919
 					}
+					position++
 					goto l918
 				l919:
 					position, tokenIndex = position918, tokenIndex918
-					if !_rules[ruleStream]() {
-						goto l916
+					if buffer[position] != rune('G') {
+						goto l902
 					}
+					position++
 				}
 			l918:
-				add(ruleStreamLike, position917)
-			}
-			return true
-		l916:
-			position, tokenIndex = position916, tokenIndex916
-			return false
-		},
-		/* 56 UDSFFuncApp <- <(FuncAppWithoutOrderBy Action42)> */
-		func() bool {
-			position920, tokenIndex920 := position, tokenIndex
-			{
-				position921 := position
-				if !_rules[ruleFuncAppWithoutOrderBy]() {
+				{
+					position920, tokenIndex920 := position, tokenIndex
+					if buffer[position] != rune('g') {
+						goto l921
+					}
+					position++
 					goto l920
+				l921:
+					position, tokenIndex = position920, tokenIndex920
+					if buffer[position] != rune('G') {
+						goto l902
+					}
+					position++
 				}
-				if !_rules[ruleAction42]() {
-					goto l920
+			l920:
+				{
+					position922, tokenIndex922 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l923
+					}
+					position++
+					goto l922
+				l923:
+					position, tokenIndex = position922, tokenIndex922
+					if buffer[position] != rune('E') {
+						goto l902
+					}
+					position++
+				}
+			l922:
+				{
+					position924, tokenIndex924 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l925
+					}
+					position++
+					goto l924
+				l925:
+					position, tokenIndex = position924, tokenIndex924
+					if buffer[position] != rune('R') {
+						goto l902
+					}
+					position++
+				}
+			l924:
+				if !_rules[rulesp]() {
+					goto l902
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l902
 				}
-				add(ruleUDSFFuncApp, position921)
+				if !_rules[ruleAction34]() {
+					goto l902
+				}
+				add(ruleDropTriggerStmt, position903)
 			}
 			return true
-		l920:
-			position, tokenIndex = position920, tokenIndex920
+		l902:
+			position, tokenIndex = position902, tokenIndex902
 			return false
 		},
-		/* 57 CapacitySpecOpt <- <(<(spOpt ',' spOpt (('b' / 'B') ('u' / 'U') ('f' / 'F') ('f' / 'F') ('e' / 'E') ('r' / 'R')) sp (('s' / 'S') ('i' / 'I') ('z' / 'Z') ('e' / 'E')) sp NonNegativeNumericLiteral)?> Action43)> */
+		/* 46 CreateAlertStmt <- <(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('a' / 'A') ('l' / 'L') ('e' / 'E') ('r' / 'R') ('t' / 'T')) sp StreamIdentifier sp (('f' / 'F') ('r' / 'R') ('o' / 'O') ('m' / 'M')) sp StreamIdentifier Filter SourceSinkSpecs Action35)> */
 		func() bool {
-			position922, tokenIndex922 := position, tokenIndex
+			position926, tokenIndex926 := position, tokenIndex
 			{
-				position923 := position
+				position927 := position
 				{
-					position924 := position
-					{
-						position925, tokenIndex925 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l925
-						}
-						if buffer[position] != rune(',') {
-							goto l925
-						}
-						position++
-						if !_rules[rulespOpt]() {
-							goto l925
-						}
-						{
-							position927, tokenIndex927 := position, tokenIndex
-							if buffer[position] != rune('b') {
-								goto l928
-							}
-							position++
-							goto l927
-						l928:
-							position, tokenIndex = position927, tokenIndex927
-							if buffer[position] != rune('B') {
-								goto l925
-							}
-							position++
-						}
-					l927:
-						{
-							position929, tokenIndex929 := position, tokenIndex
-							if buffer[position] != rune('u') {
-								goto l930
-							}
-							position++
-							goto l929
-						l930:
-							position, tokenIndex = position929, tokenIndex929
-							if buffer[position] != rune('U') {
-								goto l925
-							}
-							position++
-						}
-					l929:
-						{
-							position931, tokenIndex931 := position, tokenIndex
-							if buffer[position] != rune('f') {
-								goto l932
-							}
-							position++
-							goto l931
-						l932:
-							position, tokenIndex = position931, tokenIndex931
-							if buffer[position] != rune('F') {
-								goto l925
-							}
-							position++
-						}
-					l931:
-						{
-							position933, tokenIndex933 := position, tokenIndex
-							if buffer[position] != rune('f') {
-								goto l934
-							}
-							position++
-							goto l933
-						l934:
-							position, tokenIndex = position933, tokenIndex933
-							if buffer[position] != rune('F') {
-								goto l925
-							}
-							position++
-						}
-					l933:
-						{
-							position935, tokenIndex935 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l936
-							}
-							position++
-							goto l935
-						l936:
-							position, tokenIndex = position935, tokenIndex935
-							if buffer[position] != rune('E') {
-								goto l925
-							}
-							position++
-						}
-					l935:
-						{
-							position937, tokenIndex937 := position, tokenIndex
-							if buffer[position] != rune('r') {
-								goto l938
-							}
-							position++
-							goto l937
-						l938:
-							position, tokenIndex = position937, tokenIndex937
-							if buffer[position] != rune('R') {
-								goto l925
-							}
-							position++
-						}
-					l937:
-						if !_rules[rulesp]() {
-							goto l925
-						}
-						{
-							position939, tokenIndex939 := position, tokenIndex
-							if buffer[position] != rune('s') {
-								goto l940
-							}
-							position++
-							goto l939
-						l940:
-							position, tokenIndex = position939, tokenIndex939
-							if buffer[position] != rune('S') {
-								goto l925
-							}
-							position++
-						}
-					l939:
-						{
-							position941, tokenIndex941 := position, tokenIndex
-							if buffer[position] != rune('i') {
-								goto l942
-							}
-							position++
-							goto l941
-						l942:
-							position, tokenIndex = position941, tokenIndex941
-							if buffer[position] != rune('I') {
-								goto l925
-							}
-							position++
-						}
-					l941:
-						{
-							position943, tokenIndex943 := position, tokenIndex
-							if buffer[position] != rune('z') {
-								goto l944
-							}
+					position928, tokenIndex928 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l929
+					}
+					position++
+					goto l928
+				l929:
+					position, tokenIndex = position928, tokenIndex928
+					if buffer[position] != rune('C') {
+						goto l926
+					}
+					position++
+				}
+			l928:
+				{
+					position930, tokenIndex930 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l931
+					}
+					position++
+					goto l930
+				l931:
+					position, tokenIndex = position930, tokenIndex930
+					if buffer[position] != rune('R') {
+						goto l926
+					}
+					position++
+				}
+			l930:
+				{
+					position932, tokenIndex932 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l933
+					}
+					position++
+					goto l932
+				l933:
+					position, tokenIndex = position932, tokenIndex932
+					if buffer[position] != rune('E') {
+						goto l926
+					}
+					position++
+				}
+			l932:
+				{
+					position934, tokenIndex934 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l935
+					}
+					position++
+					goto l934
+				l935:
+					position, tokenIndex = position934, tokenIndex934
+					if buffer[position] != rune('A') {
+						goto l926
+					}
+					position++
+				}
+			l934:
+				{
+					position936, tokenIndex936 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l937
+					}
+					position++
+					goto l936
+				l937:
+					position, tokenIndex = position936, tokenIndex936
+					if buffer[position] != rune('T') {
+						goto l926
+					}
+					position++
+				}
+			l936:
+				{
+					position938, tokenIndex938 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l939
+					}
+					position++
+					goto l938
+				l939:
+					position, tokenIndex = position938, tokenIndex938
+					if buffer[position] != rune('E') {
+						goto l926
+					}
+					position++
+				}
+			l938:
+				if !_rules[rulesp]() {
+					goto l926
+				}
+				{
+					position940, tokenIndex940 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l941
+					}
+					position++
+					goto l940
+				l941:
+					position, tokenIndex = position940, tokenIndex940
+					if buffer[position] != rune('A') {
+						goto l926
+					}
+					position++
+				}
+			l940:
+				{
+					position942, tokenIndex942 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l943
+					}
+					position++
+					goto l942
+				l943:
+					position, tokenIndex = position942, tokenIndex942
+					if buffer[position] != rune('L') {
+						goto l926
+					}
+					position++
+				}
+			l942:
+				{
+					position944, tokenIndex944 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l945
+					}
+					position++
+					goto l944
+				l945:
+					position, tokenIndex = position944, tokenIndex944
+					if buffer[position] != rune('E') {
+						goto l926
+					}
+					position++
+				}
+			l944:
+				{
+					position946, tokenIndex946 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l947
+					}
+					position++
+					goto l946
+				l947:
+					position, tokenIndex = position946, tokenIndex946
+					if buffer[position] != rune('R') {
+						goto l926
+					}
+					position++
+				}
+			l946:
+				{
+					position948, tokenIndex948 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l949
+					}
+					position++
+					goto l948
+				l949:
+					position, tokenIndex = position948, tokenIndex948
+					if buffer[position] != rune('T') {
+						goto l926
+					}
+					position++
+				}
+			l948:
+				if !_rules[rulesp]() {
+					goto l926
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l926
+				}
+				if !_rules[rulesp]() {
+					goto l926
+				}
+				{
+					position950, tokenIndex950 := position, tokenIndex
+					if buffer[position] != rune('f') {
+						goto l951
+					}
+					position++
+					goto l950
+				l951:
+					position, tokenIndex = position950, tokenIndex950
+					if buffer[position] != rune('F') {
+						goto l926
+					}
+					position++
+				}
+			l950:
+				{
+					position952, tokenIndex952 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l953
+					}
+					position++
+					goto l952
+				l953:
+					position, tokenIndex = position952, tokenIndex952
+					if buffer[position] != rune('R') {
+						goto l926
+					}
+					position++
+				}
+			l952:
+				{
+					position954, tokenIndex954 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l955
+					}
+					position++
+					goto l954
+				l955:
+					position, tokenIndex = position954, tokenIndex954
+					if buffer[position] != rune('O') {
+						goto l926
+					}
+					position++
+				}
+			l954:
+				{
+					position956, tokenIndex956 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l957
+					}
+					position++
+					goto l956
+				l957:
+					position, tokenIndex = position956, tokenIndex956
+					if buffer[position] != rune('M') {
+						goto l926
+					}
+					position++
+				}
+			l956:
+				if !_rules[rulesp]() {
+					goto l926
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l926
+				}
+				if !_rules[ruleFilter]() {
+					goto l926
+				}
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l926
+				}
+				if !_rules[ruleAction35]() {
+					goto l926
+				}
+				add(ruleCreateAlertStmt, position927)
+			}
+			return true
+		l926:
+			position, tokenIndex = position926, tokenIndex926
+			return false
+		},
+		/* 47 DropAlertStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('a' / 'A') ('l' / 'L') ('e' / 'E') ('r' / 'R') ('t' / 'T')) sp StreamIdentifier Action36)> */
+		func() bool {
+			position958, tokenIndex958 := position, tokenIndex
+			{
+				position959 := position
+				{
+					position960, tokenIndex960 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l961
+					}
+					position++
+					goto l960
+				l961:
+					position, tokenIndex = position960, tokenIndex960
+					if buffer[position] != rune('D') {
+						goto l958
+					}
+					position++
+				}
+			l960:
+				{
+					position962, tokenIndex962 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l963
+					}
+					position++
+					goto l962
+				l963:
+					position, tokenIndex = position962, tokenIndex962
+					if buffer[position] != rune('R') {
+						goto l958
+					}
+					position++
+				}
+			l962:
+				{
+					position964, tokenIndex964 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l965
+					}
+					position++
+					goto l964
+				l965:
+					position, tokenIndex = position964, tokenIndex964
+					if buffer[position] != rune('O') {
+						goto l958
+					}
+					position++
+				}
+			l964:
+				{
+					position966, tokenIndex966 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l967
+					}
+					position++
+					goto l966
+				l967:
+					position, tokenIndex = position966, tokenIndex966
+					if buffer[position] != rune('P') {
+						goto l958
+					}
+					position++
+				}
+			l966:
+				if !_rules[rulesp]() {
+					goto l958
+				}
+				{
+					position968, tokenIndex968 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l969
+					}
+					position++
+					goto l968
+				l969:
+					position, tokenIndex = position968, tokenIndex968
+					if buffer[position] != rune('A') {
+						goto l958
+					}
+					position++
+				}
+			l968:
+				{
+					position970, tokenIndex970 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l971
+					}
+					position++
+					goto l970
+				l971:
+					position, tokenIndex = position970, tokenIndex970
+					if buffer[position] != rune('L') {
+						goto l958
+					}
+					position++
+				}
+			l970:
+				{
+					position972, tokenIndex972 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l973
+					}
+					position++
+					goto l972
+				l973:
+					position, tokenIndex = position972, tokenIndex972
+					if buffer[position] != rune('E') {
+						goto l958
+					}
+					position++
+				}
+			l972:
+				{
+					position974, tokenIndex974 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l975
+					}
+					position++
+					goto l974
+				l975:
+					position, tokenIndex = position974, tokenIndex974
+					if buffer[position] != rune('R') {
+						goto l958
+					}
+					position++
+				}
+			l974:
+				{
+					position976, tokenIndex976 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l977
+					}
+					position++
+					goto l976
+				l977:
+					position, tokenIndex = position976, tokenIndex976
+					if buffer[position] != rune('T') {
+						goto l958
+					}
+					position++
+				}
+			l976:
+				if !_rules[rulesp]() {
+					goto l958
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l958
+				}
+				if !_rules[ruleAction36]() {
+					goto l958
+				}
+				add(ruleDropAlertStmt, position959)
+			}
+			return true
+		l958:
+			position, tokenIndex = position958, tokenIndex958
+			return false
+		},
+		/* 48 CreateTemplateStmt <- <(<(('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('t' / 'T') ('e' / 'E') ('m' / 'M') ('p' / 'P') ('l' / 'L') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier (spOpt '(' spOpt Identifier (spOpt ',' spOpt Identifier)* spOpt ')')? sp (('a' / 'A') ('s' / 'S')) sp TemplateBody)> Action37)> */
+		func() bool {
+			position978, tokenIndex978 := position, tokenIndex
+			{
+				position979 := position
+				{
+					position980 := position
+					{
+						position981, tokenIndex981 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l982
+						}
+						position++
+						goto l981
+					l982:
+						position, tokenIndex = position981, tokenIndex981
+						if buffer[position] != rune('C') {
+							goto l978
+						}
+						position++
+					}
+				l981:
+					{
+						position983, tokenIndex983 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l984
+						}
+						position++
+						goto l983
+					l984:
+						position, tokenIndex = position983, tokenIndex983
+						if buffer[position] != rune('R') {
+							goto l978
+						}
+						position++
+					}
+				l983:
+					{
+						position985, tokenIndex985 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l986
+						}
+						position++
+						goto l985
+					l986:
+						position, tokenIndex = position985, tokenIndex985
+						if buffer[position] != rune('E') {
+							goto l978
+						}
+						position++
+					}
+				l985:
+					{
+						position987, tokenIndex987 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l988
+						}
+						position++
+						goto l987
+					l988:
+						position, tokenIndex = position987, tokenIndex987
+						if buffer[position] != rune('A') {
+							goto l978
+						}
+						position++
+					}
+				l987:
+					{
+						position989, tokenIndex989 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l990
+						}
+						position++
+						goto l989
+					l990:
+						position, tokenIndex = position989, tokenIndex989
+						if buffer[position] != rune('T') {
+							goto l978
+						}
+						position++
+					}
+				l989:
+					{
+						position991, tokenIndex991 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l992
+						}
+						position++
+						goto l991
+					l992:
+						position, tokenIndex = position991, tokenIndex991
+						if buffer[position] != rune('E') {
+							goto l978
+						}
+						position++
+					}
+				l991:
+					if !_rules[rulesp]() {
+						goto l978
+					}
+					{
+						position993, tokenIndex993 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l994
+						}
+						position++
+						goto l993
+					l994:
+						position, tokenIndex = position993, tokenIndex993
+						if buffer[position] != rune('T') {
+							goto l978
+						}
+						position++
+					}
+				l993:
+					{
+						position995, tokenIndex995 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l996
+						}
+						position++
+						goto l995
+					l996:
+						position, tokenIndex = position995, tokenIndex995
+						if buffer[position] != rune('E') {
+							goto l978
+						}
+						position++
+					}
+				l995:
+					{
+						position997, tokenIndex997 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l998
+						}
+						position++
+						goto l997
+					l998:
+						position, tokenIndex = position997, tokenIndex997
+						if buffer[position] != rune('M') {
+							goto l978
+						}
+						position++
+					}
+				l997:
+					{
+						position999, tokenIndex999 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l1000
+						}
+						position++
+						goto l999
+					l1000:
+						position, tokenIndex = position999, tokenIndex999
+						if buffer[position] != rune('P') {
+							goto l978
+						}
+						position++
+					}
+				l999:
+					{
+						position1001, tokenIndex1001 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l1002
+						}
+						position++
+						goto l1001
+					l1002:
+						position, tokenIndex = position1001, tokenIndex1001
+						if buffer[position] != rune('L') {
+							goto l978
+						}
+						position++
+					}
+				l1001:
+					{
+						position1003, tokenIndex1003 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1004
+						}
+						position++
+						goto l1003
+					l1004:
+						position, tokenIndex = position1003, tokenIndex1003
+						if buffer[position] != rune('A') {
+							goto l978
+						}
+						position++
+					}
+				l1003:
+					{
+						position1005, tokenIndex1005 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1006
+						}
+						position++
+						goto l1005
+					l1006:
+						position, tokenIndex = position1005, tokenIndex1005
+						if buffer[position] != rune('T') {
+							goto l978
+						}
+						position++
+					}
+				l1005:
+					{
+						position1007, tokenIndex1007 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1008
+						}
+						position++
+						goto l1007
+					l1008:
+						position, tokenIndex = position1007, tokenIndex1007
+						if buffer[position] != rune('E') {
+							goto l978
+						}
+						position++
+					}
+				l1007:
+					if !_rules[rulesp]() {
+						goto l978
+					}
+					if !_rules[ruleStreamIdentifier]() {
+						goto l978
+					}
+					{
+						position1009, tokenIndex1009 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l1009
+						}
+						if buffer[position] != rune('(') {
+							goto l1009
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l1009
+						}
+						if !_rules[ruleIdentifier]() {
+							goto l1009
+						}
+					l1011:
+						{
+							position1012, tokenIndex1012 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l1012
+							}
+							if buffer[position] != rune(',') {
+								goto l1012
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l1012
+							}
+							if !_rules[ruleIdentifier]() {
+								goto l1012
+							}
+							goto l1011
+						l1012:
+							position, tokenIndex = position1012, tokenIndex1012
+						}
+						if !_rules[rulespOpt]() {
+							goto l1009
+						}
+						if buffer[position] != rune(')') {
+							goto l1009
+						}
+						position++
+						goto l1010
+					l1009:
+						position, tokenIndex = position1009, tokenIndex1009
+					}
+				l1010:
+					if !_rules[rulesp]() {
+						goto l978
+					}
+					{
+						position1013, tokenIndex1013 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1014
+						}
+						position++
+						goto l1013
+					l1014:
+						position, tokenIndex = position1013, tokenIndex1013
+						if buffer[position] != rune('A') {
+							goto l978
+						}
+						position++
+					}
+				l1013:
+					{
+						position1015, tokenIndex1015 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1016
+						}
+						position++
+						goto l1015
+					l1016:
+						position, tokenIndex = position1015, tokenIndex1015
+						if buffer[position] != rune('S') {
+							goto l978
+						}
+						position++
+					}
+				l1015:
+					if !_rules[rulesp]() {
+						goto l978
+					}
+					if !_rules[ruleTemplateBody]() {
+						goto l978
+					}
+					add(rulePegText, position980)
+				}
+				if !_rules[ruleAction37]() {
+					goto l978
+				}
+				add(ruleCreateTemplateStmt, position979)
+			}
+			return true
+		l978:
+			position, tokenIndex = position978, tokenIndex978
+			return false
+		},
+		/* 49 TemplateBody <- <('$' '$' <(!('$' '$') .)*> ('$' '$') Action38)> */
+		func() bool {
+			position1017, tokenIndex1017 := position, tokenIndex
+			{
+				position1018 := position
+				if buffer[position] != rune('$') {
+					goto l1017
+				}
+				position++
+				if buffer[position] != rune('$') {
+					goto l1017
+				}
+				position++
+				{
+					position1019 := position
+				l1020:
+					{
+						position1021, tokenIndex1021 := position, tokenIndex
+						{
+							position1022, tokenIndex1022 := position, tokenIndex
+							if buffer[position] != rune('$') {
+								goto l1022
+							}
+							position++
+							if buffer[position] != rune('$') {
+								goto l1022
+							}
+							position++
+							goto l1021
+						l1022:
+							position, tokenIndex = position1022, tokenIndex1022
+						}
+						if !matchDot() {
+							goto l1021
+						}
+						goto l1020
+					l1021:
+						position, tokenIndex = position1021, tokenIndex1021
+					}
+					add(rulePegText, position1019)
+				}
+				if buffer[position] != rune('$') {
+					goto l1017
+				}
+				position++
+				if buffer[position] != rune('$') {
+					goto l1017
+				}
+				position++
+				if !_rules[ruleAction38]() {
+					goto l1017
+				}
+				add(ruleTemplateBody, position1018)
+			}
+			return true
+		l1017:
+			position, tokenIndex = position1017, tokenIndex1017
+			return false
+		},
+		/* 50 InstantiateTemplateStmt <- <(('i' / 'I') ('n' / 'N') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('n' / 'N') ('t' / 'T') ('i' / 'I') ('a' / 'A') ('t' / 'T') ('e' / 'E') sp (('t' / 'T') ('e' / 'E') ('m' / 'M') ('p' / 'P') ('l' / 'L') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier TemplateInstanceOpt SourceSinkSpecs Action39)> */
+		func() bool {
+			position1023, tokenIndex1023 := position, tokenIndex
+			{
+				position1024 := position
+				{
+					position1025, tokenIndex1025 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1026
+					}
+					position++
+					goto l1025
+				l1026:
+					position, tokenIndex = position1025, tokenIndex1025
+					if buffer[position] != rune('I') {
+						goto l1023
+					}
+					position++
+				}
+			l1025:
+				{
+					position1027, tokenIndex1027 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1028
+					}
+					position++
+					goto l1027
+				l1028:
+					position, tokenIndex = position1027, tokenIndex1027
+					if buffer[position] != rune('N') {
+						goto l1023
+					}
+					position++
+				}
+			l1027:
+				{
+					position1029, tokenIndex1029 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1030
+					}
+					position++
+					goto l1029
+				l1030:
+					position, tokenIndex = position1029, tokenIndex1029
+					if buffer[position] != rune('S') {
+						goto l1023
+					}
+					position++
+				}
+			l1029:
+				{
+					position1031, tokenIndex1031 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1032
+					}
+					position++
+					goto l1031
+				l1032:
+					position, tokenIndex = position1031, tokenIndex1031
+					if buffer[position] != rune('T') {
+						goto l1023
+					}
+					position++
+				}
+			l1031:
+				{
+					position1033, tokenIndex1033 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1034
+					}
+					position++
+					goto l1033
+				l1034:
+					position, tokenIndex = position1033, tokenIndex1033
+					if buffer[position] != rune('A') {
+						goto l1023
+					}
+					position++
+				}
+			l1033:
+				{
+					position1035, tokenIndex1035 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1036
+					}
+					position++
+					goto l1035
+				l1036:
+					position, tokenIndex = position1035, tokenIndex1035
+					if buffer[position] != rune('N') {
+						goto l1023
+					}
+					position++
+				}
+			l1035:
+				{
+					position1037, tokenIndex1037 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1038
+					}
+					position++
+					goto l1037
+				l1038:
+					position, tokenIndex = position1037, tokenIndex1037
+					if buffer[position] != rune('T') {
+						goto l1023
+					}
+					position++
+				}
+			l1037:
+				{
+					position1039, tokenIndex1039 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1040
+					}
+					position++
+					goto l1039
+				l1040:
+					position, tokenIndex = position1039, tokenIndex1039
+					if buffer[position] != rune('I') {
+						goto l1023
+					}
+					position++
+				}
+			l1039:
+				{
+					position1041, tokenIndex1041 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1042
+					}
+					position++
+					goto l1041
+				l1042:
+					position, tokenIndex = position1041, tokenIndex1041
+					if buffer[position] != rune('A') {
+						goto l1023
+					}
+					position++
+				}
+			l1041:
+				{
+					position1043, tokenIndex1043 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1044
+					}
+					position++
+					goto l1043
+				l1044:
+					position, tokenIndex = position1043, tokenIndex1043
+					if buffer[position] != rune('T') {
+						goto l1023
+					}
+					position++
+				}
+			l1043:
+				{
+					position1045, tokenIndex1045 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1046
+					}
+					position++
+					goto l1045
+				l1046:
+					position, tokenIndex = position1045, tokenIndex1045
+					if buffer[position] != rune('E') {
+						goto l1023
+					}
+					position++
+				}
+			l1045:
+				if !_rules[rulesp]() {
+					goto l1023
+				}
+				{
+					position1047, tokenIndex1047 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1048
+					}
+					position++
+					goto l1047
+				l1048:
+					position, tokenIndex = position1047, tokenIndex1047
+					if buffer[position] != rune('T') {
+						goto l1023
+					}
+					position++
+				}
+			l1047:
+				{
+					position1049, tokenIndex1049 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1050
+					}
+					position++
+					goto l1049
+				l1050:
+					position, tokenIndex = position1049, tokenIndex1049
+					if buffer[position] != rune('E') {
+						goto l1023
+					}
+					position++
+				}
+			l1049:
+				{
+					position1051, tokenIndex1051 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1052
+					}
+					position++
+					goto l1051
+				l1052:
+					position, tokenIndex = position1051, tokenIndex1051
+					if buffer[position] != rune('M') {
+						goto l1023
+					}
+					position++
+				}
+			l1051:
+				{
+					position1053, tokenIndex1053 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1054
+					}
+					position++
+					goto l1053
+				l1054:
+					position, tokenIndex = position1053, tokenIndex1053
+					if buffer[position] != rune('P') {
+						goto l1023
+					}
+					position++
+				}
+			l1053:
+				{
+					position1055, tokenIndex1055 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1056
+					}
+					position++
+					goto l1055
+				l1056:
+					position, tokenIndex = position1055, tokenIndex1055
+					if buffer[position] != rune('L') {
+						goto l1023
+					}
+					position++
+				}
+			l1055:
+				{
+					position1057, tokenIndex1057 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1058
+					}
+					position++
+					goto l1057
+				l1058:
+					position, tokenIndex = position1057, tokenIndex1057
+					if buffer[position] != rune('A') {
+						goto l1023
+					}
+					position++
+				}
+			l1057:
+				{
+					position1059, tokenIndex1059 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1060
+					}
+					position++
+					goto l1059
+				l1060:
+					position, tokenIndex = position1059, tokenIndex1059
+					if buffer[position] != rune('T') {
+						goto l1023
+					}
+					position++
+				}
+			l1059:
+				{
+					position1061, tokenIndex1061 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1062
+					}
+					position++
+					goto l1061
+				l1062:
+					position, tokenIndex = position1061, tokenIndex1061
+					if buffer[position] != rune('E') {
+						goto l1023
+					}
+					position++
+				}
+			l1061:
+				if !_rules[rulesp]() {
+					goto l1023
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l1023
+				}
+				if !_rules[ruleTemplateInstanceOpt]() {
+					goto l1023
+				}
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l1023
+				}
+				if !_rules[ruleAction39]() {
+					goto l1023
+				}
+				add(ruleInstantiateTemplateStmt, position1024)
+			}
+			return true
+		l1023:
+			position, tokenIndex = position1023, tokenIndex1023
+			return false
+		},
+		/* 51 TemplateInstanceOpt <- <(<(sp (('a' / 'A') ('s' / 'S')) sp Identifier)?> Action40)> */
+		func() bool {
+			position1063, tokenIndex1063 := position, tokenIndex
+			{
+				position1064 := position
+				{
+					position1065 := position
+					{
+						position1066, tokenIndex1066 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1066
+						}
+						{
+							position1068, tokenIndex1068 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l1069
+							}
+							position++
+							goto l1068
+						l1069:
+							position, tokenIndex = position1068, tokenIndex1068
+							if buffer[position] != rune('A') {
+								goto l1066
+							}
+							position++
+						}
+					l1068:
+						{
+							position1070, tokenIndex1070 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l1071
+							}
+							position++
+							goto l1070
+						l1071:
+							position, tokenIndex = position1070, tokenIndex1070
+							if buffer[position] != rune('S') {
+								goto l1066
+							}
+							position++
+						}
+					l1070:
+						if !_rules[rulesp]() {
+							goto l1066
+						}
+						if !_rules[ruleIdentifier]() {
+							goto l1066
+						}
+						goto l1067
+					l1066:
+						position, tokenIndex = position1066, tokenIndex1066
+					}
+				l1067:
+					add(rulePegText, position1065)
+				}
+				if !_rules[ruleAction40]() {
+					goto l1063
+				}
+				add(ruleTemplateInstanceOpt, position1064)
+			}
+			return true
+		l1063:
+			position, tokenIndex = position1063, tokenIndex1063
+			return false
+		},
+		/* 52 DropTemplateInstanceStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('t' / 'T') ('e' / 'E') ('m' / 'M') ('p' / 'P') ('l' / 'L') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp (('i' / 'I') ('n' / 'N') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('n' / 'N') ('c' / 'C') ('e' / 'E')) sp StreamIdentifier Action41)> */
+		func() bool {
+			position1072, tokenIndex1072 := position, tokenIndex
+			{
+				position1073 := position
+				{
+					position1074, tokenIndex1074 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l1075
+					}
+					position++
+					goto l1074
+				l1075:
+					position, tokenIndex = position1074, tokenIndex1074
+					if buffer[position] != rune('D') {
+						goto l1072
+					}
+					position++
+				}
+			l1074:
+				{
+					position1076, tokenIndex1076 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1077
+					}
+					position++
+					goto l1076
+				l1077:
+					position, tokenIndex = position1076, tokenIndex1076
+					if buffer[position] != rune('R') {
+						goto l1072
+					}
+					position++
+				}
+			l1076:
+				{
+					position1078, tokenIndex1078 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1079
+					}
+					position++
+					goto l1078
+				l1079:
+					position, tokenIndex = position1078, tokenIndex1078
+					if buffer[position] != rune('O') {
+						goto l1072
+					}
+					position++
+				}
+			l1078:
+				{
+					position1080, tokenIndex1080 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1081
+					}
+					position++
+					goto l1080
+				l1081:
+					position, tokenIndex = position1080, tokenIndex1080
+					if buffer[position] != rune('P') {
+						goto l1072
+					}
+					position++
+				}
+			l1080:
+				if !_rules[rulesp]() {
+					goto l1072
+				}
+				{
+					position1082, tokenIndex1082 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1083
+					}
+					position++
+					goto l1082
+				l1083:
+					position, tokenIndex = position1082, tokenIndex1082
+					if buffer[position] != rune('T') {
+						goto l1072
+					}
+					position++
+				}
+			l1082:
+				{
+					position1084, tokenIndex1084 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1085
+					}
+					position++
+					goto l1084
+				l1085:
+					position, tokenIndex = position1084, tokenIndex1084
+					if buffer[position] != rune('E') {
+						goto l1072
+					}
+					position++
+				}
+			l1084:
+				{
+					position1086, tokenIndex1086 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1087
+					}
+					position++
+					goto l1086
+				l1087:
+					position, tokenIndex = position1086, tokenIndex1086
+					if buffer[position] != rune('M') {
+						goto l1072
+					}
+					position++
+				}
+			l1086:
+				{
+					position1088, tokenIndex1088 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1089
+					}
+					position++
+					goto l1088
+				l1089:
+					position, tokenIndex = position1088, tokenIndex1088
+					if buffer[position] != rune('P') {
+						goto l1072
+					}
+					position++
+				}
+			l1088:
+				{
+					position1090, tokenIndex1090 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1091
+					}
+					position++
+					goto l1090
+				l1091:
+					position, tokenIndex = position1090, tokenIndex1090
+					if buffer[position] != rune('L') {
+						goto l1072
+					}
+					position++
+				}
+			l1090:
+				{
+					position1092, tokenIndex1092 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1093
+					}
+					position++
+					goto l1092
+				l1093:
+					position, tokenIndex = position1092, tokenIndex1092
+					if buffer[position] != rune('A') {
+						goto l1072
+					}
+					position++
+				}
+			l1092:
+				{
+					position1094, tokenIndex1094 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1095
+					}
+					position++
+					goto l1094
+				l1095:
+					position, tokenIndex = position1094, tokenIndex1094
+					if buffer[position] != rune('T') {
+						goto l1072
+					}
+					position++
+				}
+			l1094:
+				{
+					position1096, tokenIndex1096 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1097
+					}
+					position++
+					goto l1096
+				l1097:
+					position, tokenIndex = position1096, tokenIndex1096
+					if buffer[position] != rune('E') {
+						goto l1072
+					}
+					position++
+				}
+			l1096:
+				if !_rules[rulesp]() {
+					goto l1072
+				}
+				{
+					position1098, tokenIndex1098 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1099
+					}
+					position++
+					goto l1098
+				l1099:
+					position, tokenIndex = position1098, tokenIndex1098
+					if buffer[position] != rune('I') {
+						goto l1072
+					}
+					position++
+				}
+			l1098:
+				{
+					position1100, tokenIndex1100 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1101
+					}
+					position++
+					goto l1100
+				l1101:
+					position, tokenIndex = position1100, tokenIndex1100
+					if buffer[position] != rune('N') {
+						goto l1072
+					}
+					position++
+				}
+			l1100:
+				{
+					position1102, tokenIndex1102 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1103
+					}
+					position++
+					goto l1102
+				l1103:
+					position, tokenIndex = position1102, tokenIndex1102
+					if buffer[position] != rune('S') {
+						goto l1072
+					}
+					position++
+				}
+			l1102:
+				{
+					position1104, tokenIndex1104 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1105
+					}
+					position++
+					goto l1104
+				l1105:
+					position, tokenIndex = position1104, tokenIndex1104
+					if buffer[position] != rune('T') {
+						goto l1072
+					}
+					position++
+				}
+			l1104:
+				{
+					position1106, tokenIndex1106 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1107
+					}
+					position++
+					goto l1106
+				l1107:
+					position, tokenIndex = position1106, tokenIndex1106
+					if buffer[position] != rune('A') {
+						goto l1072
+					}
+					position++
+				}
+			l1106:
+				{
+					position1108, tokenIndex1108 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1109
+					}
+					position++
+					goto l1108
+				l1109:
+					position, tokenIndex = position1108, tokenIndex1108
+					if buffer[position] != rune('N') {
+						goto l1072
+					}
+					position++
+				}
+			l1108:
+				{
+					position1110, tokenIndex1110 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l1111
+					}
+					position++
+					goto l1110
+				l1111:
+					position, tokenIndex = position1110, tokenIndex1110
+					if buffer[position] != rune('C') {
+						goto l1072
+					}
+					position++
+				}
+			l1110:
+				{
+					position1112, tokenIndex1112 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1113
+					}
+					position++
+					goto l1112
+				l1113:
+					position, tokenIndex = position1112, tokenIndex1112
+					if buffer[position] != rune('E') {
+						goto l1072
+					}
+					position++
+				}
+			l1112:
+				if !_rules[rulesp]() {
+					goto l1072
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l1072
+				}
+				if !_rules[ruleAction41]() {
+					goto l1072
+				}
+				add(ruleDropTemplateInstanceStmt, position1073)
+			}
+			return true
+		l1072:
+			position, tokenIndex = position1072, tokenIndex1072
+			return false
+		},
+		/* 53 DropTemplateStmt <- <(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('t' / 'T') ('e' / 'E') ('m' / 'M') ('p' / 'P') ('l' / 'L') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier Action42)> */
+		func() bool {
+			position1114, tokenIndex1114 := position, tokenIndex
+			{
+				position1115 := position
+				{
+					position1116, tokenIndex1116 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l1117
+					}
+					position++
+					goto l1116
+				l1117:
+					position, tokenIndex = position1116, tokenIndex1116
+					if buffer[position] != rune('D') {
+						goto l1114
+					}
+					position++
+				}
+			l1116:
+				{
+					position1118, tokenIndex1118 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1119
+					}
+					position++
+					goto l1118
+				l1119:
+					position, tokenIndex = position1118, tokenIndex1118
+					if buffer[position] != rune('R') {
+						goto l1114
+					}
+					position++
+				}
+			l1118:
+				{
+					position1120, tokenIndex1120 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1121
+					}
+					position++
+					goto l1120
+				l1121:
+					position, tokenIndex = position1120, tokenIndex1120
+					if buffer[position] != rune('O') {
+						goto l1114
+					}
+					position++
+				}
+			l1120:
+				{
+					position1122, tokenIndex1122 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1123
+					}
+					position++
+					goto l1122
+				l1123:
+					position, tokenIndex = position1122, tokenIndex1122
+					if buffer[position] != rune('P') {
+						goto l1114
+					}
+					position++
+				}
+			l1122:
+				if !_rules[rulesp]() {
+					goto l1114
+				}
+				{
+					position1124, tokenIndex1124 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1125
+					}
+					position++
+					goto l1124
+				l1125:
+					position, tokenIndex = position1124, tokenIndex1124
+					if buffer[position] != rune('T') {
+						goto l1114
+					}
+					position++
+				}
+			l1124:
+				{
+					position1126, tokenIndex1126 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1127
+					}
+					position++
+					goto l1126
+				l1127:
+					position, tokenIndex = position1126, tokenIndex1126
+					if buffer[position] != rune('E') {
+						goto l1114
+					}
+					position++
+				}
+			l1126:
+				{
+					position1128, tokenIndex1128 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1129
+					}
+					position++
+					goto l1128
+				l1129:
+					position, tokenIndex = position1128, tokenIndex1128
+					if buffer[position] != rune('M') {
+						goto l1114
+					}
+					position++
+				}
+			l1128:
+				{
+					position1130, tokenIndex1130 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1131
+					}
+					position++
+					goto l1130
+				l1131:
+					position, tokenIndex = position1130, tokenIndex1130
+					if buffer[position] != rune('P') {
+						goto l1114
+					}
+					position++
+				}
+			l1130:
+				{
+					position1132, tokenIndex1132 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1133
+					}
+					position++
+					goto l1132
+				l1133:
+					position, tokenIndex = position1132, tokenIndex1132
+					if buffer[position] != rune('L') {
+						goto l1114
+					}
+					position++
+				}
+			l1132:
+				{
+					position1134, tokenIndex1134 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1135
+					}
+					position++
+					goto l1134
+				l1135:
+					position, tokenIndex = position1134, tokenIndex1134
+					if buffer[position] != rune('A') {
+						goto l1114
+					}
+					position++
+				}
+			l1134:
+				{
+					position1136, tokenIndex1136 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1137
+					}
+					position++
+					goto l1136
+				l1137:
+					position, tokenIndex = position1136, tokenIndex1136
+					if buffer[position] != rune('T') {
+						goto l1114
+					}
+					position++
+				}
+			l1136:
+				{
+					position1138, tokenIndex1138 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1139
+					}
+					position++
+					goto l1138
+				l1139:
+					position, tokenIndex = position1138, tokenIndex1138
+					if buffer[position] != rune('E') {
+						goto l1114
+					}
+					position++
+				}
+			l1138:
+				if !_rules[rulesp]() {
+					goto l1114
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l1114
+				}
+				if !_rules[ruleAction42]() {
+					goto l1114
+				}
+				add(ruleDropTemplateStmt, position1115)
+			}
+			return true
+		l1114:
+			position, tokenIndex = position1114, tokenIndex1114
+			return false
+		},
+		/* 54 LoadStateStmt <- <(('l' / 'L') ('o' / 'O') ('a' / 'A') ('d' / 'D') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier sp (('t' / 'T') ('y' / 'Y') ('p' / 'P') ('e' / 'E')) sp SourceSinkType StateTagOpt SetOptSpecs Action43)> */
+		func() bool {
+			position1140, tokenIndex1140 := position, tokenIndex
+			{
+				position1141 := position
+				{
+					position1142, tokenIndex1142 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1143
+					}
+					position++
+					goto l1142
+				l1143:
+					position, tokenIndex = position1142, tokenIndex1142
+					if buffer[position] != rune('L') {
+						goto l1140
+					}
+					position++
+				}
+			l1142:
+				{
+					position1144, tokenIndex1144 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1145
+					}
+					position++
+					goto l1144
+				l1145:
+					position, tokenIndex = position1144, tokenIndex1144
+					if buffer[position] != rune('O') {
+						goto l1140
+					}
+					position++
+				}
+			l1144:
+				{
+					position1146, tokenIndex1146 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1147
+					}
+					position++
+					goto l1146
+				l1147:
+					position, tokenIndex = position1146, tokenIndex1146
+					if buffer[position] != rune('A') {
+						goto l1140
+					}
+					position++
+				}
+			l1146:
+				{
+					position1148, tokenIndex1148 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l1149
+					}
+					position++
+					goto l1148
+				l1149:
+					position, tokenIndex = position1148, tokenIndex1148
+					if buffer[position] != rune('D') {
+						goto l1140
+					}
+					position++
+				}
+			l1148:
+				if !_rules[rulesp]() {
+					goto l1140
+				}
+				{
+					position1150, tokenIndex1150 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1151
+					}
+					position++
+					goto l1150
+				l1151:
+					position, tokenIndex = position1150, tokenIndex1150
+					if buffer[position] != rune('S') {
+						goto l1140
+					}
+					position++
+				}
+			l1150:
+				{
+					position1152, tokenIndex1152 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1153
+					}
+					position++
+					goto l1152
+				l1153:
+					position, tokenIndex = position1152, tokenIndex1152
+					if buffer[position] != rune('T') {
+						goto l1140
+					}
+					position++
+				}
+			l1152:
+				{
+					position1154, tokenIndex1154 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1155
+					}
+					position++
+					goto l1154
+				l1155:
+					position, tokenIndex = position1154, tokenIndex1154
+					if buffer[position] != rune('A') {
+						goto l1140
+					}
+					position++
+				}
+			l1154:
+				{
+					position1156, tokenIndex1156 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1157
+					}
+					position++
+					goto l1156
+				l1157:
+					position, tokenIndex = position1156, tokenIndex1156
+					if buffer[position] != rune('T') {
+						goto l1140
+					}
+					position++
+				}
+			l1156:
+				{
+					position1158, tokenIndex1158 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1159
+					}
+					position++
+					goto l1158
+				l1159:
+					position, tokenIndex = position1158, tokenIndex1158
+					if buffer[position] != rune('E') {
+						goto l1140
+					}
+					position++
+				}
+			l1158:
+				if !_rules[rulesp]() {
+					goto l1140
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l1140
+				}
+				if !_rules[rulesp]() {
+					goto l1140
+				}
+				{
+					position1160, tokenIndex1160 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1161
+					}
+					position++
+					goto l1160
+				l1161:
+					position, tokenIndex = position1160, tokenIndex1160
+					if buffer[position] != rune('T') {
+						goto l1140
+					}
+					position++
+				}
+			l1160:
+				{
+					position1162, tokenIndex1162 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l1163
+					}
+					position++
+					goto l1162
+				l1163:
+					position, tokenIndex = position1162, tokenIndex1162
+					if buffer[position] != rune('Y') {
+						goto l1140
+					}
+					position++
+				}
+			l1162:
+				{
+					position1164, tokenIndex1164 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1165
+					}
+					position++
+					goto l1164
+				l1165:
+					position, tokenIndex = position1164, tokenIndex1164
+					if buffer[position] != rune('P') {
+						goto l1140
+					}
+					position++
+				}
+			l1164:
+				{
+					position1166, tokenIndex1166 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1167
+					}
+					position++
+					goto l1166
+				l1167:
+					position, tokenIndex = position1166, tokenIndex1166
+					if buffer[position] != rune('E') {
+						goto l1140
+					}
+					position++
+				}
+			l1166:
+				if !_rules[rulesp]() {
+					goto l1140
+				}
+				if !_rules[ruleSourceSinkType]() {
+					goto l1140
+				}
+				if !_rules[ruleStateTagOpt]() {
+					goto l1140
+				}
+				if !_rules[ruleSetOptSpecs]() {
+					goto l1140
+				}
+				if !_rules[ruleAction43]() {
+					goto l1140
+				}
+				add(ruleLoadStateStmt, position1141)
+			}
+			return true
+		l1140:
+			position, tokenIndex = position1140, tokenIndex1140
+			return false
+		},
+		/* 55 LoadStateOrCreateStmt <- <(LoadStateStmt sp (('o' / 'O') ('r' / 'R')) sp (('c' / 'C') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp (('i' / 'I') ('f' / 'F')) sp (('n' / 'N') ('o' / 'O') ('t' / 'T')) sp ((('s' / 'S') ('a' / 'A') ('v' / 'V') ('e' / 'E') ('d' / 'D')) / (('e' / 'E') ('x' / 'X') ('i' / 'I') ('s' / 'S') ('t' / 'T') ('s' / 'S'))) SourceSinkSpecs Action44)> */
+		func() bool {
+			position1168, tokenIndex1168 := position, tokenIndex
+			{
+				position1169 := position
+				if !_rules[ruleLoadStateStmt]() {
+					goto l1168
+				}
+				if !_rules[rulesp]() {
+					goto l1168
+				}
+				{
+					position1170, tokenIndex1170 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1171
+					}
+					position++
+					goto l1170
+				l1171:
+					position, tokenIndex = position1170, tokenIndex1170
+					if buffer[position] != rune('O') {
+						goto l1168
+					}
+					position++
+				}
+			l1170:
+				{
+					position1172, tokenIndex1172 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1173
+					}
+					position++
+					goto l1172
+				l1173:
+					position, tokenIndex = position1172, tokenIndex1172
+					if buffer[position] != rune('R') {
+						goto l1168
+					}
+					position++
+				}
+			l1172:
+				if !_rules[rulesp]() {
+					goto l1168
+				}
+				{
+					position1174, tokenIndex1174 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l1175
+					}
+					position++
+					goto l1174
+				l1175:
+					position, tokenIndex = position1174, tokenIndex1174
+					if buffer[position] != rune('C') {
+						goto l1168
+					}
+					position++
+				}
+			l1174:
+				{
+					position1176, tokenIndex1176 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1177
+					}
+					position++
+					goto l1176
+				l1177:
+					position, tokenIndex = position1176, tokenIndex1176
+					if buffer[position] != rune('R') {
+						goto l1168
+					}
+					position++
+				}
+			l1176:
+				{
+					position1178, tokenIndex1178 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1179
+					}
+					position++
+					goto l1178
+				l1179:
+					position, tokenIndex = position1178, tokenIndex1178
+					if buffer[position] != rune('E') {
+						goto l1168
+					}
+					position++
+				}
+			l1178:
+				{
+					position1180, tokenIndex1180 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1181
+					}
+					position++
+					goto l1180
+				l1181:
+					position, tokenIndex = position1180, tokenIndex1180
+					if buffer[position] != rune('A') {
+						goto l1168
+					}
+					position++
+				}
+			l1180:
+				{
+					position1182, tokenIndex1182 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1183
+					}
+					position++
+					goto l1182
+				l1183:
+					position, tokenIndex = position1182, tokenIndex1182
+					if buffer[position] != rune('T') {
+						goto l1168
+					}
+					position++
+				}
+			l1182:
+				{
+					position1184, tokenIndex1184 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1185
+					}
+					position++
+					goto l1184
+				l1185:
+					position, tokenIndex = position1184, tokenIndex1184
+					if buffer[position] != rune('E') {
+						goto l1168
+					}
+					position++
+				}
+			l1184:
+				if !_rules[rulesp]() {
+					goto l1168
+				}
+				{
+					position1186, tokenIndex1186 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1187
+					}
+					position++
+					goto l1186
+				l1187:
+					position, tokenIndex = position1186, tokenIndex1186
+					if buffer[position] != rune('I') {
+						goto l1168
+					}
+					position++
+				}
+			l1186:
+				{
+					position1188, tokenIndex1188 := position, tokenIndex
+					if buffer[position] != rune('f') {
+						goto l1189
+					}
+					position++
+					goto l1188
+				l1189:
+					position, tokenIndex = position1188, tokenIndex1188
+					if buffer[position] != rune('F') {
+						goto l1168
+					}
+					position++
+				}
+			l1188:
+				if !_rules[rulesp]() {
+					goto l1168
+				}
+				{
+					position1190, tokenIndex1190 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1191
+					}
+					position++
+					goto l1190
+				l1191:
+					position, tokenIndex = position1190, tokenIndex1190
+					if buffer[position] != rune('N') {
+						goto l1168
+					}
+					position++
+				}
+			l1190:
+				{
+					position1192, tokenIndex1192 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1193
+					}
+					position++
+					goto l1192
+				l1193:
+					position, tokenIndex = position1192, tokenIndex1192
+					if buffer[position] != rune('O') {
+						goto l1168
+					}
+					position++
+				}
+			l1192:
+				{
+					position1194, tokenIndex1194 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1195
+					}
+					position++
+					goto l1194
+				l1195:
+					position, tokenIndex = position1194, tokenIndex1194
+					if buffer[position] != rune('T') {
+						goto l1168
+					}
+					position++
+				}
+			l1194:
+				if !_rules[rulesp]() {
+					goto l1168
+				}
+				{
+					position1196, tokenIndex1196 := position, tokenIndex
+					{
+						position1198, tokenIndex1198 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1199
+						}
+						position++
+						goto l1198
+					l1199:
+						position, tokenIndex = position1198, tokenIndex1198
+						if buffer[position] != rune('S') {
+							goto l1197
+						}
+						position++
+					}
+				l1198:
+					{
+						position1200, tokenIndex1200 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1201
+						}
+						position++
+						goto l1200
+					l1201:
+						position, tokenIndex = position1200, tokenIndex1200
+						if buffer[position] != rune('A') {
+							goto l1197
+						}
+						position++
+					}
+				l1200:
+					{
+						position1202, tokenIndex1202 := position, tokenIndex
+						if buffer[position] != rune('v') {
+							goto l1203
+						}
+						position++
+						goto l1202
+					l1203:
+						position, tokenIndex = position1202, tokenIndex1202
+						if buffer[position] != rune('V') {
+							goto l1197
+						}
+						position++
+					}
+				l1202:
+					{
+						position1204, tokenIndex1204 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1205
+						}
+						position++
+						goto l1204
+					l1205:
+						position, tokenIndex = position1204, tokenIndex1204
+						if buffer[position] != rune('E') {
+							goto l1197
+						}
+						position++
+					}
+				l1204:
+					{
+						position1206, tokenIndex1206 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l1207
+						}
+						position++
+						goto l1206
+					l1207:
+						position, tokenIndex = position1206, tokenIndex1206
+						if buffer[position] != rune('D') {
+							goto l1197
+						}
+						position++
+					}
+				l1206:
+					goto l1196
+				l1197:
+					position, tokenIndex = position1196, tokenIndex1196
+					{
+						position1208, tokenIndex1208 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1209
+						}
+						position++
+						goto l1208
+					l1209:
+						position, tokenIndex = position1208, tokenIndex1208
+						if buffer[position] != rune('E') {
+							goto l1168
+						}
+						position++
+					}
+				l1208:
+					{
+						position1210, tokenIndex1210 := position, tokenIndex
+						if buffer[position] != rune('x') {
+							goto l1211
+						}
+						position++
+						goto l1210
+					l1211:
+						position, tokenIndex = position1210, tokenIndex1210
+						if buffer[position] != rune('X') {
+							goto l1168
+						}
+						position++
+					}
+				l1210:
+					{
+						position1212, tokenIndex1212 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1213
+						}
+						position++
+						goto l1212
+					l1213:
+						position, tokenIndex = position1212, tokenIndex1212
+						if buffer[position] != rune('I') {
+							goto l1168
+						}
+						position++
+					}
+				l1212:
+					{
+						position1214, tokenIndex1214 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1215
+						}
+						position++
+						goto l1214
+					l1215:
+						position, tokenIndex = position1214, tokenIndex1214
+						if buffer[position] != rune('S') {
+							goto l1168
+						}
+						position++
+					}
+				l1214:
+					{
+						position1216, tokenIndex1216 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1217
+						}
+						position++
+						goto l1216
+					l1217:
+						position, tokenIndex = position1216, tokenIndex1216
+						if buffer[position] != rune('T') {
+							goto l1168
+						}
+						position++
+					}
+				l1216:
+					{
+						position1218, tokenIndex1218 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1219
+						}
+						position++
+						goto l1218
+					l1219:
+						position, tokenIndex = position1218, tokenIndex1218
+						if buffer[position] != rune('S') {
+							goto l1168
+						}
+						position++
+					}
+				l1218:
+				}
+			l1196:
+				if !_rules[ruleSourceSinkSpecs]() {
+					goto l1168
+				}
+				if !_rules[ruleAction44]() {
+					goto l1168
+				}
+				add(ruleLoadStateOrCreateStmt, position1169)
+			}
+			return true
+		l1168:
+			position, tokenIndex = position1168, tokenIndex1168
+			return false
+		},
+		/* 56 SaveStateStmt <- <(('s' / 'S') ('a' / 'A') ('v' / 'V') ('e' / 'E') sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier StateTagOpt Action45)> */
+		func() bool {
+			position1220, tokenIndex1220 := position, tokenIndex
+			{
+				position1221 := position
+				{
+					position1222, tokenIndex1222 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1223
+					}
+					position++
+					goto l1222
+				l1223:
+					position, tokenIndex = position1222, tokenIndex1222
+					if buffer[position] != rune('S') {
+						goto l1220
+					}
+					position++
+				}
+			l1222:
+				{
+					position1224, tokenIndex1224 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1225
+					}
+					position++
+					goto l1224
+				l1225:
+					position, tokenIndex = position1224, tokenIndex1224
+					if buffer[position] != rune('A') {
+						goto l1220
+					}
+					position++
+				}
+			l1224:
+				{
+					position1226, tokenIndex1226 := position, tokenIndex
+					if buffer[position] != rune('v') {
+						goto l1227
+					}
+					position++
+					goto l1226
+				l1227:
+					position, tokenIndex = position1226, tokenIndex1226
+					if buffer[position] != rune('V') {
+						goto l1220
+					}
+					position++
+				}
+			l1226:
+				{
+					position1228, tokenIndex1228 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1229
+					}
+					position++
+					goto l1228
+				l1229:
+					position, tokenIndex = position1228, tokenIndex1228
+					if buffer[position] != rune('E') {
+						goto l1220
+					}
+					position++
+				}
+			l1228:
+				if !_rules[rulesp]() {
+					goto l1220
+				}
+				{
+					position1230, tokenIndex1230 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1231
+					}
+					position++
+					goto l1230
+				l1231:
+					position, tokenIndex = position1230, tokenIndex1230
+					if buffer[position] != rune('S') {
+						goto l1220
+					}
+					position++
+				}
+			l1230:
+				{
+					position1232, tokenIndex1232 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1233
+					}
+					position++
+					goto l1232
+				l1233:
+					position, tokenIndex = position1232, tokenIndex1232
+					if buffer[position] != rune('T') {
+						goto l1220
+					}
+					position++
+				}
+			l1232:
+				{
+					position1234, tokenIndex1234 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1235
+					}
+					position++
+					goto l1234
+				l1235:
+					position, tokenIndex = position1234, tokenIndex1234
+					if buffer[position] != rune('A') {
+						goto l1220
+					}
+					position++
+				}
+			l1234:
+				{
+					position1236, tokenIndex1236 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1237
+					}
+					position++
+					goto l1236
+				l1237:
+					position, tokenIndex = position1236, tokenIndex1236
+					if buffer[position] != rune('T') {
+						goto l1220
+					}
+					position++
+				}
+			l1236:
+				{
+					position1238, tokenIndex1238 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1239
+					}
+					position++
+					goto l1238
+				l1239:
+					position, tokenIndex = position1238, tokenIndex1238
+					if buffer[position] != rune('E') {
+						goto l1220
+					}
+					position++
+				}
+			l1238:
+				if !_rules[rulesp]() {
+					goto l1220
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l1220
+				}
+				if !_rules[ruleStateTagOpt]() {
+					goto l1220
+				}
+				if !_rules[ruleAction45]() {
+					goto l1220
+				}
+				add(ruleSaveStateStmt, position1221)
+			}
+			return true
+		l1220:
+			position, tokenIndex = position1220, tokenIndex1220
+			return false
+		},
+		/* 57 SetConfigStmt <- <(('s' / 'S') ('e' / 'E') ('t' / 'T') sp (('c' / 'C') ('o' / 'O') ('n' / 'N') ('f' / 'F') ('i' / 'I') ('g' / 'G')) ConfigSpecs Action46)> */
+		func() bool {
+			position1240, tokenIndex1240 := position, tokenIndex
+			{
+				position1241 := position
+				{
+					position1242, tokenIndex1242 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1243
+					}
+					position++
+					goto l1242
+				l1243:
+					position, tokenIndex = position1242, tokenIndex1242
+					if buffer[position] != rune('S') {
+						goto l1240
+					}
+					position++
+				}
+			l1242:
+				{
+					position1244, tokenIndex1244 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1245
+					}
+					position++
+					goto l1244
+				l1245:
+					position, tokenIndex = position1244, tokenIndex1244
+					if buffer[position] != rune('E') {
+						goto l1240
+					}
+					position++
+				}
+			l1244:
+				{
+					position1246, tokenIndex1246 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1247
+					}
+					position++
+					goto l1246
+				l1247:
+					position, tokenIndex = position1246, tokenIndex1246
+					if buffer[position] != rune('T') {
+						goto l1240
+					}
+					position++
+				}
+			l1246:
+				if !_rules[rulesp]() {
+					goto l1240
+				}
+				{
+					position1248, tokenIndex1248 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l1249
+					}
+					position++
+					goto l1248
+				l1249:
+					position, tokenIndex = position1248, tokenIndex1248
+					if buffer[position] != rune('C') {
+						goto l1240
+					}
+					position++
+				}
+			l1248:
+				{
+					position1250, tokenIndex1250 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1251
+					}
+					position++
+					goto l1250
+				l1251:
+					position, tokenIndex = position1250, tokenIndex1250
+					if buffer[position] != rune('O') {
+						goto l1240
+					}
+					position++
+				}
+			l1250:
+				{
+					position1252, tokenIndex1252 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1253
+					}
+					position++
+					goto l1252
+				l1253:
+					position, tokenIndex = position1252, tokenIndex1252
+					if buffer[position] != rune('N') {
+						goto l1240
+					}
+					position++
+				}
+			l1252:
+				{
+					position1254, tokenIndex1254 := position, tokenIndex
+					if buffer[position] != rune('f') {
+						goto l1255
+					}
+					position++
+					goto l1254
+				l1255:
+					position, tokenIndex = position1254, tokenIndex1254
+					if buffer[position] != rune('F') {
+						goto l1240
+					}
+					position++
+				}
+			l1254:
+				{
+					position1256, tokenIndex1256 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1257
+					}
+					position++
+					goto l1256
+				l1257:
+					position, tokenIndex = position1256, tokenIndex1256
+					if buffer[position] != rune('I') {
+						goto l1240
+					}
+					position++
+				}
+			l1256:
+				{
+					position1258, tokenIndex1258 := position, tokenIndex
+					if buffer[position] != rune('g') {
+						goto l1259
+					}
+					position++
+					goto l1258
+				l1259:
+					position, tokenIndex = position1258, tokenIndex1258
+					if buffer[position] != rune('G') {
+						goto l1240
+					}
+					position++
+				}
+			l1258:
+				if !_rules[ruleConfigSpecs]() {
+					goto l1240
+				}
+				if !_rules[ruleAction46]() {
+					goto l1240
+				}
+				add(ruleSetConfigStmt, position1241)
+			}
+			return true
+		l1240:
+			position, tokenIndex = position1240, tokenIndex1240
+			return false
+		},
+		/* 58 EvalStmt <- <(('e' / 'E') ('v' / 'V') ('a' / 'A') ('l' / 'L') sp Expression <(sp (('o' / 'O') ('n' / 'N')) sp MapExpr)?> Action47)> */
+		func() bool {
+			position1260, tokenIndex1260 := position, tokenIndex
+			{
+				position1261 := position
+				{
+					position1262, tokenIndex1262 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1263
+					}
+					position++
+					goto l1262
+				l1263:
+					position, tokenIndex = position1262, tokenIndex1262
+					if buffer[position] != rune('E') {
+						goto l1260
+					}
+					position++
+				}
+			l1262:
+				{
+					position1264, tokenIndex1264 := position, tokenIndex
+					if buffer[position] != rune('v') {
+						goto l1265
+					}
+					position++
+					goto l1264
+				l1265:
+					position, tokenIndex = position1264, tokenIndex1264
+					if buffer[position] != rune('V') {
+						goto l1260
+					}
+					position++
+				}
+			l1264:
+				{
+					position1266, tokenIndex1266 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1267
+					}
+					position++
+					goto l1266
+				l1267:
+					position, tokenIndex = position1266, tokenIndex1266
+					if buffer[position] != rune('A') {
+						goto l1260
+					}
+					position++
+				}
+			l1266:
+				{
+					position1268, tokenIndex1268 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1269
+					}
+					position++
+					goto l1268
+				l1269:
+					position, tokenIndex = position1268, tokenIndex1268
+					if buffer[position] != rune('L') {
+						goto l1260
+					}
+					position++
+				}
+			l1268:
+				if !_rules[rulesp]() {
+					goto l1260
+				}
+				if !_rules[ruleExpression]() {
+					goto l1260
+				}
+				{
+					position1270 := position
+					{
+						position1271, tokenIndex1271 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1271
+						}
+						{
+							position1273, tokenIndex1273 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l1274
+							}
+							position++
+							goto l1273
+						l1274:
+							position, tokenIndex = position1273, tokenIndex1273
+							if buffer[position] != rune('O') {
+								goto l1271
+							}
+							position++
+						}
+					l1273:
+						{
+							position1275, tokenIndex1275 := position, tokenIndex
+							if buffer[position] != rune('n') {
+								goto l1276
+							}
+							position++
+							goto l1275
+						l1276:
+							position, tokenIndex = position1275, tokenIndex1275
+							if buffer[position] != rune('N') {
+								goto l1271
+							}
+							position++
+						}
+					l1275:
+						if !_rules[rulesp]() {
+							goto l1271
+						}
+						if !_rules[ruleMapExpr]() {
+							goto l1271
+						}
+						goto l1272
+					l1271:
+						position, tokenIndex = position1271, tokenIndex1271
+					}
+				l1272:
+					add(rulePegText, position1270)
+				}
+				if !_rules[ruleAction47]() {
+					goto l1260
+				}
+				add(ruleEvalStmt, position1261)
+			}
+			return true
+		l1260:
+			position, tokenIndex = position1260, tokenIndex1260
+			return false
+		},
+		/* 59 DescribeStmt <- <(('d' / 'D') ('e' / 'E') ('s' / 'S') ('c' / 'C') ('r' / 'R') ('i' / 'I') ('b' / 'B') ('e' / 'E') sp StreamIdentifier Action48)> */
+		func() bool {
+			position1277, tokenIndex1277 := position, tokenIndex
+			{
+				position1278 := position
+				{
+					position1279, tokenIndex1279 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l1280
+					}
+					position++
+					goto l1279
+				l1280:
+					position, tokenIndex = position1279, tokenIndex1279
+					if buffer[position] != rune('D') {
+						goto l1277
+					}
+					position++
+				}
+			l1279:
+				{
+					position1281, tokenIndex1281 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1282
+					}
+					position++
+					goto l1281
+				l1282:
+					position, tokenIndex = position1281, tokenIndex1281
+					if buffer[position] != rune('E') {
+						goto l1277
+					}
+					position++
+				}
+			l1281:
+				{
+					position1283, tokenIndex1283 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1284
+					}
+					position++
+					goto l1283
+				l1284:
+					position, tokenIndex = position1283, tokenIndex1283
+					if buffer[position] != rune('S') {
+						goto l1277
+					}
+					position++
+				}
+			l1283:
+				{
+					position1285, tokenIndex1285 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l1286
+					}
+					position++
+					goto l1285
+				l1286:
+					position, tokenIndex = position1285, tokenIndex1285
+					if buffer[position] != rune('C') {
+						goto l1277
+					}
+					position++
+				}
+			l1285:
+				{
+					position1287, tokenIndex1287 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1288
+					}
+					position++
+					goto l1287
+				l1288:
+					position, tokenIndex = position1287, tokenIndex1287
+					if buffer[position] != rune('R') {
+						goto l1277
+					}
+					position++
+				}
+			l1287:
+				{
+					position1289, tokenIndex1289 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1290
+					}
+					position++
+					goto l1289
+				l1290:
+					position, tokenIndex = position1289, tokenIndex1289
+					if buffer[position] != rune('I') {
+						goto l1277
+					}
+					position++
+				}
+			l1289:
+				{
+					position1291, tokenIndex1291 := position, tokenIndex
+					if buffer[position] != rune('b') {
+						goto l1292
+					}
+					position++
+					goto l1291
+				l1292:
+					position, tokenIndex = position1291, tokenIndex1291
+					if buffer[position] != rune('B') {
+						goto l1277
+					}
+					position++
+				}
+			l1291:
+				{
+					position1293, tokenIndex1293 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1294
+					}
+					position++
+					goto l1293
+				l1294:
+					position, tokenIndex = position1293, tokenIndex1293
+					if buffer[position] != rune('E') {
+						goto l1277
+					}
+					position++
+				}
+			l1293:
+				if !_rules[rulesp]() {
+					goto l1277
+				}
+				if !_rules[ruleStreamIdentifier]() {
+					goto l1277
+				}
+				if !_rules[ruleAction48]() {
+					goto l1277
+				}
+				add(ruleDescribeStmt, position1278)
+			}
+			return true
+		l1277:
+			position, tokenIndex = position1277, tokenIndex1277
+			return false
+		},
+		/* 60 Emitter <- <((sp (ISTREAM / DSTREAM / RSTREAM) !([a-z] / [A-Z] / [0-9] / '_') EmitterOptions Action49) / (<&sp> Action50))> */
+		func() bool {
+			position1295, tokenIndex1295 := position, tokenIndex
+			{
+				position1296 := position
+				{
+					position1297, tokenIndex1297 := position, tokenIndex
+					if !_rules[rulesp]() {
+						goto l1298
+					}
+					{
+						position1299, tokenIndex1299 := position, tokenIndex
+						if !_rules[ruleISTREAM]() {
+							goto l1300
+						}
+						goto l1299
+					l1300:
+						position, tokenIndex = position1299, tokenIndex1299
+						if !_rules[ruleDSTREAM]() {
+							goto l1301
+						}
+						goto l1299
+					l1301:
+						position, tokenIndex = position1299, tokenIndex1299
+						if !_rules[ruleRSTREAM]() {
+							goto l1298
+						}
+					}
+				l1299:
+					{
+						position1302, tokenIndex1302 := position, tokenIndex
+						{
+							position1303, tokenIndex1303 := position, tokenIndex
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l1304
+							}
+							position++
+							goto l1303
+						l1304:
+							position, tokenIndex = position1303, tokenIndex1303
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l1305
+							}
+							position++
+							goto l1303
+						l1305:
+							position, tokenIndex = position1303, tokenIndex1303
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l1306
+							}
+							position++
+							goto l1303
+						l1306:
+							position, tokenIndex = position1303, tokenIndex1303
+							if buffer[position] != rune('_') {
+								goto l1302
+							}
+							position++
+						}
+					l1303:
+						goto l1298
+					l1302:
+						position, tokenIndex = position1302, tokenIndex1302
+					}
+					if !_rules[ruleEmitterOptions]() {
+						goto l1298
+					}
+					if !_rules[ruleAction49]() {
+						goto l1298
+					}
+					goto l1297
+				l1298:
+					position, tokenIndex = position1297, tokenIndex1297
+					{
+						position1307 := position
+						{
+							position1308, tokenIndex1308 := position, tokenIndex
+							if !_rules[rulesp]() {
+								goto l1295
+							}
+							position, tokenIndex = position1308, tokenIndex1308
+						}
+						add(rulePegText, position1307)
+					}
+					if !_rules[ruleAction50]() {
+						goto l1295
+					}
+				}
+			l1297:
+				add(ruleEmitter, position1296)
+			}
+			return true
+		l1295:
+			position, tokenIndex = position1295, tokenIndex1295
+			return false
+		},
+		/* 61 EmitterOptions <- <(<(spOpt '[' spOpt EmitterOptionCombinations spOpt ']')?> Action51)> */
+		func() bool {
+			position1309, tokenIndex1309 := position, tokenIndex
+			{
+				position1310 := position
+				{
+					position1311 := position
+					{
+						position1312, tokenIndex1312 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l1312
+						}
+						if buffer[position] != rune('[') {
+							goto l1312
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l1312
+						}
+						if !_rules[ruleEmitterOptionCombinations]() {
+							goto l1312
+						}
+						if !_rules[rulespOpt]() {
+							goto l1312
+						}
+						if buffer[position] != rune(']') {
+							goto l1312
+						}
+						position++
+						goto l1313
+					l1312:
+						position, tokenIndex = position1312, tokenIndex1312
+					}
+				l1313:
+					add(rulePegText, position1311)
+				}
+				if !_rules[ruleAction51]() {
+					goto l1309
+				}
+				add(ruleEmitterOptions, position1310)
+			}
+			return true
+		l1309:
+			position, tokenIndex = position1309, tokenIndex1309
+			return false
+		},
+		/* 62 EmitterOptionCombinations <- <(EmitterLimit / (EmitterSample sp EmitterLimit) / EmitterSample)> */
+		func() bool {
+			position1314, tokenIndex1314 := position, tokenIndex
+			{
+				position1315 := position
+				{
+					position1316, tokenIndex1316 := position, tokenIndex
+					if !_rules[ruleEmitterLimit]() {
+						goto l1317
+					}
+					goto l1316
+				l1317:
+					position, tokenIndex = position1316, tokenIndex1316
+					if !_rules[ruleEmitterSample]() {
+						goto l1318
+					}
+					if !_rules[rulesp]() {
+						goto l1318
+					}
+					if !_rules[ruleEmitterLimit]() {
+						goto l1318
+					}
+					goto l1316
+				l1318:
+					position, tokenIndex = position1316, tokenIndex1316
+					if !_rules[ruleEmitterSample]() {
+						goto l1314
+					}
+				}
+			l1316:
+				add(ruleEmitterOptionCombinations, position1315)
+			}
+			return true
+		l1314:
+			position, tokenIndex = position1314, tokenIndex1314
+			return false
+		},
+		/* 63 EmitterLimit <- <(('l' / 'L') ('i' / 'I') ('m' / 'M') ('i' / 'I') ('t' / 'T') sp NumericLiteral Action52)> */
+		func() bool {
+			position1319, tokenIndex1319 := position, tokenIndex
+			{
+				position1320 := position
+				{
+					position1321, tokenIndex1321 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1322
+					}
+					position++
+					goto l1321
+				l1322:
+					position, tokenIndex = position1321, tokenIndex1321
+					if buffer[position] != rune('L') {
+						goto l1319
+					}
+					position++
+				}
+			l1321:
+				{
+					position1323, tokenIndex1323 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1324
+					}
+					position++
+					goto l1323
+				l1324:
+					position, tokenIndex = position1323, tokenIndex1323
+					if buffer[position] != rune('I') {
+						goto l1319
+					}
+					position++
+				}
+			l1323:
+				{
+					position1325, tokenIndex1325 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1326
+					}
+					position++
+					goto l1325
+				l1326:
+					position, tokenIndex = position1325, tokenIndex1325
+					if buffer[position] != rune('M') {
+						goto l1319
+					}
+					position++
+				}
+			l1325:
+				{
+					position1327, tokenIndex1327 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1328
+					}
+					position++
+					goto l1327
+				l1328:
+					position, tokenIndex = position1327, tokenIndex1327
+					if buffer[position] != rune('I') {
+						goto l1319
+					}
+					position++
+				}
+			l1327:
+				{
+					position1329, tokenIndex1329 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1330
+					}
+					position++
+					goto l1329
+				l1330:
+					position, tokenIndex = position1329, tokenIndex1329
+					if buffer[position] != rune('T') {
+						goto l1319
+					}
+					position++
+				}
+			l1329:
+				if !_rules[rulesp]() {
+					goto l1319
+				}
+				if !_rules[ruleNumericLiteral]() {
+					goto l1319
+				}
+				if !_rules[ruleAction52]() {
+					goto l1319
+				}
+				add(ruleEmitterLimit, position1320)
+			}
+			return true
+		l1319:
+			position, tokenIndex = position1319, tokenIndex1319
+			return false
+		},
+		/* 64 EmitterSample <- <(CountBasedSampling / RandomizedSampling / TimeBasedSampling)> */
+		func() bool {
+			position1331, tokenIndex1331 := position, tokenIndex
+			{
+				position1332 := position
+				{
+					position1333, tokenIndex1333 := position, tokenIndex
+					if !_rules[ruleCountBasedSampling]() {
+						goto l1334
+					}
+					goto l1333
+				l1334:
+					position, tokenIndex = position1333, tokenIndex1333
+					if !_rules[ruleRandomizedSampling]() {
+						goto l1335
+					}
+					goto l1333
+				l1335:
+					position, tokenIndex = position1333, tokenIndex1333
+					if !_rules[ruleTimeBasedSampling]() {
+						goto l1331
+					}
+				}
+			l1333:
+				add(ruleEmitterSample, position1332)
+			}
+			return true
+		l1331:
+			position, tokenIndex = position1331, tokenIndex1331
+			return false
+		},
+		/* 65 CountBasedSampling <- <(('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y') sp NumericLiteral spOpt '-'? spOpt ((('s' / 'S') ('t' / 'T')) / (('n' / 'N') ('d' / 'D')) / (('r' / 'R') ('d' / 'D')) / (('t' / 'T') ('h' / 'H'))) sp (('t' / 'T') ('u' / 'U') ('p' / 'P') ('l' / 'L') ('e' / 'E')) Action53)> */
+		func() bool {
+			position1336, tokenIndex1336 := position, tokenIndex
+			{
+				position1337 := position
+				{
+					position1338, tokenIndex1338 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1339
+					}
+					position++
+					goto l1338
+				l1339:
+					position, tokenIndex = position1338, tokenIndex1338
+					if buffer[position] != rune('E') {
+						goto l1336
+					}
+					position++
+				}
+			l1338:
+				{
+					position1340, tokenIndex1340 := position, tokenIndex
+					if buffer[position] != rune('v') {
+						goto l1341
+					}
+					position++
+					goto l1340
+				l1341:
+					position, tokenIndex = position1340, tokenIndex1340
+					if buffer[position] != rune('V') {
+						goto l1336
+					}
+					position++
+				}
+			l1340:
+				{
+					position1342, tokenIndex1342 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1343
+					}
+					position++
+					goto l1342
+				l1343:
+					position, tokenIndex = position1342, tokenIndex1342
+					if buffer[position] != rune('E') {
+						goto l1336
+					}
+					position++
+				}
+			l1342:
+				{
+					position1344, tokenIndex1344 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1345
+					}
+					position++
+					goto l1344
+				l1345:
+					position, tokenIndex = position1344, tokenIndex1344
+					if buffer[position] != rune('R') {
+						goto l1336
+					}
+					position++
+				}
+			l1344:
+				{
+					position1346, tokenIndex1346 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l1347
+					}
+					position++
+					goto l1346
+				l1347:
+					position, tokenIndex = position1346, tokenIndex1346
+					if buffer[position] != rune('Y') {
+						goto l1336
+					}
+					position++
+				}
+			l1346:
+				if !_rules[rulesp]() {
+					goto l1336
+				}
+				if !_rules[ruleNumericLiteral]() {
+					goto l1336
+				}
+				if !_rules[rulespOpt]() {
+					goto l1336
+				}
+				{
+					position1348, tokenIndex1348 := position, tokenIndex
+					if buffer[position] != rune('-') {
+						goto l1348
+					}
+					position++
+					goto l1349
+				l1348:
+					position, tokenIndex = position1348, tokenIndex1348
+				}
+			l1349:
+				if !_rules[rulespOpt]() {
+					goto l1336
+				}
+				{
+					position1350, tokenIndex1350 := position, tokenIndex
+					{
+						position1352, tokenIndex1352 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1353
+						}
+						position++
+						goto l1352
+					l1353:
+						position, tokenIndex = position1352, tokenIndex1352
+						if buffer[position] != rune('S') {
+							goto l1351
+						}
+						position++
+					}
+				l1352:
+					{
+						position1354, tokenIndex1354 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1355
+						}
+						position++
+						goto l1354
+					l1355:
+						position, tokenIndex = position1354, tokenIndex1354
+						if buffer[position] != rune('T') {
+							goto l1351
+						}
+						position++
+					}
+				l1354:
+					goto l1350
+				l1351:
+					position, tokenIndex = position1350, tokenIndex1350
+					{
+						position1357, tokenIndex1357 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1358
+						}
+						position++
+						goto l1357
+					l1358:
+						position, tokenIndex = position1357, tokenIndex1357
+						if buffer[position] != rune('N') {
+							goto l1356
+						}
+						position++
+					}
+				l1357:
+					{
+						position1359, tokenIndex1359 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l1360
+						}
+						position++
+						goto l1359
+					l1360:
+						position, tokenIndex = position1359, tokenIndex1359
+						if buffer[position] != rune('D') {
+							goto l1356
+						}
+						position++
+					}
+				l1359:
+					goto l1350
+				l1356:
+					position, tokenIndex = position1350, tokenIndex1350
+					{
+						position1362, tokenIndex1362 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1363
+						}
+						position++
+						goto l1362
+					l1363:
+						position, tokenIndex = position1362, tokenIndex1362
+						if buffer[position] != rune('R') {
+							goto l1361
+						}
+						position++
+					}
+				l1362:
+					{
+						position1364, tokenIndex1364 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l1365
+						}
+						position++
+						goto l1364
+					l1365:
+						position, tokenIndex = position1364, tokenIndex1364
+						if buffer[position] != rune('D') {
+							goto l1361
+						}
+						position++
+					}
+				l1364:
+					goto l1350
+				l1361:
+					position, tokenIndex = position1350, tokenIndex1350
+					{
+						position1366, tokenIndex1366 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1367
+						}
+						position++
+						goto l1366
+					l1367:
+						position, tokenIndex = position1366, tokenIndex1366
+						if buffer[position] != rune('T') {
+							goto l1336
+						}
+						position++
+					}
+				l1366:
+					{
+						position1368, tokenIndex1368 := position, tokenIndex
+						if buffer[position] != rune('h') {
+							goto l1369
+						}
+						position++
+						goto l1368
+					l1369:
+						position, tokenIndex = position1368, tokenIndex1368
+						if buffer[position] != rune('H') {
+							goto l1336
+						}
+						position++
+					}
+				l1368:
+				}
+			l1350:
+				if !_rules[rulesp]() {
+					goto l1336
+				}
+				{
+					position1370, tokenIndex1370 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1371
+					}
+					position++
+					goto l1370
+				l1371:
+					position, tokenIndex = position1370, tokenIndex1370
+					if buffer[position] != rune('T') {
+						goto l1336
+					}
+					position++
+				}
+			l1370:
+				{
+					position1372, tokenIndex1372 := position, tokenIndex
+					if buffer[position] != rune('u') {
+						goto l1373
+					}
+					position++
+					goto l1372
+				l1373:
+					position, tokenIndex = position1372, tokenIndex1372
+					if buffer[position] != rune('U') {
+						goto l1336
+					}
+					position++
+				}
+			l1372:
+				{
+					position1374, tokenIndex1374 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1375
+					}
+					position++
+					goto l1374
+				l1375:
+					position, tokenIndex = position1374, tokenIndex1374
+					if buffer[position] != rune('P') {
+						goto l1336
+					}
+					position++
+				}
+			l1374:
+				{
+					position1376, tokenIndex1376 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1377
+					}
+					position++
+					goto l1376
+				l1377:
+					position, tokenIndex = position1376, tokenIndex1376
+					if buffer[position] != rune('L') {
+						goto l1336
+					}
+					position++
+				}
+			l1376:
+				{
+					position1378, tokenIndex1378 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1379
+					}
+					position++
+					goto l1378
+				l1379:
+					position, tokenIndex = position1378, tokenIndex1378
+					if buffer[position] != rune('E') {
+						goto l1336
+					}
+					position++
+				}
+			l1378:
+				if !_rules[ruleAction53]() {
+					goto l1336
+				}
+				add(ruleCountBasedSampling, position1337)
+			}
+			return true
+		l1336:
+			position, tokenIndex = position1336, tokenIndex1336
+			return false
+		},
+		/* 66 RandomizedSampling <- <(('s' / 'S') ('a' / 'A') ('m' / 'M') ('p' / 'P') ('l' / 'L') ('e' / 'E') sp (FloatLiteral / NumericLiteral) spOpt '%' Action54)> */
+		func() bool {
+			position1380, tokenIndex1380 := position, tokenIndex
+			{
+				position1381 := position
+				{
+					position1382, tokenIndex1382 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1383
+					}
+					position++
+					goto l1382
+				l1383:
+					position, tokenIndex = position1382, tokenIndex1382
+					if buffer[position] != rune('S') {
+						goto l1380
+					}
+					position++
+				}
+			l1382:
+				{
+					position1384, tokenIndex1384 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1385
+					}
+					position++
+					goto l1384
+				l1385:
+					position, tokenIndex = position1384, tokenIndex1384
+					if buffer[position] != rune('A') {
+						goto l1380
+					}
+					position++
+				}
+			l1384:
+				{
+					position1386, tokenIndex1386 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1387
+					}
+					position++
+					goto l1386
+				l1387:
+					position, tokenIndex = position1386, tokenIndex1386
+					if buffer[position] != rune('M') {
+						goto l1380
+					}
+					position++
+				}
+			l1386:
+				{
+					position1388, tokenIndex1388 := position, tokenIndex
+					if buffer[position] != rune('p') {
+						goto l1389
+					}
+					position++
+					goto l1388
+				l1389:
+					position, tokenIndex = position1388, tokenIndex1388
+					if buffer[position] != rune('P') {
+						goto l1380
+					}
+					position++
+				}
+			l1388:
+				{
+					position1390, tokenIndex1390 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1391
+					}
+					position++
+					goto l1390
+				l1391:
+					position, tokenIndex = position1390, tokenIndex1390
+					if buffer[position] != rune('L') {
+						goto l1380
+					}
+					position++
+				}
+			l1390:
+				{
+					position1392, tokenIndex1392 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1393
+					}
+					position++
+					goto l1392
+				l1393:
+					position, tokenIndex = position1392, tokenIndex1392
+					if buffer[position] != rune('E') {
+						goto l1380
+					}
+					position++
+				}
+			l1392:
+				if !_rules[rulesp]() {
+					goto l1380
+				}
+				{
+					position1394, tokenIndex1394 := position, tokenIndex
+					if !_rules[ruleFloatLiteral]() {
+						goto l1395
+					}
+					goto l1394
+				l1395:
+					position, tokenIndex = position1394, tokenIndex1394
+					if !_rules[ruleNumericLiteral]() {
+						goto l1380
+					}
+				}
+			l1394:
+				if !_rules[rulespOpt]() {
+					goto l1380
+				}
+				if buffer[position] != rune('%') {
+					goto l1380
+				}
+				position++
+				if !_rules[ruleAction54]() {
+					goto l1380
+				}
+				add(ruleRandomizedSampling, position1381)
+			}
+			return true
+		l1380:
+			position, tokenIndex = position1380, tokenIndex1380
+			return false
+		},
+		/* 67 TimeBasedSampling <- <(TimeBasedSamplingSeconds / TimeBasedSamplingMilliseconds)> */
+		func() bool {
+			position1396, tokenIndex1396 := position, tokenIndex
+			{
+				position1397 := position
+				{
+					position1398, tokenIndex1398 := position, tokenIndex
+					if !_rules[ruleTimeBasedSamplingSeconds]() {
+						goto l1399
+					}
+					goto l1398
+				l1399:
+					position, tokenIndex = position1398, tokenIndex1398
+					if !_rules[ruleTimeBasedSamplingMilliseconds]() {
+						goto l1396
+					}
+				}
+			l1398:
+				add(ruleTimeBasedSampling, position1397)
+			}
+			return true
+		l1396:
+			position, tokenIndex = position1396, tokenIndex1396
+			return false
+		},
+		/* 68 TimeBasedSamplingSeconds <- <(('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y') sp (FloatLiteral / NumericLiteral) sp (('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S')) Action55)> */
+		func() bool {
+			position1400, tokenIndex1400 := position, tokenIndex
+			{
+				position1401 := position
+				{
+					position1402, tokenIndex1402 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1403
+					}
+					position++
+					goto l1402
+				l1403:
+					position, tokenIndex = position1402, tokenIndex1402
+					if buffer[position] != rune('E') {
+						goto l1400
+					}
+					position++
+				}
+			l1402:
+				{
+					position1404, tokenIndex1404 := position, tokenIndex
+					if buffer[position] != rune('v') {
+						goto l1405
+					}
+					position++
+					goto l1404
+				l1405:
+					position, tokenIndex = position1404, tokenIndex1404
+					if buffer[position] != rune('V') {
+						goto l1400
+					}
+					position++
+				}
+			l1404:
+				{
+					position1406, tokenIndex1406 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1407
+					}
+					position++
+					goto l1406
+				l1407:
+					position, tokenIndex = position1406, tokenIndex1406
+					if buffer[position] != rune('E') {
+						goto l1400
+					}
+					position++
+				}
+			l1406:
+				{
+					position1408, tokenIndex1408 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1409
+					}
+					position++
+					goto l1408
+				l1409:
+					position, tokenIndex = position1408, tokenIndex1408
+					if buffer[position] != rune('R') {
+						goto l1400
+					}
+					position++
+				}
+			l1408:
+				{
+					position1410, tokenIndex1410 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l1411
+					}
+					position++
+					goto l1410
+				l1411:
+					position, tokenIndex = position1410, tokenIndex1410
+					if buffer[position] != rune('Y') {
+						goto l1400
+					}
+					position++
+				}
+			l1410:
+				if !_rules[rulesp]() {
+					goto l1400
+				}
+				{
+					position1412, tokenIndex1412 := position, tokenIndex
+					if !_rules[ruleFloatLiteral]() {
+						goto l1413
+					}
+					goto l1412
+				l1413:
+					position, tokenIndex = position1412, tokenIndex1412
+					if !_rules[ruleNumericLiteral]() {
+						goto l1400
+					}
+				}
+			l1412:
+				if !_rules[rulesp]() {
+					goto l1400
+				}
+				{
+					position1414, tokenIndex1414 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1415
+					}
+					position++
+					goto l1414
+				l1415:
+					position, tokenIndex = position1414, tokenIndex1414
+					if buffer[position] != rune('S') {
+						goto l1400
+					}
+					position++
+				}
+			l1414:
+				{
+					position1416, tokenIndex1416 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1417
+					}
+					position++
+					goto l1416
+				l1417:
+					position, tokenIndex = position1416, tokenIndex1416
+					if buffer[position] != rune('E') {
+						goto l1400
+					}
+					position++
+				}
+			l1416:
+				{
+					position1418, tokenIndex1418 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l1419
+					}
+					position++
+					goto l1418
+				l1419:
+					position, tokenIndex = position1418, tokenIndex1418
+					if buffer[position] != rune('C') {
+						goto l1400
+					}
+					position++
+				}
+			l1418:
+				{
+					position1420, tokenIndex1420 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1421
+					}
+					position++
+					goto l1420
+				l1421:
+					position, tokenIndex = position1420, tokenIndex1420
+					if buffer[position] != rune('O') {
+						goto l1400
+					}
+					position++
+				}
+			l1420:
+				{
+					position1422, tokenIndex1422 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1423
+					}
+					position++
+					goto l1422
+				l1423:
+					position, tokenIndex = position1422, tokenIndex1422
+					if buffer[position] != rune('N') {
+						goto l1400
+					}
+					position++
+				}
+			l1422:
+				{
+					position1424, tokenIndex1424 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l1425
+					}
+					position++
+					goto l1424
+				l1425:
+					position, tokenIndex = position1424, tokenIndex1424
+					if buffer[position] != rune('D') {
+						goto l1400
+					}
+					position++
+				}
+			l1424:
+				{
+					position1426, tokenIndex1426 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1427
+					}
+					position++
+					goto l1426
+				l1427:
+					position, tokenIndex = position1426, tokenIndex1426
+					if buffer[position] != rune('S') {
+						goto l1400
+					}
+					position++
+				}
+			l1426:
+				if !_rules[ruleAction55]() {
+					goto l1400
+				}
+				add(ruleTimeBasedSamplingSeconds, position1401)
+			}
+			return true
+		l1400:
+			position, tokenIndex = position1400, tokenIndex1400
+			return false
+		},
+		/* 69 TimeBasedSamplingMilliseconds <- <(('e' / 'E') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('y' / 'Y') sp (FloatLiteral / NumericLiteral) sp (('m' / 'M') ('i' / 'I') ('l' / 'L') ('l' / 'L') ('i' / 'I') ('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S')) Action56)> */
+		func() bool {
+			position1428, tokenIndex1428 := position, tokenIndex
+			{
+				position1429 := position
+				{
+					position1430, tokenIndex1430 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1431
+					}
+					position++
+					goto l1430
+				l1431:
+					position, tokenIndex = position1430, tokenIndex1430
+					if buffer[position] != rune('E') {
+						goto l1428
+					}
+					position++
+				}
+			l1430:
+				{
+					position1432, tokenIndex1432 := position, tokenIndex
+					if buffer[position] != rune('v') {
+						goto l1433
+					}
+					position++
+					goto l1432
+				l1433:
+					position, tokenIndex = position1432, tokenIndex1432
+					if buffer[position] != rune('V') {
+						goto l1428
+					}
+					position++
+				}
+			l1432:
+				{
+					position1434, tokenIndex1434 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1435
+					}
+					position++
+					goto l1434
+				l1435:
+					position, tokenIndex = position1434, tokenIndex1434
+					if buffer[position] != rune('E') {
+						goto l1428
+					}
+					position++
+				}
+			l1434:
+				{
+					position1436, tokenIndex1436 := position, tokenIndex
+					if buffer[position] != rune('r') {
+						goto l1437
+					}
+					position++
+					goto l1436
+				l1437:
+					position, tokenIndex = position1436, tokenIndex1436
+					if buffer[position] != rune('R') {
+						goto l1428
+					}
+					position++
+				}
+			l1436:
+				{
+					position1438, tokenIndex1438 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l1439
+					}
+					position++
+					goto l1438
+				l1439:
+					position, tokenIndex = position1438, tokenIndex1438
+					if buffer[position] != rune('Y') {
+						goto l1428
+					}
+					position++
+				}
+			l1438:
+				if !_rules[rulesp]() {
+					goto l1428
+				}
+				{
+					position1440, tokenIndex1440 := position, tokenIndex
+					if !_rules[ruleFloatLiteral]() {
+						goto l1441
+					}
+					goto l1440
+				l1441:
+					position, tokenIndex = position1440, tokenIndex1440
+					if !_rules[ruleNumericLiteral]() {
+						goto l1428
+					}
+				}
+			l1440:
+				if !_rules[rulesp]() {
+					goto l1428
+				}
+				{
+					position1442, tokenIndex1442 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1443
+					}
+					position++
+					goto l1442
+				l1443:
+					position, tokenIndex = position1442, tokenIndex1442
+					if buffer[position] != rune('M') {
+						goto l1428
+					}
+					position++
+				}
+			l1442:
+				{
+					position1444, tokenIndex1444 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1445
+					}
+					position++
+					goto l1444
+				l1445:
+					position, tokenIndex = position1444, tokenIndex1444
+					if buffer[position] != rune('I') {
+						goto l1428
+					}
+					position++
+				}
+			l1444:
+				{
+					position1446, tokenIndex1446 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1447
+					}
+					position++
+					goto l1446
+				l1447:
+					position, tokenIndex = position1446, tokenIndex1446
+					if buffer[position] != rune('L') {
+						goto l1428
+					}
+					position++
+				}
+			l1446:
+				{
+					position1448, tokenIndex1448 := position, tokenIndex
+					if buffer[position] != rune('l') {
+						goto l1449
+					}
+					position++
+					goto l1448
+				l1449:
+					position, tokenIndex = position1448, tokenIndex1448
+					if buffer[position] != rune('L') {
+						goto l1428
+					}
+					position++
+				}
+			l1448:
+				{
+					position1450, tokenIndex1450 := position, tokenIndex
+					if buffer[position] != rune('i') {
+						goto l1451
+					}
+					position++
+					goto l1450
+				l1451:
+					position, tokenIndex = position1450, tokenIndex1450
+					if buffer[position] != rune('I') {
+						goto l1428
+					}
+					position++
+				}
+			l1450:
+				{
+					position1452, tokenIndex1452 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1453
+					}
+					position++
+					goto l1452
+				l1453:
+					position, tokenIndex = position1452, tokenIndex1452
+					if buffer[position] != rune('S') {
+						goto l1428
+					}
+					position++
+				}
+			l1452:
+				{
+					position1454, tokenIndex1454 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1455
+					}
+					position++
+					goto l1454
+				l1455:
+					position, tokenIndex = position1454, tokenIndex1454
+					if buffer[position] != rune('E') {
+						goto l1428
+					}
+					position++
+				}
+			l1454:
+				{
+					position1456, tokenIndex1456 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l1457
+					}
+					position++
+					goto l1456
+				l1457:
+					position, tokenIndex = position1456, tokenIndex1456
+					if buffer[position] != rune('C') {
+						goto l1428
+					}
+					position++
+				}
+			l1456:
+				{
+					position1458, tokenIndex1458 := position, tokenIndex
+					if buffer[position] != rune('o') {
+						goto l1459
+					}
+					position++
+					goto l1458
+				l1459:
+					position, tokenIndex = position1458, tokenIndex1458
+					if buffer[position] != rune('O') {
+						goto l1428
+					}
+					position++
+				}
+			l1458:
+				{
+					position1460, tokenIndex1460 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l1461
+					}
+					position++
+					goto l1460
+				l1461:
+					position, tokenIndex = position1460, tokenIndex1460
+					if buffer[position] != rune('N') {
+						goto l1428
+					}
+					position++
+				}
+			l1460:
+				{
+					position1462, tokenIndex1462 := position, tokenIndex
+					if buffer[position] != rune('d') {
+						goto l1463
+					}
+					position++
+					goto l1462
+				l1463:
+					position, tokenIndex = position1462, tokenIndex1462
+					if buffer[position] != rune('D') {
+						goto l1428
+					}
+					position++
+				}
+			l1462:
+				{
+					position1464, tokenIndex1464 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1465
+					}
+					position++
+					goto l1464
+				l1465:
+					position, tokenIndex = position1464, tokenIndex1464
+					if buffer[position] != rune('S') {
+						goto l1428
+					}
+					position++
+				}
+			l1464:
+				if !_rules[ruleAction56]() {
+					goto l1428
+				}
+				add(ruleTimeBasedSamplingMilliseconds, position1429)
+			}
+			return true
+		l1428:
+			position, tokenIndex = position1428, tokenIndex1428
+			return false
+		},
+		/* 70 Projections <- <(<(sp Projection (spOpt ',' spOpt Projection)*)> Action57)> */
+		func() bool {
+			position1466, tokenIndex1466 := position, tokenIndex
+			{
+				position1467 := position
+				{
+					position1468 := position
+					if !_rules[rulesp]() {
+						goto l1466
+					}
+					if !_rules[ruleProjection]() {
+						goto l1466
+					}
+				l1469:
+					{
+						position1470, tokenIndex1470 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l1470
+						}
+						if buffer[position] != rune(',') {
+							goto l1470
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l1470
+						}
+						if !_rules[ruleProjection]() {
+							goto l1470
+						}
+						goto l1469
+					l1470:
+						position, tokenIndex = position1470, tokenIndex1470
+					}
+					add(rulePegText, position1468)
+				}
+				if !_rules[ruleAction57]() {
+					goto l1466
+				}
+				add(ruleProjections, position1467)
+			}
+			return true
+		l1466:
+			position, tokenIndex = position1466, tokenIndex1466
+			return false
+		},
+		/* 71 Projection <- <(AliasExpression / ExpressionOrWildcard)> */
+		func() bool {
+			position1471, tokenIndex1471 := position, tokenIndex
+			{
+				position1472 := position
+				{
+					position1473, tokenIndex1473 := position, tokenIndex
+					if !_rules[ruleAliasExpression]() {
+						goto l1474
+					}
+					goto l1473
+				l1474:
+					position, tokenIndex = position1473, tokenIndex1473
+					if !_rules[ruleExpressionOrWildcard]() {
+						goto l1471
+					}
+				}
+			l1473:
+				add(ruleProjection, position1472)
+			}
+			return true
+		l1471:
+			position, tokenIndex = position1471, tokenIndex1471
+			return false
+		},
+		/* 72 AliasExpression <- <(ExpressionOrWildcard sp (('a' / 'A') ('s' / 'S')) sp TargetIdentifier Action58)> */
+		func() bool {
+			position1475, tokenIndex1475 := position, tokenIndex
+			{
+				position1476 := position
+				if !_rules[ruleExpressionOrWildcard]() {
+					goto l1475
+				}
+				if !_rules[rulesp]() {
+					goto l1475
+				}
+				{
+					position1477, tokenIndex1477 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1478
+					}
+					position++
+					goto l1477
+				l1478:
+					position, tokenIndex = position1477, tokenIndex1477
+					if buffer[position] != rune('A') {
+						goto l1475
+					}
+					position++
+				}
+			l1477:
+				{
+					position1479, tokenIndex1479 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1480
+					}
+					position++
+					goto l1479
+				l1480:
+					position, tokenIndex = position1479, tokenIndex1479
+					if buffer[position] != rune('S') {
+						goto l1475
+					}
+					position++
+				}
+			l1479:
+				if !_rules[rulesp]() {
+					goto l1475
+				}
+				if !_rules[ruleTargetIdentifier]() {
+					goto l1475
+				}
+				if !_rules[ruleAction58]() {
+					goto l1475
+				}
+				add(ruleAliasExpression, position1476)
+			}
+			return true
+		l1475:
+			position, tokenIndex = position1475, tokenIndex1475
+			return false
+		},
+		/* 73 WindowedFrom <- <(<(sp (('f' / 'F') ('r' / 'R') ('o' / 'O') ('m' / 'M')) sp Relations StateJoin*)?> Action59)> */
+		func() bool {
+			position1481, tokenIndex1481 := position, tokenIndex
+			{
+				position1482 := position
+				{
+					position1483 := position
+					{
+						position1484, tokenIndex1484 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1484
+						}
+						{
+							position1486, tokenIndex1486 := position, tokenIndex
+							if buffer[position] != rune('f') {
+								goto l1487
+							}
+							position++
+							goto l1486
+						l1487:
+							position, tokenIndex = position1486, tokenIndex1486
+							if buffer[position] != rune('F') {
+								goto l1484
+							}
+							position++
+						}
+					l1486:
+						{
+							position1488, tokenIndex1488 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1489
+							}
+							position++
+							goto l1488
+						l1489:
+							position, tokenIndex = position1488, tokenIndex1488
+							if buffer[position] != rune('R') {
+								goto l1484
+							}
+							position++
+						}
+					l1488:
+						{
+							position1490, tokenIndex1490 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l1491
+							}
+							position++
+							goto l1490
+						l1491:
+							position, tokenIndex = position1490, tokenIndex1490
+							if buffer[position] != rune('O') {
+								goto l1484
+							}
+							position++
+						}
+					l1490:
+						{
+							position1492, tokenIndex1492 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l1493
+							}
+							position++
+							goto l1492
+						l1493:
+							position, tokenIndex = position1492, tokenIndex1492
+							if buffer[position] != rune('M') {
+								goto l1484
+							}
+							position++
+						}
+					l1492:
+						if !_rules[rulesp]() {
+							goto l1484
+						}
+						if !_rules[ruleRelations]() {
+							goto l1484
+						}
+					l1494:
+						{
+							position1495, tokenIndex1495 := position, tokenIndex
+							if !_rules[ruleStateJoin]() {
+								goto l1495
+							}
+							goto l1494
+						l1495:
+							position, tokenIndex = position1495, tokenIndex1495
+						}
+						goto l1485
+					l1484:
+						position, tokenIndex = position1484, tokenIndex1484
+					}
+				l1485:
+					add(rulePegText, position1483)
+				}
+				if !_rules[ruleAction59]() {
+					goto l1481
+				}
+				add(ruleWindowedFrom, position1482)
+			}
+			return true
+		l1481:
+			position, tokenIndex = position1481, tokenIndex1481
+			return false
+		},
+		/* 74 InputSampling <- <(<(sp (('r' / 'R') ('e' / 'E') ('a' / 'A') ('d' / 'D')) sp (CountBasedSampling / RandomizedSampling))?> Action60)> */
+		func() bool {
+			position1496, tokenIndex1496 := position, tokenIndex
+			{
+				position1497 := position
+				{
+					position1498 := position
+					{
+						position1499, tokenIndex1499 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1499
+						}
+						{
+							position1501, tokenIndex1501 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1502
+							}
+							position++
+							goto l1501
+						l1502:
+							position, tokenIndex = position1501, tokenIndex1501
+							if buffer[position] != rune('R') {
+								goto l1499
+							}
+							position++
+						}
+					l1501:
+						{
+							position1503, tokenIndex1503 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1504
+							}
+							position++
+							goto l1503
+						l1504:
+							position, tokenIndex = position1503, tokenIndex1503
+							if buffer[position] != rune('E') {
+								goto l1499
+							}
+							position++
+						}
+					l1503:
+						{
+							position1505, tokenIndex1505 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l1506
+							}
+							position++
+							goto l1505
+						l1506:
+							position, tokenIndex = position1505, tokenIndex1505
+							if buffer[position] != rune('A') {
+								goto l1499
+							}
+							position++
+						}
+					l1505:
+						{
+							position1507, tokenIndex1507 := position, tokenIndex
+							if buffer[position] != rune('d') {
+								goto l1508
+							}
+							position++
+							goto l1507
+						l1508:
+							position, tokenIndex = position1507, tokenIndex1507
+							if buffer[position] != rune('D') {
+								goto l1499
+							}
+							position++
+						}
+					l1507:
+						if !_rules[rulesp]() {
+							goto l1499
+						}
+						{
+							position1509, tokenIndex1509 := position, tokenIndex
+							if !_rules[ruleCountBasedSampling]() {
+								goto l1510
+							}
+							goto l1509
+						l1510:
+							position, tokenIndex = position1509, tokenIndex1509
+							if !_rules[ruleRandomizedSampling]() {
+								goto l1499
+							}
+						}
+					l1509:
+						goto l1500
+					l1499:
+						position, tokenIndex = position1499, tokenIndex1499
+					}
+				l1500:
+					add(rulePegText, position1498)
+				}
+				if !_rules[ruleAction60]() {
+					goto l1496
+				}
+				add(ruleInputSampling, position1497)
+			}
+			return true
+		l1496:
+			position, tokenIndex = position1496, tokenIndex1496
+			return false
+		},
+		/* 75 Interval <- <(TimeInterval / TuplesInterval)> */
+		func() bool {
+			position1511, tokenIndex1511 := position, tokenIndex
+			{
+				position1512 := position
+				{
+					position1513, tokenIndex1513 := position, tokenIndex
+					if !_rules[ruleTimeInterval]() {
+						goto l1514
+					}
+					goto l1513
+				l1514:
+					position, tokenIndex = position1513, tokenIndex1513
+					if !_rules[ruleTuplesInterval]() {
+						goto l1511
+					}
+				}
+			l1513:
+				add(ruleInterval, position1512)
+			}
+			return true
+		l1511:
+			position, tokenIndex = position1511, tokenIndex1511
+			return false
+		},
+		/* 76 TimeInterval <- <((FloatLiteral / NumericLiteral) sp (SECONDS / MILLISECONDS) Action61)> */
+		func() bool {
+			position1515, tokenIndex1515 := position, tokenIndex
+			{
+				position1516 := position
+				{
+					position1517, tokenIndex1517 := position, tokenIndex
+					if !_rules[ruleFloatLiteral]() {
+						goto l1518
+					}
+					goto l1517
+				l1518:
+					position, tokenIndex = position1517, tokenIndex1517
+					if !_rules[ruleNumericLiteral]() {
+						goto l1515
+					}
+				}
+			l1517:
+				if !_rules[rulesp]() {
+					goto l1515
+				}
+				{
+					position1519, tokenIndex1519 := position, tokenIndex
+					if !_rules[ruleSECONDS]() {
+						goto l1520
+					}
+					goto l1519
+				l1520:
+					position, tokenIndex = position1519, tokenIndex1519
+					if !_rules[ruleMILLISECONDS]() {
+						goto l1515
+					}
+				}
+			l1519:
+				if !_rules[ruleAction61]() {
+					goto l1515
+				}
+				add(ruleTimeInterval, position1516)
+			}
+			return true
+		l1515:
+			position, tokenIndex = position1515, tokenIndex1515
+			return false
+		},
+		/* 77 TuplesInterval <- <(NumericLiteral sp TUPLES Action62)> */
+		func() bool {
+			position1521, tokenIndex1521 := position, tokenIndex
+			{
+				position1522 := position
+				if !_rules[ruleNumericLiteral]() {
+					goto l1521
+				}
+				if !_rules[rulesp]() {
+					goto l1521
+				}
+				if !_rules[ruleTUPLES]() {
+					goto l1521
+				}
+				if !_rules[ruleAction62]() {
+					goto l1521
+				}
+				add(ruleTuplesInterval, position1522)
+			}
+			return true
+		l1521:
+			position, tokenIndex = position1521, tokenIndex1521
+			return false
+		},
+		/* 78 Relations <- <(RelationLike (spOpt ',' spOpt RelationLike)*)> */
+		func() bool {
+			position1523, tokenIndex1523 := position, tokenIndex
+			{
+				position1524 := position
+				if !_rules[ruleRelationLike]() {
+					goto l1523
+				}
+			l1525:
+				{
+					position1526, tokenIndex1526 := position, tokenIndex
+					if !_rules[rulespOpt]() {
+						goto l1526
+					}
+					if buffer[position] != rune(',') {
+						goto l1526
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1526
+					}
+					if !_rules[ruleRelationLike]() {
+						goto l1526
+					}
+					goto l1525
+				l1526:
+					position, tokenIndex = position1526, tokenIndex1526
+				}
+				add(ruleRelations, position1524)
+			}
+			return true
+		l1523:
+			position, tokenIndex = position1523, tokenIndex1523
+			return false
+		},
+		/* 79 Filter <- <(<(sp (('w' / 'W') ('h' / 'H') ('e' / 'E') ('r' / 'R') ('e' / 'E')) sp Expression)?> Action63)> */
+		func() bool {
+			position1527, tokenIndex1527 := position, tokenIndex
+			{
+				position1528 := position
+				{
+					position1529 := position
+					{
+						position1530, tokenIndex1530 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1530
+						}
+						{
+							position1532, tokenIndex1532 := position, tokenIndex
+							if buffer[position] != rune('w') {
+								goto l1533
+							}
+							position++
+							goto l1532
+						l1533:
+							position, tokenIndex = position1532, tokenIndex1532
+							if buffer[position] != rune('W') {
+								goto l1530
+							}
+							position++
+						}
+					l1532:
+						{
+							position1534, tokenIndex1534 := position, tokenIndex
+							if buffer[position] != rune('h') {
+								goto l1535
+							}
+							position++
+							goto l1534
+						l1535:
+							position, tokenIndex = position1534, tokenIndex1534
+							if buffer[position] != rune('H') {
+								goto l1530
+							}
+							position++
+						}
+					l1534:
+						{
+							position1536, tokenIndex1536 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1537
+							}
+							position++
+							goto l1536
+						l1537:
+							position, tokenIndex = position1536, tokenIndex1536
+							if buffer[position] != rune('E') {
+								goto l1530
+							}
+							position++
+						}
+					l1536:
+						{
+							position1538, tokenIndex1538 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1539
+							}
+							position++
+							goto l1538
+						l1539:
+							position, tokenIndex = position1538, tokenIndex1538
+							if buffer[position] != rune('R') {
+								goto l1530
+							}
+							position++
+						}
+					l1538:
+						{
+							position1540, tokenIndex1540 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1541
+							}
+							position++
+							goto l1540
+						l1541:
+							position, tokenIndex = position1540, tokenIndex1540
+							if buffer[position] != rune('E') {
+								goto l1530
+							}
+							position++
+						}
+					l1540:
+						if !_rules[rulesp]() {
+							goto l1530
+						}
+						if !_rules[ruleExpression]() {
+							goto l1530
+						}
+						goto l1531
+					l1530:
+						position, tokenIndex = position1530, tokenIndex1530
+					}
+				l1531:
+					add(rulePegText, position1529)
+				}
+				if !_rules[ruleAction63]() {
+					goto l1527
+				}
+				add(ruleFilter, position1528)
+			}
+			return true
+		l1527:
+			position, tokenIndex = position1527, tokenIndex1527
+			return false
+		},
+		/* 80 Grouping <- <(<(sp (('g' / 'G') ('r' / 'R') ('o' / 'O') ('u' / 'U') ('p' / 'P')) sp (('b' / 'B') ('y' / 'Y')) sp (GroupingSets / GroupList))?> Action64)> */
+		func() bool {
+			position1542, tokenIndex1542 := position, tokenIndex
+			{
+				position1543 := position
+				{
+					position1544 := position
+					{
+						position1545, tokenIndex1545 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1545
+						}
+						{
+							position1547, tokenIndex1547 := position, tokenIndex
+							if buffer[position] != rune('g') {
+								goto l1548
+							}
+							position++
+							goto l1547
+						l1548:
+							position, tokenIndex = position1547, tokenIndex1547
+							if buffer[position] != rune('G') {
+								goto l1545
+							}
+							position++
+						}
+					l1547:
+						{
+							position1549, tokenIndex1549 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1550
+							}
+							position++
+							goto l1549
+						l1550:
+							position, tokenIndex = position1549, tokenIndex1549
+							if buffer[position] != rune('R') {
+								goto l1545
+							}
+							position++
+						}
+					l1549:
+						{
+							position1551, tokenIndex1551 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l1552
+							}
+							position++
+							goto l1551
+						l1552:
+							position, tokenIndex = position1551, tokenIndex1551
+							if buffer[position] != rune('O') {
+								goto l1545
+							}
+							position++
+						}
+					l1551:
+						{
+							position1553, tokenIndex1553 := position, tokenIndex
+							if buffer[position] != rune('u') {
+								goto l1554
+							}
+							position++
+							goto l1553
+						l1554:
+							position, tokenIndex = position1553, tokenIndex1553
+							if buffer[position] != rune('U') {
+								goto l1545
+							}
+							position++
+						}
+					l1553:
+						{
+							position1555, tokenIndex1555 := position, tokenIndex
+							if buffer[position] != rune('p') {
+								goto l1556
+							}
+							position++
+							goto l1555
+						l1556:
+							position, tokenIndex = position1555, tokenIndex1555
+							if buffer[position] != rune('P') {
+								goto l1545
+							}
+							position++
+						}
+					l1555:
+						if !_rules[rulesp]() {
+							goto l1545
+						}
+						{
+							position1557, tokenIndex1557 := position, tokenIndex
+							if buffer[position] != rune('b') {
+								goto l1558
+							}
+							position++
+							goto l1557
+						l1558:
+							position, tokenIndex = position1557, tokenIndex1557
+							if buffer[position] != rune('B') {
+								goto l1545
+							}
+							position++
+						}
+					l1557:
+						{
+							position1559, tokenIndex1559 := position, tokenIndex
+							if buffer[position] != rune('y') {
+								goto l1560
+							}
+							position++
+							goto l1559
+						l1560:
+							position, tokenIndex = position1559, tokenIndex1559
+							if buffer[position] != rune('Y') {
+								goto l1545
+							}
+							position++
+						}
+					l1559:
+						if !_rules[rulesp]() {
+							goto l1545
+						}
+						{
+							position1561, tokenIndex1561 := position, tokenIndex
+							if !_rules[ruleGroupingSets]() {
+								goto l1562
+							}
+							goto l1561
+						l1562:
+							position, tokenIndex = position1561, tokenIndex1561
+							if !_rules[ruleGroupList]() {
+								goto l1545
+							}
+						}
+					l1561:
+						goto l1546
+					l1545:
+						position, tokenIndex = position1545, tokenIndex1545
+					}
+				l1546:
+					add(rulePegText, position1544)
+				}
+				if !_rules[ruleAction64]() {
+					goto l1542
+				}
+				add(ruleGrouping, position1543)
+			}
+			return true
+		l1542:
+			position, tokenIndex = position1542, tokenIndex1542
+			return false
+		},
+		/* 81 GroupList <- <(Expression (spOpt ',' spOpt Expression)*)> */
+		func() bool {
+			position1563, tokenIndex1563 := position, tokenIndex
+			{
+				position1564 := position
+				if !_rules[ruleExpression]() {
+					goto l1563
+				}
+			l1565:
+				{
+					position1566, tokenIndex1566 := position, tokenIndex
+					if !_rules[rulespOpt]() {
+						goto l1566
+					}
+					if buffer[position] != rune(',') {
+						goto l1566
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1566
+					}
+					if !_rules[ruleExpression]() {
+						goto l1566
+					}
+					goto l1565
+				l1566:
+					position, tokenIndex = position1566, tokenIndex1566
+				}
+				add(ruleGroupList, position1564)
+			}
+			return true
+		l1563:
+			position, tokenIndex = position1563, tokenIndex1563
+			return false
+		},
+		/* 82 GroupingSets <- <(Rollup / GroupingSetList)> */
+		func() bool {
+			position1567, tokenIndex1567 := position, tokenIndex
+			{
+				position1568 := position
+				{
+					position1569, tokenIndex1569 := position, tokenIndex
+					if !_rules[ruleRollup]() {
+						goto l1570
+					}
+					goto l1569
+				l1570:
+					position, tokenIndex = position1569, tokenIndex1569
+					if !_rules[ruleGroupingSetList]() {
+						goto l1567
+					}
+				}
+			l1569:
+				add(ruleGroupingSets, position1568)
+			}
+			return true
+		l1567:
+			position, tokenIndex = position1567, tokenIndex1567
+			return false
+		},
+		/* 83 Rollup <- <(<(('r' / 'R') ('o' / 'O') ('l' / 'L') ('l' / 'L') ('u' / 'U') ('p' / 'P') spOpt '(' spOpt GroupList spOpt ')')> Action65)> */
+		func() bool {
+			position1571, tokenIndex1571 := position, tokenIndex
+			{
+				position1572 := position
+				{
+					position1573 := position
+					{
+						position1574, tokenIndex1574 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1575
+						}
+						position++
+						goto l1574
+					l1575:
+						position, tokenIndex = position1574, tokenIndex1574
+						if buffer[position] != rune('R') {
+							goto l1571
+						}
+						position++
+					}
+				l1574:
+					{
+						position1576, tokenIndex1576 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1577
+						}
+						position++
+						goto l1576
+					l1577:
+						position, tokenIndex = position1576, tokenIndex1576
+						if buffer[position] != rune('O') {
+							goto l1571
+						}
+						position++
+					}
+				l1576:
+					{
+						position1578, tokenIndex1578 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l1579
+						}
+						position++
+						goto l1578
+					l1579:
+						position, tokenIndex = position1578, tokenIndex1578
+						if buffer[position] != rune('L') {
+							goto l1571
+						}
+						position++
+					}
+				l1578:
+					{
+						position1580, tokenIndex1580 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l1581
+						}
+						position++
+						goto l1580
+					l1581:
+						position, tokenIndex = position1580, tokenIndex1580
+						if buffer[position] != rune('L') {
+							goto l1571
+						}
+						position++
+					}
+				l1580:
+					{
+						position1582, tokenIndex1582 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l1583
+						}
+						position++
+						goto l1582
+					l1583:
+						position, tokenIndex = position1582, tokenIndex1582
+						if buffer[position] != rune('U') {
+							goto l1571
+						}
+						position++
+					}
+				l1582:
+					{
+						position1584, tokenIndex1584 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l1585
+						}
+						position++
+						goto l1584
+					l1585:
+						position, tokenIndex = position1584, tokenIndex1584
+						if buffer[position] != rune('P') {
+							goto l1571
+						}
+						position++
+					}
+				l1584:
+					if !_rules[rulespOpt]() {
+						goto l1571
+					}
+					if buffer[position] != rune('(') {
+						goto l1571
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1571
+					}
+					if !_rules[ruleGroupList]() {
+						goto l1571
+					}
+					if !_rules[rulespOpt]() {
+						goto l1571
+					}
+					if buffer[position] != rune(')') {
+						goto l1571
+					}
+					position++
+					add(rulePegText, position1573)
+				}
+				if !_rules[ruleAction65]() {
+					goto l1571
+				}
+				add(ruleRollup, position1572)
+			}
+			return true
+		l1571:
+			position, tokenIndex = position1571, tokenIndex1571
+			return false
+		},
+		/* 84 GroupingSetList <- <(<(('g' / 'G') ('r' / 'R') ('o' / 'O') ('u' / 'U') ('p' / 'P') ('i' / 'I') ('n' / 'N') ('g' / 'G') sp (('s' / 'S') ('e' / 'E') ('t' / 'T') ('s' / 'S')) spOpt '(' spOpt GroupingSet (spOpt ',' spOpt GroupingSet)* spOpt ')')> Action66)> */
+		func() bool {
+			position1586, tokenIndex1586 := position, tokenIndex
+			{
+				position1587 := position
+				{
+					position1588 := position
+					{
+						position1589, tokenIndex1589 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l1590
+						}
+						position++
+						goto l1589
+					l1590:
+						position, tokenIndex = position1589, tokenIndex1589
+						if buffer[position] != rune('G') {
+							goto l1586
+						}
+						position++
+					}
+				l1589:
+					{
+						position1591, tokenIndex1591 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1592
+						}
+						position++
+						goto l1591
+					l1592:
+						position, tokenIndex = position1591, tokenIndex1591
+						if buffer[position] != rune('R') {
+							goto l1586
+						}
+						position++
+					}
+				l1591:
+					{
+						position1593, tokenIndex1593 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1594
+						}
+						position++
+						goto l1593
+					l1594:
+						position, tokenIndex = position1593, tokenIndex1593
+						if buffer[position] != rune('O') {
+							goto l1586
+						}
+						position++
+					}
+				l1593:
+					{
+						position1595, tokenIndex1595 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l1596
+						}
+						position++
+						goto l1595
+					l1596:
+						position, tokenIndex = position1595, tokenIndex1595
+						if buffer[position] != rune('U') {
+							goto l1586
+						}
+						position++
+					}
+				l1595:
+					{
+						position1597, tokenIndex1597 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l1598
+						}
+						position++
+						goto l1597
+					l1598:
+						position, tokenIndex = position1597, tokenIndex1597
+						if buffer[position] != rune('P') {
+							goto l1586
+						}
+						position++
+					}
+				l1597:
+					{
+						position1599, tokenIndex1599 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1600
+						}
+						position++
+						goto l1599
+					l1600:
+						position, tokenIndex = position1599, tokenIndex1599
+						if buffer[position] != rune('I') {
+							goto l1586
+						}
+						position++
+					}
+				l1599:
+					{
+						position1601, tokenIndex1601 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1602
+						}
+						position++
+						goto l1601
+					l1602:
+						position, tokenIndex = position1601, tokenIndex1601
+						if buffer[position] != rune('N') {
+							goto l1586
+						}
+						position++
+					}
+				l1601:
+					{
+						position1603, tokenIndex1603 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l1604
+						}
+						position++
+						goto l1603
+					l1604:
+						position, tokenIndex = position1603, tokenIndex1603
+						if buffer[position] != rune('G') {
+							goto l1586
+						}
+						position++
+					}
+				l1603:
+					if !_rules[rulesp]() {
+						goto l1586
+					}
+					{
+						position1605, tokenIndex1605 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1606
+						}
+						position++
+						goto l1605
+					l1606:
+						position, tokenIndex = position1605, tokenIndex1605
+						if buffer[position] != rune('S') {
+							goto l1586
+						}
+						position++
+					}
+				l1605:
+					{
+						position1607, tokenIndex1607 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1608
+						}
+						position++
+						goto l1607
+					l1608:
+						position, tokenIndex = position1607, tokenIndex1607
+						if buffer[position] != rune('E') {
+							goto l1586
+						}
+						position++
+					}
+				l1607:
+					{
+						position1609, tokenIndex1609 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1610
+						}
+						position++
+						goto l1609
+					l1610:
+						position, tokenIndex = position1609, tokenIndex1609
+						if buffer[position] != rune('T') {
+							goto l1586
+						}
+						position++
+					}
+				l1609:
+					{
+						position1611, tokenIndex1611 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1612
+						}
+						position++
+						goto l1611
+					l1612:
+						position, tokenIndex = position1611, tokenIndex1611
+						if buffer[position] != rune('S') {
+							goto l1586
+						}
+						position++
+					}
+				l1611:
+					if !_rules[rulespOpt]() {
+						goto l1586
+					}
+					if buffer[position] != rune('(') {
+						goto l1586
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1586
+					}
+					if !_rules[ruleGroupingSet]() {
+						goto l1586
+					}
+				l1613:
+					{
+						position1614, tokenIndex1614 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l1614
+						}
+						if buffer[position] != rune(',') {
+							goto l1614
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l1614
+						}
+						if !_rules[ruleGroupingSet]() {
+							goto l1614
+						}
+						goto l1613
+					l1614:
+						position, tokenIndex = position1614, tokenIndex1614
+					}
+					if !_rules[rulespOpt]() {
+						goto l1586
+					}
+					if buffer[position] != rune(')') {
+						goto l1586
+					}
+					position++
+					add(rulePegText, position1588)
+				}
+				if !_rules[ruleAction66]() {
+					goto l1586
+				}
+				add(ruleGroupingSetList, position1587)
+			}
+			return true
+		l1586:
+			position, tokenIndex = position1586, tokenIndex1586
+			return false
+		},
+		/* 85 GroupingSet <- <(<('(' spOpt GroupList? spOpt ')')> Action67)> */
+		func() bool {
+			position1615, tokenIndex1615 := position, tokenIndex
+			{
+				position1616 := position
+				{
+					position1617 := position
+					if buffer[position] != rune('(') {
+						goto l1615
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1615
+					}
+					{
+						position1618, tokenIndex1618 := position, tokenIndex
+						if !_rules[ruleGroupList]() {
+							goto l1618
+						}
+						goto l1619
+					l1618:
+						position, tokenIndex = position1618, tokenIndex1618
+					}
+				l1619:
+					if !_rules[rulespOpt]() {
+						goto l1615
+					}
+					if buffer[position] != rune(')') {
+						goto l1615
+					}
+					position++
+					add(rulePegText, position1617)
+				}
+				if !_rules[ruleAction67]() {
+					goto l1615
+				}
+				add(ruleGroupingSet, position1616)
+			}
+			return true
+		l1615:
+			position, tokenIndex = position1615, tokenIndex1615
+			return false
+		},
+		/* 86 Having <- <(<(sp (('h' / 'H') ('a' / 'A') ('v' / 'V') ('i' / 'I') ('n' / 'N') ('g' / 'G')) sp Expression)?> Action68)> */
+		func() bool {
+			position1620, tokenIndex1620 := position, tokenIndex
+			{
+				position1621 := position
+				{
+					position1622 := position
+					{
+						position1623, tokenIndex1623 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1623
+						}
+						{
+							position1625, tokenIndex1625 := position, tokenIndex
+							if buffer[position] != rune('h') {
+								goto l1626
+							}
+							position++
+							goto l1625
+						l1626:
+							position, tokenIndex = position1625, tokenIndex1625
+							if buffer[position] != rune('H') {
+								goto l1623
+							}
+							position++
+						}
+					l1625:
+						{
+							position1627, tokenIndex1627 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l1628
+							}
+							position++
+							goto l1627
+						l1628:
+							position, tokenIndex = position1627, tokenIndex1627
+							if buffer[position] != rune('A') {
+								goto l1623
+							}
+							position++
+						}
+					l1627:
+						{
+							position1629, tokenIndex1629 := position, tokenIndex
+							if buffer[position] != rune('v') {
+								goto l1630
+							}
+							position++
+							goto l1629
+						l1630:
+							position, tokenIndex = position1629, tokenIndex1629
+							if buffer[position] != rune('V') {
+								goto l1623
+							}
+							position++
+						}
+					l1629:
+						{
+							position1631, tokenIndex1631 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l1632
+							}
+							position++
+							goto l1631
+						l1632:
+							position, tokenIndex = position1631, tokenIndex1631
+							if buffer[position] != rune('I') {
+								goto l1623
+							}
+							position++
+						}
+					l1631:
+						{
+							position1633, tokenIndex1633 := position, tokenIndex
+							if buffer[position] != rune('n') {
+								goto l1634
+							}
+							position++
+							goto l1633
+						l1634:
+							position, tokenIndex = position1633, tokenIndex1633
+							if buffer[position] != rune('N') {
+								goto l1623
+							}
+							position++
+						}
+					l1633:
+						{
+							position1635, tokenIndex1635 := position, tokenIndex
+							if buffer[position] != rune('g') {
+								goto l1636
+							}
+							position++
+							goto l1635
+						l1636:
+							position, tokenIndex = position1635, tokenIndex1635
+							if buffer[position] != rune('G') {
+								goto l1623
+							}
+							position++
+						}
+					l1635:
+						if !_rules[rulesp]() {
+							goto l1623
+						}
+						if !_rules[ruleExpression]() {
+							goto l1623
+						}
+						goto l1624
+					l1623:
+						position, tokenIndex = position1623, tokenIndex1623
+					}
+				l1624:
+					add(rulePegText, position1622)
+				}
+				if !_rules[ruleAction68]() {
+					goto l1620
+				}
+				add(ruleHaving, position1621)
+			}
+			return true
+		l1620:
+			position, tokenIndex = position1620, tokenIndex1620
+			return false
+		},
+		/* 87 EmitWhen <- <(<(sp (('e' / 'E') ('m' / 'M') ('i' / 'I') ('t' / 'T')) sp (('w' / 'W') ('h' / 'H') ('e' / 'E') ('n' / 'N')) sp Expression)?> Action69)> */
+		func() bool {
+			position1637, tokenIndex1637 := position, tokenIndex
+			{
+				position1638 := position
+				{
+					position1639 := position
+					{
+						position1640, tokenIndex1640 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1640
+						}
+						{
+							position1642, tokenIndex1642 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1643
+							}
+							position++
+							goto l1642
+						l1643:
+							position, tokenIndex = position1642, tokenIndex1642
+							if buffer[position] != rune('E') {
+								goto l1640
+							}
+							position++
+						}
+					l1642:
+						{
+							position1644, tokenIndex1644 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l1645
+							}
+							position++
+							goto l1644
+						l1645:
+							position, tokenIndex = position1644, tokenIndex1644
+							if buffer[position] != rune('M') {
+								goto l1640
+							}
+							position++
+						}
+					l1644:
+						{
+							position1646, tokenIndex1646 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l1647
+							}
+							position++
+							goto l1646
+						l1647:
+							position, tokenIndex = position1646, tokenIndex1646
+							if buffer[position] != rune('I') {
+								goto l1640
+							}
+							position++
+						}
+					l1646:
+						{
+							position1648, tokenIndex1648 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l1649
+							}
+							position++
+							goto l1648
+						l1649:
+							position, tokenIndex = position1648, tokenIndex1648
+							if buffer[position] != rune('T') {
+								goto l1640
+							}
+							position++
+						}
+					l1648:
+						if !_rules[rulesp]() {
+							goto l1640
+						}
+						{
+							position1650, tokenIndex1650 := position, tokenIndex
+							if buffer[position] != rune('w') {
+								goto l1651
+							}
+							position++
+							goto l1650
+						l1651:
+							position, tokenIndex = position1650, tokenIndex1650
+							if buffer[position] != rune('W') {
+								goto l1640
+							}
+							position++
+						}
+					l1650:
+						{
+							position1652, tokenIndex1652 := position, tokenIndex
+							if buffer[position] != rune('h') {
+								goto l1653
+							}
+							position++
+							goto l1652
+						l1653:
+							position, tokenIndex = position1652, tokenIndex1652
+							if buffer[position] != rune('H') {
+								goto l1640
+							}
+							position++
+						}
+					l1652:
+						{
+							position1654, tokenIndex1654 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1655
+							}
+							position++
+							goto l1654
+						l1655:
+							position, tokenIndex = position1654, tokenIndex1654
+							if buffer[position] != rune('E') {
+								goto l1640
+							}
+							position++
+						}
+					l1654:
+						{
+							position1656, tokenIndex1656 := position, tokenIndex
+							if buffer[position] != rune('n') {
+								goto l1657
+							}
+							position++
+							goto l1656
+						l1657:
+							position, tokenIndex = position1656, tokenIndex1656
+							if buffer[position] != rune('N') {
+								goto l1640
+							}
+							position++
+						}
+					l1656:
+						if !_rules[rulesp]() {
+							goto l1640
+						}
+						if !_rules[ruleExpression]() {
+							goto l1640
+						}
+						goto l1641
+					l1640:
+						position, tokenIndex = position1640, tokenIndex1640
+					}
+				l1641:
+					add(rulePegText, position1639)
+				}
+				if !_rules[ruleAction69]() {
+					goto l1637
+				}
+				add(ruleEmitWhen, position1638)
+			}
+			return true
+		l1637:
+			position, tokenIndex = position1637, tokenIndex1637
+			return false
+		},
+		/* 88 StateJoin <- <(<(sp (('j' / 'J') ('o' / 'O') ('i' / 'I') ('n' / 'N')) sp (('s' / 'S') ('t' / 'T') ('a' / 'A') ('t' / 'T') ('e' / 'E')) sp StreamIdentifier (sp (('a' / 'A') ('s' / 'S')) sp Identifier)? sp (('o' / 'O') ('n' / 'N')) sp Expression)> Action70)> */
+		func() bool {
+			position1658, tokenIndex1658 := position, tokenIndex
+			{
+				position1659 := position
+				{
+					position1660 := position
+					if !_rules[rulesp]() {
+						goto l1658
+					}
+					{
+						position1661, tokenIndex1661 := position, tokenIndex
+						if buffer[position] != rune('j') {
+							goto l1662
+						}
+						position++
+						goto l1661
+					l1662:
+						position, tokenIndex = position1661, tokenIndex1661
+						if buffer[position] != rune('J') {
+							goto l1658
+						}
+						position++
+					}
+				l1661:
+					{
+						position1663, tokenIndex1663 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1664
+						}
+						position++
+						goto l1663
+					l1664:
+						position, tokenIndex = position1663, tokenIndex1663
+						if buffer[position] != rune('O') {
+							goto l1658
+						}
+						position++
+					}
+				l1663:
+					{
+						position1665, tokenIndex1665 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1666
+						}
+						position++
+						goto l1665
+					l1666:
+						position, tokenIndex = position1665, tokenIndex1665
+						if buffer[position] != rune('I') {
+							goto l1658
+						}
+						position++
+					}
+				l1665:
+					{
+						position1667, tokenIndex1667 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1668
+						}
+						position++
+						goto l1667
+					l1668:
+						position, tokenIndex = position1667, tokenIndex1667
+						if buffer[position] != rune('N') {
+							goto l1658
+						}
+						position++
+					}
+				l1667:
+					if !_rules[rulesp]() {
+						goto l1658
+					}
+					{
+						position1669, tokenIndex1669 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1670
+						}
+						position++
+						goto l1669
+					l1670:
+						position, tokenIndex = position1669, tokenIndex1669
+						if buffer[position] != rune('S') {
+							goto l1658
+						}
+						position++
+					}
+				l1669:
+					{
+						position1671, tokenIndex1671 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1672
+						}
+						position++
+						goto l1671
+					l1672:
+						position, tokenIndex = position1671, tokenIndex1671
+						if buffer[position] != rune('T') {
+							goto l1658
+						}
+						position++
+					}
+				l1671:
+					{
+						position1673, tokenIndex1673 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1674
+						}
+						position++
+						goto l1673
+					l1674:
+						position, tokenIndex = position1673, tokenIndex1673
+						if buffer[position] != rune('A') {
+							goto l1658
+						}
+						position++
+					}
+				l1673:
+					{
+						position1675, tokenIndex1675 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1676
+						}
+						position++
+						goto l1675
+					l1676:
+						position, tokenIndex = position1675, tokenIndex1675
+						if buffer[position] != rune('T') {
+							goto l1658
+						}
+						position++
+					}
+				l1675:
+					{
+						position1677, tokenIndex1677 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1678
+						}
+						position++
+						goto l1677
+					l1678:
+						position, tokenIndex = position1677, tokenIndex1677
+						if buffer[position] != rune('E') {
+							goto l1658
+						}
+						position++
+					}
+				l1677:
+					if !_rules[rulesp]() {
+						goto l1658
+					}
+					if !_rules[ruleStreamIdentifier]() {
+						goto l1658
+					}
+					{
+						position1679, tokenIndex1679 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1679
+						}
+						{
+							position1681, tokenIndex1681 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l1682
+							}
+							position++
+							goto l1681
+						l1682:
+							position, tokenIndex = position1681, tokenIndex1681
+							if buffer[position] != rune('A') {
+								goto l1679
+							}
+							position++
+						}
+					l1681:
+						{
+							position1683, tokenIndex1683 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l1684
+							}
+							position++
+							goto l1683
+						l1684:
+							position, tokenIndex = position1683, tokenIndex1683
+							if buffer[position] != rune('S') {
+								goto l1679
+							}
+							position++
+						}
+					l1683:
+						if !_rules[rulesp]() {
+							goto l1679
+						}
+						if !_rules[ruleIdentifier]() {
+							goto l1679
+						}
+						goto l1680
+					l1679:
+						position, tokenIndex = position1679, tokenIndex1679
+					}
+				l1680:
+					if !_rules[rulesp]() {
+						goto l1658
+					}
+					{
+						position1685, tokenIndex1685 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1686
+						}
+						position++
+						goto l1685
+					l1686:
+						position, tokenIndex = position1685, tokenIndex1685
+						if buffer[position] != rune('O') {
+							goto l1658
+						}
+						position++
+					}
+				l1685:
+					{
+						position1687, tokenIndex1687 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1688
+						}
+						position++
+						goto l1687
+					l1688:
+						position, tokenIndex = position1687, tokenIndex1687
+						if buffer[position] != rune('N') {
+							goto l1658
+						}
+						position++
+					}
+				l1687:
+					if !_rules[rulesp]() {
+						goto l1658
+					}
+					if !_rules[ruleExpression]() {
+						goto l1658
+					}
+					add(rulePegText, position1660)
+				}
+				if !_rules[ruleAction70]() {
+					goto l1658
+				}
+				add(ruleStateJoin, position1659)
+			}
+			return true
+		l1658:
+			position, tokenIndex = position1658, tokenIndex1658
+			return false
+		},
+		/* 89 RelationLike <- <(AliasedStreamWindow / (StreamWindow Action71))> */
+		func() bool {
+			position1689, tokenIndex1689 := position, tokenIndex
+			{
+				position1690 := position
+				{
+					position1691, tokenIndex1691 := position, tokenIndex
+					if !_rules[ruleAliasedStreamWindow]() {
+						goto l1692
+					}
+					goto l1691
+				l1692:
+					position, tokenIndex = position1691, tokenIndex1691
+					if !_rules[ruleStreamWindow]() {
+						goto l1689
+					}
+					if !_rules[ruleAction71]() {
+						goto l1689
+					}
+				}
+			l1691:
+				add(ruleRelationLike, position1690)
+			}
+			return true
+		l1689:
+			position, tokenIndex = position1689, tokenIndex1689
+			return false
+		},
+		/* 90 AliasedStreamWindow <- <(StreamWindow sp (('a' / 'A') ('s' / 'S')) sp Identifier Action72)> */
+		func() bool {
+			position1693, tokenIndex1693 := position, tokenIndex
+			{
+				position1694 := position
+				if !_rules[ruleStreamWindow]() {
+					goto l1693
+				}
+				if !_rules[rulesp]() {
+					goto l1693
+				}
+				{
+					position1695, tokenIndex1695 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l1696
+					}
+					position++
+					goto l1695
+				l1696:
+					position, tokenIndex = position1695, tokenIndex1695
+					if buffer[position] != rune('A') {
+						goto l1693
+					}
+					position++
+				}
+			l1695:
+				{
+					position1697, tokenIndex1697 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1698
+					}
+					position++
+					goto l1697
+				l1698:
+					position, tokenIndex = position1697, tokenIndex1697
+					if buffer[position] != rune('S') {
+						goto l1693
+					}
+					position++
+				}
+			l1697:
+				if !_rules[rulesp]() {
+					goto l1693
+				}
+				if !_rules[ruleIdentifier]() {
+					goto l1693
+				}
+				if !_rules[ruleAction72]() {
+					goto l1693
+				}
+				add(ruleAliasedStreamWindow, position1694)
+			}
+			return true
+		l1693:
+			position, tokenIndex = position1693, tokenIndex1693
+			return false
+		},
+		/* 91 StreamWindow <- <((StreamLike spOpt '[' spOpt WindowSpec CapacitySpecOpt SheddingSpecOpt spOpt ']' Action73) / (StreamLike Action74))> */
+		func() bool {
+			position1699, tokenIndex1699 := position, tokenIndex
+			{
+				position1700 := position
+				{
+					position1701, tokenIndex1701 := position, tokenIndex
+					if !_rules[ruleStreamLike]() {
+						goto l1702
+					}
+					if !_rules[rulespOpt]() {
+						goto l1702
+					}
+					if buffer[position] != rune('[') {
+						goto l1702
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1702
+					}
+					if !_rules[ruleWindowSpec]() {
+						goto l1702
+					}
+					if !_rules[ruleCapacitySpecOpt]() {
+						goto l1702
+					}
+					if !_rules[ruleSheddingSpecOpt]() {
+						goto l1702
+					}
+					if !_rules[rulespOpt]() {
+						goto l1702
+					}
+					if buffer[position] != rune(']') {
+						goto l1702
+					}
+					position++
+					if !_rules[ruleAction73]() {
+						goto l1702
+					}
+					goto l1701
+				l1702:
+					position, tokenIndex = position1701, tokenIndex1701
+					if !_rules[ruleStreamLike]() {
+						goto l1699
+					}
+					if !_rules[ruleAction74]() {
+						goto l1699
+					}
+				}
+			l1701:
+				add(ruleStreamWindow, position1700)
+			}
+			return true
+		l1699:
+			position, tokenIndex = position1699, tokenIndex1699
+			return false
+		},
+		/* 92 WindowSpec <- <(TumblingWindow / SessionWindow / RangeWindow)> */
+		func() bool {
+			position1703, tokenIndex1703 := position, tokenIndex
+			{
+				position1704 := position
+				{
+					position1705, tokenIndex1705 := position, tokenIndex
+					if !_rules[ruleTumblingWindow]() {
+						goto l1706
+					}
+					goto l1705
+				l1706:
+					position, tokenIndex = position1705, tokenIndex1705
+					if !_rules[ruleSessionWindow]() {
+						goto l1707
+					}
+					goto l1705
+				l1707:
+					position, tokenIndex = position1705, tokenIndex1705
+					if !_rules[ruleRangeWindow]() {
+						goto l1703
+					}
+				}
+			l1705:
+				add(ruleWindowSpec, position1704)
+			}
+			return true
+		l1703:
+			position, tokenIndex = position1703, tokenIndex1703
+			return false
+		},
+		/* 93 RangeWindow <- <(<(('r' / 'R') ('a' / 'A') ('n' / 'N') ('g' / 'G') ('e' / 'E') sp Interval)> Action75)> */
+		func() bool {
+			position1708, tokenIndex1708 := position, tokenIndex
+			{
+				position1709 := position
+				{
+					position1710 := position
+					{
+						position1711, tokenIndex1711 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1712
+						}
+						position++
+						goto l1711
+					l1712:
+						position, tokenIndex = position1711, tokenIndex1711
+						if buffer[position] != rune('R') {
+							goto l1708
+						}
+						position++
+					}
+				l1711:
+					{
+						position1713, tokenIndex1713 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1714
+						}
+						position++
+						goto l1713
+					l1714:
+						position, tokenIndex = position1713, tokenIndex1713
+						if buffer[position] != rune('A') {
+							goto l1708
+						}
+						position++
+					}
+				l1713:
+					{
+						position1715, tokenIndex1715 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1716
+						}
+						position++
+						goto l1715
+					l1716:
+						position, tokenIndex = position1715, tokenIndex1715
+						if buffer[position] != rune('N') {
+							goto l1708
+						}
+						position++
+					}
+				l1715:
+					{
+						position1717, tokenIndex1717 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l1718
+						}
+						position++
+						goto l1717
+					l1718:
+						position, tokenIndex = position1717, tokenIndex1717
+						if buffer[position] != rune('G') {
+							goto l1708
+						}
+						position++
+					}
+				l1717:
+					{
+						position1719, tokenIndex1719 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1720
+						}
+						position++
+						goto l1719
+					l1720:
+						position, tokenIndex = position1719, tokenIndex1719
+						if buffer[position] != rune('E') {
+							goto l1708
+						}
+						position++
+					}
+				l1719:
+					if !_rules[rulesp]() {
+						goto l1708
+					}
+					if !_rules[ruleInterval]() {
+						goto l1708
+					}
+					add(rulePegText, position1710)
+				}
+				if !_rules[ruleAction75]() {
+					goto l1708
+				}
+				add(ruleRangeWindow, position1709)
+			}
+			return true
+		l1708:
+			position, tokenIndex = position1708, tokenIndex1708
+			return false
+		},
+		/* 94 TumblingWindow <- <(<(('t' / 'T') ('u' / 'U') ('m' / 'M') ('b' / 'B') ('l' / 'L') ('i' / 'I') ('n' / 'N') ('g' / 'G') sp Interval ((sp ProcessingTime) / (spOpt ',' spOpt WatermarkDelay))?)> Action76)> */
+		func() bool {
+			position1721, tokenIndex1721 := position, tokenIndex
+			{
+				position1722 := position
+				{
+					position1723 := position
+					{
+						position1724, tokenIndex1724 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1725
+						}
+						position++
+						goto l1724
+					l1725:
+						position, tokenIndex = position1724, tokenIndex1724
+						if buffer[position] != rune('T') {
+							goto l1721
+						}
+						position++
+					}
+				l1724:
+					{
+						position1726, tokenIndex1726 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l1727
+						}
+						position++
+						goto l1726
+					l1727:
+						position, tokenIndex = position1726, tokenIndex1726
+						if buffer[position] != rune('U') {
+							goto l1721
+						}
+						position++
+					}
+				l1726:
+					{
+						position1728, tokenIndex1728 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l1729
+						}
+						position++
+						goto l1728
+					l1729:
+						position, tokenIndex = position1728, tokenIndex1728
+						if buffer[position] != rune('M') {
+							goto l1721
+						}
+						position++
+					}
+				l1728:
+					{
+						position1730, tokenIndex1730 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l1731
+						}
+						position++
+						goto l1730
+					l1731:
+						position, tokenIndex = position1730, tokenIndex1730
+						if buffer[position] != rune('B') {
+							goto l1721
+						}
+						position++
+					}
+				l1730:
+					{
+						position1732, tokenIndex1732 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l1733
+						}
+						position++
+						goto l1732
+					l1733:
+						position, tokenIndex = position1732, tokenIndex1732
+						if buffer[position] != rune('L') {
+							goto l1721
+						}
+						position++
+					}
+				l1732:
+					{
+						position1734, tokenIndex1734 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1735
+						}
+						position++
+						goto l1734
+					l1735:
+						position, tokenIndex = position1734, tokenIndex1734
+						if buffer[position] != rune('I') {
+							goto l1721
+						}
+						position++
+					}
+				l1734:
+					{
+						position1736, tokenIndex1736 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1737
+						}
+						position++
+						goto l1736
+					l1737:
+						position, tokenIndex = position1736, tokenIndex1736
+						if buffer[position] != rune('N') {
+							goto l1721
+						}
+						position++
+					}
+				l1736:
+					{
+						position1738, tokenIndex1738 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l1739
+						}
+						position++
+						goto l1738
+					l1739:
+						position, tokenIndex = position1738, tokenIndex1738
+						if buffer[position] != rune('G') {
+							goto l1721
+						}
+						position++
+					}
+				l1738:
+					if !_rules[rulesp]() {
+						goto l1721
+					}
+					if !_rules[ruleInterval]() {
+						goto l1721
+					}
+					{
+						position1740, tokenIndex1740 := position, tokenIndex
+						{
+							position1742, tokenIndex1742 := position, tokenIndex
+							if !_rules[rulesp]() {
+								goto l1743
+							}
+							if !_rules[ruleProcessingTime]() {
+								goto l1743
+							}
+							goto l1742
+						l1743:
+							position, tokenIndex = position1742, tokenIndex1742
+							if !_rules[rulespOpt]() {
+								goto l1740
+							}
+							if buffer[position] != rune(',') {
+								goto l1740
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l1740
+							}
+							if !_rules[ruleWatermarkDelay]() {
+								goto l1740
+							}
+						}
+					l1742:
+						goto l1741
+					l1740:
+						position, tokenIndex = position1740, tokenIndex1740
+					}
+				l1741:
+					add(rulePegText, position1723)
+				}
+				if !_rules[ruleAction76]() {
+					goto l1721
+				}
+				add(ruleTumblingWindow, position1722)
+			}
+			return true
+		l1721:
+			position, tokenIndex = position1721, tokenIndex1721
+			return false
+		},
+		/* 95 ProcessingTime <- <(<(('b' / 'B') ('y' / 'Y') sp (('p' / 'P') ('r' / 'R') ('o' / 'O') ('c' / 'C') ('e' / 'E') ('s' / 'S') ('s' / 'S') ('i' / 'I') ('n' / 'N') ('g' / 'G')) sp (('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E')))> Action77)> */
+		func() bool {
+			position1744, tokenIndex1744 := position, tokenIndex
+			{
+				position1745 := position
+				{
+					position1746 := position
+					{
+						position1747, tokenIndex1747 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l1748
+						}
+						position++
+						goto l1747
+					l1748:
+						position, tokenIndex = position1747, tokenIndex1747
+						if buffer[position] != rune('B') {
+							goto l1744
+						}
+						position++
+					}
+				l1747:
+					{
+						position1749, tokenIndex1749 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l1750
+						}
+						position++
+						goto l1749
+					l1750:
+						position, tokenIndex = position1749, tokenIndex1749
+						if buffer[position] != rune('Y') {
+							goto l1744
+						}
+						position++
+					}
+				l1749:
+					if !_rules[rulesp]() {
+						goto l1744
+					}
+					{
+						position1751, tokenIndex1751 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l1752
+						}
+						position++
+						goto l1751
+					l1752:
+						position, tokenIndex = position1751, tokenIndex1751
+						if buffer[position] != rune('P') {
+							goto l1744
+						}
+						position++
+					}
+				l1751:
+					{
+						position1753, tokenIndex1753 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1754
+						}
+						position++
+						goto l1753
+					l1754:
+						position, tokenIndex = position1753, tokenIndex1753
+						if buffer[position] != rune('R') {
+							goto l1744
+						}
+						position++
+					}
+				l1753:
+					{
+						position1755, tokenIndex1755 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1756
+						}
+						position++
+						goto l1755
+					l1756:
+						position, tokenIndex = position1755, tokenIndex1755
+						if buffer[position] != rune('O') {
+							goto l1744
+						}
+						position++
+					}
+				l1755:
+					{
+						position1757, tokenIndex1757 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l1758
+						}
+						position++
+						goto l1757
+					l1758:
+						position, tokenIndex = position1757, tokenIndex1757
+						if buffer[position] != rune('C') {
+							goto l1744
+						}
+						position++
+					}
+				l1757:
+					{
+						position1759, tokenIndex1759 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1760
+						}
+						position++
+						goto l1759
+					l1760:
+						position, tokenIndex = position1759, tokenIndex1759
+						if buffer[position] != rune('E') {
+							goto l1744
+						}
+						position++
+					}
+				l1759:
+					{
+						position1761, tokenIndex1761 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1762
+						}
+						position++
+						goto l1761
+					l1762:
+						position, tokenIndex = position1761, tokenIndex1761
+						if buffer[position] != rune('S') {
+							goto l1744
+						}
+						position++
+					}
+				l1761:
+					{
+						position1763, tokenIndex1763 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1764
+						}
+						position++
+						goto l1763
+					l1764:
+						position, tokenIndex = position1763, tokenIndex1763
+						if buffer[position] != rune('S') {
+							goto l1744
+						}
+						position++
+					}
+				l1763:
+					{
+						position1765, tokenIndex1765 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1766
+						}
+						position++
+						goto l1765
+					l1766:
+						position, tokenIndex = position1765, tokenIndex1765
+						if buffer[position] != rune('I') {
+							goto l1744
+						}
+						position++
+					}
+				l1765:
+					{
+						position1767, tokenIndex1767 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1768
+						}
+						position++
+						goto l1767
+					l1768:
+						position, tokenIndex = position1767, tokenIndex1767
+						if buffer[position] != rune('N') {
+							goto l1744
+						}
+						position++
+					}
+				l1767:
+					{
+						position1769, tokenIndex1769 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l1770
+						}
+						position++
+						goto l1769
+					l1770:
+						position, tokenIndex = position1769, tokenIndex1769
+						if buffer[position] != rune('G') {
+							goto l1744
+						}
+						position++
+					}
+				l1769:
+					if !_rules[rulesp]() {
+						goto l1744
+					}
+					{
+						position1771, tokenIndex1771 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1772
+						}
+						position++
+						goto l1771
+					l1772:
+						position, tokenIndex = position1771, tokenIndex1771
+						if buffer[position] != rune('T') {
+							goto l1744
+						}
+						position++
+					}
+				l1771:
+					{
+						position1773, tokenIndex1773 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1774
+						}
+						position++
+						goto l1773
+					l1774:
+						position, tokenIndex = position1773, tokenIndex1773
+						if buffer[position] != rune('I') {
+							goto l1744
+						}
+						position++
+					}
+				l1773:
+					{
+						position1775, tokenIndex1775 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l1776
+						}
+						position++
+						goto l1775
+					l1776:
+						position, tokenIndex = position1775, tokenIndex1775
+						if buffer[position] != rune('M') {
+							goto l1744
+						}
+						position++
+					}
+				l1775:
+					{
+						position1777, tokenIndex1777 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1778
+						}
+						position++
+						goto l1777
+					l1778:
+						position, tokenIndex = position1777, tokenIndex1777
+						if buffer[position] != rune('E') {
+							goto l1744
+						}
+						position++
+					}
+				l1777:
+					add(rulePegText, position1746)
+				}
+				if !_rules[ruleAction77]() {
+					goto l1744
+				}
+				add(ruleProcessingTime, position1745)
+			}
+			return true
+		l1744:
+			position, tokenIndex = position1744, tokenIndex1744
+			return false
+		},
+		/* 96 SessionWindow <- <(<(('r' / 'R') ('a' / 'A') ('n' / 'N') ('g' / 'G') ('e' / 'E') sp (('s' / 'S') ('e' / 'E') ('s' / 'S') ('s' / 'S') ('i' / 'I') ('o' / 'O') ('n' / 'N')) sp TimeInterval sp (('o' / 'O') ('n' / 'N')) sp Expression (sp ProcessingTime)?)> Action78)> */
+		func() bool {
+			position1779, tokenIndex1779 := position, tokenIndex
+			{
+				position1780 := position
+				{
+					position1781 := position
+					{
+						position1782, tokenIndex1782 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1783
+						}
+						position++
+						goto l1782
+					l1783:
+						position, tokenIndex = position1782, tokenIndex1782
+						if buffer[position] != rune('R') {
+							goto l1779
+						}
+						position++
+					}
+				l1782:
+					{
+						position1784, tokenIndex1784 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1785
+						}
+						position++
+						goto l1784
+					l1785:
+						position, tokenIndex = position1784, tokenIndex1784
+						if buffer[position] != rune('A') {
+							goto l1779
+						}
+						position++
+					}
+				l1784:
+					{
+						position1786, tokenIndex1786 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1787
+						}
+						position++
+						goto l1786
+					l1787:
+						position, tokenIndex = position1786, tokenIndex1786
+						if buffer[position] != rune('N') {
+							goto l1779
+						}
+						position++
+					}
+				l1786:
+					{
+						position1788, tokenIndex1788 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l1789
+						}
+						position++
+						goto l1788
+					l1789:
+						position, tokenIndex = position1788, tokenIndex1788
+						if buffer[position] != rune('G') {
+							goto l1779
+						}
+						position++
+					}
+				l1788:
+					{
+						position1790, tokenIndex1790 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1791
+						}
+						position++
+						goto l1790
+					l1791:
+						position, tokenIndex = position1790, tokenIndex1790
+						if buffer[position] != rune('E') {
+							goto l1779
+						}
+						position++
+					}
+				l1790:
+					if !_rules[rulesp]() {
+						goto l1779
+					}
+					{
+						position1792, tokenIndex1792 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1793
+						}
+						position++
+						goto l1792
+					l1793:
+						position, tokenIndex = position1792, tokenIndex1792
+						if buffer[position] != rune('S') {
+							goto l1779
+						}
+						position++
+					}
+				l1792:
+					{
+						position1794, tokenIndex1794 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1795
+						}
+						position++
+						goto l1794
+					l1795:
+						position, tokenIndex = position1794, tokenIndex1794
+						if buffer[position] != rune('E') {
+							goto l1779
+						}
+						position++
+					}
+				l1794:
+					{
+						position1796, tokenIndex1796 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1797
+						}
+						position++
+						goto l1796
+					l1797:
+						position, tokenIndex = position1796, tokenIndex1796
+						if buffer[position] != rune('S') {
+							goto l1779
+						}
+						position++
+					}
+				l1796:
+					{
+						position1798, tokenIndex1798 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1799
+						}
+						position++
+						goto l1798
+					l1799:
+						position, tokenIndex = position1798, tokenIndex1798
+						if buffer[position] != rune('S') {
+							goto l1779
+						}
+						position++
+					}
+				l1798:
+					{
+						position1800, tokenIndex1800 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l1801
+						}
+						position++
+						goto l1800
+					l1801:
+						position, tokenIndex = position1800, tokenIndex1800
+						if buffer[position] != rune('I') {
+							goto l1779
+						}
+						position++
+					}
+				l1800:
+					{
+						position1802, tokenIndex1802 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1803
+						}
+						position++
+						goto l1802
+					l1803:
+						position, tokenIndex = position1802, tokenIndex1802
+						if buffer[position] != rune('O') {
+							goto l1779
+						}
+						position++
+					}
+				l1802:
+					{
+						position1804, tokenIndex1804 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1805
+						}
+						position++
+						goto l1804
+					l1805:
+						position, tokenIndex = position1804, tokenIndex1804
+						if buffer[position] != rune('N') {
+							goto l1779
+						}
+						position++
+					}
+				l1804:
+					if !_rules[rulesp]() {
+						goto l1779
+					}
+					if !_rules[ruleTimeInterval]() {
+						goto l1779
+					}
+					if !_rules[rulesp]() {
+						goto l1779
+					}
+					{
+						position1806, tokenIndex1806 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l1807
+						}
+						position++
+						goto l1806
+					l1807:
+						position, tokenIndex = position1806, tokenIndex1806
+						if buffer[position] != rune('O') {
+							goto l1779
+						}
+						position++
+					}
+				l1806:
+					{
+						position1808, tokenIndex1808 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1809
+						}
+						position++
+						goto l1808
+					l1809:
+						position, tokenIndex = position1808, tokenIndex1808
+						if buffer[position] != rune('N') {
+							goto l1779
+						}
+						position++
+					}
+				l1808:
+					if !_rules[rulesp]() {
+						goto l1779
+					}
+					if !_rules[ruleExpression]() {
+						goto l1779
+					}
+					{
+						position1810, tokenIndex1810 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1810
+						}
+						if !_rules[ruleProcessingTime]() {
+							goto l1810
+						}
+						goto l1811
+					l1810:
+						position, tokenIndex = position1810, tokenIndex1810
+					}
+				l1811:
+					add(rulePegText, position1781)
+				}
+				if !_rules[ruleAction78]() {
+					goto l1779
+				}
+				add(ruleSessionWindow, position1780)
+			}
+			return true
+		l1779:
+			position, tokenIndex = position1779, tokenIndex1779
+			return false
+		},
+		/* 97 WatermarkDelay <- <(<(('w' / 'W') ('a' / 'A') ('t' / 'T') ('e' / 'E') ('r' / 'R') ('m' / 'M') ('a' / 'A') ('r' / 'R') ('k' / 'K') sp (('d' / 'D') ('e' / 'E') ('l' / 'L') ('a' / 'A') ('y' / 'Y')) sp TimeInterval)> Action79)> */
+		func() bool {
+			position1812, tokenIndex1812 := position, tokenIndex
+			{
+				position1813 := position
+				{
+					position1814 := position
+					{
+						position1815, tokenIndex1815 := position, tokenIndex
+						if buffer[position] != rune('w') {
+							goto l1816
+						}
+						position++
+						goto l1815
+					l1816:
+						position, tokenIndex = position1815, tokenIndex1815
+						if buffer[position] != rune('W') {
+							goto l1812
+						}
+						position++
+					}
+				l1815:
+					{
+						position1817, tokenIndex1817 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1818
+						}
+						position++
+						goto l1817
+					l1818:
+						position, tokenIndex = position1817, tokenIndex1817
+						if buffer[position] != rune('A') {
+							goto l1812
+						}
+						position++
+					}
+				l1817:
+					{
+						position1819, tokenIndex1819 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1820
+						}
+						position++
+						goto l1819
+					l1820:
+						position, tokenIndex = position1819, tokenIndex1819
+						if buffer[position] != rune('T') {
+							goto l1812
+						}
+						position++
+					}
+				l1819:
+					{
+						position1821, tokenIndex1821 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1822
+						}
+						position++
+						goto l1821
+					l1822:
+						position, tokenIndex = position1821, tokenIndex1821
+						if buffer[position] != rune('E') {
+							goto l1812
+						}
+						position++
+					}
+				l1821:
+					{
+						position1823, tokenIndex1823 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1824
+						}
+						position++
+						goto l1823
+					l1824:
+						position, tokenIndex = position1823, tokenIndex1823
+						if buffer[position] != rune('R') {
+							goto l1812
+						}
+						position++
+					}
+				l1823:
+					{
+						position1825, tokenIndex1825 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l1826
+						}
+						position++
+						goto l1825
+					l1826:
+						position, tokenIndex = position1825, tokenIndex1825
+						if buffer[position] != rune('M') {
+							goto l1812
+						}
+						position++
+					}
+				l1825:
+					{
+						position1827, tokenIndex1827 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1828
+						}
+						position++
+						goto l1827
+					l1828:
+						position, tokenIndex = position1827, tokenIndex1827
+						if buffer[position] != rune('A') {
+							goto l1812
+						}
+						position++
+					}
+				l1827:
+					{
+						position1829, tokenIndex1829 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l1830
+						}
+						position++
+						goto l1829
+					l1830:
+						position, tokenIndex = position1829, tokenIndex1829
+						if buffer[position] != rune('R') {
+							goto l1812
+						}
+						position++
+					}
+				l1829:
+					{
+						position1831, tokenIndex1831 := position, tokenIndex
+						if buffer[position] != rune('k') {
+							goto l1832
+						}
+						position++
+						goto l1831
+					l1832:
+						position, tokenIndex = position1831, tokenIndex1831
+						if buffer[position] != rune('K') {
+							goto l1812
+						}
+						position++
+					}
+				l1831:
+					if !_rules[rulesp]() {
+						goto l1812
+					}
+					{
+						position1833, tokenIndex1833 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l1834
+						}
+						position++
+						goto l1833
+					l1834:
+						position, tokenIndex = position1833, tokenIndex1833
+						if buffer[position] != rune('D') {
+							goto l1812
+						}
+						position++
+					}
+				l1833:
+					{
+						position1835, tokenIndex1835 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1836
+						}
+						position++
+						goto l1835
+					l1836:
+						position, tokenIndex = position1835, tokenIndex1835
+						if buffer[position] != rune('E') {
+							goto l1812
+						}
+						position++
+					}
+				l1835:
+					{
+						position1837, tokenIndex1837 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l1838
+						}
+						position++
+						goto l1837
+					l1838:
+						position, tokenIndex = position1837, tokenIndex1837
+						if buffer[position] != rune('L') {
+							goto l1812
+						}
+						position++
+					}
+				l1837:
+					{
+						position1839, tokenIndex1839 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l1840
+						}
+						position++
+						goto l1839
+					l1840:
+						position, tokenIndex = position1839, tokenIndex1839
+						if buffer[position] != rune('A') {
+							goto l1812
+						}
+						position++
+					}
+				l1839:
+					{
+						position1841, tokenIndex1841 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l1842
+						}
+						position++
+						goto l1841
+					l1842:
+						position, tokenIndex = position1841, tokenIndex1841
+						if buffer[position] != rune('Y') {
+							goto l1812
+						}
+						position++
+					}
+				l1841:
+					if !_rules[rulesp]() {
+						goto l1812
+					}
+					if !_rules[ruleTimeInterval]() {
+						goto l1812
+					}
+					add(rulePegText, position1814)
+				}
+				if !_rules[ruleAction79]() {
+					goto l1812
+				}
+				add(ruleWatermarkDelay, position1813)
+			}
+			return true
+		l1812:
+			position, tokenIndex = position1812, tokenIndex1812
+			return false
+		},
+		/* 98 StreamLike <- <(UnnestStream / UDSFFuncApp / SystemStream / Stream)> */
+		func() bool {
+			position1843, tokenIndex1843 := position, tokenIndex
+			{
+				position1844 := position
+				{
+					position1845, tokenIndex1845 := position, tokenIndex
+					if !_rules[ruleUnnestStream]() {
+						goto l1846
+					}
+					goto l1845
+				l1846:
+					position, tokenIndex = position1845, tokenIndex1845
+					if !_rules[ruleUDSFFuncApp]() {
+						goto l1847
+					}
+					goto l1845
+				l1847:
+					position, tokenIndex = position1845, tokenIndex1845
+					if !_rules[ruleSystemStream]() {
+						goto l1848
+					}
+					goto l1845
+				l1848:
+					position, tokenIndex = position1845, tokenIndex1845
+					if !_rules[ruleStream]() {
+						goto l1843
+					}
+				}
+			l1845:
+				add(ruleStreamLike, position1844)
+			}
+			return true
+		l1843:
+			position, tokenIndex = position1843, tokenIndex1843
+			return false
+		},
+		/* 99 UnnestStream <- <(<(('u' / 'U') ('n' / 'N') ('n' / 'N') ('e' / 'E') ('s' / 'S') ('t' / 'T') spOpt '(' spOpt Stream spOpt ',' spOpt RowValue spOpt ')')> Action80)> */
+		func() bool {
+			position1849, tokenIndex1849 := position, tokenIndex
+			{
+				position1850 := position
+				{
+					position1851 := position
+					{
+						position1852, tokenIndex1852 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l1853
+						}
+						position++
+						goto l1852
+					l1853:
+						position, tokenIndex = position1852, tokenIndex1852
+						if buffer[position] != rune('U') {
+							goto l1849
+						}
+						position++
+					}
+				l1852:
+					{
+						position1854, tokenIndex1854 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1855
+						}
+						position++
+						goto l1854
+					l1855:
+						position, tokenIndex = position1854, tokenIndex1854
+						if buffer[position] != rune('N') {
+							goto l1849
+						}
+						position++
+					}
+				l1854:
+					{
+						position1856, tokenIndex1856 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l1857
+						}
+						position++
+						goto l1856
+					l1857:
+						position, tokenIndex = position1856, tokenIndex1856
+						if buffer[position] != rune('N') {
+							goto l1849
+						}
+						position++
+					}
+				l1856:
+					{
+						position1858, tokenIndex1858 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l1859
+						}
+						position++
+						goto l1858
+					l1859:
+						position, tokenIndex = position1858, tokenIndex1858
+						if buffer[position] != rune('E') {
+							goto l1849
+						}
+						position++
+					}
+				l1858:
+					{
+						position1860, tokenIndex1860 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l1861
+						}
+						position++
+						goto l1860
+					l1861:
+						position, tokenIndex = position1860, tokenIndex1860
+						if buffer[position] != rune('S') {
+							goto l1849
+						}
+						position++
+					}
+				l1860:
+					{
+						position1862, tokenIndex1862 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l1863
+						}
+						position++
+						goto l1862
+					l1863:
+						position, tokenIndex = position1862, tokenIndex1862
+						if buffer[position] != rune('T') {
+							goto l1849
+						}
+						position++
+					}
+				l1862:
+					if !_rules[rulespOpt]() {
+						goto l1849
+					}
+					if buffer[position] != rune('(') {
+						goto l1849
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1849
+					}
+					if !_rules[ruleStream]() {
+						goto l1849
+					}
+					if !_rules[rulespOpt]() {
+						goto l1849
+					}
+					if buffer[position] != rune(',') {
+						goto l1849
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l1849
+					}
+					if !_rules[ruleRowValue]() {
+						goto l1849
+					}
+					if !_rules[rulespOpt]() {
+						goto l1849
+					}
+					if buffer[position] != rune(')') {
+						goto l1849
+					}
+					position++
+					add(rulePegText, position1851)
+				}
+				if !_rules[ruleAction80]() {
+					goto l1849
+				}
+				add(ruleUnnestStream, position1850)
+			}
+			return true
+		l1849:
+			position, tokenIndex = position1849, tokenIndex1849
+			return false
+		},
+		/* 100 SystemStream <- <(('s' / 'S') ('y' / 'Y') ('s' / 'S') ('t' / 'T') ('e' / 'E') ('m' / 'M') '.' <ident> Action81)> */
+		func() bool {
+			position1864, tokenIndex1864 := position, tokenIndex
+			{
+				position1865 := position
+				{
+					position1866, tokenIndex1866 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1867
+					}
+					position++
+					goto l1866
+				l1867:
+					position, tokenIndex = position1866, tokenIndex1866
+					if buffer[position] != rune('S') {
+						goto l1864
+					}
+					position++
+				}
+			l1866:
+				{
+					position1868, tokenIndex1868 := position, tokenIndex
+					if buffer[position] != rune('y') {
+						goto l1869
+					}
+					position++
+					goto l1868
+				l1869:
+					position, tokenIndex = position1868, tokenIndex1868
+					if buffer[position] != rune('Y') {
+						goto l1864
+					}
+					position++
+				}
+			l1868:
+				{
+					position1870, tokenIndex1870 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l1871
+					}
+					position++
+					goto l1870
+				l1871:
+					position, tokenIndex = position1870, tokenIndex1870
+					if buffer[position] != rune('S') {
+						goto l1864
+					}
+					position++
+				}
+			l1870:
+				{
+					position1872, tokenIndex1872 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l1873
+					}
+					position++
+					goto l1872
+				l1873:
+					position, tokenIndex = position1872, tokenIndex1872
+					if buffer[position] != rune('T') {
+						goto l1864
+					}
+					position++
+				}
+			l1872:
+				{
+					position1874, tokenIndex1874 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l1875
+					}
+					position++
+					goto l1874
+				l1875:
+					position, tokenIndex = position1874, tokenIndex1874
+					if buffer[position] != rune('E') {
+						goto l1864
+					}
+					position++
+				}
+			l1874:
+				{
+					position1876, tokenIndex1876 := position, tokenIndex
+					if buffer[position] != rune('m') {
+						goto l1877
+					}
+					position++
+					goto l1876
+				l1877:
+					position, tokenIndex = position1876, tokenIndex1876
+					if buffer[position] != rune('M') {
+						goto l1864
+					}
+					position++
+				}
+			l1876:
+				if buffer[position] != rune('.') {
+					goto l1864
+				}
+				position++
+				{
+					position1878 := position
+					if !_rules[ruleident]() {
+						goto l1864
+					}
+					add(rulePegText, position1878)
+				}
+				if !_rules[ruleAction81]() {
+					goto l1864
+				}
+				add(ruleSystemStream, position1865)
+			}
+			return true
+		l1864:
+			position, tokenIndex = position1864, tokenIndex1864
+			return false
+		},
+		/* 101 UDSFFuncApp <- <(FuncAppWithoutOrderBy Action82)> */
+		func() bool {
+			position1879, tokenIndex1879 := position, tokenIndex
+			{
+				position1880 := position
+				if !_rules[ruleFuncAppWithoutOrderBy]() {
+					goto l1879
+				}
+				if !_rules[ruleAction82]() {
+					goto l1879
+				}
+				add(ruleUDSFFuncApp, position1880)
+			}
+			return true
+		l1879:
+			position, tokenIndex = position1879, tokenIndex1879
+			return false
+		},
+		/* 102 CapacitySpecOpt <- <(<(spOpt ',' spOpt (('b' / 'B') ('u' / 'U') ('f' / 'F') ('f' / 'F') ('e' / 'E') ('r' / 'R')) sp (('s' / 'S') ('i' / 'I') ('z' / 'Z') ('e' / 'E')) sp NonNegativeNumericLiteral)?> Action83)> */
+		func() bool {
+			position1881, tokenIndex1881 := position, tokenIndex
+			{
+				position1882 := position
+				{
+					position1883 := position
+					{
+						position1884, tokenIndex1884 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l1884
+						}
+						if buffer[position] != rune(',') {
+							goto l1884
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l1884
+						}
+						{
+							position1886, tokenIndex1886 := position, tokenIndex
+							if buffer[position] != rune('b') {
+								goto l1887
+							}
+							position++
+							goto l1886
+						l1887:
+							position, tokenIndex = position1886, tokenIndex1886
+							if buffer[position] != rune('B') {
+								goto l1884
+							}
+							position++
+						}
+					l1886:
+						{
+							position1888, tokenIndex1888 := position, tokenIndex
+							if buffer[position] != rune('u') {
+								goto l1889
+							}
+							position++
+							goto l1888
+						l1889:
+							position, tokenIndex = position1888, tokenIndex1888
+							if buffer[position] != rune('U') {
+								goto l1884
+							}
+							position++
+						}
+					l1888:
+						{
+							position1890, tokenIndex1890 := position, tokenIndex
+							if buffer[position] != rune('f') {
+								goto l1891
+							}
+							position++
+							goto l1890
+						l1891:
+							position, tokenIndex = position1890, tokenIndex1890
+							if buffer[position] != rune('F') {
+								goto l1884
+							}
+							position++
+						}
+					l1890:
+						{
+							position1892, tokenIndex1892 := position, tokenIndex
+							if buffer[position] != rune('f') {
+								goto l1893
+							}
+							position++
+							goto l1892
+						l1893:
+							position, tokenIndex = position1892, tokenIndex1892
+							if buffer[position] != rune('F') {
+								goto l1884
+							}
+							position++
+						}
+					l1892:
+						{
+							position1894, tokenIndex1894 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1895
+							}
+							position++
+							goto l1894
+						l1895:
+							position, tokenIndex = position1894, tokenIndex1894
+							if buffer[position] != rune('E') {
+								goto l1884
+							}
+							position++
+						}
+					l1894:
+						{
+							position1896, tokenIndex1896 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1897
+							}
+							position++
+							goto l1896
+						l1897:
+							position, tokenIndex = position1896, tokenIndex1896
+							if buffer[position] != rune('R') {
+								goto l1884
+							}
+							position++
+						}
+					l1896:
+						if !_rules[rulesp]() {
+							goto l1884
+						}
+						{
+							position1898, tokenIndex1898 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l1899
+							}
+							position++
+							goto l1898
+						l1899:
+							position, tokenIndex = position1898, tokenIndex1898
+							if buffer[position] != rune('S') {
+								goto l1884
+							}
+							position++
+						}
+					l1898:
+						{
+							position1900, tokenIndex1900 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l1901
+							}
+							position++
+							goto l1900
+						l1901:
+							position, tokenIndex = position1900, tokenIndex1900
+							if buffer[position] != rune('I') {
+								goto l1884
+							}
+							position++
+						}
+					l1900:
+						{
+							position1902, tokenIndex1902 := position, tokenIndex
+							if buffer[position] != rune('z') {
+								goto l1903
+							}
+							position++
+							goto l1902
+						l1903:
+							position, tokenIndex = position1902, tokenIndex1902
+							if buffer[position] != rune('Z') {
+								goto l1884
+							}
+							position++
+						}
+					l1902:
+						{
+							position1904, tokenIndex1904 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1905
+							}
+							position++
+							goto l1904
+						l1905:
+							position, tokenIndex = position1904, tokenIndex1904
+							if buffer[position] != rune('E') {
+								goto l1884
+							}
+							position++
+						}
+					l1904:
+						if !_rules[rulesp]() {
+							goto l1884
+						}
+						if !_rules[ruleNonNegativeNumericLiteral]() {
+							goto l1884
+						}
+						goto l1885
+					l1884:
+						position, tokenIndex = position1884, tokenIndex1884
+					}
+				l1885:
+					add(rulePegText, position1883)
+				}
+				if !_rules[ruleAction83]() {
+					goto l1881
+				}
+				add(ruleCapacitySpecOpt, position1882)
+			}
+			return true
+		l1881:
+			position, tokenIndex = position1881, tokenIndex1881
+			return false
+		},
+		/* 103 SheddingSpecOpt <- <(<(spOpt ',' spOpt SheddingOption sp (('i' / 'I') ('f' / 'F')) sp (('f' / 'F') ('u' / 'U') ('l' / 'L') ('l' / 'L')))?> Action84)> */
+		func() bool {
+			position1906, tokenIndex1906 := position, tokenIndex
+			{
+				position1907 := position
+				{
+					position1908 := position
+					{
+						position1909, tokenIndex1909 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l1909
+						}
+						if buffer[position] != rune(',') {
+							goto l1909
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l1909
+						}
+						if !_rules[ruleSheddingOption]() {
+							goto l1909
+						}
+						if !_rules[rulesp]() {
+							goto l1909
+						}
+						{
+							position1911, tokenIndex1911 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l1912
+							}
+							position++
+							goto l1911
+						l1912:
+							position, tokenIndex = position1911, tokenIndex1911
+							if buffer[position] != rune('I') {
+								goto l1909
+							}
+							position++
+						}
+					l1911:
+						{
+							position1913, tokenIndex1913 := position, tokenIndex
+							if buffer[position] != rune('f') {
+								goto l1914
+							}
+							position++
+							goto l1913
+						l1914:
+							position, tokenIndex = position1913, tokenIndex1913
+							if buffer[position] != rune('F') {
+								goto l1909
+							}
+							position++
+						}
+					l1913:
+						if !_rules[rulesp]() {
+							goto l1909
+						}
+						{
+							position1915, tokenIndex1915 := position, tokenIndex
+							if buffer[position] != rune('f') {
+								goto l1916
+							}
+							position++
+							goto l1915
+						l1916:
+							position, tokenIndex = position1915, tokenIndex1915
+							if buffer[position] != rune('F') {
+								goto l1909
+							}
+							position++
+						}
+					l1915:
+						{
+							position1917, tokenIndex1917 := position, tokenIndex
+							if buffer[position] != rune('u') {
+								goto l1918
+							}
+							position++
+							goto l1917
+						l1918:
+							position, tokenIndex = position1917, tokenIndex1917
+							if buffer[position] != rune('U') {
+								goto l1909
+							}
+							position++
+						}
+					l1917:
+						{
+							position1919, tokenIndex1919 := position, tokenIndex
+							if buffer[position] != rune('l') {
+								goto l1920
+							}
+							position++
+							goto l1919
+						l1920:
+							position, tokenIndex = position1919, tokenIndex1919
+							if buffer[position] != rune('L') {
+								goto l1909
+							}
+							position++
+						}
+					l1919:
+						{
+							position1921, tokenIndex1921 := position, tokenIndex
+							if buffer[position] != rune('l') {
+								goto l1922
+							}
+							position++
+							goto l1921
+						l1922:
+							position, tokenIndex = position1921, tokenIndex1921
+							if buffer[position] != rune('L') {
+								goto l1909
+							}
+							position++
+						}
+					l1921:
+						goto l1910
+					l1909:
+						position, tokenIndex = position1909, tokenIndex1909
+					}
+				l1910:
+					add(rulePegText, position1908)
+				}
+				if !_rules[ruleAction84]() {
+					goto l1906
+				}
+				add(ruleSheddingSpecOpt, position1907)
+			}
+			return true
+		l1906:
+			position, tokenIndex = position1906, tokenIndex1906
+			return false
+		},
+		/* 104 SheddingOption <- <(Wait / DropOldest / DropNewest)> */
+		func() bool {
+			position1923, tokenIndex1923 := position, tokenIndex
+			{
+				position1924 := position
+				{
+					position1925, tokenIndex1925 := position, tokenIndex
+					if !_rules[ruleWait]() {
+						goto l1926
+					}
+					goto l1925
+				l1926:
+					position, tokenIndex = position1925, tokenIndex1925
+					if !_rules[ruleDropOldest]() {
+						goto l1927
+					}
+					goto l1925
+				l1927:
+					position, tokenIndex = position1925, tokenIndex1925
+					if !_rules[ruleDropNewest]() {
+						goto l1923
+					}
+				}
+			l1925:
+				add(ruleSheddingOption, position1924)
+			}
+			return true
+		l1923:
+			position, tokenIndex = position1923, tokenIndex1923
+			return false
+		},
+		/* 105 SchemaOpt <- <(<(sp (('s' / 'S') ('c' / 'C') ('h' / 'H') ('e' / 'E') ('m' / 'M') ('a' / 'A')) sp StreamIdentifier)?> Action85)> */
+		func() bool {
+			position1928, tokenIndex1928 := position, tokenIndex
+			{
+				position1929 := position
+				{
+					position1930 := position
+					{
+						position1931, tokenIndex1931 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1931
+						}
+						{
+							position1933, tokenIndex1933 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l1934
+							}
+							position++
+							goto l1933
+						l1934:
+							position, tokenIndex = position1933, tokenIndex1933
+							if buffer[position] != rune('S') {
+								goto l1931
+							}
+							position++
+						}
+					l1933:
+						{
+							position1935, tokenIndex1935 := position, tokenIndex
+							if buffer[position] != rune('c') {
+								goto l1936
+							}
+							position++
+							goto l1935
+						l1936:
+							position, tokenIndex = position1935, tokenIndex1935
+							if buffer[position] != rune('C') {
+								goto l1931
+							}
+							position++
+						}
+					l1935:
+						{
+							position1937, tokenIndex1937 := position, tokenIndex
+							if buffer[position] != rune('h') {
+								goto l1938
+							}
+							position++
+							goto l1937
+						l1938:
+							position, tokenIndex = position1937, tokenIndex1937
+							if buffer[position] != rune('H') {
+								goto l1931
+							}
+							position++
+						}
+					l1937:
+						{
+							position1939, tokenIndex1939 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1940
+							}
+							position++
+							goto l1939
+						l1940:
+							position, tokenIndex = position1939, tokenIndex1939
+							if buffer[position] != rune('E') {
+								goto l1931
+							}
+							position++
+						}
+					l1939:
+						{
+							position1941, tokenIndex1941 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l1942
+							}
+							position++
+							goto l1941
+						l1942:
+							position, tokenIndex = position1941, tokenIndex1941
+							if buffer[position] != rune('M') {
+								goto l1931
+							}
+							position++
+						}
+					l1941:
+						{
+							position1943, tokenIndex1943 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l1944
+							}
+							position++
+							goto l1943
+						l1944:
+							position, tokenIndex = position1943, tokenIndex1943
+							if buffer[position] != rune('A') {
+								goto l1931
+							}
+							position++
+						}
+					l1943:
+						if !_rules[rulesp]() {
+							goto l1931
+						}
+						if !_rules[ruleStreamIdentifier]() {
+							goto l1931
+						}
+						goto l1932
+					l1931:
+						position, tokenIndex = position1931, tokenIndex1931
+					}
+				l1932:
+					add(rulePegText, position1930)
+				}
+				if !_rules[ruleAction85]() {
+					goto l1928
+				}
+				add(ruleSchemaOpt, position1929)
+			}
+			return true
+		l1928:
+			position, tokenIndex = position1928, tokenIndex1928
+			return false
+		},
+		/* 106 InstancesOpt <- <(<(sp (('i' / 'I') ('n' / 'N') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('n' / 'N') ('c' / 'C') ('e' / 'E') ('s' / 'S')) sp NonNegativeNumericLiteral)?> Action86)> */
+		func() bool {
+			position1945, tokenIndex1945 := position, tokenIndex
+			{
+				position1946 := position
+				{
+					position1947 := position
+					{
+						position1948, tokenIndex1948 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1948
+						}
+						{
+							position1950, tokenIndex1950 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l1951
+							}
+							position++
+							goto l1950
+						l1951:
+							position, tokenIndex = position1950, tokenIndex1950
+							if buffer[position] != rune('I') {
+								goto l1948
+							}
+							position++
+						}
+					l1950:
+						{
+							position1952, tokenIndex1952 := position, tokenIndex
+							if buffer[position] != rune('n') {
+								goto l1953
+							}
+							position++
+							goto l1952
+						l1953:
+							position, tokenIndex = position1952, tokenIndex1952
+							if buffer[position] != rune('N') {
+								goto l1948
+							}
+							position++
+						}
+					l1952:
+						{
+							position1954, tokenIndex1954 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l1955
+							}
+							position++
+							goto l1954
+						l1955:
+							position, tokenIndex = position1954, tokenIndex1954
+							if buffer[position] != rune('S') {
+								goto l1948
+							}
+							position++
+						}
+					l1954:
+						{
+							position1956, tokenIndex1956 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l1957
+							}
+							position++
+							goto l1956
+						l1957:
+							position, tokenIndex = position1956, tokenIndex1956
+							if buffer[position] != rune('T') {
+								goto l1948
+							}
+							position++
+						}
+					l1956:
+						{
+							position1958, tokenIndex1958 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l1959
+							}
+							position++
+							goto l1958
+						l1959:
+							position, tokenIndex = position1958, tokenIndex1958
+							if buffer[position] != rune('A') {
+								goto l1948
+							}
+							position++
+						}
+					l1958:
+						{
+							position1960, tokenIndex1960 := position, tokenIndex
+							if buffer[position] != rune('n') {
+								goto l1961
+							}
+							position++
+							goto l1960
+						l1961:
+							position, tokenIndex = position1960, tokenIndex1960
+							if buffer[position] != rune('N') {
+								goto l1948
+							}
+							position++
+						}
+					l1960:
+						{
+							position1962, tokenIndex1962 := position, tokenIndex
+							if buffer[position] != rune('c') {
+								goto l1963
+							}
+							position++
+							goto l1962
+						l1963:
+							position, tokenIndex = position1962, tokenIndex1962
+							if buffer[position] != rune('C') {
+								goto l1948
+							}
+							position++
+						}
+					l1962:
+						{
+							position1964, tokenIndex1964 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1965
+							}
+							position++
+							goto l1964
+						l1965:
+							position, tokenIndex = position1964, tokenIndex1964
+							if buffer[position] != rune('E') {
+								goto l1948
+							}
+							position++
+						}
+					l1964:
+						{
+							position1966, tokenIndex1966 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l1967
+							}
+							position++
+							goto l1966
+						l1967:
+							position, tokenIndex = position1966, tokenIndex1966
+							if buffer[position] != rune('S') {
+								goto l1948
+							}
+							position++
+						}
+					l1966:
+						if !_rules[rulesp]() {
+							goto l1948
+						}
+						if !_rules[ruleNonNegativeNumericLiteral]() {
+							goto l1948
+						}
+						goto l1949
+					l1948:
+						position, tokenIndex = position1948, tokenIndex1948
+					}
+				l1949:
+					add(rulePegText, position1947)
+				}
+				if !_rules[ruleAction86]() {
+					goto l1945
+				}
+				add(ruleInstancesOpt, position1946)
+			}
+			return true
+		l1945:
+			position, tokenIndex = position1945, tokenIndex1945
+			return false
+		},
+		/* 107 SinkOrderingOpt <- <(<(sp (('o' / 'O') ('r' / 'R') ('d' / 'D') ('e' / 'E') ('r' / 'R')) sp (('b' / 'B') ('y' / 'Y')) sp SinkOrdering (sp (('b' / 'B') ('u' / 'U') ('f' / 'F') ('f' / 'F') ('e' / 'E') ('r' / 'R')) sp (('s' / 'S') ('i' / 'I') ('z' / 'Z') ('e' / 'E')) sp NonNegativeNumericLiteral)?)?> Action87)> */
+		func() bool {
+			position1968, tokenIndex1968 := position, tokenIndex
+			{
+				position1969 := position
+				{
+					position1970 := position
+					{
+						position1971, tokenIndex1971 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l1971
+						}
+						{
+							position1973, tokenIndex1973 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l1974
+							}
+							position++
+							goto l1973
+						l1974:
+							position, tokenIndex = position1973, tokenIndex1973
+							if buffer[position] != rune('O') {
+								goto l1971
+							}
+							position++
+						}
+					l1973:
+						{
+							position1975, tokenIndex1975 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1976
+							}
+							position++
+							goto l1975
+						l1976:
+							position, tokenIndex = position1975, tokenIndex1975
+							if buffer[position] != rune('R') {
+								goto l1971
+							}
+							position++
+						}
+					l1975:
+						{
+							position1977, tokenIndex1977 := position, tokenIndex
+							if buffer[position] != rune('d') {
+								goto l1978
+							}
+							position++
+							goto l1977
+						l1978:
+							position, tokenIndex = position1977, tokenIndex1977
+							if buffer[position] != rune('D') {
+								goto l1971
+							}
+							position++
+						}
+					l1977:
+						{
+							position1979, tokenIndex1979 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l1980
+							}
+							position++
+							goto l1979
+						l1980:
+							position, tokenIndex = position1979, tokenIndex1979
+							if buffer[position] != rune('E') {
+								goto l1971
+							}
+							position++
+						}
+					l1979:
+						{
+							position1981, tokenIndex1981 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l1982
+							}
+							position++
+							goto l1981
+						l1982:
+							position, tokenIndex = position1981, tokenIndex1981
+							if buffer[position] != rune('R') {
+								goto l1971
+							}
+							position++
+						}
+					l1981:
+						if !_rules[rulesp]() {
+							goto l1971
+						}
+						{
+							position1983, tokenIndex1983 := position, tokenIndex
+							if buffer[position] != rune('b') {
+								goto l1984
+							}
+							position++
+							goto l1983
+						l1984:
+							position, tokenIndex = position1983, tokenIndex1983
+							if buffer[position] != rune('B') {
+								goto l1971
+							}
+							position++
+						}
+					l1983:
+						{
+							position1985, tokenIndex1985 := position, tokenIndex
+							if buffer[position] != rune('y') {
+								goto l1986
+							}
+							position++
+							goto l1985
+						l1986:
+							position, tokenIndex = position1985, tokenIndex1985
+							if buffer[position] != rune('Y') {
+								goto l1971
+							}
+							position++
+						}
+					l1985:
+						if !_rules[rulesp]() {
+							goto l1971
+						}
+						if !_rules[ruleSinkOrdering]() {
+							goto l1971
+						}
+						{
+							position1987, tokenIndex1987 := position, tokenIndex
+							if !_rules[rulesp]() {
+								goto l1987
+							}
+							{
+								position1989, tokenIndex1989 := position, tokenIndex
+								if buffer[position] != rune('b') {
+									goto l1990
+								}
+								position++
+								goto l1989
+							l1990:
+								position, tokenIndex = position1989, tokenIndex1989
+								if buffer[position] != rune('B') {
+									goto l1987
+								}
+								position++
+							}
+						l1989:
+							{
+								position1991, tokenIndex1991 := position, tokenIndex
+								if buffer[position] != rune('u') {
+									goto l1992
+								}
+								position++
+								goto l1991
+							l1992:
+								position, tokenIndex = position1991, tokenIndex1991
+								if buffer[position] != rune('U') {
+									goto l1987
+								}
+								position++
+							}
+						l1991:
+							{
+								position1993, tokenIndex1993 := position, tokenIndex
+								if buffer[position] != rune('f') {
+									goto l1994
+								}
+								position++
+								goto l1993
+							l1994:
+								position, tokenIndex = position1993, tokenIndex1993
+								if buffer[position] != rune('F') {
+									goto l1987
+								}
+								position++
+							}
+						l1993:
+							{
+								position1995, tokenIndex1995 := position, tokenIndex
+								if buffer[position] != rune('f') {
+									goto l1996
+								}
+								position++
+								goto l1995
+							l1996:
+								position, tokenIndex = position1995, tokenIndex1995
+								if buffer[position] != rune('F') {
+									goto l1987
+								}
+								position++
+							}
+						l1995:
+							{
+								position1997, tokenIndex1997 := position, tokenIndex
+								if buffer[position] != rune('e') {
+									goto l1998
+								}
+								position++
+								goto l1997
+							l1998:
+								position, tokenIndex = position1997, tokenIndex1997
+								if buffer[position] != rune('E') {
+									goto l1987
+								}
+								position++
+							}
+						l1997:
+							{
+								position1999, tokenIndex1999 := position, tokenIndex
+								if buffer[position] != rune('r') {
+									goto l2000
+								}
+								position++
+								goto l1999
+							l2000:
+								position, tokenIndex = position1999, tokenIndex1999
+								if buffer[position] != rune('R') {
+									goto l1987
+								}
+								position++
+							}
+						l1999:
+							if !_rules[rulesp]() {
+								goto l1987
+							}
+							{
+								position2001, tokenIndex2001 := position, tokenIndex
+								if buffer[position] != rune('s') {
+									goto l2002
+								}
+								position++
+								goto l2001
+							l2002:
+								position, tokenIndex = position2001, tokenIndex2001
+								if buffer[position] != rune('S') {
+									goto l1987
+								}
+								position++
+							}
+						l2001:
+							{
+								position2003, tokenIndex2003 := position, tokenIndex
+								if buffer[position] != rune('i') {
+									goto l2004
+								}
+								position++
+								goto l2003
+							l2004:
+								position, tokenIndex = position2003, tokenIndex2003
+								if buffer[position] != rune('I') {
+									goto l1987
+								}
+								position++
+							}
+						l2003:
+							{
+								position2005, tokenIndex2005 := position, tokenIndex
+								if buffer[position] != rune('z') {
+									goto l2006
+								}
+								position++
+								goto l2005
+							l2006:
+								position, tokenIndex = position2005, tokenIndex2005
+								if buffer[position] != rune('Z') {
+									goto l1987
+								}
+								position++
+							}
+						l2005:
+							{
+								position2007, tokenIndex2007 := position, tokenIndex
+								if buffer[position] != rune('e') {
+									goto l2008
+								}
+								position++
+								goto l2007
+							l2008:
+								position, tokenIndex = position2007, tokenIndex2007
+								if buffer[position] != rune('E') {
+									goto l1987
+								}
+								position++
+							}
+						l2007:
+							if !_rules[rulesp]() {
+								goto l1987
+							}
+							if !_rules[ruleNonNegativeNumericLiteral]() {
+								goto l1987
+							}
+							goto l1988
+						l1987:
+							position, tokenIndex = position1987, tokenIndex1987
+						}
+					l1988:
+						goto l1972
+					l1971:
+						position, tokenIndex = position1971, tokenIndex1971
+					}
+				l1972:
+					add(rulePegText, position1970)
+				}
+				if !_rules[ruleAction87]() {
+					goto l1968
+				}
+				add(ruleSinkOrderingOpt, position1969)
+			}
+			return true
+		l1968:
+			position, tokenIndex = position1968, tokenIndex1968
+			return false
+		},
+		/* 108 SinkOrdering <- <(ArrivalOrder / TimestampOrder / RoundRobinOrder)> */
+		func() bool {
+			position2009, tokenIndex2009 := position, tokenIndex
+			{
+				position2010 := position
+				{
+					position2011, tokenIndex2011 := position, tokenIndex
+					if !_rules[ruleArrivalOrder]() {
+						goto l2012
+					}
+					goto l2011
+				l2012:
+					position, tokenIndex = position2011, tokenIndex2011
+					if !_rules[ruleTimestampOrder]() {
+						goto l2013
+					}
+					goto l2011
+				l2013:
+					position, tokenIndex = position2011, tokenIndex2011
+					if !_rules[ruleRoundRobinOrder]() {
+						goto l2009
+					}
+				}
+			l2011:
+				add(ruleSinkOrdering, position2010)
+			}
+			return true
+		l2009:
+			position, tokenIndex = position2009, tokenIndex2009
+			return false
+		},
+		/* 109 TimestampByOpt <- <(<(sp (('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('m' / 'M') ('p' / 'P')) sp (('b' / 'B') ('y' / 'Y')) sp TimestampField (sp (('f' / 'F') ('o' / 'O') ('r' / 'R') ('m' / 'M') ('a' / 'A') ('t' / 'T')) sp StringLiteral)?)?> Action88)> */
+		func() bool {
+			position2014, tokenIndex2014 := position, tokenIndex
+			{
+				position2015 := position
+				{
+					position2016 := position
+					{
+						position2017, tokenIndex2017 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2017
+						}
+						{
+							position2019, tokenIndex2019 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2020
+							}
+							position++
+							goto l2019
+						l2020:
+							position, tokenIndex = position2019, tokenIndex2019
+							if buffer[position] != rune('T') {
+								goto l2017
+							}
+							position++
+						}
+					l2019:
+						{
+							position2021, tokenIndex2021 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l2022
+							}
+							position++
+							goto l2021
+						l2022:
+							position, tokenIndex = position2021, tokenIndex2021
+							if buffer[position] != rune('I') {
+								goto l2017
+							}
+							position++
+						}
+					l2021:
+						{
+							position2023, tokenIndex2023 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l2024
+							}
+							position++
+							goto l2023
+						l2024:
+							position, tokenIndex = position2023, tokenIndex2023
+							if buffer[position] != rune('M') {
+								goto l2017
+							}
+							position++
+						}
+					l2023:
+						{
+							position2025, tokenIndex2025 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2026
+							}
+							position++
+							goto l2025
+						l2026:
+							position, tokenIndex = position2025, tokenIndex2025
+							if buffer[position] != rune('E') {
+								goto l2017
+							}
+							position++
+						}
+					l2025:
+						{
+							position2027, tokenIndex2027 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l2028
+							}
+							position++
+							goto l2027
+						l2028:
+							position, tokenIndex = position2027, tokenIndex2027
+							if buffer[position] != rune('S') {
+								goto l2017
+							}
+							position++
+						}
+					l2027:
+						{
+							position2029, tokenIndex2029 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2030
+							}
+							position++
+							goto l2029
+						l2030:
+							position, tokenIndex = position2029, tokenIndex2029
+							if buffer[position] != rune('T') {
+								goto l2017
+							}
+							position++
+						}
+					l2029:
+						{
+							position2031, tokenIndex2031 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l2032
+							}
+							position++
+							goto l2031
+						l2032:
+							position, tokenIndex = position2031, tokenIndex2031
+							if buffer[position] != rune('A') {
+								goto l2017
+							}
+							position++
+						}
+					l2031:
+						{
+							position2033, tokenIndex2033 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l2034
+							}
+							position++
+							goto l2033
+						l2034:
+							position, tokenIndex = position2033, tokenIndex2033
+							if buffer[position] != rune('M') {
+								goto l2017
+							}
+							position++
+						}
+					l2033:
+						{
+							position2035, tokenIndex2035 := position, tokenIndex
+							if buffer[position] != rune('p') {
+								goto l2036
+							}
+							position++
+							goto l2035
+						l2036:
+							position, tokenIndex = position2035, tokenIndex2035
+							if buffer[position] != rune('P') {
+								goto l2017
+							}
+							position++
+						}
+					l2035:
+						if !_rules[rulesp]() {
+							goto l2017
+						}
+						{
+							position2037, tokenIndex2037 := position, tokenIndex
+							if buffer[position] != rune('b') {
+								goto l2038
+							}
+							position++
+							goto l2037
+						l2038:
+							position, tokenIndex = position2037, tokenIndex2037
+							if buffer[position] != rune('B') {
+								goto l2017
+							}
+							position++
+						}
+					l2037:
+						{
+							position2039, tokenIndex2039 := position, tokenIndex
+							if buffer[position] != rune('y') {
+								goto l2040
+							}
+							position++
+							goto l2039
+						l2040:
+							position, tokenIndex = position2039, tokenIndex2039
+							if buffer[position] != rune('Y') {
+								goto l2017
+							}
+							position++
+						}
+					l2039:
+						if !_rules[rulesp]() {
+							goto l2017
+						}
+						if !_rules[ruleTimestampField]() {
+							goto l2017
+						}
+						{
+							position2041, tokenIndex2041 := position, tokenIndex
+							if !_rules[rulesp]() {
+								goto l2041
+							}
+							{
+								position2043, tokenIndex2043 := position, tokenIndex
+								if buffer[position] != rune('f') {
+									goto l2044
+								}
+								position++
+								goto l2043
+							l2044:
+								position, tokenIndex = position2043, tokenIndex2043
+								if buffer[position] != rune('F') {
+									goto l2041
+								}
+								position++
+							}
+						l2043:
+							{
+								position2045, tokenIndex2045 := position, tokenIndex
+								if buffer[position] != rune('o') {
+									goto l2046
+								}
+								position++
+								goto l2045
+							l2046:
+								position, tokenIndex = position2045, tokenIndex2045
+								if buffer[position] != rune('O') {
+									goto l2041
+								}
+								position++
+							}
+						l2045:
+							{
+								position2047, tokenIndex2047 := position, tokenIndex
+								if buffer[position] != rune('r') {
+									goto l2048
+								}
+								position++
+								goto l2047
+							l2048:
+								position, tokenIndex = position2047, tokenIndex2047
+								if buffer[position] != rune('R') {
+									goto l2041
+								}
+								position++
+							}
+						l2047:
+							{
+								position2049, tokenIndex2049 := position, tokenIndex
+								if buffer[position] != rune('m') {
+									goto l2050
+								}
+								position++
+								goto l2049
+							l2050:
+								position, tokenIndex = position2049, tokenIndex2049
+								if buffer[position] != rune('M') {
+									goto l2041
+								}
+								position++
+							}
+						l2049:
+							{
+								position2051, tokenIndex2051 := position, tokenIndex
+								if buffer[position] != rune('a') {
+									goto l2052
+								}
+								position++
+								goto l2051
+							l2052:
+								position, tokenIndex = position2051, tokenIndex2051
+								if buffer[position] != rune('A') {
+									goto l2041
+								}
+								position++
+							}
+						l2051:
+							{
+								position2053, tokenIndex2053 := position, tokenIndex
+								if buffer[position] != rune('t') {
+									goto l2054
+								}
+								position++
+								goto l2053
+							l2054:
+								position, tokenIndex = position2053, tokenIndex2053
+								if buffer[position] != rune('T') {
+									goto l2041
+								}
+								position++
+							}
+						l2053:
+							if !_rules[rulesp]() {
+								goto l2041
+							}
+							if !_rules[ruleStringLiteral]() {
+								goto l2041
+							}
+							goto l2042
+						l2041:
+							position, tokenIndex = position2041, tokenIndex2041
+						}
+					l2042:
+						goto l2018
+					l2017:
+						position, tokenIndex = position2017, tokenIndex2017
+					}
+				l2018:
+					add(rulePegText, position2016)
+				}
+				if !_rules[ruleAction88]() {
+					goto l2014
+				}
+				add(ruleTimestampByOpt, position2015)
+			}
+			return true
+		l2014:
+			position, tokenIndex = position2014, tokenIndex2014
+			return false
+		},
+		/* 110 TimestampField <- <(<jsonGetPath> Action89)> */
+		func() bool {
+			position2055, tokenIndex2055 := position, tokenIndex
+			{
+				position2056 := position
+				{
+					position2057 := position
+					if !_rules[rulejsonGetPath]() {
+						goto l2055
+					}
+					add(rulePegText, position2057)
+				}
+				if !_rules[ruleAction89]() {
+					goto l2055
+				}
+				add(ruleTimestampField, position2056)
+			}
+			return true
+		l2055:
+			position, tokenIndex = position2055, tokenIndex2055
+			return false
+		},
+		/* 111 OnErrorOpt <- <(<(sp (('o' / 'O') ('n' / 'N')) sp (('e' / 'E') ('r' / 'R') ('r' / 'R') ('o' / 'O') ('r' / 'R')) sp ErrorPolicy)?> Action90)> */
+		func() bool {
+			position2058, tokenIndex2058 := position, tokenIndex
+			{
+				position2059 := position
+				{
+					position2060 := position
+					{
+						position2061, tokenIndex2061 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2061
+						}
+						{
+							position2063, tokenIndex2063 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l2064
+							}
+							position++
+							goto l2063
+						l2064:
+							position, tokenIndex = position2063, tokenIndex2063
+							if buffer[position] != rune('O') {
+								goto l2061
+							}
+							position++
+						}
+					l2063:
+						{
+							position2065, tokenIndex2065 := position, tokenIndex
+							if buffer[position] != rune('n') {
+								goto l2066
+							}
+							position++
+							goto l2065
+						l2066:
+							position, tokenIndex = position2065, tokenIndex2065
+							if buffer[position] != rune('N') {
+								goto l2061
+							}
+							position++
+						}
+					l2065:
+						if !_rules[rulesp]() {
+							goto l2061
+						}
+						{
+							position2067, tokenIndex2067 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2068
+							}
+							position++
+							goto l2067
+						l2068:
+							position, tokenIndex = position2067, tokenIndex2067
+							if buffer[position] != rune('E') {
+								goto l2061
+							}
+							position++
+						}
+					l2067:
+						{
+							position2069, tokenIndex2069 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l2070
+							}
+							position++
+							goto l2069
+						l2070:
+							position, tokenIndex = position2069, tokenIndex2069
+							if buffer[position] != rune('R') {
+								goto l2061
+							}
+							position++
+						}
+					l2069:
+						{
+							position2071, tokenIndex2071 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l2072
+							}
+							position++
+							goto l2071
+						l2072:
+							position, tokenIndex = position2071, tokenIndex2071
+							if buffer[position] != rune('R') {
+								goto l2061
+							}
+							position++
+						}
+					l2071:
+						{
+							position2073, tokenIndex2073 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l2074
+							}
+							position++
+							goto l2073
+						l2074:
+							position, tokenIndex = position2073, tokenIndex2073
+							if buffer[position] != rune('O') {
+								goto l2061
+							}
+							position++
+						}
+					l2073:
+						{
+							position2075, tokenIndex2075 := position, tokenIndex
+							if buffer[position] != rune('r') {
+								goto l2076
+							}
+							position++
+							goto l2075
+						l2076:
+							position, tokenIndex = position2075, tokenIndex2075
+							if buffer[position] != rune('R') {
+								goto l2061
+							}
+							position++
+						}
+					l2075:
+						if !_rules[rulesp]() {
+							goto l2061
+						}
+						if !_rules[ruleErrorPolicy]() {
+							goto l2061
+						}
+						goto l2062
+					l2061:
+						position, tokenIndex = position2061, tokenIndex2061
+					}
+				l2062:
+					add(rulePegText, position2060)
+				}
+				if !_rules[ruleAction90]() {
+					goto l2058
+				}
+				add(ruleOnErrorOpt, position2059)
+			}
+			return true
+		l2058:
+			position, tokenIndex = position2058, tokenIndex2058
+			return false
+		},
+		/* 112 TimeoutOpt <- <(<(sp (('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ('o' / 'O') ('u' / 'U') ('t' / 'T')) sp TimeInterval)?> Action91)> */
+		func() bool {
+			position2077, tokenIndex2077 := position, tokenIndex
+			{
+				position2078 := position
+				{
+					position2079 := position
+					{
+						position2080, tokenIndex2080 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2080
+						}
+						{
+							position2082, tokenIndex2082 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2083
+							}
+							position++
+							goto l2082
+						l2083:
+							position, tokenIndex = position2082, tokenIndex2082
+							if buffer[position] != rune('T') {
+								goto l2080
+							}
+							position++
+						}
+					l2082:
+						{
+							position2084, tokenIndex2084 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l2085
+							}
+							position++
+							goto l2084
+						l2085:
+							position, tokenIndex = position2084, tokenIndex2084
+							if buffer[position] != rune('I') {
+								goto l2080
+							}
+							position++
+						}
+					l2084:
+						{
+							position2086, tokenIndex2086 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l2087
+							}
+							position++
+							goto l2086
+						l2087:
+							position, tokenIndex = position2086, tokenIndex2086
+							if buffer[position] != rune('M') {
+								goto l2080
+							}
+							position++
+						}
+					l2086:
+						{
+							position2088, tokenIndex2088 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2089
+							}
+							position++
+							goto l2088
+						l2089:
+							position, tokenIndex = position2088, tokenIndex2088
+							if buffer[position] != rune('E') {
+								goto l2080
+							}
+							position++
+						}
+					l2088:
+						{
+							position2090, tokenIndex2090 := position, tokenIndex
+							if buffer[position] != rune('o') {
+								goto l2091
+							}
+							position++
+							goto l2090
+						l2091:
+							position, tokenIndex = position2090, tokenIndex2090
+							if buffer[position] != rune('O') {
+								goto l2080
+							}
+							position++
+						}
+					l2090:
+						{
+							position2092, tokenIndex2092 := position, tokenIndex
+							if buffer[position] != rune('u') {
+								goto l2093
+							}
+							position++
+							goto l2092
+						l2093:
+							position, tokenIndex = position2092, tokenIndex2092
+							if buffer[position] != rune('U') {
+								goto l2080
+							}
+							position++
+						}
+					l2092:
+						{
+							position2094, tokenIndex2094 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2095
+							}
+							position++
+							goto l2094
+						l2095:
+							position, tokenIndex = position2094, tokenIndex2094
+							if buffer[position] != rune('T') {
+								goto l2080
+							}
+							position++
+						}
+					l2094:
+						if !_rules[rulesp]() {
+							goto l2080
+						}
+						if !_rules[ruleTimeInterval]() {
+							goto l2080
+						}
+						goto l2081
+					l2080:
+						position, tokenIndex = position2080, tokenIndex2080
+					}
+				l2081:
+					add(rulePegText, position2079)
+				}
+				if !_rules[ruleAction91]() {
+					goto l2077
+				}
+				add(ruleTimeoutOpt, position2078)
+			}
+			return true
+		l2077:
+			position, tokenIndex = position2077, tokenIndex2077
+			return false
+		},
+		/* 113 ErrorPolicy <- <(DropOnError / StopOnError / DLQOnError / (RetryOnError (sp NonNegativeNumericLiteral sp (('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ('s' / 'S')))?))> */
+		func() bool {
+			position2096, tokenIndex2096 := position, tokenIndex
+			{
+				position2097 := position
+				{
+					position2098, tokenIndex2098 := position, tokenIndex
+					if !_rules[ruleDropOnError]() {
+						goto l2099
+					}
+					goto l2098
+				l2099:
+					position, tokenIndex = position2098, tokenIndex2098
+					if !_rules[ruleStopOnError]() {
+						goto l2100
+					}
+					goto l2098
+				l2100:
+					position, tokenIndex = position2098, tokenIndex2098
+					if !_rules[ruleDLQOnError]() {
+						goto l2101
+					}
+					goto l2098
+				l2101:
+					position, tokenIndex = position2098, tokenIndex2098
+					if !_rules[ruleRetryOnError]() {
+						goto l2096
+					}
+					{
+						position2102, tokenIndex2102 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2102
+						}
+						if !_rules[ruleNonNegativeNumericLiteral]() {
+							goto l2102
+						}
+						if !_rules[rulesp]() {
+							goto l2102
+						}
+						{
+							position2104, tokenIndex2104 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2105
+							}
+							position++
+							goto l2104
+						l2105:
+							position, tokenIndex = position2104, tokenIndex2104
+							if buffer[position] != rune('T') {
+								goto l2102
+							}
+							position++
+						}
+					l2104:
+						{
+							position2106, tokenIndex2106 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l2107
+							}
+							position++
+							goto l2106
+						l2107:
+							position, tokenIndex = position2106, tokenIndex2106
+							if buffer[position] != rune('I') {
+								goto l2102
+							}
+							position++
+						}
+					l2106:
+						{
+							position2108, tokenIndex2108 := position, tokenIndex
+							if buffer[position] != rune('m') {
+								goto l2109
+							}
+							position++
+							goto l2108
+						l2109:
+							position, tokenIndex = position2108, tokenIndex2108
+							if buffer[position] != rune('M') {
+								goto l2102
+							}
+							position++
+						}
+					l2108:
+						{
+							position2110, tokenIndex2110 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2111
+							}
+							position++
+							goto l2110
+						l2111:
+							position, tokenIndex = position2110, tokenIndex2110
+							if buffer[position] != rune('E') {
+								goto l2102
+							}
+							position++
+						}
+					l2110:
+						{
+							position2112, tokenIndex2112 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l2113
+							}
+							position++
+							goto l2112
+						l2113:
+							position, tokenIndex = position2112, tokenIndex2112
+							if buffer[position] != rune('S') {
+								goto l2102
+							}
+							position++
+						}
+					l2112:
+						goto l2103
+					l2102:
+						position, tokenIndex = position2102, tokenIndex2102
+					}
+				l2103:
+				}
+			l2098:
+				add(ruleErrorPolicy, position2097)
+			}
+			return true
+		l2096:
+			position, tokenIndex = position2096, tokenIndex2096
+			return false
+		},
+		/* 114 SourceSinkSpecs <- <(<(sp (('w' / 'W') ('i' / 'I') ('t' / 'T') ('h' / 'H')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)?> Action92)> */
+		func() bool {
+			position2114, tokenIndex2114 := position, tokenIndex
+			{
+				position2115 := position
+				{
+					position2116 := position
+					{
+						position2117, tokenIndex2117 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2117
+						}
+						{
+							position2119, tokenIndex2119 := position, tokenIndex
+							if buffer[position] != rune('w') {
+								goto l2120
+							}
+							position++
+							goto l2119
+						l2120:
+							position, tokenIndex = position2119, tokenIndex2119
+							if buffer[position] != rune('W') {
+								goto l2117
+							}
+							position++
+						}
+					l2119:
+						{
+							position2121, tokenIndex2121 := position, tokenIndex
+							if buffer[position] != rune('i') {
+								goto l2122
+							}
+							position++
+							goto l2121
+						l2122:
+							position, tokenIndex = position2121, tokenIndex2121
+							if buffer[position] != rune('I') {
+								goto l2117
+							}
+							position++
+						}
+					l2121:
+						{
+							position2123, tokenIndex2123 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2124
+							}
+							position++
+							goto l2123
+						l2124:
+							position, tokenIndex = position2123, tokenIndex2123
+							if buffer[position] != rune('T') {
+								goto l2117
+							}
+							position++
+						}
+					l2123:
+						{
+							position2125, tokenIndex2125 := position, tokenIndex
+							if buffer[position] != rune('h') {
+								goto l2126
+							}
+							position++
+							goto l2125
+						l2126:
+							position, tokenIndex = position2125, tokenIndex2125
+							if buffer[position] != rune('H') {
+								goto l2117
+							}
+							position++
+						}
+					l2125:
+						if !_rules[rulesp]() {
+							goto l2117
+						}
+						if !_rules[ruleSourceSinkParam]() {
+							goto l2117
+						}
+					l2127:
+						{
+							position2128, tokenIndex2128 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l2128
+							}
+							if buffer[position] != rune(',') {
+								goto l2128
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l2128
+							}
+							if !_rules[ruleSourceSinkParam]() {
+								goto l2128
+							}
+							goto l2127
+						l2128:
+							position, tokenIndex = position2128, tokenIndex2128
+						}
+						goto l2118
+					l2117:
+						position, tokenIndex = position2117, tokenIndex2117
+					}
+				l2118:
+					add(rulePegText, position2116)
+				}
+				if !_rules[ruleAction92]() {
+					goto l2114
+				}
+				add(ruleSourceSinkSpecs, position2115)
+			}
+			return true
+		l2114:
+			position, tokenIndex = position2114, tokenIndex2114
+			return false
+		},
+		/* 115 UpdateSourceSinkSpecs <- <(<(sp (('s' / 'S') ('e' / 'E') ('t' / 'T')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)> Action93)> */
+		func() bool {
+			position2129, tokenIndex2129 := position, tokenIndex
+			{
+				position2130 := position
+				{
+					position2131 := position
+					if !_rules[rulesp]() {
+						goto l2129
+					}
+					{
+						position2132, tokenIndex2132 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2133
+						}
+						position++
+						goto l2132
+					l2133:
+						position, tokenIndex = position2132, tokenIndex2132
+						if buffer[position] != rune('S') {
+							goto l2129
+						}
+						position++
+					}
+				l2132:
+					{
+						position2134, tokenIndex2134 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2135
+						}
+						position++
+						goto l2134
+					l2135:
+						position, tokenIndex = position2134, tokenIndex2134
+						if buffer[position] != rune('E') {
+							goto l2129
+						}
+						position++
+					}
+				l2134:
+					{
+						position2136, tokenIndex2136 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2137
+						}
+						position++
+						goto l2136
+					l2137:
+						position, tokenIndex = position2136, tokenIndex2136
+						if buffer[position] != rune('T') {
+							goto l2129
+						}
+						position++
+					}
+				l2136:
+					if !_rules[rulesp]() {
+						goto l2129
+					}
+					if !_rules[ruleSourceSinkParam]() {
+						goto l2129
+					}
+				l2138:
+					{
+						position2139, tokenIndex2139 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2139
+						}
+						if buffer[position] != rune(',') {
+							goto l2139
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l2139
+						}
+						if !_rules[ruleSourceSinkParam]() {
+							goto l2139
+						}
+						goto l2138
+					l2139:
+						position, tokenIndex = position2139, tokenIndex2139
+					}
+					add(rulePegText, position2131)
+				}
+				if !_rules[ruleAction93]() {
+					goto l2129
+				}
+				add(ruleUpdateSourceSinkSpecs, position2130)
+			}
+			return true
+		l2129:
+			position, tokenIndex = position2129, tokenIndex2129
+			return false
+		},
+		/* 116 ConfigSpecs <- <(<(sp (('w' / 'W') ('i' / 'I') ('t' / 'T') ('h' / 'H')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)> Action94)> */
+		func() bool {
+			position2140, tokenIndex2140 := position, tokenIndex
+			{
+				position2141 := position
+				{
+					position2142 := position
+					if !_rules[rulesp]() {
+						goto l2140
+					}
+					{
+						position2143, tokenIndex2143 := position, tokenIndex
+						if buffer[position] != rune('w') {
+							goto l2144
+						}
+						position++
+						goto l2143
+					l2144:
+						position, tokenIndex = position2143, tokenIndex2143
+						if buffer[position] != rune('W') {
+							goto l2140
+						}
+						position++
+					}
+				l2143:
+					{
+						position2145, tokenIndex2145 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2146
+						}
+						position++
+						goto l2145
+					l2146:
+						position, tokenIndex = position2145, tokenIndex2145
+						if buffer[position] != rune('I') {
+							goto l2140
+						}
+						position++
+					}
+				l2145:
+					{
+						position2147, tokenIndex2147 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2148
+						}
+						position++
+						goto l2147
+					l2148:
+						position, tokenIndex = position2147, tokenIndex2147
+						if buffer[position] != rune('T') {
+							goto l2140
+						}
+						position++
+					}
+				l2147:
+					{
+						position2149, tokenIndex2149 := position, tokenIndex
+						if buffer[position] != rune('h') {
+							goto l2150
+						}
+						position++
+						goto l2149
+					l2150:
+						position, tokenIndex = position2149, tokenIndex2149
+						if buffer[position] != rune('H') {
+							goto l2140
+						}
+						position++
+					}
+				l2149:
+					if !_rules[rulesp]() {
+						goto l2140
+					}
+					if !_rules[ruleSourceSinkParam]() {
+						goto l2140
+					}
+				l2151:
+					{
+						position2152, tokenIndex2152 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2152
+						}
+						if buffer[position] != rune(',') {
+							goto l2152
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l2152
+						}
+						if !_rules[ruleSourceSinkParam]() {
+							goto l2152
+						}
+						goto l2151
+					l2152:
+						position, tokenIndex = position2152, tokenIndex2152
+					}
+					add(rulePegText, position2142)
+				}
+				if !_rules[ruleAction94]() {
+					goto l2140
+				}
+				add(ruleConfigSpecs, position2141)
+			}
+			return true
+		l2140:
+			position, tokenIndex = position2140, tokenIndex2140
+			return false
+		},
+		/* 117 SetOptSpecs <- <(<(sp (('s' / 'S') ('e' / 'E') ('t' / 'T')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)?> Action95)> */
+		func() bool {
+			position2153, tokenIndex2153 := position, tokenIndex
+			{
+				position2154 := position
+				{
+					position2155 := position
+					{
+						position2156, tokenIndex2156 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2156
+						}
+						{
+							position2158, tokenIndex2158 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l2159
+							}
+							position++
+							goto l2158
+						l2159:
+							position, tokenIndex = position2158, tokenIndex2158
+							if buffer[position] != rune('S') {
+								goto l2156
+							}
+							position++
+						}
+					l2158:
+						{
+							position2160, tokenIndex2160 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2161
+							}
+							position++
+							goto l2160
+						l2161:
+							position, tokenIndex = position2160, tokenIndex2160
+							if buffer[position] != rune('E') {
+								goto l2156
+							}
+							position++
+						}
+					l2160:
+						{
+							position2162, tokenIndex2162 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2163
+							}
+							position++
+							goto l2162
+						l2163:
+							position, tokenIndex = position2162, tokenIndex2162
+							if buffer[position] != rune('T') {
+								goto l2156
+							}
+							position++
+						}
+					l2162:
+						if !_rules[rulesp]() {
+							goto l2156
+						}
+						if !_rules[ruleSourceSinkParam]() {
+							goto l2156
+						}
+					l2164:
+						{
+							position2165, tokenIndex2165 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l2165
+							}
+							if buffer[position] != rune(',') {
+								goto l2165
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l2165
+							}
+							if !_rules[ruleSourceSinkParam]() {
+								goto l2165
+							}
+							goto l2164
+						l2165:
+							position, tokenIndex = position2165, tokenIndex2165
+						}
+						goto l2157
+					l2156:
+						position, tokenIndex = position2156, tokenIndex2156
+					}
+				l2157:
+					add(rulePegText, position2155)
+				}
+				if !_rules[ruleAction95]() {
+					goto l2153
+				}
+				add(ruleSetOptSpecs, position2154)
+			}
+			return true
+		l2153:
+			position, tokenIndex = position2153, tokenIndex2153
+			return false
+		},
+		/* 118 StateTagOpt <- <(<(sp (('t' / 'T') ('a' / 'A') ('g' / 'G')) sp Identifier)?> Action96)> */
+		func() bool {
+			position2166, tokenIndex2166 := position, tokenIndex
+			{
+				position2167 := position
+				{
+					position2168 := position
+					{
+						position2169, tokenIndex2169 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2169
+						}
+						{
+							position2171, tokenIndex2171 := position, tokenIndex
+							if buffer[position] != rune('t') {
+								goto l2172
+							}
 							position++
-							goto l943
-						l944:
-							position, tokenIndex = position943, tokenIndex943
-							if buffer[position] != rune('Z') {
-								goto l925
+							goto l2171
+						l2172:
+							position, tokenIndex = position2171, tokenIndex2171
+							if buffer[position] != rune('T') {
+								goto l2169
+							}
+							position++
+						}
+					l2171:
+						{
+							position2173, tokenIndex2173 := position, tokenIndex
+							if buffer[position] != rune('a') {
+								goto l2174
+							}
+							position++
+							goto l2173
+						l2174:
+							position, tokenIndex = position2173, tokenIndex2173
+							if buffer[position] != rune('A') {
+								goto l2169
+							}
+							position++
+						}
+					l2173:
+						{
+							position2175, tokenIndex2175 := position, tokenIndex
+							if buffer[position] != rune('g') {
+								goto l2176
+							}
+							position++
+							goto l2175
+						l2176:
+							position, tokenIndex = position2175, tokenIndex2175
+							if buffer[position] != rune('G') {
+								goto l2169
+							}
+							position++
+						}
+					l2175:
+						if !_rules[rulesp]() {
+							goto l2169
+						}
+						if !_rules[ruleIdentifier]() {
+							goto l2169
+						}
+						goto l2170
+					l2169:
+						position, tokenIndex = position2169, tokenIndex2169
+					}
+				l2170:
+					add(rulePegText, position2168)
+				}
+				if !_rules[ruleAction96]() {
+					goto l2166
+				}
+				add(ruleStateTagOpt, position2167)
+			}
+			return true
+		l2166:
+			position, tokenIndex = position2166, tokenIndex2166
+			return false
+		},
+		/* 119 SourceSinkParam <- <(SourceSinkParamKey spOpt '=' spOpt SourceSinkParamVal Action97)> */
+		func() bool {
+			position2177, tokenIndex2177 := position, tokenIndex
+			{
+				position2178 := position
+				if !_rules[ruleSourceSinkParamKey]() {
+					goto l2177
+				}
+				if !_rules[rulespOpt]() {
+					goto l2177
+				}
+				if buffer[position] != rune('=') {
+					goto l2177
+				}
+				position++
+				if !_rules[rulespOpt]() {
+					goto l2177
+				}
+				if !_rules[ruleSourceSinkParamVal]() {
+					goto l2177
+				}
+				if !_rules[ruleAction97]() {
+					goto l2177
+				}
+				add(ruleSourceSinkParam, position2178)
+			}
+			return true
+		l2177:
+			position, tokenIndex = position2177, tokenIndex2177
+			return false
+		},
+		/* 120 SourceSinkParamVal <- <ParamLiteral> */
+		func() bool {
+			position2179, tokenIndex2179 := position, tokenIndex
+			{
+				position2180 := position
+				if !_rules[ruleParamLiteral]() {
+					goto l2179
+				}
+				add(ruleSourceSinkParamVal, position2180)
+			}
+			return true
+		l2179:
+			position, tokenIndex = position2179, tokenIndex2179
+			return false
+		},
+		/* 121 ParamLiteral <- <(BooleanLiteral / Literal / ParamArrayExpr / ParamMapExpr)> */
+		func() bool {
+			position2181, tokenIndex2181 := position, tokenIndex
+			{
+				position2182 := position
+				{
+					position2183, tokenIndex2183 := position, tokenIndex
+					if !_rules[ruleBooleanLiteral]() {
+						goto l2184
+					}
+					goto l2183
+				l2184:
+					position, tokenIndex = position2183, tokenIndex2183
+					if !_rules[ruleLiteral]() {
+						goto l2185
+					}
+					goto l2183
+				l2185:
+					position, tokenIndex = position2183, tokenIndex2183
+					if !_rules[ruleParamArrayExpr]() {
+						goto l2186
+					}
+					goto l2183
+				l2186:
+					position, tokenIndex = position2183, tokenIndex2183
+					if !_rules[ruleParamMapExpr]() {
+						goto l2181
+					}
+				}
+			l2183:
+				add(ruleParamLiteral, position2182)
+			}
+			return true
+		l2181:
+			position, tokenIndex = position2181, tokenIndex2181
+			return false
+		},
+		/* 122 ParamArrayExpr <- <(<('[' spOpt (ParamLiteral (',' spOpt ParamLiteral)*)? spOpt ','? spOpt ']')> Action98)> */
+		func() bool {
+			position2187, tokenIndex2187 := position, tokenIndex
+			{
+				position2188 := position
+				{
+					position2189 := position
+					if buffer[position] != rune('[') {
+						goto l2187
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2187
+					}
+					{
+						position2190, tokenIndex2190 := position, tokenIndex
+						if !_rules[ruleParamLiteral]() {
+							goto l2190
+						}
+					l2192:
+						{
+							position2193, tokenIndex2193 := position, tokenIndex
+							if buffer[position] != rune(',') {
+								goto l2193
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l2193
+							}
+							if !_rules[ruleParamLiteral]() {
+								goto l2193
+							}
+							goto l2192
+						l2193:
+							position, tokenIndex = position2193, tokenIndex2193
+						}
+						goto l2191
+					l2190:
+						position, tokenIndex = position2190, tokenIndex2190
+					}
+				l2191:
+					if !_rules[rulespOpt]() {
+						goto l2187
+					}
+					{
+						position2194, tokenIndex2194 := position, tokenIndex
+						if buffer[position] != rune(',') {
+							goto l2194
+						}
+						position++
+						goto l2195
+					l2194:
+						position, tokenIndex = position2194, tokenIndex2194
+					}
+				l2195:
+					if !_rules[rulespOpt]() {
+						goto l2187
+					}
+					if buffer[position] != rune(']') {
+						goto l2187
+					}
+					position++
+					add(rulePegText, position2189)
+				}
+				if !_rules[ruleAction98]() {
+					goto l2187
+				}
+				add(ruleParamArrayExpr, position2188)
+			}
+			return true
+		l2187:
+			position, tokenIndex = position2187, tokenIndex2187
+			return false
+		},
+		/* 123 ParamMapExpr <- <(<('{' spOpt (ParamKeyValuePair (spOpt ',' spOpt ParamKeyValuePair)*)? spOpt '}')> Action99)> */
+		func() bool {
+			position2196, tokenIndex2196 := position, tokenIndex
+			{
+				position2197 := position
+				{
+					position2198 := position
+					if buffer[position] != rune('{') {
+						goto l2196
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2196
+					}
+					{
+						position2199, tokenIndex2199 := position, tokenIndex
+						if !_rules[ruleParamKeyValuePair]() {
+							goto l2199
+						}
+					l2201:
+						{
+							position2202, tokenIndex2202 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l2202
+							}
+							if buffer[position] != rune(',') {
+								goto l2202
 							}
 							position++
+							if !_rules[rulespOpt]() {
+								goto l2202
+							}
+							if !_rules[ruleParamKeyValuePair]() {
+								goto l2202
+							}
+							goto l2201
+						l2202:
+							position, tokenIndex = position2202, tokenIndex2202
+						}
+						goto l2200
+					l2199:
+						position, tokenIndex = position2199, tokenIndex2199
+					}
+				l2200:
+					if !_rules[rulespOpt]() {
+						goto l2196
+					}
+					if buffer[position] != rune('}') {
+						goto l2196
+					}
+					position++
+					add(rulePegText, position2198)
+				}
+				if !_rules[ruleAction99]() {
+					goto l2196
+				}
+				add(ruleParamMapExpr, position2197)
+			}
+			return true
+		l2196:
+			position, tokenIndex = position2196, tokenIndex2196
+			return false
+		},
+		/* 124 ParamKeyValuePair <- <(<(StringLiteral spOpt ':' spOpt ParamLiteral)> Action100)> */
+		func() bool {
+			position2203, tokenIndex2203 := position, tokenIndex
+			{
+				position2204 := position
+				{
+					position2205 := position
+					if !_rules[ruleStringLiteral]() {
+						goto l2203
+					}
+					if !_rules[rulespOpt]() {
+						goto l2203
+					}
+					if buffer[position] != rune(':') {
+						goto l2203
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2203
+					}
+					if !_rules[ruleParamLiteral]() {
+						goto l2203
+					}
+					add(rulePegText, position2205)
+				}
+				if !_rules[ruleAction100]() {
+					goto l2203
+				}
+				add(ruleParamKeyValuePair, position2204)
+			}
+			return true
+		l2203:
+			position, tokenIndex = position2203, tokenIndex2203
+			return false
+		},
+		/* 125 PausedOpt <- <(<(sp (Paused / Unpaused))?> Action101)> */
+		func() bool {
+			position2206, tokenIndex2206 := position, tokenIndex
+			{
+				position2207 := position
+				{
+					position2208 := position
+					{
+						position2209, tokenIndex2209 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2209
+						}
+						{
+							position2211, tokenIndex2211 := position, tokenIndex
+							if !_rules[rulePaused]() {
+								goto l2212
+							}
+							goto l2211
+						l2212:
+							position, tokenIndex = position2211, tokenIndex2211
+							if !_rules[ruleUnpaused]() {
+								goto l2209
+							}
+						}
+					l2211:
+						goto l2210
+					l2209:
+						position, tokenIndex = position2209, tokenIndex2209
+					}
+				l2210:
+					add(rulePegText, position2208)
+				}
+				if !_rules[ruleAction101]() {
+					goto l2206
+				}
+				add(rulePausedOpt, position2207)
+			}
+			return true
+		l2206:
+			position, tokenIndex = position2206, tokenIndex2206
+			return false
+		},
+		/* 126 TemporaryOpt <- <(<(sp Temporary)?> Action102)> */
+		func() bool {
+			position2213, tokenIndex2213 := position, tokenIndex
+			{
+				position2214 := position
+				{
+					position2215 := position
+					{
+						position2216, tokenIndex2216 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2216
+						}
+						if !_rules[ruleTemporary]() {
+							goto l2216
+						}
+						goto l2217
+					l2216:
+						position, tokenIndex = position2216, tokenIndex2216
+					}
+				l2217:
+					add(rulePegText, position2215)
+				}
+				if !_rules[ruleAction102]() {
+					goto l2213
+				}
+				add(ruleTemporaryOpt, position2214)
+			}
+			return true
+		l2213:
+			position, tokenIndex = position2213, tokenIndex2213
+			return false
+		},
+		/* 127 ExpressionOrWildcard <- <(Wildcard / Expression)> */
+		func() bool {
+			position2218, tokenIndex2218 := position, tokenIndex
+			{
+				position2219 := position
+				{
+					position2220, tokenIndex2220 := position, tokenIndex
+					if !_rules[ruleWildcard]() {
+						goto l2221
+					}
+					goto l2220
+				l2221:
+					position, tokenIndex = position2220, tokenIndex2220
+					if !_rules[ruleExpression]() {
+						goto l2218
+					}
+				}
+			l2220:
+				add(ruleExpressionOrWildcard, position2219)
+			}
+			return true
+		l2218:
+			position, tokenIndex = position2218, tokenIndex2218
+			return false
+		},
+		/* 128 Expression <- <orExpr> */
+		func() bool {
+			position2222, tokenIndex2222 := position, tokenIndex
+			{
+				position2223 := position
+				if !_rules[ruleorExpr]() {
+					goto l2222
+				}
+				add(ruleExpression, position2223)
+			}
+			return true
+		l2222:
+			position, tokenIndex = position2222, tokenIndex2222
+			return false
+		},
+		/* 129 orExpr <- <(<(andExpr (sp Or sp andExpr)*)> Action103)> */
+		func() bool {
+			position2224, tokenIndex2224 := position, tokenIndex
+			{
+				position2225 := position
+				{
+					position2226 := position
+					if !_rules[ruleandExpr]() {
+						goto l2224
+					}
+				l2227:
+					{
+						position2228, tokenIndex2228 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2228
+						}
+						if !_rules[ruleOr]() {
+							goto l2228
+						}
+						if !_rules[rulesp]() {
+							goto l2228
+						}
+						if !_rules[ruleandExpr]() {
+							goto l2228
 						}
-					l943:
-						{
-							position945, tokenIndex945 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l946
-							}
-							position++
-							goto l945
-						l946:
-							position, tokenIndex = position945, tokenIndex945
-							if buffer[position] != rune('E') {
-								goto l925
-							}
-							position++
+						goto l2227
+					l2228:
+						position, tokenIndex = position2228, tokenIndex2228
+					}
+					add(rulePegText, position2226)
+				}
+				if !_rules[ruleAction103]() {
+					goto l2224
+				}
+				add(ruleorExpr, position2225)
+			}
+			return true
+		l2224:
+			position, tokenIndex = position2224, tokenIndex2224
+			return false
+		},
+		/* 130 andExpr <- <(<(notExpr (sp And sp notExpr)*)> Action104)> */
+		func() bool {
+			position2229, tokenIndex2229 := position, tokenIndex
+			{
+				position2230 := position
+				{
+					position2231 := position
+					if !_rules[rulenotExpr]() {
+						goto l2229
+					}
+				l2232:
+					{
+						position2233, tokenIndex2233 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2233
+						}
+						if !_rules[ruleAnd]() {
+							goto l2233
 						}
-					l945:
 						if !_rules[rulesp]() {
-							goto l925
+							goto l2233
 						}
-						if !_rules[ruleNonNegativeNumericLiteral]() {
-							goto l925
+						if !_rules[rulenotExpr]() {
+							goto l2233
 						}
-						goto l926
-					l925:
-						position, tokenIndex = position925, tokenIndex925
+						goto l2232
+					l2233:
+						position, tokenIndex = position2233, tokenIndex2233
 					}
-				l926:
-					add(rulePegText, position924)
+					add(rulePegText, position2231)
 				}
-				if !_rules[ruleAction43]() {
-					goto l922
+				if !_rules[ruleAction104]() {
+					goto l2229
+				}
+				add(ruleandExpr, position2230)
+			}
+			return true
+		l2229:
+			position, tokenIndex = position2229, tokenIndex2229
+			return false
+		},
+		/* 131 notExpr <- <(<((Not sp)? comparisonExpr)> Action105)> */
+		func() bool {
+			position2234, tokenIndex2234 := position, tokenIndex
+			{
+				position2235 := position
+				{
+					position2236 := position
+					{
+						position2237, tokenIndex2237 := position, tokenIndex
+						if !_rules[ruleNot]() {
+							goto l2237
+						}
+						if !_rules[rulesp]() {
+							goto l2237
+						}
+						goto l2238
+					l2237:
+						position, tokenIndex = position2237, tokenIndex2237
+					}
+				l2238:
+					if !_rules[rulecomparisonExpr]() {
+						goto l2234
+					}
+					add(rulePegText, position2236)
+				}
+				if !_rules[ruleAction105]() {
+					goto l2234
 				}
-				add(ruleCapacitySpecOpt, position923)
+				add(rulenotExpr, position2235)
 			}
 			return true
-		l922:
-			position, tokenIndex = position922, tokenIndex922
+		l2234:
+			position, tokenIndex = position2234, tokenIndex2234
 			return false
 		},
-		/* 58 SheddingSpecOpt <- <(<(spOpt ',' spOpt SheddingOption sp (('i' / 'I') ('f' / 'F')) sp (('f' / 'F') ('u' / 'U') ('l' / 'L') ('l' / 'L')))?> Action44)> */
+		/* 132 comparisonExpr <- <(<(otherOpExpr (spOpt ComparisonOp spOpt otherOpExpr)?)> Action106)> */
 		func() bool {
-			position947, tokenIndex947 := position, tokenIndex
+			position2239, tokenIndex2239 := position, tokenIndex
 			{
-				position948 := position
+				position2240 := position
 				{
-					position949 := position
+					position2241 := position
+					if !_rules[ruleotherOpExpr]() {
+						goto l2239
+					}
 					{
-						position950, tokenIndex950 := position, tokenIndex
+						position2242, tokenIndex2242 := position, tokenIndex
 						if !_rules[rulespOpt]() {
-							goto l950
+							goto l2242
 						}
-						if buffer[position] != rune(',') {
-							goto l950
+						if !_rules[ruleComparisonOp]() {
+							goto l2242
 						}
-						position++
 						if !_rules[rulespOpt]() {
-							goto l950
+							goto l2242
 						}
-						if !_rules[ruleSheddingOption]() {
-							goto l950
+						if !_rules[ruleotherOpExpr]() {
+							goto l2242
 						}
-						if !_rules[rulesp]() {
-							goto l950
+						goto l2243
+					l2242:
+						position, tokenIndex = position2242, tokenIndex2242
+					}
+				l2243:
+					add(rulePegText, position2241)
+				}
+				if !_rules[ruleAction106]() {
+					goto l2239
+				}
+				add(rulecomparisonExpr, position2240)
+			}
+			return true
+		l2239:
+			position, tokenIndex = position2239, tokenIndex2239
+			return false
+		},
+		/* 133 otherOpExpr <- <(<(isExpr (spOpt OtherOp spOpt isExpr)*)> Action107)> */
+		func() bool {
+			position2244, tokenIndex2244 := position, tokenIndex
+			{
+				position2245 := position
+				{
+					position2246 := position
+					if !_rules[ruleisExpr]() {
+						goto l2244
+					}
+				l2247:
+					{
+						position2248, tokenIndex2248 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2248
 						}
-						{
-							position952, tokenIndex952 := position, tokenIndex
-							if buffer[position] != rune('i') {
-								goto l953
-							}
-							position++
-							goto l952
-						l953:
-							position, tokenIndex = position952, tokenIndex952
-							if buffer[position] != rune('I') {
-								goto l950
-							}
-							position++
+						if !_rules[ruleOtherOp]() {
+							goto l2248
 						}
-					l952:
-						{
-							position954, tokenIndex954 := position, tokenIndex
-							if buffer[position] != rune('f') {
-								goto l955
-							}
-							position++
-							goto l954
-						l955:
-							position, tokenIndex = position954, tokenIndex954
-							if buffer[position] != rune('F') {
-								goto l950
-							}
-							position++
+						if !_rules[rulespOpt]() {
+							goto l2248
+						}
+						if !_rules[ruleisExpr]() {
+							goto l2248
+						}
+						goto l2247
+					l2248:
+						position, tokenIndex = position2248, tokenIndex2248
+					}
+					add(rulePegText, position2246)
+				}
+				if !_rules[ruleAction107]() {
+					goto l2244
+				}
+				add(ruleotherOpExpr, position2245)
+			}
+			return true
+		l2244:
+			position, tokenIndex = position2244, tokenIndex2244
+			return false
+		},
+		/* 134 isExpr <- <(<((RowValue sp IsOp sp Missing) / (termExpr (sp IsOp sp NullLiteral)?))> Action108)> */
+		func() bool {
+			position2249, tokenIndex2249 := position, tokenIndex
+			{
+				position2250 := position
+				{
+					position2251 := position
+					{
+						position2252, tokenIndex2252 := position, tokenIndex
+						if !_rules[ruleRowValue]() {
+							goto l2253
 						}
-					l954:
 						if !_rules[rulesp]() {
-							goto l950
+							goto l2253
 						}
-						{
-							position956, tokenIndex956 := position, tokenIndex
-							if buffer[position] != rune('f') {
-								goto l957
-							}
-							position++
-							goto l956
-						l957:
-							position, tokenIndex = position956, tokenIndex956
-							if buffer[position] != rune('F') {
-								goto l950
-							}
-							position++
+						if !_rules[ruleIsOp]() {
+							goto l2253
 						}
-					l956:
-						{
-							position958, tokenIndex958 := position, tokenIndex
-							if buffer[position] != rune('u') {
-								goto l959
-							}
-							position++
-							goto l958
-						l959:
-							position, tokenIndex = position958, tokenIndex958
-							if buffer[position] != rune('U') {
-								goto l950
-							}
-							position++
+						if !_rules[rulesp]() {
+							goto l2253
+						}
+						if !_rules[ruleMissing]() {
+							goto l2253
+						}
+						goto l2252
+					l2253:
+						position, tokenIndex = position2252, tokenIndex2252
+						if !_rules[ruletermExpr]() {
+							goto l2249
 						}
-					l958:
 						{
-							position960, tokenIndex960 := position, tokenIndex
-							if buffer[position] != rune('l') {
-								goto l961
+							position2254, tokenIndex2254 := position, tokenIndex
+							if !_rules[rulesp]() {
+								goto l2254
 							}
-							position++
-							goto l960
-						l961:
-							position, tokenIndex = position960, tokenIndex960
-							if buffer[position] != rune('L') {
-								goto l950
+							if !_rules[ruleIsOp]() {
+								goto l2254
 							}
-							position++
-						}
-					l960:
-						{
-							position962, tokenIndex962 := position, tokenIndex
-							if buffer[position] != rune('l') {
-								goto l963
+							if !_rules[rulesp]() {
+								goto l2254
 							}
-							position++
-							goto l962
-						l963:
-							position, tokenIndex = position962, tokenIndex962
-							if buffer[position] != rune('L') {
-								goto l950
+							if !_rules[ruleNullLiteral]() {
+								goto l2254
 							}
-							position++
+							goto l2255
+						l2254:
+							position, tokenIndex = position2254, tokenIndex2254
 						}
-					l962:
-						goto l951
-					l950:
-						position, tokenIndex = position950, tokenIndex950
+					l2255:
 					}
-				l951:
-					add(rulePegText, position949)
+				l2252:
+					add(rulePegText, position2251)
 				}
-				if !_rules[ruleAction44]() {
-					goto l947
+				if !_rules[ruleAction108]() {
+					goto l2249
 				}
-				add(ruleSheddingSpecOpt, position948)
+				add(ruleisExpr, position2250)
 			}
 			return true
-		l947:
-			position, tokenIndex = position947, tokenIndex947
+		l2249:
+			position, tokenIndex = position2249, tokenIndex2249
 			return false
 		},
-		/* 59 SheddingOption <- <(Wait / DropOldest / DropNewest)> */
+		/* 135 termExpr <- <(<(productExpr (spOpt PlusMinusOp spOpt productExpr)*)> Action109)> */
 		func() bool {
-			position964, tokenIndex964 := position, tokenIndex
+			position2256, tokenIndex2256 := position, tokenIndex
 			{
-				position965 := position
+				position2257 := position
 				{
-					position966, tokenIndex966 := position, tokenIndex
-					if !_rules[ruleWait]() {
-						goto l967
-					}
-					goto l966
-				l967:
-					position, tokenIndex = position966, tokenIndex966
-					if !_rules[ruleDropOldest]() {
-						goto l968
+					position2258 := position
+					if !_rules[ruleproductExpr]() {
+						goto l2256
 					}
-					goto l966
-				l968:
-					position, tokenIndex = position966, tokenIndex966
-					if !_rules[ruleDropNewest]() {
-						goto l964
+				l2259:
+					{
+						position2260, tokenIndex2260 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2260
+						}
+						if !_rules[rulePlusMinusOp]() {
+							goto l2260
+						}
+						if !_rules[rulespOpt]() {
+							goto l2260
+						}
+						if !_rules[ruleproductExpr]() {
+							goto l2260
+						}
+						goto l2259
+					l2260:
+						position, tokenIndex = position2260, tokenIndex2260
 					}
+					add(rulePegText, position2258)
 				}
-			l966:
-				add(ruleSheddingOption, position965)
+				if !_rules[ruleAction109]() {
+					goto l2256
+				}
+				add(ruletermExpr, position2257)
 			}
 			return true
-		l964:
-			position, tokenIndex = position964, tokenIndex964
+		l2256:
+			position, tokenIndex = position2256, tokenIndex2256
 			return false
 		},
-		/* 60 SourceSinkSpecs <- <(<(sp (('w' / 'W') ('i' / 'I') ('t' / 'T') ('h' / 'H')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)?> Action45)> */
+		/* 136 productExpr <- <(<(minusExpr (spOpt MultDivOp spOpt minusExpr)*)> Action110)> */
 		func() bool {
-			position969, tokenIndex969 := position, tokenIndex
+			position2261, tokenIndex2261 := position, tokenIndex
 			{
-				position970 := position
+				position2262 := position
 				{
-					position971 := position
+					position2263 := position
+					if !_rules[ruleminusExpr]() {
+						goto l2261
+					}
+				l2264:
 					{
-						position972, tokenIndex972 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l972
+						position2265, tokenIndex2265 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2265
 						}
-						{
-							position974, tokenIndex974 := position, tokenIndex
-							if buffer[position] != rune('w') {
-								goto l975
-							}
-							position++
-							goto l974
-						l975:
-							position, tokenIndex = position974, tokenIndex974
-							if buffer[position] != rune('W') {
-								goto l972
-							}
-							position++
+						if !_rules[ruleMultDivOp]() {
+							goto l2265
 						}
-					l974:
-						{
-							position976, tokenIndex976 := position, tokenIndex
-							if buffer[position] != rune('i') {
-								goto l977
-							}
-							position++
-							goto l976
-						l977:
-							position, tokenIndex = position976, tokenIndex976
-							if buffer[position] != rune('I') {
-								goto l972
-							}
-							position++
+						if !_rules[rulespOpt]() {
+							goto l2265
 						}
-					l976:
-						{
-							position978, tokenIndex978 := position, tokenIndex
-							if buffer[position] != rune('t') {
-								goto l979
-							}
-							position++
-							goto l978
-						l979:
-							position, tokenIndex = position978, tokenIndex978
-							if buffer[position] != rune('T') {
-								goto l972
-							}
-							position++
+						if !_rules[ruleminusExpr]() {
+							goto l2265
 						}
-					l978:
-						{
-							position980, tokenIndex980 := position, tokenIndex
-							if buffer[position] != rune('h') {
-								goto l981
-							}
-							position++
-							goto l980
-						l981:
-							position, tokenIndex = position980, tokenIndex980
-							if buffer[position] != rune('H') {
-								goto l972
-							}
-							position++
+						goto l2264
+					l2265:
+						position, tokenIndex = position2265, tokenIndex2265
+					}
+					add(rulePegText, position2263)
+				}
+				if !_rules[ruleAction110]() {
+					goto l2261
+				}
+				add(ruleproductExpr, position2262)
+			}
+			return true
+		l2261:
+			position, tokenIndex = position2261, tokenIndex2261
+			return false
+		},
+		/* 137 minusExpr <- <(<((UnaryMinus spOpt)? castExpr)> Action111)> */
+		func() bool {
+			position2266, tokenIndex2266 := position, tokenIndex
+			{
+				position2267 := position
+				{
+					position2268 := position
+					{
+						position2269, tokenIndex2269 := position, tokenIndex
+						if !_rules[ruleUnaryMinus]() {
+							goto l2269
 						}
-					l980:
-						if !_rules[rulesp]() {
-							goto l972
+						if !_rules[rulespOpt]() {
+							goto l2269
 						}
-						if !_rules[ruleSourceSinkParam]() {
-							goto l972
+						goto l2270
+					l2269:
+						position, tokenIndex = position2269, tokenIndex2269
+					}
+				l2270:
+					if !_rules[rulecastExpr]() {
+						goto l2266
+					}
+					add(rulePegText, position2268)
+				}
+				if !_rules[ruleAction111]() {
+					goto l2266
+				}
+				add(ruleminusExpr, position2267)
+			}
+			return true
+		l2266:
+			position, tokenIndex = position2266, tokenIndex2266
+			return false
+		},
+		/* 138 castExpr <- <(<(baseExpr (spOpt (':' ':') spOpt Type)?)> Action112)> */
+		func() bool {
+			position2271, tokenIndex2271 := position, tokenIndex
+			{
+				position2272 := position
+				{
+					position2273 := position
+					if !_rules[rulebaseExpr]() {
+						goto l2271
+					}
+					{
+						position2274, tokenIndex2274 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2274
 						}
-					l982:
-						{
-							position983, tokenIndex983 := position, tokenIndex
-							if !_rules[rulespOpt]() {
-								goto l983
-							}
-							if buffer[position] != rune(',') {
-								goto l983
-							}
-							position++
-							if !_rules[rulespOpt]() {
-								goto l983
-							}
-							if !_rules[ruleSourceSinkParam]() {
-								goto l983
-							}
-							goto l982
-						l983:
-							position, tokenIndex = position983, tokenIndex983
+						if buffer[position] != rune(':') {
+							goto l2274
+						}
+						position++
+						if buffer[position] != rune(':') {
+							goto l2274
+						}
+						position++
+						if !_rules[rulespOpt]() {
+							goto l2274
+						}
+						if !_rules[ruleType]() {
+							goto l2274
 						}
-						goto l973
-					l972:
-						position, tokenIndex = position972, tokenIndex972
+						goto l2275
+					l2274:
+						position, tokenIndex = position2274, tokenIndex2274
 					}
-				l973:
-					add(rulePegText, position971)
+				l2275:
+					add(rulePegText, position2273)
 				}
-				if !_rules[ruleAction45]() {
-					goto l969
+				if !_rules[ruleAction112]() {
+					goto l2271
 				}
-				add(ruleSourceSinkSpecs, position970)
+				add(rulecastExpr, position2272)
 			}
 			return true
-		l969:
-			position, tokenIndex = position969, tokenIndex969
+		l2271:
+			position, tokenIndex = position2271, tokenIndex2271
 			return false
 		},
-		/* 61 UpdateSourceSinkSpecs <- <(<(sp (('s' / 'S') ('e' / 'E') ('t' / 'T')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)> Action46)> */
+		/* 139 baseExpr <- <(('(' spOpt Expression spOpt ')') / MapExpr / BooleanLiteral / NullLiteral / Case / RowMeta / FuncTypeCast / FuncAppSelector / FuncApp / RowValue / ArrayExpr / Literal)> */
 		func() bool {
-			position984, tokenIndex984 := position, tokenIndex
+			position2276, tokenIndex2276 := position, tokenIndex
 			{
-				position985 := position
+				position2277 := position
 				{
-					position986 := position
-					if !_rules[rulesp]() {
-						goto l984
+					position2278, tokenIndex2278 := position, tokenIndex
+					if buffer[position] != rune('(') {
+						goto l2279
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2279
+					}
+					if !_rules[ruleExpression]() {
+						goto l2279
+					}
+					if !_rules[rulespOpt]() {
+						goto l2279
+					}
+					if buffer[position] != rune(')') {
+						goto l2279
+					}
+					position++
+					goto l2278
+				l2279:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleMapExpr]() {
+						goto l2280
+					}
+					goto l2278
+				l2280:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleBooleanLiteral]() {
+						goto l2281
+					}
+					goto l2278
+				l2281:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleNullLiteral]() {
+						goto l2282
+					}
+					goto l2278
+				l2282:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleCase]() {
+						goto l2283
+					}
+					goto l2278
+				l2283:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleRowMeta]() {
+						goto l2284
+					}
+					goto l2278
+				l2284:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleFuncTypeCast]() {
+						goto l2285
+					}
+					goto l2278
+				l2285:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleFuncAppSelector]() {
+						goto l2286
+					}
+					goto l2278
+				l2286:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleFuncApp]() {
+						goto l2287
 					}
+					goto l2278
+				l2287:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleRowValue]() {
+						goto l2288
+					}
+					goto l2278
+				l2288:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleArrayExpr]() {
+						goto l2289
+					}
+					goto l2278
+				l2289:
+					position, tokenIndex = position2278, tokenIndex2278
+					if !_rules[ruleLiteral]() {
+						goto l2276
+					}
+				}
+			l2278:
+				add(rulebaseExpr, position2277)
+			}
+			return true
+		l2276:
+			position, tokenIndex = position2276, tokenIndex2276
+			return false
+		},
+		/* 140 FuncTypeCast <- <(<(('c' / 'C') ('a' / 'A') ('s' / 'S') ('t' / 'T') spOpt '(' spOpt Expression sp (('a' / 'A') ('s' / 'S')) sp Type spOpt ')')> Action113)> */
+		func() bool {
+			position2290, tokenIndex2290 := position, tokenIndex
+			{
+				position2291 := position
+				{
+					position2292 := position
 					{
-						position987, tokenIndex987 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l988
+						position2293, tokenIndex2293 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l2294
 						}
 						position++
-						goto l987
-					l988:
-						position, tokenIndex = position987, tokenIndex987
-						if buffer[position] != rune('S') {
-							goto l984
+						goto l2293
+					l2294:
+						position, tokenIndex = position2293, tokenIndex2293
+						if buffer[position] != rune('C') {
+							goto l2290
 						}
 						position++
 					}
-				l987:
+				l2293:
 					{
-						position989, tokenIndex989 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l990
+						position2295, tokenIndex2295 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2296
 						}
 						position++
-						goto l989
-					l990:
-						position, tokenIndex = position989, tokenIndex989
-						if buffer[position] != rune('E') {
-							goto l984
+						goto l2295
+					l2296:
+						position, tokenIndex = position2295, tokenIndex2295
+						if buffer[position] != rune('A') {
+							goto l2290
 						}
 						position++
 					}
-				l989:
+				l2295:
 					{
-						position991, tokenIndex991 := position, tokenIndex
+						position2297, tokenIndex2297 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2298
+						}
+						position++
+						goto l2297
+					l2298:
+						position, tokenIndex = position2297, tokenIndex2297
+						if buffer[position] != rune('S') {
+							goto l2290
+						}
+						position++
+					}
+				l2297:
+					{
+						position2299, tokenIndex2299 := position, tokenIndex
 						if buffer[position] != rune('t') {
-							goto l992
+							goto l2300
 						}
 						position++
-						goto l991
-					l992:
-						position, tokenIndex = position991, tokenIndex991
+						goto l2299
+					l2300:
+						position, tokenIndex = position2299, tokenIndex2299
 						if buffer[position] != rune('T') {
-							goto l984
+							goto l2290
 						}
 						position++
 					}
-				l991:
-					if !_rules[rulesp]() {
-						goto l984
+				l2299:
+					if !_rules[rulespOpt]() {
+						goto l2290
 					}
-					if !_rules[ruleSourceSinkParam]() {
-						goto l984
+					if buffer[position] != rune('(') {
+						goto l2290
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2290
+					}
+					if !_rules[ruleExpression]() {
+						goto l2290
+					}
+					if !_rules[rulesp]() {
+						goto l2290
 					}
-				l993:
 					{
-						position994, tokenIndex994 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l994
+						position2301, tokenIndex2301 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2302
 						}
-						if buffer[position] != rune(',') {
-							goto l994
+						position++
+						goto l2301
+					l2302:
+						position, tokenIndex = position2301, tokenIndex2301
+						if buffer[position] != rune('A') {
+							goto l2290
 						}
 						position++
-						if !_rules[rulespOpt]() {
-							goto l994
+					}
+				l2301:
+					{
+						position2303, tokenIndex2303 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2304
 						}
-						if !_rules[ruleSourceSinkParam]() {
-							goto l994
+						position++
+						goto l2303
+					l2304:
+						position, tokenIndex = position2303, tokenIndex2303
+						if buffer[position] != rune('S') {
+							goto l2290
 						}
-						goto l993
-					l994:
-						position, tokenIndex = position994, tokenIndex994
+						position++
+					}
+				l2303:
+					if !_rules[rulesp]() {
+						goto l2290
+					}
+					if !_rules[ruleType]() {
+						goto l2290
+					}
+					if !_rules[rulespOpt]() {
+						goto l2290
 					}
-					add(rulePegText, position986)
+					if buffer[position] != rune(')') {
+						goto l2290
+					}
+					position++
+					add(rulePegText, position2292)
 				}
-				if !_rules[ruleAction46]() {
-					goto l984
+				if !_rules[ruleAction113]() {
+					goto l2290
 				}
-				add(ruleUpdateSourceSinkSpecs, position985)
+				add(ruleFuncTypeCast, position2291)
 			}
 			return true
-		l984:
-			position, tokenIndex = position984, tokenIndex984
+		l2290:
+			position, tokenIndex = position2290, tokenIndex2290
 			return false
 		},
-		/* 62 SetOptSpecs <- <(<(sp (('s' / 'S') ('e' / 'E') ('t' / 'T')) sp SourceSinkParam (spOpt ',' spOpt SourceSinkParam)*)?> Action47)> */
+		/* 141 FuncApp <- <(FuncAppWithOrderBy / FuncAppWithoutOrderBy)> */
 		func() bool {
-			position995, tokenIndex995 := position, tokenIndex
+			position2305, tokenIndex2305 := position, tokenIndex
 			{
-				position996 := position
+				position2306 := position
 				{
-					position997 := position
-					{
-						position998, tokenIndex998 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l998
-						}
-						{
-							position1000, tokenIndex1000 := position, tokenIndex
-							if buffer[position] != rune('s') {
-								goto l1001
-							}
-							position++
-							goto l1000
-						l1001:
-							position, tokenIndex = position1000, tokenIndex1000
-							if buffer[position] != rune('S') {
-								goto l998
-							}
-							position++
-						}
-					l1000:
-						{
-							position1002, tokenIndex1002 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l1003
-							}
-							position++
-							goto l1002
-						l1003:
-							position, tokenIndex = position1002, tokenIndex1002
-							if buffer[position] != rune('E') {
-								goto l998
-							}
-							position++
-						}
-					l1002:
-						{
-							position1004, tokenIndex1004 := position, tokenIndex
-							if buffer[position] != rune('t') {
-								goto l1005
-							}
-							position++
-							goto l1004
-						l1005:
-							position, tokenIndex = position1004, tokenIndex1004
-							if buffer[position] != rune('T') {
-								goto l998
-							}
-							position++
-						}
-					l1004:
-						if !_rules[rulesp]() {
-							goto l998
-						}
-						if !_rules[ruleSourceSinkParam]() {
-							goto l998
-						}
-					l1006:
-						{
-							position1007, tokenIndex1007 := position, tokenIndex
-							if !_rules[rulespOpt]() {
-								goto l1007
-							}
-							if buffer[position] != rune(',') {
-								goto l1007
-							}
-							position++
-							if !_rules[rulespOpt]() {
-								goto l1007
-							}
-							if !_rules[ruleSourceSinkParam]() {
-								goto l1007
-							}
-							goto l1006
-						l1007:
-							position, tokenIndex = position1007, tokenIndex1007
-						}
-						goto l999
-					l998:
-						position, tokenIndex = position998, tokenIndex998
+					position2307, tokenIndex2307 := position, tokenIndex
+					if !_rules[ruleFuncAppWithOrderBy]() {
+						goto l2308
+					}
+					goto l2307
+				l2308:
+					position, tokenIndex = position2307, tokenIndex2307
+					if !_rules[ruleFuncAppWithoutOrderBy]() {
+						goto l2305
 					}
-				l999:
-					add(rulePegText, position997)
 				}
-				if !_rules[ruleAction47]() {
-					goto l995
+			l2307:
+				add(ruleFuncApp, position2306)
+			}
+			return true
+		l2305:
+			position, tokenIndex = position2305, tokenIndex2305
+			return false
+		},
+		/* 142 FuncAppSelector <- <(FuncApp FuncElemAccessor Action114)> */
+		func() bool {
+			position2309, tokenIndex2309 := position, tokenIndex
+			{
+				position2310 := position
+				if !_rules[ruleFuncApp]() {
+					goto l2309
 				}
-				add(ruleSetOptSpecs, position996)
+				if !_rules[ruleFuncElemAccessor]() {
+					goto l2309
+				}
+				if !_rules[ruleAction114]() {
+					goto l2309
+				}
+				add(ruleFuncAppSelector, position2310)
 			}
 			return true
-		l995:
-			position, tokenIndex = position995, tokenIndex995
+		l2309:
+			position, tokenIndex = position2309, tokenIndex2309
 			return false
 		},
-		/* 63 StateTagOpt <- <(<(sp (('t' / 'T') ('a' / 'A') ('g' / 'G')) sp Identifier)?> Action48)> */
+		/* 143 FuncElemAccessor <- <(<jsonGetPathNonHead+> Action115)> */
 		func() bool {
-			position1008, tokenIndex1008 := position, tokenIndex
+			position2311, tokenIndex2311 := position, tokenIndex
 			{
-				position1009 := position
+				position2312 := position
 				{
-					position1010 := position
+					position2313 := position
+					if !_rules[rulejsonGetPathNonHead]() {
+						goto l2311
+					}
+				l2314:
 					{
-						position1011, tokenIndex1011 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1011
-						}
-						{
-							position1013, tokenIndex1013 := position, tokenIndex
-							if buffer[position] != rune('t') {
-								goto l1014
-							}
-							position++
-							goto l1013
-						l1014:
-							position, tokenIndex = position1013, tokenIndex1013
-							if buffer[position] != rune('T') {
-								goto l1011
-							}
-							position++
-						}
-					l1013:
-						{
-							position1015, tokenIndex1015 := position, tokenIndex
-							if buffer[position] != rune('a') {
-								goto l1016
-							}
-							position++
-							goto l1015
-						l1016:
-							position, tokenIndex = position1015, tokenIndex1015
-							if buffer[position] != rune('A') {
-								goto l1011
-							}
-							position++
-						}
-					l1015:
-						{
-							position1017, tokenIndex1017 := position, tokenIndex
-							if buffer[position] != rune('g') {
-								goto l1018
-							}
-							position++
-							goto l1017
-						l1018:
-							position, tokenIndex = position1017, tokenIndex1017
-							if buffer[position] != rune('G') {
-								goto l1011
-							}
-							position++
-						}
-					l1017:
-						if !_rules[rulesp]() {
-							goto l1011
-						}
-						if !_rules[ruleIdentifier]() {
-							goto l1011
+						position2315, tokenIndex2315 := position, tokenIndex
+						if !_rules[rulejsonGetPathNonHead]() {
+							goto l2315
 						}
-						goto l1012
-					l1011:
-						position, tokenIndex = position1011, tokenIndex1011
+						goto l2314
+					l2315:
+						position, tokenIndex = position2315, tokenIndex2315
 					}
-				l1012:
-					add(rulePegText, position1010)
+					add(rulePegText, position2313)
 				}
-				if !_rules[ruleAction48]() {
-					goto l1008
+				if !_rules[ruleAction115]() {
+					goto l2311
 				}
-				add(ruleStateTagOpt, position1009)
+				add(ruleFuncElemAccessor, position2312)
 			}
 			return true
-		l1008:
-			position, tokenIndex = position1008, tokenIndex1008
+		l2311:
+			position, tokenIndex = position2311, tokenIndex2311
 			return false
 		},
-		/* 64 SourceSinkParam <- <(SourceSinkParamKey spOpt '=' spOpt SourceSinkParamVal Action49)> */
+		/* 144 FuncAppWithOrderBy <- <(Function spOpt '(' spOpt FuncParams sp ParamsOrder spOpt ')' Action116)> */
 		func() bool {
-			position1019, tokenIndex1019 := position, tokenIndex
+			position2316, tokenIndex2316 := position, tokenIndex
 			{
-				position1020 := position
-				if !_rules[ruleSourceSinkParamKey]() {
-					goto l1019
+				position2317 := position
+				if !_rules[ruleFunction]() {
+					goto l2316
 				}
 				if !_rules[rulespOpt]() {
-					goto l1019
+					goto l2316
 				}
-				if buffer[position] != rune('=') {
-					goto l1019
+				if buffer[position] != rune('(') {
+					goto l2316
 				}
 				position++
 				if !_rules[rulespOpt]() {
-					goto l1019
+					goto l2316
 				}
-				if !_rules[ruleSourceSinkParamVal]() {
-					goto l1019
+				if !_rules[ruleFuncParams]() {
+					goto l2316
 				}
-				if !_rules[ruleAction49]() {
-					goto l1019
+				if !_rules[rulesp]() {
+					goto l2316
 				}
-				add(ruleSourceSinkParam, position1020)
-			}
-			return true
-		l1019:
-			position, tokenIndex = position1019, tokenIndex1019
-			return false
-		},
-		/* 65 SourceSinkParamVal <- <ParamLiteral> */
-		func() bool {
-			position1021, tokenIndex1021 := position, tokenIndex
-			{
-				position1022 := position
-				if !_rules[ruleParamLiteral]() {
-					goto l1021
+				if !_rules[ruleParamsOrder]() {
+					goto l2316
+				}
+				if !_rules[rulespOpt]() {
+					goto l2316
+				}
+				if buffer[position] != rune(')') {
+					goto l2316
+				}
+				position++
+				if !_rules[ruleAction116]() {
+					goto l2316
 				}
-				add(ruleSourceSinkParamVal, position1022)
+				add(ruleFuncAppWithOrderBy, position2317)
 			}
 			return true
-		l1021:
-			position, tokenIndex = position1021, tokenIndex1021
+		l2316:
+			position, tokenIndex = position2316, tokenIndex2316
 			return false
 		},
-		/* 66 ParamLiteral <- <(BooleanLiteral / Literal / ParamArrayExpr / ParamMapExpr)> */
+		/* 145 FuncAppWithoutOrderBy <- <(Function spOpt '(' spOpt FuncParams <spOpt> ')' Action117)> */
 		func() bool {
-			position1023, tokenIndex1023 := position, tokenIndex
+			position2318, tokenIndex2318 := position, tokenIndex
 			{
-				position1024 := position
+				position2319 := position
+				if !_rules[ruleFunction]() {
+					goto l2318
+				}
+				if !_rules[rulespOpt]() {
+					goto l2318
+				}
+				if buffer[position] != rune('(') {
+					goto l2318
+				}
+				position++
+				if !_rules[rulespOpt]() {
+					goto l2318
+				}
+				if !_rules[ruleFuncParams]() {
+					goto l2318
+				}
 				{
-					position1025, tokenIndex1025 := position, tokenIndex
-					if !_rules[ruleBooleanLiteral]() {
-						goto l1026
-					}
-					goto l1025
-				l1026:
-					position, tokenIndex = position1025, tokenIndex1025
-					if !_rules[ruleLiteral]() {
-						goto l1027
-					}
-					goto l1025
-				l1027:
-					position, tokenIndex = position1025, tokenIndex1025
-					if !_rules[ruleParamArrayExpr]() {
-						goto l1028
-					}
-					goto l1025
-				l1028:
-					position, tokenIndex = position1025, tokenIndex1025
-					if !_rules[ruleParamMapExpr]() {
-						goto l1023
+					position2320 := position
+					if !_rules[rulespOpt]() {
+						goto l2318
 					}
+					add(rulePegText, position2320)
 				}
-			l1025:
-				add(ruleParamLiteral, position1024)
+				if buffer[position] != rune(')') {
+					goto l2318
+				}
+				position++
+				if !_rules[ruleAction117]() {
+					goto l2318
+				}
+				add(ruleFuncAppWithoutOrderBy, position2319)
 			}
 			return true
-		l1023:
-			position, tokenIndex = position1023, tokenIndex1023
+		l2318:
+			position, tokenIndex = position2318, tokenIndex2318
 			return false
 		},
-		/* 67 ParamArrayExpr <- <(<('[' spOpt (ParamLiteral (',' spOpt ParamLiteral)*)? spOpt ','? spOpt ']')> Action50)> */
+		/* 146 FuncParams <- <(<(ExpressionOrWildcard (spOpt ',' spOpt ExpressionOrWildcard)*)?> Action118)> */
 		func() bool {
-			position1029, tokenIndex1029 := position, tokenIndex
+			position2321, tokenIndex2321 := position, tokenIndex
 			{
-				position1030 := position
+				position2322 := position
 				{
-					position1031 := position
-					if buffer[position] != rune('[') {
-						goto l1029
-					}
-					position++
-					if !_rules[rulespOpt]() {
-						goto l1029
-					}
+					position2323 := position
 					{
-						position1032, tokenIndex1032 := position, tokenIndex
-						if !_rules[ruleParamLiteral]() {
-							goto l1032
+						position2324, tokenIndex2324 := position, tokenIndex
+						if !_rules[ruleExpressionOrWildcard]() {
+							goto l2324
 						}
-					l1034:
+					l2326:
 						{
-							position1035, tokenIndex1035 := position, tokenIndex
+							position2327, tokenIndex2327 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l2327
+							}
 							if buffer[position] != rune(',') {
-								goto l1035
+								goto l2327
 							}
 							position++
 							if !_rules[rulespOpt]() {
-								goto l1035
+								goto l2327
 							}
-							if !_rules[ruleParamLiteral]() {
-								goto l1035
+							if !_rules[ruleExpressionOrWildcard]() {
+								goto l2327
 							}
-							goto l1034
-						l1035:
-							position, tokenIndex = position1035, tokenIndex1035
-						}
-						goto l1033
-					l1032:
-						position, tokenIndex = position1032, tokenIndex1032
-					}
-				l1033:
-					if !_rules[rulespOpt]() {
-						goto l1029
-					}
-					{
-						position1036, tokenIndex1036 := position, tokenIndex
-						if buffer[position] != rune(',') {
-							goto l1036
+							goto l2326
+						l2327:
+							position, tokenIndex = position2327, tokenIndex2327
 						}
-						position++
-						goto l1037
-					l1036:
-						position, tokenIndex = position1036, tokenIndex1036
-					}
-				l1037:
-					if !_rules[rulespOpt]() {
-						goto l1029
-					}
-					if buffer[position] != rune(']') {
-						goto l1029
+						goto l2325
+					l2324:
+						position, tokenIndex = position2324, tokenIndex2324
 					}
-					position++
-					add(rulePegText, position1031)
+				l2325:
+					add(rulePegText, position2323)
 				}
-				if !_rules[ruleAction50]() {
-					goto l1029
+				if !_rules[ruleAction118]() {
+					goto l2321
 				}
-				add(ruleParamArrayExpr, position1030)
+				add(ruleFuncParams, position2322)
 			}
 			return true
-		l1029:
-			position, tokenIndex = position1029, tokenIndex1029
+		l2321:
+			position, tokenIndex = position2321, tokenIndex2321
 			return false
 		},
-		/* 68 ParamMapExpr <- <(<('{' spOpt (ParamKeyValuePair (spOpt ',' spOpt ParamKeyValuePair)*)? spOpt '}')> Action51)> */
+		/* 147 ParamsOrder <- <(<(('o' / 'O') ('r' / 'R') ('d' / 'D') ('e' / 'E') ('r' / 'R') sp (('b' / 'B') ('y' / 'Y')) sp SortedExpression (spOpt ',' spOpt SortedExpression)*)> Action119)> */
 		func() bool {
-			position1038, tokenIndex1038 := position, tokenIndex
+			position2328, tokenIndex2328 := position, tokenIndex
 			{
-				position1039 := position
+				position2329 := position
 				{
-					position1040 := position
-					if buffer[position] != rune('{') {
-						goto l1038
+					position2330 := position
+					{
+						position2331, tokenIndex2331 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2332
+						}
+						position++
+						goto l2331
+					l2332:
+						position, tokenIndex = position2331, tokenIndex2331
+						if buffer[position] != rune('O') {
+							goto l2328
+						}
+						position++
 					}
-					position++
-					if !_rules[rulespOpt]() {
-						goto l1038
+				l2331:
+					{
+						position2333, tokenIndex2333 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2334
+						}
+						position++
+						goto l2333
+					l2334:
+						position, tokenIndex = position2333, tokenIndex2333
+						if buffer[position] != rune('R') {
+							goto l2328
+						}
+						position++
 					}
+				l2333:
 					{
-						position1041, tokenIndex1041 := position, tokenIndex
-						if !_rules[ruleParamKeyValuePair]() {
-							goto l1041
+						position2335, tokenIndex2335 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2336
 						}
-					l1043:
-						{
-							position1044, tokenIndex1044 := position, tokenIndex
-							if !_rules[rulespOpt]() {
-								goto l1044
-							}
-							if buffer[position] != rune(',') {
-								goto l1044
-							}
-							position++
-							if !_rules[rulespOpt]() {
-								goto l1044
-							}
-							if !_rules[ruleParamKeyValuePair]() {
-								goto l1044
-							}
-							goto l1043
-						l1044:
-							position, tokenIndex = position1044, tokenIndex1044
+						position++
+						goto l2335
+					l2336:
+						position, tokenIndex = position2335, tokenIndex2335
+						if buffer[position] != rune('D') {
+							goto l2328
 						}
-						goto l1042
-					l1041:
-						position, tokenIndex = position1041, tokenIndex1041
+						position++
 					}
-				l1042:
-					if !_rules[rulespOpt]() {
-						goto l1038
+				l2335:
+					{
+						position2337, tokenIndex2337 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2338
+						}
+						position++
+						goto l2337
+					l2338:
+						position, tokenIndex = position2337, tokenIndex2337
+						if buffer[position] != rune('E') {
+							goto l2328
+						}
+						position++
 					}
-					if buffer[position] != rune('}') {
-						goto l1038
+				l2337:
+					{
+						position2339, tokenIndex2339 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2340
+						}
+						position++
+						goto l2339
+					l2340:
+						position, tokenIndex = position2339, tokenIndex2339
+						if buffer[position] != rune('R') {
+							goto l2328
+						}
+						position++
 					}
-					position++
-					add(rulePegText, position1040)
-				}
-				if !_rules[ruleAction51]() {
-					goto l1038
-				}
-				add(ruleParamMapExpr, position1039)
-			}
-			return true
-		l1038:
-			position, tokenIndex = position1038, tokenIndex1038
-			return false
-		},
-		/* 69 ParamKeyValuePair <- <(<(StringLiteral spOpt ':' spOpt ParamLiteral)> Action52)> */
-		func() bool {
-			position1045, tokenIndex1045 := position, tokenIndex
-			{
-				position1046 := position
-				{
-					position1047 := position
-					if !_rules[ruleStringLiteral]() {
-						goto l1045
+				l2339:
+					if !_rules[rulesp]() {
+						goto l2328
 					}
-					if !_rules[rulespOpt]() {
-						goto l1045
+					{
+						position2341, tokenIndex2341 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l2342
+						}
+						position++
+						goto l2341
+					l2342:
+						position, tokenIndex = position2341, tokenIndex2341
+						if buffer[position] != rune('B') {
+							goto l2328
+						}
+						position++
 					}
-					if buffer[position] != rune(':') {
-						goto l1045
+				l2341:
+					{
+						position2343, tokenIndex2343 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l2344
+						}
+						position++
+						goto l2343
+					l2344:
+						position, tokenIndex = position2343, tokenIndex2343
+						if buffer[position] != rune('Y') {
+							goto l2328
+						}
+						position++
 					}
-					position++
-					if !_rules[rulespOpt]() {
-						goto l1045
+				l2343:
+					if !_rules[rulesp]() {
+						goto l2328
 					}
-					if !_rules[ruleParamLiteral]() {
-						goto l1045
+					if !_rules[ruleSortedExpression]() {
+						goto l2328
 					}
-					add(rulePegText, position1047)
-				}
-				if !_rules[ruleAction52]() {
-					goto l1045
-				}
-				add(ruleParamKeyValuePair, position1046)
-			}
-			return true
-		l1045:
-			position, tokenIndex = position1045, tokenIndex1045
-			return false
-		},
-		/* 70 PausedOpt <- <(<(sp (Paused / Unpaused))?> Action53)> */
-		func() bool {
-			position1048, tokenIndex1048 := position, tokenIndex
-			{
-				position1049 := position
-				{
-					position1050 := position
+				l2345:
 					{
-						position1051, tokenIndex1051 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1051
+						position2346, tokenIndex2346 := position, tokenIndex
+						if !_rules[rulespOpt]() {
+							goto l2346
 						}
-						{
-							position1053, tokenIndex1053 := position, tokenIndex
-							if !_rules[rulePaused]() {
-								goto l1054
-							}
-							goto l1053
-						l1054:
-							position, tokenIndex = position1053, tokenIndex1053
-							if !_rules[ruleUnpaused]() {
-								goto l1051
-							}
+						if buffer[position] != rune(',') {
+							goto l2346
 						}
-					l1053:
-						goto l1052
-					l1051:
-						position, tokenIndex = position1051, tokenIndex1051
+						position++
+						if !_rules[rulespOpt]() {
+							goto l2346
+						}
+						if !_rules[ruleSortedExpression]() {
+							goto l2346
+						}
+						goto l2345
+					l2346:
+						position, tokenIndex = position2346, tokenIndex2346
 					}
-				l1052:
-					add(rulePegText, position1050)
+					add(rulePegText, position2330)
 				}
-				if !_rules[ruleAction53]() {
-					goto l1048
+				if !_rules[ruleAction119]() {
+					goto l2328
 				}
-				add(rulePausedOpt, position1049)
+				add(ruleParamsOrder, position2329)
 			}
 			return true
-		l1048:
-			position, tokenIndex = position1048, tokenIndex1048
+		l2328:
+			position, tokenIndex = position2328, tokenIndex2328
 			return false
 		},
-		/* 71 ExpressionOrWildcard <- <(Wildcard / Expression)> */
+		/* 148 SortedExpression <- <(Expression OrderDirectionOpt Action120)> */
 		func() bool {
-			position1055, tokenIndex1055 := position, tokenIndex
+			position2347, tokenIndex2347 := position, tokenIndex
 			{
-				position1056 := position
-				{
-					position1057, tokenIndex1057 := position, tokenIndex
-					if !_rules[ruleWildcard]() {
-						goto l1058
-					}
-					goto l1057
-				l1058:
-					position, tokenIndex = position1057, tokenIndex1057
-					if !_rules[ruleExpression]() {
-						goto l1055
-					}
+				position2348 := position
+				if !_rules[ruleExpression]() {
+					goto l2347
 				}
-			l1057:
-				add(ruleExpressionOrWildcard, position1056)
-			}
-			return true
-		l1055:
-			position, tokenIndex = position1055, tokenIndex1055
-			return false
-		},
-		/* 72 Expression <- <orExpr> */
-		func() bool {
-			position1059, tokenIndex1059 := position, tokenIndex
-			{
-				position1060 := position
-				if !_rules[ruleorExpr]() {
-					goto l1059
+				if !_rules[ruleOrderDirectionOpt]() {
+					goto l2347
+				}
+				if !_rules[ruleAction120]() {
+					goto l2347
 				}
-				add(ruleExpression, position1060)
+				add(ruleSortedExpression, position2348)
 			}
 			return true
-		l1059:
-			position, tokenIndex = position1059, tokenIndex1059
+		l2347:
+			position, tokenIndex = position2347, tokenIndex2347
 			return false
 		},
-		/* 73 orExpr <- <(<(andExpr (sp Or sp andExpr)*)> Action54)> */
+		/* 149 OrderDirectionOpt <- <(<(sp (Ascending / Descending))?> Action121)> */
 		func() bool {
-			position1061, tokenIndex1061 := position, tokenIndex
+			position2349, tokenIndex2349 := position, tokenIndex
 			{
-				position1062 := position
+				position2350 := position
 				{
-					position1063 := position
-					if !_rules[ruleandExpr]() {
-						goto l1061
-					}
-				l1064:
+					position2351 := position
 					{
-						position1065, tokenIndex1065 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1065
-						}
-						if !_rules[ruleOr]() {
-							goto l1065
-						}
+						position2352, tokenIndex2352 := position, tokenIndex
 						if !_rules[rulesp]() {
-							goto l1065
+							goto l2352
 						}
-						if !_rules[ruleandExpr]() {
-							goto l1065
+						{
+							position2354, tokenIndex2354 := position, tokenIndex
+							if !_rules[ruleAscending]() {
+								goto l2355
+							}
+							goto l2354
+						l2355:
+							position, tokenIndex = position2354, tokenIndex2354
+							if !_rules[ruleDescending]() {
+								goto l2352
+							}
 						}
-						goto l1064
-					l1065:
-						position, tokenIndex = position1065, tokenIndex1065
+					l2354:
+						goto l2353
+					l2352:
+						position, tokenIndex = position2352, tokenIndex2352
 					}
-					add(rulePegText, position1063)
+				l2353:
+					add(rulePegText, position2351)
 				}
-				if !_rules[ruleAction54]() {
-					goto l1061
+				if !_rules[ruleAction121]() {
+					goto l2349
 				}
-				add(ruleorExpr, position1062)
+				add(ruleOrderDirectionOpt, position2350)
 			}
 			return true
-		l1061:
-			position, tokenIndex = position1061, tokenIndex1061
+		l2349:
+			position, tokenIndex = position2349, tokenIndex2349
 			return false
 		},
-		/* 74 andExpr <- <(<(notExpr (sp And sp notExpr)*)> Action55)> */
+		/* 150 ArrayExpr <- <(<('[' spOpt (ExpressionOrWildcard (spOpt ',' spOpt ExpressionOrWildcard)*)? spOpt ','? spOpt ']')> Action122)> */
 		func() bool {
-			position1066, tokenIndex1066 := position, tokenIndex
+			position2356, tokenIndex2356 := position, tokenIndex
 			{
-				position1067 := position
+				position2357 := position
 				{
-					position1068 := position
-					if !_rules[rulenotExpr]() {
-						goto l1066
+					position2358 := position
+					if buffer[position] != rune('[') {
+						goto l2356
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2356
 					}
-				l1069:
 					{
-						position1070, tokenIndex1070 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1070
-						}
-						if !_rules[ruleAnd]() {
-							goto l1070
+						position2359, tokenIndex2359 := position, tokenIndex
+						if !_rules[ruleExpressionOrWildcard]() {
+							goto l2359
 						}
-						if !_rules[rulesp]() {
-							goto l1070
+					l2361:
+						{
+							position2362, tokenIndex2362 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l2362
+							}
+							if buffer[position] != rune(',') {
+								goto l2362
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l2362
+							}
+							if !_rules[ruleExpressionOrWildcard]() {
+								goto l2362
+							}
+							goto l2361
+						l2362:
+							position, tokenIndex = position2362, tokenIndex2362
 						}
-						if !_rules[rulenotExpr]() {
-							goto l1070
+						goto l2360
+					l2359:
+						position, tokenIndex = position2359, tokenIndex2359
+					}
+				l2360:
+					if !_rules[rulespOpt]() {
+						goto l2356
+					}
+					{
+						position2363, tokenIndex2363 := position, tokenIndex
+						if buffer[position] != rune(',') {
+							goto l2363
 						}
-						goto l1069
-					l1070:
-						position, tokenIndex = position1070, tokenIndex1070
+						position++
+						goto l2364
+					l2363:
+						position, tokenIndex = position2363, tokenIndex2363
+					}
+				l2364:
+					if !_rules[rulespOpt]() {
+						goto l2356
+					}
+					if buffer[position] != rune(']') {
+						goto l2356
 					}
-					add(rulePegText, position1068)
+					position++
+					add(rulePegText, position2358)
 				}
-				if !_rules[ruleAction55]() {
-					goto l1066
+				if !_rules[ruleAction122]() {
+					goto l2356
 				}
-				add(ruleandExpr, position1067)
+				add(ruleArrayExpr, position2357)
 			}
 			return true
-		l1066:
-			position, tokenIndex = position1066, tokenIndex1066
+		l2356:
+			position, tokenIndex = position2356, tokenIndex2356
 			return false
 		},
-		/* 75 notExpr <- <(<((Not sp)? comparisonExpr)> Action56)> */
+		/* 151 MapExpr <- <(<('{' spOpt (KeyValuePair (spOpt ',' spOpt KeyValuePair)*)? spOpt '}')> Action123)> */
 		func() bool {
-			position1071, tokenIndex1071 := position, tokenIndex
+			position2365, tokenIndex2365 := position, tokenIndex
 			{
-				position1072 := position
+				position2366 := position
 				{
-					position1073 := position
+					position2367 := position
+					if buffer[position] != rune('{') {
+						goto l2365
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2365
+					}
 					{
-						position1074, tokenIndex1074 := position, tokenIndex
-						if !_rules[ruleNot]() {
-							goto l1074
+						position2368, tokenIndex2368 := position, tokenIndex
+						if !_rules[ruleKeyValuePair]() {
+							goto l2368
 						}
-						if !_rules[rulesp]() {
-							goto l1074
+					l2370:
+						{
+							position2371, tokenIndex2371 := position, tokenIndex
+							if !_rules[rulespOpt]() {
+								goto l2371
+							}
+							if buffer[position] != rune(',') {
+								goto l2371
+							}
+							position++
+							if !_rules[rulespOpt]() {
+								goto l2371
+							}
+							if !_rules[ruleKeyValuePair]() {
+								goto l2371
+							}
+							goto l2370
+						l2371:
+							position, tokenIndex = position2371, tokenIndex2371
 						}
-						goto l1075
-					l1074:
-						position, tokenIndex = position1074, tokenIndex1074
+						goto l2369
+					l2368:
+						position, tokenIndex = position2368, tokenIndex2368
 					}
-				l1075:
-					if !_rules[rulecomparisonExpr]() {
-						goto l1071
+				l2369:
+					if !_rules[rulespOpt]() {
+						goto l2365
+					}
+					if buffer[position] != rune('}') {
+						goto l2365
 					}
-					add(rulePegText, position1073)
+					position++
+					add(rulePegText, position2367)
 				}
-				if !_rules[ruleAction56]() {
-					goto l1071
+				if !_rules[ruleAction123]() {
+					goto l2365
 				}
-				add(rulenotExpr, position1072)
+				add(ruleMapExpr, position2366)
 			}
 			return true
-		l1071:
-			position, tokenIndex = position1071, tokenIndex1071
+		l2365:
+			position, tokenIndex = position2365, tokenIndex2365
 			return false
 		},
-		/* 76 comparisonExpr <- <(<(otherOpExpr (spOpt ComparisonOp spOpt otherOpExpr)?)> Action57)> */
+		/* 152 KeyValuePair <- <(<(StringLiteral spOpt ':' spOpt ExpressionOrWildcard)> Action124)> */
 		func() bool {
-			position1076, tokenIndex1076 := position, tokenIndex
+			position2372, tokenIndex2372 := position, tokenIndex
 			{
-				position1077 := position
+				position2373 := position
 				{
-					position1078 := position
-					if !_rules[ruleotherOpExpr]() {
-						goto l1076
+					position2374 := position
+					if !_rules[ruleStringLiteral]() {
+						goto l2372
 					}
-					{
-						position1079, tokenIndex1079 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l1079
-						}
-						if !_rules[ruleComparisonOp]() {
-							goto l1079
-						}
-						if !_rules[rulespOpt]() {
-							goto l1079
-						}
-						if !_rules[ruleotherOpExpr]() {
-							goto l1079
-						}
-						goto l1080
-					l1079:
-						position, tokenIndex = position1079, tokenIndex1079
+					if !_rules[rulespOpt]() {
+						goto l2372
+					}
+					if buffer[position] != rune(':') {
+						goto l2372
+					}
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2372
+					}
+					if !_rules[ruleExpressionOrWildcard]() {
+						goto l2372
 					}
-				l1080:
-					add(rulePegText, position1078)
+					add(rulePegText, position2374)
 				}
-				if !_rules[ruleAction57]() {
-					goto l1076
+				if !_rules[ruleAction124]() {
+					goto l2372
 				}
-				add(rulecomparisonExpr, position1077)
+				add(ruleKeyValuePair, position2373)
 			}
 			return true
-		l1076:
-			position, tokenIndex = position1076, tokenIndex1076
+		l2372:
+			position, tokenIndex = position2372, tokenIndex2372
 			return false
 		},
-		/* 77 otherOpExpr <- <(<(isExpr (spOpt OtherOp spOpt isExpr)*)> Action58)> */
+		/* 153 Case <- <(ConditionCase / ExpressionCase)> */
 		func() bool {
-			position1081, tokenIndex1081 := position, tokenIndex
+			position2375, tokenIndex2375 := position, tokenIndex
 			{
-				position1082 := position
+				position2376 := position
 				{
-					position1083 := position
-					if !_rules[ruleisExpr]() {
-						goto l1081
+					position2377, tokenIndex2377 := position, tokenIndex
+					if !_rules[ruleConditionCase]() {
+						goto l2378
 					}
-				l1084:
-					{
-						position1085, tokenIndex1085 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l1085
-						}
-						if !_rules[ruleOtherOp]() {
-							goto l1085
-						}
-						if !_rules[rulespOpt]() {
-							goto l1085
-						}
-						if !_rules[ruleisExpr]() {
-							goto l1085
-						}
-						goto l1084
-					l1085:
-						position, tokenIndex = position1085, tokenIndex1085
+					goto l2377
+				l2378:
+					position, tokenIndex = position2377, tokenIndex2377
+					if !_rules[ruleExpressionCase]() {
+						goto l2375
 					}
-					add(rulePegText, position1083)
-				}
-				if !_rules[ruleAction58]() {
-					goto l1081
 				}
-				add(ruleotherOpExpr, position1082)
+			l2377:
+				add(ruleCase, position2376)
 			}
 			return true
-		l1081:
-			position, tokenIndex = position1081, tokenIndex1081
+		l2375:
+			position, tokenIndex = position2375, tokenIndex2375
 			return false
 		},
-		/* 78 isExpr <- <(<((RowValue sp IsOp sp Missing) / (termExpr (sp IsOp sp NullLiteral)?))> Action59)> */
+		/* 154 ConditionCase <- <(('c' / 'C') ('a' / 'A') ('s' / 'S') ('e' / 'E') <((sp WhenThenPair)+ (sp (('e' / 'E') ('l' / 'L') ('s' / 'S') ('e' / 'E')) sp Expression)? sp (('e' / 'E') ('n' / 'N') ('d' / 'D')))> Action125)> */
 		func() bool {
-			position1086, tokenIndex1086 := position, tokenIndex
+			position2379, tokenIndex2379 := position, tokenIndex
 			{
-				position1087 := position
+				position2380 := position
+				{
+					position2381, tokenIndex2381 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l2382
+					}
+					position++
+					goto l2381
+				l2382:
+					position, tokenIndex = position2381, tokenIndex2381
+					if buffer[position] != rune('C') {
+						goto l2379
+					}
+					position++
+				}
+			l2381:
+				{
+					position2383, tokenIndex2383 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l2384
+					}
+					position++
+					goto l2383
+				l2384:
+					position, tokenIndex = position2383, tokenIndex2383
+					if buffer[position] != rune('A') {
+						goto l2379
+					}
+					position++
+				}
+			l2383:
+				{
+					position2385, tokenIndex2385 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l2386
+					}
+					position++
+					goto l2385
+				l2386:
+					position, tokenIndex = position2385, tokenIndex2385
+					if buffer[position] != rune('S') {
+						goto l2379
+					}
+					position++
+				}
+			l2385:
+				{
+					position2387, tokenIndex2387 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l2388
+					}
+					position++
+					goto l2387
+				l2388:
+					position, tokenIndex = position2387, tokenIndex2387
+					if buffer[position] != rune('E') {
+						goto l2379
+					}
+					position++
+				}
+			l2387:
 				{
-					position1088 := position
+					position2389 := position
+					if !_rules[rulesp]() {
+						goto l2379
+					}
+					if !_rules[ruleWhenThenPair]() {
+						goto l2379
+					}
+				l2390:
 					{
-						position1089, tokenIndex1089 := position, tokenIndex
-						if !_rules[ruleRowValue]() {
-							goto l1090
-						}
+						position2391, tokenIndex2391 := position, tokenIndex
 						if !_rules[rulesp]() {
-							goto l1090
+							goto l2391
 						}
-						if !_rules[ruleIsOp]() {
-							goto l1090
+						if !_rules[ruleWhenThenPair]() {
+							goto l2391
 						}
+						goto l2390
+					l2391:
+						position, tokenIndex = position2391, tokenIndex2391
+					}
+					{
+						position2392, tokenIndex2392 := position, tokenIndex
 						if !_rules[rulesp]() {
-							goto l1090
+							goto l2392
 						}
-						if !_rules[ruleMissing]() {
-							goto l1090
+						{
+							position2394, tokenIndex2394 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2395
+							}
+							position++
+							goto l2394
+						l2395:
+							position, tokenIndex = position2394, tokenIndex2394
+							if buffer[position] != rune('E') {
+								goto l2392
+							}
+							position++
 						}
-						goto l1089
-					l1090:
-						position, tokenIndex = position1089, tokenIndex1089
-						if !_rules[ruletermExpr]() {
-							goto l1086
+					l2394:
+						{
+							position2396, tokenIndex2396 := position, tokenIndex
+							if buffer[position] != rune('l') {
+								goto l2397
+							}
+							position++
+							goto l2396
+						l2397:
+							position, tokenIndex = position2396, tokenIndex2396
+							if buffer[position] != rune('L') {
+								goto l2392
+							}
+							position++
 						}
+					l2396:
 						{
-							position1091, tokenIndex1091 := position, tokenIndex
-							if !_rules[rulesp]() {
-								goto l1091
+							position2398, tokenIndex2398 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l2399
 							}
-							if !_rules[ruleIsOp]() {
-								goto l1091
+							position++
+							goto l2398
+						l2399:
+							position, tokenIndex = position2398, tokenIndex2398
+							if buffer[position] != rune('S') {
+								goto l2392
 							}
-							if !_rules[rulesp]() {
-								goto l1091
+							position++
+						}
+					l2398:
+						{
+							position2400, tokenIndex2400 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2401
 							}
-							if !_rules[ruleNullLiteral]() {
-								goto l1091
+							position++
+							goto l2400
+						l2401:
+							position, tokenIndex = position2400, tokenIndex2400
+							if buffer[position] != rune('E') {
+								goto l2392
 							}
-							goto l1092
-						l1091:
-							position, tokenIndex = position1091, tokenIndex1091
+							position++
+						}
+					l2400:
+						if !_rules[rulesp]() {
+							goto l2392
+						}
+						if !_rules[ruleExpression]() {
+							goto l2392
 						}
-					l1092:
+						goto l2393
+					l2392:
+						position, tokenIndex = position2392, tokenIndex2392
 					}
-				l1089:
-					add(rulePegText, position1088)
+				l2393:
+					if !_rules[rulesp]() {
+						goto l2379
+					}
+					{
+						position2402, tokenIndex2402 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2403
+						}
+						position++
+						goto l2402
+					l2403:
+						position, tokenIndex = position2402, tokenIndex2402
+						if buffer[position] != rune('E') {
+							goto l2379
+						}
+						position++
+					}
+				l2402:
+					{
+						position2404, tokenIndex2404 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2405
+						}
+						position++
+						goto l2404
+					l2405:
+						position, tokenIndex = position2404, tokenIndex2404
+						if buffer[position] != rune('N') {
+							goto l2379
+						}
+						position++
+					}
+				l2404:
+					{
+						position2406, tokenIndex2406 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2407
+						}
+						position++
+						goto l2406
+					l2407:
+						position, tokenIndex = position2406, tokenIndex2406
+						if buffer[position] != rune('D') {
+							goto l2379
+						}
+						position++
+					}
+				l2406:
+					add(rulePegText, position2389)
 				}
-				if !_rules[ruleAction59]() {
-					goto l1086
+				if !_rules[ruleAction125]() {
+					goto l2379
 				}
-				add(ruleisExpr, position1087)
+				add(ruleConditionCase, position2380)
 			}
 			return true
-		l1086:
-			position, tokenIndex = position1086, tokenIndex1086
+		l2379:
+			position, tokenIndex = position2379, tokenIndex2379
 			return false
 		},
-		/* 79 termExpr <- <(<(productExpr (spOpt PlusMinusOp spOpt productExpr)*)> Action60)> */
+		/* 155 ExpressionCase <- <(('c' / 'C') ('a' / 'A') ('s' / 'S') ('e' / 'E') sp Expression <((sp WhenThenPair)+ (sp (('e' / 'E') ('l' / 'L') ('s' / 'S') ('e' / 'E')) sp Expression)? sp (('e' / 'E') ('n' / 'N') ('d' / 'D')))> Action126)> */
 		func() bool {
-			position1093, tokenIndex1093 := position, tokenIndex
+			position2408, tokenIndex2408 := position, tokenIndex
 			{
-				position1094 := position
+				position2409 := position
 				{
-					position1095 := position
-					if !_rules[ruleproductExpr]() {
-						goto l1093
+					position2410, tokenIndex2410 := position, tokenIndex
+					if buffer[position] != rune('c') {
+						goto l2411
+					}
+					position++
+					goto l2410
+				l2411:
+					position, tokenIndex = position2410, tokenIndex2410
+					if buffer[position] != rune('C') {
+						goto l2408
+					}
+					position++
+				}
+			l2410:
+				{
+					position2412, tokenIndex2412 := position, tokenIndex
+					if buffer[position] != rune('a') {
+						goto l2413
+					}
+					position++
+					goto l2412
+				l2413:
+					position, tokenIndex = position2412, tokenIndex2412
+					if buffer[position] != rune('A') {
+						goto l2408
+					}
+					position++
+				}
+			l2412:
+				{
+					position2414, tokenIndex2414 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l2415
+					}
+					position++
+					goto l2414
+				l2415:
+					position, tokenIndex = position2414, tokenIndex2414
+					if buffer[position] != rune('S') {
+						goto l2408
+					}
+					position++
+				}
+			l2414:
+				{
+					position2416, tokenIndex2416 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l2417
+					}
+					position++
+					goto l2416
+				l2417:
+					position, tokenIndex = position2416, tokenIndex2416
+					if buffer[position] != rune('E') {
+						goto l2408
+					}
+					position++
+				}
+			l2416:
+				if !_rules[rulesp]() {
+					goto l2408
+				}
+				if !_rules[ruleExpression]() {
+					goto l2408
+				}
+				{
+					position2418 := position
+					if !_rules[rulesp]() {
+						goto l2408
+					}
+					if !_rules[ruleWhenThenPair]() {
+						goto l2408
 					}
-				l1096:
+				l2419:
 					{
-						position1097, tokenIndex1097 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l1097
+						position2420, tokenIndex2420 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2420
 						}
-						if !_rules[rulePlusMinusOp]() {
-							goto l1097
+						if !_rules[ruleWhenThenPair]() {
+							goto l2420
 						}
-						if !_rules[rulespOpt]() {
-							goto l1097
+						goto l2419
+					l2420:
+						position, tokenIndex = position2420, tokenIndex2420
+					}
+					{
+						position2421, tokenIndex2421 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l2421
+						}
+						{
+							position2423, tokenIndex2423 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2424
+							}
+							position++
+							goto l2423
+						l2424:
+							position, tokenIndex = position2423, tokenIndex2423
+							if buffer[position] != rune('E') {
+								goto l2421
+							}
+							position++
+						}
+					l2423:
+						{
+							position2425, tokenIndex2425 := position, tokenIndex
+							if buffer[position] != rune('l') {
+								goto l2426
+							}
+							position++
+							goto l2425
+						l2426:
+							position, tokenIndex = position2425, tokenIndex2425
+							if buffer[position] != rune('L') {
+								goto l2421
+							}
+							position++
+						}
+					l2425:
+						{
+							position2427, tokenIndex2427 := position, tokenIndex
+							if buffer[position] != rune('s') {
+								goto l2428
+							}
+							position++
+							goto l2427
+						l2428:
+							position, tokenIndex = position2427, tokenIndex2427
+							if buffer[position] != rune('S') {
+								goto l2421
+							}
+							position++
+						}
+					l2427:
+						{
+							position2429, tokenIndex2429 := position, tokenIndex
+							if buffer[position] != rune('e') {
+								goto l2430
+							}
+							position++
+							goto l2429
+						l2430:
+							position, tokenIndex = position2429, tokenIndex2429
+							if buffer[position] != rune('E') {
+								goto l2421
+							}
+							position++
+						}
+					l2429:
+						if !_rules[rulesp]() {
+							goto l2421
 						}
-						if !_rules[ruleproductExpr]() {
-							goto l1097
+						if !_rules[ruleExpression]() {
+							goto l2421
 						}
-						goto l1096
-					l1097:
-						position, tokenIndex = position1097, tokenIndex1097
+						goto l2422
+					l2421:
+						position, tokenIndex = position2421, tokenIndex2421
 					}
-					add(rulePegText, position1095)
-				}
-				if !_rules[ruleAction60]() {
-					goto l1093
-				}
-				add(ruletermExpr, position1094)
-			}
-			return true
-		l1093:
-			position, tokenIndex = position1093, tokenIndex1093
-			return false
-		},
-		/* 80 productExpr <- <(<(minusExpr (spOpt MultDivOp spOpt minusExpr)*)> Action61)> */
-		func() bool {
-			position1098, tokenIndex1098 := position, tokenIndex
-			{
-				position1099 := position
-				{
-					position1100 := position
-					if !_rules[ruleminusExpr]() {
-						goto l1098
+				l2422:
+					if !_rules[rulesp]() {
+						goto l2408
 					}
-				l1101:
 					{
-						position1102, tokenIndex1102 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l1102
-						}
-						if !_rules[ruleMultDivOp]() {
-							goto l1102
-						}
-						if !_rules[rulespOpt]() {
-							goto l1102
+						position2431, tokenIndex2431 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2432
 						}
-						if !_rules[ruleminusExpr]() {
-							goto l1102
+						position++
+						goto l2431
+					l2432:
+						position, tokenIndex = position2431, tokenIndex2431
+						if buffer[position] != rune('E') {
+							goto l2408
 						}
-						goto l1101
-					l1102:
-						position, tokenIndex = position1102, tokenIndex1102
+						position++
 					}
-					add(rulePegText, position1100)
-				}
-				if !_rules[ruleAction61]() {
-					goto l1098
-				}
-				add(ruleproductExpr, position1099)
-			}
-			return true
-		l1098:
-			position, tokenIndex = position1098, tokenIndex1098
-			return false
-		},
-		/* 81 minusExpr <- <(<((UnaryMinus spOpt)? castExpr)> Action62)> */
-		func() bool {
-			position1103, tokenIndex1103 := position, tokenIndex
-			{
-				position1104 := position
-				{
-					position1105 := position
+				l2431:
 					{
-						position1106, tokenIndex1106 := position, tokenIndex
-						if !_rules[ruleUnaryMinus]() {
-							goto l1106
+						position2433, tokenIndex2433 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2434
 						}
-						if !_rules[rulespOpt]() {
-							goto l1106
+						position++
+						goto l2433
+					l2434:
+						position, tokenIndex = position2433, tokenIndex2433
+						if buffer[position] != rune('N') {
+							goto l2408
 						}
-						goto l1107
-					l1106:
-						position, tokenIndex = position1106, tokenIndex1106
-					}
-				l1107:
-					if !_rules[rulecastExpr]() {
-						goto l1103
-					}
-					add(rulePegText, position1105)
-				}
-				if !_rules[ruleAction62]() {
-					goto l1103
-				}
-				add(ruleminusExpr, position1104)
-			}
-			return true
-		l1103:
-			position, tokenIndex = position1103, tokenIndex1103
-			return false
-		},
-		/* 82 castExpr <- <(<(baseExpr (spOpt (':' ':') spOpt Type)?)> Action63)> */
-		func() bool {
-			position1108, tokenIndex1108 := position, tokenIndex
-			{
-				position1109 := position
-				{
-					position1110 := position
-					if !_rules[rulebaseExpr]() {
-						goto l1108
+						position++
 					}
+				l2433:
 					{
-						position1111, tokenIndex1111 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l1111
-						}
-						if buffer[position] != rune(':') {
-							goto l1111
+						position2435, tokenIndex2435 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2436
 						}
 						position++
-						if buffer[position] != rune(':') {
-							goto l1111
+						goto l2435
+					l2436:
+						position, tokenIndex = position2435, tokenIndex2435
+						if buffer[position] != rune('D') {
+							goto l2408
 						}
 						position++
-						if !_rules[rulespOpt]() {
-							goto l1111
-						}
-						if !_rules[ruleType]() {
-							goto l1111
-						}
-						goto l1112
-					l1111:
-						position, tokenIndex = position1111, tokenIndex1111
 					}
-				l1112:
-					add(rulePegText, position1110)
+				l2435:
+					add(rulePegText, position2418)
 				}
-				if !_rules[ruleAction63]() {
-					goto l1108
+				if !_rules[ruleAction126]() {
+					goto l2408
 				}
-				add(rulecastExpr, position1109)
+				add(ruleExpressionCase, position2409)
 			}
 			return true
-		l1108:
-			position, tokenIndex = position1108, tokenIndex1108
+		l2408:
+			position, tokenIndex = position2408, tokenIndex2408
 			return false
 		},
-		/* 83 baseExpr <- <(('(' spOpt Expression spOpt ')') / MapExpr / BooleanLiteral / NullLiteral / Case / RowMeta / FuncTypeCast / FuncAppSelector / FuncApp / RowValue / ArrayExpr / Literal)> */
+		/* 156 WhenThenPair <- <(('w' / 'W') ('h' / 'H') ('e' / 'E') ('n' / 'N') sp Expression sp (('t' / 'T') ('h' / 'H') ('e' / 'E') ('n' / 'N')) sp ExpressionOrWildcard Action127)> */
 		func() bool {
-			position1113, tokenIndex1113 := position, tokenIndex
+			position2437, tokenIndex2437 := position, tokenIndex
 			{
-				position1114 := position
+				position2438 := position
 				{
-					position1115, tokenIndex1115 := position, tokenIndex
-					if buffer[position] != rune('(') {
-						goto l1116
+					position2439, tokenIndex2439 := position, tokenIndex
+					if buffer[position] != rune('w') {
+						goto l2440
 					}
 					position++
-					if !_rules[rulespOpt]() {
-						goto l1116
+					goto l2439
+				l2440:
+					position, tokenIndex = position2439, tokenIndex2439
+					if buffer[position] != rune('W') {
+						goto l2437
 					}
-					if !_rules[ruleExpression]() {
-						goto l1116
+					position++
+				}
+			l2439:
+				{
+					position2441, tokenIndex2441 := position, tokenIndex
+					if buffer[position] != rune('h') {
+						goto l2442
 					}
-					if !_rules[rulespOpt]() {
-						goto l1116
+					position++
+					goto l2441
+				l2442:
+					position, tokenIndex = position2441, tokenIndex2441
+					if buffer[position] != rune('H') {
+						goto l2437
 					}
-					if buffer[position] != rune(')') {
-						goto l1116
+					position++
+				}
+			l2441:
+				{
+					position2443, tokenIndex2443 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l2444
 					}
 					position++
-					goto l1115
-				l1116:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleMapExpr]() {
-						goto l1117
+					goto l2443
+				l2444:
+					position, tokenIndex = position2443, tokenIndex2443
+					if buffer[position] != rune('E') {
+						goto l2437
 					}
-					goto l1115
-				l1117:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleBooleanLiteral]() {
-						goto l1118
+					position++
+				}
+			l2443:
+				{
+					position2445, tokenIndex2445 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l2446
 					}
-					goto l1115
-				l1118:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleNullLiteral]() {
-						goto l1119
+					position++
+					goto l2445
+				l2446:
+					position, tokenIndex = position2445, tokenIndex2445
+					if buffer[position] != rune('N') {
+						goto l2437
 					}
-					goto l1115
-				l1119:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleCase]() {
-						goto l1120
+					position++
+				}
+			l2445:
+				if !_rules[rulesp]() {
+					goto l2437
+				}
+				if !_rules[ruleExpression]() {
+					goto l2437
+				}
+				if !_rules[rulesp]() {
+					goto l2437
+				}
+				{
+					position2447, tokenIndex2447 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l2448
 					}
-					goto l1115
-				l1120:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleRowMeta]() {
-						goto l1121
+					position++
+					goto l2447
+				l2448:
+					position, tokenIndex = position2447, tokenIndex2447
+					if buffer[position] != rune('T') {
+						goto l2437
 					}
-					goto l1115
-				l1121:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleFuncTypeCast]() {
-						goto l1122
+					position++
+				}
+			l2447:
+				{
+					position2449, tokenIndex2449 := position, tokenIndex
+					if buffer[position] != rune('h') {
+						goto l2450
 					}
-					goto l1115
-				l1122:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleFuncAppSelector]() {
-						goto l1123
+					position++
+					goto l2449
+				l2450:
+					position, tokenIndex = position2449, tokenIndex2449
+					if buffer[position] != rune('H') {
+						goto l2437
 					}
-					goto l1115
-				l1123:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleFuncApp]() {
-						goto l1124
+					position++
+				}
+			l2449:
+				{
+					position2451, tokenIndex2451 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l2452
 					}
-					goto l1115
-				l1124:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleRowValue]() {
-						goto l1125
+					position++
+					goto l2451
+				l2452:
+					position, tokenIndex = position2451, tokenIndex2451
+					if buffer[position] != rune('E') {
+						goto l2437
 					}
-					goto l1115
-				l1125:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleArrayExpr]() {
-						goto l1126
+					position++
+				}
+			l2451:
+				{
+					position2453, tokenIndex2453 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l2454
 					}
-					goto l1115
-				l1126:
-					position, tokenIndex = position1115, tokenIndex1115
-					if !_rules[ruleLiteral]() {
-						goto l1113
+					position++
+					goto l2453
+				l2454:
+					position, tokenIndex = position2453, tokenIndex2453
+					if buffer[position] != rune('N') {
+						goto l2437
 					}
+					position++
+				}
+			l2453:
+				if !_rules[rulesp]() {
+					goto l2437
+				}
+				if !_rules[ruleExpressionOrWildcard]() {
+					goto l2437
+				}
+				if !_rules[ruleAction127]() {
+					goto l2437
 				}
-			l1115:
-				add(rulebaseExpr, position1114)
+				add(ruleWhenThenPair, position2438)
 			}
 			return true
-		l1113:
-			position, tokenIndex = position1113, tokenIndex1113
+		l2437:
+			position, tokenIndex = position2437, tokenIndex2437
 			return false
 		},
-		/* 84 FuncTypeCast <- <(<(('c' / 'C') ('a' / 'A') ('s' / 'S') ('t' / 'T') spOpt '(' spOpt Expression sp (('a' / 'A') ('s' / 'S')) sp Type spOpt ')')> Action64)> */
+		/* 157 Literal <- <(FloatLiteral / NumericLiteral / StringLiteral)> */
 		func() bool {
-			position1127, tokenIndex1127 := position, tokenIndex
+			position2455, tokenIndex2455 := position, tokenIndex
 			{
-				position1128 := position
+				position2456 := position
 				{
-					position1129 := position
-					{
-						position1130, tokenIndex1130 := position, tokenIndex
-						if buffer[position] != rune('c') {
-							goto l1131
-						}
-						position++
-						goto l1130
-					l1131:
-						position, tokenIndex = position1130, tokenIndex1130
-						if buffer[position] != rune('C') {
-							goto l1127
-						}
-						position++
-					}
-				l1130:
-					{
-						position1132, tokenIndex1132 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1133
-						}
-						position++
-						goto l1132
-					l1133:
-						position, tokenIndex = position1132, tokenIndex1132
-						if buffer[position] != rune('A') {
-							goto l1127
-						}
-						position++
-					}
-				l1132:
-					{
-						position1134, tokenIndex1134 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1135
-						}
-						position++
-						goto l1134
-					l1135:
-						position, tokenIndex = position1134, tokenIndex1134
-						if buffer[position] != rune('S') {
-							goto l1127
-						}
-						position++
-					}
-				l1134:
-					{
-						position1136, tokenIndex1136 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1137
-						}
-						position++
-						goto l1136
-					l1137:
-						position, tokenIndex = position1136, tokenIndex1136
-						if buffer[position] != rune('T') {
-							goto l1127
-						}
-						position++
-					}
-				l1136:
-					if !_rules[rulespOpt]() {
-						goto l1127
-					}
-					if buffer[position] != rune('(') {
-						goto l1127
+					position2457, tokenIndex2457 := position, tokenIndex
+					if !_rules[ruleFloatLiteral]() {
+						goto l2458
 					}
-					position++
-					if !_rules[rulespOpt]() {
-						goto l1127
+					goto l2457
+				l2458:
+					position, tokenIndex = position2457, tokenIndex2457
+					if !_rules[ruleNumericLiteral]() {
+						goto l2459
 					}
-					if !_rules[ruleExpression]() {
-						goto l1127
+					goto l2457
+				l2459:
+					position, tokenIndex = position2457, tokenIndex2457
+					if !_rules[ruleStringLiteral]() {
+						goto l2455
 					}
-					if !_rules[rulesp]() {
-						goto l1127
+				}
+			l2457:
+				add(ruleLiteral, position2456)
+			}
+			return true
+		l2455:
+			position, tokenIndex = position2455, tokenIndex2455
+			return false
+		},
+		/* 158 ComparisonOp <- <(Equal / NotEqual / LessOrEqual / Less / GreaterOrEqual / Greater / NotEqual)> */
+		func() bool {
+			position2460, tokenIndex2460 := position, tokenIndex
+			{
+				position2461 := position
+				{
+					position2462, tokenIndex2462 := position, tokenIndex
+					if !_rules[ruleEqual]() {
+						goto l2463
 					}
-					{
-						position1138, tokenIndex1138 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1139
-						}
-						position++
-						goto l1138
-					l1139:
-						position, tokenIndex = position1138, tokenIndex1138
-						if buffer[position] != rune('A') {
-							goto l1127
-						}
-						position++
+					goto l2462
+				l2463:
+					position, tokenIndex = position2462, tokenIndex2462
+					if !_rules[ruleNotEqual]() {
+						goto l2464
 					}
-				l1138:
-					{
-						position1140, tokenIndex1140 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1141
-						}
-						position++
-						goto l1140
-					l1141:
-						position, tokenIndex = position1140, tokenIndex1140
-						if buffer[position] != rune('S') {
-							goto l1127
-						}
-						position++
+					goto l2462
+				l2464:
+					position, tokenIndex = position2462, tokenIndex2462
+					if !_rules[ruleLessOrEqual]() {
+						goto l2465
 					}
-				l1140:
-					if !_rules[rulesp]() {
-						goto l1127
+					goto l2462
+				l2465:
+					position, tokenIndex = position2462, tokenIndex2462
+					if !_rules[ruleLess]() {
+						goto l2466
 					}
-					if !_rules[ruleType]() {
-						goto l1127
+					goto l2462
+				l2466:
+					position, tokenIndex = position2462, tokenIndex2462
+					if !_rules[ruleGreaterOrEqual]() {
+						goto l2467
 					}
-					if !_rules[rulespOpt]() {
-						goto l1127
+					goto l2462
+				l2467:
+					position, tokenIndex = position2462, tokenIndex2462
+					if !_rules[ruleGreater]() {
+						goto l2468
 					}
-					if buffer[position] != rune(')') {
-						goto l1127
+					goto l2462
+				l2468:
+					position, tokenIndex = position2462, tokenIndex2462
+					if !_rules[ruleNotEqual]() {
+						goto l2460
 					}
-					position++
-					add(rulePegText, position1129)
-				}
-				if !_rules[ruleAction64]() {
-					goto l1127
 				}
-				add(ruleFuncTypeCast, position1128)
+			l2462:
+				add(ruleComparisonOp, position2461)
 			}
 			return true
-		l1127:
-			position, tokenIndex = position1127, tokenIndex1127
+		l2460:
+			position, tokenIndex = position2460, tokenIndex2460
 			return false
 		},
-		/* 85 FuncApp <- <(FuncAppWithOrderBy / FuncAppWithoutOrderBy)> */
+		/* 159 OtherOp <- <Concat> */
 		func() bool {
-			position1142, tokenIndex1142 := position, tokenIndex
+			position2469, tokenIndex2469 := position, tokenIndex
 			{
-				position1143 := position
-				{
-					position1144, tokenIndex1144 := position, tokenIndex
-					if !_rules[ruleFuncAppWithOrderBy]() {
-						goto l1145
+				position2470 := position
+				if !_rules[ruleConcat]() {
+					goto l2469
+				}
+				add(ruleOtherOp, position2470)
+			}
+			return true
+		l2469:
+			position, tokenIndex = position2469, tokenIndex2469
+			return false
+		},
+		/* 160 IsOp <- <(IsNot / Is)> */
+		func() bool {
+			position2471, tokenIndex2471 := position, tokenIndex
+			{
+				position2472 := position
+				{
+					position2473, tokenIndex2473 := position, tokenIndex
+					if !_rules[ruleIsNot]() {
+						goto l2474
 					}
-					goto l1144
-				l1145:
-					position, tokenIndex = position1144, tokenIndex1144
-					if !_rules[ruleFuncAppWithoutOrderBy]() {
-						goto l1142
+					goto l2473
+				l2474:
+					position, tokenIndex = position2473, tokenIndex2473
+					if !_rules[ruleIs]() {
+						goto l2471
 					}
 				}
-			l1144:
-				add(ruleFuncApp, position1143)
+			l2473:
+				add(ruleIsOp, position2472)
 			}
 			return true
-		l1142:
-			position, tokenIndex = position1142, tokenIndex1142
+		l2471:
+			position, tokenIndex = position2471, tokenIndex2471
 			return false
 		},
-		/* 86 FuncAppSelector <- <(FuncApp FuncElemAccessor Action65)> */
+		/* 161 PlusMinusOp <- <(Plus / Minus)> */
 		func() bool {
-			position1146, tokenIndex1146 := position, tokenIndex
+			position2475, tokenIndex2475 := position, tokenIndex
 			{
-				position1147 := position
-				if !_rules[ruleFuncApp]() {
-					goto l1146
-				}
-				if !_rules[ruleFuncElemAccessor]() {
-					goto l1146
-				}
-				if !_rules[ruleAction65]() {
-					goto l1146
+				position2476 := position
+				{
+					position2477, tokenIndex2477 := position, tokenIndex
+					if !_rules[rulePlus]() {
+						goto l2478
+					}
+					goto l2477
+				l2478:
+					position, tokenIndex = position2477, tokenIndex2477
+					if !_rules[ruleMinus]() {
+						goto l2475
+					}
 				}
-				add(ruleFuncAppSelector, position1147)
+			l2477:
+				add(rulePlusMinusOp, position2476)
 			}
 			return true
-		l1146:
-			position, tokenIndex = position1146, tokenIndex1146
+		l2475:
+			position, tokenIndex = position2475, tokenIndex2475
 			return false
 		},
-		/* 87 FuncElemAccessor <- <(<jsonGetPathNonHead+> Action66)> */
+		/* 162 MultDivOp <- <(Multiply / Divide / Modulo)> */
 		func() bool {
-			position1148, tokenIndex1148 := position, tokenIndex
+			position2479, tokenIndex2479 := position, tokenIndex
 			{
-				position1149 := position
+				position2480 := position
 				{
-					position1150 := position
-					if !_rules[rulejsonGetPathNonHead]() {
-						goto l1148
+					position2481, tokenIndex2481 := position, tokenIndex
+					if !_rules[ruleMultiply]() {
+						goto l2482
 					}
-				l1151:
-					{
-						position1152, tokenIndex1152 := position, tokenIndex
-						if !_rules[rulejsonGetPathNonHead]() {
-							goto l1152
-						}
-						goto l1151
-					l1152:
-						position, tokenIndex = position1152, tokenIndex1152
+					goto l2481
+				l2482:
+					position, tokenIndex = position2481, tokenIndex2481
+					if !_rules[ruleDivide]() {
+						goto l2483
+					}
+					goto l2481
+				l2483:
+					position, tokenIndex = position2481, tokenIndex2481
+					if !_rules[ruleModulo]() {
+						goto l2479
 					}
-					add(rulePegText, position1150)
-				}
-				if !_rules[ruleAction66]() {
-					goto l1148
 				}
-				add(ruleFuncElemAccessor, position1149)
+			l2481:
+				add(ruleMultDivOp, position2480)
 			}
 			return true
-		l1148:
-			position, tokenIndex = position1148, tokenIndex1148
+		l2479:
+			position, tokenIndex = position2479, tokenIndex2479
 			return false
 		},
-		/* 88 FuncAppWithOrderBy <- <(Function spOpt '(' spOpt FuncParams sp ParamsOrder spOpt ')' Action67)> */
+		/* 163 Stream <- <(<ident> Action128)> */
 		func() bool {
-			position1153, tokenIndex1153 := position, tokenIndex
+			position2484, tokenIndex2484 := position, tokenIndex
 			{
-				position1154 := position
-				if !_rules[ruleFunction]() {
-					goto l1153
-				}
-				if !_rules[rulespOpt]() {
-					goto l1153
-				}
-				if buffer[position] != rune('(') {
-					goto l1153
-				}
-				position++
-				if !_rules[rulespOpt]() {
-					goto l1153
-				}
-				if !_rules[ruleFuncParams]() {
-					goto l1153
-				}
-				if !_rules[rulesp]() {
-					goto l1153
-				}
-				if !_rules[ruleParamsOrder]() {
-					goto l1153
-				}
-				if !_rules[rulespOpt]() {
-					goto l1153
-				}
-				if buffer[position] != rune(')') {
-					goto l1153
+				position2485 := position
+				{
+					position2486 := position
+					if !_rules[ruleident]() {
+						goto l2484
+					}
+					add(rulePegText, position2486)
 				}
-				position++
-				if !_rules[ruleAction67]() {
-					goto l1153
+				if !_rules[ruleAction128]() {
+					goto l2484
 				}
-				add(ruleFuncAppWithOrderBy, position1154)
+				add(ruleStream, position2485)
 			}
 			return true
-		l1153:
-			position, tokenIndex = position1153, tokenIndex1153
+		l2484:
+			position, tokenIndex = position2484, tokenIndex2484
 			return false
 		},
-		/* 89 FuncAppWithoutOrderBy <- <(Function spOpt '(' spOpt FuncParams <spOpt> ')' Action68)> */
+		/* 164 RowMeta <- <(RowTimestamp / RowMetadata)> */
 		func() bool {
-			position1155, tokenIndex1155 := position, tokenIndex
+			position2487, tokenIndex2487 := position, tokenIndex
 			{
-				position1156 := position
-				if !_rules[ruleFunction]() {
-					goto l1155
-				}
-				if !_rules[rulespOpt]() {
-					goto l1155
-				}
-				if buffer[position] != rune('(') {
-					goto l1155
-				}
-				position++
-				if !_rules[rulespOpt]() {
-					goto l1155
-				}
-				if !_rules[ruleFuncParams]() {
-					goto l1155
-				}
+				position2488 := position
 				{
-					position1157 := position
-					if !_rules[rulespOpt]() {
-						goto l1155
+					position2489, tokenIndex2489 := position, tokenIndex
+					if !_rules[ruleRowTimestamp]() {
+						goto l2490
+					}
+					goto l2489
+				l2490:
+					position, tokenIndex = position2489, tokenIndex2489
+					if !_rules[ruleRowMetadata]() {
+						goto l2487
 					}
-					add(rulePegText, position1157)
-				}
-				if buffer[position] != rune(')') {
-					goto l1155
-				}
-				position++
-				if !_rules[ruleAction68]() {
-					goto l1155
 				}
-				add(ruleFuncAppWithoutOrderBy, position1156)
+			l2489:
+				add(ruleRowMeta, position2488)
 			}
 			return true
-		l1155:
-			position, tokenIndex = position1155, tokenIndex1155
+		l2487:
+			position, tokenIndex = position2487, tokenIndex2487
 			return false
 		},
-		/* 90 FuncParams <- <(<(ExpressionOrWildcard (spOpt ',' spOpt ExpressionOrWildcard)*)?> Action69)> */
+		/* 165 RowTimestamp <- <(<((ident ':')? ('t' 's' '(' ')'))> Action129)> */
 		func() bool {
-			position1158, tokenIndex1158 := position, tokenIndex
+			position2491, tokenIndex2491 := position, tokenIndex
 			{
-				position1159 := position
+				position2492 := position
 				{
-					position1160 := position
+					position2493 := position
 					{
-						position1161, tokenIndex1161 := position, tokenIndex
-						if !_rules[ruleExpressionOrWildcard]() {
-							goto l1161
+						position2494, tokenIndex2494 := position, tokenIndex
+						if !_rules[ruleident]() {
+							goto l2494
 						}
-					l1163:
-						{
-							position1164, tokenIndex1164 := position, tokenIndex
-							if !_rules[rulespOpt]() {
-								goto l1164
-							}
-							if buffer[position] != rune(',') {
-								goto l1164
-							}
-							position++
-							if !_rules[rulespOpt]() {
-								goto l1164
-							}
-							if !_rules[ruleExpressionOrWildcard]() {
-								goto l1164
-							}
-							goto l1163
-						l1164:
-							position, tokenIndex = position1164, tokenIndex1164
+						if buffer[position] != rune(':') {
+							goto l2494
 						}
-						goto l1162
-					l1161:
-						position, tokenIndex = position1161, tokenIndex1161
+						position++
+						goto l2495
+					l2494:
+						position, tokenIndex = position2494, tokenIndex2494
+					}
+				l2495:
+					if buffer[position] != rune('t') {
+						goto l2491
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l2491
+					}
+					position++
+					if buffer[position] != rune('(') {
+						goto l2491
 					}
-				l1162:
-					add(rulePegText, position1160)
+					position++
+					if buffer[position] != rune(')') {
+						goto l2491
+					}
+					position++
+					add(rulePegText, position2493)
 				}
-				if !_rules[ruleAction69]() {
-					goto l1158
+				if !_rules[ruleAction129]() {
+					goto l2491
 				}
-				add(ruleFuncParams, position1159)
+				add(ruleRowTimestamp, position2492)
 			}
 			return true
-		l1158:
-			position, tokenIndex = position1158, tokenIndex1158
+		l2491:
+			position, tokenIndex = position2491, tokenIndex2491
 			return false
 		},
-		/* 91 ParamsOrder <- <(<(('o' / 'O') ('r' / 'R') ('d' / 'D') ('e' / 'E') ('r' / 'R') sp (('b' / 'B') ('y' / 'Y')) sp SortedExpression (spOpt ',' spOpt SortedExpression)*)> Action70)> */
+		/* 166 RowMetadata <- <(<((ident ':')? ('m' 'e' 't' 'a' '(') spOpt StringLiteral spOpt ')')> Action130)> */
 		func() bool {
-			position1165, tokenIndex1165 := position, tokenIndex
+			position2496, tokenIndex2496 := position, tokenIndex
 			{
-				position1166 := position
+				position2497 := position
 				{
-					position1167 := position
-					{
-						position1168, tokenIndex1168 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1169
-						}
-						position++
-						goto l1168
-					l1169:
-						position, tokenIndex = position1168, tokenIndex1168
-						if buffer[position] != rune('O') {
-							goto l1165
-						}
-						position++
-					}
-				l1168:
+					position2498 := position
 					{
-						position1170, tokenIndex1170 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1171
+						position2499, tokenIndex2499 := position, tokenIndex
+						if !_rules[ruleident]() {
+							goto l2499
 						}
-						position++
-						goto l1170
-					l1171:
-						position, tokenIndex = position1170, tokenIndex1170
-						if buffer[position] != rune('R') {
-							goto l1165
+						if buffer[position] != rune(':') {
+							goto l2499
 						}
 						position++
+						goto l2500
+					l2499:
+						position, tokenIndex = position2499, tokenIndex2499
 					}
-				l1170:
-					{
-						position1172, tokenIndex1172 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1173
-						}
-						position++
-						goto l1172
-					l1173:
-						position, tokenIndex = position1172, tokenIndex1172
-						if buffer[position] != rune('D') {
-							goto l1165
-						}
-						position++
+				l2500:
+					if buffer[position] != rune('m') {
+						goto l2496
 					}
-				l1172:
-					{
-						position1174, tokenIndex1174 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1175
-						}
-						position++
-						goto l1174
-					l1175:
-						position, tokenIndex = position1174, tokenIndex1174
-						if buffer[position] != rune('E') {
-							goto l1165
-						}
-						position++
+					position++
+					if buffer[position] != rune('e') {
+						goto l2496
 					}
-				l1174:
-					{
-						position1176, tokenIndex1176 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1177
-						}
-						position++
-						goto l1176
-					l1177:
-						position, tokenIndex = position1176, tokenIndex1176
-						if buffer[position] != rune('R') {
-							goto l1165
-						}
-						position++
+					position++
+					if buffer[position] != rune('t') {
+						goto l2496
 					}
-				l1176:
-					if !_rules[rulesp]() {
-						goto l1165
+					position++
+					if buffer[position] != rune('a') {
+						goto l2496
 					}
-					{
-						position1178, tokenIndex1178 := position, tokenIndex
-						if buffer[position] != rune('b') {
-							goto l1179
-						}
-						position++
-						goto l1178
-					l1179:
-						position, tokenIndex = position1178, tokenIndex1178
-						if buffer[position] != rune('B') {
-							goto l1165
-						}
-						position++
+					position++
+					if buffer[position] != rune('(') {
+						goto l2496
 					}
-				l1178:
-					{
-						position1180, tokenIndex1180 := position, tokenIndex
-						if buffer[position] != rune('y') {
-							goto l1181
-						}
-						position++
-						goto l1180
-					l1181:
-						position, tokenIndex = position1180, tokenIndex1180
-						if buffer[position] != rune('Y') {
-							goto l1165
-						}
-						position++
+					position++
+					if !_rules[rulespOpt]() {
+						goto l2496
 					}
-				l1180:
-					if !_rules[rulesp]() {
-						goto l1165
+					if !_rules[ruleStringLiteral]() {
+						goto l2496
 					}
-					if !_rules[ruleSortedExpression]() {
-						goto l1165
+					if !_rules[rulespOpt]() {
+						goto l2496
 					}
-				l1182:
-					{
-						position1183, tokenIndex1183 := position, tokenIndex
-						if !_rules[rulespOpt]() {
-							goto l1183
-						}
-						if buffer[position] != rune(',') {
-							goto l1183
-						}
-						position++
-						if !_rules[rulespOpt]() {
-							goto l1183
-						}
-						if !_rules[ruleSortedExpression]() {
-							goto l1183
-						}
-						goto l1182
-					l1183:
-						position, tokenIndex = position1183, tokenIndex1183
+					if buffer[position] != rune(')') {
+						goto l2496
 					}
-					add(rulePegText, position1167)
+					position++
+					add(rulePegText, position2498)
 				}
-				if !_rules[ruleAction70]() {
-					goto l1165
+				if !_rules[ruleAction130]() {
+					goto l2496
 				}
-				add(ruleParamsOrder, position1166)
+				add(ruleRowMetadata, position2497)
 			}
 			return true
-		l1165:
-			position, tokenIndex = position1165, tokenIndex1165
+		l2496:
+			position, tokenIndex = position2496, tokenIndex2496
 			return false
 		},
-		/* 92 SortedExpression <- <(Expression OrderDirectionOpt Action71)> */
+		/* 167 RowValue <- <(<((ident ':' !':')? jsonGetPath)> Action131)> */
 		func() bool {
-			position1184, tokenIndex1184 := position, tokenIndex
+			position2501, tokenIndex2501 := position, tokenIndex
 			{
-				position1185 := position
-				if !_rules[ruleExpression]() {
-					goto l1184
-				}
-				if !_rules[ruleOrderDirectionOpt]() {
-					goto l1184
+				position2502 := position
+				{
+					position2503 := position
+					{
+						position2504, tokenIndex2504 := position, tokenIndex
+						if !_rules[ruleident]() {
+							goto l2504
+						}
+						if buffer[position] != rune(':') {
+							goto l2504
+						}
+						position++
+						{
+							position2506, tokenIndex2506 := position, tokenIndex
+							if buffer[position] != rune(':') {
+								goto l2506
+							}
+							position++
+							goto l2504
+						l2506:
+							position, tokenIndex = position2506, tokenIndex2506
+						}
+						goto l2505
+					l2504:
+						position, tokenIndex = position2504, tokenIndex2504
+					}
+				l2505:
+					if !_rules[rulejsonGetPath]() {
+						goto l2501
+					}
+					add(rulePegText, position2503)
 				}
-				if !_rules[ruleAction71]() {
-					goto l1184
+				if !_rules[ruleAction131]() {
+					goto l2501
 				}
-				add(ruleSortedExpression, position1185)
+				add(ruleRowValue, position2502)
 			}
 			return true
-		l1184:
-			position, tokenIndex = position1184, tokenIndex1184
+		l2501:
+			position, tokenIndex = position2501, tokenIndex2501
 			return false
 		},
-		/* 93 OrderDirectionOpt <- <(<(sp (Ascending / Descending))?> Action72)> */
+		/* 168 NumericLiteral <- <(<('-'? [0-9]+)> Action132)> */
 		func() bool {
-			position1186, tokenIndex1186 := position, tokenIndex
+			position2507, tokenIndex2507 := position, tokenIndex
 			{
-				position1187 := position
+				position2508 := position
 				{
-					position1188 := position
+					position2509 := position
 					{
-						position1189, tokenIndex1189 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1189
+						position2510, tokenIndex2510 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l2510
 						}
-						{
-							position1191, tokenIndex1191 := position, tokenIndex
-							if !_rules[ruleAscending]() {
-								goto l1192
-							}
-							goto l1191
-						l1192:
-							position, tokenIndex = position1191, tokenIndex1191
-							if !_rules[ruleDescending]() {
-								goto l1189
-							}
+						position++
+						goto l2511
+					l2510:
+						position, tokenIndex = position2510, tokenIndex2510
+					}
+				l2511:
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l2507
+					}
+					position++
+				l2512:
+					{
+						position2513, tokenIndex2513 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l2513
 						}
-					l1191:
-						goto l1190
-					l1189:
-						position, tokenIndex = position1189, tokenIndex1189
+						position++
+						goto l2512
+					l2513:
+						position, tokenIndex = position2513, tokenIndex2513
 					}
-				l1190:
-					add(rulePegText, position1188)
+					add(rulePegText, position2509)
 				}
-				if !_rules[ruleAction72]() {
-					goto l1186
+				if !_rules[ruleAction132]() {
+					goto l2507
 				}
-				add(ruleOrderDirectionOpt, position1187)
+				add(ruleNumericLiteral, position2508)
 			}
 			return true
-		l1186:
-			position, tokenIndex = position1186, tokenIndex1186
+		l2507:
+			position, tokenIndex = position2507, tokenIndex2507
 			return false
 		},
-		/* 94 ArrayExpr <- <(<('[' spOpt (ExpressionOrWildcard (spOpt ',' spOpt ExpressionOrWildcard)*)? spOpt ','? spOpt ']')> Action73)> */
+		/* 169 NonNegativeNumericLiteral <- <(<[0-9]+> Action133)> */
 		func() bool {
-			position1193, tokenIndex1193 := position, tokenIndex
+			position2514, tokenIndex2514 := position, tokenIndex
 			{
-				position1194 := position
+				position2515 := position
 				{
-					position1195 := position
-					if buffer[position] != rune('[') {
-						goto l1193
+					position2516 := position
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l2514
 					}
 					position++
-					if !_rules[rulespOpt]() {
-						goto l1193
-					}
+				l2517:
 					{
-						position1196, tokenIndex1196 := position, tokenIndex
-						if !_rules[ruleExpressionOrWildcard]() {
-							goto l1196
+						position2518, tokenIndex2518 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l2518
 						}
-					l1198:
-						{
-							position1199, tokenIndex1199 := position, tokenIndex
-							if !_rules[rulespOpt]() {
-								goto l1199
-							}
-							if buffer[position] != rune(',') {
-								goto l1199
-							}
-							position++
-							if !_rules[rulespOpt]() {
-								goto l1199
-							}
-							if !_rules[ruleExpressionOrWildcard]() {
-								goto l1199
-							}
-							goto l1198
-						l1199:
-							position, tokenIndex = position1199, tokenIndex1199
+						position++
+						goto l2517
+					l2518:
+						position, tokenIndex = position2518, tokenIndex2518
+					}
+					add(rulePegText, position2516)
+				}
+				if !_rules[ruleAction133]() {
+					goto l2514
+				}
+				add(ruleNonNegativeNumericLiteral, position2515)
+			}
+			return true
+		l2514:
+			position, tokenIndex = position2514, tokenIndex2514
+			return false
+		},
+		/* 170 FloatLiteral <- <(<('-'? [0-9]+ '.' [0-9]+)> Action134)> */
+		func() bool {
+			position2519, tokenIndex2519 := position, tokenIndex
+			{
+				position2520 := position
+				{
+					position2521 := position
+					{
+						position2522, tokenIndex2522 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l2522
 						}
-						goto l1197
-					l1196:
-						position, tokenIndex = position1196, tokenIndex1196
+						position++
+						goto l2523
+					l2522:
+						position, tokenIndex = position2522, tokenIndex2522
 					}
-				l1197:
-					if !_rules[rulespOpt]() {
-						goto l1193
+				l2523:
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l2519
 					}
+					position++
+				l2524:
 					{
-						position1200, tokenIndex1200 := position, tokenIndex
-						if buffer[position] != rune(',') {
-							goto l1200
+						position2525, tokenIndex2525 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l2525
 						}
 						position++
-						goto l1201
-					l1200:
-						position, tokenIndex = position1200, tokenIndex1200
+						goto l2524
+					l2525:
+						position, tokenIndex = position2525, tokenIndex2525
 					}
-				l1201:
-					if !_rules[rulespOpt]() {
-						goto l1193
+					if buffer[position] != rune('.') {
+						goto l2519
 					}
-					if buffer[position] != rune(']') {
-						goto l1193
+					position++
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l2519
 					}
 					position++
-					add(rulePegText, position1195)
+				l2526:
+					{
+						position2527, tokenIndex2527 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l2527
+						}
+						position++
+						goto l2526
+					l2527:
+						position, tokenIndex = position2527, tokenIndex2527
+					}
+					add(rulePegText, position2521)
 				}
-				if !_rules[ruleAction73]() {
-					goto l1193
+				if !_rules[ruleAction134]() {
+					goto l2519
 				}
-				add(ruleArrayExpr, position1194)
+				add(ruleFloatLiteral, position2520)
 			}
 			return true
-		l1193:
-			position, tokenIndex = position1193, tokenIndex1193
+		l2519:
+			position, tokenIndex = position2519, tokenIndex2519
 			return false
 		},
-		/* 95 MapExpr <- <(<('{' spOpt (KeyValuePair (spOpt ',' spOpt KeyValuePair)*)? spOpt '}')> Action74)> */
+		/* 171 Function <- <(<ident> Action135)> */
 		func() bool {
-			position1202, tokenIndex1202 := position, tokenIndex
+			position2528, tokenIndex2528 := position, tokenIndex
 			{
-				position1203 := position
+				position2529 := position
 				{
-					position1204 := position
-					if buffer[position] != rune('{') {
-						goto l1202
+					position2530 := position
+					if !_rules[ruleident]() {
+						goto l2528
 					}
-					position++
-					if !_rules[rulespOpt]() {
-						goto l1202
+					add(rulePegText, position2530)
+				}
+				if !_rules[ruleAction135]() {
+					goto l2528
+				}
+				add(ruleFunction, position2529)
+			}
+			return true
+		l2528:
+			position, tokenIndex = position2528, tokenIndex2528
+			return false
+		},
+		/* 172 NullLiteral <- <(<(('n' / 'N') ('u' / 'U') ('l' / 'L') ('l' / 'L'))> Action136)> */
+		func() bool {
+			position2531, tokenIndex2531 := position, tokenIndex
+			{
+				position2532 := position
+				{
+					position2533 := position
+					{
+						position2534, tokenIndex2534 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2535
+						}
+						position++
+						goto l2534
+					l2535:
+						position, tokenIndex = position2534, tokenIndex2534
+						if buffer[position] != rune('N') {
+							goto l2531
+						}
+						position++
 					}
+				l2534:
 					{
-						position1205, tokenIndex1205 := position, tokenIndex
-						if !_rules[ruleKeyValuePair]() {
-							goto l1205
+						position2536, tokenIndex2536 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2537
 						}
-					l1207:
-						{
-							position1208, tokenIndex1208 := position, tokenIndex
-							if !_rules[rulespOpt]() {
-								goto l1208
-							}
-							if buffer[position] != rune(',') {
-								goto l1208
-							}
-							position++
-							if !_rules[rulespOpt]() {
-								goto l1208
-							}
-							if !_rules[ruleKeyValuePair]() {
-								goto l1208
-							}
-							goto l1207
-						l1208:
-							position, tokenIndex = position1208, tokenIndex1208
+						position++
+						goto l2536
+					l2537:
+						position, tokenIndex = position2536, tokenIndex2536
+						if buffer[position] != rune('U') {
+							goto l2531
 						}
-						goto l1206
-					l1205:
-						position, tokenIndex = position1205, tokenIndex1205
+						position++
 					}
-				l1206:
-					if !_rules[rulespOpt]() {
-						goto l1202
+				l2536:
+					{
+						position2538, tokenIndex2538 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2539
+						}
+						position++
+						goto l2538
+					l2539:
+						position, tokenIndex = position2538, tokenIndex2538
+						if buffer[position] != rune('L') {
+							goto l2531
+						}
+						position++
 					}
-					if buffer[position] != rune('}') {
-						goto l1202
+				l2538:
+					{
+						position2540, tokenIndex2540 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2541
+						}
+						position++
+						goto l2540
+					l2541:
+						position, tokenIndex = position2540, tokenIndex2540
+						if buffer[position] != rune('L') {
+							goto l2531
+						}
+						position++
 					}
-					position++
-					add(rulePegText, position1204)
+				l2540:
+					add(rulePegText, position2533)
 				}
-				if !_rules[ruleAction74]() {
-					goto l1202
+				if !_rules[ruleAction136]() {
+					goto l2531
 				}
-				add(ruleMapExpr, position1203)
+				add(ruleNullLiteral, position2532)
 			}
 			return true
-		l1202:
-			position, tokenIndex = position1202, tokenIndex1202
+		l2531:
+			position, tokenIndex = position2531, tokenIndex2531
 			return false
 		},
-		/* 96 KeyValuePair <- <(<(StringLiteral spOpt ':' spOpt ExpressionOrWildcard)> Action75)> */
+		/* 173 Missing <- <(<(('m' / 'M') ('i' / 'I') ('s' / 'S') ('s' / 'S') ('i' / 'I') ('n' / 'N') ('g' / 'G'))> Action137)> */
 		func() bool {
-			position1209, tokenIndex1209 := position, tokenIndex
+			position2542, tokenIndex2542 := position, tokenIndex
 			{
-				position1210 := position
+				position2543 := position
 				{
-					position1211 := position
-					if !_rules[ruleStringLiteral]() {
-						goto l1209
+					position2544 := position
+					{
+						position2545, tokenIndex2545 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2546
+						}
+						position++
+						goto l2545
+					l2546:
+						position, tokenIndex = position2545, tokenIndex2545
+						if buffer[position] != rune('M') {
+							goto l2542
+						}
+						position++
 					}
-					if !_rules[rulespOpt]() {
-						goto l1209
+				l2545:
+					{
+						position2547, tokenIndex2547 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2548
+						}
+						position++
+						goto l2547
+					l2548:
+						position, tokenIndex = position2547, tokenIndex2547
+						if buffer[position] != rune('I') {
+							goto l2542
+						}
+						position++
 					}
-					if buffer[position] != rune(':') {
-						goto l1209
+				l2547:
+					{
+						position2549, tokenIndex2549 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2550
+						}
+						position++
+						goto l2549
+					l2550:
+						position, tokenIndex = position2549, tokenIndex2549
+						if buffer[position] != rune('S') {
+							goto l2542
+						}
+						position++
 					}
-					position++
-					if !_rules[rulespOpt]() {
-						goto l1209
+				l2549:
+					{
+						position2551, tokenIndex2551 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2552
+						}
+						position++
+						goto l2551
+					l2552:
+						position, tokenIndex = position2551, tokenIndex2551
+						if buffer[position] != rune('S') {
+							goto l2542
+						}
+						position++
 					}
-					if !_rules[ruleExpressionOrWildcard]() {
-						goto l1209
+				l2551:
+					{
+						position2553, tokenIndex2553 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2554
+						}
+						position++
+						goto l2553
+					l2554:
+						position, tokenIndex = position2553, tokenIndex2553
+						if buffer[position] != rune('I') {
+							goto l2542
+						}
+						position++
+					}
+				l2553:
+					{
+						position2555, tokenIndex2555 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2556
+						}
+						position++
+						goto l2555
+					l2556:
+						position, tokenIndex = position2555, tokenIndex2555
+						if buffer[position] != rune('N') {
+							goto l2542
+						}
+						position++
+					}
+				l2555:
+					{
+						position2557, tokenIndex2557 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l2558
+						}
+						position++
+						goto l2557
+					l2558:
+						position, tokenIndex = position2557, tokenIndex2557
+						if buffer[position] != rune('G') {
+							goto l2542
+						}
+						position++
 					}
-					add(rulePegText, position1211)
+				l2557:
+					add(rulePegText, position2544)
 				}
-				if !_rules[ruleAction75]() {
-					goto l1209
+				if !_rules[ruleAction137]() {
+					goto l2542
 				}
-				add(ruleKeyValuePair, position1210)
+				add(ruleMissing, position2543)
 			}
 			return true
-		l1209:
-			position, tokenIndex = position1209, tokenIndex1209
+		l2542:
+			position, tokenIndex = position2542, tokenIndex2542
 			return false
 		},
-		/* 97 Case <- <(ConditionCase / ExpressionCase)> */
+		/* 174 BooleanLiteral <- <(TRUE / FALSE)> */
 		func() bool {
-			position1212, tokenIndex1212 := position, tokenIndex
+			position2559, tokenIndex2559 := position, tokenIndex
 			{
-				position1213 := position
+				position2560 := position
 				{
-					position1214, tokenIndex1214 := position, tokenIndex
-					if !_rules[ruleConditionCase]() {
-						goto l1215
+					position2561, tokenIndex2561 := position, tokenIndex
+					if !_rules[ruleTRUE]() {
+						goto l2562
 					}
-					goto l1214
-				l1215:
-					position, tokenIndex = position1214, tokenIndex1214
-					if !_rules[ruleExpressionCase]() {
-						goto l1212
+					goto l2561
+				l2562:
+					position, tokenIndex = position2561, tokenIndex2561
+					if !_rules[ruleFALSE]() {
+						goto l2559
 					}
 				}
-			l1214:
-				add(ruleCase, position1213)
+			l2561:
+				add(ruleBooleanLiteral, position2560)
 			}
 			return true
-		l1212:
-			position, tokenIndex = position1212, tokenIndex1212
+		l2559:
+			position, tokenIndex = position2559, tokenIndex2559
 			return false
 		},
-		/* 98 ConditionCase <- <(('c' / 'C') ('a' / 'A') ('s' / 'S') ('e' / 'E') <((sp WhenThenPair)+ (sp (('e' / 'E') ('l' / 'L') ('s' / 'S') ('e' / 'E')) sp Expression)? sp (('e' / 'E') ('n' / 'N') ('d' / 'D')))> Action76)> */
+		/* 175 TRUE <- <(<(('t' / 'T') ('r' / 'R') ('u' / 'U') ('e' / 'E'))> Action138)> */
 		func() bool {
-			position1216, tokenIndex1216 := position, tokenIndex
+			position2563, tokenIndex2563 := position, tokenIndex
 			{
-				position1217 := position
+				position2564 := position
 				{
-					position1218, tokenIndex1218 := position, tokenIndex
-					if buffer[position] != rune('c') {
-						goto l1219
+					position2565 := position
+					{
+						position2566, tokenIndex2566 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2567
+						}
+						position++
+						goto l2566
+					l2567:
+						position, tokenIndex = position2566, tokenIndex2566
+						if buffer[position] != rune('T') {
+							goto l2563
+						}
+						position++
 					}
-					position++
-					goto l1218
-				l1219:
-					position, tokenIndex = position1218, tokenIndex1218
-					if buffer[position] != rune('C') {
-						goto l1216
+				l2566:
+					{
+						position2568, tokenIndex2568 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2569
+						}
+						position++
+						goto l2568
+					l2569:
+						position, tokenIndex = position2568, tokenIndex2568
+						if buffer[position] != rune('R') {
+							goto l2563
+						}
+						position++
 					}
-					position++
-				}
-			l1218:
-				{
-					position1220, tokenIndex1220 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l1221
+				l2568:
+					{
+						position2570, tokenIndex2570 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2571
+						}
+						position++
+						goto l2570
+					l2571:
+						position, tokenIndex = position2570, tokenIndex2570
+						if buffer[position] != rune('U') {
+							goto l2563
+						}
+						position++
 					}
-					position++
-					goto l1220
-				l1221:
-					position, tokenIndex = position1220, tokenIndex1220
-					if buffer[position] != rune('A') {
-						goto l1216
+				l2570:
+					{
+						position2572, tokenIndex2572 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2573
+						}
+						position++
+						goto l2572
+					l2573:
+						position, tokenIndex = position2572, tokenIndex2572
+						if buffer[position] != rune('E') {
+							goto l2563
+						}
+						position++
 					}
-					position++
+				l2572:
+					add(rulePegText, position2565)
 				}
-			l1220:
-				{
-					position1222, tokenIndex1222 := position, tokenIndex
-					if buffer[position] != rune('s') {
-						goto l1223
-					}
-					position++
-					goto l1222
-				l1223:
-					position, tokenIndex = position1222, tokenIndex1222
-					if buffer[position] != rune('S') {
-						goto l1216
-					}
-					position++
+				if !_rules[ruleAction138]() {
+					goto l2563
 				}
-			l1222:
+				add(ruleTRUE, position2564)
+			}
+			return true
+		l2563:
+			position, tokenIndex = position2563, tokenIndex2563
+			return false
+		},
+		/* 176 FALSE <- <(<(('f' / 'F') ('a' / 'A') ('l' / 'L') ('s' / 'S') ('e' / 'E'))> Action139)> */
+		func() bool {
+			position2574, tokenIndex2574 := position, tokenIndex
+			{
+				position2575 := position
 				{
-					position1224, tokenIndex1224 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l1225
+					position2576 := position
+					{
+						position2577, tokenIndex2577 := position, tokenIndex
+						if buffer[position] != rune('f') {
+							goto l2578
+						}
+						position++
+						goto l2577
+					l2578:
+						position, tokenIndex = position2577, tokenIndex2577
+						if buffer[position] != rune('F') {
+							goto l2574
+						}
+						position++
 					}
-					position++
-					goto l1224
-				l1225:
-					position, tokenIndex = position1224, tokenIndex1224
-					if buffer[position] != rune('E') {
-						goto l1216
+				l2577:
+					{
+						position2579, tokenIndex2579 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2580
+						}
+						position++
+						goto l2579
+					l2580:
+						position, tokenIndex = position2579, tokenIndex2579
+						if buffer[position] != rune('A') {
+							goto l2574
+						}
+						position++
 					}
-					position++
-				}
-			l1224:
-				{
-					position1226 := position
-					if !_rules[rulesp]() {
-						goto l1216
+				l2579:
+					{
+						position2581, tokenIndex2581 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2582
+						}
+						position++
+						goto l2581
+					l2582:
+						position, tokenIndex = position2581, tokenIndex2581
+						if buffer[position] != rune('L') {
+							goto l2574
+						}
+						position++
 					}
-					if !_rules[ruleWhenThenPair]() {
-						goto l1216
+				l2581:
+					{
+						position2583, tokenIndex2583 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2584
+						}
+						position++
+						goto l2583
+					l2584:
+						position, tokenIndex = position2583, tokenIndex2583
+						if buffer[position] != rune('S') {
+							goto l2574
+						}
+						position++
 					}
-				l1227:
+				l2583:
 					{
-						position1228, tokenIndex1228 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1228
+						position2585, tokenIndex2585 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2586
 						}
-						if !_rules[ruleWhenThenPair]() {
-							goto l1228
+						position++
+						goto l2585
+					l2586:
+						position, tokenIndex = position2585, tokenIndex2585
+						if buffer[position] != rune('E') {
+							goto l2574
 						}
-						goto l1227
-					l1228:
-						position, tokenIndex = position1228, tokenIndex1228
+						position++
 					}
+				l2585:
+					add(rulePegText, position2576)
+				}
+				if !_rules[ruleAction139]() {
+					goto l2574
+				}
+				add(ruleFALSE, position2575)
+			}
+			return true
+		l2574:
+			position, tokenIndex = position2574, tokenIndex2574
+			return false
+		},
+		/* 177 Wildcard <- <(<((ident ':' !':')? '*')> Action140)> */
+		func() bool {
+			position2587, tokenIndex2587 := position, tokenIndex
+			{
+				position2588 := position
+				{
+					position2589 := position
 					{
-						position1229, tokenIndex1229 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1229
+						position2590, tokenIndex2590 := position, tokenIndex
+						if !_rules[ruleident]() {
+							goto l2590
 						}
-						{
-							position1231, tokenIndex1231 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l1232
-							}
-							position++
-							goto l1231
-						l1232:
-							position, tokenIndex = position1231, tokenIndex1231
-							if buffer[position] != rune('E') {
-								goto l1229
-							}
-							position++
+						if buffer[position] != rune(':') {
+							goto l2590
 						}
-					l1231:
+						position++
 						{
-							position1233, tokenIndex1233 := position, tokenIndex
-							if buffer[position] != rune('l') {
-								goto l1234
-							}
-							position++
-							goto l1233
-						l1234:
-							position, tokenIndex = position1233, tokenIndex1233
-							if buffer[position] != rune('L') {
-								goto l1229
+							position2592, tokenIndex2592 := position, tokenIndex
+							if buffer[position] != rune(':') {
+								goto l2592
 							}
 							position++
+							goto l2590
+						l2592:
+							position, tokenIndex = position2592, tokenIndex2592
 						}
-					l1233:
+						goto l2591
+					l2590:
+						position, tokenIndex = position2590, tokenIndex2590
+					}
+				l2591:
+					if buffer[position] != rune('*') {
+						goto l2587
+					}
+					position++
+					add(rulePegText, position2589)
+				}
+				if !_rules[ruleAction140]() {
+					goto l2587
+				}
+				add(ruleWildcard, position2588)
+			}
+			return true
+		l2587:
+			position, tokenIndex = position2587, tokenIndex2587
+			return false
+		},
+		/* 178 StringLiteral <- <(<('"' (('"' '"') / (!'"' .))* '"')> Action141)> */
+		func() bool {
+			position2593, tokenIndex2593 := position, tokenIndex
+			{
+				position2594 := position
+				{
+					position2595 := position
+					if buffer[position] != rune('"') {
+						goto l2593
+					}
+					position++
+				l2596:
+					{
+						position2597, tokenIndex2597 := position, tokenIndex
 						{
-							position1235, tokenIndex1235 := position, tokenIndex
-							if buffer[position] != rune('s') {
-								goto l1236
+							position2598, tokenIndex2598 := position, tokenIndex
+							if buffer[position] != rune('"') {
+								goto l2599
 							}
 							position++
-							goto l1235
-						l1236:
-							position, tokenIndex = position1235, tokenIndex1235
-							if buffer[position] != rune('S') {
-								goto l1229
+							if buffer[position] != rune('"') {
+								goto l2599
 							}
 							position++
-						}
-					l1235:
-						{
-							position1237, tokenIndex1237 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l1238
+							goto l2598
+						l2599:
+							position, tokenIndex = position2598, tokenIndex2598
+							{
+								position2600, tokenIndex2600 := position, tokenIndex
+								if buffer[position] != rune('"') {
+									goto l2600
+								}
+								position++
+								goto l2597
+							l2600:
+								position, tokenIndex = position2600, tokenIndex2600
 							}
-							position++
-							goto l1237
-						l1238:
-							position, tokenIndex = position1237, tokenIndex1237
-							if buffer[position] != rune('E') {
-								goto l1229
+							if !matchDot() {
+								goto l2597
 							}
-							position++
 						}
-					l1237:
-						if !_rules[rulesp]() {
-							goto l1229
+					l2598:
+						goto l2596
+					l2597:
+						position, tokenIndex = position2597, tokenIndex2597
+					}
+					if buffer[position] != rune('"') {
+						goto l2593
+					}
+					position++
+					add(rulePegText, position2595)
+				}
+				if !_rules[ruleAction141]() {
+					goto l2593
+				}
+				add(ruleStringLiteral, position2594)
+			}
+			return true
+		l2593:
+			position, tokenIndex = position2593, tokenIndex2593
+			return false
+		},
+		/* 179 ISTREAM <- <(<(('i' / 'I') ('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M'))> Action142)> */
+		func() bool {
+			position2601, tokenIndex2601 := position, tokenIndex
+			{
+				position2602 := position
+				{
+					position2603 := position
+					{
+						position2604, tokenIndex2604 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2605
 						}
-						if !_rules[ruleExpression]() {
-							goto l1229
+						position++
+						goto l2604
+					l2605:
+						position, tokenIndex = position2604, tokenIndex2604
+						if buffer[position] != rune('I') {
+							goto l2601
 						}
-						goto l1230
-					l1229:
-						position, tokenIndex = position1229, tokenIndex1229
+						position++
 					}
-				l1230:
-					if !_rules[rulesp]() {
-						goto l1216
+				l2604:
+					{
+						position2606, tokenIndex2606 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2607
+						}
+						position++
+						goto l2606
+					l2607:
+						position, tokenIndex = position2606, tokenIndex2606
+						if buffer[position] != rune('S') {
+							goto l2601
+						}
+						position++
+					}
+				l2606:
+					{
+						position2608, tokenIndex2608 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2609
+						}
+						position++
+						goto l2608
+					l2609:
+						position, tokenIndex = position2608, tokenIndex2608
+						if buffer[position] != rune('T') {
+							goto l2601
+						}
+						position++
+					}
+				l2608:
+					{
+						position2610, tokenIndex2610 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2611
+						}
+						position++
+						goto l2610
+					l2611:
+						position, tokenIndex = position2610, tokenIndex2610
+						if buffer[position] != rune('R') {
+							goto l2601
+						}
+						position++
 					}
+				l2610:
 					{
-						position1239, tokenIndex1239 := position, tokenIndex
+						position2612, tokenIndex2612 := position, tokenIndex
 						if buffer[position] != rune('e') {
-							goto l1240
+							goto l2613
 						}
 						position++
-						goto l1239
-					l1240:
-						position, tokenIndex = position1239, tokenIndex1239
+						goto l2612
+					l2613:
+						position, tokenIndex = position2612, tokenIndex2612
 						if buffer[position] != rune('E') {
-							goto l1216
+							goto l2601
 						}
 						position++
 					}
-				l1239:
+				l2612:
 					{
-						position1241, tokenIndex1241 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1242
+						position2614, tokenIndex2614 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2615
 						}
 						position++
-						goto l1241
-					l1242:
-						position, tokenIndex = position1241, tokenIndex1241
-						if buffer[position] != rune('N') {
-							goto l1216
+						goto l2614
+					l2615:
+						position, tokenIndex = position2614, tokenIndex2614
+						if buffer[position] != rune('A') {
+							goto l2601
 						}
 						position++
 					}
-				l1241:
+				l2614:
 					{
-						position1243, tokenIndex1243 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1244
+						position2616, tokenIndex2616 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2617
 						}
 						position++
-						goto l1243
-					l1244:
-						position, tokenIndex = position1243, tokenIndex1243
-						if buffer[position] != rune('D') {
-							goto l1216
+						goto l2616
+					l2617:
+						position, tokenIndex = position2616, tokenIndex2616
+						if buffer[position] != rune('M') {
+							goto l2601
 						}
 						position++
 					}
-				l1243:
-					add(rulePegText, position1226)
+				l2616:
+					add(rulePegText, position2603)
 				}
-				if !_rules[ruleAction76]() {
-					goto l1216
+				if !_rules[ruleAction142]() {
+					goto l2601
 				}
-				add(ruleConditionCase, position1217)
+				add(ruleISTREAM, position2602)
 			}
 			return true
-		l1216:
-			position, tokenIndex = position1216, tokenIndex1216
+		l2601:
+			position, tokenIndex = position2601, tokenIndex2601
 			return false
 		},
-		/* 99 ExpressionCase <- <(('c' / 'C') ('a' / 'A') ('s' / 'S') ('e' / 'E') sp Expression <((sp WhenThenPair)+ (sp (('e' / 'E') ('l' / 'L') ('s' / 'S') ('e' / 'E')) sp Expression)? sp (('e' / 'E') ('n' / 'N') ('d' / 'D')))> Action77)> */
+		/* 180 DSTREAM <- <(<(('d' / 'D') ('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M'))> Action143)> */
 		func() bool {
-			position1245, tokenIndex1245 := position, tokenIndex
+			position2618, tokenIndex2618 := position, tokenIndex
 			{
-				position1246 := position
-				{
-					position1247, tokenIndex1247 := position, tokenIndex
-					if buffer[position] != rune('c') {
-						goto l1248
-					}
-					position++
-					goto l1247
-				l1248:
-					position, tokenIndex = position1247, tokenIndex1247
-					if buffer[position] != rune('C') {
-						goto l1245
-					}
-					position++
-				}
-			l1247:
-				{
-					position1249, tokenIndex1249 := position, tokenIndex
-					if buffer[position] != rune('a') {
-						goto l1250
-					}
-					position++
-					goto l1249
-				l1250:
-					position, tokenIndex = position1249, tokenIndex1249
-					if buffer[position] != rune('A') {
-						goto l1245
-					}
-					position++
-				}
-			l1249:
+				position2619 := position
 				{
-					position1251, tokenIndex1251 := position, tokenIndex
-					if buffer[position] != rune('s') {
-						goto l1252
-					}
-					position++
-					goto l1251
-				l1252:
-					position, tokenIndex = position1251, tokenIndex1251
-					if buffer[position] != rune('S') {
-						goto l1245
+					position2620 := position
+					{
+						position2621, tokenIndex2621 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2622
+						}
+						position++
+						goto l2621
+					l2622:
+						position, tokenIndex = position2621, tokenIndex2621
+						if buffer[position] != rune('D') {
+							goto l2618
+						}
+						position++
 					}
-					position++
-				}
-			l1251:
-				{
-					position1253, tokenIndex1253 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l1254
+				l2621:
+					{
+						position2623, tokenIndex2623 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2624
+						}
+						position++
+						goto l2623
+					l2624:
+						position, tokenIndex = position2623, tokenIndex2623
+						if buffer[position] != rune('S') {
+							goto l2618
+						}
+						position++
 					}
-					position++
-					goto l1253
-				l1254:
-					position, tokenIndex = position1253, tokenIndex1253
-					if buffer[position] != rune('E') {
-						goto l1245
+				l2623:
+					{
+						position2625, tokenIndex2625 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2626
+						}
+						position++
+						goto l2625
+					l2626:
+						position, tokenIndex = position2625, tokenIndex2625
+						if buffer[position] != rune('T') {
+							goto l2618
+						}
+						position++
 					}
-					position++
-				}
-			l1253:
-				if !_rules[rulesp]() {
-					goto l1245
-				}
-				if !_rules[ruleExpression]() {
-					goto l1245
-				}
-				{
-					position1255 := position
-					if !_rules[rulesp]() {
-						goto l1245
+				l2625:
+					{
+						position2627, tokenIndex2627 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2628
+						}
+						position++
+						goto l2627
+					l2628:
+						position, tokenIndex = position2627, tokenIndex2627
+						if buffer[position] != rune('R') {
+							goto l2618
+						}
+						position++
 					}
-					if !_rules[ruleWhenThenPair]() {
-						goto l1245
+				l2627:
+					{
+						position2629, tokenIndex2629 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2630
+						}
+						position++
+						goto l2629
+					l2630:
+						position, tokenIndex = position2629, tokenIndex2629
+						if buffer[position] != rune('E') {
+							goto l2618
+						}
+						position++
 					}
-				l1256:
+				l2629:
 					{
-						position1257, tokenIndex1257 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1257
+						position2631, tokenIndex2631 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2632
 						}
-						if !_rules[ruleWhenThenPair]() {
-							goto l1257
+						position++
+						goto l2631
+					l2632:
+						position, tokenIndex = position2631, tokenIndex2631
+						if buffer[position] != rune('A') {
+							goto l2618
 						}
-						goto l1256
-					l1257:
-						position, tokenIndex = position1257, tokenIndex1257
+						position++
 					}
+				l2631:
 					{
-						position1258, tokenIndex1258 := position, tokenIndex
-						if !_rules[rulesp]() {
-							goto l1258
+						position2633, tokenIndex2633 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2634
 						}
-						{
-							position1260, tokenIndex1260 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l1261
-							}
-							position++
-							goto l1260
-						l1261:
-							position, tokenIndex = position1260, tokenIndex1260
-							if buffer[position] != rune('E') {
-								goto l1258
-							}
-							position++
+						position++
+						goto l2633
+					l2634:
+						position, tokenIndex = position2633, tokenIndex2633
+						if buffer[position] != rune('M') {
+							goto l2618
 						}
-					l1260:
-						{
-							position1262, tokenIndex1262 := position, tokenIndex
-							if buffer[position] != rune('l') {
-								goto l1263
-							}
-							position++
-							goto l1262
-						l1263:
-							position, tokenIndex = position1262, tokenIndex1262
-							if buffer[position] != rune('L') {
-								goto l1258
-							}
-							position++
+						position++
+					}
+				l2633:
+					add(rulePegText, position2620)
+				}
+				if !_rules[ruleAction143]() {
+					goto l2618
+				}
+				add(ruleDSTREAM, position2619)
+			}
+			return true
+		l2618:
+			position, tokenIndex = position2618, tokenIndex2618
+			return false
+		},
+		/* 181 RSTREAM <- <(<(('r' / 'R') ('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M'))> Action144)> */
+		func() bool {
+			position2635, tokenIndex2635 := position, tokenIndex
+			{
+				position2636 := position
+				{
+					position2637 := position
+					{
+						position2638, tokenIndex2638 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2639
 						}
-					l1262:
-						{
-							position1264, tokenIndex1264 := position, tokenIndex
-							if buffer[position] != rune('s') {
-								goto l1265
-							}
-							position++
-							goto l1264
-						l1265:
-							position, tokenIndex = position1264, tokenIndex1264
-							if buffer[position] != rune('S') {
-								goto l1258
-							}
-							position++
+						position++
+						goto l2638
+					l2639:
+						position, tokenIndex = position2638, tokenIndex2638
+						if buffer[position] != rune('R') {
+							goto l2635
 						}
-					l1264:
-						{
-							position1266, tokenIndex1266 := position, tokenIndex
-							if buffer[position] != rune('e') {
-								goto l1267
-							}
-							position++
-							goto l1266
-						l1267:
-							position, tokenIndex = position1266, tokenIndex1266
-							if buffer[position] != rune('E') {
-								goto l1258
-							}
-							position++
+						position++
+					}
+				l2638:
+					{
+						position2640, tokenIndex2640 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2641
 						}
-					l1266:
-						if !_rules[rulesp]() {
-							goto l1258
+						position++
+						goto l2640
+					l2641:
+						position, tokenIndex = position2640, tokenIndex2640
+						if buffer[position] != rune('S') {
+							goto l2635
 						}
-						if !_rules[ruleExpression]() {
-							goto l1258
+						position++
+					}
+				l2640:
+					{
+						position2642, tokenIndex2642 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2643
 						}
-						goto l1259
-					l1258:
-						position, tokenIndex = position1258, tokenIndex1258
+						position++
+						goto l2642
+					l2643:
+						position, tokenIndex = position2642, tokenIndex2642
+						if buffer[position] != rune('T') {
+							goto l2635
+						}
+						position++
 					}
-				l1259:
-					if !_rules[rulesp]() {
-						goto l1245
+				l2642:
+					{
+						position2644, tokenIndex2644 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2645
+						}
+						position++
+						goto l2644
+					l2645:
+						position, tokenIndex = position2644, tokenIndex2644
+						if buffer[position] != rune('R') {
+							goto l2635
+						}
+						position++
 					}
+				l2644:
 					{
-						position1268, tokenIndex1268 := position, tokenIndex
+						position2646, tokenIndex2646 := position, tokenIndex
 						if buffer[position] != rune('e') {
-							goto l1269
+							goto l2647
 						}
 						position++
-						goto l1268
-					l1269:
-						position, tokenIndex = position1268, tokenIndex1268
+						goto l2646
+					l2647:
+						position, tokenIndex = position2646, tokenIndex2646
 						if buffer[position] != rune('E') {
-							goto l1245
+							goto l2635
 						}
 						position++
 					}
-				l1268:
+				l2646:
 					{
-						position1270, tokenIndex1270 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1271
+						position2648, tokenIndex2648 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2649
 						}
 						position++
-						goto l1270
-					l1271:
-						position, tokenIndex = position1270, tokenIndex1270
-						if buffer[position] != rune('N') {
-							goto l1245
+						goto l2648
+					l2649:
+						position, tokenIndex = position2648, tokenIndex2648
+						if buffer[position] != rune('A') {
+							goto l2635
 						}
 						position++
 					}
-				l1270:
+				l2648:
 					{
-						position1272, tokenIndex1272 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1273
+						position2650, tokenIndex2650 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2651
 						}
 						position++
-						goto l1272
-					l1273:
-						position, tokenIndex = position1272, tokenIndex1272
-						if buffer[position] != rune('D') {
-							goto l1245
+						goto l2650
+					l2651:
+						position, tokenIndex = position2650, tokenIndex2650
+						if buffer[position] != rune('M') {
+							goto l2635
 						}
 						position++
 					}
-				l1272:
-					add(rulePegText, position1255)
+				l2650:
+					add(rulePegText, position2637)
 				}
-				if !_rules[ruleAction77]() {
-					goto l1245
+				if !_rules[ruleAction144]() {
+					goto l2635
 				}
-				add(ruleExpressionCase, position1246)
+				add(ruleRSTREAM, position2636)
 			}
 			return true
-		l1245:
-			position, tokenIndex = position1245, tokenIndex1245
+		l2635:
+			position, tokenIndex = position2635, tokenIndex2635
 			return false
 		},
-		/* 100 WhenThenPair <- <(('w' / 'W') ('h' / 'H') ('e' / 'E') ('n' / 'N') sp Expression sp (('t' / 'T') ('h' / 'H') ('e' / 'E') ('n' / 'N')) sp ExpressionOrWildcard Action78)> */
+		/* 182 TUPLES <- <(<(('t' / 'T') ('u' / 'U') ('p' / 'P') ('l' / 'L') ('e' / 'E') ('s' / 'S'))> Action145)> */
 		func() bool {
-			position1274, tokenIndex1274 := position, tokenIndex
+			position2652, tokenIndex2652 := position, tokenIndex
 			{
-				position1275 := position
-				{
-					position1276, tokenIndex1276 := position, tokenIndex
-					if buffer[position] != rune('w') {
-						goto l1277
-					}
-					position++
-					goto l1276
-				l1277:
-					position, tokenIndex = position1276, tokenIndex1276
-					if buffer[position] != rune('W') {
-						goto l1274
-					}
-					position++
-				}
-			l1276:
-				{
-					position1278, tokenIndex1278 := position, tokenIndex
-					if buffer[position] != rune('h') {
-						goto l1279
-					}
-					position++
-					goto l1278
-				l1279:
-					position, tokenIndex = position1278, tokenIndex1278
-					if buffer[position] != rune('H') {
-						goto l1274
-					}
-					position++
-				}
-			l1278:
-				{
-					position1280, tokenIndex1280 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l1281
-					}
-					position++
-					goto l1280
-				l1281:
-					position, tokenIndex = position1280, tokenIndex1280
-					if buffer[position] != rune('E') {
-						goto l1274
-					}
-					position++
-				}
-			l1280:
-				{
-					position1282, tokenIndex1282 := position, tokenIndex
-					if buffer[position] != rune('n') {
-						goto l1283
-					}
-					position++
-					goto l1282
-				l1283:
-					position, tokenIndex = position1282, tokenIndex1282
-					if buffer[position] != rune('N') {
-						goto l1274
-					}
-					position++
-				}
-			l1282:
-				if !_rules[rulesp]() {
-					goto l1274
-				}
-				if !_rules[ruleExpression]() {
-					goto l1274
-				}
-				if !_rules[rulesp]() {
-					goto l1274
-				}
-				{
-					position1284, tokenIndex1284 := position, tokenIndex
-					if buffer[position] != rune('t') {
-						goto l1285
-					}
-					position++
-					goto l1284
-				l1285:
-					position, tokenIndex = position1284, tokenIndex1284
-					if buffer[position] != rune('T') {
-						goto l1274
-					}
-					position++
-				}
-			l1284:
+				position2653 := position
 				{
-					position1286, tokenIndex1286 := position, tokenIndex
-					if buffer[position] != rune('h') {
-						goto l1287
+					position2654 := position
+					{
+						position2655, tokenIndex2655 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2656
+						}
+						position++
+						goto l2655
+					l2656:
+						position, tokenIndex = position2655, tokenIndex2655
+						if buffer[position] != rune('T') {
+							goto l2652
+						}
+						position++
 					}
-					position++
-					goto l1286
-				l1287:
-					position, tokenIndex = position1286, tokenIndex1286
-					if buffer[position] != rune('H') {
-						goto l1274
+				l2655:
+					{
+						position2657, tokenIndex2657 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2658
+						}
+						position++
+						goto l2657
+					l2658:
+						position, tokenIndex = position2657, tokenIndex2657
+						if buffer[position] != rune('U') {
+							goto l2652
+						}
+						position++
 					}
-					position++
-				}
-			l1286:
-				{
-					position1288, tokenIndex1288 := position, tokenIndex
-					if buffer[position] != rune('e') {
-						goto l1289
+				l2657:
+					{
+						position2659, tokenIndex2659 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2660
+						}
+						position++
+						goto l2659
+					l2660:
+						position, tokenIndex = position2659, tokenIndex2659
+						if buffer[position] != rune('P') {
+							goto l2652
+						}
+						position++
 					}
-					position++
-					goto l1288
-				l1289:
-					position, tokenIndex = position1288, tokenIndex1288
-					if buffer[position] != rune('E') {
-						goto l1274
+				l2659:
+					{
+						position2661, tokenIndex2661 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2662
+						}
+						position++
+						goto l2661
+					l2662:
+						position, tokenIndex = position2661, tokenIndex2661
+						if buffer[position] != rune('L') {
+							goto l2652
+						}
+						position++
 					}
-					position++
-				}
-			l1288:
-				{
-					position1290, tokenIndex1290 := position, tokenIndex
-					if buffer[position] != rune('n') {
-						goto l1291
+				l2661:
+					{
+						position2663, tokenIndex2663 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2664
+						}
+						position++
+						goto l2663
+					l2664:
+						position, tokenIndex = position2663, tokenIndex2663
+						if buffer[position] != rune('E') {
+							goto l2652
+						}
+						position++
 					}
-					position++
-					goto l1290
-				l1291:
-					position, tokenIndex = position1290, tokenIndex1290
-					if buffer[position] != rune('N') {
-						goto l1274
+				l2663:
+					{
+						position2665, tokenIndex2665 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2666
+						}
+						position++
+						goto l2665
+					l2666:
+						position, tokenIndex = position2665, tokenIndex2665
+						if buffer[position] != rune('S') {
+							goto l2652
+						}
+						position++
 					}
-					position++
-				}
-			l1290:
-				if !_rules[rulesp]() {
-					goto l1274
-				}
-				if !_rules[ruleExpressionOrWildcard]() {
-					goto l1274
-				}
-				if !_rules[ruleAction78]() {
-					goto l1274
+				l2665:
+					add(rulePegText, position2654)
 				}
-				add(ruleWhenThenPair, position1275)
-			}
-			return true
-		l1274:
-			position, tokenIndex = position1274, tokenIndex1274
-			return false
-		},
-		/* 101 Literal <- <(FloatLiteral / NumericLiteral / StringLiteral)> */
-		func() bool {
-			position1292, tokenIndex1292 := position, tokenIndex
-			{
-				position1293 := position
-				{
-					position1294, tokenIndex1294 := position, tokenIndex
-					if !_rules[ruleFloatLiteral]() {
-						goto l1295
-					}
-					goto l1294
-				l1295:
-					position, tokenIndex = position1294, tokenIndex1294
-					if !_rules[ruleNumericLiteral]() {
-						goto l1296
-					}
-					goto l1294
-				l1296:
-					position, tokenIndex = position1294, tokenIndex1294
-					if !_rules[ruleStringLiteral]() {
-						goto l1292
-					}
+				if !_rules[ruleAction145]() {
+					goto l2652
 				}
-			l1294:
-				add(ruleLiteral, position1293)
+				add(ruleTUPLES, position2653)
 			}
 			return true
-		l1292:
-			position, tokenIndex = position1292, tokenIndex1292
+		l2652:
+			position, tokenIndex = position2652, tokenIndex2652
 			return false
 		},
-		/* 102 ComparisonOp <- <(Equal / NotEqual / LessOrEqual / Less / GreaterOrEqual / Greater / NotEqual)> */
+		/* 183 SECONDS <- <(<(('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S'))> Action146)> */
 		func() bool {
-			position1297, tokenIndex1297 := position, tokenIndex
+			position2667, tokenIndex2667 := position, tokenIndex
 			{
-				position1298 := position
+				position2668 := position
 				{
-					position1299, tokenIndex1299 := position, tokenIndex
-					if !_rules[ruleEqual]() {
-						goto l1300
-					}
-					goto l1299
-				l1300:
-					position, tokenIndex = position1299, tokenIndex1299
-					if !_rules[ruleNotEqual]() {
-						goto l1301
-					}
-					goto l1299
-				l1301:
-					position, tokenIndex = position1299, tokenIndex1299
-					if !_rules[ruleLessOrEqual]() {
-						goto l1302
-					}
-					goto l1299
-				l1302:
-					position, tokenIndex = position1299, tokenIndex1299
-					if !_rules[ruleLess]() {
-						goto l1303
-					}
-					goto l1299
-				l1303:
-					position, tokenIndex = position1299, tokenIndex1299
-					if !_rules[ruleGreaterOrEqual]() {
-						goto l1304
+					position2669 := position
+					{
+						position2670, tokenIndex2670 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2671
+						}
+						position++
+						goto l2670
+					l2671:
+						position, tokenIndex = position2670, tokenIndex2670
+						if buffer[position] != rune('S') {
+							goto l2667
+						}
+						position++
 					}
-					goto l1299
-				l1304:
-					position, tokenIndex = position1299, tokenIndex1299
-					if !_rules[ruleGreater]() {
-						goto l1305
+				l2670:
+					{
+						position2672, tokenIndex2672 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2673
+						}
+						position++
+						goto l2672
+					l2673:
+						position, tokenIndex = position2672, tokenIndex2672
+						if buffer[position] != rune('E') {
+							goto l2667
+						}
+						position++
 					}
-					goto l1299
-				l1305:
-					position, tokenIndex = position1299, tokenIndex1299
-					if !_rules[ruleNotEqual]() {
-						goto l1297
+				l2672:
+					{
+						position2674, tokenIndex2674 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l2675
+						}
+						position++
+						goto l2674
+					l2675:
+						position, tokenIndex = position2674, tokenIndex2674
+						if buffer[position] != rune('C') {
+							goto l2667
+						}
+						position++
 					}
-				}
-			l1299:
-				add(ruleComparisonOp, position1298)
-			}
-			return true
-		l1297:
-			position, tokenIndex = position1297, tokenIndex1297
-			return false
-		},
-		/* 103 OtherOp <- <Concat> */
-		func() bool {
-			position1306, tokenIndex1306 := position, tokenIndex
-			{
-				position1307 := position
-				if !_rules[ruleConcat]() {
-					goto l1306
-				}
-				add(ruleOtherOp, position1307)
-			}
-			return true
-		l1306:
-			position, tokenIndex = position1306, tokenIndex1306
-			return false
-		},
-		/* 104 IsOp <- <(IsNot / Is)> */
-		func() bool {
-			position1308, tokenIndex1308 := position, tokenIndex
-			{
-				position1309 := position
-				{
-					position1310, tokenIndex1310 := position, tokenIndex
-					if !_rules[ruleIsNot]() {
-						goto l1311
+				l2674:
+					{
+						position2676, tokenIndex2676 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2677
+						}
+						position++
+						goto l2676
+					l2677:
+						position, tokenIndex = position2676, tokenIndex2676
+						if buffer[position] != rune('O') {
+							goto l2667
+						}
+						position++
 					}
-					goto l1310
-				l1311:
-					position, tokenIndex = position1310, tokenIndex1310
-					if !_rules[ruleIs]() {
-						goto l1308
+				l2676:
+					{
+						position2678, tokenIndex2678 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2679
+						}
+						position++
+						goto l2678
+					l2679:
+						position, tokenIndex = position2678, tokenIndex2678
+						if buffer[position] != rune('N') {
+							goto l2667
+						}
+						position++
 					}
-				}
-			l1310:
-				add(ruleIsOp, position1309)
-			}
-			return true
-		l1308:
-			position, tokenIndex = position1308, tokenIndex1308
-			return false
-		},
-		/* 105 PlusMinusOp <- <(Plus / Minus)> */
-		func() bool {
-			position1312, tokenIndex1312 := position, tokenIndex
-			{
-				position1313 := position
-				{
-					position1314, tokenIndex1314 := position, tokenIndex
-					if !_rules[rulePlus]() {
-						goto l1315
+				l2678:
+					{
+						position2680, tokenIndex2680 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2681
+						}
+						position++
+						goto l2680
+					l2681:
+						position, tokenIndex = position2680, tokenIndex2680
+						if buffer[position] != rune('D') {
+							goto l2667
+						}
+						position++
 					}
-					goto l1314
-				l1315:
-					position, tokenIndex = position1314, tokenIndex1314
-					if !_rules[ruleMinus]() {
-						goto l1312
+				l2680:
+					{
+						position2682, tokenIndex2682 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2683
+						}
+						position++
+						goto l2682
+					l2683:
+						position, tokenIndex = position2682, tokenIndex2682
+						if buffer[position] != rune('S') {
+							goto l2667
+						}
+						position++
 					}
+				l2682:
+					add(rulePegText, position2669)
 				}
-			l1314:
-				add(rulePlusMinusOp, position1313)
+				if !_rules[ruleAction146]() {
+					goto l2667
+				}
+				add(ruleSECONDS, position2668)
 			}
 			return true
-		l1312:
-			position, tokenIndex = position1312, tokenIndex1312
+		l2667:
+			position, tokenIndex = position2667, tokenIndex2667
 			return false
 		},
-		/* 106 MultDivOp <- <(Multiply / Divide / Modulo)> */
+		/* 184 MILLISECONDS <- <(<(('m' / 'M') ('i' / 'I') ('l' / 'L') ('l' / 'L') ('i' / 'I') ('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S'))> Action147)> */
 		func() bool {
-			position1316, tokenIndex1316 := position, tokenIndex
+			position2684, tokenIndex2684 := position, tokenIndex
 			{
-				position1317 := position
+				position2685 := position
 				{
-					position1318, tokenIndex1318 := position, tokenIndex
-					if !_rules[ruleMultiply]() {
-						goto l1319
+					position2686 := position
+					{
+						position2687, tokenIndex2687 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2688
+						}
+						position++
+						goto l2687
+					l2688:
+						position, tokenIndex = position2687, tokenIndex2687
+						if buffer[position] != rune('M') {
+							goto l2684
+						}
+						position++
 					}
-					goto l1318
-				l1319:
-					position, tokenIndex = position1318, tokenIndex1318
-					if !_rules[ruleDivide]() {
-						goto l1320
+				l2687:
+					{
+						position2689, tokenIndex2689 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2690
+						}
+						position++
+						goto l2689
+					l2690:
+						position, tokenIndex = position2689, tokenIndex2689
+						if buffer[position] != rune('I') {
+							goto l2684
+						}
+						position++
 					}
-					goto l1318
-				l1320:
-					position, tokenIndex = position1318, tokenIndex1318
-					if !_rules[ruleModulo]() {
-						goto l1316
+				l2689:
+					{
+						position2691, tokenIndex2691 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2692
+						}
+						position++
+						goto l2691
+					l2692:
+						position, tokenIndex = position2691, tokenIndex2691
+						if buffer[position] != rune('L') {
+							goto l2684
+						}
+						position++
 					}
-				}
-			l1318:
-				add(ruleMultDivOp, position1317)
-			}
-			return true
-		l1316:
-			position, tokenIndex = position1316, tokenIndex1316
-			return false
-		},
-		/* 107 Stream <- <(<ident> Action79)> */
-		func() bool {
-			position1321, tokenIndex1321 := position, tokenIndex
-			{
-				position1322 := position
-				{
-					position1323 := position
-					if !_rules[ruleident]() {
-						goto l1321
+				l2691:
+					{
+						position2693, tokenIndex2693 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2694
+						}
+						position++
+						goto l2693
+					l2694:
+						position, tokenIndex = position2693, tokenIndex2693
+						if buffer[position] != rune('L') {
+							goto l2684
+						}
+						position++
 					}
-					add(rulePegText, position1323)
-				}
-				if !_rules[ruleAction79]() {
-					goto l1321
-				}
-				add(ruleStream, position1322)
-			}
-			return true
-		l1321:
-			position, tokenIndex = position1321, tokenIndex1321
-			return false
-		},
-		/* 108 RowMeta <- <RowTimestamp> */
-		func() bool {
-			position1324, tokenIndex1324 := position, tokenIndex
-			{
-				position1325 := position
-				if !_rules[ruleRowTimestamp]() {
-					goto l1324
-				}
-				add(ruleRowMeta, position1325)
-			}
-			return true
-		l1324:
-			position, tokenIndex = position1324, tokenIndex1324
-			return false
-		},
-		/* 109 RowTimestamp <- <(<((ident ':')? ('t' 's' '(' ')'))> Action80)> */
-		func() bool {
-			position1326, tokenIndex1326 := position, tokenIndex
-			{
-				position1327 := position
-				{
-					position1328 := position
+				l2693:
 					{
-						position1329, tokenIndex1329 := position, tokenIndex
-						if !_rules[ruleident]() {
-							goto l1329
+						position2695, tokenIndex2695 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2696
 						}
-						if buffer[position] != rune(':') {
-							goto l1329
+						position++
+						goto l2695
+					l2696:
+						position, tokenIndex = position2695, tokenIndex2695
+						if buffer[position] != rune('I') {
+							goto l2684
 						}
 						position++
-						goto l1330
-					l1329:
-						position, tokenIndex = position1329, tokenIndex1329
 					}
-				l1330:
-					if buffer[position] != rune('t') {
-						goto l1326
+				l2695:
+					{
+						position2697, tokenIndex2697 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2698
+						}
+						position++
+						goto l2697
+					l2698:
+						position, tokenIndex = position2697, tokenIndex2697
+						if buffer[position] != rune('S') {
+							goto l2684
+						}
+						position++
 					}
-					position++
-					if buffer[position] != rune('s') {
-						goto l1326
+				l2697:
+					{
+						position2699, tokenIndex2699 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2700
+						}
+						position++
+						goto l2699
+					l2700:
+						position, tokenIndex = position2699, tokenIndex2699
+						if buffer[position] != rune('E') {
+							goto l2684
+						}
+						position++
 					}
-					position++
-					if buffer[position] != rune('(') {
-						goto l1326
+				l2699:
+					{
+						position2701, tokenIndex2701 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l2702
+						}
+						position++
+						goto l2701
+					l2702:
+						position, tokenIndex = position2701, tokenIndex2701
+						if buffer[position] != rune('C') {
+							goto l2684
+						}
+						position++
 					}
-					position++
-					if buffer[position] != rune(')') {
-						goto l1326
+				l2701:
+					{
+						position2703, tokenIndex2703 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2704
+						}
+						position++
+						goto l2703
+					l2704:
+						position, tokenIndex = position2703, tokenIndex2703
+						if buffer[position] != rune('O') {
+							goto l2684
+						}
+						position++
 					}
-					position++
-					add(rulePegText, position1328)
+				l2703:
+					{
+						position2705, tokenIndex2705 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2706
+						}
+						position++
+						goto l2705
+					l2706:
+						position, tokenIndex = position2705, tokenIndex2705
+						if buffer[position] != rune('N') {
+							goto l2684
+						}
+						position++
+					}
+				l2705:
+					{
+						position2707, tokenIndex2707 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2708
+						}
+						position++
+						goto l2707
+					l2708:
+						position, tokenIndex = position2707, tokenIndex2707
+						if buffer[position] != rune('D') {
+							goto l2684
+						}
+						position++
+					}
+				l2707:
+					{
+						position2709, tokenIndex2709 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2710
+						}
+						position++
+						goto l2709
+					l2710:
+						position, tokenIndex = position2709, tokenIndex2709
+						if buffer[position] != rune('S') {
+							goto l2684
+						}
+						position++
+					}
+				l2709:
+					add(rulePegText, position2686)
 				}
-				if !_rules[ruleAction80]() {
-					goto l1326
+				if !_rules[ruleAction147]() {
+					goto l2684
 				}
-				add(ruleRowTimestamp, position1327)
+				add(ruleMILLISECONDS, position2685)
 			}
 			return true
-		l1326:
-			position, tokenIndex = position1326, tokenIndex1326
+		l2684:
+			position, tokenIndex = position2684, tokenIndex2684
 			return false
 		},
-		/* 110 RowValue <- <(<((ident ':' !':')? jsonGetPath)> Action81)> */
+		/* 185 DropOnError <- <(<(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P'))> Action148)> */
 		func() bool {
-			position1331, tokenIndex1331 := position, tokenIndex
+			position2711, tokenIndex2711 := position, tokenIndex
 			{
-				position1332 := position
+				position2712 := position
 				{
-					position1333 := position
+					position2713 := position
 					{
-						position1334, tokenIndex1334 := position, tokenIndex
-						if !_rules[ruleident]() {
-							goto l1334
+						position2714, tokenIndex2714 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2715
 						}
-						if buffer[position] != rune(':') {
-							goto l1334
+						position++
+						goto l2714
+					l2715:
+						position, tokenIndex = position2714, tokenIndex2714
+						if buffer[position] != rune('D') {
+							goto l2711
 						}
 						position++
-						{
-							position1336, tokenIndex1336 := position, tokenIndex
-							if buffer[position] != rune(':') {
-								goto l1336
-							}
-							position++
-							goto l1334
-						l1336:
-							position, tokenIndex = position1336, tokenIndex1336
+					}
+				l2714:
+					{
+						position2716, tokenIndex2716 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2717
 						}
-						goto l1335
-					l1334:
-						position, tokenIndex = position1334, tokenIndex1334
+						position++
+						goto l2716
+					l2717:
+						position, tokenIndex = position2716, tokenIndex2716
+						if buffer[position] != rune('R') {
+							goto l2711
+						}
+						position++
 					}
-				l1335:
-					if !_rules[rulejsonGetPath]() {
-						goto l1331
+				l2716:
+					{
+						position2718, tokenIndex2718 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2719
+						}
+						position++
+						goto l2718
+					l2719:
+						position, tokenIndex = position2718, tokenIndex2718
+						if buffer[position] != rune('O') {
+							goto l2711
+						}
+						position++
+					}
+				l2718:
+					{
+						position2720, tokenIndex2720 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2721
+						}
+						position++
+						goto l2720
+					l2721:
+						position, tokenIndex = position2720, tokenIndex2720
+						if buffer[position] != rune('P') {
+							goto l2711
+						}
+						position++
 					}
-					add(rulePegText, position1333)
+				l2720:
+					add(rulePegText, position2713)
 				}
-				if !_rules[ruleAction81]() {
-					goto l1331
+				if !_rules[ruleAction148]() {
+					goto l2711
 				}
-				add(ruleRowValue, position1332)
+				add(ruleDropOnError, position2712)
 			}
 			return true
-		l1331:
-			position, tokenIndex = position1331, tokenIndex1331
+		l2711:
+			position, tokenIndex = position2711, tokenIndex2711
 			return false
 		},
-		/* 111 NumericLiteral <- <(<('-'? [0-9]+)> Action82)> */
+		/* 186 StopOnError <- <(<(('s' / 'S') ('t' / 'T') ('o' / 'O') ('p' / 'P'))> Action149)> */
 		func() bool {
-			position1337, tokenIndex1337 := position, tokenIndex
+			position2722, tokenIndex2722 := position, tokenIndex
 			{
-				position1338 := position
+				position2723 := position
 				{
-					position1339 := position
+					position2724 := position
 					{
-						position1340, tokenIndex1340 := position, tokenIndex
-						if buffer[position] != rune('-') {
-							goto l1340
+						position2725, tokenIndex2725 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2726
+						}
+						position++
+						goto l2725
+					l2726:
+						position, tokenIndex = position2725, tokenIndex2725
+						if buffer[position] != rune('S') {
+							goto l2722
 						}
 						position++
-						goto l1341
-					l1340:
-						position, tokenIndex = position1340, tokenIndex1340
 					}
-				l1341:
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1337
+				l2725:
+					{
+						position2727, tokenIndex2727 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2728
+						}
+						position++
+						goto l2727
+					l2728:
+						position, tokenIndex = position2727, tokenIndex2727
+						if buffer[position] != rune('T') {
+							goto l2722
+						}
+						position++
 					}
-					position++
-				l1342:
+				l2727:
 					{
-						position1343, tokenIndex1343 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1343
+						position2729, tokenIndex2729 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2730
+						}
+						position++
+						goto l2729
+					l2730:
+						position, tokenIndex = position2729, tokenIndex2729
+						if buffer[position] != rune('O') {
+							goto l2722
+						}
+						position++
+					}
+				l2729:
+					{
+						position2731, tokenIndex2731 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2732
+						}
+						position++
+						goto l2731
+					l2732:
+						position, tokenIndex = position2731, tokenIndex2731
+						if buffer[position] != rune('P') {
+							goto l2722
 						}
 						position++
-						goto l1342
-					l1343:
-						position, tokenIndex = position1343, tokenIndex1343
 					}
-					add(rulePegText, position1339)
+				l2731:
+					add(rulePegText, position2724)
 				}
-				if !_rules[ruleAction82]() {
-					goto l1337
+				if !_rules[ruleAction149]() {
+					goto l2722
 				}
-				add(ruleNumericLiteral, position1338)
+				add(ruleStopOnError, position2723)
 			}
 			return true
-		l1337:
-			position, tokenIndex = position1337, tokenIndex1337
+		l2722:
+			position, tokenIndex = position2722, tokenIndex2722
 			return false
 		},
-		/* 112 NonNegativeNumericLiteral <- <(<[0-9]+> Action83)> */
+		/* 187 DLQOnError <- <(<(('d' / 'D') ('l' / 'L') ('q' / 'Q'))> Action150)> */
 		func() bool {
-			position1344, tokenIndex1344 := position, tokenIndex
+			position2733, tokenIndex2733 := position, tokenIndex
 			{
-				position1345 := position
+				position2734 := position
 				{
-					position1346 := position
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1344
+					position2735 := position
+					{
+						position2736, tokenIndex2736 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2737
+						}
+						position++
+						goto l2736
+					l2737:
+						position, tokenIndex = position2736, tokenIndex2736
+						if buffer[position] != rune('D') {
+							goto l2733
+						}
+						position++
 					}
-					position++
-				l1347:
+				l2736:
 					{
-						position1348, tokenIndex1348 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1348
+						position2738, tokenIndex2738 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2739
+						}
+						position++
+						goto l2738
+					l2739:
+						position, tokenIndex = position2738, tokenIndex2738
+						if buffer[position] != rune('L') {
+							goto l2733
+						}
+						position++
+					}
+				l2738:
+					{
+						position2740, tokenIndex2740 := position, tokenIndex
+						if buffer[position] != rune('q') {
+							goto l2741
+						}
+						position++
+						goto l2740
+					l2741:
+						position, tokenIndex = position2740, tokenIndex2740
+						if buffer[position] != rune('Q') {
+							goto l2733
 						}
 						position++
-						goto l1347
-					l1348:
-						position, tokenIndex = position1348, tokenIndex1348
 					}
-					add(rulePegText, position1346)
+				l2740:
+					add(rulePegText, position2735)
 				}
-				if !_rules[ruleAction83]() {
-					goto l1344
+				if !_rules[ruleAction150]() {
+					goto l2733
 				}
-				add(ruleNonNegativeNumericLiteral, position1345)
+				add(ruleDLQOnError, position2734)
 			}
 			return true
-		l1344:
-			position, tokenIndex = position1344, tokenIndex1344
+		l2733:
+			position, tokenIndex = position2733, tokenIndex2733
 			return false
 		},
-		/* 113 FloatLiteral <- <(<('-'? [0-9]+ '.' [0-9]+)> Action84)> */
+		/* 188 RetryOnError <- <(<(('r' / 'R') ('e' / 'E') ('t' / 'T') ('r' / 'R') ('y' / 'Y'))> Action151)> */
 		func() bool {
-			position1349, tokenIndex1349 := position, tokenIndex
+			position2742, tokenIndex2742 := position, tokenIndex
 			{
-				position1350 := position
+				position2743 := position
 				{
-					position1351 := position
+					position2744 := position
 					{
-						position1352, tokenIndex1352 := position, tokenIndex
-						if buffer[position] != rune('-') {
-							goto l1352
+						position2745, tokenIndex2745 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2746
+						}
+						position++
+						goto l2745
+					l2746:
+						position, tokenIndex = position2745, tokenIndex2745
+						if buffer[position] != rune('R') {
+							goto l2742
 						}
 						position++
-						goto l1353
-					l1352:
-						position, tokenIndex = position1352, tokenIndex1352
-					}
-				l1353:
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1349
 					}
-					position++
-				l1354:
+				l2745:
 					{
-						position1355, tokenIndex1355 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1355
+						position2747, tokenIndex2747 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2748
+						}
+						position++
+						goto l2747
+					l2748:
+						position, tokenIndex = position2747, tokenIndex2747
+						if buffer[position] != rune('E') {
+							goto l2742
 						}
 						position++
-						goto l1354
-					l1355:
-						position, tokenIndex = position1355, tokenIndex1355
-					}
-					if buffer[position] != rune('.') {
-						goto l1349
 					}
-					position++
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1349
+				l2747:
+					{
+						position2749, tokenIndex2749 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2750
+						}
+						position++
+						goto l2749
+					l2750:
+						position, tokenIndex = position2749, tokenIndex2749
+						if buffer[position] != rune('T') {
+							goto l2742
+						}
+						position++
 					}
-					position++
-				l1356:
+				l2749:
 					{
-						position1357, tokenIndex1357 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1357
+						position2751, tokenIndex2751 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2752
+						}
+						position++
+						goto l2751
+					l2752:
+						position, tokenIndex = position2751, tokenIndex2751
+						if buffer[position] != rune('R') {
+							goto l2742
 						}
 						position++
-						goto l1356
-					l1357:
-						position, tokenIndex = position1357, tokenIndex1357
 					}
-					add(rulePegText, position1351)
-				}
-				if !_rules[ruleAction84]() {
-					goto l1349
-				}
-				add(ruleFloatLiteral, position1350)
-			}
-			return true
-		l1349:
-			position, tokenIndex = position1349, tokenIndex1349
-			return false
-		},
-		/* 114 Function <- <(<ident> Action85)> */
-		func() bool {
-			position1358, tokenIndex1358 := position, tokenIndex
-			{
-				position1359 := position
-				{
-					position1360 := position
-					if !_rules[ruleident]() {
-						goto l1358
+				l2751:
+					{
+						position2753, tokenIndex2753 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l2754
+						}
+						position++
+						goto l2753
+					l2754:
+						position, tokenIndex = position2753, tokenIndex2753
+						if buffer[position] != rune('Y') {
+							goto l2742
+						}
+						position++
 					}
-					add(rulePegText, position1360)
+				l2753:
+					add(rulePegText, position2744)
 				}
-				if !_rules[ruleAction85]() {
-					goto l1358
+				if !_rules[ruleAction151]() {
+					goto l2742
 				}
-				add(ruleFunction, position1359)
+				add(ruleRetryOnError, position2743)
 			}
 			return true
-		l1358:
-			position, tokenIndex = position1358, tokenIndex1358
+		l2742:
+			position, tokenIndex = position2742, tokenIndex2742
 			return false
 		},
-		/* 115 NullLiteral <- <(<(('n' / 'N') ('u' / 'U') ('l' / 'L') ('l' / 'L'))> Action86)> */
+		/* 189 Wait <- <(<(('w' / 'W') ('a' / 'A') ('i' / 'I') ('t' / 'T'))> Action152)> */
 		func() bool {
-			position1361, tokenIndex1361 := position, tokenIndex
+			position2755, tokenIndex2755 := position, tokenIndex
 			{
-				position1362 := position
+				position2756 := position
 				{
-					position1363 := position
+					position2757 := position
 					{
-						position1364, tokenIndex1364 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1365
+						position2758, tokenIndex2758 := position, tokenIndex
+						if buffer[position] != rune('w') {
+							goto l2759
 						}
 						position++
-						goto l1364
-					l1365:
-						position, tokenIndex = position1364, tokenIndex1364
-						if buffer[position] != rune('N') {
-							goto l1361
+						goto l2758
+					l2759:
+						position, tokenIndex = position2758, tokenIndex2758
+						if buffer[position] != rune('W') {
+							goto l2755
 						}
 						position++
 					}
-				l1364:
+				l2758:
 					{
-						position1366, tokenIndex1366 := position, tokenIndex
-						if buffer[position] != rune('u') {
-							goto l1367
+						position2760, tokenIndex2760 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2761
 						}
 						position++
-						goto l1366
-					l1367:
-						position, tokenIndex = position1366, tokenIndex1366
-						if buffer[position] != rune('U') {
-							goto l1361
+						goto l2760
+					l2761:
+						position, tokenIndex = position2760, tokenIndex2760
+						if buffer[position] != rune('A') {
+							goto l2755
 						}
 						position++
 					}
-				l1366:
+				l2760:
 					{
-						position1368, tokenIndex1368 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1369
+						position2762, tokenIndex2762 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2763
 						}
 						position++
-						goto l1368
-					l1369:
-						position, tokenIndex = position1368, tokenIndex1368
-						if buffer[position] != rune('L') {
-							goto l1361
+						goto l2762
+					l2763:
+						position, tokenIndex = position2762, tokenIndex2762
+						if buffer[position] != rune('I') {
+							goto l2755
 						}
 						position++
 					}
-				l1368:
+				l2762:
 					{
-						position1370, tokenIndex1370 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1371
+						position2764, tokenIndex2764 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2765
 						}
 						position++
-						goto l1370
-					l1371:
-						position, tokenIndex = position1370, tokenIndex1370
-						if buffer[position] != rune('L') {
-							goto l1361
+						goto l2764
+					l2765:
+						position, tokenIndex = position2764, tokenIndex2764
+						if buffer[position] != rune('T') {
+							goto l2755
 						}
 						position++
 					}
-				l1370:
-					add(rulePegText, position1363)
+				l2764:
+					add(rulePegText, position2757)
 				}
-				if !_rules[ruleAction86]() {
-					goto l1361
+				if !_rules[ruleAction152]() {
+					goto l2755
 				}
-				add(ruleNullLiteral, position1362)
+				add(ruleWait, position2756)
 			}
 			return true
-		l1361:
-			position, tokenIndex = position1361, tokenIndex1361
+		l2755:
+			position, tokenIndex = position2755, tokenIndex2755
 			return false
 		},
-		/* 116 Missing <- <(<(('m' / 'M') ('i' / 'I') ('s' / 'S') ('s' / 'S') ('i' / 'I') ('n' / 'N') ('g' / 'G'))> Action87)> */
+		/* 190 DropOldest <- <(<(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('o' / 'O') ('l' / 'L') ('d' / 'D') ('e' / 'E') ('s' / 'S') ('t' / 'T')))> Action153)> */
 		func() bool {
-			position1372, tokenIndex1372 := position, tokenIndex
+			position2766, tokenIndex2766 := position, tokenIndex
 			{
-				position1373 := position
+				position2767 := position
 				{
-					position1374 := position
+					position2768 := position
 					{
-						position1375, tokenIndex1375 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1376
+						position2769, tokenIndex2769 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2770
 						}
 						position++
-						goto l1375
-					l1376:
-						position, tokenIndex = position1375, tokenIndex1375
-						if buffer[position] != rune('M') {
-							goto l1372
+						goto l2769
+					l2770:
+						position, tokenIndex = position2769, tokenIndex2769
+						if buffer[position] != rune('D') {
+							goto l2766
 						}
 						position++
 					}
-				l1375:
+				l2769:
 					{
-						position1377, tokenIndex1377 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1378
+						position2771, tokenIndex2771 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2772
 						}
 						position++
-						goto l1377
-					l1378:
-						position, tokenIndex = position1377, tokenIndex1377
-						if buffer[position] != rune('I') {
-							goto l1372
+						goto l2771
+					l2772:
+						position, tokenIndex = position2771, tokenIndex2771
+						if buffer[position] != rune('R') {
+							goto l2766
 						}
 						position++
 					}
-				l1377:
+				l2771:
 					{
-						position1379, tokenIndex1379 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1380
+						position2773, tokenIndex2773 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2774
 						}
 						position++
-						goto l1379
-					l1380:
-						position, tokenIndex = position1379, tokenIndex1379
-						if buffer[position] != rune('S') {
-							goto l1372
+						goto l2773
+					l2774:
+						position, tokenIndex = position2773, tokenIndex2773
+						if buffer[position] != rune('O') {
+							goto l2766
 						}
 						position++
 					}
-				l1379:
+				l2773:
 					{
-						position1381, tokenIndex1381 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1382
+						position2775, tokenIndex2775 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2776
 						}
 						position++
-						goto l1381
-					l1382:
-						position, tokenIndex = position1381, tokenIndex1381
-						if buffer[position] != rune('S') {
-							goto l1372
+						goto l2775
+					l2776:
+						position, tokenIndex = position2775, tokenIndex2775
+						if buffer[position] != rune('P') {
+							goto l2766
 						}
 						position++
 					}
-				l1381:
+				l2775:
+					if !_rules[rulesp]() {
+						goto l2766
+					}
 					{
-						position1383, tokenIndex1383 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1384
+						position2777, tokenIndex2777 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2778
 						}
 						position++
-						goto l1383
-					l1384:
-						position, tokenIndex = position1383, tokenIndex1383
-						if buffer[position] != rune('I') {
-							goto l1372
+						goto l2777
+					l2778:
+						position, tokenIndex = position2777, tokenIndex2777
+						if buffer[position] != rune('O') {
+							goto l2766
 						}
 						position++
 					}
-				l1383:
+				l2777:
 					{
-						position1385, tokenIndex1385 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1386
+						position2779, tokenIndex2779 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2780
 						}
 						position++
-						goto l1385
-					l1386:
-						position, tokenIndex = position1385, tokenIndex1385
-						if buffer[position] != rune('N') {
-							goto l1372
+						goto l2779
+					l2780:
+						position, tokenIndex = position2779, tokenIndex2779
+						if buffer[position] != rune('L') {
+							goto l2766
 						}
 						position++
 					}
-				l1385:
+				l2779:
 					{
-						position1387, tokenIndex1387 := position, tokenIndex
-						if buffer[position] != rune('g') {
-							goto l1388
+						position2781, tokenIndex2781 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2782
 						}
 						position++
-						goto l1387
-					l1388:
-						position, tokenIndex = position1387, tokenIndex1387
-						if buffer[position] != rune('G') {
-							goto l1372
+						goto l2781
+					l2782:
+						position, tokenIndex = position2781, tokenIndex2781
+						if buffer[position] != rune('D') {
+							goto l2766
 						}
 						position++
 					}
-				l1387:
-					add(rulePegText, position1374)
-				}
-				if !_rules[ruleAction87]() {
-					goto l1372
-				}
-				add(ruleMissing, position1373)
-			}
-			return true
-		l1372:
-			position, tokenIndex = position1372, tokenIndex1372
-			return false
-		},
-		/* 117 BooleanLiteral <- <(TRUE / FALSE)> */
-		func() bool {
-			position1389, tokenIndex1389 := position, tokenIndex
-			{
-				position1390 := position
-				{
-					position1391, tokenIndex1391 := position, tokenIndex
-					if !_rules[ruleTRUE]() {
-						goto l1392
+				l2781:
+					{
+						position2783, tokenIndex2783 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2784
+						}
+						position++
+						goto l2783
+					l2784:
+						position, tokenIndex = position2783, tokenIndex2783
+						if buffer[position] != rune('E') {
+							goto l2766
+						}
+						position++
 					}
-					goto l1391
-				l1392:
-					position, tokenIndex = position1391, tokenIndex1391
-					if !_rules[ruleFALSE]() {
-						goto l1389
+				l2783:
+					{
+						position2785, tokenIndex2785 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2786
+						}
+						position++
+						goto l2785
+					l2786:
+						position, tokenIndex = position2785, tokenIndex2785
+						if buffer[position] != rune('S') {
+							goto l2766
+						}
+						position++
+					}
+				l2785:
+					{
+						position2787, tokenIndex2787 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2788
+						}
+						position++
+						goto l2787
+					l2788:
+						position, tokenIndex = position2787, tokenIndex2787
+						if buffer[position] != rune('T') {
+							goto l2766
+						}
+						position++
 					}
+				l2787:
+					add(rulePegText, position2768)
 				}
-			l1391:
-				add(ruleBooleanLiteral, position1390)
+				if !_rules[ruleAction153]() {
+					goto l2766
+				}
+				add(ruleDropOldest, position2767)
 			}
 			return true
-		l1389:
-			position, tokenIndex = position1389, tokenIndex1389
+		l2766:
+			position, tokenIndex = position2766, tokenIndex2766
 			return false
 		},
-		/* 118 TRUE <- <(<(('t' / 'T') ('r' / 'R') ('u' / 'U') ('e' / 'E'))> Action88)> */
+		/* 191 DropNewest <- <(<(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('n' / 'N') ('e' / 'E') ('w' / 'W') ('e' / 'E') ('s' / 'S') ('t' / 'T')))> Action154)> */
 		func() bool {
-			position1393, tokenIndex1393 := position, tokenIndex
+			position2789, tokenIndex2789 := position, tokenIndex
 			{
-				position1394 := position
+				position2790 := position
 				{
-					position1395 := position
+					position2791 := position
 					{
-						position1396, tokenIndex1396 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1397
+						position2792, tokenIndex2792 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2793
 						}
 						position++
-						goto l1396
-					l1397:
-						position, tokenIndex = position1396, tokenIndex1396
-						if buffer[position] != rune('T') {
-							goto l1393
+						goto l2792
+					l2793:
+						position, tokenIndex = position2792, tokenIndex2792
+						if buffer[position] != rune('D') {
+							goto l2789
 						}
 						position++
 					}
-				l1396:
+				l2792:
 					{
-						position1398, tokenIndex1398 := position, tokenIndex
+						position2794, tokenIndex2794 := position, tokenIndex
 						if buffer[position] != rune('r') {
-							goto l1399
+							goto l2795
 						}
 						position++
-						goto l1398
-					l1399:
-						position, tokenIndex = position1398, tokenIndex1398
+						goto l2794
+					l2795:
+						position, tokenIndex = position2794, tokenIndex2794
 						if buffer[position] != rune('R') {
-							goto l1393
+							goto l2789
 						}
 						position++
 					}
-				l1398:
+				l2794:
 					{
-						position1400, tokenIndex1400 := position, tokenIndex
-						if buffer[position] != rune('u') {
-							goto l1401
+						position2796, tokenIndex2796 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2797
 						}
 						position++
-						goto l1400
-					l1401:
-						position, tokenIndex = position1400, tokenIndex1400
-						if buffer[position] != rune('U') {
-							goto l1393
+						goto l2796
+					l2797:
+						position, tokenIndex = position2796, tokenIndex2796
+						if buffer[position] != rune('O') {
+							goto l2789
 						}
 						position++
 					}
-				l1400:
+				l2796:
 					{
-						position1402, tokenIndex1402 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1403
+						position2798, tokenIndex2798 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2799
 						}
 						position++
-						goto l1402
-					l1403:
-						position, tokenIndex = position1402, tokenIndex1402
-						if buffer[position] != rune('E') {
-							goto l1393
+						goto l2798
+					l2799:
+						position, tokenIndex = position2798, tokenIndex2798
+						if buffer[position] != rune('P') {
+							goto l2789
 						}
 						position++
 					}
-				l1402:
-					add(rulePegText, position1395)
-				}
-				if !_rules[ruleAction88]() {
-					goto l1393
-				}
-				add(ruleTRUE, position1394)
-			}
-			return true
-		l1393:
-			position, tokenIndex = position1393, tokenIndex1393
-			return false
-		},
-		/* 119 FALSE <- <(<(('f' / 'F') ('a' / 'A') ('l' / 'L') ('s' / 'S') ('e' / 'E'))> Action89)> */
-		func() bool {
-			position1404, tokenIndex1404 := position, tokenIndex
-			{
-				position1405 := position
-				{
-					position1406 := position
+				l2798:
+					if !_rules[rulesp]() {
+						goto l2789
+					}
 					{
-						position1407, tokenIndex1407 := position, tokenIndex
-						if buffer[position] != rune('f') {
-							goto l1408
+						position2800, tokenIndex2800 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2801
 						}
 						position++
-						goto l1407
-					l1408:
-						position, tokenIndex = position1407, tokenIndex1407
-						if buffer[position] != rune('F') {
-							goto l1404
+						goto l2800
+					l2801:
+						position, tokenIndex = position2800, tokenIndex2800
+						if buffer[position] != rune('N') {
+							goto l2789
 						}
 						position++
 					}
-				l1407:
+				l2800:
 					{
-						position1409, tokenIndex1409 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1410
+						position2802, tokenIndex2802 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2803
 						}
 						position++
-						goto l1409
-					l1410:
-						position, tokenIndex = position1409, tokenIndex1409
-						if buffer[position] != rune('A') {
-							goto l1404
+						goto l2802
+					l2803:
+						position, tokenIndex = position2802, tokenIndex2802
+						if buffer[position] != rune('E') {
+							goto l2789
 						}
 						position++
 					}
-				l1409:
+				l2802:
 					{
-						position1411, tokenIndex1411 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1412
+						position2804, tokenIndex2804 := position, tokenIndex
+						if buffer[position] != rune('w') {
+							goto l2805
 						}
 						position++
-						goto l1411
-					l1412:
-						position, tokenIndex = position1411, tokenIndex1411
-						if buffer[position] != rune('L') {
-							goto l1404
+						goto l2804
+					l2805:
+						position, tokenIndex = position2804, tokenIndex2804
+						if buffer[position] != rune('W') {
+							goto l2789
 						}
 						position++
 					}
-				l1411:
+				l2804:
+					{
+						position2806, tokenIndex2806 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2807
+						}
+						position++
+						goto l2806
+					l2807:
+						position, tokenIndex = position2806, tokenIndex2806
+						if buffer[position] != rune('E') {
+							goto l2789
+						}
+						position++
+					}
+				l2806:
 					{
-						position1413, tokenIndex1413 := position, tokenIndex
+						position2808, tokenIndex2808 := position, tokenIndex
 						if buffer[position] != rune('s') {
-							goto l1414
+							goto l2809
 						}
 						position++
-						goto l1413
-					l1414:
-						position, tokenIndex = position1413, tokenIndex1413
+						goto l2808
+					l2809:
+						position, tokenIndex = position2808, tokenIndex2808
 						if buffer[position] != rune('S') {
-							goto l1404
+							goto l2789
 						}
 						position++
 					}
-				l1413:
+				l2808:
 					{
-						position1415, tokenIndex1415 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1416
+						position2810, tokenIndex2810 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2811
 						}
 						position++
-						goto l1415
-					l1416:
-						position, tokenIndex = position1415, tokenIndex1415
-						if buffer[position] != rune('E') {
-							goto l1404
+						goto l2810
+					l2811:
+						position, tokenIndex = position2810, tokenIndex2810
+						if buffer[position] != rune('T') {
+							goto l2789
 						}
 						position++
 					}
-				l1415:
-					add(rulePegText, position1406)
+				l2810:
+					add(rulePegText, position2791)
 				}
-				if !_rules[ruleAction89]() {
-					goto l1404
+				if !_rules[ruleAction154]() {
+					goto l2789
 				}
-				add(ruleFALSE, position1405)
+				add(ruleDropNewest, position2790)
 			}
 			return true
-		l1404:
-			position, tokenIndex = position1404, tokenIndex1404
+		l2789:
+			position, tokenIndex = position2789, tokenIndex2789
 			return false
 		},
-		/* 120 Wildcard <- <(<((ident ':' !':')? '*')> Action90)> */
+		/* 192 ArrivalOrder <- <(<(('a' / 'A') ('r' / 'R') ('r' / 'R') ('i' / 'I') ('v' / 'V') ('a' / 'A') ('l' / 'L'))> Action155)> */
 		func() bool {
-			position1417, tokenIndex1417 := position, tokenIndex
+			position2812, tokenIndex2812 := position, tokenIndex
 			{
-				position1418 := position
+				position2813 := position
 				{
-					position1419 := position
+					position2814 := position
 					{
-						position1420, tokenIndex1420 := position, tokenIndex
-						if !_rules[ruleident]() {
-							goto l1420
+						position2815, tokenIndex2815 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2816
 						}
-						if buffer[position] != rune(':') {
-							goto l1420
+						position++
+						goto l2815
+					l2816:
+						position, tokenIndex = position2815, tokenIndex2815
+						if buffer[position] != rune('A') {
+							goto l2812
 						}
 						position++
-						{
-							position1422, tokenIndex1422 := position, tokenIndex
-							if buffer[position] != rune(':') {
-								goto l1422
-							}
-							position++
-							goto l1420
-						l1422:
-							position, tokenIndex = position1422, tokenIndex1422
+					}
+				l2815:
+					{
+						position2817, tokenIndex2817 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2818
 						}
-						goto l1421
-					l1420:
-						position, tokenIndex = position1420, tokenIndex1420
+						position++
+						goto l2817
+					l2818:
+						position, tokenIndex = position2817, tokenIndex2817
+						if buffer[position] != rune('R') {
+							goto l2812
+						}
+						position++
 					}
-				l1421:
-					if buffer[position] != rune('*') {
-						goto l1417
+				l2817:
+					{
+						position2819, tokenIndex2819 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2820
+						}
+						position++
+						goto l2819
+					l2820:
+						position, tokenIndex = position2819, tokenIndex2819
+						if buffer[position] != rune('R') {
+							goto l2812
+						}
+						position++
 					}
-					position++
-					add(rulePegText, position1419)
-				}
-				if !_rules[ruleAction90]() {
-					goto l1417
-				}
-				add(ruleWildcard, position1418)
-			}
-			return true
-		l1417:
-			position, tokenIndex = position1417, tokenIndex1417
-			return false
-		},
-		/* 121 StringLiteral <- <(<('"' (('"' '"') / (!'"' .))* '"')> Action91)> */
-		func() bool {
-			position1423, tokenIndex1423 := position, tokenIndex
-			{
-				position1424 := position
-				{
-					position1425 := position
-					if buffer[position] != rune('"') {
-						goto l1423
+				l2819:
+					{
+						position2821, tokenIndex2821 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2822
+						}
+						position++
+						goto l2821
+					l2822:
+						position, tokenIndex = position2821, tokenIndex2821
+						if buffer[position] != rune('I') {
+							goto l2812
+						}
+						position++
 					}
-					position++
-				l1426:
+				l2821:
 					{
-						position1427, tokenIndex1427 := position, tokenIndex
-						{
-							position1428, tokenIndex1428 := position, tokenIndex
-							if buffer[position] != rune('"') {
-								goto l1429
-							}
-							position++
-							if buffer[position] != rune('"') {
-								goto l1429
-							}
-							position++
-							goto l1428
-						l1429:
-							position, tokenIndex = position1428, tokenIndex1428
-							{
-								position1430, tokenIndex1430 := position, tokenIndex
-								if buffer[position] != rune('"') {
-									goto l1430
-								}
-								position++
-								goto l1427
-							l1430:
-								position, tokenIndex = position1430, tokenIndex1430
-							}
-							if !matchDot() {
-								goto l1427
-							}
+						position2823, tokenIndex2823 := position, tokenIndex
+						if buffer[position] != rune('v') {
+							goto l2824
 						}
-					l1428:
-						goto l1426
-					l1427:
-						position, tokenIndex = position1427, tokenIndex1427
+						position++
+						goto l2823
+					l2824:
+						position, tokenIndex = position2823, tokenIndex2823
+						if buffer[position] != rune('V') {
+							goto l2812
+						}
+						position++
 					}
-					if buffer[position] != rune('"') {
-						goto l1423
+				l2823:
+					{
+						position2825, tokenIndex2825 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2826
+						}
+						position++
+						goto l2825
+					l2826:
+						position, tokenIndex = position2825, tokenIndex2825
+						if buffer[position] != rune('A') {
+							goto l2812
+						}
+						position++
 					}
-					position++
-					add(rulePegText, position1425)
+				l2825:
+					{
+						position2827, tokenIndex2827 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2828
+						}
+						position++
+						goto l2827
+					l2828:
+						position, tokenIndex = position2827, tokenIndex2827
+						if buffer[position] != rune('L') {
+							goto l2812
+						}
+						position++
+					}
+				l2827:
+					add(rulePegText, position2814)
 				}
-				if !_rules[ruleAction91]() {
-					goto l1423
+				if !_rules[ruleAction155]() {
+					goto l2812
 				}
-				add(ruleStringLiteral, position1424)
+				add(ruleArrivalOrder, position2813)
 			}
 			return true
-		l1423:
-			position, tokenIndex = position1423, tokenIndex1423
+		l2812:
+			position, tokenIndex = position2812, tokenIndex2812
 			return false
 		},
-		/* 122 ISTREAM <- <(<(('i' / 'I') ('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M'))> Action92)> */
+		/* 193 TimestampOrder <- <(<(('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('m' / 'M') ('p' / 'P'))> Action156)> */
 		func() bool {
-			position1431, tokenIndex1431 := position, tokenIndex
+			position2829, tokenIndex2829 := position, tokenIndex
 			{
-				position1432 := position
+				position2830 := position
 				{
-					position1433 := position
+					position2831 := position
+					{
+						position2832, tokenIndex2832 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2833
+						}
+						position++
+						goto l2832
+					l2833:
+						position, tokenIndex = position2832, tokenIndex2832
+						if buffer[position] != rune('T') {
+							goto l2829
+						}
+						position++
+					}
+				l2832:
 					{
-						position1434, tokenIndex1434 := position, tokenIndex
+						position2834, tokenIndex2834 := position, tokenIndex
 						if buffer[position] != rune('i') {
-							goto l1435
+							goto l2835
 						}
 						position++
-						goto l1434
-					l1435:
-						position, tokenIndex = position1434, tokenIndex1434
+						goto l2834
+					l2835:
+						position, tokenIndex = position2834, tokenIndex2834
 						if buffer[position] != rune('I') {
-							goto l1431
+							goto l2829
 						}
 						position++
 					}
-				l1434:
+				l2834:
 					{
-						position1436, tokenIndex1436 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1437
+						position2836, tokenIndex2836 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2837
 						}
 						position++
-						goto l1436
-					l1437:
-						position, tokenIndex = position1436, tokenIndex1436
-						if buffer[position] != rune('S') {
-							goto l1431
+						goto l2836
+					l2837:
+						position, tokenIndex = position2836, tokenIndex2836
+						if buffer[position] != rune('M') {
+							goto l2829
 						}
 						position++
 					}
-				l1436:
+				l2836:
 					{
-						position1438, tokenIndex1438 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1439
+						position2838, tokenIndex2838 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2839
 						}
 						position++
-						goto l1438
-					l1439:
-						position, tokenIndex = position1438, tokenIndex1438
-						if buffer[position] != rune('T') {
-							goto l1431
+						goto l2838
+					l2839:
+						position, tokenIndex = position2838, tokenIndex2838
+						if buffer[position] != rune('E') {
+							goto l2829
 						}
 						position++
 					}
-				l1438:
+				l2838:
 					{
-						position1440, tokenIndex1440 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1441
+						position2840, tokenIndex2840 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2841
 						}
 						position++
-						goto l1440
-					l1441:
-						position, tokenIndex = position1440, tokenIndex1440
-						if buffer[position] != rune('R') {
-							goto l1431
+						goto l2840
+					l2841:
+						position, tokenIndex = position2840, tokenIndex2840
+						if buffer[position] != rune('S') {
+							goto l2829
 						}
 						position++
 					}
-				l1440:
+				l2840:
 					{
-						position1442, tokenIndex1442 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1443
+						position2842, tokenIndex2842 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2843
 						}
 						position++
-						goto l1442
-					l1443:
-						position, tokenIndex = position1442, tokenIndex1442
-						if buffer[position] != rune('E') {
-							goto l1431
+						goto l2842
+					l2843:
+						position, tokenIndex = position2842, tokenIndex2842
+						if buffer[position] != rune('T') {
+							goto l2829
 						}
 						position++
 					}
-				l1442:
+				l2842:
 					{
-						position1444, tokenIndex1444 := position, tokenIndex
+						position2844, tokenIndex2844 := position, tokenIndex
 						if buffer[position] != rune('a') {
-							goto l1445
+							goto l2845
 						}
 						position++
-						goto l1444
-					l1445:
-						position, tokenIndex = position1444, tokenIndex1444
+						goto l2844
+					l2845:
+						position, tokenIndex = position2844, tokenIndex2844
 						if buffer[position] != rune('A') {
-							goto l1431
+							goto l2829
 						}
 						position++
 					}
-				l1444:
+				l2844:
 					{
-						position1446, tokenIndex1446 := position, tokenIndex
+						position2846, tokenIndex2846 := position, tokenIndex
 						if buffer[position] != rune('m') {
-							goto l1447
+							goto l2847
 						}
 						position++
-						goto l1446
-					l1447:
-						position, tokenIndex = position1446, tokenIndex1446
+						goto l2846
+					l2847:
+						position, tokenIndex = position2846, tokenIndex2846
 						if buffer[position] != rune('M') {
-							goto l1431
+							goto l2829
+						}
+						position++
+					}
+				l2846:
+					{
+						position2848, tokenIndex2848 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2849
+						}
+						position++
+						goto l2848
+					l2849:
+						position, tokenIndex = position2848, tokenIndex2848
+						if buffer[position] != rune('P') {
+							goto l2829
 						}
 						position++
 					}
-				l1446:
-					add(rulePegText, position1433)
+				l2848:
+					add(rulePegText, position2831)
 				}
-				if !_rules[ruleAction92]() {
-					goto l1431
+				if !_rules[ruleAction156]() {
+					goto l2829
 				}
-				add(ruleISTREAM, position1432)
+				add(ruleTimestampOrder, position2830)
 			}
 			return true
-		l1431:
-			position, tokenIndex = position1431, tokenIndex1431
+		l2829:
+			position, tokenIndex = position2829, tokenIndex2829
 			return false
 		},
-		/* 123 DSTREAM <- <(<(('d' / 'D') ('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M'))> Action93)> */
+		/* 194 RoundRobinOrder <- <(<(('r' / 'R') ('o' / 'O') ('u' / 'U') ('n' / 'N') ('d' / 'D') sp (('r' / 'R') ('o' / 'O') ('b' / 'B') ('i' / 'I') ('n' / 'N')))> Action157)> */
 		func() bool {
-			position1448, tokenIndex1448 := position, tokenIndex
+			position2850, tokenIndex2850 := position, tokenIndex
 			{
-				position1449 := position
+				position2851 := position
 				{
-					position1450 := position
+					position2852 := position
 					{
-						position1451, tokenIndex1451 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1452
+						position2853, tokenIndex2853 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2854
 						}
 						position++
-						goto l1451
-					l1452:
-						position, tokenIndex = position1451, tokenIndex1451
-						if buffer[position] != rune('D') {
-							goto l1448
+						goto l2853
+					l2854:
+						position, tokenIndex = position2853, tokenIndex2853
+						if buffer[position] != rune('R') {
+							goto l2850
 						}
 						position++
 					}
-				l1451:
+				l2853:
 					{
-						position1453, tokenIndex1453 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1454
+						position2855, tokenIndex2855 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2856
 						}
 						position++
-						goto l1453
-					l1454:
-						position, tokenIndex = position1453, tokenIndex1453
-						if buffer[position] != rune('S') {
-							goto l1448
+						goto l2855
+					l2856:
+						position, tokenIndex = position2855, tokenIndex2855
+						if buffer[position] != rune('O') {
+							goto l2850
 						}
 						position++
 					}
-				l1453:
+				l2855:
 					{
-						position1455, tokenIndex1455 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1456
+						position2857, tokenIndex2857 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2858
 						}
 						position++
-						goto l1455
-					l1456:
-						position, tokenIndex = position1455, tokenIndex1455
-						if buffer[position] != rune('T') {
-							goto l1448
+						goto l2857
+					l2858:
+						position, tokenIndex = position2857, tokenIndex2857
+						if buffer[position] != rune('U') {
+							goto l2850
 						}
 						position++
 					}
-				l1455:
+				l2857:
+					{
+						position2859, tokenIndex2859 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2860
+						}
+						position++
+						goto l2859
+					l2860:
+						position, tokenIndex = position2859, tokenIndex2859
+						if buffer[position] != rune('N') {
+							goto l2850
+						}
+						position++
+					}
+				l2859:
+					{
+						position2861, tokenIndex2861 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2862
+						}
+						position++
+						goto l2861
+					l2862:
+						position, tokenIndex = position2861, tokenIndex2861
+						if buffer[position] != rune('D') {
+							goto l2850
+						}
+						position++
+					}
+				l2861:
+					if !_rules[rulesp]() {
+						goto l2850
+					}
 					{
-						position1457, tokenIndex1457 := position, tokenIndex
+						position2863, tokenIndex2863 := position, tokenIndex
 						if buffer[position] != rune('r') {
-							goto l1458
+							goto l2864
 						}
 						position++
-						goto l1457
-					l1458:
-						position, tokenIndex = position1457, tokenIndex1457
+						goto l2863
+					l2864:
+						position, tokenIndex = position2863, tokenIndex2863
 						if buffer[position] != rune('R') {
-							goto l1448
+							goto l2850
 						}
 						position++
 					}
-				l1457:
+				l2863:
 					{
-						position1459, tokenIndex1459 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1460
+						position2865, tokenIndex2865 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2866
 						}
 						position++
-						goto l1459
-					l1460:
-						position, tokenIndex = position1459, tokenIndex1459
-						if buffer[position] != rune('E') {
-							goto l1448
+						goto l2865
+					l2866:
+						position, tokenIndex = position2865, tokenIndex2865
+						if buffer[position] != rune('O') {
+							goto l2850
 						}
 						position++
 					}
-				l1459:
+				l2865:
 					{
-						position1461, tokenIndex1461 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1462
+						position2867, tokenIndex2867 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l2868
 						}
 						position++
-						goto l1461
-					l1462:
-						position, tokenIndex = position1461, tokenIndex1461
-						if buffer[position] != rune('A') {
-							goto l1448
+						goto l2867
+					l2868:
+						position, tokenIndex = position2867, tokenIndex2867
+						if buffer[position] != rune('B') {
+							goto l2850
 						}
 						position++
 					}
-				l1461:
+				l2867:
 					{
-						position1463, tokenIndex1463 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1464
+						position2869, tokenIndex2869 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2870
 						}
 						position++
-						goto l1463
-					l1464:
-						position, tokenIndex = position1463, tokenIndex1463
-						if buffer[position] != rune('M') {
-							goto l1448
+						goto l2869
+					l2870:
+						position, tokenIndex = position2869, tokenIndex2869
+						if buffer[position] != rune('I') {
+							goto l2850
 						}
 						position++
 					}
-				l1463:
-					add(rulePegText, position1450)
+				l2869:
+					{
+						position2871, tokenIndex2871 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2872
+						}
+						position++
+						goto l2871
+					l2872:
+						position, tokenIndex = position2871, tokenIndex2871
+						if buffer[position] != rune('N') {
+							goto l2850
+						}
+						position++
+					}
+				l2871:
+					add(rulePegText, position2852)
 				}
-				if !_rules[ruleAction93]() {
-					goto l1448
+				if !_rules[ruleAction157]() {
+					goto l2850
+				}
+				add(ruleRoundRobinOrder, position2851)
+			}
+			return true
+		l2850:
+			position, tokenIndex = position2850, tokenIndex2850
+			return false
+		},
+		/* 195 StreamIdentifier <- <(<ident> Action158)> */
+		func() bool {
+			position2873, tokenIndex2873 := position, tokenIndex
+			{
+				position2874 := position
+				{
+					position2875 := position
+					if !_rules[ruleident]() {
+						goto l2873
+					}
+					add(rulePegText, position2875)
+				}
+				if !_rules[ruleAction158]() {
+					goto l2873
+				}
+				add(ruleStreamIdentifier, position2874)
+			}
+			return true
+		l2873:
+			position, tokenIndex = position2873, tokenIndex2873
+			return false
+		},
+		/* 196 SourceSinkType <- <(<ident> Action159)> */
+		func() bool {
+			position2876, tokenIndex2876 := position, tokenIndex
+			{
+				position2877 := position
+				{
+					position2878 := position
+					if !_rules[ruleident]() {
+						goto l2876
+					}
+					add(rulePegText, position2878)
+				}
+				if !_rules[ruleAction159]() {
+					goto l2876
 				}
-				add(ruleDSTREAM, position1449)
+				add(ruleSourceSinkType, position2877)
 			}
 			return true
-		l1448:
-			position, tokenIndex = position1448, tokenIndex1448
+		l2876:
+			position, tokenIndex = position2876, tokenIndex2876
 			return false
 		},
-		/* 124 RSTREAM <- <(<(('r' / 'R') ('s' / 'S') ('t' / 'T') ('r' / 'R') ('e' / 'E') ('a' / 'A') ('m' / 'M'))> Action94)> */
+		/* 197 SourceSinkParamKey <- <(<ident> Action160)> */
 		func() bool {
-			position1465, tokenIndex1465 := position, tokenIndex
+			position2879, tokenIndex2879 := position, tokenIndex
 			{
-				position1466 := position
+				position2880 := position
 				{
-					position1467 := position
+					position2881 := position
+					if !_rules[ruleident]() {
+						goto l2879
+					}
+					add(rulePegText, position2881)
+				}
+				if !_rules[ruleAction160]() {
+					goto l2879
+				}
+				add(ruleSourceSinkParamKey, position2880)
+			}
+			return true
+		l2879:
+			position, tokenIndex = position2879, tokenIndex2879
+			return false
+		},
+		/* 198 Paused <- <(<(('p' / 'P') ('a' / 'A') ('u' / 'U') ('s' / 'S') ('e' / 'E') ('d' / 'D'))> Action161)> */
+		func() bool {
+			position2882, tokenIndex2882 := position, tokenIndex
+			{
+				position2883 := position
+				{
+					position2884 := position
 					{
-						position1468, tokenIndex1468 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1469
+						position2885, tokenIndex2885 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2886
 						}
 						position++
-						goto l1468
-					l1469:
-						position, tokenIndex = position1468, tokenIndex1468
-						if buffer[position] != rune('R') {
-							goto l1465
+						goto l2885
+					l2886:
+						position, tokenIndex = position2885, tokenIndex2885
+						if buffer[position] != rune('P') {
+							goto l2882
 						}
 						position++
 					}
-				l1468:
+				l2885:
 					{
-						position1470, tokenIndex1470 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1471
+						position2887, tokenIndex2887 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2888
 						}
 						position++
-						goto l1470
-					l1471:
-						position, tokenIndex = position1470, tokenIndex1470
-						if buffer[position] != rune('S') {
-							goto l1465
+						goto l2887
+					l2888:
+						position, tokenIndex = position2887, tokenIndex2887
+						if buffer[position] != rune('A') {
+							goto l2882
 						}
 						position++
 					}
-				l1470:
+				l2887:
 					{
-						position1472, tokenIndex1472 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1473
+						position2889, tokenIndex2889 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2890
 						}
 						position++
-						goto l1472
-					l1473:
-						position, tokenIndex = position1472, tokenIndex1472
-						if buffer[position] != rune('T') {
-							goto l1465
+						goto l2889
+					l2890:
+						position, tokenIndex = position2889, tokenIndex2889
+						if buffer[position] != rune('U') {
+							goto l2882
 						}
 						position++
 					}
-				l1472:
+				l2889:
 					{
-						position1474, tokenIndex1474 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1475
+						position2891, tokenIndex2891 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2892
 						}
 						position++
-						goto l1474
-					l1475:
-						position, tokenIndex = position1474, tokenIndex1474
-						if buffer[position] != rune('R') {
-							goto l1465
+						goto l2891
+					l2892:
+						position, tokenIndex = position2891, tokenIndex2891
+						if buffer[position] != rune('S') {
+							goto l2882
 						}
 						position++
 					}
-				l1474:
+				l2891:
 					{
-						position1476, tokenIndex1476 := position, tokenIndex
+						position2893, tokenIndex2893 := position, tokenIndex
 						if buffer[position] != rune('e') {
-							goto l1477
+							goto l2894
 						}
 						position++
-						goto l1476
-					l1477:
-						position, tokenIndex = position1476, tokenIndex1476
+						goto l2893
+					l2894:
+						position, tokenIndex = position2893, tokenIndex2893
 						if buffer[position] != rune('E') {
-							goto l1465
-						}
-						position++
-					}
-				l1476:
-					{
-						position1478, tokenIndex1478 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1479
-						}
-						position++
-						goto l1478
-					l1479:
-						position, tokenIndex = position1478, tokenIndex1478
-						if buffer[position] != rune('A') {
-							goto l1465
+							goto l2882
 						}
 						position++
 					}
-				l1478:
+				l2893:
 					{
-						position1480, tokenIndex1480 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1481
+						position2895, tokenIndex2895 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2896
 						}
 						position++
-						goto l1480
-					l1481:
-						position, tokenIndex = position1480, tokenIndex1480
-						if buffer[position] != rune('M') {
-							goto l1465
+						goto l2895
+					l2896:
+						position, tokenIndex = position2895, tokenIndex2895
+						if buffer[position] != rune('D') {
+							goto l2882
 						}
 						position++
 					}
-				l1480:
-					add(rulePegText, position1467)
+				l2895:
+					add(rulePegText, position2884)
 				}
-				if !_rules[ruleAction94]() {
-					goto l1465
+				if !_rules[ruleAction161]() {
+					goto l2882
 				}
-				add(ruleRSTREAM, position1466)
+				add(rulePaused, position2883)
 			}
 			return true
-		l1465:
-			position, tokenIndex = position1465, tokenIndex1465
+		l2882:
+			position, tokenIndex = position2882, tokenIndex2882
 			return false
 		},
-		/* 125 TUPLES <- <(<(('t' / 'T') ('u' / 'U') ('p' / 'P') ('l' / 'L') ('e' / 'E') ('s' / 'S'))> Action95)> */
+		/* 199 Unpaused <- <(<(('u' / 'U') ('n' / 'N') ('p' / 'P') ('a' / 'A') ('u' / 'U') ('s' / 'S') ('e' / 'E') ('d' / 'D'))> Action162)> */
 		func() bool {
-			position1482, tokenIndex1482 := position, tokenIndex
+			position2897, tokenIndex2897 := position, tokenIndex
 			{
-				position1483 := position
+				position2898 := position
 				{
-					position1484 := position
+					position2899 := position
 					{
-						position1485, tokenIndex1485 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1486
+						position2900, tokenIndex2900 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2901
 						}
 						position++
-						goto l1485
-					l1486:
-						position, tokenIndex = position1485, tokenIndex1485
-						if buffer[position] != rune('T') {
-							goto l1482
+						goto l2900
+					l2901:
+						position, tokenIndex = position2900, tokenIndex2900
+						if buffer[position] != rune('U') {
+							goto l2897
 						}
 						position++
 					}
-				l1485:
+				l2900:
 					{
-						position1487, tokenIndex1487 := position, tokenIndex
-						if buffer[position] != rune('u') {
-							goto l1488
+						position2902, tokenIndex2902 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2903
 						}
 						position++
-						goto l1487
-					l1488:
-						position, tokenIndex = position1487, tokenIndex1487
-						if buffer[position] != rune('U') {
-							goto l1482
+						goto l2902
+					l2903:
+						position, tokenIndex = position2902, tokenIndex2902
+						if buffer[position] != rune('N') {
+							goto l2897
 						}
 						position++
 					}
-				l1487:
+				l2902:
 					{
-						position1489, tokenIndex1489 := position, tokenIndex
+						position2904, tokenIndex2904 := position, tokenIndex
 						if buffer[position] != rune('p') {
-							goto l1490
+							goto l2905
 						}
 						position++
-						goto l1489
-					l1490:
-						position, tokenIndex = position1489, tokenIndex1489
+						goto l2904
+					l2905:
+						position, tokenIndex = position2904, tokenIndex2904
 						if buffer[position] != rune('P') {
-							goto l1482
+							goto l2897
 						}
 						position++
 					}
-				l1489:
+				l2904:
 					{
-						position1491, tokenIndex1491 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1492
+						position2906, tokenIndex2906 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2907
 						}
 						position++
-						goto l1491
-					l1492:
-						position, tokenIndex = position1491, tokenIndex1491
-						if buffer[position] != rune('L') {
-							goto l1482
+						goto l2906
+					l2907:
+						position, tokenIndex = position2906, tokenIndex2906
+						if buffer[position] != rune('A') {
+							goto l2897
 						}
 						position++
 					}
-				l1491:
+				l2906:
 					{
-						position1493, tokenIndex1493 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1494
+						position2908, tokenIndex2908 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l2909
 						}
 						position++
-						goto l1493
-					l1494:
-						position, tokenIndex = position1493, tokenIndex1493
-						if buffer[position] != rune('E') {
-							goto l1482
+						goto l2908
+					l2909:
+						position, tokenIndex = position2908, tokenIndex2908
+						if buffer[position] != rune('U') {
+							goto l2897
 						}
 						position++
 					}
-				l1493:
+				l2908:
 					{
-						position1495, tokenIndex1495 := position, tokenIndex
+						position2910, tokenIndex2910 := position, tokenIndex
 						if buffer[position] != rune('s') {
-							goto l1496
+							goto l2911
 						}
 						position++
-						goto l1495
-					l1496:
-						position, tokenIndex = position1495, tokenIndex1495
+						goto l2910
+					l2911:
+						position, tokenIndex = position2910, tokenIndex2910
 						if buffer[position] != rune('S') {
-							goto l1482
+							goto l2897
+						}
+						position++
+					}
+				l2910:
+					{
+						position2912, tokenIndex2912 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2913
+						}
+						position++
+						goto l2912
+					l2913:
+						position, tokenIndex = position2912, tokenIndex2912
+						if buffer[position] != rune('E') {
+							goto l2897
+						}
+						position++
+					}
+				l2912:
+					{
+						position2914, tokenIndex2914 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2915
+						}
+						position++
+						goto l2914
+					l2915:
+						position, tokenIndex = position2914, tokenIndex2914
+						if buffer[position] != rune('D') {
+							goto l2897
 						}
 						position++
 					}
-				l1495:
-					add(rulePegText, position1484)
+				l2914:
+					add(rulePegText, position2899)
 				}
-				if !_rules[ruleAction95]() {
-					goto l1482
+				if !_rules[ruleAction162]() {
+					goto l2897
 				}
-				add(ruleTUPLES, position1483)
+				add(ruleUnpaused, position2898)
 			}
 			return true
-		l1482:
-			position, tokenIndex = position1482, tokenIndex1482
+		l2897:
+			position, tokenIndex = position2897, tokenIndex2897
 			return false
 		},
-		/* 126 SECONDS <- <(<(('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S'))> Action96)> */
+		/* 200 Temporary <- <(<(('t' / 'T') ('e' / 'E') ('m' / 'M') ('p' / 'P') ('o' / 'O') ('r' / 'R') ('a' / 'A') ('r' / 'R') ('y' / 'Y'))> Action163)> */
 		func() bool {
-			position1497, tokenIndex1497 := position, tokenIndex
+			position2916, tokenIndex2916 := position, tokenIndex
 			{
-				position1498 := position
+				position2917 := position
 				{
-					position1499 := position
-					{
-						position1500, tokenIndex1500 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1501
+					position2918 := position
+					{
+						position2919, tokenIndex2919 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2920
 						}
 						position++
-						goto l1500
-					l1501:
-						position, tokenIndex = position1500, tokenIndex1500
-						if buffer[position] != rune('S') {
-							goto l1497
+						goto l2919
+					l2920:
+						position, tokenIndex = position2919, tokenIndex2919
+						if buffer[position] != rune('T') {
+							goto l2916
 						}
 						position++
 					}
-				l1500:
+				l2919:
 					{
-						position1502, tokenIndex1502 := position, tokenIndex
+						position2921, tokenIndex2921 := position, tokenIndex
 						if buffer[position] != rune('e') {
-							goto l1503
+							goto l2922
 						}
 						position++
-						goto l1502
-					l1503:
-						position, tokenIndex = position1502, tokenIndex1502
+						goto l2921
+					l2922:
+						position, tokenIndex = position2921, tokenIndex2921
 						if buffer[position] != rune('E') {
-							goto l1497
+							goto l2916
 						}
 						position++
 					}
-				l1502:
+				l2921:
 					{
-						position1504, tokenIndex1504 := position, tokenIndex
-						if buffer[position] != rune('c') {
-							goto l1505
+						position2923, tokenIndex2923 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l2924
 						}
 						position++
-						goto l1504
-					l1505:
-						position, tokenIndex = position1504, tokenIndex1504
-						if buffer[position] != rune('C') {
-							goto l1497
+						goto l2923
+					l2924:
+						position, tokenIndex = position2923, tokenIndex2923
+						if buffer[position] != rune('M') {
+							goto l2916
+						}
+						position++
+					}
+				l2923:
+					{
+						position2925, tokenIndex2925 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l2926
+						}
+						position++
+						goto l2925
+					l2926:
+						position, tokenIndex = position2925, tokenIndex2925
+						if buffer[position] != rune('P') {
+							goto l2916
 						}
 						position++
 					}
-				l1504:
+				l2925:
 					{
-						position1506, tokenIndex1506 := position, tokenIndex
+						position2927, tokenIndex2927 := position, tokenIndex
 						if buffer[position] != rune('o') {
-							goto l1507
+							goto l2928
 						}
 						position++
-						goto l1506
-					l1507:
-						position, tokenIndex = position1506, tokenIndex1506
+						goto l2927
+					l2928:
+						position, tokenIndex = position2927, tokenIndex2927
 						if buffer[position] != rune('O') {
-							goto l1497
+							goto l2916
 						}
 						position++
 					}
-				l1506:
+				l2927:
 					{
-						position1508, tokenIndex1508 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1509
+						position2929, tokenIndex2929 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2930
 						}
 						position++
-						goto l1508
-					l1509:
-						position, tokenIndex = position1508, tokenIndex1508
-						if buffer[position] != rune('N') {
-							goto l1497
+						goto l2929
+					l2930:
+						position, tokenIndex = position2929, tokenIndex2929
+						if buffer[position] != rune('R') {
+							goto l2916
 						}
 						position++
 					}
-				l1508:
+				l2929:
 					{
-						position1510, tokenIndex1510 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1511
+						position2931, tokenIndex2931 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2932
 						}
 						position++
-						goto l1510
-					l1511:
-						position, tokenIndex = position1510, tokenIndex1510
-						if buffer[position] != rune('D') {
-							goto l1497
+						goto l2931
+					l2932:
+						position, tokenIndex = position2931, tokenIndex2931
+						if buffer[position] != rune('A') {
+							goto l2916
 						}
 						position++
 					}
-				l1510:
+				l2931:
 					{
-						position1512, tokenIndex1512 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1513
+						position2933, tokenIndex2933 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l2934
 						}
 						position++
-						goto l1512
-					l1513:
-						position, tokenIndex = position1512, tokenIndex1512
-						if buffer[position] != rune('S') {
-							goto l1497
+						goto l2933
+					l2934:
+						position, tokenIndex = position2933, tokenIndex2933
+						if buffer[position] != rune('R') {
+							goto l2916
+						}
+						position++
+					}
+				l2933:
+					{
+						position2935, tokenIndex2935 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l2936
+						}
+						position++
+						goto l2935
+					l2936:
+						position, tokenIndex = position2935, tokenIndex2935
+						if buffer[position] != rune('Y') {
+							goto l2916
 						}
 						position++
 					}
-				l1512:
-					add(rulePegText, position1499)
+				l2935:
+					add(rulePegText, position2918)
 				}
-				if !_rules[ruleAction96]() {
-					goto l1497
+				if !_rules[ruleAction163]() {
+					goto l2916
 				}
-				add(ruleSECONDS, position1498)
+				add(ruleTemporary, position2917)
 			}
 			return true
-		l1497:
-			position, tokenIndex = position1497, tokenIndex1497
+		l2916:
+			position, tokenIndex = position2916, tokenIndex2916
 			return false
 		},
-		/* 127 MILLISECONDS <- <(<(('m' / 'M') ('i' / 'I') ('l' / 'L') ('l' / 'L') ('i' / 'I') ('s' / 'S') ('e' / 'E') ('c' / 'C') ('o' / 'O') ('n' / 'N') ('d' / 'D') ('s' / 'S'))> Action97)> */
+		/* 201 Ascending <- <(<(('a' / 'A') ('s' / 'S') ('c' / 'C'))> Action164)> */
 		func() bool {
-			position1514, tokenIndex1514 := position, tokenIndex
+			position2937, tokenIndex2937 := position, tokenIndex
 			{
-				position1515 := position
+				position2938 := position
 				{
-					position1516 := position
+					position2939 := position
 					{
-						position1517, tokenIndex1517 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1518
+						position2940, tokenIndex2940 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2941
 						}
 						position++
-						goto l1517
-					l1518:
-						position, tokenIndex = position1517, tokenIndex1517
-						if buffer[position] != rune('M') {
-							goto l1514
+						goto l2940
+					l2941:
+						position, tokenIndex = position2940, tokenIndex2940
+						if buffer[position] != rune('A') {
+							goto l2937
 						}
 						position++
 					}
-				l1517:
+				l2940:
 					{
-						position1519, tokenIndex1519 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1520
+						position2942, tokenIndex2942 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l2943
 						}
 						position++
-						goto l1519
-					l1520:
-						position, tokenIndex = position1519, tokenIndex1519
-						if buffer[position] != rune('I') {
-							goto l1514
+						goto l2942
+					l2943:
+						position, tokenIndex = position2942, tokenIndex2942
+						if buffer[position] != rune('S') {
+							goto l2937
 						}
 						position++
 					}
-				l1519:
+				l2942:
 					{
-						position1521, tokenIndex1521 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1522
+						position2944, tokenIndex2944 := position, tokenIndex
+						if buffer[position] != rune('c') {
+							goto l2945
 						}
 						position++
-						goto l1521
-					l1522:
-						position, tokenIndex = position1521, tokenIndex1521
-						if buffer[position] != rune('L') {
-							goto l1514
+						goto l2944
+					l2945:
+						position, tokenIndex = position2944, tokenIndex2944
+						if buffer[position] != rune('C') {
+							goto l2937
 						}
 						position++
 					}
-				l1521:
+				l2944:
+					add(rulePegText, position2939)
+				}
+				if !_rules[ruleAction164]() {
+					goto l2937
+				}
+				add(ruleAscending, position2938)
+			}
+			return true
+		l2937:
+			position, tokenIndex = position2937, tokenIndex2937
+			return false
+		},
+		/* 202 Descending <- <(<(('d' / 'D') ('e' / 'E') ('s' / 'S') ('c' / 'C'))> Action165)> */
+		func() bool {
+			position2946, tokenIndex2946 := position, tokenIndex
+			{
+				position2947 := position
+				{
+					position2948 := position
 					{
-						position1523, tokenIndex1523 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1524
+						position2949, tokenIndex2949 := position, tokenIndex
+						if buffer[position] != rune('d') {
+							goto l2950
 						}
 						position++
-						goto l1523
-					l1524:
-						position, tokenIndex = position1523, tokenIndex1523
-						if buffer[position] != rune('L') {
-							goto l1514
+						goto l2949
+					l2950:
+						position, tokenIndex = position2949, tokenIndex2949
+						if buffer[position] != rune('D') {
+							goto l2946
 						}
 						position++
 					}
-				l1523:
+				l2949:
 					{
-						position1525, tokenIndex1525 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1526
+						position2951, tokenIndex2951 := position, tokenIndex
+						if buffer[position] != rune('e') {
+							goto l2952
 						}
 						position++
-						goto l1525
-					l1526:
-						position, tokenIndex = position1525, tokenIndex1525
-						if buffer[position] != rune('I') {
-							goto l1514
+						goto l2951
+					l2952:
+						position, tokenIndex = position2951, tokenIndex2951
+						if buffer[position] != rune('E') {
+							goto l2946
 						}
 						position++
 					}
-				l1525:
+				l2951:
 					{
-						position1527, tokenIndex1527 := position, tokenIndex
+						position2953, tokenIndex2953 := position, tokenIndex
 						if buffer[position] != rune('s') {
-							goto l1528
+							goto l2954
 						}
 						position++
-						goto l1527
-					l1528:
-						position, tokenIndex = position1527, tokenIndex1527
+						goto l2953
+					l2954:
+						position, tokenIndex = position2953, tokenIndex2953
 						if buffer[position] != rune('S') {
-							goto l1514
-						}
-						position++
-					}
-				l1527:
-					{
-						position1529, tokenIndex1529 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1530
-						}
-						position++
-						goto l1529
-					l1530:
-						position, tokenIndex = position1529, tokenIndex1529
-						if buffer[position] != rune('E') {
-							goto l1514
+							goto l2946
 						}
 						position++
 					}
-				l1529:
+				l2953:
 					{
-						position1531, tokenIndex1531 := position, tokenIndex
+						position2955, tokenIndex2955 := position, tokenIndex
 						if buffer[position] != rune('c') {
-							goto l1532
+							goto l2956
 						}
 						position++
-						goto l1531
-					l1532:
-						position, tokenIndex = position1531, tokenIndex1531
+						goto l2955
+					l2956:
+						position, tokenIndex = position2955, tokenIndex2955
 						if buffer[position] != rune('C') {
-							goto l1514
+							goto l2946
 						}
 						position++
 					}
-				l1531:
+				l2955:
+					add(rulePegText, position2948)
+				}
+				if !_rules[ruleAction165]() {
+					goto l2946
+				}
+				add(ruleDescending, position2947)
+			}
+			return true
+		l2946:
+			position, tokenIndex = position2946, tokenIndex2946
+			return false
+		},
+		/* 203 Type <- <(Bool / Int / Float / String / Blob / Timestamp / Array / Map)> */
+		func() bool {
+			position2957, tokenIndex2957 := position, tokenIndex
+			{
+				position2958 := position
+				{
+					position2959, tokenIndex2959 := position, tokenIndex
+					if !_rules[ruleBool]() {
+						goto l2960
+					}
+					goto l2959
+				l2960:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleInt]() {
+						goto l2961
+					}
+					goto l2959
+				l2961:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleFloat]() {
+						goto l2962
+					}
+					goto l2959
+				l2962:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleString]() {
+						goto l2963
+					}
+					goto l2959
+				l2963:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleBlob]() {
+						goto l2964
+					}
+					goto l2959
+				l2964:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleTimestamp]() {
+						goto l2965
+					}
+					goto l2959
+				l2965:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleArray]() {
+						goto l2966
+					}
+					goto l2959
+				l2966:
+					position, tokenIndex = position2959, tokenIndex2959
+					if !_rules[ruleMap]() {
+						goto l2957
+					}
+				}
+			l2959:
+				add(ruleType, position2958)
+			}
+			return true
+		l2957:
+			position, tokenIndex = position2957, tokenIndex2957
+			return false
+		},
+		/* 204 Bool <- <(<(('b' / 'B') ('o' / 'O') ('o' / 'O') ('l' / 'L'))> Action166)> */
+		func() bool {
+			position2967, tokenIndex2967 := position, tokenIndex
+			{
+				position2968 := position
+				{
+					position2969 := position
 					{
-						position1533, tokenIndex1533 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1534
+						position2970, tokenIndex2970 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l2971
 						}
 						position++
-						goto l1533
-					l1534:
-						position, tokenIndex = position1533, tokenIndex1533
-						if buffer[position] != rune('O') {
-							goto l1514
+						goto l2970
+					l2971:
+						position, tokenIndex = position2970, tokenIndex2970
+						if buffer[position] != rune('B') {
+							goto l2967
 						}
 						position++
 					}
-				l1533:
+				l2970:
 					{
-						position1535, tokenIndex1535 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1536
+						position2972, tokenIndex2972 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2973
 						}
 						position++
-						goto l1535
-					l1536:
-						position, tokenIndex = position1535, tokenIndex1535
-						if buffer[position] != rune('N') {
-							goto l1514
+						goto l2972
+					l2973:
+						position, tokenIndex = position2972, tokenIndex2972
+						if buffer[position] != rune('O') {
+							goto l2967
 						}
 						position++
 					}
-				l1535:
+				l2972:
 					{
-						position1537, tokenIndex1537 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1538
+						position2974, tokenIndex2974 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l2975
 						}
 						position++
-						goto l1537
-					l1538:
-						position, tokenIndex = position1537, tokenIndex1537
-						if buffer[position] != rune('D') {
-							goto l1514
+						goto l2974
+					l2975:
+						position, tokenIndex = position2974, tokenIndex2974
+						if buffer[position] != rune('O') {
+							goto l2967
 						}
 						position++
 					}
-				l1537:
+				l2974:
 					{
-						position1539, tokenIndex1539 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1540
+						position2976, tokenIndex2976 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2977
 						}
 						position++
-						goto l1539
-					l1540:
-						position, tokenIndex = position1539, tokenIndex1539
-						if buffer[position] != rune('S') {
-							goto l1514
+						goto l2976
+					l2977:
+						position, tokenIndex = position2976, tokenIndex2976
+						if buffer[position] != rune('L') {
+							goto l2967
 						}
 						position++
 					}
-				l1539:
-					add(rulePegText, position1516)
+				l2976:
+					add(rulePegText, position2969)
 				}
-				if !_rules[ruleAction97]() {
-					goto l1514
+				if !_rules[ruleAction166]() {
+					goto l2967
 				}
-				add(ruleMILLISECONDS, position1515)
+				add(ruleBool, position2968)
 			}
 			return true
-		l1514:
-			position, tokenIndex = position1514, tokenIndex1514
+		l2967:
+			position, tokenIndex = position2967, tokenIndex2967
 			return false
 		},
-		/* 128 Wait <- <(<(('w' / 'W') ('a' / 'A') ('i' / 'I') ('t' / 'T'))> Action98)> */
+		/* 205 Int <- <(<(('i' / 'I') ('n' / 'N') ('t' / 'T'))> Action167)> */
 		func() bool {
-			position1541, tokenIndex1541 := position, tokenIndex
+			position2978, tokenIndex2978 := position, tokenIndex
 			{
-				position1542 := position
+				position2979 := position
 				{
-					position1543 := position
-					{
-						position1544, tokenIndex1544 := position, tokenIndex
-						if buffer[position] != rune('w') {
-							goto l1545
-						}
-						position++
-						goto l1544
-					l1545:
-						position, tokenIndex = position1544, tokenIndex1544
-						if buffer[position] != rune('W') {
-							goto l1541
-						}
-						position++
-					}
-				l1544:
+					position2980 := position
 					{
-						position1546, tokenIndex1546 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1547
+						position2981, tokenIndex2981 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l2982
 						}
 						position++
-						goto l1546
-					l1547:
-						position, tokenIndex = position1546, tokenIndex1546
-						if buffer[position] != rune('A') {
-							goto l1541
+						goto l2981
+					l2982:
+						position, tokenIndex = position2981, tokenIndex2981
+						if buffer[position] != rune('I') {
+							goto l2978
 						}
 						position++
 					}
-				l1546:
+				l2981:
 					{
-						position1548, tokenIndex1548 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1549
+						position2983, tokenIndex2983 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l2984
 						}
 						position++
-						goto l1548
-					l1549:
-						position, tokenIndex = position1548, tokenIndex1548
-						if buffer[position] != rune('I') {
-							goto l1541
+						goto l2983
+					l2984:
+						position, tokenIndex = position2983, tokenIndex2983
+						if buffer[position] != rune('N') {
+							goto l2978
 						}
 						position++
 					}
-				l1548:
+				l2983:
 					{
-						position1550, tokenIndex1550 := position, tokenIndex
+						position2985, tokenIndex2985 := position, tokenIndex
 						if buffer[position] != rune('t') {
-							goto l1551
+							goto l2986
 						}
 						position++
-						goto l1550
-					l1551:
-						position, tokenIndex = position1550, tokenIndex1550
+						goto l2985
+					l2986:
+						position, tokenIndex = position2985, tokenIndex2985
 						if buffer[position] != rune('T') {
-							goto l1541
+							goto l2978
 						}
 						position++
 					}
-				l1550:
-					add(rulePegText, position1543)
+				l2985:
+					add(rulePegText, position2980)
 				}
-				if !_rules[ruleAction98]() {
-					goto l1541
+				if !_rules[ruleAction167]() {
+					goto l2978
 				}
-				add(ruleWait, position1542)
+				add(ruleInt, position2979)
 			}
 			return true
-		l1541:
-			position, tokenIndex = position1541, tokenIndex1541
+		l2978:
+			position, tokenIndex = position2978, tokenIndex2978
 			return false
 		},
-		/* 129 DropOldest <- <(<(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('o' / 'O') ('l' / 'L') ('d' / 'D') ('e' / 'E') ('s' / 'S') ('t' / 'T')))> Action99)> */
+		/* 206 Float <- <(<(('f' / 'F') ('l' / 'L') ('o' / 'O') ('a' / 'A') ('t' / 'T'))> Action168)> */
 		func() bool {
-			position1552, tokenIndex1552 := position, tokenIndex
+			position2987, tokenIndex2987 := position, tokenIndex
 			{
-				position1553 := position
+				position2988 := position
 				{
-					position1554 := position
+					position2989 := position
 					{
-						position1555, tokenIndex1555 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1556
+						position2990, tokenIndex2990 := position, tokenIndex
+						if buffer[position] != rune('f') {
+							goto l2991
 						}
 						position++
-						goto l1555
-					l1556:
-						position, tokenIndex = position1555, tokenIndex1555
-						if buffer[position] != rune('D') {
-							goto l1552
+						goto l2990
+					l2991:
+						position, tokenIndex = position2990, tokenIndex2990
+						if buffer[position] != rune('F') {
+							goto l2987
 						}
 						position++
 					}
-				l1555:
+				l2990:
 					{
-						position1557, tokenIndex1557 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1558
+						position2992, tokenIndex2992 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l2993
 						}
 						position++
-						goto l1557
-					l1558:
-						position, tokenIndex = position1557, tokenIndex1557
-						if buffer[position] != rune('R') {
-							goto l1552
+						goto l2992
+					l2993:
+						position, tokenIndex = position2992, tokenIndex2992
+						if buffer[position] != rune('L') {
+							goto l2987
 						}
 						position++
 					}
-				l1557:
+				l2992:
 					{
-						position1559, tokenIndex1559 := position, tokenIndex
+						position2994, tokenIndex2994 := position, tokenIndex
 						if buffer[position] != rune('o') {
-							goto l1560
+							goto l2995
 						}
 						position++
-						goto l1559
-					l1560:
-						position, tokenIndex = position1559, tokenIndex1559
+						goto l2994
+					l2995:
+						position, tokenIndex = position2994, tokenIndex2994
 						if buffer[position] != rune('O') {
-							goto l1552
+							goto l2987
 						}
 						position++
 					}
-				l1559:
+				l2994:
 					{
-						position1561, tokenIndex1561 := position, tokenIndex
-						if buffer[position] != rune('p') {
-							goto l1562
+						position2996, tokenIndex2996 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l2997
 						}
 						position++
-						goto l1561
-					l1562:
-						position, tokenIndex = position1561, tokenIndex1561
-						if buffer[position] != rune('P') {
-							goto l1552
+						goto l2996
+					l2997:
+						position, tokenIndex = position2996, tokenIndex2996
+						if buffer[position] != rune('A') {
+							goto l2987
 						}
 						position++
 					}
-				l1561:
-					if !_rules[rulesp]() {
-						goto l1552
+				l2996:
+					{
+						position2998, tokenIndex2998 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l2999
+						}
+						position++
+						goto l2998
+					l2999:
+						position, tokenIndex = position2998, tokenIndex2998
+						if buffer[position] != rune('T') {
+							goto l2987
+						}
+						position++
 					}
+				l2998:
+					add(rulePegText, position2989)
+				}
+				if !_rules[ruleAction168]() {
+					goto l2987
+				}
+				add(ruleFloat, position2988)
+			}
+			return true
+		l2987:
+			position, tokenIndex = position2987, tokenIndex2987
+			return false
+		},
+		/* 207 String <- <(<(('s' / 'S') ('t' / 'T') ('r' / 'R') ('i' / 'I') ('n' / 'N') ('g' / 'G'))> Action169)> */
+		func() bool {
+			position3000, tokenIndex3000 := position, tokenIndex
+			{
+				position3001 := position
+				{
+					position3002 := position
 					{
-						position1563, tokenIndex1563 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1564
+						position3003, tokenIndex3003 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l3004
 						}
 						position++
-						goto l1563
-					l1564:
-						position, tokenIndex = position1563, tokenIndex1563
-						if buffer[position] != rune('O') {
-							goto l1552
+						goto l3003
+					l3004:
+						position, tokenIndex = position3003, tokenIndex3003
+						if buffer[position] != rune('S') {
+							goto l3000
 						}
 						position++
 					}
-				l1563:
+				l3003:
 					{
-						position1565, tokenIndex1565 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1566
+						position3005, tokenIndex3005 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l3006
 						}
 						position++
-						goto l1565
-					l1566:
-						position, tokenIndex = position1565, tokenIndex1565
-						if buffer[position] != rune('L') {
-							goto l1552
+						goto l3005
+					l3006:
+						position, tokenIndex = position3005, tokenIndex3005
+						if buffer[position] != rune('T') {
+							goto l3000
 						}
 						position++
 					}
-				l1565:
+				l3005:
 					{
-						position1567, tokenIndex1567 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1568
+						position3007, tokenIndex3007 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l3008
 						}
 						position++
-						goto l1567
-					l1568:
-						position, tokenIndex = position1567, tokenIndex1567
-						if buffer[position] != rune('D') {
-							goto l1552
+						goto l3007
+					l3008:
+						position, tokenIndex = position3007, tokenIndex3007
+						if buffer[position] != rune('R') {
+							goto l3000
 						}
 						position++
 					}
-				l1567:
+				l3007:
 					{
-						position1569, tokenIndex1569 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1570
+						position3009, tokenIndex3009 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l3010
 						}
 						position++
-						goto l1569
-					l1570:
-						position, tokenIndex = position1569, tokenIndex1569
-						if buffer[position] != rune('E') {
-							goto l1552
+						goto l3009
+					l3010:
+						position, tokenIndex = position3009, tokenIndex3009
+						if buffer[position] != rune('I') {
+							goto l3000
 						}
 						position++
 					}
-				l1569:
+				l3009:
 					{
-						position1571, tokenIndex1571 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1572
+						position3011, tokenIndex3011 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l3012
 						}
 						position++
-						goto l1571
-					l1572:
-						position, tokenIndex = position1571, tokenIndex1571
-						if buffer[position] != rune('S') {
-							goto l1552
+						goto l3011
+					l3012:
+						position, tokenIndex = position3011, tokenIndex3011
+						if buffer[position] != rune('N') {
+							goto l3000
 						}
 						position++
 					}
-				l1571:
+				l3011:
 					{
-						position1573, tokenIndex1573 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1574
+						position3013, tokenIndex3013 := position, tokenIndex
+						if buffer[position] != rune('g') {
+							goto l3014
 						}
 						position++
-						goto l1573
-					l1574:
-						position, tokenIndex = position1573, tokenIndex1573
-						if buffer[position] != rune('T') {
-							goto l1552
+						goto l3013
+					l3014:
+						position, tokenIndex = position3013, tokenIndex3013
+						if buffer[position] != rune('G') {
+							goto l3000
 						}
 						position++
 					}
-				l1573:
-					add(rulePegText, position1554)
+				l3013:
+					add(rulePegText, position3002)
 				}
-				if !_rules[ruleAction99]() {
-					goto l1552
+				if !_rules[ruleAction169]() {
+					goto l3000
 				}
-				add(ruleDropOldest, position1553)
+				add(ruleString, position3001)
 			}
 			return true
-		l1552:
-			position, tokenIndex = position1552, tokenIndex1552
+		l3000:
+			position, tokenIndex = position3000, tokenIndex3000
 			return false
 		},
-		/* 130 DropNewest <- <(<(('d' / 'D') ('r' / 'R') ('o' / 'O') ('p' / 'P') sp (('n' / 'N') ('e' / 'E') ('w' / 'W') ('e' / 'E') ('s' / 'S') ('t' / 'T')))> Action100)> */
+		/* 208 Blob <- <(<(('b' / 'B') ('l' / 'L') ('o' / 'O') ('b' / 'B'))> Action170)> */
 		func() bool {
-			position1575, tokenIndex1575 := position, tokenIndex
+			position3015, tokenIndex3015 := position, tokenIndex
 			{
-				position1576 := position
+				position3016 := position
 				{
-					position1577 := position
+					position3017 := position
 					{
-						position1578, tokenIndex1578 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1579
+						position3018, tokenIndex3018 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l3019
 						}
 						position++
-						goto l1578
-					l1579:
-						position, tokenIndex = position1578, tokenIndex1578
-						if buffer[position] != rune('D') {
-							goto l1575
+						goto l3018
+					l3019:
+						position, tokenIndex = position3018, tokenIndex3018
+						if buffer[position] != rune('B') {
+							goto l3015
 						}
 						position++
 					}
-				l1578:
+				l3018:
 					{
-						position1580, tokenIndex1580 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1581
+						position3020, tokenIndex3020 := position, tokenIndex
+						if buffer[position] != rune('l') {
+							goto l3021
 						}
 						position++
-						goto l1580
-					l1581:
-						position, tokenIndex = position1580, tokenIndex1580
-						if buffer[position] != rune('R') {
-							goto l1575
+						goto l3020
+					l3021:
+						position, tokenIndex = position3020, tokenIndex3020
+						if buffer[position] != rune('L') {
+							goto l3015
 						}
 						position++
 					}
-				l1580:
+				l3020:
 					{
-						position1582, tokenIndex1582 := position, tokenIndex
+						position3022, tokenIndex3022 := position, tokenIndex
 						if buffer[position] != rune('o') {
-							goto l1583
+							goto l3023
 						}
 						position++
-						goto l1582
-					l1583:
-						position, tokenIndex = position1582, tokenIndex1582
+						goto l3022
+					l3023:
+						position, tokenIndex = position3022, tokenIndex3022
 						if buffer[position] != rune('O') {
-							goto l1575
+							goto l3015
 						}
 						position++
 					}
-				l1582:
+				l3022:
 					{
-						position1584, tokenIndex1584 := position, tokenIndex
-						if buffer[position] != rune('p') {
-							goto l1585
+						position3024, tokenIndex3024 := position, tokenIndex
+						if buffer[position] != rune('b') {
+							goto l3025
 						}
 						position++
-						goto l1584
-					l1585:
-						position, tokenIndex = position1584, tokenIndex1584
-						if buffer[position] != rune('P') {
-							goto l1575
+						goto l3024
+					l3025:
+						position, tokenIndex = position3024, tokenIndex3024
+						if buffer[position] != rune('B') {
+							goto l3015
 						}
 						position++
 					}
-				l1584:
-					if !_rules[rulesp]() {
-						goto l1575
-					}
+				l3024:
+					add(rulePegText, position3017)
+				}
+				if !_rules[ruleAction170]() {
+					goto l3015
+				}
+				add(ruleBlob, position3016)
+			}
+			return true
+		l3015:
+			position, tokenIndex = position3015, tokenIndex3015
+			return false
+		},
+		/* 209 Timestamp <- <(<(('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('m' / 'M') ('p' / 'P'))> Action171)> */
+		func() bool {
+			position3026, tokenIndex3026 := position, tokenIndex
+			{
+				position3027 := position
+				{
+					position3028 := position
 					{
-						position1586, tokenIndex1586 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1587
+						position3029, tokenIndex3029 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l3030
 						}
 						position++
-						goto l1586
-					l1587:
-						position, tokenIndex = position1586, tokenIndex1586
-						if buffer[position] != rune('N') {
-							goto l1575
+						goto l3029
+					l3030:
+						position, tokenIndex = position3029, tokenIndex3029
+						if buffer[position] != rune('T') {
+							goto l3026
 						}
 						position++
 					}
-				l1586:
+				l3029:
 					{
-						position1588, tokenIndex1588 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1589
+						position3031, tokenIndex3031 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l3032
 						}
 						position++
-						goto l1588
-					l1589:
-						position, tokenIndex = position1588, tokenIndex1588
-						if buffer[position] != rune('E') {
-							goto l1575
+						goto l3031
+					l3032:
+						position, tokenIndex = position3031, tokenIndex3031
+						if buffer[position] != rune('I') {
+							goto l3026
 						}
 						position++
 					}
-				l1588:
+				l3031:
 					{
-						position1590, tokenIndex1590 := position, tokenIndex
-						if buffer[position] != rune('w') {
-							goto l1591
+						position3033, tokenIndex3033 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l3034
 						}
 						position++
-						goto l1590
-					l1591:
-						position, tokenIndex = position1590, tokenIndex1590
-						if buffer[position] != rune('W') {
-							goto l1575
+						goto l3033
+					l3034:
+						position, tokenIndex = position3033, tokenIndex3033
+						if buffer[position] != rune('M') {
+							goto l3026
 						}
 						position++
 					}
-				l1590:
+				l3033:
 					{
-						position1592, tokenIndex1592 := position, tokenIndex
+						position3035, tokenIndex3035 := position, tokenIndex
 						if buffer[position] != rune('e') {
-							goto l1593
+							goto l3036
 						}
 						position++
-						goto l1592
-					l1593:
-						position, tokenIndex = position1592, tokenIndex1592
+						goto l3035
+					l3036:
+						position, tokenIndex = position3035, tokenIndex3035
 						if buffer[position] != rune('E') {
-							goto l1575
+							goto l3026
 						}
 						position++
 					}
-				l1592:
+				l3035:
 					{
-						position1594, tokenIndex1594 := position, tokenIndex
+						position3037, tokenIndex3037 := position, tokenIndex
 						if buffer[position] != rune('s') {
-							goto l1595
+							goto l3038
 						}
 						position++
-						goto l1594
-					l1595:
-						position, tokenIndex = position1594, tokenIndex1594
+						goto l3037
+					l3038:
+						position, tokenIndex = position3037, tokenIndex3037
 						if buffer[position] != rune('S') {
-							goto l1575
+							goto l3026
 						}
 						position++
 					}
-				l1594:
+				l3037:
 					{
-						position1596, tokenIndex1596 := position, tokenIndex
+						position3039, tokenIndex3039 := position, tokenIndex
 						if buffer[position] != rune('t') {
-							goto l1597
+							goto l3040
 						}
 						position++
-						goto l1596
-					l1597:
-						position, tokenIndex = position1596, tokenIndex1596
+						goto l3039
+					l3040:
+						position, tokenIndex = position3039, tokenIndex3039
 						if buffer[position] != rune('T') {
-							goto l1575
+							goto l3026
 						}
 						position++
 					}
-				l1596:
-					add(rulePegText, position1577)
-				}
-				if !_rules[ruleAction100]() {
-					goto l1575
-				}
-				add(ruleDropNewest, position1576)
-			}
-			return true
-		l1575:
-			position, tokenIndex = position1575, tokenIndex1575
-			return false
-		},
-		/* 131 StreamIdentifier <- <(<ident> Action101)> */
-		func() bool {
-			position1598, tokenIndex1598 := position, tokenIndex
-			{
-				position1599 := position
-				{
-					position1600 := position
-					if !_rules[ruleident]() {
-						goto l1598
+				l3039:
+					{
+						position3041, tokenIndex3041 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l3042
+						}
+						position++
+						goto l3041
+					l3042:
+						position, tokenIndex = position3041, tokenIndex3041
+						if buffer[position] != rune('A') {
+							goto l3026
+						}
+						position++
 					}
-					add(rulePegText, position1600)
-				}
-				if !_rules[ruleAction101]() {
-					goto l1598
-				}
-				add(ruleStreamIdentifier, position1599)
-			}
-			return true
-		l1598:
-			position, tokenIndex = position1598, tokenIndex1598
-			return false
-		},
-		/* 132 SourceSinkType <- <(<ident> Action102)> */
-		func() bool {
-			position1601, tokenIndex1601 := position, tokenIndex
-			{
-				position1602 := position
-				{
-					position1603 := position
-					if !_rules[ruleident]() {
-						goto l1601
+				l3041:
+					{
+						position3043, tokenIndex3043 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l3044
+						}
+						position++
+						goto l3043
+					l3044:
+						position, tokenIndex = position3043, tokenIndex3043
+						if buffer[position] != rune('M') {
+							goto l3026
+						}
+						position++
 					}
-					add(rulePegText, position1603)
-				}
-				if !_rules[ruleAction102]() {
-					goto l1601
-				}
-				add(ruleSourceSinkType, position1602)
-			}
-			return true
-		l1601:
-			position, tokenIndex = position1601, tokenIndex1601
-			return false
-		},
-		/* 133 SourceSinkParamKey <- <(<ident> Action103)> */
-		func() bool {
-			position1604, tokenIndex1604 := position, tokenIndex
-			{
-				position1605 := position
-				{
-					position1606 := position
-					if !_rules[ruleident]() {
-						goto l1604
+				l3043:
+					{
+						position3045, tokenIndex3045 := position, tokenIndex
+						if buffer[position] != rune('p') {
+							goto l3046
+						}
+						position++
+						goto l3045
+					l3046:
+						position, tokenIndex = position3045, tokenIndex3045
+						if buffer[position] != rune('P') {
+							goto l3026
+						}
+						position++
 					}
-					add(rulePegText, position1606)
+				l3045:
+					add(rulePegText, position3028)
 				}
-				if !_rules[ruleAction103]() {
-					goto l1604
+				if !_rules[ruleAction171]() {
+					goto l3026
 				}
-				add(ruleSourceSinkParamKey, position1605)
+				add(ruleTimestamp, position3027)
 			}
 			return true
-		l1604:
-			position, tokenIndex = position1604, tokenIndex1604
+		l3026:
+			position, tokenIndex = position3026, tokenIndex3026
 			return false
 		},
-		/* 134 Paused <- <(<(('p' / 'P') ('a' / 'A') ('u' / 'U') ('s' / 'S') ('e' / 'E') ('d' / 'D'))> Action104)> */
+		/* 210 Array <- <(<(('a' / 'A') ('r' / 'R') ('r' / 'R') ('a' / 'A') ('y' / 'Y'))> Action172)> */
 		func() bool {
-			position1607, tokenIndex1607 := position, tokenIndex
+			position3047, tokenIndex3047 := position, tokenIndex
 			{
-				position1608 := position
+				position3048 := position
 				{
-					position1609 := position
-					{
-						position1610, tokenIndex1610 := position, tokenIndex
-						if buffer[position] != rune('p') {
-							goto l1611
-						}
-						position++
-						goto l1610
-					l1611:
-						position, tokenIndex = position1610, tokenIndex1610
-						if buffer[position] != rune('P') {
-							goto l1607
-						}
-						position++
-					}
-				l1610:
+					position3049 := position
 					{
-						position1612, tokenIndex1612 := position, tokenIndex
+						position3050, tokenIndex3050 := position, tokenIndex
 						if buffer[position] != rune('a') {
-							goto l1613
+							goto l3051
 						}
 						position++
-						goto l1612
-					l1613:
-						position, tokenIndex = position1612, tokenIndex1612
+						goto l3050
+					l3051:
+						position, tokenIndex = position3050, tokenIndex3050
 						if buffer[position] != rune('A') {
-							goto l1607
+							goto l3047
 						}
 						position++
 					}
-				l1612:
+				l3050:
 					{
-						position1614, tokenIndex1614 := position, tokenIndex
-						if buffer[position] != rune('u') {
-							goto l1615
+						position3052, tokenIndex3052 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l3053
 						}
 						position++
-						goto l1614
-					l1615:
-						position, tokenIndex = position1614, tokenIndex1614
-						if buffer[position] != rune('U') {
-							goto l1607
+						goto l3052
+					l3053:
+						position, tokenIndex = position3052, tokenIndex3052
+						if buffer[position] != rune('R') {
+							goto l3047
 						}
 						position++
 					}
-				l1614:
+				l3052:
 					{
-						position1616, tokenIndex1616 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1617
+						position3054, tokenIndex3054 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l3055
 						}
 						position++
-						goto l1616
-					l1617:
-						position, tokenIndex = position1616, tokenIndex1616
-						if buffer[position] != rune('S') {
-							goto l1607
+						goto l3054
+					l3055:
+						position, tokenIndex = position3054, tokenIndex3054
+						if buffer[position] != rune('R') {
+							goto l3047
 						}
 						position++
 					}
-				l1616:
+				l3054:
 					{
-						position1618, tokenIndex1618 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1619
+						position3056, tokenIndex3056 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l3057
 						}
 						position++
-						goto l1618
-					l1619:
-						position, tokenIndex = position1618, tokenIndex1618
-						if buffer[position] != rune('E') {
-							goto l1607
+						goto l3056
+					l3057:
+						position, tokenIndex = position3056, tokenIndex3056
+						if buffer[position] != rune('A') {
+							goto l3047
 						}
 						position++
 					}
-				l1618:
+				l3056:
 					{
-						position1620, tokenIndex1620 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1621
+						position3058, tokenIndex3058 := position, tokenIndex
+						if buffer[position] != rune('y') {
+							goto l3059
 						}
 						position++
-						goto l1620
-					l1621:
-						position, tokenIndex = position1620, tokenIndex1620
-						if buffer[position] != rune('D') {
-							goto l1607
+						goto l3058
+					l3059:
+						position, tokenIndex = position3058, tokenIndex3058
+						if buffer[position] != rune('Y') {
+							goto l3047
 						}
 						position++
 					}
-				l1620:
-					add(rulePegText, position1609)
+				l3058:
+					add(rulePegText, position3049)
 				}
-				if !_rules[ruleAction104]() {
-					goto l1607
+				if !_rules[ruleAction172]() {
+					goto l3047
 				}
-				add(rulePaused, position1608)
+				add(ruleArray, position3048)
 			}
 			return true
-		l1607:
-			position, tokenIndex = position1607, tokenIndex1607
+		l3047:
+			position, tokenIndex = position3047, tokenIndex3047
 			return false
 		},
-		/* 135 Unpaused <- <(<(('u' / 'U') ('n' / 'N') ('p' / 'P') ('a' / 'A') ('u' / 'U') ('s' / 'S') ('e' / 'E') ('d' / 'D'))> Action105)> */
+		/* 211 Map <- <(<(('m' / 'M') ('a' / 'A') ('p' / 'P'))> Action173)> */
 		func() bool {
-			position1622, tokenIndex1622 := position, tokenIndex
+			position3060, tokenIndex3060 := position, tokenIndex
 			{
-				position1623 := position
+				position3061 := position
 				{
-					position1624 := position
+					position3062 := position
 					{
-						position1625, tokenIndex1625 := position, tokenIndex
-						if buffer[position] != rune('u') {
-							goto l1626
+						position3063, tokenIndex3063 := position, tokenIndex
+						if buffer[position] != rune('m') {
+							goto l3064
 						}
 						position++
-						goto l1625
-					l1626:
-						position, tokenIndex = position1625, tokenIndex1625
-						if buffer[position] != rune('U') {
-							goto l1622
+						goto l3063
+					l3064:
+						position, tokenIndex = position3063, tokenIndex3063
+						if buffer[position] != rune('M') {
+							goto l3060
 						}
 						position++
 					}
-				l1625:
+				l3063:
 					{
-						position1627, tokenIndex1627 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1628
+						position3065, tokenIndex3065 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l3066
 						}
 						position++
-						goto l1627
-					l1628:
-						position, tokenIndex = position1627, tokenIndex1627
-						if buffer[position] != rune('N') {
-							goto l1622
+						goto l3065
+					l3066:
+						position, tokenIndex = position3065, tokenIndex3065
+						if buffer[position] != rune('A') {
+							goto l3060
 						}
 						position++
 					}
-				l1627:
+				l3065:
 					{
-						position1629, tokenIndex1629 := position, tokenIndex
+						position3067, tokenIndex3067 := position, tokenIndex
 						if buffer[position] != rune('p') {
-							goto l1630
+							goto l3068
 						}
 						position++
-						goto l1629
-					l1630:
-						position, tokenIndex = position1629, tokenIndex1629
+						goto l3067
+					l3068:
+						position, tokenIndex = position3067, tokenIndex3067
 						if buffer[position] != rune('P') {
-							goto l1622
+							goto l3060
 						}
 						position++
 					}
-				l1629:
+				l3067:
+					add(rulePegText, position3062)
+				}
+				if !_rules[ruleAction173]() {
+					goto l3060
+				}
+				add(ruleMap, position3061)
+			}
+			return true
+		l3060:
+			position, tokenIndex = position3060, tokenIndex3060
+			return false
+		},
+		/* 212 Or <- <(<(('o' / 'O') ('r' / 'R'))> Action174)> */
+		func() bool {
+			position3069, tokenIndex3069 := position, tokenIndex
+			{
+				position3070 := position
+				{
+					position3071 := position
 					{
-						position1631, tokenIndex1631 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1632
+						position3072, tokenIndex3072 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l3073
 						}
 						position++
-						goto l1631
-					l1632:
-						position, tokenIndex = position1631, tokenIndex1631
-						if buffer[position] != rune('A') {
-							goto l1622
+						goto l3072
+					l3073:
+						position, tokenIndex = position3072, tokenIndex3072
+						if buffer[position] != rune('O') {
+							goto l3069
 						}
 						position++
 					}
-				l1631:
+				l3072:
 					{
-						position1633, tokenIndex1633 := position, tokenIndex
-						if buffer[position] != rune('u') {
-							goto l1634
+						position3074, tokenIndex3074 := position, tokenIndex
+						if buffer[position] != rune('r') {
+							goto l3075
 						}
 						position++
-						goto l1633
-					l1634:
-						position, tokenIndex = position1633, tokenIndex1633
-						if buffer[position] != rune('U') {
-							goto l1622
+						goto l3074
+					l3075:
+						position, tokenIndex = position3074, tokenIndex3074
+						if buffer[position] != rune('R') {
+							goto l3069
 						}
 						position++
 					}
-				l1633:
+				l3074:
+					add(rulePegText, position3071)
+				}
+				if !_rules[ruleAction174]() {
+					goto l3069
+				}
+				add(ruleOr, position3070)
+			}
+			return true
+		l3069:
+			position, tokenIndex = position3069, tokenIndex3069
+			return false
+		},
+		/* 213 And <- <(<(('a' / 'A') ('n' / 'N') ('d' / 'D'))> Action175)> */
+		func() bool {
+			position3076, tokenIndex3076 := position, tokenIndex
+			{
+				position3077 := position
+				{
+					position3078 := position
 					{
-						position1635, tokenIndex1635 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1636
+						position3079, tokenIndex3079 := position, tokenIndex
+						if buffer[position] != rune('a') {
+							goto l3080
 						}
 						position++
-						goto l1635
-					l1636:
-						position, tokenIndex = position1635, tokenIndex1635
-						if buffer[position] != rune('S') {
-							goto l1622
+						goto l3079
+					l3080:
+						position, tokenIndex = position3079, tokenIndex3079
+						if buffer[position] != rune('A') {
+							goto l3076
 						}
 						position++
 					}
-				l1635:
+				l3079:
 					{
-						position1637, tokenIndex1637 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1638
+						position3081, tokenIndex3081 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l3082
 						}
 						position++
-						goto l1637
-					l1638:
-						position, tokenIndex = position1637, tokenIndex1637
-						if buffer[position] != rune('E') {
-							goto l1622
+						goto l3081
+					l3082:
+						position, tokenIndex = position3081, tokenIndex3081
+						if buffer[position] != rune('N') {
+							goto l3076
 						}
 						position++
 					}
-				l1637:
+				l3081:
 					{
-						position1639, tokenIndex1639 := position, tokenIndex
+						position3083, tokenIndex3083 := position, tokenIndex
 						if buffer[position] != rune('d') {
-							goto l1640
+							goto l3084
 						}
 						position++
-						goto l1639
-					l1640:
-						position, tokenIndex = position1639, tokenIndex1639
+						goto l3083
+					l3084:
+						position, tokenIndex = position3083, tokenIndex3083
 						if buffer[position] != rune('D') {
-							goto l1622
+							goto l3076
 						}
 						position++
 					}
-				l1639:
-					add(rulePegText, position1624)
+				l3083:
+					add(rulePegText, position3078)
 				}
-				if !_rules[ruleAction105]() {
-					goto l1622
+				if !_rules[ruleAction175]() {
+					goto l3076
 				}
-				add(ruleUnpaused, position1623)
+				add(ruleAnd, position3077)
 			}
 			return true
-		l1622:
-			position, tokenIndex = position1622, tokenIndex1622
+		l3076:
+			position, tokenIndex = position3076, tokenIndex3076
 			return false
 		},
-		/* 136 Ascending <- <(<(('a' / 'A') ('s' / 'S') ('c' / 'C'))> Action106)> */
+		/* 214 Not <- <(<(('n' / 'N') ('o' / 'O') ('t' / 'T'))> Action176)> */
 		func() bool {
-			position1641, tokenIndex1641 := position, tokenIndex
+			position3085, tokenIndex3085 := position, tokenIndex
 			{
-				position1642 := position
+				position3086 := position
 				{
-					position1643 := position
+					position3087 := position
 					{
-						position1644, tokenIndex1644 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1645
+						position3088, tokenIndex3088 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l3089
 						}
 						position++
-						goto l1644
-					l1645:
-						position, tokenIndex = position1644, tokenIndex1644
-						if buffer[position] != rune('A') {
-							goto l1641
+						goto l3088
+					l3089:
+						position, tokenIndex = position3088, tokenIndex3088
+						if buffer[position] != rune('N') {
+							goto l3085
 						}
 						position++
 					}
-				l1644:
+				l3088:
 					{
-						position1646, tokenIndex1646 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1647
+						position3090, tokenIndex3090 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l3091
 						}
 						position++
-						goto l1646
-					l1647:
-						position, tokenIndex = position1646, tokenIndex1646
-						if buffer[position] != rune('S') {
-							goto l1641
+						goto l3090
+					l3091:
+						position, tokenIndex = position3090, tokenIndex3090
+						if buffer[position] != rune('O') {
+							goto l3085
 						}
 						position++
 					}
-				l1646:
+				l3090:
 					{
-						position1648, tokenIndex1648 := position, tokenIndex
-						if buffer[position] != rune('c') {
-							goto l1649
+						position3092, tokenIndex3092 := position, tokenIndex
+						if buffer[position] != rune('t') {
+							goto l3093
 						}
 						position++
-						goto l1648
-					l1649:
-						position, tokenIndex = position1648, tokenIndex1648
-						if buffer[position] != rune('C') {
-							goto l1641
+						goto l3092
+					l3093:
+						position, tokenIndex = position3092, tokenIndex3092
+						if buffer[position] != rune('T') {
+							goto l3085
 						}
 						position++
 					}
-				l1648:
-					add(rulePegText, position1643)
+				l3092:
+					add(rulePegText, position3087)
 				}
-				if !_rules[ruleAction106]() {
-					goto l1641
+				if !_rules[ruleAction176]() {
+					goto l3085
 				}
-				add(ruleAscending, position1642)
+				add(ruleNot, position3086)
 			}
 			return true
-		l1641:
-			position, tokenIndex = position1641, tokenIndex1641
+		l3085:
+			position, tokenIndex = position3085, tokenIndex3085
 			return false
 		},
-		/* 137 Descending <- <(<(('d' / 'D') ('e' / 'E') ('s' / 'S') ('c' / 'C'))> Action107)> */
+		/* 215 Equal <- <(<'='> Action177)> */
 		func() bool {
-			position1650, tokenIndex1650 := position, tokenIndex
+			position3094, tokenIndex3094 := position, tokenIndex
 			{
-				position1651 := position
+				position3095 := position
 				{
-					position1652 := position
-					{
-						position1653, tokenIndex1653 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1654
-						}
-						position++
-						goto l1653
-					l1654:
-						position, tokenIndex = position1653, tokenIndex1653
-						if buffer[position] != rune('D') {
-							goto l1650
-						}
-						position++
+					position3096 := position
+					if buffer[position] != rune('=') {
+						goto l3094
 					}
-				l1653:
-					{
-						position1655, tokenIndex1655 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1656
-						}
-						position++
-						goto l1655
-					l1656:
-						position, tokenIndex = position1655, tokenIndex1655
-						if buffer[position] != rune('E') {
-							goto l1650
-						}
-						position++
+					position++
+					add(rulePegText, position3096)
+				}
+				if !_rules[ruleAction177]() {
+					goto l3094
+				}
+				add(ruleEqual, position3095)
+			}
+			return true
+		l3094:
+			position, tokenIndex = position3094, tokenIndex3094
+			return false
+		},
+		/* 216 Less <- <(<'<'> Action178)> */
+		func() bool {
+			position3097, tokenIndex3097 := position, tokenIndex
+			{
+				position3098 := position
+				{
+					position3099 := position
+					if buffer[position] != rune('<') {
+						goto l3097
 					}
-				l1655:
-					{
-						position1657, tokenIndex1657 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1658
-						}
-						position++
-						goto l1657
-					l1658:
-						position, tokenIndex = position1657, tokenIndex1657
-						if buffer[position] != rune('S') {
-							goto l1650
-						}
-						position++
+					position++
+					add(rulePegText, position3099)
+				}
+				if !_rules[ruleAction178]() {
+					goto l3097
+				}
+				add(ruleLess, position3098)
+			}
+			return true
+		l3097:
+			position, tokenIndex = position3097, tokenIndex3097
+			return false
+		},
+		/* 217 LessOrEqual <- <(<('<' '=')> Action179)> */
+		func() bool {
+			position3100, tokenIndex3100 := position, tokenIndex
+			{
+				position3101 := position
+				{
+					position3102 := position
+					if buffer[position] != rune('<') {
+						goto l3100
 					}
-				l1657:
-					{
-						position1659, tokenIndex1659 := position, tokenIndex
-						if buffer[position] != rune('c') {
-							goto l1660
-						}
-						position++
-						goto l1659
-					l1660:
-						position, tokenIndex = position1659, tokenIndex1659
-						if buffer[position] != rune('C') {
-							goto l1650
-						}
-						position++
+					position++
+					if buffer[position] != rune('=') {
+						goto l3100
+					}
+					position++
+					add(rulePegText, position3102)
+				}
+				if !_rules[ruleAction179]() {
+					goto l3100
+				}
+				add(ruleLessOrEqual, position3101)
+			}
+			return true
+		l3100:
+			position, tokenIndex = position3100, tokenIndex3100
+			return false
+		},
+		/* 218 Greater <- <(<'>'> Action180)> */
+		func() bool {
+			position3103, tokenIndex3103 := position, tokenIndex
+			{
+				position3104 := position
+				{
+					position3105 := position
+					if buffer[position] != rune('>') {
+						goto l3103
 					}
-				l1659:
-					add(rulePegText, position1652)
+					position++
+					add(rulePegText, position3105)
 				}
-				if !_rules[ruleAction107]() {
-					goto l1650
+				if !_rules[ruleAction180]() {
+					goto l3103
 				}
-				add(ruleDescending, position1651)
+				add(ruleGreater, position3104)
 			}
 			return true
-		l1650:
-			position, tokenIndex = position1650, tokenIndex1650
+		l3103:
+			position, tokenIndex = position3103, tokenIndex3103
 			return false
 		},
-		/* 138 Type <- <(Bool / Int / Float / String / Blob / Timestamp / Array / Map)> */
+		/* 219 GreaterOrEqual <- <(<('>' '=')> Action181)> */
 		func() bool {
-			position1661, tokenIndex1661 := position, tokenIndex
+			position3106, tokenIndex3106 := position, tokenIndex
 			{
-				position1662 := position
+				position3107 := position
 				{
-					position1663, tokenIndex1663 := position, tokenIndex
-					if !_rules[ruleBool]() {
-						goto l1664
-					}
-					goto l1663
-				l1664:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleInt]() {
-						goto l1665
-					}
-					goto l1663
-				l1665:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleFloat]() {
-						goto l1666
-					}
-					goto l1663
-				l1666:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleString]() {
-						goto l1667
-					}
-					goto l1663
-				l1667:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleBlob]() {
-						goto l1668
-					}
-					goto l1663
-				l1668:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleTimestamp]() {
-						goto l1669
-					}
-					goto l1663
-				l1669:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleArray]() {
-						goto l1670
+					position3108 := position
+					if buffer[position] != rune('>') {
+						goto l3106
 					}
-					goto l1663
-				l1670:
-					position, tokenIndex = position1663, tokenIndex1663
-					if !_rules[ruleMap]() {
-						goto l1661
+					position++
+					if buffer[position] != rune('=') {
+						goto l3106
 					}
+					position++
+					add(rulePegText, position3108)
 				}
-			l1663:
-				add(ruleType, position1662)
+				if !_rules[ruleAction181]() {
+					goto l3106
+				}
+				add(ruleGreaterOrEqual, position3107)
 			}
 			return true
-		l1661:
-			position, tokenIndex = position1661, tokenIndex1661
+		l3106:
+			position, tokenIndex = position3106, tokenIndex3106
 			return false
 		},
-		/* 139 Bool <- <(<(('b' / 'B') ('o' / 'O') ('o' / 'O') ('l' / 'L'))> Action108)> */
+		/* 220 NotEqual <- <(<(('!' '=') / ('<' '>'))> Action182)> */
 		func() bool {
-			position1671, tokenIndex1671 := position, tokenIndex
+			position3109, tokenIndex3109 := position, tokenIndex
 			{
-				position1672 := position
+				position3110 := position
 				{
-					position1673 := position
+					position3111 := position
 					{
-						position1674, tokenIndex1674 := position, tokenIndex
-						if buffer[position] != rune('b') {
-							goto l1675
+						position3112, tokenIndex3112 := position, tokenIndex
+						if buffer[position] != rune('!') {
+							goto l3113
 						}
 						position++
-						goto l1674
-					l1675:
-						position, tokenIndex = position1674, tokenIndex1674
-						if buffer[position] != rune('B') {
-							goto l1671
+						if buffer[position] != rune('=') {
+							goto l3113
 						}
 						position++
-					}
-				l1674:
-					{
-						position1676, tokenIndex1676 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1677
+						goto l3112
+					l3113:
+						position, tokenIndex = position3112, tokenIndex3112
+						if buffer[position] != rune('<') {
+							goto l3109
 						}
 						position++
-						goto l1676
-					l1677:
-						position, tokenIndex = position1676, tokenIndex1676
-						if buffer[position] != rune('O') {
-							goto l1671
+						if buffer[position] != rune('>') {
+							goto l3109
 						}
 						position++
 					}
-				l1676:
-					{
-						position1678, tokenIndex1678 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1679
-						}
-						position++
-						goto l1678
-					l1679:
-						position, tokenIndex = position1678, tokenIndex1678
-						if buffer[position] != rune('O') {
-							goto l1671
-						}
-						position++
+				l3112:
+					add(rulePegText, position3111)
+				}
+				if !_rules[ruleAction182]() {
+					goto l3109
+				}
+				add(ruleNotEqual, position3110)
+			}
+			return true
+		l3109:
+			position, tokenIndex = position3109, tokenIndex3109
+			return false
+		},
+		/* 221 Concat <- <(<('|' '|')> Action183)> */
+		func() bool {
+			position3114, tokenIndex3114 := position, tokenIndex
+			{
+				position3115 := position
+				{
+					position3116 := position
+					if buffer[position] != rune('|') {
+						goto l3114
 					}
-				l1678:
-					{
-						position1680, tokenIndex1680 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1681
-						}
-						position++
-						goto l1680
-					l1681:
-						position, tokenIndex = position1680, tokenIndex1680
-						if buffer[position] != rune('L') {
-							goto l1671
-						}
-						position++
+					position++
+					if buffer[position] != rune('|') {
+						goto l3114
 					}
-				l1680:
-					add(rulePegText, position1673)
+					position++
+					add(rulePegText, position3116)
 				}
-				if !_rules[ruleAction108]() {
-					goto l1671
+				if !_rules[ruleAction183]() {
+					goto l3114
 				}
-				add(ruleBool, position1672)
+				add(ruleConcat, position3115)
 			}
 			return true
-		l1671:
-			position, tokenIndex = position1671, tokenIndex1671
+		l3114:
+			position, tokenIndex = position3114, tokenIndex3114
 			return false
 		},
-		/* 140 Int <- <(<(('i' / 'I') ('n' / 'N') ('t' / 'T'))> Action109)> */
+		/* 222 Is <- <(<(('i' / 'I') ('s' / 'S'))> Action184)> */
 		func() bool {
-			position1682, tokenIndex1682 := position, tokenIndex
+			position3117, tokenIndex3117 := position, tokenIndex
 			{
-				position1683 := position
+				position3118 := position
 				{
-					position1684 := position
+					position3119 := position
 					{
-						position1685, tokenIndex1685 := position, tokenIndex
+						position3120, tokenIndex3120 := position, tokenIndex
 						if buffer[position] != rune('i') {
-							goto l1686
+							goto l3121
 						}
 						position++
-						goto l1685
-					l1686:
-						position, tokenIndex = position1685, tokenIndex1685
+						goto l3120
+					l3121:
+						position, tokenIndex = position3120, tokenIndex3120
 						if buffer[position] != rune('I') {
-							goto l1682
-						}
-						position++
-					}
-				l1685:
-					{
-						position1687, tokenIndex1687 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1688
-						}
-						position++
-						goto l1687
-					l1688:
-						position, tokenIndex = position1687, tokenIndex1687
-						if buffer[position] != rune('N') {
-							goto l1682
+							goto l3117
 						}
 						position++
 					}
-				l1687:
+				l3120:
 					{
-						position1689, tokenIndex1689 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1690
+						position3122, tokenIndex3122 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l3123
 						}
 						position++
-						goto l1689
-					l1690:
-						position, tokenIndex = position1689, tokenIndex1689
-						if buffer[position] != rune('T') {
-							goto l1682
+						goto l3122
+					l3123:
+						position, tokenIndex = position3122, tokenIndex3122
+						if buffer[position] != rune('S') {
+							goto l3117
 						}
 						position++
 					}
-				l1689:
-					add(rulePegText, position1684)
+				l3122:
+					add(rulePegText, position3119)
 				}
-				if !_rules[ruleAction109]() {
-					goto l1682
+				if !_rules[ruleAction184]() {
+					goto l3117
 				}
-				add(ruleInt, position1683)
+				add(ruleIs, position3118)
 			}
 			return true
-		l1682:
-			position, tokenIndex = position1682, tokenIndex1682
+		l3117:
+			position, tokenIndex = position3117, tokenIndex3117
 			return false
 		},
-		/* 141 Float <- <(<(('f' / 'F') ('l' / 'L') ('o' / 'O') ('a' / 'A') ('t' / 'T'))> Action110)> */
+		/* 223 IsNot <- <(<(('i' / 'I') ('s' / 'S') sp (('n' / 'N') ('o' / 'O') ('t' / 'T')))> Action185)> */
 		func() bool {
-			position1691, tokenIndex1691 := position, tokenIndex
+			position3124, tokenIndex3124 := position, tokenIndex
 			{
-				position1692 := position
+				position3125 := position
 				{
-					position1693 := position
+					position3126 := position
 					{
-						position1694, tokenIndex1694 := position, tokenIndex
-						if buffer[position] != rune('f') {
-							goto l1695
+						position3127, tokenIndex3127 := position, tokenIndex
+						if buffer[position] != rune('i') {
+							goto l3128
 						}
 						position++
-						goto l1694
-					l1695:
-						position, tokenIndex = position1694, tokenIndex1694
-						if buffer[position] != rune('F') {
-							goto l1691
+						goto l3127
+					l3128:
+						position, tokenIndex = position3127, tokenIndex3127
+						if buffer[position] != rune('I') {
+							goto l3124
 						}
 						position++
 					}
-				l1694:
+				l3127:
 					{
-						position1696, tokenIndex1696 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1697
+						position3129, tokenIndex3129 := position, tokenIndex
+						if buffer[position] != rune('s') {
+							goto l3130
 						}
 						position++
-						goto l1696
-					l1697:
-						position, tokenIndex = position1696, tokenIndex1696
-						if buffer[position] != rune('L') {
-							goto l1691
+						goto l3129
+					l3130:
+						position, tokenIndex = position3129, tokenIndex3129
+						if buffer[position] != rune('S') {
+							goto l3124
 						}
 						position++
 					}
-				l1696:
+				l3129:
+					if !_rules[rulesp]() {
+						goto l3124
+					}
 					{
-						position1698, tokenIndex1698 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1699
+						position3131, tokenIndex3131 := position, tokenIndex
+						if buffer[position] != rune('n') {
+							goto l3132
 						}
 						position++
-						goto l1698
-					l1699:
-						position, tokenIndex = position1698, tokenIndex1698
-						if buffer[position] != rune('O') {
-							goto l1691
+						goto l3131
+					l3132:
+						position, tokenIndex = position3131, tokenIndex3131
+						if buffer[position] != rune('N') {
+							goto l3124
 						}
 						position++
 					}
-				l1698:
+				l3131:
 					{
-						position1700, tokenIndex1700 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1701
+						position3133, tokenIndex3133 := position, tokenIndex
+						if buffer[position] != rune('o') {
+							goto l3134
 						}
 						position++
-						goto l1700
-					l1701:
-						position, tokenIndex = position1700, tokenIndex1700
-						if buffer[position] != rune('A') {
-							goto l1691
+						goto l3133
+					l3134:
+						position, tokenIndex = position3133, tokenIndex3133
+						if buffer[position] != rune('O') {
+							goto l3124
 						}
 						position++
 					}
-				l1700:
+				l3133:
 					{
-						position1702, tokenIndex1702 := position, tokenIndex
+						position3135, tokenIndex3135 := position, tokenIndex
 						if buffer[position] != rune('t') {
-							goto l1703
+							goto l3136
 						}
 						position++
-						goto l1702
-					l1703:
-						position, tokenIndex = position1702, tokenIndex1702
+						goto l3135
+					l3136:
+						position, tokenIndex = position3135, tokenIndex3135
 						if buffer[position] != rune('T') {
-							goto l1691
+							goto l3124
 						}
 						position++
 					}
-				l1702:
-					add(rulePegText, position1693)
+				l3135:
+					add(rulePegText, position3126)
 				}
-				if !_rules[ruleAction110]() {
-					goto l1691
+				if !_rules[ruleAction185]() {
+					goto l3124
 				}
-				add(ruleFloat, position1692)
+				add(ruleIsNot, position3125)
 			}
 			return true
-		l1691:
-			position, tokenIndex = position1691, tokenIndex1691
+		l3124:
+			position, tokenIndex = position3124, tokenIndex3124
 			return false
 		},
-		/* 142 String <- <(<(('s' / 'S') ('t' / 'T') ('r' / 'R') ('i' / 'I') ('n' / 'N') ('g' / 'G'))> Action111)> */
+		/* 224 Plus <- <(<'+'> Action186)> */
 		func() bool {
-			position1704, tokenIndex1704 := position, tokenIndex
+			position3137, tokenIndex3137 := position, tokenIndex
 			{
-				position1705 := position
+				position3138 := position
 				{
-					position1706 := position
-					{
-						position1707, tokenIndex1707 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1708
-						}
-						position++
-						goto l1707
-					l1708:
-						position, tokenIndex = position1707, tokenIndex1707
-						if buffer[position] != rune('S') {
-							goto l1704
-						}
-						position++
+					position3139 := position
+					if buffer[position] != rune('+') {
+						goto l3137
 					}
-				l1707:
-					{
-						position1709, tokenIndex1709 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1710
-						}
-						position++
-						goto l1709
-					l1710:
-						position, tokenIndex = position1709, tokenIndex1709
-						if buffer[position] != rune('T') {
-							goto l1704
-						}
-						position++
+					position++
+					add(rulePegText, position3139)
+				}
+				if !_rules[ruleAction186]() {
+					goto l3137
+				}
+				add(rulePlus, position3138)
+			}
+			return true
+		l3137:
+			position, tokenIndex = position3137, tokenIndex3137
+			return false
+		},
+		/* 225 Minus <- <(<'-'> Action187)> */
+		func() bool {
+			position3140, tokenIndex3140 := position, tokenIndex
+			{
+				position3141 := position
+				{
+					position3142 := position
+					if buffer[position] != rune('-') {
+						goto l3140
 					}
-				l1709:
-					{
-						position1711, tokenIndex1711 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1712
-						}
-						position++
-						goto l1711
-					l1712:
-						position, tokenIndex = position1711, tokenIndex1711
-						if buffer[position] != rune('R') {
-							goto l1704
-						}
-						position++
+					position++
+					add(rulePegText, position3142)
+				}
+				if !_rules[ruleAction187]() {
+					goto l3140
+				}
+				add(ruleMinus, position3141)
+			}
+			return true
+		l3140:
+			position, tokenIndex = position3140, tokenIndex3140
+			return false
+		},
+		/* 226 Multiply <- <(<'*'> Action188)> */
+		func() bool {
+			position3143, tokenIndex3143 := position, tokenIndex
+			{
+				position3144 := position
+				{
+					position3145 := position
+					if buffer[position] != rune('*') {
+						goto l3143
 					}
-				l1711:
-					{
-						position1713, tokenIndex1713 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1714
-						}
-						position++
-						goto l1713
-					l1714:
-						position, tokenIndex = position1713, tokenIndex1713
-						if buffer[position] != rune('I') {
-							goto l1704
-						}
-						position++
+					position++
+					add(rulePegText, position3145)
+				}
+				if !_rules[ruleAction188]() {
+					goto l3143
+				}
+				add(ruleMultiply, position3144)
+			}
+			return true
+		l3143:
+			position, tokenIndex = position3143, tokenIndex3143
+			return false
+		},
+		/* 227 Divide <- <(<'/'> Action189)> */
+		func() bool {
+			position3146, tokenIndex3146 := position, tokenIndex
+			{
+				position3147 := position
+				{
+					position3148 := position
+					if buffer[position] != rune('/') {
+						goto l3146
 					}
-				l1713:
-					{
-						position1715, tokenIndex1715 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1716
-						}
-						position++
-						goto l1715
-					l1716:
-						position, tokenIndex = position1715, tokenIndex1715
-						if buffer[position] != rune('N') {
-							goto l1704
-						}
-						position++
+					position++
+					add(rulePegText, position3148)
+				}
+				if !_rules[ruleAction189]() {
+					goto l3146
+				}
+				add(ruleDivide, position3147)
+			}
+			return true
+		l3146:
+			position, tokenIndex = position3146, tokenIndex3146
+			return false
+		},
+		/* 228 Modulo <- <(<'%'> Action190)> */
+		func() bool {
+			position3149, tokenIndex3149 := position, tokenIndex
+			{
+				position3150 := position
+				{
+					position3151 := position
+					if buffer[position] != rune('%') {
+						goto l3149
 					}
-				l1715:
-					{
-						position1717, tokenIndex1717 := position, tokenIndex
-						if buffer[position] != rune('g') {
-							goto l1718
-						}
-						position++
-						goto l1717
-					l1718:
-						position, tokenIndex = position1717, tokenIndex1717
-						if buffer[position] != rune('G') {
-							goto l1704
-						}
-						position++
+					position++
+					add(rulePegText, position3151)
+				}
+				if !_rules[ruleAction190]() {
+					goto l3149
+				}
+				add(ruleModulo, position3150)
+			}
+			return true
+		l3149:
+			position, tokenIndex = position3149, tokenIndex3149
+			return false
+		},
+		/* 229 UnaryMinus <- <(<'-'> Action191)> */
+		func() bool {
+			position3152, tokenIndex3152 := position, tokenIndex
+			{
+				position3153 := position
+				{
+					position3154 := position
+					if buffer[position] != rune('-') {
+						goto l3152
+					}
+					position++
+					add(rulePegText, position3154)
+				}
+				if !_rules[ruleAction191]() {
+					goto l3152
+				}
+				add(ruleUnaryMinus, position3153)
+			}
+			return true
+		l3152:
+			position, tokenIndex = position3152, tokenIndex3152
+			return false
+		},
+		/* 230 Identifier <- <(<ident> Action192)> */
+		func() bool {
+			position3155, tokenIndex3155 := position, tokenIndex
+			{
+				position3156 := position
+				{
+					position3157 := position
+					if !_rules[ruleident]() {
+						goto l3155
 					}
-				l1717:
-					add(rulePegText, position1706)
+					add(rulePegText, position3157)
 				}
-				if !_rules[ruleAction111]() {
-					goto l1704
+				if !_rules[ruleAction192]() {
+					goto l3155
 				}
-				add(ruleString, position1705)
+				add(ruleIdentifier, position3156)
 			}
 			return true
-		l1704:
-			position, tokenIndex = position1704, tokenIndex1704
+		l3155:
+			position, tokenIndex = position3155, tokenIndex3155
 			return false
 		},
-		/* 143 Blob <- <(<(('b' / 'B') ('l' / 'L') ('o' / 'O') ('b' / 'B'))> Action112)> */
+		/* 231 TargetIdentifier <- <(<('*' / jsonSetPath)> Action193)> */
 		func() bool {
-			position1719, tokenIndex1719 := position, tokenIndex
+			position3158, tokenIndex3158 := position, tokenIndex
 			{
-				position1720 := position
+				position3159 := position
 				{
-					position1721 := position
+					position3160 := position
 					{
-						position1722, tokenIndex1722 := position, tokenIndex
-						if buffer[position] != rune('b') {
-							goto l1723
+						position3161, tokenIndex3161 := position, tokenIndex
+						if buffer[position] != rune('*') {
+							goto l3162
 						}
 						position++
-						goto l1722
-					l1723:
-						position, tokenIndex = position1722, tokenIndex1722
-						if buffer[position] != rune('B') {
-							goto l1719
+						goto l3161
+					l3162:
+						position, tokenIndex = position3161, tokenIndex3161
+						if !_rules[rulejsonSetPath]() {
+							goto l3158
 						}
-						position++
 					}
-				l1722:
-					{
-						position1724, tokenIndex1724 := position, tokenIndex
-						if buffer[position] != rune('l') {
-							goto l1725
-						}
-						position++
-						goto l1724
-					l1725:
-						position, tokenIndex = position1724, tokenIndex1724
-						if buffer[position] != rune('L') {
-							goto l1719
-						}
-						position++
+				l3161:
+					add(rulePegText, position3160)
+				}
+				if !_rules[ruleAction193]() {
+					goto l3158
+				}
+				add(ruleTargetIdentifier, position3159)
+			}
+			return true
+		l3158:
+			position, tokenIndex = position3158, tokenIndex3158
+			return false
+		},
+		/* 232 ident <- <(([a-z] / [A-Z]) ([a-z] / [A-Z] / [0-9] / '_')*)> */
+		func() bool {
+			position3163, tokenIndex3163 := position, tokenIndex
+			{
+				position3164 := position
+				{
+					position3165, tokenIndex3165 := position, tokenIndex
+					if c := buffer[position]; c < rune('a') || c > rune('z') {
+						goto l3166
 					}
-				l1724:
+					position++
+					goto l3165
+				l3166:
+					position, tokenIndex = position3165, tokenIndex3165
+					if c := buffer[position]; c < rune('A') || c > rune('Z') {
+						goto l3163
+					}
+					position++
+				}
+			l3165:
+			l3167:
+				{
+					position3168, tokenIndex3168 := position, tokenIndex
 					{
-						position1726, tokenIndex1726 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1727
+						position3169, tokenIndex3169 := position, tokenIndex
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l3170
 						}
 						position++
-						goto l1726
-					l1727:
-						position, tokenIndex = position1726, tokenIndex1726
-						if buffer[position] != rune('O') {
-							goto l1719
+						goto l3169
+					l3170:
+						position, tokenIndex = position3169, tokenIndex3169
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l3171
 						}
 						position++
-					}
-				l1726:
-					{
-						position1728, tokenIndex1728 := position, tokenIndex
-						if buffer[position] != rune('b') {
-							goto l1729
+						goto l3169
+					l3171:
+						position, tokenIndex = position3169, tokenIndex3169
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3172
 						}
 						position++
-						goto l1728
-					l1729:
-						position, tokenIndex = position1728, tokenIndex1728
-						if buffer[position] != rune('B') {
-							goto l1719
+						goto l3169
+					l3172:
+						position, tokenIndex = position3169, tokenIndex3169
+						if buffer[position] != rune('_') {
+							goto l3168
 						}
 						position++
 					}
-				l1728:
-					add(rulePegText, position1721)
+				l3169:
+					goto l3167
+				l3168:
+					position, tokenIndex = position3168, tokenIndex3168
 				}
-				if !_rules[ruleAction112]() {
-					goto l1719
+				add(ruleident, position3164)
+			}
+			return true
+		l3163:
+			position, tokenIndex = position3163, tokenIndex3163
+			return false
+		},
+		/* 233 jsonGetPath <- <(jsonPathHead jsonGetPathNonHead*)> */
+		func() bool {
+			position3173, tokenIndex3173 := position, tokenIndex
+			{
+				position3174 := position
+				if !_rules[rulejsonPathHead]() {
+					goto l3173
+				}
+			l3175:
+				{
+					position3176, tokenIndex3176 := position, tokenIndex
+					if !_rules[rulejsonGetPathNonHead]() {
+						goto l3176
+					}
+					goto l3175
+				l3176:
+					position, tokenIndex = position3176, tokenIndex3176
 				}
-				add(ruleBlob, position1720)
+				add(rulejsonGetPath, position3174)
 			}
 			return true
-		l1719:
-			position, tokenIndex = position1719, tokenIndex1719
+		l3173:
+			position, tokenIndex = position3173, tokenIndex3173
 			return false
 		},
-		/* 144 Timestamp <- <(<(('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ('s' / 'S') ('t' / 'T') ('a' / 'A') ('m' / 'M') ('p' / 'P'))> Action113)> */
+		/* 234 jsonSetPath <- <(jsonPathHead jsonSetPathNonHead*)> */
 		func() bool {
-			position1730, tokenIndex1730 := position, tokenIndex
+			position3177, tokenIndex3177 := position, tokenIndex
 			{
-				position1731 := position
+				position3178 := position
+				if !_rules[rulejsonPathHead]() {
+					goto l3177
+				}
+			l3179:
 				{
-					position1732 := position
-					{
-						position1733, tokenIndex1733 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1734
-						}
-						position++
-						goto l1733
-					l1734:
-						position, tokenIndex = position1733, tokenIndex1733
-						if buffer[position] != rune('T') {
-							goto l1730
-						}
-						position++
+					position3180, tokenIndex3180 := position, tokenIndex
+					if !_rules[rulejsonSetPathNonHead]() {
+						goto l3180
 					}
-				l1733:
-					{
-						position1735, tokenIndex1735 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1736
-						}
-						position++
-						goto l1735
-					l1736:
-						position, tokenIndex = position1735, tokenIndex1735
-						if buffer[position] != rune('I') {
-							goto l1730
-						}
-						position++
+					goto l3179
+				l3180:
+					position, tokenIndex = position3180, tokenIndex3180
+				}
+				add(rulejsonSetPath, position3178)
+			}
+			return true
+		l3177:
+			position, tokenIndex = position3177, tokenIndex3177
+			return false
+		},
+		/* 235 jsonPathHead <- <(jsonMapAccessString / jsonMapAccessBracket)> */
+		func() bool {
+			position3181, tokenIndex3181 := position, tokenIndex
+			{
+				position3182 := position
+				{
+					position3183, tokenIndex3183 := position, tokenIndex
+					if !_rules[rulejsonMapAccessString]() {
+						goto l3184
 					}
-				l1735:
-					{
-						position1737, tokenIndex1737 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1738
-						}
-						position++
-						goto l1737
-					l1738:
-						position, tokenIndex = position1737, tokenIndex1737
-						if buffer[position] != rune('M') {
-							goto l1730
-						}
-						position++
+					goto l3183
+				l3184:
+					position, tokenIndex = position3183, tokenIndex3183
+					if !_rules[rulejsonMapAccessBracket]() {
+						goto l3181
 					}
-				l1737:
-					{
-						position1739, tokenIndex1739 := position, tokenIndex
-						if buffer[position] != rune('e') {
-							goto l1740
-						}
-						position++
-						goto l1739
-					l1740:
-						position, tokenIndex = position1739, tokenIndex1739
-						if buffer[position] != rune('E') {
-							goto l1730
-						}
-						position++
+				}
+			l3183:
+				add(rulejsonPathHead, position3182)
+			}
+			return true
+		l3181:
+			position, tokenIndex = position3181, tokenIndex3181
+			return false
+		},
+		/* 236 jsonGetPathNonHead <- <(jsonMapMultipleLevel / jsonMapSingleLevel / jsonArrayFullSlice / jsonArrayPartialSlice / jsonArraySlice / jsonArrayAccess)> */
+		func() bool {
+			position3185, tokenIndex3185 := position, tokenIndex
+			{
+				position3186 := position
+				{
+					position3187, tokenIndex3187 := position, tokenIndex
+					if !_rules[rulejsonMapMultipleLevel]() {
+						goto l3188
 					}
-				l1739:
-					{
-						position1741, tokenIndex1741 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1742
-						}
-						position++
-						goto l1741
-					l1742:
-						position, tokenIndex = position1741, tokenIndex1741
-						if buffer[position] != rune('S') {
-							goto l1730
-						}
-						position++
+					goto l3187
+				l3188:
+					position, tokenIndex = position3187, tokenIndex3187
+					if !_rules[rulejsonMapSingleLevel]() {
+						goto l3189
 					}
-				l1741:
-					{
-						position1743, tokenIndex1743 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1744
-						}
-						position++
-						goto l1743
-					l1744:
-						position, tokenIndex = position1743, tokenIndex1743
-						if buffer[position] != rune('T') {
-							goto l1730
-						}
-						position++
+					goto l3187
+				l3189:
+					position, tokenIndex = position3187, tokenIndex3187
+					if !_rules[rulejsonArrayFullSlice]() {
+						goto l3190
 					}
-				l1743:
-					{
-						position1745, tokenIndex1745 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1746
-						}
-						position++
-						goto l1745
-					l1746:
-						position, tokenIndex = position1745, tokenIndex1745
-						if buffer[position] != rune('A') {
-							goto l1730
-						}
-						position++
+					goto l3187
+				l3190:
+					position, tokenIndex = position3187, tokenIndex3187
+					if !_rules[rulejsonArrayPartialSlice]() {
+						goto l3191
+					}
+					goto l3187
+				l3191:
+					position, tokenIndex = position3187, tokenIndex3187
+					if !_rules[rulejsonArraySlice]() {
+						goto l3192
+					}
+					goto l3187
+				l3192:
+					position, tokenIndex = position3187, tokenIndex3187
+					if !_rules[rulejsonArrayAccess]() {
+						goto l3185
+					}
+				}
+			l3187:
+				add(rulejsonGetPathNonHead, position3186)
+			}
+			return true
+		l3185:
+			position, tokenIndex = position3185, tokenIndex3185
+			return false
+		},
+		/* 237 jsonSetPathNonHead <- <(jsonMapSingleLevel / jsonNonNegativeArrayAccess)> */
+		func() bool {
+			position3193, tokenIndex3193 := position, tokenIndex
+			{
+				position3194 := position
+				{
+					position3195, tokenIndex3195 := position, tokenIndex
+					if !_rules[rulejsonMapSingleLevel]() {
+						goto l3196
+					}
+					goto l3195
+				l3196:
+					position, tokenIndex = position3195, tokenIndex3195
+					if !_rules[rulejsonNonNegativeArrayAccess]() {
+						goto l3193
+					}
+				}
+			l3195:
+				add(rulejsonSetPathNonHead, position3194)
+			}
+			return true
+		l3193:
+			position, tokenIndex = position3193, tokenIndex3193
+			return false
+		},
+		/* 238 jsonMapSingleLevel <- <(('.' jsonMapAccessString) / jsonMapAccessBracket)> */
+		func() bool {
+			position3197, tokenIndex3197 := position, tokenIndex
+			{
+				position3198 := position
+				{
+					position3199, tokenIndex3199 := position, tokenIndex
+					if buffer[position] != rune('.') {
+						goto l3200
+					}
+					position++
+					if !_rules[rulejsonMapAccessString]() {
+						goto l3200
+					}
+					goto l3199
+				l3200:
+					position, tokenIndex = position3199, tokenIndex3199
+					if !_rules[rulejsonMapAccessBracket]() {
+						goto l3197
 					}
-				l1745:
+				}
+			l3199:
+				add(rulejsonMapSingleLevel, position3198)
+			}
+			return true
+		l3197:
+			position, tokenIndex = position3197, tokenIndex3197
+			return false
+		},
+		/* 239 jsonMapMultipleLevel <- <('.' '.' (jsonMapAccessString / jsonMapAccessBracket))> */
+		func() bool {
+			position3201, tokenIndex3201 := position, tokenIndex
+			{
+				position3202 := position
+				if buffer[position] != rune('.') {
+					goto l3201
+				}
+				position++
+				if buffer[position] != rune('.') {
+					goto l3201
+				}
+				position++
+				{
+					position3203, tokenIndex3203 := position, tokenIndex
+					if !_rules[rulejsonMapAccessString]() {
+						goto l3204
+					}
+					goto l3203
+				l3204:
+					position, tokenIndex = position3203, tokenIndex3203
+					if !_rules[rulejsonMapAccessBracket]() {
+						goto l3201
+					}
+				}
+			l3203:
+				add(rulejsonMapMultipleLevel, position3202)
+			}
+			return true
+		l3201:
+			position, tokenIndex = position3201, tokenIndex3201
+			return false
+		},
+		/* 240 jsonMapAccessString <- <<(([a-z] / [A-Z]) ([a-z] / [A-Z] / [0-9] / '_')*)>> */
+		func() bool {
+			position3205, tokenIndex3205 := position, tokenIndex
+			{
+				position3206 := position
+				{
+					position3207 := position
 					{
-						position1747, tokenIndex1747 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1748
+						position3208, tokenIndex3208 := position, tokenIndex
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l3209
 						}
 						position++
-						goto l1747
-					l1748:
-						position, tokenIndex = position1747, tokenIndex1747
-						if buffer[position] != rune('M') {
-							goto l1730
+						goto l3208
+					l3209:
+						position, tokenIndex = position3208, tokenIndex3208
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l3205
 						}
 						position++
 					}
-				l1747:
+				l3208:
+				l3210:
 					{
-						position1749, tokenIndex1749 := position, tokenIndex
-						if buffer[position] != rune('p') {
-							goto l1750
-						}
-						position++
-						goto l1749
-					l1750:
-						position, tokenIndex = position1749, tokenIndex1749
-						if buffer[position] != rune('P') {
-							goto l1730
+						position3211, tokenIndex3211 := position, tokenIndex
+						{
+							position3212, tokenIndex3212 := position, tokenIndex
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l3213
+							}
+							position++
+							goto l3212
+						l3213:
+							position, tokenIndex = position3212, tokenIndex3212
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l3214
+							}
+							position++
+							goto l3212
+						l3214:
+							position, tokenIndex = position3212, tokenIndex3212
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l3215
+							}
+							position++
+							goto l3212
+						l3215:
+							position, tokenIndex = position3212, tokenIndex3212
+							if buffer[position] != rune('_') {
+								goto l3211
+							}
+							position++
 						}
-						position++
+					l3212:
+						goto l3210
+					l3211:
+						position, tokenIndex = position3211, tokenIndex3211
 					}
-				l1749:
-					add(rulePegText, position1732)
+					add(rulePegText, position3207)
+				}
+				add(rulejsonMapAccessString, position3206)
+			}
+			return true
+		l3205:
+			position, tokenIndex = position3205, tokenIndex3205
+			return false
+		},
+		/* 241 jsonMapAccessBracket <- <('[' doubleQuotedString ']')> */
+		func() bool {
+			position3216, tokenIndex3216 := position, tokenIndex
+			{
+				position3217 := position
+				if buffer[position] != rune('[') {
+					goto l3216
 				}
-				if !_rules[ruleAction113]() {
-					goto l1730
+				position++
+				if !_rules[ruledoubleQuotedString]() {
+					goto l3216
+				}
+				if buffer[position] != rune(']') {
+					goto l3216
 				}
-				add(ruleTimestamp, position1731)
+				position++
+				add(rulejsonMapAccessBracket, position3217)
 			}
 			return true
-		l1730:
-			position, tokenIndex = position1730, tokenIndex1730
+		l3216:
+			position, tokenIndex = position3216, tokenIndex3216
 			return false
 		},
-		/* 145 Array <- <(<(('a' / 'A') ('r' / 'R') ('r' / 'R') ('a' / 'A') ('y' / 'Y'))> Action114)> */
+		/* 242 doubleQuotedString <- <('"' <(('"' '"') / (!'"' .))*> '"')> */
 		func() bool {
-			position1751, tokenIndex1751 := position, tokenIndex
+			position3218, tokenIndex3218 := position, tokenIndex
 			{
-				position1752 := position
+				position3219 := position
+				if buffer[position] != rune('"') {
+					goto l3218
+				}
+				position++
 				{
-					position1753 := position
-					{
-						position1754, tokenIndex1754 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1755
-						}
-						position++
-						goto l1754
-					l1755:
-						position, tokenIndex = position1754, tokenIndex1754
-						if buffer[position] != rune('A') {
-							goto l1751
-						}
-						position++
-					}
-				l1754:
-					{
-						position1756, tokenIndex1756 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1757
-						}
-						position++
-						goto l1756
-					l1757:
-						position, tokenIndex = position1756, tokenIndex1756
-						if buffer[position] != rune('R') {
-							goto l1751
-						}
-						position++
-					}
-				l1756:
-					{
-						position1758, tokenIndex1758 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1759
-						}
-						position++
-						goto l1758
-					l1759:
-						position, tokenIndex = position1758, tokenIndex1758
-						if buffer[position] != rune('R') {
-							goto l1751
-						}
-						position++
-					}
-				l1758:
-					{
-						position1760, tokenIndex1760 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1761
-						}
-						position++
-						goto l1760
-					l1761:
-						position, tokenIndex = position1760, tokenIndex1760
-						if buffer[position] != rune('A') {
-							goto l1751
-						}
-						position++
-					}
-				l1760:
+					position3220 := position
+				l3221:
 					{
-						position1762, tokenIndex1762 := position, tokenIndex
-						if buffer[position] != rune('y') {
-							goto l1763
-						}
-						position++
-						goto l1762
-					l1763:
-						position, tokenIndex = position1762, tokenIndex1762
-						if buffer[position] != rune('Y') {
-							goto l1751
+						position3222, tokenIndex3222 := position, tokenIndex
+						{
+							position3223, tokenIndex3223 := position, tokenIndex
+							if buffer[position] != rune('"') {
+								goto l3224
+							}
+							position++
+							if buffer[position] != rune('"') {
+								goto l3224
+							}
+							position++
+							goto l3223
+						l3224:
+							position, tokenIndex = position3223, tokenIndex3223
+							{
+								position3225, tokenIndex3225 := position, tokenIndex
+								if buffer[position] != rune('"') {
+									goto l3225
+								}
+								position++
+								goto l3222
+							l3225:
+								position, tokenIndex = position3225, tokenIndex3225
+							}
+							if !matchDot() {
+								goto l3222
+							}
 						}
-						position++
+					l3223:
+						goto l3221
+					l3222:
+						position, tokenIndex = position3222, tokenIndex3222
 					}
-				l1762:
-					add(rulePegText, position1753)
+					add(rulePegText, position3220)
 				}
-				if !_rules[ruleAction114]() {
-					goto l1751
+				if buffer[position] != rune('"') {
+					goto l3218
 				}
-				add(ruleArray, position1752)
+				position++
+				add(ruledoubleQuotedString, position3219)
 			}
 			return true
-		l1751:
-			position, tokenIndex = position1751, tokenIndex1751
+		l3218:
+			position, tokenIndex = position3218, tokenIndex3218
 			return false
 		},
-		/* 146 Map <- <(<(('m' / 'M') ('a' / 'A') ('p' / 'P'))> Action115)> */
+		/* 243 jsonArrayAccess <- <('[' <('-'? [0-9]+)> ']')> */
 		func() bool {
-			position1764, tokenIndex1764 := position, tokenIndex
+			position3226, tokenIndex3226 := position, tokenIndex
 			{
-				position1765 := position
+				position3227 := position
+				if buffer[position] != rune('[') {
+					goto l3226
+				}
+				position++
 				{
-					position1766 := position
+					position3228 := position
 					{
-						position1767, tokenIndex1767 := position, tokenIndex
-						if buffer[position] != rune('m') {
-							goto l1768
-						}
-						position++
-						goto l1767
-					l1768:
-						position, tokenIndex = position1767, tokenIndex1767
-						if buffer[position] != rune('M') {
-							goto l1764
+						position3229, tokenIndex3229 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l3229
 						}
 						position++
+						goto l3230
+					l3229:
+						position, tokenIndex = position3229, tokenIndex3229
 					}
-				l1767:
-					{
-						position1769, tokenIndex1769 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1770
-						}
-						position++
-						goto l1769
-					l1770:
-						position, tokenIndex = position1769, tokenIndex1769
-						if buffer[position] != rune('A') {
-							goto l1764
-						}
-						position++
+				l3230:
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l3226
 					}
-				l1769:
+					position++
+				l3231:
 					{
-						position1771, tokenIndex1771 := position, tokenIndex
-						if buffer[position] != rune('p') {
-							goto l1772
-						}
-						position++
-						goto l1771
-					l1772:
-						position, tokenIndex = position1771, tokenIndex1771
-						if buffer[position] != rune('P') {
-							goto l1764
+						position3232, tokenIndex3232 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3232
 						}
 						position++
+						goto l3231
+					l3232:
+						position, tokenIndex = position3232, tokenIndex3232
 					}
-				l1771:
-					add(rulePegText, position1766)
+					add(rulePegText, position3228)
 				}
-				if !_rules[ruleAction115]() {
-					goto l1764
+				if buffer[position] != rune(']') {
+					goto l3226
 				}
-				add(ruleMap, position1765)
+				position++
+				add(rulejsonArrayAccess, position3227)
 			}
 			return true
-		l1764:
-			position, tokenIndex = position1764, tokenIndex1764
+		l3226:
+			position, tokenIndex = position3226, tokenIndex3226
 			return false
 		},
-		/* 147 Or <- <(<(('o' / 'O') ('r' / 'R'))> Action116)> */
+		/* 244 jsonNonNegativeArrayAccess <- <('[' <[0-9]+> ']')> */
 		func() bool {
-			position1773, tokenIndex1773 := position, tokenIndex
+			position3233, tokenIndex3233 := position, tokenIndex
 			{
-				position1774 := position
+				position3234 := position
+				if buffer[position] != rune('[') {
+					goto l3233
+				}
+				position++
 				{
-					position1775 := position
-					{
-						position1776, tokenIndex1776 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1777
-						}
-						position++
-						goto l1776
-					l1777:
-						position, tokenIndex = position1776, tokenIndex1776
-						if buffer[position] != rune('O') {
-							goto l1773
-						}
-						position++
+					position3235 := position
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l3233
 					}
-				l1776:
+					position++
+				l3236:
 					{
-						position1778, tokenIndex1778 := position, tokenIndex
-						if buffer[position] != rune('r') {
-							goto l1779
-						}
-						position++
-						goto l1778
-					l1779:
-						position, tokenIndex = position1778, tokenIndex1778
-						if buffer[position] != rune('R') {
-							goto l1773
+						position3237, tokenIndex3237 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3237
 						}
 						position++
+						goto l3236
+					l3237:
+						position, tokenIndex = position3237, tokenIndex3237
 					}
-				l1778:
-					add(rulePegText, position1775)
+					add(rulePegText, position3235)
 				}
-				if !_rules[ruleAction116]() {
-					goto l1773
+				if buffer[position] != rune(']') {
+					goto l3233
 				}
-				add(ruleOr, position1774)
+				position++
+				add(rulejsonNonNegativeArrayAccess, position3234)
 			}
 			return true
-		l1773:
-			position, tokenIndex = position1773, tokenIndex1773
+		l3233:
+			position, tokenIndex = position3233, tokenIndex3233
 			return false
 		},
-		/* 148 And <- <(<(('a' / 'A') ('n' / 'N') ('d' / 'D'))> Action117)> */
+		/* 245 jsonArraySlice <- <('[' <('-'? [0-9]+ ':' '-'? [0-9]+ (':' '-'? [0-9]+)?)> ']')> */
 		func() bool {
-			position1780, tokenIndex1780 := position, tokenIndex
+			position3238, tokenIndex3238 := position, tokenIndex
 			{
-				position1781 := position
+				position3239 := position
+				if buffer[position] != rune('[') {
+					goto l3238
+				}
+				position++
 				{
-					position1782 := position
+					position3240 := position
 					{
-						position1783, tokenIndex1783 := position, tokenIndex
-						if buffer[position] != rune('a') {
-							goto l1784
+						position3241, tokenIndex3241 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l3241
 						}
 						position++
-						goto l1783
-					l1784:
-						position, tokenIndex = position1783, tokenIndex1783
-						if buffer[position] != rune('A') {
-							goto l1780
+						goto l3242
+					l3241:
+						position, tokenIndex = position3241, tokenIndex3241
+					}
+				l3242:
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l3238
+					}
+					position++
+				l3243:
+					{
+						position3244, tokenIndex3244 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3244
 						}
 						position++
+						goto l3243
+					l3244:
+						position, tokenIndex = position3244, tokenIndex3244
+					}
+					if buffer[position] != rune(':') {
+						goto l3238
 					}
-				l1783:
+					position++
 					{
-						position1785, tokenIndex1785 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1786
+						position3245, tokenIndex3245 := position, tokenIndex
+						if buffer[position] != rune('-') {
+							goto l3245
 						}
 						position++
-						goto l1785
-					l1786:
-						position, tokenIndex = position1785, tokenIndex1785
-						if buffer[position] != rune('N') {
-							goto l1780
+						goto l3246
+					l3245:
+						position, tokenIndex = position3245, tokenIndex3245
+					}
+				l3246:
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l3238
+					}
+					position++
+				l3247:
+					{
+						position3248, tokenIndex3248 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3248
 						}
 						position++
+						goto l3247
+					l3248:
+						position, tokenIndex = position3248, tokenIndex3248
 					}
-				l1785:
 					{
-						position1787, tokenIndex1787 := position, tokenIndex
-						if buffer[position] != rune('d') {
-							goto l1788
+						position3249, tokenIndex3249 := position, tokenIndex
+						if buffer[position] != rune(':') {
+							goto l3249
 						}
 						position++
-						goto l1787
-					l1788:
-						position, tokenIndex = position1787, tokenIndex1787
-						if buffer[position] != rune('D') {
-							goto l1780
+						{
+							position3251, tokenIndex3251 := position, tokenIndex
+							if buffer[position] != rune('-') {
+								goto l3251
+							}
+							position++
+							goto l3252
+						l3251:
+							position, tokenIndex = position3251, tokenIndex3251
+						}
+					l3252:
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3249
 						}
 						position++
+					l3253:
+						{
+							position3254, tokenIndex3254 := position, tokenIndex
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l3254
+							}
+							position++
+							goto l3253
+						l3254:
+							position, tokenIndex = position3254, tokenIndex3254
+						}
+						goto l3250
+					l3249:
+						position, tokenIndex = position3249, tokenIndex3249
 					}
-				l1787:
-					add(rulePegText, position1782)
+				l3250:
+					add(rulePegText, position3240)
 				}
-				if !_rules[ruleAction117]() {
-					goto l1780
+				if buffer[position] != rune(']') {
+					goto l3238
 				}
-				add(ruleAnd, position1781)
+				position++
+				add(rulejsonArraySlice, position3239)
 			}
 			return true
-		l1780:
-			position, tokenIndex = position1780, tokenIndex1780
+		l3238:
+			position, tokenIndex = position3238, tokenIndex3238
 			return false
 		},
-		/* 149 Not <- <(<(('n' / 'N') ('o' / 'O') ('t' / 'T'))> Action118)> */
+		/* 246 jsonArrayPartialSlice <- <('[' <((':' '-'? [0-9]+) / ('-'? [0-9]+ ':'))> ']')> */
 		func() bool {
-			position1789, tokenIndex1789 := position, tokenIndex
+			position3255, tokenIndex3255 := position, tokenIndex
 			{
-				position1790 := position
+				position3256 := position
+				if buffer[position] != rune('[') {
+					goto l3255
+				}
+				position++
 				{
-					position1791 := position
+					position3257 := position
 					{
-						position1792, tokenIndex1792 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1793
+						position3258, tokenIndex3258 := position, tokenIndex
+						if buffer[position] != rune(':') {
+							goto l3259
 						}
 						position++
-						goto l1792
-					l1793:
-						position, tokenIndex = position1792, tokenIndex1792
-						if buffer[position] != rune('N') {
-							goto l1789
+						{
+							position3260, tokenIndex3260 := position, tokenIndex
+							if buffer[position] != rune('-') {
+								goto l3260
+							}
+							position++
+							goto l3261
+						l3260:
+							position, tokenIndex = position3260, tokenIndex3260
 						}
-						position++
-					}
-				l1792:
-					{
-						position1794, tokenIndex1794 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1795
+					l3261:
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3259
 						}
 						position++
-						goto l1794
-					l1795:
-						position, tokenIndex = position1794, tokenIndex1794
-						if buffer[position] != rune('O') {
-							goto l1789
+					l3262:
+						{
+							position3263, tokenIndex3263 := position, tokenIndex
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l3263
+							}
+							position++
+							goto l3262
+						l3263:
+							position, tokenIndex = position3263, tokenIndex3263
 						}
-						position++
-					}
-				l1794:
-					{
-						position1796, tokenIndex1796 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1797
+						goto l3258
+					l3259:
+						position, tokenIndex = position3258, tokenIndex3258
+						{
+							position3264, tokenIndex3264 := position, tokenIndex
+							if buffer[position] != rune('-') {
+								goto l3264
+							}
+							position++
+							goto l3265
+						l3264:
+							position, tokenIndex = position3264, tokenIndex3264
+						}
+					l3265:
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l3255
 						}
 						position++
-						goto l1796
-					l1797:
-						position, tokenIndex = position1796, tokenIndex1796
-						if buffer[position] != rune('T') {
-							goto l1789
+					l3266:
+						{
+							position3267, tokenIndex3267 := position, tokenIndex
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l3267
+							}
+							position++
+							goto l3266
+						l3267:
+							position, tokenIndex = position3267, tokenIndex3267
+						}
+						if buffer[position] != rune(':') {
+							goto l3255
 						}
 						position++
 					}
-				l1796:
-					add(rulePegText, position1791)
+				l3258:
+					add(rulePegText, position3257)
 				}
-				if !_rules[ruleAction118]() {
-					goto l1789
+				if buffer[position] != rune(']') {
+					goto l3255
 				}
-				add(ruleNot, position1790)
+				position++
+				add(rulejsonArrayPartialSlice, position3256)
 			}
 			return true
-		l1789:
-			position, tokenIndex = position1789, tokenIndex1789
+		l3255:
+			position, tokenIndex = position3255, tokenIndex3255
 			return false
 		},
-		/* 150 Equal <- <(<'='> Action119)> */
+		/* 247 jsonArrayFullSlice <- <('[' ':' ']')> */
 		func() bool {
-			position1798, tokenIndex1798 := position, tokenIndex
+			position3268, tokenIndex3268 := position, tokenIndex
 			{
-				position1799 := position
-				{
-					position1800 := position
-					if buffer[position] != rune('=') {
-						goto l1798
-					}
-					position++
-					add(rulePegText, position1800)
-				}
-				if !_rules[ruleAction119]() {
-					goto l1798
+				position3269 := position
+				if buffer[position] != rune('[') {
+					goto l3268
 				}
-				add(ruleEqual, position1799)
-			}
-			return true
-		l1798:
-			position, tokenIndex = position1798, tokenIndex1798
-			return false
-		},
-		/* 151 Less <- <(<'<'> Action120)> */
-		func() bool {
-			position1801, tokenIndex1801 := position, tokenIndex
-			{
-				position1802 := position
-				{
-					position1803 := position
-					if buffer[position] != rune('<') {
-						goto l1801
-					}
-					position++
-					add(rulePegText, position1803)
+				position++
+				if buffer[position] != rune(':') {
+					goto l3268
 				}
-				if !_rules[ruleAction120]() {
-					goto l1801
+				position++
+				if buffer[position] != rune(']') {
+					goto l3268
 				}
-				add(ruleLess, position1802)
+				position++
+				add(rulejsonArrayFullSlice, position3269)
 			}
 			return true
-		l1801:
-			position, tokenIndex = position1801, tokenIndex1801
+		l3268:
+			position, tokenIndex = position3268, tokenIndex3268
 			return false
 		},
-		/* 152 LessOrEqual <- <(<('<' '=')> Action121)> */
+		/* 248 spElem <- <(' ' / '\t' / '\n' / '\r' / comment / finalComment)> */
 		func() bool {
-			position1804, tokenIndex1804 := position, tokenIndex
+			position3270, tokenIndex3270 := position, tokenIndex
 			{
-				position1805 := position
+				position3271 := position
 				{
-					position1806 := position
-					if buffer[position] != rune('<') {
-						goto l1804
+					position3272, tokenIndex3272 := position, tokenIndex
+					if buffer[position] != rune(' ') {
+						goto l3273
 					}
 					position++
-					if buffer[position] != rune('=') {
-						goto l1804
+					goto l3272
+				l3273:
+					position, tokenIndex = position3272, tokenIndex3272
+					if buffer[position] != rune('\t') {
+						goto l3274
 					}
 					position++
-					add(rulePegText, position1806)
-				}
-				if !_rules[ruleAction121]() {
-					goto l1804
+					goto l3272
+				l3274:
+					position, tokenIndex = position3272, tokenIndex3272
+					if buffer[position] != rune('\n') {
+						goto l3275
+					}
+					position++
+					goto l3272
+				l3275:
+					position, tokenIndex = position3272, tokenIndex3272
+					if buffer[position] != rune('\r') {
+						goto l3276
+					}
+					position++
+					goto l3272
+				l3276:
+					position, tokenIndex = position3272, tokenIndex3272
+					if !_rules[rulecomment]() {
+						goto l3277
+					}
+					goto l3272
+				l3277:
+					position, tokenIndex = position3272, tokenIndex3272
+					if !_rules[rulefinalComment]() {
+						goto l3270
+					}
 				}
-				add(ruleLessOrEqual, position1805)
+			l3272:
+				add(rulespElem, position3271)
 			}
 			return true
-		l1804:
-			position, tokenIndex = position1804, tokenIndex1804
+		l3270:
+			position, tokenIndex = position3270, tokenIndex3270
 			return false
 		},
-		/* 153 Greater <- <(<'>'> Action122)> */
+		/* 249 sp <- <spElem+> */
 		func() bool {
-			position1807, tokenIndex1807 := position, tokenIndex
+			position3278, tokenIndex3278 := position, tokenIndex
 			{
-				position1808 := position
+				position3279 := position
+				if !_rules[rulespElem]() {
+					goto l3278
+				}
+			l3280:
 				{
-					position1809 := position
-					if buffer[position] != rune('>') {
-						goto l1807
+					position3281, tokenIndex3281 := position, tokenIndex
+					if !_rules[rulespElem]() {
+						goto l3281
 					}
-					position++
-					add(rulePegText, position1809)
-				}
-				if !_rules[ruleAction122]() {
-					goto l1807
+					goto l3280
+				l3281:
+					position, tokenIndex = position3281, tokenIndex3281
 				}
-				add(ruleGreater, position1808)
+				add(rulesp, position3279)
 			}
 			return true
-		l1807:
-			position, tokenIndex = position1807, tokenIndex1807
+		l3278:
+			position, tokenIndex = position3278, tokenIndex3278
 			return false
 		},
-		/* 154 GreaterOrEqual <- <(<('>' '=')> Action123)> */
+		/* 250 spOpt <- <spElem*> */
 		func() bool {
-			position1810, tokenIndex1810 := position, tokenIndex
 			{
-				position1811 := position
+				position3283 := position
+			l3284:
 				{
-					position1812 := position
-					if buffer[position] != rune('>') {
-						goto l1810
-					}
-					position++
-					if buffer[position] != rune('=') {
-						goto l1810
+					position3285, tokenIndex3285 := position, tokenIndex
+					if !_rules[rulespElem]() {
+						goto l3285
 					}
-					position++
-					add(rulePegText, position1812)
-				}
-				if !_rules[ruleAction123]() {
-					goto l1810
+					goto l3284
+				l3285:
+					position, tokenIndex = position3285, tokenIndex3285
 				}
-				add(ruleGreaterOrEqual, position1811)
+				add(rulespOpt, position3283)
 			}
 			return true
-		l1810:
-			position, tokenIndex = position1810, tokenIndex1810
-			return false
 		},
-		/* 155 NotEqual <- <(<(('!' '=') / ('<' '>'))> Action124)> */
+		/* 251 comment <- <('-' '-' (!('\r' / '\n') .)* ('\r' / '\n'))> */
 		func() bool {
-			position1813, tokenIndex1813 := position, tokenIndex
+			position3286, tokenIndex3286 := position, tokenIndex
 			{
-				position1814 := position
+				position3287 := position
+				if buffer[position] != rune('-') {
+					goto l3286
+				}
+				position++
+				if buffer[position] != rune('-') {
+					goto l3286
+				}
+				position++
+			l3288:
 				{
-					position1815 := position
+					position3289, tokenIndex3289 := position, tokenIndex
 					{
-						position1816, tokenIndex1816 := position, tokenIndex
-						if buffer[position] != rune('!') {
-							goto l1817
-						}
-						position++
-						if buffer[position] != rune('=') {
-							goto l1817
-						}
-						position++
-						goto l1816
-					l1817:
-						position, tokenIndex = position1816, tokenIndex1816
-						if buffer[position] != rune('<') {
-							goto l1813
-						}
-						position++
-						if buffer[position] != rune('>') {
-							goto l1813
+						position3290, tokenIndex3290 := position, tokenIndex
+						{
+							position3291, tokenIndex3291 := position, tokenIndex
+							if buffer[position] != rune('\r') {
+								goto l3292
+							}
+							position++
+							goto l3291
+						l3292:
+							position, tokenIndex = position3291, tokenIndex3291
+							if buffer[position] != rune('\n') {
+								goto l3290
+							}
+							position++
 						}
-						position++
+					l3291:
+						goto l3289
+					l3290:
+						position, tokenIndex = position3290, tokenIndex3290
 					}
-				l1816:
-					add(rulePegText, position1815)
-				}
-				if !_rules[ruleAction124]() {
-					goto l1813
+					if !matchDot() {
+						goto l3289
+					}
+					goto l3288
+				l3289:
+					position, tokenIndex = position3289, tokenIndex3289
 				}
-				add(ruleNotEqual, position1814)
-			}
-			return true
-		l1813:
-			position, tokenIndex = position1813, tokenIndex1813
-			return false
-		},
-		/* 156 Concat <- <(<('|' '|')> Action125)> */
-		func() bool {
-			position1818, tokenIndex1818 := position, tokenIndex
-			{
-				position1819 := position
 				{
-					position1820 := position
-					if buffer[position] != rune('|') {
-						goto l1818
+					position3293, tokenIndex3293 := position, tokenIndex
+					if buffer[position] != rune('\r') {
+						goto l3294
 					}
 					position++
-					if buffer[position] != rune('|') {
-						goto l1818
+					goto l3293
+				l3294:
+					position, tokenIndex = position3293, tokenIndex3293
+					if buffer[position] != rune('\n') {
+						goto l3286
 					}
 					position++
-					add(rulePegText, position1820)
 				}
-				if !_rules[ruleAction125]() {
-					goto l1818
-				}
-				add(ruleConcat, position1819)
+			l3293:
+				add(rulecomment, position3287)
 			}
 			return true
-		l1818:
-			position, tokenIndex = position1818, tokenIndex1818
+		l3286:
+			position, tokenIndex = position3286, tokenIndex3286
 			return false
 		},
-		/* 157 Is <- <(<(('i' / 'I') ('s' / 'S'))> Action126)> */
+		/* 252 finalComment <- <('-' '-' (!('\r' / '\n') .)* !.)> */
 		func() bool {
-			position1821, tokenIndex1821 := position, tokenIndex
+			position3295, tokenIndex3295 := position, tokenIndex
 			{
-				position1822 := position
+				position3296 := position
+				if buffer[position] != rune('-') {
+					goto l3295
+				}
+				position++
+				if buffer[position] != rune('-') {
+					goto l3295
+				}
+				position++
+			l3297:
 				{
-					position1823 := position
+					position3298, tokenIndex3298 := position, tokenIndex
 					{
-						position1824, tokenIndex1824 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1825
-						}
-						position++
-						goto l1824
-					l1825:
-						position, tokenIndex = position1824, tokenIndex1824
-						if buffer[position] != rune('I') {
-							goto l1821
+						position3299, tokenIndex3299 := position, tokenIndex
+						{
+							position3300, tokenIndex3300 := position, tokenIndex
+							if buffer[position] != rune('\r') {
+								goto l3301
+							}
+							position++
+							goto l3300
+						l3301:
+							position, tokenIndex = position3300, tokenIndex3300
+							if buffer[position] != rune('\n') {
+								goto l3299
+							}
+							position++
 						}
-						position++
+					l3300:
+						goto l3298
+					l3299:
+						position, tokenIndex = position3299, tokenIndex3299
 					}
-				l1824:
-					{
-						position1826, tokenIndex1826 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1827
-						}
-						position++
-						goto l1826
-					l1827:
-						position, tokenIndex = position1826, tokenIndex1826
-						if buffer[position] != rune('S') {
-							goto l1821
-						}
-						position++
+					if !matchDot() {
+						goto l3298
 					}
-				l1826:
-					add(rulePegText, position1823)
+					goto l3297
+				l3298:
+					position, tokenIndex = position3298, tokenIndex3298
 				}
-				if !_rules[ruleAction126]() {
-					goto l1821
+				{
+					position3302, tokenIndex3302 := position, tokenIndex
+					if !matchDot() {
+						goto l3302
+					}
+					goto l3295
+				l3302:
+					position, tokenIndex = position3302, tokenIndex3302
 				}
-				add(ruleIs, position1822)
+				add(rulefinalComment, position3296)
 			}
 			return true
-		l1821:
-			position, tokenIndex = position1821, tokenIndex1821
+		l3295:
+			position, tokenIndex = position3295, tokenIndex3295
 			return false
 		},
-		/* 158 IsNot <- <(<(('i' / 'I') ('s' / 'S') sp (('n' / 'N') ('o' / 'O') ('t' / 'T')))> Action127)> */
+		nil,
+		/* 255 Action0 <- <{
+		    p.IncludeTrailingWhitespace(begin, end)
+		}> */
 		func() bool {
-			position1828, tokenIndex1828 := position, tokenIndex
 			{
-				position1829 := position
-				{
-					position1830 := position
-					{
-						position1831, tokenIndex1831 := position, tokenIndex
-						if buffer[position] != rune('i') {
-							goto l1832
-						}
-						position++
-						goto l1831
-					l1832:
-						position, tokenIndex = position1831, tokenIndex1831
-						if buffer[position] != rune('I') {
-							goto l1828
-						}
-						position++
-					}
-				l1831:
-					{
-						position1833, tokenIndex1833 := position, tokenIndex
-						if buffer[position] != rune('s') {
-							goto l1834
-						}
-						position++
-						goto l1833
-					l1834:
-						position, tokenIndex = position1833, tokenIndex1833
-						if buffer[position] != rune('S') {
-							goto l1828
-						}
-						position++
-					}
-				l1833:
-					if !_rules[rulesp]() {
-						goto l1828
-					}
-					{
-						position1835, tokenIndex1835 := position, tokenIndex
-						if buffer[position] != rune('n') {
-							goto l1836
-						}
-						position++
-						goto l1835
-					l1836:
-						position, tokenIndex = position1835, tokenIndex1835
-						if buffer[position] != rune('N') {
-							goto l1828
-						}
-						position++
-					}
-				l1835:
-					{
-						position1837, tokenIndex1837 := position, tokenIndex
-						if buffer[position] != rune('o') {
-							goto l1838
-						}
-						position++
-						goto l1837
-					l1838:
-						position, tokenIndex = position1837, tokenIndex1837
-						if buffer[position] != rune('O') {
-							goto l1828
-						}
-						position++
-					}
-				l1837:
-					{
-						position1839, tokenIndex1839 := position, tokenIndex
-						if buffer[position] != rune('t') {
-							goto l1840
-						}
-						position++
-						goto l1839
-					l1840:
-						position, tokenIndex = position1839, tokenIndex1839
-						if buffer[position] != rune('T') {
-							goto l1828
-						}
-						position++
-					}
-				l1839:
-					add(rulePegText, position1830)
-				}
-				if !_rules[ruleAction127]() {
-					goto l1828
-				}
-				add(ruleIsNot, position1829)
+				add(ruleAction0, position)
+			}
+			return true
+		},
+		/* 256 Action1 <- <{
+		    p.IncludeTrailingWhitespace(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction1, position)
+			}
+			return true
+		},
+		/* 257 Action2 <- <{
+		    p.AssembleSelect()
+		}> */
+		func() bool {
+			{
+				add(ruleAction2, position)
+			}
+			return true
+		},
+		/* 258 Action3 <- <{
+		    p.AssembleSelectUnion(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction3, position)
+			}
+			return true
+		},
+		/* 259 Action4 <- <{
+		    p.AssembleCreateStreamAsSelect()
+		}> */
+		func() bool {
+			{
+				add(ruleAction4, position)
+			}
+			return true
+		},
+		/* 260 Action5 <- <{
+		    p.AssembleCreateStreamAsSelectUnion()
+		}> */
+		func() bool {
+			{
+				add(ruleAction5, position)
+			}
+			return true
+		},
+		/* 261 Action6 <- <{
+		    p.AssembleCreateStreamRoutes()
+		}> */
+		func() bool {
+			{
+				add(ruleAction6, position)
+			}
+			return true
+		},
+		/* 262 Action7 <- <{
+		    p.AssemblePartitionedRoutes(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction7, position)
+			}
+			return true
+		},
+		/* 263 Action8 <- <{
+		    p.AssembleConditionalRoutes(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction8, position)
+			}
+			return true
+		},
+		/* 264 Action9 <- <{
+		    p.AssembleConditionalRoute()
+		}> */
+		func() bool {
+			{
+				add(ruleAction9, position)
 			}
 			return true
-		l1828:
-			position, tokenIndex = position1828, tokenIndex1828
-			return false
 		},
-		/* 159 Plus <- <(<'+'> Action128)> */
+		/* 265 Action10 <- <{
+		    p.AssembleElseRoute()
+		}> */
 		func() bool {
-			position1841, tokenIndex1841 := position, tokenIndex
 			{
-				position1842 := position
-				{
-					position1843 := position
-					if buffer[position] != rune('+') {
-						goto l1841
-					}
-					position++
-					add(rulePegText, position1843)
-				}
-				if !_rules[ruleAction128]() {
-					goto l1841
-				}
-				add(rulePlus, position1842)
+				add(ruleAction10, position)
 			}
 			return true
-		l1841:
-			position, tokenIndex = position1841, tokenIndex1841
-			return false
 		},
-		/* 160 Minus <- <(<'-'> Action129)> */
+		/* 266 Action11 <- <{
+		    p.AssembleCreateSource()
+		}> */
 		func() bool {
-			position1844, tokenIndex1844 := position, tokenIndex
 			{
-				position1845 := position
-				{
-					position1846 := position
-					if buffer[position] != rune('-') {
-						goto l1844
-					}
-					position++
-					add(rulePegText, position1846)
-				}
-				if !_rules[ruleAction129]() {
-					goto l1844
-				}
-				add(ruleMinus, position1845)
+				add(ruleAction11, position)
 			}
 			return true
-		l1844:
-			position, tokenIndex = position1844, tokenIndex1844
-			return false
 		},
-		/* 161 Multiply <- <(<'*'> Action130)> */
+		/* 267 Action12 <- <{
+		    p.AssembleCreateSink()
+		}> */
 		func() bool {
-			position1847, tokenIndex1847 := position, tokenIndex
 			{
-				position1848 := position
-				{
-					position1849 := position
-					if buffer[position] != rune('*') {
-						goto l1847
-					}
-					position++
-					add(rulePegText, position1849)
-				}
-				if !_rules[ruleAction130]() {
-					goto l1847
-				}
-				add(ruleMultiply, position1848)
+				add(ruleAction12, position)
 			}
 			return true
-		l1847:
-			position, tokenIndex = position1847, tokenIndex1847
-			return false
 		},
-		/* 162 Divide <- <(<'/'> Action131)> */
+		/* 268 Action13 <- <{
+		    p.AssembleCreateState()
+		}> */
 		func() bool {
-			position1850, tokenIndex1850 := position, tokenIndex
 			{
-				position1851 := position
-				{
-					position1852 := position
-					if buffer[position] != rune('/') {
-						goto l1850
-					}
-					position++
-					add(rulePegText, position1852)
-				}
-				if !_rules[ruleAction131]() {
-					goto l1850
-				}
-				add(ruleDivide, position1851)
+				add(ruleAction13, position)
 			}
 			return true
-		l1850:
-			position, tokenIndex = position1850, tokenIndex1850
-			return false
 		},
-		/* 163 Modulo <- <(<'%'> Action132)> */
+		/* 269 Action14 <- <{
+		    p.AssembleUpdateState()
+		}> */
 		func() bool {
-			position1853, tokenIndex1853 := position, tokenIndex
 			{
-				position1854 := position
-				{
-					position1855 := position
-					if buffer[position] != rune('%') {
-						goto l1853
-					}
-					position++
-					add(rulePegText, position1855)
-				}
-				if !_rules[ruleAction132]() {
-					goto l1853
-				}
-				add(ruleModulo, position1854)
+				add(ruleAction14, position)
 			}
 			return true
-		l1853:
-			position, tokenIndex = position1853, tokenIndex1853
-			return false
 		},
-		/* 164 UnaryMinus <- <(<'-'> Action133)> */
+		/* 270 Action15 <- <{
+		    p.AssembleUpdateSource()
+		}> */
 		func() bool {
-			position1856, tokenIndex1856 := position, tokenIndex
 			{
-				position1857 := position
-				{
-					position1858 := position
-					if buffer[position] != rune('-') {
-						goto l1856
-					}
-					position++
-					add(rulePegText, position1858)
-				}
-				if !_rules[ruleAction133]() {
-					goto l1856
-				}
-				add(ruleUnaryMinus, position1857)
+				add(ruleAction15, position)
 			}
 			return true
-		l1856:
-			position, tokenIndex = position1856, tokenIndex1856
-			return false
 		},
-		/* 165 Identifier <- <(<ident> Action134)> */
+		/* 271 Action16 <- <{
+		    p.AssembleUpdateSink()
+		}> */
 		func() bool {
-			position1859, tokenIndex1859 := position, tokenIndex
 			{
-				position1860 := position
-				{
-					position1861 := position
-					if !_rules[ruleident]() {
-						goto l1859
-					}
-					add(rulePegText, position1861)
-				}
-				if !_rules[ruleAction134]() {
-					goto l1859
-				}
-				add(ruleIdentifier, position1860)
+				add(ruleAction16, position)
 			}
 			return true
-		l1859:
-			position, tokenIndex = position1859, tokenIndex1859
-			return false
 		},
-		/* 166 TargetIdentifier <- <(<('*' / jsonSetPath)> Action135)> */
+		/* 272 Action17 <- <{
+		    p.AssembleInsertIntoFrom()
+		}> */
 		func() bool {
-			position1862, tokenIndex1862 := position, tokenIndex
 			{
-				position1863 := position
-				{
-					position1864 := position
-					{
-						position1865, tokenIndex1865 := position, tokenIndex
-						if buffer[position] != rune('*') {
-							goto l1866
-						}
-						position++
-						goto l1865
-					l1866:
-						position, tokenIndex = position1865, tokenIndex1865
-						if !_rules[rulejsonSetPath]() {
-							goto l1862
-						}
-					}
-				l1865:
-					add(rulePegText, position1864)
-				}
-				if !_rules[ruleAction135]() {
-					goto l1862
-				}
-				add(ruleTargetIdentifier, position1863)
+				add(ruleAction17, position)
 			}
 			return true
-		l1862:
-			position, tokenIndex = position1862, tokenIndex1862
-			return false
 		},
-		/* 167 ident <- <(([a-z] / [A-Z]) ([a-z] / [A-Z] / [0-9] / '_')*)> */
+		/* 273 Action18 <- <{
+		    p.EnsureProjections(begin, end)
+		}> */
 		func() bool {
-			position1867, tokenIndex1867 := position, tokenIndex
 			{
-				position1868 := position
-				{
-					position1869, tokenIndex1869 := position, tokenIndex
-					if c := buffer[position]; c < rune('a') || c > rune('z') {
-						goto l1870
-					}
-					position++
-					goto l1869
-				l1870:
-					position, tokenIndex = position1869, tokenIndex1869
-					if c := buffer[position]; c < rune('A') || c > rune('Z') {
-						goto l1867
-					}
-					position++
-				}
-			l1869:
-			l1871:
-				{
-					position1872, tokenIndex1872 := position, tokenIndex
-					{
-						position1873, tokenIndex1873 := position, tokenIndex
-						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l1874
-						}
-						position++
-						goto l1873
-					l1874:
-						position, tokenIndex = position1873, tokenIndex1873
-						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l1875
-						}
-						position++
-						goto l1873
-					l1875:
-						position, tokenIndex = position1873, tokenIndex1873
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1876
-						}
-						position++
-						goto l1873
-					l1876:
-						position, tokenIndex = position1873, tokenIndex1873
-						if buffer[position] != rune('_') {
-							goto l1872
-						}
-						position++
-					}
-				l1873:
-					goto l1871
-				l1872:
-					position, tokenIndex = position1872, tokenIndex1872
-				}
-				add(ruleident, position1868)
+				add(ruleAction18, position)
 			}
 			return true
-		l1867:
-			position, tokenIndex = position1867, tokenIndex1867
-			return false
 		},
-		/* 168 jsonGetPath <- <(jsonPathHead jsonGetPathNonHead*)> */
+		/* 274 Action19 <- <{
+		    p.AssemblePauseSource()
+		}> */
 		func() bool {
-			position1877, tokenIndex1877 := position, tokenIndex
 			{
-				position1878 := position
-				if !_rules[rulejsonPathHead]() {
-					goto l1877
-				}
-			l1879:
-				{
-					position1880, tokenIndex1880 := position, tokenIndex
-					if !_rules[rulejsonGetPathNonHead]() {
-						goto l1880
-					}
-					goto l1879
-				l1880:
-					position, tokenIndex = position1880, tokenIndex1880
-				}
-				add(rulejsonGetPath, position1878)
+				add(ruleAction19, position)
 			}
 			return true
-		l1877:
-			position, tokenIndex = position1877, tokenIndex1877
-			return false
 		},
-		/* 169 jsonSetPath <- <(jsonPathHead jsonSetPathNonHead*)> */
+		/* 275 Action20 <- <{
+		    p.AssembleResumeSource()
+		}> */
 		func() bool {
-			position1881, tokenIndex1881 := position, tokenIndex
 			{
-				position1882 := position
-				if !_rules[rulejsonPathHead]() {
-					goto l1881
-				}
-			l1883:
-				{
-					position1884, tokenIndex1884 := position, tokenIndex
-					if !_rules[rulejsonSetPathNonHead]() {
-						goto l1884
-					}
-					goto l1883
-				l1884:
-					position, tokenIndex = position1884, tokenIndex1884
-				}
-				add(rulejsonSetPath, position1882)
+				add(ruleAction20, position)
 			}
 			return true
-		l1881:
-			position, tokenIndex = position1881, tokenIndex1881
-			return false
 		},
-		/* 170 jsonPathHead <- <(jsonMapAccessString / jsonMapAccessBracket)> */
+		/* 276 Action21 <- <{
+		    p.AssembleRewindSource()
+		}> */
 		func() bool {
-			position1885, tokenIndex1885 := position, tokenIndex
 			{
-				position1886 := position
-				{
-					position1887, tokenIndex1887 := position, tokenIndex
-					if !_rules[rulejsonMapAccessString]() {
-						goto l1888
-					}
-					goto l1887
-				l1888:
-					position, tokenIndex = position1887, tokenIndex1887
-					if !_rules[rulejsonMapAccessBracket]() {
-						goto l1885
-					}
-				}
-			l1887:
-				add(rulejsonPathHead, position1886)
+				add(ruleAction21, position)
 			}
 			return true
-		l1885:
-			position, tokenIndex = position1885, tokenIndex1885
-			return false
 		},
-		/* 171 jsonGetPathNonHead <- <(jsonMapMultipleLevel / jsonMapSingleLevel / jsonArrayFullSlice / jsonArrayPartialSlice / jsonArraySlice / jsonArrayAccess)> */
+		/* 277 Action22 <- <{
+		    p.AssembleDropSource()
+		}> */
 		func() bool {
-			position1889, tokenIndex1889 := position, tokenIndex
 			{
-				position1890 := position
-				{
-					position1891, tokenIndex1891 := position, tokenIndex
-					if !_rules[rulejsonMapMultipleLevel]() {
-						goto l1892
-					}
-					goto l1891
-				l1892:
-					position, tokenIndex = position1891, tokenIndex1891
-					if !_rules[rulejsonMapSingleLevel]() {
-						goto l1893
-					}
-					goto l1891
-				l1893:
-					position, tokenIndex = position1891, tokenIndex1891
-					if !_rules[rulejsonArrayFullSlice]() {
-						goto l1894
-					}
-					goto l1891
-				l1894:
-					position, tokenIndex = position1891, tokenIndex1891
-					if !_rules[rulejsonArrayPartialSlice]() {
-						goto l1895
-					}
-					goto l1891
-				l1895:
-					position, tokenIndex = position1891, tokenIndex1891
-					if !_rules[rulejsonArraySlice]() {
-						goto l1896
-					}
-					goto l1891
-				l1896:
-					position, tokenIndex = position1891, tokenIndex1891
-					if !_rules[rulejsonArrayAccess]() {
-						goto l1889
-					}
-				}
-			l1891:
-				add(rulejsonGetPathNonHead, position1890)
+				add(ruleAction22, position)
 			}
 			return true
-		l1889:
-			position, tokenIndex = position1889, tokenIndex1889
-			return false
 		},
-		/* 172 jsonSetPathNonHead <- <(jsonMapSingleLevel / jsonNonNegativeArrayAccess)> */
+		/* 278 Action23 <- <{
+		    p.AssembleDropStream()
+		}> */
 		func() bool {
-			position1897, tokenIndex1897 := position, tokenIndex
 			{
-				position1898 := position
-				{
-					position1899, tokenIndex1899 := position, tokenIndex
-					if !_rules[rulejsonMapSingleLevel]() {
-						goto l1900
-					}
-					goto l1899
-				l1900:
-					position, tokenIndex = position1899, tokenIndex1899
-					if !_rules[rulejsonNonNegativeArrayAccess]() {
-						goto l1897
-					}
-				}
-			l1899:
-				add(rulejsonSetPathNonHead, position1898)
+				add(ruleAction23, position)
 			}
 			return true
-		l1897:
-			position, tokenIndex = position1897, tokenIndex1897
-			return false
 		},
-		/* 173 jsonMapSingleLevel <- <(('.' jsonMapAccessString) / jsonMapAccessBracket)> */
+		/* 279 Action24 <- <{
+		    p.AssembleResumeStream()
+		}> */
 		func() bool {
-			position1901, tokenIndex1901 := position, tokenIndex
 			{
-				position1902 := position
-				{
-					position1903, tokenIndex1903 := position, tokenIndex
-					if buffer[position] != rune('.') {
-						goto l1904
-					}
-					position++
-					if !_rules[rulejsonMapAccessString]() {
-						goto l1904
-					}
-					goto l1903
-				l1904:
-					position, tokenIndex = position1903, tokenIndex1903
-					if !_rules[rulejsonMapAccessBracket]() {
-						goto l1901
-					}
-				}
-			l1903:
-				add(rulejsonMapSingleLevel, position1902)
+				add(ruleAction24, position)
 			}
 			return true
-		l1901:
-			position, tokenIndex = position1901, tokenIndex1901
-			return false
 		},
-		/* 174 jsonMapMultipleLevel <- <('.' '.' (jsonMapAccessString / jsonMapAccessBracket))> */
+		/* 280 Action25 <- <{
+		    p.AssembleDropSink()
+		}> */
 		func() bool {
-			position1905, tokenIndex1905 := position, tokenIndex
 			{
-				position1906 := position
-				if buffer[position] != rune('.') {
-					goto l1905
-				}
-				position++
-				if buffer[position] != rune('.') {
-					goto l1905
-				}
-				position++
-				{
-					position1907, tokenIndex1907 := position, tokenIndex
-					if !_rules[rulejsonMapAccessString]() {
-						goto l1908
-					}
-					goto l1907
-				l1908:
-					position, tokenIndex = position1907, tokenIndex1907
-					if !_rules[rulejsonMapAccessBracket]() {
-						goto l1905
-					}
-				}
-			l1907:
-				add(rulejsonMapMultipleLevel, position1906)
+				add(ruleAction25, position)
+			}
+			return true
+		},
+		/* 281 Action26 <- <{
+		    p.AssembleProfileStream()
+		}> */
+		func() bool {
+			{
+				add(ruleAction26, position)
+			}
+			return true
+		},
+		/* 282 Action27 <- <{
+		    p.AssembleReplaySink()
+		}> */
+		func() bool {
+			{
+				add(ruleAction27, position)
+			}
+			return true
+		},
+		/* 283 Action28 <- <{
+		    p.AssembleDropState()
+		}> */
+		func() bool {
+			{
+				add(ruleAction28, position)
+			}
+			return true
+		},
+		/* 284 Action29 <- <{
+		    p.AssembleCreateType(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction29, position)
+			}
+			return true
+		},
+		/* 285 Action30 <- <{
+		    p.AssembleTypeField()
+		}> */
+		func() bool {
+			{
+				add(ruleAction30, position)
 			}
 			return true
-		l1905:
-			position, tokenIndex = position1905, tokenIndex1905
-			return false
 		},
-		/* 175 jsonMapAccessString <- <<(([a-z] / [A-Z]) ([a-z] / [A-Z] / [0-9] / '_')*)>> */
+		/* 286 Action31 <- <{
+		    p.AssembleDropType()
+		}> */
 		func() bool {
-			position1909, tokenIndex1909 := position, tokenIndex
 			{
-				position1910 := position
-				{
-					position1911 := position
-					{
-						position1912, tokenIndex1912 := position, tokenIndex
-						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l1913
-						}
-						position++
-						goto l1912
-					l1913:
-						position, tokenIndex = position1912, tokenIndex1912
-						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l1909
-						}
-						position++
-					}
-				l1912:
-				l1914:
-					{
-						position1915, tokenIndex1915 := position, tokenIndex
-						{
-							position1916, tokenIndex1916 := position, tokenIndex
-							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l1917
-							}
-							position++
-							goto l1916
-						l1917:
-							position, tokenIndex = position1916, tokenIndex1916
-							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l1918
-							}
-							position++
-							goto l1916
-						l1918:
-							position, tokenIndex = position1916, tokenIndex1916
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l1919
-							}
-							position++
-							goto l1916
-						l1919:
-							position, tokenIndex = position1916, tokenIndex1916
-							if buffer[position] != rune('_') {
-								goto l1915
-							}
-							position++
-						}
-					l1916:
-						goto l1914
-					l1915:
-						position, tokenIndex = position1915, tokenIndex1915
-					}
-					add(rulePegText, position1911)
-				}
-				add(rulejsonMapAccessString, position1910)
+				add(ruleAction31, position)
 			}
 			return true
-		l1909:
-			position, tokenIndex = position1909, tokenIndex1909
-			return false
 		},
-		/* 176 jsonMapAccessBracket <- <('[' doubleQuotedString ']')> */
+		/* 287 Action32 <- <{
+		    p.AssembleCreateTrigger()
+		}> */
 		func() bool {
-			position1920, tokenIndex1920 := position, tokenIndex
 			{
-				position1921 := position
-				if buffer[position] != rune('[') {
-					goto l1920
-				}
-				position++
-				if !_rules[ruledoubleQuotedString]() {
-					goto l1920
-				}
-				if buffer[position] != rune(']') {
-					goto l1920
-				}
-				position++
-				add(rulejsonMapAccessBracket, position1921)
+				add(ruleAction32, position)
 			}
 			return true
-		l1920:
-			position, tokenIndex = position1920, tokenIndex1920
-			return false
 		},
-		/* 177 doubleQuotedString <- <('"' <(('"' '"') / (!'"' .))*> '"')> */
+		/* 288 Action33 <- <{
+		    p.AssembleTriggerInsert()
+		}> */
 		func() bool {
-			position1922, tokenIndex1922 := position, tokenIndex
 			{
-				position1923 := position
-				if buffer[position] != rune('"') {
-					goto l1922
-				}
-				position++
-				{
-					position1924 := position
-				l1925:
-					{
-						position1926, tokenIndex1926 := position, tokenIndex
-						{
-							position1927, tokenIndex1927 := position, tokenIndex
-							if buffer[position] != rune('"') {
-								goto l1928
-							}
-							position++
-							if buffer[position] != rune('"') {
-								goto l1928
-							}
-							position++
-							goto l1927
-						l1928:
-							position, tokenIndex = position1927, tokenIndex1927
-							{
-								position1929, tokenIndex1929 := position, tokenIndex
-								if buffer[position] != rune('"') {
-									goto l1929
-								}
-								position++
-								goto l1926
-							l1929:
-								position, tokenIndex = position1929, tokenIndex1929
-							}
-							if !matchDot() {
-								goto l1926
-							}
-						}
-					l1927:
-						goto l1925
-					l1926:
-						position, tokenIndex = position1926, tokenIndex1926
-					}
-					add(rulePegText, position1924)
-				}
-				if buffer[position] != rune('"') {
-					goto l1922
-				}
-				position++
-				add(ruledoubleQuotedString, position1923)
+				add(ruleAction33, position)
 			}
 			return true
-		l1922:
-			position, tokenIndex = position1922, tokenIndex1922
-			return false
 		},
-		/* 178 jsonArrayAccess <- <('[' <('-'? [0-9]+)> ']')> */
+		/* 289 Action34 <- <{
+		    p.AssembleDropTrigger()
+		}> */
 		func() bool {
-			position1930, tokenIndex1930 := position, tokenIndex
 			{
-				position1931 := position
-				if buffer[position] != rune('[') {
-					goto l1930
-				}
-				position++
-				{
-					position1932 := position
-					{
-						position1933, tokenIndex1933 := position, tokenIndex
-						if buffer[position] != rune('-') {
-							goto l1933
-						}
-						position++
-						goto l1934
-					l1933:
-						position, tokenIndex = position1933, tokenIndex1933
-					}
-				l1934:
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1930
-					}
-					position++
-				l1935:
-					{
-						position1936, tokenIndex1936 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1936
-						}
-						position++
-						goto l1935
-					l1936:
-						position, tokenIndex = position1936, tokenIndex1936
-					}
-					add(rulePegText, position1932)
-				}
-				if buffer[position] != rune(']') {
-					goto l1930
-				}
-				position++
-				add(rulejsonArrayAccess, position1931)
+				add(ruleAction34, position)
 			}
 			return true
-		l1930:
-			position, tokenIndex = position1930, tokenIndex1930
-			return false
 		},
-		/* 179 jsonNonNegativeArrayAccess <- <('[' <[0-9]+> ']')> */
+		/* 290 Action35 <- <{
+		    p.AssembleCreateAlert()
+		}> */
 		func() bool {
-			position1937, tokenIndex1937 := position, tokenIndex
 			{
-				position1938 := position
-				if buffer[position] != rune('[') {
-					goto l1937
-				}
-				position++
-				{
-					position1939 := position
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1937
-					}
-					position++
-				l1940:
-					{
-						position1941, tokenIndex1941 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1941
-						}
-						position++
-						goto l1940
-					l1941:
-						position, tokenIndex = position1941, tokenIndex1941
-					}
-					add(rulePegText, position1939)
-				}
-				if buffer[position] != rune(']') {
-					goto l1937
-				}
-				position++
-				add(rulejsonNonNegativeArrayAccess, position1938)
+				add(ruleAction35, position)
+			}
+			return true
+		},
+		/* 291 Action36 <- <{
+		    p.AssembleDropAlert()
+		}> */
+		func() bool {
+			{
+				add(ruleAction36, position)
 			}
 			return true
-		l1937:
-			position, tokenIndex = position1937, tokenIndex1937
-			return false
 		},
-		/* 180 jsonArraySlice <- <('[' <('-'? [0-9]+ ':' '-'? [0-9]+ (':' '-'? [0-9]+)?)> ']')> */
+		/* 292 Action37 <- <{
+		    p.AssembleCreateTemplate(begin, end)
+		}> */
 		func() bool {
-			position1942, tokenIndex1942 := position, tokenIndex
 			{
-				position1943 := position
-				if buffer[position] != rune('[') {
-					goto l1942
-				}
-				position++
-				{
-					position1944 := position
-					{
-						position1945, tokenIndex1945 := position, tokenIndex
-						if buffer[position] != rune('-') {
-							goto l1945
-						}
-						position++
-						goto l1946
-					l1945:
-						position, tokenIndex = position1945, tokenIndex1945
-					}
-				l1946:
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1942
-					}
-					position++
-				l1947:
-					{
-						position1948, tokenIndex1948 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1948
-						}
-						position++
-						goto l1947
-					l1948:
-						position, tokenIndex = position1948, tokenIndex1948
-					}
-					if buffer[position] != rune(':') {
-						goto l1942
-					}
-					position++
-					{
-						position1949, tokenIndex1949 := position, tokenIndex
-						if buffer[position] != rune('-') {
-							goto l1949
-						}
-						position++
-						goto l1950
-					l1949:
-						position, tokenIndex = position1949, tokenIndex1949
-					}
-				l1950:
-					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l1942
-					}
-					position++
-				l1951:
-					{
-						position1952, tokenIndex1952 := position, tokenIndex
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1952
-						}
-						position++
-						goto l1951
-					l1952:
-						position, tokenIndex = position1952, tokenIndex1952
-					}
-					{
-						position1953, tokenIndex1953 := position, tokenIndex
-						if buffer[position] != rune(':') {
-							goto l1953
-						}
-						position++
-						{
-							position1955, tokenIndex1955 := position, tokenIndex
-							if buffer[position] != rune('-') {
-								goto l1955
-							}
-							position++
-							goto l1956
-						l1955:
-							position, tokenIndex = position1955, tokenIndex1955
-						}
-					l1956:
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1953
-						}
-						position++
-					l1957:
-						{
-							position1958, tokenIndex1958 := position, tokenIndex
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l1958
-							}
-							position++
-							goto l1957
-						l1958:
-							position, tokenIndex = position1958, tokenIndex1958
-						}
-						goto l1954
-					l1953:
-						position, tokenIndex = position1953, tokenIndex1953
-					}
-				l1954:
-					add(rulePegText, position1944)
-				}
-				if buffer[position] != rune(']') {
-					goto l1942
-				}
-				position++
-				add(rulejsonArraySlice, position1943)
+				add(ruleAction37, position)
 			}
 			return true
-		l1942:
-			position, tokenIndex = position1942, tokenIndex1942
-			return false
 		},
-		/* 181 jsonArrayPartialSlice <- <('[' <((':' '-'? [0-9]+) / ('-'? [0-9]+ ':'))> ']')> */
+		/* 293 Action38 <- <{
+		    substr := string([]rune(buffer)[begin:end])
+		    p.PushComponent(begin, end, StringLiteral{substr})
+		}> */
 		func() bool {
-			position1959, tokenIndex1959 := position, tokenIndex
 			{
-				position1960 := position
-				if buffer[position] != rune('[') {
-					goto l1959
-				}
-				position++
-				{
-					position1961 := position
-					{
-						position1962, tokenIndex1962 := position, tokenIndex
-						if buffer[position] != rune(':') {
-							goto l1963
-						}
-						position++
-						{
-							position1964, tokenIndex1964 := position, tokenIndex
-							if buffer[position] != rune('-') {
-								goto l1964
-							}
-							position++
-							goto l1965
-						l1964:
-							position, tokenIndex = position1964, tokenIndex1964
-						}
-					l1965:
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1963
-						}
-						position++
-					l1966:
-						{
-							position1967, tokenIndex1967 := position, tokenIndex
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l1967
-							}
-							position++
-							goto l1966
-						l1967:
-							position, tokenIndex = position1967, tokenIndex1967
-						}
-						goto l1962
-					l1963:
-						position, tokenIndex = position1962, tokenIndex1962
-						{
-							position1968, tokenIndex1968 := position, tokenIndex
-							if buffer[position] != rune('-') {
-								goto l1968
-							}
-							position++
-							goto l1969
-						l1968:
-							position, tokenIndex = position1968, tokenIndex1968
-						}
-					l1969:
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l1959
-						}
-						position++
-					l1970:
-						{
-							position1971, tokenIndex1971 := position, tokenIndex
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l1971
-							}
-							position++
-							goto l1970
-						l1971:
-							position, tokenIndex = position1971, tokenIndex1971
-						}
-						if buffer[position] != rune(':') {
-							goto l1959
-						}
-						position++
-					}
-				l1962:
-					add(rulePegText, position1961)
-				}
-				if buffer[position] != rune(']') {
-					goto l1959
-				}
-				position++
-				add(rulejsonArrayPartialSlice, position1960)
+				add(ruleAction38, position)
 			}
 			return true
-		l1959:
-			position, tokenIndex = position1959, tokenIndex1959
-			return false
 		},
-		/* 182 jsonArrayFullSlice <- <('[' ':' ']')> */
+		/* 294 Action39 <- <{
+		    p.AssembleInstantiateTemplate()
+		}> */
 		func() bool {
-			position1972, tokenIndex1972 := position, tokenIndex
 			{
-				position1973 := position
-				if buffer[position] != rune('[') {
-					goto l1972
-				}
-				position++
-				if buffer[position] != rune(':') {
-					goto l1972
-				}
-				position++
-				if buffer[position] != rune(']') {
-					goto l1972
-				}
-				position++
-				add(rulejsonArrayFullSlice, position1973)
+				add(ruleAction39, position)
 			}
 			return true
-		l1972:
-			position, tokenIndex = position1972, tokenIndex1972
-			return false
 		},
-		/* 183 spElem <- <(' ' / '\t' / '\n' / '\r' / comment / finalComment)> */
+		/* 295 Action40 <- <{
+		    p.EnsureIdentifier(begin, end)
+		}> */
 		func() bool {
-			position1974, tokenIndex1974 := position, tokenIndex
 			{
-				position1975 := position
-				{
-					position1976, tokenIndex1976 := position, tokenIndex
-					if buffer[position] != rune(' ') {
-						goto l1977
-					}
-					position++
-					goto l1976
-				l1977:
-					position, tokenIndex = position1976, tokenIndex1976
-					if buffer[position] != rune('\t') {
-						goto l1978
-					}
-					position++
-					goto l1976
-				l1978:
-					position, tokenIndex = position1976, tokenIndex1976
-					if buffer[position] != rune('\n') {
-						goto l1979
-					}
-					position++
-					goto l1976
-				l1979:
-					position, tokenIndex = position1976, tokenIndex1976
-					if buffer[position] != rune('\r') {
-						goto l1980
-					}
-					position++
-					goto l1976
-				l1980:
-					position, tokenIndex = position1976, tokenIndex1976
-					if !_rules[rulecomment]() {
-						goto l1981
-					}
-					goto l1976
-				l1981:
-					position, tokenIndex = position1976, tokenIndex1976
-					if !_rules[rulefinalComment]() {
-						goto l1974
-					}
-				}
-			l1976:
-				add(rulespElem, position1975)
+				add(ruleAction40, position)
 			}
 			return true
-		l1974:
-			position, tokenIndex = position1974, tokenIndex1974
-			return false
 		},
-		/* 184 sp <- <spElem+> */
+		/* 296 Action41 <- <{
+		    p.AssembleDropTemplateInstance()
+		}> */
 		func() bool {
-			position1982, tokenIndex1982 := position, tokenIndex
 			{
-				position1983 := position
-				if !_rules[rulespElem]() {
-					goto l1982
-				}
-			l1984:
-				{
-					position1985, tokenIndex1985 := position, tokenIndex
-					if !_rules[rulespElem]() {
-						goto l1985
-					}
-					goto l1984
-				l1985:
-					position, tokenIndex = position1985, tokenIndex1985
-				}
-				add(rulesp, position1983)
+				add(ruleAction41, position)
 			}
 			return true
-		l1982:
-			position, tokenIndex = position1982, tokenIndex1982
-			return false
 		},
-		/* 185 spOpt <- <spElem*> */
+		/* 297 Action42 <- <{
+		    p.AssembleDropTemplate()
+		}> */
 		func() bool {
 			{
-				position1987 := position
-			l1988:
-				{
-					position1989, tokenIndex1989 := position, tokenIndex
-					if !_rules[rulespElem]() {
-						goto l1989
-					}
-					goto l1988
-				l1989:
-					position, tokenIndex = position1989, tokenIndex1989
-				}
-				add(rulespOpt, position1987)
+				add(ruleAction42, position)
 			}
 			return true
 		},
-		/* 186 comment <- <('-' '-' (!('\r' / '\n') .)* ('\r' / '\n'))> */
+		/* 298 Action43 <- <{
+		    p.AssembleLoadState()
+		}> */
 		func() bool {
-			position1990, tokenIndex1990 := position, tokenIndex
 			{
-				position1991 := position
-				if buffer[position] != rune('-') {
-					goto l1990
-				}
-				position++
-				if buffer[position] != rune('-') {
-					goto l1990
-				}
-				position++
-			l1992:
-				{
-					position1993, tokenIndex1993 := position, tokenIndex
-					{
-						position1994, tokenIndex1994 := position, tokenIndex
-						{
-							position1995, tokenIndex1995 := position, tokenIndex
-							if buffer[position] != rune('\r') {
-								goto l1996
-							}
-							position++
-							goto l1995
-						l1996:
-							position, tokenIndex = position1995, tokenIndex1995
-							if buffer[position] != rune('\n') {
-								goto l1994
-							}
-							position++
-						}
-					l1995:
-						goto l1993
-					l1994:
-						position, tokenIndex = position1994, tokenIndex1994
-					}
-					if !matchDot() {
-						goto l1993
-					}
-					goto l1992
-				l1993:
-					position, tokenIndex = position1993, tokenIndex1993
-				}
-				{
-					position1997, tokenIndex1997 := position, tokenIndex
-					if buffer[position] != rune('\r') {
-						goto l1998
-					}
-					position++
-					goto l1997
-				l1998:
-					position, tokenIndex = position1997, tokenIndex1997
-					if buffer[position] != rune('\n') {
-						goto l1990
-					}
-					position++
-				}
-			l1997:
-				add(rulecomment, position1991)
+				add(ruleAction43, position)
 			}
 			return true
-		l1990:
-			position, tokenIndex = position1990, tokenIndex1990
-			return false
 		},
-		/* 187 finalComment <- <('-' '-' (!('\r' / '\n') .)* !.)> */
+		/* 299 Action44 <- <{
+		    p.AssembleLoadStateOrCreate()
+		}> */
 		func() bool {
-			position1999, tokenIndex1999 := position, tokenIndex
 			{
-				position2000 := position
-				if buffer[position] != rune('-') {
-					goto l1999
-				}
-				position++
-				if buffer[position] != rune('-') {
-					goto l1999
-				}
-				position++
-			l2001:
-				{
-					position2002, tokenIndex2002 := position, tokenIndex
-					{
-						position2003, tokenIndex2003 := position, tokenIndex
-						{
-							position2004, tokenIndex2004 := position, tokenIndex
-							if buffer[position] != rune('\r') {
-								goto l2005
-							}
-							position++
-							goto l2004
-						l2005:
-							position, tokenIndex = position2004, tokenIndex2004
-							if buffer[position] != rune('\n') {
-								goto l2003
-							}
-							position++
-						}
-					l2004:
-						goto l2002
-					l2003:
-						position, tokenIndex = position2003, tokenIndex2003
-					}
-					if !matchDot() {
-						goto l2002
-					}
-					goto l2001
-				l2002:
-					position, tokenIndex = position2002, tokenIndex2002
-				}
-				{
-					position2006, tokenIndex2006 := position, tokenIndex
-					if !matchDot() {
-						goto l2006
-					}
-					goto l1999
-				l2006:
-					position, tokenIndex = position2006, tokenIndex2006
-				}
-				add(rulefinalComment, position2000)
+				add(ruleAction44, position)
 			}
 			return true
-		l1999:
-			position, tokenIndex = position1999, tokenIndex1999
-			return false
 		},
-		nil,
-		/* 190 Action0 <- <{
-		    p.IncludeTrailingWhitespace(begin, end)
+		/* 300 Action45 <- <{
+		    p.AssembleSaveState()
 		}> */
 		func() bool {
 			{
-				add(ruleAction0, position)
+				add(ruleAction45, position)
 			}
 			return true
 		},
-		/* 191 Action1 <- <{
-		    p.IncludeTrailingWhitespace(begin, end)
+		/* 301 Action46 <- <{
+		    p.AssembleSetConfig()
 		}> */
 		func() bool {
 			{
-				add(ruleAction1, position)
+				add(ruleAction46, position)
 			}
 			return true
 		},
-		/* 192 Action2 <- <{
-		    p.AssembleSelect()
+		/* 302 Action47 <- <{
+		    p.AssembleEval(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction2, position)
+				add(ruleAction47, position)
 			}
 			return true
 		},
-		/* 193 Action3 <- <{
-		    p.AssembleSelectUnion(begin, end)
+		/* 303 Action48 <- <{
+		    p.AssembleDescribe()
 		}> */
 		func() bool {
 			{
-				add(ruleAction3, position)
+				add(ruleAction48, position)
 			}
 			return true
 		},
-		/* 194 Action4 <- <{
-		    p.AssembleCreateStreamAsSelect()
+		/* 304 Action49 <- <{
+		    p.AssembleEmitter()
 		}> */
 		func() bool {
 			{
-				add(ruleAction4, position)
+				add(ruleAction49, position)
 			}
 			return true
 		},
-		/* 195 Action5 <- <{
-		    p.AssembleCreateStreamAsSelectUnion()
+		/* 305 Action50 <- <{
+		    p.AssembleImplicitEmitter(begin)
 		}> */
 		func() bool {
 			{
-				add(ruleAction5, position)
+				add(ruleAction50, position)
 			}
 			return true
 		},
-		/* 196 Action6 <- <{
-		    p.AssembleCreateSource()
+		/* 306 Action51 <- <{
+		    p.AssembleEmitterOptions(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction6, position)
+				add(ruleAction51, position)
 			}
 			return true
 		},
-		/* 197 Action7 <- <{
-		    p.AssembleCreateSink()
+		/* 307 Action52 <- <{
+		    p.AssembleEmitterLimit()
 		}> */
 		func() bool {
 			{
-				add(ruleAction7, position)
+				add(ruleAction52, position)
 			}
 			return true
 		},
-		/* 198 Action8 <- <{
-		    p.AssembleCreateState()
+		/* 308 Action53 <- <{
+		    p.AssembleEmitterSampling(CountBasedSampling, 1)
 		}> */
 		func() bool {
 			{
-				add(ruleAction8, position)
+				add(ruleAction53, position)
 			}
 			return true
 		},
-		/* 199 Action9 <- <{
-		    p.AssembleUpdateState()
+		/* 309 Action54 <- <{
+		    p.AssembleEmitterSampling(RandomizedSampling, 1)
 		}> */
 		func() bool {
 			{
-				add(ruleAction9, position)
+				add(ruleAction54, position)
 			}
 			return true
 		},
-		/* 200 Action10 <- <{
-		    p.AssembleUpdateSource()
+		/* 310 Action55 <- <{
+		    p.AssembleEmitterSampling(TimeBasedSampling, 1)
 		}> */
 		func() bool {
 			{
-				add(ruleAction10, position)
+				add(ruleAction55, position)
 			}
 			return true
 		},
-		/* 201 Action11 <- <{
-		    p.AssembleUpdateSink()
+		/* 311 Action56 <- <{
+		    p.AssembleEmitterSampling(TimeBasedSampling, 0.001)
 		}> */
 		func() bool {
 			{
-				add(ruleAction11, position)
+				add(ruleAction56, position)
 			}
 			return true
 		},
-		/* 202 Action12 <- <{
-		    p.AssembleInsertIntoFrom()
+		/* 312 Action57 <- <{
+		    p.AssembleProjections(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction12, position)
+				add(ruleAction57, position)
 			}
 			return true
 		},
-		/* 203 Action13 <- <{
-		    p.AssemblePauseSource()
+		/* 313 Action58 <- <{
+		    p.AssembleAlias()
 		}> */
 		func() bool {
 			{
-				add(ruleAction13, position)
+				add(ruleAction58, position)
 			}
 			return true
 		},
-		/* 204 Action14 <- <{
-		    p.AssembleResumeSource()
+		/* 314 Action59 <- <{
+		    // This is *always* executed, even if there is no
+		    // FROM clause present in the statement.
+		    p.AssembleWindowedFrom(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction14, position)
+				add(ruleAction59, position)
 			}
 			return true
 		},
-		/* 205 Action15 <- <{
-		    p.AssembleRewindSource()
+		/* 315 Action60 <- <{
+		    // This is *always* executed, even if there is no
+		    // READ clause present in the statement.
+		    p.AssembleInputSampling(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction15, position)
+				add(ruleAction60, position)
 			}
 			return true
 		},
-		/* 206 Action16 <- <{
-		    p.AssembleDropSource()
+		/* 316 Action61 <- <{
+		    p.AssembleInterval()
 		}> */
 		func() bool {
 			{
-				add(ruleAction16, position)
+				add(ruleAction61, position)
 			}
 			return true
 		},
-		/* 207 Action17 <- <{
-		    p.AssembleDropStream()
+		/* 317 Action62 <- <{
+		    p.AssembleInterval()
 		}> */
 		func() bool {
 			{
-				add(ruleAction17, position)
+				add(ruleAction62, position)
 			}
 			return true
 		},
-		/* 208 Action18 <- <{
-		    p.AssembleDropSink()
+		/* 318 Action63 <- <{
+		    // This is *always* executed, even if there is no
+		    // WHERE clause present in the statement.
+		    p.AssembleFilter(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction18, position)
+				add(ruleAction63, position)
 			}
 			return true
 		},
-		/* 209 Action19 <- <{
-		    p.AssembleDropState()
+		/* 319 Action64 <- <{
+		    // This is *always* executed, even if there is no
+		    // GROUP BY clause present in the statement.
+		    p.AssembleGrouping(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction19, position)
+				add(ruleAction64, position)
 			}
 			return true
 		},
-		/* 210 Action20 <- <{
-		    p.AssembleLoadState()
+		/* 320 Action65 <- <{
+		    p.AssembleRollup(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction20, position)
+				add(ruleAction65, position)
 			}
 			return true
 		},
-		/* 211 Action21 <- <{
-		    p.AssembleLoadStateOrCreate()
+		/* 321 Action66 <- <{
+		    p.AssembleGroupingSets(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction21, position)
+				add(ruleAction66, position)
 			}
 			return true
 		},
-		/* 212 Action22 <- <{
-		    p.AssembleSaveState()
+		/* 322 Action67 <- <{
+		    p.AssembleExpressions(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction22, position)
+				add(ruleAction67, position)
 			}
 			return true
 		},
-		/* 213 Action23 <- <{
-		    p.AssembleEval(begin, end)
+		/* 323 Action68 <- <{
+		    // This is *always* executed, even if there is no
+		    // HAVING clause present in the statement.
+		    p.AssembleHaving(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction23, position)
+				add(ruleAction68, position)
 			}
 			return true
 		},
-		/* 214 Action24 <- <{
-		    p.AssembleEmitter()
+		/* 324 Action69 <- <{
+		    // This is *always* executed, even if there is no
+		    // EMIT WHEN clause present in the statement.
+		    p.AssembleEmitWhen(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction24, position)
+				add(ruleAction69, position)
 			}
 			return true
 		},
-		/* 215 Action25 <- <{
-		    p.AssembleEmitterOptions(begin, end)
+		/* 325 Action70 <- <{
+		    p.AssembleStateJoin(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction25, position)
+				add(ruleAction70, position)
 			}
 			return true
 		},
-		/* 216 Action26 <- <{
-		    p.AssembleEmitterLimit()
+		/* 326 Action71 <- <{
+		    p.EnsureAliasedStreamWindow()
 		}> */
 		func() bool {
 			{
-				add(ruleAction26, position)
+				add(ruleAction71, position)
 			}
 			return true
 		},
-		/* 217 Action27 <- <{
-		    p.AssembleEmitterSampling(CountBasedSampling, 1)
+		/* 327 Action72 <- <{
+		    p.AssembleAliasedStreamWindow()
 		}> */
 		func() bool {
 			{
-				add(ruleAction27, position)
+				add(ruleAction72, position)
 			}
 			return true
 		},
-		/* 218 Action28 <- <{
-		    p.AssembleEmitterSampling(RandomizedSampling, 1)
+		/* 328 Action73 <- <{
+		    p.AssembleStreamWindow()
 		}> */
 		func() bool {
 			{
-				add(ruleAction28, position)
+				add(ruleAction73, position)
 			}
 			return true
 		},
-		/* 219 Action29 <- <{
-		    p.AssembleEmitterSampling(TimeBasedSampling, 1)
+		/* 329 Action74 <- <{
+		    p.AssembleImplicitStreamWindow()
 		}> */
 		func() bool {
 			{
-				add(ruleAction29, position)
+				add(ruleAction74, position)
+			}
+			return true
+		},
+		/* 330 Action75 <- <{
+		    p.PushComponent(end, end, TumblingAST{})
+		}> */
+		func() bool {
+			{
+				add(ruleAction75, position)
+			}
+			return true
+		},
+		/* 331 Action76 <- <{
+		    p.AssembleTumblingWindow(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction76, position)
+			}
+			return true
+		},
+		/* 332 Action77 <- <{
+		    p.PushComponent(begin, end, TumblingAST{Tumbling: true, ProcessingTime: true})
+		}> */
+		func() bool {
+			{
+				add(ruleAction77, position)
 			}
 			return true
 		},
-		/* 220 Action30 <- <{
-		    p.AssembleEmitterSampling(TimeBasedSampling, 0.001)
+		/* 333 Action78 <- <{
+		    p.AssembleSessionWindow(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction30, position)
+				add(ruleAction78, position)
 			}
 			return true
 		},
-		/* 221 Action31 <- <{
-		    p.AssembleProjections(begin, end)
+		/* 334 Action79 <- <{
+		    p.AssembleWatermarkDelay(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction31, position)
+				add(ruleAction79, position)
 			}
 			return true
 		},
-		/* 222 Action32 <- <{
-		    p.AssembleAlias()
+		/* 335 Action80 <- <{
+		    p.AssembleUnnestStream(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction32, position)
+				add(ruleAction80, position)
 			}
 			return true
 		},
-		/* 223 Action33 <- <{
-		    // This is *always* executed, even if there is no
-		    // FROM clause present in the statement.
-		    p.AssembleWindowedFrom(begin, end)
+		/* 336 Action81 <- <{
+		    substr := string([]rune(buffer)[begin:end])
+		    p.PushComponent(begin, end, Stream{SystemStream, substr, nil})
 		}> */
 		func() bool {
 			{
-				add(ruleAction33, position)
+				add(ruleAction81, position)
 			}
 			return true
 		},
-		/* 224 Action34 <- <{
-		    p.AssembleInterval()
+		/* 337 Action82 <- <{
+		    p.AssembleUDSFFuncApp()
 		}> */
 		func() bool {
 			{
-				add(ruleAction34, position)
+				add(ruleAction82, position)
 			}
 			return true
 		},
-		/* 225 Action35 <- <{
-		    p.AssembleInterval()
+		/* 338 Action83 <- <{
+		    p.EnsureCapacitySpec(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction35, position)
+				add(ruleAction83, position)
 			}
 			return true
 		},
-		/* 226 Action36 <- <{
-		    // This is *always* executed, even if there is no
-		    // WHERE clause present in the statement.
-		    p.AssembleFilter(begin, end)
+		/* 339 Action84 <- <{
+		    p.EnsureSheddingSpec(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction36, position)
+				add(ruleAction84, position)
 			}
 			return true
 		},
-		/* 227 Action37 <- <{
-		    // This is *always* executed, even if there is no
-		    // GROUP BY clause present in the statement.
-		    p.AssembleGrouping(begin, end)
+		/* 340 Action85 <- <{
+		    p.AssembleSchema(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction37, position)
+				add(ruleAction85, position)
 			}
 			return true
 		},
-		/* 228 Action38 <- <{
-		    // This is *always* executed, even if there is no
-		    // HAVING clause present in the statement.
-		    p.AssembleHaving(begin, end)
+		/* 341 Action86 <- <{
+		    p.AssembleInstances(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction38, position)
+				add(ruleAction86, position)
 			}
 			return true
 		},
-		/* 229 Action39 <- <{
-		    p.EnsureAliasedStreamWindow()
+		/* 342 Action87 <- <{
+		    p.AssembleSinkOrdering(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction39, position)
+				add(ruleAction87, position)
 			}
 			return true
 		},
-		/* 230 Action40 <- <{
-		    p.AssembleAliasedStreamWindow()
+		/* 343 Action88 <- <{
+		    p.AssembleTimestampBy(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction40, position)
+				add(ruleAction88, position)
 			}
 			return true
 		},
-		/* 231 Action41 <- <{
-		    p.AssembleStreamWindow()
+		/* 344 Action89 <- <{
+		    substr := string([]rune(buffer)[begin:end])
+		    p.PushComponent(begin, end, Raw{substr})
 		}> */
 		func() bool {
 			{
-				add(ruleAction41, position)
+				add(ruleAction89, position)
 			}
 			return true
 		},
-		/* 232 Action42 <- <{
-		    p.AssembleUDSFFuncApp()
+		/* 345 Action90 <- <{
+		    p.AssembleOnError(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction42, position)
+				add(ruleAction90, position)
 			}
 			return true
 		},
-		/* 233 Action43 <- <{
-		    p.EnsureCapacitySpec(begin, end)
+		/* 346 Action91 <- <{
+		    p.AssembleTimeout(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction43, position)
+				add(ruleAction91, position)
 			}
 			return true
 		},
-		/* 234 Action44 <- <{
-		    p.EnsureSheddingSpec(begin, end)
+		/* 347 Action92 <- <{
+		    p.AssembleSourceSinkSpecs(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction44, position)
+				add(ruleAction92, position)
 			}
 			return true
 		},
-		/* 235 Action45 <- <{
+		/* 348 Action93 <- <{
 		    p.AssembleSourceSinkSpecs(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction45, position)
+				add(ruleAction93, position)
 			}
 			return true
 		},
-		/* 236 Action46 <- <{
+		/* 349 Action94 <- <{
 		    p.AssembleSourceSinkSpecs(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction46, position)
+				add(ruleAction94, position)
 			}
 			return true
 		},
-		/* 237 Action47 <- <{
+		/* 350 Action95 <- <{
 		    p.AssembleSourceSinkSpecs(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction47, position)
+				add(ruleAction95, position)
 			}
 			return true
 		},
-		/* 238 Action48 <- <{
+		/* 351 Action96 <- <{
 		    p.EnsureIdentifier(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction48, position)
+				add(ruleAction96, position)
 			}
 			return true
 		},
-		/* 239 Action49 <- <{
+		/* 352 Action97 <- <{
 		    p.AssembleSourceSinkParam()
 		}> */
 		func() bool {
 			{
-				add(ruleAction49, position)
+				add(ruleAction97, position)
 			}
 			return true
 		},
-		/* 240 Action50 <- <{
+		/* 353 Action98 <- <{
 		    p.AssembleExpressions(begin, end)
 		    p.AssembleArray()
 		}> */
 		func() bool {
 			{
-				add(ruleAction50, position)
+				add(ruleAction98, position)
 			}
 			return true
 		},
-		/* 241 Action51 <- <{
+		/* 354 Action99 <- <{
 		    p.AssembleMap(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction51, position)
+				add(ruleAction99, position)
 			}
 			return true
 		},
-		/* 242 Action52 <- <{
+		/* 355 Action100 <- <{
 		    p.AssembleKeyValuePair()
 		}> */
 		func() bool {
 			{
-				add(ruleAction52, position)
+				add(ruleAction100, position)
 			}
 			return true
 		},
-		/* 243 Action53 <- <{
+		/* 356 Action101 <- <{
 		    p.EnsureKeywordPresent(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction53, position)
+				add(ruleAction101, position)
+			}
+			return true
+		},
+		/* 357 Action102 <- <{
+		    p.EnsureKeywordPresent(begin, end)
+		}> */
+		func() bool {
+			{
+				add(ruleAction102, position)
 			}
 			return true
 		},
-		/* 244 Action54 <- <{
+		/* 358 Action103 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction54, position)
+				add(ruleAction103, position)
 			}
 			return true
 		},
-		/* 245 Action55 <- <{
+		/* 359 Action104 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction55, position)
+				add(ruleAction104, position)
 			}
 			return true
 		},
-		/* 246 Action56 <- <{
+		/* 360 Action105 <- <{
 		    p.AssembleUnaryPrefixOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction56, position)
+				add(ruleAction105, position)
 			}
 			return true
 		},
-		/* 247 Action57 <- <{
+		/* 361 Action106 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction57, position)
+				add(ruleAction106, position)
 			}
 			return true
 		},
-		/* 248 Action58 <- <{
+		/* 362 Action107 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction58, position)
+				add(ruleAction107, position)
 			}
 			return true
 		},
-		/* 249 Action59 <- <{
+		/* 363 Action108 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction59, position)
+				add(ruleAction108, position)
 			}
 			return true
 		},
-		/* 250 Action60 <- <{
+		/* 364 Action109 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction60, position)
+				add(ruleAction109, position)
 			}
 			return true
 		},
-		/* 251 Action61 <- <{
+		/* 365 Action110 <- <{
 		    p.AssembleBinaryOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction61, position)
+				add(ruleAction110, position)
 			}
 			return true
 		},
-		/* 252 Action62 <- <{
+		/* 366 Action111 <- <{
 		    p.AssembleUnaryPrefixOperation(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction62, position)
+				add(ruleAction111, position)
 			}
 			return true
 		},
-		/* 253 Action63 <- <{
+		/* 367 Action112 <- <{
 		    p.AssembleTypeCast(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction63, position)
+				add(ruleAction112, position)
 			}
 			return true
 		},
-		/* 254 Action64 <- <{
+		/* 368 Action113 <- <{
 		    p.AssembleTypeCast(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction64, position)
+				add(ruleAction113, position)
 			}
 			return true
 		},
-		/* 255 Action65 <- <{
+		/* 369 Action114 <- <{
 		    p.AssembleFuncAppSelector()
 		}> */
 		func() bool {
 			{
-				add(ruleAction65, position)
+				add(ruleAction114, position)
 			}
 			return true
 		},
-		/* 256 Action66 <- <{
+		/* 370 Action115 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewRaw(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction66, position)
+				add(ruleAction115, position)
 			}
 			return true
 		},
-		/* 257 Action67 <- <{
+		/* 371 Action116 <- <{
 		    p.AssembleFuncApp()
 		}> */
 		func() bool {
 			{
-				add(ruleAction67, position)
+				add(ruleAction116, position)
 			}
 			return true
 		},
-		/* 258 Action68 <- <{
+		/* 372 Action117 <- <{
 		    p.AssembleExpressions(begin, end)
 		    p.AssembleFuncApp()
 		}> */
 		func() bool {
 			{
-				add(ruleAction68, position)
+				add(ruleAction117, position)
 			}
 			return true
 		},
-		/* 259 Action69 <- <{
+		/* 373 Action118 <- <{
 		    p.AssembleExpressions(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction69, position)
+				add(ruleAction118, position)
 			}
 			return true
 		},
-		/* 260 Action70 <- <{
+		/* 374 Action119 <- <{
 		    p.AssembleExpressions(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction70, position)
+				add(ruleAction119, position)
 			}
 			return true
 		},
-		/* 261 Action71 <- <{
+		/* 375 Action120 <- <{
 		    p.AssembleSortedExpression()
 		}> */
 		func() bool {
 			{
-				add(ruleAction71, position)
+				add(ruleAction120, position)
 			}
 			return true
 		},
-		/* 262 Action72 <- <{
+		/* 376 Action121 <- <{
 		    p.EnsureKeywordPresent(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction72, position)
+				add(ruleAction121, position)
 			}
 			return true
 		},
-		/* 263 Action73 <- <{
+		/* 377 Action122 <- <{
 		    p.AssembleExpressions(begin, end)
 		    p.AssembleArray()
 		}> */
 		func() bool {
 			{
-				add(ruleAction73, position)
+				add(ruleAction122, position)
 			}
 			return true
 		},
-		/* 264 Action74 <- <{
+		/* 378 Action123 <- <{
 		    p.AssembleMap(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction74, position)
+				add(ruleAction123, position)
 			}
 			return true
 		},
-		/* 265 Action75 <- <{
+		/* 379 Action124 <- <{
 		    p.AssembleKeyValuePair()
 		}> */
 		func() bool {
 			{
-				add(ruleAction75, position)
+				add(ruleAction124, position)
 			}
 			return true
 		},
-		/* 266 Action76 <- <{
+		/* 380 Action125 <- <{
 		    p.AssembleConditionCase(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction76, position)
+				add(ruleAction125, position)
 			}
 			return true
 		},
-		/* 267 Action77 <- <{
+		/* 381 Action126 <- <{
 		    p.AssembleExpressionCase(begin, end)
 		}> */
 		func() bool {
 			{
-				add(ruleAction77, position)
+				add(ruleAction126, position)
 			}
 			return true
 		},
-		/* 268 Action78 <- <{
+		/* 382 Action127 <- <{
 		    p.AssembleWhenThenPair()
 		}> */
 		func() bool {
 			{
-				add(ruleAction78, position)
+				add(ruleAction127, position)
 			}
 			return true
 		},
-		/* 269 Action79 <- <{
+		/* 383 Action128 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewStream(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction79, position)
+				add(ruleAction128, position)
 			}
 			return true
 		},
-		/* 270 Action80 <- <{
+		/* 384 Action129 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewRowMeta(substr, TimestampMeta))
 		}> */
 		func() bool {
 			{
-				add(ruleAction80, position)
+				add(ruleAction129, position)
+			}
+			return true
+		},
+		/* 385 Action130 <- <{
+		    substr := string([]rune(buffer)[begin:end])
+		    p.AssembleRowMetadata(begin, end, substr)
+		}> */
+		func() bool {
+			{
+				add(ruleAction130, position)
 			}
 			return true
 		},
-		/* 271 Action81 <- <{
+		/* 386 Action131 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewRowValue(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction81, position)
+				add(ruleAction131, position)
 			}
 			return true
 		},
-		/* 272 Action82 <- <{
+		/* 387 Action132 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewNumericLiteral(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction82, position)
+				add(ruleAction132, position)
 			}
 			return true
 		},
-		/* 273 Action83 <- <{
+		/* 388 Action133 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewNumericLiteral(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction83, position)
+				add(ruleAction133, position)
 			}
 			return true
 		},
-		/* 274 Action84 <- <{
+		/* 389 Action134 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewFloatLiteral(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction84, position)
+				add(ruleAction134, position)
 			}
 			return true
 		},
-		/* 275 Action85 <- <{
+		/* 390 Action135 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, FuncName(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction85, position)
+				add(ruleAction135, position)
 			}
 			return true
 		},
-		/* 276 Action86 <- <{
+		/* 391 Action136 <- <{
 		    p.PushComponent(begin, end, NewNullLiteral())
 		}> */
 		func() bool {
 			{
-				add(ruleAction86, position)
+				add(ruleAction136, position)
 			}
 			return true
 		},
-		/* 277 Action87 <- <{
+		/* 392 Action137 <- <{
 		    p.PushComponent(begin, end, NewMissing())
 		}> */
 		func() bool {
 			{
-				add(ruleAction87, position)
+				add(ruleAction137, position)
 			}
 			return true
 		},
-		/* 278 Action88 <- <{
+		/* 393 Action138 <- <{
 		    p.PushComponent(begin, end, NewBoolLiteral(true))
 		}> */
 		func() bool {
 			{
-				add(ruleAction88, position)
+				add(ruleAction138, position)
 			}
 			return true
 		},
-		/* 279 Action89 <- <{
+		/* 394 Action139 <- <{
 		    p.PushComponent(begin, end, NewBoolLiteral(false))
 		}> */
 		func() bool {
 			{
-				add(ruleAction89, position)
+				add(ruleAction139, position)
 			}
 			return true
 		},
-		/* 280 Action90 <- <{
+		/* 395 Action140 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewWildcard(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction90, position)
+				add(ruleAction140, position)
 			}
 			return true
 		},
-		/* 281 Action91 <- <{
+		/* 396 Action141 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, NewStringLiteral(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction91, position)
+				add(ruleAction141, position)
 			}
 			return true
 		},
-		/* 282 Action92 <- <{
+		/* 397 Action142 <- <{
 		    p.PushComponent(begin, end, Istream)
 		}> */
 		func() bool {
 			{
-				add(ruleAction92, position)
+				add(ruleAction142, position)
 			}
 			return true
 		},
-		/* 283 Action93 <- <{
+		/* 398 Action143 <- <{
 		    p.PushComponent(begin, end, Dstream)
 		}> */
 		func() bool {
 			{
-				add(ruleAction93, position)
+				add(ruleAction143, position)
 			}
 			return true
 		},
-		/* 284 Action94 <- <{
+		/* 399 Action144 <- <{
 		    p.PushComponent(begin, end, Rstream)
 		}> */
 		func() bool {
 			{
-				add(ruleAction94, position)
+				add(ruleAction144, position)
 			}
 			return true
 		},
-		/* 285 Action95 <- <{
+		/* 400 Action145 <- <{
 		    p.PushComponent(begin, end, Tuples)
 		}> */
 		func() bool {
 			{
-				add(ruleAction95, position)
+				add(ruleAction145, position)
 			}
 			return true
 		},
-		/* 286 Action96 <- <{
+		/* 401 Action146 <- <{
 		    p.PushComponent(begin, end, Seconds)
 		}> */
 		func() bool {
 			{
-				add(ruleAction96, position)
+				add(ruleAction146, position)
 			}
 			return true
 		},
-		/* 287 Action97 <- <{
+		/* 402 Action147 <- <{
 		    p.PushComponent(begin, end, Milliseconds)
 		}> */
 		func() bool {
 			{
-				add(ruleAction97, position)
+				add(ruleAction147, position)
+			}
+			return true
+		},
+		/* 403 Action148 <- <{
+		    p.PushComponent(begin, end, DropOnError)
+		}> */
+		func() bool {
+			{
+				add(ruleAction148, position)
+			}
+			return true
+		},
+		/* 404 Action149 <- <{
+		    p.PushComponent(begin, end, StopOnError)
+		}> */
+		func() bool {
+			{
+				add(ruleAction149, position)
+			}
+			return true
+		},
+		/* 405 Action150 <- <{
+		    p.PushComponent(begin, end, DLQOnError)
+		}> */
+		func() bool {
+			{
+				add(ruleAction150, position)
+			}
+			return true
+		},
+		/* 406 Action151 <- <{
+		    p.PushComponent(begin, end, RetryOnError)
+		}> */
+		func() bool {
+			{
+				add(ruleAction151, position)
 			}
 			return true
 		},
-		/* 288 Action98 <- <{
+		/* 407 Action152 <- <{
 		    p.PushComponent(begin, end, Wait)
 		}> */
 		func() bool {
 			{
-				add(ruleAction98, position)
+				add(ruleAction152, position)
 			}
 			return true
 		},
-		/* 289 Action99 <- <{
+		/* 408 Action153 <- <{
 		    p.PushComponent(begin, end, DropOldest)
 		}> */
 		func() bool {
 			{
-				add(ruleAction99, position)
+				add(ruleAction153, position)
 			}
 			return true
 		},
-		/* 290 Action100 <- <{
+		/* 409 Action154 <- <{
 		    p.PushComponent(begin, end, DropNewest)
 		}> */
 		func() bool {
 			{
-				add(ruleAction100, position)
+				add(ruleAction154, position)
+			}
+			return true
+		},
+		/* 410 Action155 <- <{
+		    p.PushComponent(begin, end, ArrivalOrder)
+		}> */
+		func() bool {
+			{
+				add(ruleAction155, position)
+			}
+			return true
+		},
+		/* 411 Action156 <- <{
+		    p.PushComponent(begin, end, TimestampOrder)
+		}> */
+		func() bool {
+			{
+				add(ruleAction156, position)
 			}
 			return true
 		},
-		/* 291 Action101 <- <{
+		/* 412 Action157 <- <{
+		    p.PushComponent(begin, end, RoundRobinOrder)
+		}> */
+		func() bool {
+			{
+				add(ruleAction157, position)
+			}
+			return true
+		},
+		/* 413 Action158 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, StreamIdentifier(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction101, position)
+				add(ruleAction158, position)
 			}
 			return true
 		},
-		/* 292 Action102 <- <{
+		/* 414 Action159 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, SourceSinkType(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction102, position)
+				add(ruleAction159, position)
 			}
 			return true
 		},
-		/* 293 Action103 <- <{
+		/* 415 Action160 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, SourceSinkParamKey(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction103, position)
+				add(ruleAction160, position)
 			}
 			return true
 		},
-		/* 294 Action104 <- <{
+		/* 416 Action161 <- <{
 		    p.PushComponent(begin, end, Yes)
 		}> */
 		func() bool {
 			{
-				add(ruleAction104, position)
+				add(ruleAction161, position)
 			}
 			return true
 		},
-		/* 295 Action105 <- <{
+		/* 417 Action162 <- <{
 		    p.PushComponent(begin, end, No)
 		}> */
 		func() bool {
 			{
-				add(ruleAction105, position)
+				add(ruleAction162, position)
 			}
 			return true
 		},
-		/* 296 Action106 <- <{
+		/* 418 Action163 <- <{
 		    p.PushComponent(begin, end, Yes)
 		}> */
 		func() bool {
 			{
-				add(ruleAction106, position)
+				add(ruleAction163, position)
+			}
+			return true
+		},
+		/* 419 Action164 <- <{
+		    p.PushComponent(begin, end, Yes)
+		}> */
+		func() bool {
+			{
+				add(ruleAction164, position)
 			}
 			return true
 		},
-		/* 297 Action107 <- <{
+		/* 420 Action165 <- <{
 		    p.PushComponent(begin, end, No)
 		}> */
 		func() bool {
 			{
-				add(ruleAction107, position)
+				add(ruleAction165, position)
 			}
 			return true
 		},
-		/* 298 Action108 <- <{
+		/* 421 Action166 <- <{
 		    p.PushComponent(begin, end, Bool)
 		}> */
 		func() bool {
 			{
-				add(ruleAction108, position)
+				add(ruleAction166, position)
 			}
 			return true
 		},
-		/* 299 Action109 <- <{
+		/* 422 Action167 <- <{
 		    p.PushComponent(begin, end, Int)
 		}> */
 		func() bool {
 			{
-				add(ruleAction109, position)
+				add(ruleAction167, position)
 			}
 			return true
 		},
-		/* 300 Action110 <- <{
+		/* 423 Action168 <- <{
 		    p.PushComponent(begin, end, Float)
 		}> */
 		func() bool {
 			{
-				add(ruleAction110, position)
+				add(ruleAction168, position)
 			}
 			return true
 		},
-		/* 301 Action111 <- <{
+		/* 424 Action169 <- <{
 		    p.PushComponent(begin, end, String)
 		}> */
 		func() bool {
 			{
-				add(ruleAction111, position)
+				add(ruleAction169, position)
 			}
 			return true
 		},
-		/* 302 Action112 <- <{
+		/* 425 Action170 <- <{
 		    p.PushComponent(begin, end, Blob)
 		}> */
 		func() bool {
 			{
-				add(ruleAction112, position)
+				add(ruleAction170, position)
 			}
 			return true
 		},
-		/* 303 Action113 <- <{
+		/* 426 Action171 <- <{
 		    p.PushComponent(begin, end, Timestamp)
 		}> */
 		func() bool {
 			{
-				add(ruleAction113, position)
+				add(ruleAction171, position)
 			}
 			return true
 		},
-		/* 304 Action114 <- <{
+		/* 427 Action172 <- <{
 		    p.PushComponent(begin, end, Array)
 		}> */
 		func() bool {
 			{
-				add(ruleAction114, position)
+				add(ruleAction172, position)
 			}
 			return true
 		},
-		/* 305 Action115 <- <{
+		/* 428 Action173 <- <{
 		    p.PushComponent(begin, end, Map)
 		}> */
 		func() bool {
 			{
-				add(ruleAction115, position)
+				add(ruleAction173, position)
 			}
 			return true
 		},
-		/* 306 Action116 <- <{
+		/* 429 Action174 <- <{
 		    p.PushComponent(begin, end, Or)
 		}> */
 		func() bool {
 			{
-				add(ruleAction116, position)
+				add(ruleAction174, position)
 			}
 			return true
 		},
-		/* 307 Action117 <- <{
+		/* 430 Action175 <- <{
 		    p.PushComponent(begin, end, And)
 		}> */
 		func() bool {
 			{
-				add(ruleAction117, position)
+				add(ruleAction175, position)
 			}
 			return true
 		},
-		/* 308 Action118 <- <{
+		/* 431 Action176 <- <{
 		    p.PushComponent(begin, end, Not)
 		}> */
 		func() bool {
 			{
-				add(ruleAction118, position)
+				add(ruleAction176, position)
 			}
 			return true
 		},
-		/* 309 Action119 <- <{
+		/* 432 Action177 <- <{
 		    p.PushComponent(begin, end, Equal)
 		}> */
 		func() bool {
 			{
-				add(ruleAction119, position)
+				add(ruleAction177, position)
 			}
 			return true
 		},
-		/* 310 Action120 <- <{
+		/* 433 Action178 <- <{
 		    p.PushComponent(begin, end, Less)
 		}> */
 		func() bool {
 			{
-				add(ruleAction120, position)
+				add(ruleAction178, position)
 			}
 			return true
 		},
-		/* 311 Action121 <- <{
+		/* 434 Action179 <- <{
 		    p.PushComponent(begin, end, LessOrEqual)
 		}> */
 		func() bool {
 			{
-				add(ruleAction121, position)
+				add(ruleAction179, position)
 			}
 			return true
 		},
-		/* 312 Action122 <- <{
+		/* 435 Action180 <- <{
 		    p.PushComponent(begin, end, Greater)
 		}> */
 		func() bool {
 			{
-				add(ruleAction122, position)
+				add(ruleAction180, position)
 			}
 			return true
 		},
-		/* 313 Action123 <- <{
+		/* 436 Action181 <- <{
 		    p.PushComponent(begin, end, GreaterOrEqual)
 		}> */
 		func() bool {
 			{
-				add(ruleAction123, position)
+				add(ruleAction181, position)
 			}
 			return true
 		},
-		/* 314 Action124 <- <{
+		/* 437 Action182 <- <{
 		    p.PushComponent(begin, end, NotEqual)
 		}> */
 		func() bool {
 			{
-				add(ruleAction124, position)
+				add(ruleAction182, position)
 			}
 			return true
 		},
-		/* 315 Action125 <- <{
+		/* 438 Action183 <- <{
 		    p.PushComponent(begin, end, Concat)
 		}> */
 		func() bool {
 			{
-				add(ruleAction125, position)
+				add(ruleAction183, position)
 			}
 			return true
 		},
-		/* 316 Action126 <- <{
+		/* 439 Action184 <- <{
 		    p.PushComponent(begin, end, Is)
 		}> */
 		func() bool {
 			{
-				add(ruleAction126, position)
+				add(ruleAction184, position)
 			}
 			return true
 		},
-		/* 317 Action127 <- <{
+		/* 440 Action185 <- <{
 		    p.PushComponent(begin, end, IsNot)
 		}> */
 		func() bool {
 			{
-				add(ruleAction127, position)
+				add(ruleAction185, position)
 			}
 			return true
 		},
-		/* 318 Action128 <- <{
+		/* 441 Action186 <- <{
 		    p.PushComponent(begin, end, Plus)
 		}> */
 		func() bool {
 			{
-				add(ruleAction128, position)
+				add(ruleAction186, position)
 			}
 			return true
 		},
-		/* 319 Action129 <- <{
+		/* 442 Action187 <- <{
 		    p.PushComponent(begin, end, Minus)
 		}> */
 		func() bool {
 			{
-				add(ruleAction129, position)
+				add(ruleAction187, position)
 			}
 			return true
 		},
-		/* 320 Action130 <- <{
+		/* 443 Action188 <- <{
 		    p.PushComponent(begin, end, Multiply)
 		}> */
 		func() bool {
 			{
-				add(ruleAction130, position)
+				add(ruleAction188, position)
 			}
 			return true
 		},
-		/* 321 Action131 <- <{
+		/* 444 Action189 <- <{
 		    p.PushComponent(begin, end, Divide)
 		}> */
 		func() bool {
 			{
-				add(ruleAction131, position)
+				add(ruleAction189, position)
 			}
 			return true
 		},
-		/* 322 Action132 <- <{
+		/* 445 Action190 <- <{
 		    p.PushComponent(begin, end, Modulo)
 		}> */
 		func() bool {
 			{
-				add(ruleAction132, position)
+				add(ruleAction190, position)
 			}
 			return true
 		},
-		/* 323 Action133 <- <{
+		/* 446 Action191 <- <{
 		    p.PushComponent(begin, end, UnaryMinus)
 		}> */
 		func() bool {
 			{
-				add(ruleAction133, position)
+				add(ruleAction191, position)
 			}
 			return true
 		},
-		/* 324 Action134 <- <{
+		/* 447 Action192 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, Identifier(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction134, position)
+				add(ruleAction192, position)
 			}
 			return true
 		},
-		/* 325 Action135 <- <{
+		/* 448 Action193 <- <{
 		    substr := string([]rune(buffer)[begin:end])
 		    p.PushComponent(begin, end, Identifier(substr))
 		}> */
 		func() bool {
 			{
-				add(ruleAction135, position)
+				add(ruleAction193, position)
 			}
 			return true
 		},
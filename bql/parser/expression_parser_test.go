@@ -56,14 +56,19 @@ func TestExpressionParser(t *testing.T) {
 		"ts()::STRING":         {[]Expression{TypeCastAST{RowMeta{"", TimestampMeta}, String}}, "ts()::STRING"},
 		"tab:ts()::STRING":     {[]Expression{TypeCastAST{RowMeta{"tab", TimestampMeta}, String}}, "tab:ts()::STRING"},
 		// RowValue
-		"a":         {[]Expression{RowValue{"", "a"}}, "a"},
-		"-a":        {[]Expression{UnaryOpAST{UnaryMinus, RowValue{"", "a"}}}, "-a"},
-		"tab:a":     {[]Expression{RowValue{"tab", "a"}}, "tab:a"},
-		"ts()":      {[]Expression{RowMeta{"", TimestampMeta}}, "ts()"},
-		"tab:ts()":  {[]Expression{RowMeta{"tab", TimestampMeta}}, "tab:ts()"},
-		"a, b":      {[]Expression{RowValue{"", "a"}, RowValue{"", "b"}}, "a, b"},
-		"A":         {[]Expression{RowValue{"", "A"}}, "A"},
-		"my_mem_27": {[]Expression{RowValue{"", "my_mem_27"}}, "my_mem_27"},
+		"a":        {[]Expression{RowValue{"", "a"}}, "a"},
+		"-a":       {[]Expression{UnaryOpAST{UnaryMinus, RowValue{"", "a"}}}, "-a"},
+		"tab:a":    {[]Expression{RowValue{"tab", "a"}}, "tab:a"},
+		"ts()":     {[]Expression{RowMeta{"", TimestampMeta}}, "ts()"},
+		"tab:ts()": {[]Expression{RowMeta{"tab", TimestampMeta}}, "tab:ts()"},
+		// RowMetadata
+		`meta("offset")`:              {[]Expression{RowMetadata{"", "offset"}}, `meta("offset")`},
+		`tab:meta( "a""b" )`:          {[]Expression{RowMetadata{"tab", `a"b`}}, `tab:meta("a""b")`},
+		`meta("a:(b")::INT`:           {[]Expression{TypeCastAST{RowMetadata{"", "a:(b"}, Int}}, `meta("a:(b")::INT`},
+		`f(meta("partition"), tab:a)`: {[]Expression{FuncAppAST{FuncName("f"), ExpressionsAST{[]Expression{RowMetadata{"", "partition"}, RowValue{"tab", "a"}}}, nil}}, `f(meta("partition"), tab:a)`},
+		"a, b":                        {[]Expression{RowValue{"", "a"}, RowValue{"", "b"}}, "a, b"},
+		"A":                           {[]Expression{RowValue{"", "A"}}, "A"},
+		"my_mem_27":                   {[]Expression{RowValue{"", "my_mem_27"}}, "my_mem_27"},
 		/// JSON Path
 		`["hoge"]`:       {[]Expression{RowValue{"", `["hoge"]`}}, `["hoge"]`},
 		`["hoge"][0]..y`: {[]Expression{RowValue{"", `["hoge"][0]..y`}}, `["hoge"][0]..y`},
@@ -116,10 +121,10 @@ func TestExpressionParser(t *testing.T) {
 		"CASE WHEN true THEN 3 END":          {[]Expression{ConditionCaseAST{[]WhenThenPairAST{{BoolLiteral{true}, NumericLiteral{3}}}, nil}}, "CASE WHEN TRUE THEN 3 END"},
 		"CASE WHEN false THEN 3 ELSE 6 END":  {[]Expression{ConditionCaseAST{[]WhenThenPairAST{{BoolLiteral{false}, NumericLiteral{3}}}, NumericLiteral{6}}}, "CASE WHEN FALSE THEN 3 ELSE 6 END"},
 		// NumericLiteral
-		"2":    {[]Expression{NumericLiteral{2}}, "2"},
-		"-2":   {[]Expression{UnaryOpAST{UnaryMinus, NumericLiteral{2}}}, "-2"},
-		"- -2": {[]Expression{UnaryOpAST{UnaryMinus, NumericLiteral{-2}}}, "- -2"}, // like PostgreSQL
-		"999999999999999999999999999": {nil, ""}, // int64 overflow
+		"2":                           {[]Expression{NumericLiteral{2}}, "2"},
+		"-2":                          {[]Expression{UnaryOpAST{UnaryMinus, NumericLiteral{2}}}, "-2"},
+		"- -2":                        {[]Expression{UnaryOpAST{UnaryMinus, NumericLiteral{-2}}}, "- -2"}, // like PostgreSQL
+		"999999999999999999999999999": {nil, ""},                                                          // int64 overflow
 		// FloatLiteral
 		"1.2":   {[]Expression{FloatLiteral{1.2}}, "1.2"},
 		"-3.14": {[]Expression{UnaryOpAST{UnaryMinus, FloatLiteral{3.14}}}, "-3.14"},
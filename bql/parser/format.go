@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+const formatIndent = "    "
+
+// Format returns the canonical form of a statement returned from the
+// parser. A SELECT statement is formatted with one clause per line and
+// the SELECT statement of CREATE STREAM is indented. Other statements are
+// formatted in one line as their String methods do. The result can be
+// parsed again and results in the same statement.
+func Format(stmt interface{}) string {
+	switch s := stmt.(type) {
+	case SelectStmt:
+		return strings.Join(formatSelect(s), "\n")
+
+	case SelectUnionStmt:
+		return strings.Join(formatSelectUnion(s), "\n")
+
+	case CreateStreamAsSelectStmt:
+		return formatCreateStream(s.Temporary, s.Name, s.SchemaAST,
+			formatSelect(s.Select), s.TimestampByAST, s.OnErrorAST)
+
+	case CreateStreamAsSelectUnionStmt:
+		return formatCreateStream(s.Temporary, s.Name, s.SchemaAST,
+			formatSelectUnion(s.SelectUnionStmt), s.TimestampByAST, s.OnErrorAST)
+
+	case fmt.Stringer:
+		return s.String()
+	}
+	return fmt.Sprint(stmt)
+}
+
+// FormatStmts formats statements with Format and terminates each of them
+// with a semicolon and a newline.
+func FormatStmts(stmts []interface{}) string {
+	str := make([]string, len(stmts))
+	for i, s := range stmts {
+		str[i] = Format(s) + ";\n"
+	}
+	return strings.Join(str, "")
+}
+
+func formatSelect(s SelectStmt) []string {
+	clauses := []string{
+		"SELECT " + s.EmitterAST.string() + " " + s.ProjectionsAST.string(),
+		s.WindowedFromAST.string(),
+		s.InputSamplingAST.string(),
+		s.FilterAST.string(),
+		s.GroupingAST.string(),
+		s.HavingAST.string(),
+		s.EmitWhenAST.string(),
+	}
+
+	lines := []string{}
+	for _, c := range clauses {
+		if c != "" {
+			lines = append(lines, c)
+		}
+	}
+	return lines
+}
+
+func formatSelectUnion(s SelectUnionStmt) []string {
+	lines := []string{}
+	for i, sel := range s.Selects {
+		if i > 0 {
+			lines = append(lines, "UNION ALL")
+		}
+		lines = append(lines, formatSelect(sel)...)
+	}
+	return lines
+}
+
+func formatCreateStream(temporary BinaryKeyword, name StreamIdentifier, schema SchemaAST,
+	selectLines []string, timestampBy TimestampByAST, onError OnErrorAST) string {
+	head := []string{"CREATE"}
+	if t := temporary.string("TEMPORARY", ""); t != "" {
+		head = append(head, t)
+	}
+	head = append(head, "STREAM", string(name))
+	if sc := schema.string(); sc != "" {
+		head = append(head, sc)
+	}
+	head = append(head, "AS")
+
+	lines := []string{strings.Join(head, " ")}
+	for _, l := range selectLines {
+		lines = append(lines, formatIndent+l)
+	}
+	if t := timestampBy.string(); t != "" {
+		lines = append(lines, t)
+	}
+	if e := onError.string(); e != "" {
+		lines = append(lines, e)
+	}
+	return strings.Join(lines, "\n")
+}
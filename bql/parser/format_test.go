@@ -0,0 +1,84 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	p := New()
+
+	Convey("Given a CREATE STREAM statement", t, func() {
+		stmts, err := p.ParseStmts(`create stream s as select istream a, count(*) as c
+			from x [range 10 tuples] where a > 1 group by a having c > 2
+			timestamp by ts on error drop`)
+		So(err, ShouldBeNil)
+		So(len(stmts), ShouldEqual, 1)
+
+		Convey("When formatting it", func() {
+			s := Format(stmts[0])
+
+			Convey("Then each clause should be in a line", func() {
+				So(s, ShouldEqual, `CREATE STREAM s AS
+    SELECT ISTREAM a, count(*) AS c
+    FROM x [RANGE 10 TUPLES]
+    WHERE a > 1
+    GROUP BY a
+    HAVING c > 2
+TIMESTAMP BY ts
+ON ERROR DROP`)
+			})
+		})
+	})
+
+	Convey("Given a SELECT UNION statement", t, func() {
+		stmts, err := p.ParseStmts(`select rstream a from x [range 1 tuples] union all
+			select rstream a from y [range 1 tuples]`)
+		So(err, ShouldBeNil)
+
+		Convey("When formatting it", func() {
+			s := Format(stmts[0])
+
+			Convey("Then UNION ALL should be in its own line", func() {
+				So(s, ShouldEqual, `SELECT RSTREAM a
+FROM x [RANGE 1 TUPLES]
+UNION ALL
+SELECT RSTREAM a
+FROM y [RANGE 1 TUPLES]`)
+			})
+		})
+	})
+
+	Convey("Given various statements", t, func() {
+		stmts, err := p.ParseStmts(`
+			create paused source src type dummy with num = 4;
+			create temporary stream s schema r as select istream * from src [range 1 tuples]
+				union all select istream * from src [range 2 seconds];
+			create stream t as select rstream a.x, b.y from s [range 1 tuples] as a,
+				s [range 5 seconds, buffer size 10, drop oldest if full] as b
+				where a.x = b.y emit when a.x > 0;
+			create sink snk type stdout;
+			insert into snk from t;
+			select istream [every 2-nd tuple] * from t [range 1 tuples] read sample 50%;
+			drop stream t`)
+		So(err, ShouldBeNil)
+
+		Convey("When formatting them", func() {
+			s := FormatStmts(stmts)
+
+			Convey("Then the result should be parsed to the same statements", func() {
+				res, err := p.ParseStmts(s)
+				So(err, ShouldBeNil)
+				So(res, ShouldResemble, stmts)
+			})
+
+			Convey("Then each statement should end with a semicolon", func() {
+				res, err := p.ParseStmts(s)
+				So(err, ShouldBeNil)
+				for _, r := range res {
+					So(s, ShouldContainSubstring, Format(r)+";\n")
+				}
+			})
+		})
+	})
+}
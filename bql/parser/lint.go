@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LintIssue is a suspicious construct found in a statement. Unlike a parse
+// error, it doesn't prevent the statement from being executed.
+type LintIssue struct {
+	// Index is the index of the statement having the issue. It's -1 when
+	// the issue isn't related to a particular statement.
+	Index int `json:"index"`
+
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	if i.Index < 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("statement %v: %v", i.Index+1, i.Message)
+}
+
+const (
+	// lintMaxWindowTuples is the maximum size of a tuple-based window which
+	// isn't regarded as unbounded.
+	lintMaxWindowTuples = 10000
+
+	// lintMaxWindowSeconds is the maximum size of a time-based window in
+	// seconds which isn't regarded as unbounded.
+	lintMaxWindowSeconds = 3600
+)
+
+// Lint reports suspicious constructs in statements returned from the
+// parser. It reports:
+//
+//	* windows so large that they're virtually unbounded
+//	* SELECT statements joining relations without a WHERE clause
+//	* SELECT statements whose results aren't inserted into any sink
+//	* sources and streams which are never used as an input
+//	* sinks which never receive tuples
+//
+// Because statements are checked without a topology, nodes created
+// outside of the given statements aren't taken into account.
+func Lint(stmts []interface{}) []LintIssue {
+	l := &linter{
+		defined: map[string]int{},
+		used:    map[string]bool{},
+		sinks:   map[string]int{},
+		inserts: map[string]bool{},
+	}
+	for i, stmt := range stmts {
+		l.lintStmt(i, stmt)
+	}
+
+	var names []string
+	for n := range l.defined {
+		if !l.used[n] {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		l.add(l.defined[n], "'%v' is never used as an input", n)
+	}
+
+	names = nil
+	for n := range l.sinks {
+		if !l.inserts[n] {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		l.add(l.sinks[n], "sink '%v' never receives tuples", n)
+	}
+
+	sort.Stable(lintIssuesByIndex(l.issues))
+	return l.issues
+}
+
+type linter struct {
+	issues []LintIssue
+
+	// defined has the indexes of statements creating sources or streams.
+	defined map[string]int
+
+	// used has the names of sources or streams used as inputs.
+	used map[string]bool
+
+	// sinks has the indexes of statements creating sinks.
+	sinks map[string]int
+
+	// inserts has the names of sinks receiving tuples.
+	inserts map[string]bool
+}
+
+func (l *linter) add(i int, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{
+		Index:   i,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintStmt(i int, stmt interface{}) {
+	switch s := stmt.(type) {
+	case SelectStmt:
+		l.add(i, "the result of SELECT isn't inserted into any sink")
+		l.lintSelect(i, s)
+
+	case SelectUnionStmt:
+		l.add(i, "the result of SELECT isn't inserted into any sink")
+		for _, sel := range s.Selects {
+			l.lintSelect(i, sel)
+		}
+
+	case CreateStreamAsSelectStmt:
+		l.defined[string(s.Name)] = i
+		l.lintSelect(i, s.Select)
+
+	case CreateStreamAsSelectUnionStmt:
+		l.defined[string(s.Name)] = i
+		for _, sel := range s.Selects {
+			l.lintSelect(i, sel)
+		}
+
+	case CreateStreamRoutesStmt:
+		l.used[string(s.Input)] = true
+		for _, r := range s.Routes {
+			l.defined[string(r.Name)] = i
+		}
+
+	case CreateSourceStmt:
+		l.defined[string(s.Name)] = i
+
+	case CreateSinkStmt:
+		l.sinks[string(s.Name)] = i
+
+	case InsertIntoFromStmt:
+		l.used[string(s.Input)] = true
+		l.inserts[string(s.Sink)] = true
+
+	case CreateTriggerStmt:
+		if a, ok := s.Action.(TriggerInsertStmt); ok {
+			l.inserts[string(a.Sink)] = true
+		}
+
+	case CreateAlertStmt:
+		l.used[string(s.Input)] = true
+
+	case DropSourceStmt:
+		delete(l.defined, string(s.Source))
+
+	case DropStreamStmt:
+		delete(l.defined, string(s.Stream))
+
+	case DropSinkStmt:
+		delete(l.sinks, string(s.Sink))
+	}
+}
+
+func (l *linter) lintSelect(i int, s SelectStmt) {
+	for _, r := range s.Relations {
+		switch r.Type {
+		case ActualStream, UnnestStream:
+			l.used[r.Name] = true
+		case UDSFStream:
+			// UDSFs usually receive the names of their input streams as
+			// string parameters.
+			for _, p := range r.Params {
+				if str, ok := p.(StringLiteral); ok {
+					l.used[str.Value] = true
+				}
+			}
+		}
+
+		switch r.Unit {
+		case Tuples:
+			if r.Value > lintMaxWindowTuples {
+				l.add(i, "the window of '%v' is too large to be practical: %v",
+					r.Name, r.window())
+			}
+		case Seconds, Milliseconds:
+			sec := r.Value
+			if r.Unit == Milliseconds {
+				sec /= 1000
+			}
+			if sec > lintMaxWindowSeconds {
+				l.add(i, "the window of '%v' is too large to be practical: %v",
+					r.Name, r.window())
+			}
+		}
+	}
+
+	if len(s.Relations) > 1 && s.Filter == nil {
+		l.add(i, "SELECT joins %v relations without a WHERE clause and emits their cross product",
+			len(s.Relations))
+	}
+}
+
+type lintIssuesByIndex []LintIssue
+
+func (is lintIssuesByIndex) Len() int           { return len(is) }
+func (is lintIssuesByIndex) Less(i, j int) bool { return is[i].Index < is[j].Index }
+func (is lintIssuesByIndex) Swap(i, j int)      { is[i], is[j] = is[j], is[i] }
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	p := New()
+	lint := func(bql string) []LintIssue {
+		stmts, err := p.ParseStmts(bql)
+		So(err, ShouldBeNil)
+		return Lint(stmts)
+	}
+
+	Convey("Given statements building a pipeline", t, func() {
+		bql := `create source src type dummy;
+			create stream s as select istream * from src [range 1 tuples];
+			create stream t as select istream * from my_udsf("s", 1) [range 1 tuples];
+			create stream u as select istream * from unnest(t, t.a) [range 1 tuples];
+			create sink snk type stdout;
+			insert into snk from u;`
+
+		Convey("When linting them", func() {
+			Convey("Then no issue should be reported", func() {
+				So(lint(bql), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a stream isn't used", func() {
+			is := lint(bql + `create stream v as select istream * from u [range 1 tuples];`)
+
+			Convey("Then it should be reported", func() {
+				So(is, ShouldResemble, []LintIssue{
+					{6, "'v' is never used as an input"},
+				})
+			})
+		})
+
+		Convey("When the unused stream is dropped", func() {
+			is := lint(bql + `create stream v as select istream * from u [range 1 tuples];
+				drop stream v;`)
+
+			Convey("Then it shouldn't be reported", func() {
+				So(is, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a sink doesn't receive tuples", func() {
+			is := lint(bql + `create sink snk2 type stdout;`)
+
+			Convey("Then it should be reported", func() {
+				So(is, ShouldResemble, []LintIssue{
+					{6, "sink 'snk2' never receives tuples"},
+				})
+			})
+		})
+	})
+
+	Convey("Given statements having windows", t, func() {
+		Convey("When a window is too large", func() {
+			is := lint(`select istream * from a [range 10001 tuples];
+				select istream * from a [range 3601 seconds];
+				select istream * from a [range 3600001 milliseconds];`)
+
+			Convey("Then it should be reported", func() {
+				So(is, ShouldResemble, []LintIssue{
+					{0, "the result of SELECT isn't inserted into any sink"},
+					{0, "the window of 'a' is too large to be practical: RANGE 10001 TUPLES"},
+					{1, "the result of SELECT isn't inserted into any sink"},
+					{1, "the window of 'a' is too large to be practical: RANGE 3601 SECONDS"},
+					{2, "the result of SELECT isn't inserted into any sink"},
+					{2, "the window of 'a' is too large to be practical: RANGE 3.600001e+06 MILLISECONDS"},
+				})
+			})
+		})
+
+		Convey("When relations are joined without WHERE", func() {
+			is := lint(`create stream s as select istream * from a [range 1 tuples],
+				b [range 1 tuples];
+				insert into snk from s`)
+
+			Convey("Then it should be reported", func() {
+				So(is, ShouldResemble, []LintIssue{
+					{0, "SELECT joins 2 relations without a WHERE clause and emits their cross product"},
+				})
+			})
+		})
+
+		Convey("When relations are joined with WHERE", func() {
+			is := lint(`create stream s as select istream * from a [range 1 tuples],
+				b [range 1 tuples] where a:x = b:x;
+				insert into snk from s`)
+
+			Convey("Then it shouldn't be reported", func() {
+				So(is, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,279 @@
+package parser
+
+import (
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// SortStmts reorders statements so that statements creating nodes and
+// states come before statements referring to them. It allows files such as
+// a bql_file or statements saved for restoring a topology to declare nodes
+// and states in any order.
+//
+// Statements are only moved when it's necessary and the original order is
+// kept otherwise. Statements are never moved across statements whose
+// meaning depends on their positions:
+//
+//	* DROP statements
+//	* SET CONFIG statements
+//	* statements unknown to SortStmts
+//
+// Statements having side effects on existing nodes or states, such as PAUSE
+// SOURCE or SAVE STATE, are kept in the original order among themselves.
+// References are detected from FROM clauses, INTO clauses, names of target
+// nodes or states, and string parameters of UDSFs, sources, sinks, and
+// states, which usually refer to states or streams by their names. When
+// statements refer to each other, the ones in the cycle are kept in the
+// original order.
+func SortStmts(stmts []interface{}) []interface{} {
+	res := make([]interface{}, 0, len(stmts))
+	start := 0
+	for i, stmt := range stmts {
+		if isOrderBarrier(stmt) {
+			res = append(res, sortStmtSegment(stmts[start:i])...)
+			res = append(res, stmt)
+			start = i + 1
+		}
+	}
+	return append(res, sortStmtSegment(stmts[start:])...)
+}
+
+func isOrderBarrier(stmt interface{}) bool {
+	switch stmt.(type) {
+	case DropSourceStmt, DropStreamStmt, DropSinkStmt, DropStateStmt,
+		DropTypeStmt, DropTriggerStmt, SetConfigStmt:
+		return true
+	}
+	_, known := stmtDeps(stmt)
+	return !known
+}
+
+// sortStmtSegment sorts statements not having any barrier.
+func sortStmtSegment(stmts []interface{}) []interface{} {
+	if len(stmts) < 2 {
+		return stmts
+	}
+
+	deps := make([]*stmtDependency, len(stmts))
+	defined := map[string]int{}
+	for i, stmt := range stmts {
+		deps[i], _ = stmtDeps(stmt)
+		for _, d := range deps[i].defs {
+			if _, ok := defined[d]; !ok {
+				defined[d] = i
+			}
+		}
+	}
+
+	// preds[j] has the indexes of statements which must come before j.
+	preds := make([]map[int]bool, len(stmts))
+	lastAction := -1
+	for j, d := range deps {
+		preds[j] = map[int]bool{}
+		for _, r := range d.refs {
+			if i, ok := defined[r]; ok && i != j {
+				preds[j][i] = true
+			}
+		}
+		if d.action {
+			if lastAction >= 0 {
+				preds[j][lastAction] = true
+			}
+			lastAction = j
+		}
+	}
+
+	res := make([]interface{}, 0, len(stmts))
+	done := make([]bool, len(stmts))
+	ready := func(j int) bool {
+		for i := range preds[j] {
+			if !done[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(res) < len(stmts) {
+		next := -1
+		for j := range stmts {
+			if !done[j] && ready(j) {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			// break a cycle by issuing the first remaining statement
+			for j := range stmts {
+				if !done[j] {
+					next = j
+					break
+				}
+			}
+		}
+		done[next] = true
+		res = append(res, stmts[next])
+	}
+	return res
+}
+
+// stmtDependency has names defined or referred by a statement. Names are
+// prefixed by their kinds, "node:" or "state:", and lower-cased.
+type stmtDependency struct {
+	defs []string
+	refs []string
+
+	// action is true when the statement has side effects on existing nodes
+	// or states.
+	action bool
+}
+
+func (d *stmtDependency) def(kind, name string) {
+	d.defs = append(d.defs, kind+":"+strings.ToLower(name))
+}
+
+func (d *stmtDependency) ref(kind, name string) {
+	d.refs = append(d.refs, kind+":"+strings.ToLower(name))
+}
+
+// refParams adds string parameters as references to nodes and states.
+func (d *stmtDependency) refParams(s SourceSinkSpecsAST) {
+	for _, p := range s.Params {
+		if p.Value.Type() != data.TypeString {
+			continue
+		}
+		str, _ := data.AsString(p.Value)
+		d.ref("node", str)
+		d.ref("state", str)
+	}
+}
+
+func (d *stmtDependency) refSelect(s SelectStmt) {
+	for _, r := range s.Relations {
+		switch r.Type {
+		case ActualStream, UnnestStream:
+			d.ref("node", r.Name)
+		case UDSFStream:
+			for _, p := range r.Params {
+				if str, ok := p.(StringLiteral); ok {
+					d.ref("node", str.Value)
+					d.ref("state", str.Value)
+				}
+			}
+		}
+	}
+}
+
+// stmtDeps returns the dependency of the statement. It returns false when
+// the statement is unknown.
+func stmtDeps(stmt interface{}) (*stmtDependency, bool) {
+	d := &stmtDependency{}
+	switch s := stmt.(type) {
+	case CreateSourceStmt:
+		d.def("node", string(s.Name))
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case CreateStreamAsSelectStmt:
+		d.def("node", string(s.Name))
+		d.refSelect(s.Select)
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case CreateStreamAsSelectUnionStmt:
+		d.def("node", string(s.Name))
+		for _, sel := range s.Selects {
+			d.refSelect(sel)
+		}
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case CreateStreamRoutesStmt:
+		d.ref("node", string(s.Input))
+		for _, r := range s.Routes {
+			d.def("node", string(r.Name))
+		}
+
+	case CreateSinkStmt:
+		d.def("node", string(s.Name))
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case CreateStateStmt:
+		d.def("state", string(s.Name))
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case LoadStateStmt:
+		d.def("state", string(s.Name))
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case LoadStateOrCreateStmt:
+		d.def("state", string(s.Name))
+		d.refParams(s.LoadSpecs)
+		d.refParams(s.CreateSpecs)
+
+	case InsertIntoFromStmt:
+		d.ref("node", string(s.Sink))
+		d.ref("node", string(s.Input))
+
+	case CreateTriggerStmt:
+		d.def("trigger", string(s.Name))
+		if a, ok := s.Action.(TriggerInsertStmt); ok {
+			d.ref("node", string(a.Sink))
+		}
+
+	case CreateAlertStmt:
+		d.def("alert", string(s.Name))
+		d.ref("node", string(s.Input))
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case CreateTypeStmt:
+		d.def("type", string(s.Name))
+
+	case CreateTemplateStmt:
+		d.def("template", string(s.Name))
+
+	case InstantiateTemplateStmt:
+		// Nodes created by the template aren't known until it's
+		// instantiated.
+		d.action = true
+		d.ref("template", string(s.Template))
+		d.refParams(s.SourceSinkSpecsAST)
+
+	case PauseSourceStmt:
+		d.action = true
+		d.ref("node", string(s.Source))
+
+	case ResumeSourceStmt:
+		d.action = true
+		d.ref("node", string(s.Source))
+
+	case RewindSourceStmt:
+		d.action = true
+		d.ref("node", string(s.Source))
+
+	case ResumeStreamStmt:
+		d.action = true
+		d.ref("node", string(s.Stream))
+
+	case ReplaySinkStmt:
+		d.action = true
+		d.ref("node", string(s.Sink))
+
+	case UpdateSourceStmt:
+		d.action = true
+		d.ref("node", string(s.Name))
+
+	case UpdateSinkStmt:
+		d.action = true
+		d.ref("node", string(s.Name))
+
+	case UpdateStateStmt:
+		d.action = true
+		d.ref("state", string(s.Name))
+
+	case SaveStateStmt:
+		d.action = true
+		d.ref("state", string(s.Name))
+
+	default:
+		return d, false
+	}
+	return d, true
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestSortStmts(t *testing.T) {
+	p := New()
+	sortStmts := func(bql string) []string {
+		stmts, err := p.ParseStmts(bql)
+		So(err, ShouldBeNil)
+		sorted := SortStmts(stmts)
+		So(sorted, ShouldHaveLength, len(stmts))
+		res := make([]string, len(sorted))
+		for i, s := range sorted {
+			// e.g. "CREATE STREAM s AS ..." becomes "create s"
+			fs := strings.Fields(fmt.Sprint(s))
+			res[i] = strings.ToLower(fs[0] + " " + fs[2])
+		}
+		return res
+	}
+
+	Convey("Given statements in the dependency order", t, func() {
+		bql := `create source src type dummy;
+			create state st type my_state;
+			create stream s as select istream * from src [range 1 tuples];
+			create stream t as select istream * from my_udsf("st", 1) [range 1 tuples];
+			create sink snk type uds with name="st";
+			insert into snk from s;`
+
+		Convey("When sorting them", func() {
+			res := sortStmts(bql)
+
+			Convey("Then the order should be kept", func() {
+				So(res, ShouldResemble, []string{
+					"create src", "create st", "create s", "create t", "create snk", "insert snk"})
+			})
+		})
+	})
+
+	Convey("Given statements referring to nodes and states defined later", t, func() {
+		bql := `insert into snk from s;
+			create sink snk type uds with name="st";
+			create stream t as select istream * from my_udsf("st", 1) [range 1 tuples];
+			create stream s as select istream * from SRC [range 1 tuples];
+			create state st type my_state;
+			create source src type dummy;`
+
+		Convey("When sorting them", func() {
+			res := sortStmts(bql)
+
+			Convey("Then definitions should come before references", func() {
+				So(res, ShouldResemble, []string{
+					"create st", "create snk", "create t", "create src", "create s", "insert snk"})
+			})
+		})
+	})
+
+	Convey("Given statements having side effects", t, func() {
+		bql := `resume source src;
+			save state st;
+			create source src type dummy with paused=true;
+			create state st type my_state;`
+
+		Convey("When sorting them", func() {
+			res := sortStmts(bql)
+
+			Convey("Then they should come after definitions in the original order", func() {
+				So(res, ShouldResemble, []string{
+					"create src", "resume src", "create st", "save st"})
+			})
+		})
+	})
+
+	Convey("Given statements having DROP", t, func() {
+		bql := `drop stream s;
+			create stream s as select istream * from src [range 1 tuples];
+			create source src type dummy;`
+
+		Convey("When sorting them", func() {
+			res := sortStmts(bql)
+
+			Convey("Then statements shouldn't be moved across it", func() {
+				So(res, ShouldResemble, []string{"drop s", "create src", "create s"})
+			})
+		})
+	})
+
+	Convey("Given statements referring to each other", t, func() {
+		bql := `create stream a as select istream * from b [range 1 tuples];
+			create stream b as select istream * from a [range 1 tuples];
+			create source c type dummy;`
+
+		Convey("When sorting them", func() {
+			res := sortStmts(bql)
+
+			Convey("Then the cycle should be kept in the original order", func() {
+				So(res, ShouldResemble, []string{"create c", "create a", "create b"})
+			})
+		})
+	})
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"gopkg.in/sensorbee/sensorbee.v0/data"
 )
@@ -69,29 +70,34 @@ func (ps *parseStack) Peek() (value *ParsedComponent) {
 // a single SelectStmt element.
 //
 //  EmitterAST
+//  EmitWhenAST
 //  HavingAST
 //  GroupingAST
 //  FilterAST
+//  InputSamplingAST
 //  WindowedFromAST
 //  ProjectionsAST
 //   =>
-//  SelectStmt{EmitterAST, ProjectionsAST, WindowedFromAST, FilterAST, GroupingAST, HavingAST}
+//  SelectStmt{EmitterAST, ProjectionsAST, WindowedFromAST, InputSamplingAST,
+//    FilterAST, GroupingAST, HavingAST, EmitWhenAST}
 func (ps *parseStack) AssembleSelect() {
 	// pop the components from the stack in reverse order
-	_having, _grouping, _filter, _from, _projections, _emitter := ps.pop6()
+	_emitWhen, _having, _grouping, _filter, _sampling, _from, _projections, _emitter := ps.pop8()
 
 	// extract and convert the contained structure
 	// (if this fails, this is a fundamental parser bug => panic ok)
+	emitWhen := _emitWhen.comp.(EmitWhenAST)
 	having := _having.comp.(HavingAST)
 	grouping := _grouping.comp.(GroupingAST)
 	filter := _filter.comp.(FilterAST)
+	sampling := _sampling.comp.(InputSamplingAST)
 	from := _from.comp.(WindowedFromAST)
 	projections := _projections.comp.(ProjectionsAST)
 	emitter := _emitter.comp.(EmitterAST)
 
 	// assemble the SelectStmt and push it back
-	s := SelectStmt{emitter, projections, from, filter, grouping, having}
-	se := ParsedComponent{_emitter.begin, _having.end, s}
+	s := SelectStmt{emitter, projections, from, sampling, filter, grouping, having, emitWhen}
+	se := ParsedComponent{_emitter.begin, _emitWhen.end, s}
 	ps.Push(&se)
 }
 
@@ -118,22 +124,35 @@ func (ps *parseStack) AssembleSelectUnion(begin int, end int) {
 // assuming they are components of a CREATE STREAM statement, and
 // replaces them by a single CreateStreamAsSelectStmt element.
 //
+//  SourceSinkSpecsAST
+//  TimeoutAST
+//  OnErrorAST
+//  TimestampByAST
 //  SelectStmt
+//  SchemaAST
 //  StreamIdentifier
+//  BinaryKeyword
 //   =>
-//  CreateStreamAsSelectStmt{StreamIdentifier, SelectStmt}
+//  CreateStreamAsSelectStmt{BinaryKeyword, StreamIdentifier, SchemaAST,
+//    SelectStmt, TimestampByAST, OnErrorAST, TimeoutAST, SourceSinkSpecsAST}
 func (ps *parseStack) AssembleCreateStreamAsSelect() {
 	// now pop the components from the stack in reverse order
-	_select, _name := ps.pop2()
+	_specs, _timeout, _onError, _timestampBy, _select, _schema, _name, _temporary := ps.pop8()
 
 	// extract and convert the contained structure
 	// (if this fails, this is a fundamental parser bug => panic ok)
+	specs := _specs.comp.(SourceSinkSpecsAST)
+	timeout := _timeout.comp.(TimeoutAST)
+	onError := _onError.comp.(OnErrorAST)
+	timestampBy := _timestampBy.comp.(TimestampByAST)
 	s := _select.comp.(SelectStmt)
+	schema := _schema.comp.(SchemaAST)
 	name := _name.comp.(StreamIdentifier)
+	temporary := _temporary.comp.(BinaryKeyword)
 
 	// assemble the SelectStmt and push it back
-	css := CreateStreamAsSelectStmt{name, s}
-	se := ParsedComponent{_name.begin, _select.end, css}
+	css := CreateStreamAsSelectStmt{temporary, name, schema, s, timestampBy, onError, timeout, specs}
+	se := ParsedComponent{_temporary.begin, _specs.end, css}
 	ps.Push(&se)
 }
 
@@ -141,50 +160,167 @@ func (ps *parseStack) AssembleCreateStreamAsSelect() {
 // stack, assuming they are components of a CREATE STREAM statement, and
 // replaces them by a single CreateStreamAsSelectUnionStmt element.
 //
+//  SourceSinkSpecsAST
+//  TimeoutAST
+//  OnErrorAST
+//  TimestampByAST
 //  SelectUnionStmt
+//  SchemaAST
 //  StreamIdentifier
+//  BinaryKeyword
 //   =>
-//  CreateStreamAsSelectUnionStmt{StreamIdentifier, SelectUnionStmt}
+//  CreateStreamAsSelectUnionStmt{BinaryKeyword, StreamIdentifier,
+//    SchemaAST, SelectUnionStmt, TimestampByAST, OnErrorAST, TimeoutAST,
+//    SourceSinkSpecsAST}
 func (ps *parseStack) AssembleCreateStreamAsSelectUnion() {
 	// now pop the components from the stack in reverse order
-	_selectUnion, _name := ps.pop2()
+	_specs, _timeout, _onError, _timestampBy, _selectUnion, _schema, _name, _temporary := ps.pop8()
 
 	// extract and convert the contained structure
 	// (if this fails, this is a fundamental parser bug => panic ok)
+	specs := _specs.comp.(SourceSinkSpecsAST)
+	timeout := _timeout.comp.(TimeoutAST)
+	onError := _onError.comp.(OnErrorAST)
+	timestampBy := _timestampBy.comp.(TimestampByAST)
 	selectUnion := _selectUnion.comp.(SelectUnionStmt)
+	schema := _schema.comp.(SchemaAST)
 	name := _name.comp.(StreamIdentifier)
+	temporary := _temporary.comp.(BinaryKeyword)
 
 	// assemble the SelectUnionStmt and push it back
-	css := CreateStreamAsSelectUnionStmt{name, selectUnion}
-	se := ParsedComponent{_name.begin, _selectUnion.end, css}
+	css := CreateStreamAsSelectUnionStmt{temporary, name, schema, selectUnion, timestampBy, onError, timeout, specs}
+	se := ParsedComponent{_temporary.begin, _specs.end, css}
 	ps.Push(&se)
 }
 
+// AssembleCreateStreamRoutes takes the topmost elements from the
+// stack, assuming they are components of a CREATE STREAM ROUTES
+// statement, and replaces them by a single CreateStreamRoutesStmt
+// element.
+//
+//  RoutesAST
+//  StreamIdentifier
+//   =>
+//  CreateStreamRoutesStmt{StreamIdentifier, RoutesAST}
+func (ps *parseStack) AssembleCreateStreamRoutes() {
+	// now pop the components from the stack in reverse order
+	_routes, _input := ps.pop2()
+
+	// extract and convert the contained structure
+	// (if this fails, this is a fundamental parser bug => panic ok)
+	routes := _routes.comp.(RoutesAST)
+	input := _input.comp.(StreamIdentifier)
+
+	// assemble the CreateStreamRoutesStmt and push it back
+	s := CreateStreamRoutesStmt{input, routes}
+	se := ParsedComponent{_input.begin, _routes.end, s}
+	ps.Push(&se)
+}
+
+// AssemblePartitionedRoutes takes the elements from the stack that
+// correspond to the input[begin:end] string and wraps a RoutesAST
+// struct with a partition key around them.
+//
+//  Expression
+//  StreamIdentifier
+//  StreamIdentifier
+//   =>
+//  RoutesAST{Expression, [RouteAST{StreamIdentifier, nil}, RouteAST{StreamIdentifier, nil}]}
+func (ps *parseStack) AssemblePartitionedRoutes(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	key := elems[0].(Expression)
+	routes := make([]RouteAST, len(elems)-1)
+	for i, elem := range elems[1:] {
+		routes[i] = RouteAST{elem.(StreamIdentifier), nil}
+	}
+	// push the grouped list back
+	ps.PushComponent(begin, end, RoutesAST{key, routes})
+}
+
+// AssembleConditionalRoutes takes the elements from the stack that
+// correspond to the input[begin:end] string, makes sure they are all
+// RouteAST elements and wraps a RoutesAST struct around them.
+//
+//  RouteAST
+//  RouteAST
+//   =>
+//  RoutesAST{nil, [RouteAST, RouteAST]}
+func (ps *parseStack) AssembleConditionalRoutes(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	routes := make([]RouteAST, len(elems))
+	for i, elem := range elems {
+		routes[i] = elem.(RouteAST)
+	}
+	// push the grouped list back
+	ps.PushComponent(begin, end, RoutesAST{nil, routes})
+}
+
+// AssembleConditionalRoute takes the topmost elements from the stack,
+// assuming they are components of a WHEN ... INTO ... clause, and
+// replaces them by a single RouteAST element.
+//
+//  StreamIdentifier
+//  Expression
+//   =>
+//  RouteAST{StreamIdentifier, Expression}
+func (ps *parseStack) AssembleConditionalRoute() {
+	_name, _cond := ps.pop2()
+
+	name := _name.comp.(StreamIdentifier)
+	cond := _cond.comp.(Expression)
+
+	ps.PushComponent(_cond.begin, _name.end, RouteAST{name, cond})
+}
+
+// AssembleElseRoute takes the topmost element from the stack, assuming
+// it is the component of an ELSE INTO ... clause, and replaces it by a
+// single RouteAST element.
+//
+//  StreamIdentifier
+//   =>
+//  RouteAST{StreamIdentifier, nil}
+func (ps *parseStack) AssembleElseRoute() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	ps.PushComponent(_name.begin, _name.end, RouteAST{name, nil})
+}
+
 // AssembleCreateSource takes the topmost elements from the stack,
 // assuming they are components of a CREATE SOURCE statement, and
 // replaces them by a single CreateSourceStmt element.
 //
 //  BinaryKeyword
+//  TimestampByAST
 //  SourceSinkSpecsAST
+//  InstancesAST
+//  SchemaAST
 //  SourceSinkType
 //  StreamIdentifier
+//  BinaryKeyword
 //   =>
-//  CreateSourceStmt{BinaryKeyword, StreamIdentifier, SourceSinkType,
-//    SourceSinkSpecsAST}
+//  CreateSourceStmt{BinaryKeyword, BinaryKeyword, StreamIdentifier,
+//    SourceSinkType, SchemaAST, InstancesAST, SourceSinkSpecsAST,
+//    TimestampByAST}
 func (ps *parseStack) AssembleCreateSource() {
 	// pop the components from the stack in reverse order
-	_specs, _sourceType, _name, _paused := ps.pop4()
+	_timestampBy, _specs, _instances, _schema, _sourceType, _name, _paused, _temporary := ps.pop8()
 
 	// extract and convert the contained structure
 	// (if this fails, this is a fundamental parser bug => panic ok)
+	timestampBy := _timestampBy.comp.(TimestampByAST)
 	specs := _specs.comp.(SourceSinkSpecsAST)
+	instances := _instances.comp.(InstancesAST)
+	schema := _schema.comp.(SchemaAST)
 	sourceType := _sourceType.comp.(SourceSinkType)
 	name := _name.comp.(StreamIdentifier)
 	paused := _paused.comp.(BinaryKeyword)
+	temporary := _temporary.comp.(BinaryKeyword)
 
 	// assemble the CreateSourceStmt and push it back
-	s := CreateSourceStmt{paused, name, sourceType, specs}
-	se := ParsedComponent{_paused.begin, _specs.end, s}
+	s := CreateSourceStmt{temporary, paused, name, sourceType, schema, instances, specs, timestampBy}
+	se := ParsedComponent{_temporary.begin, _timestampBy.end, s}
 	ps.Push(&se)
 }
 
@@ -192,20 +328,25 @@ func (ps *parseStack) AssembleCreateSource() {
 // assuming they are components of a CREATE SINK statement, and
 // replaces them by a single CreateSinkStmt element.
 //
+//  SinkOrderingAST
 //  SourceSinkSpecsAST
 //  SourceSinkType
 //  StreamIdentifier
+//  BinaryKeyword
 //   =>
-//  CreateSinkStmt{StreamIdentifier, SourceSinkType, SourceSinkSpecsAST}
+//  CreateSinkStmt{BinaryKeyword, StreamIdentifier, SourceSinkType,
+//    SourceSinkSpecsAST, SinkOrderingAST}
 func (ps *parseStack) AssembleCreateSink() {
-	_specs, _sinkType, _name := ps.pop3()
+	_ordering, _specs, _sinkType, _name, _temporary := ps.pop5()
 
+	ordering := _ordering.comp.(SinkOrderingAST)
 	specs := _specs.comp.(SourceSinkSpecsAST)
 	sinkType := _sinkType.comp.(SourceSinkType)
 	name := _name.comp.(StreamIdentifier)
+	temporary := _temporary.comp.(BinaryKeyword)
 
-	s := CreateSinkStmt{name, sinkType, specs}
-	se := ParsedComponent{_name.begin, _specs.end, s}
+	s := CreateSinkStmt{temporary, name, sinkType, specs, ordering}
+	se := ParsedComponent{_temporary.begin, _ordering.end, s}
 	ps.Push(&se)
 }
 
@@ -268,6 +409,23 @@ func (ps *parseStack) AssembleUpdateSource() {
 	ps.Push(&se)
 }
 
+// AssembleSetConfig takes the topmost elements from the stack,
+// assuming they are components of a SET CONFIG statement, and
+// replaces them by a single SetConfigStmt element.
+//
+//  SourceSinkSpecsAST
+//   =>
+//  SetConfigStmt{SourceSinkSpecsAST}
+func (ps *parseStack) AssembleSetConfig() {
+	_specs := ps.Pop()
+
+	specs := _specs.comp.(SourceSinkSpecsAST)
+
+	s := SetConfigStmt{specs}
+	se := ParsedComponent{_specs.begin, _specs.end, s}
+	ps.Push(&se)
+}
+
 // AssembleUpdateSink takes the topmost elements from the stack,
 // assuming they are components of a UPDATE SINK statement, and
 // replaces them by a single UpdateSinkStmt element.
@@ -291,18 +449,25 @@ func (ps *parseStack) AssembleUpdateSink() {
 // assuming they are components of a INSERT ... FROM ... statement, and
 // replaces them by a single InsertIntoFromStmt element.
 //
+//  OnErrorAST
+//  FilterAST
 //  StreamIdentifier
+//  ProjectionsAST
 //  StreamIdentifier
 //   =>
-//  InsertIntoFromStmt{StreamIdentifier, StreamIdentifier}
+//  InsertIntoFromStmt{StreamIdentifier, ProjectionsAST, StreamIdentifier,
+//    FilterAST, OnErrorAST}
 func (ps *parseStack) AssembleInsertIntoFrom() {
-	_input, _sink := ps.pop2()
+	_onError, _filter, _input, _projections, _sink := ps.pop5()
 
+	onError := _onError.comp.(OnErrorAST)
+	filter := _filter.comp.(FilterAST)
 	input := _input.comp.(StreamIdentifier)
+	projections := _projections.comp.(ProjectionsAST)
 	sink := _sink.comp.(StreamIdentifier)
 
-	s := InsertIntoFromStmt{sink, input}
-	se := ParsedComponent{_sink.begin, _input.end, s}
+	s := InsertIntoFromStmt{sink, projections, input, filter, onError}
+	se := ParsedComponent{_sink.begin, _onError.end, s}
 	ps.Push(&se)
 }
 
@@ -391,6 +556,58 @@ func (ps *parseStack) AssembleDropStream() {
 	ps.Push(&se)
 }
 
+// AssembleResumeStream takes the topmost elements from the stack,
+// assuming they are components of a RESUME STREAM statement, and
+// replaces them by a single ResumeStreamStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  ResumeStreamStmt{StreamIdentifier}
+func (ps *parseStack) AssembleResumeStream() {
+	// pop the components from the stack in reverse order
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, ResumeStreamStmt{name}}
+	ps.Push(&se)
+}
+
+// AssembleProfileStream takes the topmost elements from the stack,
+// assuming they are components of a PROFILE STREAM statement, and
+// replaces them by a single ProfileStreamStmt element.
+//
+//  SourceSinkSpecsAST
+//  StreamIdentifier
+//   =>
+//  ProfileStreamStmt{StreamIdentifier, SourceSinkSpecsAST}
+func (ps *parseStack) AssembleProfileStream() {
+	_specs, _name := ps.pop2()
+
+	specs := _specs.comp.(SourceSinkSpecsAST)
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _specs.end, ProfileStreamStmt{name, specs}}
+	ps.Push(&se)
+}
+
+// AssembleReplaySink takes the topmost elements from the stack,
+// assuming they are components of a REPLAY SINK statement, and
+// replaces them by a single ReplaySinkStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  ReplaySinkStmt{StreamIdentifier}
+func (ps *parseStack) AssembleReplaySink() {
+	// pop the components from the stack in reverse order
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, ReplaySinkStmt{name}}
+	ps.Push(&se)
+}
+
 // AssembleDropSink takes the topmost elements from the stack,
 // assuming they are components of a DROP SINK statement, and
 // replaces them by a single DropSinkStmt element.
@@ -425,6 +642,232 @@ func (ps *parseStack) AssembleDropState() {
 	ps.Push(&se)
 }
 
+// AssembleCreateType takes the elements from the stack that correspond
+// to the input[begin:end] string, assuming they are components of a
+// CREATE TYPE statement, and replaces them by a single CreateTypeStmt
+// element.
+//
+//  TypeFieldAST
+//  TypeFieldAST
+//  StreamIdentifier
+//   =>
+//  CreateTypeStmt{StreamIdentifier, [TypeFieldAST, TypeFieldAST]}
+func (ps *parseStack) AssembleCreateType(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	// (if this conversion fails, this is a fundamental parser bug)
+	name := elems[0].(StreamIdentifier)
+	fields := make([]TypeFieldAST, len(elems)-1)
+	for i, elem := range elems[1:] {
+		fields[i] = elem.(TypeFieldAST)
+	}
+	ps.PushComponent(begin, end, CreateTypeStmt{name, fields})
+}
+
+// AssembleTypeField takes the topmost elements from the stack,
+// assuming they are components of a field of a CREATE TYPE statement,
+// and replaces them by a single TypeFieldAST element.
+//
+//  Type
+//  Identifier
+//   =>
+//  TypeFieldAST{Identifier, Type}
+func (ps *parseStack) AssembleTypeField() {
+	_type, _name := ps.pop2()
+
+	t := _type.comp.(Type)
+	name := _name.comp.(Identifier)
+
+	ps.PushComponent(_name.begin, _type.end, TypeFieldAST{string(name), t})
+}
+
+// AssembleDropType takes the topmost elements from the stack,
+// assuming they are components of a DROP TYPE statement, and
+// replaces them by a single DropTypeStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  DropTypeStmt{StreamIdentifier}
+func (ps *parseStack) AssembleDropType() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, DropTypeStmt{name}}
+	ps.Push(&se)
+}
+
+// AssembleCreateTrigger takes the topmost elements from the stack,
+// assuming they are components of a CREATE TRIGGER statement, and
+// replaces them by a single CreateTriggerStmt element.
+//
+//  Any
+//  StringLiteral
+//  StreamIdentifier
+//   =>
+//  CreateTriggerStmt{StreamIdentifier, StringLiteral.Value, Any}
+func (ps *parseStack) AssembleCreateTrigger() {
+	_action, _schedule, _name := ps.pop3()
+
+	action := _action.comp.(fmt.Stringer)
+	schedule := _schedule.comp.(StringLiteral)
+	name := _name.comp.(StreamIdentifier)
+
+	s := CreateTriggerStmt{name, schedule.Value, action}
+	se := ParsedComponent{_name.begin, _action.end, s}
+	ps.Push(&se)
+}
+
+// AssembleTriggerInsert takes the topmost elements from the stack,
+// assuming they are components of an INSERT INTO ... SELECT action of a
+// trigger, and replaces them by a single TriggerInsertStmt element.
+//
+//  ProjectionsAST
+//  StreamIdentifier
+//   =>
+//  TriggerInsertStmt{StreamIdentifier, ProjectionsAST}
+func (ps *parseStack) AssembleTriggerInsert() {
+	_projections, _sink := ps.pop2()
+
+	projections := _projections.comp.(ProjectionsAST)
+	sink := _sink.comp.(StreamIdentifier)
+
+	s := TriggerInsertStmt{sink, projections}
+	se := ParsedComponent{_sink.begin, _projections.end, s}
+	ps.Push(&se)
+}
+
+// AssembleDropTrigger takes the topmost elements from the stack,
+// assuming they are components of a DROP TRIGGER statement, and
+// replaces them by a single DropTriggerStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  DropTriggerStmt{StreamIdentifier}
+func (ps *parseStack) AssembleDropTrigger() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, DropTriggerStmt{name}}
+	ps.Push(&se)
+}
+
+// AssembleCreateTemplate takes the elements from the stack that correspond
+// to the input[begin:end] string, assuming they are components of a
+// CREATE TEMPLATE statement, and replaces them by a single
+// CreateTemplateStmt element.
+//
+//  StringLiteral
+//  Identifier
+//  Identifier
+//  StreamIdentifier
+//   =>
+//  CreateTemplateStmt{StreamIdentifier, [Identifier, Identifier], StringLiteral.Value}
+func (ps *parseStack) AssembleCreateTemplate(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	// (if this conversion fails, this is a fundamental parser bug)
+	name := elems[0].(StreamIdentifier)
+	body := elems[len(elems)-1].(StringLiteral)
+	var params []string
+	for _, elem := range elems[1 : len(elems)-1] {
+		params = append(params, string(elem.(Identifier)))
+	}
+	ps.PushComponent(begin, end, CreateTemplateStmt{name, params, body.Value})
+}
+
+// AssembleInstantiateTemplate takes the topmost elements from the stack,
+// assuming they are components of an INSTANTIATE TEMPLATE statement, and
+// replaces them by a single InstantiateTemplateStmt element.
+//
+//  SourceSinkSpecsAST
+//  Identifier
+//  StreamIdentifier
+//   =>
+//  InstantiateTemplateStmt{StreamIdentifier, Identifier, SourceSinkSpecsAST}
+func (ps *parseStack) AssembleInstantiateTemplate() {
+	_specs, _instance, _name := ps.pop3()
+
+	specs := _specs.comp.(SourceSinkSpecsAST)
+	instance := _instance.comp.(Identifier)
+	name := _name.comp.(StreamIdentifier)
+
+	s := InstantiateTemplateStmt{name, StreamIdentifier(instance), specs}
+	se := ParsedComponent{_name.begin, _specs.end, s}
+	ps.Push(&se)
+}
+
+// AssembleDropTemplateInstance takes the topmost elements from the stack,
+// assuming they are components of a DROP TEMPLATE INSTANCE statement, and
+// replaces them by a single DropTemplateInstanceStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  DropTemplateInstanceStmt{StreamIdentifier}
+func (ps *parseStack) AssembleDropTemplateInstance() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, DropTemplateInstanceStmt{name}}
+	ps.Push(&se)
+}
+
+// AssembleDropTemplate takes the topmost elements from the stack,
+// assuming they are components of a DROP TEMPLATE statement, and
+// replaces them by a single DropTemplateStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  DropTemplateStmt{StreamIdentifier}
+func (ps *parseStack) AssembleDropTemplate() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, DropTemplateStmt{name}}
+	ps.Push(&se)
+}
+
+// AssembleCreateAlert takes the topmost elements from the stack,
+// assuming they are components of a CREATE ALERT statement, and
+// replaces them by a single CreateAlertStmt element.
+//
+//  SourceSinkSpecsAST
+//  FilterAST
+//  StreamIdentifier
+//  StreamIdentifier
+//   =>
+//  CreateAlertStmt{StreamIdentifier, StreamIdentifier, FilterAST,
+//    SourceSinkSpecsAST}
+func (ps *parseStack) AssembleCreateAlert() {
+	_specs, _filter, _input, _name := ps.pop4()
+
+	specs := _specs.comp.(SourceSinkSpecsAST)
+	filter := _filter.comp.(FilterAST)
+	input := _input.comp.(StreamIdentifier)
+	name := _name.comp.(StreamIdentifier)
+
+	s := CreateAlertStmt{name, input, filter, specs}
+	se := ParsedComponent{_name.begin, _specs.end, s}
+	ps.Push(&se)
+}
+
+// AssembleDropAlert takes the topmost elements from the stack,
+// assuming they are components of a DROP ALERT statement, and
+// replaces them by a single DropAlertStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  DropAlertStmt{StreamIdentifier}
+func (ps *parseStack) AssembleDropAlert() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, DropAlertStmt{name}}
+	ps.Push(&se)
+}
+
 // AssembleLoadState takes the topmost elements from the stack,
 // assuming they are components of a LOAD STATE statement, and
 // replaces them by a single LoadStateStmt element.
@@ -494,6 +937,22 @@ func (ps *parseStack) AssembleSaveState() {
 	ps.Push(&se)
 }
 
+// AssembleDescribe takes the topmost element from the stack, assuming
+// it is a component of a DESCRIBE statement, and replaces it by a
+// single DescribeStmt element.
+//
+//  StreamIdentifier
+//   =>
+//  DescribeStmt{StreamIdentifier}
+func (ps *parseStack) AssembleDescribe() {
+	_name := ps.Pop()
+
+	name := _name.comp.(StreamIdentifier)
+
+	se := ParsedComponent{_name.begin, _name.end, DescribeStmt{name}}
+	ps.Push(&se)
+}
+
 // AssembleEval takes the topmost one or two elements from the
 // stack, assuming they are components of an EVAL statement, and
 // replaces them by a single EvalStmt element.
@@ -550,6 +1009,17 @@ func (ps *parseStack) AssembleEmitter() {
 	ps.PushComponent(_emitter.begin, _options.end, EmitterAST{emitter, options})
 }
 
+// AssembleImplicitEmitter pushes an EmitterAST having UnspecifiedEmitter
+// when the emitter of a SELECT statement is omitted.
+//
+//  ...
+//   =>
+//  ...
+//  EmitterAST{UnspecifiedEmitter, nil}
+func (ps *parseStack) AssembleImplicitEmitter(pos int) {
+	ps.PushComponent(pos, pos, EmitterAST{UnspecifiedEmitter, nil})
+}
+
 // AssembleEmitterOptions takes the elements from the stack that
 // correspond to the input[begin:end] string and pushes a slice
 // with all of them back to the stack.
@@ -647,31 +1117,60 @@ func (ps *parseStack) AssembleAlias() {
 
 // AssembleWindowedFrom takes the elements from the stack that
 // correspond to the input[begin:end] string, makes sure they are all
-// AliasedStreamWindowAST elements and wraps a WindowedFromAST struct
-// around them. If there are no such elements, adds an
-// empty WindowedFromAST struct to the stack.
+// AliasedStreamWindowAST elements (followed by StateJoinAST elements,
+// if any) and wraps a WindowedFromAST struct around them. If there
+// are no such elements, adds an empty WindowedFromAST struct to the
+// stack.
 //
 //  AliasedStreamWindowAST
 //  AliasedStreamWindowAST
+//  StateJoinAST
 //   =>
-//  WindowedFromAST{[AliasedStreamWindowAST, AliasedStreamWindowAST]}
+//  WindowedFromAST{[AliasedStreamWindowAST, AliasedStreamWindowAST], [StateJoinAST]}
 func (ps *parseStack) AssembleWindowedFrom(begin int, end int) {
 	if begin == end {
 		// push an empty FROM clause
 		ps.PushComponent(begin, end, WindowedFromAST{})
 	} else {
 		elems := ps.collectElements(begin, end)
-		rels := make([]AliasedStreamWindowAST, len(elems), len(elems))
-		for i, elem := range elems {
+		rels := make([]AliasedStreamWindowAST, 0, len(elems))
+		var joins []StateJoinAST
+		for _, elem := range elems {
+			if j, ok := elem.(StateJoinAST); ok {
+				joins = append(joins, j)
+				continue
+			}
 			// (if this conversion fails, this is a fundamental parser bug)
 			e := elem.(AliasedStreamWindowAST)
-			rels[i] = e
+			rels = append(rels, e)
 		}
 		// push the grouped list back
-		ps.PushComponent(begin, end, WindowedFromAST{rels})
+		ps.PushComponent(begin, end, WindowedFromAST{rels, joins})
 	}
 }
 
+// AssembleStateJoin takes the elements from the stack that correspond
+// to the input[begin:end] string and replaces them by a single
+// StateJoinAST element. The alias is optional.
+//
+//  StreamIdentifier
+//  Identifier
+//  Any
+//   =>
+//  StateJoinAST{StreamIdentifier, Identifier, Any}
+func (ps *parseStack) AssembleStateJoin(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	// (if these conversions fail, this is a fundamental parser bug)
+	name := elems[0].(StreamIdentifier)
+	alias := ""
+	if len(elems) == 3 {
+		alias = string(elems[1].(Identifier))
+	}
+	on := elems[len(elems)-1].(Expression)
+
+	ps.PushComponent(begin, end, StateJoinAST{string(name), alias, on})
+}
+
 // AssembleInterval takes the topmost elements from the stack, assuming
 // they are components of a RANGE clause, and replaces them by
 // a single IntervalAST element.
@@ -743,6 +1242,13 @@ func (ps *parseStack) AssembleFilter(begin int, end int) {
 //  GroupingAST{[Any, Any, Any]}
 func (ps *parseStack) AssembleGrouping(begin int, end int) {
 	elems := ps.collectElements(begin, end)
+	if len(elems) == 1 {
+		// GROUPING SETS and ROLLUP clauses are already assembled
+		if g, ok := elems[0].(GroupingAST); ok {
+			ps.PushComponent(begin, end, g)
+			return
+		}
+	}
 	var exprs []Expression
 	if len(elems) > 0 {
 		exprs = make([]Expression, len(elems))
@@ -751,7 +1257,68 @@ func (ps *parseStack) AssembleGrouping(begin int, end int) {
 		exprs[i] = elems[i].(Expression)
 	}
 	// push the grouped list back
-	ps.PushComponent(begin, end, GroupingAST{exprs})
+	ps.PushComponent(begin, end, GroupingAST{exprs, nil, false})
+}
+
+// AssembleRollup takes the elements from the stack that correspond
+// to the input[begin:end] string and wraps a GroupingAST struct with
+// one grouping set for each prefix of the given expressions around
+// them, starting with the longest one.
+//
+//  Any
+//  Any
+//   =>
+//  GroupingAST{[Any, Any], [[0, 1], [0], []], true}
+func (ps *parseStack) AssembleRollup(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	exprs := make([]Expression, len(elems))
+	for i := range elems {
+		exprs[i] = elems[i].(Expression)
+	}
+	sets := make([][]int, len(exprs)+1)
+	for i := range sets {
+		n := len(exprs) - i
+		set := make([]int, n)
+		for j := 0; j < n; j++ {
+			set[j] = j
+		}
+		sets[i] = set
+	}
+	// push the grouped list back
+	ps.PushComponent(begin, end, GroupingAST{exprs, sets, true})
+}
+
+// AssembleGroupingSets takes the elements from the stack that
+// correspond to the input[begin:end] string, makes sure they are
+// all ExpressionsAST elements and wraps a GroupingAST struct around
+// them. Expressions appearing in more than one grouping set are
+// stored in GroupList only once.
+//
+//  ExpressionsAST
+//  ExpressionsAST
+//   =>
+//  GroupingAST{[Any, Any], [[0, 1], [0]], false}
+func (ps *parseStack) AssembleGroupingSets(begin int, end int) {
+	elems := ps.collectElements(begin, end)
+	exprs := []Expression{}
+	indexes := map[string]int{}
+	sets := make([][]int, len(elems))
+	for i, elem := range elems {
+		set := elem.(ExpressionsAST)
+		sets[i] = make([]int, len(set.Expressions))
+		for j, expr := range set.Expressions {
+			key := expr.String()
+			idx, ok := indexes[key]
+			if !ok {
+				idx = len(exprs)
+				indexes[key] = idx
+				exprs = append(exprs, expr)
+			}
+			sets[i][j] = idx
+		}
+	}
+	// push the grouped list back
+	ps.PushComponent(begin, end, GroupingAST{exprs, sets, false})
 }
 
 /* HAVING clause */
@@ -779,6 +1346,56 @@ func (ps *parseStack) AssembleHaving(begin int, end int) {
 	}
 }
 
+/* READ clause */
+
+// AssembleInputSampling takes the EmitterSampling on top of the stack
+// (if there is a READ clause) and wraps an InputSamplingAST struct
+// around it. If there is no READ clause, an InputSamplingAST with
+// UnspecifiedSamplingType is used.
+//
+//  EmitterSampling
+//   =>
+//  InputSamplingAST{EmitterSampling}
+func (ps *parseStack) AssembleInputSampling(begin int, end int) {
+	if begin == end {
+		// push an empty READ clause
+		ps.PushComponent(begin, end, InputSamplingAST{})
+	} else {
+		// if the stack is empty at this point, this is
+		// a serious parser bug
+		e := ps.Pop()
+		if begin > e.begin || end < e.end {
+			panic("the item on top of the stack is not within given range")
+		}
+		ps.PushComponent(begin, end, InputSamplingAST{e.comp.(EmitterSampling)})
+	}
+}
+
+/* EMIT WHEN clause */
+
+// AssembleEmitWhen takes the expression on top of the stack
+// (if there is an EMIT WHEN clause) and wraps an EmitWhenAST
+// struct around it. If there is no EMIT WHEN clause, an empty
+// EmitWhenAST struct is used.
+//
+//  Any
+//   =>
+//  EmitWhenAST{Any}
+func (ps *parseStack) AssembleEmitWhen(begin int, end int) {
+	if begin == end {
+		// push an empty EMIT WHEN clause
+		ps.PushComponent(begin, end, EmitWhenAST{})
+	} else {
+		// if the stack is empty at this point, this is
+		// a serious parser bug
+		e := ps.Pop()
+		if begin > e.begin || end < e.end {
+			panic("the item on top of the stack is not within given range")
+		}
+		ps.PushComponent(begin, end, EmitWhenAST{e.comp.(Expression)})
+	}
+}
+
 // AssembleAliasedStreamWindow takes the topmost elements from the stack, assuming
 // they are components of an AS clause, and replaces them by
 // a single AliasedStreamWindowAST element.
@@ -820,21 +1437,90 @@ func (ps *parseStack) EnsureAliasedStreamWindow() {
 // they are components of an AS clause, and replaces them by
 // a single StreamWindowAST element.
 //
+//  SheddingOption
+//  NumericLiteral
+//  TumblingAST
 //  IntervalAST
 //  Stream
 //   =>
-//  StreamWindowAST{Stream, IntervalAST}
+//  StreamWindowAST{Stream, IntervalAST, ..., TumblingAST}
 func (ps *parseStack) AssembleStreamWindow() {
 	// pop the components from the stack in reverse order
-	_shedding, _capacity, _range, _rel := ps.pop4()
+	_shedding, _capacity, _tumbling, _range, _rel := ps.pop5()
 
 	rel := _rel.comp.(Stream)
 	rangeAst := _range.comp.(IntervalAST)
+	tumbling := _tumbling.comp.(TumblingAST)
 	capacity := _capacity.comp.(NumericLiteral)
 	shedding := _shedding.comp.(SheddingOption)
 
 	ps.PushComponent(_rel.begin, _shedding.end, StreamWindowAST{rel, rangeAst,
-		capacity.Value, shedding})
+		capacity.Value, shedding, tumbling})
+}
+
+// AssembleTumblingWindow takes the options of a TUMBLING window on top of
+// the stack (if there are any) and pushes a TumblingAST. The IntervalAST of
+// the window is left on the stack.
+//
+//  TumblingAST (optional)
+//  IntervalAST
+//   =>
+//  IntervalAST
+//  TumblingAST{true, ...}
+func (ps *parseStack) AssembleTumblingWindow(begin int, end int) {
+	if _, ok := ps.Peek().comp.(TumblingAST); ok {
+		return
+	}
+	ps.PushComponent(end, end, TumblingAST{Tumbling: true})
+}
+
+// AssembleSessionWindow takes the key of a SESSION window and its option
+// (if there is one) on top of the stack and pushes a TumblingAST having
+// the key. The IntervalAST of the window is left on the stack.
+//
+//  TumblingAST (optional)
+//  Expression
+//  IntervalAST
+//   =>
+//  IntervalAST
+//  TumblingAST{true, ..., Expression}
+func (ps *parseStack) AssembleSessionWindow(begin int, end int) {
+	session := TumblingAST{Tumbling: true}
+	if opt, ok := ps.Peek().comp.(TumblingAST); ok {
+		ps.Pop()
+		session = opt
+	}
+	_key := ps.Pop()
+	session.SessionKey = _key.comp.(Expression)
+	ps.PushComponent(_key.end, end, session)
+}
+
+// AssembleWatermarkDelay takes the IntervalAST on top of the stack and
+// replaces it by a TumblingAST having the interval as the delay of the
+// watermark.
+//
+//  IntervalAST
+//   =>
+//  TumblingAST{true, false, IntervalAST}
+func (ps *parseStack) AssembleWatermarkDelay(begin int, end int) {
+	delay := ps.Pop().comp.(IntervalAST)
+	ps.PushComponent(begin, end, TumblingAST{Tumbling: true, Delay: delay})
+}
+
+// AssembleImplicitStreamWindow takes the topmost element from the stack,
+// assuming it is a stream without a window, and replaces it by a
+// StreamWindowAST having UnspecifiedIntervalUnit as its Unit.
+//
+//  Stream
+//   =>
+//  StreamWindowAST{Stream, IntervalAST{FloatLiteral{0}, UnspecifiedIntervalUnit}, ...}
+func (ps *parseStack) AssembleImplicitStreamWindow() {
+	_rel := ps.Pop()
+
+	rel := _rel.comp.(Stream)
+
+	ps.PushComponent(_rel.begin, _rel.end, StreamWindowAST{rel, IntervalAST{},
+		UnspecifiedCapacity, UnspecifiedSheddingOption, TumblingAST{}})
 }
 
 // AssembleUDSFFuncApp takes the topmost elements from the stack,
@@ -854,6 +1540,24 @@ func (ps *parseStack) AssembleUDSFFuncApp() {
 	ps.Push(&se)
 }
 
+// AssembleUnnestStream takes the topmost elements from the stack, assuming
+// they are components of UNNEST(s, path), and replaces them by a single
+// Stream element.
+//
+//  RowValue
+//  Stream
+//   =>
+//  Stream{UnnestStream, Stream.Name, []Expression{RowValue}}
+func (ps *parseStack) AssembleUnnestStream(begin int, end int) {
+	_path, _stream := ps.pop2()
+
+	path := _path.comp.(RowValue)
+	stream := _stream.comp.(Stream)
+
+	ps.PushComponent(begin, end,
+		Stream{UnnestStream, stream.Name, []Expression{path}})
+}
+
 // EnsureCapacitySpec makes sure that the top element of the stack
 // is a NumericLiteral element.
 func (ps *parseStack) EnsureCapacitySpec(begin int, end int) {
@@ -888,6 +1592,156 @@ func (ps *parseStack) EnsureSheddingSpec(begin int, end int) {
 	}
 }
 
+// AssembleOnError takes the ErrorPolicy (and the number of retries,
+// if given) on top of the stack (if there is an ON ERROR clause) and
+// wraps an OnErrorAST struct around it. If there is no ON ERROR clause,
+// an OnErrorAST with UnspecifiedErrorPolicy is pushed.
+//
+//  NumericLiteral (optional)
+//  ErrorPolicy
+//   =>
+//  OnErrorAST{ErrorPolicy, NumericLiteral}
+func (ps *parseStack) AssembleOnError(begin int, end int) {
+	if begin == end {
+		ps.PushComponent(begin, end, OnErrorAST{UnspecifiedErrorPolicy, UnspecifiedRetries})
+		return
+	}
+	// if the stack is empty at this point, this is
+	// a serious parser bug
+	retries := UnspecifiedRetries
+	top := ps.Pop()
+	if begin > top.begin || end < top.end {
+		panic("the item on top of the stack is not within given range")
+	}
+	if n, ok := top.comp.(NumericLiteral); ok {
+		retries = n.Value
+		top = ps.Pop()
+	}
+	policy := top.comp.(ErrorPolicy)
+	ps.PushComponent(begin, end, OnErrorAST{policy, retries})
+}
+
+// AssembleTimeout takes the IntervalAST on top of the stack (if there
+// is a TIMEOUT clause) and wraps a TimeoutAST struct around it. If there
+// is no TIMEOUT clause, a TimeoutAST with UnspecifiedIntervalUnit is pushed.
+//
+//  IntervalAST
+//   =>
+//  TimeoutAST{IntervalAST}
+func (ps *parseStack) AssembleTimeout(begin int, end int) {
+	if begin == end {
+		ps.PushComponent(begin, end, TimeoutAST{})
+		return
+	}
+	// if the stack is empty at this point, this is
+	// a serious parser bug
+	top := ps.Pop()
+	if begin > top.begin || end < top.end {
+		panic("the item on top of the stack is not within given range")
+	}
+	interval := top.comp.(IntervalAST)
+	ps.PushComponent(begin, end, TimeoutAST{interval})
+}
+
+// AssembleSchema takes the type name on top of the stack (if there is
+// a SCHEMA clause) and wraps a SchemaAST struct around it. If there is
+// no SCHEMA clause, an empty SchemaAST is pushed.
+//
+//  StreamIdentifier
+//   =>
+//  SchemaAST{StreamIdentifier}
+func (ps *parseStack) AssembleSchema(begin int, end int) {
+	if begin == end {
+		ps.PushComponent(begin, end, SchemaAST{})
+		return
+	}
+	// if the stack is empty at this point, this is
+	// a serious parser bug
+	top := ps.Pop()
+	if begin > top.begin || end < top.end {
+		panic("the item on top of the stack is not within given range")
+	}
+	ps.PushComponent(begin, end, SchemaAST{string(top.comp.(StreamIdentifier))})
+}
+
+// AssembleInstances takes the number on top of the stack (if there is
+// an INSTANCES clause) and wraps an InstancesAST struct around it. If
+// there is no INSTANCES clause, UnspecifiedInstances is pushed.
+//
+//  NumericLiteral
+//   =>
+//  InstancesAST{NumericLiteral.Value}
+func (ps *parseStack) AssembleInstances(begin int, end int) {
+	if begin == end {
+		ps.PushComponent(begin, end, InstancesAST{UnspecifiedInstances})
+		return
+	}
+	// if the stack is empty at this point, this is
+	// a serious parser bug
+	top := ps.Pop()
+	if begin > top.begin || end < top.end {
+		panic("the item on top of the stack is not within given range")
+	}
+	ps.PushComponent(begin, end, InstancesAST{top.comp.(NumericLiteral).Value})
+}
+
+// AssembleSinkOrdering takes the ordering policy (and the buffer size,
+// if given) on top of the stack (if there is an ORDER BY clause) and
+// wraps a SinkOrderingAST struct around it. If there is no ORDER BY
+// clause, a SinkOrderingAST with UnspecifiedSinkOrdering is pushed.
+//
+//  NumericLiteral (optional)
+//  SinkOrdering
+//   =>
+//  SinkOrderingAST{SinkOrdering, NumericLiteral}
+func (ps *parseStack) AssembleSinkOrdering(begin int, end int) {
+	if begin == end {
+		ps.PushComponent(begin, end, SinkOrderingAST{UnspecifiedSinkOrdering, UnspecifiedCapacity})
+		return
+	}
+	// if the stack is empty at this point, this is
+	// a serious parser bug
+	size := UnspecifiedCapacity
+	top := ps.Pop()
+	if begin > top.begin || end < top.end {
+		panic("the item on top of the stack is not within given range")
+	}
+	if n, ok := top.comp.(NumericLiteral); ok {
+		size = n.Value
+		top = ps.Pop()
+	}
+	ps.PushComponent(begin, end, SinkOrderingAST{top.comp.(SinkOrdering), size})
+}
+
+// AssembleTimestampBy takes the field (and the format, if given) on
+// top of the stack (if there is a TIMESTAMP BY clause) and wraps a
+// TimestampByAST struct around it. If there is no TIMESTAMP BY clause,
+// an empty TimestampByAST is pushed.
+//
+//  StringLiteral (optional)
+//  Raw
+//   =>
+//  TimestampByAST{Raw, StringLiteral}
+func (ps *parseStack) AssembleTimestampBy(begin int, end int) {
+	if begin == end {
+		ps.PushComponent(begin, end, TimestampByAST{})
+		return
+	}
+	// if the stack is empty at this point, this is
+	// a serious parser bug
+	format := ""
+	top := ps.Pop()
+	if begin > top.begin || end < top.end {
+		panic("the item on top of the stack is not within given range")
+	}
+	if s, ok := top.comp.(StringLiteral); ok {
+		format = s.Value
+		top = ps.Pop()
+	}
+	field := top.comp.(Raw)
+	ps.PushComponent(begin, end, TimestampByAST{field.Expr, format})
+}
+
 // AssembleSourceSinkSpecs takes the elements from the stack that
 // correspond to the input[begin:end] string, makes sure
 // they are all SourceSinkParamAST elements and wraps a SourceSinkSpecsAST
@@ -984,6 +1838,24 @@ func (ps *parseStack) EnsureIdentifier(begin int, end int) {
 	}
 }
 
+// EnsureProjections makes sure that the top element of the stack
+// is a ProjectionsAST element. If there was no ProjectionsAST on top,
+// a ProjectionsAST having nil Projections is pushed there.
+func (ps *parseStack) EnsureProjections(begin int, end int) {
+	top := ps.Peek()
+	if top == nil || top.end <= begin {
+		// there is no item in the given range
+		ps.PushComponent(begin, end, ProjectionsAST{})
+	} else {
+		// there is an item in the given range, don't touch it
+		_, ok := top.comp.(ProjectionsAST)
+		if !ok {
+			panic(fmt.Sprintf("begin (%d) != end (%d), but there "+
+				"was a %T on the stack", begin, end, top.comp))
+		}
+	}
+}
+
 // EnsureKeywordPresent makes sure that the top element of the stack
 // is a BinaryKeyword element.
 func (ps *parseStack) EnsureKeywordPresent(begin int, end int) {
@@ -1103,6 +1975,24 @@ func (ps *parseStack) AssembleTypeCast(begin int, end int) {
 	}
 }
 
+// AssembleRowMetadata takes the topmost element from the stack, assuming
+// it is the key of meta("key"), and replaces it by a RowMetadata element.
+// The relation name is extracted from the given string.
+//
+//  StringLiteral
+//   =>
+//  RowMetadata{Relation, Key}
+func (ps *parseStack) AssembleRowMetadata(begin int, end int, s string) {
+	_key := ps.Pop()
+	key := _key.comp.(StringLiteral)
+
+	rel := ""
+	if i := strings.Index(s, ":"); i >= 0 && i < strings.Index(s, "(") {
+		rel = s[:i]
+	}
+	ps.PushComponent(begin, end, RowMetadata{rel, key.Value})
+}
+
 // AssembleFuncApp takes the topmost elements from the stack, assuming
 // they are components of a function application clause, and replaces
 // them by a single FuncAppAST element.
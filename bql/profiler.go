@@ -0,0 +1,314 @@
+package bql
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// defaultProfilerSamplingRate is the ratio of tuples profiled by
+	// PROFILE STREAM when sampling_rate isn't given.
+	defaultProfilerSamplingRate = 0.1
+
+	// defaultProfilerMaxFields is the maximum number of fields tracked by
+	// PROFILE STREAM when max_fields isn't given.
+	defaultProfilerMaxFields = 256
+
+	// profilerMaxDepth is the maximum depth of nested maps whose fields
+	// are profiled.
+	profilerMaxDepth = 8
+)
+
+// schemaProfiler is a sink inferring the schema of tuples emitted from a
+// source or a stream. It samples tuples and records types, null rates, and
+// ranges of values of each field. Fields of nested maps are recorded with
+// JSON Paths such as "a.b".
+type schemaProfiler struct {
+	m            sync.Mutex
+	samplingRate float64
+	maxFields    int
+
+	numReceived int64
+	numSampled  int64
+	fields      map[string]*fieldProfile
+
+	// numDroppedFields is the number of fields which weren't recorded
+	// because there're already maxFields fields.
+	numDroppedFields int64
+}
+
+type fieldProfile struct {
+	count int64
+	nulls int64
+	types map[data.TypeID]int64
+
+	hasRange bool
+	min      float64
+	max      float64
+}
+
+var (
+	_ core.Statuser = &schemaProfiler{}
+)
+
+func newSchemaProfiler(params data.Map) (*schemaProfiler, error) {
+	p := &schemaProfiler{
+		fields: map[string]*fieldProfile{},
+	}
+	if err := p.update(params); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// update updates parameters of the profiler. Parameters which aren't given
+// are reset to their default values.
+func (p *schemaProfiler) update(params data.Map) error {
+	rate := defaultProfilerSamplingRate
+	maxFields := defaultProfilerMaxFields
+	for k, v := range params {
+		switch k {
+		case "sampling_rate":
+			r, err := data.ToFloat(v)
+			if err != nil {
+				return fmt.Errorf("sampling_rate must be a number: %v", err)
+			}
+			if r <= 0 || r > 1 {
+				return fmt.Errorf("sampling_rate must be in (0, 1]: %v", r)
+			}
+			rate = r
+		case "max_fields":
+			n, err := data.ToInt(v)
+			if err != nil {
+				return fmt.Errorf("max_fields must be an integer: %v", err)
+			}
+			if n <= 0 {
+				return fmt.Errorf("max_fields must be positive: %v", n)
+			}
+			maxFields = int(n)
+		default:
+			return fmt.Errorf("unknown parameter: %v", k)
+		}
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.samplingRate = rate
+	p.maxFields = maxFields
+	return nil
+}
+
+func (p *schemaProfiler) Write(ctx *core.Context, t *core.Tuple) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.numReceived++
+	if p.samplingRate < 1 && rand.Float64() >= p.samplingRate {
+		return nil
+	}
+	p.numSampled++
+	p.profileMap("", t.Data, 0)
+	return nil
+}
+
+func (p *schemaProfiler) profileMap(prefix string, m data.Map, depth int) {
+	for k, v := range m {
+		path := profilerFieldPath(prefix, k)
+		p.profileValue(path, v)
+		if depth+1 >= profilerMaxDepth {
+			continue
+		}
+		if child, ok := v.(data.Map); ok {
+			p.profileMap(path, child, depth+1)
+		}
+	}
+}
+
+func (p *schemaProfiler) profileValue(path string, v data.Value) {
+	f, ok := p.fields[path]
+	if !ok {
+		if len(p.fields) >= p.maxFields {
+			p.numDroppedFields++
+			return
+		}
+		f = &fieldProfile{
+			types: map[data.TypeID]int64{},
+		}
+		p.fields[path] = f
+	}
+	f.count++
+
+	if v.Type() == data.TypeNull {
+		f.nulls++
+		return
+	}
+	f.types[v.Type()]++
+	if v.Type() != data.TypeInt && v.Type() != data.TypeFloat {
+		return
+	}
+	x, err := data.ToFloat(v)
+	if err != nil || math.IsNaN(x) {
+		return
+	}
+	if !f.hasRange || x < f.min {
+		f.min = x
+	}
+	if !f.hasRange || x > f.max {
+		f.max = x
+	}
+	f.hasRange = true
+}
+
+var profilerSimpleFieldRe = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+func profilerFieldPath(prefix, key string) string {
+	if !profilerSimpleFieldRe.MatchString(key) {
+		return fmt.Sprintf(`%s["%s"]`, prefix, strings.Replace(key, `"`, `""`, -1))
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func (p *schemaProfiler) Close(ctx *core.Context) error {
+	return nil
+}
+
+// Status returns the inferred schema.
+func (p *schemaProfiler) Status() data.Map {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	fields := make(data.Map, len(p.fields))
+	for path, f := range p.fields {
+		types := make(data.Map, len(f.types))
+		for t, n := range f.types {
+			types[t.String()] = data.Int(n)
+		}
+		m := data.Map{
+			"types":        types,
+			"count":        data.Int(f.count),
+			"null_rate":    data.Float(profilerRate(f.nulls, p.numSampled)),
+			"missing_rate": data.Float(profilerRate(p.numSampled-f.count, p.numSampled)),
+		}
+		if f.hasRange {
+			m["min"] = data.Float(f.min)
+			m["max"] = data.Float(f.max)
+		}
+		fields[path] = m
+	}
+	return data.Map{
+		"sampling_rate":      data.Float(p.samplingRate),
+		"num_received":       data.Int(p.numReceived),
+		"num_sampled":        data.Int(p.numSampled),
+		"num_dropped_fields": data.Int(p.numDroppedFields),
+		"fields":             fields,
+	}
+}
+
+func profilerRate(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// profilerName returns the name of the sink profiling the node.
+func profilerName(nodeName string) string {
+	return "sensorbee_profiler_" + strings.ToLower(nodeName)
+}
+
+// profileStream creates the schema profiler of a source or a stream. When the
+// node is already profiled, its parameters are updated.
+func (tb *TopologyBuilder) profileStream(stmt *parser.ProfileStreamStmt) (core.Node, error) {
+	name := string(stmt.Name)
+	n, err := tb.topology.Node(name)
+	if err != nil {
+		return nil, err
+	}
+	if n.Type() == core.NTSink {
+		return nil, fmt.Errorf("a sink '%v' cannot be profiled", name)
+	}
+	params, err := tb.mkParamsMap(stmt.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	if p, sn, err := tb.lookUpProfiler(name); err == nil {
+		if err := p.update(params); err != nil {
+			return nil, err
+		}
+		return sn, nil
+	}
+
+	p, err := newSchemaProfiler(params)
+	if err != nil {
+		return nil, err
+	}
+	sn, err := tb.topology.AddSink(profilerName(name), p, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := sn.Input(name, nil); err != nil {
+		tb.topology.Remove(sn.Name())
+		return nil, err
+	}
+	sn.StopOnDisconnect()
+	sn.RemoveOnStop()
+	return sn, nil
+}
+
+func (tb *TopologyBuilder) lookUpProfiler(nodeName string) (*schemaProfiler, core.SinkNode, error) {
+	sn, err := tb.topology.Sink(profilerName(nodeName))
+	if err != nil {
+		return nil, nil, err
+	}
+	p, ok := sn.Sink().(*schemaProfiler)
+	if !ok {
+		return nil, nil, core.NotExistError(fmt.Errorf("'%v' isn't profiled", nodeName))
+	}
+	return p, sn, nil
+}
+
+// Describe returns information of a source or a stream. When the node is
+// profiled by PROFILE STREAM, the result has "inferred_schema" containing the
+// status of the profiler:
+//
+//   - sampling_rate: the ratio of tuples profiled
+//   - num_received: the number of tuples the profiler has received
+//   - num_sampled: the number of tuples the profiler has profiled
+//   - num_dropped_fields: the number of fields not profiled due to max_fields
+//   - fields: a map from the JSON Path of each field to its statistics,
+//     which are "types", "count", "null_rate", "missing_rate", and "min"
+//     and "max" of numeric values
+//
+// "inferred_schema" is null when the node isn't profiled.
+func (tb *TopologyBuilder) Describe(name string) (data.Map, error) {
+	n, err := tb.topology.Node(name)
+	if err != nil {
+		return nil, err
+	}
+	res := data.Map{
+		"name":            data.String(n.Name()),
+		"node_type":       data.String(n.Type().String()),
+		"inferred_schema": data.Null{},
+	}
+	if p, _, err := tb.lookUpProfiler(name); err == nil {
+		res["inferred_schema"] = p.Status()
+	}
+	return res, nil
+}
+
+// RunDescribeStmt returns the information of the node specified in the
+// DESCRIBE statement. See Describe for details.
+func (tb *TopologyBuilder) RunDescribeStmt(stmt *parser.DescribeStmt) (data.Map, error) {
+	return tb.Describe(string(stmt.Name))
+}
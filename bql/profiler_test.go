@@ -0,0 +1,161 @@
+package bql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSchemaProfiler(t *testing.T) {
+	Convey("Given a schema profiler sampling all tuples", t, func() {
+		p, err := newSchemaProfiler(data.Map{"sampling_rate": data.Float(1), "max_fields": data.Int(4)})
+		So(err, ShouldBeNil)
+		ctx := core.NewContext(nil)
+
+		Convey("When writing tuples", func() {
+			for _, d := range []data.Map{
+				{"a": data.Int(1), "b": data.Map{"c": data.String("x")}},
+				{"a": data.Float(-2.5), "b": data.Null{}},
+				{"a": data.Null{}, "d e": data.True},
+				{"f": data.Int(1)},
+			} {
+				So(p.Write(ctx, core.NewTuple(d)), ShouldBeNil)
+			}
+			st := p.Status()
+			field := func(path string) data.Map {
+				v, err := st.Get(data.MustCompilePath("fields"))
+				So(err, ShouldBeNil)
+				f, _ := data.AsMap(v)
+				m, _ := data.AsMap(f[path])
+				return m
+			}
+
+			Convey("Then it should infer types and ranges", func() {
+				a := field("a")
+				So(a["types"], ShouldResemble, data.Map{"int": data.Int(1), "float": data.Int(1)})
+				So(a["count"], ShouldEqual, data.Int(3))
+				So(a["null_rate"], ShouldEqual, data.Float(0.25))
+				So(a["missing_rate"], ShouldEqual, data.Float(0.25))
+				So(a["min"], ShouldEqual, data.Float(-2.5))
+				So(a["max"], ShouldEqual, data.Float(1))
+			})
+
+			Convey("Then it should record nested fields and escape keys", func() {
+				So(field("b.c")["types"], ShouldResemble, data.Map{"string": data.Int(1)})
+				So(field(`["d e"]`)["types"], ShouldResemble, data.Map{"bool": data.Int(1)})
+			})
+
+			Convey("Then it should limit the number of fields", func() {
+				So(field("f"), ShouldBeNil)
+				So(st["num_dropped_fields"], ShouldEqual, data.Int(1))
+				So(st["num_sampled"], ShouldEqual, data.Int(4))
+			})
+		})
+	})
+
+	Convey("Given invalid parameters", t, func() {
+		for _, params := range []data.Map{
+			{"sampling_rate": data.Float(0)},
+			{"sampling_rate": data.Float(1.5)},
+			{"max_fields": data.Int(0)},
+			{"no_such_param": data.Int(1)},
+		} {
+			Convey("Then creating a profiler should fail with "+params.String(), func() {
+				_, err := newSchemaProfiler(params)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
+
+func TestProfileStream(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a paused source", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy;`), ShouldBeNil)
+
+		describe := func(name string) (data.Map, error) {
+			stmt, _, err := parser.New().ParseStmt("DESCRIBE " + name)
+			So(err, ShouldBeNil)
+			s := stmt.(parser.DescribeStmt)
+			return tb.RunDescribeStmt(&s)
+		}
+
+		Convey("When describing the source without profiling", func() {
+			d, err := describe("s")
+
+			Convey("Then it shouldn't have the inferred schema", func() {
+				So(err, ShouldBeNil)
+				So(d["node_type"], ShouldEqual, data.String("source"))
+				So(d["inferred_schema"], ShouldResemble, data.Null{})
+			})
+		})
+
+		Convey("When profiling the source", func() {
+			So(addBQLToTopology(tb, `PROFILE STREAM s WITH sampling_rate=1.0;
+				RESUME SOURCE s;`), ShouldBeNil)
+			var d data.Map
+			for i := 0; i < 1000; i++ {
+				d, err = describe("s")
+				So(err, ShouldBeNil)
+				if n, _ := d.Get(data.MustCompilePath("inferred_schema.num_sampled")); n == data.Int(4) {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then DESCRIBE should return the inferred schema", func() {
+				v, err := d.Get(data.MustCompilePath("inferred_schema.fields.int"))
+				So(err, ShouldBeNil)
+				f, _ := data.AsMap(v)
+				So(f["types"], ShouldResemble, data.Map{"int": data.Int(4)})
+				So(f["min"], ShouldEqual, data.Float(1))
+				So(f["max"], ShouldEqual, data.Float(4))
+			})
+
+			Convey("Then profiling it again should update the parameters", func() {
+				So(addBQLToTopology(tb, `PROFILE STREAM s WITH sampling_rate=0.5;`), ShouldBeNil)
+				d, err := describe("s")
+				So(err, ShouldBeNil)
+				v, _ := d.Get(data.MustCompilePath("inferred_schema.sampling_rate"))
+				So(v, ShouldEqual, data.Float(0.5))
+			})
+
+			Convey("Then dropping the source should remove the profiler", func() {
+				So(addBQLToTopology(tb, `DROP SOURCE s;`), ShouldBeNil)
+				removed := false
+				for i := 0; i < 1000 && !removed; i++ {
+					_, err := dt.Sink(profilerName("s"))
+					removed = core.IsNotExist(err)
+					time.Sleep(time.Millisecond)
+				}
+				So(removed, ShouldBeTrue)
+			})
+		})
+
+		Convey("When profiling a sink", func() {
+			So(addBQLToTopology(tb, `CREATE SINK k TYPE collector;`), ShouldBeNil)
+			err := addBQLToTopology(tb, `PROFILE STREAM k;`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When describing a nonexistent node", func() {
+			_, err := describe("no_such_node")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
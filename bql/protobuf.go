@@ -0,0 +1,161 @@
+package bql
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// ProtobufDecoder decodes binary protobuf messages into data.Map by using a
+// message descriptor loaded at runtime, so that sources can receive protobuf
+// payloads without having code generated for each message type.
+//
+// Fields are stored with their names in .proto files. Nested messages are
+// converted to maps, repeated fields to arrays, and map fields to maps whose
+// keys are strings. Values of enums are converted to the names of the
+// values. google.protobuf.Timestamp is converted to a timestamp. Fields of
+// a oneof which aren't set and message fields which aren't set are omitted.
+type ProtobufDecoder struct {
+	md protoreflect.MessageDescriptor
+}
+
+// NewProtobufDecoder creates a ProtobufDecoder of the message from a
+// FileDescriptorSet file, which can be created by protoc with the
+// --descriptor_set_out and --include_imports options. messageName is the
+// fully-qualified name of the message such as "package.Message".
+func NewProtobufDecoder(descriptorSetPath, messageName string) (*ProtobufDecoder, error) {
+	b, err := ioutil.ReadFile(descriptorSetPath)
+	if err != nil {
+		return nil, err
+	}
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, fds); err != nil {
+		return nil, fmt.Errorf("cannot parse the descriptor set: %v", err)
+	}
+	return NewProtobufDecoderFromDescriptorSet(fds, messageName)
+}
+
+// NewProtobufDecoderFromDescriptorSet creates a ProtobufDecoder of the message
+// from a FileDescriptorSet.
+func NewProtobufDecoderFromDescriptorSet(fds *descriptorpb.FileDescriptorSet, messageName string) (*ProtobufDecoder, error) {
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %v", err)
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the message '%v': %v", messageName, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("'%v' isn't a message", messageName)
+	}
+	return &ProtobufDecoder{
+		md: md,
+	}, nil
+}
+
+// Decode decodes a binary protobuf message.
+func (d *ProtobufDecoder) Decode(b []byte) (data.Map, error) {
+	m := dynamicpb.NewMessage(d.md)
+	if err := proto.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return protobufMessageToMap(m), nil
+}
+
+func protobufMessageToMap(m protoreflect.Message) data.Map {
+	fields := m.Descriptor().Fields()
+	res := make(data.Map, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.ContainingOneof() != nil || (fd.Message() != nil && !fd.IsList() && !fd.IsMap()) {
+			if !m.Has(fd) {
+				continue
+			}
+		}
+		res[string(fd.Name())] = protobufFieldToValue(fd, m.Get(fd))
+	}
+	return res
+}
+
+func protobufFieldToValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) data.Value {
+	switch {
+	case fd.IsList():
+		l := v.List()
+		a := make(data.Array, l.Len())
+		for i := range a {
+			a[i] = protobufScalarToValue(fd, l.Get(i))
+		}
+		return a
+	case fd.IsMap():
+		res := data.Map{}
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			res[k.String()] = protobufScalarToValue(fd.MapValue(), v)
+			return true
+		})
+		return res
+	}
+	return protobufScalarToValue(fd, v)
+}
+
+func protobufScalarToValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) data.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return data.Bool(v.Bool())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return data.Int(v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return data.Float(u)
+		}
+		return data.Int(v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return data.Float(v.Float())
+	case protoreflect.StringKind:
+		return data.String(v.String())
+	case protoreflect.BytesKind:
+		return data.Blob(v.Bytes())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return data.String(ev.Name())
+		}
+		return data.Int(v.Enum()) // unknown values
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message()
+		if m.Descriptor().FullName() == "google.protobuf.Timestamp" {
+			fs := m.Descriptor().Fields()
+			return data.Timestamp(time.Unix(m.Get(fs.ByName("seconds")).Int(),
+				m.Get(fs.ByName("nanos")).Int()).UTC())
+		}
+		return protobufMessageToMap(m)
+	}
+	return data.Null{}
+}
+
+// newProtobufDecoderCreator returns a recordDecoderCreator decoding a stream
+// of length-delimited protobuf messages.
+func newProtobufDecoderCreator(params data.Map) (recordDecoderCreator, error) {
+	c := &struct {
+		DescriptorSet string `bql:",required"`
+		Message       string `bql:",required"`
+	}{}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	pd, err := NewProtobufDecoder(c.DescriptorSet, c.Message)
+	if err != nil {
+		return nil, err
+	}
+	return newDelimitedDecoderCreator("protobuf", pd.Decode), nil
+}
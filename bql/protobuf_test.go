@@ -0,0 +1,262 @@
+package bql
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// testProtobufDescriptorSet returns a descriptor set equivalent to the
+// following .proto file and google/protobuf/timestamp.proto:
+//
+//	syntax = "proto3";
+//	package sbtest;
+//	import "google/protobuf/timestamp.proto";
+//
+//	message Reading {
+//	  enum Unit {
+//	    CELSIUS = 0;
+//	    FAHRENHEIT = 1;
+//	  }
+//	  message Location {
+//	    double lat = 1;
+//	    double lon = 2;
+//	  }
+//	  string sensor_id = 1;
+//	  double value = 2;
+//	  Unit unit = 3;
+//	  Location location = 4;
+//	  repeated int32 samples = 5;
+//	  map<string, string> labels = 6;
+//	  google.protobuf.Timestamp ts = 7;
+//	  bytes raw = 8;
+//	  uint64 seq = 9;
+//	}
+func testProtobufDescriptorSet() *descriptorpb.FileDescriptorSet {
+	field := func(name string, num int32, t descriptorpb.FieldDescriptorProto_Type, typeName string,
+		label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     t.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	opt := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	rep := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("sbtest/reading.proto"),
+		Package:    proto.String("sbtest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Reading"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("sensor_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", opt),
+				field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "", opt),
+				field("unit", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".sbtest.Reading.Unit", opt),
+				field("location", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".sbtest.Reading.Location", opt),
+				field("samples", 5, descriptorpb.FieldDescriptorProto_TYPE_INT32, "", rep),
+				field("labels", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".sbtest.Reading.LabelsEntry", rep),
+				field("ts", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp", opt),
+				field("raw", 8, descriptorpb.FieldDescriptorProto_TYPE_BYTES, "", opt),
+				field("seq", 9, descriptorpb.FieldDescriptorProto_TYPE_UINT64, "", opt),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Location"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("lat", 1, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "", opt),
+					field("lon", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, "", opt),
+				},
+			}, {
+				Name: proto.String("LabelsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", opt),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", opt),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name: proto.String("Unit"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("CELSIUS"), Number: proto.Int32(0)},
+					{Name: proto.String("FAHRENHEIT"), Number: proto.Int32(1)},
+				},
+			}},
+		}},
+	}
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			fd,
+		},
+	}
+}
+
+func TestProtobufDecoder(t *testing.T) {
+	fds := testProtobufDescriptorSet()
+	ts := time.Date(2015, time.April, 10, 10, 23, 0, 0, time.UTC)
+
+	// newReading creates a binary Reading message.
+	newReading := func(id string, full bool) []byte {
+		files, err := protodesc.NewFiles(fds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := files.FindDescriptorByName("sbtest.Reading")
+		if err != nil {
+			t.Fatal(err)
+		}
+		md := d.(protoreflect.MessageDescriptor)
+		m := dynamicpb.NewMessage(md)
+		fs := md.Fields()
+		m.Set(fs.ByName("sensor_id"), protoreflect.ValueOfString(id))
+		if full {
+			m.Set(fs.ByName("value"), protoreflect.ValueOfFloat64(21.5))
+			m.Set(fs.ByName("unit"), protoreflect.ValueOfEnum(1))
+			loc := m.Mutable(fs.ByName("location")).Message()
+			loc.Set(loc.Descriptor().Fields().ByName("lat"), protoreflect.ValueOfFloat64(35.5))
+			l := m.Mutable(fs.ByName("samples")).List()
+			l.Append(protoreflect.ValueOfInt32(1))
+			l.Append(protoreflect.ValueOfInt32(-2))
+			m.Mutable(fs.ByName("labels")).Map().Set(protoreflect.ValueOfString("room").MapKey(),
+				protoreflect.ValueOfString("a"))
+			tm := m.Mutable(fs.ByName("ts")).Message()
+			tfs := tm.Descriptor().Fields()
+			tm.Set(tfs.ByName("seconds"), protoreflect.ValueOfInt64(ts.Unix()))
+			m.Set(fs.ByName("raw"), protoreflect.ValueOfBytes([]byte{1, 2}))
+			m.Set(fs.ByName("seq"), protoreflect.ValueOfUint64(1<<63))
+		}
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	Convey("Given a protobuf decoder", t, func() {
+		d, err := NewProtobufDecoderFromDescriptorSet(fds, "sbtest.Reading")
+		So(err, ShouldBeNil)
+
+		Convey("When decoding a message having all fields", func() {
+			m, err := d.Decode(newReading("s1", true))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be converted to a map", func() {
+				So(m, ShouldResemble, data.Map{
+					"sensor_id": data.String("s1"),
+					"value":     data.Float(21.5),
+					"unit":      data.String("FAHRENHEIT"),
+					"location":  data.Map{"lat": data.Float(35.5), "lon": data.Float(0)},
+					"samples":   data.Array{data.Int(1), data.Int(-2)},
+					"labels":    data.Map{"room": data.String("a")},
+					"ts":        data.Timestamp(ts),
+					"raw":       data.Blob([]byte{1, 2}),
+					"seq":       data.Float(1 << 63),
+				})
+			})
+		})
+
+		Convey("When decoding a message having only a few fields", func() {
+			m, err := d.Decode(newReading("s2", false))
+			So(err, ShouldBeNil)
+
+			Convey("Then unset message fields should be omitted", func() {
+				So(m, ShouldResemble, data.Map{
+					"sensor_id": data.String("s2"),
+					"value":     data.Float(0),
+					"unit":      data.String("CELSIUS"),
+					"samples":   data.Array{},
+					"labels":    data.Map{},
+					"raw":       data.Blob(nil),
+					"seq":       data.Int(0),
+				})
+			})
+		})
+
+		Convey("When decoding a broken message", func() {
+			_, err := d.Decode([]byte{0xff, 0xff})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a decoder of an undefined message", func() {
+			_, err := NewProtobufDecoderFromDescriptorSet(fds, "sbtest.NoSuchMessage")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a file having length-delimited protobuf messages", t, func() {
+		dir, err := ioutil.TempDir("", "sbtest_bql_protobuf")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		b, err := proto.Marshal(fds)
+		So(err, ShouldBeNil)
+		descPath := dir + "/reading.pb"
+		So(ioutil.WriteFile(descPath, b, 0644), ShouldBeNil)
+
+		var input []byte
+		for _, msg := range [][]byte{newReading("s1", false), {0xff, 0xff}, newReading("s2", false)} {
+			input = binary.AppendUvarint(input, uint64(len(msg)))
+			input = append(input, msg...)
+		}
+		path := dir + "/readings.bin"
+		So(ioutil.WriteFile(path, input, 0644), ShouldBeNil)
+
+		Convey("When reading the file by file source", func() {
+			ctx := core.NewContext(nil)
+			w := &testFileWriter{}
+			w.c = sync.NewCond(&w.m)
+			s, err := createFileSource(ctx, &IOParams{}, data.Map{
+				"path":           data.String(path),
+				"format":         data.String("protobuf"),
+				"descriptor_set": data.String(descPath),
+				"message":        data.String("sbtest.Reading"),
+			})
+			So(err, ShouldBeNil)
+			So(s.GenerateStream(ctx, w), ShouldBeNil)
+
+			Convey("Then it should emit valid messages", func() {
+				So(w.cnt, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When creating a file source without a message name", func() {
+			_, err := createFileSource(core.NewContext(nil), &IOParams{}, data.Map{
+				"path":           data.String(path),
+				"format":         data.String("protobuf"),
+				"descriptor_set": data.String(descPath),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
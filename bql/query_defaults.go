@@ -0,0 +1,141 @@
+package bql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+// Keys of the config store of a topology controlling clauses omitted in
+// SELECT statements:
+//
+//   - default_emitter: the emitter used when it's omitted, which is one of
+//     "istream", "dstream", or "rstream"
+//   - default_range: the window used when it's omitted, such as "1 tuples",
+//     "10 seconds", or "500 milliseconds"
+//   - require_explicit_window: when it's true, every input stream must have
+//     a window even if default_range is set
+//
+// A statement omitting the emitter or a window fails when the corresponding
+// default isn't configured.
+const (
+	defaultEmitterConfig        = "default_emitter"
+	defaultRangeConfig          = "default_range"
+	requireExplicitWindowConfig = "require_explicit_window"
+)
+
+var defaultRangeRegexp = regexp.MustCompile(`(?i)^\s*([0-9]+(?:\.[0-9]+)?)\s+(tuples|seconds|milliseconds)\s*$`)
+
+// queryDefaults has defaults of clauses omitted in SELECT statements.
+type queryDefaults struct {
+	emitter       parser.Emitter
+	rangeAST      parser.IntervalAST
+	requireWindow bool
+}
+
+// queryDefaults reads defaults from the config store of the topology.
+func (tb *TopologyBuilder) queryDefaults() (*queryDefaults, error) {
+	conf := tb.topology.Context().Config
+	d := &queryDefaults{}
+
+	if s, err := conf.GetString(defaultEmitterConfig); err == nil {
+		switch strings.ToLower(s) {
+		case "istream":
+			d.emitter = parser.Istream
+		case "dstream":
+			d.emitter = parser.Dstream
+		case "rstream":
+			d.emitter = parser.Rstream
+		default:
+			return nil, fmt.Errorf(`%v must be "istream", "dstream", or "rstream": %v`, defaultEmitterConfig, s)
+		}
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("%v must be a string: %v", defaultEmitterConfig, err)
+	}
+
+	if s, err := conf.GetString(defaultRangeConfig); err == nil {
+		r, err := parseDefaultRange(s)
+		if err != nil {
+			return nil, err
+		}
+		d.rangeAST = r
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("%v must be a string: %v", defaultRangeConfig, err)
+	}
+
+	if b, err := conf.GetBool(requireExplicitWindowConfig); err == nil {
+		d.requireWindow = b
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("%v must be a bool: %v", requireExplicitWindowConfig, err)
+	}
+	return d, nil
+}
+
+// parseDefaultRange parses a range having the same form as the RANGE clause
+// without the keyword, e.g. "5 tuples".
+func parseDefaultRange(s string) (parser.IntervalAST, error) {
+	m := defaultRangeRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return parser.IntervalAST{}, fmt.Errorf(`%v must have the form "<number> tuples|seconds|milliseconds": %v`,
+			defaultRangeConfig, s)
+	}
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil || v <= 0 {
+		return parser.IntervalAST{}, fmt.Errorf("%v must be positive: %v", defaultRangeConfig, s)
+	}
+	var unit parser.IntervalUnit
+	switch strings.ToLower(m[2]) {
+	case "tuples":
+		if v != float64(int64(v)) {
+			return parser.IntervalAST{}, fmt.Errorf("%v must be an integer when its unit is tuples: %v",
+				defaultRangeConfig, s)
+		}
+		unit = parser.Tuples
+	case "seconds":
+		unit = parser.Seconds
+	case "milliseconds":
+		unit = parser.Milliseconds
+	}
+	return parser.IntervalAST{FloatLiteral: parser.FloatLiteral{Value: v}, Unit: unit}, nil
+}
+
+// applyQueryDefaults fills the emitter and windows omitted in the statement
+// with defaults configured in the topology. It returns an error when there's
+// no default or the topology requires explicit windows.
+func (tb *TopologyBuilder) applyQueryDefaults(stmt *parser.SelectStmt) error {
+	d, err := tb.queryDefaults()
+	if err != nil {
+		return err
+	}
+
+	if stmt.EmitterType == parser.UnspecifiedEmitter {
+		if d.emitter == parser.UnspecifiedEmitter {
+			return fmt.Errorf("the emitter must be specified because %v isn't configured", defaultEmitterConfig)
+		}
+		stmt.EmitterType = d.emitter
+	}
+
+	// Relations is copied so that the original statement isn't modified.
+	rels := make([]parser.AliasedStreamWindowAST, len(stmt.Relations))
+	copy(rels, stmt.Relations)
+	for i, rel := range rels {
+		if rel.Unit != parser.UnspecifiedIntervalUnit {
+			continue
+		}
+		if d.requireWindow {
+			return fmt.Errorf("the window of '%v' must be specified because %v is true",
+				rel.Name, requireExplicitWindowConfig)
+		}
+		if d.rangeAST.Unit == parser.UnspecifiedIntervalUnit {
+			return fmt.Errorf("the window of '%v' must be specified because %v isn't configured",
+				rel.Name, defaultRangeConfig)
+		}
+		rels[i].IntervalAST = d.rangeAST
+	}
+	stmt.Relations = rels
+	return nil
+}
@@ -0,0 +1,86 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestQueryDefaults(t *testing.T) {
+	Convey("Given a topology builder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;`), ShouldBeNil)
+		conf := dt.Context().Config
+
+		Convey("When defaults aren't configured", func() {
+			Convey("Then a statement omitting the emitter should fail", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT * FROM s [RANGE 1 TUPLES];`), ShouldNotBeNil)
+			})
+
+			Convey("Then a statement omitting the window should fail", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s;`), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When defaults are configured", func() {
+			conf.Set("default_emitter", data.String("rstream"))
+			conf.Set("default_range", data.String("1 TUPLES"))
+
+			Convey("Then a statement omitting them should use the defaults", func() {
+				So(addBQLToTopology(tb, `
+					CREATE STREAM t AS SELECT * FROM s;
+					CREATE SINK k TYPE collector;
+					INSERT INTO k FROM t;
+					RESUME SOURCE s;`), ShouldBeNil)
+				sink, err := dt.Sink("k")
+				So(err, ShouldBeNil)
+				si := sink.Sink().(*tupleCollectorSink)
+				si.Wait(4)
+				So(si.len(), ShouldEqual, 4)
+			})
+
+			Convey("Then explicit clauses should take precedence", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT ISTREAM * FROM s [RANGE 2 SECONDS];`), ShouldBeNil)
+				n, err := dt.Box("t")
+				So(err, ShouldBeNil)
+				stmt := n.Box().(*bqlBox).stmt
+				So(stmt.String(), ShouldEqual, "SELECT ISTREAM * FROM s [RANGE 2 SECONDS]")
+			})
+
+			Convey("Then the defaults should be filled in the statement of the box", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT * FROM s;`), ShouldBeNil)
+				n, err := dt.Box("t")
+				So(err, ShouldBeNil)
+				stmt := n.Box().(*bqlBox).stmt
+				So(stmt.String(), ShouldEqual, "SELECT RSTREAM * FROM s [RANGE 1 TUPLES]")
+			})
+
+			Convey("Then a statement omitting the window should fail in the strict mode", func() {
+				conf.Set("require_explicit_window", data.True)
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT * FROM s;`), ShouldNotBeNil)
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT * FROM s [RANGE 1 TUPLES];`), ShouldBeNil)
+			})
+		})
+
+		Convey("When defaults are invalid", func() {
+			Convey("Then an invalid emitter should be rejected", func() {
+				conf.Set("default_emitter", data.String("xstream"))
+				So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT * FROM s [RANGE 1 TUPLES];`), ShouldNotBeNil)
+			})
+
+			Convey("Then an invalid range should be rejected", func() {
+				conf.Set("default_emitter", data.String("istream"))
+				for _, r := range []string{"1", "1.5 tuples", "0 seconds", "1 minutes"} {
+					conf.Set("default_range", data.String(r))
+					So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT * FROM s;`), ShouldNotBeNil)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,158 @@
+package bql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Keys configuring queues of edges connected to a node. CREATE STREAM
+// applies them to all inputs of the stream, and CREATE SINK applies them to
+// all inputs added to the sink by INSERT INTO statements:
+//
+//	CREATE STREAM s AS SELECT RSTREAM * FROM t [RANGE 1 TUPLES]
+//	  WITH buffer_size = 1024, drop_mode = "oldest";
+//	CREATE SINK snk TYPE stdout WITH drop_mode = "latest_only";
+//
+//   - buffer_size: the capacity of each queue
+//   - drop_mode: what to do when a queue is full, which is one of "wait"
+//     (also "block"), "oldest", "newest" (also "latest"), or
+//     "latest_only". "latest_only" keeps only the latest tuple and cannot
+//     be used with buffer_size
+//
+// BUFFER SIZE and IF FULL given to a relation in the FROM clause override
+// them. The parameters aren't passed to creators of sinks.
+const (
+	bufferSizeParam = "buffer_size"
+	dropModeParam   = "drop_mode"
+)
+
+// queueConfig is a config of queues of edges given by the WITH clause.
+type queueConfig struct {
+	// capacity is the capacity of queues. The default capacity is used when
+	// it's 0.
+	capacity int
+	dropMode core.QueueDropMode
+}
+
+// extractQueueConfig removes the queue parameters from params and returns
+// the config given by them.
+func extractQueueConfig(params data.Map) (queueConfig, error) {
+	var c queueConfig
+	if v, ok := params[bufferSizeParam]; ok {
+		delete(params, bufferSizeParam)
+		n, err := data.AsInt(v)
+		if err != nil {
+			return c, fmt.Errorf("'%v' parameter must be an integer: %v", bufferSizeParam, err)
+		}
+		if n <= 0 || n > int64(core.MaxCapacity) {
+			return c, fmt.Errorf("'%v' parameter must be in [1, %v]: %v",
+				bufferSizeParam, core.MaxCapacity, n)
+		}
+		c.capacity = int(n)
+	}
+
+	v, ok := params[dropModeParam]
+	if !ok {
+		return c, nil
+	}
+	delete(params, dropModeParam)
+	s, err := data.AsString(v)
+	if err != nil {
+		return c, fmt.Errorf("'%v' parameter must be a string: %v", dropModeParam, err)
+	}
+	switch strings.ToLower(s) {
+	case "wait", "block":
+		c.dropMode = core.DropNone
+	case "oldest":
+		c.dropMode = core.DropOldest
+	case "newest", "latest":
+		c.dropMode = core.DropLatest
+	case "latest_only":
+		if c.capacity != 0 {
+			return c, fmt.Errorf("'%v' parameter cannot be used with %v=\"latest_only\"",
+				bufferSizeParam, dropModeParam)
+		}
+		// dropping the oldest tuple from a queue having only one tuple keeps
+		// the latest one
+		c.capacity = 1
+		c.dropMode = core.DropOldest
+	default:
+		return c, fmt.Errorf("invalid '%v' parameter: %v", dropModeParam, s)
+	}
+	return c, nil
+}
+
+// streamQueueConfig returns the queue config given by the WITH clause of a
+// CREATE STREAM statement. It also returns the parameters other than the
+// ones of the config.
+func (tb *TopologyBuilder) streamQueueConfig(params []parser.SourceSinkParamAST) (queueConfig, []parser.SourceSinkParamAST, error) {
+	var queueParams, rest []parser.SourceSinkParamAST
+	for _, param := range params {
+		switch string(param.Key) {
+		case bufferSizeParam, dropModeParam:
+			queueParams = append(queueParams, param)
+		default:
+			rest = append(rest, param)
+		}
+	}
+	m, err := tb.mkParamsMap(queueParams)
+	if err != nil {
+		return queueConfig{}, nil, err
+	}
+	c, err := extractQueueConfig(m)
+	if err != nil {
+		return queueConfig{}, nil, err
+	}
+	return c, rest, nil
+}
+
+// boxInputConfig sets the capacity and the drop mode of conf for the
+// relation. BUFFER SIZE and IF FULL of the relation take precedence over
+// the config.
+func (c queueConfig) boxInputConfig(rel *parser.AliasedStreamWindowAST, conf *core.BoxInputConfig) error {
+	conf.Capacity = c.capacity
+	conf.DropMode = c.dropMode
+	// set capacity of input pipe
+	if rel.Capacity != parser.UnspecifiedCapacity {
+		if rel.Capacity > math.MaxInt32 {
+			return fmt.Errorf("specified buffer capacity %d is too large", rel.Capacity)
+		} else if rel.Capacity < 0 {
+			// the parser should not allow this to happen, actually
+			return fmt.Errorf("specified buffer capacity %d must not be negative", rel.Capacity)
+		}
+		conf.Capacity = int(rel.Capacity)
+	}
+	// set drop mode for box
+	if rel.Shedding == parser.DropOldest {
+		conf.DropMode = core.DropOldest
+	} else if rel.Shedding == parser.DropNewest {
+		conf.DropMode = core.DropLatest
+	} else if rel.Shedding == parser.Wait {
+		conf.DropMode = core.DropNone
+	}
+	return nil
+}
+
+// setSinkQueueConfig records the queue config of a sink created by CREATE
+// SINK, which is applied to inputs added by INSERT INTO.
+func (tb *TopologyBuilder) setSinkQueueConfig(name string, c queueConfig) {
+	tb.sinkQueueMutex.Lock()
+	defer tb.sinkQueueMutex.Unlock()
+	if c == (queueConfig{}) {
+		delete(tb.sinkQueues, strings.ToLower(name))
+		return
+	}
+	tb.sinkQueues[strings.ToLower(name)] = c
+}
+
+// sinkQueueConfig returns the queue config of the sink.
+func (tb *TopologyBuilder) sinkQueueConfig(name string) queueConfig {
+	tb.sinkQueueMutex.Lock()
+	defer tb.sinkQueueMutex.Unlock()
+	return tb.sinkQueues[strings.ToLower(name)]
+}
@@ -0,0 +1,115 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestQueueConfig(t *testing.T) {
+	Convey("Given a topology builder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE source TYPE dummy WITH num=4;
+			CREATE PAUSED SOURCE source2 TYPE dummy WITH num=4;`), ShouldBeNil)
+
+		queueSize := func(st data.Map, input string) data.Value {
+			inputs := st["input_stats"].(data.Map)["inputs"].(data.Map)
+			So(inputs, ShouldContainKey, input)
+			return inputs[input].(data.Map)["queue_size"]
+		}
+
+		Convey("When creating a stream with queue parameters", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM box AS SELECT RSTREAM * FROM
+				source [RANGE 1 TUPLES], source2 [RANGE 1 TUPLES, BUFFER SIZE 3]
+				WITH buffer_size=10, drop_mode="oldest"`), ShouldBeNil)
+			bn, err := dt.Box("box")
+			So(err, ShouldBeNil)
+
+			Convey("Then they should be applied to inputs", func() {
+				st := bn.Status()
+				So(queueSize(st, "source"), ShouldEqual, data.Int(10))
+			})
+
+			Convey("Then BUFFER SIZE of a relation should override them", func() {
+				st := bn.Status()
+				So(queueSize(st, "source2"), ShouldEqual, data.Int(3))
+			})
+		})
+
+		Convey("When creating a stream with latest_only", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM box AS SELECT RSTREAM * FROM
+				source [RANGE 1 TUPLES] UNION ALL SELECT RSTREAM * FROM source2 [RANGE 1 TUPLES]
+				WITH drop_mode="latest_only"`), ShouldBeNil)
+
+			Convey("Then the stream should be created", func() {
+				_, err := dt.Box("box")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When creating a sink with queue parameters", func() {
+			So(addBQLToTopology(tb, `CREATE SINK snk TYPE collector WITH drop_mode="latest_only";
+				INSERT INTO snk FROM source;`), ShouldBeNil)
+			sn, err := dt.Sink("snk")
+			So(err, ShouldBeNil)
+
+			Convey("Then they should be applied to inputs", func() {
+				So(queueSize(sn.Status(), "source"), ShouldEqual, data.Int(1))
+			})
+
+			Convey("Then they should be forgotten after the sink is dropped", func() {
+				So(addBQLToTopology(tb, `DROP SINK snk; CREATE SINK snk TYPE collector;
+					INSERT INTO snk FROM source;`), ShouldBeNil)
+				sn, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				So(queueSize(sn.Status(), "source"), ShouldNotEqual, data.Int(1))
+			})
+		})
+
+		Convey("When creating a stream with invalid queue parameters", func() {
+			for _, p := range []string{
+				`buffer_size=0`,
+				`buffer_size=1000000`,
+				`buffer_size="a"`,
+				`drop_mode="random"`,
+				`drop_mode=1`,
+				`buffer_size=10, drop_mode="latest_only"`,
+			} {
+				Convey("Then it should fail with "+p, func() {
+					So(addBQLToTopology(tb, `CREATE STREAM box AS SELECT RSTREAM * FROM
+						source [RANGE 1 TUPLES] WITH `+p), ShouldNotBeNil)
+				})
+			}
+		})
+	})
+
+	Convey("Given parameters of queues", t, func() {
+		for _, c := range []struct {
+			params   data.Map
+			expected queueConfig
+		}{
+			{data.Map{}, queueConfig{}},
+			{data.Map{"buffer_size": data.Int(5)}, queueConfig{capacity: 5}},
+			{data.Map{"drop_mode": data.String("block")}, queueConfig{dropMode: core.DropNone}},
+			{data.Map{"drop_mode": data.String("Latest")}, queueConfig{dropMode: core.DropLatest}},
+			{data.Map{"drop_mode": data.String("latest_only")}, queueConfig{capacity: 1, dropMode: core.DropOldest}},
+		} {
+			c := c
+			Convey("Then extractQueueConfig should parse "+c.params.String(), func() {
+				params := c.params.Copy()
+				params["other"] = data.Int(1)
+				q, err := extractQueueConfig(params)
+				So(err, ShouldBeNil)
+				So(q, ShouldResemble, c.expected)
+				So(params, ShouldResemble, data.Map{"other": data.Int(1)})
+			})
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package bql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// redactionParam is the name of the parameter of CREATE STREAM giving the
+// redaction policy of the stream. It's a map from paths of fields to actions
+// applied to them:
+//
+//	CREATE STREAM users AS SELECT RSTREAM * FROM src [RANGE 1 TUPLES]
+//	  WITH redaction = {"email": "hash", "card.number": "mask",
+//	                    "password": "redact"};
+//
+// Actions are the following:
+//
+//   - redact: replaces the value with "[REDACTED]" (see the redact function)
+//   - mask: replaces each character of the value with "*" (see the mask
+//     function)
+//   - hash: replaces the value with its SHA-256 hash (see the hash_field
+//     function)
+//
+// The policy is applied to tuples of the stream before they leave the
+// topology, that is, when they're written to sinks by INSERT INTO
+// statements or returned as results of SELECT statements. Other streams
+// reading the stream receive tuples as they are. Fields which don't exist
+// in a tuple are ignored.
+const redactionParam = "redaction"
+
+// redactionActions maps actions of a redaction policy to the names of
+// the functions implementing them.
+var redactionActions = map[string]string{
+	"redact": "redact",
+	"mask":   "mask",
+	"hash":   "hash_field",
+}
+
+type redactionRule struct {
+	path data.Path
+	f    udf.UDF
+}
+
+// redactionPolicy is a policy given by the redaction parameter.
+type redactionPolicy struct {
+	rules []redactionRule
+}
+
+// newRedactionPolicy creates a policy from the value of the redaction
+// parameter. Functions implementing actions are looked up in reg.
+func newRedactionPolicy(v data.Value, reg udf.FunctionRegistry) (*redactionPolicy, error) {
+	m, err := data.AsMap(v)
+	if err != nil {
+		return nil, fmt.Errorf("'%v' parameter must be a map: %v", redactionParam, err)
+	}
+	// apply rules in a deterministic order
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	p := &redactionPolicy{}
+	for _, path := range paths {
+		a, err := data.AsString(m[path])
+		if err != nil {
+			return nil, fmt.Errorf("the redaction action of '%v' must be a string: %v", path, err)
+		}
+		name, ok := redactionActions[strings.ToLower(a)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported redaction action of '%v': %v", path, a)
+		}
+		compiled, err := data.CompilePath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path in '%v' parameter: %v", redactionParam, err)
+		}
+		f, err := reg.Lookup(name, 1)
+		if err != nil {
+			return nil, err
+		}
+		p.rules = append(p.rules, redactionRule{
+			path: compiled,
+			f:    f,
+		})
+	}
+	return p, nil
+}
+
+// apply returns a copy of the tuple having redacted fields.
+func (p *redactionPolicy) apply(ctx *core.Context, t *core.Tuple) (*core.Tuple, error) {
+	out := t.Copy()
+	for _, r := range p.rules {
+		v, err := out.Data.Get(r.path)
+		if err != nil {
+			continue
+		}
+		res, err := r.f.Call(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot redact '%v': %v", r.path, err)
+		}
+		if err := out.Data.Set(r.path, res); err != nil {
+			return nil, fmt.Errorf("cannot redact '%v': %v", r.path, err)
+		}
+	}
+	return out, nil
+}
+
+// redactionTransformer is an EdgeTransformer applying a redaction policy to
+// tuples before next transforms them.
+type redactionTransformer struct {
+	policy *redactionPolicy
+	next   core.EdgeTransformer
+}
+
+func (r *redactionTransformer) Transform(ctx *core.Context, t *core.Tuple) (*core.Tuple, error) {
+	t, err := r.policy.apply(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	if r.next == nil {
+		return t, nil
+	}
+	return r.next.Transform(ctx, t)
+}
+
+// streamRedactionPolicy returns the redaction policy given by the WITH
+// clause of a CREATE STREAM statement. It returns nil when the statement
+// doesn't have the policy. It also returns the parameters other than the
+// one of the policy.
+func (tb *TopologyBuilder) streamRedactionPolicy(params []parser.SourceSinkParamAST) (*redactionPolicy, []parser.SourceSinkParamAST, error) {
+	var policyParams, rest []parser.SourceSinkParamAST
+	for _, param := range params {
+		if string(param.Key) == redactionParam {
+			policyParams = append(policyParams, param)
+		} else {
+			rest = append(rest, param)
+		}
+	}
+	m, err := tb.mkParamsMap(policyParams)
+	if err != nil {
+		return nil, nil, err
+	}
+	v, ok := m[redactionParam]
+	if !ok {
+		return nil, rest, nil
+	}
+	p, err := newRedactionPolicy(v, tb.Reg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, rest, nil
+}
+
+// setRedactionPolicy records the redaction policy of a stream. The policy
+// is removed when p is nil.
+func (tb *TopologyBuilder) setRedactionPolicy(name string, p *redactionPolicy) {
+	tb.redactionMutex.Lock()
+	defer tb.redactionMutex.Unlock()
+	if p == nil {
+		delete(tb.redactions, strings.ToLower(name))
+		return
+	}
+	tb.redactions[strings.ToLower(name)] = p
+}
+
+// redactionTransformer returns an EdgeTransformer applying the redaction
+// policy of the input node before next. It returns next as is when the node
+// doesn't have a policy.
+func (tb *TopologyBuilder) redactionTransformer(input string, next core.EdgeTransformer) core.EdgeTransformer {
+	tb.redactionMutex.Lock()
+	p := tb.redactions[strings.ToLower(input)]
+	tb.redactionMutex.Unlock()
+	if p == nil {
+		return next
+	}
+	return &redactionTransformer{
+		policy: p,
+		next:   next,
+	}
+}
@@ -0,0 +1,113 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestRedactionPolicy(t *testing.T) {
+	Convey("Given a topology builder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE source TYPE dummy WITH num=1`), ShouldBeNil)
+
+		collect := func(input string) data.Map {
+			So(addBQLToTopology(tb, `CREATE SINK snk TYPE collector;
+				INSERT INTO snk FROM `+input+`;
+				RESUME SOURCE source;`), ShouldBeNil)
+			sin, err := dt.Sink("snk")
+			So(err, ShouldBeNil)
+			si := sin.Sink().(*tupleCollectorSink)
+			si.Wait(1)
+			return si.get(0).Data
+		}
+
+		redacted := data.Map{
+			"int":   data.Int(1),
+			"email": data.String("ff8d9819fc0e12bf0d24892e45987e249a28dce836a85cad60e28eaaa8c6d976"),
+			"card":  data.Map{"number": data.String("****************")},
+			"name":  data.String("[REDACTED]"),
+		}
+
+		Convey("When creating a stream with a redaction policy", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM users AS SELECT RSTREAM int,
+				"alice@example.com" AS email, "4111111111111111" AS card.number, "Alice" AS name
+				FROM source [RANGE 1 TUPLES]
+				WITH redaction={"email": "hash", "card.number": "mask", "name": "Redact",
+					"missing": "redact"}`), ShouldBeNil)
+
+			Convey("Then tuples written to a sink should be redacted", func() {
+				So(collect("users"), ShouldResemble, redacted)
+			})
+
+			Convey("Then results of a SELECT statement should be redacted", func() {
+				istmt, _, err := parser.New().ParseStmt(`SELECT RSTREAM * FROM users [RANGE 1 TUPLES];`)
+				So(err, ShouldBeNil)
+				stmt := istmt.(parser.SelectStmt)
+				sn, ch, err := tb.AddSelectStmt(&stmt)
+				So(err, ShouldBeNil)
+				Reset(func() {
+					sn.Stop()
+				})
+				So(addBQLToTopology(tb, `RESUME SOURCE source`), ShouldBeNil)
+				t := <-ch
+				So(t.Data, ShouldResemble, redacted)
+			})
+
+			Convey("Then streams reading it should receive tuples as they are", func() {
+				So(addBQLToTopology(tb, `CREATE STREAM box AS SELECT RSTREAM mask(card.number, 4) AS card,
+					name FROM users [RANGE 1 TUPLES]`), ShouldBeNil)
+				So(collect("box"), ShouldResemble, data.Map{
+					"card": data.String("************1111"),
+					"name": data.String("Alice"),
+				})
+			})
+
+			Convey("Then the policy should be removed when the stream is dropped", func() {
+				So(addBQLToTopology(tb, `DROP STREAM users;
+					CREATE STREAM users AS SELECT RSTREAM int, "Alice" AS name
+					FROM source [RANGE 1 TUPLES]`), ShouldBeNil)
+				So(collect("users"), ShouldResemble, data.Map{
+					"int":  data.Int(1),
+					"name": data.String("Alice"),
+				})
+			})
+		})
+
+		Convey("When creating a stream with a redaction policy and UNION ALL", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM users AS SELECT RSTREAM int, "Alice" AS name
+				FROM source [RANGE 1 TUPLES] WHERE int = 1
+				UNION ALL SELECT RSTREAM int, "Bob" AS name
+				FROM source [RANGE 1 TUPLES] WHERE int = 2
+				WITH redaction={"name": "mask"}`), ShouldBeNil)
+
+			Convey("Then tuples written to a sink should be redacted", func() {
+				So(collect("users"), ShouldResemble, data.Map{
+					"int":  data.Int(1),
+					"name": data.String("*****"),
+				})
+			})
+		})
+
+		Convey("When creating a stream with an invalid redaction policy", func() {
+			for _, p := range []string{
+				`redaction="email"`,
+				`redaction={"email": "encrypt"}`,
+				`redaction={"email": 1}`,
+				`redaction={"email[": "redact"}`,
+			} {
+				Convey("Then it should fail with "+p, func() {
+					So(addBQLToTopology(tb, `CREATE STREAM users AS SELECT RSTREAM *
+						FROM source [RANGE 1 TUPLES] WITH `+p), ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package bql
+
+import (
+	"fmt"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// routeIndexKey and routeDataKey are the keys of the Map emitted by
+	// routeBox. The former holds the index of the route and the latter
+	// holds the original data of the tuple.
+	routeIndexKey = "route"
+	routeDataKey  = "data"
+)
+
+// routeBox executes a CREATE STREAM ROUTES statement. It evaluates the
+// conditions (or the partition key) of all routes once for each tuple
+// and emits one tuple for every route the input tuple belongs to. Those
+// tuples are wrapped so that the box of each output stream only has to
+// check the route index, see newRouteOutputBox.
+type routeBox struct {
+	// conditions holds the evaluators of the WHEN conditions. An ELSE
+	// route has a nil evaluator.
+	conditions []execution.Evaluator
+	// partitionKey is the evaluator of the PARTITIONED BY expression or
+	// nil if the routes have conditions.
+	partitionKey execution.Evaluator
+	numRoutes    int
+}
+
+func newRouteBox(stmt *parser.CreateStreamRoutesStmt, reg udf.FunctionRegistry) (*routeBox, error) {
+	input := string(stmt.Input)
+	compile := func(expr parser.Expression) (execution.Evaluator, error) {
+		// expressions are evaluated on the data of the input tuple, so
+		// references to the input stream are removed
+		for rel := range expr.ReferencedRelations() {
+			if rel != "" && rel != input {
+				return nil, fmt.Errorf("cannot refer to relation '%s' "+
+					"when using only '%s'", rel, input)
+			}
+		}
+		expr = expr.RenameReferencedRelation(input, "")
+		flatExpr, err := execution.ParserExprToFlatExpr(expr, reg)
+		if err != nil {
+			return nil, err
+		}
+		return execution.ExpressionToEvaluator(flatExpr, reg)
+	}
+
+	b := &routeBox{
+		numRoutes: len(stmt.Routes),
+	}
+	if stmt.PartitionKey != nil {
+		key, err := compile(stmt.PartitionKey)
+		if err != nil {
+			return nil, err
+		}
+		b.partitionKey = key
+		return b, nil
+	}
+
+	b.conditions = make([]execution.Evaluator, len(stmt.Routes))
+	for i, r := range stmt.Routes {
+		if r.Condition == nil {
+			continue
+		}
+		cond, err := compile(r.Condition)
+		if err != nil {
+			return nil, err
+		}
+		b.conditions[i] = cond
+	}
+	return b, nil
+}
+
+func (b *routeBox) Process(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+	if b.partitionKey != nil {
+		key, err := b.partitionKey.Eval(t.Data)
+		if err != nil {
+			return err
+		}
+		return b.emit(ctx, t, w, int(uint64(data.Hash(key))%uint64(b.numRoutes)))
+	}
+
+	matched := false
+	for i, cond := range b.conditions {
+		if cond == nil {
+			// the ELSE route, which is always the last one
+			if matched {
+				return nil
+			}
+			return b.emit(ctx, t, w, i)
+		}
+		res, err := cond.Eval(t.Data)
+		if err != nil {
+			return err
+		}
+		// a NULL value is definitely not "true" as in a WHERE clause
+		if res.Type() == data.TypeNull {
+			continue
+		}
+		ok, err := data.AsBool(res)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		matched = true
+		if err := b.emit(ctx, t, w, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *routeBox) emit(ctx *core.Context, t *core.Tuple, w core.Writer, route int) error {
+	out := t.ShallowCopy()
+	out.Data = data.Map{
+		routeIndexKey: data.Int(route),
+		routeDataKey:  t.Data,
+	}
+	return w.Write(ctx, out)
+}
+
+// newRouteOutputBox creates a box that forwards the tuples a routeBox
+// emitted for the given route after unwrapping them.
+func newRouteOutputBox(route int) core.Box {
+	idx := data.Int(route)
+	return core.BoxFunc(func(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+		if t.Data[routeIndexKey] != idx {
+			return nil
+		}
+		d, err := data.AsMap(t.Data[routeDataKey])
+		if err != nil {
+			return err
+		}
+		out := t.ShallowCopy()
+		out.Data = d
+		return w.Write(ctx, out)
+	})
+}
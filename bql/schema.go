@@ -0,0 +1,224 @@
+package bql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Schema is a named record schema defined by a CREATE TYPE statement.
+// Sources and streams referring to a Schema with a SCHEMA clause only
+// emit tuples having all fields of the schema. A field may be null.
+type Schema struct {
+	Name   string        `json:"name"`
+	Fields []SchemaField `json:"fields"`
+}
+
+// SchemaField is a field of a Schema.
+type SchemaField struct {
+	Name string
+	Type data.TypeID
+}
+
+// MarshalJSON returns the field in JSON having the name of the type
+// instead of its ID.
+func (f SchemaField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"name": f.Name,
+		"type": f.Type.String(),
+	})
+}
+
+func newSchema(stmt *parser.CreateTypeStmt) (*Schema, error) {
+	if err := core.ValidateSymbol(string(stmt.Name)); err != nil {
+		return nil, fmt.Errorf("invalid name for type: %v", err)
+	}
+	s := &Schema{
+		Name:   string(stmt.Name),
+		Fields: make([]SchemaField, len(stmt.Fields)),
+	}
+	names := map[string]bool{}
+	for i, f := range stmt.Fields {
+		if names[f.Name] {
+			return nil, fmt.Errorf("field '%v' is defined more than once", f.Name)
+		}
+		names[f.Name] = true
+
+		t, err := typeIDFromParserType(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		s.Fields[i] = SchemaField{f.Name, t}
+	}
+	return s, nil
+}
+
+func typeIDFromParserType(t parser.Type) (data.TypeID, error) {
+	switch t {
+	case parser.Bool:
+		return data.TypeBool, nil
+	case parser.Int:
+		return data.TypeInt, nil
+	case parser.Float:
+		return data.TypeFloat, nil
+	case parser.String:
+		return data.TypeString, nil
+	case parser.Blob:
+		return data.TypeBlob, nil
+	case parser.Timestamp:
+		return data.TypeTimestamp, nil
+	case parser.Array:
+		return data.TypeArray, nil
+	case parser.Map:
+		return data.TypeMap, nil
+	}
+	return 0, fmt.Errorf("unsupported type: %v", t)
+}
+
+// Validate returns an error if the tuple doesn't have a field of the schema
+// or the type of the field is different from the one defined in the schema.
+// An integer is accepted as a float.
+func (s *Schema) Validate(t *core.Tuple) error {
+	for _, f := range s.Fields {
+		v, ok := t.Data[f.Name]
+		if !ok {
+			return fmt.Errorf("field '%v' of type '%v' is missing", f.Name, s.Name)
+		}
+		switch vt := v.Type(); {
+		case vt == f.Type, vt == data.TypeNull:
+		case vt == data.TypeInt && f.Type == data.TypeFloat:
+		default:
+			return fmt.Errorf("field '%v' of type '%v' must be %v, not %v",
+				f.Name, s.Name, f.Type, vt)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) hasField(name string) bool {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// columnHead returns the name of the top-level field accessed by the path
+// of a column, or an empty string if it cannot easily be determined.
+func columnHead(col string) string {
+	if i := strings.IndexAny(col, ".["); i >= 0 {
+		return col[:i]
+	}
+	return col
+}
+
+// checkSchemaReferences makes sure that all columns which the statement
+// refers to are defined by the schemas of its input streams.
+func checkSchemaReferences(plan *execution.LogicalPlan, schemas map[string]*Schema) error {
+	for rel, cols := range plan.ReferencedColumns() {
+		if rel == "" && len(plan.Relations) == 1 {
+			rel = plan.Relations[0].Alias
+		}
+		s, ok := schemas[rel]
+		if !ok {
+			continue
+		}
+		for _, c := range cols {
+			h := columnHead(c)
+			if h == "" || s.hasField(h) {
+				continue
+			}
+			return fmt.Errorf("column '%v' of '%v' is not defined in type '%v'",
+				c, rel, s.Name)
+		}
+	}
+	return nil
+}
+
+func (tb *TopologyBuilder) createType(stmt *parser.CreateTypeStmt) error {
+	s, err := newSchema(stmt)
+	if err != nil {
+		return err
+	}
+
+	tb.schemaMutex.Lock()
+	defer tb.schemaMutex.Unlock()
+	name := strings.ToLower(s.Name)
+	if _, ok := tb.schemas[name]; ok {
+		return fmt.Errorf("type '%v' already exists", s.Name)
+	}
+	tb.schemas[name] = s
+	return nil
+}
+
+func (tb *TopologyBuilder) dropType(stmt *parser.DropTypeStmt) error {
+	tb.schemaMutex.Lock()
+	defer tb.schemaMutex.Unlock()
+	name := strings.ToLower(string(stmt.Type))
+	s, ok := tb.schemas[name]
+	if !ok {
+		return core.NotExistError(fmt.Errorf("type '%v' was not found", stmt.Type))
+	}
+	for n, node := range tb.topology.Nodes() {
+		if node.Meta() == s {
+			return fmt.Errorf("type '%v' is used by '%v'", stmt.Type, n)
+		}
+	}
+	delete(tb.schemas, name)
+	return nil
+}
+
+// lookupSchema returns the type referred by a SCHEMA clause. It returns nil
+// when there's no SCHEMA clause.
+func (tb *TopologyBuilder) lookupSchema(a parser.SchemaAST) (*Schema, error) {
+	if a.Schema == "" {
+		return nil, nil
+	}
+	tb.schemaMutex.RLock()
+	defer tb.schemaMutex.RUnlock()
+	s, ok := tb.schemas[strings.ToLower(a.Schema)]
+	if !ok {
+		return nil, core.NotExistError(fmt.Errorf("type '%v' was not found", a.Schema))
+	}
+	return s, nil
+}
+
+// checkInputSchemas returns an error if the statement refers to a column
+// that isn't defined in the type of an input stream created with a SCHEMA
+// clause.
+func (tb *TopologyBuilder) checkInputSchemas(stmt *parser.SelectStmt) error {
+	schemas := map[string]*Schema{}
+	for _, rel := range stmt.Relations {
+		if rel.Type != parser.ActualStream {
+			continue
+		}
+		n, err := tb.topology.Node(rel.Name)
+		if err != nil {
+			continue // this error will be reported later
+		}
+		s, ok := n.Meta().(*Schema)
+		if !ok {
+			continue
+		}
+		alias := rel.Alias
+		if alias == "" {
+			alias = rel.Name
+		}
+		schemas[alias] = s
+	}
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	plan, err := execution.Analyze(*stmt, tb.Reg)
+	if err != nil {
+		return err
+	}
+	return checkSchemaReferences(plan, schemas)
+}
@@ -0,0 +1,178 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSchema(t *testing.T) {
+	Convey("Given a schema", t, func() {
+		s := &Schema{
+			Name: "reading",
+			Fields: []SchemaField{
+				{"id", data.TypeString},
+				{"value", data.TypeFloat},
+			},
+		}
+
+		Convey("When validating a tuple having all fields", func() {
+			err := s.Validate(core.NewTuple(data.Map{
+				"id":    data.String("a"),
+				"value": data.Float(1.5),
+				"other": data.Int(1),
+			}))
+
+			Convey("Then it should succeed", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When validating a tuple having an int for a float field", func() {
+			err := s.Validate(core.NewTuple(data.Map{
+				"id":    data.String("a"),
+				"value": data.Int(1),
+			}))
+
+			Convey("Then it should succeed", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When validating a tuple having a null field", func() {
+			err := s.Validate(core.NewTuple(data.Map{
+				"id":    data.Null{},
+				"value": data.Float(1.5),
+			}))
+
+			Convey("Then it should succeed", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When validating a tuple missing a field", func() {
+			err := s.Validate(core.NewTuple(data.Map{
+				"id": data.String("a"),
+			}))
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "'value'")
+			})
+		})
+
+		Convey("When validating a tuple having a field of a wrong type", func() {
+			err := s.Validate(core.NewTuple(data.Map{
+				"id":    data.Int(1),
+				"value": data.Float(1.5),
+			}))
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "must be string")
+			})
+		})
+	})
+}
+
+func TestCreateTypeStmt(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		Convey("When creating a type", func() {
+			So(addBQLToTopology(tb, `CREATE TYPE reading AS (int INT)`), ShouldBeNil)
+
+			Convey("Then creating the same type again should fail", func() {
+				err := addBQLToTopology(tb, `CREATE TYPE Reading AS (x STRING)`)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "already exists")
+			})
+
+			Convey("Then a source should emit valid tuples", func() {
+				So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy SCHEMA reading WITH num=3;
+					CREATE SINK snk TYPE collector; INSERT INTO snk FROM s; RESUME SOURCE s;`), ShouldBeNil)
+				sn, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				si := sn.Sink().(*tupleCollectorSink)
+				si.Wait(3)
+				So(si.len(), ShouldEqual, 3)
+
+				Convey("And the source should have the schema as its meta information", func() {
+					src, err := dt.Source("s")
+					So(err, ShouldBeNil)
+					So(src.Meta(), ShouldHaveSameTypeAs, &Schema{})
+					So(src.Meta().(*Schema).Name, ShouldEqual, "reading")
+				})
+			})
+
+			Convey("Then a stream should drop invalid tuples", func() {
+				So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=3;
+					CREATE STREAM t SCHEMA reading AS SELECT RSTREAM
+						CASE WHEN int = 2 THEN "two" ELSE int END AS int FROM s [RANGE 1 TUPLES];
+					CREATE SINK snk TYPE collector; INSERT INTO snk FROM t; RESUME SOURCE s;`), ShouldBeNil)
+				sn, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				si := sn.Sink().(*tupleCollectorSink)
+				si.Wait(2)
+				So(si.get(0).Data["int"], ShouldEqual, data.Int(1))
+				So(si.get(1).Data["int"], ShouldEqual, data.Int(3))
+			})
+
+			Convey("Then a stream referring to an undefined column should fail", func() {
+				So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy SCHEMA reading`), ShouldBeNil)
+				err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM int, foo FROM s [RANGE 1 TUPLES]`)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "'foo' of 's' is not defined in type 'reading'")
+
+				Convey("And a stream referring to defined columns should succeed", func() {
+					So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM x:int FROM s [RANGE 1 TUPLES] AS x
+						WHERE x:int > 1`), ShouldBeNil)
+				})
+			})
+
+			Convey("Then dropping the type used by a source should fail", func() {
+				So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy SCHEMA reading`), ShouldBeNil)
+				err := addBQLToTopology(tb, `DROP TYPE reading`)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "used by")
+
+				Convey("And dropping it after dropping the source should succeed", func() {
+					So(addBQLToTopology(tb, `DROP SOURCE s; DROP TYPE reading`), ShouldBeNil)
+					So(addBQLToTopology(tb, `CREATE SOURCE s2 TYPE dummy SCHEMA reading`), ShouldNotBeNil)
+				})
+			})
+		})
+
+		Convey("When creating a type having duplicated fields", func() {
+			err := addBQLToTopology(tb, `CREATE TYPE reading AS (a INT, a STRING)`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a source with an undefined type", func() {
+			err := addBQLToTopology(tb, `CREATE SOURCE s TYPE dummy SCHEMA reading`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "was not found")
+			})
+		})
+
+		Convey("When dropping an undefined type", func() {
+			err := addBQLToTopology(tb, `DROP TYPE reading`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package bql
+
+import (
+	"errors"
+	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+// Session is a scope of statements issued by a client such as a WebSocket
+// connection. Nodes created by CREATE TEMPORARY statements in a session are
+// removed from the topology when the session is closed so that exploratory
+// work doesn't leave unnecessary nodes behind.
+type Session struct {
+	tb    *TopologyBuilder
+	owner string
+
+	m      sync.Mutex
+	nodes  []core.Node
+	closed bool
+}
+
+// NewSession creates a new session of the TopologyBuilder. The caller must
+// call Close when the client is disconnected.
+func (tb *TopologyBuilder) NewSession() *Session {
+	return tb.NewSessionAs("")
+}
+
+// NewSessionAs creates a new session whose statements are added on behalf of
+// the owner as TopologyBuilder.AddStmtAs does.
+func (tb *TopologyBuilder) NewSessionAs(owner string) *Session {
+	return &Session{
+		tb:    tb,
+		owner: owner,
+	}
+}
+
+// AddStmt adds a node created from a statement to the topology in the same
+// way as TopologyBuilder.AddStmt. It also accepts statements creating
+// temporary nodes.
+func (s *Session) AddStmt(stmt interface{}) (core.Node, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return nil, errors.New("the session is already closed")
+	}
+
+	release, err := s.tb.admit(maxConcurrentStatementsConfig, "statements")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	n, err := s.tb.addStmt(s.owner, stmt)
+	if err != nil || n == nil || !IsTemporaryStmt(stmt) {
+		return n, err
+	}
+	s.nodes = append(s.nodes, n)
+	return n, nil
+}
+
+// TemporaryNodes returns the names of temporary nodes created in the session
+// which still exist in the topology.
+func (s *Session) TemporaryNodes() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var names []string
+	for _, n := range s.nodes {
+		if s.exists(n) {
+			names = append(names, n.Name())
+		}
+	}
+	return names
+}
+
+// exists returns true when the node is in the topology. It returns false
+// when the node was dropped even if another node having the same name was
+// created after that.
+func (s *Session) exists(n core.Node) bool {
+	cur, err := s.tb.topology.Node(n.Name())
+	return err == nil && cur == n
+}
+
+// Close removes all temporary nodes created in the session. Nodes are
+// removed in the reverse order of their creation. It returns the last
+// error which occurred while removing nodes. Close can be called more than
+// once.
+func (s *Session) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.closed = true
+
+	var lastErr error
+	for i := len(s.nodes) - 1; i >= 0; i-- {
+		n := s.nodes[i]
+		if !s.exists(n) {
+			continue
+		}
+		if err := s.tb.topology.Remove(n.Name()); err != nil {
+			s.tb.topology.Context().ErrLog(err).WithField("node_type", n.Type()).
+				WithField("node_name", n.Name()).Error("Cannot remove a temporary node")
+			lastErr = err
+		}
+	}
+	s.nodes = nil
+	return lastErr
+}
+
+// IsTemporaryStmt returns true when the statement creates a temporary node.
+// Such a statement only lives as long as its session, so it shouldn't be
+// recorded to restore the topology later.
+func IsTemporaryStmt(stmt interface{}) bool {
+	switch stmt := stmt.(type) {
+	case parser.CreateSourceStmt:
+		return stmt.Temporary == parser.Yes
+	case parser.CreateStreamAsSelectStmt:
+		return stmt.Temporary == parser.Yes
+	case parser.CreateStreamAsSelectUnionStmt:
+		return stmt.Temporary == parser.Yes
+	case parser.CreateSinkStmt:
+		return stmt.Temporary == parser.Yes
+	}
+	return false
+}
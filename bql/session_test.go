@@ -0,0 +1,80 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+func addBQLToSession(s *Session, bql string) error {
+	bp := parser.New()
+	stmts, err := bp.ParseStmts(bql)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := s.AddStmt(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSession(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		Convey("When creating a temporary node without a session", func() {
+			err := addBQLToTopology(tb, `CREATE TEMPORARY PAUSED SOURCE s TYPE dummy`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "session")
+				So(dt.Nodes(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When creating temporary nodes in a session", func() {
+			s := tb.NewSession()
+			So(addBQLToSession(s, `CREATE PAUSED SOURCE s TYPE dummy;
+				CREATE TEMPORARY PAUSED SOURCE tmp TYPE dummy;
+				CREATE TEMPORARY STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES];
+				CREATE TEMPORARY SINK snk TYPE collector;
+				INSERT INTO snk FROM t;`), ShouldBeNil)
+			So(len(dt.Nodes()), ShouldEqual, 4)
+			So(s.TemporaryNodes(), ShouldResemble, []string{"tmp", "t", "snk"})
+
+			Convey("Then closing the session should remove them", func() {
+				So(s.Close(), ShouldBeNil)
+				So(len(dt.Nodes()), ShouldEqual, 1)
+				_, err := dt.Source("s")
+				So(err, ShouldBeNil)
+
+				Convey("And the session cannot be used after that", func() {
+					So(addBQLToSession(s, `CREATE SINK snk TYPE collector`), ShouldNotBeNil)
+				})
+
+				Convey("And closing the session again should succeed", func() {
+					So(s.Close(), ShouldBeNil)
+				})
+			})
+
+			Convey("Then a node recreated with the same name shouldn't be removed", func() {
+				So(addBQLToTopology(tb, `DROP SINK snk; CREATE SINK snk TYPE collector;`), ShouldBeNil)
+				So(s.TemporaryNodes(), ShouldResemble, []string{"tmp", "t"})
+				So(s.Close(), ShouldBeNil)
+				_, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				_, err = dt.Box("t")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+	})
+}
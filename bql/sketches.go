@@ -0,0 +1,399 @@
+package bql
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// This file has memory-bounded data structures summarizing streams. They
+// aren't thread-safe.
+
+// mixHash improves the distribution of bits in a hash value (splitmix64
+// finalizer) so that its bits can be used independently.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// countMinSketch estimates the number of occurrences of each key. An
+// estimate is never less than the actual count and exceeds it by at most
+// e/width*total with probability 1-exp(-depth).
+type countMinSketch struct {
+	width  int
+	depth  int
+	counts []int64 // depth rows of width counters
+	total  int64
+}
+
+func newCountMinSketch(width, depth int) (*countMinSketch, error) {
+	if width <= 0 || depth <= 0 {
+		return nil, errors.New("width and depth must be positive")
+	}
+	return &countMinSketch{
+		width:  width,
+		depth:  depth,
+		counts: make([]int64, width*depth),
+	}, nil
+}
+
+// indexes calls f with the counter index of each row for the key.
+func (s *countMinSketch) indexes(key data.Value, f func(i int)) {
+	h := mixHash(uint64(data.Hash(key)))
+	h1, h2 := h&0xffffffff, h>>32|1
+	for r := 0; r < s.depth; r++ {
+		f(r*s.width + int((h1+uint64(r)*h2)%uint64(s.width)))
+	}
+}
+
+func (s *countMinSketch) addCount(key data.Value, n int64) {
+	s.indexes(key, func(i int) {
+		s.counts[i] += n
+	})
+	s.total += n
+}
+
+func (s *countMinSketch) estimate(key data.Value) int64 {
+	min := int64(math.MaxInt64)
+	s.indexes(key, func(i int) {
+		if c := s.counts[i]; c < min {
+			min = c
+		}
+	})
+	return min
+}
+
+// hyperLogLog estimates the number of distinct values with 2^precision
+// registers. The standard error is about 1.04/sqrt(2^precision).
+type hyperLogLog struct {
+	precision uint
+	registers []uint8
+}
+
+func newHyperLogLog(precision int) (*hyperLogLog, error) {
+	if precision < 4 || precision > 18 {
+		return nil, errors.New("precision must be from 4 to 18")
+	}
+	return &hyperLogLog{
+		precision: uint(precision),
+		registers: make([]uint8, 1<<uint(precision)),
+	}, nil
+}
+
+func (h *hyperLogLog) addValue(v data.Value) {
+	x := mixHash(uint64(data.Hash(v)))
+	i := x >> (64 - h.precision)
+	// the rank is the position of the leftmost 1 in the remaining bits
+	rank := uint8(bits.LeadingZeros64(x<<h.precision|1<<(h.precision-1)) + 1)
+	if rank > h.registers[i] {
+		h.registers[i] = rank
+	}
+}
+
+func (h *hyperLogLog) estimate() int64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+	e := alpha * m * m / sum
+	if e <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		e = m * math.Log(m/float64(zeros))
+	}
+	return int64(e + 0.5)
+}
+
+// spaceSaving finds the most frequent values (heavy hitters) with a bounded
+// number of counters by the Space-Saving algorithm. When all counters are in
+// use, the value having the smallest count is replaced by a new value, which
+// inherits the count as its overestimation. Any value occurring more than
+// total/capacity times is guaranteed to be kept.
+type spaceSaving struct {
+	capacity int
+	counters spaceSavingHeap
+	index    map[data.HashValue][]*spaceSavingCounter
+	total    int64
+}
+
+type spaceSavingCounter struct {
+	value data.Value
+	hash  data.HashValue
+	count int64
+
+	// err is the maximum overestimation of count.
+	err int64
+
+	// pos is the position in the heap.
+	pos int
+}
+
+func newSpaceSaving(capacity int) (*spaceSaving, error) {
+	if capacity <= 0 {
+		return nil, errors.New("capacity must be positive")
+	}
+	return &spaceSaving{
+		capacity: capacity,
+		index:    map[data.HashValue][]*spaceSavingCounter{},
+	}, nil
+}
+
+func (s *spaceSaving) addCount(v data.Value, n int64) {
+	s.total += n
+	h := data.Hash(v)
+	for _, c := range s.index[h] {
+		if data.Equal(c.value, v) {
+			c.count += n
+			heap.Fix(&s.counters, c.pos)
+			return
+		}
+	}
+
+	if len(s.counters) < s.capacity {
+		c := &spaceSavingCounter{
+			value: v,
+			hash:  h,
+			count: n,
+		}
+		heap.Push(&s.counters, c)
+		s.index[h] = append(s.index[h], c)
+		return
+	}
+
+	// replace the value having the smallest count
+	c := s.counters[0]
+	s.unindex(c)
+	c.value = v
+	c.hash = h
+	c.err = c.count
+	c.count += n
+	s.index[h] = append(s.index[h], c)
+	heap.Fix(&s.counters, c.pos)
+}
+
+func (s *spaceSaving) unindex(c *spaceSavingCounter) {
+	cs := s.index[c.hash]
+	for i, x := range cs {
+		if x == c {
+			cs = append(cs[:i], cs[i+1:]...)
+			break
+		}
+	}
+	if len(cs) == 0 {
+		delete(s.index, c.hash)
+	} else {
+		s.index[c.hash] = cs
+	}
+}
+
+// top returns at most k counters in the descending order of their counts.
+// Counters having the same count are ordered by their values.
+func (s *spaceSaving) top(k int) []*spaceSavingCounter {
+	cs := make([]*spaceSavingCounter, len(s.counters))
+	copy(cs, s.counters)
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].count != cs[j].count {
+			return cs[i].count > cs[j].count
+		}
+		return data.Less(cs[i].value, cs[j].value)
+	})
+	if len(cs) > k {
+		cs = cs[:k]
+	}
+	return cs
+}
+
+// spaceSavingHeap is a min-heap of counters ordered by their counts.
+type spaceSavingHeap []*spaceSavingCounter
+
+func (h spaceSavingHeap) Len() int           { return len(h) }
+func (h spaceSavingHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h spaceSavingHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *spaceSavingHeap) Push(x interface{}) {
+	c := x.(*spaceSavingCounter)
+	c.pos = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *spaceSavingHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// tDigest estimates quantiles of numeric values with a bounded number of
+// centroids. Estimates are more accurate near the both ends of the
+// distribution. It's a merging t-digest: added values are buffered and
+// merged into centroids when the buffer gets full.
+type tDigest struct {
+	compression float64
+	centroids   []tDigestCentroid
+	buffer      []tDigestCentroid
+	count       float64
+	min, max    float64
+}
+
+type tDigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+func newTDigest(compression float64) (*tDigest, error) {
+	if compression < 10 || compression > 10000 {
+		return nil, errors.New("compression must be from 10 to 10000")
+	}
+	return &tDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}, nil
+}
+
+func (t *tDigest) addFloat(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	t.buffer = append(t.buffer, tDigestCentroid{mean: x, weight: 1})
+	t.count++
+	if x < t.min {
+		t.min = x
+	}
+	if x > t.max {
+		t.max = x
+	}
+	if len(t.buffer) >= int(t.compression)*5 {
+		t.compress()
+	}
+}
+
+// compress merges buffered values into centroids.
+func (t *tDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	cs := append(t.centroids, t.buffer...)
+	t.buffer = t.buffer[:0]
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].mean < cs[j].mean
+	})
+
+	merged := make([]tDigestCentroid, 0, len(cs))
+	cur := cs[0]
+	cumulative := 0.0
+	for _, c := range cs[1:] {
+		q := (cumulative + (cur.weight+c.weight)/2) / t.count
+		limit := 4 * t.count * q * (1 - q) / t.compression
+		if cur.weight+c.weight <= math.Max(limit, 1) {
+			w := cur.weight + c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / w
+			cur.weight = w
+			continue
+		}
+		cumulative += cur.weight
+		merged = append(merged, cur)
+		cur = c
+	}
+	t.centroids = append(merged, cur)
+}
+
+// quantile returns the estimated q-quantile. It returns NaN when no value
+// has been added.
+func (t *tDigest) quantile(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+	if len(t.centroids) == 1 {
+		return t.centroids[0].mean
+	}
+
+	// interpolate between centers of centroids
+	target := q * t.count
+	cumulative := 0.0
+	prevMean, prevCenter := t.min, 0.0
+	for _, c := range t.centroids {
+		center := cumulative + c.weight/2
+		if target < center {
+			if center == prevCenter {
+				return c.mean
+			}
+			return prevMean + (c.mean-prevMean)*(target-prevCenter)/(center-prevCenter)
+		}
+		prevMean, prevCenter = c.mean, center
+		cumulative += c.weight
+	}
+	if t.count == prevCenter {
+		return t.max
+	}
+	return prevMean + (t.max-prevMean)*(target-prevCenter)/(t.count-prevCenter)
+}
+
+// runningStats has the count, the mean, and the variance of numeric values
+// computed by Welford's algorithm.
+type runningStats struct {
+	count    int64
+	mean     float64
+	m2       float64
+	min, max float64
+	sum      float64
+}
+
+func (s *runningStats) addFloat(x float64) {
+	if s.count == 0 || x < s.min {
+		s.min = x
+	}
+	if s.count == 0 || x > s.max {
+		s.max = x
+	}
+	s.count++
+	d := x - s.mean
+	s.mean += d / float64(s.count)
+	s.m2 += d * (x - s.mean)
+	s.sum += x
+}
+
+// variance returns the sample variance. It's 0 when the count is less than
+// 2.
+func (s *runningStats) variance() float64 {
+	if s.count < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.count-1)
+}
@@ -15,6 +15,17 @@ type IOParams struct {
 
 	// Name is the name of the instance specified in a CREATE statement.
 	Name string
+
+	// Instance is the index of the reader when a source has more than one
+	// reader running in parallel. A source creator can use it to decide,
+	// for example, which partition the reader should read. It's always 0
+	// for a sink.
+	Instance int
+
+	// NumInstances is the number of readers of a source specified by the
+	// INSTANCES clause. It's 1 when a source doesn't have the clause, and
+	// always 0 for a sink.
+	NumInstances int
 }
 
 // SourceCreator is an interface which creates instances of a Source.
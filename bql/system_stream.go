@@ -0,0 +1,218 @@
+package bql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// setUpSystemStream creates a temporary source emitting tuples of the
+// virtual stream and connects it to subsequentBox. The returned source is
+// paused and has to be resumed by the caller.
+func (tb *TopologyBuilder) setUpSystemStream(subsequentBox core.BoxNode, rel *parser.AliasedStreamWindowAST,
+	queue queueConfig, errMode core.ErrorMode, maxRetries int) (core.SourceNode, error) {
+	src, err := tb.newSystemStreamSource(rel.Name)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := temporaryNodeInputConfig(rel, queue, errMode, maxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	temporaryName := fmt.Sprintf("sensorbee_tmp_system_%v", topologyBuilderNextTemporaryID())
+	sn, err := tb.topology.AddSource(temporaryName, src, &core.SourceConfig{
+		PausedOnStartup: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := subsequentBox.Input(temporaryName, conf); err != nil {
+		tb.topology.Remove(temporaryName)
+		return nil, err
+	}
+	sn.StopOnDisconnect()
+	sn.RemoveOnStop()
+	return sn, nil
+}
+
+// newSystemStreamSource creates a source of a virtual stream which can be
+// referred to as system.name in a FROM clause. Each of them periodically
+// emits the metadata of the topology:
+//
+//	* nodes: the status of each node, which is the same as node_statuses
+//	* edges: the statistics of each edge, which is the same as edge_statuses
+//	* statements: the statement which created each node
+//	* stats: the numbers of tuples each node has received, sent, and dropped
+//	  with labels of the node
+//	* types: the fields of each type created by CREATE TYPE
+//	* usage: the resources accumulated by the topology, which is the same as
+//	  core.Topology.Usage with topology_name
+func (tb *TopologyBuilder) newSystemStreamSource(name string) (core.Source, error) {
+	interval := tb.systemStreamInterval
+	switch strings.ToLower(name) {
+	case "nodes":
+		return &nodeStatusSource{
+			topology: tb.topology,
+			interval: interval,
+			stopCh:   make(chan struct{}),
+		}, nil
+	case "edges":
+		return &edgeStatusSource{
+			topology: tb.topology,
+			interval: interval,
+			stopCh:   make(chan struct{}),
+		}, nil
+	case "statements":
+		return newSnapshotSource(interval, tb.statementSnapshot), nil
+	case "stats":
+		return newSnapshotSource(interval, tb.statsSnapshot), nil
+	case "types":
+		return newSnapshotSource(interval, tb.typeSnapshot), nil
+	case "usage":
+		return newSnapshotSource(interval, tb.usageSnapshot), nil
+	}
+	return nil, core.NotExistError(fmt.Errorf("system stream 'system.%v' was not found", name))
+}
+
+func (tb *TopologyBuilder) statementSnapshot() []data.Map {
+	nodes := tb.topology.Nodes()
+
+	tb.stmtMutex.Lock()
+	defer tb.stmtMutex.Unlock()
+	var ms []data.Map
+	for name, stmt := range tb.statements {
+		n, ok := nodes[name]
+		if !ok {
+			// the node has been removed
+			delete(tb.statements, name)
+			continue
+		}
+		ms = append(ms, data.Map{
+			"node_name": data.String(name),
+			"node_type": data.String(n.Type().String()),
+			"statement": data.String(stmt),
+		})
+	}
+	return ms
+}
+
+var (
+	statsPaths = map[string]data.Path{
+		"num_received": data.MustCompilePath("input_stats.num_received_total"),
+		"num_errors":   data.MustCompilePath("input_stats.num_errors"),
+		"num_sent":     data.MustCompilePath("output_stats.num_sent_total"),
+		"num_dropped":  data.MustCompilePath("output_stats.num_dropped"),
+	}
+)
+
+func (tb *TopologyBuilder) statsSnapshot() []data.Map {
+	var ms []data.Map
+	for name, n := range tb.topology.Nodes() {
+		st := n.Status()
+		m := data.Map{
+			"node_name": data.String(name),
+			"node_type": data.String(n.Type().String()),
+			"state":     st["state"],
+		}
+		if ls := n.Labels(); len(ls) > 0 {
+			m["labels"] = labelsToMap(ls)
+		}
+		// A source doesn't have input_stats and a sink doesn't have
+		// output_stats. Their values are 0.
+		for k, p := range statsPaths {
+			m[k] = data.Int(0)
+			if v, err := st.Get(p); err == nil {
+				m[k] = v
+			}
+		}
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+func (tb *TopologyBuilder) usageSnapshot() []data.Map {
+	m := tb.topology.Usage()
+	m["topology_name"] = data.String(tb.topology.Name())
+	return []data.Map{m}
+}
+
+func (tb *TopologyBuilder) typeSnapshot() []data.Map {
+	tb.schemaMutex.RLock()
+	defer tb.schemaMutex.RUnlock()
+	names := make([]string, 0, len(tb.schemas))
+	for n := range tb.schemas {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	ms := make([]data.Map, 0, len(names))
+	for _, n := range names {
+		s := tb.schemas[n]
+		fs := make(data.Array, len(s.Fields))
+		for i, f := range s.Fields {
+			fs[i] = data.Map{
+				"name": data.String(f.Name),
+				"type": data.String(f.Type.String()),
+			}
+		}
+		ms = append(ms, data.Map{
+			"name":   data.String(s.Name),
+			"fields": fs,
+		})
+	}
+	return ms
+}
+
+// snapshotSource periodically emits a tuple for each map returned from
+// the snapshot function.
+type snapshotSource struct {
+	interval time.Duration
+	snapshot func() []data.Map
+	stopCh   chan struct{}
+}
+
+func newSnapshotSource(interval time.Duration, snapshot func() []data.Map) *snapshotSource {
+	return &snapshotSource{
+		interval: interval,
+		snapshot: snapshot,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+func (s *snapshotSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	next := time.Now().Add(s.interval)
+	for {
+		select {
+		case <-s.stopCh:
+			return nil
+		case <-time.After(next.Sub(time.Now())):
+		}
+		now := time.Now()
+
+		for _, m := range s.snapshot() {
+			t := &core.Tuple{
+				Timestamp:     now,
+				ProcTimestamp: now,
+				Data:          m,
+			}
+			w.Write(ctx, t)
+		}
+
+		next = next.Add(s.interval)
+		if next.Before(now) {
+			// delayed too much and should be rescheduled.
+			next = now.Add(s.interval)
+		}
+	}
+}
+
+func (s *snapshotSource) Stop(ctx *core.Context) error {
+	close(s.stopCh)
+	return nil
+}
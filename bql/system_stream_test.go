@@ -0,0 +1,142 @@
+package bql
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSystemStream(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with nodes", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		tb.systemStreamInterval = 10 * time.Millisecond
+		So(addBQLToTopology(tb, `CREATE TYPE reading AS (int INT);
+			CREATE PAUSED SOURCE s TYPE dummy WITH num=4;
+			CREATE STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES];`), ShouldBeNil)
+
+		selectOne := func(stmt string) data.Map {
+			bp := parser.New()
+			istmt, _, err := bp.ParseStmt(stmt)
+			So(err, ShouldBeNil)
+			s := istmt.(parser.SelectStmt)
+			sn, ch, err := tb.AddSelectStmt(&s)
+			So(err, ShouldBeNil)
+			defer func() {
+				go func() {
+					for _ = range ch {
+					}
+				}()
+				So(sn.Stop(), ShouldBeNil)
+			}()
+			t := <-ch
+			return t.Data
+		}
+
+		Convey("When selecting from system.nodes", func() {
+			m := selectOne(`SELECT ISTREAM node_name, node_type, state FROM system.nodes [RANGE 1 TUPLES]
+				WHERE node_name = "s";`)
+
+			Convey("Then it should emit the status of the node", func() {
+				So(m, ShouldResemble, data.Map{
+					"node_name": data.String("s"),
+					"node_type": data.String("source"),
+					"state":     data.String("paused"),
+				})
+			})
+		})
+
+		Convey("When selecting from system.edges", func() {
+			m := selectOne(`SELECT ISTREAM sender.node_name AS sender, receiver.node_name AS receiver
+				FROM system.edges [RANGE 1 TUPLES] WHERE sender.node_name = "s";`)
+
+			Convey("Then it should emit the edge", func() {
+				So(m, ShouldResemble, data.Map{
+					"sender":   data.String("s"),
+					"receiver": data.String("t"),
+				})
+			})
+		})
+
+		Convey("When selecting from system.statements", func() {
+			m := selectOne(`SELECT ISTREAM x:statement FROM system.statements [RANGE 1 TUPLES] AS x
+				WHERE x:node_name = "t";`)
+
+			Convey("Then it should emit the statement which created the node", func() {
+				So(m["statement"], ShouldEqual, data.String("CREATE STREAM t AS SELECT ISTREAM * FROM s [RANGE 1 TUPLES]"))
+			})
+		})
+
+		Convey("When selecting from system.stats", func() {
+			So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+			m := selectOne(`SELECT ISTREAM * FROM system.stats [RANGE 1 TUPLES]
+				WHERE node_name = "t" AND num_received = 4;`)
+
+			Convey("Then it should emit the statistics of the node", func() {
+				So(m["node_type"], ShouldEqual, data.String("box"))
+				So(m["num_errors"], ShouldEqual, data.Int(0))
+				So(m["num_dropped"], ShouldEqual, data.Int(4))
+			})
+		})
+
+		Convey("When selecting from system.types", func() {
+			m := selectOne(`SELECT ISTREAM * FROM system.types [RANGE 1 TUPLES];`)
+
+			Convey("Then it should emit the type", func() {
+				So(m, ShouldResemble, data.Map{
+					"name": data.String("reading"),
+					"fields": data.Array{data.Map{
+						"name": data.String("int"),
+						"type": data.String("int"),
+					}},
+				})
+			})
+		})
+
+		Convey("When selecting from system.usage", func() {
+			So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+			m := selectOne(`SELECT ISTREAM * FROM system.usage [RANGE 1 TUPLES]
+				WHERE num_emitted >= 8;`)
+
+			Convey("Then it should emit the usage of the topology", func() {
+				So(m["topology_name"], ShouldEqual, data.String(dt.Name()))
+				So(m["num_processed"], ShouldBeGreaterThanOrEqualTo, data.Int(4))
+				So(m, ShouldContainKey, "bytes_emitted")
+				So(m, ShouldContainKey, "cpu_time_estimate")
+				So(m, ShouldContainKey, "peak_heap_bytes")
+			})
+		})
+
+		Convey("When creating a stream from an undefined system stream", func() {
+			numNodes := len(dt.Nodes())
+			err := addBQLToTopology(tb, `CREATE STREAM u AS SELECT ISTREAM * FROM system.hoge [RANGE 1 TUPLES];`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(core.IsNotExist(err), ShouldBeTrue)
+				So(len(dt.Nodes()), ShouldEqual, numNodes)
+			})
+		})
+
+		Convey("When dropping a stream reading a system stream", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM u AS SELECT ISTREAM * FROM system.nodes [RANGE 1 TUPLES];`), ShouldBeNil)
+			So(len(dt.Nodes()), ShouldEqual, 4)
+			So(addBQLToTopology(tb, `DROP STREAM u;`), ShouldBeNil)
+
+			Convey("Then the temporary source should be removed", func() {
+				waitForExpectedCondition(func() bool {
+					return len(dt.Nodes()) == 2
+				})
+				So(len(dt.Nodes()), ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,322 @@
+package bql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Template is a parameterized set of statements created by a CREATE
+// TEMPLATE statement. It can be instantiated many times with different
+// parameters, e.g. to create the same pipeline for each device.
+type Template struct {
+	Name   string
+	Params []string
+	Body   string
+}
+
+// templateInstance has the nodes and states created by an INSTANTIATE
+// TEMPLATE statement so that they can be dropped at once.
+type templateInstance struct {
+	name     string
+	template string
+
+	// nodes and states are in the order of their creation. A state has a
+	// nil node.
+	nodes  []core.Node
+	states []string
+}
+
+var (
+	templateParamRegexp = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+func newTemplate(stmt *parser.CreateTemplateStmt) (*Template, error) {
+	if err := core.ValidateSymbol(string(stmt.Name)); err != nil {
+		return nil, fmt.Errorf("invalid name for template: %v", err)
+	}
+	params := map[string]bool{}
+	for _, p := range stmt.Params {
+		p = strings.ToLower(p)
+		if params[p] {
+			return nil, fmt.Errorf("parameter '%v' is defined more than once", p)
+		}
+		params[p] = true
+	}
+	for _, m := range templateParamRegexp.FindAllStringSubmatch(stmt.Body, -1) {
+		if !params[strings.ToLower(m[1])] {
+			return nil, fmt.Errorf("parameter '%v' is not defined in template '%v'", m[1], stmt.Name)
+		}
+	}
+	if strings.TrimSpace(stmt.Body) == "" {
+		return nil, fmt.Errorf("template '%v' doesn't have any statement", stmt.Name)
+	}
+	return &Template{
+		Name:   string(stmt.Name),
+		Params: stmt.Params,
+		Body:   stmt.Body,
+	}, nil
+}
+
+// expand replaces ${param} in the body with the value of the parameter. A
+// string is inserted without quotes so that it can be a part of a name.
+// Write "${param}" to use it as a string literal.
+func (t *Template) expand(params data.Map) (string, error) {
+	values := map[string]string{}
+	for k, v := range params {
+		k = strings.ToLower(k)
+		if !t.hasParam(k) {
+			return "", fmt.Errorf("template '%v' doesn't have parameter '%v'", t.Name, k)
+		}
+		s, err := data.ToString(v)
+		if err != nil {
+			return "", fmt.Errorf("cannot use the value of parameter '%v': %v", k, err)
+		}
+		values[k] = s
+	}
+	for _, p := range t.Params {
+		if _, ok := values[strings.ToLower(p)]; !ok {
+			return "", fmt.Errorf("parameter '%v' of template '%v' is missing", p, t.Name)
+		}
+	}
+
+	return templateParamRegexp.ReplaceAllStringFunc(t.Body, func(m string) string {
+		return values[strings.ToLower(m[2:len(m)-1])]
+	}), nil
+}
+
+func (t *Template) hasParam(name string) bool {
+	for _, p := range t.Params {
+		if strings.ToLower(p) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultInstanceName returns the name of an instance created without AS.
+// It's the name of the template followed by values of the parameters such
+// as pipeline_dev1.
+func (t *Template) defaultInstanceName(params data.Map) string {
+	str := []string{t.Name}
+	for _, p := range t.Params {
+		for k, v := range params {
+			if strings.ToLower(k) == strings.ToLower(p) {
+				s, _ := data.ToString(v)
+				str = append(str, s)
+			}
+		}
+	}
+	return strings.Join(str, "_")
+}
+
+func (tb *TopologyBuilder) createTemplate(stmt *parser.CreateTemplateStmt) error {
+	t, err := newTemplate(stmt)
+	if err != nil {
+		return err
+	}
+
+	tb.templateMutex.Lock()
+	defer tb.templateMutex.Unlock()
+	name := strings.ToLower(t.Name)
+	if _, ok := tb.templates[name]; ok {
+		return fmt.Errorf("template '%v' already exists", t.Name)
+	}
+	tb.templates[name] = t
+	return nil
+}
+
+func (tb *TopologyBuilder) dropTemplate(stmt *parser.DropTemplateStmt) error {
+	tb.templateMutex.Lock()
+	defer tb.templateMutex.Unlock()
+	name := strings.ToLower(string(stmt.Template))
+	if _, ok := tb.templates[name]; !ok {
+		return core.NotExistError(fmt.Errorf("template '%v' was not found", stmt.Template))
+	}
+	for _, i := range tb.templateInstances {
+		if strings.ToLower(i.template) == name {
+			return fmt.Errorf("template '%v' is used by instance '%v'", stmt.Template, i.name)
+		}
+	}
+	delete(tb.templates, name)
+	return nil
+}
+
+// instantiateTemplate runs all statements of the template. When one of them
+// fails, nodes and states created by preceding statements are removed.
+func (tb *TopologyBuilder) instantiateTemplate(owner string, stmt *parser.InstantiateTemplateStmt) error {
+	tb.templateMutex.Lock()
+	defer tb.templateMutex.Unlock()
+	t, ok := tb.templates[strings.ToLower(string(stmt.Template))]
+	if !ok {
+		return core.NotExistError(fmt.Errorf("template '%v' was not found", stmt.Template))
+	}
+
+	params, err := tb.mkParamsMap(stmt.Params)
+	if err != nil {
+		return err
+	}
+	name := string(stmt.Name)
+	if name == "" {
+		name = t.defaultInstanceName(params)
+	}
+	if _, ok := tb.templateInstances[strings.ToLower(name)]; ok {
+		return fmt.Errorf("template instance '%v' already exists", name)
+	}
+
+	body, err := t.expand(params)
+	if err != nil {
+		return err
+	}
+	stmts, err := parser.New().ParseStmts(body)
+	if err != nil {
+		return fmt.Errorf("cannot parse template '%v' with the given parameters: %v", t.Name, err)
+	}
+	for _, s := range stmts {
+		if err := checkTemplateStmt(s); err != nil {
+			return err
+		}
+	}
+
+	i := &templateInstance{
+		name:     name,
+		template: t.Name,
+	}
+	for _, s := range stmts {
+		if err := tb.addTemplateStmt(owner, i, s); err != nil {
+			tb.removeTemplateInstance(i)
+			return fmt.Errorf("cannot instantiate template '%v': %v", t.Name, err)
+		}
+	}
+	tb.templateInstances[strings.ToLower(name)] = i
+	return nil
+}
+
+// checkTemplateStmt returns an error if the statement cannot be used in a
+// template. Statements whose effect cannot be undone when the instance is
+// dropped aren't allowed.
+func checkTemplateStmt(stmt interface{}) error {
+	if IsTemporaryStmt(stmt) {
+		return errors.New("a template cannot create a temporary node")
+	}
+	switch stmt.(type) {
+	case parser.CreateSourceStmt, parser.CreateStreamAsSelectStmt,
+		parser.CreateStreamAsSelectUnionStmt, parser.CreateStreamRoutesStmt,
+		parser.CreateSinkStmt, parser.CreateStateStmt, parser.LoadStateOrCreateStmt,
+		parser.CreateTriggerStmt, parser.CreateAlertStmt, parser.InsertIntoFromStmt,
+		parser.UpdateSourceStmt, parser.UpdateSinkStmt, parser.UpdateStateStmt,
+		parser.PauseSourceStmt, parser.ResumeSourceStmt, parser.RewindSourceStmt:
+		return nil
+	}
+	return fmt.Errorf("a template cannot have the statement: %v", stmt)
+}
+
+func (tb *TopologyBuilder) addTemplateStmt(owner string, i *templateInstance, stmt interface{}) error {
+	n, err := tb.addStmt(owner, stmt)
+	if err != nil {
+		return err
+	}
+
+	switch stmt := stmt.(type) {
+	case parser.CreateSourceStmt, parser.CreateStreamAsSelectStmt,
+		parser.CreateStreamAsSelectUnionStmt, parser.CreateSinkStmt,
+		parser.CreateTriggerStmt, parser.CreateAlertStmt:
+		i.nodes = append(i.nodes, n)
+		i.states = append(i.states, "")
+
+	case parser.CreateStreamRoutesStmt:
+		// The router is removed when all routes are dropped.
+		for _, r := range stmt.Routes {
+			b, err := tb.topology.Box(string(r.Name))
+			if err != nil {
+				return err
+			}
+			i.nodes = append(i.nodes, b)
+			i.states = append(i.states, "")
+		}
+
+	case parser.CreateStateStmt:
+		i.nodes = append(i.nodes, nil)
+		i.states = append(i.states, string(stmt.Name))
+
+	case parser.LoadStateOrCreateStmt:
+		i.nodes = append(i.nodes, nil)
+		i.states = append(i.states, string(stmt.Name))
+	}
+	return nil
+}
+
+func (tb *TopologyBuilder) dropTemplateInstance(stmt *parser.DropTemplateInstanceStmt) error {
+	tb.templateMutex.Lock()
+	defer tb.templateMutex.Unlock()
+	name := strings.ToLower(string(stmt.Instance))
+	i, ok := tb.templateInstances[name]
+	if !ok {
+		return core.NotExistError(fmt.Errorf("template instance '%v' was not found", stmt.Instance))
+	}
+	delete(tb.templateInstances, name)
+	return tb.removeTemplateInstance(i)
+}
+
+// removeTemplateInstance removes nodes and states of the instance in the
+// reverse order of their creation. Nodes which have already been dropped
+// are ignored. It returns the last error which occurred while removing
+// them.
+func (tb *TopologyBuilder) removeTemplateInstance(i *templateInstance) error {
+	ctx := tb.topology.Context()
+	var lastErr error
+	for j := len(i.nodes) - 1; j >= 0; j-- {
+		if n := i.nodes[j]; n != nil {
+			if cur, err := tb.topology.Node(n.Name()); err != nil || cur != n {
+				continue
+			}
+			if err := tb.topology.Remove(n.Name()); err != nil {
+				ctx.ErrLog(err).WithField("node_type", n.Type()).
+					WithField("node_name", n.Name()).
+					WithField("template_instance", i.name).
+					Error("Cannot remove a node of the template instance")
+				lastErr = err
+			}
+			continue
+		}
+
+		s := i.states[j]
+		if _, err := ctx.SharedStates.Get(s); err != nil {
+			continue
+		}
+		tb.removeLatestByFeeder(s)
+		if _, err := ctx.SharedStates.Remove(s); err != nil {
+			ctx.ErrLog(err).WithField("state_name", s).
+				WithField("template_instance", i.name).
+				Error("Cannot remove a state of the template instance")
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// TemplateInstances returns the names of template instances and the names
+// of nodes created by each of them which still exist in the topology.
+func (tb *TopologyBuilder) TemplateInstances() map[string][]string {
+	tb.templateMutex.Lock()
+	defer tb.templateMutex.Unlock()
+	res := make(map[string][]string, len(tb.templateInstances))
+	for _, i := range tb.templateInstances {
+		names := []string{}
+		for _, n := range i.nodes {
+			if n == nil {
+				continue
+			}
+			if cur, err := tb.topology.Node(n.Name()); err == nil && cur == n {
+				names = append(names, n.Name())
+			}
+		}
+		res[i.name] = names
+	}
+	return res
+}
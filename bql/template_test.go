@@ -0,0 +1,146 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+func TestTemplate(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a template", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE TEMPLATE pipeline(device_id, num) AS $$
+			CREATE PAUSED SOURCE src_${device_id} TYPE dummy WITH num=${num};
+			CREATE STREAM s_${device_id} AS SELECT ISTREAM * FROM src_${device_id} [RANGE 1 TUPLES];
+			CREATE STATE st_${device_id} TYPE dummy_uds WITH num=${num};
+			CREATE SINK snk_${device_id} TYPE collector;
+			INSERT INTO snk_${device_id} FROM s_${device_id};
+		$$`), ShouldBeNil)
+
+		nodeExists := func(name string) bool {
+			_, err := dt.Node(name)
+			return err == nil
+		}
+
+		Convey("When instantiating the template", func() {
+			So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev1", num=4`), ShouldBeNil)
+
+			Convey("Then nodes of the instance should be created", func() {
+				So(nodeExists("src_dev1"), ShouldBeTrue)
+				So(nodeExists("s_dev1"), ShouldBeTrue)
+				So(nodeExists("snk_dev1"), ShouldBeTrue)
+				_, err := dt.Context().SharedStates.Get("st_dev1")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the instance should have the default name", func() {
+				So(tb.TemplateInstances(), ShouldResemble, map[string][]string{
+					"pipeline_dev1_4": []string{"src_dev1", "s_dev1", "snk_dev1"},
+				})
+			})
+
+			Convey("Then the same instance cannot be created again", func() {
+				So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev1", num=4`), ShouldNotBeNil)
+			})
+
+			Convey("Then another instance can be created", func() {
+				So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline AS dev2 WITH device_id="dev2", num=4`), ShouldBeNil)
+				So(nodeExists("s_dev2"), ShouldBeTrue)
+				So(tb.TemplateInstances(), ShouldContainKey, "dev2")
+			})
+
+			Convey("Then the template cannot be dropped", func() {
+				So(addBQLToTopology(tb, `DROP TEMPLATE pipeline`), ShouldNotBeNil)
+			})
+
+			Convey("Then dropping the instance should remove all of its nodes and states", func() {
+				So(addBQLToTopology(tb, `DROP TEMPLATE INSTANCE pipeline_dev1_4`), ShouldBeNil)
+				So(nodeExists("src_dev1"), ShouldBeFalse)
+				So(nodeExists("s_dev1"), ShouldBeFalse)
+				So(nodeExists("snk_dev1"), ShouldBeFalse)
+				_, err := dt.Context().SharedStates.Get("st_dev1")
+				So(err, ShouldNotBeNil)
+				So(tb.TemplateInstances(), ShouldBeEmpty)
+
+				Convey("And the template can be dropped", func() {
+					So(addBQLToTopology(tb, `DROP TEMPLATE pipeline`), ShouldBeNil)
+					So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev1", num=4`), ShouldNotBeNil)
+				})
+			})
+
+			Convey("Then a node recreated with the same name shouldn't be removed with the instance", func() {
+				So(addBQLToTopology(tb, `DROP SINK snk_dev1; CREATE SINK snk_dev1 TYPE collector`), ShouldBeNil)
+				So(addBQLToTopology(tb, `DROP TEMPLATE INSTANCE pipeline_dev1_4`), ShouldBeNil)
+				So(nodeExists("snk_dev1"), ShouldBeTrue)
+				So(nodeExists("s_dev1"), ShouldBeFalse)
+			})
+		})
+
+		Convey("When instantiating the template with a failing statement", func() {
+			So(addBQLToTopology(tb, `CREATE SINK snk_dev1 TYPE collector`), ShouldBeNil)
+			err := addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev1", num=4`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then nodes created by preceding statements should be removed", func() {
+				So(nodeExists("src_dev1"), ShouldBeFalse)
+				So(nodeExists("s_dev1"), ShouldBeFalse)
+				_, err := dt.Context().SharedStates.Get("st_dev1")
+				So(err, ShouldNotBeNil)
+				So(nodeExists("snk_dev1"), ShouldBeTrue)
+				So(tb.TemplateInstances(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When instantiating the template with wrong parameters", func() {
+			Convey("Then a missing parameter should be reported", func() {
+				So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev1"`), ShouldNotBeNil)
+			})
+
+			Convey("Then an undefined parameter should be reported", func() {
+				So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev1", num=4, x=1`), ShouldNotBeNil)
+			})
+
+			Convey("Then a value resulting in an invalid statement should be reported", func() {
+				So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE pipeline WITH device_id="dev 1", num=4`), ShouldNotBeNil)
+				So(tb.TemplateInstances(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When instantiating a missing template", func() {
+			err := addBQLToTopology(tb, `INSTANTIATE TEMPLATE nothing`)
+
+			Convey("Then it should fail", func() {
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When creating a template with the same name", func() {
+			Convey("Then it should fail", func() {
+				So(addBQLToTopology(tb, `CREATE TEMPLATE Pipeline AS $$CREATE SINK s TYPE collector$$`), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a template referring to an undefined parameter", func() {
+			Convey("Then it should fail", func() {
+				So(addBQLToTopology(tb, `CREATE TEMPLATE t2(a) AS $$CREATE SINK s_${b} TYPE collector$$`), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a template having a statement which cannot be undone", func() {
+			So(addBQLToTopology(tb, `CREATE TEMPLATE t2 AS $$DROP SINK snk$$`), ShouldBeNil)
+
+			Convey("Then it cannot be instantiated", func() {
+				So(addBQLToTopology(tb, `INSTANTIATE TEMPLATE t2`), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,247 @@
+package testkit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/yaml.v2"
+)
+
+// Suite is a set of table-driven test cases of BQL statements. It's written
+// in YAML in a .bqltest file as follows:
+//
+//	statements: |
+//	  CREATE STREAM hot AS SELECT RSTREAM * FROM readings [RANGE 1 TUPLES]
+//	    WHERE temperature > 30;
+//	cases:
+//	  - name: only hot readings are emitted
+//	    input:
+//	      - stream: readings
+//	        data: {temperature: 31}
+//	      - advance: 1s
+//	      - stream: readings
+//	        data: {temperature: 20}
+//	    expect:
+//	      hot:
+//	        - {temperature: 31}
+//
+// Each case runs on a new Harness executing the statements.
+type Suite struct {
+	Statements string `bql:",required"`
+	Cases      []*Case
+}
+
+// Case is a test case in a Suite.
+type Case struct {
+	Name string
+
+	// Input has steps executed in order.
+	Input []*Step
+
+	// Expect has data of tuples expected to be emitted by each stream. Its
+	// values are arrays of maps. Streams not in Expect aren't checked.
+	Expect data.Map
+
+	// Unordered is true when the order of emitted tuples isn't checked.
+	Unordered bool
+}
+
+// Step is a step of a Case. When it has Advance, the virtual clock is
+// advanced first. When it has At without Stream, the virtual clock is set to
+// At. When it has Stream, a tuple having Data is fed to the stream. The
+// timestamp of the tuple is At if it's given, or the virtual clock otherwise.
+type Step struct {
+	Stream  string
+	Data    data.Map
+	At      time.Time
+	Advance time.Duration
+}
+
+// CaseResult is a result of a Case.
+type CaseResult struct {
+	Name string
+
+	// Failures has differences between expected and emitted tuples.
+	Failures []string
+
+	// Err is an error which prevented the case from running.
+	Err error
+}
+
+// Passed returns true when the case passed.
+func (r *CaseResult) Passed() bool {
+	return r.Err == nil && len(r.Failures) == 0
+}
+
+// ParseSuite parses a Suite written in YAML.
+func ParseSuite(b []byte) (*Suite, error) {
+	var yml map[string]interface{}
+	if err := yaml.Unmarshal(b, &yml); err != nil {
+		return nil, err
+	}
+	m, err := data.NewMap(yml)
+	if err != nil {
+		return nil, err
+	}
+	s := &Suite{}
+	if err := data.NewDecoder(nil).Decode(m, s); err != nil {
+		return nil, err
+	}
+	for i, c := range s.Cases {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case #%v", i+1)
+		}
+		for j, st := range c.Input {
+			if st.Stream == "" && st.At.IsZero() && st.Advance == 0 {
+				return nil, fmt.Errorf("%v: step #%v has nothing to do", c.Name, j+1)
+			}
+		}
+		for stream, v := range c.Expect {
+			if _, err := expectedData(v); err != nil {
+				return nil, fmt.Errorf("%v: expected tuples of '%v' are invalid: %v", c.Name, stream, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+// RunFile runs a Suite read from a .bqltest file.
+func RunFile(path string) ([]*CaseResult, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ParseSuite(b)
+	if err != nil {
+		return nil, err
+	}
+	return s.Run(), nil
+}
+
+// Run runs all cases in the Suite.
+func (s *Suite) Run() []*CaseResult {
+	res := make([]*CaseResult, len(s.Cases))
+	for i, c := range s.Cases {
+		res[i] = s.run(c)
+	}
+	return res
+}
+
+func (s *Suite) run(c *Case) *CaseResult {
+	res := &CaseResult{Name: c.Name}
+	h, err := New(s.Statements)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer h.Close()
+
+	for i, st := range c.Input {
+		if st.Advance != 0 {
+			h.Advance(st.Advance)
+		}
+		if st.Stream == "" {
+			if !st.At.IsZero() {
+				h.SetTime(st.At)
+			}
+			continue
+		}
+		ts := st.At
+		if ts.IsZero() {
+			ts = h.Now()
+		}
+		d := st.Data
+		if d == nil {
+			d = data.Map{}
+		}
+		if err := h.FeedAt(st.Stream, ts, d); err != nil {
+			res.Err = fmt.Errorf("step #%v: %v", i+1, err)
+			return res
+		}
+	}
+
+	streams := make([]string, 0, len(c.Expect))
+	for stream := range c.Expect {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+	for _, stream := range streams {
+		expected, _ := expectedData(c.Expect[stream])
+		res.Failures = append(res.Failures,
+			Compare(stream, expected, h.Output(stream), c.Unordered)...)
+	}
+	return res
+}
+
+func expectedData(v data.Value) ([]data.Map, error) {
+	if v.Type() == data.TypeNull {
+		return nil, nil
+	}
+	a, err := data.AsArray(v)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]data.Map, len(a))
+	for i, e := range a {
+		m, err := data.AsMap(e)
+		if err != nil {
+			return nil, fmt.Errorf("#%v: %v", i+1, err)
+		}
+		res[i] = m
+	}
+	return res, nil
+}
+
+// Compare compares data of tuples emitted by the stream with expected ones
+// and returns differences. An integer and a float having the same value are
+// considered equal. The order of tuples isn't compared when unordered is
+// true.
+func Compare(stream string, expected, actual []data.Map, unordered bool) []string {
+	if len(expected) != len(actual) {
+		return []string{fmt.Sprintf("stream '%v': expected %v tuples but got %v: %v",
+			stream, len(expected), len(actual), mapsToArray(actual))}
+	}
+
+	var fs []string
+	if !unordered {
+		for i := range expected {
+			if !data.Equal(expected[i], actual[i]) {
+				fs = append(fs, fmt.Sprintf("stream '%v': tuple #%v is %v but expected %v",
+					stream, i+1, actual[i], expected[i]))
+			}
+		}
+		return fs
+	}
+
+	used := make([]bool, len(actual))
+	for _, e := range expected {
+		found := false
+		for i, a := range actual {
+			if !used[i] && data.Equal(e, a) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			fs = append(fs, fmt.Sprintf("stream '%v': %v was expected but not emitted", stream, e))
+		}
+	}
+	for i, a := range actual {
+		if !used[i] {
+			fs = append(fs, fmt.Sprintf("stream '%v': %v was emitted but not expected", stream, a))
+		}
+	}
+	return fs
+}
+
+func mapsToArray(ms []data.Map) data.Array {
+	a := make(data.Array, len(ms))
+	for i, m := range ms {
+		a[i] = m
+	}
+	return a
+}
@@ -0,0 +1,113 @@
+package testkit
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSuite(t *testing.T) {
+	Convey("Given a test suite", t, func() {
+		s, err := ParseSuite([]byte(`
+statements: |
+  CREATE STREAM hot AS SELECT RSTREAM * FROM readings [RANGE 1 TUPLES]
+    WHERE temperature > 30;
+  CREATE STREAM sums AS SELECT RSTREAM sum(temperature) AS s FROM readings [RANGE 10 SECONDS];
+cases:
+  - name: hot readings
+    input:
+      - stream: readings
+        data: {temperature: 31}
+      - advance: 1s
+      - stream: readings
+        data: {temperature: 20}
+    expect:
+      hot:
+        - {temperature: 31}
+      sums:
+        - {s: 31}
+        - {s: 51.0}
+  - name: windows
+    input:
+      - stream: readings
+        data: {temperature: 1}
+      - advance: 11
+      - stream: readings
+        data: {temperature: 2}
+    expect:
+      hot: []
+      sums:
+        - {s: 1}
+        - {s: 3}
+  - input:
+      - stream: readings
+        data: {temperature: 40}
+      - stream: readings
+        data: {temperature: 50}
+    unordered: true
+    expect:
+      hot:
+        - {temperature: 50}
+        - {temperature: 40}
+`))
+		So(err, ShouldBeNil)
+
+		Convey("When running it", func() {
+			res := s.Run()
+
+			Convey("Then each case should have a result", func() {
+				So(res, ShouldHaveLength, 3)
+				So(res[0].Name, ShouldEqual, "hot readings")
+				So(res[0].Passed(), ShouldBeTrue)
+				So(res[2].Name, ShouldEqual, "case #3")
+				So(res[2].Passed(), ShouldBeTrue)
+			})
+
+			Convey("Then the failure should be reported", func() {
+				So(res[1].Passed(), ShouldBeFalse)
+				So(res[1].Err, ShouldBeNil)
+				So(res[1].Failures, ShouldResemble, []string{
+					`stream 'sums': tuple #2 is {"s":2} but expected {"s":3}`,
+				})
+			})
+		})
+	})
+
+	Convey("Given an invalid test suite", t, func() {
+		for _, suite := range []string{
+			"cases: []",
+			"statements: CREATE STREAM a AS SELECT RSTREAM * FROM b [RANGE 1 TUPLES];\ncases:\n  - input:\n      - data: {a: 1}",
+			"statements: CREATE STREAM a AS SELECT RSTREAM * FROM b [RANGE 1 TUPLES];\ncases:\n  - expect:\n      a: 1",
+		} {
+			Convey("When parsing "+suite, func() {
+				_, err := ParseSuite([]byte(suite))
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
+	})
+
+	Convey("Given tuples to be compared", t, func() {
+		expected := []data.Map{{"a": data.Int(1)}, {"a": data.Int(2)}}
+
+		Convey("When the order is different", func() {
+			actual := []data.Map{{"a": data.Float(2)}, {"a": data.Int(1)}}
+
+			Convey("Then only the ordered comparison should fail", func() {
+				So(Compare("s", expected, actual, true), ShouldBeEmpty)
+				So(Compare("s", expected, actual, false), ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When the number of tuples is different", func() {
+			fs := Compare("s", expected, expected[:1], true)
+
+			Convey("Then it should fail", func() {
+				So(fs, ShouldResemble, []string{`stream 's': expected 2 tuples but got 1: [{"a":1}]`})
+			})
+		})
+	})
+}
@@ -0,0 +1,313 @@
+package testkit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// TraceRecord is a tuple in a trace. A trace is a JSON Lines file having a
+// record in each line. Traces of inputs of a topology can be recorded by
+// trace_recorder sink.
+type TraceRecord struct {
+	// Stream is the name of the stream which the tuple belongs to.
+	Stream string `json:"stream"`
+
+	// Timestamp is the timestamp of the tuple.
+	Timestamp data.Timestamp `json:"timestamp"`
+
+	// Data is the data of the tuple.
+	Data data.Map `json:"data"`
+}
+
+// ReadTrace reads records of a trace. Empty lines are ignored.
+func ReadTrace(r io.Reader) ([]*TraceRecord, error) {
+	var rs []*TraceRecord
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 64*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+		rec := &TraceRecord{}
+		if err := json.Unmarshal(s.Bytes(), rec); err != nil {
+			return nil, fmt.Errorf("invalid record at line %v: %v", line, err)
+		}
+		if rec.Stream == "" {
+			return nil, fmt.Errorf("the record at line %v doesn't have the stream", line)
+		}
+		if rec.Data == nil {
+			rec.Data = data.Map{}
+		}
+		rs = append(rs, rec)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// ReadTraceFile reads records of a trace from a file.
+func ReadTraceFile(path string) ([]*TraceRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTrace(f)
+}
+
+// WriteTrace writes records of a trace.
+func WriteTrace(w io.Writer, rs []*TraceRecord) error {
+	bw := bufio.NewWriter(w)
+	for _, r := range rs {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		bw.Write(b)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WriteTraceFile writes records of a trace to a file. The file is truncated
+// when it exists.
+func WriteTraceFile(path string, rs []*TraceRecord) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteTrace(f, rs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Replay feeds tuples in the trace in order. The virtual clock follows the
+// timestamps of the tuples, that is, it's set to the timestamp of each tuple
+// unless the timestamp is older than the clock.
+func (h *Harness) Replay(trace []*TraceRecord) error {
+	for i, r := range trace {
+		ts := time.Time(r.Timestamp)
+		if ts.After(h.Now()) {
+			h.SetTime(ts)
+		}
+		if err := h.FeedAt(r.Stream, ts, r.Data); err != nil {
+			return fmt.Errorf("record #%v: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Streams returns names of streams created by statements, sorted by their
+// names.
+func (h *Harness) Streams() []string {
+	var names []string
+	for _, s := range h.streams {
+		if s.box != nil {
+			names = append(names, s.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Record returns tuples emitted by the streams as records of a trace. Records
+// of each stream are grouped in the given order. Values of the records are
+// normalized as they would be written to and read from a file, so that they
+// can be compared with records read by ReadTrace.
+func (h *Harness) Record(streams ...string) ([]*TraceRecord, error) {
+	var rs []*TraceRecord
+	for _, s := range streams {
+		for _, t := range h.Tuples(s) {
+			rs = append(rs, &TraceRecord{
+				Stream:    s,
+				Timestamp: data.Timestamp(t.Timestamp),
+				Data:      t.Data,
+			})
+		}
+	}
+
+	// Some values such as timestamps are written as strings.
+	b, err := json.Marshal(rs)
+	if err != nil {
+		return nil, err
+	}
+	var res []*TraceRecord
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RunGolden replays the input trace through the statements and returns
+// records of tuples emitted by the streams. All streams created by the
+// statements are recorded when streams is empty.
+func RunGolden(stmts string, input []*TraceRecord, streams []string) ([]*TraceRecord, error) {
+	h, err := New(stmts)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+	if err := h.Replay(input); err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		streams = h.Streams()
+	}
+	return h.Record(streams...)
+}
+
+// Tolerance has tolerances of differences between golden and actual outputs.
+type Tolerance struct {
+	// Float is the maximum absolute difference of numbers considered equal.
+	Float float64
+
+	// Timestamp is the maximum difference of timestamps considered equal.
+	// It's applied to timestamps of tuples and strings in RFC3339 format in
+	// data.
+	Timestamp time.Duration
+}
+
+// Diff compares records of the golden output with the actual output and
+// returns divergences. Records are compared stream by stream in order.
+func Diff(golden, actual []*TraceRecord, tol Tolerance) []string {
+	group := func(rs []*TraceRecord) map[string][]*TraceRecord {
+		m := map[string][]*TraceRecord{}
+		for _, r := range rs {
+			m[r.Stream] = append(m[r.Stream], r)
+		}
+		return m
+	}
+	gs, as := group(golden), group(actual)
+	var streams []string
+	for s := range gs {
+		streams = append(streams, s)
+	}
+	for s := range as {
+		if _, ok := gs[s]; !ok {
+			streams = append(streams, s)
+		}
+	}
+	sort.Strings(streams)
+
+	var diffs []string
+	for _, s := range streams {
+		g, a := gs[s], as[s]
+		if len(g) != len(a) {
+			diffs = append(diffs, fmt.Sprintf("stream '%v': the golden output has %v tuples but got %v",
+				s, len(g), len(a)))
+		}
+		n := len(g)
+		if len(a) < n {
+			n = len(a)
+		}
+		for i := 0; i < n; i++ {
+			prefix := fmt.Sprintf("stream '%v' tuple #%v", s, i+1)
+			gt, at := time.Time(g[i].Timestamp), time.Time(a[i].Timestamp)
+			if !timestampsNear(gt, at, tol.Timestamp) {
+				diffs = append(diffs, fmt.Sprintf("%v: timestamp is %v but the golden one is %v",
+					prefix, at.Format(time.RFC3339Nano), gt.Format(time.RFC3339Nano)))
+			}
+			diffs = diffValues(diffs, prefix+": data", g[i].Data, a[i].Data, &tol)
+		}
+	}
+	return diffs
+}
+
+// diffValues appends differences between g and a to diffs.
+func diffValues(diffs []string, path string, g, a data.Value, tol *Tolerance) []string {
+	differ := func() []string {
+		return append(diffs, fmt.Sprintf("%v is %v but the golden one is %v", path, a, g))
+	}
+
+	switch {
+	case isNumber(g) && isNumber(a):
+		gf, _ := data.ToFloat(g)
+		af, _ := data.ToFloat(a)
+		if math.Abs(gf-af) > tol.Float && !(math.IsNaN(gf) && math.IsNaN(af)) {
+			return differ()
+		}
+		return diffs
+
+	case g.Type() != a.Type():
+		return differ()
+
+	case g.Type() == data.TypeMap:
+		gm, _ := data.AsMap(g)
+		am, _ := data.AsMap(a)
+		keys := make([]string, 0, len(gm))
+		for k := range gm {
+			keys = append(keys, k)
+		}
+		for k := range am {
+			if _, ok := gm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			gv, gok := gm[k]
+			av, aok := am[k]
+			p := path + "." + k
+			switch {
+			case !aok:
+				diffs = append(diffs, fmt.Sprintf("%v is missing", p))
+			case !gok:
+				diffs = append(diffs, fmt.Sprintf("%v isn't in the golden output", p))
+			default:
+				diffs = diffValues(diffs, p, gv, av, tol)
+			}
+		}
+		return diffs
+
+	case g.Type() == data.TypeArray:
+		ga, _ := data.AsArray(g)
+		aa, _ := data.AsArray(a)
+		if len(ga) != len(aa) {
+			return differ()
+		}
+		for i := range ga {
+			diffs = diffValues(diffs, fmt.Sprintf("%v[%v]", path, i), ga[i], aa[i], tol)
+		}
+		return diffs
+
+	case g.Type() == data.TypeString && tol.Timestamp > 0:
+		gs, _ := data.AsString(g)
+		as, _ := data.AsString(a)
+		gt, gerr := time.Parse(time.RFC3339Nano, gs)
+		at, aerr := time.Parse(time.RFC3339Nano, as)
+		if gerr == nil && aerr == nil {
+			if !timestampsNear(gt, at, tol.Timestamp) {
+				return differ()
+			}
+			return diffs
+		}
+	}
+	if !data.Equal(g, a) {
+		return differ()
+	}
+	return diffs
+}
+
+func isNumber(v data.Value) bool {
+	return v.Type() == data.TypeInt || v.Type() == data.TypeFloat
+}
+
+func timestampsNear(t1, t2 time.Time, tol time.Duration) bool {
+	d := t1.Sub(t2)
+	if d < 0 {
+		d = -d
+	}
+	return d <= tol
+}
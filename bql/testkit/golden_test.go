@@ -0,0 +1,115 @@
+package testkit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestGolden(t *testing.T) {
+	stmts := `CREATE STREAM avg AS SELECT RSTREAM avg(v) AS a FROM readings [RANGE 2 TUPLES];
+		CREATE STREAM stamped AS SELECT RSTREAM now() AS now FROM readings [RANGE 1 TUPLES];`
+	ts := func(sec int) data.Timestamp {
+		return data.Timestamp(DefaultStartTime.Add(time.Duration(sec) * time.Second))
+	}
+
+	Convey("Given a trace", t, func() {
+		trace, err := ReadTrace(bytes.NewBufferString(`
+{"stream":"readings","timestamp":"2000-01-01T00:00:01Z","data":{"v":1}}
+
+{"stream":"readings","timestamp":"2000-01-01T00:00:02Z","data":{"v":2}}
+{"stream":"readings","timestamp":"2000-01-01T00:00:03Z","data":{"v":4}}
+`))
+		So(err, ShouldBeNil)
+		So(trace, ShouldHaveLength, 3)
+		So(trace[1].Timestamp, ShouldResemble, ts(2))
+		So(trace[1].Data, ShouldResemble, data.Map{"v": data.Int(2)})
+
+		Convey("When replaying it", func() {
+			out, err := RunGolden(stmts, trace, nil)
+			So(err, ShouldBeNil)
+
+			Convey("Then outputs of all streams should be recorded", func() {
+				So(out, ShouldHaveLength, 6)
+				So(out[1].Stream, ShouldEqual, "avg")
+				So(out[1].Timestamp, ShouldResemble, ts(2))
+				So(out[1].Data, ShouldResemble, data.Map{"a": data.Float(1.5)})
+			})
+
+			Convey("Then the virtual clock should follow timestamps", func() {
+				So(out[5].Stream, ShouldEqual, "stamped")
+				So(out[5].Data, ShouldResemble, data.Map{"now": data.String("2000-01-01T00:00:03Z")})
+			})
+
+			Convey("Then it should be same as the output read from a file", func() {
+				b := &bytes.Buffer{}
+				So(WriteTrace(b, out), ShouldBeNil)
+				golden, err := ReadTrace(b)
+				So(err, ShouldBeNil)
+				So(Diff(golden, out, Tolerance{}), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When replaying only a stream", func() {
+			out, err := RunGolden(stmts, trace, []string{"avg"})
+			So(err, ShouldBeNil)
+
+			Convey("Then only the stream should be recorded", func() {
+				So(out, ShouldHaveLength, 3)
+			})
+		})
+	})
+
+	Convey("Given golden and actual outputs", t, func() {
+		golden := []*TraceRecord{
+			{"s", ts(1), data.Map{"a": data.Float(1.5), "t": data.String("2000-01-01T00:00:00Z")}},
+			{"s", ts(2), data.Map{"a": data.Int(2), "b": data.Array{data.Int(1)}}},
+		}
+
+		Convey("When they're slightly different", func() {
+			actual := []*TraceRecord{
+				{"s", ts(1), data.Map{"a": data.Float(1.5001), "t": data.String("2000-01-01T00:00:00.5Z")}},
+				{"s", ts(2), data.Map{"a": data.Float(2), "b": data.Array{data.Int(1)}}},
+			}
+
+			Convey("Then they should diverge without tolerances", func() {
+				So(Diff(golden, actual, Tolerance{}), ShouldResemble, []string{
+					`stream 's' tuple #1: data.a is 1.5001 but the golden one is 1.5`,
+					`stream 's' tuple #1: data.t is "2000-01-01T00:00:00.5Z" but the golden one is "2000-01-01T00:00:00Z"`,
+				})
+			})
+
+			Convey("Then they should match with tolerances", func() {
+				So(Diff(golden, actual, Tolerance{Float: 0.001, Timestamp: time.Second}), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When they're different", func() {
+			actual := []*TraceRecord{
+				{"s", ts(3), data.Map{"a": data.Float(1.5), "c": data.Int(1)}},
+				{"t", ts(2), data.Map{}},
+			}
+
+			Convey("Then divergences should be reported", func() {
+				So(Diff(golden, actual, Tolerance{}), ShouldResemble, []string{
+					`stream 's': the golden output has 2 tuples but got 1`,
+					`stream 's' tuple #1: timestamp is 2000-01-01T00:00:03Z but the golden one is 2000-01-01T00:00:01Z`,
+					`stream 's' tuple #1: data.c isn't in the golden output`,
+					`stream 's' tuple #1: data.t is missing`,
+					`stream 't': the golden output has 0 tuples but got 1`,
+				})
+			})
+		})
+	})
+
+	Convey("Given an invalid trace", t, func() {
+		_, err := ReadTrace(bytes.NewBufferString(`{"timestamp":"2000-01-01T00:00:01Z","data":{}}`))
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
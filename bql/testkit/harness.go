@@ -0,0 +1,297 @@
+/*
+Package testkit runs BQL statements on literal tuples under a virtual clock so
+that queries can be unit-tested.
+
+A Harness executes CREATE STREAM AS SELECT statements synchronously: Feed
+returns after all streams derived from the fed tuple have processed it, and
+tuples emitted by each stream are recorded. Streams which aren't created by
+statements, such as sources, are fed by tests. The timestamp of a fed tuple
+and now() in statements are given by the virtual clock of the Harness, which
+only moves when the test advances it.
+
+Tests can also be written in .bqltest files, which are run by RunFile and
+"sensorbee test" command.
+
+For regression tests of statements running in production, RunGolden replays
+a trace of inputs recorded by trace_recorder sink and Diff compares the
+outputs with a stored golden output.
+*/
+package testkit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+var (
+	// DefaultStartTime is the initial time of the virtual clock of a
+	// Harness.
+	DefaultStartTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Harness runs BQL statements on tuples given by a test. It isn't
+// thread-safe.
+type Harness struct {
+	tb  *bql.TopologyBuilder
+	ctx *core.Context
+
+	clockMutex sync.Mutex
+	now        time.Time
+
+	// streams has streams created by statements and streams referred by
+	// them. Its keys are lower case names.
+	streams map[string]*stream
+	closed  bool
+}
+
+// stream is a stream in a Harness.
+type stream struct {
+	name string
+	// box is the box executing the statement which created the stream. It's
+	// nil when the stream is fed by tests.
+	box core.StatefulBox
+	// readers has boxes reading the stream.
+	readers []*reader
+	// output has tuples emitted by the stream.
+	output []*core.Tuple
+}
+
+// reader is a box reading a stream.
+type reader struct {
+	stream    *stream
+	inputName string
+}
+
+// New creates a Harness executing BQL statements. CREATE STREAM AS SELECT
+// statements are executed by the Harness itself. Statements handling states,
+// types, and the config of the topology are executed by a
+// bql.TopologyBuilder. Other statements, such as ones creating sources or
+// sinks, aren't supported.
+func New(stmts string) (*Harness, error) {
+	h := &Harness{
+		now:     DefaultStartTime,
+		streams: map[string]*stream{},
+	}
+	h.ctx = core.NewContext(&core.ContextConfig{
+		Clock: h.Now,
+	})
+	t, err := core.NewDefaultTopology(h.ctx, "testkit")
+	if err != nil {
+		return nil, err
+	}
+	tb, err := bql.NewTopologyBuilder(t)
+	if err != nil {
+		t.Stop()
+		return nil, err
+	}
+	h.tb = tb
+
+	ss, err := parser.New().ParseStmts(stmts)
+	if err != nil {
+		h.Close()
+		return nil, err
+	}
+	for _, s := range ss {
+		if err := h.addStmt(s); err != nil {
+			h.Close()
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+func (h *Harness) addStmt(stmt interface{}) error {
+	switch s := stmt.(type) {
+	case parser.CreateStreamAsSelectStmt:
+		return h.createStream(&s)
+
+	case parser.CreateStateStmt, parser.UpdateStateStmt, parser.DropStateStmt,
+		parser.LoadStateStmt, parser.LoadStateOrCreateStmt, parser.SetConfigStmt,
+		parser.CreateTypeStmt, parser.DropTypeStmt:
+		_, err := h.tb.AddStmt(stmt)
+		return err
+	}
+	return fmt.Errorf("testkit doesn't support the statement: %v", stmt)
+}
+
+func (h *Harness) createStream(stmt *parser.CreateStreamAsSelectStmt) error {
+	name := string(stmt.Name)
+	if s, ok := h.streams[strings.ToLower(name)]; ok {
+		if s.box != nil {
+			return fmt.Errorf("stream '%v' already exists", name)
+		}
+		// this prevents cycles of streams
+		return fmt.Errorf("stream '%v' is referred before it's created", name)
+	}
+	for _, rel := range stmt.Select.Relations {
+		if rel.Type != parser.ActualStream {
+			return fmt.Errorf("testkit only supports streams in FROM clause: %v", rel.Name)
+		}
+		if strings.ToLower(rel.Name) == strings.ToLower(name) {
+			return fmt.Errorf("a stream '%v' contains a selfloop", name)
+		}
+	}
+
+	box := bql.NewBQLBox(&stmt.Select, h.tb.Reg)
+	if err := box.Init(h.ctx); err != nil {
+		return err
+	}
+	s := &stream{
+		name: name,
+		box:  box,
+	}
+	h.streams[strings.ToLower(name)] = s
+	for _, rel := range stmt.Select.Relations {
+		in := h.stream(rel.Name)
+		in.readers = append(in.readers, &reader{
+			stream:    s,
+			inputName: rel.Name,
+		})
+	}
+	return nil
+}
+
+// stream returns the stream having the name. It creates a stream fed by
+// tests when it doesn't exist.
+func (h *Harness) stream(name string) *stream {
+	s, ok := h.streams[strings.ToLower(name)]
+	if !ok {
+		s = &stream{name: name}
+		h.streams[strings.ToLower(name)] = s
+	}
+	return s
+}
+
+// TopologyBuilder returns the bql.TopologyBuilder executing statements other
+// than CREATE STREAM AS SELECT.
+func (h *Harness) TopologyBuilder() *bql.TopologyBuilder {
+	return h.tb
+}
+
+// Now returns the current time of the virtual clock.
+func (h *Harness) Now() time.Time {
+	h.clockMutex.Lock()
+	defer h.clockMutex.Unlock()
+	return h.now
+}
+
+// SetTime sets the time of the virtual clock. The time can go back.
+func (h *Harness) SetTime(t time.Time) {
+	h.clockMutex.Lock()
+	defer h.clockMutex.Unlock()
+	h.now = t
+}
+
+// Advance advances the virtual clock.
+func (h *Harness) Advance(d time.Duration) {
+	h.clockMutex.Lock()
+	defer h.clockMutex.Unlock()
+	h.now = h.now.Add(d)
+}
+
+// Feed feeds a tuple having the data to the stream. The timestamp of the
+// tuple is the current time of the virtual clock. It returns after all
+// streams derived from the stream have processed the tuple.
+func (h *Harness) Feed(stream string, d data.Map) error {
+	return h.FeedAt(stream, h.Now(), d)
+}
+
+// FeedAt feeds a tuple having the data and the timestamp to the stream. It
+// doesn't change the virtual clock.
+func (h *Harness) FeedAt(stream string, ts time.Time, d data.Map) error {
+	if h.closed {
+		return errors.New("the harness is already closed")
+	}
+	t := core.NewTuple(d)
+	t.Timestamp = ts
+	t.ProcTimestamp = h.Now()
+	return h.emit(h.stream(stream), t)
+}
+
+// emit records the tuple emitted by the stream and passes it to streams
+// reading the stream.
+func (h *Harness) emit(s *stream, t *core.Tuple) error {
+	s.output = append(s.output, t)
+	// the tuple is kept in the output
+	t.Flags.Set(core.TFShared)
+
+	for _, r := range s.readers {
+		in := t.ShallowCopy()
+		in.InputName = r.inputName
+		if err := r.stream.box.Process(h.ctx, in, &streamWriter{h, r.stream}); err != nil {
+			return fmt.Errorf("stream '%v' failed to process a tuple: %v", r.stream.name, err)
+		}
+	}
+	return nil
+}
+
+// streamWriter is a core.Writer passing tuples emitted by a stream.
+type streamWriter struct {
+	h *Harness
+	s *stream
+}
+
+func (w *streamWriter) Write(ctx *core.Context, t *core.Tuple) error {
+	return w.h.emit(w.s, t)
+}
+
+// Tuples returns tuples emitted by the stream since the Harness was created
+// or the last call to Clear. Tuples fed to the stream are also included. The
+// returned tuples must not be modified.
+func (h *Harness) Tuples(stream string) []*core.Tuple {
+	s, ok := h.streams[strings.ToLower(stream)]
+	if !ok {
+		return nil
+	}
+	return s.output
+}
+
+// Output returns data of tuples returned from Tuples.
+func (h *Harness) Output(stream string) []data.Map {
+	ts := h.Tuples(stream)
+	res := make([]data.Map, len(ts))
+	for i, t := range ts {
+		res[i] = t.Data
+	}
+	return res
+}
+
+// Clear removes recorded tuples of all streams. Windows of statements aren't
+// cleared.
+func (h *Harness) Clear() {
+	for _, s := range h.streams {
+		s.output = nil
+	}
+}
+
+// Close terminates all statements and stops the topology having states.
+func (h *Harness) Close() error {
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+	var errs []string
+	for _, s := range h.streams {
+		if s.box == nil {
+			continue
+		}
+		if err := s.box.Terminate(h.ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("stream '%v': %v", s.name, err))
+		}
+	}
+	if err := h.tb.Topology().Stop(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("cannot close the harness: %v", strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package testkit
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	_ "gopkg.in/sensorbee/sensorbee.v0/bql/udf/builtin"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestHarness(t *testing.T) {
+	Convey("Given a harness with derived streams", t, func() {
+		h, err := New(`CREATE STREAM hot AS SELECT RSTREAM * FROM readings [RANGE 1 TUPLES]
+				WHERE temperature > 30;
+			CREATE STREAM counts AS SELECT RSTREAM count(*) AS c FROM hot [RANGE 10 SECONDS];
+			CREATE STREAM stamped AS SELECT RSTREAM now() AS now, ts() AS ts FROM readings [RANGE 1 TUPLES];`)
+		So(err, ShouldBeNil)
+		Reset(func() {
+			h.Close()
+		})
+
+		Convey("When feeding tuples", func() {
+			So(h.Feed("readings", data.Map{"temperature": data.Int(31)}), ShouldBeNil)
+			h.Advance(5 * time.Second)
+			So(h.Feed("readings", data.Map{"temperature": data.Int(20)}), ShouldBeNil)
+			h.Advance(6 * time.Second)
+			So(h.Feed("readings", data.Map{"temperature": data.Int(35)}), ShouldBeNil)
+
+			Convey("Then outputs should be available right after feeding", func() {
+				So(h.Output("readings"), ShouldHaveLength, 3)
+				So(h.Output("hot"), ShouldResemble, []data.Map{
+					{"temperature": data.Int(31)},
+					{"temperature": data.Int(35)},
+				})
+			})
+
+			Convey("Then windows should follow the virtual clock", func() {
+				So(h.Output("counts"), ShouldResemble, []data.Map{
+					{"c": data.Int(1)},
+					{"c": data.Int(1)},
+				})
+			})
+
+			Convey("Then now() should return the virtual time", func() {
+				out := h.Output("stamped")
+				So(out, ShouldHaveLength, 3)
+				So(out[1]["now"], ShouldResemble, data.Timestamp(DefaultStartTime.Add(5*time.Second)))
+				So(out[1]["ts"], ShouldResemble, data.Timestamp(DefaultStartTime.Add(5*time.Second)))
+			})
+
+			Convey("Then Clear should remove outputs", func() {
+				h.Clear()
+				So(h.Output("hot"), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When feeding a tuple with a timestamp", func() {
+			ts := DefaultStartTime.Add(time.Hour)
+			So(h.FeedAt("readings", ts, data.Map{"temperature": data.Int(10)}), ShouldBeNil)
+
+			Convey("Then the tuple should have the timestamp", func() {
+				out := h.Output("stamped")
+				So(out[0]["ts"], ShouldResemble, data.Timestamp(ts))
+				So(out[0]["now"], ShouldResemble, data.Timestamp(DefaultStartTime))
+			})
+		})
+
+		Convey("When feeding a tuple after closing the harness", func() {
+			So(h.Close(), ShouldBeNil)
+
+			Convey("Then it should fail", func() {
+				So(h.Feed("readings", data.Map{}), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a harness with a state", t, func() {
+		h, err := New(`CREATE STATE t TYPE latest_by WITH key="id";
+			CREATE STREAM s AS SELECT RSTREAM * FROM readings [RANGE 1 TUPLES];`)
+		So(err, ShouldBeNil)
+		Reset(func() {
+			h.Close()
+		})
+
+		Convey("Then the state should be created in the topology", func() {
+			_, err := h.TopologyBuilder().Topology().Context().SharedStates.Get("t")
+			So(err, ShouldBeNil)
+		})
+	})
+
+	Convey("Given unsupported statements", t, func() {
+		for _, stmt := range []string{
+			"CREATE SOURCE s TYPE dummy",
+			"CREATE STREAM a AS SELECT RSTREAM * FROM a [RANGE 1 TUPLES]",
+			`CREATE STREAM a AS SELECT RSTREAM * FROM b [RANGE 1 TUPLES];
+			CREATE STREAM b AS SELECT RSTREAM * FROM c [RANGE 1 TUPLES];`,
+		} {
+			Convey("When creating a harness with "+stmt, func() {
+				_, err := New(stmt)
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package bql
+
+import (
+	"fmt"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// minTopKCapacity is the minimum number of counters of top_k when the
+	// capacity isn't given. The default capacity is 10 times k.
+	minTopKCapacity = 100
+
+	// maxTopKCapacity is the maximum number of counters of top_k.
+	maxTopKCapacity = 100000
+)
+
+// topKFunc is an aggregate function returning the k most frequent values in
+// a window. Unlike GROUP BY with ORDER BY, which keeps a group for every
+// distinct value, it counts values with a space-saving sketch having a
+// bounded number of counters, so that "top talkers" of a stream having many
+// distinct values can be found in constant memory:
+//
+//	SELECT RSTREAM top_k(src_ip, 10) AS talkers
+//	  FROM packets [RANGE 1 MINUTES];
+//
+// It can be used in BQL as `top_k(expr, k)` or `top_k(expr, k, capacity)`.
+// capacity is the number of counters, which is 10 times k (at least 100)
+// by default and at most 100000. Counts are exact as long as the number of
+// distinct values in the window doesn't exceed capacity. Otherwise, a count
+// may be overestimated by at most the "error" field of the result, and any
+// value occurring more than n/capacity times in n values is found.
+//
+//	Input: any (aggregated), Int, Int (optional)
+//	Return Type: Array of Maps having "value", "count", and "error" fields
+//	  in the descending order of count (Null on empty input)
+//
+// Null values are ignored.
+type topKFunc struct{}
+
+var _ udf.UDF = &topKFunc{}
+
+func (f *topKFunc) Accept(arity int) bool {
+	return arity == 2 || arity == 3
+}
+
+func (f *topKFunc) IsAggregationParameter(k int) bool {
+	return k == 0
+}
+
+func (f *topKFunc) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, fmt.Errorf("function takes two or three arguments")
+	}
+	arr, err := data.AsArray(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("function needs array input, not %T", args[0])
+	}
+	k, err := data.AsInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("k must be an integer: %v", args[1])
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("k must be positive: %v", k)
+	}
+
+	capacity := k * 10
+	if capacity < minTopKCapacity {
+		capacity = minTopKCapacity
+	}
+	if len(args) == 3 {
+		if capacity, err = data.AsInt(args[2]); err != nil {
+			return nil, fmt.Errorf("capacity must be an integer: %v", args[2])
+		}
+		if capacity < k {
+			return nil, fmt.Errorf("capacity must be greater than or equal to k: %v", capacity)
+		}
+	}
+	if capacity > maxTopKCapacity {
+		if len(args) == 3 {
+			return nil, fmt.Errorf("capacity must be at most %v: %v", maxTopKCapacity, capacity)
+		}
+		capacity = maxTopKCapacity
+	}
+	if k > capacity {
+		return nil, fmt.Errorf("k must be at most %v: %v", maxTopKCapacity, k)
+	}
+
+	s, err := newSpaceSaving(int(capacity))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range arr {
+		if v.Type() == data.TypeNull {
+			continue
+		}
+		s.addCount(v, 1)
+	}
+	if s.total == 0 {
+		return data.Null{}, nil
+	}
+
+	top := s.top(int(k))
+	res := make(data.Array, len(top))
+	for i, c := range top {
+		res[i] = data.Map{
+			"value": c.value,
+			"count": data.Int(c.count),
+			"error": data.Int(c.err),
+		}
+	}
+	return res, nil
+}
+
+func init() {
+	udf.MustRegisterGlobalUDF("top_k", &topKFunc{})
+}
@@ -0,0 +1,119 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestTopK(t *testing.T) {
+	ctx := core.NewContext(nil)
+	f := &topKFunc{}
+
+	Convey("Given the top_k function", t, func() {
+		Convey("When counting values fitting in the capacity", func() {
+			arr := data.Array{}
+			for i := 0; i < 100; i++ {
+				arr = append(arr, data.Int(i%10*i%7))
+			}
+			arr = append(arr, data.String("a"), data.String("a"), data.Null{})
+			v, err := f.Call(ctx, arr, data.Int(3))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should return exact counts of the k most frequent values", func() {
+				counts := map[data.HashValue]int64{}
+				for _, x := range arr {
+					if x.Type() != data.TypeNull {
+						counts[data.Hash(x)]++
+					}
+				}
+				res := v.(data.Array)
+				So(len(res), ShouldEqual, 3)
+				prev := int64(len(arr))
+				for _, r := range res {
+					m := r.(data.Map)
+					c, _ := data.AsInt(m["count"])
+					So(c, ShouldEqual, counts[data.Hash(m["value"])])
+					So(c, ShouldBeLessThanOrEqualTo, prev)
+					So(m["error"], ShouldEqual, data.Int(0))
+					prev = c
+				}
+			})
+		})
+
+		Convey("When there are more distinct values than the capacity", func() {
+			arr := data.Array{}
+			for i := 0; i < 1000; i++ {
+				arr = append(arr, data.Int(i)) // each value occurs once
+				if i%4 == 0 {
+					arr = append(arr, data.String("heavy"))
+				}
+				if i%10 == 0 {
+					arr = append(arr, data.String("medium"))
+				}
+			}
+			v, err := f.Call(ctx, arr, data.Int(2), data.Int(20))
+			So(err, ShouldBeNil)
+
+			Convey("Then heavy hitters should be found", func() {
+				res := v.(data.Array)
+				So(len(res), ShouldEqual, 2)
+				for i, name := range []string{"heavy", "medium"} {
+					m := res[i].(data.Map)
+					So(m["value"], ShouldEqual, data.String(name))
+					c, _ := data.AsInt(m["count"])
+					e, _ := data.AsInt(m["error"])
+					So(c-e, ShouldBeLessThanOrEqualTo, map[string]int64{"heavy": 250, "medium": 100}[name])
+					So(c, ShouldBeGreaterThanOrEqualTo, map[string]int64{"heavy": 250, "medium": 100}[name])
+				}
+			})
+		})
+
+		Convey("When the input only has NULLs", func() {
+			v, err := f.Call(ctx, data.Array{data.Null{}}, data.Int(1))
+
+			Convey("Then it should return NULL", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, data.Null{})
+			})
+		})
+
+		Convey("When arguments are invalid", func() {
+			for _, args := range [][]data.Value{
+				{data.Array{}, data.Int(0)},
+				{data.Array{}, data.String("a")},
+				{data.Array{}, data.Int(10), data.Int(5)},
+				{data.Array{}, data.Int(10), data.Int(maxTopKCapacity + 1)},
+				{data.Int(1), data.Int(1)},
+			} {
+				_, err := f.Call(ctx, args...)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+
+	Convey("Given a stream selecting top_k in a window", t, func() {
+		tb, err := setupTopology(`CREATE STREAM box AS SELECT RSTREAM top_k(int % 2, 1) AS t
+			FROM source [RANGE 3 TUPLES]`, false)
+		So(err, ShouldBeNil)
+		dt := tb.Topology()
+		Reset(func() {
+			dt.Stop()
+		})
+		sin, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sin.Sink().(*tupleCollectorSink)
+
+		Convey("When tuples are emitted", func() {
+			si.Wait(4)
+
+			Convey("Then the most frequent value in the window should be emitted", func() {
+				So(si.get(3).Data["t"], ShouldResemble, data.Array{
+					data.Map{"value": data.Int(0), "count": data.Int(2), "error": data.Int(0)},
+				})
+			})
+		})
+	})
+}
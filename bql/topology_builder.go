@@ -3,10 +3,13 @@ package bql
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
 	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
@@ -23,6 +26,75 @@ type TopologyBuilder struct {
 	SourceCreators SourceCreatorRegistry
 	SinkCreators   SinkCreatorRegistry
 	UDSStorage     udf.UDSStorage
+
+	// ParamEnvAllowlist has names of environment variables which string
+	// values of parameters in WITH clauses can refer to. An entry ending with
+	// '*' allows all names having the prefix. References are resolved when a
+	// statement is executed:
+	//
+	//   - ${NAME} is replaced with the value of NAME. The statement fails
+	//     when the variable isn't set.
+	//   - ${default(NAME, fallback)} is replaced with the value of NAME, or
+	//     fallback when the variable isn't set. Spaces around fallback are
+	//     removed.
+	//   - $${ is replaced with ${.
+	//
+	// A statement referring to a variable which isn't allowed fails.
+	// References are left as they are when the allowlist is empty.
+	ParamEnvAllowlist []string
+
+	// schemas has types created by CREATE TYPE statements. Its keys are
+	// lower case names of the types.
+	schemaMutex sync.RWMutex
+	schemas     map[string]*Schema
+
+	// statements has the statement which created each node. Its keys are
+	// names of nodes. Statements of removed nodes are deleted lazily when
+	// system.statements is read.
+	stmtMutex  sync.Mutex
+	statements map[string]string
+
+	// systemStreamInterval is the interval at which virtual streams such as
+	// system.nodes emit tuples.
+	systemStreamInterval time.Duration
+
+	// templates has templates created by CREATE TEMPLATE statements and
+	// templateInstances has instances created by INSTANTIATE TEMPLATE
+	// statements. Their keys are lower case names.
+	templateMutex     sync.Mutex
+	templates         map[string]*Template
+	templateInstances map[string]*templateInstance
+
+	// backfills has progresses of backfills. Its keys are lower case names
+	// of streams created by Backfill. Progresses of removed streams are
+	// deleted lazily when they're read.
+	backfillMutex sync.Mutex
+	backfills     map[string]*backfill
+	// stateLabels has labels of states created by CREATE STATE statements.
+	// Its keys are lower case names of the states.
+	stateLabelMutex sync.Mutex
+	stateLabels     map[string]map[string]string
+	// sinkQueues has queue configs of sinks created by CREATE SINK
+	// statements. Its keys are lower case names of the sinks.
+	sinkQueueMutex sync.Mutex
+	sinkQueues     map[string]queueConfig
+	// redactions has redaction policies of streams created by CREATE
+	// STREAM statements. Its keys are lower case names of the streams.
+	redactionMutex sync.Mutex
+	redactions     map[string]*redactionPolicy
+	// nodeOwners and stateOwners have owners of nodes and states created by
+	// CREATE statements. Their keys are lower case names.
+	ownerMutex  sync.Mutex
+	nodeOwners  map[string]*ownership
+	stateOwners map[string]*ownership
+
+	// admission limits statements running at the same time.
+	admission admission
+
+	// lineage has lineage records of tuples processed by BQL statements.
+	// It's nil until a statement is created with "lineage_max_records".
+	lineageMutex sync.Mutex
+	lineage      *lineageRecorder
 }
 
 // TODO: Provide AtomicTopologyBuilder which support building multiple nodes
@@ -73,6 +145,23 @@ func NewTopologyBuilder(t core.Topology) (*TopologyBuilder, error) {
 		SourceCreators: srcs,
 		SinkCreators:   sinks,
 		UDSStorage:     udf.NewInMemoryUDSStorage(),
+		schemas:        map[string]*Schema{},
+		statements:     map[string]string{},
+
+		systemStreamInterval: 1 * time.Second,
+		templates:            map[string]*Template{},
+		templateInstances:    map[string]*templateInstance{},
+		backfills:            map[string]*backfill{},
+		stateLabels:          map[string]map[string]string{},
+		sinkQueues:           map[string]queueConfig{},
+		redactions:           map[string]*redactionPolicy{},
+		nodeOwners:           map[string]*ownership{},
+		stateOwners:          map[string]*ownership{},
+	}
+	// lineage UDF is registered here because it reads records of this
+	// TopologyBuilder.
+	if err := tb.Reg.Register("lineage", createLineageUDF(tb)); err != nil {
+		return nil, err
 	}
 	return tb, nil
 }
@@ -95,35 +184,115 @@ func (tb *TopologyBuilder) Topology() core.Topology {
 
 // AddStmt add a node created from a statement to the topology. It returns
 // a created node. It returns a nil node when the statement is CREATE STATE.
+// Statements creating temporary nodes have to be added by Session.AddStmt.
+// The statement is added without an owner.
 func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
+	return tb.AddStmtAs("", stmt)
+}
+
+// AddStmtAs adds a statement in the same way as AddStmt on behalf of the
+// owner. Nodes and states created by the statement are owned by the owner
+// and the statement fails with ProtectedError when it drops or alters a
+// protected node or state owned by others.
+func (tb *TopologyBuilder) AddStmtAs(owner string, stmt interface{}) (core.Node, error) {
+	if IsTemporaryStmt(stmt) {
+		return nil, errors.New("a temporary node can only be created in a session")
+	}
+	release, err := tb.admit(maxConcurrentStatementsConfig, "statements")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return tb.addStmt(owner, stmt)
+}
+
+// addStmt processes the statement on behalf of the owner and records the
+// statement which created the node.
+func (tb *TopologyBuilder) addStmt(owner string, stmt interface{}) (core.Node, error) {
+	if err := tb.checkOwnership(owner, stmt); err != nil {
+		return nil, err
+	}
+	kind, names, protected, err := ownershipCreated(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if protected && owner == "" {
+		return nil, errors.New("a protected node or state can only be created by a client having an owner")
+	}
+
+	n, err := tb.processStmt(owner, stmt)
+	if err != nil {
+		return n, err
+	}
+	if kind != "" {
+		tb.setOwnership(kind, names, &ownership{owner: owner, protected: protected})
+	} else if k, name := ownershipTarget(stmt); k != "" && isDropStmt(stmt) {
+		tb.setOwnership(k, []string{name}, nil)
+	}
+	if n == nil {
+		return nil, nil
+	}
+	if s, ok := stmt.(fmt.Stringer); ok {
+		tb.stmtMutex.Lock()
+		tb.statements[n.Name()] = s.String()
+		tb.stmtMutex.Unlock()
+	}
+	return n, nil
+}
+
+func (tb *TopologyBuilder) processStmt(owner string, stmt interface{}) (core.Node, error) {
 	// TODO: Enable StopOnDisconnect properly
 
 	// check the type of statement
 	switch stmt := stmt.(type) {
 	case parser.CreateSourceStmt:
 		// load params into map for faster access
-		paramsMap := tb.mkParamsMap(stmt.Params)
+		paramsMap, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		delete(paramsMap, protectedParam)
+		labels, err := extractLabels(paramsMap)
+		if err != nil {
+			return nil, err
+		}
+
+		extractor, err := timestampExtractorFromTimestampBy(stmt.TimestampByAST)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := tb.lookupSchema(stmt.SchemaAST)
+		if err != nil {
+			return nil, err
+		}
 
 		// check if we know this type of source
 		creator, err := tb.SourceCreators.Lookup(string(stmt.Type))
 		if err != nil {
 			return nil, err
 		}
+		if paramsMap, err = validateCreatorParams(creator, string(stmt.Type), paramsMap); err != nil {
+			return nil, err
+		}
 
 		// if so, try to create such a source
-		source, err := creator.CreateSource(tb.topology.Context(), &IOParams{
-			TypeName: string(stmt.Type),
-			Name:     string(stmt.Name),
-		}, paramsMap)
+		source, err := tb.createSource(creator, &stmt, paramsMap)
 		if err != nil {
 			return nil, err
 		}
-		return tb.topology.AddSource(string(stmt.Name), source, &core.SourceConfig{
-			PausedOnStartup: stmt.Paused == parser.Yes,
-		})
+		conf := &core.SourceConfig{
+			PausedOnStartup:    stmt.Paused == parser.Yes,
+			TimestampExtractor: extractor,
+			Labels:             labels,
+		}
+		if schema != nil {
+			conf.Validator = schema
+			conf.Meta = schema
+		}
+		return tb.topology.AddSource(string(stmt.Name), source, conf)
 
 	case parser.CreateStreamAsSelectStmt:
-		return tb.createStreamAsSelectStmt(&stmt)
+		return tb.createStreamAsSelectStmt(&stmt, nil, false)
 
 	case parser.CreateStreamAsSelectUnionStmt:
 		// idea: create an intermediate box for each SELECT substatement,
@@ -135,14 +304,25 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 				tb.topology.Remove(name)
 			}
 		}
+		// the redaction policy is only applied to the resulting stream
+		redaction, params, err := tb.streamRedactionPolicy(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
 		for _, selStmt := range stmt.Selects {
 			// create a stream with a generated name and recurse
 			tmpName := fmt.Sprintf("sensorbee_tmp_%v", topologyBuilderNextTemporaryID())
 			tmpStmt := parser.CreateStreamAsSelectStmt{
+				parser.UnspecifiedKeyword,
 				parser.StreamIdentifier(tmpName),
+				parser.SchemaAST{},
 				selStmt,
+				parser.TimestampByAST{},
+				stmt.OnErrorAST,
+				stmt.TimeoutAST,
+				parser.SourceSinkSpecsAST{params},
 			}
-			box, err := tb.AddStmt(tmpStmt)
+			box, err := tb.addStmt("", tmpStmt)
 			if err != nil {
 				removeTmpNodes()
 				return nil, err
@@ -154,7 +334,41 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 		forwardBox := core.BoxFunc(func(ctx *core.Context, t *core.Tuple, w core.Writer) error {
 			return w.Write(ctx, t)
 		})
-		node, err := tb.topology.AddBox(string(stmt.Name), forwardBox, nil)
+		// the numeric policy and the queue config have been applied to
+		// each SELECT
+		_, params, err = tb.numericPolicy(params)
+		if err != nil {
+			removeTmpNodes()
+			return nil, err
+		}
+		if _, params, err = tb.streamQueueConfig(params); err != nil {
+			removeTmpNodes()
+			return nil, err
+		}
+		labels, err := tb.streamLabels(params)
+		if err != nil {
+			removeTmpNodes()
+			return nil, err
+		}
+		extractor, err := timestampExtractorFromTimestampBy(stmt.TimestampByAST)
+		if err != nil {
+			removeTmpNodes()
+			return nil, err
+		}
+		schema, err := tb.lookupSchema(stmt.SchemaAST)
+		if err != nil {
+			removeTmpNodes()
+			return nil, err
+		}
+		conf := &core.BoxConfig{
+			TimestampExtractor: extractor,
+			Labels:             labels,
+		}
+		if schema != nil {
+			conf.Validator = schema
+			conf.Meta = schema
+		}
+		node, err := tb.topology.AddBox(string(stmt.Name), forwardBox, conf)
 		if err != nil {
 			removeTmpNodes()
 			return nil, err
@@ -172,17 +386,44 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 		}
 		node.StopOnDisconnect(core.Inbound)
 		node.RemoveOnStop()
+		tb.setRedactionPolicy(string(stmt.Name), redaction)
 		return node, nil
 
+	case parser.CreateStreamRoutesStmt:
+		return tb.createStreamRoutesStmt(&stmt)
+
 	case parser.CreateSinkStmt:
 		// load params into map for faster access
-		paramsMap := tb.mkParamsMap(stmt.Params)
+		paramsMap, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		delete(paramsMap, protectedParam)
+		labels, err := extractLabels(paramsMap)
+		if err != nil {
+			return nil, err
+		}
+		queue, err := extractQueueConfig(paramsMap)
+		if err != nil {
+			return nil, err
+		}
 
 		// check if we know this type of sink
 		creator, err := tb.SinkCreators.Lookup(string(stmt.Type))
 		if err != nil {
 			return nil, err
 		}
+		if paramsMap, err = validateCreatorParams(creator, string(stmt.Type), paramsMap); err != nil {
+			return nil, err
+		}
+		config, err := sinkConfigFromOrderBy(stmt.SinkOrderingAST)
+		if err != nil {
+			return nil, err
+		}
+		config.Labels = labels
+		if config.WAL, err = tb.sinkWAL(string(stmt.Name)); err != nil {
+			return nil, err
+		}
 
 		// if so, try to create such a sink
 		sink, err := creator.CreateSink(tb.topology.Context(), &IOParams{
@@ -195,7 +436,12 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 		// we insert a sink, but cannot connect it to
 		// any streams yet, therefore we have to keep track
 		// of the SinkDeclarer
-		return tb.topology.AddSink(string(stmt.Name), sink, nil)
+		sn, err := tb.topology.AddSink(string(stmt.Name), sink, config)
+		if err != nil {
+			return nil, err
+		}
+		tb.setSinkQueueConfig(string(stmt.Name), queue)
+		return sn, nil
 
 	case parser.CreateStateStmt:
 		c, err := tb.UDSCreators.Lookup(string(stmt.Type))
@@ -203,14 +449,31 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 			return nil, err
 		}
 
+		paramsMap, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		delete(paramsMap, protectedParam)
+		labels, err := extractLabels(paramsMap)
+		if err != nil {
+			return nil, err
+		}
+
 		ctx := tb.topology.Context()
-		s, err := c.CreateState(ctx, tb.mkParamsMap(stmt.Params))
+		s, err := c.CreateState(ctx, paramsMap)
 		if err != nil {
 			return nil, err
 		}
 		if err := ctx.SharedStates.Add(string(stmt.Name), string(stmt.Type), s); err != nil {
 			return nil, err
 		}
+		if err := tb.feedLatestBy(string(stmt.Name), s); err != nil {
+			if _, err := ctx.SharedStates.Remove(string(stmt.Name)); err != nil {
+				ctx.ErrLog(err).WithField("state", string(stmt.Name)).Error("Cannot remove the state")
+			}
+			return nil, err
+		}
+		tb.setStateLabels(string(stmt.Name), labels)
 		return nil, nil
 
 	case parser.UpdateStateStmt:
@@ -224,23 +487,35 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 		if !ok {
 			return nil, fmt.Errorf("%s cannot be updated", string(stmt.Name))
 		}
-		return nil, u.Update(ctx, tb.mkParamsMap(stmt.Params))
+		params, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		return nil, u.Update(ctx, params)
 
 	case parser.SaveStateStmt:
 		return nil, tb.saveState(string(stmt.Name), stmt.Tag)
 
 	case parser.LoadStateStmt:
-		_, err := tb.loadState(string(stmt.Type), string(stmt.Name), stmt.Tag, tb.mkParamsMap(stmt.Params))
+		params, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tb.loadState(string(stmt.Type), string(stmt.Name), stmt.Tag, params)
 		return nil, err
 
 	case parser.LoadStateOrCreateStmt:
-		shouldCreate, err := tb.loadState(string(stmt.Type), string(stmt.Name), stmt.Tag, tb.mkParamsMap(stmt.LoadSpecs.Params))
+		params, err := tb.mkParamsMap(stmt.LoadSpecs.Params)
+		if err != nil {
+			return nil, err
+		}
+		shouldCreate, err := tb.loadState(string(stmt.Type), string(stmt.Name), stmt.Tag, params)
 		if shouldCreate {
 			c := parser.CreateStateStmt{}
 			c.Type = stmt.Type
 			c.Name = stmt.Name
 			c.Params = stmt.CreateSpecs.Params
-			return tb.AddStmt(c)
+			return tb.addStmt(owner, c)
 		}
 		return nil, err
 
@@ -254,7 +529,11 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 		if !ok {
 			return nil, fmt.Errorf("%s cannot be updated", string(stmt.Name))
 		}
-		return nil, u.Update(tb.topology.Context(), tb.mkParamsMap(stmt.Params))
+		params, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		return nil, u.Update(tb.topology.Context(), params)
 
 	case parser.UpdateSinkStmt:
 		sink, err := tb.topology.Sink(string(stmt.Name))
@@ -266,7 +545,19 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 		if !ok {
 			return nil, fmt.Errorf("%s cannot be updated", string(stmt.Name))
 		}
-		return nil, u.Update(tb.topology.Context(), tb.mkParamsMap(stmt.Params))
+		params, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		return nil, u.Update(tb.topology.Context(), params)
+
+	case parser.SetConfigStmt:
+		params, err := tb.mkParamsMap(stmt.Params)
+		if err != nil {
+			return nil, err
+		}
+		tb.topology.Context().Config.Update(params)
+		return nil, nil
 
 	case parser.DropSourceStmt:
 		_, err := tb.topology.Source(string(stmt.Source))
@@ -282,14 +573,39 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 			return nil, err
 		}
 
+		tb.setRedactionPolicy(string(stmt.Stream), nil)
 		return nil, tb.topology.Remove(string(stmt.Stream))
 
+	case parser.ResumeStreamStmt:
+		box, err := tb.topology.Box(string(stmt.Stream))
+		if err != nil {
+			return nil, err
+		}
+		if err := box.ReleaseQuarantine(); err != nil {
+			return nil, err
+		}
+		return box, nil
+
+	case parser.ProfileStreamStmt:
+		return tb.profileStream(&stmt)
+
+	case parser.ReplaySinkStmt:
+		sink, err := tb.topology.Sink(string(stmt.Sink))
+		if err != nil {
+			return nil, err
+		}
+		if err := sink.ReplayWAL(); err != nil {
+			return nil, err
+		}
+		return sink, nil
+
 	case parser.DropSinkStmt:
 		_, err := tb.topology.Sink(string(stmt.Sink))
 		if err != nil {
 			return nil, err
 		}
 
+		tb.setSinkQueueConfig(string(stmt.Sink), queueConfig{})
 		return nil, tb.topology.Remove(string(stmt.Sink))
 
 	case parser.DropStateStmt:
@@ -299,17 +615,69 @@ func (tb *TopologyBuilder) AddStmt(stmt interface{}) (core.Node, error) {
 			return nil, err
 		}
 
+		tb.setStateLabels(string(stmt.State), nil)
+		tb.removeLatestByFeeder(string(stmt.State))
 		_, err = ctx.SharedStates.Remove(string(stmt.State))
 		return nil, err
 
+	case parser.CreateTypeStmt:
+		return nil, tb.createType(&stmt)
+
+	case parser.DropTypeStmt:
+		return nil, tb.dropType(&stmt)
+
+	case parser.CreateTriggerStmt:
+		return tb.createTrigger(&stmt)
+
+	case parser.DropTriggerStmt:
+		return nil, tb.dropTrigger(&stmt)
+
+	case parser.CreateAlertStmt:
+		return tb.createAlert(&stmt)
+
+	case parser.DropAlertStmt:
+		return nil, tb.dropAlert(&stmt)
+
+	case parser.CreateTemplateStmt:
+		return nil, tb.createTemplate(&stmt)
+
+	case parser.InstantiateTemplateStmt:
+		return nil, tb.instantiateTemplate(owner, &stmt)
+
+	case parser.DropTemplateStmt:
+		return nil, tb.dropTemplate(&stmt)
+
+	case parser.DropTemplateInstanceStmt:
+		return nil, tb.dropTemplateInstance(&stmt)
+
 	case parser.InsertIntoFromStmt:
 		// get the sink to add an input to
 		sink, err := tb.topology.Sink(string(stmt.Sink))
 		if err != nil {
 			return nil, err
 		}
+		errMode, maxRetries, err := errorModeFromOnError(stmt.OnErrorAST)
+		if err != nil {
+			return nil, err
+		}
+		numeric, _, err := tb.numericPolicy(nil)
+		if err != nil {
+			return nil, err
+		}
+		transformer, err := newEdgeTransformer(&stmt, execution.WithNumericPolicy(tb.Reg, numeric))
+		if err != nil {
+			return nil, err
+		}
+		transformer = tb.redactionTransformer(string(stmt.Input), transformer)
+		queue := tb.sinkQueueConfig(string(stmt.Sink))
 		// now connect the sink to the specified box
-		if err := sink.Input(string(stmt.Input), nil); err != nil {
+		if err := sink.Input(string(stmt.Input), &core.SinkInputConfig{
+			Capacity:    queue.capacity,
+			DropMode:    queue.dropMode,
+			ErrorMode:   errMode,
+			MaxRetries:  maxRetries,
+			Transformer: transformer,
+		}); err != nil {
 			return nil, err
 		}
 		return sink, nil
@@ -355,6 +723,7 @@ type udsfBox struct {
 
 var (
 	_ core.StatefulBox = &udsfBox{}
+	_ core.Statuser    = &udsfBox{}
 )
 
 func newUDSFBox(f udf.UDSF) *udsfBox {
@@ -375,6 +744,14 @@ func (b *udsfBox) Terminate(ctx *core.Context) error {
 	return b.f.Terminate(ctx)
 }
 
+// Status returns the status of the UDSF if it implements core.Statuser.
+func (b *udsfBox) Status() data.Map {
+	if s, ok := b.f.(core.Statuser); ok {
+		return s.Status()
+	}
+	return data.Map{}
+}
+
 // udsfSource is a core.Source which runs a UDSF in the source mode.
 type udsfSource struct {
 	f       udf.UDSF
@@ -408,12 +785,84 @@ func (s *udsfSource) Stop(ctx *core.Context) error {
 	return s.f.Terminate(ctx)
 }
 
-func (tb *TopologyBuilder) createStreamAsSelectStmt(stmt *parser.CreateStreamAsSelectStmt) (core.Node, error) {
+// createStreamAsSelectStmt creates a bqlBox executing the SELECT statement.
+// inputs maps a lower case name of a relation to names of nodes which are
+// connected to the box instead of the relation itself. Tuples from those nodes
+// have the name of the relation as their input name. inputs can be nil. When
+// redact is true, redaction policies of input streams are applied to tuples
+// received from them, which is required when results of the statement leave
+// the topology.
+func (tb *TopologyBuilder) createStreamAsSelectStmt(stmt *parser.CreateStreamAsSelectStmt,
+	inputs map[string][]string, redact bool) (core.Node, error) {
+	if err := tb.applyQueryDefaults(&stmt.Select); err != nil {
+		return nil, err
+	}
+	errMode, maxRetries, err := errorModeFromOnError(stmt.OnErrorAST)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := processTimeoutFromTimeout(stmt.TimeoutAST)
+	if err != nil {
+		return nil, err
+	}
+	extractor, err := timestampExtractorFromTimestampBy(stmt.TimestampByAST)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := tb.lookupSchema(stmt.SchemaAST)
+	if err != nil {
+		return nil, err
+	}
+	if err := tb.checkInputSchemas(&stmt.Select); err != nil {
+		return nil, err
+	}
+	numeric, params, err := tb.numericPolicy(stmt.Params)
+	if err != nil {
+		return nil, err
+	}
+	queue, params, err := tb.streamQueueConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	redaction, params, err := tb.streamRedactionPolicy(params)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := tb.streamLabels(params)
+	if err != nil {
+		return nil, err
+	}
+
 	// insert a bqlBox that executes the SELECT statement
 	outName := string(stmt.Name)
+	quarantine, err := tb.quarantinePolicy()
+	if err != nil {
+		return nil, err
+	}
+	propagation, err := tb.metadataPropagation()
+	if err != nil {
+		return nil, err
+	}
+	lineage, err := tb.boxLineage(outName, stmt.String())
+	if err != nil {
+		return nil, err
+	}
 	box := NewBQLBox(&stmt.Select, tb.Reg)
+	box.numeric = numeric
+	box.metadata = propagation
+	box.lineage = lineage
+	conf := &core.BoxConfig{
+		TimestampExtractor: extractor,
+		ProcessTimeout:     timeout,
+		Quarantine:         quarantine,
+		Labels:             labels,
+	}
+	if schema != nil {
+		conf.Validator = schema
+		conf.Meta = schema
+	}
 	// add all the referenced relations as named inputs
-	dbox, err := tb.topology.AddBox(outName, box, nil)
+	dbox, err := tb.topology.AddBox(outName, box, conf)
 	if err != nil {
 		return nil, err
 	}
@@ -467,27 +916,30 @@ func (tb *TopologyBuilder) createStreamAsSelectStmt(stmt *parser.CreateStreamAsS
 				//
 				// If we used the rel.Alias here, then we would have to make multiple
 				// input connections to the same box, which is not possible.
-				InputName: rel.Name,
+				InputName:  rel.Name,
+				ErrorMode:  errMode,
+				MaxRetries: maxRetries,
 			}
-			// set capacity of input pipe
-			if rel.Capacity != parser.UnspecifiedCapacity {
-				conf.Capacity = int(rel.Capacity)
+			if err := queue.boxInputConfig(&rel, conf); err != nil {
+				return nil, err
 			}
-			// set drop mode for box
-			if rel.Shedding == parser.DropOldest {
-				conf.DropMode = core.DropOldest
-			} else if rel.Shedding == parser.DropNewest {
-				conf.DropMode = core.DropLatest
-			} else if rel.Shedding == parser.Wait {
-				conf.DropMode = core.DropNone
+			srcs, ok := inputs[strings.ToLower(rel.Name)]
+			if !ok {
+				srcs = []string{rel.Name}
 			}
-			if err := dbox.Input(rel.Name, conf); err != nil {
-				return nil, err
+			for _, src := range srcs {
+				c := *conf
+				if redact {
+					c.Transformer = tb.redactionTransformer(src, nil)
+				}
+				if err := dbox.Input(src, &c); err != nil {
+					return nil, err
+				}
 			}
 			connected[rel.Name] = true
 
-		case parser.UDSFStream:
-			sn, name, err := tb.setUpUDSFStream(dbox, &rel)
+		case parser.UDSFStream, parser.UnnestStream:
+			sn, name, err := tb.setUpUDSFStream(dbox, &stmt.Select, &rel, queue, errMode, maxRetries, timeout)
 			if err != nil {
 				return nil, err
 			}
@@ -496,6 +948,14 @@ func (tb *TopologyBuilder) createStreamAsSelectStmt(stmt *parser.CreateStreamAsS
 				pausedSources = append(pausedSources, sn)
 			}
 
+		case parser.SystemStream:
+			sn, err := tb.setUpSystemStream(dbox, &rel, queue, errMode, maxRetries)
+			if err != nil {
+				return nil, err
+			}
+			temporaryNodes = append(temporaryNodes, sn.Name())
+			pausedSources = append(pausedSources, sn)
+
 		default:
 			return nil, fmt.Errorf("input stream of type %s not implemented",
 				rel.Type)
@@ -503,21 +963,319 @@ func (tb *TopologyBuilder) createStreamAsSelectStmt(stmt *parser.CreateStreamAsS
 	}
 	dbox.(core.BoxNode).StopOnDisconnect(core.Inbound)
 
-	// Resume all UDSFs running in the source mode as fairly as possible.
+	// Resume all UDSFs running in the source mode and virtual streams as
+	// fairly as possible.
 	for _, sn := range pausedSources {
 		if err := sn.Resume(); err != nil {
 			return nil, err
 		}
 	}
 	removeNodes = false
+	tb.setRedactionPolicy(outName, redaction)
 	return dbox, nil
 }
 
+// DefaultMaxRetries is the number of retries of `ON ERROR RETRY` when
+// the number isn't given.
+const DefaultMaxRetries = 3
+
+// errorModeFromOnError converts an ON ERROR clause to the error mode of
+// input pipes and the maximum number of retries. Without the clause, the
+// tuple which caused an error is reported as a dropped tuple, which is the
+// same as `ON ERROR DLQ`.
+func errorModeFromOnError(a parser.OnErrorAST) (core.ErrorMode, int, error) {
+	switch a.ErrorPolicy {
+	case parser.DropOnError:
+		return core.DropOnError, 0, nil
+	case parser.StopOnError:
+		return core.StopOnError, 0, nil
+	case parser.RetryOnError:
+		if a.Retries == parser.UnspecifiedRetries {
+			return core.RetryOnError, DefaultMaxRetries, nil
+		}
+		if a.Retries < 0 || a.Retries > math.MaxInt32 {
+			return 0, 0, fmt.Errorf("invalid number of retries: %v", a.Retries)
+		}
+		return core.RetryOnError, int(a.Retries), nil
+	}
+	return core.ReportOnError, 0, nil
+}
+
+// processTimeoutFromTimeout converts a TIMEOUT clause to the maximum
+// duration of processing a tuple. It returns 0 without the clause, which
+// means there's no timeout.
+func processTimeoutFromTimeout(a parser.TimeoutAST) (time.Duration, error) {
+	var unit time.Duration
+	switch a.Timeout.Unit {
+	case parser.UnspecifiedIntervalUnit:
+		return 0, nil
+	case parser.Seconds:
+		unit = time.Second
+	case parser.Milliseconds:
+		unit = time.Millisecond
+	default:
+		return 0, fmt.Errorf("invalid unit of a timeout: %v", a.Timeout.Unit)
+	}
+	d := time.Duration(a.Timeout.Value * float64(unit))
+	if d <= 0 {
+		return 0, fmt.Errorf("a timeout must be positive: %v", a.Timeout.Value)
+	}
+	return d, nil
+}
+
+// metadataPropagation returns how boxes created by BQL statements propagate
+// core.Tuple.Metadata of input tuples to output tuples. It's read from
+// "metadata_propagation" in the config store of the topology, which can be
+// "all" (default), "none", or an array of keys to be propagated. It returns
+// nil when all metadata is propagated.
+func (tb *TopologyBuilder) metadataPropagation() (*metadataPropagation, error) {
+	v, err := tb.topology.Context().Config.Get("metadata_propagation")
+	if err != nil {
+		if core.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	errInvalid := fmt.Errorf(`metadata_propagation must be "all", "none", `+
+		"or an array of strings: %v", v)
+	switch v.Type() {
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		switch strings.ToLower(s) {
+		case "all":
+			return nil, nil
+		case "none":
+			return &metadataPropagation{}, nil
+		}
+		return nil, errInvalid
+	case data.TypeArray:
+		a, _ := data.AsArray(v)
+		p := &metadataPropagation{
+			keys: make(map[string]bool, len(a)),
+		}
+		for _, k := range a {
+			s, err := data.AsString(k)
+			if err != nil {
+				return nil, errInvalid
+			}
+			p.keys[s] = true
+		}
+		return p, nil
+	}
+	return nil, errInvalid
+}
+
+// quarantinePolicy returns the quarantine policy of boxes created by BQL
+// statements. The policy is read from "quarantine_max_panics" and
+// "quarantine_window" in the config store of the topology. It returns nil
+// when "quarantine_max_panics" isn't set, so that a panic stops the box.
+func (tb *TopologyBuilder) quarantinePolicy() (*core.QuarantinePolicy, error) {
+	conf := tb.topology.Context().Config
+	n, err := conf.GetInt("quarantine_max_panics")
+	if err != nil {
+		if core.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("quarantine_max_panics must be an integer: %v", err)
+	}
+	p := &core.QuarantinePolicy{
+		MaxPanics: int(n),
+	}
+	if w, err := conf.GetDuration("quarantine_window"); err == nil {
+		p.Window = w
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("quarantine_window must be a duration: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// sinkWAL returns the WAL config of a sink created by a BQL statement. The
+// WAL is enabled when "sink_wal_dir" is set in the config store of the
+// topology, and the log of each sink is written to
+// "<sink_wal_dir>/<topology>/<sink>". "sink_wal_segment_size" and
+// "sink_wal_max_retry_interval" can optionally be set. It returns nil when
+// "sink_wal_dir" isn't set.
+func (tb *TopologyBuilder) sinkWAL(sinkName string) (*core.SinkWALConfig, error) {
+	conf := tb.topology.Context().Config
+	dir, err := conf.GetString("sink_wal_dir")
+	if err != nil {
+		if core.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("sink_wal_dir must be a string: %v", err)
+	}
+	c := &core.SinkWALConfig{
+		Dir: filepath.Join(dir, tb.topology.Name(), strings.ToLower(sinkName)),
+	}
+	if n, err := conf.GetInt("sink_wal_segment_size"); err == nil {
+		c.SegmentSize = n
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("sink_wal_segment_size must be an integer: %v", err)
+	}
+	if d, err := conf.GetDuration("sink_wal_max_retry_interval"); err == nil {
+		c.MaxRetryInterval = d
+	} else if !core.IsNotExist(err) {
+		return nil, fmt.Errorf("sink_wal_max_retry_interval must be a duration: %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// sinkConfigFromOrderBy converts an ORDER BY clause of a sink to a
+// SinkConfig. Without the clause, tuples are written in the order of their
+// arrival.
+func sinkConfigFromOrderBy(a parser.SinkOrderingAST) (*core.SinkConfig, error) {
+	c := &core.SinkConfig{}
+	switch a.Ordering {
+	case parser.UnspecifiedSinkOrdering, parser.ArrivalOrder:
+		if a.BufferSize != parser.UnspecifiedCapacity {
+			return nil, errors.New("BUFFER SIZE cannot be used with ORDER BY ARRIVAL")
+		}
+		return c, nil
+	case parser.TimestampOrder:
+		c.Ordering = core.TimestampOrder
+	case parser.RoundRobinOrder:
+		c.Ordering = core.RoundRobinOrder
+	}
+	if a.BufferSize != parser.UnspecifiedCapacity {
+		if a.BufferSize <= 0 || a.BufferSize > math.MaxInt32 {
+			return nil, fmt.Errorf("invalid buffer size: %v", a.BufferSize)
+		}
+		c.OrderingBufferSize = int(a.BufferSize)
+	}
+	return c, nil
+}
+
+// timestampExtractorFromTimestampBy converts a TIMESTAMP BY clause to a
+// TimestampExtractor. It returns nil when the clause isn't given so that
+// tuples keep the timestamp assigned when they were generated.
+func timestampExtractorFromTimestampBy(a parser.TimestampByAST) (*core.TimestampExtractor, error) {
+	if a.Field == "" {
+		return nil, nil
+	}
+	p, err := data.CompilePath(a.Field)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp field '%v': %v", a.Field, err)
+	}
+	return &core.TimestampExtractor{
+		Path:   p,
+		Format: a.Format,
+	}, nil
+}
+
+// createStreamRoutesStmt creates a box which evaluates the conditions of
+// all routes and a box for each route that only forwards the tuples
+// assigned to it. It returns the node of the former box.
+func (tb *TopologyBuilder) createStreamRoutesStmt(stmt *parser.CreateStreamRoutesStmt) (core.Node, error) {
+	input := string(stmt.Input)
+	outNames := map[string]bool{}
+	for _, r := range stmt.Routes {
+		name := strings.ToLower(string(r.Name))
+		if name == strings.ToLower(input) {
+			return nil, fmt.Errorf("a stream '%v' contains a selfloop", r.Name)
+		}
+		if outNames[name] {
+			return nil, fmt.Errorf("a stream '%v' is used in more than one route", r.Name)
+		}
+		outNames[name] = true
+	}
+
+	box, err := newRouteBox(stmt, tb.Reg)
+	if err != nil {
+		return nil, err
+	}
+	routerName := fmt.Sprintf("sensorbee_tmp_%v", topologyBuilderNextTemporaryID())
+	router, err := tb.topology.AddBox(routerName, box, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	removeNodes := true
+	var names []string
+	defer func() {
+		if !removeNodes {
+			return
+		}
+
+		for _, n := range names {
+			tb.topology.Remove(n)
+		}
+		tb.topology.Remove(routerName)
+	}()
+
+	if err := router.Input(input, nil); err != nil {
+		return nil, err
+	}
+	nodes := make([]core.BoxNode, 0, len(stmt.Routes))
+	for i, r := range stmt.Routes {
+		node, err := tb.topology.AddBox(string(r.Name), newRouteOutputBox(i), nil)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, string(r.Name))
+		if err := node.Input(routerName, nil); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	for _, node := range nodes {
+		node.StopOnDisconnect(core.Inbound)
+	}
+	// the router is removed when all routes are dropped
+	router.StopOnDisconnect(core.Inbound | core.Outbound)
+	router.RemoveOnStop()
+	removeNodes = false
+	return router, nil
+}
+
+// createSource creates a source with the creator. When the statement has an
+// INSTANCES clause, the creator is called for each instance and sources
+// created are merged into one source by core.NewParallelSource.
+func (tb *TopologyBuilder) createSource(creator SourceCreator, stmt *parser.CreateSourceStmt,
+	params data.Map) (core.Source, error) {
+	n := 1
+	if stmt.Instances != parser.UnspecifiedInstances {
+		if stmt.Instances < 1 {
+			return nil, fmt.Errorf("the number of instances must be positive: %v", stmt.Instances)
+		}
+		n = int(stmt.Instances)
+	}
+
+	ctx := tb.topology.Context()
+	srcs := make([]core.Source, 0, n)
+	for i := 0; i < n; i++ {
+		s, err := creator.CreateSource(ctx, &IOParams{
+			TypeName:     string(stmt.Type),
+			Name:         string(stmt.Name),
+			Instance:     i,
+			NumInstances: n,
+		}, params)
+		if err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, s)
+	}
+	if stmt.Instances == parser.UnspecifiedInstances {
+		return srcs[0], nil
+	}
+	return core.NewParallelSource(srcs)
+}
+
 // setUpUDSFStream creates a Source or a Box from a UDSF. When it creates a
 // Source, it will return the corresponding core.SourceNode of it. Otherwise,
 // it returns nil for core.SourceNode. It also returns the temporary name of
-// the UDSF node.
-func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *parser.AliasedStreamWindowAST) (core.SourceNode, string, error) {
+// the UDSF node. errMode and maxRetries are set to all inputs of the UDSF
+// node and the subsequent box, and queue is applied to the input of the
+// subsequent box. timeout is the process timeout of the UDSF
+// node when it runs as a Box. When the UDSFCreator implements
+// udf.UDSFPushdownCreator, filters and columns of stmt are pushed down to it.
+func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, stmt *parser.SelectStmt, rel *parser.AliasedStreamWindowAST,
+	queue queueConfig, errMode core.ErrorMode, maxRetries int, timeout time.Duration) (core.SourceNode, string, error) {
 	// Compute the values of the UDSF parameters (if there was
 	// an unusable parameter, as in `udsf(7, col)` this will fail).
 	// Note: it doesn't feel exactly right to do this kind of
@@ -525,16 +1283,31 @@ func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *pars
 	// on the other hand the parser should not evaluate expressions
 	// (and cannot import the execution package) or make too many
 	// semantical checks, so we leave this here for the moment.
+	udsfName := rel.Name
 	params := make([]data.Value, len(rel.Params))
-	for i, expr := range rel.Params {
-		p, err := execution.EvaluateFoldable(expr, tb.Reg)
-		if err != nil {
-			return nil, "", err
+	if rel.Type == parser.UnnestStream {
+		// UNNEST(s, path) is a shorthand of unnest_array('s', 'path')
+		path, ok := rel.Params[0].(parser.RowValue)
+		if !ok {
+			return nil, "", fmt.Errorf("the path of UNNEST must be a column: %v", rel.Params[0])
+		}
+		if path.Relation != "" && path.Relation != rel.Name {
+			return nil, "", fmt.Errorf("cannot refer to relation '%s' in UNNEST(%s, ...)",
+				path.Relation, rel.Name)
+		}
+		udsfName = "unnest_array"
+		params = []data.Value{data.String(rel.Name), data.String(path.Column)}
+	} else {
+		for i, expr := range rel.Params {
+			p, err := execution.EvaluateFoldable(expr, tb.Reg)
+			if err != nil {
+				return nil, "", err
+			}
+			params[i] = p
 		}
-		params[i] = p
 	}
 
-	udsfc, err := tb.UDSFCreators.Lookup(rel.Name, len(params))
+	udsfc, err := tb.UDSFCreators.Lookup(udsfName, len(params))
 	if err != nil {
 		return nil, "", err
 	}
@@ -550,6 +1323,14 @@ func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *pars
 				}
 			}
 		}()
+		if pc, ok := udsfc.(udf.UDSFPushdownCreator); ok {
+			pd := tb.udsfPushdown(stmt, rel)
+			f, err := pc.CreateUDSFWithPushdown(tb.topology.Context(), decl, pd, params...)
+			if err == nil {
+				tb.logUDSFPushdown(udsfName, pd)
+			}
+			return f, err
+		}
 		return udsfc.CreateUDSF(tb.topology.Context(), decl, params...)
 	}()
 	if err != nil {
@@ -558,40 +1339,9 @@ func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *pars
 
 	temporaryName := fmt.Sprintf("sensorbee_tmp_udsf_%v", topologyBuilderNextTemporaryID())
 	addInput := func() error {
-		alias := rel.Alias
-		if alias == "" {
-			alias = rel.Name
-		}
-		conf := &core.BoxInputConfig{
-			// As opposed to actual streams, for `udsf("s") AS a, udsf("s") AS b`,
-			// there will be *multiple* boxes and we will have one connection to
-			// udsf 1 (the one aliased to `a`) and udsf 2 (the one aliased to `b`).
-			// Therefore we need to have different InputNames for them. Note that
-			// we cannot just take the alias, as there would be the danger of
-			// overriding an input stream with that same name, as in
-			// `FROM x AS b, udsf("s") AS x`, and we should also use a string
-			// that does not possibly conflict with any input name.
-			// Note that `addTupleToBuffer` in defaultSelectExecutionPlan needs
-			// to use that same method.
-			InputName: fmt.Sprintf("%s/%s", rel.Name, alias),
-		}
-		// set capacity of input pipe
-		if rel.Capacity != parser.UnspecifiedCapacity {
-			if rel.Capacity > math.MaxInt32 {
-				return fmt.Errorf("specified buffer capacity %d is too large", rel.Capacity)
-			} else if rel.Capacity < 0 {
-				// the parser should not allow this to happen, actually
-				return fmt.Errorf("specified buffer capacity %d must not be negative", rel.Capacity)
-			}
-			conf.Capacity = int(rel.Capacity)
-		}
-		// set drop mode for box
-		if rel.Shedding == parser.DropOldest {
-			conf.DropMode = core.DropOldest
-		} else if rel.Shedding == parser.DropNewest {
-			conf.DropMode = core.DropLatest
-		} else if rel.Shedding == parser.Wait {
-			conf.DropMode = core.DropNone
+		conf, err := temporaryNodeInputConfig(rel, queue, errMode, maxRetries)
+		if err != nil {
+			return err
 		}
 		return subsequentBox.Input(temporaryName, conf)
 	}
@@ -612,8 +1362,14 @@ func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *pars
 		return sn, temporaryName, nil
 	}
 
+	quarantine, err := tb.quarantinePolicy()
+	if err != nil {
+		return nil, "", err
+	}
 	bn, err := tb.topology.AddBox(temporaryName, newUDSFBox(udsf), &core.BoxConfig{
-	// TODO: add information of the statement
+		// TODO: add information of the statement
+		ProcessTimeout: timeout,
+		Quarantine:     quarantine,
 	})
 	if err != nil {
 		return nil, "", err
@@ -627,9 +1383,11 @@ func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *pars
 				"specified buffer capacity %d must not be negative", config.Capacity)
 		}
 		bc := &core.BoxInputConfig{
-			InputName: config.InputName,
-			Capacity:  config.Capacity,
-			DropMode:  config.DropMode,
+			InputName:  config.InputName,
+			Capacity:   config.Capacity,
+			DropMode:   config.DropMode,
+			ErrorMode:  errMode,
+			MaxRetries: maxRetries,
 		}
 		if err := bn.Input(input, bc); err != nil {
 			return nil, "", err
@@ -643,18 +1401,51 @@ func (tb *TopologyBuilder) setUpUDSFStream(subsequentBox core.BoxNode, rel *pars
 	return nil, temporaryName, nil
 }
 
-func (tb *TopologyBuilder) mkParamsMap(params []parser.SourceSinkParamAST) data.Map {
+// temporaryNodeInputConfig returns the input config of the connection from a
+// temporary node created for a relation, such as a UDSF, to the box
+// processing the relation.
+func temporaryNodeInputConfig(rel *parser.AliasedStreamWindowAST, queue queueConfig,
+	errMode core.ErrorMode, maxRetries int) (*core.BoxInputConfig, error) {
+	alias := rel.Alias
+	if alias == "" {
+		alias = rel.Name
+	}
+	conf := &core.BoxInputConfig{
+		// As opposed to actual streams, for `udsf("s") AS a, udsf("s") AS b`,
+		// there will be *multiple* boxes and we will have one connection to
+		// udsf 1 (the one aliased to `a`) and udsf 2 (the one aliased to `b`).
+		// Therefore we need to have different InputNames for them. Note that
+		// we cannot just take the alias, as there would be the danger of
+		// overriding an input stream with that same name, as in
+		// `FROM x AS b, udsf("s") AS x`, and we should also use a string
+		// that does not possibly conflict with any input name.
+		// Note that `addTupleToBuffer` in defaultSelectExecutionPlan needs
+		// to use that same method.
+		InputName:  fmt.Sprintf("%s/%s", rel.Name, alias),
+		ErrorMode:  errMode,
+		MaxRetries: maxRetries,
+	}
+	if err := queue.boxInputConfig(rel, conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func (tb *TopologyBuilder) mkParamsMap(params []parser.SourceSinkParamAST) (data.Map, error) {
 	paramsMap := make(data.Map, len(params))
 	for _, kv := range params {
 		paramsMap[string(kv.Key)] = kv.Value
 	}
-	return paramsMap
+	return tb.expandParamEnv(paramsMap)
 }
 
 type chanSink struct {
 	m      sync.RWMutex
 	ch     chan *core.Tuple
 	closed bool
+
+	// onClose is called when the sink is closed if it isn't nil.
+	onClose func()
 }
 
 func newChanSink() (*chanSink, <-chan *core.Tuple) {
@@ -690,6 +1481,9 @@ func (s *chanSink) Close(ctx *core.Context) error {
 	}
 	s.closed = true
 	close(s.ch)
+	if s.onClose != nil {
+		s.onClose()
+	}
 	return nil
 }
 
@@ -708,13 +1502,65 @@ func (tb *TopologyBuilder) AddSelectStmt(stmt *parser.SelectStmt) (core.SinkNode
 // chan receiving tuples from the Sink, and an error if happens. The caller must
 // stop the Sink node once it get unnecessary.
 func (tb *TopologyBuilder) AddSelectUnionStmt(stmts *parser.SelectUnionStmt) (core.SinkNode, <-chan *core.Tuple, error) {
+	release, err := tb.admit(maxConcurrentSelectsConfig, "streaming SELECT statements")
+	if err != nil {
+		return nil, nil, err
+	}
 	sink, ch := newChanSink()
+	sink.onClose = release
 	tmpUnionNodeName := fmt.Sprintf("sensorbee_tmp_select_sink_%v", topologyBuilderNextTemporaryID())
-	sn, err := tb.topology.AddSink(tmpUnionNodeName, sink, nil)
+	sn, err := tb.addSelectUnionStmt(stmts, tmpUnionNodeName, sink, nil)
 	if err != nil {
-		sink.Close(tb.topology.Context())
+		release()
 		return nil, nil, err
 	}
+	return sn, ch, nil
+}
+
+// DeliverSelectUnionStmt creates nodes handling a SELECT ... UNION ALL
+// statement in the topology and delivers its results to a new sink created by
+// the sink creator registered as typeName. Unlike AddSelectUnionStmt, the sink
+// remains in the topology until it's dropped by a DROP SINK statement, so
+// long-running SELECT statements don't depend on clients. A name is generated
+// when name is empty.
+func (tb *TopologyBuilder) DeliverSelectUnionStmt(stmts *parser.SelectUnionStmt, name, typeName string,
+	params data.Map) (core.SinkNode, error) {
+	if name == "" {
+		name = fmt.Sprintf("sensorbee_delivery_%v", topologyBuilderNextTemporaryID())
+	} else if err := core.ValidateSymbol(name); err != nil {
+		return nil, err
+	}
+
+	creator, err := tb.SinkCreators.Lookup(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if params, err = validateCreatorParams(creator, typeName, params); err != nil {
+		return nil, err
+	}
+	config := &core.SinkConfig{}
+	if config.WAL, err = tb.sinkWAL(name); err != nil {
+		return nil, err
+	}
+	sink, err := creator.CreateSink(tb.topology.Context(), &IOParams{
+		TypeName: typeName,
+		Name:     name,
+	}, params)
+	if err != nil {
+		return nil, err
+	}
+	return tb.addSelectUnionStmt(stmts, name, sink, config)
+}
+
+// addSelectUnionStmt adds the sink to the topology and connects nodes
+// handling the SELECT statements to it. The sink is closed on failure.
+func (tb *TopologyBuilder) addSelectUnionStmt(stmts *parser.SelectUnionStmt, tmpUnionNodeName string,
+	sink core.Sink, config *core.SinkConfig) (core.SinkNode, error) {
+	sn, err := tb.topology.AddSink(tmpUnionNodeName, sink, config)
+	if err != nil {
+		sink.Close(tb.topology.Context())
+		return nil, err
+	}
 
 	names := make([]string, 0, len(stmts.Selects))
 	for _, stmt := range stmts.Selects {
@@ -738,20 +1584,32 @@ func (tb *TopologyBuilder) AddSelectUnionStmt(stmts *parser.SelectUnionStmt) (co
 			//  + a connection (random_string -> sink)
 			tmpName := fmt.Sprintf("sensorbee_tmp_%v", topologyBuilderNextTemporaryID())
 			tmpStmt := parser.CreateStreamAsSelectStmt{
+				parser.UnspecifiedKeyword,
 				parser.StreamIdentifier(tmpName),
+				parser.SchemaAST{},
 				parser.SelectStmt{
 					stmt.EmitterAST,
 					stmt.ProjectionsAST,
 					stmt.WindowedFromAST,
+					stmt.InputSamplingAST,
 					stmt.FilterAST,
 					stmt.GroupingAST,
 					stmt.HavingAST,
+					stmt.EmitWhenAST,
 				},
+				parser.TimestampByAST{},
+				parser.OnErrorAST{},
+				parser.TimeoutAST{},
+				parser.SourceSinkSpecsAST{},
 			}
-			box, err := tb.AddStmt(tmpStmt)
+			// results of the statement leave the topology
+			box, err := tb.createStreamAsSelectStmt(&tmpStmt, nil, true)
 			if err != nil {
 				return nil, err
 			}
+			tb.stmtMutex.Lock()
+			tb.statements[tmpName] = tmpStmt.String()
+			tb.stmtMutex.Unlock()
 			box.(core.BoxNode).RemoveOnStop()
 
 			// now connect the sink to that box
@@ -782,7 +1640,7 @@ func (tb *TopologyBuilder) AddSelectUnionStmt(stmts *parser.SelectUnionStmt) (co
 					WithField("node_name", tmpUnionNodeName).Error("Cannot stop the temporary sink")
 			}
 			tb.topology.Remove(tmpUnionNodeName)
-			return nil, nil, err
+			return nil, err
 		}
 		names = append(names, node.Name())
 	}
@@ -804,7 +1662,7 @@ func (tb *TopologyBuilder) AddSelectUnionStmt(stmts *parser.SelectUnionStmt) (co
 		sn.RemoveOnStop()
 		sn.StopOnDisconnect()
 	}()
-	return sn, ch, nil
+	return sn, nil
 }
 
 // RunEvalStmt evaluates the expression contained in the given EvalStmt
@@ -873,14 +1731,32 @@ func (tb *TopologyBuilder) loadState(typeName, name, tag string, params data.Map
 		return core.IsNotExist(err), err
 	}
 	defer r.Close()
+	return false, tb.LoadState(typeName, name, r, params)
+}
+
+// LoadStateAs loads a state in the same way as LoadState on behalf of the
+// owner. It fails with ProtectedError when the state is protected by another
+// owner.
+func (tb *TopologyBuilder) LoadStateAs(owner, typeName, name string, r io.Reader, params data.Map) error {
+	if err := tb.checkOwnershipOf(owner, "state", name); err != nil {
+		return err
+	}
+	return tb.LoadState(typeName, name, r, params)
+}
 
+// LoadState loads a state of the given type from a reader which has data
+// written by the state's Save method. When the topology already has the
+// state and it provides Load method, Load method will be used to overwrite
+// it. Otherwise, a new state is created from the data and replaces the
+// current one.
+func (tb *TopologyBuilder) LoadState(typeName, name string, r io.Reader, params data.Map) error {
 	c, err := tb.UDSCreators.Lookup(typeName)
 	if err != nil {
-		return false, err
+		return err
 	}
 	loader, ok := c.(udf.UDSLoader)
 	if !ok {
-		return false, fmt.Errorf("the state '%v-%v' cannot be loaded", name, tag)
+		return fmt.Errorf("the state '%v' cannot be loaded", name)
 	}
 
 	// If the state is loaded and it provides Load method, Load method will be
@@ -890,22 +1766,22 @@ func (tb *TopologyBuilder) loadState(typeName, name, tag string, params data.Map
 	if err != nil {
 		// TODO: check if the error is "not found". Return only on other errors.
 	} else if t, err := reg.Type(name); err != nil {
-		return false, err
+		return err
 	} else if t != typeName {
-		return false, fmt.Errorf("type name doesn't much to the current state's type")
+		return fmt.Errorf("type name doesn't much to the current state's type")
 	}
 
 	if l, ok := s.(core.LoadableSharedState); ok {
-		return false, l.Load(tb.topology.Context(), r, params)
+		return l.Load(tb.topology.Context(), r, params)
 	}
 
 	newState, err := loader.LoadState(tb.topology.Context(), r, params)
 	if err != nil {
-		return false, err
+		return err
 	}
 	prev, err := reg.Replace(name, typeName, newState)
 	if err != nil {
-		return false, err
+		return err
 	}
 	if prev != nil {
 		if err := prev.Terminate(tb.topology.Context()); err != nil {
@@ -913,5 +1789,5 @@ func (tb *TopologyBuilder) loadState(typeName, name, tag string, params data.Map
 				Error("Cannot terminate the previous instance of the loaded state")
 		}
 	}
-	return false, nil
+	return nil
 }
@@ -2,7 +2,10 @@ package bql
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -417,6 +420,170 @@ func TestCreateStreamAsSelectUnionStmt(t *testing.T) {
 	})
 }
 
+func TestCreateStreamAsSelectUnnest(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a stream having arrays", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=3;
+			CREATE STREAM arr AS SELECT RSTREAM int, [int, int * 10] AS a FROM s [RANGE 1 TUPLES];`), ShouldBeNil)
+
+		Convey("When running CREATE STREAM AS SELECT with UNNEST", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM int, a AS elem
+				FROM UNNEST(arr, arr:a) [RANGE 1 TUPLES]`)
+			So(err, ShouldBeNil)
+
+			Convey("Then the stream should emit a tuple for each element", func() {
+				So(addBQLToTopology(tb, `CREATE SINK snk TYPE collector;
+					INSERT INTO snk FROM t; RESUME SOURCE s;`), ShouldBeNil)
+				sn, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				si := sn.Sink().(*tupleCollectorSink)
+				si.Wait(6)
+
+				So(si.len(), ShouldEqual, 6)
+				var res []data.Map
+				si.forEachTuple(func(t *core.Tuple) {
+					res = append(res, t.Data)
+				})
+				So(res, ShouldResemble, []data.Map{
+					{"int": data.Int(1), "elem": data.Int(1)},
+					{"int": data.Int(1), "elem": data.Int(10)},
+					{"int": data.Int(2), "elem": data.Int(2)},
+					{"int": data.Int(2), "elem": data.Int(20)},
+					{"int": data.Int(3), "elem": data.Int(3)},
+					{"int": data.Int(3), "elem": data.Int(30)},
+				})
+			})
+		})
+
+		Convey("When running CREATE STREAM AS SELECT with UNNEST referring to another relation", func() {
+			prevNodes := len(tb.topology.Nodes())
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM *
+				FROM UNNEST(arr, s:a) [RANGE 1 TUPLES]`)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "cannot refer to relation 's'")
+				So(len(tb.topology.Nodes()), ShouldEqual, prevNodes)
+			})
+		})
+	})
+}
+
+func TestCreateStreamRoutesStmt(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a source", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=6`), ShouldBeNil)
+
+		collect := func(names ...string) []*tupleCollectorSink {
+			sinks := make([]*tupleCollectorSink, len(names))
+			for i, name := range names {
+				So(addBQLToTopology(tb, fmt.Sprintf(`CREATE SINK snk_%v TYPE collector;
+					INSERT INTO snk_%v FROM %v;`, name, name, name)), ShouldBeNil)
+				sn, err := dt.Sink("snk_" + name)
+				So(err, ShouldBeNil)
+				sinks[i] = sn.Sink().(*tupleCollectorSink)
+			}
+			return sinks
+		}
+		ints := func(si *tupleCollectorSink) []int64 {
+			var res []int64
+			si.forEachTuple(func(t *core.Tuple) {
+				i, _ := data.AsInt(t.Data["int"])
+				res = append(res, i)
+			})
+			return res
+		}
+
+		Convey("When running CREATE STREAM ROUTES with conditions", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM ROUTES FROM s
+				WHEN int % 2 = 0 INTO even WHEN s:int > 4 INTO big ELSE INTO rest`)
+			So(err, ShouldBeNil)
+			sinks := collect("even", "big", "rest")
+			So(addBQLToTopology(tb, `RESUME SOURCE s`), ShouldBeNil)
+
+			Convey("Then each stream should receive the tuples matching its condition", func() {
+				sinks[0].Wait(3)
+				sinks[1].Wait(2)
+				sinks[2].Wait(2)
+				So(ints(sinks[0]), ShouldResemble, []int64{2, 4, 6})
+				So(ints(sinks[1]), ShouldResemble, []int64{5, 6})
+				So(ints(sinks[2]), ShouldResemble, []int64{1, 3})
+			})
+
+			Convey("And the input data should not be wrapped", func() {
+				sinks[2].Wait(1)
+				So(sinks[2].get(0).Data, ShouldResemble, data.Map{"int": data.Int(1)})
+			})
+		})
+
+		Convey("When running CREATE STREAM ROUTES with a partition key", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM ROUTES FROM s PARTITIONED BY int INTO p0, p1, p2`)
+			So(err, ShouldBeNil)
+			sinks := collect("p0", "p1", "p2")
+			So(addBQLToTopology(tb, `RESUME SOURCE s`), ShouldBeNil)
+
+			Convey("Then every tuple should be emitted by exactly one stream", func() {
+				waitForExpectedCondition(func() bool {
+					return sinks[0].len()+sinks[1].len()+sinks[2].len() == 6
+				})
+				So(sinks[0].len()+sinks[1].len()+sinks[2].len(), ShouldEqual, 6)
+			})
+		})
+
+		Convey("When running CREATE STREAM ROUTES with an existing output stream name", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM x AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]`), ShouldBeNil)
+			prevNodes := len(tb.topology.Nodes())
+			err := addBQLToTopology(tb, `CREATE STREAM ROUTES FROM s WHEN int = 1 INTO a WHEN int = 2 INTO x`)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "already")
+				So(len(tb.topology.Nodes()), ShouldEqual, prevNodes)
+			})
+		})
+
+		Convey("When running CREATE STREAM ROUTES on a non-existing stream", func() {
+			prevNodes := len(tb.topology.Nodes())
+			err := addBQLToTopology(tb, `CREATE STREAM ROUTES FROM bar WHEN int = 1 INTO a`)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "was not found")
+				So(len(tb.topology.Nodes()), ShouldEqual, prevNodes)
+			})
+		})
+
+		Convey("When running CREATE STREAM ROUTES with invalid routes", func() {
+			prevNodes := len(tb.topology.Nodes())
+			for stmt, msg := range map[string]string{
+				`CREATE STREAM ROUTES FROM s WHEN int = 1 INTO s`:                  "selfloop",
+				`CREATE STREAM ROUTES FROM s WHEN int = 1 INTO a ELSE INTO a`:      "more than one route",
+				`CREATE STREAM ROUTES FROM s WHEN t:int = 1 INTO a`:                "cannot refer to relation 't'",
+				`CREATE STREAM ROUTES FROM s WHEN count(int) = 1 INTO a`:           "aggregate",
+				`CREATE STREAM ROUTES FROM s PARTITIONED BY undefined(int) INTO a`: "undefined",
+			} {
+				err := addBQLToTopology(tb, stmt)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, msg)
+			}
+
+			Convey("Then no node should be created", func() {
+				So(len(tb.topology.Nodes()), ShouldEqual, prevNodes)
+			})
+		})
+	})
+}
+
 func TestCreateSinkStmt(t *testing.T) {
 	Convey("Given a BQL TopologyBuilder", t, func() {
 		dt := newTestTopology()
@@ -512,6 +679,526 @@ func TestInsertIntoFromStmt(t *testing.T) {
 	})
 }
 
+func TestInsertIntoSelectFromStmt(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a source and a sink", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;
+			CREATE SINK foo TYPE collector;`), ShouldBeNil)
+
+		collected := func(n int) []data.Map {
+			sn, err := dt.Sink("foo")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			si.Wait(n)
+			var res []data.Map
+			si.forEachTuple(func(t *core.Tuple) {
+				res = append(res, t.Data)
+			})
+			return res
+		}
+
+		Convey("When inserting projected tuples with a filter", func() {
+			So(addBQLToTopology(tb, `INSERT INTO foo SELECT int * 10 AS x, int AS y
+				FROM s WHERE int % 2 = 0; RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then the sink should receive transformed tuples which passed the filter", func() {
+				So(collected(2), ShouldResemble, []data.Map{
+					{"x": data.Int(20), "y": data.Int(2)},
+					{"x": data.Int(40), "y": data.Int(4)},
+				})
+			})
+
+			Convey("Then the topology shouldn't have any additional node", func() {
+				So(len(dt.Boxes()), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When inserting tuples only with a filter", func() {
+			So(addBQLToTopology(tb, `INSERT INTO foo FROM s WHERE int > 3; RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then the sink should receive tuples as they are", func() {
+				So(collected(1), ShouldResemble, []data.Map{{"int": data.Int(4)}})
+			})
+		})
+
+		Convey("When inserting tuples with an aggregate function", func() {
+			err := addBQLToTopology(tb, `INSERT INTO foo SELECT count(*) FROM s`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When inserting tuples with a reference to another stream", func() {
+			err := addBQLToTopology(tb, `INSERT INTO foo SELECT t:int FROM s`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestTimestampByClause(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		timestamps := func(sink string, n int) []time.Time {
+			sn, err := dt.Sink(sink)
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			si.Wait(n)
+			var res []time.Time
+			si.forEachTuple(func(t *core.Tuple) {
+				res = append(res, t.Timestamp)
+			})
+			return res
+		}
+
+		Convey("When creating a source with TIMESTAMP BY", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=3 TIMESTAMP BY int;
+				CREATE SINK snk TYPE collector; INSERT INTO snk FROM s; RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then tuples should have the timestamp of the field", func() {
+				So(timestamps("snk", 3), ShouldResemble, []time.Time{
+					time.Unix(1, 0), time.Unix(2, 0), time.Unix(3, 0),
+				})
+			})
+		})
+
+		Convey("When creating a stream with TIMESTAMP BY and FORMAT", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=3;
+				CREATE STREAM t AS SELECT RSTREAM int, int * 1000 AS ms FROM s [RANGE 1 TUPLES]
+					TIMESTAMP BY ms FORMAT "unix_milli";
+				CREATE SINK snk TYPE collector; INSERT INTO snk FROM t; RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then tuples should have the timestamp of the field", func() {
+				So(timestamps("snk", 3), ShouldResemble, []time.Time{
+					time.Unix(1, 0), time.Unix(2, 0), time.Unix(3, 0),
+				})
+			})
+		})
+
+		Convey("When creating a stream with TIMESTAMP BY a missing field", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=3;
+				CREATE STREAM t AS SELECT RSTREAM int FROM s [RANGE 1 TUPLES]
+					WHERE int != 2 TIMESTAMP BY int;
+				CREATE STREAM u AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES] TIMESTAMP BY ts;
+				CREATE SINK snk TYPE collector; INSERT INTO snk FROM t; INSERT INTO snk FROM u;
+				RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then tuples without the field should be dropped", func() {
+				So(timestamps("snk", 2), ShouldResemble, []time.Time{
+					time.Unix(1, 0), time.Unix(3, 0),
+				})
+			})
+		})
+	})
+}
+
+func TestInstancesClause(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a source type recording IOParams", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+
+		var ioParams []IOParams
+		So(tb.SourceCreators.Register("recording", SourceCreatorFunc(
+			func(ctx *core.Context, ioParams_ *IOParams, params data.Map) (core.Source, error) {
+				ioParams = append(ioParams, *ioParams_)
+				return createDummySource(ctx, ioParams_, params)
+			})), ShouldBeNil)
+
+		Convey("When creating a source with INSTANCES", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE recording INSTANCES 3 WITH num=2;
+				CREATE SINK snk TYPE collector; INSERT INTO snk FROM s; RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then the creator should be called for each instance", func() {
+				So(ioParams, ShouldResemble, []IOParams{
+					{"recording", "s", 0, 3},
+					{"recording", "s", 1, 3},
+					{"recording", "s", 2, 3},
+				})
+			})
+
+			Convey("Then the sink should receive tuples from all instances", func() {
+				sn, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				si := sn.Sink().(*tupleCollectorSink)
+				si.Wait(6)
+				So(si.len(), ShouldEqual, 6)
+			})
+
+			Convey("Then the source should report the status of each instance", func() {
+				sn, err := dt.Source("s")
+				So(err, ShouldBeNil)
+				st := sn.Status()["source"].(data.Map)
+				So(st["num_instances"], ShouldEqual, data.Int(3))
+				So(len(st["instances"].(data.Array)), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When creating a source without INSTANCES", func() {
+			So(addBQLToTopology(tb, `CREATE SOURCE s TYPE recording`), ShouldBeNil)
+
+			Convey("Then the creator should be called once", func() {
+				So(ioParams, ShouldResemble, []IOParams{{"recording", "s", 0, 1}})
+			})
+		})
+
+		Convey("When creating a source with no instance", func() {
+			err := addBQLToTopology(tb, `CREATE SOURCE s TYPE recording INSTANCES 0`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				_, err := dt.Source("s")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestSinkOrderByClause(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with two sources", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s1 TYPE dummy WITH num=3;
+			CREATE PAUSED SOURCE s2 TYPE dummy WITH num=3;`), ShouldBeNil)
+
+		Convey("When creating a sink with ORDER BY TIMESTAMP", func() {
+			So(addBQLToTopology(tb, `CREATE SINK snk TYPE collector ORDER BY TIMESTAMP BUFFER SIZE 10;
+				INSERT INTO snk FROM s1; INSERT INTO snk FROM s2;
+				RESUME SOURCE s1; RESUME SOURCE s2;`), ShouldBeNil)
+			sn, err := dt.Sink("snk")
+			So(err, ShouldBeNil)
+
+			Convey("Then the sink should receive all tuples after sources are dropped", func() {
+				// The last tuples are buffered until the other source is
+				// disconnected because the dummy source doesn't stop.
+				si := sn.Sink().(*tupleCollectorSink)
+				si.Wait(3)
+				So(addBQLToTopology(tb, `DROP SOURCE s1; DROP SOURCE s2;`), ShouldBeNil)
+				si.Wait(6)
+				So(si.len(), ShouldEqual, 6)
+			})
+
+			Convey("Then the sink should report the ordering policy", func() {
+				st := sn.Status()["ordering"].(data.Map)
+				So(st["policy"], ShouldEqual, data.String("timestamp"))
+				So(st["buffer_size"], ShouldEqual, data.Int(10))
+			})
+		})
+
+		Convey("When creating a sink without ORDER BY", func() {
+			So(addBQLToTopology(tb, `CREATE SINK snk TYPE collector`), ShouldBeNil)
+
+			Convey("Then the sink shouldn't have an ordering policy", func() {
+				sn, err := dt.Sink("snk")
+				So(err, ShouldBeNil)
+				So(sn.Status(), ShouldNotContainKey, "ordering")
+			})
+		})
+
+		Convey("When creating a sink with an invalid buffer size", func() {
+			err := addBQLToTopology(tb, `CREATE SINK snk TYPE collector ORDER BY ROUND ROBIN BUFFER SIZE 0`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				_, err := dt.Sink("snk")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a sink with a buffer size for arrival order", func() {
+			err := addBQLToTopology(tb, `CREATE SINK snk TYPE collector ORDER BY ARRIVAL BUFFER SIZE 10`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// blockOnTwoCanceled is closed when block_on_two is canceled.
+var blockOnTwoCanceled = make(chan struct{}, 1)
+
+func init() {
+	udf.MustRegisterGlobalUDF("block_on_two", udf.MustConvertGeneric(func(ctx *core.Context, i int) (int, error) {
+		if i != 2 {
+			return i, nil
+		}
+		<-ctx.Done()
+		blockOnTwoCanceled <- struct{}{}
+		return 0, ctx.Err()
+	}))
+}
+
+func TestTimeoutClause(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a source and a sink", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4;
+			CREATE SINK foo TYPE collector;`), ShouldBeNil)
+
+		Convey("When creating a stream calling a slow UDF with TIMEOUT", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS
+				SELECT RSTREAM block_on_two(int) AS x FROM s [RANGE 1 TUPLES]
+				ON ERROR DROP TIMEOUT 20 MILLISECONDS;
+				INSERT INTO foo FROM t;
+				RESUME SOURCE s;`), ShouldBeNil)
+			sn, err := dt.Sink("foo")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+
+			Convey("Then the UDF should be canceled and the tuple should be dropped", func() {
+				<-blockOnTwoCanceled
+				si.Wait(3)
+				So(si.len(), ShouldEqual, 3)
+				si.forEachTuple(func(t *core.Tuple) {
+					So(t.Data["x"], ShouldNotEqual, data.Int(2))
+				})
+
+				b, err := dt.Box("t")
+				So(err, ShouldBeNil)
+				v, err := b.Status().Get(data.MustCompilePath("input_stats.num_timeouts"))
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Int(1))
+			})
+		})
+
+		Convey("When creating a stream with a zero timeout", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES] TIMEOUT 0 SECONDS`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func init() {
+	udf.MustRegisterGlobalUDF("panic_under_three", udf.MustConvertGeneric(func(i int) int {
+		if i < 3 {
+			panic("less than three")
+		}
+		return i
+	}))
+}
+
+func TestQuarantine(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a quarantine policy", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `SET CONFIG WITH quarantine_max_panics=2;
+			CREATE PAUSED SOURCE s TYPE dummy WITH num=4;
+			CREATE SINK foo TYPE collector;`), ShouldBeNil)
+
+		Convey("When a UDF in a stream panics too many times", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS
+				SELECT RSTREAM panic_under_three(int) AS x FROM s [RANGE 1 TUPLES]
+				ON ERROR DROP;
+				INSERT INTO foo FROM t;
+				RESUME SOURCE s;`), ShouldBeNil)
+			sn, err := dt.Sink("foo")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			b, err := dt.Box("t")
+			So(err, ShouldBeNil)
+			quarantine := func(field string) data.Value {
+				v, _ := b.Status().Get(data.MustCompilePath("quarantine." + field))
+				return v
+			}
+			for i := 0; i < 1000 && quarantine("num_dropped") != data.Int(2); i++ {
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then the stream should be in quarantine", func() {
+				So(quarantine("quarantined"), ShouldEqual, data.True)
+				So(quarantine("num_panics"), ShouldEqual, data.Int(2))
+				So(quarantine("num_dropped"), ShouldEqual, data.Int(2))
+				So(b.State().Get(), ShouldEqual, core.TSRunning)
+				So(si.len(), ShouldEqual, 0)
+			})
+
+			Convey("Then RESUME STREAM should release it", func() {
+				So(addBQLToTopology(tb, `RESUME STREAM t`), ShouldBeNil)
+				So(quarantine("quarantined"), ShouldEqual, data.False)
+
+				Convey("And RESUME STREAM should fail when it isn't in quarantine", func() {
+					So(addBQLToTopology(tb, `RESUME STREAM t`), ShouldNotBeNil)
+				})
+			})
+		})
+	})
+
+	Convey("Given a BQL TopologyBuilder with an invalid quarantine policy", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `SET CONFIG WITH quarantine_max_panics=0;
+			CREATE PAUSED SOURCE s TYPE dummy;`), ShouldBeNil)
+
+		Convey("When creating a stream", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestMetadataPropagation(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy;`), ShouldBeNil)
+
+		process := func() *core.Tuple {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM meta("a") AS a FROM s [RANGE 1 TUPLES];`), ShouldBeNil)
+			bn, err := dt.Box("t")
+			So(err, ShouldBeNil)
+			tup := core.NewTuple(data.Map{})
+			tup.InputName = "s"
+			tup.Metadata = data.Map{"a": data.Int(1), "b": data.Int(2)}
+			w := &tupleCollectorSink{}
+			So(bn.Box().Process(dt.Context(), tup, w), ShouldBeNil)
+			So(len(w.Tuples), ShouldEqual, 1)
+			So(w.Tuples[0].Data, ShouldResemble, data.Map{"a": data.Int(1)})
+			return w.Tuples[0]
+		}
+
+		Convey("When metadata_propagation isn't set", func() {
+			out := process()
+
+			Convey("Then all metadata should be propagated", func() {
+				So(out.Metadata, ShouldResemble, data.Map{"a": data.Int(1), "b": data.Int(2)})
+			})
+		})
+
+		Convey("When metadata_propagation is none", func() {
+			So(addBQLToTopology(tb, `SET CONFIG WITH metadata_propagation="none";`), ShouldBeNil)
+			out := process()
+
+			Convey("Then no metadata should be propagated", func() {
+				So(out.Metadata, ShouldBeNil)
+			})
+		})
+
+		Convey("When metadata_propagation has keys", func() {
+			So(addBQLToTopology(tb, `SET CONFIG WITH metadata_propagation=["b", "c"];`), ShouldBeNil)
+			out := process()
+
+			Convey("Then only those keys should be propagated", func() {
+				So(out.Metadata, ShouldResemble, data.Map{"b": data.Int(2)})
+			})
+		})
+
+		Convey("When metadata_propagation is invalid", func() {
+			So(addBQLToTopology(tb, `SET CONFIG WITH metadata_propagation="some";`), ShouldBeNil)
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES];`)
+
+			Convey("Then creating a stream should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestOnErrorClause(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a source and a sink", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		err = addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy`)
+		So(err, ShouldBeNil)
+		err = addBQLToTopology(tb, `CREATE SINK foo TYPE collector`)
+		So(err, ShouldBeNil)
+
+		errorMode := func(st data.Map, input string) data.Value {
+			v, err := st.Get(data.MustCompilePath("input_stats.inputs." + input + ".error_mode"))
+			So(err, ShouldBeNil)
+			return v
+		}
+
+		Convey("When creating a stream without ON ERROR", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES]`)
+			So(err, ShouldBeNil)
+
+			Convey("Then the stream should report errors", func() {
+				b, err := dt.Box("t")
+				So(err, ShouldBeNil)
+				So(errorMode(b.Status(), "s"), ShouldEqual, data.String("report"))
+			})
+		})
+
+		Convey("When creating a stream with ON ERROR STOP", func() {
+			err := addBQLToTopology(tb, `CREATE STREAM t AS SELECT RSTREAM * FROM s [RANGE 1 TUPLES] ON ERROR STOP`)
+			So(err, ShouldBeNil)
+
+			Convey("Then the stream should stop on errors", func() {
+				b, err := dt.Box("t")
+				So(err, ShouldBeNil)
+				So(errorMode(b.Status(), "s"), ShouldEqual, data.String("stop"))
+			})
+		})
+
+		Convey("When running INSERT INTO with ON ERROR RETRY", func() {
+			err := addBQLToTopology(tb, `INSERT INTO foo FROM s ON ERROR RETRY 2 TIMES`)
+			So(err, ShouldBeNil)
+
+			Convey("Then the sink should retry on errors", func() {
+				s, err := dt.Sink("foo")
+				So(err, ShouldBeNil)
+				So(errorMode(s.Status(), "s"), ShouldEqual, data.String("retry"))
+			})
+		})
+
+		Convey("When running INSERT INTO with too many retries", func() {
+			err := addBQLToTopology(tb, `INSERT INTO foo FROM s ON ERROR RETRY 4294967296 TIMES`)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
 func TestMultipleStatements(t *testing.T) {
 	Convey("Given an empty BQL TopologyBuilder", t, func() {
 		dt := newTestTopology()
@@ -1008,6 +1695,85 @@ func TestSelectUnionStmt(t *testing.T) {
 	})
 }
 
+func TestDeliverSelectUnionStmt(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a source", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=4, resumable=false;`), ShouldBeNil)
+		bp := parser.New()
+		istmt, _, err := bp.ParseStmt(`SELECT ISTREAM * FROM s [RANGE 1 TUPLES] WHERE int%2=0
+			UNION ALL SELECT ISTREAM * FROM s [RANGE 1 TUPLES] WHERE int%2=1`)
+		So(err, ShouldBeNil)
+		stmt := istmt.(parser.SelectUnionStmt)
+
+		Convey("When delivering results of a SELECT stmt to a sink", func() {
+			sn, err := tb.DeliverSelectUnionStmt(&stmt, "", "collector", data.Map{})
+			So(err, ShouldBeNil)
+			So(addBQLToTopology(tb, `RESUME SOURCE s;`), ShouldBeNil)
+
+			Convey("Then the sink should receive all tuples", func() {
+				si := sn.Sink().(*tupleCollectorSink)
+				si.Wait(4)
+				So(si.len(), ShouldEqual, 4)
+			})
+
+			Convey("Then the sink should have a generated name", func() {
+				So(sn.Name(), ShouldStartWith, "sensorbee_delivery_")
+			})
+
+			Convey("And dropping the sink", func() {
+				numNodes := len(tb.topology.Nodes())
+				So(addBQLToTopology(tb, fmt.Sprintf(`DROP SINK %v;`, sn.Name())), ShouldBeNil)
+
+				Convey("Then nodes created for the stmt should be removed", func() {
+					waitForExpectedCondition(func() bool {
+						return len(tb.topology.Nodes()) == numNodes-3
+					})
+					So(len(tb.topology.Nodes()), ShouldEqual, numNodes-3)
+				})
+			})
+		})
+
+		Convey("When delivering results to a sink with a name", func() {
+			sn, err := tb.DeliverSelectUnionStmt(&stmt, "results", "collector", data.Map{})
+			So(err, ShouldBeNil)
+
+			Convey("Then the sink should have the name", func() {
+				So(sn.Name(), ShouldEqual, "results")
+			})
+
+			Convey("Then another sink cannot have the same name", func() {
+				_, err := tb.DeliverSelectUnionStmt(&stmt, "results", "collector", data.Map{})
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When delivering results to a sink which cannot be created", func() {
+			numNodes := len(tb.topology.Nodes())
+			cases := []struct {
+				title, name, typeName string
+				params                data.Map
+			}{
+				{"an unknown type", "", "no_such_sink", data.Map{}},
+				{"an invalid parameter", "", "collector", data.Map{"a": data.Int(1)}},
+				{"an invalid name", "in valid", "collector", data.Map{}},
+			}
+			for _, c := range cases {
+				c := c
+				Convey("Then it should fail with "+c.title, func() {
+					_, err := tb.DeliverSelectUnionStmt(&stmt, c.name, c.typeName, c.params)
+					So(err, ShouldNotBeNil)
+					So(len(tb.topology.Nodes()), ShouldEqual, numNodes)
+				})
+			}
+		})
+	})
+}
+
 func TestEvalStmt(t *testing.T) {
 	Convey("Given a BQL TopologyBuilder", t, func() {
 		dt := newTestTopology()
@@ -1116,6 +1882,39 @@ func TestEvalStmt(t *testing.T) {
 	})
 }
 
+func TestSetConfigStmt(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		conf := dt.Context().Config
+
+		Convey("When setting config parameters", func() {
+			So(addBQLToTopology(tb, `SET CONFIG WITH endpoint="localhost:1234", retries=3`), ShouldBeNil)
+
+			Convey("Then the context should have them", func() {
+				e, err := conf.GetString("endpoint")
+				So(err, ShouldBeNil)
+				So(e, ShouldEqual, "localhost:1234")
+				r, err := conf.GetInt("retries")
+				So(err, ShouldBeNil)
+				So(r, ShouldEqual, 3)
+			})
+
+			Convey("Then setting them again should overwrite only given parameters", func() {
+				So(addBQLToTopology(tb, `SET CONFIG WITH retries=5`), ShouldBeNil)
+				So(conf.ToMap(), ShouldResemble, data.Map{
+					"endpoint": data.String("localhost:1234"),
+					"retries":  data.Int(5),
+				})
+			})
+		})
+	})
+}
+
 func TestDropSourceStmt(t *testing.T) {
 	Convey("Given a BQL TopologyBuilder", t, func() {
 		dt := newTestTopology()
@@ -1229,3 +2028,69 @@ func waitForExpectedCondition(f func() bool) {
 		time.Sleep(time.Nanosecond)
 	}
 }
+
+func TestSinkWALConfig(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a sink WAL directory", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_bql_sink_wal_test")
+		So(err, ShouldBeNil)
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+			os.RemoveAll(dir)
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, fmt.Sprintf(`SET CONFIG WITH sink_wal_dir="%v";
+			CREATE PAUSED SOURCE s TYPE dummy WITH num=4;`, dir)), ShouldBeNil)
+
+		Convey("When creating a sink", func() {
+			So(addBQLToTopology(tb, `CREATE SINK foo TYPE collector;
+				INSERT INTO foo FROM s;
+				RESUME SOURCE s;`), ShouldBeNil)
+			sn, err := dt.Sink("foo")
+			So(err, ShouldBeNil)
+			si := sn.Sink().(*tupleCollectorSink)
+			for i := 0; i < 1000 && si.len() < 4; i++ {
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then it should write tuples through the WAL", func() {
+				So(si.len(), ShouldEqual, 4)
+				d, err := sn.Status().Get(data.MustCompilePath("wal.dir"))
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, data.String(filepath.Join(dir, dt.Name(), "foo")))
+			})
+
+			Convey("Then REPLAY SINK should succeed", func() {
+				So(addBQLToTopology(tb, `REPLAY SINK foo`), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a BQL TopologyBuilder without a sink WAL directory", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE SINK foo TYPE collector;`), ShouldBeNil)
+
+		Convey("When replaying the sink", func() {
+			err := addBQLToTopology(tb, `REPLAY SINK foo`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the sink doesn't have a WAL", func() {
+			sn, err := dt.Sink("foo")
+			So(err, ShouldBeNil)
+
+			Convey("Then the status shouldn't have it", func() {
+				So(sn.Status(), ShouldNotContainKey, "wal")
+			})
+		})
+	})
+}
@@ -0,0 +1,194 @@
+package bql
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// triggerSource is a source which runs the action of a trigger on its
+// schedule. A trigger is a source node so that it's listed, monitored, and
+// stopped along with other nodes in the topology. When the action is
+// INSERT INTO ... SELECT, the source emits the computed tuple to the sink.
+type triggerSource struct {
+	tb       *TopologyBuilder
+	stmt     parser.CreateTriggerStmt
+	schedule schedule
+	stopCh   chan struct{}
+
+	m       sync.Mutex
+	numRuns int64
+	lastRun time.Time
+	nextRun time.Time
+	lastErr error
+}
+
+func (tb *TopologyBuilder) createTrigger(stmt *parser.CreateTriggerStmt) (core.Node, error) {
+	sch, err := parseSchedule(stmt.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var sink core.SinkNode
+	switch a := stmt.Action.(type) {
+	case parser.TriggerInsertStmt:
+		for _, p := range a.Projections {
+			if _, ok := p.(parser.Wildcard); ok {
+				return nil, errors.New("a trigger cannot insert '*'")
+			}
+			if !p.Foldable() {
+				return nil, fmt.Errorf("a trigger cannot refer to columns: %v", p)
+			}
+		}
+		s, err := tb.topology.Sink(string(a.Sink))
+		if err != nil {
+			return nil, err
+		}
+		sink = s
+
+	case parser.UpdateStateStmt, parser.SaveStateStmt, parser.UpdateSourceStmt,
+		parser.UpdateSinkStmt, parser.PauseSourceStmt, parser.ResumeSourceStmt,
+		parser.RewindSourceStmt:
+
+	default:
+		return nil, fmt.Errorf("unsupported action of a trigger: %v", stmt.Action)
+	}
+
+	name := string(stmt.Name)
+	src := &triggerSource{
+		tb:       tb,
+		stmt:     *stmt,
+		schedule: sch,
+		stopCh:   make(chan struct{}),
+	}
+	sn, err := tb.topology.AddSource(name, src, &core.SourceConfig{
+		PausedOnStartup: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sink != nil {
+		if err := sink.Input(name, nil); err != nil {
+			tb.topology.Remove(name)
+			return nil, err
+		}
+	}
+	if err := sn.Resume(); err != nil {
+		tb.topology.Remove(name)
+		return nil, err
+	}
+	return sn, nil
+}
+
+func (tb *TopologyBuilder) dropTrigger(stmt *parser.DropTriggerStmt) error {
+	sn, err := tb.topology.Source(string(stmt.Trigger))
+	if err != nil {
+		return err
+	}
+	if _, ok := sn.Source().(*triggerSource); !ok {
+		return fmt.Errorf("'%v' is not a trigger", stmt.Trigger)
+	}
+	return tb.topology.Remove(string(stmt.Trigger))
+}
+
+func (s *triggerSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	for {
+		now := time.Now()
+		next := s.schedule.next(now)
+		if next.IsZero() {
+			return fmt.Errorf("the schedule '%v' never runs the trigger", s.stmt.Schedule)
+		}
+		s.m.Lock()
+		s.nextRun = next
+		s.m.Unlock()
+
+		select {
+		case <-s.stopCh:
+			return nil
+		case <-time.After(next.Sub(now)):
+		}
+
+		err := s.run(ctx, w)
+		if err != nil {
+			ctx.ErrLog(err).WithField("node_type", core.NTSource).
+				WithField("node_name", string(s.stmt.Name)).
+				Error("Cannot run the action of the trigger")
+		}
+		s.m.Lock()
+		s.numRuns++
+		s.lastRun = next
+		s.lastErr = err
+		s.m.Unlock()
+	}
+}
+
+func (s *triggerSource) run(ctx *core.Context, w core.Writer) error {
+	a, ok := s.stmt.Action.(parser.TriggerInsertStmt)
+	if !ok {
+		// The action isn't limited by max_concurrent_statements.
+		_, err := s.tb.addStmt("", s.stmt.Action)
+		return err
+	}
+
+	m := data.Map{}
+	for i, p := range a.Projections {
+		expr, col := p, fmt.Sprintf("col_%v", i)
+		switch p := p.(type) {
+		case parser.AliasAST:
+			expr, col = p.Expr, p.Alias
+		case parser.FuncAppAST:
+			col = string(p.Function)
+		}
+		v, err := execution.EvaluateFoldable(expr, s.tb.Reg)
+		if err != nil {
+			return err
+		}
+		path, err := data.CompilePath(col)
+		if err != nil {
+			return err
+		}
+		if err := m.Set(path, v); err != nil {
+			return err
+		}
+	}
+	return w.Write(ctx, core.NewTuple(m))
+}
+
+func (s *triggerSource) Stop(ctx *core.Context) error {
+	close(s.stopCh)
+	return nil
+}
+
+// Status returns the status of the trigger. It has following fields:
+//
+//	* schedule: the schedule of the trigger
+//	* action: the statement run by the trigger
+//	* num_runs: the number of times the action has been run
+//	* last_run: the time at which the action was run last (optional)
+//	* next_run: the time at which the action will be run next (optional)
+//	* error: the error which the last run reported (optional)
+func (s *triggerSource) Status() data.Map {
+	s.m.Lock()
+	defer s.m.Unlock()
+	st := data.Map{
+		"schedule": data.String(s.stmt.Schedule),
+		"action":   data.String(s.stmt.Action.String()),
+		"num_runs": data.Int(s.numRuns),
+	}
+	if !s.lastRun.IsZero() {
+		st["last_run"] = data.Timestamp(s.lastRun)
+	}
+	if !s.nextRun.IsZero() {
+		st["next_run"] = data.Timestamp(s.nextRun)
+	}
+	if s.lastErr != nil {
+		st["error"] = data.String(s.lastErr.Error())
+	}
+	return st
+}
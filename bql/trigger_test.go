@@ -0,0 +1,96 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestCreateTriggerStmt(t *testing.T) {
+	Convey("Given a BQL TopologyBuilder with a sink", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE SINK snk TYPE collector`), ShouldBeNil)
+		sn, err := dt.Sink("snk")
+		So(err, ShouldBeNil)
+		si := sn.Sink().(*tupleCollectorSink)
+
+		Convey("When creating a trigger inserting tuples into the sink", func() {
+			So(addBQLToTopology(tb, `CREATE TRIGGER tr EVERY "@every 10ms"
+				DO INSERT INTO snk SELECT 1 + 2 AS a.b, "x", str(4)`), ShouldBeNil)
+
+			Convey("Then the sink should periodically receive the tuple", func() {
+				si.Wait(2)
+				So(si.get(0).Data, ShouldResemble, data.Map{
+					"a":     data.Map{"b": data.Int(3)},
+					"col_1": data.String("x"),
+					"str":   data.String("4"),
+				})
+				So(si.get(1).Data, ShouldResemble, si.get(0).Data)
+
+				Convey("And the trigger should report its status", func() {
+					tr, err := dt.Source("tr")
+					So(err, ShouldBeNil)
+					st := tr.Status()["source"].(data.Map)
+					So(st["schedule"], ShouldEqual, data.String("@every 10ms"))
+					n, err := data.AsInt(st["num_runs"])
+					So(err, ShouldBeNil)
+					So(n, ShouldBeGreaterThanOrEqualTo, 2)
+				})
+			})
+
+			Convey("Then dropping the trigger should remove it", func() {
+				So(addBQLToTopology(tb, `DROP TRIGGER tr`), ShouldBeNil)
+				_, err := dt.Source("tr")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When creating a trigger resuming a source", func() {
+			So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy WITH num=1;
+				CREATE TRIGGER tr EVERY "@every 10ms" DO RESUME SOURCE s`), ShouldBeNil)
+
+			Convey("Then the source should be resumed", func() {
+				src, err := dt.Source("s")
+				So(err, ShouldBeNil)
+				So(src.State().Wait(core.TSRunning), ShouldBeGreaterThanOrEqualTo, core.TSRunning)
+			})
+
+			Convey("Then the source cannot be dropped by DROP TRIGGER", func() {
+				So(addBQLToTopology(tb, `DROP TRIGGER s`), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a trigger with an invalid schedule", func() {
+			err := addBQLToTopology(tb, `CREATE TRIGGER tr EVERY "0 25 * * *" DO RESUME SOURCE s`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				_, err := dt.Source("tr")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a trigger inserting into a missing sink", func() {
+			err := addBQLToTopology(tb, `CREATE TRIGGER tr EVERY "@daily" DO INSERT INTO hoge SELECT 1`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a trigger inserting a column", func() {
+			err := addBQLToTopology(tb, `CREATE TRIGGER tr EVERY "@daily" DO INSERT INTO snk SELECT a`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,131 @@
+package bql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// TupleTemplate formats tuples with text/template so that sinks can emit
+// payloads in the shape downstream systems expect. Sinks supporting it accept
+// the template in the "format_template" parameter.
+//
+// The template is executed with TupleTemplateData, so fields of a tuple are
+// referred as {{.Data.field}} and the timestamp of the tuple as
+// {{.Timestamp}}. In addition to builtin functions of text/template, the
+// following functions are available:
+//
+//	- json: encodes a value in JSON, e.g. {{json .Data}}
+//	- formatTime: formats a timestamp with a layout of the time package,
+//	  e.g. {{formatTime "2006-01-02" .Timestamp}}
+//	- lower, upper: converts a string to lower or upper case
+type TupleTemplate struct {
+	tmpl *template.Template
+}
+
+// TupleTemplateData is the data passed to a TupleTemplate.
+type TupleTemplateData struct {
+	// Data has fields of the tuple. Values are converted to native Go
+	// values: timestamps are time.Time and null is nil.
+	Data map[string]interface{}
+
+	// Timestamp is the timestamp of the tuple.
+	Timestamp time.Time
+
+	// ProcTimestamp is the time at which the tuple was emitted by a source.
+	ProcTimestamp time.Time
+
+	// InputName is the name of the node which emitted the tuple.
+	InputName string
+
+	// Metadata has the metadata of the tuple. It's empty when the tuple
+	// doesn't have metadata.
+	Metadata map[string]interface{}
+}
+
+var tupleTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	},
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// NewTupleTemplate parses the text as a TupleTemplate.
+func NewTupleTemplate(text string) (*TupleTemplate, error) {
+	t, err := template.New("format_template").Funcs(tupleTemplateFuncs).
+		Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format_template: %v", err)
+	}
+	return &TupleTemplate{
+		tmpl: t,
+	}, nil
+}
+
+// Execute writes the tuple formatted by the template to w.
+func (t *TupleTemplate) Execute(w io.Writer, tuple *core.Tuple) error {
+	d := &TupleTemplateData{
+		Data:          tupleTemplateMap(tuple.Data),
+		Timestamp:     tuple.Timestamp,
+		ProcTimestamp: tuple.ProcTimestamp,
+		InputName:     tuple.InputName,
+		Metadata:      tupleTemplateMap(tuple.Metadata),
+	}
+	return t.tmpl.Execute(w, d)
+}
+
+func tupleTemplateMap(m data.Map) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		res[k] = tupleTemplateValue(v)
+	}
+	return res
+}
+
+func tupleTemplateValue(v data.Value) interface{} {
+	switch v.Type() {
+	case data.TypeBool:
+		b, _ := data.AsBool(v)
+		return b
+	case data.TypeInt:
+		i, _ := data.AsInt(v)
+		return i
+	case data.TypeFloat:
+		f, _ := data.AsFloat(v)
+		return f
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		return s
+	case data.TypeBlob:
+		b, _ := data.AsBlob(v)
+		return b
+	case data.TypeTimestamp:
+		t, _ := data.AsTimestamp(v)
+		return t
+	case data.TypeArray:
+		a, _ := data.AsArray(v)
+		res := make([]interface{}, len(a))
+		for i, e := range a {
+			res[i] = tupleTemplateValue(e)
+		}
+		return res
+	case data.TypeMap:
+		m, _ := data.AsMap(v)
+		return tupleTemplateMap(m)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package bql
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestTupleTemplate(t *testing.T) {
+	ts := time.Date(2016, time.January, 2, 3, 4, 5, 0, time.UTC)
+	tuple := core.NewTuple(data.Map{
+		"name":  data.String("sensor1"),
+		"value": data.Float(1.5),
+		"tags":  data.Array{data.String("a"), data.String("b")},
+		"pos":   data.Map{"x": data.Int(1)},
+		"none":  data.Null{},
+		"at":    data.Timestamp(ts),
+	})
+	tuple.Timestamp = ts
+	tuple.Metadata = data.Map{"key": data.String("k1")}
+
+	cases := []struct {
+		tmpl     string
+		expected string
+	}{
+		{`{{.Data.name}}={{.Data.value}}`, "sensor1=1.5"},
+		{`{{index .Data.tags 1}} {{.Data.pos.x}}`, "b 1"},
+		{`{{json .Data.tags}}`, `["a","b"]`},
+		{`{{formatTime "2006-01-02" .Timestamp}}`, "2016-01-02"},
+		{`{{formatTime "15:04" .Data.at}}`, "03:04"},
+		{`{{upper .Data.name}} {{.Metadata.key}}`, "SENSOR1 k1"},
+		{`{{if .Data.none}}x{{else}}null{{end}}`, "null"},
+		{`{{.Data.no_such_field}}`, "<no value>"},
+	}
+
+	for _, c := range cases {
+		c := c
+		Convey("Given a template "+c.tmpl, t, func() {
+			tmpl, err := NewTupleTemplate(c.tmpl)
+			So(err, ShouldBeNil)
+
+			Convey("When formatting a tuple", func() {
+				var b bytes.Buffer
+				err := tmpl.Execute(&b, tuple)
+
+				Convey("Then it should emit the expected payload", func() {
+					So(err, ShouldBeNil)
+					So(b.String(), ShouldEqual, c.expected)
+				})
+			})
+		})
+	}
+
+	Convey("Given an invalid template", t, func() {
+		_, err := NewTupleTemplate(`{{.Data.name`)
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
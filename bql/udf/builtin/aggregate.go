@@ -560,3 +560,79 @@ var sumFunc udf.UDF = &singleParamAggFunc{
 }
 
 // skipping xmlagg here since we have no XML data type
+
+// percentileUDAF is a UDAF computing a percentile of numbers with linear
+// interpolation between the closest ranks.
+type percentileUDAF struct{}
+
+func (f *percentileUDAF) Accept(arity int) bool {
+	return arity == 2
+}
+
+func (f *percentileUDAF) IsAggregationParameter(k int) bool {
+	return k == 0
+}
+
+func (f *percentileUDAF) Init(ctx *core.Context) (udf.UDAFState, error) {
+	return &percentileState{}, nil
+}
+
+type percentileState struct {
+	values   []float64
+	fraction data.Value
+}
+
+func (s *percentileState) Accumulate(ctx *core.Context, args ...data.Value) error {
+	s.fraction = args[1]
+	switch args[0].Type() {
+	case data.TypeInt:
+		i, _ := data.AsInt(args[0])
+		s.values = append(s.values, float64(i))
+	case data.TypeFloat:
+		f, _ := data.AsFloat(args[0])
+		s.values = append(s.values, f)
+	case data.TypeNull:
+	default:
+		return fmt.Errorf("cannot interpret %s (%T) as a number", args[0], args[0])
+	}
+	return nil
+}
+
+func (s *percentileState) Merge(ctx *core.Context, other udf.UDAFState) error {
+	o := other.(*percentileState)
+	s.values = append(s.values, o.values...)
+	if s.fraction == nil {
+		s.fraction = o.fraction
+	}
+	return nil
+}
+
+func (s *percentileState) Result(ctx *core.Context) (data.Value, error) {
+	if len(s.values) == 0 || s.fraction.Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	p, err := data.ToFloat(s.fraction)
+	if err != nil || !(p >= 0 && p <= 1) {
+		return nil, fmt.Errorf("fraction must be a number from 0 to 1: %v", s.fraction)
+	}
+	sort.Float64s(s.values)
+	r := p * float64(len(s.values)-1)
+	lower := int(math.Floor(r))
+	if lower == len(s.values)-1 {
+		return data.Float(s.values[lower]), nil
+	}
+	d := r - float64(lower)
+	return data.Float(s.values[lower]*(1-d) + s.values[lower+1]*d), nil
+}
+
+// percentileFunc is an aggregate function that computes the value
+// below which the given fraction of input values fall, interpolating
+// linearly between the two closest values. Null values are ignored,
+// non-numeric values lead to an error.
+// See also: PostgreSQL's `percentile_cont`
+//
+// It can be used in BQL as `percentile`.
+//
+//  Input: Int or Float (aggregated), Float (0 to 1)
+//  Return Type: Float (Null on empty input)
+var percentileFunc udf.UDAF = &percentileUDAF{}
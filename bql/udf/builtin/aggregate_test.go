@@ -326,6 +326,27 @@ func TestBinaryAggregateFuncs(t *testing.T) {
 			// array contains non-string
 			{data.Array{data.String("foo"), data.Int(7)}, data.String(", "), nil},
 		}},
+		{"percentile", udf.UDAFToUDF(percentileFunc), []udfBinaryTestCaseInput{
+			{data.Array{}, data.Float(0.5), data.Null{}},
+			{data.Array{data.Null{}}, data.Float(0.5), data.Null{}},
+			// normal cases
+			{data.Array{data.Int(3)}, data.Float(0.9), data.Float(3)},
+			{data.Array{data.Int(4), data.Float(1), data.Int(3), data.Int(2)}, data.Float(0.5),
+				data.Float(2.5)},
+			{data.Array{data.Int(4), data.Null{}, data.Int(3), data.Int(2), data.Int(1)}, data.Float(0.9),
+				data.Float(3.7)},
+			{data.Array{data.Int(4), data.Int(3), data.Int(2), data.Int(1)}, data.Int(0),
+				data.Float(1)},
+			{data.Array{data.Int(4), data.Int(3), data.Int(2), data.Int(1)}, data.Int(1),
+				data.Float(4)},
+			{data.Array{data.Int(1)}, data.Null{}, data.Null{}},
+			/// fail cases
+			// fraction is out of range
+			{data.Array{data.Int(1)}, data.Float(1.5), nil},
+			{data.Array{data.Int(1)}, data.String("a"), nil},
+			// array contains non-number
+			{data.Array{data.Int(1), data.String("foo")}, data.Float(0.5), nil},
+		}},
 	}
 
 	for _, testCase := range udfBinaryTestCases {
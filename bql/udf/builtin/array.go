@@ -5,6 +5,7 @@ import (
 	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"sort"
 )
 
 // arrayLengthFunc returns the length of the given array.
@@ -23,3 +24,224 @@ var arrayLengthFunc udf.UDF = udf.UnaryFunc(func(ctx *core.Context, arg data.Val
 	}
 	return nil, fmt.Errorf("%v is not an array", arg)
 })
+
+type arrayContainsFuncTmpl struct {
+	twoParamFunc
+}
+
+func (f *arrayContainsFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("function takes exactly two arguments")
+	}
+	if args[0].Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	a, err := data.AsArray(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v is not an array", args[0])
+	}
+	for _, e := range a {
+		if data.Equal(e, args[1]) {
+			return data.True, nil
+		}
+	}
+	return data.False, nil
+}
+
+// arrayContainsFunc(arr, v) returns true if the array contains an element
+// which is equal to `v`. Ints and Floats having the same value are
+// considered to be equal.
+//
+// It can be used in BQL as `array_contains`.
+//
+//  Input: Array, Any
+//  Return Type: Bool
+var arrayContainsFunc udf.UDF = &arrayContainsFuncTmpl{}
+
+type arraySliceFuncTmpl struct {
+}
+
+func (f *arraySliceFuncTmpl) Accept(arity int) bool {
+	return arity == 2 || arity == 3
+}
+
+func (f *arraySliceFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *arraySliceFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("function takes two or three arguments")
+	}
+	for _, arg := range args {
+		if arg.Type() == data.TypeNull {
+			return data.Null{}, nil
+		}
+	}
+	a, err := data.AsArray(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v is not an array", args[0])
+	}
+	from, err := data.AsInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as an integer", args[1])
+	}
+	if from < 0 {
+		return nil, fmt.Errorf("`from` parameter must be at least 0")
+	}
+	if from > int64(len(a)) {
+		from = int64(len(a))
+	}
+	to := int64(len(a))
+	if len(args) == 3 {
+		length, err := data.AsInt(args[2])
+		if err != nil {
+			return nil, fmt.Errorf("cannot interpret %s as an integer", args[2])
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("`for` parameter must be at least 0")
+		}
+		if from+length < to {
+			to = from + length
+		}
+	}
+	res := make(data.Array, to-from)
+	copy(res, a[from:to])
+	return res, nil
+}
+
+// arraySliceFunc(arr, from, [for]) returns the `for` elements of `arr`
+// starting from the `from` index (0-based). If `for` is not given,
+// everything until the end of `arr` is returned.
+// See also: substring
+//
+// It can be used in BQL as `array_slice`.
+//
+//  Input: Array, Int, [Int]
+//  Return Type: Array
+var arraySliceFunc udf.UDF = &arraySliceFuncTmpl{}
+
+// arrayConcatFunc concatenates all arrays given as input arguments.
+// Null values are ignored, non-array arguments lead to an error.
+//
+// It can be used in BQL as `array_concat`.
+//
+//  Input: n * Array
+//  Return Type: Array
+var arrayConcatFunc udf.UDF = &variadicFunc{
+	minParams: 1,
+	varFun: func(args ...data.Value) (data.Value, error) {
+		res := data.Array{}
+		for _, item := range args {
+			switch item.Type() {
+			case data.TypeNull:
+				continue
+			case data.TypeArray:
+				a, _ := data.AsArray(item)
+				res = append(res, a...)
+			default:
+				return nil, fmt.Errorf("%v is not an array", item)
+			}
+		}
+		return res, nil
+	},
+}
+
+// arrayDistinctFunc removes duplicate elements from an array. The order of
+// the first occurrences of elements is preserved.
+//
+// It can be used in BQL as `array_distinct`.
+//
+//  Input: Array
+//  Return Type: Array
+var arrayDistinctFunc udf.UDF = udf.UnaryFunc(func(ctx *core.Context, arg data.Value) (data.Value, error) {
+	if arg.Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	a, err := data.AsArray(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not an array", arg)
+	}
+	seen := map[data.HashValue][]data.Value{}
+	res := data.Array{}
+elemLoop:
+	for _, e := range a {
+		h := data.Hash(e)
+		for _, s := range seen[h] {
+			if data.Equal(e, s) {
+				continue elemLoop
+			}
+		}
+		seen[h] = append(seen[h], e)
+		res = append(res, e)
+	}
+	return res, nil
+})
+
+// arraySortFunc sorts the elements of an array in ascending order.
+// Elements of different types are ordered as described in data.Less.
+//
+// It can be used in BQL as `array_sort`.
+//
+//  Input: Array
+//  Return Type: Array
+var arraySortFunc udf.UDF = udf.UnaryFunc(func(ctx *core.Context, arg data.Value) (data.Value, error) {
+	if arg.Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	a, err := data.AsArray(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not an array", arg)
+	}
+	res := make(data.Array, len(a))
+	copy(res, a)
+	sort.Stable(sortableArray(res))
+	return res, nil
+})
+
+// sortableArray implements sort.Interface using data.Less.
+type sortableArray data.Array
+
+func (a sortableArray) Len() int           { return len(a) }
+func (a sortableArray) Less(i, j int) bool { return data.Less(a[i], a[j]) }
+func (a sortableArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// arrayZipFunc combines elements of arrays at the same index into arrays.
+// The length of the result is the length of the shortest input array.
+// For example, array_zip([1, 2, 3], ["a", "b"]) returns
+// [[1, "a"], [2, "b"]].
+//
+// It can be used in BQL as `array_zip`.
+//
+//  Input: n * Array
+//  Return Type: Array of Array
+var arrayZipFunc udf.UDF = &variadicFunc{
+	minParams: 1,
+	varFun: func(args ...data.Value) (data.Value, error) {
+		arrays := make([]data.Array, len(args))
+		length := -1
+		for i, item := range args {
+			if item.Type() == data.TypeNull {
+				return data.Null{}, nil
+			}
+			a, err := data.AsArray(item)
+			if err != nil {
+				return nil, fmt.Errorf("%v is not an array", item)
+			}
+			arrays[i] = a
+			if length < 0 || len(a) < length {
+				length = len(a)
+			}
+		}
+
+		res := make(data.Array, length)
+		for i := range res {
+			tuple := make(data.Array, len(arrays))
+			for j, a := range arrays {
+				tuple[j] = a[i]
+			}
+			res[i] = tuple
+		}
+		return res, nil
+	},
+}
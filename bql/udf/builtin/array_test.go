@@ -33,6 +33,20 @@ func TestUnaryArrayFuncs(t *testing.T) {
 			{data.Array{data.Null{}}, data.Int(1)},
 			{data.Array{data.Int(2), data.Float(3)}, data.Int(2)},
 		}},
+		{"array_distinct", arrayDistinctFunc, []udfUnaryTestCaseInput{
+			{data.Array{}, data.Array{}},
+			{data.Array{data.Int(2), data.String("a"), data.Float(2), data.Null{}, data.String("a"), data.Null{}},
+				data.Array{data.Int(2), data.String("a"), data.Null{}}},
+			{data.Array{data.Map{"a": data.Int(1)}, data.Map{"a": data.Int(1)}},
+				data.Array{data.Map{"a": data.Int(1)}}},
+		}},
+		{"array_sort", arraySortFunc, []udfUnaryTestCaseInput{
+			{data.Array{}, data.Array{}},
+			{data.Array{data.Int(3), data.Float(1.5), data.Int(2)},
+				data.Array{data.Float(1.5), data.Int(2), data.Int(3)}},
+			{data.Array{data.String("b"), data.Int(1), data.Null{}, data.String("a")},
+				data.Array{data.Null{}, data.Int(1), data.String("a"), data.String("b")}},
+		}},
 	}
 
 	for _, testCase := range udfUnaryTestCases {
@@ -80,3 +94,84 @@ func TestUnaryArrayFuncs(t *testing.T) {
 		})
 	}
 }
+
+func TestVariadicArrayFuncs(t *testing.T) {
+	udfVariadicTestCases := []udfVariadicTestCase{
+		{"array_contains", arrayContainsFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.Array{data.Int(1), data.String("a")}, data.String("a")}, data.True},
+			{[]data.Value{data.Array{data.Int(1), data.String("a")}, data.Float(1)}, data.True},
+			{[]data.Value{data.Array{data.Int(1), data.String("a")}, data.Int(2)}, data.False},
+			{[]data.Value{data.Array{}, data.Null{}}, data.False},
+			{[]data.Value{data.Null{}, data.Int(1)}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Map{}, data.Int(1)}, nil},
+		}},
+		{"array_slice", arraySliceFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.Array{data.Int(1), data.Int(2), data.Int(3)}, data.Int(1)},
+				data.Array{data.Int(2), data.Int(3)}},
+			{[]data.Value{data.Array{data.Int(1), data.Int(2), data.Int(3)}, data.Int(0), data.Int(2)},
+				data.Array{data.Int(1), data.Int(2)}},
+			{[]data.Value{data.Array{data.Int(1), data.Int(2), data.Int(3)}, data.Int(2), data.Int(5)},
+				data.Array{data.Int(3)}},
+			{[]data.Value{data.Array{data.Int(1), data.Int(2), data.Int(3)}, data.Int(5)},
+				data.Array{}},
+			{[]data.Value{data.Null{}, data.Int(1)}, data.Null{}},
+			{[]data.Value{data.Array{}, data.Int(1), data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Array{}, data.Int(-1)}, nil},
+			{[]data.Value{data.Array{}, data.Int(0), data.Int(-1)}, nil},
+			{[]data.Value{data.Array{}, data.String("0")}, nil},
+			{[]data.Value{data.String("abc"), data.Int(0)}, nil},
+		}},
+		{"array_concat", arrayConcatFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.Array{data.Int(1)}, data.Null{}, data.Array{data.Int(2), data.Int(3)}},
+				data.Array{data.Int(1), data.Int(2), data.Int(3)}},
+			{[]data.Value{data.Null{}}, data.Array{}},
+			// invalid cases
+			{[]data.Value{data.Array{}, data.Int(1)}, nil},
+		}},
+		{"array_zip", arrayZipFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.Array{data.Int(1), data.Int(2), data.Int(3)}, data.Array{data.String("a"), data.String("b")}},
+				data.Array{
+					data.Array{data.Int(1), data.String("a")},
+					data.Array{data.Int(2), data.String("b")},
+				}},
+			{[]data.Value{data.Array{data.Int(1)}}, data.Array{data.Array{data.Int(1)}}},
+			{[]data.Value{data.Array{data.Int(1)}, data.Array{}}, data.Array{}},
+			{[]data.Value{data.Array{data.Int(1)}, data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Array{}, data.Map{}}, nil},
+		}},
+	}
+
+	for _, testCase := range udfVariadicTestCases {
+		f := testCase.f
+
+		Convey(fmt.Sprintf("Given the %s function", testCase.name), t, func() {
+			for _, tc := range testCase.inputs {
+				tc := tc
+
+				Convey(fmt.Sprintf("When evaluating it on %#v", tc.input), func() {
+					val, err := f.Call(nil, tc.input...)
+
+					if tc.expected == nil {
+						Convey("Then evaluation should fail", func() {
+							So(err, ShouldNotBeNil)
+						})
+					} else {
+						Convey(fmt.Sprintf("Then the result should be %s", tc.expected), func() {
+							So(err, ShouldBeNil)
+							So(val, ShouldResemble, tc.expected)
+						})
+					}
+				})
+			}
+
+			Convey("Then it should equal the one in the default registry", func() {
+				regFun, err := udf.CopyGlobalUDFRegistry(nil).Lookup(testCase.name, 2)
+				So(err, ShouldBeNil)
+				So(regFun, ShouldHaveSameTypeAs, f)
+			})
+		})
+	}
+}
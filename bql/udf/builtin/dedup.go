@@ -0,0 +1,216 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// defaultDeduplicateMaxKeys is the default maximum number of keys
+	// the deduplicate UDSF remembers at once.
+	defaultDeduplicateMaxKeys = 100000
+)
+
+// deduplicateUDSF suppresses tuples having a key which has already been seen
+// within a window. The window is defined by the time elapsed since the first
+// tuple having the key arrived (based on the timestamp of tuples), by the
+// number of tuples arrived after it, or both. The first tuple having a key
+// is always emitted and the key is forgotten when it leaves the window.
+//
+// It can be used in BQL as `deduplicate`:
+//
+//	deduplicate(stream, key [, params])
+//
+// stream is the name of the input stream and key is a JSON Path of the
+// key in tuples. params is a map which can have following parameters:
+//
+//	* duration: a duration for which a key is remembered. It's in seconds
+//	  when the value is a number. A string is parsed by time.ParseDuration.
+//	* count: the number of subsequent tuples for which a key is remembered.
+//	* max_keys: the maximum number of keys to be remembered. When the number
+//	  of keys exceeds this limit, the oldest key is forgotten even if it's
+//	  still in the window. The default value is 100000.
+//
+// When neither duration nor count is specified, a key is only forgotten due
+// to max_keys. The status of the node running this UDSF contains following
+// fields:
+//
+//	* num_keys: the number of keys currently remembered
+//	* num_suppressed: the number of tuples suppressed as duplicates
+//	* num_evicted: the number of keys forgotten due to max_keys
+//
+// Timestamps of tuples are assumed to be monotonically increasing. When they
+// aren't, keys could be remembered longer than the duration.
+type deduplicateUDSF struct {
+	key      data.Path
+	duration time.Duration
+	count    int64
+	maxKeys  int
+
+	m sync.Mutex
+
+	// keys has entries in the order they were added. An entry is removed
+	// from keys and index when it leaves the window.
+	keys  []*deduplicateEntry
+	index map[data.HashValue][]*deduplicateEntry
+	seq   int64
+
+	numSuppressed int64
+	numEvicted    int64
+}
+
+type deduplicateEntry struct {
+	key       data.Value
+	hash      data.HashValue
+	timestamp time.Time
+	seq       int64
+}
+
+var (
+	_ core.Statuser = &deduplicateUDSF{}
+)
+
+func createDeduplicateUDSF(decl udf.UDSFDeclarer, stream, key string, params ...data.Map) (udf.UDSF, error) {
+	if len(params) > 1 {
+		return nil, errors.New("too many arguments")
+	}
+
+	path, err := data.CompilePath(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key '%v': %v", key, err)
+	}
+	f := &deduplicateUDSF{
+		key:     path,
+		maxKeys: defaultDeduplicateMaxKeys,
+		index:   map[data.HashValue][]*deduplicateEntry{},
+	}
+
+	if len(params) == 1 {
+		for k, v := range params[0] {
+			switch k {
+			case "duration":
+				d, err := data.ToDuration(v)
+				if err != nil {
+					return nil, fmt.Errorf("duration parameter cannot be converted to a duration: %v", err)
+				}
+				if d <= 0 {
+					return nil, errors.New("duration parameter must be positive")
+				}
+				f.duration = d
+			case "count":
+				c, err := data.ToInt(v)
+				if err != nil {
+					return nil, fmt.Errorf("count parameter cannot be converted to an integer: %v", err)
+				}
+				if c <= 0 {
+					return nil, errors.New("count parameter must be positive")
+				}
+				f.count = c
+			case "max_keys":
+				n, err := data.ToInt(v)
+				if err != nil {
+					return nil, fmt.Errorf("max_keys parameter cannot be converted to an integer: %v", err)
+				}
+				if n <= 0 {
+					return nil, errors.New("max_keys parameter must be positive")
+				}
+				f.maxKeys = int(n)
+			default:
+				return nil, fmt.Errorf("unknown parameter: %v", k)
+			}
+		}
+	}
+
+	if err := decl.Input(stream, nil); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *deduplicateUDSF) Process(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+	k, err := t.Data.Get(f.key)
+	if err != nil {
+		// a tuple which doesn't have the key can't be a duplicate
+		return w.Write(ctx, t)
+	}
+
+	f.m.Lock()
+	f.seq++
+	f.expire(t.Timestamp)
+	h := data.Hash(k)
+	for _, e := range f.index[h] {
+		if data.Equal(e.key, k) {
+			f.numSuppressed++
+			f.m.Unlock()
+			return nil
+		}
+	}
+	e := &deduplicateEntry{
+		key:       k,
+		hash:      h,
+		timestamp: t.Timestamp,
+		seq:       f.seq,
+	}
+	f.keys = append(f.keys, e)
+	f.index[h] = append(f.index[h], e)
+	for len(f.keys) > f.maxKeys {
+		f.removeOldest()
+		f.numEvicted++
+	}
+	f.m.Unlock()
+	return w.Write(ctx, t)
+}
+
+// expire removes keys which are out of the window. The caller must hold the
+// lock.
+func (f *deduplicateUDSF) expire(now time.Time) {
+	for len(f.keys) > 0 {
+		e := f.keys[0]
+		expired := (f.duration > 0 && now.Sub(e.timestamp) > f.duration) ||
+			(f.count > 0 && f.seq-e.seq > f.count)
+		if !expired {
+			break
+		}
+		f.removeOldest()
+	}
+}
+
+// removeOldest removes the oldest key. The caller must hold the lock.
+func (f *deduplicateUDSF) removeOldest() {
+	e := f.keys[0]
+	f.keys[0] = nil
+	f.keys = f.keys[1:]
+
+	es := f.index[e.hash]
+	for i, x := range es {
+		if x == e {
+			es = append(es[:i], es[i+1:]...)
+			break
+		}
+	}
+	if len(es) == 0 {
+		delete(f.index, e.hash)
+	} else {
+		f.index[e.hash] = es
+	}
+}
+
+func (f *deduplicateUDSF) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (f *deduplicateUDSF) Status() data.Map {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return data.Map{
+		"num_keys":       data.Int(len(f.keys)),
+		"num_suppressed": data.Int(f.numSuppressed),
+		"num_evicted":    data.Int(f.numEvicted),
+	}
+}
@@ -0,0 +1,124 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestDeduplicateUDSF(t *testing.T) {
+	ctx := core.NewContext(nil)
+	base := time.Date(2015, time.May, 1, 14, 27, 0, 0, time.UTC)
+
+	Convey("Given the deduplicate UDSF creator", t, func() {
+		c := udf.MustConvertToUDSFCreator(createDeduplicateUDSF)
+		decl := udf.NewUDSFDeclarer()
+
+		create := func(params data.Map) *deduplicateUDSF {
+			args := []data.Value{data.String("s"), data.String("id")}
+			if params != nil {
+				args = append(args, params)
+			}
+			f, err := c.CreateUDSF(ctx, decl, args...)
+			So(err, ShouldBeNil)
+			return f.(*deduplicateUDSF)
+		}
+
+		var emitted []int64
+		w := core.WriterFunc(func(ctx *core.Context, t *core.Tuple) error {
+			i, _ := data.AsInt(t.Data["seq"])
+			emitted = append(emitted, i)
+			return nil
+		})
+		process := func(f *deduplicateUDSF, ids ...data.Value) {
+			for i, id := range ids {
+				d := data.Map{"seq": data.Int(i)}
+				if id != nil {
+					d["id"] = id
+				}
+				t := core.NewTuple(d)
+				t.Timestamp = base.Add(time.Duration(i) * time.Second)
+				So(f.Process(ctx, t, w), ShouldBeNil)
+			}
+		}
+
+		Convey("When creating it with only a key", func() {
+			f := create(nil)
+
+			Convey("Then it should declare the input stream", func() {
+				So(decl.ListInputs(), ShouldContainKey, "s")
+			})
+
+			Convey("Then it should suppress all duplicates", func() {
+				process(f, data.Int(1), data.Int(2), data.Int(1), data.Float(2),
+					data.String("1"), data.Int(2))
+				So(emitted, ShouldResemble, []int64{0, 1, 4})
+
+				Convey("And its status should have the number of suppressed tuples", func() {
+					So(f.Status(), ShouldResemble, data.Map{
+						"num_keys":       data.Int(3),
+						"num_suppressed": data.Int(3),
+						"num_evicted":    data.Int(0),
+					})
+				})
+			})
+
+			Convey("Then it should emit tuples not having the key", func() {
+				process(f, nil, nil, data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 1, 2})
+			})
+		})
+
+		Convey("When creating it with a duration", func() {
+			f := create(data.Map{"duration": data.Float(2.5)})
+
+			Convey("Then it should forget keys out of the window", func() {
+				process(f, data.Int(1), data.Int(1), data.Int(1), data.Int(1), data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 3})
+			})
+		})
+
+		Convey("When creating it with a count", func() {
+			f := create(data.Map{"count": data.Int(2)})
+
+			Convey("Then it should forget keys after the given number of tuples", func() {
+				process(f, data.Int(1), data.Int(2), data.Int(1), data.Int(3), data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 1, 3, 4})
+			})
+		})
+
+		Convey("When creating it with max_keys", func() {
+			f := create(data.Map{"max_keys": data.Int(2)})
+
+			Convey("Then it should evict the oldest key", func() {
+				process(f, data.Int(1), data.Int(2), data.Int(3), data.Int(2), data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 1, 2, 4})
+				So(f.Status()["num_keys"], ShouldEqual, data.Int(2))
+				So(f.Status()["num_evicted"], ShouldEqual, data.Int(2))
+				So(f.Status()["num_suppressed"], ShouldEqual, data.Int(1))
+			})
+		})
+
+		Convey("When creating it with invalid parameters", func() {
+			for _, args := range [][]data.Value{
+				{data.String("s"), data.String("id"), data.Map{"duration": data.Int(0)}},
+				{data.String("s"), data.String("id"), data.Map{"duration": data.String("a")}},
+				{data.String("s"), data.String("id"), data.Map{"count": data.Int(-1)}},
+				{data.String("s"), data.String("id"), data.Map{"max_keys": data.Int(0)}},
+				{data.String("s"), data.String("id"), data.Map{"no_such_param": data.Int(1)}},
+				{data.String("s"), data.String("id"), data.Map{}, data.Map{}},
+				{data.String("s"), data.String("id["), data.Map{}},
+			} {
+				_, err := c.CreateUDSF(ctx, udf.NewUDSFDeclarer(), args...)
+
+				Convey("Then it should fail: "+data.Array(args).String(), func() {
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
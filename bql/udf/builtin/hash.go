@@ -0,0 +1,297 @@
+package builtin
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// hashAlgorithms has constructors of hash functions which can be passed to
+// digest and hmac. Names are case-insensitive.
+var hashAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+func lookupHashAlgorithm(v data.Value) (func() hash.Hash, error) {
+	name, err := data.AsString(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as a string", v)
+	}
+	h, ok := hashAlgorithms[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash algorithm: %v", name)
+	}
+	return h, nil
+}
+
+// asHashInput returns the bytes of a String or a Blob.
+func asHashInput(v data.Value) ([]byte, error) {
+	switch v.Type() {
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		return []byte(s), nil
+	case data.TypeBlob:
+		b, _ := data.AsBlob(v)
+		return b, nil
+	}
+	return nil, fmt.Errorf("cannot interpret %s as a string or a blob", v)
+}
+
+// hasNull returns true if any of the arguments is Null.
+func hasNull(args ...data.Value) bool {
+	for _, a := range args {
+		if a.Type() == data.TypeNull {
+			return true
+		}
+	}
+	return false
+}
+
+type digestFuncTmpl struct {
+	twoParamFunc
+}
+
+func (f *digestFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("function takes exactly two arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	b, err := asHashInput(args[0])
+	if err != nil {
+		return nil, err
+	}
+	newHash, err := lookupHashAlgorithm(args[1])
+	if err != nil {
+		return nil, err
+	}
+	h := newHash()
+	h.Write(b)
+	return data.Blob(h.Sum(nil)), nil
+}
+
+// digestFunc(data, algo) computes the binary hash value of a string or
+// a blob. `algo` is one of "md5", "sha1", "sha256", and "sha512".
+// See also: pgcrypto's `digest(data, type)`
+//
+// It can be used in BQL as `digest`.
+//
+//  Input: String or Blob, String
+//  Return Type: Blob
+var digestFunc udf.UDF = &digestFuncTmpl{}
+
+type hmacFuncTmpl struct {
+}
+
+func (f *hmacFuncTmpl) Accept(arity int) bool {
+	return arity == 3
+}
+
+func (f *hmacFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *hmacFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("function takes exactly three arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	msg, err := asHashInput(args[0])
+	if err != nil {
+		return nil, err
+	}
+	key, err := asHashInput(args[1])
+	if err != nil {
+		return nil, err
+	}
+	newHash, err := lookupHashAlgorithm(args[2])
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(newHash, key)
+	h.Write(msg)
+	return data.Blob(h.Sum(nil)), nil
+}
+
+// hmacFunc(data, key, algo) computes the HMAC of a string or a blob with
+// `key`. `algo` is one of "md5", "sha1", "sha256", and "sha512". To verify
+// a signature, compare the result with the expected value, e.g.
+// `encode(hmac(payload, 'secret', 'sha256'), 'hex') = signature`.
+// See also: pgcrypto's `hmac(data, key, type)`
+//
+// It can be used in BQL as `hmac`.
+//
+//  Input: String or Blob, String or Blob, String
+//  Return Type: Blob
+var hmacFunc udf.UDF = &hmacFuncTmpl{}
+
+type crc32FuncTmpl struct {
+	singleParamFunc
+}
+
+func (f *crc32FuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("function takes exactly one argument")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	b, err := asHashInput(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return data.Int(crc32.ChecksumIEEE(b)), nil
+}
+
+// crc32Func computes the CRC-32 checksum (IEEE) of a string or a blob.
+// See also: hash/crc32.ChecksumIEEE
+//
+// It can be used in BQL as `crc32`.
+//
+//  Input: String or Blob
+//  Return Type: Int
+var crc32Func udf.UDF = &crc32FuncTmpl{}
+
+type consistentHashFuncTmpl struct {
+	twoParamFunc
+}
+
+func (f *consistentHashFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("function takes exactly two arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	buckets, err := data.AsInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as an integer", args[1])
+	}
+	if buckets <= 0 {
+		return nil, fmt.Errorf("the number of buckets must be positive")
+	}
+	return data.Int(jumpHash(uint64(data.Hash(args[0])), buckets)), nil
+}
+
+// jumpHash implements "A Fast, Minimal Memory, Consistent Hash Algorithm"
+// by John Lamping and Eric Veach.
+func jumpHash(key uint64, buckets int64) int64 {
+	var b, j int64 = -1, 0
+	for j < buckets {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return b
+}
+
+// consistentHashFunc(key, buckets) maps a value to a bucket in
+// [0, buckets). When the number of buckets changes from n to n+1, only
+// 1/(n+1) of keys are moved to a different bucket. Values which are equal
+// in BQL, such as 2 and 2.0, are mapped to the same bucket.
+//
+// It can be used in BQL as `consistent_hash`.
+//
+//  Input: Any, Int
+//  Return Type: Int
+var consistentHashFunc udf.UDF = &consistentHashFuncTmpl{}
+
+type encodeFuncTmpl struct {
+	twoParamFunc
+}
+
+func (f *encodeFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("function takes exactly two arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	b, err := asHashInput(args[0])
+	if err != nil {
+		return nil, err
+	}
+	format, err := data.AsString(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as a string", args[1])
+	}
+	switch strings.ToLower(format) {
+	case "hex":
+		return data.String(hex.EncodeToString(b)), nil
+	case "base64":
+		return data.String(base64.StdEncoding.EncodeToString(b)), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %v", format)
+}
+
+// encodeFunc(data, format) encodes a blob (or a string) into a textual
+// representation. `format` is either "hex" or "base64".
+// See also: SQL's `encode(data bytea, format text)`
+//
+// It can be used in BQL as `encode`.
+//
+//  Input: Blob or String, String
+//  Return Type: String
+var encodeFunc udf.UDF = &encodeFuncTmpl{}
+
+type decodeFuncTmpl struct {
+	twoParamFunc
+}
+
+func (f *decodeFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("function takes exactly two arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	s, err := data.AsString(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as a string", args[0])
+	}
+	format, err := data.AsString(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as a string", args[1])
+	}
+	var b []byte
+	switch strings.ToLower(format) {
+	case "hex":
+		b, err = hex.DecodeString(s)
+	case "base64":
+		b, err = base64.StdEncoding.DecodeString(s)
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data.Blob(b), nil
+}
+
+// decodeFunc(str, format) is the inverse of encode. `format` is either
+// "hex" or "base64".
+// See also: SQL's `decode(string text, format text)`
+//
+// It can be used in BQL as `decode`.
+//
+//  Input: 2 * String
+//  Return Type: Blob
+var decodeFunc udf.UDF = &decodeFuncTmpl{}
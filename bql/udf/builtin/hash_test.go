@@ -0,0 +1,155 @@
+package builtin
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func mustDecodeHex(s string) data.Blob {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data.Blob(b)
+}
+
+func TestHashFuncs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		f      udf.UDF
+		inputs []udfVariadicTestCaseInput
+	}{
+		{"digest", digestFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("abc"), data.String("md5")},
+				mustDecodeHex("900150983cd24fb0d6963f7d28e17f72")},
+			{[]data.Value{data.Blob("abc"), data.String("SHA1")},
+				mustDecodeHex("a9993e364706816aba3e25717850c26c9cd0d89d")},
+			{[]data.Value{data.String("abc"), data.String("sha256")},
+				mustDecodeHex("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")},
+			{[]data.Value{data.Null{}, data.String("sha256")}, data.Null{}},
+			{[]data.Value{data.String("abc"), data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.String("abc"), data.String("sha3")}, nil},
+			{[]data.Value{data.Int(1), data.String("md5")}, nil},
+			{[]data.Value{data.String("abc"), data.Int(1)}, nil},
+		}},
+		{"hmac", hmacFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("The quick brown fox jumps over the lazy dog"), data.String("key"), data.String("sha256")},
+				mustDecodeHex("f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")},
+			{[]data.Value{data.Blob("The quick brown fox jumps over the lazy dog"), data.Blob("key"), data.String("md5")},
+				mustDecodeHex("80070713463e7749b90c2dc24911e275")},
+			{[]data.Value{data.String("abc"), data.Null{}, data.String("md5")}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.String("abc"), data.Int(1), data.String("md5")}, nil},
+			{[]data.Value{data.String("abc"), data.String("key"), data.String("crc")}, nil},
+		}},
+		{"crc32", crc32Func, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("abc")}, data.Int(891568578)},
+			{[]data.Value{data.Blob("abc")}, data.Int(891568578)},
+			{[]data.Value{data.String("")}, data.Int(0)},
+			{[]data.Value{data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Float(1.5)}, nil},
+		}},
+		{"encode", encodeFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.Blob("\x01\xab"), data.String("hex")}, data.String("01ab")},
+			{[]data.Value{data.String("abc"), data.String("base64")}, data.String("YWJj")},
+			{[]data.Value{data.Null{}, data.String("hex")}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Blob("abc"), data.String("base32")}, nil},
+			{[]data.Value{data.Int(1), data.String("hex")}, nil},
+		}},
+		{"decode", decodeFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("01AB"), data.String("hex")}, data.Blob("\x01\xab")},
+			{[]data.Value{data.String("YWJj"), data.String("base64")}, data.Blob("abc")},
+			{[]data.Value{data.String("YWJj"), data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.String("0g"), data.String("hex")}, nil},
+			{[]data.Value{data.String("YWJj"), data.String("base32")}, nil},
+			{[]data.Value{data.Blob("01"), data.String("hex")}, nil},
+		}},
+		{"consistent_hash", consistentHashFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("abc"), data.Int(1)}, data.Int(0)},
+			{[]data.Value{data.Null{}, data.Int(10)}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.String("abc"), data.Int(0)}, nil},
+			{[]data.Value{data.String("abc"), data.String("10")}, nil},
+		}},
+	}
+
+	for _, testCase := range testCases {
+		f := testCase.f
+
+		Convey(fmt.Sprintf("Given the %s function", testCase.name), t, func() {
+			for _, tc := range testCase.inputs {
+				tc := tc
+
+				Convey(fmt.Sprintf("When evaluating it on %#v", tc.input), func() {
+					val, err := f.Call(nil, tc.input...)
+
+					if tc.expected == nil {
+						Convey("Then evaluation should fail", func() {
+							So(err, ShouldNotBeNil)
+						})
+					} else {
+						Convey(fmt.Sprintf("Then the result should be %s", tc.expected), func() {
+							So(err, ShouldBeNil)
+							So(val, ShouldResemble, tc.expected)
+						})
+					}
+				})
+			}
+
+			Convey("Then it should equal the one in the default registry", func() {
+				regFun, err := udf.CopyGlobalUDFRegistry(nil).Lookup(testCase.name, len(testCase.inputs[0].input))
+				So(err, ShouldBeNil)
+				So(regFun, ShouldHaveSameTypeAs, f)
+			})
+		})
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	Convey("Given the consistent_hash function", t, func() {
+		bucket := func(key data.Value, n int) int64 {
+			v, err := consistentHashFunc.Call(nil, key, data.Int(n))
+			So(err, ShouldBeNil)
+			b, err := data.AsInt(v)
+			So(err, ShouldBeNil)
+			return b
+		}
+
+		Convey("When computing buckets of many keys", func() {
+			Convey("Then all buckets should be in range", func() {
+				for i := 0; i < 1000; i++ {
+					b := bucket(data.Int(i), 7)
+					So(b, ShouldBeGreaterThanOrEqualTo, 0)
+					So(b, ShouldBeLessThan, 7)
+				}
+			})
+
+			Convey("Then keys should only move to the new bucket when adding one", func() {
+				for i := 0; i < 1000; i++ {
+					b1 := bucket(data.String(fmt.Sprint("device", i)), 10)
+					b2 := bucket(data.String(fmt.Sprint("device", i)), 11)
+					if b1 != b2 {
+						So(b2, ShouldEqual, 10)
+					}
+				}
+			})
+		})
+
+		Convey("When computing buckets of equal values", func() {
+			Convey("Then they should be the same", func() {
+				So(bucket(data.Int(2), 100), ShouldEqual, bucket(data.Float(2), 100))
+				So(bucket(data.Map{"a": data.Int(1)}, 100), ShouldEqual,
+					bucket(data.Map{"a": data.Float(1)}, 100))
+			})
+		})
+	})
+}
@@ -61,11 +61,48 @@ func init() {
 	udf.RegisterGlobalUDF("upper", upperFunc)
 	udf.RegisterGlobalUDF("encode_json", udf.UnaryFunc(encodeJSON))
 	udf.RegisterGlobalUDF("decode_json", udf.UnaryFunc(decodeJSON))
+	udf.RegisterGlobalUDF("ends_with", endsWithFunc)
+	udf.RegisterGlobalUDF("levenshtein", levenshteinFunc)
+	udf.RegisterGlobalUDF("lpad", lpadFunc)
+	udf.RegisterGlobalUDF("parse_url", udf.UnaryFunc(parseURL))
+	udf.RegisterGlobalUDF("rpad", rpadFunc)
+	udf.RegisterGlobalUDF("split", splitFunc)
+	udf.RegisterGlobalUDF("sprintf", formatFunc)
+	udf.RegisterGlobalUDF("starts_with", startsWithFunc)
+	udf.RegisterGlobalUDF("url_decode", udf.UnaryFunc(urlDecode))
+	udf.RegisterGlobalUDF("url_encode", urlEncodeFunc)
+	udf.RegisterGlobalUDF("uuid", uuidFunc)
+	// hash functions
+	udf.RegisterGlobalUDF("consistent_hash", consistentHashFunc)
+	udf.RegisterGlobalUDF("crc32", crc32Func)
+	udf.RegisterGlobalUDF("decode", decodeFunc)
+	udf.RegisterGlobalUDF("digest", digestFunc)
+	udf.RegisterGlobalUDF("encode", encodeFunc)
+	udf.RegisterGlobalUDF("hmac", hmacFunc)
+	// redaction functions
+	udf.RegisterGlobalUDF("hash_field", hashFieldFunc)
+	udf.RegisterGlobalUDF("mask", maskFunc)
+	udf.RegisterGlobalUDF("redact", redactFunc)
 	// time functions
 	udf.RegisterGlobalUDF("distance_us", diffUsFunc)
 	udf.RegisterGlobalUDF("clock_timestamp", clockTimestampFunc)
+	udf.RegisterGlobalUDF("format_timestamp", formatTimestampFunc)
+	udf.RegisterGlobalUDF("guess_timestamp", guessTimestampFunc)
+	udf.RegisterGlobalUDF("parse_timestamp", parseTimestampFunc)
 	// array functions
+	udf.RegisterGlobalUDF("array_concat", arrayConcatFunc)
+	udf.RegisterGlobalUDF("array_contains", arrayContainsFunc)
+	udf.RegisterGlobalUDF("array_distinct", arrayDistinctFunc)
 	udf.RegisterGlobalUDF("array_length", arrayLengthFunc)
+	udf.RegisterGlobalUDF("array_slice", arraySliceFunc)
+	udf.RegisterGlobalUDF("array_sort", arraySortFunc)
+	udf.RegisterGlobalUDF("array_zip", arrayZipFunc)
+	// map functions
+	udf.RegisterGlobalUDF("map_keys", mapKeysFunc)
+	udf.RegisterGlobalUDF("map_merge", mapMergeFunc)
+	udf.RegisterGlobalUDF("map_omit", mapOmitFunc)
+	udf.RegisterGlobalUDF("map_pick", mapPickFunc)
+	udf.RegisterGlobalUDF("map_values", mapValuesFunc)
 	// aggregate functions
 	udf.RegisterGlobalUDF("array_agg", arrayAggFunc)
 	udf.RegisterGlobalUDF("avg", avgFunc)
@@ -76,10 +113,21 @@ func init() {
 	udf.RegisterGlobalUDF("max", maxFunc)
 	udf.RegisterGlobalUDF("median", medianFunc)
 	udf.RegisterGlobalUDF("min", minFunc)
+	udf.RegisterGlobalUDAF("percentile", percentileFunc)
 	udf.RegisterGlobalUDF("string_agg", stringAggFunc)
 	udf.RegisterGlobalUDF("sum", sumFunc)
 	// conversion functions
 	udf.RegisterGlobalUDF("blob_to_raw_string", udf.MustConvertGeneric(blobToRawString))
 	// other functions
 	udf.RegisterGlobalUDF("coalesce", coalesceFunc)
+
+	// stream functions
+	udf.MustRegisterGlobalUDSFCreator("debounce",
+		udf.MustConvertToUDSFCreator(createDebounceUDSF))
+	udf.MustRegisterGlobalUDSFCreator("deduplicate",
+		udf.MustConvertToUDSFCreator(createDeduplicateUDSF))
+	udf.MustRegisterGlobalUDSFCreator("unnest_array",
+		udf.MustConvertToUDSFCreator(createUnnestArrayUDSF))
+	udf.MustRegisterGlobalUDSFCreator("throttle",
+		udf.MustConvertToUDSFCreator(createThrottleUDSF))
 }
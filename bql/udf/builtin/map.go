@@ -0,0 +1,168 @@
+package builtin
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// sortedMapKeys returns keys of a map in ascending order.
+func sortedMapKeys(m data.Map) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mapKeysFunc returns the keys of a map in ascending order.
+//
+// It can be used in BQL as `map_keys`.
+//
+//  Input: Map
+//  Return Type: Array of String
+var mapKeysFunc udf.UDF = udf.UnaryFunc(func(ctx *core.Context, arg data.Value) (data.Value, error) {
+	if arg.Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	m, err := data.AsMap(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a map", arg)
+	}
+	res := make(data.Array, 0, len(m))
+	for _, k := range sortedMapKeys(m) {
+		res = append(res, data.String(k))
+	}
+	return res, nil
+})
+
+// mapValuesFunc returns the values of a map in the ascending order of
+// their keys so that the result corresponds to the one of map_keys.
+//
+// It can be used in BQL as `map_values`.
+//
+//  Input: Map
+//  Return Type: Array
+var mapValuesFunc udf.UDF = udf.UnaryFunc(func(ctx *core.Context, arg data.Value) (data.Value, error) {
+	if arg.Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	m, err := data.AsMap(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a map", arg)
+	}
+	res := make(data.Array, 0, len(m))
+	for _, k := range sortedMapKeys(m) {
+		res = append(res, m[k])
+	}
+	return res, nil
+})
+
+// mapMergeFunc merges all maps given as input arguments into a new map.
+// When more than one map has the same key, the value in the last map is
+// used. Maps are merged shallowly. Null values are ignored, non-map
+// arguments lead to an error.
+//
+// It can be used in BQL as `map_merge`.
+//
+//  Input: n * Map
+//  Return Type: Map
+var mapMergeFunc udf.UDF = &variadicFunc{
+	minParams: 1,
+	varFun: func(args ...data.Value) (data.Value, error) {
+		res := data.Map{}
+		for _, item := range args {
+			switch item.Type() {
+			case data.TypeNull:
+				continue
+			case data.TypeMap:
+				m, _ := data.AsMap(item)
+				for k, v := range m {
+					res[k] = v
+				}
+			default:
+				return nil, fmt.Errorf("%v is not a map", item)
+			}
+		}
+		return res, nil
+	},
+}
+
+// mapKeyArgs converts arguments of map_pick and map_omit into a set of keys.
+// Each argument can be a String or an Array of Strings.
+func mapKeyArgs(args []data.Value) (map[string]bool, error) {
+	keys := map[string]bool{}
+	for _, arg := range args {
+		switch arg.Type() {
+		case data.TypeNull:
+			continue
+		case data.TypeString:
+			k, _ := data.AsString(arg)
+			keys[k] = true
+		case data.TypeArray:
+			a, _ := data.AsArray(arg)
+			for _, e := range a {
+				k, err := data.AsString(e)
+				if err != nil {
+					return nil, fmt.Errorf("cannot interpret %s as a string", e)
+				}
+				keys[k] = true
+			}
+		default:
+			return nil, fmt.Errorf("cannot interpret %s as a string", arg)
+		}
+	}
+	return keys, nil
+}
+
+// newMapFilterFunc creates a function which creates a map only having
+// keys (not) given as arguments.
+func newMapFilterFunc(pick bool) udf.UDF {
+	return &variadicFunc{
+		minParams: 1,
+		varFun: func(args ...data.Value) (data.Value, error) {
+			if args[0].Type() == data.TypeNull {
+				return data.Null{}, nil
+			}
+			m, err := data.AsMap(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("%v is not a map", args[0])
+			}
+			keys, err := mapKeyArgs(args[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			res := data.Map{}
+			for k, v := range m {
+				if keys[k] == pick {
+					res[k] = v
+				}
+			}
+			return res, nil
+		},
+	}
+}
+
+// mapPickFunc(m, keys...) returns a new map only having the given keys.
+// Keys can be given as Strings or Arrays of Strings. Keys which don't exist
+// in the map are ignored.
+//
+// It can be used in BQL as `map_pick`.
+//
+//  Input: Map, n * (String or Array of String)
+//  Return Type: Map
+var mapPickFunc = newMapFilterFunc(true)
+
+// mapOmitFunc(m, keys...) returns a new map having all keys of the map
+// except the given keys. Keys can be given as Strings or Arrays of Strings.
+//
+// It can be used in BQL as `map_omit`.
+//
+//  Input: Map, n * (String or Array of String)
+//  Return Type: Map
+var mapOmitFunc = newMapFilterFunc(false)
@@ -0,0 +1,94 @@
+package builtin
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestMapFuncs(t *testing.T) {
+	m := data.Map{
+		"b": data.Int(2),
+		"a": data.String("x"),
+		"c": data.Map{"d": data.Null{}},
+	}
+
+	udfVariadicTestCases := []udfVariadicTestCase{
+		{"map_keys", mapKeysFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{m}, data.Array{data.String("a"), data.String("b"), data.String("c")}},
+			{[]data.Value{data.Map{}}, data.Array{}},
+			{[]data.Value{data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Array{}}, nil},
+		}},
+		{"map_values", mapValuesFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{m}, data.Array{data.String("x"), data.Int(2), data.Map{"d": data.Null{}}}},
+			{[]data.Value{data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.String("a")}, nil},
+		}},
+		{"map_merge", mapMergeFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{m, data.Null{}, data.Map{"b": data.Int(3), "e": data.True}},
+				data.Map{
+					"a": data.String("x"),
+					"b": data.Int(3),
+					"c": data.Map{"d": data.Null{}},
+					"e": data.True,
+				}},
+			{[]data.Value{data.Null{}}, data.Map{}},
+			// invalid cases
+			{[]data.Value{m, data.Array{}}, nil},
+		}},
+		{"map_pick", mapPickFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{m, data.String("a"), data.String("z")}, data.Map{"a": data.String("x")}},
+			{[]data.Value{m, data.Array{data.String("a"), data.String("b")}},
+				data.Map{"a": data.String("x"), "b": data.Int(2)}},
+			{[]data.Value{m}, data.Map{}},
+			{[]data.Value{data.Null{}, data.String("a")}, data.Null{}},
+			// invalid cases
+			{[]data.Value{m, data.Int(1)}, nil},
+			{[]data.Value{m, data.Array{data.Int(1)}}, nil},
+			{[]data.Value{data.Array{}, data.String("a")}, nil},
+		}},
+		{"map_omit", mapOmitFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{m, data.String("a"), data.Array{data.String("c")}}, data.Map{"b": data.Int(2)}},
+			{[]data.Value{m}, m},
+			// invalid cases
+			{[]data.Value{m, data.Bool(true)}, nil},
+		}},
+	}
+
+	for _, testCase := range udfVariadicTestCases {
+		f := testCase.f
+
+		Convey(fmt.Sprintf("Given the %s function", testCase.name), t, func() {
+			for _, tc := range testCase.inputs {
+				tc := tc
+
+				Convey(fmt.Sprintf("When evaluating it on %#v", tc.input), func() {
+					val, err := f.Call(nil, tc.input...)
+
+					if tc.expected == nil {
+						Convey("Then evaluation should fail", func() {
+							So(err, ShouldNotBeNil)
+						})
+					} else {
+						Convey(fmt.Sprintf("Then the result should be %s", tc.expected), func() {
+							So(err, ShouldBeNil)
+							So(val, ShouldResemble, tc.expected)
+						})
+					}
+				})
+			}
+
+			Convey("Then it should equal the one in the default registry", func() {
+				regFun, err := udf.CopyGlobalUDFRegistry(nil).Lookup(testCase.name, len(testCase.inputs[0].input))
+				So(err, ShouldBeNil)
+				So(regFun, ShouldHaveSameTypeAs, f)
+			})
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package builtin
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"unicode/utf8"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// defaultRedactedValue is the value which redact returns when a replacement
+// isn't given.
+const defaultRedactedValue = "[REDACTED]"
+
+type redactFuncTmpl struct {
+}
+
+func (f *redactFuncTmpl) Accept(arity int) bool {
+	return arity == 1 || arity == 2
+}
+
+func (f *redactFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *redactFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("function takes one or two arguments")
+	}
+	if args[0].Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	if len(args) == 2 {
+		return args[1], nil
+	}
+	return data.String(defaultRedactedValue), nil
+}
+
+// redactFunc(value, [replacement]) replaces a value with `replacement`,
+// which is "[REDACTED]" by default. NULL isn't replaced so that the absence
+// of the value can still be observed.
+//
+// It can be used in BQL as `redact`.
+//
+//  Input: Any, [Any]
+//  Return Type: Any
+var redactFunc udf.UDF = &redactFuncTmpl{}
+
+// asRedactionInput returns the string representation of a scalar value
+// given to mask or hash_field.
+func asRedactionInput(v data.Value) (string, error) {
+	switch v.Type() {
+	case data.TypeString, data.TypeInt, data.TypeFloat, data.TypeBool, data.TypeTimestamp:
+		return data.ToString(v)
+	case data.TypeBlob:
+		b, _ := data.AsBlob(v)
+		return string(b), nil
+	}
+	return "", fmt.Errorf("cannot interpret %s as a scalar value", v)
+}
+
+type maskFuncTmpl struct {
+}
+
+func (f *maskFuncTmpl) Accept(arity int) bool {
+	return arity >= 1 && arity <= 3
+}
+
+func (f *maskFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *maskFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("function takes one to three arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	s, err := asRedactionInput(args[0])
+	if err != nil {
+		return nil, err
+	}
+	keep := int64(0)
+	if len(args) >= 2 {
+		if args[1].Type() != data.TypeInt {
+			return nil, fmt.Errorf("cannot interpret %s as an integer", args[1])
+		}
+		keep, _ = data.AsInt(args[1])
+		if keep < 0 {
+			return nil, fmt.Errorf("the number of unmasked characters must not be negative")
+		}
+	}
+	maskChar := '*'
+	if len(args) == 3 {
+		c, err := data.AsString(args[2])
+		if err != nil {
+			return nil, fmt.Errorf("cannot interpret %s as a string", args[2])
+		}
+		if utf8.RuneCountInString(c) != 1 {
+			return nil, fmt.Errorf("the mask must be a single character: %v", c)
+		}
+		maskChar, _ = utf8.DecodeRuneInString(c)
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes)-int(keep); i++ {
+		runes[i] = maskChar
+	}
+	return data.String(runes), nil
+}
+
+// maskFunc(value, [keep_last], [mask]) replaces each character of the
+// string representation of a value with `mask` ("*" by default) except for
+// the last `keep_last` characters (0 by default), e.g.
+// `mask('4111111111111111', 4)` returns "************1111". The length of
+// the value is kept.
+//
+// It can be used in BQL as `mask`.
+//
+//  Input: String, Int, Float, Bool, Timestamp, or Blob, [Int], [String]
+//  Return Type: String
+var maskFunc udf.UDF = &maskFuncTmpl{}
+
+type hashFieldFuncTmpl struct {
+}
+
+func (f *hashFieldFuncTmpl) Accept(arity int) bool {
+	return arity >= 1 && arity <= 3
+}
+
+func (f *hashFieldFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *hashFieldFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, fmt.Errorf("function takes one to three arguments")
+	}
+	if hasNull(args...) {
+		return data.Null{}, nil
+	}
+	s, err := asRedactionInput(args[0])
+	if err != nil {
+		return nil, err
+	}
+	newHash := hashAlgorithms["sha256"]
+	if len(args) == 3 {
+		if newHash, err = lookupHashAlgorithm(args[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	var h hash.Hash
+	if len(args) >= 2 {
+		salt, err := asHashInput(args[1])
+		if err != nil {
+			return nil, err
+		}
+		h = hmac.New(newHash, salt)
+	} else {
+		h = newHash()
+	}
+	h.Write([]byte(s))
+	return data.String(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// hashFieldFunc(value, [salt], [algo]) pseudonymizes a value by hashing its
+// string representation. The same value always results in the same hash so
+// that the result can still be used to join or group tuples. When `salt` is
+// given, the HMAC of the value with `salt` as the key is computed, which
+// prevents the original value from being guessed by hashing candidates.
+// `algo` is one of "md5", "sha1", "sha256" (default), and "sha512".
+//
+// It can be used in BQL as `hash_field`.
+//
+//  Input: String, Int, Float, Bool, Timestamp, or Blob, [String or Blob], [String]
+//  Return Type: String
+var hashFieldFunc udf.UDF = &hashFieldFuncTmpl{}
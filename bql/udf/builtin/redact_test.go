@@ -0,0 +1,94 @@
+package builtin
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestRedactionFuncs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		f      udf.UDF
+		inputs []udfVariadicTestCaseInput
+	}{
+		{"redact", redactFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("alice@example.com")}, data.String("[REDACTED]")},
+			{[]data.Value{data.Map{"a": data.Int(1)}}, data.String("[REDACTED]")},
+			{[]data.Value{data.Int(1), data.String("***")}, data.String("***")},
+			{[]data.Value{data.Int(1), data.Int(0)}, data.Int(0)},
+			{[]data.Value{data.Null{}}, data.Null{}},
+		}},
+		{"mask", maskFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("secret")}, data.String("******")},
+			{[]data.Value{data.String("4111111111111111"), data.Int(4)}, data.String("************1111")},
+			{[]data.Value{data.Int(4111111111111111), data.Int(4), data.String("#")}, data.String("############1111")},
+			{[]data.Value{data.String("山田太郎"), data.Int(1), data.String("＊")}, data.String("＊＊＊郎")},
+			{[]data.Value{data.String("abc"), data.Int(10)}, data.String("abc")},
+			{[]data.Value{data.String("")}, data.String("")},
+			{[]data.Value{data.Null{}, data.Int(4)}, data.Null{}},
+			{[]data.Value{data.String("abc"), data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.String("abc"), data.Int(-1)}, nil},
+			{[]data.Value{data.String("abc"), data.Float(1)}, nil},
+			{[]data.Value{data.String("abc"), data.Int(1), data.String("**")}, nil},
+			{[]data.Value{data.Array{data.Int(1)}}, nil},
+		}},
+		{"hash_field", hashFieldFunc, []udfVariadicTestCaseInput{
+			{[]data.Value{data.String("abc")},
+				data.String("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")},
+			{[]data.Value{data.Int(123)},
+				data.String("a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")},
+			{[]data.Value{data.String("The quick brown fox jumps over the lazy dog"), data.String("key")},
+				data.String("f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")},
+			{[]data.Value{data.String("abc"), data.Blob(""), data.String("md5")},
+				data.String("dd2701993d29fdd0b032c233cec63403")},
+			{[]data.Value{data.Null{}}, data.Null{}},
+			{[]data.Value{data.String("abc"), data.Null{}}, data.Null{}},
+			// invalid cases
+			{[]data.Value{data.Map{}}, nil},
+			{[]data.Value{data.String("abc"), data.Int(1)}, nil},
+			{[]data.Value{data.String("abc"), data.String("key"), data.String("sha3")}, nil},
+		}},
+	}
+
+	for _, testCase := range testCases {
+		f := testCase.f
+
+		Convey(fmt.Sprintf("Given the %s function", testCase.name), t, func() {
+			for _, tc := range testCase.inputs {
+				tc := tc
+
+				Convey(fmt.Sprintf("When evaluating it on %#v", tc.input), func() {
+					So(f.Accept(len(tc.input)), ShouldBeTrue)
+					val, err := f.Call(nil, tc.input...)
+
+					if tc.expected == nil {
+						Convey("Then evaluation should fail", func() {
+							So(err, ShouldNotBeNil)
+						})
+					} else {
+						Convey(fmt.Sprintf("Then the result should be %s", tc.expected), func() {
+							So(err, ShouldBeNil)
+							So(val, ShouldResemble, tc.expected)
+						})
+					}
+				})
+			}
+
+			Convey("Then it should equal the one in the default registry", func() {
+				regFun, err := udf.CopyGlobalUDFRegistry(nil).Lookup(testCase.name, len(testCase.inputs[0].input))
+				So(err, ShouldBeNil)
+				So(regFun, ShouldHaveSameTypeAs, f)
+			})
+
+			Convey("Then it shouldn't accept too many arguments", func() {
+				So(f.Accept(0), ShouldBeFalse)
+				So(f.Accept(4), ShouldBeFalse)
+			})
+		})
+	}
+}
@@ -3,6 +3,7 @@ package builtin
 import (
 	"bytes"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/json"
@@ -11,6 +12,8 @@ import (
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"io"
+	"net"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -645,3 +648,275 @@ func decodeJSON(ctx *core.Context, v data.Value) (data.Value, error) {
 		return nil, fmt.Errorf("ill-formed JSON (starting with %c)", first)
 	}
 }
+
+// splitFunc(str, sep) splits `str` into all substrings separated
+// by `sep`. When `sep` is empty, `str` is split into characters.
+// See also: strings.Split
+//
+// It can be used in BQL as `split`.
+//
+//  Input: 2 * String
+//  Return Type: Array of String
+var splitFunc udf.UDF = &twoParamStringFunc{
+	strFun: func(str, sep string) data.Value {
+		parts := strings.Split(str, sep)
+		a := make(data.Array, len(parts))
+		for i, p := range parts {
+			a[i] = data.String(p)
+		}
+		return a
+	},
+}
+
+// startsWithFunc(str, prefix) returns true if `str` begins with `prefix`.
+// See also: strings.HasPrefix
+//
+// It can be used in BQL as `starts_with`.
+//
+//  Input: 2 * String
+//  Return Type: Bool
+var startsWithFunc udf.UDF = &twoParamStringFunc{
+	strFun: func(str, prefix string) data.Value {
+		return data.Bool(strings.HasPrefix(str, prefix))
+	},
+}
+
+// endsWithFunc(str, suffix) returns true if `str` ends with `suffix`.
+// See also: strings.HasSuffix
+//
+// It can be used in BQL as `ends_with`.
+//
+//  Input: 2 * String
+//  Return Type: Bool
+var endsWithFunc udf.UDF = &twoParamStringFunc{
+	strFun: func(str, suffix string) data.Value {
+		return data.Bool(strings.HasSuffix(str, suffix))
+	},
+}
+
+// levenshteinFunc(s1, s2) computes the Levenshtein distance between two
+// strings, i.e., the minimum number of single-character insertions,
+// deletions, or substitutions required to change `s1` into `s2`.
+//
+// It can be used in BQL as `levenshtein`.
+//
+//  Input: 2 * String
+//  Return Type: Int
+var levenshteinFunc udf.UDF = &twoParamStringFunc{
+	strFun: func(s1, s2 string) data.Value {
+		r1, r2 := []rune(s1), []rune(s2)
+		prev := make([]int, len(r2)+1)
+		cur := make([]int, len(r2)+1)
+		for j := range prev {
+			prev[j] = j
+		}
+		for i := 1; i <= len(r1); i++ {
+			cur[0] = i
+			for j := 1; j <= len(r2); j++ {
+				cost := 1
+				if r1[i-1] == r2[j-1] {
+					cost = 0
+				}
+				cur[j] = prev[j-1] + cost
+				if d := prev[j] + 1; d < cur[j] {
+					cur[j] = d
+				}
+				if d := cur[j-1] + 1; d < cur[j] {
+					cur[j] = d
+				}
+			}
+			prev, cur = cur, prev
+		}
+		return data.Int(prev[len(r2)])
+	},
+}
+
+type padFuncTmpl struct {
+	left bool
+}
+
+func (f *padFuncTmpl) Accept(arity int) bool {
+	return arity == 2 || arity == 3
+}
+
+func (f *padFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *padFuncTmpl) Call(ctx *core.Context, args ...data.Value) (val data.Value, err error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, fmt.Errorf("function takes two or three arguments")
+	}
+	s := ""
+	switch args[0].Type() {
+	default:
+		return nil, fmt.Errorf("cannot interpret %s as a string", args[0])
+	case data.TypeNull:
+		return data.Null{}, nil
+	case data.TypeString:
+		s, _ = data.AsString(args[0])
+	}
+	length := int64(0)
+	switch args[1].Type() {
+	default:
+		return nil, fmt.Errorf("cannot interpret %s as an integer", args[1])
+	case data.TypeNull:
+		return data.Null{}, nil
+	case data.TypeInt:
+		length, _ = data.AsInt(args[1])
+	}
+	fill := " "
+	if len(args) == 3 {
+		switch args[2].Type() {
+		default:
+			return nil, fmt.Errorf("cannot interpret %s as a string", args[2])
+		case data.TypeNull:
+			return data.Null{}, nil
+		case data.TypeString:
+			fill, _ = data.AsString(args[2])
+		}
+	}
+	if length < 0 {
+		length = 0
+	}
+
+	sRunes := []rune(s)
+	if int64(len(sRunes)) >= length {
+		return data.String(sRunes[:length]), nil
+	}
+	fillRunes := []rune(fill)
+	if len(fillRunes) == 0 {
+		return data.String(s), nil
+	}
+	padding := make([]rune, length-int64(len(sRunes)))
+	for i := range padding {
+		padding[i] = fillRunes[i%len(fillRunes)]
+	}
+	if f.left {
+		return data.String(string(padding) + s), nil
+	}
+	return data.String(s + string(padding)), nil
+}
+
+// lpadFunc(str, length, [fill]) fills up `str` to `length` characters
+// by prepending `fill` (a space by default). If `str` is already longer
+// than `length`, it's truncated on the right.
+// See also: SQL's `lpad(string, length, fill)`
+//
+// It can be used in BQL as `lpad`.
+//
+//  Input: String, Int, [String]
+//  Return Type: String
+var lpadFunc udf.UDF = &padFuncTmpl{left: true}
+
+// rpadFunc(str, length, [fill]) fills up `str` to `length` characters
+// by appending `fill` (a space by default). If `str` is already longer
+// than `length`, it's truncated.
+// See also: SQL's `rpad(string, length, fill)`
+//
+// It can be used in BQL as `rpad`.
+//
+//  Input: String, Int, [String]
+//  Return Type: String
+var rpadFunc udf.UDF = &padFuncTmpl{}
+
+// urlEncodeFunc escapes a string so that it can be safely placed
+// inside a URL query.
+// See also: net/url.QueryEscape
+//
+// It can be used in BQL as `url_encode`.
+//
+//  Input: String
+//  Return Type: String
+var urlEncodeFunc udf.UDF = &singleParamStringFunc{
+	strFun: func(s string) data.Value {
+		return data.String(url.QueryEscape(s))
+	},
+}
+
+// urlDecode is the inverse of url_encode. It returns an error if the string
+// contains an invalid escape sequence.
+func urlDecode(ctx *core.Context, v data.Value) (data.Value, error) {
+	switch v.Type() {
+	case data.TypeNull:
+		return data.Null{}, nil
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		res, err := url.QueryUnescape(s)
+		if err != nil {
+			return nil, err
+		}
+		return data.String(res), nil
+	default:
+		return nil, fmt.Errorf("cannot interpret %s as a string", v)
+	}
+}
+
+// parseURL parses a URL and returns a map having following fields:
+//
+//	* scheme: String
+//	* user: String (without password)
+//	* host: String (without port)
+//	* port: String
+//	* path: String
+//	* query: String (raw query)
+//	* params: Map of Array of String (decoded query parameters)
+//	* fragment: String
+//
+// Missing components are empty strings.
+func parseURL(ctx *core.Context, v data.Value) (data.Value, error) {
+	switch v.Type() {
+	case data.TypeNull:
+		return data.Null{}, nil
+	case data.TypeString:
+	default:
+		return nil, fmt.Errorf("cannot interpret %s as a string", v)
+	}
+	s, _ := data.AsString(v)
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	user := ""
+	if u.User != nil {
+		user = u.User.Username()
+	}
+	host, port := u.Host, ""
+	if h, p, err := net.SplitHostPort(u.Host); err == nil {
+		host, port = h, p
+	}
+	params := data.Map{}
+	for k, vs := range u.Query() {
+		a := make(data.Array, len(vs))
+		for i, v := range vs {
+			a[i] = data.String(v)
+		}
+		params[k] = a
+	}
+	return data.Map{
+		"scheme":   data.String(u.Scheme),
+		"user":     data.String(user),
+		"host":     data.String(host),
+		"port":     data.String(port),
+		"path":     data.String(u.Path),
+		"query":    data.String(u.RawQuery),
+		"params":   params,
+		"fragment": data.String(u.Fragment),
+	}, nil
+}
+
+// uuidFunc generates a random (version 4) UUID.
+//
+// It can be used in BQL as `uuid`.
+//
+//  Input: None
+//  Return Type: String
+var uuidFunc = udf.MustConvertGeneric(func() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+})
@@ -0,0 +1,348 @@
+package builtin
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// defaultThrottleMaxKeys is the default maximum number of keys the
+	// throttle and debounce UDSFs keep at once.
+	defaultThrottleMaxKeys = 100000
+)
+
+// keyedEntries has an entry for each key in the order the entries were last
+// updated. It isn't thread-safe.
+type keyedEntries struct {
+	order *list.List
+	index map[data.HashValue][]*list.Element
+}
+
+type keyedEntry struct {
+	key       data.Value
+	hash      data.HashValue
+	timestamp time.Time
+	tuple     *core.Tuple
+}
+
+func newKeyedEntries() *keyedEntries {
+	return &keyedEntries{
+		order: list.New(),
+		index: map[data.HashValue][]*list.Element{},
+	}
+}
+
+func (k *keyedEntries) len() int {
+	return k.order.Len()
+}
+
+func (k *keyedEntries) get(key data.Value, h data.HashValue) *keyedEntry {
+	for _, e := range k.index[h] {
+		if ke := e.Value.(*keyedEntry); data.Equal(ke.key, key) {
+			return ke
+		}
+	}
+	return nil
+}
+
+// touch moves the entry having the key to the back, or adds a new entry
+// when there's no entry having the key.
+func (k *keyedEntries) touch(key data.Value, h data.HashValue) *keyedEntry {
+	for _, e := range k.index[h] {
+		if ke := e.Value.(*keyedEntry); data.Equal(ke.key, key) {
+			k.order.MoveToBack(e)
+			return ke
+		}
+	}
+	ke := &keyedEntry{
+		key:  key,
+		hash: h,
+	}
+	k.index[h] = append(k.index[h], k.order.PushBack(ke))
+	return ke
+}
+
+// oldest returns the entry which was updated least recently.
+func (k *keyedEntries) oldest() *keyedEntry {
+	if e := k.order.Front(); e != nil {
+		return e.Value.(*keyedEntry)
+	}
+	return nil
+}
+
+// removeOldest removes the entry which was updated least recently.
+func (k *keyedEntries) removeOldest() *keyedEntry {
+	e := k.order.Front()
+	if e == nil {
+		return nil
+	}
+	k.order.Remove(e)
+	ke := e.Value.(*keyedEntry)
+
+	es := k.index[ke.hash]
+	for i, x := range es {
+		if x == e {
+			es = append(es[:i], es[i+1:]...)
+			break
+		}
+	}
+	if len(es) == 0 {
+		delete(k.index, ke.hash)
+	} else {
+		k.index[ke.hash] = es
+	}
+	return ke
+}
+
+// parseThrottleArgs parses arguments common to the throttle and debounce
+// UDSFs.
+func parseThrottleArgs(name, key string, period data.Value, params []data.Map) (data.Path, time.Duration, int, error) {
+	if len(params) > 1 {
+		return nil, 0, 0, errors.New("too many arguments")
+	}
+	path, err := data.CompilePath(key)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid key '%v': %v", key, err)
+	}
+	d, err := data.ToDuration(period)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%v cannot be converted to a duration: %v", name, err)
+	}
+	if d <= 0 {
+		return nil, 0, 0, fmt.Errorf("%v must be positive", name)
+	}
+
+	maxKeys := defaultThrottleMaxKeys
+	if len(params) == 1 {
+		for k, v := range params[0] {
+			switch k {
+			case "max_keys":
+				n, err := data.ToInt(v)
+				if err != nil {
+					return nil, 0, 0, fmt.Errorf("max_keys parameter cannot be converted to an integer: %v", err)
+				}
+				if n <= 0 {
+					return nil, 0, 0, errors.New("max_keys parameter must be positive")
+				}
+				maxKeys = int(n)
+			default:
+				return nil, 0, 0, fmt.Errorf("unknown parameter: %v", k)
+			}
+		}
+	}
+	return path, d, maxKeys, nil
+}
+
+// throttleUDSF emits at most one tuple for each key within an interval. The
+// first tuple having a key is emitted and following tuples having the key are
+// suppressed until the interval passes since the tuple was emitted (based on
+// the timestamp of tuples).
+//
+// It can be used in BQL as `throttle`:
+//
+//	throttle(stream, key, interval [, params])
+//
+// stream is the name of the input stream and key is a JSON Path of the key
+// in tuples. interval is in seconds when it's a number. A string is parsed by
+// time.ParseDuration. params is a map which can have following parameters:
+//
+//	* max_keys: the maximum number of keys to be remembered. When the number
+//	  of keys exceeds this limit, the key emitted least recently is forgotten
+//	  even if its interval hasn't passed. The default value is 100000.
+//
+// A key is forgotten when its interval passes. The status of the node
+// running this UDSF contains following fields:
+//
+//	* num_keys: the number of keys currently remembered
+//	* num_suppressed: the number of tuples suppressed
+//	* num_evicted: the number of keys forgotten due to max_keys
+//
+// Timestamps of tuples are assumed to be monotonically increasing.
+type throttleUDSF struct {
+	key      data.Path
+	interval time.Duration
+	maxKeys  int
+
+	m sync.Mutex
+
+	// keys has the timestamp of the last emitted tuple of each key.
+	keys *keyedEntries
+
+	numSuppressed int64
+	numEvicted    int64
+}
+
+var (
+	_ core.Statuser = &throttleUDSF{}
+)
+
+func createThrottleUDSF(decl udf.UDSFDeclarer, stream, key string, interval data.Value, params ...data.Map) (udf.UDSF, error) {
+	path, d, maxKeys, err := parseThrottleArgs("interval", key, interval, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := decl.Input(stream, nil); err != nil {
+		return nil, err
+	}
+	return &throttleUDSF{
+		key:      path,
+		interval: d,
+		maxKeys:  maxKeys,
+		keys:     newKeyedEntries(),
+	}, nil
+}
+
+func (f *throttleUDSF) Process(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+	k, err := t.Data.Get(f.key)
+	if err != nil {
+		// a tuple which doesn't have the key isn't throttled
+		return w.Write(ctx, t)
+	}
+
+	f.m.Lock()
+	for e := f.keys.oldest(); e != nil && t.Timestamp.Sub(e.timestamp) >= f.interval; e = f.keys.oldest() {
+		f.keys.removeOldest()
+	}
+	h := data.Hash(k)
+	if f.keys.get(k, h) != nil {
+		f.numSuppressed++
+		f.m.Unlock()
+		return nil
+	}
+	f.keys.touch(k, h).timestamp = t.Timestamp
+	for f.keys.len() > f.maxKeys {
+		f.keys.removeOldest()
+		f.numEvicted++
+	}
+	f.m.Unlock()
+	return w.Write(ctx, t)
+}
+
+func (f *throttleUDSF) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (f *throttleUDSF) Status() data.Map {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return data.Map{
+		"num_keys":       data.Int(f.keys.len()),
+		"num_suppressed": data.Int(f.numSuppressed),
+		"num_evicted":    data.Int(f.numEvicted),
+	}
+}
+
+// debounceUDSF emits a tuple having a key only after no other tuple having
+// the key arrives for a quiet period. When tuples having the same key arrive
+// in a burst, only the last one is emitted after the burst.
+//
+// It can be used in BQL as `debounce`:
+//
+//	debounce(stream, key, quiet_period [, params])
+//
+// stream is the name of the input stream and key is a JSON Path of the key
+// in tuples. quiet_period is in seconds when it's a number. A string is
+// parsed by time.ParseDuration. params is a map which can have following
+// parameters:
+//
+//	* max_keys: the maximum number of keys having pending tuples. When the
+//	  number of keys exceeds this limit, the pending tuple of the key which
+//	  was updated least recently is emitted even if its quiet period hasn't
+//	  passed. The default value is 100000.
+//
+// Time is measured by the timestamp of tuples, so a pending tuple is emitted
+// when a subsequent tuple having any key shows that the quiet period has
+// passed. Pending tuples are discarded when the UDSF terminates. The status
+// of the node running this UDSF contains following fields:
+//
+//	* num_keys: the number of keys having pending tuples
+//	* num_suppressed: the number of tuples replaced by following ones
+//	* num_evicted: the number of tuples emitted early due to max_keys
+//
+// Timestamps of tuples are assumed to be monotonically increasing.
+type debounceUDSF struct {
+	key     data.Path
+	quiet   time.Duration
+	maxKeys int
+
+	m sync.Mutex
+
+	// pending has the last tuple of each key.
+	pending *keyedEntries
+
+	numSuppressed int64
+	numEvicted    int64
+}
+
+var (
+	_ core.Statuser = &debounceUDSF{}
+)
+
+func createDebounceUDSF(decl udf.UDSFDeclarer, stream, key string, quietPeriod data.Value, params ...data.Map) (udf.UDSF, error) {
+	path, d, maxKeys, err := parseThrottleArgs("quiet_period", key, quietPeriod, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := decl.Input(stream, nil); err != nil {
+		return nil, err
+	}
+	return &debounceUDSF{
+		key:     path,
+		quiet:   d,
+		maxKeys: maxKeys,
+		pending: newKeyedEntries(),
+	}, nil
+}
+
+func (f *debounceUDSF) Process(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+	var ready []*core.Tuple
+	f.m.Lock()
+	for e := f.pending.oldest(); e != nil && t.Timestamp.Sub(e.timestamp) >= f.quiet; e = f.pending.oldest() {
+		ready = append(ready, f.pending.removeOldest().tuple)
+	}
+
+	if k, err := t.Data.Get(f.key); err != nil {
+		// a tuple which doesn't have the key isn't debounced
+		ready = append(ready, t)
+	} else {
+		e := f.pending.touch(k, data.Hash(k))
+		if e.tuple != nil {
+			f.numSuppressed++
+		}
+		e.tuple = t
+		e.timestamp = t.Timestamp
+		for f.pending.len() > f.maxKeys {
+			ready = append(ready, f.pending.removeOldest().tuple)
+			f.numEvicted++
+		}
+	}
+	f.m.Unlock()
+
+	for _, r := range ready {
+		if err := w.Write(ctx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *debounceUDSF) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (f *debounceUDSF) Status() data.Map {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return data.Map{
+		"num_keys":       data.Int(f.pending.len()),
+		"num_suppressed": data.Int(f.numSuppressed),
+		"num_evicted":    data.Int(f.numEvicted),
+	}
+}
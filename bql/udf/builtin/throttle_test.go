@@ -0,0 +1,181 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestThrottleUDSF(t *testing.T) {
+	ctx := core.NewContext(nil)
+	base := time.Date(2015, time.May, 1, 14, 27, 0, 0, time.UTC)
+
+	Convey("Given the throttle UDSF creator", t, func() {
+		c := udf.MustConvertToUDSFCreator(createThrottleUDSF)
+		decl := udf.NewUDSFDeclarer()
+
+		create := func(interval data.Value, params data.Map) *throttleUDSF {
+			args := []data.Value{data.String("s"), data.String("id"), interval}
+			if params != nil {
+				args = append(args, params)
+			}
+			f, err := c.CreateUDSF(ctx, decl, args...)
+			So(err, ShouldBeNil)
+			return f.(*throttleUDSF)
+		}
+
+		var emitted []int64
+		w := core.WriterFunc(func(ctx *core.Context, t *core.Tuple) error {
+			i, _ := data.AsInt(t.Data["seq"])
+			emitted = append(emitted, i)
+			return nil
+		})
+		process := func(f *throttleUDSF, ids ...data.Value) {
+			for i, id := range ids {
+				d := data.Map{"seq": data.Int(i)}
+				if id != nil {
+					d["id"] = id
+				}
+				t := core.NewTuple(d)
+				t.Timestamp = base.Add(time.Duration(i) * time.Second)
+				So(f.Process(ctx, t, w), ShouldBeNil)
+			}
+		}
+
+		Convey("When creating it with an interval", func() {
+			f := create(data.Float(2.5), nil)
+
+			Convey("Then it should declare the input stream", func() {
+				So(decl.ListInputs(), ShouldContainKey, "s")
+			})
+
+			Convey("Then it should emit at most one tuple for each key within the interval", func() {
+				process(f, data.Int(1), data.Int(2), data.Int(1), data.Int(1), data.Int(2),
+					data.Int(1), nil, data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 1, 3, 4, 6, 7})
+				So(f.Status(), ShouldResemble, data.Map{
+					"num_keys":       data.Int(1),
+					"num_suppressed": data.Int(2),
+					"num_evicted":    data.Int(0),
+				})
+			})
+		})
+
+		Convey("When creating it with an interval given as a string", func() {
+			f := create(data.String("1500ms"), nil)
+
+			Convey("Then it should use the interval", func() {
+				process(f, data.Int(1), data.Int(1), data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 2})
+			})
+		})
+
+		Convey("When creating it with max_keys", func() {
+			f := create(data.Int(10), data.Map{"max_keys": data.Int(2)})
+
+			Convey("Then it should evict the oldest key", func() {
+				process(f, data.Int(1), data.Int(2), data.Int(3), data.Int(2), data.Int(1))
+				So(emitted, ShouldResemble, []int64{0, 1, 2, 4})
+				So(f.Status()["num_keys"], ShouldEqual, data.Int(2))
+				So(f.Status()["num_evicted"], ShouldEqual, data.Int(2))
+			})
+		})
+
+		Convey("When creating it with invalid arguments", func() {
+			for _, args := range [][]data.Value{
+				{data.String("s"), data.String("id"), data.Int(0)},
+				{data.String("s"), data.String("id"), data.String("a")},
+				{data.String("s"), data.String("id"), data.Int(1), data.Map{"max_keys": data.Int(0)}},
+				{data.String("s"), data.String("id"), data.Int(1), data.Map{"no_such_param": data.Int(1)}},
+				{data.String("s"), data.String("id"), data.Int(1), data.Map{}, data.Map{}},
+				{data.String("s"), data.String("id["), data.Int(1)},
+			} {
+				_, err := c.CreateUDSF(ctx, udf.NewUDSFDeclarer(), args...)
+
+				Convey("Then it should fail: "+data.Array(args).String(), func() {
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
+
+func TestDebounceUDSF(t *testing.T) {
+	ctx := core.NewContext(nil)
+	base := time.Date(2015, time.May, 1, 14, 27, 0, 0, time.UTC)
+
+	Convey("Given the debounce UDSF creator", t, func() {
+		c := udf.MustConvertToUDSFCreator(createDebounceUDSF)
+		decl := udf.NewUDSFDeclarer()
+
+		create := func(params data.Map) *debounceUDSF {
+			args := []data.Value{data.String("s"), data.String("id"), data.Int(2)}
+			if params != nil {
+				args = append(args, params)
+			}
+			f, err := c.CreateUDSF(ctx, decl, args...)
+			So(err, ShouldBeNil)
+			return f.(*debounceUDSF)
+		}
+
+		var emitted []int64
+		w := core.WriterFunc(func(ctx *core.Context, t *core.Tuple) error {
+			i, _ := data.AsInt(t.Data["seq"])
+			emitted = append(emitted, i)
+			return nil
+		})
+		process := func(f *debounceUDSF, secs []int, ids ...data.Value) {
+			for i, id := range ids {
+				d := data.Map{"seq": data.Int(i)}
+				if id != nil {
+					d["id"] = id
+				}
+				t := core.NewTuple(d)
+				t.Timestamp = base.Add(time.Duration(secs[i]) * time.Second)
+				So(f.Process(ctx, t, w), ShouldBeNil)
+			}
+		}
+
+		Convey("When creating it with a quiet period", func() {
+			f := create(nil)
+
+			Convey("Then it should declare the input stream", func() {
+				So(decl.ListInputs(), ShouldContainKey, "s")
+			})
+
+			Convey("Then it should emit the last tuple of each burst after the quiet period", func() {
+				process(f, []int{0, 1, 1, 2, 5, 6, 9},
+					data.Int(1), data.Int(1), data.Int(2), data.Int(1), data.Int(1), nil, data.Int(3))
+				So(emitted, ShouldResemble, []int64{2, 3, 5, 4})
+				So(f.Status(), ShouldResemble, data.Map{
+					"num_keys":       data.Int(1),
+					"num_suppressed": data.Int(2),
+					"num_evicted":    data.Int(0),
+				})
+			})
+		})
+
+		Convey("When creating it with max_keys", func() {
+			f := create(data.Map{"max_keys": data.Int(2)})
+
+			Convey("Then it should emit the pending tuple of the oldest key early", func() {
+				process(f, []int{0, 0, 0, 0}, data.Int(1), data.Int(2), data.Int(1), data.Int(3))
+				So(emitted, ShouldResemble, []int64{1})
+				So(f.Status()["num_keys"], ShouldEqual, data.Int(2))
+				So(f.Status()["num_evicted"], ShouldEqual, data.Int(1))
+			})
+		})
+
+		Convey("When creating it with an invalid quiet period", func() {
+			_, err := c.CreateUDSF(ctx, udf.NewUDSFDeclarer(), data.String("s"), data.String("id"), data.Int(-1))
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,676 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// timeLayout is a compiled layout of timestamps.
+type timeLayout struct {
+	// parse is the layout given to time.Parse.
+	parse string
+
+	// chunks is the layout given to time.Format split by names of months
+	// and weekdays, which are replaced with the ones of a locale.
+	chunks []layoutChunk
+
+	// months and weekdays are the forms of names of months and weekdays
+	// in the layout, i.e. "January", "Jan", or "" when it doesn't have them.
+	months   string
+	weekdays string
+}
+
+type layoutChunk struct {
+	layout string
+	name   bool
+}
+
+// namedLayouts are layouts which can be specified by the names of the
+// constants in the time package.
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+}
+
+// strftimeDirectives are Go layouts corresponding to strftime directives.
+var strftimeDirectives = map[string]string{
+	"a":  "Mon",
+	"A":  "Monday",
+	"b":  "Jan",
+	"B":  "January",
+	"d":  "02",
+	"D":  "01/02/06",
+	"e":  "_2",
+	"F":  "2006-01-02",
+	"h":  "Jan",
+	"H":  "15",
+	"I":  "03",
+	"j":  "002",
+	"m":  "01",
+	"M":  "04",
+	"p":  "PM",
+	"R":  "15:04",
+	"S":  "05",
+	"T":  "15:04:05",
+	"y":  "06",
+	"Y":  "2006",
+	"z":  "-0700",
+	":z": "-07:00",
+	"Z":  "MST",
+	"%":  "%",
+}
+
+// compileLayout compiles a layout, which is one of the following:
+//
+//   - a strftime-style layout, e.g. "%Y-%m-%d %H:%M:%S.%f %z"
+//   - the name of a layout defined in the time package, e.g. "RFC3339"
+//   - a layout of the time package, e.g. "2006-01-02 15:04:05"
+//
+// A layout is regarded as strftime-style when it contains '%'. %f is the
+// fractional second, which must follow '.' or ','. It's formatted with 6
+// digits and parsed with any number of digits.
+func compileLayout(layout string) (*timeLayout, error) {
+	parse, format := layout, layout
+	if l, ok := namedLayouts[layout]; ok {
+		parse, format = l, l
+	} else if strings.Contains(layout, "%") {
+		var err error
+		if parse, format, err = strftimeToLayout(layout); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &timeLayout{parse: parse}
+	for len(format) > 0 {
+		i, name := nextLayoutName(format)
+		if i > 0 {
+			l.chunks = append(l.chunks, layoutChunk{layout: format[:i]})
+		}
+		if name == "" {
+			break
+		}
+		switch name {
+		case "January", "Jan":
+			l.months = name
+		case "Monday", "Mon":
+			l.weekdays = name
+		}
+		l.chunks = append(l.chunks, layoutChunk{layout: name, name: true})
+		format = format[i+len(name):]
+	}
+	return l, nil
+}
+
+// nextLayoutName returns the position and the value of the first name of
+// a month or a weekday in a Go layout. It returns len(layout) and an empty
+// string when the layout doesn't have one.
+func nextLayoutName(layout string) (int, string) {
+	for i := 0; i < len(layout); i++ {
+		for _, n := range []string{"January", "Jan", "Monday", "Mon"} {
+			if strings.HasPrefix(layout[i:], n) {
+				return i, n
+			}
+		}
+	}
+	return len(layout), ""
+}
+
+// strftimeToLayout converts a strftime-style layout to Go layouts for
+// parsing and formatting.
+func strftimeToLayout(s string) (string, string, error) {
+	parse, format := []byte{}, []byte{}
+	literal := []byte{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '%' {
+			if '0' <= c && c <= '9' {
+				return "", "", fmt.Errorf("layout cannot have digits outside directives: %v", s)
+			}
+			parse, format = append(parse, c), append(format, c)
+			literal = append(literal, c)
+			continue
+		}
+		literal = append(literal, 0)
+		if i+1 >= len(s) {
+			return "", "", fmt.Errorf("layout ends with an incomplete directive: %v", s)
+		}
+		d := s[i+1 : i+2]
+		if d == ":" && i+2 < len(s) {
+			d = s[i+1 : i+3]
+		}
+		i += len(d)
+
+		if d == "f" {
+			if len(format) == 0 || (format[len(format)-1] != '.' && format[len(format)-1] != ',') {
+				return "", "", fmt.Errorf("%%f must follow '.' or ',': %v", s)
+			}
+			parse, format = append(parse, "999999999"...), append(format, "000000"...)
+			continue
+		}
+		l, ok := strftimeDirectives[d]
+		if !ok {
+			return "", "", fmt.Errorf("unsupported directive %%%v: %v", d, s)
+		}
+		parse, format = append(parse, l...), append(format, l...)
+	}
+
+	// literals must not be taken as a part of Go's layouts
+	for _, l := range []string{"Jan", "Mon", "MST", "PM", "pm"} {
+		if strings.Contains(string(literal), l) {
+			return "", "", fmt.Errorf("layout cannot have %v outside directives: %v", l, s)
+		}
+	}
+	return string(parse), string(format), nil
+}
+
+// timeLocale has names of months and weekdays (starting on Sunday) in a
+// language.
+type timeLocale struct {
+	months        [12]string
+	shortMonths   [12]string
+	weekdays      [7]string
+	shortWeekdays [7]string
+}
+
+// timeLocales are locales supported by parse_timestamp and
+// format_timestamp. They're looked up by the language part of a locale,
+// e.g. "de" of "de_DE".
+var timeLocales = map[string]*timeLocale{
+	"en": {
+		months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+			"Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday",
+			"Friday", "Saturday"},
+		shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	},
+	"de": {
+		months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun",
+			"Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag",
+			"Freitag", "Samstag"},
+		shortWeekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	},
+	"es": {
+		months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun",
+			"jul", "ago", "sep", "oct", "nov", "dic"},
+		weekdays: [7]string{"domingo", "lunes", "martes", "miércoles", "jueves",
+			"viernes", "sábado"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	"fr": {
+		months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin",
+			"juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays: [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi",
+			"vendredi", "samedi"},
+		shortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	"it": {
+		months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu",
+			"lug", "ago", "set", "ott", "nov", "dic"},
+		weekdays: [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì",
+			"venerdì", "sabato"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	"ja": {
+		months: [12]string{"1月", "2月", "3月", "4月", "5月", "6月",
+			"7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月",
+			"7月", "8月", "9月", "10月", "11月", "12月"},
+		weekdays: [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日",
+			"金曜日", "土曜日"},
+		shortWeekdays: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	},
+}
+
+func lookupTimeLocale(name string) (*timeLocale, error) {
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "" || lang == "c" || lang == "posix" {
+		lang = "en"
+	}
+	l, ok := timeLocales[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported locale: %v", name)
+	}
+	return l, nil
+}
+
+// format formats t with the locale.
+func (l *timeLayout) format(t time.Time, loc *timeLocale) string {
+	b := make([]byte, 0, 32)
+	for _, c := range l.chunks {
+		if !c.name {
+			b = t.AppendFormat(b, c.layout)
+			continue
+		}
+		switch c.layout {
+		case "January":
+			b = append(b, loc.months[t.Month()-1]...)
+		case "Jan":
+			b = append(b, loc.shortMonths[t.Month()-1]...)
+		case "Monday":
+			b = append(b, loc.weekdays[t.Weekday()]...)
+		case "Mon":
+			b = append(b, loc.shortWeekdays[t.Weekday()]...)
+		}
+	}
+	return string(b)
+}
+
+// parseTime parses s in the location and the locale. Names of months and
+// weekdays in s are replaced with English ones before parsing.
+func (l *timeLayout) parseTime(s string, tz *time.Location, loc *timeLocale) (time.Time, error) {
+	if loc != timeLocales["en"] {
+		s = l.translate(s, loc)
+	}
+	return time.ParseInLocation(l.parse, s, tz)
+}
+
+func (l *timeLayout) translate(s string, loc *timeLocale) string {
+	en := timeLocales["en"]
+	type name struct {
+		local, en string
+	}
+	var names []name
+	if l.months != "" {
+		for i := range loc.months {
+			e := en.shortMonths[i]
+			if l.months == "January" {
+				e = en.months[i]
+			}
+			names = append(names, name{loc.months[i], e}, name{loc.shortMonths[i], e})
+		}
+	}
+	if l.weekdays != "" {
+		for i := range loc.weekdays {
+			e := en.shortWeekdays[i]
+			if l.weekdays == "Monday" {
+				e = en.weekdays[i]
+			}
+			names = append(names, name{loc.weekdays[i], e}, name{loc.shortWeekdays[i], e})
+		}
+	}
+	if len(names) == 0 {
+		return s
+	}
+
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		// the longest name matches so that a full name isn't taken as an
+		// abbreviation
+		var m *name
+		for j := range names {
+			n := &names[j]
+			if len(n.local) <= len(s)-i && strings.EqualFold(s[i:i+len(n.local)], n.local) &&
+				(m == nil || len(n.local) > len(m.local)) {
+				m = n
+			}
+		}
+		if m == nil {
+			b = append(b, s[i])
+			i++
+			continue
+		}
+		b = append(b, m.en...)
+		i += len(m.local)
+	}
+	return string(b)
+}
+
+// maxCachedTimeLayouts is the maximum number of compiled layouts and
+// locations held in the cache. It's cleared when it gets full, which
+// only happens when layouts are generated dynamically.
+const maxCachedTimeLayouts = 1024
+
+var timeLayoutCache = struct {
+	m         sync.Mutex
+	layouts   map[string]*timeLayout
+	locations map[string]*time.Location
+}{
+	layouts:   map[string]*timeLayout{},
+	locations: map[string]*time.Location{},
+}
+
+// cachedLayout returns the compiled layout from the cache.
+func cachedLayout(layout string) (*timeLayout, error) {
+	c := &timeLayoutCache
+	c.m.Lock()
+	defer c.m.Unlock()
+	if l, ok := c.layouts[layout]; ok {
+		return l, nil
+	}
+	l, err := compileLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.layouts) >= maxCachedTimeLayouts {
+		c.layouts = map[string]*timeLayout{}
+	}
+	c.layouts[layout] = l
+	return l, nil
+}
+
+// cachedLocation returns the location having the name, which is a name
+// of the IANA Time Zone database (e.g. "Asia/Tokyo"), "UTC", "Local", or
+// a fixed offset such as "+09:00" and "-0700". An empty name means UTC.
+func cachedLocation(name string) (*time.Location, error) {
+	switch name {
+	case "", "UTC", "Z":
+		return time.UTC, nil
+	case "Local":
+		return time.Local, nil
+	}
+	c := &timeLayoutCache
+	c.m.Lock()
+	defer c.m.Unlock()
+	if l, ok := c.locations[name]; ok {
+		return l, nil
+	}
+
+	var loc *time.Location
+	if name[0] == '+' || name[0] == '-' {
+		var t time.Time
+		var err error
+		for _, l := range []string{"-07:00", "-0700", "-07"} {
+			if t, err = time.Parse(l, name); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone offset: %v", name)
+		}
+		_, offset := t.Zone()
+		loc = time.FixedZone(name, offset)
+	} else {
+		l, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %v: %v", name, err)
+		}
+		loc = l
+	}
+	if len(c.locations) >= maxCachedTimeLayouts {
+		c.locations = map[string]*time.Location{}
+	}
+	c.locations[name] = loc
+	return loc, nil
+}
+
+// timeFormatArgs extracts the layout, the time zone, and the locale from
+// the arguments of parse_timestamp and format_timestamp. It returns a nil
+// layout when one of them is NULL.
+func timeFormatArgs(args []data.Value) (*timeLayout, *time.Location, *timeLocale, error) {
+	strs := make([]string, 3)
+	for i, a := range args[1:] {
+		switch a.Type() {
+		case data.TypeNull:
+			return nil, nil, nil, nil
+		case data.TypeString:
+			strs[i], _ = data.AsString(a)
+		default:
+			return nil, nil, nil, fmt.Errorf("cannot interpret %s as a string", a)
+		}
+	}
+	layout, err := cachedLayout(strs[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tz, err := cachedLocation(strs[1])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	loc, err := lookupTimeLocale(strs[2])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return layout, tz, loc, nil
+}
+
+type timeFormatFuncTmpl struct {
+	parse bool
+}
+
+func (f *timeFormatFuncTmpl) Accept(arity int) bool {
+	return arity >= 2 && arity <= 4
+}
+
+func (f *timeFormatFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *timeFormatFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, fmt.Errorf("function takes two to four arguments")
+	}
+	if args[0].Type() == data.TypeNull {
+		return data.Null{}, nil
+	}
+	layout, tz, loc, err := timeFormatArgs(args)
+	if err != nil {
+		return nil, err
+	} else if layout == nil {
+		return data.Null{}, nil
+	}
+
+	if f.parse {
+		s, err := data.AsString(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("cannot interpret %s as a string", args[0])
+		}
+		t, err := layout.parseTime(s, tz, loc)
+		if err != nil {
+			return nil, err
+		}
+		return data.Timestamp(t.In(time.UTC)), nil
+	}
+	t, err := data.AsTimestamp(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot interpret %s as a timestamp", args[0])
+	}
+	return data.String(layout.format(t.In(tz), loc)), nil
+}
+
+// parseTimestampFunc(value, layout, [tz, [locale]]) parses a string
+// having a timestamp in the layout. The layout is a strftime-style one
+// (e.g. "%Y-%m-%d %H:%M:%S"), the name of a layout defined in Go's time
+// package (e.g. "RFC3339"), or a layout of the package (e.g.
+// "2006-01-02 15:04:05"). tz is the time zone used when the value doesn't
+// have one, which is UTC by default. locale is the language of names of
+// months and weekdays, which is "en" by default.
+// See also: time.ParseInLocation
+//
+// It can be used in BQL as `parse_timestamp`.
+//
+//  Input: String, String, [String, [String]]
+//  Return Type: Timestamp
+var parseTimestampFunc udf.UDF = &timeFormatFuncTmpl{parse: true}
+
+// formatTimestampFunc(ts, layout, [tz, [locale]]) formats a timestamp in
+// the layout. The layout, tz, and locale are the same as
+// parse_timestamp's.
+// See also: time.Time.Format
+//
+// It can be used in BQL as `format_timestamp`.
+//
+//  Input: Timestamp, String, [String, [String]]
+//  Return Type: String
+var formatTimestampFunc udf.UDF = &timeFormatFuncTmpl{}
+
+// guessedLayouts are layouts tried by guess_timestamp in order.
+var guessedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04:05 -0700",
+	"2006-01-02",
+	"2006/01/02",
+	"20060102T150405Z0700",
+	"20060102T150405",
+	"02/Jan/2006:15:04:05 -0700",
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC850,
+	time.RFC822,
+	time.RFC822Z,
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+}
+
+// epochUnit returns the number of the unit of a time since the Unix
+// epoch in a second. The unit (seconds, milliseconds, microseconds, or
+// nanoseconds) is guessed by the magnitude so that timestamps from 1973 to
+// 5138 are converted correctly.
+func epochUnit(v float64) int64 {
+	a := math.Abs(v)
+	switch {
+	case a < 1e11:
+		return 1
+	case a < 1e14:
+		return 1e3
+	case a < 1e17:
+		return 1e6
+	}
+	return 1e9
+}
+
+func intEpochTimestamp(v int64) time.Time {
+	u := epochUnit(float64(v))
+	return time.Unix(v/u, v%u*(1e9/u)).In(time.UTC)
+}
+
+func floatEpochTimestamp(v float64) (time.Time, error) {
+	sec := v / float64(epochUnit(v))
+	if math.IsNaN(sec) || sec < data.MinConvFloat64 || sec > data.MaxConvFloat64 {
+		return time.Time{}, fmt.Errorf("cannot interpret %v as a timestamp", v)
+	}
+	i := math.Floor(sec)
+	return time.Unix(int64(i), int64(math.Round((sec-i)*1e9))).In(time.UTC), nil
+}
+
+// guessTimestamp parses a string having a timestamp in one of the
+// commonly used formats.
+func guessTimestamp(s string, tz *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return intEpochTimestamp(i), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return floatEpochTimestamp(f)
+	}
+	for _, l := range guessedLayouts {
+		if t, err := time.ParseInLocation(l, s, tz); err == nil {
+			return t.In(time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot interpret %v as a timestamp", s)
+}
+
+type guessTimestampFuncTmpl struct {
+}
+
+func (f *guessTimestampFuncTmpl) Accept(arity int) bool {
+	return arity == 1 || arity == 2
+}
+
+func (f *guessTimestampFuncTmpl) IsAggregationParameter(k int) bool {
+	return false
+}
+
+func (f *guessTimestampFuncTmpl) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("function takes one or two arguments")
+	}
+	tz := time.UTC
+	if len(args) == 2 {
+		switch args[1].Type() {
+		case data.TypeNull:
+			return data.Null{}, nil
+		case data.TypeString:
+			s, _ := data.AsString(args[1])
+			l, err := cachedLocation(s)
+			if err != nil {
+				return nil, err
+			}
+			tz = l
+		default:
+			return nil, fmt.Errorf("cannot interpret %s as a string", args[1])
+		}
+	}
+
+	var t time.Time
+	var err error
+	switch v := args[0].(type) {
+	case data.Null:
+		return data.Null{}, nil
+	case data.Timestamp:
+		return v, nil
+	case data.Int:
+		t = intEpochTimestamp(int64(v))
+	case data.Float:
+		t, err = floatEpochTimestamp(float64(v))
+	case data.String:
+		t, err = guessTimestamp(string(v), tz)
+	default:
+		return nil, fmt.Errorf("cannot interpret %s as a timestamp", args[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data.Timestamp(t), nil
+}
+
+// guessTimestampFunc(value, [tz]) converts a value from a device to a
+// timestamp by guessing its format. A number or a string having a number
+// is regarded as the time since the Unix epoch, whose unit (seconds,
+// milliseconds, microseconds, or nanoseconds) is guessed by its
+// magnitude. Other strings are parsed in one of the common formats such
+// as RFC 3339, "2006-01-02 15:04:05", "2006/01/02 15:04:05", RFC 1123,
+// and the one of Apache's access logs. tz is the time zone used when the
+// value doesn't have one, which is UTC by default.
+//
+// It can be used in BQL as `guess_timestamp`.
+//
+//  Input: Int, Float, String, or Timestamp, [String]
+//  Return Type: Timestamp
+var guessTimestampFunc udf.UDF = &guessTimestampFuncTmpl{}
@@ -0,0 +1,192 @@
+package builtin
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestTimestampLayoutFuncs(t *testing.T) {
+	ts := func(y int, mo time.Month, d, h, mi, s, ns int) data.Value {
+		return data.Timestamp(time.Date(y, mo, d, h, mi, s, ns, time.UTC))
+	}
+	str := func(ss ...string) []data.Value {
+		vs := make([]data.Value, len(ss))
+		for i, s := range ss {
+			vs[i] = data.String(s)
+		}
+		return vs
+	}
+	withTS := func(t data.Value, ss ...string) []data.Value {
+		return append([]data.Value{t}, str(ss...)...)
+	}
+
+	cases := []struct {
+		name  string
+		f     udf.UDF
+		cases []struct {
+			args     []data.Value
+			expected data.Value // nil means an error
+		}
+	}{
+		{"parse_timestamp", parseTimestampFunc, []struct {
+			args     []data.Value
+			expected data.Value
+		}{
+			{str("2015-04-10 10:23:45", "%Y-%m-%d %H:%M:%S"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("2015-04-10 10:23:45.123", "%F %T.%f"), ts(2015, time.April, 10, 10, 23, 45, 123000000)},
+			{str("2015-04-10 10:23:45", "%F %T.%f"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("10/Apr/2015:10:23:45 +0900", "%d/%b/%Y:%H:%M:%S %z"), ts(2015, time.April, 10, 1, 23, 45, 0)},
+			{str("Friday, April 10, 2015 10:23 PM", "%A, %B %e, %Y %I:%M %p"), ts(2015, time.April, 10, 22, 23, 0, 0)},
+			{str("100% 2015", "100%% %Y"), nil},
+			{str("% 2015", "%% %Y"), ts(2015, time.January, 1, 0, 0, 0, 0)},
+			{str("2015-04-10T10:23:45+09:00", "RFC3339"), ts(2015, time.April, 10, 1, 23, 45, 0)},
+			{str("2015-04-10 10:23:45", "2006-01-02 15:04:05"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			// time zones
+			{str("2015-04-10 10:23:45", "%F %T", "Asia/Tokyo"), ts(2015, time.April, 10, 1, 23, 45, 0)},
+			{str("2015-04-10 10:23:45", "%F %T", "-07:00"), ts(2015, time.April, 10, 17, 23, 45, 0)},
+			{str("2015-04-10 10:23:45 +0000", "%F %T %z", "Asia/Tokyo"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("2015-04-10 10:23:45", "%F %T", "Nowhere/Unknown"), nil},
+			{str("2015-04-10 10:23:45", "%F %T", "+9"), nil},
+			// locales
+			{str("Freitag, 10. April 2015", "%A, %d. %B %Y", "UTC", "de_DE"), ts(2015, time.April, 10, 0, 0, 0, 0)},
+			{str("10 MARS 2015", "%d %B %Y", "", "fr"), ts(2015, time.March, 10, 0, 0, 0, 0)},
+			{str("ven. 10 avr. 2015", "%a %d %b %Y", "", "fr"), ts(2015, time.April, 10, 0, 0, 0, 0)},
+			{str("2015年4月10日", "%Y年%B%d日", "", "ja"), ts(2015, time.April, 10, 0, 0, 0, 0)},
+			{str("10 April 2015", "%d %B %Y", "", "en_US.UTF-8"), ts(2015, time.April, 10, 0, 0, 0, 0)},
+			{str("10 April 2015", "%d %B %Y", "", "xx"), nil},
+			// invalid layouts and values
+			{str("2015-04-10", "%Y-%m-%d %H"), nil},
+			{str("2015", "%Q"), nil},
+			{str("2015", "%Y%"), nil},
+			{str("2015-04-10 10:23:45 123", "%F %T %f"), nil},
+			{str("2015 Mon", "%Y Mon"), nil},
+			{str("2015 1", "%Y 1"), nil},
+			{[]data.Value{data.Int(2015), data.String("%Y")}, nil},
+			{[]data.Value{data.String("2015"), data.Int(1)}, nil},
+			// NULL
+			{[]data.Value{data.Null{}, data.String("%Y")}, data.Null{}},
+			{[]data.Value{data.String("2015"), data.Null{}}, data.Null{}},
+			{[]data.Value{data.String("2015"), data.String("%Y"), data.Null{}}, data.Null{}},
+		}},
+		{"format_timestamp", formatTimestampFunc, []struct {
+			args     []data.Value
+			expected data.Value
+		}{
+			{withTS(ts(2015, time.April, 10, 1, 3, 5, 7000), "%Y-%m-%d %H:%M:%S.%f %z"),
+				data.String("2015-04-10 01:03:05.000007 +0000")},
+			{withTS(ts(2015, time.April, 10, 1, 3, 5, 0), "%D %e %j %I %p %%"),
+				data.String("04/10/15 10 100 01 AM %")},
+			{withTS(ts(2015, time.April, 10, 1, 3, 5, 0), "%a, %d %b %Y %H:%M:%S %Z", "Asia/Tokyo"),
+				data.String("Fri, 10 Apr 2015 10:03:05 JST")},
+			{withTS(ts(2015, time.April, 10, 1, 3, 5, 0), "%F %T%:z", "-07:00"),
+				data.String("2015-04-09 18:03:05-07:00")},
+			{withTS(ts(2015, time.April, 10, 1, 3, 5, 0), "RFC1123Z"),
+				data.String("Fri, 10 Apr 2015 01:03:05 +0000")},
+			{withTS(ts(2015, time.April, 10, 1, 3, 5, 0), "Monday 2006"),
+				data.String("Friday 2015")},
+			// locales
+			{withTS(ts(2015, time.March, 10, 1, 3, 5, 0), "%A, %d. %B %Y", "UTC", "de"),
+				data.String("Dienstag, 10. März 2015")},
+			{withTS(ts(2015, time.March, 10, 1, 3, 5, 0), "%a %d %b", "UTC", "fr"),
+				data.String("mar. 10 mars")},
+			{withTS(ts(2015, time.March, 10, 1, 3, 5, 0), "Mon 2 Jan", "UTC", "es"),
+				data.String("mar 10 mar")},
+			{withTS(ts(2015, time.March, 10, 1, 3, 5, 0), "%Y年%B%d日(%a)", "Asia/Tokyo", "ja_JP"),
+				data.String("2015年3月10日(火)")},
+			// invalid arguments
+			{str("2015-04-10", "%Y"), nil},
+			{withTS(ts(2015, time.March, 10, 1, 3, 5, 0), "%Y", "Nowhere/Unknown"), nil},
+			{[]data.Value{data.Null{}, data.String("%Y")}, data.Null{}},
+			{[]data.Value{ts(2015, time.March, 10, 1, 3, 5, 0), data.String("%Y"), data.String(""), data.Null{}},
+				data.Null{}},
+		}},
+		{"guess_timestamp", guessTimestampFunc, []struct {
+			args     []data.Value
+			expected data.Value
+		}{
+			{str("2015-04-10T10:23:45.123+09:00"), ts(2015, time.April, 10, 1, 23, 45, 123000000)},
+			{str("2015-04-10T10:23:45.5"), ts(2015, time.April, 10, 10, 23, 45, 500000000)},
+			{str("2015-04-10T10:23:45+0900"), ts(2015, time.April, 10, 1, 23, 45, 0)},
+			{str(" 2015-04-10 10:23:45 "), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("2015-04-10 10:23:45 -0700"), ts(2015, time.April, 10, 17, 23, 45, 0)},
+			{str("2015/04/10 10:23:45"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("2015-04-10"), ts(2015, time.April, 10, 0, 0, 0, 0)},
+			{str("20150410T102345Z"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("10/Apr/2015:10:23:45 +0900"), ts(2015, time.April, 10, 1, 23, 45, 0)},
+			{str("Fri, 10 Apr 2015 10:23:45 GMT"), ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{str("2015-04-10 10:23:45", "Asia/Tokyo"), ts(2015, time.April, 10, 1, 23, 45, 0)},
+			{str("10.04.2015"), nil},
+			{str("hoge"), nil},
+			// times since the Unix epoch
+			{[]data.Value{data.Int(1428661425)}, ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{[]data.Value{data.Int(1428661425123)}, ts(2015, time.April, 10, 10, 23, 45, 123000000)},
+			{[]data.Value{data.Int(1428661425123456)}, ts(2015, time.April, 10, 10, 23, 45, 123456000)},
+			{[]data.Value{data.Int(1428661425123456789)}, ts(2015, time.April, 10, 10, 23, 45, 123456789)},
+			{[]data.Value{data.Int(-1)}, ts(1969, time.December, 31, 23, 59, 59, 0)},
+			{[]data.Value{data.Float(1428661425.5)}, ts(2015, time.April, 10, 10, 23, 45, 500000000)},
+			{[]data.Value{data.Float(1428661425500)}, ts(2015, time.April, 10, 10, 23, 45, 500000000)},
+			{str("1428661425123"), ts(2015, time.April, 10, 10, 23, 45, 123000000)},
+			{str("1428661425.25"), ts(2015, time.April, 10, 10, 23, 45, 250000000)},
+			{[]data.Value{data.Float(1e300)}, nil},
+			// other types
+			{[]data.Value{ts(2015, time.April, 10, 10, 23, 45, 0)}, ts(2015, time.April, 10, 10, 23, 45, 0)},
+			{[]data.Value{data.Null{}}, data.Null{}},
+			{[]data.Value{data.String("2015-04-10"), data.Null{}}, data.Null{}},
+			{[]data.Value{data.Bool(true)}, nil},
+			{[]data.Value{data.String("2015-04-10"), data.Int(9)}, nil},
+		}},
+	}
+
+	for _, c := range cases {
+		c := c
+		Convey(fmt.Sprintf("Given the %s function", c.name), t, func() {
+			for _, tc := range c.cases {
+				tc := tc
+
+				Convey(fmt.Sprintf("When evaluating it on %v", tc.args), func() {
+					So(c.f.Accept(len(tc.args)), ShouldBeTrue)
+					val, err := c.f.Call(nil, tc.args...)
+
+					if tc.expected == nil {
+						Convey("Then evaluation should fail", func() {
+							So(err, ShouldNotBeNil)
+						})
+					} else {
+						Convey(fmt.Sprintf("Then the result should be %v", tc.expected), func() {
+							So(err, ShouldBeNil)
+							So(val, ShouldResemble, tc.expected)
+						})
+					}
+				})
+			}
+
+			Convey("Then it should equal the one in the default registry", func() {
+				regFun, err := udf.CopyGlobalUDFRegistry(nil).Lookup(c.name, 2)
+				So(err, ShouldBeNil)
+				So(regFun, ShouldHaveSameTypeAs, c.f)
+			})
+		})
+	}
+
+	Convey("Given the parse_timestamp function", t, func() {
+		Convey("Then it shouldn't accept too few or too many arguments", func() {
+			So(parseTimestampFunc.Accept(1), ShouldBeFalse)
+			So(parseTimestampFunc.Accept(5), ShouldBeFalse)
+			So(guessTimestampFunc.Accept(3), ShouldBeFalse)
+		})
+
+		Convey("Then compiled layouts should be cached", func() {
+			l1, err := cachedLayout("%Y-%m-%d")
+			So(err, ShouldBeNil)
+			l2, err := cachedLayout("%Y-%m-%d")
+			So(err, ShouldBeNil)
+			So(l1, ShouldPointTo, l2)
+			So(l1.parse, ShouldEqual, "2006-01-02")
+		})
+	})
+}
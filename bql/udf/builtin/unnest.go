@@ -0,0 +1,67 @@
+package builtin
+
+import (
+	"fmt"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// unnestArrayUDSF expands an array in a tuple into multiple tuples. It emits
+// one tuple for each element of the array. Each tuple has the same fields as
+// the input tuple except that the array is replaced with the element.
+//
+// It can be used in BQL as `unnest_array`:
+//
+//	unnest_array(stream, path)
+//
+// stream is the name of the input stream and path is a JSON Path of the array
+// in tuples. Tuples not having the array or having an empty array don't
+// result in any output. It's an error when the value isn't an array or null.
+//
+// UNNEST(stream, path) in a FROM clause is a shorthand of this UDSF, e.g.
+// `FROM UNNEST(s, items) [RANGE 1 TUPLES]` is the same as
+// `FROM unnest_array("s", "items") [RANGE 1 TUPLES] AS s`.
+type unnestArrayUDSF struct {
+	path data.Path
+}
+
+func createUnnestArrayUDSF(decl udf.UDSFDeclarer, stream, path string) (udf.UDSF, error) {
+	p, err := data.CompilePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path '%v': %v", path, err)
+	}
+	if err := decl.Input(stream, nil); err != nil {
+		return nil, err
+	}
+	return &unnestArrayUDSF{
+		path: p,
+	}, nil
+}
+
+func (f *unnestArrayUDSF) Process(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+	v, err := t.Data.Get(f.path)
+	if err != nil || v.Type() == data.TypeNull {
+		return nil
+	}
+	a, err := data.AsArray(v)
+	if err != nil {
+		return fmt.Errorf("%v is not an array", v)
+	}
+
+	for _, e := range a {
+		out := t.Copy()
+		if err := out.Data.Set(f.path, e); err != nil {
+			return err
+		}
+		if err := w.Write(ctx, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *unnestArrayUDSF) Terminate(ctx *core.Context) error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestUnnestArrayUDSF(t *testing.T) {
+	ctx := core.NewContext(nil)
+
+	Convey("Given the unnest_array UDSF", t, func() {
+		c := udf.MustConvertToUDSFCreator(createUnnestArrayUDSF)
+		decl := udf.NewUDSFDeclarer()
+		f, err := c.CreateUDSF(ctx, decl, data.String("s"), data.String("a.b"))
+		So(err, ShouldBeNil)
+		So(decl.ListInputs(), ShouldContainKey, "s")
+
+		var out []*core.Tuple
+		w := core.WriterFunc(func(ctx *core.Context, t *core.Tuple) error {
+			out = append(out, t)
+			return nil
+		})
+
+		Convey("When processing a tuple having an array", func() {
+			t := core.NewTuple(data.Map{
+				"id": data.Int(1),
+				"a":  data.Map{"b": data.Array{data.Int(10), data.String("x")}},
+			})
+			So(f.Process(ctx, t, w), ShouldBeNil)
+
+			Convey("Then it should emit a tuple for each element", func() {
+				So(len(out), ShouldEqual, 2)
+				So(out[0].Data, ShouldResemble, data.Map{
+					"id": data.Int(1),
+					"a":  data.Map{"b": data.Int(10)},
+				})
+				So(out[1].Data, ShouldResemble, data.Map{
+					"id": data.Int(1),
+					"a":  data.Map{"b": data.String("x")},
+				})
+			})
+
+			Convey("Then the input tuple should not be modified", func() {
+				So(t.Data["a"], ShouldResemble, data.Map{"b": data.Array{data.Int(10), data.String("x")}})
+			})
+		})
+
+		Convey("When processing tuples without elements", func() {
+			for _, d := range []data.Map{
+				{"id": data.Int(1)},
+				{"a": data.Map{"b": data.Null{}}},
+				{"a": data.Map{"b": data.Array{}}},
+			} {
+				So(f.Process(ctx, core.NewTuple(d), w), ShouldBeNil)
+			}
+
+			Convey("Then it should emit nothing", func() {
+				So(out, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When processing a tuple having a non-array value", func() {
+			err := f.Process(ctx, core.NewTuple(data.Map{"a": data.Map{"b": data.Int(1)}}), w)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given the unnest_array UDSF creator", t, func() {
+		c := udf.MustConvertToUDSFCreator(createUnnestArrayUDSF)
+
+		Convey("When creating it with an invalid path", func() {
+			_, err := c.CreateUDSF(ctx, udf.NewUDSFDeclarer(), data.String("s"), data.String("a["))
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
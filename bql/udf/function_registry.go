@@ -178,7 +178,7 @@ func (fr *defaultFunctionRegistry) Register(name string, f UDF) error {
 	switch lowerName {
 	case "count", "avg", "max", "min", "sum",
 		"coalesce", "lower", "upper", "octet_length",
-		"substring":
+		"substring", "mask":
 		// skip check
 	default:
 		if err := core.ValidateSymbol(name); err != nil {
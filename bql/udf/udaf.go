@@ -0,0 +1,177 @@
+package udf
+
+import (
+	"fmt"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// UDAF is an interface having a user defined aggregate function. Unlike an
+// aggregate function implemented as a UDF, which receives all values of a
+// group as arrays at once, a UDAF receives values row by row through
+// UDAFState:
+//
+//	type sumState struct {
+//		sum int64
+//	}
+//
+//	func (s *sumState) Accumulate(ctx *core.Context, args ...data.Value) error {
+//		i, err := data.AsInt(args[0])
+//		s.sum += i
+//		return err
+//	}
+//
+//	func (s *sumState) Merge(ctx *core.Context, other udf.UDAFState) error {
+//		s.sum += other.(*sumState).sum
+//		return nil
+//	}
+//
+//	func (s *sumState) Result(ctx *core.Context) (data.Value, error) {
+//		return data.Int(s.sum), nil
+//	}
+//
+// A UDAF is registered with RegisterGlobalUDAF, or converted to a UDF with
+// UDAFToUDF, and can be used in GROUP BY queries like built-in aggregate
+// functions.
+type UDAF interface {
+	// Accept checks if the function accepts the given number of arguments
+	// excluding core.Context.
+	Accept(arity int) bool
+
+	// IsAggregationParameter returns true if the k-th parameter expects
+	// aggregated values. It must return true for at least one parameter.
+	IsAggregationParameter(k int) bool
+
+	// Init returns a new state of the aggregation of a group.
+	Init(ctx *core.Context) (UDAFState, error)
+}
+
+// UDAFState is a state of the aggregation of a group created by UDAF.Init.
+// Methods of a state are never called concurrently, but different states of
+// the same UDAF can be used concurrently.
+type UDAFState interface {
+	// Accumulate adds a row to the state. args have the values of all
+	// parameters of the function for the row. Values of non-aggregation
+	// parameters are the same in all rows of the group.
+	Accumulate(ctx *core.Context, args ...data.Value) error
+
+	// Merge adds all rows accumulated in another state created by the same
+	// UDAF. The rows in the other state follow the ones in this state, so
+	// that the order of rows is kept when a group is aggregated in parallel.
+	// The other state isn't used after Merge is called.
+	Merge(ctx *core.Context, other UDAFState) error
+
+	// Result returns the result of the aggregation. It can be called more
+	// than once, and Accumulate can be called after Result.
+	Result(ctx *core.Context) (data.Value, error)
+}
+
+// UDAFUDF is a UDF converted from a UDAF by UDAFToUDF.
+type UDAFUDF interface {
+	UDF
+
+	// UDAF returns the original UDAF.
+	UDAF() UDAF
+}
+
+// UDAFToUDF converts a UDAF to a UDF, which can be registered to a
+// FunctionManager. The UDF receives values of aggregation parameters as
+// arrays having one value per row and returns the result of the
+// aggregation of all rows.
+func UDAFToUDF(f UDAF) UDAFUDF {
+	return &udafFunc{f}
+}
+
+type udafFunc struct {
+	f UDAF
+}
+
+func (u *udafFunc) UDAF() UDAF {
+	return u.f
+}
+
+func (u *udafFunc) Accept(arity int) bool {
+	return u.f.Accept(arity)
+}
+
+func (u *udafFunc) IsAggregationParameter(k int) bool {
+	return u.f.IsAggregationParameter(k)
+}
+
+func (u *udafFunc) Call(ctx *core.Context, args ...data.Value) (data.Value, error) {
+	n, err := NumUDAFRows(u.f, args)
+	if err != nil {
+		return nil, err
+	}
+	s, err := u.f.Init(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := AccumulateUDAF(ctx, u.f, s, args, 0, n); err != nil {
+		return nil, err
+	}
+	return s.Result(ctx)
+}
+
+// NumUDAFRows returns the number of rows in arguments of a UDF converted
+// from the UDAF. All aggregation parameters must be arrays having the same
+// length.
+func NumUDAFRows(f UDAF, args []data.Value) (int, error) {
+	n := -1
+	for i, a := range args {
+		if !f.IsAggregationParameter(i) {
+			continue
+		}
+		arr, err := data.AsArray(a)
+		if err != nil {
+			return 0, fmt.Errorf("aggregation parameter %v must be an array: %v", i, a.Type())
+		}
+		if n >= 0 && len(arr) != n {
+			return 0, fmt.Errorf("aggregation parameters have different numbers of rows: %v and %v",
+				n, len(arr))
+		}
+		n = len(arr)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("the function doesn't have an aggregation parameter")
+	}
+	return n, nil
+}
+
+// AccumulateUDAF accumulates rows from begin (inclusive) to end (exclusive)
+// of arguments of a UDF converted from the UDAF into the state. The number
+// of rows must be checked by NumUDAFRows in advance.
+func AccumulateUDAF(ctx *core.Context, f UDAF, s UDAFState, args []data.Value, begin, end int) error {
+	row := make([]data.Value, len(args))
+	for i, a := range args {
+		if !f.IsAggregationParameter(i) {
+			row[i] = a
+		}
+	}
+	for r := begin; r < end; r++ {
+		for i, a := range args {
+			if f.IsAggregationParameter(i) {
+				row[i] = a.(data.Array)[r]
+			}
+		}
+		if err := s.Accumulate(ctx, row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterGlobalUDAF adds a UDAF which is visible to all topologies in the
+// same way as RegisterGlobalUDF.
+func RegisterGlobalUDAF(name string, f UDAF) error {
+	return globalUDFRegistry.Register(name, UDAFToUDF(f))
+}
+
+// MustRegisterGlobalUDAF is like RegisterGlobalUDAF but
+// panics if an error occurred.
+func MustRegisterGlobalUDAF(name string, f UDAF) {
+	if err := RegisterGlobalUDAF(name, f); err != nil {
+		panic(fmt.Errorf("udf.MustRegisterGlobalUDAF: cannot register '%v': %v", name, err))
+	}
+}
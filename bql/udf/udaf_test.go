@@ -0,0 +1,139 @@
+package udf
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// rowsUDAF collects rows of all parameters.
+type rowsUDAF struct{}
+
+func (f *rowsUDAF) Accept(arity int) bool {
+	return arity == 3
+}
+
+func (f *rowsUDAF) IsAggregationParameter(k int) bool {
+	return k != 1
+}
+
+func (f *rowsUDAF) Init(ctx *core.Context) (UDAFState, error) {
+	return &rowsState{}, nil
+}
+
+type rowsState struct {
+	rows data.Array
+}
+
+func (s *rowsState) Accumulate(ctx *core.Context, args ...data.Value) error {
+	if args[0].Type() == data.TypeNull {
+		return core.NotExistError(fmt.Errorf("null"))
+	}
+	s.rows = append(s.rows, data.Array(args).Copy())
+	return nil
+}
+
+func (s *rowsState) Merge(ctx *core.Context, other UDAFState) error {
+	s.rows = append(s.rows, other.(*rowsState).rows...)
+	return nil
+}
+
+func (s *rowsState) Result(ctx *core.Context) (data.Value, error) {
+	return s.rows, nil
+}
+
+func TestUDAF(t *testing.T) {
+	Convey("Given a UDAF converted to a UDF", t, func() {
+		f := UDAFToUDF(&rowsUDAF{})
+
+		Convey("Then it should behave as an aggregate function", func() {
+			So(f.Accept(3), ShouldBeTrue)
+			So(f.Accept(2), ShouldBeFalse)
+			So(f.IsAggregationParameter(0), ShouldBeTrue)
+			So(f.IsAggregationParameter(1), ShouldBeFalse)
+			So(f.UDAF(), ShouldHaveSameTypeAs, &rowsUDAF{})
+		})
+
+		Convey("When calling it with arrays of aggregation parameters", func() {
+			v, err := f.Call(nil, data.Array{data.Int(1), data.Int(2)}, data.String("x"),
+				data.Array{data.Int(3), data.Int(4)})
+
+			Convey("Then the state should receive rows", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, data.Array{
+					data.Array{data.Int(1), data.String("x"), data.Int(3)},
+					data.Array{data.Int(2), data.String("x"), data.Int(4)},
+				})
+			})
+		})
+
+		Convey("When calling it with an empty group", func() {
+			v, err := f.Call(nil, data.Array{}, data.String("x"), data.Array{})
+
+			Convey("Then the result of the initial state should be returned", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, data.Array(nil))
+			})
+		})
+
+		Convey("When calling it with invalid arguments", func() {
+			Convey("Then it should fail", func() {
+				_, err := f.Call(nil, data.Array{data.Int(1), data.Int(2)}, data.String("x"),
+					data.Array{data.Int(3)})
+				So(err, ShouldNotBeNil)
+				_, err = f.Call(nil, data.Int(1), data.String("x"), data.Array{data.Int(3)})
+				So(err, ShouldNotBeNil)
+				_, err = f.Call(nil, data.Array{data.Null{}}, data.String("x"), data.Array{data.Int(3)})
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When accumulating a part of rows", func() {
+			args := []data.Value{data.Array{data.Int(1), data.Int(2), data.Int(3)}, data.Null{},
+				data.Array{data.Int(4), data.Int(5), data.Int(6)}}
+			n, err := NumUDAFRows(f.UDAF(), args)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, 3)
+			s1, err := f.UDAF().Init(nil)
+			So(err, ShouldBeNil)
+			s2, err := f.UDAF().Init(nil)
+			So(err, ShouldBeNil)
+			So(AccumulateUDAF(nil, f.UDAF(), s1, args, 0, 1), ShouldBeNil)
+			So(AccumulateUDAF(nil, f.UDAF(), s2, args, 1, 3), ShouldBeNil)
+
+			Convey("Then merged states should have all rows in order", func() {
+				So(s1.Merge(nil, s2), ShouldBeNil)
+				v, err := s1.Result(nil)
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, data.Array{
+					data.Array{data.Int(1), data.Null{}, data.Int(4)},
+					data.Array{data.Int(2), data.Null{}, data.Int(5)},
+					data.Array{data.Int(3), data.Null{}, data.Int(6)},
+				})
+			})
+		})
+	})
+
+	// the global registry cannot be reset
+	regErr := RegisterGlobalUDAF("test_collect_rows", &rowsUDAF{})
+	Convey("Given a UDAF registered globally", t, func() {
+		So(regErr, ShouldBeNil)
+
+		Convey("Then it should be looked up as an aggregate UDF", func() {
+			f, err := CopyGlobalUDFRegistry(nil).Lookup("test_collect_rows", 3)
+			So(err, ShouldBeNil)
+			So(f, ShouldImplement, (*UDAFUDF)(nil))
+			So(f.IsAggregationParameter(0), ShouldBeTrue)
+		})
+
+		Convey("Then registering the same name again should fail", func() {
+			So(RegisterGlobalUDAF("test_collect_rows", &rowsUDAF{}), ShouldNotBeNil)
+			So(func() {
+				MustRegisterGlobalUDAF("test_collect_rows", &rowsUDAF{})
+			}, ShouldPanic)
+		})
+	})
+}
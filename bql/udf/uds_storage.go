@@ -213,3 +213,25 @@ func (w *inMemoryUDSStorageWriter) Abort() error {
 	w.buf = nil
 	return nil
 }
+
+type udsCheckpointStorage struct {
+	s UDSStorage
+}
+
+// NewCheckpointStorage creates a core.CheckpointStorage storing checkpoints
+// in the UDSStorage. An entry of a checkpoint is stored as a state having
+// the name of the entry with the tag "checkpoint_" followed by the kind of
+// the entry, such as "checkpoint_box".
+func NewCheckpointStorage(s UDSStorage) core.CheckpointStorage {
+	return &udsCheckpointStorage{
+		s: s,
+	}
+}
+
+func (s *udsCheckpointStorage) Save(topology, kind, name string) (core.CheckpointWriter, error) {
+	return s.s.Save(topology, name, "checkpoint_"+kind)
+}
+
+func (s *udsCheckpointStorage) Load(topology, kind, name string) (io.ReadCloser, error) {
+	return s.s.Load(topology, name, "checkpoint_"+kind)
+}
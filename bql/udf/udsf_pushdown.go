@@ -0,0 +1,88 @@
+package udf
+
+import (
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// UDSFFilterOp is a comparison operator of a UDSFFilter.
+type UDSFFilterOp string
+
+const (
+	// UDSFFilterEqual is "=".
+	UDSFFilterEqual UDSFFilterOp = "="
+
+	// UDSFFilterNotEqual is "!=".
+	UDSFFilterNotEqual UDSFFilterOp = "!="
+
+	// UDSFFilterLess is "<".
+	UDSFFilterLess UDSFFilterOp = "<"
+
+	// UDSFFilterLessOrEqual is "<=".
+	UDSFFilterLessOrEqual UDSFFilterOp = "<="
+
+	// UDSFFilterGreater is ">".
+	UDSFFilterGreater UDSFFilterOp = ">"
+
+	// UDSFFilterGreaterOrEqual is ">=".
+	UDSFFilterGreaterOrEqual UDSFFilterOp = ">="
+
+	// UDSFFilterIsNull is "IS NULL". Value of the filter is data.Null.
+	UDSFFilterIsNull UDSFFilterOp = "IS NULL"
+
+	// UDSFFilterIsNotNull is "IS NOT NULL". Value of the filter is data.Null.
+	UDSFFilterIsNotNull UDSFFilterOp = "IS NOT NULL"
+)
+
+// UDSFFilter is a condition in the WHERE clause of a statement which
+// compares a column of tuples emitted by a UDSF with a constant value, e.g.
+// "col > 10" is a UDSFFilter{Column: "col", Op: UDSFFilterGreater,
+// Value: data.Int(10)}.
+type UDSFFilter struct {
+	// Column is the name of the top-level field of tuples emitted by the
+	// UDSF.
+	Column string
+
+	// Op is the comparison operator. The column is always on the left side
+	// of the operator.
+	Op UDSFFilterOp
+
+	// Value is the constant value compared with the column.
+	Value data.Value
+
+	// Accepted is set to true by UDSFPushdownCreator when the UDSF only
+	// emits tuples satisfying the filter.
+	Accepted bool
+}
+
+// UDSFPushdown has filters and columns which a statement can push down to a
+// UDSF in its FROM clause so that the UDSF can avoid emitting tuples which
+// would be discarded by the statement. For example, a UDSF reading rows from
+// a database can translate them to a WHERE clause and a column list of SQL.
+type UDSFPushdown struct {
+	// Filters are conditions combined with AND in the WHERE clause of the
+	// statement. Conditions which cannot be expressed as UDSFFilter aren't
+	// included.
+	Filters []*UDSFFilter
+
+	// Columns are top-level fields of tuples which the statement refers to.
+	// The UDSF can omit other fields. It's nil when the statement requires
+	// all fields, e.g. when it has "*" in its projections.
+	Columns []string
+}
+
+// UDSFPushdownCreator is implemented by UDSFCreators accepting filters and
+// columns pushed down by statements.
+//
+// The statement still evaluates the whole WHERE clause, so accepting filters
+// only reduces the number of tuples emitted by the UDSF and doesn't change
+// the result of the statement. The UDSF mustn't drop tuples which satisfy
+// all accepted filters.
+type UDSFPushdownCreator interface {
+	UDSFCreator
+
+	// CreateUDSFWithPushdown is called instead of UDSFCreator.CreateUDSF.
+	// It creates a UDSF in the same way as CreateUDSF does, and sets
+	// UDSFFilter.Accepted of filters applied by the UDSF.
+	CreateUDSFWithPushdown(ctx *core.Context, decl UDSFDeclarer, pd *UDSFPushdown, args ...data.Value) (UDSF, error)
+}
@@ -0,0 +1,210 @@
+package bql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/execution"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// udsfPushdown analyzes the SELECT statement and returns filters and columns
+// which can be pushed down to the UDSF in its FROM clause.
+func (tb *TopologyBuilder) udsfPushdown(stmt *parser.SelectStmt, rel *parser.AliasedStreamWindowAST) *udf.UDSFPushdown {
+	alias := rel.Alias
+	if alias == "" {
+		alias = rel.Name
+	}
+	// A column without a relation refers to the only input relation.
+	implicit := len(stmt.Relations) == 1 && len(stmt.StateJoins) == 0
+	refersTo := func(r string) bool {
+		return r == alias || (r == "" && implicit)
+	}
+
+	pd := &udf.UDSFPushdown{}
+	if stmt.Filter != nil {
+		for _, c := range splitConjunction(stmt.Filter) {
+			if f := tb.udsfFilter(c, refersTo); f != nil {
+				pd.Filters = append(pd.Filters, f)
+			}
+		}
+	}
+
+	// Columns referred by UDSF arguments don't have to be taken into account
+	// because they're always foldable.
+	s := *stmt
+	s.Relations = nil
+	cols := map[string]bool{}
+	if !collectUDSFColumns(reflect.ValueOf(s), refersTo, cols) {
+		return pd
+	}
+	pd.Columns = make([]string, 0, len(cols))
+	for c := range cols {
+		pd.Columns = append(pd.Columns, c)
+	}
+	sort.Strings(pd.Columns)
+	return pd
+}
+
+// logUDSFPushdown logs filters accepted by the UDSF and columns pushed down
+// to it.
+func (tb *TopologyBuilder) logUDSFPushdown(name string, pd *udf.UDSFPushdown) {
+	var accepted []string
+	for _, f := range pd.Filters {
+		if !f.Accepted {
+			continue
+		}
+		if f.Op == udf.UDSFFilterIsNull || f.Op == udf.UDSFFilterIsNotNull {
+			accepted = append(accepted, fmt.Sprintf("%v %v", f.Column, f.Op))
+		} else {
+			accepted = append(accepted, fmt.Sprintf("%v %v %v", f.Column, f.Op, f.Value))
+		}
+	}
+	tb.topology.Context().Log().WithFields(logrus.Fields{
+		"udsf":             name,
+		"offered_filters":  len(pd.Filters),
+		"accepted_filters": accepted,
+		"columns":          pd.Columns,
+	}).Debug("Pushed down filters and columns to the UDSF")
+}
+
+// splitConjunction splits an expression combined with AND.
+func splitConjunction(e parser.Expression) []parser.Expression {
+	b, ok := e.(parser.BinaryOpAST)
+	if !ok || b.Op != parser.And {
+		return []parser.Expression{e}
+	}
+	return append(splitConjunction(b.Left), splitConjunction(b.Right)...)
+}
+
+// udsfFilter converts a comparison between a top-level column of the UDSF
+// and a foldable expression to a UDSFFilter. It returns nil when the
+// expression cannot be converted.
+func (tb *TopologyBuilder) udsfFilter(e parser.Expression, refersTo func(string) bool) *udf.UDSFFilter {
+	b, ok := e.(parser.BinaryOpAST)
+	if !ok {
+		return nil
+	}
+	col, other, flipped := b.Left, b.Right, false
+	if _, ok := col.(parser.RowValue); !ok {
+		col, other, flipped = b.Right, b.Left, true
+	}
+	rv, ok := col.(parser.RowValue)
+	if !ok || !refersTo(rv.Relation) || !isTopLevelColumn(rv.Column) {
+		return nil
+	}
+
+	var op udf.UDSFFilterOp
+	switch b.Op {
+	case parser.Is, parser.IsNot:
+		if _, ok := other.(parser.NullLiteral); !ok || flipped {
+			return nil
+		}
+		op = udf.UDSFFilterIsNull
+		if b.Op == parser.IsNot {
+			op = udf.UDSFFilterIsNotNull
+		}
+		return &udf.UDSFFilter{Column: rv.Column, Op: op, Value: data.Null{}}
+	case parser.Equal:
+		op = udf.UDSFFilterEqual
+	case parser.NotEqual:
+		op = udf.UDSFFilterNotEqual
+	case parser.Less:
+		op = flipUDSFFilterOp(udf.UDSFFilterLess, udf.UDSFFilterGreater, flipped)
+	case parser.LessOrEqual:
+		op = flipUDSFFilterOp(udf.UDSFFilterLessOrEqual, udf.UDSFFilterGreaterOrEqual, flipped)
+	case parser.Greater:
+		op = flipUDSFFilterOp(udf.UDSFFilterGreater, udf.UDSFFilterLess, flipped)
+	case parser.GreaterOrEqual:
+		op = flipUDSFFilterOp(udf.UDSFFilterGreaterOrEqual, udf.UDSFFilterLessOrEqual, flipped)
+	default:
+		return nil
+	}
+	if !other.Foldable() {
+		return nil
+	}
+	v, err := execution.EvaluateFoldable(other, tb.Reg)
+	if err != nil || v.Type() == data.TypeNull {
+		// Comparing with NULL never satisfies the condition, but it's left
+		// to the statement.
+		return nil
+	}
+	return &udf.UDSFFilter{Column: rv.Column, Op: op, Value: v}
+}
+
+// flipUDSFFilterOp returns flippedOp when the column is on the right side of
+// the operator.
+func flipUDSFFilterOp(op, flippedOp udf.UDSFFilterOp, flipped bool) udf.UDSFFilterOp {
+	if flipped {
+		return flippedOp
+	}
+	return op
+}
+
+// isTopLevelColumn returns true when the column isn't a path like "a.b" or
+// "a[0]".
+func isTopLevelColumn(c string) bool {
+	return c != "" && !strings.ContainsAny(c, ".[]")
+}
+
+// collectUDSFColumns collects top-level columns of the UDSF referred by
+// expressions in v. It returns false when the UDSF must emit all columns.
+func collectUDSFColumns(v reflect.Value, refersTo func(string) bool, cols map[string]bool) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return true
+		}
+		return collectUDSFColumns(v.Elem(), refersTo, cols)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !collectUDSFColumns(v.Index(i), refersTo, cols) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if !collectUDSFColumns(v.MapIndex(k), refersTo, cols) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+	default:
+		return true
+	}
+
+	if !v.CanInterface() {
+		return true
+	}
+	switch e := v.Interface().(type) {
+	case parser.RowValue:
+		if !refersTo(e.Relation) {
+			return true
+		}
+		c := e.Column
+		if i := strings.IndexAny(c, ".["); i >= 0 {
+			c = c[:i]
+		}
+		if c == "" {
+			return false
+		}
+		cols[c] = true
+		return true
+	case parser.Wildcard:
+		return e.Relation != "" && !refersTo(e.Relation)
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if !collectUDSFColumns(v.Field(i), refersTo, cols) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package bql
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type pushdownUDSFCreator struct {
+	udf.UDSFCreator
+	pd *udf.UDSFPushdown
+}
+
+func (c *pushdownUDSFCreator) CreateUDSFWithPushdown(ctx *core.Context, decl udf.UDSFDeclarer,
+	pd *udf.UDSFPushdown, args ...data.Value) (udf.UDSF, error) {
+	c.pd = pd
+	for _, f := range pd.Filters {
+		f.Accepted = f.Op == udf.UDSFFilterEqual
+	}
+	return c.CreateUDSF(ctx, decl, args...)
+}
+
+func TestUDSFPushdown(t *testing.T) {
+	Convey("Given a topology builder having a UDSF accepting pushdown", t, func() {
+		dt := newTestTopology()
+		Reset(func() {
+			dt.Stop()
+		})
+		tb, err := NewTopologyBuilder(dt)
+		So(err, ShouldBeNil)
+		So(addBQLToTopology(tb, `CREATE PAUSED SOURCE s TYPE dummy`), ShouldBeNil)
+
+		c := &pushdownUDSFCreator{UDSFCreator: udf.MustConvertToUDSFCreator(createSequenceUDSF)}
+		So(tb.UDSFCreators.Register("pushdown_sequence", c), ShouldBeNil)
+
+		Convey("When creating a stream filtering the UDSF", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT ISTREAM int, x.y AS y FROM
+				pushdown_sequence(4) [RANGE 2 SECONDS]
+				WHERE int = 2 AND 1 < int AND z IS NOT NULL AND int + 1 = 3 AND (w = 1 OR w = 2) AND v = 1 + 1`), ShouldBeNil)
+
+			Convey("Then comparisons with constants should be pushed down", func() {
+				So(c.pd.Filters, ShouldResemble, []*udf.UDSFFilter{
+					{Column: "int", Op: udf.UDSFFilterEqual, Value: data.Int(2), Accepted: true},
+					{Column: "int", Op: udf.UDSFFilterGreater, Value: data.Int(1)},
+					{Column: "z", Op: udf.UDSFFilterIsNotNull, Value: data.Null{}},
+					{Column: "v", Op: udf.UDSFFilterEqual, Value: data.Int(2), Accepted: true},
+				})
+			})
+
+			Convey("Then referred columns should be pushed down", func() {
+				So(c.pd.Columns, ShouldResemble, []string{"int", "v", "w", "x", "z"})
+			})
+		})
+
+		Convey("When creating a stream selecting all columns of the UDSF", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT ISTREAM * FROM
+				pushdown_sequence(4) [RANGE 2 SECONDS] WHERE int = 2`), ShouldBeNil)
+
+			Convey("Then all columns should be required", func() {
+				So(c.pd.Columns, ShouldBeNil)
+				So(c.pd.Filters, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When creating a stream joining the UDSF with another stream", func() {
+			So(addBQLToTopology(tb, `CREATE STREAM t AS SELECT ISTREAM p:int, s:a FROM
+				pushdown_sequence(4) [RANGE 2 SECONDS] AS p, s [RANGE 2 SECONDS]
+				WHERE p:int > s:a AND p:int <= 3 AND s:b = 1`), ShouldBeNil)
+
+			Convey("Then only conditions on the UDSF should be pushed down", func() {
+				So(c.pd.Filters, ShouldResemble, []*udf.UDSFFilter{
+					{Column: "int", Op: udf.UDSFFilterLessOrEqual, Value: data.Int(3)},
+				})
+				So(c.pd.Columns, ShouldResemble, []string{"int"})
+			})
+		})
+	})
+}
@@ -0,0 +1,232 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/response"
+)
+
+// Client is a high level client of the SensorBee API server. It wraps
+// Requester and provides methods to manage topologies and to issue BQL
+// statements. Client can be used concurrently.
+type Client struct {
+	r *Requester
+
+	// ReconnectInterval is the interval between reconnection attempts of a
+	// Cursor. The default value is 1 second.
+	ReconnectInterval time.Duration
+
+	// MaxReconnects is the maximum number of consecutive reconnection attempts
+	// of a Cursor when the connection is lost. A Cursor doesn't reconnect when
+	// it's 0, and it retries forever when it's negative.
+	MaxReconnects int
+}
+
+// New creates a new Client of the server at the given URL using the v1 API.
+func New(url string) (*Client, error) {
+	r, err := NewRequester(url, "v1")
+	if err != nil {
+		return nil, err
+	}
+	return NewWithRequester(r), nil
+}
+
+// NewWithRequester creates a new Client using the given Requester.
+func NewWithRequester(r *Requester) *Client {
+	return &Client{
+		r:                 r,
+		ReconnectInterval: 1 * time.Second,
+		MaxReconnects:     5,
+	}
+}
+
+// Requester returns the Requester used by the Client. It can be used to send
+// requests which the Client doesn't support.
+func (c *Client) Requester() *Requester {
+	return c.r
+}
+
+// APIError is returned when the server responded with an error.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code, Message, RequestID, and Meta are the error information returned
+	// from the server. See response.Error for details.
+	Code      string
+	Message   string
+	RequestID string
+	Meta      data.Map
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: %v (request_id: %v)", e.Code, e.Message, e.RequestID)
+}
+
+// Retriable returns true when the same request can succeed later without any
+// change. See response.ErrorCodes for codes of retriable errors.
+func (e *APIError) Retriable() bool {
+	c := response.LookupErrorCode(e.Code)
+	return c != nil && c.Retriable
+}
+
+// ErrorCode returns the error code of err when it's an *APIError, e.g.
+// response.BQLStmtParseErrorCode. It returns an empty string otherwise.
+func ErrorCode(err error) string {
+	if e, ok := err.(*APIError); ok {
+		return e.Code
+	}
+	return ""
+}
+
+// do sends a request and returns an *APIError when the server responded
+// with an error. The caller must close the response.
+func (c *Client) do(method Method, apiPath string, body interface{}) (*Response, error) {
+	res, err := c.r.Do(method, apiPath, body)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsError() {
+		return res, nil
+	}
+	defer res.Close()
+	e, err := res.Error()
+	if err != nil {
+		return nil, fmt.Errorf("the server returned an error (%v) and it cannot be read: %v",
+			res.Raw.Status, err)
+	}
+	return nil, &APIError{
+		StatusCode: res.Raw.StatusCode,
+		Code:       e.Code,
+		Message:    e.Message,
+		RequestID:  e.RequestID,
+		Meta:       e.Meta,
+	}
+}
+
+func topologyPath(name string, elems ...string) (string, error) {
+	// This is checked here to avoid sending a request to a wrong URL.
+	if err := core.ValidateSymbol(name); err != nil {
+		return "", fmt.Errorf("the name of the topology is invalid: %v", err)
+	}
+	return path.Join(append([]string{"topologies", name}, elems...)...), nil
+}
+
+// Topologies returns the names of topologies in the server.
+func (c *Client) Topologies() ([]string, error) {
+	res, err := c.do(Get, "topologies", nil)
+	if err != nil {
+		return nil, err
+	}
+	js := struct {
+		Topologies []*response.Topology `json:"topologies"`
+	}{}
+	if err := res.ReadJSON(&js); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(js.Topologies))
+	for i, t := range js.Topologies {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+// CreateTopology creates a new topology. config can be nil.
+func (c *Client) CreateTopology(name string, config map[string]interface{}) error {
+	body := map[string]interface{}{
+		"name": name,
+	}
+	if config != nil {
+		body["config"] = config
+	}
+	res, err := c.do(Post, "topologies", body)
+	if err != nil {
+		return err
+	}
+	return res.Close()
+}
+
+// DropTopology stops and removes a topology.
+func (c *Client) DropTopology(name string) error {
+	p, err := topologyPath(name)
+	if err != nil {
+		return err
+	}
+	res, err := c.do(Delete, p, nil)
+	if err != nil {
+		return err
+	}
+	return res.Close()
+}
+
+// QueryResult is the result of Client.Query. Cursor is set when the server
+// returned a stream, which happens when a SELECT statement is issued.
+// Otherwise, Body has the JSON response.
+type QueryResult struct {
+	Body   map[string]interface{}
+	Cursor *Cursor
+}
+
+// Query issues BQL statements to the topology. When the result has a
+// Cursor, the caller must close it.
+func (c *Client) Query(topology, queries string) (*QueryResult, error) {
+	p, err := topologyPath(topology, "queries")
+	if err != nil {
+		return nil, err
+	}
+	send := func() (*Response, error) {
+		return c.do(Post, p, map[string]interface{}{
+			"queries": queries,
+		})
+	}
+	res, err := send()
+	if err != nil {
+		return nil, err
+	}
+	if res.IsStream() {
+		cur, err := newCursor(c, res, send)
+		if err != nil {
+			res.Close()
+			return nil, err
+		}
+		return &QueryResult{Cursor: cur}, nil
+	}
+
+	var js map[string]interface{}
+	if err := res.ReadJSON(&js); err != nil {
+		return nil, err
+	}
+	return &QueryResult{Body: js}, nil
+}
+
+// Exec issues BQL statements which don't return a stream and returns the
+// JSON response.
+func (c *Client) Exec(topology, queries string) (map[string]interface{}, error) {
+	r, err := c.Query(topology, queries)
+	if err != nil {
+		return nil, err
+	}
+	if r.Cursor != nil {
+		r.Cursor.Close()
+		return nil, errors.New("the statement returned a stream, use Select instead")
+	}
+	return r.Body, nil
+}
+
+// Select issues a SELECT statement and returns a Cursor iterating over its
+// results. The caller must close the Cursor.
+func (c *Client) Select(topology, query string) (*Cursor, error) {
+	r, err := c.Query(topology, query)
+	if err != nil {
+		return nil, err
+	}
+	if r.Cursor == nil {
+		return nil, errors.New("the statement didn't return a stream")
+	}
+	return r.Cursor, nil
+}
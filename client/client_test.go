@@ -0,0 +1,245 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/server/response"
+)
+
+// fakeServer imitates the queries API of the server. SELECT statements
+// return a stream of JSONs.
+type fakeServer struct {
+	m         sync.Mutex
+	requests  int
+	breakAt   int  // the stream is disconnected before sending the breakAt-th JSON
+	breaks    int  // the number of times the stream is disconnected
+	notFound  bool // the topology doesn't exist
+	numValues int
+}
+
+func (f *fakeServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.m.Lock()
+	f.requests++
+	notFound := f.notFound
+	f.m.Unlock()
+
+	if notFound {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `{"error":{"code":"E0001","message":"not found","request_id":"1","meta":{}}}`)
+		return
+	}
+
+	switch {
+	case req.Method == "GET" && req.URL.Path == "/api/v1/topologies":
+		fmt.Fprint(rw, `{"topologies":[{"name":"a"},{"name":"b"}]}`)
+	case req.Method == "POST" && req.URL.Path == "/api/v1/topologies/test/queries":
+		var body map[string]string
+		json.NewDecoder(req.Body).Decode(&body)
+		if body["queries"] != "SELECT" {
+			fmt.Fprint(rw, `{"result":1}`)
+			return
+		}
+		f.stream(rw)
+	default:
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (f *fakeServer) stream(rw http.ResponseWriter) {
+	mw := multipart.NewWriter(rw)
+	rw.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	rw.WriteHeader(http.StatusOK)
+
+	f.m.Lock()
+	shouldBreak := f.breaks > 0
+	if shouldBreak {
+		f.breaks--
+	}
+	f.m.Unlock()
+
+	for i := 0; i < f.numValues; i++ {
+		if shouldBreak && i == f.breakAt {
+			// Returning without closing the multipart writer leaves the stream
+			// unfinished. The last JSON is lost because it isn't followed by
+			// a boundary.
+			rw.(http.Flusher).Flush()
+			return
+		}
+		w, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, `{"v":%v}`, i)
+		rw.(http.Flusher).Flush()
+	}
+	mw.Close()
+}
+
+func (f *fakeServer) numRequests() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.requests
+}
+
+func readAll(cur *Cursor) []interface{} {
+	var vs []interface{}
+	for cur.Next() {
+		vs = append(vs, cur.Value().(map[string]interface{})["v"])
+	}
+	return vs
+}
+
+func TestClient(t *testing.T) {
+	Convey("Given a server and a client", t, func() {
+		f := &fakeServer{numValues: 3}
+		s := httptest.NewServer(f)
+		Reset(s.Close)
+		c, err := New(s.URL)
+		So(err, ShouldBeNil)
+		c.ReconnectInterval = time.Millisecond
+
+		Convey("When listing topologies", func() {
+			ts, err := c.Topologies()
+
+			Convey("Then it should return their names", func() {
+				So(err, ShouldBeNil)
+				So(ts, ShouldResemble, []string{"a", "b"})
+			})
+		})
+
+		Convey("When the server returns an error", func() {
+			f.notFound = true
+			err := c.DropTopology("test")
+
+			Convey("Then it should be an APIError", func() {
+				So(err, ShouldHaveSameTypeAs, &APIError{})
+				e := err.(*APIError)
+				So(e.StatusCode, ShouldEqual, http.StatusNotFound)
+				So(e.Code, ShouldEqual, "E0001")
+				So(ErrorCode(err), ShouldEqual, response.ResourceNotFoundErrorCode)
+				So(e.Retriable(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When using an invalid topology name", func() {
+			_, err := c.Exec("in/valid", "EVAL 1;")
+
+			Convey("Then it should fail without sending a request", func() {
+				So(err, ShouldNotBeNil)
+				So(f.numRequests(), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When executing a statement", func() {
+			res, err := c.Exec("test", "EVAL")
+
+			Convey("Then it should return the body", func() {
+				So(err, ShouldBeNil)
+				So(res["result"], ShouldEqual, json.Number("1"))
+			})
+
+			Convey("Then Select should fail", func() {
+				_, err := c.Select("test", "EVAL")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When selecting a stream", func() {
+			cur, err := c.Select("test", "SELECT")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				cur.Close()
+			})
+
+			Convey("Then the cursor should read all values", func() {
+				So(readAll(cur), ShouldResemble, []interface{}{0.0, 1.0, 2.0})
+				So(cur.Err(), ShouldBeNil)
+				So(f.numRequests(), ShouldEqual, 1)
+			})
+
+			Convey("Then Exec should fail", func() {
+				_, err := c.Exec("test", "SELECT")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the stream is disconnected", func() {
+			f.breakAt = 2
+			f.breaks = 2
+			cur, err := c.Select("test", "SELECT")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				cur.Close()
+			})
+
+			Convey("Then the cursor should reconnect", func() {
+				vs := readAll(cur)
+				So(cur.Err(), ShouldBeNil)
+				So(vs, ShouldResemble, []interface{}{0.0, 0.0, 0.0, 1.0, 2.0})
+				So(f.numRequests(), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When the server goes down", func() {
+			f.breakAt = 2
+			f.breaks = 1
+			c.MaxReconnects = 2
+			cur, err := c.Select("test", "SELECT")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				cur.Close()
+			})
+			So(cur.Next(), ShouldBeTrue)
+			s.Close()
+
+			Convey("Then the cursor should fail after retrying", func() {
+				So(cur.Next(), ShouldBeFalse)
+				So(cur.Err(), ShouldNotBeNil)
+				So(cur.Err().Error(), ShouldContainSubstring, "lost")
+				So(f.numRequests(), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the topology is removed while reconnecting", func() {
+			f.breakAt = 2
+			f.breaks = 1
+			cur, err := c.Select("test", "SELECT")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				cur.Close()
+			})
+			So(cur.Next(), ShouldBeTrue)
+			f.m.Lock()
+			f.notFound = true
+			f.m.Unlock()
+
+			Convey("Then the cursor should fail without retrying", func() {
+				So(cur.Next(), ShouldBeFalse)
+				So(cur.Err(), ShouldHaveSameTypeAs, &APIError{})
+			})
+		})
+
+		Convey("When closing a cursor in the middle of the stream", func() {
+			f.numValues = 1000000
+			cur, err := c.Select("test", "SELECT")
+			So(err, ShouldBeNil)
+			So(cur.Next(), ShouldBeTrue)
+			So(cur.Close(), ShouldBeNil)
+
+			Convey("Then Next should return false", func() {
+				So(cur.Next(), ShouldBeFalse)
+				So(cur.Err(), ShouldBeNil)
+			})
+		})
+	})
+}
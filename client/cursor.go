@@ -0,0 +1,203 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Cursor iterates over JSONs in a stream response such as results of a
+// SELECT statement:
+//
+//	cur, err := c.Select("topology", "SELECT RSTREAM * FROM s [RANGE 1 TUPLES];")
+//	if err != nil {
+//		return err
+//	}
+//	defer cur.Close()
+//	for cur.Next() {
+//		fmt.Println(cur.Value())
+//	}
+//	if err := cur.Err(); err != nil {
+//		return err
+//	}
+//
+// When the connection is lost in the middle of the stream, the Cursor
+// reissues the statement according to Client.ReconnectInterval and
+// Client.MaxReconnects. Results emitted while the Cursor is reconnecting are
+// lost.
+//
+// Next, Value, and Err must be called from one goroutine. Close can be called
+// from any goroutine.
+type Cursor struct {
+	c    *Client
+	send func() (*Response, error)
+
+	ch    chan interface{}
+	value interface{}
+
+	// err is set before ch is closed.
+	err error
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newCursor(c *Client, res *Response, send func() (*Response, error)) (*Cursor, error) {
+	ch, err := res.ReadStreamJSON()
+	if err != nil {
+		return nil, err
+	}
+	cur := &Cursor{
+		c:    c,
+		send: send,
+		ch:   make(chan interface{}),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go cur.run(res, ch)
+	return cur, nil
+}
+
+func (cur *Cursor) run(res *Response, ch <-chan interface{}) {
+	defer close(cur.done)
+	defer close(cur.ch)
+
+	retries := 0
+	for {
+		if !cur.forward(res, ch) {
+			res.Close()
+			return
+		}
+		if !retryStream(res) {
+			cur.err = res.StreamError()
+			res.Close()
+			return
+		}
+		lastErr := res.StreamError()
+		if lastErr == nil {
+			lastErr = io.ErrUnexpectedEOF
+		}
+		res.Close()
+
+		res, ch = nil, nil
+		for res == nil {
+			if cur.c.MaxReconnects >= 0 && retries >= cur.c.MaxReconnects {
+				cur.err = fmt.Errorf("the connection to the server was lost: %v", lastErr)
+				return
+			}
+			retries++
+
+			select {
+			case <-cur.stop:
+				return
+			case <-time.After(cur.c.ReconnectInterval):
+			}
+
+			r, err := cur.send()
+			if err != nil {
+				if _, ok := err.(*APIError); ok {
+					cur.err = err
+					return
+				}
+				lastErr = err
+				continue
+			}
+			if !r.IsStream() {
+				r.Close()
+				cur.err = errors.New("the server didn't return a stream on reconnection")
+				return
+			}
+			c, err := r.ReadStreamJSON()
+			if err != nil {
+				r.Close()
+				lastErr = err
+				continue
+			}
+			res, ch = r, c
+		}
+		retries = 0
+	}
+}
+
+// forward sends JSONs in the stream to cur.ch until the stream is closed. It
+// returns false when the Cursor is closed.
+func (cur *Cursor) forward(res *Response, ch <-chan interface{}) bool {
+	for {
+		select {
+		case <-cur.stop:
+			return false
+		case v, ok := <-ch:
+			if !ok {
+				return true
+			}
+			select {
+			case <-cur.stop:
+				return false
+			case cur.ch <- v:
+			}
+		}
+	}
+}
+
+// retryStream returns true when the stream was interrupted before the server
+// finished it.
+func retryStream(res *Response) bool {
+	if res.StreamEnded() {
+		return false
+	}
+	err := res.StreamError()
+	return err == nil || err == io.ErrUnexpectedEOF || isNetError(err)
+}
+
+func isNetError(err error) bool {
+	type netError interface {
+		Timeout() bool
+		Temporary() bool
+	}
+	_, ok := err.(netError)
+	return ok
+}
+
+// Next moves the Cursor to the next JSON. It blocks until the next JSON
+// arrives and returns false when the stream ends, an error occurs, or the
+// Cursor is closed.
+func (cur *Cursor) Next() bool {
+	v, ok := <-cur.ch
+	if !ok {
+		return false
+	}
+	cur.value = v
+	return true
+}
+
+// Value returns the current JSON.
+func (cur *Cursor) Value() interface{} {
+	return cur.value
+}
+
+// Err returns an error which stopped the Cursor. It returns nil when the
+// stream ended normally or the Cursor was closed. Call it after Next returns
+// false.
+func (cur *Cursor) Err() error {
+	return cur.err
+}
+
+// C returns a channel receiving JSONs in the stream. It can be used instead of
+// Next to wait for other events at the same time. The channel is closed when
+// Next would return false.
+func (cur *Cursor) C() <-chan interface{} {
+	return cur.ch
+}
+
+// Close stops the Cursor and closes the connection. Close can be called more
+// than once.
+func (cur *Cursor) Close() error {
+	cur.closeOnce.Do(func() {
+		close(cur.stop)
+	})
+	<-cur.done
+	return nil
+}
@@ -24,6 +24,7 @@ type Response struct {
 	closeStream  chan struct{}
 	streamClosed chan struct{}
 	streamErr    error
+	streamEnded  bool
 
 	closed   bool
 	closeErr error
@@ -207,6 +208,7 @@ func (r *Response) ReadStreamJSON() (<-chan interface{}, error) {
 
 			} else if b, final := isBoundaryLine(line); b {
 				if final {
+					r.streamEnded = true
 					return
 				}
 				break
@@ -312,6 +314,7 @@ func (r *Response) ReadStreamJSON() (<-chan interface{}, error) {
 				}
 			}
 			if finalPart {
+				r.streamEnded = true
 				return
 			}
 		}
@@ -319,6 +322,14 @@ func (r *Response) ReadStreamJSON() (<-chan interface{}, error) {
 	return ch, nil
 }
 
+// StreamEnded returns true when the server finished the stream, i.e. the
+// stream had the final boundary. It returns false when the connection was
+// closed in the middle of the stream. Don't call this method before the
+// channel returned from ReadStreamJSON is closed.
+func (r *Response) StreamEnded() bool {
+	return r.streamEnded
+}
+
 // StreamError returns an error which occurred in a goroutine spawned from
 // ReadStreamJSON method. Don't call this method before the channel returned
 // from ReadStreamJSON is closed.
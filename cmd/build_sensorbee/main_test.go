@@ -64,6 +64,8 @@ commands:
 						"shell":    commandDetail{},
 						"topology": commandDetail{},
 						"runfile":  commandDetail{},
+						"validate": commandDetail{},
+						"test":     commandDetail{},
 					},
 					Version: version.Version,
 				}
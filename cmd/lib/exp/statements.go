@@ -19,14 +19,18 @@ type Statement struct {
 // statements are not supported:
 //
 //	* SELECT (CREATE STREAM is supported)
+//	* CREATE STREAM ROUTES
 //	* UPDATE SOURCE/SINK/STATE
 //	* DROP SOURCE/STREAM/SINK/STATE
+//	* RESUME STREAM
+//	* REPLAY SINK
 //	* SAVE/LOAD STATE (they are automatically saved)
 func NewStatement(s interface{}) (*Statement, error) {
 	switch s.(type) {
-	case parser.SelectStmt, parser.SelectUnionStmt,
+	case parser.SelectStmt, parser.SelectUnionStmt, parser.CreateStreamRoutesStmt,
 		parser.UpdateSourceStmt, parser.UpdateSinkStmt, parser.UpdateStateStmt,
 		parser.DropSourceStmt, parser.DropStreamStmt, parser.DropSinkStmt, parser.DropStateStmt,
+		parser.ResumeStreamStmt, parser.ReplaySinkStmt,
 		parser.SaveStateStmt, parser.LoadStateStmt, parser.LoadStateOrCreateStmt:
 		return nil, fmt.Errorf("statement isn't supported by sensorbee exp command: %v", s)
 	}
@@ -66,7 +70,7 @@ func inputFromSelect(stmt *parser.SelectStmt) ([]string, error) {
 	var names []string
 	for _, rel := range stmt.Relations {
 		switch rel.Type {
-		case parser.ActualStream:
+		case parser.ActualStream, parser.UnnestStream:
 			names = append(names, rel.Name)
 
 		case parser.UDSFStream:
@@ -3,7 +3,9 @@
 package run
 
 import (
+	"crypto/tls"
 	"fmt"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/pfnet/jasco.v1"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"gopkg.in/sensorbee/sensorbee.v0/server"
@@ -13,6 +15,8 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 )
 
 // SetUp sets up SensorBee's HTTP server. The URL or port ID is set with server
@@ -31,78 +35,89 @@ func SetUp() cli.Command {
 			Usage:  "file path of a config file in YAML format",
 			EnvVar: "SENSORBEE_CONFIG",
 		},
+		cli.StringFlag{
+			Name:  "service-name",
+			Value: "sensorbee",
+			Usage: "the name of the Windows service",
+		},
+		cli.BoolFlag{
+			Name:  "install-service",
+			Usage: "register the run command with the config as a Windows service and exit",
+		},
+		cli.BoolFlag{
+			Name:  "uninstall-service",
+			Usage: "remove the Windows service and exit",
+		},
+		cli.StringFlag{
+			Name:  "tls-cert-file",
+			Usage: "file path of the certificate of the server in PEM format to serve the API over TLS",
+		},
+		cli.StringFlag{
+			Name:  "tls-key-file",
+			Usage: "file path of the private key of the server in PEM format",
+		},
+		cli.StringFlag{
+			Name:  "tls-client-ca-file",
+			Usage: "file path of certificates of CAs in PEM format which verify client certificates",
+		},
 	}
 	return cmd
 }
 
-// Run run the HTTP server.
+// Run run the HTTP server. When the process is started by systemd with
+// Type=notify, the server notifies systemd of its readiness and sends
+// keep-alive messages of the watchdog while it responds to requests. When
+// the process is started as a Windows service, it reports its state to the
+// service control manager.
 func Run(c *cli.Context) error {
 	err := func() error {
-		var conf *config.Config
-		if c.IsSet("config") {
-			p := c.String("config")
-			in, err := ioutil.ReadFile(p)
-			if err != nil {
-				return fmt.Errorf("Cannot read the config file %v: %v", p, err)
-			}
-
-			var yml map[string]interface{}
-			if err := yaml.Unmarshal(in, &yml); err != nil {
-				return fmt.Errorf("Cannot parse the config file %v: %v", p, err)
+		name := c.String("service-name")
+		if c.Bool("install-service") {
+			var args []string
+			if c.IsSet("config") {
+				p, err := filepath.Abs(c.String("config"))
+				if err != nil {
+					return fmt.Errorf("Cannot get the absolute path of the config file: %v", err)
+				}
+				args = append(args, "--config", p)
 			}
-			m, err := data.NewMap(yml)
-			if err != nil {
-				return fmt.Errorf("The config file %v has invalid values: %v", p, err)
+			if c.IsSet("service-name") {
+				args = append(args, "--service-name", name)
 			}
-			c, err := config.New(m)
-			if err != nil {
-				return fmt.Errorf("Cannot apply the cnofig file %v: %v", p, err)
+			for _, f := range []string{"tls-cert-file", "tls-key-file", "tls-client-ca-file"} {
+				if !c.IsSet(f) {
+					continue
+				}
+				p, err := filepath.Abs(c.String(f))
+				if err != nil {
+					return fmt.Errorf("Cannot get the absolute path of --%v: %v", f, err)
+				}
+				args = append(args, "--"+f, p)
 			}
-			conf = c
-
-		} else {
-			// Currently there's no required parameters. However, when a required
-			// parameter is added to Config, remove this else block and add a
-			// default value like "/etc/sensorbee/config.yaml" to the config option.
-			c, err := config.New(data.Map{})
-			if err != nil {
-				return fmt.Errorf("Cannot apply the default config: %v", err)
+			if err := installService(name, args); err != nil {
+				return fmt.Errorf("Cannot install the service %v: %v", name, err)
 			}
-			conf = c
+			fmt.Fprintf(os.Stderr, "The service %v has been installed\n", name)
+			return nil
 		}
-
-		cgvars, err := server.SetUpContextGlobalVariables(conf)
-		if err != nil {
-			return fmt.Errorf("Cannot set up the server context: %v", err)
+		if c.Bool("uninstall-service") {
+			if err := uninstallService(name); err != nil {
+				return fmt.Errorf("Cannot uninstall the service %v: %v", name, err)
+			}
+			fmt.Fprintf(os.Stderr, "The service %v has been uninstalled\n", name)
+			return nil
 		}
 
-		cgvars.Logger.WithField("config", conf.ToMap()).Info("Setting up the server context")
-
-		jascoRoot := jasco.New("/", cgvars.Logger)
-		router, err := server.SetUpContextAndRouter("/", jascoRoot, cgvars)
+		isService, err := isWindowsService()
 		if err != nil {
-			return fmt.Errorf("Cannot set up the server context: %v", err)
+			return fmt.Errorf("Cannot determine if the process is a Windows service: %v", err)
 		}
-		server.SetUpAPIRouter("/", router, nil)
-
-		bind := c.String("listen-on")
-		if _, err := net.ResolveTCPAddr("tcp", bind); err != nil {
-			return fmt.Errorf("--listen-on(-l) parameter has an invalid address: %v", err)
+		if isService {
+			return runAsService(name, func(stop <-chan struct{}, ready func()) error {
+				return serve(c, stop, ready)
+			})
 		}
-
-		// TODO: support listening on multiple addresses
-		// TODO: support stopping the server
-		// TODO: support graceful shutdown
-		s := &http.Server{
-			Addr:    conf.Network.ListenOn,
-			Handler: jascoRoot,
-		}
-		cgvars.Logger.Infof("Starting the server on %v", conf.Network.ListenOn)
-		if err := s.ListenAndServe(); err != nil {
-			return fmt.Errorf("Cannot start the server: %v", err)
-		}
-		cgvars.Logger.Infof("The server stopped")
-		return nil
+		return serve(c, nil, func() {})
 	}()
 	if err != nil {
 		// NOTE: using something like this allows tests to check exit codes.
@@ -115,3 +130,177 @@ func Run(c *cli.Context) error {
 	}
 	return nil
 }
+
+// serve runs the HTTP server until stop is closed. ready is called when the
+// server starts listening. stop can be nil.
+func serve(c *cli.Context, stop <-chan struct{}, ready func()) error {
+	var conf *config.Config
+	if c.IsSet("config") {
+		p := c.String("config")
+		in, err := ioutil.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("Cannot read the config file %v: %v", p, err)
+		}
+
+		var yml map[string]interface{}
+		if err := yaml.Unmarshal(in, &yml); err != nil {
+			return fmt.Errorf("Cannot parse the config file %v: %v", p, err)
+		}
+		m, err := data.NewMap(yml)
+		if err != nil {
+			return fmt.Errorf("The config file %v has invalid values: %v", p, err)
+		}
+		c, err := config.New(m)
+		if err != nil {
+			return fmt.Errorf("Cannot apply the cnofig file %v: %v", p, err)
+		}
+		conf = c
+
+	} else {
+		// Currently there's no required parameters. However, when a required
+		// parameter is added to Config, remove this else block and add a
+		// default value like "/etc/sensorbee/config.yaml" to the config option.
+		c, err := config.New(data.Map{})
+		if err != nil {
+			return fmt.Errorf("Cannot apply the default config: %v", err)
+		}
+		conf = c
+	}
+
+	if err := applyTLSFlags(c, conf.Network); err != nil {
+		return err
+	}
+	var tlsConfig *tls.Config
+	if conf.Network.TLS != nil {
+		tc, err := server.NewTLSConfig(conf.Network.TLS)
+		if err != nil {
+			return fmt.Errorf("Cannot set up TLS: %v", err)
+		}
+		tlsConfig = tc
+	}
+
+	cgvars, err := server.SetUpContextGlobalVariables(conf)
+	if err != nil {
+		return fmt.Errorf("Cannot set up the server context: %v", err)
+	}
+
+	if cgvars.Recorder != nil {
+		defer cgvars.Recorder.Close()
+	}
+
+	cgvars.Logger.WithField("config", conf.ToMap()).Info("Setting up the server context")
+
+	shuffle, err := server.SetUpShuffleServer(cgvars)
+	if err != nil {
+		return fmt.Errorf("Cannot start the shuffle server: %v", err)
+	}
+	if shuffle != nil {
+		cgvars.Logger.Infof("Receiving shuffled tuples on %v", shuffle.Addr())
+		defer shuffle.Stop()
+	}
+
+	jascoRoot := jasco.New("/", cgvars.Logger)
+	router, err := server.SetUpContextAndRouter("/", jascoRoot, cgvars)
+	if err != nil {
+		return fmt.Errorf("Cannot set up the server context: %v", err)
+	}
+	server.SetUpAPIRouter("/", router, nil)
+
+	if agent := server.NewClusterAgent(cgvars); agent != nil {
+		cgvars.Logger.WithField("coordinator", conf.Cluster.Coordinator).
+			Info("Joining the cluster as a worker")
+		agent.Start()
+		defer agent.Stop()
+	}
+
+	if cgvars.Standby != nil {
+		cgvars.Logger.WithField("primary", conf.Replication.Primary).
+			Info("Replicating topologies as a standby server")
+		cgvars.Standby.Start()
+		defer cgvars.Standby.Stop()
+	}
+
+	bind := c.String("listen-on")
+	if _, err := net.ResolveTCPAddr("tcp", bind); err != nil {
+		return fmt.Errorf("--listen-on(-l) parameter has an invalid address: %v", err)
+	}
+
+	// TODO: support listening on multiple addresses
+	// TODO: support graceful shutdown
+	s := &http.Server{
+		Addr:    conf.Network.ListenOn,
+		Handler: jascoRoot,
+	}
+	l, err := net.Listen("tcp", conf.Network.ListenOn)
+	if err != nil {
+		return fmt.Errorf("Cannot start the server: %v", err)
+	}
+	if tlsConfig != nil {
+		// http.Server.ServeTLS isn't used because it enables HTTP/2.
+		l = tls.NewListener(l, tlsConfig)
+		cgvars.Logger.Infof("Starting the server on %v with TLS", conf.Network.ListenOn)
+	} else {
+		cgvars.Logger.Infof("Starting the server on %v", conf.Network.ListenOn)
+	}
+	ready()
+	notifyServiceManager(cgvars.Logger, "READY=1")
+	defer notifyServiceManager(cgvars.Logger, "STOPPING=1")
+
+	if interval, err := sdWatchdogInterval(); err != nil {
+		cgvars.Logger.WithField("err", err).Error("Cannot enable the watchdog")
+	} else if interval > 0 {
+		cgvars.Logger.WithField("interval", interval).Info("Sending keep-alive messages of the watchdog")
+		w := newWatchdog(interval, jascoRoot, cgvars.Logger)
+		w.start()
+		defer w.stop()
+	}
+
+	if stop != nil {
+		go func() {
+			<-stop
+			s.Close()
+		}()
+	}
+	if err := s.Serve(l); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("Cannot start the server: %v", err)
+	}
+	cgvars.Logger.Infof("The server stopped")
+	return nil
+}
+
+// notifyServiceManager sends the state to the service manager if the process
+// is started by the one supporting sd_notify.
+func notifyServiceManager(l *logrus.Logger, state string) {
+	if _, err := sdNotify(state); err != nil {
+		l.WithField("err", err).WithField("state", state).Error("Cannot notify the service manager")
+	}
+}
+
+// applyTLSFlags overwrites the tls section of the network config with
+// command line flags. --tls-cert-file and --tls-key-file must be given
+// together unless the config already has them.
+func applyTLSFlags(c *cli.Context, n *config.Network) error {
+	if !c.IsSet("tls-cert-file") && !c.IsSet("tls-key-file") && !c.IsSet("tls-client-ca-file") {
+		return nil
+	}
+	t := n.TLS
+	if t == nil {
+		if !c.IsSet("tls-cert-file") || !c.IsSet("tls-key-file") {
+			return fmt.Errorf("--tls-cert-file and --tls-key-file must be given together")
+		}
+		t = &config.TLS{
+			ClientAuth: "require",
+		}
+	}
+	if c.IsSet("tls-cert-file") {
+		t.CertFile = c.String("tls-cert-file")
+	}
+	if c.IsSet("tls-key-file") {
+		t.KeyFile = c.String("tls-key-file")
+	}
+	if c.IsSet("tls-client-ca-file") {
+		t.ClientCAFile = c.String("tls-client-ca-file")
+	}
+	n.TLS = t
+	return nil
+}
@@ -0,0 +1,141 @@
+package run
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sdNotify sends the state to the service manager through the socket given by
+// $NOTIFY_SOCKET as described in sd_notify(3). It returns false without an
+// error when the process isn't started by a service manager supporting the
+// protocol such as systemd with Type=notify.
+func sdNotify(state string) (bool, error) {
+	name := os.Getenv("NOTIFY_SOCKET")
+	if name == "" {
+		return false, nil
+	}
+	if name[0] == '@' { // abstract namespace
+		name = "\x00" + name[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: name, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sdWatchdogInterval returns the interval at which the process has to send
+// "WATCHDOG=1" to the service manager. It's read from $WATCHDOG_USEC. It
+// returns 0 when the watchdog isn't enabled for the process.
+func sdWatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" {
+		p, err := strconv.Atoi(pid)
+		if err != nil {
+			return 0, fmt.Errorf("WATCHDOG_PID has an invalid value: %v", pid)
+		}
+		if p != os.Getpid() {
+			return 0, nil
+		}
+	}
+	n, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("WATCHDOG_USEC has an invalid value: %v", usec)
+	}
+	return time.Duration(n) * time.Microsecond, nil
+}
+
+// watchdog periodically sends "WATCHDOG=1" to the service manager while the
+// server is alive. The server is considered alive when it responds to
+// GET /api/v1/runtime_status within the half of the interval. Otherwise, the
+// watchdog doesn't send the keep-alive so that the service manager can restart
+// the hung server.
+type watchdog struct {
+	interval time.Duration
+	handler  http.Handler
+	logger   *logrus.Logger
+	notify   func(state string) (bool, error)
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newWatchdog(interval time.Duration, h http.Handler, l *logrus.Logger) *watchdog {
+	return &watchdog{
+		interval: interval,
+		handler:  h,
+		logger:   l,
+		notify:   sdNotify,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+func (w *watchdog) start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		// systemd recommends sending the keep-alive at the half of the interval.
+		t := time.NewTicker(w.interval / 2)
+		defer t.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case <-t.C:
+			}
+
+			if err := w.check(w.interval / 2); err != nil {
+				w.logger.WithField("err", err).Error("The server isn't responding, skipping the watchdog keep-alive")
+				continue
+			}
+			if _, err := w.notify("WATCHDOG=1"); err != nil {
+				w.logger.WithField("err", err).Error("Cannot send the watchdog keep-alive")
+			}
+		}
+	}()
+}
+
+// check checks if the server responds within the timeout.
+func (w *watchdog) check(timeout time.Duration) error {
+	ch := make(chan int, 1)
+	go func() {
+		req, err := http.NewRequest("GET", "/api/v1/runtime_status", nil)
+		if err != nil {
+			ch <- http.StatusInternalServerError
+			return
+		}
+		rec := httptest.NewRecorder()
+		w.handler.ServeHTTP(rec, req)
+		ch <- rec.Code
+	}()
+
+	select {
+	case code := <-ch:
+		if code != http.StatusOK {
+			return fmt.Errorf("the server returned status %v", code)
+		}
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("the server didn't respond within %v", timeout)
+	}
+}
+
+func (w *watchdog) stop() {
+	close(w.stopCh)
+	w.wg.Wait()
+}
@@ -0,0 +1,126 @@
+package run
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSDNotify(t *testing.T) {
+	Convey("Given a socket of the service manager", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_sdnotify")
+		So(err, ShouldBeNil)
+		path := filepath.Join(dir, "notify.sock")
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		So(err, ShouldBeNil)
+		os.Setenv("NOTIFY_SOCKET", path)
+		Reset(func() {
+			os.Unsetenv("NOTIFY_SOCKET")
+			conn.Close()
+			os.RemoveAll(dir)
+		})
+
+		Convey("When notifying the state", func() {
+			ok, err := sdNotify("READY=1")
+
+			Convey("Then the service manager should receive it", func() {
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				buf := make([]byte, 64)
+				conn.SetReadDeadline(time.Now().Add(time.Second))
+				n, err := conn.Read(buf)
+				So(err, ShouldBeNil)
+				So(string(buf[:n]), ShouldEqual, "READY=1")
+			})
+		})
+	})
+
+	Convey("Given no socket of the service manager", t, func() {
+		os.Unsetenv("NOTIFY_SOCKET")
+
+		Convey("When notifying the state", func() {
+			ok, err := sdNotify("READY=1")
+
+			Convey("Then it should do nothing", func() {
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestSDWatchdogInterval(t *testing.T) {
+	Convey("Given environment variables of the watchdog", t, func() {
+		Reset(func() {
+			os.Unsetenv("WATCHDOG_USEC")
+			os.Unsetenv("WATCHDOG_PID")
+		})
+
+		Convey("When WATCHDOG_USEC is set", func() {
+			os.Setenv("WATCHDOG_USEC", "2000000")
+
+			Convey("Then the interval should be returned", func() {
+				d, err := sdWatchdogInterval()
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, 2*time.Second)
+			})
+
+			Convey("Then it should be disabled when WATCHDOG_PID is another process", func() {
+				os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+				d, err := sdWatchdogInterval()
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When WATCHDOG_USEC is invalid", func() {
+			os.Setenv("WATCHDOG_USEC", "a")
+
+			Convey("Then it should fail", func() {
+				_, err := sdWatchdogInterval()
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestWatchdog(t *testing.T) {
+	Convey("Given a watchdog", t, func() {
+		block := make(chan struct{})
+		h := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			<-block
+		})
+		notified := make(chan string, 100)
+		w := newWatchdog(20*time.Millisecond, h, nil)
+		w.notify = func(state string) (bool, error) {
+			notified <- state
+			return true, nil
+		}
+
+		Convey("When the server responds", func() {
+			close(block)
+			w.start()
+			Reset(w.stop)
+
+			Convey("Then it should send keep-alive messages", func() {
+				So(<-notified, ShouldEqual, "WATCHDOG=1")
+			})
+		})
+
+		Convey("When the server hangs", func() {
+			err := w.check(10 * time.Millisecond)
+			close(block)
+
+			Convey("Then the check should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+// +build !windows
+
+package run
+
+import (
+	"errors"
+)
+
+var errWindowsServiceUnsupported = errors.New("Windows services are only supported on Windows")
+
+func isWindowsService() (bool, error) {
+	return false, nil
+}
+
+func runAsService(name string, serve func(stop <-chan struct{}, ready func()) error) error {
+	return errWindowsServiceUnsupported
+}
+
+func installService(name string, args []string) error {
+	return errWindowsServiceUnsupported
+}
+
+func uninstallService(name string) error {
+	return errWindowsServiceUnsupported
+}
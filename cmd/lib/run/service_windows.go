@@ -0,0 +1,123 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// isWindowsService returns true when the process is started by the Windows
+// service control manager.
+func isWindowsService() (bool, error) {
+	return svc.IsWindowsService()
+}
+
+// runAsService runs the server as a Windows service. The server reports
+// svc.Running after ready is called and is stopped by closing stop when the
+// service control manager requests it.
+func runAsService(name string, serve func(stop <-chan struct{}, ready func()) error) error {
+	h := &serviceHandler{
+		serve: serve,
+	}
+	if err := svc.Run(name, h); err != nil {
+		return err
+	}
+	return h.err
+}
+
+type serviceHandler struct {
+	serve func(stop <-chan struct{}, ready func()) error
+	err   error
+}
+
+func (h *serviceHandler) Execute(args []string, reqs <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+	stop := make(chan struct{})
+	readyCh := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- h.serve(stop, func() { close(readyCh) })
+	}()
+
+	accepted := svc.AcceptStop | svc.AcceptShutdown
+	for {
+		select {
+		case <-readyCh:
+			readyCh = nil
+			status <- svc.Status{State: svc.Running, Accepts: accepted}
+
+		case err := <-done:
+			h.err = err
+			if err != nil {
+				return true, 1
+			}
+			return false, 0
+
+		case r := <-reqs:
+			switch r.Cmd {
+			case svc.Interrogate:
+				status <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				close(stop)
+				h.err = <-done
+				if h.err != nil {
+					return true, 1
+				}
+				return false, 0
+			}
+		}
+	}
+}
+
+// installService registers the executable as a Windows service which is
+// started automatically with the given arguments of the run command.
+func installService(name string, args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	exe, err = filepath.Abs(exe)
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(name); err == nil {
+		s.Close()
+		return fmt.Errorf("the service %v already exists", name)
+	}
+
+	s, err := m.CreateService(name, exe, mgr.Config{
+		DisplayName: "SensorBee",
+		Description: "SensorBee server",
+		StartType:   mgr.StartAutomatic,
+	}, append([]string{"run"}, args...)...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return nil
+}
+
+// uninstallService removes the Windows service.
+func uninstallService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("the service %v is not installed: %v", name, err)
+	}
+	defer s.Close()
+	return s.Delete()
+}
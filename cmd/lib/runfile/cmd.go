@@ -207,6 +207,21 @@ func Run(c *cli.Context) error {
 
 func setUpUDSStorage(conf *config.UDSStorage) (udf.UDSStorage, error) {
 	// TODO: merge this implementation with server/context.go
+	s, err := newUDSStorage(conf)
+	if err != nil {
+		return nil, err
+	}
+	if e := conf.Encryption; e != nil {
+		key, err := udsstorage.ReadEncryptionKey(e.KeyFile, e.KeyEnv)
+		if err != nil {
+			return nil, err
+		}
+		return udsstorage.NewEncrypted(s, key)
+	}
+	return s, nil
+}
+
+func newUDSStorage(conf *config.UDSStorage) (udf.UDSStorage, error) {
 	// Parameters are already validated in conf
 	switch conf.Type {
 	case "in_memory":
@@ -218,11 +233,48 @@ func setUpUDSStorage(conf *config.UDSStorage) (udf.UDSStorage, error) {
 			tempDir, _ = data.AsString(v)
 		}
 		return udsstorage.NewFS(dir, tempDir)
+	case "s3":
+		return udsstorage.NewS3(&udsstorage.S3Config{
+			Bucket:          udsStorageParam(conf, "bucket"),
+			Region:          udsStorageParam(conf, "region"),
+			Endpoint:        udsStorageParam(conf, "endpoint"),
+			Prefix:          udsStorageParam(conf, "prefix"),
+			AccessKeyID:     udsStorageParam(conf, "access_key_id"),
+			SecretAccessKey: udsStorageParam(conf, "secret_access_key"),
+		})
+	case "redis":
+		var db int64
+		if v, ok := conf.Params["db"]; ok {
+			db, _ = data.AsInt(v)
+		}
+		return udsstorage.NewRedis(&udsstorage.RedisConfig{
+			Address:  udsStorageParam(conf, "address"),
+			Password: udsStorageParam(conf, "password"),
+			DB:       int(db),
+			Prefix:   udsStorageParam(conf, "prefix"),
+		})
+	case "sql":
+		return udsstorage.NewSQL(&udsstorage.SQLConfig{
+			Driver:     udsStorageParam(conf, "driver"),
+			DataSource: udsStorageParam(conf, "data_source"),
+			Table:      udsStorageParam(conf, "table"),
+		})
 	default:
 		return nil, fmt.Errorf("unsupported uds storage type: %v", conf.Type)
 	}
 }
 
+// udsStorageParam returns a string parameter of the storage. It returns an
+// empty string when the parameter is missing.
+func udsStorageParam(conf *config.UDSStorage, name string) string {
+	v, ok := conf.Params[name]
+	if !ok {
+		return ""
+	}
+	s, _ := data.AsString(v)
+	return s
+}
+
 func setUpTopology(name string, logger *logrus.Logger, conf *config.Config, us udf.UDSStorage) (
 	*bql.TopologyBuilder, error) {
 	cc := &core.ContextConfig{
@@ -231,6 +283,9 @@ func setUpTopology(name string, logger *logrus.Logger, conf *config.Config, us u
 	cc.Flags.DroppedTupleLog.Set(conf.Logging.LogDroppedTuples)
 	cc.Flags.DestinationlessTupleLog.Set(conf.Logging.LogDestinationlessTuples)
 	cc.Flags.DroppedTupleSummarization.Set(conf.Logging.SummarizeDroppedTuples)
+	if t, ok := conf.Topologies[name]; ok {
+		cc.Config = t.Config
+	}
 
 	tp, err := core.NewDefaultTopology(core.NewContext(cc), name)
 	if err != nil {
@@ -269,7 +324,7 @@ func setUpBQLStmt(tb *bql.TopologyBuilder, bqlFile string) error {
 		return err
 	}
 
-	for _, stmt := range stmts {
+	for _, stmt := range parser.SortStmts(stmts) {
 		// TODO: if stmt is CREATE SOURCE, create it with PAUSED
 		if n, err := tb.AddStmt(stmt); err != nil {
 			tb.Topology().Context().ErrLog(err).WithField("stmt", stmt).Error(
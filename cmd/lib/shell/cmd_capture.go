@@ -0,0 +1,574 @@
+package shell
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/sensorbee/sensorbee.v0/client"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// output is the writer to which results are printed. It's changed by
+	// the \o command.
+	output io.Writer = os.Stdout
+
+	// outputFile is the file opened by the \o command. It's nil when results
+	// are printed to the standard output.
+	outputFile *os.File
+
+	// variables are shell variables set by the \set command. Values are
+	// results of EVAL statements decoded from JSON.
+	variables = map[string]interface{}{}
+
+	variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// NewCaptureCommands returns command list to capture results into files and
+// shell variables.
+func NewCaptureCommands() []Command {
+	return []Command{
+		&outputCmd{},
+		&copyCmd{},
+		&setCmd{},
+		&unsetCmd{},
+	}
+}
+
+type outputCmd struct {
+	filePath string
+}
+
+func (o *outputCmd) Init() error {
+	return nil
+}
+
+func (o *outputCmd) Name() []string {
+	return []string{`\o`}
+}
+
+func (o *outputCmd) Input(input string) (cmdInputStatusType, error) {
+	inputs := strings.Fields(input)
+	switch len(inputs) {
+	case 1:
+		o.filePath = ""
+	case 2:
+		o.filePath = inputs[1]
+	default:
+		return invalidCMD, fmt.Errorf("name included spaces is not supported: %v",
+			strings.Join(inputs[1:], " "))
+	}
+	return preparedCMD, nil
+}
+
+// Eval redirects results to the file. Results are printed to the standard
+// output again when the file path is omitted.
+func (o *outputCmd) Eval(requester *client.Requester) {
+	var f *os.File
+	if o.filePath != "" {
+		var err error
+		f, err = os.Create(o.filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open the output file: %v\n", err)
+			return
+		}
+	}
+	setOutputFile(f)
+}
+
+// setOutputFile changes the output to f and closes the previous file. The
+// output is changed to the standard output when f is nil.
+func setOutputFile(f *os.File) {
+	if outputFile != nil {
+		if err := outputFile.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot close the output file: %v\n", err)
+		}
+	}
+	outputFile = f
+	if f == nil {
+		output = os.Stdout
+	} else {
+		output = f
+	}
+}
+
+// copyFormat is the format of a file written by the \copy command.
+type copyFormat int
+
+const (
+	copyFormatCSV copyFormat = iota
+	copyFormatJSON
+)
+
+type copyCmd struct {
+	stream    string
+	filePath  string
+	format    copyFormat
+	header    bool
+	delimiter rune
+	limit     int64
+	timeout   time.Duration
+}
+
+func (c *copyCmd) Init() error {
+	return nil
+}
+
+func (c *copyCmd) Name() []string {
+	return []string{`\copy`}
+}
+
+// Input parses the \copy command:
+//
+//	\copy stream TO 'file' [FORMAT csv|json] [HEADER] [DELIMITER 'c']
+//	      [LIMIT n] [TIMEOUT seconds]
+//
+// The format is json when it's omitted and the file has the .json or .jsonl
+// extension, and csv otherwise.
+func (c *copyCmd) Input(input string) (cmdInputStatusType, error) {
+	args, err := splitCopyArgs(strings.TrimSuffix(strings.TrimSpace(input), ";"))
+	if err != nil {
+		return invalidCMD, err
+	}
+	if len(args) < 4 || strings.ToLower(args[2]) != "to" {
+		return invalidCMD, fmt.Errorf(`usage: \copy stream TO 'file' [option ...]`)
+	}
+	*c = copyCmd{
+		stream:    args[1],
+		filePath:  args[3],
+		delimiter: ',',
+	}
+	switch strings.ToLower(filepath.Ext(c.filePath)) {
+	case ".json", ".jsonl":
+		c.format = copyFormatJSON
+	}
+
+	opts := args[4:]
+	value := func(name string) (string, error) {
+		if len(opts) < 2 {
+			return "", fmt.Errorf("%v requires a value", name)
+		}
+		v := opts[1]
+		opts = opts[1:]
+		return v, nil
+	}
+	for ; len(opts) > 0; opts = opts[1:] {
+		switch name := strings.ToLower(opts[0]); name {
+		case "format":
+			v, err := value(name)
+			if err != nil {
+				return invalidCMD, err
+			}
+			switch strings.ToLower(v) {
+			case "csv":
+				c.format = copyFormatCSV
+			case "json":
+				c.format = copyFormatJSON
+			default:
+				return invalidCMD, fmt.Errorf("unsupported format: %v", v)
+			}
+		case "header":
+			c.header = true
+		case "delimiter":
+			v, err := value(name)
+			if err != nil {
+				return invalidCMD, err
+			}
+			r := []rune(v)
+			if len(r) != 1 {
+				return invalidCMD, fmt.Errorf("delimiter must be a single character: %v", v)
+			}
+			c.delimiter = r[0]
+		case "limit":
+			v, err := value(name)
+			if err != nil {
+				return invalidCMD, err
+			}
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n <= 0 {
+				return invalidCMD, fmt.Errorf("limit must be a positive integer: %v", v)
+			}
+			c.limit = n
+		case "timeout":
+			v, err := value(name)
+			if err != nil {
+				return invalidCMD, err
+			}
+			sec, err := strconv.ParseFloat(v, 64)
+			if err != nil || sec <= 0 {
+				return invalidCMD, fmt.Errorf("timeout must be a positive number: %v", v)
+			}
+			c.timeout = time.Duration(sec * float64(time.Second))
+		default:
+			return invalidCMD, fmt.Errorf("unknown option: %v", opts[0])
+		}
+	}
+	return preparedCMD, nil
+}
+
+// splitCopyArgs splits arguments of the \copy command by spaces. An argument
+// can be quoted by single quotes, and a single quote in it is escaped by
+// doubling it.
+func splitCopyArgs(s string) ([]string, error) {
+	var (
+		args   []string
+		cur    []rune
+		quoted bool
+		inArg  bool
+	)
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quoted:
+			if r != '\'' {
+				cur = append(cur, r)
+			} else if i+1 < len(rs) && rs[i+1] == '\'' {
+				cur = append(cur, r)
+				i++
+			} else {
+				quoted = false
+			}
+		case r == '\'':
+			quoted, inArg = true, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, string(cur))
+				cur, inArg = nil, false
+			}
+		default:
+			cur, inArg = append(cur, r), true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quoted string: %v", s)
+	}
+	if inArg {
+		args = append(args, string(cur))
+	}
+	return args, nil
+}
+
+// Eval writes tuples of the stream to the file until LIMIT tuples are written,
+// TIMEOUT passes, or Ctrl+C is pressed.
+func (c *copyCmd) Eval(requester *client.Requester) {
+	if currentTopology.name == "" {
+		fmt.Fprintln(os.Stderr, "cannot make request: no topology set")
+		return
+	}
+	f, err := os.Create(c.filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot open the file: %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	stmt := fmt.Sprintf("SELECT RSTREAM * FROM %v [RANGE 1 TUPLES];", c.stream)
+	cur, err := client.NewWithRequester(requester).Select(currentTopology.name, stmt)
+	if err != nil {
+		printRequestError(err)
+		return
+	}
+	defer cur.Close()
+
+	var timeout <-chan time.Time
+	if c.timeout > 0 {
+		timeout = time.After(c.timeout)
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	w := c.newTupleWriter(f)
+	var n int64
+	func() {
+		for c.limit == 0 || n < c.limit {
+			select {
+			case js, ok := <-cur.C():
+				if !ok {
+					if err := cur.Err(); err != nil {
+						fmt.Fprintln(os.Stderr, err)
+					}
+					return
+				}
+				if err := w.write(js); err != nil {
+					fmt.Fprintf(os.Stderr, "cannot write a tuple: %v\n", err)
+					return
+				}
+				n++
+			case <-timeout:
+				return
+			case <-sig:
+				return
+			}
+		}
+	}()
+	if err := w.flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write tuples: %v\n", err)
+	}
+	fmt.Printf("copied %d tuples to %v\n", n, c.filePath)
+}
+
+// tupleWriter writes tuples received by the \copy command.
+type tupleWriter interface {
+	write(js interface{}) error
+	flush() error
+}
+
+func (c *copyCmd) newTupleWriter(w io.Writer) tupleWriter {
+	if c.format == copyFormatJSON {
+		return &jsonTupleWriter{
+			enc: json.NewEncoder(w),
+		}
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = c.delimiter
+	return &csvTupleWriter{
+		w:      cw,
+		header: c.header,
+	}
+}
+
+// jsonTupleWriter writes a tuple as a JSON object per line.
+type jsonTupleWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonTupleWriter) write(js interface{}) error {
+	return w.enc.Encode(js)
+}
+
+func (w *jsonTupleWriter) flush() error {
+	return nil
+}
+
+// csvTupleWriter writes a tuple as a CSV record. Columns are the fields of
+// the first tuple sorted by their names. Fields which aren't strings are
+// written as JSON.
+type csvTupleWriter struct {
+	w       *csv.Writer
+	header  bool
+	columns []string
+}
+
+func (w *csvTupleWriter) write(js interface{}) error {
+	m, ok := js.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("the tuple isn't a JSON object: %v", js)
+	}
+	if w.columns == nil {
+		w.columns = make([]string, 0, len(m))
+		for k := range m {
+			w.columns = append(w.columns, k)
+		}
+		sort.Strings(w.columns)
+		if w.header {
+			if err := w.w.Write(w.columns); err != nil {
+				return err
+			}
+		}
+	}
+
+	record := make([]string, len(w.columns))
+	for i, k := range w.columns {
+		switch v := m[k].(type) {
+		case nil:
+		case string:
+			record[i] = v
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			record[i] = string(b)
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvTupleWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type setCmd struct {
+	name   string
+	buffer string
+}
+
+func (s *setCmd) Init() error {
+	return nil
+}
+
+func (s *setCmd) Name() []string {
+	return []string{`\set`}
+}
+
+// Input parses the \set command:
+//
+//	\set name EVAL expression;
+//
+// The EVAL statement can span multiple lines. All variables are listed when
+// no argument is given.
+func (s *setCmd) Input(input string) (cmdInputStatusType, error) {
+	if s.name == "" {
+		inputs := strings.SplitN(strings.TrimSpace(input), " ", 3)
+		if len(inputs) == 1 {
+			return preparedCMD, nil
+		}
+		if !variableNamePattern.MatchString(inputs[1]) {
+			return invalidCMD, fmt.Errorf("invalid variable name: %v", inputs[1])
+		}
+		if len(inputs) == 2 {
+			return invalidCMD, fmt.Errorf("an EVAL statement is missing")
+		}
+		s.name = inputs[1]
+		s.buffer = strings.TrimSpace(inputs[2])
+		input = s.buffer
+	} else {
+		s.buffer += "\n" + input
+	}
+	if !strings.HasSuffix(input, ";") {
+		return continuousCMD, nil
+	}
+
+	if f := strings.Fields(s.buffer); strings.ToLower(f[0]) != "eval" {
+		s.name, s.buffer = "", ""
+		return invalidCMD, fmt.Errorf("only the result of an EVAL statement can be set")
+	}
+	return preparedCMD, nil
+}
+
+// Eval issues the EVAL statement and sets its result to the variable.
+func (s *setCmd) Eval(requester *client.Requester) {
+	name, stmt := s.name, s.buffer
+	s.name, s.buffer = "", ""
+	if name == "" {
+		printVariables()
+		return
+	}
+
+	if currentTopology.name == "" {
+		fmt.Fprintln(os.Stderr, "cannot make request: no topology set")
+		return
+	}
+	stmt, err := expandVariables(stmt)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	res, err := client.NewWithRequester(requester).Exec(currentTopology.name, stmt)
+	if err != nil {
+		printRequestError(err)
+		return
+	}
+	variables[name] = res["result"]
+}
+
+func printVariables() {
+	names := make([]string, 0, len(variables))
+	for n := range variables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Printf("%v = %v\n", n, bqlLiteral(variables[n]))
+	}
+}
+
+type unsetCmd struct {
+	name string
+}
+
+func (u *unsetCmd) Init() error {
+	return nil
+}
+
+func (u *unsetCmd) Name() []string {
+	return []string{`\unset`}
+}
+
+func (u *unsetCmd) Input(input string) (cmdInputStatusType, error) {
+	inputs := strings.Fields(input)
+	if len(inputs) != 2 {
+		return invalidCMD, fmt.Errorf("a variable name is required")
+	}
+	u.name = inputs[1]
+	return preparedCMD, nil
+}
+
+func (u *unsetCmd) Eval(requester *client.Requester) {
+	delete(variables, u.name)
+}
+
+// expandVariables replaces ${name} in the statements with the value of the
+// variable as a BQL literal. Variables in string literals aren't expanded.
+func expandVariables(stmts string) (string, error) {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(stmts); i++ {
+		c := stmts[i]
+		if c == '"' {
+			quoted = !quoted
+		}
+		if quoted || c != '$' || i+1 >= len(stmts) || stmts[i+1] != '{' {
+			b.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(stmts[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference: %v", stmts[i:])
+		}
+		name := stmts[i+2 : i+end]
+		v, ok := variables[name]
+		if !ok {
+			return "", fmt.Errorf("variable %v is not set", name)
+		}
+		b.WriteString(bqlLiteral(v))
+		i += end
+	}
+	return b.String(), nil
+}
+
+// bqlLiteral returns the BQL literal of a value decoded from JSON.
+func bqlLiteral(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case string:
+		return `"` + strings.Replace(v, `"`, `""`, -1) + `"`
+	case []interface{}:
+		es := make([]string, len(v))
+		for i, e := range v {
+			es[i] = bqlLiteral(e)
+		}
+		return "[" + strings.Join(es, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		es := make([]string, len(keys))
+		for i, k := range keys {
+			es[i] = bqlLiteral(k) + ": " + bqlLiteral(v[k])
+		}
+		return "{" + strings.Join(es, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,255 @@
+package shell
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/smartystreets/goconvey/convey"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOutputCommand(t *testing.T) {
+	Convey("Given a temporary directory", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_shell_test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			setOutputFile(nil)
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "out.json")
+
+		Convey("When the output is redirected to a file", func() {
+			cmd := outputCmd{}
+			status, err := cmd.Input(`\o ` + path)
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, preparedCMD)
+			cmd.Eval(nil)
+			printJSONResult(map[string]interface{}{"a": 1})
+
+			Convey("Then results should be written to the file", func() {
+				b, err := ioutil.ReadFile(path)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, "{\"a\":1}\n")
+			})
+
+			Convey("And the output is reset", func() {
+				status, err := cmd.Input(`\o`)
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				cmd.Eval(nil)
+
+				Convey("Then results should be printed to the standard output", func() {
+					So(output, ShouldEqual, os.Stdout)
+					So(outputFile, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func TestCopyCommandInput(t *testing.T) {
+	Convey("Given a copy command struct", t, func() {
+		cmd := copyCmd{}
+
+		Convey("When input a command without options", func() {
+			status, err := cmd.Input(`\copy s TO 'out file.csv';`)
+			Convey("Then it should have default options", func() {
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				So(cmd.stream, ShouldEqual, "s")
+				So(cmd.filePath, ShouldEqual, "out file.csv")
+				So(cmd.format, ShouldEqual, copyFormatCSV)
+				So(cmd.header, ShouldBeFalse)
+				So(cmd.delimiter, ShouldEqual, ',')
+				So(cmd.limit, ShouldEqual, 0)
+				So(cmd.timeout, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When input a command with options", func() {
+			status, err := cmd.Input(`\copy s to 'out.txt' FORMAT csv HEADER DELIMITER ';' LIMIT 10 TIMEOUT 1.5`)
+			Convey("Then it should have given options", func() {
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				So(cmd.filePath, ShouldEqual, "out.txt")
+				So(cmd.header, ShouldBeTrue)
+				So(cmd.delimiter, ShouldEqual, ';')
+				So(cmd.limit, ShouldEqual, 10)
+				So(cmd.timeout, ShouldEqual, 1500*time.Millisecond)
+			})
+		})
+
+		Convey("When input a file having the json extension", func() {
+			_, err := cmd.Input(`\copy s TO 'out.jsonl'`)
+			Convey("Then the format should be json", func() {
+				So(err, ShouldBeNil)
+				So(cmd.format, ShouldEqual, copyFormatJSON)
+			})
+		})
+
+		Convey("When input invalid commands", func() {
+			Convey("Then they should be invalid", func() {
+				for _, in := range []string{
+					`\copy s`,
+					`\copy s FROM 'out.csv'`,
+					`\copy s TO 'out.csv`,
+					`\copy s TO 'out.csv' FORMAT xml`,
+					`\copy s TO 'out.csv' DELIMITER`,
+					`\copy s TO 'out.csv' DELIMITER ';;'`,
+					`\copy s TO 'out.csv' LIMIT 0`,
+					`\copy s TO 'out.csv' TIMEOUT a`,
+					`\copy s TO 'out.csv' UNKNOWN`,
+				} {
+					status, err := cmd.Input(in)
+					So(err, ShouldNotBeNil)
+					So(status, ShouldEqual, invalidCMD)
+				}
+			})
+		})
+	})
+}
+
+func TestSplitCopyArgs(t *testing.T) {
+	Convey("Given arguments having quoted strings", t, func() {
+		args, err := splitCopyArgs(`\copy  s TO 'it''s here.csv' DELIMITER ' '`)
+
+		Convey("Then they should be split by spaces outside quotes", func() {
+			So(err, ShouldBeNil)
+			So(args, ShouldResemble, []string{`\copy`, "s", "TO", "it's here.csv", "DELIMITER", " "})
+		})
+	})
+}
+
+func TestTupleWriters(t *testing.T) {
+	tuples := []interface{}{
+		map[string]interface{}{"b": "x,y", "a": json.Number("1"), "c": map[string]interface{}{"d": true}},
+		map[string]interface{}{"a": json.Number("2"), "e": "ignored"},
+	}
+
+	Convey("Given a CSV tuple writer having a header", t, func() {
+		b := bytes.NewBuffer(nil)
+		w := (&copyCmd{header: true, delimiter: ','}).newTupleWriter(b)
+
+		Convey("When writing tuples", func() {
+			for _, t := range tuples {
+				So(w.write(t), ShouldBeNil)
+			}
+			So(w.flush(), ShouldBeNil)
+
+			Convey("Then columns should be fields of the first tuple", func() {
+				So(b.String(), ShouldEqual, "a,b,c\n"+
+					"1,\"x,y\",\"{\"\"d\"\":true}\"\n"+
+					"2,,\n")
+			})
+		})
+
+		Convey("When writing a value which isn't an object", func() {
+			Convey("Then it should fail", func() {
+				So(w.write("a"), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a JSON tuple writer", t, func() {
+		b := bytes.NewBuffer(nil)
+		w := (&copyCmd{format: copyFormatJSON}).newTupleWriter(b)
+
+		Convey("When writing tuples", func() {
+			for _, t := range tuples {
+				So(w.write(t), ShouldBeNil)
+			}
+			So(w.flush(), ShouldBeNil)
+
+			Convey("Then each tuple should be written in a line", func() {
+				So(b.String(), ShouldEqual, `{"a":1,"b":"x,y","c":{"d":true}}`+"\n"+
+					`{"a":2,"e":"ignored"}`+"\n")
+			})
+		})
+	})
+}
+
+func TestSetCommandInput(t *testing.T) {
+	Convey("Given a set command struct", t, func() {
+		cmd := setCmd{}
+
+		Convey("When input an EVAL statement in multiple lines", func() {
+			status, err := cmd.Input(`\set v eval 1 +`)
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, continuousCMD)
+			status, err = cmd.Input("2;")
+
+			Convey("Then it should buffer the statement", func() {
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				So(cmd.name, ShouldEqual, "v")
+				So(cmd.buffer, ShouldEqual, "eval 1 +\n2;")
+			})
+		})
+
+		Convey("When input no argument", func() {
+			status, err := cmd.Input(`\set`)
+			Convey("Then it should list variables", func() {
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				So(cmd.name, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When input invalid commands", func() {
+			Convey("Then they should be invalid", func() {
+				for _, in := range []string{
+					`\set 1v eval 1;`,
+					`\set v`,
+					`\set v select * from s;`,
+				} {
+					status, err := cmd.Input(in)
+					So(err, ShouldNotBeNil)
+					So(status, ShouldEqual, invalidCMD)
+					So(cmd.name, ShouldBeEmpty)
+				}
+			})
+		})
+	})
+}
+
+func TestExpandVariables(t *testing.T) {
+	Convey("Given shell variables", t, func() {
+		variables["i"] = json.Number("10")
+		variables["s"] = `a"b`
+		variables["m"] = map[string]interface{}{
+			"b": []interface{}{true, nil},
+			"a": 1.5,
+		}
+		Reset(func() {
+			variables = map[string]interface{}{}
+		})
+
+		Convey("When expanding statements referring to them", func() {
+			s, err := expandVariables(`EVAL ${i} + 1, ${s}, ${m}, "${i}";`)
+
+			Convey("Then they should be replaced with BQL literals", func() {
+				So(err, ShouldBeNil)
+				So(s, ShouldEqual, `EVAL 10 + 1, "a""b", {"a": 1.5, "b": [TRUE, NULL]}, "${i}";`)
+			})
+		})
+
+		Convey("When expanding a statement referring to an undefined variable", func() {
+			_, err := expandVariables(`EVAL ${x};`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When expanding a statement having an unterminated reference", func() {
+			_, err := expandVariables(`EVAL ${i;`)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,355 @@
+package shell
+
+import (
+	"fmt"
+	"gopkg.in/sensorbee/sensorbee.v0/client"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// NewJobCommands returns command list to run SELECT statements in the
+// background and control them.
+func NewJobCommands() []Command {
+	return []Command{
+		&bgCmd{},
+		&jobsCmd{},
+		&fgCmd{},
+		&killCmd{},
+	}
+}
+
+// jobCursor is the part of client.Cursor used by background jobs.
+type jobCursor interface {
+	C() <-chan interface{}
+	Err() error
+	Close() error
+}
+
+// backgroundJob is a SELECT statement running in the background. Results are
+// discarded while the job isn't attached to the terminal.
+type backgroundJob struct {
+	id       int
+	topology string
+	stmt     string
+	cur      jobCursor
+
+	// count is the number of tuples received. It must be accessed
+	// atomically.
+	count int64
+
+	m        sync.Mutex
+	attached bool
+
+	// done is closed when the stream ends. err is set before done is closed.
+	done chan struct{}
+	err  error
+}
+
+func (j *backgroundJob) run() {
+	defer close(j.done)
+	for js := range j.cur.C() {
+		atomic.AddInt64(&j.count, 1)
+		j.m.Lock()
+		if j.attached {
+			printJSONResult(js)
+		}
+		j.m.Unlock()
+	}
+	j.err = j.cur.Err()
+}
+
+func (j *backgroundJob) setAttached(attached bool) {
+	j.m.Lock()
+	defer j.m.Unlock()
+	j.attached = attached
+}
+
+// tuples returns the number of tuples received so far.
+func (j *backgroundJob) tuples() int64 {
+	return atomic.LoadInt64(&j.count)
+}
+
+// finished returns true when the stream of the job has ended.
+func (j *backgroundJob) finished() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (j *backgroundJob) status() string {
+	if !j.finished() {
+		return "running"
+	}
+	if j.err != nil {
+		return fmt.Sprintf("failed (%v)", j.err)
+	}
+	return "done"
+}
+
+func (j *backgroundJob) String() string {
+	return fmt.Sprintf("[%d] %v: %v", j.id, j.topology,
+		strings.Join(strings.Fields(j.stmt), " "))
+}
+
+// jobTable has background jobs of the shell. IDs of jobs are assigned
+// sequentially from 1.
+type jobTable struct {
+	m      sync.Mutex
+	jobs   map[int]*backgroundJob
+	lastID int
+}
+
+var (
+	jobs = newJobTable()
+)
+
+func newJobTable() *jobTable {
+	return &jobTable{
+		jobs: map[int]*backgroundJob{},
+	}
+}
+
+// start registers a new job reading results from the cursor.
+func (t *jobTable) start(topology, stmt string, cur jobCursor) *backgroundJob {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.lastID++
+	j := &backgroundJob{
+		id:       t.lastID,
+		topology: topology,
+		stmt:     stmt,
+		cur:      cur,
+		done:     make(chan struct{}),
+	}
+	t.jobs[j.id] = j
+	go j.run()
+	return j
+}
+
+// get returns the job having the ID. The latest job is returned when id is 0.
+// It returns nil when the job doesn't exist.
+func (t *jobTable) get(id int) *backgroundJob {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if id != 0 {
+		return t.jobs[id]
+	}
+	var latest *backgroundJob
+	for _, j := range t.jobs {
+		if latest == nil || j.id > latest.id {
+			latest = j
+		}
+	}
+	return latest
+}
+
+// remove unregisters the job.
+func (t *jobTable) remove(j *backgroundJob) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	delete(t.jobs, j.id)
+}
+
+// list returns all jobs sorted by their IDs.
+func (t *jobTable) list() []*backgroundJob {
+	t.m.Lock()
+	defer t.m.Unlock()
+	js := make([]*backgroundJob, 0, len(t.jobs))
+	for _, j := range t.jobs {
+		js = append(js, j)
+	}
+	sort.Slice(js, func(i, k int) bool {
+		return js[i].id < js[k].id
+	})
+	return js
+}
+
+// parseJobID parses the optional job ID given to a job control command. It
+// returns 0 when the ID is omitted.
+func parseJobID(input string) (int, error) {
+	inputs := strings.Fields(input)
+	switch len(inputs) {
+	case 1:
+		return 0, nil
+	case 2:
+		id, err := strconv.Atoi(strings.TrimPrefix(inputs[1], "%"))
+		if err != nil || id <= 0 {
+			return 0, fmt.Errorf("invalid job ID: %v", inputs[1])
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("too many arguments: %v", strings.Join(inputs[1:], " "))
+	}
+}
+
+type bgCmd struct {
+	buffer string
+}
+
+func (b *bgCmd) Init() error {
+	return nil
+}
+
+func (b *bgCmd) Name() []string {
+	return []string{`\bg`}
+}
+
+// Input buffers a SELECT statement following \bg. The statement can span
+// multiple lines like other BQL statements.
+func (b *bgCmd) Input(input string) (cmdInputStatusType, error) {
+	if b.buffer == "" {
+		input = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), b.Name()[0]))
+		b.buffer = input
+	} else {
+		b.buffer += "\n" + input
+	}
+	if !strings.HasSuffix(input, ";") {
+		return continuousCMD, nil
+	}
+
+	if f := strings.Fields(b.buffer); strings.ToLower(f[0]) != "select" {
+		b.buffer = ""
+		return invalidCMD, fmt.Errorf("only a SELECT statement can run in the background")
+	}
+	return preparedCMD, nil
+}
+
+// Eval issues the SELECT statement and starts a job receiving its results.
+func (b *bgCmd) Eval(requester *client.Requester) {
+	stmt := b.buffer
+	b.buffer = ""
+
+	if currentTopology.name == "" {
+		fmt.Fprintln(os.Stderr, "cannot make request: no topology set")
+		return
+	}
+	stmt, err := expandVariables(stmt)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	cur, err := client.NewWithRequester(requester).Select(currentTopology.name, stmt)
+	if err != nil {
+		printRequestError(err)
+		return
+	}
+	fmt.Println(jobs.start(currentTopology.name, stmt, cur))
+}
+
+type jobsCmd struct {
+}
+
+func (jc *jobsCmd) Init() error {
+	return nil
+}
+
+func (jc *jobsCmd) Name() []string {
+	return []string{`\jobs`}
+}
+
+func (jc *jobsCmd) Input(input string) (cmdInputStatusType, error) {
+	if inputs := strings.Fields(input); len(inputs) != 1 {
+		return invalidCMD, fmt.Errorf("too many arguments: %v", strings.Join(inputs[1:], " "))
+	}
+	return preparedCMD, nil
+}
+
+// Eval lists background jobs with the number of tuples they received. Jobs
+// which have finished are removed after they're listed.
+func (jc *jobsCmd) Eval(requester *client.Requester) {
+	for _, j := range jobs.list() {
+		fmt.Printf("%v (%v, %d tuples)\n", j, j.status(), j.tuples())
+		if j.finished() {
+			jobs.remove(j)
+		}
+	}
+}
+
+type fgCmd struct {
+	id int
+}
+
+func (f *fgCmd) Init() error {
+	return nil
+}
+
+func (f *fgCmd) Name() []string {
+	return []string{`\fg`}
+}
+
+func (f *fgCmd) Input(input string) (cmdInputStatusType, error) {
+	id, err := parseJobID(input)
+	if err != nil {
+		return invalidCMD, err
+	}
+	f.id = id
+	return preparedCMD, nil
+}
+
+// Eval attaches the job to the terminal and prints its results until the job
+// finishes or Ctrl+C is pressed. The job keeps running in the background
+// after Ctrl+C.
+func (f *fgCmd) Eval(requester *client.Requester) {
+	j := jobs.get(f.id)
+	if j == nil {
+		fmt.Fprintln(os.Stderr, "no such job")
+		return
+	}
+	fmt.Printf("%v (press Ctrl+C to send it back to the background)\n", j)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	j.setAttached(true)
+	defer j.setAttached(false)
+	select {
+	case <-j.done:
+		jobs.remove(j)
+		if j.err != nil {
+			fmt.Fprintln(os.Stderr, j.err)
+		}
+	case <-sig:
+	}
+}
+
+type killCmd struct {
+	id int
+}
+
+func (k *killCmd) Init() error {
+	return nil
+}
+
+func (k *killCmd) Name() []string {
+	return []string{`\kill`}
+}
+
+func (k *killCmd) Input(input string) (cmdInputStatusType, error) {
+	id, err := parseJobID(input)
+	if err != nil {
+		return invalidCMD, err
+	}
+	k.id = id
+	return preparedCMD, nil
+}
+
+// Eval cancels the job by closing its connection.
+func (k *killCmd) Eval(requester *client.Requester) {
+	j := jobs.get(k.id)
+	if j == nil {
+		fmt.Fprintln(os.Stderr, "no such job")
+		return
+	}
+	j.cur.Close()
+	jobs.remove(j)
+	fmt.Printf("%v (killed, %d tuples)\n", j, j.tuples())
+}
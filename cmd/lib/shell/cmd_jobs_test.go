@@ -0,0 +1,152 @@
+package shell
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+type testJobCursor struct {
+	ch     chan interface{}
+	err    error
+	closed bool
+}
+
+func (c *testJobCursor) C() <-chan interface{} {
+	return c.ch
+}
+
+func (c *testJobCursor) Err() error {
+	return c.err
+}
+
+func (c *testJobCursor) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestBackgroundCommandInput(t *testing.T) {
+	Convey("Given a background command struct", t, func() {
+		cmd := bgCmd{}
+		Convey("When input a SELECT statement", func() {
+			status, err := cmd.Input(`\bg select * from hoge;`)
+			Convey("Then it should buffer the statement without the command name", func() {
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				So(cmd.buffer, ShouldEqual, "select * from hoge;")
+			})
+		})
+		Convey("When input a SELECT statement in multiple lines", func() {
+			status, err := cmd.Input(`\bg`)
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, continuousCMD)
+			status, err = cmd.Input("select *")
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, continuousCMD)
+			status, err = cmd.Input("from hoge;")
+			Convey("Then it should buffer the whole statement", func() {
+				So(err, ShouldBeNil)
+				So(status, ShouldEqual, preparedCMD)
+				So(cmd.buffer, ShouldEqual, "select *\nfrom hoge;")
+			})
+		})
+		Convey("When input a statement other than SELECT", func() {
+			status, err := cmd.Input(`\bg drop source hoge;`)
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+				So(status, ShouldEqual, invalidCMD)
+				So(cmd.buffer, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestParseJobID(t *testing.T) {
+	Convey("Given inputs of job control commands", t, func() {
+		Convey("When the ID is omitted", func() {
+			id, err := parseJobID(`\fg`)
+			Convey("Then it should be 0", func() {
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 0)
+			})
+		})
+		Convey("When the ID is given", func() {
+			Convey("Then it should be parsed", func() {
+				id, err := parseJobID(`\kill 2`)
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 2)
+				id, err = parseJobID(`\fg %3`)
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, 3)
+			})
+		})
+		Convey("When the ID is invalid", func() {
+			Convey("Then it should fail", func() {
+				for _, in := range []string{`\fg a`, `\fg 0`, `\fg 1 2`} {
+					_, err := parseJobID(in)
+					So(err, ShouldNotBeNil)
+				}
+			})
+		})
+	})
+}
+
+func TestJobTable(t *testing.T) {
+	Convey("Given a job table having two jobs", t, func() {
+		jt := newJobTable()
+		c1 := &testJobCursor{ch: make(chan interface{})}
+		c2 := &testJobCursor{ch: make(chan interface{})}
+		j1 := jt.start("test", "select *\nfrom hoge;", c1)
+		j2 := jt.start("test", "select * from fuga;", c2)
+
+		Convey("Then they should have sequential IDs", func() {
+			So(j1.id, ShouldEqual, 1)
+			So(j2.id, ShouldEqual, 2)
+			So(jt.list(), ShouldResemble, []*backgroundJob{j1, j2})
+			So(j1.String(), ShouldEqual, "[1] test: select * from hoge;")
+		})
+
+		Convey("Then the latest job should be returned without an ID", func() {
+			So(jt.get(0), ShouldEqual, j2)
+			So(jt.get(1), ShouldEqual, j1)
+			So(jt.get(3), ShouldBeNil)
+		})
+
+		Convey("When a job receives tuples", func() {
+			c1.ch <- map[string]interface{}{"a": 1}
+			c1.ch <- map[string]interface{}{"a": 2}
+			close(c1.ch)
+			select {
+			case <-j1.done:
+			case <-time.After(time.Second):
+				So("timeout", ShouldBeNil)
+			}
+
+			Convey("Then it should count them", func() {
+				So(j1.tuples(), ShouldEqual, 2)
+				So(j1.status(), ShouldEqual, "done")
+				So(j2.status(), ShouldEqual, "running")
+			})
+		})
+
+		Convey("When a job fails", func() {
+			c2.err = errors.New("connection lost")
+			close(c2.ch)
+			<-j2.done
+
+			Convey("Then its status should have the error", func() {
+				So(j2.status(), ShouldEqual, "failed (connection lost)")
+			})
+		})
+
+		Convey("When a job is removed", func() {
+			jt.remove(j2)
+
+			Convey("Then it shouldn't be listed", func() {
+				So(jt.list(), ShouldResemble, []*backgroundJob{j1})
+				So(jt.get(0), ShouldEqual, j1)
+			})
+		})
+	})
+}
@@ -55,12 +55,19 @@ func Launch(c *cli.Context) error {
 		for _, c := range NewFileLoadCommands() {
 			cmds = append(cmds, c)
 		}
+		for _, c := range NewJobCommands() {
+			cmds = append(cmds, c)
+		}
+		for _, c := range NewCaptureCommands() {
+			cmds = append(cmds, c)
+		}
 		app := SetUpCommands(cmds)
 		req, err := newRequester(c)
 		if err != nil {
 			return err
 		}
 		app.Run(req)
+		setOutputFile(nil)
 		return nil
 	}()
 	if err != nil {
@@ -94,6 +94,11 @@ func (b *bqlCmd) Eval(requester *client.Requester) {
 	queries := b.buffer
 	b.buffer = ""
 
+	queries, err := expandVariables(queries)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
 	sendBQLQueries(requester, queries)
 }
 
@@ -102,32 +107,15 @@ func sendBQLQueries(requester *client.Requester, queries string) {
 		fmt.Fprintln(os.Stderr, "cannot make request: no topology set")
 		return
 	}
-	uri := topologiesHeader + "/" + currentTopology.name + "/queries"
-	res, err := requester.Do(client.Post, uri, map[string]interface{}{
-		"queries": queries,
-	})
+	res, err := client.NewWithRequester(requester).Query(currentTopology.name, queries)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "request failed: %v\n", err)
-		return
-	}
-	defer res.Close()
-
-	if res.IsError() {
-		// TODO: provide error reporting utility
-		errRes, err := res.Error()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
-		}
-
-		// TODO: enhance error message
-		fmt.Fprintf(os.Stderr, "request failed: %v: %v: %v\n", errRes.Code,
-			errRes.Message, errRes.Meta)
+		printRequestError(err)
 		return
 	}
 
-	if res.IsStream() {
-		showStreamResponses(res)
+	if res.Cursor != nil {
+		defer res.Cursor.Close()
+		showStreamResponses(res.Cursor)
 		return
 	}
 
@@ -135,49 +123,52 @@ func sendBQLQueries(requester *client.Requester, queries string) {
 	// if so, print it.
 	// NB. We should also display some more status information that
 	// is reported by most statements.
-	var data map[string]interface{}
-	err = res.ReadJSON(&data)
-	if err == nil {
-		result, ok := data["result"]
-		if ok {
-			printJSONResult(result)
-		}
+	if result, ok := res.Body["result"]; ok {
+		printJSONResult(result)
 	}
+}
 
+// printRequestError prints an error returned from the client.
+func printRequestError(err error) {
+	if e, ok := err.(*client.APIError); ok {
+		// TODO: enhance error message
+		fmt.Fprintf(os.Stderr, "request failed: %v: %v: %v\n", e.Code,
+			e.Message, e.Meta)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "request failed: %v\n", err)
 }
 
-// printJSONResult prints a result in JSON format. This function directly print
-// an error message on failure and doesn't return an error.
+// printJSONResult prints a result in JSON format to the output, which is
+// changed by the \o command. This function directly print an error message on
+// failure and doesn't return an error.
 func printJSONResult(v interface{}) {
 	data, err := json.Marshal(v)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "cannot marshal the result into a JSON: %v\n", err)
 		return
 	}
-	fmt.Printf("%s\n", data)
+	fmt.Fprintf(output, "%s\n", data)
 }
 
-func showStreamResponses(res *client.Response) {
-	ch, err := res.ReadStreamJSON()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return
-	}
-
+func showStreamResponses(cur *client.Cursor) {
 	sig := make(chan os.Signal)
 	signal.Notify(sig, os.Interrupt)
 	defer signal.Stop(sig)
 
 	for {
 		select {
-		case js, ok := <-ch:
+		case js, ok := <-cur.C():
 			if !ok {
+				if err := cur.Err(); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
 				return
 			}
 			printJSONResult(js)
 
 		case <-sig:
-			return // The response is closed by the caller
+			return // The cursor is closed by the caller
 		}
 	}
 }
@@ -0,0 +1,164 @@
+/*
+Package test implements sensorbee test command. This command runs test cases
+of BQL statements written in .bqltest files. See the testkit package for the
+format of the files.
+
+When the trace flag is given, the command runs in the golden mode instead. It
+replays a recorded trace of inputs through statements in a BQL file and
+reports divergences of the outputs from the golden output. The golden output
+is (re)written with the update flag.
+*/
+package test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/testkit"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// SetUp sets up a command for testing BQL statements.
+func SetUp() cli.Command {
+	cmd := cli.Command{
+		Name:        "test",
+		Usage:       "run tests of BQL statements",
+		ArgsUsage:   "BQLTEST_FILE...",
+		Description: "test command runs table-driven test cases written in .bqltest files",
+		Action:      Run,
+	}
+
+	cmd.Flags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "verbose, v",
+			Usage: "print passed cases as well",
+		},
+		cli.StringFlag{
+			Name:  "trace",
+			Usage: "run the golden mode replaying the trace file recorded by trace_recorder sink",
+		},
+		cli.StringFlag{
+			Name:  "bql, b",
+			Usage: "BQL file having statements tested in the golden mode",
+		},
+		cli.StringFlag{
+			Name:  "golden, g",
+			Usage: "file having the golden output in the golden mode",
+		},
+		cli.BoolFlag{
+			Name:  "update",
+			Usage: "write the output to the golden file instead of comparing them",
+		},
+		cli.StringSliceFlag{
+			Name:  "stream, s",
+			Usage: "stream whose output is recorded in the golden mode (all streams by default)",
+		},
+		cli.Float64Flag{
+			Name:  "float-tolerance",
+			Usage: "maximum absolute difference of numbers considered equal",
+		},
+		cli.DurationFlag{
+			Name:  "timestamp-tolerance",
+			Usage: "maximum difference of timestamps considered equal",
+		},
+	}
+	return cmd
+}
+
+// Run runs "test" command.
+func Run(c *cli.Context) error {
+	if c.IsSet("trace") {
+		return runGolden(c)
+	}
+	if len(c.Args()) == 0 {
+		cli.ShowSubcommandHelp(c)
+		os.Exit(1)
+	}
+
+	emptyError := fmt.Errorf("") // to provide exit code but not error message for cli
+	numPassed, numFailed := 0, 0
+	for _, f := range c.Args() {
+		results, err := testkit.RunFile(f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", f, err)
+			numFailed++
+			continue
+		}
+		for _, r := range results {
+			if r.Passed() {
+				numPassed++
+				if c.Bool("verbose") {
+					fmt.Printf("PASS: %v: %v\n", f, r.Name)
+				}
+				continue
+			}
+			numFailed++
+			fmt.Printf("FAIL: %v: %v\n", f, r.Name)
+			if r.Err != nil {
+				fmt.Printf("    %v\n", r.Err)
+			}
+			for _, failure := range r.Failures {
+				fmt.Printf("    %v\n", failure)
+			}
+		}
+	}
+	fmt.Printf("%v passed, %v failed\n", numPassed, numFailed)
+	if numFailed > 0 {
+		return emptyError
+	}
+	return nil
+}
+
+// runGolden runs the golden mode.
+func runGolden(c *cli.Context) error {
+	emptyError := fmt.Errorf("") // to provide exit code but not error message for cli
+	if c.String("bql") == "" || c.String("golden") == "" {
+		fmt.Fprintln(os.Stderr, "bql and golden flags are required in the golden mode")
+		return emptyError
+	}
+
+	actual, err := func() ([]*testkit.TraceRecord, error) {
+		stmts, err := ioutil.ReadFile(c.String("bql"))
+		if err != nil {
+			return nil, err
+		}
+		input, err := testkit.ReadTraceFile(c.String("trace"))
+		if err != nil {
+			return nil, err
+		}
+		return testkit.RunGolden(string(stmts), input, c.StringSlice("stream"))
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot replay the trace: %v\n", err)
+		return emptyError
+	}
+
+	if c.Bool("update") {
+		if err := testkit.WriteTraceFile(c.String("golden"), actual); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot write the golden file: %v\n", err)
+			return emptyError
+		}
+		fmt.Printf("%v tuples were written to %v\n", len(actual), c.String("golden"))
+		return nil
+	}
+
+	golden, err := testkit.ReadTraceFile(c.String("golden"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot read the golden file: %v\n", err)
+		return emptyError
+	}
+	diffs := testkit.Diff(golden, actual, testkit.Tolerance{
+		Float:     c.Float64("float-tolerance"),
+		Timestamp: c.Duration("timestamp-tolerance"),
+	})
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	if len(diffs) > 0 {
+		fmt.Printf("FAIL: %v divergences from the golden output\n", len(diffs))
+		return emptyError
+	}
+	fmt.Printf("PASS: %v tuples matched the golden output\n", len(actual))
+	return nil
+}
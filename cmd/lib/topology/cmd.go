@@ -24,6 +24,7 @@ func SetUp() cli.Command {
 			setUpCreate(),
 			setUpList(),
 			setUpDrop(),
+			setUpGraph(),
 		},
 	}
 	return cmd
@@ -79,6 +80,23 @@ func newRequester(c *cli.Context) (*client.Requester, error) {
 	return r, nil
 }
 
+func newClient(c *cli.Context) (*client.Client, error) {
+	r, err := newRequester(c)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewWithRequester(r), nil
+}
+
+// clientError converts an error returned from client.Client into an error
+// message of the command.
+func clientError(baseErrMsg string, err error) error {
+	if e, ok := err.(*client.APIError); ok {
+		return fmt.Errorf("%v: %v, %v: %v", baseErrMsg, e.Code, e.RequestID, e.Message)
+	}
+	return fmt.Errorf("%v: %v", baseErrMsg, err)
+}
+
 func do(c *cli.Context, method client.Method, path string, body interface{}, baseErrMsg string) (*client.Response, error) {
 	req, err := newRequester(c)
 	if err != nil {
@@ -2,7 +2,6 @@ package topology
 
 import (
 	"fmt"
-	"gopkg.in/sensorbee/sensorbee.v0/client"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -32,12 +31,13 @@ func runCreate(c *cli.Context) error {
 		return fmt.Errorf("too many command line arguments")
 	}
 
-	res, err := do(c, client.Post, "topologies", map[string]interface{}{
-		"name": args[0],
-	}, "Cannot create a topology")
+	cl, err := newClient(c)
 	if err != nil {
 		return err
 	}
+	if err := cl.CreateTopology(args[0], nil); err != nil {
+		return clientError("Cannot create a topology", err)
+	}
 	// TODO: show something about the created topology
-	return res.Close()
+	return nil
 }
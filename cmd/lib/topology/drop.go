@@ -2,10 +2,8 @@ package topology
 
 import (
 	"fmt"
-	"gopkg.in/sensorbee/sensorbee.v0/client"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/urfave/cli.v1"
-	"path"
 )
 
 func setUpDrop() cli.Command {
@@ -39,9 +37,12 @@ func runDrop(c *cli.Context) error {
 		// This is checked here to avoid sending wrong DELETE request to different URL.
 		return fmt.Errorf("The name of the topology is invalid: %v", err)
 	}
-	res, err := do(c, client.Delete, path.Join("topologies", name), nil, "Cannot drop a topology")
+	cl, err := newClient(c)
 	if err != nil {
 		return err
 	}
-	return res.Close()
+	if err := cl.DropTopology(name); err != nil {
+		return clientError("Cannot drop a topology", err)
+	}
+	return nil
 }
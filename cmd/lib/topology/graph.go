@@ -0,0 +1,81 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/sensorbee/sensorbee.v0/client"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/server/response"
+	"gopkg.in/urfave/cli.v1"
+	"path"
+)
+
+func setUpGraph() cli.Command {
+	return cli.Command{
+		Name:    "graph",
+		Aliases: []string{"g"},
+		Usage:   "export the graph of a topology",
+		Description: "sensorbee topology graph <topology_name> prints the graph of nodes and edges " +
+			"of the topology in Graphviz DOT or JSON",
+		Action: actionWrapper(runGraph),
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "format, f",
+				Value: "dot",
+				Usage: "the output format (dot or json)",
+			},
+		}, commonFlags...),
+	}
+}
+
+func runGraph(c *cli.Context) error {
+	if err := validateFlags(c); err != nil {
+		return err
+	}
+
+	format := c.String("format")
+	switch format {
+	case "dot", "json":
+	default:
+		return fmt.Errorf("--format flag must be dot or json: %v", format)
+	}
+
+	args := c.Args()
+	switch l := len(args); l {
+	case 1:
+		// ok
+	case 0:
+		return fmt.Errorf("topology_name is missing")
+	default:
+		return fmt.Errorf("too many command line arguments")
+	}
+
+	name := args[0]
+	if err := core.ValidateSymbol(name); err != nil {
+		return fmt.Errorf("The name of the topology is invalid: %v", err)
+	}
+	res, err := do(c, client.Get, path.Join("topologies", name, "graph"), nil, "Cannot get the graph of the topology")
+	if err != nil {
+		return err
+	}
+	g := struct {
+		Graph *response.TopologyGraph `json:"graph"`
+	}{}
+	if err := res.ReadJSON(&g); err != nil { // ReadJSON closes the body
+		return fmt.Errorf("Cannot read a response: %v", err)
+	}
+	if g.Graph == nil {
+		return fmt.Errorf("The response doesn't have a graph")
+	}
+
+	if format == "dot" {
+		fmt.Fprint(c.App.Writer, g.Graph.DOT())
+		return nil
+	}
+	js, err := json.MarshalIndent(g.Graph, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Cannot encode the graph: %v", err)
+	}
+	fmt.Fprintln(c.App.Writer, string(js))
+	return nil
+}
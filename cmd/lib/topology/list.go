@@ -2,8 +2,6 @@ package topology
 
 import (
 	"fmt"
-	"gopkg.in/sensorbee/sensorbee.v0/client"
-	"gopkg.in/sensorbee/sensorbee.v0/server/response"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -29,19 +27,17 @@ func runList(c *cli.Context) error {
 		return fmt.Errorf("too many command line arguments")
 	}
 
-	res, err := do(c, client.Get, "topologies", nil, "Cannot get a list of topologies")
+	cl, err := newClient(c)
 	if err != nil {
 		return err
 	}
-	ts := struct {
-		Topologies []*response.Topology `json:"topologies"`
-	}{}
-	if err := res.ReadJSON(&ts); err != nil { // ReadJSON closes the body
-		return fmt.Errorf("Cannot read a response: %v", err)
+	names, err := cl.Topologies()
+	if err != nil {
+		return clientError("Cannot get a list of topologies", err)
 	}
 
-	for _, t := range ts.Topologies {
-		fmt.Fprintln(c.App.Writer, t.Name)
+	for _, n := range names {
+		fmt.Fprintln(c.App.Writer, n)
 	}
 	return nil
 }
@@ -0,0 +1,83 @@
+/*
+Package validate implements sensorbee validate command. This command checks
+BQL files without running them. It reports syntax errors and suspicious
+constructs found by the linter, and can print statements in the canonical
+format.
+*/
+package validate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// SetUp sets up a command for validating BQL files.
+func SetUp() cli.Command {
+	cmd := cli.Command{
+		Name:        "validate",
+		Usage:       "validate BQL files",
+		ArgsUsage:   "BQL_FILE...",
+		Description: "validate command parses BQL files and reports syntax errors and lint issues",
+		Action:      Run,
+	}
+
+	cmd.Flags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "format, f",
+			Usage: "print statements in the canonical format",
+		},
+		cli.BoolFlag{
+			Name:  "strict",
+			Usage: "fail when lint issues are found",
+		},
+	}
+	return cmd
+}
+
+// Run runs "validate" command.
+func Run(c *cli.Context) error {
+	if len(c.Args()) == 0 {
+		cli.ShowSubcommandHelp(c)
+		os.Exit(1)
+	}
+
+	emptyError := fmt.Errorf("") // to provide exit code but not error message for cli
+	failed := false
+	for _, f := range c.Args() {
+		issues, err := validateFile(f, c.Bool("format"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", f, err)
+			failed = true
+			continue
+		}
+		for _, i := range issues {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", f, i)
+		}
+		if c.Bool("strict") && len(issues) > 0 {
+			failed = true
+		}
+	}
+	if failed {
+		return emptyError
+	}
+	return nil
+}
+
+func validateFile(path string, format bool) ([]parser.LintIssue, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	stmts, err := parser.New().ParseStmts(string(b))
+	if err != nil {
+		return nil, err
+	}
+	if format {
+		fmt.Print(parser.FormatStmts(stmts))
+	}
+	return parser.Lint(stmts), nil
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // A Box is an elementary building block of a SensorBee topology.
@@ -165,6 +167,20 @@ type boxWriterAdapter struct {
 	box  Box
 	name string
 	dst  *traceWriter
+
+	// timeout is the maximum duration of Process. numTimeouts is incremented
+	// when Process times out.
+	timeout     time.Duration
+	numTimeouts *int64
+
+	// quarantine, if not nil, recovers panics in Process and counts them.
+	quarantine *quarantine
+
+	// usage, if not nil, records tuples processed by the box.
+	usage *topologyUsage
+
+	// latency, if not nil, records durations of Process.
+	latency *latencyHistogram
 }
 
 func newBoxWriterAdapter(b Box, name string, dst WriteCloser) *boxWriterAdapter {
@@ -177,6 +193,108 @@ func newBoxWriterAdapter(b Box, name string, dst WriteCloser) *boxWriterAdapter
 }
 
 func (wa *boxWriterAdapter) Write(ctx *Context, t *Tuple) error {
+	if wa.usage != nil || wa.latency != nil {
+		start := time.Now()
+		defer func() {
+			d := time.Now().Sub(start)
+			if wa.usage != nil {
+				wa.usage.processed(d)
+			}
+			if wa.latency != nil {
+				wa.latency.observe(d)
+			}
+		}()
+	}
+	if wa.quarantine == nil {
+		return wa.process(ctx, t)
+	}
+
+	if wa.quarantine.drop() {
+		ctx.droppedTuple(t, NTBox, wa.name, ETInput, fmt.Errorf("the box '%v' is in quarantine", wa.name))
+		return nil
+	}
+	err := wa.processRecovering(ctx, t)
+	if IsPanicError(err) && wa.quarantine.record(err) {
+		ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTBox, wa.name)).
+			Error("The box was put into quarantine due to too many panics")
+	}
+	return err
+}
+
+func (wa *boxWriterAdapter) process(ctx *Context, t *Tuple) error {
 	tracing(t, ctx, ETInput, wa.name)
-	return wa.box.Process(ctx, t, wa.dst)
+	if wa.timeout <= 0 {
+		return wa.box.Process(ctx, t, wa.dst)
+	}
+	return wa.processWithTimeout(ctx, t)
+}
+
+// processRecovering calls process and converts a panic in it to an error
+// satisfying IsPanicError so that the box doesn't stop.
+func (wa *boxWriterAdapter) processRecovering(ctx *Context, t *Tuple) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = PanicError(fmt.Errorf("the box '%v' panicked: %v", wa.name, e))
+		}
+	}()
+	return wa.process(ctx, t)
+}
+
+// processWithTimeout calls Process in another goroutine and waits for it
+// until the timeout expires. A panic in Process is propagated to the caller
+// when it occurs before the timeout so that the box behaves in the same way
+// as it does without a timeout.
+func (wa *boxWriterAdapter) processWithTimeout(ctx *Context, t *Tuple) error {
+	c, cancel := ctx.WithTimeout(wa.timeout)
+	defer cancel()
+
+	type result struct {
+		err   error
+		panic interface{}
+	}
+	w := &cancelableWriter{w: wa.dst, ctx: c}
+	ch := make(chan result, 1)
+	go func() {
+		var res result
+		defer func() {
+			if e := recover(); e != nil {
+				res.panic = e
+			}
+			ch <- res
+		}()
+		res.err = wa.box.Process(c, t, w)
+	}()
+
+	select {
+	case res := <-ch:
+		if res.panic != nil {
+			panic(res.panic)
+		}
+		return res.err
+	case <-c.Done():
+		if wa.numTimeouts != nil {
+			atomic.AddInt64(wa.numTimeouts, 1)
+		}
+		go func() {
+			if res := <-ch; res.panic != nil {
+				ctx.ErrLog(fmt.Errorf("%v", res.panic)).WithFields(ctx.nodeLogFields(NTBox, wa.name)).
+					Error("The box panicked after its Process timed out")
+			}
+		}()
+		return TimeoutError(fmt.Errorf("the box '%v' didn't process a tuple within %v", wa.name, wa.timeout))
+	}
+}
+
+// cancelableWriter is a Writer which discards tuples after the Context is
+// canceled.
+type cancelableWriter struct {
+	w   Writer
+	ctx *Context
+}
+
+func (cw *cancelableWriter) Write(ctx *Context, t *Tuple) error {
+	if err := cw.ctx.Err(); err != nil {
+		return fmt.Errorf("the tuple cannot be written after the cancellation: %v", err)
+	}
+	return cw.w.Write(ctx, t)
 }
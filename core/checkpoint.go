@@ -0,0 +1,387 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// CheckpointConfig has parameters of checkpoints of a topology. A checkpoint
+// is a snapshot of states of nodes in the topology such as UDSs and windows
+// of boxes. It's taken periodically or on demand with Topology.Checkpoint, and
+// it's restored with Topology.RestoreCheckpoint after the topology is created
+// again, for example, after a server restarts.
+//
+// Each state and each box is saved separately as an entry of the checkpoint.
+// Tuples flowing in the topology aren't stopped while a checkpoint is taken,
+// so a checkpoint isn't globally consistent: entries may be taken at slightly
+// different moments and tuples in queues between nodes aren't saved.
+type CheckpointConfig struct {
+	// Storage stores checkpoints. It's required.
+	Storage CheckpointStorage
+
+	// Interval is the interval of taking checkpoints periodically. When it's
+	// zero, checkpoints are only taken by Topology.Checkpoint.
+	Interval time.Duration
+}
+
+// CheckpointStorage stores entries of checkpoints. kind is the kind of the
+// entry, which is one of "state", "box", or "manifest", and name is the name
+// of the node or state. Only the latest entry is kept for each pair of kind
+// and name.
+type CheckpointStorage interface {
+	// Save returns a writer to write an entry. The previous entry isn't
+	// discarded until CheckpointWriter.Commit is called.
+	Save(topology, kind, name string) (CheckpointWriter, error)
+
+	// Load returns a reader of the entry. It returns NotExistError when the
+	// entry doesn't exist.
+	Load(topology, kind, name string) (io.ReadCloser, error)
+}
+
+// CheckpointWriter writes an entry of a checkpoint. Either Commit or Abort
+// has to be called.
+type CheckpointWriter interface {
+	io.Writer
+
+	// Commit persists the entry and closes the writer.
+	Commit() error
+
+	// Abort discards the entry and closes the writer.
+	Abort() error
+}
+
+// CheckpointableBox is a Box having an internal state which can be included
+// in checkpoints, such as windows of tuples.
+type CheckpointableBox interface {
+	Box
+
+	// SaveCheckpoint writes the internal state of the box to w. It can be
+	// called concurrently with Process.
+	SaveCheckpoint(ctx *Context, w io.Writer) error
+
+	// LoadCheckpoint overwrites the internal state of the box with the data
+	// written by SaveCheckpoint. It can be called concurrently with Process.
+	LoadCheckpoint(ctx *Context, r io.Reader) error
+}
+
+const (
+	checkpointKindState    = "state"
+	checkpointKindBox      = "box"
+	checkpointKindManifest = "manifest"
+
+	checkpointManifestName = "checkpoint"
+)
+
+// checkpointManifest describes a checkpoint. It's written after all other
+// entries are committed, so a checkpoint isn't visible until all entries are
+// saved. However, entries of a checkpoint which failed in the middle might
+// overwrite some entries of the previous checkpoint.
+type checkpointManifest struct {
+	ID     int64     `json:"id"`
+	Time   time.Time `json:"time"`
+	States []string  `json:"states"`
+	Boxes  []string  `json:"boxes"`
+}
+
+func (m *checkpointManifest) status() data.Map {
+	states := make(data.Array, len(m.States))
+	for i, s := range m.States {
+		states[i] = data.String(s)
+	}
+	boxes := make(data.Array, len(m.Boxes))
+	for i, b := range m.Boxes {
+		boxes[i] = data.String(b)
+	}
+	return data.Map{
+		"id":     data.Int(m.ID),
+		"time":   data.Timestamp(m.Time),
+		"states": states,
+		"boxes":  boxes,
+	}
+}
+
+// topologyCheckpointer takes and restores checkpoints of a topology.
+type topologyCheckpointer struct {
+	t      *defaultTopology
+	config *CheckpointConfig
+
+	// m serializes checkpoints and restorations.
+	m sync.Mutex
+
+	// statusMutex protects fields below.
+	statusMutex  sync.Mutex
+	last         *checkpointManifest
+	lastErr      error
+	lastErrTime  time.Time
+	lastRestored *checkpointManifest
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// newTopologyCheckpointer creates a new topologyCheckpointer. When the
+// interval is configured, it starts taking checkpoints periodically and stop
+// has to be called to stop it. config can be nil.
+func newTopologyCheckpointer(t *defaultTopology, config *CheckpointConfig) *topologyCheckpointer {
+	c := &topologyCheckpointer{
+		t:      t,
+		config: config,
+		stopCh: make(chan struct{}),
+	}
+	if c.enabled() && config.Interval > 0 {
+		go c.run()
+	}
+	return c
+}
+
+func (c *topologyCheckpointer) enabled() bool {
+	return c.config != nil && c.config.Storage != nil
+}
+
+func (c *topologyCheckpointer) run() {
+	t := time.NewTicker(c.config.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-t.C:
+			if _, err := c.checkpoint(); err != nil {
+				c.t.ctx.ErrLog(err).Error("Cannot take a checkpoint of the topology")
+			}
+		}
+	}
+}
+
+// stop stops taking checkpoints periodically.
+func (c *topologyCheckpointer) stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
+
+var errCheckpointDisabled = errors.New("checkpoints aren't enabled in the topology")
+
+// checkpoint takes a checkpoint of states and boxes of the topology.
+func (c *topologyCheckpointer) checkpoint() (data.Map, error) {
+	if !c.enabled() {
+		return nil, errCheckpointDisabled
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	m, err := c.save()
+	c.statusMutex.Lock()
+	if err != nil {
+		c.lastErr = err
+		c.lastErrTime = time.Now()
+		c.statusMutex.Unlock()
+		return nil, err
+	}
+	c.last = m
+	c.lastErr = nil
+	c.statusMutex.Unlock()
+
+	c.t.ctx.lifecycleEvent(&LifecycleEvent{
+		Type:   LECheckpointCompleted,
+		Detail: m.status(),
+	})
+	return m.status(), nil
+}
+
+func (c *topologyCheckpointer) save() (*checkpointManifest, error) {
+	ctx := c.t.ctx
+	m := &checkpointManifest{
+		Time:   time.Now(),
+		States: []string{},
+		Boxes:  []string{},
+	}
+	if c.last != nil {
+		m.ID = c.last.ID
+	}
+	if c.lastRestored != nil && c.lastRestored.ID > m.ID {
+		m.ID = c.lastRestored.ID
+	}
+	m.ID++
+
+	states, err := ctx.SharedStates.List()
+	if err != nil {
+		return nil, err
+	}
+	for name, s := range states {
+		st, ok := s.(SavableSharedState)
+		if !ok {
+			continue
+		}
+		if err := c.saveEntry(checkpointKindState, name, func(w io.Writer) error {
+			return st.Save(ctx, w, data.Map{})
+		}); err != nil {
+			return nil, fmt.Errorf("cannot save the state '%v': %v", name, err)
+		}
+		m.States = append(m.States, name)
+	}
+
+	for name, b := range c.t.Boxes() {
+		cb, ok := b.Box().(CheckpointableBox)
+		if !ok {
+			continue
+		}
+		if err := c.saveEntry(checkpointKindBox, name, func(w io.Writer) error {
+			return cb.SaveCheckpoint(ctx, w)
+		}); err != nil {
+			return nil, fmt.Errorf("cannot save the box '%v': %v", name, err)
+		}
+		m.Boxes = append(m.Boxes, name)
+	}
+	sort.Strings(m.States)
+	sort.Strings(m.Boxes)
+
+	if err := c.saveEntry(checkpointKindManifest, checkpointManifestName, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(m)
+	}); err != nil {
+		return nil, fmt.Errorf("cannot save the manifest: %v", err)
+	}
+	return m, nil
+}
+
+func (c *topologyCheckpointer) saveEntry(kind, name string, f func(w io.Writer) error) error {
+	w, err := c.config.Storage.Save(c.t.name, kind, name)
+	if err != nil {
+		return err
+	}
+	if err := f(w); err != nil {
+		w.Abort()
+		return err
+	}
+	return w.Commit()
+}
+
+// restore restores the latest checkpoint. States and boxes have to be
+// created before calling this method. Entries of states or boxes which no
+// longer exist are ignored. It returns nil without an error when there's no
+// checkpoint.
+func (c *topologyCheckpointer) restore() (data.Map, error) {
+	if !c.enabled() {
+		return nil, errCheckpointDisabled
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	m, err := c.load()
+	if err != nil {
+		c.statusMutex.Lock()
+		defer c.statusMutex.Unlock()
+		c.lastErr = err
+		c.lastErrTime = time.Now()
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	c.statusMutex.Lock()
+	defer c.statusMutex.Unlock()
+	c.lastRestored = m
+	return m.status(), nil
+}
+
+func (c *topologyCheckpointer) load() (*checkpointManifest, error) {
+	ctx := c.t.ctx
+	m := &checkpointManifest{}
+	if err := c.loadEntry(checkpointKindManifest, checkpointManifestName, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(m)
+	}); err != nil {
+		if IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot load the manifest: %v", err)
+	}
+
+	var lastErr error
+	for _, name := range m.States {
+		s, err := ctx.SharedStates.Get(name)
+		if err != nil {
+			ctx.Log().WithField("state", name).Warn("The state in the checkpoint doesn't exist")
+			continue
+		}
+		st, ok := s.(LoadableSharedState)
+		if !ok {
+			ctx.Log().WithField("state", name).Warn("The state in the checkpoint cannot be loaded")
+			continue
+		}
+		if err := c.loadEntry(checkpointKindState, name, func(r io.Reader) error {
+			return st.Load(ctx, r, data.Map{})
+		}); err != nil {
+			lastErr = fmt.Errorf("cannot load the state '%v': %v", name, err)
+			ctx.ErrLog(err).WithField("state", name).Error("Cannot restore the state")
+		}
+	}
+
+	for _, name := range m.Boxes {
+		b, err := c.t.Box(name)
+		if err != nil {
+			ctx.Log().WithFields(ctx.nodeLogFields(NTBox, name)).
+				Warn("The box in the checkpoint doesn't exist")
+			continue
+		}
+		cb, ok := b.Box().(CheckpointableBox)
+		if !ok {
+			ctx.Log().WithFields(ctx.nodeLogFields(NTBox, name)).
+				Warn("The box in the checkpoint cannot be restored")
+			continue
+		}
+		if err := c.loadEntry(checkpointKindBox, name, func(r io.Reader) error {
+			return cb.LoadCheckpoint(ctx, r)
+		}); err != nil {
+			lastErr = fmt.Errorf("cannot load the box '%v': %v", name, err)
+			ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTBox, name)).
+				Error("Cannot restore the box")
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return m, nil
+}
+
+func (c *topologyCheckpointer) loadEntry(kind, name string, f func(r io.Reader) error) error {
+	r, err := c.config.Storage.Load(c.t.name, kind, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return f(r)
+}
+
+// status returns the status of checkpoints.
+func (c *topologyCheckpointer) status() data.Map {
+	st := data.Map{
+		"enabled": data.Bool(c.enabled()),
+	}
+	if !c.enabled() {
+		return st
+	}
+	st["interval"] = data.Float(c.config.Interval.Seconds())
+
+	c.statusMutex.Lock()
+	defer c.statusMutex.Unlock()
+	if c.last != nil {
+		st["last_checkpoint"] = c.last.status()
+	}
+	if c.lastRestored != nil {
+		st["last_restored"] = c.lastRestored.status()
+	}
+	if c.lastErr != nil {
+		st["last_error"] = data.Map{
+			"time":  data.Timestamp(c.lastErrTime),
+			"error": data.String(c.lastErr.Error()),
+		}
+	}
+	return st
+}
@@ -0,0 +1,297 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type testCheckpointStorage struct {
+	m       sync.Mutex
+	entries map[string][]byte
+}
+
+func newTestCheckpointStorage() *testCheckpointStorage {
+	return &testCheckpointStorage{
+		entries: map[string][]byte{},
+	}
+}
+
+func (s *testCheckpointStorage) Save(topology, kind, name string) (CheckpointWriter, error) {
+	return &testCheckpointWriter{
+		s:   s,
+		key: topology + "/" + kind + "/" + name,
+	}, nil
+}
+
+func (s *testCheckpointStorage) Load(topology, kind, name string) (io.ReadCloser, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	b, ok := s.entries[topology+"/"+kind+"/"+name]
+	if !ok {
+		return nil, NotExistError(errors.New("the entry doesn't exist"))
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *testCheckpointStorage) has(key string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	_, ok := s.entries[key]
+	return ok
+}
+
+type testCheckpointWriter struct {
+	s   *testCheckpointStorage
+	key string
+	buf bytes.Buffer
+}
+
+func (w *testCheckpointWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *testCheckpointWriter) Commit() error {
+	w.s.m.Lock()
+	defer w.s.m.Unlock()
+	w.s.entries[w.key] = w.buf.Bytes()
+	return nil
+}
+
+func (w *testCheckpointWriter) Abort() error {
+	return nil
+}
+
+// checkpointCounterState is a loadable state having a number.
+type checkpointCounterState struct {
+	m   sync.Mutex
+	num int64
+}
+
+func (s *checkpointCounterState) Terminate(ctx *Context) error {
+	return nil
+}
+
+func (s *checkpointCounterState) Save(ctx *Context, w io.Writer, params data.Map) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return binary.Write(w, binary.LittleEndian, s.num)
+}
+
+func (s *checkpointCounterState) Load(ctx *Context, r io.Reader, params data.Map) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return binary.Read(r, binary.LittleEndian, &s.num)
+}
+
+func (s *checkpointCounterState) value() int64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.num
+}
+
+// checkpointCounterBox counts tuples it processed.
+type checkpointCounterBox struct {
+	m    sync.Mutex
+	num  int64
+	fail bool
+}
+
+func (b *checkpointCounterBox) Process(ctx *Context, t *Tuple, w Writer) error {
+	b.m.Lock()
+	b.num++
+	b.m.Unlock()
+	return w.Write(ctx, t)
+}
+
+func (b *checkpointCounterBox) SaveCheckpoint(ctx *Context, w io.Writer) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if b.fail {
+		return errors.New("box failure")
+	}
+	return binary.Write(w, binary.LittleEndian, b.num)
+}
+
+func (b *checkpointCounterBox) LoadCheckpoint(ctx *Context, r io.Reader) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return binary.Read(r, binary.LittleEndian, &b.num)
+}
+
+func (b *checkpointCounterBox) value() int64 {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.num
+}
+
+func TestTopologyCheckpoint(t *testing.T) {
+	Convey("Given a topology having checkpoints enabled", t, func() {
+		storage := newTestCheckpointStorage()
+		newTopology := func() (Topology, *checkpointCounterState, *checkpointCounterBox) {
+			ctx := NewContext(&ContextConfig{
+				Checkpoint: &CheckpointConfig{
+					Storage: storage,
+				},
+			})
+			t, err := NewDefaultTopology(ctx, "test")
+			So(err, ShouldBeNil)
+			s := &checkpointCounterState{}
+			So(ctx.SharedStates.Add("counter", "test_counter", s), ShouldBeNil)
+			b := &checkpointCounterBox{}
+			_, err = t.AddBox("box", b, nil)
+			So(err, ShouldBeNil)
+			_, err = t.AddBox("stateless", BoxFunc(forwardBox), nil)
+			So(err, ShouldBeNil)
+			return t, s, b
+		}
+		t, s, b := newTopology()
+		Reset(func() {
+			t.Stop()
+		})
+
+		so := NewTupleIncrementalEmitterSource(freshTuples())
+		_, err := t.AddSource("source", so, nil)
+		So(err, ShouldBeNil)
+		bn, err := t.Box("box")
+		So(err, ShouldBeNil)
+		So(bn.Input("source", nil), ShouldBeNil)
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("box", nil), ShouldBeNil)
+
+		so.EmitTuples(3)
+		si.Wait(3)
+		s.num = 10
+
+		Convey("When restoring a checkpoint before taking one", func() {
+			info, err := t.RestoreCheckpoint()
+
+			Convey("Then it should do nothing", func() {
+				So(err, ShouldBeNil)
+				So(info, ShouldBeNil)
+				So(b.value(), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When taking a checkpoint", func() {
+			info, err := t.Checkpoint()
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have saved the state and the box", func() {
+				So(info["id"], ShouldEqual, data.Int(1))
+				So(info["states"], ShouldResemble, data.Array{data.String("counter")})
+				So(info["boxes"], ShouldResemble, data.Array{data.String("box")})
+				So(storage.has("test/manifest/checkpoint"), ShouldBeTrue)
+			})
+
+			Convey("Then the status should have the checkpoint", func() {
+				st := t.CheckpointStatus()
+				So(st["enabled"], ShouldEqual, data.True)
+				So(st["last_checkpoint"], ShouldResemble, info)
+				So(st, ShouldNotContainKey, "last_error")
+			})
+
+			Convey("And restoring it in a new topology", func() {
+				t2, s2, b2 := newTopology()
+				Reset(func() {
+					t2.Stop()
+				})
+				info2, err := t2.RestoreCheckpoint()
+				So(err, ShouldBeNil)
+
+				Convey("Then the state and the box should be restored", func() {
+					So(info2["id"], ShouldEqual, info["id"])
+					So(info2["states"], ShouldResemble, info["states"])
+					So(s2.value(), ShouldEqual, 10)
+					So(b2.value(), ShouldEqual, 3)
+				})
+
+				Convey("Then the next checkpoint should have the next id", func() {
+					info3, err := t2.Checkpoint()
+					So(err, ShouldBeNil)
+					So(info3["id"], ShouldEqual, data.Int(2))
+					So(t2.CheckpointStatus()["last_restored"], ShouldContainKey, "time")
+				})
+			})
+		})
+
+		Convey("When taking a checkpoint fails", func() {
+			b.m.Lock()
+			b.fail = true
+			b.m.Unlock()
+			_, err := t.Checkpoint()
+
+			Convey("Then it should fail without writing the manifest", func() {
+				So(err, ShouldNotBeNil)
+				So(storage.has("test/manifest/checkpoint"), ShouldBeFalse)
+			})
+
+			Convey("Then the status should have the error", func() {
+				st := t.CheckpointStatus()
+				So(st, ShouldContainKey, "last_error")
+				So(st, ShouldNotContainKey, "last_checkpoint")
+			})
+		})
+
+		Convey("When the topology is stopped", func() {
+			So(t.Stop(), ShouldBeNil)
+
+			Convey("Then a checkpoint cannot be taken", func() {
+				_, err := t.Checkpoint()
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a topology taking checkpoints periodically", t, func() {
+		storage := newTestCheckpointStorage()
+		ctx := NewContext(&ContextConfig{
+			Checkpoint: &CheckpointConfig{
+				Storage:  storage,
+				Interval: 10 * time.Millisecond,
+			},
+		})
+		t, err := NewDefaultTopology(ctx, "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		Convey("When waiting for a while", func() {
+			for i := 0; i < 500 && t.CheckpointStatus()["last_checkpoint"] == nil; i++ {
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then checkpoints should be taken", func() {
+				So(t.CheckpointStatus(), ShouldContainKey, "last_checkpoint")
+				So(storage.has("test/manifest/checkpoint"), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a topology without checkpoints", t, func() {
+		t, err := NewDefaultTopology(NewContext(nil), "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		Convey("Then checkpoints should be disabled", func() {
+			_, err := t.Checkpoint()
+			So(err, ShouldNotBeNil)
+			_, err = t.RestoreCheckpoint()
+			So(err, ShouldNotBeNil)
+			So(t.CheckpointStatus(), ShouldResemble, data.Map{"enabled": data.False})
+		})
+	})
+}
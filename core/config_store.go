@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// ConfigStore is a key/value store of configuration parameters tied to a
+// Context. It's populated from the server config and BQL statements, and
+// UDFs, boxes, sources, and sinks read their parameters from it instead of
+// reading environment variables by themselves. Because a Context is tied to
+// one topology, parameters can differ from topology to topology.
+//
+// Typed getters convert a value with data.ToXXX functions so that a value
+// given as a string in a config file, e.g. "10", can be read as an integer.
+// All getters return an error satisfying IsNotExist when the key isn't
+// registered.
+//
+// ConfigStore is thread-safe.
+type ConfigStore struct {
+	m      sync.RWMutex
+	values data.Map
+}
+
+// NewConfigStore creates a new ConfigStore having a copy of the given
+// values. values can be nil.
+func NewConfigStore(values data.Map) *ConfigStore {
+	s := &ConfigStore{
+		values: data.Map{},
+	}
+	if values != nil {
+		s.values = values.Copy()
+	}
+	return s
+}
+
+// Get returns the value associated with the key.
+func (s *ConfigStore) Get(key string) (data.Value, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	v, ok := s.values[key]
+	if !ok {
+		return nil, NotExistError(fmt.Errorf("config '%v' was not found", key))
+	}
+	return copyConfigValue(v), nil
+}
+
+// GetString returns the value associated with the key as a string.
+func (s *ConfigStore) GetString(key string) (string, error) {
+	v, err := s.Get(key)
+	if err != nil {
+		return "", err
+	}
+	return data.ToString(v)
+}
+
+// GetInt returns the value associated with the key as an integer.
+func (s *ConfigStore) GetInt(key string) (int64, error) {
+	v, err := s.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	return data.ToInt(v)
+}
+
+// GetFloat returns the value associated with the key as a float.
+func (s *ConfigStore) GetFloat(key string) (float64, error) {
+	v, err := s.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	return data.ToFloat(v)
+}
+
+// GetBool returns the value associated with the key as a bool.
+func (s *ConfigStore) GetBool(key string) (bool, error) {
+	v, err := s.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return data.ToBool(v)
+}
+
+// GetDuration returns the value associated with the key as a duration. An
+// integer or a float is regarded as seconds.
+func (s *ConfigStore) GetDuration(key string) (time.Duration, error) {
+	v, err := s.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	return data.ToDuration(v)
+}
+
+// Set associates the value with the key. It overwrites the existing value.
+func (s *ConfigStore) Set(key string, v data.Value) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.values[key] = copyConfigValue(v)
+}
+
+// Update sets all values in the map.
+func (s *ConfigStore) Update(values data.Map) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for k, v := range values.Copy() {
+		s.values[k] = v
+	}
+}
+
+// Delete removes the key from the store. It doesn't return an error when
+// the key isn't registered.
+func (s *ConfigStore) Delete(key string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	delete(s.values, key)
+}
+
+// ToMap returns a copy of all parameters in the store.
+func (s *ConfigStore) ToMap() data.Map {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return s.values.Copy()
+}
+
+// copyConfigValue returns a deep copy of a value so that a caller cannot
+// modify values in the store.
+func copyConfigValue(v data.Value) data.Value {
+	return data.Map{"v": v}.Copy()["v"]
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestConfigStore(t *testing.T) {
+	Convey("Given a context having initial config parameters", t, func() {
+		ctx := NewContext(&ContextConfig{
+			Config: data.Map{
+				"str":      data.String("a"),
+				"int":      data.Int(10),
+				"int_str":  data.String("20"),
+				"float":    data.Float(1.5),
+				"bool":     data.True,
+				"duration": data.String("3s"),
+				"map":      data.Map{"a": data.Int(1)},
+			},
+		})
+		c := ctx.Config
+
+		Convey("When getting parameters with typed getters", func() {
+			Convey("Then they should return converted values", func() {
+				s, err := c.GetString("str")
+				So(err, ShouldBeNil)
+				So(s, ShouldEqual, "a")
+
+				i, err := c.GetInt("int")
+				So(err, ShouldBeNil)
+				So(i, ShouldEqual, 10)
+
+				i, err = c.GetInt("int_str")
+				So(err, ShouldBeNil)
+				So(i, ShouldEqual, 20)
+
+				f, err := c.GetFloat("float")
+				So(err, ShouldBeNil)
+				So(f, ShouldEqual, 1.5)
+
+				b, err := c.GetBool("bool")
+				So(err, ShouldBeNil)
+				So(b, ShouldBeTrue)
+
+				d, err := c.GetDuration("duration")
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, 3*time.Second)
+			})
+
+			Convey("Then a value which cannot be converted should fail", func() {
+				_, err := c.GetInt("map")
+				So(err, ShouldNotBeNil)
+				So(IsNotExist(err), ShouldBeFalse)
+			})
+		})
+
+		Convey("When getting a missing parameter", func() {
+			_, err := c.GetString("missing")
+
+			Convey("Then it should fail with a not exist error", func() {
+				So(err, ShouldNotBeNil)
+				So(IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When modifying a returned value", func() {
+			v, err := c.Get("map")
+			So(err, ShouldBeNil)
+			v.(data.Map)["a"] = data.Int(2)
+
+			Convey("Then the stored value shouldn't be changed", func() {
+				v, err := c.Get("map")
+				So(err, ShouldBeNil)
+				So(v, ShouldResemble, data.Map{"a": data.Int(1)})
+			})
+		})
+
+		Convey("When updating parameters", func() {
+			c.Set("str", data.String("b"))
+			c.Update(data.Map{"int": data.Int(11), "new": data.Null{}})
+			c.Delete("float")
+
+			Convey("Then the store should have new values", func() {
+				m := c.ToMap()
+				So(m["str"], ShouldEqual, data.String("b"))
+				So(m["int"], ShouldEqual, data.Int(11))
+				So(m, ShouldContainKey, "new")
+				So(m, ShouldNotContainKey, "float")
+			})
+		})
+	})
+
+	Convey("Given a context without config parameters", t, func() {
+		ctx := NewContext(nil)
+
+		Convey("Then it should have an empty config store", func() {
+			So(ctx.Config, ShouldNotBeNil)
+			So(ctx.Config.ToMap(), ShouldBeEmpty)
+		})
+	})
+}
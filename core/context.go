@@ -1,11 +1,13 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
@@ -31,8 +33,49 @@ type Context struct {
 	Flags        ContextFlags
 	SharedStates SharedStateRegistry
 
-	dtMutex   sync.RWMutex
-	dtSources map[int64]*droppedTupleCollectorSource
+	// Config has configuration parameters which components in the topology
+	// can read.
+	Config *ConfigStore
+
+	dt *droppedTupleSources
+
+	// labels has labels of nodes. It's shared by a Context and Contexts
+	// derived from it.
+	labels *nodeLabels
+
+	// trace has *TraceConfig. It's shared by a Context and Contexts derived
+	// from it.
+	trace *atomic.Value
+
+	// cancel is canceled when the processing using the Context is canceled.
+	// It's nil when the Context is never canceled.
+	cancel context.Context
+
+	// follow has the Context whose cancellation is followed by a Context
+	// created by Fork. It's nil for other Contexts.
+	follow *atomic.Value
+
+	// clock returns the current time. It's nil when the wall clock is used.
+	clock func() time.Time
+
+	// checkpoint has the configuration of checkpoints of the topology. It's
+	// nil when checkpoints are disabled.
+	checkpoint *CheckpointConfig
+
+	// metrics has the topology created with the Context. DefaultMetrics is
+	// used when it's nil.
+	metrics *Metrics
+
+	// lifecycle receives lifecycle events of the topology. It's nil when
+	// nobody listens to them.
+	lifecycle LifecycleListener
+}
+
+// droppedTupleSources has listeners of dropped tuples. It's shared by a
+// Context and Contexts derived from it.
+type droppedTupleSources struct {
+	m       sync.RWMutex
+	sources map[int64]*droppedTupleCollectorSource
 }
 
 // ContextConfig has configuration parameters of a Context.
@@ -40,6 +83,31 @@ type ContextConfig struct {
 	// Logger provides a logrus's logger used by the Context.
 	Logger *logrus.Logger
 	Flags  ContextFlags
+
+	// Config has initial values of Context.Config.
+	Config data.Map
+
+	// Trace has parameters of tuple tracing. When it's nil, all tuples are
+	// traced while ContextFlags.TupleTrace is enabled.
+	Trace *TraceConfig
+
+	// Clock returns the current time seen by components in the topology,
+	// such as now() of BQL statements. The wall clock is used when it's nil.
+	// It's mainly used to run statements under a virtual clock in tests.
+	Clock func() time.Time
+
+	// Checkpoint has parameters of checkpoints of the topology. Checkpoints
+	// are disabled when it's nil.
+	Checkpoint *CheckpointConfig
+
+	// Metrics has topologies whose node statuses are collected. The topology
+	// created with the Context is added to it. DefaultMetrics is used when
+	// it's nil.
+	Metrics *Metrics
+
+	// LifecycleListener receives lifecycle events of the topology such as
+	// failures of nodes. Events aren't emitted when it's nil.
+	LifecycleListener LifecycleListener
 }
 
 // NewContext creates a new Context based on the config. If config is nil,
@@ -53,10 +121,21 @@ func NewContext(config *ContextConfig) *Context {
 		logger = logrus.StandardLogger()
 	}
 	c := &Context{
-		logger:    logger,
-		Flags:     config.Flags,
-		dtSources: map[int64]*droppedTupleCollectorSource{},
+		logger: logger,
+		Flags:  config.Flags,
+		Config: NewConfigStore(config.Config),
+		dt: &droppedTupleSources{
+			sources: map[int64]*droppedTupleCollectorSource{},
+		},
+		trace:  &atomic.Value{},
+		labels: &nodeLabels{labels: map[string]map[string]string{}},
+		clock:  config.Clock,
+
+		checkpoint: config.Checkpoint,
+		metrics:    config.Metrics,
+		lifecycle:  config.LifecycleListener,
 	}
+	c.SetTraceConfig(config.Trace)
 	c.SharedStates = NewDefaultSharedStateRegistry(c)
 	return c
 }
@@ -85,6 +164,13 @@ func (c *Context) log(depth int) *logrus.Entry {
 	})
 }
 
+// DroppedTuple reports a tuple which the node couldn't process, such as a
+// malformed record read by a source. The tuple can be received by the
+// dropped_tuples source in the same way as tuples dropped by the topology.
+func (c *Context) DroppedTuple(t *Tuple, nodeType NodeType, nodeName string, et EventType, err error) {
+	c.droppedTuple(t, nodeType, nodeName, et, err)
+}
+
 // droppedTuple records tuples dropped by errors.
 func (c *Context) droppedTuple(t *Tuple, nodeType NodeType, nodeName string, et EventType, err error) {
 	if t.Flags.IsSet(TFDropped) {
@@ -99,7 +185,7 @@ func (c *Context) droppedTuple(t *Tuple, nodeType NodeType, nodeName string, et
 			js = t.Data.String()
 		}
 
-		l := c.Log().WithFields(nodeLogFields(nodeType, nodeName)).WithFields(logrus.Fields{
+		l := c.Log().WithFields(c.nodeLogFields(nodeType, nodeName)).WithFields(logrus.Fields{
 			"event_type": et.String(),
 			"tuple": logrus.Fields{
 				"timestamp": data.Timestamp(t.Timestamp),
@@ -113,9 +199,12 @@ func (c *Context) droppedTuple(t *Tuple, nodeType NodeType, nodeName string, et
 		l.Info("A tuple was dropped from the topology") // TODO: debug should be better?
 	}
 
-	c.dtMutex.RLock()
-	defer c.dtMutex.RUnlock()
-	if len(c.dtSources) == 0 {
+	if c.dt == nil {
+		return // the Context wasn't created by NewContext
+	}
+	c.dt.m.RLock()
+	defer c.dt.m.RUnlock()
+	if len(c.dt.sources) == 0 {
 		return
 	}
 
@@ -134,11 +223,11 @@ func (c *Context) droppedTuple(t *Tuple, nodeType NodeType, nodeName string, et
 		dt.Data["error"] = data.String(err.Error())
 	}
 	dt.Flags.Set(TFDropped)
-	if len(c.dtSources) > 1 {
+	if len(c.dt.sources) > 1 {
 		dt.Flags.Set(TFShared)
 	} // Otherwise, the value of TFShared should not be modified.
 
-	for _, s := range c.dtSources {
+	for _, s := range c.dt.sources {
 		s.w.Write(c, dt) // There isn't much meaning to report errors here.
 	}
 }
@@ -147,17 +236,154 @@ func (c *Context) droppedTuple(t *Tuple, nodeType NodeType, nodeName string, et
 // return value is the ID of the listener and it'll be required for
 // removeDroppedTupleListener.
 func (c *Context) addDroppedTupleSource(s *droppedTupleCollectorSource) int64 {
-	c.dtMutex.Lock()
-	defer c.dtMutex.Unlock()
+	c.dt.m.Lock()
+	defer c.dt.m.Unlock()
 	id := NewTemporaryID()
-	c.dtSources[id] = s
+	c.dt.sources[id] = s
 	return id
 }
 
 func (c *Context) removeDroppedTupleSource(id int64) {
-	c.dtMutex.Lock()
-	defer c.dtMutex.Unlock()
-	delete(c.dtSources, id)
+	c.dt.m.Lock()
+	defer c.dt.m.Unlock()
+	delete(c.dt.sources, id)
+}
+
+// WithTimeout returns a copy of the Context which is canceled when the
+// timeout expires or the returned cancel function is called. The copy shares
+// everything other than cancellation with the original Context. Flags of the
+// copy are a snapshot of the original's and aren't updated later.
+//
+// Because Go cannot abort a running goroutine, cancellation only works when
+// user code such as Box.Process or UDFs checks Done or Err of the Context.
+// The cancel function must be called to release resources.
+func (c *Context) WithTimeout(timeout time.Duration) (*Context, func()) {
+	parent := c.cancelContext()
+	if parent == nil {
+		parent = context.Background()
+	}
+	cc, cancel := context.WithTimeout(parent, timeout)
+	nc := c.copy()
+	nc.cancel = cc
+	return nc, cancel
+}
+
+// Fork returns a copy of the Context whose cancellation follows the Context
+// given to Follow. It's used by a component which needs a Context before
+// processing tuples, e.g. a UDF bound to an execution plan, so that the
+// component can observe the cancellation of each call of Box.Process. The
+// copy is never canceled until Follow is called.
+func (c *Context) Fork() *Context {
+	nc := c.copy()
+	nc.follow = &atomic.Value{}
+	return nc
+}
+
+// Follow makes the Context canceled when src is canceled. When src is nil,
+// the Context won't be canceled. It can only be called on a Context returned
+// from Fork.
+func (c *Context) Follow(src *Context) {
+	if c.follow == nil {
+		panic(errors.New("the context isn't created by Fork"))
+	}
+	c.follow.Store(followedContext{src})
+}
+
+type followedContext struct {
+	c *Context
+}
+
+func (c *Context) copy() *Context {
+	return &Context{
+		logger:       c.logger,
+		topologyName: c.topologyName,
+		Flags:        c.Flags.snapshot(),
+		SharedStates: c.SharedStates,
+		Config:       c.Config,
+		dt:           c.dt,
+		trace:        c.trace,
+		labels:       c.labels,
+		clock:        c.clock,
+		checkpoint:   c.checkpoint,
+		metrics:      c.metrics,
+		lifecycle:    c.lifecycle,
+	}
+}
+
+// Metrics returns the Metrics having the topology of the Context.
+func (c *Context) Metrics() *Metrics {
+	if c.metrics == nil {
+		return DefaultMetrics
+	}
+	return c.metrics
+}
+
+// Now returns the current time of the topology. It's the wall clock unless
+// ContextConfig.Clock is given.
+func (c *Context) Now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
+}
+
+// TraceConfig returns the current parameters of tuple tracing. It returns
+// nil when they aren't set. The returned value must not be modified.
+func (c *Context) TraceConfig() *TraceConfig {
+	if c.trace == nil {
+		return nil
+	}
+	conf, _ := c.trace.Load().(*TraceConfig)
+	return conf
+}
+
+// SetTraceConfig replaces parameters of tuple tracing with a copy of conf. It
+// can be called while the topology is running. Passing nil resets the
+// parameters so that all tuples are traced.
+func (c *Context) SetTraceConfig(conf *TraceConfig) {
+	if c.trace == nil {
+		return
+	}
+	if conf != nil {
+		cp := *conf
+		conf = &cp
+	}
+	c.trace.Store(conf)
+}
+
+// cancelContext returns the context.Context controlling the cancellation of
+// the Context. It returns nil if the Context is never canceled.
+func (c *Context) cancelContext() context.Context {
+	if c.follow == nil {
+		return c.cancel
+	}
+	f, ok := c.follow.Load().(followedContext)
+	if !ok || f.c == nil {
+		return nil
+	}
+	return f.c.cancelContext()
+}
+
+// Done returns a channel which is closed when the processing using the
+// Context is canceled. It returns nil if the Context is never canceled, so
+// receiving from it blocks forever. The Context given to Box.Process is
+// canceled when the box has BoxConfig.ProcessTimeout and the timeout expires.
+func (c *Context) Done() <-chan struct{} {
+	cc := c.cancelContext()
+	if cc == nil {
+		return nil
+	}
+	return cc.Done()
+}
+
+// Err returns a non-nil error after Done is closed. The error satisfies
+// IsTimeoutError when the Context is canceled due to a timeout.
+func (c *Context) Err() error {
+	cc := c.cancelContext()
+	if cc == nil {
+		return nil
+	}
+	return cc.Err()
 }
 
 // AtomicFlag is a boolean flag which can be read/written atomically.
@@ -208,6 +434,16 @@ type ContextFlags struct {
 	DroppedTupleSummarization AtomicFlag
 }
 
+// snapshot returns a copy of the flags read atomically.
+func (f *ContextFlags) snapshot() ContextFlags {
+	var c ContextFlags
+	c.TupleTrace.Set(f.TupleTrace.Enabled())
+	c.DroppedTupleLog.Set(f.DroppedTupleLog.Enabled())
+	c.DestinationlessTupleLog.Set(f.DestinationlessTupleLog.Enabled())
+	c.DroppedTupleSummarization.Set(f.DroppedTupleSummarization.Enabled())
+	return c
+}
+
 type droppedTupleCollectorSource struct {
 	w     Writer
 	id    int64
@@ -3,6 +3,7 @@ package core
 import (
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
+	"time"
 )
 
 func TestAtomicFlag(t *testing.T) {
@@ -44,3 +45,58 @@ func TestAtomicFlag(t *testing.T) {
 		})
 	})
 }
+
+func TestContextCancellation(t *testing.T) {
+	Convey("Given a context", t, func() {
+		ctx := NewContext(nil)
+
+		Convey("Then it should never be canceled", func() {
+			So(ctx.Done(), ShouldBeNil)
+			So(ctx.Err(), ShouldBeNil)
+		})
+
+		Convey("When creating a context with a timeout", func() {
+			c, cancel := ctx.WithTimeout(time.Millisecond)
+			defer cancel()
+
+			Convey("Then it should share components with the original", func() {
+				So(c.SharedStates, ShouldEqual, ctx.SharedStates)
+				So(c.Config, ShouldEqual, ctx.Config)
+			})
+
+			Convey("Then it should time out", func() {
+				<-c.Done()
+				So(IsTimeoutError(c.Err()), ShouldBeTrue)
+				So(ctx.Err(), ShouldBeNil)
+			})
+		})
+
+		Convey("When forking the context", func() {
+			f := ctx.Fork()
+
+			Convey("Then it shouldn't be canceled before following another context", func() {
+				So(f.Done(), ShouldBeNil)
+			})
+
+			Convey("Then it should be canceled with the followed context", func() {
+				c, cancel := ctx.WithTimeout(time.Hour)
+				f.Follow(c)
+				So(f.Err(), ShouldBeNil)
+				cancel()
+				<-f.Done()
+				So(f.Err(), ShouldNotBeNil)
+				So(IsTimeoutError(f.Err()), ShouldBeFalse)
+
+				Convey("And it shouldn't be canceled after following nil", func() {
+					f.Follow(nil)
+					So(f.Done(), ShouldBeNil)
+					So(f.Err(), ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("Then Follow on a non-forked context should panic", func() {
+			So(func() { ctx.Follow(ctx) }, ShouldPanic)
+		})
+	})
+}
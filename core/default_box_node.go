@@ -2,16 +2,26 @@ package core
 
 import (
 	"fmt"
+	"sync/atomic"
+
 	"gopkg.in/sensorbee/sensorbee.v0/data"
 )
 
 type defaultBoxNode struct {
+	// numTimeouts must be here for 64-bit alignment.
+	numTimeouts int64
+
 	*defaultNode
 	config *BoxConfig
 	srcs   *dataSources
 	box    Box
 	dsts   *dataDestinations
 
+	quarantine *quarantine
+
+	// latency has durations of processing tuples.
+	latency *latencyHistogram
+
 	gracefulStopEnabled bool
 	stopOnDisconnectDir ConnDir
 	runErr              error
@@ -44,6 +54,10 @@ func (db *defaultBoxNode) Input(refname string, config *BoxInputConfig) error {
 
 	recv, send := newPipe(config.inputName(), config.capacity())
 	send.dropMode = config.DropMode
+	send.nodeType, send.nodeName = NTBox, db.name
+	recv.errorMode = config.ErrorMode
+	recv.maxRetries = config.MaxRetries
+	recv.transformer = config.Transformer
 	if err := s.destinations().add(db.name, send); err != nil {
 		return err
 	}
@@ -65,7 +79,7 @@ func (db *defaultBoxNode) run() (runErr error) {
 				if db.runErr == nil {
 					db.runErr = fmt.Errorf("the box couldn't be terminated due to panic: %v", e)
 				} else {
-					db.topology.ctx.ErrLog(fmt.Errorf("%v", e)).WithFields(nodeLogFields(NTBox, db.name)).
+					db.topology.ctx.ErrLog(fmt.Errorf("%v", e)).WithFields(db.topology.ctx.nodeLogFields(NTBox, db.name)).
 						Error("Cannot terminate the box due to panic")
 				}
 			}
@@ -78,14 +92,26 @@ func (db *defaultBoxNode) run() (runErr error) {
 				if db.runErr == nil {
 					db.runErr = err
 				} else {
-					db.topology.ctx.ErrLog(err).WithFields(nodeLogFields(NTBox, db.name)).
+					db.topology.ctx.ErrLog(err).WithFields(db.topology.ctx.nodeLogFields(NTBox, db.name)).
 						Error("Cannot terminate the box")
 				}
 			}
 		}
 	}()
 	db.state.Set(TSRunning)
-	w := newBoxWriterAdapter(db.box, db.name, db.dsts)
+	var dst WriteCloser = newUsageEmitWriter(db.dsts, db.topology.usage)
+	if db.config.TimestampExtractor != nil {
+		dst = newTimestampWriter(dst, db.config.TimestampExtractor, NTBox, db.name)
+	}
+	if db.config.Validator != nil {
+		dst = newValidatingWriter(dst, db.config.Validator, NTBox, db.name)
+	}
+	w := newBoxWriterAdapter(db.box, db.name, dst)
+	w.timeout = db.config.ProcessTimeout
+	w.numTimeouts = &db.numTimeouts
+	w.quarantine = db.quarantine
+	w.usage = db.topology.usage
+	w.latency = db.latency
 	db.runErr = db.srcs.pour(db.topology.ctx, w, 1) // TODO: make parallelism configurable
 	return
 }
@@ -128,6 +154,50 @@ func (db *defaultBoxNode) stop() {
 	db.state.Wait(TSStopped)
 }
 
+func (db *defaultBoxNode) Pause() error {
+	db.stateMutex.Lock()
+	defer db.stateMutex.Unlock()
+
+	switch db.state.getWithoutLock() {
+	case TSRunning:
+	case TSPaused:
+		return nil
+	default:
+		return fmt.Errorf("box '%v' isn't running", db.name)
+	}
+	db.srcs.pause()
+	db.state.setWithoutLock(TSPaused)
+	return nil
+}
+
+func (db *defaultBoxNode) Resume() error {
+	db.stateMutex.Lock()
+	defer db.stateMutex.Unlock()
+
+	switch db.state.getWithoutLock() {
+	case TSRunning:
+		return nil
+	case TSPaused:
+	default:
+		return fmt.Errorf("box '%v' isn't running", db.name)
+	}
+	db.srcs.resume()
+	db.state.setWithoutLock(TSRunning)
+	return nil
+}
+
+func (db *defaultBoxNode) ReleaseQuarantine() error {
+	if db.quarantine == nil {
+		return fmt.Errorf("the box '%v' doesn't have a quarantine policy", db.name)
+	}
+	if err := db.quarantine.release(); err != nil {
+		return err
+	}
+	db.topology.ctx.Log().WithFields(db.topology.ctx.nodeLogFields(NTBox, db.name)).
+		Info("The box was released from quarantine")
+	return nil
+}
+
 func (db *defaultBoxNode) Status() data.Map {
 	db.stateMutex.Lock()
 	st := db.state.getWithoutLock()
@@ -136,10 +206,16 @@ func (db *defaultBoxNode) Status() data.Map {
 	removeOnStop := db.config.RemoveOnStop
 	db.stateMutex.Unlock()
 
+	inputStats := db.srcs.status()
+	if db.config.ProcessTimeout > 0 {
+		inputStats["num_timeouts"] = data.Int(atomic.LoadInt64(&db.numTimeouts))
+	}
+
 	m := data.Map{
-		"state":        data.String(st.String()),
-		"input_stats":  db.srcs.status(),
-		"output_stats": db.dsts.status(),
+		"state":           data.String(st.String()),
+		"input_stats":     inputStats,
+		"output_stats":    db.dsts.status(),
+		"process_latency": db.latency.status(),
 		"behaviors": data.Map{
 			"stop_on_inbound_disconnect":  data.Bool((connDir & Inbound) != 0),
 			"stop_on_outbound_disconnect": data.Bool((connDir & Outbound) != 0),
@@ -153,6 +229,10 @@ func (db *defaultBoxNode) Status() data.Map {
 	if b, ok := db.box.(Statuser); ok {
 		m["box"] = b.Status()
 	}
+	db.addLabelsStatus(m)
+	if db.quarantine != nil {
+		m["quarantine"] = db.quarantine.status()
+	}
 	return m
 }
 
@@ -11,6 +11,15 @@ type defaultSinkNode struct {
 	srcs   *dataSources
 	sink   Sink
 
+	// writer writes tuples to the sink. It's ordering when the sink has
+	// an ordering policy other than ArrivalOrder.
+	writer   Writer
+	ordering *orderingWriter
+	wal      *sinkWAL
+
+	// latency has durations of writing tuples.
+	latency *latencyHistogram
+
 	gracefulStopEnabled     bool
 	stopOnDisconnectEnabled bool
 	runErr                  error
@@ -45,11 +54,25 @@ func (ds *defaultSinkNode) Input(refname string, config *SinkInputConfig) error
 		return err
 	}
 
-	recv, send := newPipe("output", config.capacity())
+	// The ordering writer needs the name of the input to identify tuples
+	// from each input.
+	inputName := "output"
+	if ds.ordering != nil {
+		inputName = s.Name()
+	}
+
+	recv, send := newPipe(inputName, config.capacity())
 	send.dropMode = config.DropMode
+	send.nodeType, send.nodeName = NTSink, ds.name
+	recv.errorMode = config.ErrorMode
+	recv.maxRetries = config.MaxRetries
+	recv.transformer = config.Transformer
 	if err := s.destinations().add(ds.name, send); err != nil {
 		return err
 	}
+	if ds.ordering != nil {
+		ds.ordering.addInput(inputName)
+	}
 	if err := ds.srcs.add(s.Name(), recv); err != nil {
 		s.destinations().remove(ds.name)
 		return err
@@ -69,7 +92,7 @@ func (ds *defaultSinkNode) run() (runErr error) {
 				if ds.runErr == nil {
 					ds.runErr = fmt.Errorf("the box couldn't be terminated due to panic: %v", e)
 				} else {
-					ds.topology.ctx.ErrLog(fmt.Errorf("%v", e)).WithFields(nodeLogFields(NTBox, ds.name)).
+					ds.topology.ctx.ErrLog(fmt.Errorf("%v", e)).WithFields(ds.topology.ctx.nodeLogFields(NTBox, ds.name)).
 						Error("Cannot terminate the box due to panic")
 				}
 			}
@@ -77,12 +100,30 @@ func (ds *defaultSinkNode) run() (runErr error) {
 		}()
 		if err := ds.sink.Close(ds.topology.ctx); err != nil {
 			ds.runErr = err
-			ds.topology.ctx.ErrLog(err).WithFields(nodeLogFields(NTSink, ds.name)).
+			ds.topology.ctx.ErrLog(err).WithFields(ds.topology.ctx.nodeLogFields(NTSink, ds.name)).
 				Error("Cannot stop the sink")
 		}
 	}()
+	if ds.wal != nil {
+		ds.wal.start(ds.topology.ctx)
+	}
 	ds.state.Set(TSRunning)
-	ds.runErr = ds.srcs.pour(ds.topology.ctx, newTraceWriter(ds.sink, ETInput, ds.name), 1)
+	w := &usageProcessWriter{
+		w:       ds.writer,
+		usage:   ds.topology.usage,
+		latency: ds.latency,
+	}
+	ds.runErr = ds.srcs.pour(ds.topology.ctx, w, 1)
+	if ds.ordering != nil {
+		if err := ds.ordering.flush(ds.topology.ctx); err != nil && ds.runErr == nil {
+			ds.runErr = err
+		}
+	}
+	if ds.wal != nil {
+		if err := ds.wal.close(); err != nil && ds.runErr == nil {
+			ds.runErr = err
+		}
+	}
 	return
 }
 
@@ -122,8 +163,9 @@ func (ds *defaultSinkNode) Status() data.Map {
 	ds.stateMutex.Unlock()
 
 	m := data.Map{
-		"state":       data.String(st.String()),
-		"input_stats": ds.srcs.status(),
+		"state":           data.String(st.String()),
+		"input_stats":     ds.srcs.status(),
+		"process_latency": ds.latency.status(),
 		"behaviors": data.Map{
 			"stop_on_disconnect": data.Bool(stopOnDisconnect),
 			"graceful_stop":      data.Bool(gstop),
@@ -133,12 +175,59 @@ func (ds *defaultSinkNode) Status() data.Map {
 	if st == TSStopped && ds.runErr != nil {
 		m["error"] = data.String(ds.runErr.Error())
 	}
+	if ds.ordering != nil {
+		m["ordering"] = ds.ordering.status()
+	}
+	if ds.wal != nil {
+		m["wal"] = ds.wal.status()
+	}
 	if s, ok := ds.sink.(Statuser); ok {
 		m["sink"] = s.Status()
 	}
+	ds.addLabelsStatus(m)
 	return m
 }
 
+func (ds *defaultSinkNode) Pause() error {
+	ds.stateMutex.Lock()
+	defer ds.stateMutex.Unlock()
+
+	switch ds.state.getWithoutLock() {
+	case TSRunning:
+	case TSPaused:
+		return nil
+	default:
+		return fmt.Errorf("sink '%v' isn't running", ds.name)
+	}
+	ds.srcs.pause()
+	ds.state.setWithoutLock(TSPaused)
+	return nil
+}
+
+func (ds *defaultSinkNode) Resume() error {
+	ds.stateMutex.Lock()
+	defer ds.stateMutex.Unlock()
+
+	switch ds.state.getWithoutLock() {
+	case TSRunning:
+		return nil
+	case TSPaused:
+	default:
+		return fmt.Errorf("sink '%v' isn't running", ds.name)
+	}
+	ds.srcs.resume()
+	ds.state.setWithoutLock(TSRunning)
+	return nil
+}
+
+func (ds *defaultSinkNode) ReplayWAL() error {
+	if ds.wal == nil {
+		return fmt.Errorf("the sink '%v' doesn't have a WAL", ds.name)
+	}
+	ds.wal.replay()
+	return nil
+}
+
 func (ds *defaultSinkNode) RemoveOnStop() {
 	ds.stateMutex.Lock()
 	ds.config.RemoveOnStop = true
@@ -56,7 +56,14 @@ func (ds *defaultSourceNode) run() (runErr error) {
 		return
 	}
 
-	ds.runErr = ds.source.GenerateStream(ds.topology.ctx, newTraceWriter(ds.dsts, ETOutput, ds.name))
+	var w WriteCloser = newUsageEmitWriter(ds.dsts, ds.topology.usage)
+	if ds.config.TimestampExtractor != nil {
+		w = newTimestampWriter(w, ds.config.TimestampExtractor, NTSource, ds.name)
+	}
+	if ds.config.Validator != nil {
+		w = newValidatingWriter(w, ds.config.Validator, NTSource, ds.name)
+	}
+	ds.runErr = ds.source.GenerateStream(ds.topology.ctx, newTraceWriter(w, ETOutput, ds.name))
 	return
 }
 
@@ -213,6 +220,7 @@ func (ds *defaultSourceNode) Status() data.Map {
 	if s, ok := ds.source.(Statuser); ok {
 		m["source"] = s.Status()
 	}
+	ds.addLabelsStatus(m)
 	return m
 }
 
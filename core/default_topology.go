@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 )
 
 type defaultTopology struct {
@@ -21,6 +23,9 @@ type defaultTopology struct {
 	state      *topologyStateHolder
 	stateMutex sync.Mutex
 
+	usage        *topologyUsage
+	checkpointer *topologyCheckpointer
+
 	// TODO: support lazy invocation of GenerateStream (call it when the first
 	// destination is added or a Sink is indirectly connected). Maybe graph
 	// management is required.
@@ -40,9 +45,13 @@ func NewDefaultTopology(ctx *Context, name string) (Topology, error) {
 		sources: map[string]*defaultSourceNode{},
 		boxes:   map[string]*defaultBoxNode{},
 		sinks:   map[string]*defaultSinkNode{},
+
+		usage: newTopologyUsage(),
 	}
 	t.state = newTopologyStateHolder(&t.stateMutex)
 	t.state.state = TSRunning // A topology is running by default.
+	t.checkpointer = newTopologyCheckpointer(t, ctx.checkpoint)
+	ctx.Metrics().add(t)
 	return t, nil
 }
 
@@ -62,6 +71,9 @@ func (t *defaultTopology) AddSource(name string, s Source, config *SourceConfig)
 	if config == nil {
 		config = &SourceConfig{}
 	}
+	if err := ValidateLabels(config.Labels); err != nil {
+		return nil, err
+	}
 
 	// This method assumes adding a Source having a duplicated name is rare.
 	// Under this assumption, acquiring wlock without checking the existence
@@ -82,7 +94,7 @@ func (t *defaultTopology) AddSource(name string, s Source, config *SourceConfig)
 	}
 
 	ds := &defaultSourceNode{
-		defaultNode:     newDefaultNode(t, name, config.Meta),
+		defaultNode:     newDefaultNode(t, name, config.Meta, config.Labels),
 		source:          s,
 		dsts:            newDataDestinations(NTSource, name),
 		pausedOnStartup: config.PausedOnStartup,
@@ -96,12 +108,14 @@ func (t *defaultTopology) AddSource(name string, s Source, config *SourceConfig)
 		return nil, err
 	}
 	t.sources[strings.ToLower(name)] = ds
+	t.ctx.setNodeLabels(name, ds.labels)
 
 	go func() {
 		// TODO: Support lazy invocation
 		if err := ds.run(); err != nil {
-			t.ctx.ErrLog(err).WithFields(nodeLogFields(NTSource, name)).
+			t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTSource, name)).
 				Error("Cannot generate a stream from the source")
+			t.ctx.nodeFailed(NTSource, name, err)
 		}
 		ds.stateMutex.Lock()
 		removeOnStop := ds.config.RemoveOnStop
@@ -109,7 +123,7 @@ func (t *defaultTopology) AddSource(name string, s Source, config *SourceConfig)
 		if removeOnStop {
 			if err := t.Remove(name); err != nil {
 				if !IsNotExist(err) {
-					t.ctx.ErrLog(err).WithFields(nodeLogFields(NTSource, name)).
+					t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTSource, name)).
 						Error("Cannot remove the source from topology")
 				}
 			}
@@ -149,6 +163,14 @@ func (t *defaultTopology) AddBox(name string, b Box, config *BoxConfig) (BoxNode
 	if config == nil {
 		config = &BoxConfig{}
 	}
+	if err := ValidateLabels(config.Labels); err != nil {
+		return nil, err
+	}
+	if config.Quarantine != nil {
+		if err := config.Quarantine.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	t.nodeMutex.Lock()
 	defer t.nodeMutex.Unlock()
@@ -179,20 +201,26 @@ func (t *defaultTopology) AddBox(name string, b Box, config *BoxConfig) (BoxNode
 	}
 
 	db := &defaultBoxNode{
-		defaultNode: newDefaultNode(t, name, config.Meta),
+		defaultNode: newDefaultNode(t, name, config.Meta, config.Labels),
 		srcs:        newDataSources(NTBox, name),
 		box:         b,
 		dsts:        newDataDestinations(NTBox, name),
+		latency:     newLatencyHistogram(),
 	}
 	db.config = &BoxConfig{}
 	*db.config = *config
+	if config.Quarantine != nil {
+		db.quarantine = newQuarantine(config.Quarantine)
+	}
 	db.dsts.callback = db.dstCallback
 	t.boxes[strings.ToLower(name)] = db
+	t.ctx.setNodeLabels(name, db.labels)
 
 	go func() {
 		if err := db.run(); err != nil {
-			t.ctx.ErrLog(err).WithFields(nodeLogFields(NTBox, db.name)).
+			t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTBox, db.name)).
 				Error("The box failed")
+			t.ctx.nodeFailed(NTBox, db.name, err)
 		}
 		db.stateMutex.Lock()
 		removeOnStop := db.config.RemoveOnStop
@@ -200,7 +228,7 @@ func (t *defaultTopology) AddBox(name string, b Box, config *BoxConfig) (BoxNode
 		if removeOnStop {
 			if err := t.Remove(name); err != nil {
 				if !IsNotExist(err) {
-					t.ctx.ErrLog(err).WithFields(nodeLogFields(NTBox, db.name)).
+					t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTBox, db.name)).
 						Error("Cannot remove the box from topology")
 				}
 			}
@@ -220,12 +248,12 @@ func (t *defaultTopology) AddSink(name string, s Sink, config *SinkConfig) (Sink
 		}
 		defer func() {
 			if e := recover(); e != nil {
-				t.ctx.Log().WithFields(nodeLogFields(NTSink, name)).
+				t.ctx.Log().WithFields(t.ctx.nodeLogFields(NTSink, name)).
 					Errorf("Cannot close the sink which hasn't been added to the topology: %v", e)
 			}
 		}()
 		if err := s.Close(t.ctx); err != nil {
-			t.ctx.ErrLog(err).WithFields(nodeLogFields(NTSink, name)).
+			t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTSink, name)).
 				Error("Cannot close the sink which hasn't been added to the topology")
 		}
 	}()
@@ -238,32 +266,72 @@ func (t *defaultTopology) AddSink(name string, s Sink, config *SinkConfig) (Sink
 	if config == nil {
 		config = &SinkConfig{}
 	}
+	if err := ValidateLabels(config.Labels); err != nil {
+		closeSinkFlag = true
+		return nil, err
+	}
+	var w Writer = newTraceWriter(s, ETInput, name)
+	var wal *sinkWAL
+	if config.WAL != nil {
+		l, err := newSinkWAL(config.WAL, name, w)
+		if err != nil {
+			closeSinkFlag = true
+			return nil, err
+		}
+		wal = l
+		w = l
+	}
+	closeWAL := func() {
+		if wal != nil {
+			wal.close()
+		}
+	}
+
+	var ow *orderingWriter
+	if config.Ordering != ArrivalOrder {
+		o, err := newOrderingWriter(w, name, config.Ordering, config.OrderingBufferSize)
+		if err != nil {
+			closeWAL()
+			closeSinkFlag = true
+			return nil, err
+		}
+		ow = o
+		w = o
+	}
 
 	t.nodeMutex.Lock()
 	defer t.nodeMutex.Unlock()
 	if t.state.Get() >= TSStopping {
+		closeWAL()
 		closeSinkFlag = true
 		return nil, fmt.Errorf("the topology is already stopped")
 	}
 
 	if err := t.checkNodeNameDuplication(name); err != nil {
+		closeWAL()
 		closeSinkFlag = true
 		return nil, err
 	}
 
 	ds := &defaultSinkNode{
-		defaultNode: newDefaultNode(t, name, config.Meta),
+		defaultNode: newDefaultNode(t, name, config.Meta, config.Labels),
 		srcs:        newDataSources(NTSink, name),
 		sink:        s,
+		writer:      w,
+		ordering:    ow,
+		wal:         wal,
+		latency:     newLatencyHistogram(),
 	}
 	ds.config = &SinkConfig{}
 	*ds.config = *config
 	t.sinks[strings.ToLower(name)] = ds
+	t.ctx.setNodeLabels(name, ds.labels)
 
 	go func() {
 		if err := ds.run(); err != nil {
-			t.ctx.ErrLog(err).WithFields(nodeLogFields(NTSink, ds.name)).
+			t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTSink, ds.name)).
 				Error("The sink failed")
+			t.ctx.nodeFailed(NTSink, ds.name, err)
 		}
 		ds.stateMutex.Lock()
 		removeOnStop := ds.config.RemoveOnStop
@@ -271,7 +339,7 @@ func (t *defaultTopology) AddSink(name string, s Sink, config *SinkConfig) (Sink
 		if removeOnStop {
 			if err := t.Remove(name); err != nil {
 				if !IsNotExist(err) {
-					t.ctx.ErrLog(err).WithFields(nodeLogFields(NTSink, ds.name)).
+					t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTSink, ds.name)).
 						Error("Cannot remove the sink from topology")
 				}
 			}
@@ -290,6 +358,7 @@ func (t *defaultTopology) Stop() error {
 	} else if stopped {
 		return nil
 	}
+	t.checkpointer.stop()
 
 	var lastErr error
 	for name, src := range t.sources {
@@ -297,7 +366,7 @@ func (t *defaultTopology) Stop() error {
 		if err := src.Stop(); err != nil { // Stop doesn't panic
 			lastErr = err
 			src.dsts.Close(t.ctx)
-			t.ctx.ErrLog(err).WithFields(nodeLogFields(NTSource, name)).
+			t.ctx.ErrLog(err).WithFields(t.ctx.nodeLogFields(NTSource, name)).
 				Error("Cannot stop the source")
 		}
 	}
@@ -306,6 +375,8 @@ func (t *defaultTopology) Stop() error {
 	for _, b := range t.boxes {
 		b := b
 
+		// A paused node doesn't notice that its inputs are closed.
+		b.Resume()
 		b.StopOnDisconnect(Inbound | Outbound)
 		wg.Add(1)
 		go func() {
@@ -317,6 +388,8 @@ func (t *defaultTopology) Stop() error {
 	for _, s := range t.sinks {
 		s := s
 
+		// A paused node doesn't notice that its inputs are closed.
+		s.Resume()
 		s.StopOnDisconnect()
 		wg.Add(1)
 		go func() {
@@ -329,6 +402,8 @@ func (t *defaultTopology) Stop() error {
 	t.sources = nil
 	t.boxes = nil
 	t.sinks = nil
+	t.usage.stop()
+	t.ctx.Metrics().remove(t)
 	t.state.Set(TSStopped)
 	return lastErr
 }
@@ -337,6 +412,28 @@ func (t *defaultTopology) State() TopologyStateHolder {
 	return t.state
 }
 
+func (t *defaultTopology) Usage() data.Map {
+	return t.usage.status()
+}
+
+func (t *defaultTopology) Checkpoint() (data.Map, error) {
+	if st := t.state.Get(); st >= TSStopping {
+		return nil, fmt.Errorf("the topology has already stopped")
+	}
+	return t.checkpointer.checkpoint()
+}
+
+func (t *defaultTopology) RestoreCheckpoint() (data.Map, error) {
+	if st := t.state.Get(); st >= TSStopping {
+		return nil, fmt.Errorf("the topology has already stopped")
+	}
+	return t.checkpointer.restore()
+}
+
+func (t *defaultTopology) CheckpointStatus() data.Map {
+	return t.checkpointer.status()
+}
+
 func (t *defaultTopology) Remove(name string) error {
 	lowerName := strings.ToLower(name)
 	n, err := func() (Node, error) {
@@ -361,6 +458,9 @@ func (t *defaultTopology) Remove(name string) error {
 		return err
 	}
 
+	// Labels are removed after the node stops so that logs written while
+	// it's stopping have them.
+	defer t.ctx.setNodeLabels(name, nil)
 	if err := n.Stop(); err != nil { // stop never panics
 		if n.Type() == NTSource {
 			s := n.(*defaultSourceNode)
@@ -495,17 +595,26 @@ type defaultNode struct {
 	state      *topologyStateHolder
 	stateMutex sync.Mutex
 
-	meta interface{}
+	meta   interface{}
+	labels map[string]string
 }
 
-func newDefaultNode(t *defaultTopology, name string, meta interface{}) *defaultNode {
+func newDefaultNode(t *defaultTopology, name string, meta interface{}, labels map[string]string) *defaultNode {
 	if meta == nil {
 		meta = map[string]interface{}{}
 	}
+	var ls map[string]string
+	if len(labels) > 0 {
+		ls = make(map[string]string, len(labels))
+		for k, v := range labels {
+			ls[k] = v
+		}
+	}
 	dn := &defaultNode{
 		topology: t,
 		name:     name,
 		meta:     meta,
+		labels:   ls,
 	}
 	dn.state = newTopologyStateHolder(&dn.stateMutex)
 	return dn
@@ -523,6 +632,22 @@ func (dn *defaultNode) Meta() interface{} {
 	return dn.meta
 }
 
+func (dn *defaultNode) Labels() map[string]string {
+	return dn.labels
+}
+
+// addLabelsStatus adds "labels" field to the status when the node has labels.
+func (dn *defaultNode) addLabelsStatus(m data.Map) {
+	if len(dn.labels) == 0 {
+		return
+	}
+	ls := make(data.Map, len(dn.labels))
+	for k, v := range dn.labels {
+		ls[k] = data.String(v)
+	}
+	m["labels"] = ls
+}
+
 func (dn *defaultNode) checkAndPrepareForRunning(nodeType string) error {
 	dn.stateMutex.Lock()
 	defer dn.stateMutex.Unlock()
@@ -0,0 +1,28 @@
+package core
+
+// EdgeTransformer transforms or filters tuples on an edge between two nodes.
+// It's executed inline by the destination node before the tuple is given to
+// the Box or the Sink, so a trivial projection or filter doesn't require a
+// dedicated Box and an additional pipe.
+type EdgeTransformer interface {
+	// Transform returns the transformed tuple, or nil when the tuple is
+	// filtered out. It must not modify the given tuple when its TFShared
+	// flag is set. Transform is called concurrently when the destination
+	// node processes tuples in parallel.
+	//
+	// An error returned from Transform is handled in the same way as an
+	// error returned from Box.Process or Sink.Write according to the
+	// ErrorMode of the input.
+	Transform(ctx *Context, t *Tuple) (*Tuple, error)
+}
+
+type edgeTransformerFunc func(ctx *Context, t *Tuple) (*Tuple, error)
+
+// EdgeTransformerFunc creates an EdgeTransformer from a function.
+func EdgeTransformerFunc(f func(ctx *Context, t *Tuple) (*Tuple, error)) EdgeTransformer {
+	return edgeTransformerFunc(f)
+}
+
+func (f edgeTransformerFunc) Transform(ctx *Context, t *Tuple) (*Tuple, error) {
+	return f(ctx, t)
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestEdgeTransformer(t *testing.T) {
+	oddFilter := EdgeTransformerFunc(func(ctx *Context, t *Tuple) (*Tuple, error) {
+		seq, err := data.ToInt(t.Data["seq"])
+		if err != nil {
+			return nil, err
+		}
+		if seq%2 == 0 {
+			return nil, nil
+		}
+		return t, nil
+	})
+	doubler := EdgeTransformerFunc(func(ctx *Context, t *Tuple) (*Tuple, error) {
+		seq, err := data.ToInt(t.Data["seq"])
+		if err != nil {
+			return nil, err
+		}
+		t = t.ShallowCopy()
+		t.Data = data.Map{"doubled": data.Int(seq * 2)}
+		return t, nil
+	})
+
+	Convey("Given a topology with transformers on edges", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "edge_transformer")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(freshTuples()), &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+
+		bn, err := t.AddBox("box", BoxFunc(forwardBox), nil)
+		So(err, ShouldBeNil)
+		So(bn.Input("source", &BoxInputConfig{
+			Transformer: oddFilter,
+		}), ShouldBeNil)
+
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("box", &SinkInputConfig{
+			Transformer: doubler,
+		}), ShouldBeNil)
+
+		Convey("When the source generates tuples", func() {
+			So(son.Resume(), ShouldBeNil)
+			son.State().Wait(TSStopped)
+			si.Wait(4)
+
+			Convey("Then the sink should receive transformed tuples which passed the filter", func() {
+				So(si.len(), ShouldEqual, 4)
+				for i := 0; i < 4; i++ {
+					So(si.get(i).Data, ShouldResemble, data.Map{"doubled": data.Int(4*i + 2)})
+				}
+			})
+		})
+	})
+
+	Convey("Given a topology with a failing transformer", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "edge_transformer")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(freshTuples()), &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+
+		failOnOdd := EdgeTransformerFunc(func(ctx *Context, t *Tuple) (*Tuple, error) {
+			if _, err := oddFilter.Transform(ctx, t); err != nil {
+				return nil, err
+			}
+			seq, _ := data.ToInt(t.Data["seq"])
+			if seq%2 == 1 {
+				return nil, errors.New("odd")
+			}
+			return t, nil
+		})
+
+		Convey("When the sink drops tuples on errors", func() {
+			si := NewTupleCollectorSink()
+			sin, err := t.AddSink("sink", si, nil)
+			So(err, ShouldBeNil)
+			So(sin.Input("source", &SinkInputConfig{
+				Transformer: failOnOdd,
+				ErrorMode:   DropOnError,
+			}), ShouldBeNil)
+			So(son.Resume(), ShouldBeNil)
+			son.State().Wait(TSStopped)
+			si.Wait(4)
+
+			Convey("Then only tuples transformed successfully should be written", func() {
+				So(si.len(), ShouldEqual, 4)
+				So(si.get(0).Data["seq"], ShouldEqual, data.Int(2))
+			})
+		})
+
+		Convey("When the sink stops on errors", func() {
+			si := NewTupleCollectorSink()
+			sin, err := t.AddSink("sink", si, nil)
+			So(err, ShouldBeNil)
+			So(sin.Input("source", &SinkInputConfig{
+				Transformer: failOnOdd,
+				ErrorMode:   StopOnError,
+			}), ShouldBeNil)
+			So(son.Resume(), ShouldBeNil)
+
+			Convey("Then the sink should stop", func() {
+				sin.State().Wait(TSStopped)
+				So(si.len(), ShouldEqual, 0)
+				So(sin.Status()["error"], ShouldNotBeNil)
+			})
+		})
+	})
+}
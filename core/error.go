@@ -200,3 +200,88 @@ func NotExistError(err error) error {
 		err: err,
 	}
 }
+
+// IsTimeoutError returns true when the given error is caused by a timeout.
+// If the error implements the following interface, IsTimeoutError returns the
+// return value of Timeout method:
+//
+//	interface {
+//		Timeout() bool
+//	}
+//
+// Otherwise, it returns false. context.DeadlineExceeded is also a timeout
+// error because it implements the interface.
+func IsTimeoutError(err error) bool {
+	type timeout interface {
+		Timeout() bool
+	}
+
+	if e, ok := err.(timeout); ok {
+		return e.Timeout()
+	}
+	return false
+}
+
+type timeoutError struct {
+	err error
+}
+
+func (t *timeoutError) Error() string {
+	return t.err.Error()
+}
+
+func (t *timeoutError) Timeout() bool {
+	return true
+}
+
+// TimeoutError decorates the given error so that
+// IsTimeoutError(TimeoutError(err)) returns true even if err doesn't have
+// Timeout method. It will panic if err is nil.
+func TimeoutError(err error) error {
+	if err == nil {
+		panic(fmt.Errorf("the error cannot be nil"))
+	}
+	return &timeoutError{err: err}
+}
+
+// IsPanicError returns true when the given error is caused by a panic. If
+// the error implements the following interface, IsPanicError returns the
+// return value of Panic method:
+//
+//	interface {
+//		Panic() bool
+//	}
+//
+// Otherwise, it returns false.
+func IsPanicError(err error) bool {
+	type panicked interface {
+		Panic() bool
+	}
+
+	if e, ok := err.(panicked); ok {
+		return e.Panic()
+	}
+	return false
+}
+
+type panicError struct {
+	err error
+}
+
+func (p *panicError) Error() string {
+	return p.err.Error()
+}
+
+func (p *panicError) Panic() bool {
+	return true
+}
+
+// PanicError decorates the given error so that IsPanicError(PanicError(err))
+// returns true even if err doesn't have Panic method. It will panic if err is
+// nil.
+func PanicError(err error) error {
+	if err == nil {
+		panic(fmt.Errorf("the error cannot be nil"))
+	}
+	return &panicError{err: err}
+}
@@ -0,0 +1,244 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// oddFailBox fails to process tuples having an odd "seq" until it has
+// been called failCount times for the tuple.
+type oddFailBox struct {
+	m         sync.Mutex
+	failCount int
+	attempts  map[int64]int
+}
+
+func (b *oddFailBox) Process(ctx *Context, t *Tuple, w Writer) error {
+	seq, _ := data.AsInt(t.Data["seq"])
+	b.m.Lock()
+	b.attempts[seq]++
+	n := b.attempts[seq]
+	b.m.Unlock()
+	if seq%2 == 1 && n <= b.failCount {
+		return errors.New("odd number")
+	}
+	return w.Write(ctx, t)
+}
+
+func TestErrorMode(t *testing.T) {
+	Convey("Given a topology with a box failing on some tuples", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "error_mode")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(freshTuples()), &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+		dtso := NewDroppedTupleCollectorSource().(*droppedTupleCollectorSource)
+		_, err = t.AddSource("dropped_tuples", dtso, nil)
+		So(err, ShouldBeNil)
+		dtso.state.Wait(TSRunning)
+		dropped := NewTupleCollectorSink()
+		dsin, err := t.AddSink("dropped", dropped, nil)
+		So(err, ShouldBeNil)
+		So(dsin.Input("dropped_tuples", nil), ShouldBeNil)
+
+		b := &oddFailBox{
+			failCount: 2,
+			attempts:  map[int64]int{},
+		}
+		bn, err := t.AddBox("box", b, nil)
+		So(err, ShouldBeNil)
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("box", nil), ShouldBeNil)
+
+		inputStats := func() data.Map {
+			st, err := bn.Status().Get(data.MustCompilePath("input_stats"))
+			So(err, ShouldBeNil)
+			m, err := data.AsMap(st)
+			So(err, ShouldBeNil)
+			return m
+		}
+
+		Convey("When the box uses the default error mode", func() {
+			So(bn.Input("source", nil), ShouldBeNil)
+			So(son.Resume(), ShouldBeNil)
+
+			Convey("Then failed tuples should be reported", func() {
+				si.Wait(4)
+				dropped.Wait(4)
+				So(si.len(), ShouldEqual, 4)
+				So(dropped.len(), ShouldEqual, 4)
+			})
+		})
+
+		Convey("When the box drops tuples on errors", func() {
+			So(bn.Input("source", &BoxInputConfig{ErrorMode: DropOnError}), ShouldBeNil)
+
+			Convey("Then the status should have the error mode", func() {
+				m, err := inputStats().Get(data.MustCompilePath("inputs.source.error_mode"))
+				So(err, ShouldBeNil)
+				So(m, ShouldEqual, data.String("drop"))
+			})
+
+			Convey("Then failed tuples should not be reported", func() {
+				So(son.Resume(), ShouldBeNil)
+				si.Wait(4)
+				So(si.len(), ShouldEqual, 4)
+				So(dropped.len(), ShouldEqual, 0)
+				So(inputStats()["num_errors"], ShouldEqual, data.Int(4))
+			})
+		})
+
+		Convey("When the box stops on errors", func() {
+			So(bn.Input("source", &BoxInputConfig{ErrorMode: StopOnError}), ShouldBeNil)
+			So(son.Resume(), ShouldBeNil)
+
+			Convey("Then the box should stop", func() {
+				bn.State().Wait(TSStopped)
+				So(bn.State().Get(), ShouldEqual, TSStopped)
+				So(bn.Status()["error"], ShouldEqual, data.String("odd number"))
+
+				Convey("And the tuple should be reported", func() {
+					dropped.Wait(1)
+					So(dropped.len(), ShouldEqual, 1)
+				})
+			})
+		})
+
+		Convey("When the box retries on errors", func() {
+			So(bn.Input("source", &BoxInputConfig{
+				ErrorMode:  RetryOnError,
+				MaxRetries: 2,
+			}), ShouldBeNil)
+			So(son.Resume(), ShouldBeNil)
+
+			Convey("Then all tuples should be processed", func() {
+				si.Wait(8)
+				So(si.len(), ShouldEqual, 8)
+				So(dropped.len(), ShouldEqual, 0)
+				So(inputStats()["num_retries"], ShouldEqual, data.Int(8))
+				So(inputStats()["num_errors"], ShouldEqual, data.Int(0))
+			})
+		})
+
+		Convey("When the box retries on errors fewer times than needed", func() {
+			So(bn.Input("source", &BoxInputConfig{
+				ErrorMode:  RetryOnError,
+				MaxRetries: 1,
+			}), ShouldBeNil)
+			So(son.Resume(), ShouldBeNil)
+
+			Convey("Then failed tuples should be reported", func() {
+				si.Wait(4)
+				dropped.Wait(4)
+				So(si.len(), ShouldEqual, 4)
+				So(dropped.len(), ShouldEqual, 4)
+				So(inputStats()["num_retries"], ShouldEqual, data.Int(4))
+			})
+		})
+
+		Convey("When adding an input with a negative number of retries", func() {
+			err := bn.Input("source", &BoxInputConfig{
+				ErrorMode:  RetryOnError,
+				MaxRetries: -1,
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// slowBox blocks processing tuples having the "seq" of 2 until the Context
+// is canceled.
+type slowBox struct {
+	canceled chan struct{}
+}
+
+func (b *slowBox) Process(ctx *Context, t *Tuple, w Writer) error {
+	if seq, _ := data.AsInt(t.Data["seq"]); seq == 2 {
+		<-ctx.Done()
+		close(b.canceled)
+		return w.Write(ctx, t) // this should be discarded
+	}
+	return w.Write(ctx, t)
+}
+
+func TestBoxProcessTimeout(t *testing.T) {
+	Convey("Given a topology with a box having a process timeout", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "process_timeout")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(freshTuples()), &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+
+		b := &slowBox{canceled: make(chan struct{})}
+		bn, err := t.AddBox("box", b, &BoxConfig{
+			ProcessTimeout: 10 * time.Millisecond,
+		})
+		So(err, ShouldBeNil)
+		So(bn.Input("source", &BoxInputConfig{
+			ErrorMode: DropOnError,
+		}), ShouldBeNil)
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("box", nil), ShouldBeNil)
+
+		Convey("When the box takes too long to process a tuple", func() {
+			So(son.Resume(), ShouldBeNil)
+			si.Wait(7)
+			<-b.canceled
+
+			Convey("Then the tuple should be dropped", func() {
+				So(si.len(), ShouldEqual, 7)
+				for i := 0; i < si.len(); i++ {
+					seq, _ := data.AsInt(si.get(i).Data["seq"])
+					So(seq, ShouldNotEqual, 2)
+				}
+			})
+
+			Convey("Then the timeout should be counted", func() {
+				st := bn.Status()["input_stats"].(data.Map)
+				So(st["num_timeouts"], ShouldEqual, data.Int(1))
+				So(st["num_errors"], ShouldEqual, data.Int(1))
+			})
+		})
+	})
+
+	Convey("Given a topology with a box without a process timeout", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "process_timeout")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		bn, err := t.AddBox("box", &oddFailBox{attempts: map[int64]int{}}, nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then the status shouldn't have the number of timeouts", func() {
+			st := bn.Status()["input_stats"].(data.Map)
+			So(st, ShouldNotContainKey, "num_timeouts")
+		})
+	})
+}
@@ -225,3 +225,22 @@ func TestNotExistError(t *testing.T) {
 		})
 	})
 }
+
+func TestTimeoutError(t *testing.T) {
+	Convey("Given an error", t, func() {
+		err := errors.New("test failure")
+
+		Convey("Then it shouldn't be a timeout error", func() {
+			So(IsTimeoutError(err), ShouldBeFalse)
+		})
+
+		Convey("Then it can be wrapped as a timeout error", func() {
+			e := TimeoutError(err)
+			So(IsTimeoutError(e), ShouldBeTrue)
+
+			Convey("And the original error message shouldn't be changed", func() {
+				So(e.Error(), ShouldEqual, "test failure")
+			})
+		})
+	})
+}
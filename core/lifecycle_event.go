@@ -0,0 +1,101 @@
+package core
+
+import (
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// LifecycleEventType is the type of a LifecycleEvent.
+type LifecycleEventType string
+
+const (
+	// LENodeFailed is the event emitted when a node stops with an error.
+	// Detail has "error".
+	LENodeFailed LifecycleEventType = "node_failed"
+
+	// LEQueueSaturated is the event emitted when an input queue of a node
+	// becomes full. The event is emitted at most once in
+	// QueueSaturationInterval for each queue. Detail has "input" and
+	// "queue_size".
+	LEQueueSaturated LifecycleEventType = "queue_saturated"
+
+	// LECheckpointCompleted is the event emitted when a checkpoint of the
+	// topology is taken. Detail has the same information as
+	// Topology.Checkpoint returns.
+	LECheckpointCompleted LifecycleEventType = "checkpoint_completed"
+)
+
+// QueueSaturationInterval is the minimum interval of LEQueueSaturated events
+// of a queue.
+const QueueSaturationInterval = time.Minute
+
+// LifecycleEvent is an event which happened in a topology, such as a node
+// stopped with an error.
+type LifecycleEvent struct {
+	Type     LifecycleEventType
+	Topology string
+
+	// NodeType and NodeName are the node where the event happened. NodeName
+	// is empty when the event isn't related to a node.
+	NodeType NodeType
+	NodeName string
+
+	Time   time.Time
+	Detail data.Map
+}
+
+// LifecycleListener receives lifecycle events of a topology. It's called
+// synchronously by the goroutine where the event happened, which may be the
+// one processing tuples. So, it must return quickly and must not call methods
+// of the topology.
+type LifecycleListener func(e *LifecycleEvent)
+
+// lifecycleEvent emits the event to the listener. Topology and Time are set
+// by this method.
+func (c *Context) lifecycleEvent(e *LifecycleEvent) {
+	if c.lifecycle == nil {
+		return
+	}
+	e.Topology = c.topologyName
+	e.Time = time.Now()
+	c.lifecycle(e)
+}
+
+// nodeFailed emits LENodeFailed.
+func (c *Context) nodeFailed(nodeType NodeType, nodeName string, err error) {
+	c.lifecycleEvent(&LifecycleEvent{
+		Type:     LENodeFailed,
+		NodeType: nodeType,
+		NodeName: nodeName,
+		Detail: data.Map{
+			"error": data.String(err.Error()),
+		},
+	})
+}
+
+// saturated emits LEQueueSaturated unless the queue has been reported in
+// QueueSaturationInterval.
+func (s *pipeSender) saturated(ctx *Context) {
+	if ctx == nil || ctx.lifecycle == nil || s.nodeName == "" {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&s.lastSaturated)
+	if last != 0 && now-last < int64(QueueSaturationInterval) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&s.lastSaturated, last, now) {
+		return // Another goroutine is reporting it.
+	}
+	ctx.lifecycleEvent(&LifecycleEvent{
+		Type:     LEQueueSaturated,
+		NodeType: s.nodeType,
+		NodeName: s.nodeName,
+		Detail: data.Map{
+			"input":      data.String(s.inputName),
+			"queue_size": data.Int(cap(s.out)),
+		},
+	})
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// lifecycleEventRecorder records lifecycle events emitted by a topology.
+type lifecycleEventRecorder struct {
+	m      sync.Mutex
+	events []*LifecycleEvent
+	ch     chan struct{}
+}
+
+func newLifecycleEventRecorder() *lifecycleEventRecorder {
+	return &lifecycleEventRecorder{
+		ch: make(chan struct{}, 100),
+	}
+}
+
+func (r *lifecycleEventRecorder) listen(e *LifecycleEvent) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.events = append(r.events, e)
+	r.ch <- struct{}{}
+}
+
+// wait waits for an event and returns it.
+func (r *lifecycleEventRecorder) wait() *LifecycleEvent {
+	select {
+	case <-r.ch:
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.events[len(r.events)-1]
+}
+
+func (r *lifecycleEventRecorder) len() int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return len(r.events)
+}
+
+func TestLifecycleEvents(t *testing.T) {
+	Convey("Given a topology having a lifecycle listener", t, func() {
+		r := newLifecycleEventRecorder()
+		ctx := NewContext(&ContextConfig{
+			Checkpoint: &CheckpointConfig{
+				Storage: newTestCheckpointStorage(),
+			},
+			LifecycleListener: r.listen,
+		})
+		t, err := NewDefaultTopology(ctx, "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		Convey("When a source fails", func() {
+			_, err := t.AddSource("source", &failingSource{err: errors.New("test failure")}, nil)
+			So(err, ShouldBeNil)
+
+			Convey("Then node_failed should be emitted", func() {
+				e := r.wait()
+				So(e, ShouldNotBeNil)
+				So(e.Type, ShouldEqual, LENodeFailed)
+				So(e.Topology, ShouldEqual, "test")
+				So(e.NodeType, ShouldEqual, NTSource)
+				So(e.NodeName, ShouldEqual, "source")
+				So(e.Detail["error"], ShouldEqual, data.String("test failure"))
+			})
+		})
+
+		Convey("When a checkpoint is taken", func() {
+			_, err := t.Checkpoint()
+			So(err, ShouldBeNil)
+
+			Convey("Then checkpoint_completed should be emitted", func() {
+				e := r.wait()
+				So(e, ShouldNotBeNil)
+				So(e.Type, ShouldEqual, LECheckpointCompleted)
+				So(e.NodeName, ShouldBeEmpty)
+				So(e.Detail["id"], ShouldEqual, data.Int(1))
+			})
+		})
+	})
+
+	Convey("Given a pipe of a node whose context has a lifecycle listener", t, func() {
+		r := newLifecycleEventRecorder()
+		ctx := NewContext(&ContextConfig{
+			LifecycleListener: r.listen,
+		})
+		ctx.topologyName = "test"
+		_, s := newPipe("input", 1)
+		s.dropMode = DropLatest
+		s.nodeType, s.nodeName = NTBox, "box"
+
+		Convey("When the queue becomes full", func() {
+			for i := 0; i < 3; i++ {
+				So(s.Write(ctx, NewTuple(data.Map{})), ShouldBeNil)
+			}
+
+			Convey("Then queue_saturated should be emitted only once", func() {
+				e := r.wait()
+				So(e, ShouldNotBeNil)
+				So(e.Type, ShouldEqual, LEQueueSaturated)
+				So(e.NodeType, ShouldEqual, NTBox)
+				So(e.NodeName, ShouldEqual, "box")
+				So(e.Detail["input"], ShouldEqual, data.String("input"))
+				So(e.Detail["queue_size"], ShouldEqual, data.Int(1))
+				So(r.len(), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the queue has room", func() {
+			So(s.Write(ctx, NewTuple(data.Map{})), ShouldBeNil)
+
+			Convey("Then no event should be emitted", func() {
+				So(r.len(), ShouldEqual, 0)
+			})
+		})
+	})
+}
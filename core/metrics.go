@@ -0,0 +1,114 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// latencyBuckets has upper bounds of buckets of latency histograms in
+// seconds. Processing a tuple usually takes from microseconds to
+// milliseconds, so buckets are finer than the ones commonly used for
+// latencies of requests.
+var latencyBuckets = []float64{
+	0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5,
+}
+
+// latencyHistogram is a histogram of durations of processing tuples. It's
+// updated without locks.
+//
+// Like dataDestinations, int64 fields must be at the beginning of the struct
+// for 64-bit alignment.
+type latencyHistogram struct {
+	count    int64
+	sumNanos int64
+
+	// counts has the number of observations in each bucket. The last
+	// element is for observations larger than the last bucket.
+	counts []int64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{
+		counts: make([]int64, len(latencyBuckets)+1),
+	}
+}
+
+// observe records a duration of processing a tuple.
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := sort.SearchFloat64s(latencyBuckets, d.Seconds())
+	atomic.AddInt64(&h.counts[i], 1)
+	atomic.AddInt64(&h.sumNanos, int64(d))
+	atomic.AddInt64(&h.count, 1)
+}
+
+// status returns the histogram. "buckets" has cumulative counts of
+// observations less than or equal to "le" in seconds.
+func (h *latencyHistogram) status() data.Map {
+	buckets := make(data.Array, len(latencyBuckets))
+	var cum int64
+	for i, le := range latencyBuckets {
+		cum += atomic.LoadInt64(&h.counts[i])
+		buckets[i] = data.Map{
+			"le":    data.Float(le),
+			"count": data.Int(cum),
+		}
+	}
+	return data.Map{
+		"count":   data.Int(atomic.LoadInt64(&h.count)),
+		"sum":     data.Float(time.Duration(atomic.LoadInt64(&h.sumNanos)).Seconds()),
+		"buckets": buckets,
+	}
+}
+
+// Metrics keeps track of running topologies so that statuses of their nodes
+// can be collected and exported to monitoring systems. A topology is added to
+// the Metrics of its Context when it's created and removed when it stops.
+//
+// Contexts share DefaultMetrics unless ContextConfig.Metrics is given, so
+// that topologies created in the same process, whether by a server or by
+// a program, are collected together.
+type Metrics struct {
+	m          sync.RWMutex
+	topologies map[Topology]struct{}
+}
+
+// DefaultMetrics is the Metrics used by Contexts which aren't given one by
+// ContextConfig.Metrics.
+var DefaultMetrics = NewMetrics()
+
+// NewMetrics creates a new Metrics having no topology.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		topologies: map[Topology]struct{}{},
+	}
+}
+
+func (m *Metrics) add(t Topology) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.topologies[t] = struct{}{}
+}
+
+func (m *Metrics) remove(t Topology) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	delete(m.topologies, t)
+}
+
+// Topologies returns topologies which are running, sorted by their names.
+func (m *Metrics) Topologies() []Topology {
+	m.m.RLock()
+	ts := make([]Topology, 0, len(m.topologies))
+	for t := range m.topologies {
+		ts = append(ts, t)
+	}
+	m.m.RUnlock()
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Name() < ts[j].Name()
+	})
+	return ts
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	Convey("Given a latency histogram", t, func() {
+		h := newLatencyHistogram()
+
+		Convey("When observing durations", func() {
+			h.observe(20 * time.Microsecond)
+			h.observe(100 * time.Microsecond)
+			h.observe(2 * time.Millisecond)
+			h.observe(time.Minute)
+			st := h.status()
+
+			Convey("Then it should have the number and the sum of them", func() {
+				So(st["count"], ShouldEqual, 4)
+				sum, err := data.AsFloat(st["sum"])
+				So(err, ShouldBeNil)
+				So(sum, ShouldAlmostEqual, 60.00212)
+			})
+
+			Convey("Then buckets should have cumulative counts", func() {
+				bs := st["buckets"].(data.Array)
+				So(len(bs), ShouldEqual, len(latencyBuckets))
+				counts := map[float64]int64{}
+				for _, b := range bs {
+					m := b.(data.Map)
+					le, _ := data.AsFloat(m["le"])
+					c, _ := data.AsInt(m["count"])
+					counts[le] = c
+				}
+				So(counts[0.00001], ShouldEqual, 0)
+				So(counts[0.00005], ShouldEqual, 1)
+				So(counts[0.0001], ShouldEqual, 2)
+				So(counts[0.001], ShouldEqual, 2)
+				So(counts[0.005], ShouldEqual, 3)
+				So(counts[5], ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	Convey("Given a Metrics", t, func() {
+		m := NewMetrics()
+
+		Convey("When creating topologies with contexts having it", func() {
+			t1, err := NewDefaultTopology(NewContext(&ContextConfig{Metrics: m}), "t1")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				t1.Stop()
+			})
+			t2, err := NewDefaultTopology(NewContext(&ContextConfig{Metrics: m}), "t0")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				t2.Stop()
+			})
+
+			Convey("Then it should have them sorted by their names", func() {
+				So(m.Topologies(), ShouldResemble, []Topology{t2, t1})
+			})
+
+			Convey("Then the default one shouldn't have them", func() {
+				for _, t := range DefaultMetrics.Topologies() {
+					So(t, ShouldNotEqual, t1)
+				}
+			})
+
+			Convey("Then a stopped topology should be removed", func() {
+				So(t1.Stop(), ShouldBeNil)
+				So(m.Topologies(), ShouldResemble, []Topology{t2})
+			})
+		})
+	})
+
+	Convey("Given a context without a Metrics", t, func() {
+		ctx := NewContext(nil)
+
+		Convey("When creating a topology", func() {
+			t, err := NewDefaultTopology(ctx, "test")
+			So(err, ShouldBeNil)
+			Reset(func() {
+				t.Stop()
+			})
+
+			Convey("Then it should be added to the default one", func() {
+				So(ctx.Metrics(), ShouldEqual, DefaultMetrics)
+				So(DefaultMetrics.Topologies(), ShouldContain, t)
+			})
+		})
+	})
+}
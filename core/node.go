@@ -6,6 +6,7 @@ import (
 	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 // NodeType represents the type of a node in a topology.
@@ -61,11 +62,55 @@ func ValidateSymbol(name string) error {
 	return nil
 }
 
-func nodeLogFields(t NodeType, name string) logrus.Fields {
-	return logrus.Fields{
+// ValidateLabels validates keys and values of labels attached to nodes. A key
+// has to be a valid symbol and a value has to be at most 255 letters so that
+// labels can be used as labels of metrics and fields of logs.
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if !nodeNameRegexp.MatchString(k) || len(k) > 127 {
+			return fmt.Errorf("the label key doesn't follow the format [a-zA-Z][a-zA-Z0-9_]*: %v", k)
+		}
+		if len(v) > 255 {
+			return fmt.Errorf("the value of label '%v' can be at most 255 letters: %v", k, len(v))
+		}
+	}
+	return nil
+}
+
+// nodeLabels has labels of nodes in a topology so that logs written with
+// the name of a node can have its labels.
+type nodeLabels struct {
+	m      sync.RWMutex
+	labels map[string]map[string]string
+}
+
+func (c *Context) setNodeLabels(name string, labels map[string]string) {
+	if c.labels == nil {
+		return
+	}
+	c.labels.m.Lock()
+	defer c.labels.m.Unlock()
+	if len(labels) == 0 {
+		delete(c.labels.labels, strings.ToLower(name))
+		return
+	}
+	c.labels.labels[strings.ToLower(name)] = labels
+}
+
+func (c *Context) nodeLogFields(t NodeType, name string) logrus.Fields {
+	fs := logrus.Fields{
 		"node_type": t.String(),
 		"node_name": name,
 	}
+	if c.labels == nil {
+		return fs
+	}
+	c.labels.m.RLock()
+	defer c.labels.m.RUnlock()
+	if l, ok := c.labels.labels[strings.ToLower(name)]; ok {
+		fs["labels"] = l
+	}
+	return fs
 }
 
 // Node is a node registered to a topology. It defines methods
@@ -116,6 +161,7 @@ type Node interface {
 	//	* error: an error message of the Box if an error happened and it stopped the Box
 	//	* input_stats: statistical information of the Source's output
 	//	* output_stats: statistical information of the Box's output
+	//	* process_latency: the histogram of durations of Box.Process
 	//	* behaviors:
 	//		* stop_on_inbound_disconnect: true if the Box stops when all inbound
 	//		                              connections are closed
@@ -125,12 +171,23 @@ type Node interface {
 	//		* remove_on_stop: true if the Box is removed from the topology
 	//		                  when it stops
 	//	* box: the status of the Box if it implements Statuser
+	//	* quarantine: the quarantine status of the Box. Only a Box having
+	//	              BoxConfig.Quarantine has this field. It has following
+	//	              fields:
+	//		* quarantined: true if the Box is in quarantine
+	//		* num_panics: the total number of panics occurred in the Box
+	//		* num_dropped: the number of tuples dropped in quarantine
+	//		* max_panics: the number of panics which puts the Box into quarantine
+	//		* window: the window of the policy in seconds
+	//		* since: the time when the Box was put into quarantine
+	//		* last_panic: the message of the last panic
 	//
 	// When the node is a Sink, following information will be returned:
 	//
 	//	* state: the current state of the Box
 	//	* error: an error message of the Sink if an error happened and it stopped the Sink
 	//	* input_stats: statistical information of the Source's output
+	//	* process_latency: the histogram of durations of Sink.Write
 	//	* behaviors:
 	//		* stop_on_disconnect: true if the Sink stops when all inbound
 	//		                      connections are closed
@@ -138,6 +195,20 @@ type Node interface {
 	//		* remove_on_stop: true if the Sink is removed from the topology
 	//		                  when it stops
 	//	* sink: the status of the Sink if it implements Statuser
+	//	* wal: the status of the write-ahead log. Only a Sink having
+	//	       SinkConfig.WAL has this field. It has following fields:
+	//		* dir: the directory of the log
+	//		* num_pending: the number of tuples which aren't written to the Sink yet
+	//		* pending_bytes: the size of pending tuples in the log
+	//		* num_appended: the number of tuples appended to the log
+	//		* num_delivered: the number of tuples written to the Sink
+	//		* num_failures: the number of times the Sink failed to write a tuple
+	//		* num_dropped: the number of tuples dropped due to fatal errors
+	//		* lag: the time in seconds since the oldest pending tuple was appended
+	//		* last_error: the message of the last error returned from the Sink
+	//
+	// A node having labels given by its config also has "labels" field
+	// containing them.
 	//
 	// "input_stats" contains statistical information of the node's input. It
 	// has following fields:
@@ -145,6 +216,10 @@ type Node interface {
 	//	* num_received_total: the total number of tuples the node received
 	//	* num_errors: the number of errors that the node failed to process tuples
 	//	              including temporary errors
+	//	* num_retries: the number of retries of tuples which failed to be
+	//	               processed
+	//	* num_timeouts: the number of tuples whose processing timed out. Only
+	//	                a Box having BoxConfig.ProcessTimeout has this field
 	//	* inputs: the information of data sources connected to the node
 	//
 	// "inputs" field in "input_stats" contains the input statistics of each
@@ -153,6 +228,7 @@ type Node interface {
 	//	* num_received: the number of tuples the node has received so far
 	//	* queue_size: the size of the queue connected to the node
 	//	* num_queued: the number of tuples buffered in the queue
+	//	* error_mode: the mode which controls the behavior on errors
 	//
 	// "output_stats" contains statistical information of the node's output. It
 	// has following fields:
@@ -170,10 +246,21 @@ type Node interface {
 	//	* queue_size: the size of the queue connected to the node
 	//	* num_queued: the number of tuples buffered in the queue
 	//
+	// "process_latency" has the following fields:
+	//
+	//	* count: the number of tuples processed
+	//	* sum: the total duration of processing them in seconds
+	//	* buckets: an array of maps having "le" and "count", where "count" is
+	//	           the number of tuples processed in "le" seconds or less
+	//
 	// Numbers in inputs and outputs might not be accurate because they use
 	// loose synchronization for efficiency.
 	Status() data.Map
 
+	// Labels returns labels given to the node by its config. The returned map
+	// must not be modified.
+	Labels() map[string]string
+
 	// Meta returns meta information of the node. The meta information can be
 	// updated by changing the return value. However, the meta information is
 	// not protected from concurrent writes and the caller has to care about it.
@@ -251,6 +338,21 @@ type BoxNode interface {
 	//	boxNode.StopOnDisconnect(core.Inbound | core.Outbound)
 	//	boxNode.StopOnDisconnect(core.Outbound) // core.Inbound is still enabled.
 	StopOnDisconnect(dir ConnDir)
+
+	// Pause pauses the Box. A paused Box doesn't read tuples from its inputs
+	// and they're kept in input queues. Once a queue gets full, the sender
+	// blocks or tuples are dropped depending on BoxInputConfig.DropMode. When
+	// the Box is stopped while it's paused, tuples in its queues are
+	// processed only if graceful stop is enabled. Pause is idempotent.
+	Pause() error
+
+	// Resume resumes the paused Box. Resume is idempotent.
+	Resume() error
+
+	// ReleaseQuarantine releases the Box from quarantine and the Box resumes
+	// processing tuples buffered in its input queues. It returns an error
+	// when the Box doesn't have BoxConfig.Quarantine or isn't in quarantine.
+	ReleaseQuarantine() error
 }
 
 // ConnDir shows a direction of a connection between nodes.
@@ -279,6 +381,59 @@ func validateCapacity(c int) error {
 	return nil
 }
 
+// ErrorMode is a mode which controls the behavior of a Box or a Sink when it
+// fails to process a tuple received from an input. Fatal errors always stop
+// the node regardless of the mode.
+type ErrorMode int
+
+const (
+	// ReportOnError is one of ErrorMode that the tuple which caused an error
+	// is dropped and reported as a dropped tuple, so that it can be received
+	// from a Source created by NewDroppedTupleCollectorSource (i.e. the
+	// dead-letter stream). This is the default mode.
+	ReportOnError ErrorMode = iota
+
+	// DropOnError is one of ErrorMode that the tuple which caused an error is
+	// silently dropped without being reported.
+	DropOnError
+
+	// StopOnError is one of ErrorMode that an error is considered fatal and
+	// stops the node.
+	StopOnError
+
+	// RetryOnError is one of ErrorMode that the node processes the tuple
+	// again until it succeeds or the number of retries exceeds the limit.
+	// The tuple is reported as a dropped tuple when all retries failed. Note
+	// that a Box may emit the same tuple more than once when it wrote some
+	// tuples before returning an error.
+	RetryOnError
+)
+
+func (m ErrorMode) String() string {
+	switch m {
+	case ReportOnError:
+		return "report"
+	case DropOnError:
+		return "drop"
+	case StopOnError:
+		return "stop"
+	case RetryOnError:
+		return "retry"
+	default:
+		return "unknown"
+	}
+}
+
+func validateErrorMode(m ErrorMode, maxRetries int) error {
+	if m < ReportOnError || m > RetryOnError {
+		return fmt.Errorf("unknown error mode: %v", int(m))
+	}
+	if maxRetries < 0 {
+		return fmt.Errorf("the number of retries %d must not be negative", maxRetries)
+	}
+	return nil
+}
+
 // BoxInputConfig has parameters to customize input behavior of a Box on each
 // input pipe.
 type BoxInputConfig struct {
@@ -295,11 +450,26 @@ type BoxInputConfig struct {
 	// DropMode is a mode which controls the behavior of dropping tuples at the
 	// output side of the queue when it is full.
 	DropMode QueueDropMode
+
+	// ErrorMode is a mode which controls the behavior of the Box when it
+	// fails to process a tuple received from this input.
+	ErrorMode ErrorMode
+
+	// MaxRetries is the maximum number of retries when ErrorMode is
+	// RetryOnError.
+	MaxRetries int
+
+	// Transformer transforms or filters tuples received from this input
+	// before they're processed by the Box. It's optional.
+	Transformer EdgeTransformer
 }
 
 // Validate validates values of BoxInputConfig.
 func (c *BoxInputConfig) Validate() error {
-	return validateCapacity(c.Capacity)
+	if err := validateCapacity(c.Capacity); err != nil {
+		return err
+	}
+	return validateErrorMode(c.ErrorMode, c.MaxRetries)
 }
 
 func (c *BoxInputConfig) inputName() string {
@@ -342,6 +512,19 @@ type SinkNode interface {
 	// method, the Sink can automatically stop even if Stop method isn't
 	// explicitly called.
 	StopOnDisconnect()
+
+	// Pause pauses the Sink. A paused Sink doesn't write tuples and they're
+	// kept in input queues as BoxNode.Pause does. Pause is idempotent.
+	Pause() error
+
+	// Resume resumes the paused Sink. Resume is idempotent.
+	Resume() error
+
+	// ReplayWAL makes the Sink retry writing tuples pending in its
+	// write-ahead log immediately instead of waiting for the next retry, e.g.
+	// after the destination of the Sink recovered. It returns an error when
+	// the Sink doesn't have SinkConfig.WAL.
+	ReplayWAL() error
 }
 
 // SinkInputConfig has parameters to customize input behavior of a Sink on
@@ -356,11 +539,26 @@ type SinkInputConfig struct {
 	// DropMode is a mode which controls the behavior of dropping tuples at the
 	// output side of the queue when it is full.
 	DropMode QueueDropMode
+
+	// ErrorMode is a mode which controls the behavior of the Sink when it
+	// fails to write a tuple received from this input.
+	ErrorMode ErrorMode
+
+	// MaxRetries is the maximum number of retries when ErrorMode is
+	// RetryOnError.
+	MaxRetries int
+
+	// Transformer transforms or filters tuples received from this input
+	// before they're written to the Sink. It's optional.
+	Transformer EdgeTransformer
 }
 
 // Validate validates values of SinkInputConfig.
 func (c *SinkInputConfig) Validate() error {
-	return validateCapacity(c.Capacity)
+	if err := validateCapacity(c.Capacity); err != nil {
+		return err
+	}
+	return validateErrorMode(c.ErrorMode, c.MaxRetries)
 }
 
 func (c *SinkInputConfig) capacity() int {
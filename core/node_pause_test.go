@@ -0,0 +1,86 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func waitQueued(n Node, input string, queued int64) bool {
+	for i := 0; i < 1000; i++ {
+		st := n.Status()["input_stats"].(data.Map)["inputs"].(data.Map)
+		if in, ok := st[input].(data.Map); ok && in["num_queued"] == data.Int(queued) {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestPauseBoxAndSink(t *testing.T) {
+	Convey("Given a topology having a source, a box, and a sink", t, func() {
+		tp, err := NewDefaultTopology(NewContext(nil), "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			tp.Stop()
+		})
+
+		so := NewTupleIncrementalEmitterSource(freshTuples())
+		_, err = tp.AddSource("source", so, nil)
+		So(err, ShouldBeNil)
+		bn, err := tp.AddBox("box", BoxFunc(forwardBox), nil)
+		So(err, ShouldBeNil)
+		So(bn.Input("source", nil), ShouldBeNil)
+		si := NewTupleCollectorSink()
+		sn, err := tp.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sn.Input("box", nil), ShouldBeNil)
+
+		Convey("When pausing the box", func() {
+			So(bn.Pause(), ShouldBeNil)
+			So(bn.Pause(), ShouldBeNil)
+			so.EmitTuples(2)
+
+			Convey("Then tuples should be kept in its queue", func() {
+				So(bn.State().Get(), ShouldEqual, TSPaused)
+				So(waitQueued(bn, "source", 2), ShouldBeTrue)
+				So(si.len(), ShouldEqual, 0)
+			})
+
+			Convey("Then the sink should receive tuples after resuming the box", func() {
+				So(bn.Resume(), ShouldBeNil)
+				So(bn.Resume(), ShouldBeNil)
+				si.Wait(2)
+				So(bn.State().Get(), ShouldEqual, TSRunning)
+				So(si.get(1).Data["seq"], ShouldEqual, data.Int(2))
+			})
+
+			Convey("Then graceful stop should process tuples in its queue", func() {
+				So(waitQueued(bn, "source", 2), ShouldBeTrue)
+				bn.EnableGracefulStop()
+				So(bn.Stop(), ShouldBeNil)
+				si.Wait(2)
+				So(bn.Pause(), ShouldNotBeNil)
+				So(bn.Resume(), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When pausing the sink", func() {
+			So(sn.Pause(), ShouldBeNil)
+			so.EmitTuples(2)
+
+			Convey("Then tuples should be kept in its queue", func() {
+				So(sn.State().Get(), ShouldEqual, TSPaused)
+				So(waitQueued(sn, "box", 2), ShouldBeTrue)
+				So(si.len(), ShouldEqual, 0)
+			})
+
+			Convey("Then it should write tuples after resuming", func() {
+				So(sn.Resume(), ShouldBeNil)
+				si.Wait(2)
+			})
+		})
+	})
+}
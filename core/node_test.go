@@ -2,6 +2,7 @@ package core
 
 import (
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"strings"
 	"testing"
 )
@@ -72,3 +73,76 @@ func TestValidateCapacity(t *testing.T) {
 		})
 	})
 }
+
+func TestNodeLabels(t *testing.T) {
+	Convey("Given a default topology", t, func() {
+		ctx := NewContext(nil)
+		dt, err := NewDefaultTopology(ctx, "dt1")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			dt.Stop()
+		})
+		labels := map[string]string{"team": "sensing"}
+
+		Convey("When adding nodes having labels", func() {
+			sn, err := dt.AddSource("source1", &DoesNothingSource{}, &SourceConfig{Labels: labels})
+			So(err, ShouldBeNil)
+			bn, err := dt.AddBox("box1", &DoesNothingBox{}, &BoxConfig{Labels: labels})
+			So(err, ShouldBeNil)
+			kn, err := dt.AddSink("sink1", &DoesNothingSink{}, &SinkConfig{Labels: labels})
+			So(err, ShouldBeNil)
+			labels["team"] = "modified"
+
+			Convey("Then they should have a copy of the labels", func() {
+				for _, n := range []Node{sn, bn, kn} {
+					So(n.Labels(), ShouldResemble, map[string]string{"team": "sensing"})
+				}
+			})
+
+			Convey("Then their statuses should have the labels", func() {
+				for _, n := range []Node{sn, bn, kn} {
+					So(n.Status()["labels"], ShouldResemble, data.Map{"team": data.String("sensing")})
+				}
+			})
+
+			Convey("Then log fields of them should have the labels", func() {
+				So(ctx.nodeLogFields(NTBox, "BOX1")["labels"], ShouldResemble, map[string]string{"team": "sensing"})
+			})
+
+			Convey("Then log fields shouldn't have the labels after the node is removed", func() {
+				So(dt.Remove("box1"), ShouldBeNil)
+				So(ctx.nodeLogFields(NTBox, "box1"), ShouldNotContainKey, "labels")
+			})
+		})
+
+		Convey("When adding a node without labels", func() {
+			bn, err := dt.AddBox("box1", &DoesNothingBox{}, nil)
+			So(err, ShouldBeNil)
+
+			Convey("Then its status shouldn't have labels", func() {
+				So(bn.Labels(), ShouldBeNil)
+				So(bn.Status(), ShouldNotContainKey, "labels")
+			})
+		})
+
+		Convey("When adding a node having invalid labels", func() {
+			_, err := dt.AddBox("box1", &DoesNothingBox{}, &BoxConfig{
+				Labels: map[string]string{"invalid-key": "a"},
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When adding a node having a too long label value", func() {
+			_, err := dt.AddSource("source1", &DoesNothingSource{}, &SourceConfig{
+				Labels: map[string]string{"team": strings.Repeat("a", 256)},
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
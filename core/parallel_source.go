@@ -0,0 +1,158 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// parallelSource runs multiple instances of sources in parallel and merges
+// their outputs into one stream.
+type parallelSource struct {
+	instances []*sourceInstance
+
+	m       sync.Mutex
+	stopped bool
+}
+
+// sourceInstance is one of the sources of a parallelSource.
+type sourceInstance struct {
+	source     Source
+	numWritten int64
+
+	// started and running are protected by parallelSource.m. err is set
+	// before running becomes false.
+	started bool
+	running bool
+	err     error
+}
+
+var (
+	_ Statuser = &parallelSource{}
+)
+
+// NewParallelSource creates a source which runs all sources given in
+// parallel. Tuples written by them are merged into one stream in the order
+// of their arrival. It's used to read from multiple partitions or sockets
+// behind one source name.
+//
+// The returned source stops when all sources stop. An error returned from
+// a source doesn't stop others, and it's reported in the status of the
+// source. The returned source doesn't support Resumable and
+// RewindableSource even if all sources support them, so the default pause
+// and resume implementation of a source node is used.
+func NewParallelSource(srcs []Source) (Source, error) {
+	if len(srcs) == 0 {
+		return nil, errors.New("at least one source is required")
+	}
+	ps := &parallelSource{
+		instances: make([]*sourceInstance, len(srcs)),
+	}
+	for i, s := range srcs {
+		ps.instances[i] = &sourceInstance{source: s}
+	}
+	return ps, nil
+}
+
+func (ps *parallelSource) GenerateStream(ctx *Context, w Writer) error {
+	wg := sync.WaitGroup{}
+	ps.m.Lock()
+	if ps.stopped {
+		ps.m.Unlock()
+		return nil
+	}
+	for _, i := range ps.instances {
+		i := i
+		i.started = true
+		i.running = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := i.source.GenerateStream(ctx, WriterFunc(func(ctx *Context, t *Tuple) error {
+				atomic.AddInt64(&i.numWritten, 1)
+				return w.Write(ctx, t)
+			}))
+
+			ps.m.Lock()
+			defer ps.m.Unlock()
+			i.err = err
+			i.running = false
+		}()
+	}
+	ps.m.Unlock()
+	wg.Wait()
+
+	// Only the first error is returned because others are in the status.
+	for _, i := range ps.instances {
+		if i.err != nil {
+			return i.err
+		}
+	}
+	return nil
+}
+
+func (ps *parallelSource) Stop(ctx *Context) error {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	ps.stopped = true
+
+	var lastErr error
+	for _, i := range ps.instances {
+		if !i.started {
+			continue
+		}
+		if err := i.source.Stop(ctx); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Status returns the aggregated status of all sources and the status of
+// each source. It has following fields:
+//
+//	* num_instances: the number of sources
+//	* num_running: the number of sources which are generating tuples
+//	* num_written: the total number of tuples written by sources
+//	* instances: an array of statuses of sources
+//
+// The status of each source has following fields:
+//
+//	* running: true when the source is generating tuples
+//	* num_written: the number of tuples written by the source
+//	* error: the error returned from the source (optional)
+//	* source: the status of the source if it implements Statuser (optional)
+func (ps *parallelSource) Status() data.Map {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+
+	var numRunning, total int64
+	is := make(data.Array, len(ps.instances))
+	for j, i := range ps.instances {
+		n := atomic.LoadInt64(&i.numWritten)
+		total += n
+		if i.running {
+			numRunning++
+		}
+
+		st := data.Map{
+			"running":     data.Bool(i.running),
+			"num_written": data.Int(n),
+		}
+		if i.err != nil {
+			st["error"] = data.String(i.err.Error())
+		}
+		if s, ok := i.source.(Statuser); ok {
+			st["source"] = s.Status()
+		}
+		is[j] = st
+	}
+	return data.Map{
+		"num_instances": data.Int(len(ps.instances)),
+		"num_running":   data.Int(numRunning),
+		"num_written":   data.Int(total),
+		"instances":     is,
+	}
+}
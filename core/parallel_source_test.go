@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestParallelSource(t *testing.T) {
+	Convey("Given a topology with a parallel source", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "parallel_source")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		srcs := []Source{
+			NewTupleEmitterSource(freshTuples()),
+			NewTupleEmitterSource(freshTuples()[:4]),
+			&failingSource{errors.New("cannot connect")},
+		}
+		ps, err := NewParallelSource(srcs)
+		So(err, ShouldBeNil)
+		son, err := t.AddSource("source", ps, &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("source", nil), ShouldBeNil)
+
+		Convey("When the source generates tuples", func() {
+			So(son.Resume(), ShouldBeNil)
+			son.State().Wait(TSStopped)
+
+			Convey("Then the sink should receive tuples from all instances", func() {
+				si.Wait(12)
+				So(si.len(), ShouldEqual, 12)
+			})
+
+			Convey("Then the status should have aggregated statistics", func() {
+				st := son.Status()["source"].(data.Map)
+				So(st["num_instances"], ShouldEqual, data.Int(3))
+				So(st["num_running"], ShouldEqual, data.Int(0))
+				So(st["num_written"], ShouldEqual, data.Int(12))
+			})
+
+			Convey("Then the status should have statistics of each instance", func() {
+				is := son.Status()["source"].(data.Map)["instances"].(data.Array)
+				So(len(is), ShouldEqual, 3)
+				So(is[0].(data.Map)["num_written"], ShouldEqual, data.Int(8))
+				So(is[1].(data.Map)["num_written"], ShouldEqual, data.Int(4))
+				So(is[2].(data.Map)["num_written"], ShouldEqual, data.Int(0))
+				So(is[2].(data.Map)["error"], ShouldEqual, data.String("cannot connect"))
+			})
+
+			Convey("Then the error of the instance should be reported by the node", func() {
+				So(son.Status()["error"], ShouldEqual, data.String("cannot connect"))
+			})
+		})
+	})
+
+	Convey("Given no source", t, func() {
+		Convey("When creating a parallel source", func() {
+			_, err := NewParallelSource(nil)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// failingSource is a source which fails without writing any tuple.
+type failingSource struct {
+	err error
+}
+
+func (s *failingSource) GenerateStream(ctx *Context, w Writer) error {
+	return s.err
+}
+
+func (s *failingSource) Stop(ctx *Context) error {
+	return nil
+}
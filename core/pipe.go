@@ -28,6 +28,15 @@ func newPipe(inputName string, capacity int) (*pipeReceiver, *pipeSender) {
 type pipeReceiver struct {
 	in     <-chan *Tuple
 	sender *pipeSender
+
+	// errorMode and maxRetries control the behavior of the node when it
+	// fails to process a tuple received from this pipe.
+	errorMode  ErrorMode
+	maxRetries int
+
+	// transformer is applied to tuples received from this pipe before
+	// they're written to the node. It's nil when the input doesn't have it.
+	transformer EdgeTransformer
 }
 
 // close closes the channel from the receiver side. It doesn't directly close
@@ -81,10 +90,19 @@ type pipeSender struct {
 	// cnt is the first field of this struct for 64-bit alignment.
 	cnt int64
 
+	// lastSaturated is the time in nanoseconds when the queue was reported
+	// to be full last time. It follows cnt for 64-bit alignment.
+	lastSaturated int64
+
 	inputName string
 	out       chan *Tuple
 	dropMode  QueueDropMode
 
+	// nodeType and nodeName are the node receiving tuples from the pipe.
+	// They're used to report saturation of the queue.
+	nodeType NodeType
+	nodeName string
+
 	// rwm protects out from write-close conflicts.
 	rwm sync.RWMutex
 
@@ -118,7 +136,12 @@ func (s *pipeSender) write(ctx *Context, in *Tuple, droppedTuple func(*Tuple)) e
 	t.InputName = s.inputName
 
 	if s.dropMode == DropNone {
-		s.out <- t
+		select {
+		case s.out <- t:
+		default:
+			s.saturated(ctx)
+			s.out <- t
+		}
 	} else {
 	sendLoop:
 		for {
@@ -126,6 +149,7 @@ func (s *pipeSender) write(ctx *Context, in *Tuple, droppedTuple func(*Tuple)) e
 			case s.out <- t:
 				break sendLoop
 			default:
+				s.saturated(ctx)
 				if s.dropMode == DropLatest {
 					droppedTuple(t)
 					return nil
@@ -215,6 +239,7 @@ type dataSources struct {
 	// See godoc for this struct.
 	numReceived int64
 	numErrors   int64
+	numRetries  int64
 
 	nodeType NodeType
 	nodeName string
@@ -252,6 +277,8 @@ const (
 	ddscStop
 	ddscToggleGracefulStop
 	ddscStopOnDisconnect
+	ddscPause
+	ddscResume
 )
 
 func (s *dataSources) add(name string, r *pipeReceiver) error {
@@ -349,8 +376,9 @@ func (s *dataSources) pour(ctx *Context, w Writer, parallelism int) error {
 			}
 		}
 
-		genCases := func(msgCh <-chan *dataSourcesMessage) []reflect.SelectCase {
+		genCases := func(msgCh <-chan *dataSourcesMessage) ([]reflect.SelectCase, map[uintptr]*pipeReceiver) {
 			cs := make([]reflect.SelectCase, 0, len(s.recvs)+2)
+			recvs := make(map[uintptr]*pipeReceiver, len(s.recvs))
 			cs = append(cs, reflect.SelectCase{
 				Dir:  reflect.SelectRecv,
 				Chan: reflect.ValueOf(msgCh),
@@ -364,12 +392,14 @@ func (s *dataSources) pour(ctx *Context, w Writer, parallelism int) error {
 			})
 
 			for _, r := range s.recvs {
-				cs = append(cs, reflect.SelectCase{
+				c := reflect.SelectCase{
 					Dir:  reflect.SelectRecv,
 					Chan: reflect.ValueOf(r.in),
-				})
+				}
+				cs = append(cs, c)
+				recvs[c.Chan.Pointer()] = r
 			}
-			return cs
+			return cs, recvs
 		}
 
 		// ensureLocked ensures proper lock for s. Removing this introduces
@@ -390,10 +420,10 @@ func (s *dataSources) pour(ctx *Context, w Writer, parallelism int) error {
 						ensureLocked.Done()
 					}
 				}()
-				cs := genCases(msgCh)
+				cs, recvs := genCases(msgCh)
 				ensureLocked.Done()
 				needDone = false
-				ins, err := s.pouringThread(ctx, w, cs)
+				ins, err := s.pouringThread(ctx, w, cs, recvs)
 				collectInputs.Do(func() {
 					// It's sufficient to collect input only once. The only
 					// problem which might happen is that ins has old receivers.
@@ -417,7 +447,7 @@ func (s *dataSources) pour(ctx *Context, w Writer, parallelism int) error {
 				if err != nil {
 					logOnce.Do(func() {
 						threadErr = err // return only one error
-						ctx.ErrLog(err).WithFields(nodeLogFields(s.nodeType, s.nodeName)).
+						ctx.ErrLog(err).WithFields(ctx.nodeLogFields(s.nodeType, s.nodeName)).
 							Error("the node stopped with a fatal error")
 					})
 				}
@@ -470,7 +500,18 @@ func (s *dataSources) pour(ctx *Context, w Writer, parallelism int) error {
 	return threadErr
 }
 
-func (s *dataSources) pouringThread(ctx *Context, w Writer, cs []reflect.SelectCase) (inputs []reflect.SelectCase, retErr error) {
+// inputObserver is implemented by a Writer given to dataSources.pour when
+// it needs to know that an input has been disconnected.
+type inputObserver interface {
+	// inputClosed is called with the InputName of tuples from the input.
+	// The node stops when it returns an error.
+	inputClosed(ctx *Context, inputName string) error
+}
+
+// pouringThread receives tuples from the given cases and writes them to w.
+// recvs has the pipeReceiver of each input case, keyed by the pointer of its
+// channel, to look up the error mode of the input.
+func (s *dataSources) pouringThread(ctx *Context, w Writer, cs []reflect.SelectCase, recvs map[uintptr]*pipeReceiver) (inputs []reflect.SelectCase, retErr error) {
 	const (
 		message = iota
 		defaultCase
@@ -514,6 +555,7 @@ func (s *dataSources) pouringThread(ctx *Context, w Writer, cs []reflect.SelectC
 
 	gracefulStopEnabled := false
 	stopOnDisconnect := false
+	paused := false
 
 	reportDT := func(t *Tuple, err error) {
 		ctx.droppedTuple(t, s.nodeType, s.nodeName, ETInput, err)
@@ -528,14 +570,30 @@ receiveLoop:
 			break
 		}
 
-		i, v, ok := reflect.Select(cs) // all cases are receive direction
+		// While paused, only controlling channels are watched so that tuples
+		// are kept in input queues.
+		active := cs
+		if paused {
+			active = cs[:maxControlIndex+1]
+		}
+		i, v, ok := reflect.Select(active) // all cases are receive direction
 		if !ok && i != defaultCase {
 			if i <= maxControlIndex {
 				retErr = FatalError(fmt.Errorf("a controlling channel (%v) of '%v' has been closed", i, s.nodeName))
 				return
 			}
 
+			if o, ok := w.(inputObserver); ok {
+				if r := recvs[cs[i].Chan.Pointer()]; r != nil {
+					if err := o.inputClosed(ctx, r.sender.inputName); err != nil {
+						retErr = err
+						return
+					}
+				}
+			}
+
 			// remove the closed channel by swapping it with the last element.
+			delete(recvs, cs[i].Chan.Pointer())
 			cs[i], cs[len(cs)-1] = cs[len(cs)-1], cs[i]
 			cs = cs[:len(cs)-1]
 			continue
@@ -545,7 +603,7 @@ receiveLoop:
 		case message:
 			msg, ok := v.Interface().(*dataSourcesMessage)
 			if !ok {
-				ctx.Log().WithFields(nodeLogFields(s.nodeType, s.nodeName)).
+				ctx.Log().WithFields(ctx.nodeLogFields(s.nodeType, s.nodeName)).
 					Warnf("Received an invalid control message in dataSources: %v", v.Interface())
 				continue
 			}
@@ -554,19 +612,23 @@ receiveLoop:
 			case ddscAddReceiver:
 				c, ok := msg.v.(*pipeReceiver)
 				if !ok {
-					ctx.Log().WithFields(nodeLogFields(s.nodeType, s.nodeName)).
+					ctx.Log().WithFields(ctx.nodeLogFields(s.nodeType, s.nodeName)).
 						Warn("Cannot add a new receiver due to a type error")
 					break
 				}
-				cs = append(cs, reflect.SelectCase{
+				nc := reflect.SelectCase{
 					Dir:  reflect.SelectRecv,
 					Chan: reflect.ValueOf(c.in),
-				})
+				}
+				cs = append(cs, nc)
+				recvs[nc.Chan.Pointer()] = c
 
 			case ddscStop:
 				if !gracefulStopEnabled {
 					break receiveLoop
 				}
+				// tuples in queues need to be processed even when paused
+				paused = false
 				cs[defaultCase].Dir = reflect.SelectDefault // activate the default case
 
 			case ddscToggleGracefulStop:
@@ -574,6 +636,12 @@ receiveLoop:
 
 			case ddscStopOnDisconnect:
 				stopOnDisconnect = true
+
+			case ddscPause:
+				paused = true
+
+			case ddscResume:
+				paused = false
 			}
 
 		case defaultCase:
@@ -585,12 +653,34 @@ receiveLoop:
 			t, ok := v.Interface().(*Tuple)
 			if !ok {
 				atomic.AddInt64(&s.numErrors, 1)
-				ctx.Log().WithFields(nodeLogFields(s.nodeType, s.nodeName)).
+				ctx.Log().WithFields(ctx.nodeLogFields(s.nodeType, s.nodeName)).
 					Error("Cannot receive a tuple from a receiver due to a type error")
 				break
 			}
 
-			err := w.Write(ctx, t)
+			errorMode := ReportOnError
+			r := recvs[cs[i].Chan.Pointer()]
+			write := w.Write
+			if r != nil {
+				errorMode = r.errorMode
+				if r.transformer != nil {
+					write = func(ctx *Context, t *Tuple) error {
+						tt, err := r.transformer.Transform(ctx, t)
+						if err != nil || tt == nil {
+							return err
+						}
+						return w.Write(ctx, tt)
+					}
+				}
+			}
+
+			err := write(ctx, t)
+			if err != nil && errorMode == RetryOnError {
+				for n := 0; n < r.maxRetries && err != nil && !IsFatalError(err); n++ {
+					atomic.AddInt64(&s.numRetries, 1)
+					err = write(ctx, t)
+				}
+			}
 			if err == nil {
 				break
 			}
@@ -603,6 +693,14 @@ receiveLoop:
 				reportDT(t, err)
 				return
 
+			case errorMode == StopOnError:
+				retErr = FatalError(err)
+				reportDT(t, err)
+				return
+
+			case errorMode == DropOnError:
+				// Skip this tuple without reporting it
+
 			case IsTemporaryError(err):
 				// TODO: retry
 				reportDT(t, err) // TODO: don't write a tuple until retry fails
@@ -635,6 +733,22 @@ func (s *dataSources) stopOnDisconnect() {
 	})
 }
 
+// pause stops reading tuples from inputs. Tuples sent while it's paused are
+// kept in input queues, so senders block or tuples are dropped depending on
+// the drop mode of each input once its queue gets full.
+func (s *dataSources) pause() {
+	s.sendMessage(&dataSourcesMessage{
+		cmd: ddscPause,
+	})
+}
+
+// resume resumes reading tuples from inputs.
+func (s *dataSources) resume() {
+	s.sendMessage(&dataSourcesMessage{
+		cmd: ddscResume,
+	})
+}
+
 // stop stops the source after processing tuples which it currently has.
 func (s *dataSources) stop(ctx *Context) {
 	s.m.Lock()
@@ -664,7 +778,8 @@ func (s *dataSources) status() data.Map {
 	st := data.Map{}
 	st["num_received_total"] = data.Int(atomic.LoadInt64(&s.numReceived))
 	st["num_errors"] = data.Int(atomic.LoadInt64(&s.numErrors))
-	// TODO: Add num_temporary_errors and num_retries.
+	st["num_retries"] = data.Int(atomic.LoadInt64(&s.numRetries))
+	// TODO: Add num_temporary_errors.
 
 	m := make(data.Map, len(s.recvs))
 	for name, recv := range s.recvs {
@@ -678,6 +793,7 @@ func (s *dataSources) status() data.Map {
 			"num_received": data.Int(recv.sender.count() - int64(l)),
 			"queue_size":   data.Int(c),
 			"num_queued":   data.Int(l),
+			"error_mode":   data.String(recv.errorMode.String()),
 		}
 	}
 	st["inputs"] = m
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// QuarantinePolicy controls when a Box is put into quarantine. A Box in
+// quarantine stops processing tuples until BoxNode.ReleaseQuarantine is
+// called, so a Box which keeps panicking doesn't waste resources or flood
+// logs. Tuples sent to the Box while it's in quarantine are dropped and
+// reported as dropped tuples regardless of the ErrorMode of its inputs.
+// They aren't kept in input queues because they would block sources shared
+// with other nodes.
+//
+// A panic occurred in Box.Process, including a panic in a UDF called from it
+// which is reported as an error satisfying IsPanicError, is counted by the
+// policy.
+type QuarantinePolicy struct {
+	// MaxPanics is the number of panics within Window which puts the Box into
+	// quarantine. It must be positive.
+	MaxPanics int
+
+	// Window is the duration in which panics are counted. When it's 0, all
+	// panics since the Box started or was released from quarantine are
+	// counted.
+	Window time.Duration
+}
+
+// Validate validates the policy.
+func (p *QuarantinePolicy) Validate() error {
+	if p.MaxPanics <= 0 {
+		return fmt.Errorf("the maximum number of panics must be positive: %v", p.MaxPanics)
+	}
+	if p.Window < 0 {
+		return fmt.Errorf("the window must not be negative: %v", p.Window)
+	}
+	return nil
+}
+
+// quarantine records panics of a Box and whether the Box is in quarantine.
+type quarantine struct {
+	policy QuarantinePolicy
+
+	m          sync.Mutex
+	panics     []time.Time
+	numPanics  int64
+	numDropped int64
+	lastPanic  string
+	since      time.Time
+}
+
+func newQuarantine(p *QuarantinePolicy) *quarantine {
+	return &quarantine{
+		policy: *p,
+	}
+}
+
+// record records a panic and returns true when the Box is put into
+// quarantine by the panic.
+func (q *quarantine) record(err error) bool {
+	q.m.Lock()
+	defer q.m.Unlock()
+	now := time.Now()
+	q.numPanics++
+	q.lastPanic = err.Error()
+	q.panics = append(q.panics, now)
+	if q.policy.Window > 0 {
+		i := 0
+		for ; i < len(q.panics); i++ {
+			if now.Sub(q.panics[i]) <= q.policy.Window {
+				break
+			}
+		}
+		q.panics = q.panics[i:]
+	}
+	if len(q.panics) < q.policy.MaxPanics || !q.since.IsZero() {
+		return false
+	}
+	q.since = now
+	return true
+}
+
+// drop returns true and counts a dropped tuple when the Box is in
+// quarantine.
+func (q *quarantine) drop() bool {
+	q.m.Lock()
+	defer q.m.Unlock()
+	if q.since.IsZero() {
+		return false
+	}
+	q.numDropped++
+	return true
+}
+
+func (q *quarantine) release() error {
+	q.m.Lock()
+	defer q.m.Unlock()
+	if q.since.IsZero() {
+		return errors.New("the box isn't in quarantine")
+	}
+	q.since = time.Time{}
+	q.panics = nil
+	return nil
+}
+
+func (q *quarantine) status() data.Map {
+	q.m.Lock()
+	defer q.m.Unlock()
+	st := data.Map{
+		"quarantined": data.Bool(!q.since.IsZero()),
+		"num_panics":  data.Int(q.numPanics),
+		"num_dropped": data.Int(q.numDropped),
+		"max_panics":  data.Int(q.policy.MaxPanics),
+		"window":      data.Float(q.policy.Window.Seconds()),
+	}
+	if !q.since.IsZero() {
+		st["since"] = data.Timestamp(q.since)
+	}
+	if q.lastPanic != "" {
+		st["last_panic"] = data.String(q.lastPanic)
+	}
+	return st
+}
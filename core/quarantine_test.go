@@ -0,0 +1,232 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// seqPanicBox panics when it receives a tuple whose "seq" is less than or
+// equal to maxSeq.
+type seqPanicBox struct {
+	maxSeq int64
+}
+
+func (b *seqPanicBox) Process(ctx *Context, t *Tuple, w Writer) error {
+	seq, _ := data.AsInt(t.Data["seq"])
+	if seq <= b.maxSeq {
+		panic("panic box")
+	}
+	return w.Write(ctx, t)
+}
+
+func waitDropped(bn BoxNode, n int64) bool {
+	for i := 0; i < 1000; i++ {
+		q := bn.Status()["quarantine"].(data.Map)
+		if q["num_dropped"] == data.Int(n) {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestPanicError(t *testing.T) {
+	Convey("Given an error decorated by PanicError", t, func() {
+		err := PanicError(errors.New("test"))
+
+		Convey("Then it should be a panic error", func() {
+			So(IsPanicError(err), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "test")
+		})
+	})
+
+	Convey("Given a regular error", t, func() {
+		err := errors.New("test")
+
+		Convey("Then it shouldn't be a panic error", func() {
+			So(IsPanicError(err), ShouldBeFalse)
+		})
+	})
+}
+
+func TestQuarantinePolicy(t *testing.T) {
+	Convey("Given a quarantine with a window", t, func() {
+		q := newQuarantine(&QuarantinePolicy{
+			MaxPanics: 2,
+			Window:    10 * time.Millisecond,
+		})
+
+		Convey("When panics occur with a longer interval than the window", func() {
+			So(q.record(errors.New("1")), ShouldBeFalse)
+			time.Sleep(20 * time.Millisecond)
+			So(q.record(errors.New("2")), ShouldBeFalse)
+
+			Convey("Then it shouldn't be in quarantine", func() {
+				st := q.status()
+				So(st["quarantined"], ShouldEqual, data.False)
+				So(st["num_panics"], ShouldEqual, data.Int(2))
+				So(st["last_panic"], ShouldEqual, data.String("2"))
+			})
+		})
+
+		Convey("When panics occur within the window", func() {
+			So(q.record(errors.New("1")), ShouldBeFalse)
+			So(q.record(errors.New("2")), ShouldBeTrue)
+
+			Convey("Then it should be in quarantine", func() {
+				So(q.status()["quarantined"], ShouldEqual, data.True)
+				So(q.status(), ShouldContainKey, "since")
+				So(q.drop(), ShouldBeTrue)
+				So(q.status()["num_dropped"], ShouldEqual, data.Int(1))
+			})
+
+			Convey("Then it should be released", func() {
+				So(q.release(), ShouldBeNil)
+				So(q.status()["quarantined"], ShouldEqual, data.False)
+				So(q.drop(), ShouldBeFalse)
+
+				Convey("And releasing it again should fail", func() {
+					So(q.release(), ShouldNotBeNil)
+				})
+			})
+		})
+	})
+
+	Convey("Given an invalid policy", t, func() {
+		Convey("Then a non-positive number of panics should be rejected", func() {
+			So((&QuarantinePolicy{}).Validate(), ShouldNotBeNil)
+		})
+
+		Convey("Then a negative window should be rejected", func() {
+			So((&QuarantinePolicy{MaxPanics: 1, Window: -1}).Validate(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestBoxQuarantine(t *testing.T) {
+	Convey("Given a topology with a box having a quarantine policy", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "quarantine")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(freshTuples()), &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+
+		bn, err := t.AddBox("box", &seqPanicBox{maxSeq: 2}, &BoxConfig{
+			Quarantine: &QuarantinePolicy{
+				MaxPanics: 2,
+			},
+		})
+		So(err, ShouldBeNil)
+		So(bn.Input("source", &BoxInputConfig{
+			ErrorMode: DropOnError,
+		}), ShouldBeNil)
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("box", nil), ShouldBeNil)
+
+		Convey("When the box panics too many times", func() {
+			So(son.Resume(), ShouldBeNil)
+			So(waitDropped(bn, 6), ShouldBeTrue)
+
+			Convey("Then the box should keep running", func() {
+				So(bn.State().Get(), ShouldEqual, TSRunning)
+				q := bn.Status()["quarantine"].(data.Map)
+				So(q["quarantined"], ShouldEqual, data.True)
+				So(q["num_panics"], ShouldEqual, data.Int(2))
+				So(q["max_panics"], ShouldEqual, data.Int(2))
+			})
+
+			Convey("Then the box should drop the rest of tuples", func() {
+				So(si.len(), ShouldEqual, 0)
+			})
+
+			Convey("Then the box should be released", func() {
+				So(bn.ReleaseQuarantine(), ShouldBeNil)
+				So(bn.Status()["quarantine"].(data.Map)["quarantined"], ShouldEqual, data.False)
+			})
+
+			Convey("Then the box should be able to stop", func() {
+				So(bn.Stop(), ShouldBeNil)
+				So(bn.State().Get(), ShouldEqual, TSStopped)
+			})
+		})
+	})
+
+	Convey("Given a box writer adapter with a quarantine policy", t, func() {
+		ctx := NewContext(nil)
+		si := NewTupleCollectorSink()
+		wa := newBoxWriterAdapter(&seqPanicBox{maxSeq: 1}, "box", si)
+		wa.quarantine = newQuarantine(&QuarantinePolicy{MaxPanics: 1})
+		ts := freshTuples()
+
+		Convey("When the box panics", func() {
+			err := wa.Write(ctx, ts[0])
+
+			Convey("Then the panic should be returned as an error", func() {
+				So(IsPanicError(err), ShouldBeTrue)
+				So(IsFatalError(err), ShouldBeFalse)
+			})
+
+			Convey("Then the following tuple should be dropped", func() {
+				So(wa.Write(ctx, ts[1]), ShouldBeNil)
+				So(si.len(), ShouldEqual, 0)
+			})
+
+			Convey("Then the box should process tuples after it's released", func() {
+				So(wa.quarantine.release(), ShouldBeNil)
+				So(wa.Write(ctx, ts[1]), ShouldBeNil)
+				So(si.len(), ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given a topology with a box without a quarantine policy", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "quarantine")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		bn, err := t.AddBox("box", &seqPanicBox{}, nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then the status shouldn't have the quarantine", func() {
+			So(bn.Status(), ShouldNotContainKey, "quarantine")
+		})
+
+		Convey("Then it cannot be released", func() {
+			So(bn.ReleaseQuarantine(), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a topology", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "quarantine")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		Convey("When adding a box with an invalid quarantine policy", func() {
+			_, err := t.AddBox("box", &seqPanicBox{}, &BoxConfig{
+				Quarantine: &QuarantinePolicy{},
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
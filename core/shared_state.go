@@ -68,6 +68,24 @@ type LoadableSharedState interface {
 	Load(ctx *Context, r io.Reader, params data.Map) error
 }
 
+// KeyedState is a SharedState whose data can be looked up by a key. It is
+// mainly used for reference data such as a table of devices, and a stream
+// can be enriched with such data in BQL by a JOIN STATE clause:
+//
+//	SELECT RSTREAM s:id, d:name FROM s [RANGE 1 TUPLES]
+//	  JOIN STATE devices AS d ON s:id = d:key
+type KeyedState interface {
+	SharedState
+
+	// Lookup returns the data associated with the given key. It returns
+	// NotExistError if the state doesn't have the key. The caller must not
+	// modify the returned Map, so the state should return a copy if it
+	// might modify the Map later.
+	//
+	// Lookup and other methods can be called concurrently.
+	Lookup(ctx *Context, key data.Value) (data.Map, error)
+}
+
 // TODO: Add MixiableSharedState interface
 
 // SharedStateRegistry manages SharedState with names assigned to each state.
@@ -0,0 +1,362 @@
+package core
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// SinkOrdering is a policy of the order in which a sink receives tuples
+// when multiple inputs are connected to it.
+type SinkOrdering int
+
+const (
+	// ArrivalOrder writes tuples in the order of their arrival. This is the
+	// default policy and doesn't buffer any tuple.
+	ArrivalOrder SinkOrdering = iota
+
+	// TimestampOrder writes tuples in the order of their Timestamp. A tuple
+	// is buffered until all inputs have a buffered tuple or the buffer gets
+	// full, so tuples are approximately ordered when an input is idle or
+	// produces tuples out of order.
+	TimestampOrder
+
+	// RoundRobinOrder writes a tuple from each input in turn. A tuple is
+	// buffered until all inputs have a buffered tuple or the buffer gets
+	// full.
+	RoundRobinOrder
+)
+
+func (o SinkOrdering) String() string {
+	switch o {
+	case ArrivalOrder:
+		return "arrival"
+	case TimestampOrder:
+		return "timestamp"
+	case RoundRobinOrder:
+		return "round_robin"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultSinkOrderingBufferSize is the default number of tuples buffered
+// to order tuples.
+const defaultSinkOrderingBufferSize = 1024
+
+// orderingWriter buffers tuples written to a sink and writes them in the
+// order of a SinkOrdering. When the sink fails to write a tuple, the tuple
+// is reported as a dropped tuple instead of returning the error because
+// the tuple isn't the one received from the input and the ErrorMode of the
+// input cannot be applied to it.
+type orderingWriter struct {
+	w        Writer
+	nodeName string
+	ordering SinkOrdering
+	size     int
+
+	m       sync.Mutex
+	buf     orderingBuffer
+	last    time.Time
+	numLate int64
+}
+
+// orderingBuffer has tuples waiting to be written.
+type orderingBuffer interface {
+	// addInput registers an input connected to the sink. A tuple from an
+	// unregistered input registers the input implicitly.
+	addInput(name string)
+
+	// removeInput unregisters an input disconnected from the sink. Tuples
+	// from the input which are still buffered will be written.
+	removeInput(name string)
+
+	push(t *Tuple)
+
+	// pop returns the next tuple to be written. When force is false, it
+	// returns nil unless all registered inputs have a buffered tuple.
+	pop(force bool) *Tuple
+
+	len() int
+}
+
+func newOrderingWriter(w Writer, nodeName string, ordering SinkOrdering, size int) (*orderingWriter, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("the buffer size must not be negative: %v", size)
+	}
+	if size == 0 {
+		size = defaultSinkOrderingBufferSize
+	}
+
+	ow := &orderingWriter{
+		w:        w,
+		nodeName: nodeName,
+		ordering: ordering,
+		size:     size,
+	}
+	switch ordering {
+	case TimestampOrder:
+		ow.buf = &timestampBuffer{counts: map[string]int{}}
+	case RoundRobinOrder:
+		ow.buf = &roundRobinBuffer{
+			queues: map[string][]*Tuple{},
+			active: map[string]bool{},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sink ordering: %v", ordering)
+	}
+	return ow, nil
+}
+
+func (ow *orderingWriter) addInput(name string) {
+	ow.m.Lock()
+	defer ow.m.Unlock()
+	ow.buf.addInput(name)
+}
+
+// inputClosed is called by dataSources.pour when an input is disconnected.
+// Tuples which have been waiting for a tuple from the input are written.
+func (ow *orderingWriter) inputClosed(ctx *Context, name string) error {
+	ow.m.Lock()
+	ow.buf.removeInput(name)
+	ow.m.Unlock()
+	return ow.writeReady(ctx)
+}
+
+func (ow *orderingWriter) Write(ctx *Context, t *Tuple) error {
+	ow.m.Lock()
+	if ow.ordering == TimestampOrder && t.Timestamp.Before(ow.last) {
+		ow.numLate++
+	}
+	ow.buf.push(t)
+	ow.m.Unlock()
+	return ow.writeReady(ctx)
+}
+
+// writeReady writes tuples which can be written according to the policy.
+func (ow *orderingWriter) writeReady(ctx *Context) error {
+	for {
+		ow.m.Lock()
+		next := ow.buf.pop(ow.buf.len() > ow.size)
+		if next != nil && next.Timestamp.After(ow.last) {
+			ow.last = next.Timestamp
+		}
+		ow.m.Unlock()
+		if next == nil {
+			return nil
+		}
+		if err := ow.write(ctx, next); err != nil {
+			return err
+		}
+	}
+}
+
+// flush writes all buffered tuples. It's called when the sink stops.
+func (ow *orderingWriter) flush(ctx *Context) error {
+	for {
+		ow.m.Lock()
+		next := ow.buf.pop(true)
+		ow.m.Unlock()
+		if next == nil {
+			return nil
+		}
+		if err := ow.write(ctx, next); err != nil {
+			return err
+		}
+	}
+}
+
+func (ow *orderingWriter) write(ctx *Context, t *Tuple) error {
+	err := ow.w.Write(ctx, t)
+	if err == nil || IsFatalError(err) {
+		return err
+	}
+	ctx.droppedTuple(t, NTSink, ow.nodeName, ETInput, err)
+	return nil
+}
+
+func (ow *orderingWriter) status() data.Map {
+	ow.m.Lock()
+	defer ow.m.Unlock()
+	st := data.Map{
+		"policy":       data.String(ow.ordering.String()),
+		"buffer_size":  data.Int(ow.size),
+		"num_buffered": data.Int(ow.buf.len()),
+	}
+	if ow.ordering == TimestampOrder {
+		st["num_late"] = data.Int(ow.numLate)
+	}
+	return st
+}
+
+// timestampBuffer is a priority queue of tuples ordered by their Timestamp.
+// Tuples having the same timestamp are popped in the order of their arrival.
+type timestampBuffer struct {
+	h      timestampHeap
+	seq    int64
+	counts map[string]int
+}
+
+func (b *timestampBuffer) addInput(name string) {
+	if _, ok := b.counts[name]; !ok {
+		b.counts[name] = 0
+	}
+}
+
+func (b *timestampBuffer) removeInput(name string) {
+	delete(b.counts, name)
+}
+
+func (b *timestampBuffer) push(t *Tuple) {
+	b.seq++
+	heap.Push(&b.h, timestampHeapItem{t, b.seq})
+	b.counts[t.InputName]++
+}
+
+func (b *timestampBuffer) pop(force bool) *Tuple {
+	if len(b.h) == 0 {
+		return nil
+	}
+	if !force {
+		for _, c := range b.counts {
+			if c == 0 {
+				return nil
+			}
+		}
+	}
+	t := heap.Pop(&b.h).(timestampHeapItem).t
+	if _, ok := b.counts[t.InputName]; ok {
+		b.counts[t.InputName]--
+	}
+	return t
+}
+
+func (b *timestampBuffer) len() int {
+	return len(b.h)
+}
+
+type timestampHeapItem struct {
+	t   *Tuple
+	seq int64
+}
+
+type timestampHeap []timestampHeapItem
+
+func (h timestampHeap) Len() int { return len(h) }
+
+func (h timestampHeap) Less(i, j int) bool {
+	if h[i].t.Timestamp.Equal(h[j].t.Timestamp) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].t.Timestamp.Before(h[j].t.Timestamp)
+}
+
+func (h timestampHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *timestampHeap) Push(x interface{}) {
+	*h = append(*h, x.(timestampHeapItem))
+}
+
+func (h *timestampHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = timestampHeapItem{}
+	*h = old[:n-1]
+	return x
+}
+
+// roundRobinBuffer has a queue for each input and pops tuples from them in
+// turn.
+type roundRobinBuffer struct {
+	queues map[string][]*Tuple
+	active map[string]bool
+
+	// inputs has the names of inputs in the order of their registration and
+	// next is the index of the input whose tuple is popped next. An input
+	// removed by removeInput stays in inputs until its queue gets empty.
+	inputs []string
+	next   int
+	n      int
+}
+
+func (b *roundRobinBuffer) addInput(name string) {
+	b.active[name] = true
+	if _, ok := b.queues[name]; !ok {
+		b.queues[name] = nil
+		b.inputs = append(b.inputs, name)
+	}
+}
+
+func (b *roundRobinBuffer) removeInput(name string) {
+	delete(b.active, name)
+	b.cleanUp(name)
+}
+
+func (b *roundRobinBuffer) push(t *Tuple) {
+	b.addInput(t.InputName)
+	b.queues[t.InputName] = append(b.queues[t.InputName], t)
+	b.n++
+}
+
+func (b *roundRobinBuffer) pop(force bool) *Tuple {
+	if b.n == 0 {
+		return nil
+	}
+	if !force {
+		for name := range b.active {
+			if len(b.queues[name]) == 0 {
+				return nil
+			}
+		}
+	}
+
+	for i := 0; i < len(b.inputs); i++ {
+		idx := (b.next + i) % len(b.inputs)
+		name := b.inputs[idx]
+		q := b.queues[name]
+		if len(q) == 0 {
+			continue
+		}
+		t := q[0]
+		q[0] = nil
+		b.queues[name] = q[1:]
+		b.next = (idx + 1) % len(b.inputs)
+		b.n--
+		b.cleanUp(name)
+		return t
+	}
+	return nil
+}
+
+// cleanUp removes the input from the rotation if it has been removed and
+// doesn't have any buffered tuple.
+func (b *roundRobinBuffer) cleanUp(name string) {
+	if b.active[name] || len(b.queues[name]) > 0 {
+		return
+	}
+	if _, ok := b.queues[name]; !ok {
+		return
+	}
+	delete(b.queues, name)
+	for i, n := range b.inputs {
+		if n != name {
+			continue
+		}
+		b.inputs = append(b.inputs[:i], b.inputs[i+1:]...)
+		if b.next > i {
+			b.next--
+		}
+		if b.next >= len(b.inputs) {
+			b.next = 0
+		}
+		break
+	}
+}
+
+func (b *roundRobinBuffer) len() int {
+	return b.n
+}
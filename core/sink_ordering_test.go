@@ -0,0 +1,207 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func orderingTestTuple(input string, sec int64) *Tuple {
+	return &Tuple{
+		InputName: input,
+		Timestamp: time.Unix(sec, 0),
+		Data: data.Map{
+			"input": data.String(input),
+			"sec":   data.Int(sec),
+		},
+	}
+}
+
+func orderingTestSeconds(ts []*Tuple) []int64 {
+	res := make([]int64, len(ts))
+	for i, t := range ts {
+		res[i] = t.Timestamp.Unix()
+	}
+	return res
+}
+
+func TestOrderingWriter(t *testing.T) {
+	ctx := NewContext(nil)
+
+	Convey("Given an ordering writer with timestamp order", t, func() {
+		si := NewTupleCollectorSink()
+		ow, err := newOrderingWriter(si, "sink", TimestampOrder, 3)
+		So(err, ShouldBeNil)
+		ow.addInput("a")
+		ow.addInput("b")
+
+		Convey("When tuples are written from two inputs", func() {
+			So(ow.Write(ctx, orderingTestTuple("a", 1)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("a", 4)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("b", 2)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("b", 3)), ShouldBeNil)
+
+			Convey("Then tuples should be written in the order of timestamps", func() {
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1, 2, 3})
+				So(ow.status()["num_buffered"], ShouldEqual, data.Int(1))
+			})
+
+			Convey("Then flush should write the rest of tuples", func() {
+				So(ow.flush(ctx), ShouldBeNil)
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1, 2, 3, 4})
+				So(ow.status()["num_buffered"], ShouldEqual, data.Int(0))
+			})
+		})
+
+		Convey("When one of the inputs is idle", func() {
+			for _, s := range []int64{5, 2, 3} {
+				So(ow.Write(ctx, orderingTestTuple("a", s)), ShouldBeNil)
+			}
+
+			Convey("Then tuples should be buffered", func() {
+				So(si.Tuples, ShouldBeEmpty)
+			})
+
+			Convey("Then the oldest tuple should be written when the buffer gets full", func() {
+				So(ow.Write(ctx, orderingTestTuple("a", 1)), ShouldBeNil)
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1})
+
+				Convey("And a tuple older than written ones should be counted as late", func() {
+					So(ow.Write(ctx, orderingTestTuple("b", 0)), ShouldBeNil)
+					So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1, 0})
+					So(ow.status()["num_late"], ShouldEqual, data.Int(1))
+				})
+			})
+
+			Convey("Then tuples should be written when the idle input is disconnected", func() {
+				So(ow.inputClosed(ctx, "b"), ShouldBeNil)
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{2, 3, 5})
+			})
+		})
+	})
+
+	Convey("Given an ordering writer with round robin order", t, func() {
+		si := NewTupleCollectorSink()
+		ow, err := newOrderingWriter(si, "sink", RoundRobinOrder, 4)
+		So(err, ShouldBeNil)
+		ow.addInput("a")
+		ow.addInput("b")
+
+		Convey("When tuples are written from two inputs", func() {
+			So(ow.Write(ctx, orderingTestTuple("a", 1)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("a", 2)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("a", 3)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("b", 10)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("b", 11)), ShouldBeNil)
+			So(ow.flush(ctx), ShouldBeNil)
+
+			Convey("Then tuples should be written from each input in turn", func() {
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1, 10, 2, 11, 3})
+			})
+		})
+
+		Convey("When an input is disconnected while its tuples are buffered", func() {
+			So(ow.Write(ctx, orderingTestTuple("a", 1)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("a", 2)), ShouldBeNil)
+			So(ow.inputClosed(ctx, "b"), ShouldBeNil)
+
+			Convey("Then the buffered tuples should be written", func() {
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1, 2})
+			})
+
+			Convey("Then the input shouldn't block other inputs", func() {
+				So(ow.Write(ctx, orderingTestTuple("a", 3)), ShouldBeNil)
+				So(orderingTestSeconds(si.Tuples), ShouldResemble, []int64{1, 2, 3})
+				So(ow.status()["num_buffered"], ShouldEqual, data.Int(0))
+			})
+		})
+	})
+
+	Convey("Given an ordering writer whose sink fails", t, func() {
+		ow, err := newOrderingWriter(WriterFunc(func(ctx *Context, t *Tuple) error {
+			return errors.New("failure")
+		}), "sink", TimestampOrder, 1)
+		So(err, ShouldBeNil)
+
+		Convey("When tuples are written", func() {
+			So(ow.Write(ctx, orderingTestTuple("a", 1)), ShouldBeNil)
+			So(ow.Write(ctx, orderingTestTuple("a", 2)), ShouldBeNil)
+
+			Convey("Then the error should be ignored", func() {
+				So(ow.flush(ctx), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given an invalid buffer size", t, func() {
+		_, err := newOrderingWriter(NewTupleCollectorSink(), "sink", TimestampOrder, -1)
+
+		Convey("Then the writer shouldn't be created", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSinkOrdering(t *testing.T) {
+	Convey("Given a topology with a sink having an ordering policy", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "sink_ordering")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(freshTuples()), &SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, &SinkConfig{
+			Ordering:           TimestampOrder,
+			OrderingBufferSize: 100,
+		})
+		So(err, ShouldBeNil)
+		So(sin.Input("source", nil), ShouldBeNil)
+
+		Convey("When the source generates tuples", func() {
+			So(son.Resume(), ShouldBeNil)
+			son.State().Wait(TSStopped)
+
+			Convey("Then the sink should receive all tuples", func() {
+				si.Wait(8)
+				So(si.len(), ShouldEqual, 8)
+				So(si.get(0).InputName, ShouldEqual, "source")
+			})
+
+			Convey("Then the status should have the ordering policy", func() {
+				st := sin.Status()["ordering"].(data.Map)
+				So(st["policy"], ShouldEqual, data.String("timestamp"))
+				So(st["buffer_size"], ShouldEqual, data.Int(100))
+				So(st["num_buffered"], ShouldEqual, data.Int(0))
+			})
+		})
+	})
+
+	Convey("Given a topology", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "sink_ordering")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		Convey("When adding a sink with an unknown ordering policy", func() {
+			_, err := t.AddSink("sink", NewTupleCollectorSink(), &SinkConfig{
+				Ordering: SinkOrdering(100),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,695 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// SinkWALConfig has parameters of a write-ahead log (WAL) of a sink. When a
+// sink has a WAL, tuples are appended to the log on the local filesystem
+// before they're written to the sink, and they're removed from the log once
+// the sink accepts them. A tuple which the sink failed to write is retried
+// until it succeeds, so an outage of the destination of the sink doesn't lose
+// tuples. Tuples remaining in the log when the sink stops are written when a
+// sink having the same Dir is added again, e.g. after a restart of the
+// server.
+//
+// Tuples are delivered at least once: a tuple can be written to the sink
+// twice when the server crashes right after the sink accepted the tuple.
+// Because tuples are encoded in msgpack, timestamps in a tuple's data are
+// restored as integers having seconds since the epoch. The timestamps of
+// the tuple itself are kept as they are.
+type SinkWALConfig struct {
+	// Dir is the directory where the log is written. It must not be shared
+	// with other sinks. It's created when it doesn't exist.
+	Dir string
+
+	// SegmentSize is the maximum size of each file of the log in bytes. A file
+	// is removed when all tuples in it are written to the sink. The default
+	// size is used when it's 0.
+	SegmentSize int64
+
+	// MaxRetryInterval is the maximum interval between retries of a tuple
+	// which the sink failed to write. The interval starts from 1 second and
+	// doubles on each failure. The default interval is used when it's 0.
+	MaxRetryInterval time.Duration
+}
+
+const (
+	defaultSinkWALSegmentSize      = 64 * 1024 * 1024
+	defaultSinkWALMaxRetryInterval = time.Minute
+	sinkWALRecordHeaderSize        = 8
+	sinkWALPositionFile            = "position"
+)
+
+// Validate validates parameters of the WAL.
+func (c *SinkWALConfig) Validate() error {
+	if c.Dir == "" {
+		return errors.New("the directory of the WAL must be specified")
+	}
+	if c.SegmentSize < 0 {
+		return fmt.Errorf("the segment size of the WAL must not be negative: %v", c.SegmentSize)
+	}
+	if c.MaxRetryInterval < 0 {
+		return fmt.Errorf("the max retry interval of the WAL must not be negative: %v", c.MaxRetryInterval)
+	}
+	return nil
+}
+
+func (c *SinkWALConfig) segmentSize() int64 {
+	if c.SegmentSize == 0 {
+		return defaultSinkWALSegmentSize
+	}
+	return c.SegmentSize
+}
+
+func (c *SinkWALConfig) maxRetryInterval() time.Duration {
+	if c.MaxRetryInterval == 0 {
+		return defaultSinkWALMaxRetryInterval
+	}
+	return c.MaxRetryInterval
+}
+
+// sinkWAL is a Writer which appends tuples to a log and writes them to the
+// underlying Writer in a separate goroutine.
+//
+// The log consists of segment files named "<seq>.wal". Each record in a
+// segment has a 4 byte big endian length and a 4 byte CRC32 checksum of the
+// payload followed by the payload. The position of the next record to be
+// delivered is saved in the "position" file.
+type sinkWAL struct {
+	config   SinkWALConfig
+	sinkName string
+	w        Writer
+
+	m       sync.Mutex
+	started bool
+	closed  bool
+
+	// wseg and wfile are the segment to which records are appended. wsize
+	// is the size of wfile.
+	wseg  int64
+	wfile *os.File
+	wsize int64
+
+	// rseg and roff is the position of the next record to be delivered.
+	// They're only modified by the delivering goroutine.
+	rseg int64
+	roff int64
+
+	numPending      int64
+	numPendingBytes int64
+	numAppended     int64
+	numDelivered    int64
+	numFailures     int64
+	numDropped      int64
+	lastErr         error
+
+	// oldest is the time when the oldest pending record was appended.
+	oldest time.Time
+
+	appended chan struct{}
+	replayCh chan struct{}
+	closeCh  chan struct{}
+	done     chan struct{}
+}
+
+// newSinkWAL opens the WAL in the directory. It doesn't start delivering
+// tuples until start is called.
+func newSinkWAL(config *SinkWALConfig, sinkName string, w Writer) (*sinkWAL, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(config.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create the directory of the WAL: %v", err)
+	}
+
+	s := &sinkWAL{
+		config:   *config,
+		sinkName: sinkName,
+		w:        w,
+		appended: make(chan struct{}, 1),
+		replayCh: make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := s.open(); err != nil {
+		if s.wfile != nil {
+			s.wfile.Close()
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// open reads the position and existing segments and prepares the segment to
+// which records are appended. A broken record, which is usually written
+// partially due to a crash, and records following it are truncated.
+func (s *sinkWAL) open() error {
+	segs, err := s.segments()
+	if err != nil {
+		return err
+	}
+	if err := s.loadPosition(); err != nil {
+		return err
+	}
+	if len(segs) > 0 && s.rseg < segs[0] {
+		s.rseg, s.roff = segs[0], 0
+	}
+
+	found := false
+	for _, seg := range segs {
+		if seg == s.rseg {
+			found = true
+		}
+		if seg < s.rseg {
+			if err := os.Remove(s.segmentPath(seg)); err != nil {
+				return err
+			}
+			continue
+		}
+		off := int64(0)
+		if seg == s.rseg {
+			off = s.roff
+		}
+		if err := s.scanSegment(seg, off); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		s.roff = 0
+	}
+
+	s.wseg = s.rseg
+	if len(segs) > 0 && segs[len(segs)-1] > s.wseg {
+		s.wseg = segs[len(segs)-1]
+	}
+	f, err := os.OpenFile(s.segmentPath(s.wseg), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	s.wfile = f
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	s.wsize = st.Size()
+	return nil
+}
+
+// segments returns sequence numbers of existing segments in ascending order.
+func (s *sinkWAL) segments() ([]int64, error) {
+	fs, err := ioutil.ReadDir(s.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var segs []int64
+	for _, f := range fs {
+		if !strings.HasSuffix(f.Name(), ".wal") {
+			continue
+		}
+		seg, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), ".wal"), 10, 64)
+		if err != nil {
+			continue
+		}
+		segs = append(segs, seg)
+	}
+	sort.Sort(int64Slice(segs))
+	return segs, nil
+}
+
+type int64Slice []int64
+
+func (s int64Slice) Len() int           { return len(s) }
+func (s int64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// scanSegment counts pending records in the segment starting from off.
+func (s *sinkWAL) scanSegment(seg, off int64) error {
+	f, err := os.OpenFile(s.segmentPath(seg), os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if off > st.Size() {
+		// The segment was truncated after the position was saved.
+		off = st.Size()
+		s.roff = off
+	}
+
+	for {
+		payload, err := readSinkWALRecord(f, off)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			// The rest of the segment is discarded.
+			return f.Truncate(off)
+		}
+		if s.numPending == 0 {
+			if t, err := decodeSinkWALRecord(payload); err == nil {
+				s.oldest = t.appendedAt
+			}
+		}
+		s.numPending++
+		s.numPendingBytes += int64(sinkWALRecordHeaderSize + len(payload))
+		off += int64(sinkWALRecordHeaderSize + len(payload))
+	}
+}
+
+type sinkWALPosition struct {
+	Segment int64 `json:"segment"`
+	Offset  int64 `json:"offset"`
+}
+
+func (s *sinkWAL) loadPosition() error {
+	b, err := ioutil.ReadFile(filepath.Join(s.config.Dir, sinkWALPositionFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var p sinkWALPosition
+	if err := json.Unmarshal(b, &p); err != nil {
+		return fmt.Errorf("the position file of the WAL is broken: %v", err)
+	}
+	s.rseg, s.roff = p.Segment, p.Offset
+	return nil
+}
+
+// savePosition saves the current position atomically.
+func (s *sinkWAL) savePosition() error {
+	b, err := json.Marshal(&sinkWALPosition{
+		Segment: s.rseg,
+		Offset:  s.roff,
+	})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.config.Dir, sinkWALPositionFile)
+	if err := ioutil.WriteFile(path+".tmp", b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (s *sinkWAL) segmentPath(seg int64) string {
+	return filepath.Join(s.config.Dir, fmt.Sprintf("%016d.wal", seg))
+}
+
+// Write appends the tuple to the log. The tuple is written to the underlying
+// Writer asynchronously.
+func (s *sinkWAL) Write(ctx *Context, t *Tuple) error {
+	payload, err := encodeSinkWALRecord(t, time.Now())
+	if err != nil {
+		return err
+	}
+	rec := make([]byte, sinkWALRecordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(rec[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(payload))
+	copy(rec[sinkWALRecordHeaderSize:], payload)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.closed {
+		return errors.New("the WAL is already closed")
+	}
+	if s.wsize > 0 && s.wsize+int64(len(rec)) > s.config.segmentSize() {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.wfile.Write(rec); err != nil {
+		// Truncate the partially written record so that following records
+		// can be read.
+		s.wfile.Truncate(s.wsize)
+		return fmt.Errorf("cannot append a tuple to the WAL: %v", err)
+	}
+	s.wsize += int64(len(rec))
+	if s.numPending == 0 {
+		s.oldest = time.Now()
+	}
+	s.numPending++
+	s.numPendingBytes += int64(len(rec))
+	s.numAppended++
+
+	select {
+	case s.appended <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// rotate starts a new segment. The caller must hold s.m.
+func (s *sinkWAL) rotate() error {
+	f, err := os.OpenFile(s.segmentPath(s.wseg+1), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot create a new segment of the WAL: %v", err)
+	}
+	s.wfile.Close()
+	s.wseg++
+	s.wfile = f
+	s.wsize = 0
+	return nil
+}
+
+// start starts a goroutine delivering tuples in the log.
+func (s *sinkWAL) start(ctx *Context) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.started || s.closed {
+		return
+	}
+	s.started = true
+	go s.deliver(ctx)
+}
+
+func (s *sinkWAL) deliver(ctx *Context) {
+	defer close(s.done)
+
+	var rfile *os.File
+	defer func() {
+		if rfile != nil {
+			rfile.Close()
+		}
+		s.m.Lock()
+		if err := s.savePosition(); err != nil {
+			ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+				Error("Cannot save the position of the WAL")
+		}
+		s.m.Unlock()
+	}()
+
+	lastSaved := time.Now()
+	for {
+		s.m.Lock()
+		pending := s.numPending
+		wseg := s.wseg
+		s.m.Unlock()
+
+		if pending == 0 {
+			select {
+			case <-s.appended:
+				continue
+			case <-s.closeCh:
+				return
+			}
+		}
+
+		if rfile == nil {
+			f, err := os.Open(s.segmentPath(s.rseg))
+			if err != nil {
+				ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+					Error("Cannot open a segment of the WAL")
+				return
+			}
+			rfile = f
+		}
+
+		payload, err := readSinkWALRecord(rfile, s.roff)
+		if err == io.EOF && s.rseg < wseg {
+			// Move to the next segment. The position is saved before the
+			// segment is removed so that removed records aren't delivered
+			// again.
+			rfile.Close()
+			rfile = nil
+			old := s.rseg
+			s.m.Lock()
+			s.rseg, s.roff = s.rseg+1, 0
+			err := s.savePosition()
+			s.m.Unlock()
+			if err != nil {
+				ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+					Error("Cannot save the position of the WAL")
+				return
+			}
+			os.Remove(s.segmentPath(old))
+			lastSaved = time.Now()
+			continue
+		} else if err != nil {
+			ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+				Error("Cannot read a tuple from the WAL")
+			return
+		}
+
+		r, err := decodeSinkWALRecord(payload)
+		if err == nil {
+			s.m.Lock()
+			s.oldest = r.appendedAt
+			s.m.Unlock()
+			if !s.deliverTuple(ctx, r.tuple) {
+				return
+			}
+		} else {
+			ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+				Error("Cannot decode a tuple in the WAL")
+			s.m.Lock()
+			s.numDropped++
+			s.m.Unlock()
+		}
+
+		s.m.Lock()
+		s.roff += int64(sinkWALRecordHeaderSize + len(payload))
+		s.numPending--
+		s.numPendingBytes -= int64(sinkWALRecordHeaderSize + len(payload))
+		if s.numPending == 0 || time.Since(lastSaved) > time.Second {
+			if err := s.savePosition(); err != nil {
+				ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+					Error("Cannot save the position of the WAL")
+			}
+			lastSaved = time.Now()
+		}
+		s.m.Unlock()
+	}
+}
+
+// deliverTuple writes the tuple to the underlying Writer until it succeeds.
+// It returns false when the WAL is closed before the tuple is written.
+func (s *sinkWAL) deliverTuple(ctx *Context, t *Tuple) bool {
+	interval := time.Second
+	for {
+		err := s.w.Write(ctx, t)
+		if err == nil {
+			s.m.Lock()
+			s.numDelivered++
+			s.m.Unlock()
+			return true
+		}
+		if IsFatalError(err) {
+			ctx.droppedTuple(t, NTSink, s.sinkName, ETInput, err)
+			ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+				Error("The sink failed to write a tuple in the WAL with a fatal error and it was dropped")
+			s.m.Lock()
+			s.numDropped++
+			s.lastErr = err
+			s.m.Unlock()
+			return true
+		}
+
+		s.m.Lock()
+		s.numFailures++
+		s.lastErr = err
+		s.m.Unlock()
+
+		// When the WAL is being closed, the tuple is left in the log so
+		// that the sink can stop without waiting for the recovery of its
+		// destination.
+		select {
+		case <-s.closeCh:
+			return false
+		default:
+		}
+		ctx.ErrLog(err).WithFields(ctx.nodeLogFields(NTSink, s.sinkName)).
+			WithField("retry_interval", interval.String()).
+			Warn("The sink failed to write a tuple in the WAL and it'll be retried")
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-s.replayCh:
+			timer.Stop()
+			interval = time.Second
+			continue
+		case <-s.closeCh:
+			timer.Stop()
+			return false
+		}
+		interval *= 2
+		if max := s.config.maxRetryInterval(); interval > max {
+			interval = max
+		}
+	}
+}
+
+// replay makes the WAL retry writing pending tuples immediately without
+// waiting for the next retry.
+func (s *sinkWAL) replay() {
+	select {
+	case s.replayCh <- struct{}{}:
+	default:
+	}
+}
+
+// close stops appending tuples and waits until all pending tuples are
+// written to the underlying Writer. When the Writer fails, it stops waiting
+// and pending tuples are left in the log.
+func (s *sinkWAL) close() error {
+	s.m.Lock()
+	if s.closed {
+		s.m.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.closeCh)
+	if !s.started {
+		close(s.done)
+	}
+	s.m.Unlock()
+
+	<-s.done
+	return s.wfile.Close()
+}
+
+// status returns the status of the WAL.
+func (s *sinkWAL) status() data.Map {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	lag := 0.0
+	if s.numPending > 0 {
+		lag = time.Since(s.oldest).Seconds()
+	}
+	m := data.Map{
+		"dir":           data.String(s.config.Dir),
+		"num_pending":   data.Int(s.numPending),
+		"pending_bytes": data.Int(s.numPendingBytes),
+		"num_appended":  data.Int(s.numAppended),
+		"num_delivered": data.Int(s.numDelivered),
+		"num_failures":  data.Int(s.numFailures),
+		"num_dropped":   data.Int(s.numDropped),
+		"lag":           data.Float(lag),
+	}
+	if s.lastErr != nil {
+		m["last_error"] = data.String(s.lastErr.Error())
+	}
+	return m
+}
+
+// readSinkWALRecord reads the payload of a record at the offset. It returns
+// io.EOF when there's no record at the offset.
+func readSinkWALRecord(f *os.File, off int64) ([]byte, error) {
+	var h [sinkWALRecordHeaderSize]byte
+	if n, err := f.ReadAt(h[:], off); err != nil {
+		if err == io.EOF && n == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("the header of a record is broken: %v", err)
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(h[0:4]))
+	if _, err := f.ReadAt(payload, off+sinkWALRecordHeaderSize); err != nil {
+		return nil, fmt.Errorf("the payload of a record is broken: %v", err)
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(h[4:8]) {
+		return nil, errors.New("the checksum of a record doesn't match")
+	}
+	return payload, nil
+}
+
+type sinkWALRecord struct {
+	tuple      *Tuple
+	appendedAt time.Time
+}
+
+func encodeSinkWALRecord(t *Tuple, appendedAt time.Time) ([]byte, error) {
+	m := data.Map{
+		"data":           t.Data,
+		"timestamp":      data.Int(sinkWALTimeToInt(t.Timestamp)),
+		"proc_timestamp": data.Int(sinkWALTimeToInt(t.ProcTimestamp)),
+		"batch_id":       data.Int(t.BatchID),
+		"appended_at":    data.Int(appendedAt.UnixNano()),
+	}
+	if t.Metadata != nil {
+		m["metadata"] = t.Metadata
+	}
+	return data.MarshalMsgpack(m)
+}
+
+func decodeSinkWALRecord(b []byte) (*sinkWALRecord, error) {
+	m, err := data.UnmarshalMsgpack(b)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := m["data"]
+	if !ok {
+		return nil, errors.New("a tuple in the WAL doesn't have data")
+	}
+	d, err := data.AsMap(v)
+	if err != nil {
+		return nil, fmt.Errorf("the data of a tuple in the WAL is broken: %v", err)
+	}
+	getInt := func(key string) int64 {
+		v, ok := m[key]
+		if !ok {
+			return 0
+		}
+		i, _ := data.AsInt(v)
+		return i
+	}
+	ts := getInt("timestamp")
+	procTS := getInt("proc_timestamp")
+	batchID := getInt("batch_id")
+	appendedAt := getInt("appended_at")
+	var meta data.Map
+	if v, ok := m["metadata"]; ok {
+		meta, err = data.AsMap(v)
+		if err != nil {
+			return nil, fmt.Errorf("the metadata of a tuple in the WAL is broken: %v", err)
+		}
+	}
+	return &sinkWALRecord{
+		tuple: &Tuple{
+			Data:          d,
+			Metadata:      meta,
+			Timestamp:     sinkWALIntToTime(ts),
+			ProcTimestamp: sinkWALIntToTime(procTS),
+			BatchID:       batchID,
+		},
+		appendedAt: time.Unix(0, appendedAt),
+	}, nil
+}
+
+// sinkWALTimeToInt converts a time to nanoseconds since the epoch. A zero
+// time is converted to 0 so that it's restored as a zero time.
+func sinkWALTimeToInt(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func sinkWALIntToTime(i int64) time.Time {
+	if i == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, i)
+}
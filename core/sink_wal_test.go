@@ -0,0 +1,318 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// switchableFailSink fails to write tuples while fail is true.
+type switchableFailSink struct {
+	*TupleCollectorSink
+	m        sync.Mutex
+	fail     bool
+	fatal    bool
+	attempts int
+}
+
+func newSwitchableFailSink() *switchableFailSink {
+	return &switchableFailSink{
+		TupleCollectorSink: NewTupleCollectorSink(),
+	}
+}
+
+func (s *switchableFailSink) Write(ctx *Context, t *Tuple) error {
+	s.m.Lock()
+	s.attempts++
+	fail, fatal := s.fail, s.fatal
+	s.m.Unlock()
+	if fatal {
+		return FatalError(errors.New("fatal failure"))
+	}
+	if fail {
+		return errors.New("the destination is down")
+	}
+	return s.TupleCollectorSink.Write(ctx, t)
+}
+
+func (s *switchableFailSink) setFail(fail bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.fail = fail
+}
+
+func (s *switchableFailSink) numAttempts() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.attempts
+}
+
+func sinkWALSeqs(ts []*Tuple) []int64 {
+	res := make([]int64, len(ts))
+	for i, t := range ts {
+		res[i], _ = data.AsInt(t.Data["seq"])
+	}
+	return res
+}
+
+func waitSinkWALStatus(w *sinkWAL, key string, v data.Value) {
+	for i := 0; i < 500; i++ {
+		if w.status()[key] == v {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSinkWAL(t *testing.T) {
+	ctx := NewContext(nil)
+
+	Convey("Given a sink with a WAL", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_sink_wal_test")
+		So(err, ShouldBeNil)
+
+		si := newSwitchableFailSink()
+		conf := &SinkWALConfig{
+			Dir:              dir,
+			SegmentSize:      100,
+			MaxRetryInterval: time.Hour,
+		}
+		w, err := newSinkWAL(conf, "sink", si)
+		So(err, ShouldBeNil)
+		w.start(ctx)
+		Reset(func() {
+			w.close()
+			os.RemoveAll(dir)
+		})
+
+		Convey("When writing tuples", func() {
+			ts := freshTuples()
+			ts[0].Timestamp = time.Date(2015, time.May, 1, 14, 27, 0, 123, time.UTC)
+			for _, t := range ts {
+				So(w.Write(ctx, t), ShouldBeNil)
+			}
+			si.Wait(len(ts))
+
+			Convey("Then they should be written to the sink in order", func() {
+				So(sinkWALSeqs(si.Tuples), ShouldResemble, []int64{1, 2, 3, 4, 5, 6, 7, 8})
+				So(si.Tuples[0].Timestamp.Equal(ts[0].Timestamp), ShouldBeTrue)
+				So(si.Tuples[1].Timestamp.IsZero(), ShouldBeTrue)
+			})
+
+			Convey("Then the status should have the number of delivered tuples", func() {
+				waitSinkWALStatus(w, "num_pending", data.Int(0))
+				st := w.status()
+				So(st["num_appended"], ShouldEqual, data.Int(8))
+				So(st["num_delivered"], ShouldEqual, data.Int(8))
+				So(st["num_pending"], ShouldEqual, data.Int(0))
+				So(st["lag"], ShouldEqual, data.Float(0))
+			})
+
+			Convey("Then delivered segments should be removed", func() {
+				So(w.close(), ShouldBeNil)
+				segs, err := w.segments()
+				So(err, ShouldBeNil)
+				So(len(segs), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the sink fails", func() {
+			si.setFail(true)
+			ts := freshTuples()
+			for _, t := range ts[:3] {
+				So(w.Write(ctx, t), ShouldBeNil)
+			}
+			for si.numAttempts() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then tuples should be pending", func() {
+				st := w.status()
+				So(st["num_pending"], ShouldEqual, data.Int(3))
+				So(st["num_failures"], ShouldBeGreaterThan, 0)
+				So(st["last_error"], ShouldEqual, data.String("the destination is down"))
+				So(si.len(), ShouldEqual, 0)
+			})
+
+			Convey("And the sink recovers and tuples are replayed", func() {
+				si.setFail(false)
+				w.replay()
+				si.Wait(3)
+
+				Convey("Then they should be written to the sink", func() {
+					So(sinkWALSeqs(si.Tuples), ShouldResemble, []int64{1, 2, 3})
+				})
+			})
+
+			Convey("And the WAL is reopened after the sink recovers", func() {
+				So(w.close(), ShouldBeNil)
+				So(si.len(), ShouldEqual, 0)
+
+				si2 := NewTupleCollectorSink()
+				w2, err := newSinkWAL(conf, "sink", si2)
+				So(err, ShouldBeNil)
+				So(w2.status()["num_pending"], ShouldEqual, data.Int(3))
+				w2.start(ctx)
+				Reset(func() {
+					w2.close()
+				})
+				So(w2.Write(ctx, ts[3]), ShouldBeNil)
+				si2.Wait(4)
+
+				Convey("Then pending tuples should be written before new ones", func() {
+					So(sinkWALSeqs(si2.Tuples), ShouldResemble, []int64{1, 2, 3, 4})
+				})
+			})
+		})
+
+		Convey("When the sink returns a fatal error", func() {
+			si.m.Lock()
+			si.fatal = true
+			si.m.Unlock()
+			So(w.Write(ctx, freshTuples()[0]), ShouldBeNil)
+			waitSinkWALStatus(w, "num_dropped", data.Int(1))
+
+			Convey("Then the tuple should be dropped", func() {
+				st := w.status()
+				So(st["num_dropped"], ShouldEqual, data.Int(1))
+				So(st["num_pending"], ShouldEqual, data.Int(0))
+			})
+		})
+
+		Convey("When the last record is broken", func() {
+			So(w.close(), ShouldBeNil)
+			si.setFail(true)
+
+			w, err := newSinkWAL(conf, "sink", si)
+			So(err, ShouldBeNil)
+			So(w.Write(ctx, freshTuples()[0]), ShouldBeNil)
+			So(w.wfile.Close(), ShouldBeNil)
+			f, err := os.OpenFile(w.segmentPath(w.wseg), os.O_WRONLY|os.O_APPEND, 0600)
+			So(err, ShouldBeNil)
+			_, err = f.Write([]byte{0, 0, 1})
+			So(err, ShouldBeNil)
+			So(f.Close(), ShouldBeNil)
+
+			Convey("Then it should be truncated when the WAL is reopened", func() {
+				si2 := NewTupleCollectorSink()
+				w2, err := newSinkWAL(conf, "sink", si2)
+				So(err, ShouldBeNil)
+				So(w2.status()["num_pending"], ShouldEqual, data.Int(1))
+				w2.start(ctx)
+				Reset(func() {
+					w2.close()
+				})
+				So(w2.Write(ctx, freshTuples()[1]), ShouldBeNil)
+				si2.Wait(2)
+				So(sinkWALSeqs(si2.Tuples), ShouldResemble, []int64{1, 2})
+			})
+		})
+	})
+
+	Convey("Given an invalid WAL config", t, func() {
+		Convey("When the directory is empty", func() {
+			_, err := newSinkWAL(&SinkWALConfig{}, "sink", NewTupleCollectorSink())
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the segment size is negative", func() {
+			c := &SinkWALConfig{Dir: filepath.Join(os.TempDir(), "sensorbee_sink_wal_invalid"), SegmentSize: -1}
+
+			Convey("Then it should be invalid", func() {
+				So(c.Validate(), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestSinkWALInTopology(t *testing.T) {
+	Convey("Given a topology having a sink with a WAL", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_sink_wal_test")
+		So(err, ShouldBeNil)
+
+		ctx := NewContext(nil)
+		tp, err := NewDefaultTopology(ctx, "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			tp.Stop()
+			os.RemoveAll(dir)
+		})
+
+		so := NewTupleIncrementalEmitterSource(freshTuples())
+		_, err = tp.AddSource("source", so, nil)
+		So(err, ShouldBeNil)
+
+		si := newSwitchableFailSink()
+		sn, err := tp.AddSink("sink", si, &SinkConfig{
+			WAL: &SinkWALConfig{Dir: dir},
+		})
+		So(err, ShouldBeNil)
+		So(sn.Input("source", nil), ShouldBeNil)
+
+		Convey("When tuples are emitted", func() {
+			so.EmitTuples(4)
+			si.Wait(4)
+
+			Convey("Then the sink should receive them", func() {
+				So(sinkWALSeqs(si.Tuples), ShouldResemble, []int64{1, 2, 3, 4})
+			})
+
+			Convey("Then the status should have the WAL", func() {
+				st := sn.Status()
+				So(st, ShouldContainKey, "wal")
+				w := st["wal"].(data.Map)
+				So(w["num_appended"], ShouldEqual, data.Int(4))
+				So(w["dir"], ShouldEqual, data.String(dir))
+			})
+
+			Convey("Then the topology should stop", func() {
+				So(tp.Stop(), ShouldBeNil)
+			})
+		})
+
+		Convey("When the sink fails while the topology stops", func() {
+			si.setFail(true)
+			so.EmitTuples(2)
+			for si.numAttempts() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			So(sn.ReplayWAL(), ShouldBeNil)
+			So(tp.Stop(), ShouldBeNil)
+
+			Convey("Then the tuples should be left in the WAL", func() {
+				w, err := newSinkWAL(&SinkWALConfig{Dir: dir}, "sink", NewTupleCollectorSink())
+				So(err, ShouldBeNil)
+				defer w.close()
+				So(w.status()["num_pending"], ShouldEqual, data.Int(2))
+			})
+		})
+	})
+
+	Convey("Given a topology having a sink without a WAL", t, func() {
+		tp, err := NewDefaultTopology(NewContext(nil), "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			tp.Stop()
+		})
+		sn, err := tp.AddSink("sink", NewTupleCollectorSink(), nil)
+		So(err, ShouldBeNil)
+
+		Convey("When replaying the WAL", func() {
+			err := sn.ReplayWAL()
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
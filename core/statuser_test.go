@@ -301,6 +301,12 @@ func TestNodeStatus(t *testing.T) {
 				})
 			})
 
+			Convey("Then it should have the latency of processing all tuples", func() {
+				v, err := st.Get(data.MustCompilePath("process_latency.count"))
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, 6)
+			})
+
 			// TODO: st["box"]
 		})
 
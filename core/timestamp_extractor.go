@@ -0,0 +1,112 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// TimestampExtractor sets the Timestamp of a tuple to the value of a field
+// of its Data. It's used to process tuples based on the time reported by
+// a device (i.e. event time) instead of the time at which tuples were
+// generated by a source.
+type TimestampExtractor struct {
+	// Path is the path to the field having the timestamp.
+	Path data.Path
+
+	// Format is the format of the field. When it's empty, the value is
+	// converted by data.ToTimestamp. "unix", "unix_milli", "unix_micro",
+	// and "unix_nano" interpret a numeric value as the elapsed time since
+	// the Unix epoch in the respective unit. Any other format is regarded
+	// as a layout of time.Parse and is used to parse a string value.
+	Format string
+}
+
+// Extract sets t.Timestamp to the value of the field. t isn't modified when
+// this method returns an error.
+func (e *TimestampExtractor) Extract(t *Tuple) error {
+	if e.Path == nil {
+		return errors.New("the path to the timestamp field is missing")
+	}
+	v, err := t.Data.Get(e.Path)
+	if err != nil {
+		return err
+	}
+	ts, err := e.toTimestamp(v)
+	if err != nil {
+		return fmt.Errorf("cannot extract the timestamp: %v", err)
+	}
+	t.Timestamp = ts
+	return nil
+}
+
+func (e *TimestampExtractor) toTimestamp(v data.Value) (time.Time, error) {
+	var unit int64
+	switch e.Format {
+	case "":
+		return data.ToTimestamp(v)
+	case "unix":
+		unit = int64(time.Second)
+	case "unix_milli":
+		unit = int64(time.Millisecond)
+	case "unix_micro":
+		unit = int64(time.Microsecond)
+	case "unix_nano":
+		unit = int64(time.Nanosecond)
+	default:
+		if v.Type() == data.TypeTimestamp {
+			return data.AsTimestamp(v)
+		}
+		s, err := data.AsString(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Parse(e.Format, s)
+	}
+
+	switch v.Type() {
+	case data.TypeInt:
+		i, _ := data.AsInt(v)
+		return time.Unix(i/(int64(time.Second)/unit), i%(int64(time.Second)/unit)*unit), nil
+	case data.TypeFloat:
+		f, _ := data.AsFloat(v)
+		return data.ToTimestamp(data.Float(f * float64(unit) / float64(time.Second)))
+	case data.TypeTimestamp:
+		return data.AsTimestamp(v)
+	default:
+		return time.Time{}, fmt.Errorf("%v format requires a numeric value: %v",
+			e.Format, v.Type())
+	}
+}
+
+// timestampWriter sets the timestamp of each tuple before writing it. A
+// tuple whose timestamp cannot be extracted is dropped and reported.
+type timestampWriter struct {
+	w        WriteCloser
+	e        *TimestampExtractor
+	nodeType NodeType
+	nodeName string
+}
+
+func newTimestampWriter(w WriteCloser, e *TimestampExtractor, nodeType NodeType, nodeName string) *timestampWriter {
+	return &timestampWriter{
+		w:        w,
+		e:        e,
+		nodeType: nodeType,
+		nodeName: nodeName,
+	}
+}
+
+func (tw *timestampWriter) Write(ctx *Context, t *Tuple) error {
+	if err := tw.e.Extract(t); err != nil {
+		ctx.droppedTuple(t, tw.nodeType, tw.nodeName, ETOutput, err)
+		return nil
+	}
+	return tw.w.Write(ctx, t)
+}
+
+func (tw *timestampWriter) Close(ctx *Context) error {
+	return tw.w.Close(ctx)
+}
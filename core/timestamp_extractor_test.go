@@ -0,0 +1,141 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestTimestampExtractor(t *testing.T) {
+	Convey("Given a tuple", t, func() {
+		tup := &Tuple{
+			Data: data.Map{
+				"sec":   data.Int(1500000000),
+				"ms":    data.Int(1500000000123),
+				"fsec":  data.Float(1500000000.5),
+				"str":   data.String("2017-07-14T02:40:00.123Z"),
+				"plain": data.String("2017/07/14 02:40:00"),
+				"obj":   data.Map{"ts": data.Int(1500000000)},
+			},
+		}
+
+		for _, c := range []struct {
+			path     string
+			format   string
+			expected time.Time
+		}{
+			{"sec", "", time.Unix(1500000000, 0)},
+			{"sec", "unix", time.Unix(1500000000, 0)},
+			{"ms", "unix_milli", time.Unix(1500000000, 123000000)},
+			{"fsec", "", time.Unix(1500000000, 500000000)},
+			{"fsec", "unix", time.Unix(1500000000, 500000000)},
+			{"str", "", time.Date(2017, 7, 14, 2, 40, 0, 123000000, time.UTC)},
+			{"plain", "2006/01/02 15:04:05", time.Date(2017, 7, 14, 2, 40, 0, 0, time.UTC)},
+			{"obj.ts", "", time.Unix(1500000000, 0)},
+		} {
+			c := c
+			Convey("When extracting "+c.path+" with format '"+c.format+"'", func() {
+				e := &TimestampExtractor{
+					Path:   data.MustCompilePath(c.path),
+					Format: c.format,
+				}
+				err := e.Extract(tup)
+
+				Convey("Then the timestamp should be set", func() {
+					So(err, ShouldBeNil)
+					So(tup.Timestamp.Equal(c.expected), ShouldBeTrue)
+				})
+			})
+		}
+
+		Convey("When extracting a missing field", func() {
+			e := &TimestampExtractor{Path: data.MustCompilePath("nothing")}
+			err := e.Extract(tup)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(tup.Timestamp.IsZero(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When extracting a string with a unix format", func() {
+			e := &TimestampExtractor{
+				Path:   data.MustCompilePath("str"),
+				Format: "unix_milli",
+			}
+
+			Convey("Then it should fail", func() {
+				So(e.Extract(tup), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When extracting a string not matching the layout", func() {
+			e := &TimestampExtractor{
+				Path:   data.MustCompilePath("plain"),
+				Format: time.RFC1123,
+			}
+
+			Convey("Then it should fail", func() {
+				So(e.Extract(tup), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a topology with a source extracting timestamps", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "timestamp_extractor")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		tuples := freshTuples()[:4]
+		for _, tup := range tuples {
+			seq, _ := data.AsInt(tup.Data["seq"])
+			tup.Data["ts"] = data.Int(1000 + seq)
+		}
+		delete(tuples[2].Data, "ts")
+
+		son, err := t.AddSource("source", NewTupleEmitterSource(tuples), &SourceConfig{
+			PausedOnStartup: true,
+			TimestampExtractor: &TimestampExtractor{
+				Path: data.MustCompilePath("ts"),
+			},
+		})
+		So(err, ShouldBeNil)
+		dtso := NewDroppedTupleCollectorSource().(*droppedTupleCollectorSource)
+		_, err = t.AddSource("dropped_tuples", dtso, nil)
+		So(err, ShouldBeNil)
+		dtso.state.Wait(TSRunning)
+		dropped := NewTupleCollectorSink()
+		dsin, err := t.AddSink("dropped", dropped, nil)
+		So(err, ShouldBeNil)
+		So(dsin.Input("dropped_tuples", nil), ShouldBeNil)
+
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("source", nil), ShouldBeNil)
+
+		Convey("When the source generates tuples", func() {
+			So(son.Resume(), ShouldBeNil)
+
+			Convey("Then the sink should receive tuples with extracted timestamps", func() {
+				si.Wait(3)
+				So(si.len(), ShouldEqual, 3)
+				si.m.Lock()
+				defer si.m.Unlock()
+				So(si.Tuples[0].Timestamp.Equal(time.Unix(1001, 0)), ShouldBeTrue)
+				So(si.Tuples[1].Timestamp.Equal(time.Unix(1002, 0)), ShouldBeTrue)
+				So(si.Tuples[2].Timestamp.Equal(time.Unix(1004, 0)), ShouldBeTrue)
+			})
+
+			Convey("Then the tuple without a timestamp should be reported", func() {
+				dropped.Wait(1)
+				So(dropped.len(), ShouldEqual, 1)
+			})
+		})
+	})
+}
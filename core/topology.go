@@ -1,5 +1,11 @@
 package core
 
+import (
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
 // Topology is a topology which can add Sources, Boxes, and Sinks
 // dynamically. Boxes and Sinks can also add inputs dynamically from running
 // Sources or Boxes.
@@ -51,6 +57,53 @@ type Topology interface {
 	// isn't relevant to those nodes have.
 	State() TopologyStateHolder
 
+	// Usage returns resources accumulated by the topology since it was
+	// created. It's still available after the topology stops. The returned
+	// map has the following fields:
+	//
+	//	* created_at: the time when the topology was created
+	//	* num_processed: the number of tuples processed by boxes and sinks
+	//	* num_emitted: the number of tuples emitted by sources and boxes
+	//	* bytes_emitted: the estimated size of data of emitted tuples in
+	//	  bytes
+	//	* cpu_time_estimate: the total time in seconds boxes and sinks spent
+	//	  on processing tuples. It includes the time blocked by full queues
+	//	  of subsequent nodes, so it's an upper bound of the CPU time.
+	//	* peak_heap_bytes: the peak heap size of the process observed while
+	//	  the topology was running. Topologies in the same process share the
+	//	  heap, so it's also an upper bound.
+	Usage() data.Map
+
+	// Checkpoint takes a checkpoint of states of the topology, which are
+	// UDSs implementing SavableSharedState and boxes implementing
+	// CheckpointableBox. It fails when ContextConfig.Checkpoint isn't
+	// configured. It returns the information of the checkpoint having the
+	// following fields:
+	//
+	//	* id: the sequential number of the checkpoint
+	//	* time: the time when the checkpoint was started
+	//	* states: names of saved states
+	//	* boxes: names of saved boxes
+	Checkpoint() (data.Map, error)
+
+	// RestoreCheckpoint restores states and boxes from the latest
+	// checkpoint. States and boxes have to be created before calling this
+	// method, and only states implementing LoadableSharedState are restored.
+	// It returns the information of the restored checkpoint in the same
+	// format as Checkpoint, or nil when there's no checkpoint.
+	RestoreCheckpoint() (data.Map, error)
+
+	// CheckpointStatus returns the status of checkpoints of the topology. The
+	// returned map has the following fields:
+	//
+	//	* enabled: true when checkpoints are configured
+	//	* interval: the interval of periodic checkpoints in seconds. It's 0
+	//	  when checkpoints are only taken on demand.
+	//	* last_checkpoint(optional): the information of the last checkpoint
+	//	* last_restored(optional): the information of the restored checkpoint
+	//	* last_error(optional): the time and the message of the last error
+	CheckpointStatus() data.Map
+
 	// TODO: low priority: Pause, Resume
 
 	// Node returns a node registered to the topology. It returns NotExistError
@@ -97,10 +150,25 @@ type SourceConfig struct {
 	// If it is true, the source is removed.
 	RemoveOnStop bool
 
+	// TimestampExtractor, if not nil, sets the timestamp of each tuple
+	// generated by the source. Tuples whose timestamp cannot be extracted
+	// are dropped.
+	TimestampExtractor *TimestampExtractor
+
+	// Validator, if not nil, validates each tuple generated by the source
+	// before its timestamp is extracted. Invalid tuples are dropped.
+	Validator TupleValidator
+
 	// Meta contains meta information of the source. This field won't be used
 	// by core package and application can store any form of information
 	// related to the source.
 	Meta interface{}
+
+	// Labels are arbitrary key-value pairs attached to the source such as
+	// the team owning it. They appear in the status and logs of the node so
+	// that they can be used to attribute costs or filter nodes in monitoring
+	// systems. Keys and values are validated by ValidateLabels.
+	Labels map[string]string
 }
 
 // BoxConfig has configuration parameters of a Box node.
@@ -111,10 +179,39 @@ type BoxConfig struct {
 	// If it is true, the box is removed.
 	RemoveOnStop bool
 
+	// TimestampExtractor, if not nil, sets the timestamp of each tuple
+	// written by the box. Tuples whose timestamp cannot be extracted are
+	// dropped.
+	TimestampExtractor *TimestampExtractor
+
+	// Validator, if not nil, validates each tuple written by the box
+	// before its timestamp is extracted. Invalid tuples are dropped.
+	Validator TupleValidator
+
+	// ProcessTimeout, if positive, is the maximum duration of a single call
+	// of Box.Process. When Process doesn't return in time, the tuple fails
+	// with an error satisfying IsTimeoutError and the Context given to Process
+	// is canceled. Tuples written by Process after the timeout are discarded.
+	// Process keeps running in background until it returns, so Process and
+	// UDFs called from it should check Context.Done to stop early.
+	ProcessTimeout time.Duration
+
+	// Quarantine, if not nil, makes the Box recover from panics in
+	// Box.Process instead of stopping, and puts the Box into quarantine when
+	// it panics too often. A recovered panic fails the tuple with an error
+	// satisfying IsPanicError. See QuarantinePolicy for details.
+	Quarantine *QuarantinePolicy
+
 	// Meta contains meta information of the box. This field won't be used
 	// by core package and application can store any form of information
 	// related to the box.
 	Meta interface{}
+
+	// Labels are arbitrary key-value pairs attached to the box such as
+	// the team owning it. They appear in the status and logs of the node so
+	// that they can be used to attribute costs or filter nodes in monitoring
+	// systems. Keys and values are validated by ValidateLabels.
+	Labels map[string]string
 }
 
 // SinkConfig has configuration parameters of a Sink node.
@@ -127,4 +224,27 @@ type SinkConfig struct {
 	// by core package and application can store any form of information
 	// related to the sink.
 	Meta interface{}
+
+	// Labels are arbitrary key-value pairs attached to the sink such as
+	// the team owning it. They appear in the status and logs of the node so
+	// that they can be used to attribute costs or filter nodes in monitoring
+	// systems. Keys and values are validated by ValidateLabels.
+	Labels map[string]string
+
+	// Ordering is the order in which tuples from multiple inputs are written
+	// to the sink. It's a property of the sink rather than of each input
+	// because the order is defined among all inputs. The default value is
+	// ArrivalOrder.
+	Ordering SinkOrdering
+
+	// OrderingBufferSize is the maximum number of tuples buffered to order
+	// tuples when Ordering isn't ArrivalOrder. A larger buffer tolerates
+	// a larger delay of an input at the cost of latency. The default size is
+	// used when it's 0.
+	OrderingBufferSize int
+
+	// WAL, if not nil, makes the sink write tuples through a write-ahead log
+	// so that tuples aren't lost while the sink cannot write them. See
+	// SinkWALConfig for details.
+	WAL *SinkWALConfig
 }
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -50,12 +52,82 @@ func (t EventType) String() string {
 	}
 }
 
+// TraceConfig has parameters controlling tuple tracing enabled by
+// ContextFlags.TupleTrace. The zero value traces all tuples and keeps their
+// whole traces in Tuple.Trace.
+type TraceConfig struct {
+	// SamplingRate is the ratio of tuples to be traced. Whether a tuple is
+	// traced is decided when its first event is recorded, which is usually
+	// when it's emitted from a source, and tuples derived from it by
+	// ShallowCopy or Copy follow the decision. All tuples are traced when
+	// it's 0 or greater than or equal to 1.
+	SamplingRate float64
+
+	// MaxEvents is the maximum number of events kept for a tuple. When the
+	// number of events exceeds it, the oldest event is discarded. The number
+	// of events isn't limited when it's 0.
+	MaxEvents int
+
+	// Store receives events instead of Tuple.Trace when it isn't nil. Events
+	// are keyed by Tuple.TraceID, which is assigned when the first event of
+	// a tuple is recorded. Because Tuple.Trace stays empty, copying a tuple
+	// doesn't copy its trace.
+	Store TraceStore
+}
+
+// TraceStore stores trace events of tuples out of band.
+type TraceStore interface {
+	// AddEvent adds an event to the trace identified by the ID. It can be
+	// called concurrently.
+	AddEvent(id int64, ev TraceEvent)
+}
+
+func (c *TraceConfig) sampled(t *Tuple) bool {
+	if c.SamplingRate <= 0 || c.SamplingRate >= 1 {
+		return true
+	}
+	if t.Flags.IsSet(TFTraceSampled) {
+		return true
+	}
+	if t.Flags.IsSet(TFTraceSkipped) {
+		return false
+	}
+	if rand.Float64() < c.SamplingRate {
+		t.Flags.Set(TFTraceSampled)
+		return true
+	}
+	t.Flags.Set(TFTraceSkipped)
+	return false
+}
+
 func tracing(t *Tuple, ctx *Context, inout EventType, msg string) {
 	if !ctx.Flags.TupleTrace.Enabled() {
 		return
 	}
+	conf := ctx.TraceConfig()
+	if conf == nil {
+		t.AddEvent(newDefaultEvent(inout, msg))
+		return
+	}
+	if !conf.sampled(t) {
+		return
+	}
+
 	ev := newDefaultEvent(inout, msg)
+	if conf.Store != nil {
+		if t.TraceID == 0 {
+			t.TraceID = NewTemporaryID()
+		}
+		conf.Store.AddEvent(t.TraceID, ev)
+		return
+	}
 	t.AddEvent(ev)
+	if conf.MaxEvents > 0 && len(t.Trace) > conf.MaxEvents {
+		// Shifting events instead of reslicing keeps the underlying array
+		// from growing.
+		n := copy(t.Trace, t.Trace[len(t.Trace)-conf.MaxEvents:])
+		t.Trace = t.Trace[:n]
+	}
 }
 
 func newDefaultEvent(inout EventType, msg string) TraceEvent {
@@ -88,3 +160,63 @@ func (tw *traceWriter) Write(ctx *Context, t *Tuple) error {
 func (tw *traceWriter) Close(ctx *Context) error {
 	return tw.w.Close(ctx)
 }
+
+// MemoryTraceStore is a TraceStore keeping traces in memory. It only keeps a
+// limited number of the most recent traces.
+type MemoryTraceStore struct {
+	m         sync.Mutex
+	maxTraces int
+	maxEvents int
+	traces    map[int64][]TraceEvent
+
+	// ids has IDs of traces in the order of creation.
+	ids []int64
+}
+
+// NewMemoryTraceStore creates a new MemoryTraceStore keeping at most maxTraces
+// traces each of which has at most maxEvents events. The oldest trace is
+// discarded when a new trace is added to the full store. The number of
+// events isn't limited when maxEvents is 0.
+func NewMemoryTraceStore(maxTraces, maxEvents int) *MemoryTraceStore {
+	if maxTraces <= 0 {
+		maxTraces = 1
+	}
+	return &MemoryTraceStore{
+		maxTraces: maxTraces,
+		maxEvents: maxEvents,
+		traces:    map[int64][]TraceEvent{},
+	}
+}
+
+// AddEvent adds an event to the trace.
+func (s *MemoryTraceStore) AddEvent(id int64, ev TraceEvent) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	tr, ok := s.traces[id]
+	if !ok {
+		if len(s.ids) >= s.maxTraces {
+			delete(s.traces, s.ids[0])
+			s.ids = s.ids[1:]
+		}
+		s.ids = append(s.ids, id)
+	}
+	tr = append(tr, ev)
+	if s.maxEvents > 0 && len(tr) > s.maxEvents {
+		tr = tr[len(tr)-s.maxEvents:]
+	}
+	s.traces[id] = tr
+}
+
+// Events returns a copy of events of the trace. It returns nil when the
+// trace doesn't exist or has already been discarded.
+func (s *MemoryTraceStore) Events(id int64) []TraceEvent {
+	s.m.Lock()
+	defer s.m.Unlock()
+	tr, ok := s.traces[id]
+	if !ok {
+		return nil
+	}
+	res := make([]TraceEvent, len(tr))
+	copy(res, tr)
+	return res
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestTraceConfig(t *testing.T) {
+	Convey("Given a context with tracing enabled", t, func() {
+		ctx := NewContext(nil)
+		ctx.Flags.TupleTrace.Set(true)
+
+		trace := func(t *Tuple, n int) {
+			for i := 0; i < n; i++ {
+				tracing(t, ctx, ETOther, "test")
+			}
+		}
+
+		Convey("When the trace config isn't set", func() {
+			tup := NewTuple(data.Map{})
+			trace(tup, 10)
+
+			Convey("Then all events should be recorded", func() {
+				So(len(tup.Trace), ShouldEqual, 10)
+			})
+		})
+
+		Convey("When the number of events is limited", func() {
+			ctx.SetTraceConfig(&TraceConfig{MaxEvents: 3})
+			tup := NewTuple(data.Map{})
+			for i := 0; i < 5; i++ {
+				tracing(tup, ctx, ETOther, string('a'+rune(i)))
+			}
+
+			Convey("Then only the latest events should be kept", func() {
+				So(len(tup.Trace), ShouldEqual, 3)
+				So(tup.Trace[0].Msg, ShouldEqual, "c")
+				So(tup.Trace[2].Msg, ShouldEqual, "e")
+			})
+
+			Convey("Then a forked context should share the config", func() {
+				So(ctx.Fork().TraceConfig().MaxEvents, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When tuples are sampled", func() {
+			ctx.SetTraceConfig(&TraceConfig{SamplingRate: 0.5})
+			traced := 0
+			for i := 0; i < 1000; i++ {
+				tup := NewTuple(data.Map{})
+				trace(tup, 1)
+				derived := tup.ShallowCopy()
+				trace(derived, 1)
+
+				if len(tup.Trace) == 0 {
+					So(len(derived.Trace), ShouldEqual, 0)
+					So(tup.Flags.IsSet(TFTraceSkipped), ShouldBeTrue)
+					continue
+				}
+				So(len(derived.Trace), ShouldEqual, 2)
+				traced++
+			}
+
+			Convey("Then only a part of tuples should be traced", func() {
+				So(traced, ShouldBeGreaterThan, 300)
+				So(traced, ShouldBeLessThan, 700)
+			})
+		})
+
+		Convey("When traces are stored out of band", func() {
+			store := NewMemoryTraceStore(2, 2)
+			ctx.SetTraceConfig(&TraceConfig{Store: store})
+			tups := []*Tuple{NewTuple(data.Map{}), NewTuple(data.Map{}), NewTuple(data.Map{})}
+			for _, tup := range tups {
+				trace(tup, 3)
+			}
+
+			Convey("Then tuples shouldn't have traces", func() {
+				for _, tup := range tups {
+					So(tup.Trace, ShouldBeEmpty)
+					So(tup.TraceID, ShouldNotEqual, 0)
+				}
+			})
+
+			Convey("Then the store should only have the latest traces", func() {
+				So(store.Events(tups[0].TraceID), ShouldBeNil)
+				So(len(store.Events(tups[1].TraceID)), ShouldEqual, 2)
+				So(len(store.Events(tups[2].TraceID)), ShouldEqual, 2)
+			})
+		})
+	})
+}
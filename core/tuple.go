@@ -12,6 +12,17 @@ type Tuple struct {
 	// Data is the actual data that is processed.
 	Data data.Map
 
+	// Metadata has information about the tuple which isn't a part of Data,
+	// such as an offset or a partition of the source, the content type of the
+	// original payload, or the subject who sent it. It's nil when the tuple
+	// doesn't have metadata. Metadata follows the same sharing rules as Data,
+	// i.e. it must not be modified directly when TFSharedData is set.
+	//
+	// BQL statements can access metadata with meta("key"). Whether metadata
+	// of input tuples is propagated to output tuples of a BQL statement is
+	// controlled by "metadata_propagation" in the config of the topology.
+	Metadata data.Map
+
 	// InputName can be used to identify the sender of a tuple when a
 	// Box processes data from multiple inputs. It will be set before
 	// Box.Process is called. Also see BoxDeclarer.NamedInput.
@@ -42,6 +53,17 @@ type Tuple struct {
 	// Trace is used during debugging to trace to way of a Tuple through
 	// a topology. See the documentation for TraceEvent.
 	Trace []TraceEvent
+
+	// TraceID identifies the trace of this tuple in TraceConfig.Store. It's
+	// 0 when the trace isn't stored out of band. Tuples derived from this
+	// tuple share the ID.
+	TraceID int64
+
+	// LineageID identifies the lineage record of this tuple. It's 0 when
+	// lineage of the tuple isn't recorded. A BQL statement assigns a new ID to
+	// each output tuple when "lineage_max_records" is set in the config of
+	// the topology.
+	LineageID int64
 }
 
 // AddEvent adds a TraceEvent to this Tuple's trace. This is not
@@ -60,12 +82,15 @@ func (t *Tuple) Copy() *Tuple {
 	// except Data
 	out := t.shallowCopy()
 	out.Data = out.Data.Copy()
+	if out.Metadata != nil {
+		out.Metadata = out.Metadata.Copy()
+	}
 	out.Flags.Clear(TFSharedData) // not shared
 	return out
 }
 
 // ShallowCopy creates a new copy of a tuple. It only deep copies trace
-// information. Because Data is shared between the old tuple and the new tuple,
+// information. Because Data and Metadata are shared between the old tuple and the new tuple,
 // this method sets TFSharedData flag for both tuples. However, the tuple itself
 // returned from this method isn't shared and its TFShared flag isn't set.
 func (t *Tuple) ShallowCopy() *Tuple {
@@ -132,6 +157,14 @@ const (
 	//	(false, true): a tuple returned from ShallowCopy
 	//	(false, false): a tuple returned from NewTuple or Copy
 	TFSharedData
+
+	// TFTraceSampled is a flag which is set when a tuple is chosen to be
+	// traced by the sampling of TraceConfig.
+	TFTraceSampled
+
+	// TFTraceSkipped is a flag which is set when a tuple is chosen not to be
+	// traced by the sampling of TraceConfig.
+	TFTraceSkipped
 )
 
 // Set sets a set of flags at once.
@@ -79,6 +79,29 @@ func TestTuple(t *testing.T) {
 			})
 		})
 
+		Convey("When deep-copying the Tuple having metadata", func() {
+			t := tup.Copy()
+			t.Metadata = data.Map{"offset": data.Int(1)}
+			copy := t.Copy()
+
+			Convey("Then metadata should be copied", func() {
+				So(copy.Metadata, ShouldResemble, t.Metadata)
+				copy.Metadata["offset"] = data.Int(2)
+				So(t.Metadata["offset"], ShouldEqual, data.Int(1))
+			})
+		})
+
+		Convey("When shallow-copying the Tuple having metadata", func() {
+			t := tup.Copy()
+			t.Metadata = data.Map{"offset": data.Int(1)}
+			copy := t.ShallowCopy()
+
+			Convey("Then metadata should be shared", func() {
+				So(copy.Metadata, ShouldResemble, t.Metadata)
+				So(copy.Flags.IsSet(TFSharedData), ShouldBeTrue)
+			})
+		})
+
 		Convey("When creating a Tuple by NewTuple", func() {
 			t := NewTuple(testData)
 
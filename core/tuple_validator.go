@@ -0,0 +1,38 @@
+package core
+
+// TupleValidator validates tuples written by a Source or a Box.
+type TupleValidator interface {
+	// Validate returns an error when the tuple is invalid. It must not
+	// modify the tuple.
+	Validate(t *Tuple) error
+}
+
+// validatingWriter writes only valid tuples. An invalid tuple is dropped
+// and reported.
+type validatingWriter struct {
+	w        WriteCloser
+	v        TupleValidator
+	nodeType NodeType
+	nodeName string
+}
+
+func newValidatingWriter(w WriteCloser, v TupleValidator, nodeType NodeType, nodeName string) *validatingWriter {
+	return &validatingWriter{
+		w:        w,
+		v:        v,
+		nodeType: nodeType,
+		nodeName: nodeName,
+	}
+}
+
+func (vw *validatingWriter) Write(ctx *Context, t *Tuple) error {
+	if err := vw.v.Validate(t); err != nil {
+		ctx.droppedTuple(t, vw.nodeType, vw.nodeName, ETOutput, err)
+		return nil
+	}
+	return vw.w.Write(ctx, t)
+}
+
+func (vw *validatingWriter) Close(ctx *Context) error {
+	return vw.w.Close(ctx)
+}
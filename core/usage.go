@@ -0,0 +1,201 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+var (
+	// UsageMemorySamplingInterval is the interval of sampling the heap size
+	// of the process to compute the peak memory usage of topologies. It has
+	// to be set before topologies are created.
+	UsageMemorySamplingInterval = 10 * time.Second
+)
+
+// topologyUsage accumulates resources used by a topology since it was
+// created. Counters are updated by nodes without locks.
+//
+// Like dataDestinations, int64 fields must be at the beginning of the struct
+// for 64-bit alignment.
+type topologyUsage struct {
+	numProcessed int64
+	numEmitted   int64
+	bytesEmitted int64
+	processNanos int64
+	peakHeap     int64
+
+	createdAt time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// newTopologyUsage creates a topologyUsage and starts sampling the heap
+// size. stop has to be called to stop sampling.
+func newTopologyUsage() *topologyUsage {
+	u := &topologyUsage{
+		createdAt: time.Now(),
+		stopCh:    make(chan struct{}),
+	}
+	u.sampleHeap()
+	go u.runHeapSampler()
+	return u
+}
+
+func (u *topologyUsage) runHeapSampler() {
+	t := time.NewTicker(UsageMemorySamplingInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-u.stopCh:
+			return
+		case <-t.C:
+			u.sampleHeap()
+		}
+	}
+}
+
+// sampleHeap updates the peak heap size with the current heap size.
+func (u *topologyUsage) sampleHeap() {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	heap := int64(ms.HeapAlloc)
+	for {
+		peak := atomic.LoadInt64(&u.peakHeap)
+		if heap <= peak || atomic.CompareAndSwapInt64(&u.peakHeap, peak, heap) {
+			return
+		}
+	}
+}
+
+// stop stops sampling the heap size. Counters are still readable after the
+// usage is stopped.
+func (u *topologyUsage) stop() {
+	u.stopOnce.Do(func() {
+		u.sampleHeap()
+		close(u.stopCh)
+	})
+}
+
+func (u *topologyUsage) stopped() bool {
+	select {
+	case <-u.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// processed records that a box or a sink has processed a tuple in d.
+func (u *topologyUsage) processed(d time.Duration) {
+	atomic.AddInt64(&u.numProcessed, 1)
+	atomic.AddInt64(&u.processNanos, int64(d))
+}
+
+// emitted records that a source or a box has emitted a tuple.
+func (u *topologyUsage) emitted(t *Tuple) {
+	atomic.AddInt64(&u.numEmitted, 1)
+	atomic.AddInt64(&u.bytesEmitted, estimateDataSize(t.Data))
+}
+
+func (u *topologyUsage) status() data.Map {
+	if !u.stopped() {
+		u.sampleHeap()
+	}
+	return data.Map{
+		"created_at":        data.Timestamp(u.createdAt),
+		"num_processed":     data.Int(atomic.LoadInt64(&u.numProcessed)),
+		"num_emitted":       data.Int(atomic.LoadInt64(&u.numEmitted)),
+		"bytes_emitted":     data.Int(atomic.LoadInt64(&u.bytesEmitted)),
+		"cpu_time_estimate": data.Float(time.Duration(atomic.LoadInt64(&u.processNanos)).Seconds()),
+		"peak_heap_bytes":   data.Int(atomic.LoadInt64(&u.peakHeap)),
+	}
+}
+
+// estimateDataSize returns the approximate number of bytes of the value
+// encoded in a compact binary format such as MessagePack. It's only used for
+// accounting and doesn't have to be accurate.
+func estimateDataSize(v data.Value) int64 {
+	switch v.Type() {
+	case data.TypeNull, data.TypeBool:
+		return 1
+	case data.TypeInt, data.TypeFloat, data.TypeTimestamp:
+		return 9
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		return int64(len(s)) + 5
+	case data.TypeBlob:
+		b, _ := data.AsBlob(v)
+		return int64(len(b)) + 5
+	case data.TypeArray:
+		a, _ := data.AsArray(v)
+		n := int64(5)
+		for _, e := range a {
+			n += estimateDataSize(e)
+		}
+		return n
+	case data.TypeMap:
+		m, _ := data.AsMap(v)
+		n := int64(5)
+		for k, e := range m {
+			n += int64(len(k)) + 5 + estimateDataSize(e)
+		}
+		return n
+	}
+	return 0
+}
+
+// usageEmitWriter records tuples emitted by a source or a box.
+type usageEmitWriter struct {
+	w     WriteCloser
+	usage *topologyUsage
+}
+
+func newUsageEmitWriter(w WriteCloser, u *topologyUsage) *usageEmitWriter {
+	return &usageEmitWriter{
+		w:     w,
+		usage: u,
+	}
+}
+
+func (uw *usageEmitWriter) Write(ctx *Context, t *Tuple) error {
+	uw.usage.emitted(t)
+	return uw.w.Write(ctx, t)
+}
+
+func (uw *usageEmitWriter) Close(ctx *Context) error {
+	return uw.w.Close(ctx)
+}
+
+// usageProcessWriter records tuples processed by a sink and the time spent
+// on writing them. latency is optional.
+type usageProcessWriter struct {
+	w       Writer
+	usage   *topologyUsage
+	latency *latencyHistogram
+}
+
+func (uw *usageProcessWriter) Write(ctx *Context, t *Tuple) error {
+	start := time.Now()
+	defer func() {
+		d := time.Now().Sub(start)
+		uw.usage.processed(d)
+		if uw.latency != nil {
+			uw.latency.observe(d)
+		}
+	}()
+	return uw.w.Write(ctx, t)
+}
+
+// inputClosed passes the notification to the underlying writer, such as an
+// orderingWriter, so that wrapping it doesn't change the behavior.
+func (uw *usageProcessWriter) inputClosed(ctx *Context, inputName string) error {
+	if o, ok := uw.w.(inputObserver); ok {
+		return o.inputClosed(ctx, inputName)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"testing"
+)
+
+func TestTopologyUsage(t *testing.T) {
+	Convey("Given a topology having a source, a box, and a sink", t, func() {
+		ctx := NewContext(nil)
+		t, err := NewDefaultTopology(ctx, "test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		tup := &Tuple{
+			Data: data.Map{
+				"s": data.String("abc"),
+			},
+		}
+		so := NewTupleIncrementalEmitterSource([]*Tuple{tup.Copy(), tup.Copy(), tup.Copy()})
+		_, err = t.AddSource("source", so, nil)
+		So(err, ShouldBeNil)
+
+		bn, err := t.AddBox("box", BoxFunc(forwardBox), nil)
+		So(err, ShouldBeNil)
+		So(bn.Input("source", nil), ShouldBeNil)
+
+		si := NewTupleCollectorSink()
+		sin, err := t.AddSink("sink", si, nil)
+		So(err, ShouldBeNil)
+		So(sin.Input("box", nil), ShouldBeNil)
+
+		Convey("When tuples flow through the topology and it stops", func() {
+			so.EmitTuples(3)
+			si.Wait(3)
+			So(t.Stop(), ShouldBeNil)
+			u := t.Usage()
+
+			Convey("Then the usage should count tuples processed by the box and the sink", func() {
+				So(u["num_processed"], ShouldEqual, data.Int(6))
+			})
+
+			Convey("Then the usage should count tuples emitted by the source and the box", func() {
+				So(u["num_emitted"], ShouldEqual, data.Int(6))
+				So(u["bytes_emitted"], ShouldEqual, data.Int(6*estimateDataSize(tup.Data)))
+			})
+
+			Convey("Then the usage should have the time and memory used", func() {
+				cpu, err := data.ToFloat(u["cpu_time_estimate"])
+				So(err, ShouldBeNil)
+				So(cpu, ShouldBeGreaterThan, 0)
+				heap, err := data.ToInt(u["peak_heap_bytes"])
+				So(err, ShouldBeNil)
+				So(heap, ShouldBeGreaterThan, 0)
+				So(u, ShouldContainKey, "created_at")
+			})
+		})
+	})
+
+	Convey("Given values to be estimated", t, func() {
+		Convey("Then larger values should have larger sizes", func() {
+			small := estimateDataSize(data.Map{"a": data.String("x")})
+			large := estimateDataSize(data.Map{
+				"a": data.String("xxxxxxxxxx"),
+				"b": data.Array{data.Int(1), data.Blob([]byte("abc"))},
+			})
+			So(small, ShouldBeGreaterThan, 0)
+			So(large, ShouldBeGreaterThan, small)
+		})
+	})
+}
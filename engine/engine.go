@@ -0,0 +1,187 @@
+// Package engine provides an API to run SensorBee in-process without the HTTP
+// server. A Go application embedding SensorBee creates topologies with an
+// Engine, issues BQL statements to them, and receives results of SELECT
+// statements from channels:
+//
+//	e := engine.New(nil)
+//	defer e.Close()
+//
+//	t, err := e.CreateTopology("app", nil)
+//	if err != nil {
+//		return err
+//	}
+//	if _, err := t.Exec(`CREATE SOURCE s TYPE file WITH path="events.jsonl";`); err != nil {
+//		return err
+//	}
+//	sub, err := t.Select(`SELECT RSTREAM * FROM s [RANGE 1 TUPLES];`)
+//	if err != nil {
+//		return err
+//	}
+//	defer sub.Close()
+//	for tuple := range sub.Tuples() {
+//		fmt.Println(tuple.Data)
+//	}
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Config has parameters of an Engine.
+type Config struct {
+	// Logger is used by topologies created by the Engine. The standard
+	// logger of logrus is used when it's nil.
+	Logger *logrus.Logger
+
+	// UDSStorage is used by SAVE STATE and LOAD STATE statements. An
+	// in-memory storage is used when it's nil.
+	UDSStorage udf.UDSStorage
+
+	// LogDroppedTuples, LogDestinationlessTuples, and SummarizeDroppedTuples
+	// are the initial values of the corresponding flags of topologies.
+	LogDroppedTuples         bool
+	LogDestinationlessTuples bool
+	SummarizeDroppedTuples   bool
+}
+
+// Engine manages topologies running in the current process.
+//
+// Engine is thread-safe.
+type Engine struct {
+	config Config
+
+	m          sync.RWMutex
+	topologies map[string]*Topology
+	closed     bool
+}
+
+// New creates a new Engine. The default configuration is used when conf is
+// nil. The caller must call Close when the Engine gets unnecessary.
+func New(conf *Config) *Engine {
+	e := &Engine{
+		topologies: map[string]*Topology{},
+	}
+	if conf != nil {
+		e.config = *conf
+	}
+	if e.config.UDSStorage == nil {
+		e.config.UDSStorage = udf.NewInMemoryUDSStorage()
+	}
+	return e
+}
+
+// CreateTopology creates a new topology having the given config. The config
+// can be accessed in the topology as core.Context.Config and it can be nil.
+// Names of topologies are case-insensitive.
+func (e *Engine) CreateTopology(name string, config data.Map) (*Topology, error) {
+	if err := core.ValidateSymbol(name); err != nil {
+		return nil, err
+	}
+
+	e.m.Lock()
+	defer e.m.Unlock()
+	if e.closed {
+		return nil, errors.New("the engine is already closed")
+	}
+	n := strings.ToLower(name)
+	if _, ok := e.topologies[n]; ok {
+		return nil, fmt.Errorf("topology '%v' already exists", name)
+	}
+
+	cc := &core.ContextConfig{
+		Logger: e.config.Logger,
+		Config: config,
+	}
+	cc.Flags.DroppedTupleLog.Set(e.config.LogDroppedTuples)
+	cc.Flags.DestinationlessTupleLog.Set(e.config.LogDestinationlessTuples)
+	cc.Flags.DroppedTupleSummarization.Set(e.config.SummarizeDroppedTuples)
+
+	tp, err := core.NewDefaultTopology(core.NewContext(cc), name)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := bql.NewTopologyBuilder(tp)
+	if err != nil {
+		tp.Stop()
+		return nil, err
+	}
+	tb.UDSStorage = e.config.UDSStorage
+
+	t := newTopology(tb)
+	e.topologies[n] = t
+	return t, nil
+}
+
+// Topology returns the topology having the name. It returns an error
+// satisfying core.IsNotExist when the topology doesn't exist.
+func (e *Engine) Topology(name string) (*Topology, error) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	t, ok := e.topologies[strings.ToLower(name)]
+	if !ok {
+		return nil, core.NotExistError(fmt.Errorf("topology '%v' doesn't exist", name))
+	}
+	return t, nil
+}
+
+// Topologies returns all topologies sorted by their names.
+func (e *Engine) Topologies() []*Topology {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	ts := make([]*Topology, 0, len(e.topologies))
+	for _, t := range e.topologies {
+		ts = append(ts, t)
+	}
+	sort.Sort(topologiesByName(ts))
+	return ts
+}
+
+// DestroyTopology stops the topology and removes it from the Engine. It
+// returns an error satisfying core.IsNotExist when the topology doesn't
+// exist. The topology is removed even if it cannot be stopped successfully.
+func (e *Engine) DestroyTopology(name string) error {
+	e.m.Lock()
+	n := strings.ToLower(name)
+	t, ok := e.topologies[n]
+	if !ok {
+		e.m.Unlock()
+		return core.NotExistError(fmt.Errorf("topology '%v' doesn't exist", name))
+	}
+	delete(e.topologies, n)
+	e.m.Unlock()
+	return t.stop()
+}
+
+// Close stops all topologies. It returns the last error which occurred while
+// stopping topologies. No topology can be created after Close is called.
+func (e *Engine) Close() error {
+	e.m.Lock()
+	e.closed = true
+	ts := e.topologies
+	e.topologies = map[string]*Topology{}
+	e.m.Unlock()
+
+	var lastErr error
+	for _, t := range ts {
+		if err := t.stop(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+type topologiesByName []*Topology
+
+func (t topologiesByName) Len() int           { return len(t) }
+func (t topologiesByName) Less(i, j int) bool { return t[i].Name() < t[j].Name() }
+func (t topologiesByName) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
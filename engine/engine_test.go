@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestEngine(t *testing.T) {
+	Convey("Given an engine", t, func() {
+		e := New(nil)
+		Reset(func() {
+			e.Close()
+		})
+
+		Convey("When creating a topology", func() {
+			tp, err := e.CreateTopology("Test", data.Map{"key": data.String("value")})
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be looked up case-insensitively", func() {
+				t2, err := e.Topology("test")
+				So(err, ShouldBeNil)
+				So(t2, ShouldEqual, tp)
+				So(tp.Name(), ShouldEqual, "Test")
+			})
+
+			Convey("Then it should have the config", func() {
+				v, err := tp.Context().Config.Get("key")
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.String("value"))
+			})
+
+			Convey("Then another topology having the same name cannot be created", func() {
+				_, err := e.CreateTopology("TEST", nil)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("And destroying it", func() {
+				So(e.DestroyTopology("test"), ShouldBeNil)
+
+				Convey("Then it shouldn't be found", func() {
+					_, err := e.Topology("test")
+					So(core.IsNotExist(err), ShouldBeTrue)
+					So(e.Topologies(), ShouldBeEmpty)
+				})
+
+				Convey("Then it cannot be destroyed again", func() {
+					So(core.IsNotExist(e.DestroyTopology("test")), ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("When creating multiple topologies", func() {
+			for _, n := range []string{"c", "a", "b"} {
+				_, err := e.CreateTopology(n, nil)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then they should be listed in order", func() {
+				ts := e.Topologies()
+				So(len(ts), ShouldEqual, 3)
+				for i, n := range []string{"a", "b", "c"} {
+					So(ts[i].Name(), ShouldEqual, n)
+				}
+			})
+		})
+
+		Convey("When creating a topology with an invalid name", func() {
+			_, err := e.CreateTopology("in valid", nil)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a topology after closing the engine", func() {
+			So(e.Close(), ShouldBeNil)
+			_, err := e.CreateTopology("test", nil)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestTopology(t *testing.T) {
+	Convey("Given a topology having a paused source", t, func() {
+		f, err := ioutil.TempFile("", "sensorbee_engine_test")
+		So(err, ShouldBeNil)
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(f, `{"int":%v}`+"\n", i)
+		}
+		So(f.Close(), ShouldBeNil)
+
+		e := New(nil)
+		Reset(func() {
+			e.Close()
+			os.Remove(f.Name())
+		})
+		tp, err := e.CreateTopology("test", nil)
+		So(err, ShouldBeNil)
+		nodes, err := tp.Exec(fmt.Sprintf(`CREATE PAUSED SOURCE s TYPE file WITH path=%q;
+			CREATE STREAM t AS SELECT RSTREAM int * 2 AS v FROM s [RANGE 1 TUPLES];`, f.Name()))
+		So(err, ShouldBeNil)
+		So(len(nodes), ShouldEqual, 2)
+
+		Convey("When subscribing a SELECT statement and resuming the source", func() {
+			sub, err := tp.Select(`SELECT RSTREAM * FROM t [RANGE 1 TUPLES];`)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				sub.Close()
+			})
+			_, err = tp.Exec(`RESUME SOURCE s;`)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should receive all results", func() {
+				for i := 0; i < 5; i++ {
+					select {
+					case t := <-sub.Tuples():
+						So(t.Data["v"], ShouldEqual, data.Int(i*2))
+					case <-time.After(5 * time.Second):
+						So("timed out", ShouldBeNil)
+					}
+				}
+			})
+
+			Convey("Then closing the subscription should close the channel", func() {
+				So(sub.Close(), ShouldBeNil)
+				So(sub.Close(), ShouldBeNil)
+				closed := false
+				for i := 0; i < 10 && !closed; i++ {
+					select {
+					case _, ok := <-sub.Tuples():
+						closed = !ok
+					case <-time.After(time.Second):
+					}
+				}
+				So(closed, ShouldBeTrue)
+			})
+		})
+
+		Convey("When evaluating an expression", func() {
+			v, err := tp.Eval(`EVAL 1 + 2;`)
+
+			Convey("Then it should return the result", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, data.Int(3))
+			})
+		})
+
+		Convey("When issuing statements of wrong kinds", func() {
+			Convey("Then Exec should reject SELECT and EVAL", func() {
+				_, err := tp.Exec(`SELECT RSTREAM * FROM t [RANGE 1 TUPLES];`)
+				So(err, ShouldNotBeNil)
+				_, err = tp.Exec(`EVAL 1;`)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then Select should reject other statements", func() {
+				_, err := tp.Select(`EVAL 1;`)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then Eval should reject other statements", func() {
+				_, err := tp.Eval(`SELECT RSTREAM * FROM t [RANGE 1 TUPLES];`)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then Select should reject multiple statements", func() {
+				_, err := tp.Select(`EVAL 1; EVAL 2;`)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When issuing an invalid statement", func() {
+			nodes, err := tp.Exec(`CREATE STREAM u AS SELECT RSTREAM * FROM t [RANGE 1 TUPLES];
+				CREATE SOURCE x TYPE no_such_type;`)
+
+			Convey("Then it should fail after issuing the valid one", func() {
+				So(err, ShouldNotBeNil)
+				So(len(nodes), ShouldEqual, 1)
+				So(nodes[0].Name(), ShouldEqual, "u")
+			})
+		})
+	})
+}
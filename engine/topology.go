@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Topology is a topology running in an Engine.
+//
+// Topology is thread-safe.
+type Topology struct {
+	tb *bql.TopologyBuilder
+}
+
+func newTopology(tb *bql.TopologyBuilder) *Topology {
+	return &Topology{
+		tb: tb,
+	}
+}
+
+// Name returns the name of the topology.
+func (t *Topology) Name() string {
+	return t.tb.Topology().Name()
+}
+
+// Builder returns the bql.TopologyBuilder of the topology. It can be used to
+// register source, sink, or UDS creators only available in the topology.
+func (t *Topology) Builder() *bql.TopologyBuilder {
+	return t.tb
+}
+
+// Context returns the core.Context of the topology.
+func (t *Topology) Context() *core.Context {
+	return t.tb.Topology().Context()
+}
+
+func (t *Topology) parse(queries string) ([]interface{}, error) {
+	// A parser isn't thread-safe, so a new one is created for each call.
+	return parser.New().ParseStmts(queries)
+}
+
+// Exec issues BQL statements to the topology in order. It returns nodes
+// created or updated by the statements. A statement may not have a
+// corresponding node, and a nil is contained in that case. SELECT, EVAL, and
+// DESCRIBE statements cannot be issued by Exec; use Select, Eval, or
+// Builder().Describe instead.
+//
+// Exec isn't atomic. When the second statement fails, the node created by the
+// first statement remains in the topology.
+func (t *Topology) Exec(queries string) ([]core.Node, error) {
+	stmts, err := t.parse(queries)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case parser.SelectStmt, parser.SelectUnionStmt, parser.EvalStmt, parser.DescribeStmt:
+			return nil, fmt.Errorf("Exec doesn't support '%v'", stmt)
+		}
+	}
+
+	nodes := make([]core.Node, 0, len(stmts))
+	for _, stmt := range stmts {
+		n, err := t.tb.AddStmt(stmt)
+		if err != nil {
+			return nodes, fmt.Errorf("cannot issue '%v': %v", stmt, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// Select issues a SELECT or SELECT ... UNION ALL statement and returns a
+// Subscription receiving its results. The caller must close the
+// Subscription.
+func (t *Topology) Select(query string) (*Subscription, error) {
+	stmt, err := t.parseOne(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sn core.SinkNode
+		ch <-chan *core.Tuple
+	)
+	switch s := stmt.(type) {
+	case parser.SelectStmt:
+		sn, ch, err = t.tb.AddSelectStmt(&s)
+	case parser.SelectUnionStmt:
+		sn, ch, err = t.tb.AddSelectUnionStmt(&s)
+	default:
+		return nil, fmt.Errorf("'%v' isn't a SELECT statement", stmt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		sink: sn,
+		ch:   ch,
+	}, nil
+}
+
+// Eval evaluates an EVAL statement and returns the result.
+func (t *Topology) Eval(query string) (data.Value, error) {
+	stmt, err := t.parseOne(query)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := stmt.(parser.EvalStmt)
+	if !ok {
+		return nil, fmt.Errorf("'%v' isn't an EVAL statement", stmt)
+	}
+	return t.tb.RunEvalStmt(&s)
+}
+
+func (t *Topology) parseOne(query string) (interface{}, error) {
+	stmts, err := t.parse(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) != 1 {
+		return nil, errors.New("exactly one statement must be given")
+	}
+	return stmts[0], nil
+}
+
+func (t *Topology) stop() error {
+	return t.tb.Topology().Stop()
+}
+
+// Subscription receives results of a SELECT statement.
+type Subscription struct {
+	sink core.SinkNode
+	ch   <-chan *core.Tuple
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Tuples returns the channel receiving results. The channel is closed when
+// the Subscription is closed or the topology is stopped.
+func (s *Subscription) Tuples() <-chan *core.Tuple {
+	return s.ch
+}
+
+// Close stops receiving results and removes nodes created for the SELECT
+// statement from the topology. Close can be called more than once.
+func (s *Subscription) Close() error {
+	s.closeOnce.Do(func() {
+		go func() {
+			// vacuum all tuples to avoid blocking the sink.
+			for _ = range s.ch {
+			}
+		}()
+		s.closeErr = s.sink.Stop()
+	})
+	return s.closeErr
+}
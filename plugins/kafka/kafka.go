@@ -0,0 +1,705 @@
+// Package kafka provides the "kafka" source and sink, which consume and
+// produce records of Apache Kafka topics. The package registers them when
+// it's imported, so add it to the plugins section of build.yaml to use them:
+//
+//	plugins:
+//	  - gopkg.in/sensorbee/sensorbee.v0/plugins/kafka
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Commit modes of the kafka source.
+const (
+	// kafkaCommitAuto commits offsets periodically and when the source is
+	// paused or stopped.
+	kafkaCommitAuto = "auto"
+
+	// kafkaCommitSync commits offsets after all records returned from each
+	// fetch are written.
+	kafkaCommitSync = "sync"
+
+	// kafkaCommitNone doesn't commit offsets.
+	kafkaCommitNone = "none"
+)
+
+// kafkaBrokers returns addresses of brokers given as an array or a comma
+// separated string.
+func kafkaBrokers(v data.Value) ([]string, error) {
+	var res []string
+	switch v.Type() {
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		for _, b := range strings.Split(s, ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				res = append(res, b)
+			}
+		}
+	case data.TypeArray:
+		a, _ := data.AsArray(v)
+		for _, e := range a {
+			b, err := data.AsString(e)
+			if err != nil {
+				return nil, fmt.Errorf("brokers must be strings: %v", err)
+			}
+			res = append(res, b)
+		}
+	default:
+		return nil, errors.New("brokers must be a string or an array of strings")
+	}
+	if len(res) == 0 {
+		return nil, errors.New("brokers must not be empty")
+	}
+	return res, nil
+}
+
+// kafkaRecordMetadata returns the metadata of a tuple created from the
+// record. The key and values of headers are strings when they're valid
+// UTF-8 and blobs otherwise.
+func kafkaRecordMetadata(topic string, partition int32, r *kafkaRecord) data.Map {
+	bytesValue := func(b []byte) data.Value {
+		if b == nil {
+			return data.Null{}
+		}
+		if utf8.Valid(b) {
+			return data.String(b)
+		}
+		return data.Blob(b)
+	}
+	m := data.Map{
+		"kafka_topic":     data.String(topic),
+		"kafka_partition": data.Int(partition),
+		"kafka_offset":    data.Int(r.offset),
+		"kafka_key":       bytesValue(r.key),
+	}
+	if len(r.headers) > 0 {
+		hs := make(data.Map, len(r.headers))
+		for _, h := range r.headers {
+			hs[h.key] = bytesValue(h.value)
+		}
+		m["kafka_headers"] = hs
+	}
+	return m
+}
+
+type kafkaSourceConfig struct {
+	Brokers           data.Value `bql:",required"`
+	Topic             string     `bql:",required"`
+	GroupID           string
+	ClientID          string
+	StartOffset       string
+	CommitMode        string
+	CommitInterval    time.Duration
+	Format            string
+	SchemaRegistry    string
+	Schema            string
+	SessionTimeout    time.Duration
+	RebalanceTimeout  time.Duration
+	Timeout           time.Duration
+	FetchMaxWait      time.Duration
+	FetchMaxBytes     int32
+	ReconnectInterval time.Duration
+}
+
+// kafkaSource consumes records of a topic. Each record is decoded into a
+// tuple. The source runs a session which connects to the cluster, joins the
+// consumer group, and fetches records. When the session fails, the source
+// retries it after reconnect_interval. Pausing or stopping the source ends
+// the session after committing offsets and leaving the group, so that
+// other members of the group take over the partitions while it's paused.
+type kafkaSource struct {
+	conf     *kafkaSourceConfig
+	brokers  []string
+	ioParams *bql.IOParams
+	decode   func(b []byte) (data.Map, error)
+
+	m       sync.Mutex
+	cond    *sync.Cond
+	paused  bool
+	stopped bool
+	// running is true while a session is running or waiting for a retry.
+	running bool
+	// interrupt is closed to end the running session.
+	interrupt chan struct{}
+}
+
+func createKafkaSource(ctx *core.Context, ioParams *bql.IOParams, params data.Map) (core.Source, error) {
+	c := &kafkaSourceConfig{
+		ClientID:          "sensorbee",
+		StartOffset:       "latest",
+		CommitMode:        kafkaCommitAuto,
+		CommitInterval:    5 * time.Second,
+		Format:            "json",
+		SessionTimeout:    10 * time.Second,
+		RebalanceTimeout:  30 * time.Second,
+		Timeout:           30 * time.Second,
+		FetchMaxWait:      500 * time.Millisecond,
+		FetchMaxBytes:     1024 * 1024,
+		ReconnectInterval: time.Second,
+	}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	brokers, err := kafkaBrokers(c.Brokers)
+	if err != nil {
+		return nil, err
+	}
+	switch c.StartOffset {
+	case "latest", "earliest":
+	default:
+		return nil, fmt.Errorf("start_offset must be 'latest' or 'earliest': %v", c.StartOffset)
+	}
+	switch c.CommitMode {
+	case kafkaCommitAuto, kafkaCommitSync, kafkaCommitNone:
+	default:
+		return nil, fmt.Errorf("unsupported commit_mode: %v", c.CommitMode)
+	}
+	if c.CommitInterval <= 0 {
+		return nil, errors.New("commit_interval must be positive")
+	}
+	if c.FetchMaxBytes <= 0 {
+		return nil, errors.New("fetch_max_bytes must be positive")
+	}
+
+	s := &kafkaSource{
+		conf:     c,
+		brokers:  brokers,
+		ioParams: ioParams,
+	}
+	s.cond = sync.NewCond(&s.m)
+	switch c.Format {
+	case "json":
+		s.decode = func(b []byte) (data.Map, error) {
+			m := data.Map{}
+			if err := json.Unmarshal(b, &m); err != nil {
+				return nil, err
+			}
+			return m, nil
+		}
+	case "avro":
+		if c.SchemaRegistry == "" {
+			return nil, errors.New("schema_registry is required for the avro format")
+		}
+		var reader *bql.AvroSchema
+		if c.Schema != "" {
+			if reader, err = bql.ParseAvroSchema(c.Schema); err != nil {
+				return nil, err
+			}
+		}
+		s.decode = bql.NewAvroDecoder(bql.NewAvroSchemaRegistry(c.SchemaRegistry), reader).Decode
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", c.Format)
+	}
+	return s, nil
+}
+
+func (s *kafkaSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	for {
+		s.m.Lock()
+		for s.paused && !s.stopped {
+			s.cond.Wait()
+		}
+		if s.stopped {
+			s.m.Unlock()
+			return nil
+		}
+		interrupt := make(chan struct{})
+		s.interrupt = interrupt
+		s.running = true
+		s.m.Unlock()
+
+		if err := s.runSession(ctx, w, interrupt); err != nil {
+			ctx.ErrLog(err).WithField("node_name", s.ioParams.Name).
+				WithField("retry_in", s.conf.ReconnectInterval).
+				Error("The kafka session failed")
+			select {
+			case <-interrupt:
+			case <-time.After(s.conf.ReconnectInterval):
+			}
+		}
+
+		s.m.Lock()
+		s.running = false
+		s.interrupt = nil
+		s.cond.Broadcast()
+		s.m.Unlock()
+	}
+}
+
+// interruptSession ends the running session and waits for it. The caller
+// must hold the lock.
+func (s *kafkaSource) interruptSession() {
+	if s.interrupt != nil {
+		close(s.interrupt)
+		s.interrupt = nil
+	}
+	for s.running {
+		s.cond.Wait()
+	}
+}
+
+func (s *kafkaSource) Pause(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.paused = true
+	s.interruptSession()
+	return nil
+}
+
+func (s *kafkaSource) Resume(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.paused = false
+	s.cond.Broadcast()
+	return nil
+}
+
+func (s *kafkaSource) Stop(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.stopped = true
+	s.interruptSession()
+	s.cond.Broadcast()
+	return nil
+}
+
+// kafkaSession is a session of kafkaSource.
+type kafkaSession struct {
+	src    *kafkaSource
+	client *kafkaClient
+	member *kafkaGroupMember
+
+	partitions []int32
+	// offsets has the offset of the next record of each partition.
+	offsets map[int32]int64
+	// uncommitted is true when offsets have been changed since the last
+	// commit.
+	uncommitted bool
+}
+
+// runSession runs a session until interrupt is closed. It returns nil when
+// it's interrupted.
+func (s *kafkaSource) runSession(ctx *core.Context, w core.Writer, interrupt <-chan struct{}) error {
+	ss := &kafkaSession{
+		src:    s,
+		client: newKafkaClient(s.brokers, s.conf.ClientID, s.conf.Timeout),
+	}
+	defer ss.client.Close()
+	if s.conf.GroupID != "" {
+		ss.member = &kafkaGroupMember{
+			client:           ss.client,
+			group:            s.conf.GroupID,
+			topic:            s.conf.Topic,
+			sessionTimeout:   s.conf.SessionTimeout,
+			rebalanceTimeout: s.conf.RebalanceTimeout,
+		}
+		defer func() {
+			if err := ss.member.leave(); err != nil {
+				ctx.ErrLog(err).WithField("node_name", s.ioParams.Name).
+					Warning("Cannot leave the consumer group")
+			}
+		}()
+	}
+
+	if err := ss.assign(); err != nil {
+		return err
+	}
+	lastCommit := time.Now()
+	lastHeartbeat := time.Now()
+	for {
+		select {
+		case <-interrupt:
+			return ss.commit()
+		default:
+		}
+
+		if ss.member != nil && time.Now().Sub(lastHeartbeat) >= s.conf.SessionTimeout/3 {
+			if err := ss.member.heartbeat(); err != nil {
+				ke, ok := err.(kafkaError)
+				if !ok || !ke.needsRejoin() {
+					return err
+				}
+				// Offsets cannot be committed in the old generation. Records
+				// after the last commit will be consumed again by the member
+				// having the partitions.
+				if err := ss.assign(); err != nil {
+					return err
+				}
+			}
+			lastHeartbeat = time.Now()
+		}
+
+		if err := ss.fetch(ctx, w, interrupt); err != nil {
+			return err
+		}
+
+		switch s.conf.CommitMode {
+		case kafkaCommitSync:
+			if err := ss.commit(); err != nil {
+				return err
+			}
+		case kafkaCommitAuto:
+			if time.Now().Sub(lastCommit) >= s.conf.CommitInterval {
+				if err := ss.commit(); err != nil {
+					return err
+				}
+				lastCommit = time.Now()
+			}
+		}
+	}
+}
+
+// assign determines partitions to be consumed and their offsets. When the
+// source is in a consumer group, partitions are assigned by the group and
+// offsets committed by the group are used.
+func (ss *kafkaSession) assign() error {
+	topic := ss.src.conf.Topic
+	var err error
+	if ss.member != nil {
+		ss.partitions, err = ss.member.join()
+	} else {
+		ss.partitions, err = ss.client.partitions(topic)
+	}
+	if err != nil {
+		return err
+	}
+
+	ss.offsets = map[int32]int64{}
+	ss.uncommitted = false
+	if ss.member != nil && len(ss.partitions) > 0 {
+		committed, err := ss.member.committed(ss.partitions)
+		if err != nil {
+			return fmt.Errorf("cannot fetch committed offsets: %v", err)
+		}
+		for p, o := range committed {
+			ss.offsets[p] = o
+		}
+	}
+	for _, p := range ss.partitions {
+		if _, ok := ss.offsets[p]; ok {
+			continue
+		}
+		if err := ss.resetOffset(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetOffset sets the offset of the partition according to start_offset.
+func (ss *kafkaSession) resetOffset(partition int32) error {
+	topic := ss.src.conf.Topic
+	conn, err := ss.client.leader(topic, partition)
+	if err != nil {
+		return err
+	}
+	ts := kafkaLatestOffset
+	if ss.src.conf.StartOffset == "earliest" {
+		ts = kafkaEarliestOffset
+	}
+	o, err := conn.listOffset(topic, partition, ts)
+	if err != nil {
+		ss.client.handleError(err)
+		return fmt.Errorf("cannot get the offset of partition %v: %v", partition, err)
+	}
+	ss.offsets[partition] = o
+	return nil
+}
+
+// fetch fetches records from all partitions and writes them.
+func (ss *kafkaSession) fetch(ctx *core.Context, w core.Writer, interrupt <-chan struct{}) error {
+	conf := ss.src.conf
+	if len(ss.partitions) == 0 {
+		// all partitions are assigned to other members
+		select {
+		case <-interrupt:
+		case <-time.After(conf.FetchMaxWait):
+		}
+		return nil
+	}
+
+	reqs := map[*kafkaConn][]*kafkaFetchRequest{}
+	var order []*kafkaConn
+	for _, p := range ss.partitions {
+		conn, err := ss.client.leader(conf.Topic, p)
+		if err != nil {
+			return err
+		}
+		if _, ok := reqs[conn]; !ok {
+			order = append(order, conn)
+		}
+		reqs[conn] = append(reqs[conn], &kafkaFetchRequest{
+			partition: p,
+			offset:    ss.offsets[p],
+		})
+	}
+
+	for _, conn := range order {
+		res, err := conn.fetch(conf.Topic, reqs[conn], conf.FetchMaxWait, conf.FetchMaxBytes)
+		if err != nil {
+			return err
+		}
+		for _, r := range res {
+			if r.err != nil {
+				if r.err == kafkaErrOffsetOutOfRange {
+					if err := ss.resetOffset(r.partition); err != nil {
+						return err
+					}
+					continue
+				}
+				ss.client.handleError(r.err)
+				return fmt.Errorf("cannot fetch records from partition %v: %v", r.partition, r.err)
+			}
+			if err := ss.write(ctx, w, r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ss *kafkaSession) write(ctx *core.Context, w core.Writer, r *kafkaFetchResult) error {
+	topic := ss.src.conf.Topic
+	for _, rec := range r.records {
+		// a batch can have records before the requested offset
+		if rec.offset < ss.offsets[r.partition] {
+			continue
+		}
+		ss.offsets[r.partition] = rec.offset + 1
+		ss.uncommitted = true
+
+		meta := kafkaRecordMetadata(topic, r.partition, rec)
+		m, err := ss.src.decode(rec.value)
+		if err != nil {
+			ctx.ErrLog(err).WithField("node_name", ss.src.ioParams.Name).
+				WithField("partition", r.partition).WithField("offset", rec.offset).
+				Warning("Ignoring a malformed record")
+			meta["body"] = data.Blob(rec.value)
+			ctx.DroppedTuple(core.NewTuple(meta), core.NTSource, ss.src.ioParams.Name, core.ETOutput, err)
+			continue
+		}
+		t := core.NewTuple(m)
+		t.Timestamp = rec.timestamp
+		t.Metadata = meta
+		if err := w.Write(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commit commits offsets when the source is in a consumer group and the
+// commit mode isn't "none".
+func (ss *kafkaSession) commit() error {
+	if ss.member == nil || ss.src.conf.CommitMode == kafkaCommitNone || !ss.uncommitted {
+		return nil
+	}
+	offsets := make(map[int32]int64, len(ss.offsets))
+	for p, o := range ss.offsets {
+		offsets[p] = o
+	}
+	if err := ss.member.commit(offsets); err != nil {
+		return fmt.Errorf("cannot commit offsets: %v", err)
+	}
+	ss.uncommitted = false
+	return nil
+}
+
+type kafkaSinkConfig struct {
+	Brokers        data.Value `bql:",required"`
+	Topic          string     `bql:",required"`
+	ClientID       string
+	KeyField       string
+	Acks           int16
+	Compression    string
+	Format         string
+	SchemaRegistry string
+	Schema         string
+	Timeout        time.Duration
+	MaxRetries     int
+	RetryInterval  time.Duration
+}
+
+// kafkaSink produces a record for each tuple. The partition of a record is
+// determined by its key in the same way as the default partitioner of Java
+// clients when key_field is given. Otherwise, partitions are selected in the
+// round-robin manner. Connections are established lazily and reestablished
+// when they're broken.
+type kafkaSink struct {
+	conf     *kafkaSinkConfig
+	client   *kafkaClient
+	codec    int16
+	keyField data.Path
+	encode   func(m data.Map) ([]byte, error)
+
+	m    sync.Mutex
+	next int
+}
+
+func createKafkaSink(ctx *core.Context, ioParams *bql.IOParams, params data.Map) (core.Sink, error) {
+	c := &kafkaSinkConfig{
+		ClientID:      "sensorbee",
+		Acks:          -1,
+		Compression:   bql.CompressionNone,
+		Format:        "json",
+		Timeout:       30 * time.Second,
+		MaxRetries:    3,
+		RetryInterval: time.Second,
+	}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	brokers, err := kafkaBrokers(c.Brokers)
+	if err != nil {
+		return nil, err
+	}
+	if c.Acks != -1 && c.Acks != 1 {
+		return nil, fmt.Errorf("acks must be -1 (all) or 1: %v", c.Acks)
+	}
+	codec, ok := kafkaCodecs[c.Compression]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression: %v", c.Compression)
+	}
+
+	s := &kafkaSink{
+		conf:   c,
+		client: newKafkaClient(brokers, c.ClientID, c.Timeout),
+		codec:  codec,
+	}
+	if c.KeyField != "" {
+		if s.keyField, err = data.CompilePath(c.KeyField); err != nil {
+			return nil, fmt.Errorf("key_field doesn't have a valid path: %v", err)
+		}
+	}
+	switch c.Format {
+	case "json":
+		s.encode = func(m data.Map) ([]byte, error) {
+			return json.Marshal(m)
+		}
+	case "avro":
+		if c.SchemaRegistry == "" || c.Schema == "" {
+			return nil, errors.New("schema_registry and schema are required for the avro format")
+		}
+		schema, err := bql.ParseAvroSchema(c.Schema)
+		if err != nil {
+			return nil, err
+		}
+		s.encode = bql.NewAvroEncoder(bql.NewAvroSchemaRegistry(c.SchemaRegistry), c.Topic+"-value", schema).Encode
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", c.Format)
+	}
+	return s, nil
+}
+
+func (s *kafkaSink) Write(ctx *core.Context, t *core.Tuple) error {
+	value, err := s.encode(t.Data)
+	if err != nil {
+		return err
+	}
+	rec := &kafkaRecord{
+		timestamp: t.Timestamp,
+		value:     value,
+	}
+	if s.keyField != nil {
+		if v, err := t.Data.Get(s.keyField); err == nil && v.Type() != data.TypeNull {
+			if b, err := data.AsBlob(v); err == nil {
+				rec.key = b
+			} else if str, err := data.ToString(v); err == nil {
+				rec.key = []byte(str)
+			}
+		}
+	}
+	batch, err := encodeKafkaRecordBatch([]*kafkaRecord{rec}, s.codec)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; ; i++ {
+		err = s.produce(rec.key, batch)
+		if err == nil || i >= s.conf.MaxRetries {
+			return err
+		}
+		ctx.ErrLog(err).WithField("retry", i+1).Warning("Cannot produce a record to kafka")
+		time.Sleep(s.conf.RetryInterval)
+	}
+}
+
+func (s *kafkaSink) produce(key, batch []byte) error {
+	ps, err := s.client.partitions(s.conf.Topic)
+	if err != nil {
+		return err
+	}
+	var p int32
+	if key != nil {
+		p = ps[int(kafkaMurmur2(key)&0x7fffffff)%len(ps)]
+	} else {
+		s.m.Lock()
+		p = ps[s.next%len(ps)]
+		s.next++
+		s.m.Unlock()
+	}
+	conn, err := s.client.leader(s.conf.Topic, p)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.produce(s.conf.Topic, p, s.conf.Acks, s.conf.Timeout, batch); err != nil {
+		s.client.handleError(err)
+		return err
+	}
+	return nil
+}
+
+func (s *kafkaSink) Close(ctx *core.Context) error {
+	return s.client.Close()
+}
+
+// kafkaMurmur2 is the hash function used by the default partitioner of Java
+// clients.
+func kafkaMurmur2(b []byte) int32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+	h := uint32(seed) ^ uint32(len(b))
+	n := len(b) / 4 * 4
+	for i := 0; i < n; i += 4 {
+		k := uint32(b[i]) | uint32(b[i+1])<<8 | uint32(b[i+2])<<16 | uint32(b[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+	switch len(b) % 4 {
+	case 3:
+		h ^= uint32(b[n+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(b[n+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(b[n])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
+
+func init() {
+	bql.MustRegisterGlobalSourceCreator("kafka", bql.SourceCreatorFunc(createKafkaSource))
+	bql.MustRegisterGlobalSinkCreator("kafka", bql.SinkCreatorFunc(createKafkaSink))
+}
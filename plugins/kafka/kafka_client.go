@@ -0,0 +1,413 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kafkaClient manages connections to brokers of a Kafka cluster and caches
+// its metadata.
+type kafkaClient struct {
+	m         sync.Mutex
+	bootstrap []string
+	clientID  string
+	timeout   time.Duration
+	conns     map[string]*kafkaConn
+	metadata  *kafkaMetadata
+	closed    bool
+}
+
+func newKafkaClient(bootstrap []string, clientID string, timeout time.Duration) *kafkaClient {
+	return &kafkaClient{
+		bootstrap: bootstrap,
+		clientID:  clientID,
+		timeout:   timeout,
+		conns:     map[string]*kafkaConn{},
+	}
+}
+
+// conn returns a connection to the broker at the address. A broken
+// connection is replaced with a new one.
+func (c *kafkaClient) conn(addr string) (*kafkaConn, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return nil, errors.New("the kafka client is already closed")
+	}
+	if conn, ok := c.conns[addr]; ok {
+		conn.m.Lock()
+		broken := conn.broken
+		conn.m.Unlock()
+		if !broken {
+			return conn, nil
+		}
+		delete(c.conns, addr)
+	}
+	conn, err := dialKafka(addr, c.clientID, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	c.conns[addr] = conn
+	return conn, nil
+}
+
+// anyConn returns a connection to one of the bootstrap brokers.
+func (c *kafkaClient) anyConn() (*kafkaConn, error) {
+	var errs []string
+	for _, addr := range c.bootstrap {
+		conn, err := c.conn(addr)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, fmt.Errorf("cannot connect to any broker: %v", strings.Join(errs, ", "))
+}
+
+// topicMetadata returns partitions of the topic. The metadata is fetched
+// when it isn't cached or it doesn't have the topic.
+func (c *kafkaClient) topicMetadata(topic string) (*kafkaMetadata, []*kafkaPartitionMetadata, error) {
+	c.m.Lock()
+	md := c.metadata
+	c.m.Unlock()
+	if md != nil {
+		if ps, ok := md.topics[topic]; ok {
+			return md, ps, nil
+		}
+	}
+
+	conn, err := c.anyConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	md, err = conn.metadata([]string{topic})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := md.topicErrs[topic]; err != nil {
+		return nil, nil, fmt.Errorf("cannot get the metadata of topic '%v': %v", topic, err)
+	}
+	ps, ok := md.topics[topic]
+	if !ok || len(ps) == 0 {
+		return nil, nil, fmt.Errorf("topic '%v' doesn't have any partition", topic)
+	}
+	c.m.Lock()
+	c.metadata = md
+	c.m.Unlock()
+	return md, ps, nil
+}
+
+// partitions returns IDs of partitions of the topic in ascending order.
+func (c *kafkaClient) partitions(topic string) ([]int32, error) {
+	_, ps, err := c.topicMetadata(topic)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int32, len(ps))
+	for i, p := range ps {
+		ids[i] = p.id
+	}
+	return ids, nil
+}
+
+// leader returns a connection to the leader of the partition.
+func (c *kafkaClient) leader(topic string, partition int32) (*kafkaConn, error) {
+	md, ps, err := c.topicMetadata(topic)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ps {
+		if p.id != partition {
+			continue
+		}
+		if p.err != nil {
+			c.handleError(p.err)
+			return nil, fmt.Errorf("partition %v of topic '%v' isn't available: %v", partition, topic, p.err)
+		}
+		b, ok := md.brokers[p.leader]
+		if !ok {
+			c.invalidate()
+			return nil, fmt.Errorf("the leader of partition %v of topic '%v' is unknown", partition, topic)
+		}
+		return c.conn(b.addr)
+	}
+	c.invalidate()
+	return nil, fmt.Errorf("topic '%v' doesn't have partition %v", topic, partition)
+}
+
+// invalidate discards the cached metadata so that it's fetched again.
+func (c *kafkaClient) invalidate() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.metadata = nil
+}
+
+// handleError invalidates the metadata when the error means that it's out of
+// date.
+func (c *kafkaClient) handleError(err error) {
+	if ke, ok := err.(kafkaError); ok && ke.staleMetadata() {
+		c.invalidate()
+	}
+}
+
+func (c *kafkaClient) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.closed = true
+	for addr, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, addr)
+	}
+	return nil
+}
+
+// kafkaAssignor is the name of the partition assignment strategy used by
+// kafkaGroupMember. It's compatible with RangeAssignor of Java clients, so
+// that sources can be in the same group as other consumers using it.
+const kafkaAssignor = "range"
+
+// kafkaGroupMember is a member of a consumer group consuming a topic.
+type kafkaGroupMember struct {
+	client           *kafkaClient
+	group            string
+	topic            string
+	sessionTimeout   time.Duration
+	rebalanceTimeout time.Duration
+
+	coordinator *kafkaConn
+	memberID    string
+	generation  int32
+}
+
+func (g *kafkaGroupMember) findCoordinator() (*kafkaConn, error) {
+	if g.coordinator != nil {
+		g.coordinator.m.Lock()
+		broken := g.coordinator.broken
+		g.coordinator.m.Unlock()
+		if !broken {
+			return g.coordinator, nil
+		}
+	}
+	conn, err := g.client.anyConn()
+	if err != nil {
+		return nil, err
+	}
+	addr, err := conn.findCoordinator(g.group)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the coordinator of group '%v': %v", g.group, err)
+	}
+	if g.coordinator, err = g.client.conn(addr); err != nil {
+		return nil, err
+	}
+	return g.coordinator, nil
+}
+
+// coordinatorError forgets the coordinator when the error means that it has
+// moved.
+func (g *kafkaGroupMember) coordinatorError(err error) error {
+	if ke, ok := err.(kafkaError); ok && ke.staleMetadata() {
+		g.coordinator = nil
+	}
+	return err
+}
+
+// join joins the group and returns partitions assigned to the member.
+func (g *kafkaGroupMember) join() ([]int32, error) {
+	coord, err := g.findCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	meta := encodeKafkaSubscription([]string{g.topic})
+	res, err := coord.joinGroup(g.group, g.memberID, g.sessionTimeout, g.rebalanceTimeout,
+		map[string][]byte{kafkaAssignor: meta})
+	if err == kafkaErrUnknownMemberID && g.memberID != "" {
+		g.memberID = ""
+		res, err = coord.joinGroup(g.group, "", g.sessionTimeout, g.rebalanceTimeout,
+			map[string][]byte{kafkaAssignor: meta})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot join group '%v': %v", g.group, g.coordinatorError(err))
+	}
+	g.memberID = res.memberID
+	g.generation = res.generation
+
+	var assignments map[string][]byte
+	if res.leader == res.memberID {
+		if assignments, err = g.assign(res.members); err != nil {
+			return nil, err
+		}
+	}
+	a, err := coord.syncGroup(g.group, g.generation, g.memberID, assignments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sync group '%v': %v", g.group, g.coordinatorError(err))
+	}
+	return decodeKafkaAssignment(a, g.topic)
+}
+
+// assign computes assignments of all members by the range strategy: each
+// member subscribing a topic gets a contiguous range of its partitions in
+// the order of member IDs.
+func (g *kafkaGroupMember) assign(members map[string][]byte) (map[string][]byte, error) {
+	subscribers := map[string][]string{}
+	for id, meta := range members {
+		topics, err := decodeKafkaSubscription(meta)
+		if err != nil {
+			return nil, fmt.Errorf("the subscription of member '%v' is broken: %v", id, err)
+		}
+		for _, t := range topics {
+			subscribers[t] = append(subscribers[t], id)
+		}
+	}
+
+	assigned := map[string]map[string][]int32{}
+	for id := range members {
+		assigned[id] = map[string][]int32{}
+	}
+	for topic, ids := range subscribers {
+		ps, err := g.client.partitions(topic)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(ids)
+		n, rest := len(ps)/len(ids), len(ps)%len(ids)
+		start := 0
+		for i, id := range ids {
+			size := n
+			if i < rest {
+				size++
+			}
+			if size > 0 {
+				assigned[id][topic] = ps[start : start+size]
+			}
+			start += size
+		}
+	}
+
+	res := make(map[string][]byte, len(assigned))
+	for id, a := range assigned {
+		res[id] = encodeKafkaAssignment(a)
+	}
+	return res, nil
+}
+
+func (g *kafkaGroupMember) heartbeat() error {
+	coord, err := g.findCoordinator()
+	if err != nil {
+		return err
+	}
+	return g.coordinatorError(coord.heartbeat(g.group, g.generation, g.memberID))
+}
+
+func (g *kafkaGroupMember) commit(offsets map[int32]int64) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+	coord, err := g.findCoordinator()
+	if err != nil {
+		return err
+	}
+	return g.coordinatorError(coord.commitOffsets(g.group, g.generation, g.memberID, g.topic, offsets))
+}
+
+// committed returns offsets committed by the group.
+func (g *kafkaGroupMember) committed(partitions []int32) (map[int32]int64, error) {
+	coord, err := g.findCoordinator()
+	if err != nil {
+		return nil, err
+	}
+	res, err := coord.fetchOffsets(g.group, g.topic, partitions)
+	if err != nil {
+		return nil, g.coordinatorError(err)
+	}
+	return res, nil
+}
+
+// leave leaves the group so that partitions of the member are assigned to
+// other members without waiting for the session timeout.
+func (g *kafkaGroupMember) leave() error {
+	if g.memberID == "" {
+		return nil
+	}
+	coord, err := g.findCoordinator()
+	if err != nil {
+		return err
+	}
+	err = coord.leaveGroup(g.group, g.memberID)
+	g.memberID = ""
+	return err
+}
+
+// encodeKafkaSubscription encodes the subscription of a member in the
+// consumer protocol.
+func encodeKafkaSubscription(topics []string) []byte {
+	e := &kafkaEncoder{}
+	e.putInt16(0) // version
+	e.putArrayLen(len(topics))
+	for _, t := range topics {
+		e.putString(t)
+	}
+	e.putBytes(nil) // user_data
+	return e.Bytes()
+}
+
+// decodeKafkaSubscription returns topics in the subscription. Fields added
+// in newer versions are ignored.
+func decodeKafkaSubscription(b []byte) ([]string, error) {
+	d := &kafkaDecoder{b: b}
+	d.readInt16() // version
+	n := d.readArrayLen()
+	topics := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		topics = append(topics, d.readString())
+	}
+	return topics, d.err
+}
+
+func encodeKafkaAssignment(a map[string][]int32) []byte {
+	topics := make([]string, 0, len(a))
+	for t := range a {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	e := &kafkaEncoder{}
+	e.putInt16(0) // version
+	e.putArrayLen(len(topics))
+	for _, t := range topics {
+		e.putString(t)
+		e.putArrayLen(len(a[t]))
+		for _, p := range a[t] {
+			e.putInt32(p)
+		}
+	}
+	e.putBytes(nil) // user_data
+	return e.Bytes()
+}
+
+// decodeKafkaAssignment returns partitions of the topic in the assignment.
+// An empty assignment means that no partition is assigned.
+func decodeKafkaAssignment(b []byte, topic string) ([]int32, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	d := &kafkaDecoder{b: b}
+	d.readInt16() // version
+	var res []int32
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		t := d.readString()
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			p := d.readInt32()
+			if t == topic {
+				res = append(res, p)
+			}
+		}
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("the assignment is broken: %v", d.err)
+	}
+	return res, nil
+}
@@ -0,0 +1,1083 @@
+package kafka
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+)
+
+// This file implements the subset of the Kafka wire protocol used by the
+// kafka source and sink. Only non-flexible versions of APIs supported by
+// Kafka 2.1 and later, including 4.x, are used so that requests don't need
+// tagged fields. Records are always in the message format v2 (record
+// batches).
+
+// API keys of Kafka requests.
+const (
+	kafkaAPIProduce         int16 = 0
+	kafkaAPIFetch           int16 = 1
+	kafkaAPIListOffsets     int16 = 2
+	kafkaAPIMetadata        int16 = 3
+	kafkaAPIOffsetCommit    int16 = 8
+	kafkaAPIOffsetFetch     int16 = 9
+	kafkaAPIFindCoordinator int16 = 10
+	kafkaAPIJoinGroup       int16 = 11
+	kafkaAPIHeartbeat       int16 = 12
+	kafkaAPILeaveGroup      int16 = 13
+	kafkaAPISyncGroup       int16 = 14
+)
+
+// kafkaAPIVersions has the version of each API sent to brokers.
+var kafkaAPIVersions = map[int16]int16{
+	kafkaAPIProduce:         3,
+	kafkaAPIFetch:           4,
+	kafkaAPIListOffsets:     1,
+	kafkaAPIMetadata:        4,
+	kafkaAPIOffsetCommit:    2,
+	kafkaAPIOffsetFetch:     1,
+	kafkaAPIFindCoordinator: 1,
+	kafkaAPIJoinGroup:       2,
+	kafkaAPIHeartbeat:       1,
+	kafkaAPILeaveGroup:      1,
+	kafkaAPISyncGroup:       1,
+}
+
+const (
+	// kafkaMaxResponseSize is the maximum size of a response accepted.
+	kafkaMaxResponseSize = 256 * 1024 * 1024
+
+	// Special timestamps of ListOffsets requests.
+	kafkaLatestOffset   int64 = -1
+	kafkaEarliestOffset int64 = -2
+)
+
+// kafkaError is an error code returned from a broker.
+type kafkaError int16
+
+// Error codes handled by the client.
+const (
+	kafkaErrOffsetOutOfRange          kafkaError = 1
+	kafkaErrUnknownTopicOrPartition   kafkaError = 3
+	kafkaErrLeaderNotAvailable        kafkaError = 5
+	kafkaErrNotLeaderForPartition     kafkaError = 6
+	kafkaErrRequestTimedOut           kafkaError = 7
+	kafkaErrCoordinatorLoadInProgress kafkaError = 14
+	kafkaErrCoordinatorNotAvailable   kafkaError = 15
+	kafkaErrNotCoordinator            kafkaError = 16
+	kafkaErrIllegalGeneration         kafkaError = 22
+	kafkaErrUnknownMemberID           kafkaError = 25
+	kafkaErrRebalanceInProgress       kafkaError = 27
+)
+
+var kafkaErrorNames = map[kafkaError]string{
+	kafkaErrOffsetOutOfRange:          "OFFSET_OUT_OF_RANGE",
+	kafkaErrUnknownTopicOrPartition:   "UNKNOWN_TOPIC_OR_PARTITION",
+	kafkaErrLeaderNotAvailable:        "LEADER_NOT_AVAILABLE",
+	kafkaErrNotLeaderForPartition:     "NOT_LEADER_OR_FOLLOWER",
+	kafkaErrRequestTimedOut:           "REQUEST_TIMED_OUT",
+	kafkaErrCoordinatorLoadInProgress: "COORDINATOR_LOAD_IN_PROGRESS",
+	kafkaErrCoordinatorNotAvailable:   "COORDINATOR_NOT_AVAILABLE",
+	kafkaErrNotCoordinator:            "NOT_COORDINATOR",
+	kafkaErrIllegalGeneration:         "ILLEGAL_GENERATION",
+	kafkaErrUnknownMemberID:           "UNKNOWN_MEMBER_ID",
+	kafkaErrRebalanceInProgress:       "REBALANCE_IN_PROGRESS",
+}
+
+func (e kafkaError) Error() string {
+	if n, ok := kafkaErrorNames[e]; ok {
+		return fmt.Sprintf("kafka error %v (%v)", int16(e), n)
+	}
+	return fmt.Sprintf("kafka error %v", int16(e))
+}
+
+// staleMetadata returns true when the error means that the metadata of the
+// cluster cached by the client is out of date.
+func (e kafkaError) staleMetadata() bool {
+	switch e {
+	case kafkaErrUnknownTopicOrPartition, kafkaErrLeaderNotAvailable, kafkaErrNotLeaderForPartition,
+		kafkaErrCoordinatorNotAvailable, kafkaErrNotCoordinator:
+		return true
+	}
+	return false
+}
+
+// needsRejoin returns true when a member of a consumer group has to join the
+// group again.
+func (e kafkaError) needsRejoin() bool {
+	switch e {
+	case kafkaErrIllegalGeneration, kafkaErrUnknownMemberID, kafkaErrRebalanceInProgress:
+		return true
+	}
+	return false
+}
+
+func kafkaErrorOf(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	return kafkaError(code)
+}
+
+// kafkaEncoder encodes primitive types of the Kafka protocol.
+type kafkaEncoder struct {
+	bytes.Buffer
+}
+
+func (e *kafkaEncoder) putInt8(v int8) {
+	e.WriteByte(byte(v))
+}
+
+func (e *kafkaEncoder) putInt16(v int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	e.Write(b[:])
+}
+
+func (e *kafkaEncoder) putInt32(v int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(v))
+	e.Write(b[:])
+}
+
+func (e *kafkaEncoder) putInt64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	e.Write(b[:])
+}
+
+func (e *kafkaEncoder) putBool(v bool) {
+	if v {
+		e.putInt8(1)
+	} else {
+		e.putInt8(0)
+	}
+}
+
+func (e *kafkaEncoder) putString(s string) {
+	e.putInt16(int16(len(s)))
+	e.WriteString(s)
+}
+
+func (e *kafkaEncoder) putNullString() {
+	e.putInt16(-1)
+}
+
+// putBytes writes bytes. nil is written as null.
+func (e *kafkaEncoder) putBytes(b []byte) {
+	if b == nil {
+		e.putInt32(-1)
+		return
+	}
+	e.putInt32(int32(len(b)))
+	e.Write(b)
+}
+
+func (e *kafkaEncoder) putArrayLen(n int) {
+	e.putInt32(int32(n))
+}
+
+func (e *kafkaEncoder) putVarint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v)
+	e.Write(b[:n])
+}
+
+// putVarintBytes writes bytes prefixed by its length in varint as records
+// do. nil is written as null.
+func (e *kafkaEncoder) putVarintBytes(b []byte) {
+	if b == nil {
+		e.putVarint(-1)
+		return
+	}
+	e.putVarint(int64(len(b)))
+	e.Write(b)
+}
+
+// errKafkaTruncated is set to kafkaDecoder.err when a message is shorter
+// than expected.
+var errKafkaTruncated = errors.New("the kafka message is truncated")
+
+// kafkaDecoder decodes primitive types of the Kafka protocol. Once it fails
+// to read a value, err is set and all subsequent reads return zero values.
+type kafkaDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *kafkaDecoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.b) < n {
+		d.err = errKafkaTruncated
+		d.b = nil
+		return nil
+	}
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func (d *kafkaDecoder) readInt8() int8 {
+	b := d.take(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+func (d *kafkaDecoder) readInt16() int16 {
+	b := d.take(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *kafkaDecoder) readInt32() int32 {
+	b := d.take(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *kafkaDecoder) readInt64() int64 {
+	b := d.take(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *kafkaDecoder) readBool() bool {
+	return d.readInt8() != 0
+}
+
+// readString reads a string. null is read as an empty string.
+func (d *kafkaDecoder) readString() string {
+	n := d.readInt16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+// readBytes reads bytes. null is read as nil.
+func (d *kafkaDecoder) readBytes() []byte {
+	n := d.readInt32()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// readArrayLen reads the length of an array. null is read as 0. It fails
+// when the length is obviously larger than the rest of the message so that
+// a broken message doesn't allocate a huge slice.
+func (d *kafkaDecoder) readArrayLen() int {
+	n := d.readInt32()
+	if n < 0 {
+		return 0
+	}
+	if int(n) > len(d.b) {
+		d.err = errKafkaTruncated
+		return 0
+	}
+	return int(n)
+}
+
+func (d *kafkaDecoder) readVarint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errKafkaTruncated
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+// readVarintBytes reads bytes prefixed by its length in varint. null is read
+// as nil.
+func (d *kafkaDecoder) readVarintBytes() []byte {
+	n := d.readVarint()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// kafkaConn is a connection to a broker. Requests are sent one by one.
+type kafkaConn struct {
+	m             sync.Mutex
+	addr          string
+	conn          net.Conn
+	r             *bufio.Reader
+	clientID      string
+	timeout       time.Duration
+	correlationID int32
+	broken        bool
+}
+
+func dialKafka(addr, clientID string, timeout time.Duration) (*kafkaConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaConn{
+		addr:     addr,
+		conn:     conn,
+		r:        bufio.NewReader(conn),
+		clientID: clientID,
+		timeout:  timeout,
+	}, nil
+}
+
+// request sends a request and returns a decoder of the response body. wait
+// is added to the timeout for requests such as Fetch which can be blocked
+// in brokers. The connection must not be used after it returns an I/O
+// error.
+func (c *kafkaConn) request(apiKey int16, body *kafkaEncoder, wait time.Duration) (*kafkaDecoder, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.broken {
+		return nil, fmt.Errorf("the connection to %v is broken", c.addr)
+	}
+	res, err := c.roundTrip(apiKey, body, wait)
+	if err != nil {
+		c.broken = true
+		c.conn.Close()
+		return nil, fmt.Errorf("request to %v failed: %v", c.addr, err)
+	}
+	return res, nil
+}
+
+func (c *kafkaConn) roundTrip(apiKey int16, body *kafkaEncoder, wait time.Duration) (*kafkaDecoder, error) {
+	c.correlationID++
+	e := &kafkaEncoder{}
+	e.putInt32(0) // the size is set later
+	e.putInt16(apiKey)
+	e.putInt16(kafkaAPIVersions[apiKey])
+	e.putInt32(c.correlationID)
+	e.putString(c.clientID)
+	e.Write(body.Bytes())
+	b := e.Bytes()
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout + wait)); err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(b); err != nil {
+		return nil, err
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(c.r, size[:]); err != nil {
+		return nil, err
+	}
+	n := int32(binary.BigEndian.Uint32(size[:]))
+	if n < 4 || n > kafkaMaxResponseSize {
+		return nil, fmt.Errorf("invalid response size: %v", n)
+	}
+	res := make([]byte, n)
+	if _, err := io.ReadFull(c.r, res); err != nil {
+		return nil, err
+	}
+	d := &kafkaDecoder{b: res}
+	if id := d.readInt32(); id != c.correlationID {
+		return nil, fmt.Errorf("the correlation ID of the response is %v but %v was expected", id, c.correlationID)
+	}
+	return d, nil
+}
+
+func (c *kafkaConn) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.broken = true
+	return c.conn.Close()
+}
+
+// kafkaBroker is a broker in the metadata.
+type kafkaBroker struct {
+	id   int32
+	addr string
+}
+
+// kafkaPartitionMetadata is the metadata of a partition.
+type kafkaPartitionMetadata struct {
+	id     int32
+	leader int32
+	err    error
+}
+
+// kafkaMetadata is the metadata of the cluster.
+type kafkaMetadata struct {
+	brokers map[int32]*kafkaBroker
+	// topics has partitions of each topic sorted by their IDs.
+	topics    map[string][]*kafkaPartitionMetadata
+	topicErrs map[string]error
+}
+
+func (c *kafkaConn) metadata(topics []string) (*kafkaMetadata, error) {
+	e := &kafkaEncoder{}
+	e.putArrayLen(len(topics))
+	for _, t := range topics {
+		e.putString(t)
+	}
+	e.putBool(false) // allow_auto_topic_creation
+	d, err := c.request(kafkaAPIMetadata, e, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	md := &kafkaMetadata{
+		brokers:   map[int32]*kafkaBroker{},
+		topics:    map[string][]*kafkaPartitionMetadata{},
+		topicErrs: map[string]error{},
+	}
+	d.readInt32() // throttle_time_ms
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		b := &kafkaBroker{id: d.readInt32()}
+		host := d.readString()
+		port := d.readInt32()
+		d.readString() // rack
+		b.addr = net.JoinHostPort(host, fmt.Sprint(port))
+		md.brokers[b.id] = b
+	}
+	d.readString() // cluster_id
+	d.readInt32()  // controller_id
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		code := d.readInt16()
+		name := d.readString()
+		d.readBool() // is_internal
+		var ps []*kafkaPartitionMetadata
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			p := &kafkaPartitionMetadata{}
+			p.err = kafkaErrorOf(d.readInt16())
+			p.id = d.readInt32()
+			p.leader = d.readInt32()
+			for k, l := 0, d.readArrayLen(); k < l; k++ {
+				d.readInt32() // replica_nodes
+			}
+			for k, l := 0, d.readArrayLen(); k < l; k++ {
+				d.readInt32() // isr_nodes
+			}
+			ps = append(ps, p)
+		}
+		if err := kafkaErrorOf(code); err != nil {
+			md.topicErrs[name] = err
+			continue
+		}
+		sortKafkaPartitions(ps)
+		md.topics[name] = ps
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return md, nil
+}
+
+func sortKafkaPartitions(ps []*kafkaPartitionMetadata) {
+	for i := 1; i < len(ps); i++ {
+		for j := i; j > 0 && ps[j].id < ps[j-1].id; j-- {
+			ps[j], ps[j-1] = ps[j-1], ps[j]
+		}
+	}
+}
+
+// produce writes the record batch to the partition. It returns the offset of
+// the first record.
+func (c *kafkaConn) produce(topic string, partition int32, acks int16, timeout time.Duration,
+	batch []byte) (int64, error) {
+	e := &kafkaEncoder{}
+	e.putNullString() // transactional_id
+	e.putInt16(acks)
+	e.putInt32(int32(timeout / time.Millisecond))
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(1)
+	e.putInt32(partition)
+	e.putBytes(batch)
+	d, err := c.request(kafkaAPIProduce, e, timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var resErr error = errKafkaTruncated
+	var offset int64
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		d.readString() // name
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			d.readInt32() // index
+			resErr = kafkaErrorOf(d.readInt16())
+			offset = d.readInt64()
+			d.readInt64() // log_append_time_ms
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return offset, resErr
+}
+
+// kafkaFetchRequest is a partition to be fetched.
+type kafkaFetchRequest struct {
+	partition int32
+	offset    int64
+}
+
+// kafkaFetchResult is records fetched from a partition.
+type kafkaFetchResult struct {
+	partition     int32
+	err           error
+	highWatermark int64
+	records       []*kafkaRecord
+}
+
+func (c *kafkaConn) fetch(topic string, reqs []*kafkaFetchRequest, maxWait time.Duration,
+	maxBytes int32) ([]*kafkaFetchResult, error) {
+	e := &kafkaEncoder{}
+	e.putInt32(-1) // replica_id
+	e.putInt32(int32(maxWait / time.Millisecond))
+	e.putInt32(1)        // min_bytes
+	e.putInt32(maxBytes) // max_bytes
+	e.putInt8(0)         // isolation_level: READ_UNCOMMITTED
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(len(reqs))
+	for _, r := range reqs {
+		e.putInt32(r.partition)
+		e.putInt64(r.offset)
+		e.putInt32(maxBytes)
+	}
+	d, err := c.request(kafkaAPIFetch, e, maxWait)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*kafkaFetchResult
+	d.readInt32() // throttle_time_ms
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		d.readString() // topic
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			r := &kafkaFetchResult{
+				partition: d.readInt32(),
+			}
+			r.err = kafkaErrorOf(d.readInt16())
+			r.highWatermark = d.readInt64()
+			d.readInt64() // last_stable_offset
+			for k, l := 0, d.readArrayLen(); k < l; k++ {
+				d.readInt64() // producer_id
+				d.readInt64() // first_offset
+			}
+			records := d.readBytes()
+			if d.err != nil {
+				return nil, d.err
+			}
+			if r.err == nil {
+				if r.records, err = decodeKafkaRecordBatches(records); err != nil {
+					r.err = err
+				}
+			}
+			res = append(res, r)
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return res, nil
+}
+
+// listOffset returns the offset of the partition at the timestamp, which can
+// be kafkaLatestOffset or kafkaEarliestOffset.
+func (c *kafkaConn) listOffset(topic string, partition int32, timestamp int64) (int64, error) {
+	e := &kafkaEncoder{}
+	e.putInt32(-1) // replica_id
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(1)
+	e.putInt32(partition)
+	e.putInt64(timestamp)
+	d, err := c.request(kafkaAPIListOffsets, e, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	var resErr error = errKafkaTruncated
+	var offset int64
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		d.readString() // name
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			d.readInt32() // partition_index
+			resErr = kafkaErrorOf(d.readInt16())
+			d.readInt64() // timestamp
+			offset = d.readInt64()
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return offset, resErr
+}
+
+// findCoordinator returns the address of the coordinator of the group.
+func (c *kafkaConn) findCoordinator(group string) (string, error) {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putInt8(0) // key_type: GROUP
+	d, err := c.request(kafkaAPIFindCoordinator, e, 0)
+	if err != nil {
+		return "", err
+	}
+	d.readInt32() // throttle_time_ms
+	code := d.readInt16()
+	d.readString() // error_message
+	d.readInt32()  // node_id
+	host := d.readString()
+	port := d.readInt32()
+	if d.err != nil {
+		return "", d.err
+	}
+	if err := kafkaErrorOf(code); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, fmt.Sprint(port)), nil
+}
+
+// kafkaJoinGroupResult is the response of JoinGroup. members are only given
+// to the leader.
+type kafkaJoinGroupResult struct {
+	generation int32
+	protocol   string
+	leader     string
+	memberID   string
+	members    map[string][]byte
+}
+
+func (c *kafkaConn) joinGroup(group, memberID string, sessionTimeout, rebalanceTimeout time.Duration,
+	protocols map[string][]byte) (*kafkaJoinGroupResult, error) {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putInt32(int32(sessionTimeout / time.Millisecond))
+	e.putInt32(int32(rebalanceTimeout / time.Millisecond))
+	e.putString(memberID)
+	e.putString("consumer")
+	e.putArrayLen(len(protocols))
+	for name, md := range protocols {
+		e.putString(name)
+		e.putBytes(md)
+	}
+	// JoinGroup blocks until all members join
+	d, err := c.request(kafkaAPIJoinGroup, e, rebalanceTimeout)
+	if err != nil {
+		return nil, err
+	}
+	d.readInt32() // throttle_time_ms
+	code := d.readInt16()
+	res := &kafkaJoinGroupResult{
+		generation: d.readInt32(),
+		protocol:   d.readString(),
+		leader:     d.readString(),
+		memberID:   d.readString(),
+		members:    map[string][]byte{},
+	}
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		id := d.readString()
+		res.members[id] = d.readBytes()
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if err := kafkaErrorOf(code); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// syncGroup sends assignments computed by the leader, which is empty when
+// the member isn't the leader, and returns the assignment of the member.
+func (c *kafkaConn) syncGroup(group string, generation int32, memberID string,
+	assignments map[string][]byte) ([]byte, error) {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putInt32(generation)
+	e.putString(memberID)
+	e.putArrayLen(len(assignments))
+	for id, a := range assignments {
+		e.putString(id)
+		e.putBytes(a)
+	}
+	d, err := c.request(kafkaAPISyncGroup, e, 0)
+	if err != nil {
+		return nil, err
+	}
+	d.readInt32() // throttle_time_ms
+	code := d.readInt16()
+	a := d.readBytes()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if err := kafkaErrorOf(code); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (c *kafkaConn) heartbeat(group string, generation int32, memberID string) error {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putInt32(generation)
+	e.putString(memberID)
+	d, err := c.request(kafkaAPIHeartbeat, e, 0)
+	if err != nil {
+		return err
+	}
+	d.readInt32() // throttle_time_ms
+	code := d.readInt16()
+	if d.err != nil {
+		return d.err
+	}
+	return kafkaErrorOf(code)
+}
+
+func (c *kafkaConn) leaveGroup(group, memberID string) error {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putString(memberID)
+	d, err := c.request(kafkaAPILeaveGroup, e, 0)
+	if err != nil {
+		return err
+	}
+	d.readInt32() // throttle_time_ms
+	code := d.readInt16()
+	if d.err != nil {
+		return d.err
+	}
+	return kafkaErrorOf(code)
+}
+
+// commitOffsets commits offsets of partitions of the topic. Offsets are the
+// ones of the next records to be consumed.
+func (c *kafkaConn) commitOffsets(group string, generation int32, memberID, topic string,
+	offsets map[int32]int64) error {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putInt32(generation)
+	e.putString(memberID)
+	e.putInt64(-1) // retention_time_ms: the default of the broker
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(len(offsets))
+	for p, o := range offsets {
+		e.putInt32(p)
+		e.putInt64(o)
+		e.putNullString() // committed_metadata
+	}
+	d, err := c.request(kafkaAPIOffsetCommit, e, 0)
+	if err != nil {
+		return err
+	}
+	var resErr error
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		d.readString() // name
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			d.readInt32() // partition_index
+			if err := kafkaErrorOf(d.readInt16()); err != nil {
+				resErr = err
+			}
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	return resErr
+}
+
+// fetchOffsets returns offsets committed by the group. Partitions without
+// committed offsets are not included.
+func (c *kafkaConn) fetchOffsets(group, topic string, partitions []int32) (map[int32]int64, error) {
+	e := &kafkaEncoder{}
+	e.putString(group)
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(len(partitions))
+	for _, p := range partitions {
+		e.putInt32(p)
+	}
+	d, err := c.request(kafkaAPIOffsetFetch, e, 0)
+	if err != nil {
+		return nil, err
+	}
+	res := map[int32]int64{}
+	var resErr error
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		d.readString() // name
+		for j, m := 0, d.readArrayLen(); j < m; j++ {
+			p := d.readInt32()
+			o := d.readInt64()
+			d.readString() // metadata
+			if err := kafkaErrorOf(d.readInt16()); err != nil {
+				resErr = err
+				continue
+			}
+			if o >= 0 {
+				res[p] = o
+			}
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return res, resErr
+}
+
+// kafkaRecord is a record in a partition.
+type kafkaRecord struct {
+	offset    int64
+	timestamp time.Time
+	key       []byte
+	value     []byte
+	headers   []kafkaHeader
+}
+
+// kafkaHeader is a header of a record.
+type kafkaHeader struct {
+	key   string
+	value []byte
+}
+
+// Compression codecs of record batches.
+const (
+	kafkaCodecNone   int16 = 0
+	kafkaCodecGzip   int16 = 1
+	kafkaCodecSnappy int16 = 2
+	kafkaCodecLZ4    int16 = 3
+	kafkaCodecZstd   int16 = 4
+)
+
+var kafkaCodecs = map[string]int16{
+	bql.CompressionNone:   kafkaCodecNone,
+	bql.CompressionGzip:   kafkaCodecGzip,
+	bql.CompressionSnappy: kafkaCodecSnappy,
+	bql.CompressionZstd:   kafkaCodecZstd,
+}
+
+const (
+	kafkaBatchHeaderSize   = 61 // including base_offset and batch_length
+	kafkaBatchCodecMask    = 0x07
+	kafkaBatchLogAppend    = 0x08
+	kafkaBatchControl      = 0x20
+	kafkaBatchCRCOffset    = 17
+	kafkaBatchAttrsOffset  = 21
+	kafkaBatchLengthOffset = 8
+)
+
+var kafkaCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// encodeKafkaRecordBatch encodes records in a record batch. Offsets of the
+// records are assigned by the broker.
+func encodeKafkaRecordBatch(rs []*kafkaRecord, codec int16) ([]byte, error) {
+	base := rs[0].timestamp.UnixNano() / int64(time.Millisecond)
+	max := base
+	recs := &kafkaEncoder{}
+	for i, r := range rs {
+		ts := r.timestamp.UnixNano() / int64(time.Millisecond)
+		if ts > max {
+			max = ts
+		}
+		rec := &kafkaEncoder{}
+		rec.putInt8(0) // attributes
+		rec.putVarint(ts - base)
+		rec.putVarint(int64(i))
+		rec.putVarintBytes(r.key)
+		rec.putVarintBytes(r.value)
+		rec.putVarint(int64(len(r.headers)))
+		for _, h := range r.headers {
+			rec.putVarintBytes([]byte(h.key))
+			rec.putVarintBytes(h.value)
+		}
+		recs.putVarint(int64(rec.Len()))
+		recs.Write(rec.Bytes())
+	}
+	payload, err := kafkaCompress(codec, recs.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	e := &kafkaEncoder{}
+	e.putInt64(0)  // base_offset
+	e.putInt32(0)  // batch_length is set later
+	e.putInt32(-1) // partition_leader_epoch
+	e.putInt8(2)   // magic
+	e.putInt32(0)  // crc is set later
+	e.putInt16(codec)
+	e.putInt32(int32(len(rs) - 1)) // last_offset_delta
+	e.putInt64(base)
+	e.putInt64(max)
+	e.putInt64(-1) // producer_id
+	e.putInt16(-1) // producer_epoch
+	e.putInt32(-1) // base_sequence
+	e.putInt32(int32(len(rs)))
+	e.Write(payload)
+	b := e.Bytes()
+	binary.BigEndian.PutUint32(b[kafkaBatchLengthOffset:], uint32(len(b)-kafkaBatchLengthOffset-4))
+	binary.BigEndian.PutUint32(b[kafkaBatchCRCOffset:], crc32.Checksum(b[kafkaBatchAttrsOffset:], kafkaCRCTable))
+	return b, nil
+}
+
+// decodeKafkaRecordBatches decodes record batches returned from Fetch. A
+// partial batch at the end, which brokers can return when the batch exceeds
+// the size limit, is ignored. Control batches of transactions are skipped.
+func decodeKafkaRecordBatches(b []byte) ([]*kafkaRecord, error) {
+	var res []*kafkaRecord
+	for len(b) >= kafkaBatchHeaderSize {
+		baseOffset := int64(binary.BigEndian.Uint64(b))
+		size := int(int32(binary.BigEndian.Uint32(b[kafkaBatchLengthOffset:]))) + kafkaBatchLengthOffset + 4
+		if size < kafkaBatchHeaderSize {
+			return nil, fmt.Errorf("invalid size of a record batch: %v", size)
+		}
+		if len(b) < size {
+			break
+		}
+		batch := b[:size]
+		b = b[size:]
+
+		if magic := batch[16]; magic != 2 {
+			return nil, fmt.Errorf("unsupported message format: v%v", magic)
+		}
+		if crc := binary.BigEndian.Uint32(batch[kafkaBatchCRCOffset:]); crc != crc32.Checksum(batch[kafkaBatchAttrsOffset:], kafkaCRCTable) {
+			return nil, errors.New("the CRC of a record batch doesn't match")
+		}
+		d := &kafkaDecoder{b: batch[kafkaBatchAttrsOffset:]}
+		attrs := d.readInt16()
+		d.readInt32() // last_offset_delta
+		baseTimestamp := d.readInt64()
+		maxTimestamp := d.readInt64()
+		d.readInt64() // producer_id
+		d.readInt16() // producer_epoch
+		d.readInt32() // base_sequence
+		n := int(d.readInt32())
+		if attrs&kafkaBatchControl != 0 {
+			continue
+		}
+		payload, err := kafkaDecompress(int16(attrs&kafkaBatchCodecMask), d.b)
+		if err != nil {
+			return nil, err
+		}
+
+		rd := &kafkaDecoder{b: payload}
+		for i := 0; i < n; i++ {
+			rec := &kafkaDecoder{b: rd.take(int(rd.readVarint()))}
+			rec.readInt8() // attributes
+			tsDelta := rec.readVarint()
+			r := &kafkaRecord{
+				offset: baseOffset + rec.readVarint(),
+			}
+			ts := baseTimestamp + tsDelta
+			if attrs&kafkaBatchLogAppend != 0 {
+				ts = maxTimestamp
+			}
+			r.timestamp = time.Unix(0, ts*int64(time.Millisecond))
+			r.key = rec.readVarintBytes()
+			r.value = rec.readVarintBytes()
+			for j, m := 0, int(rec.readVarint()); j < m && rec.err == nil; j++ {
+				r.headers = append(r.headers, kafkaHeader{
+					key:   string(rec.readVarintBytes()),
+					value: rec.readVarintBytes(),
+				})
+			}
+			if rec.err != nil || rd.err != nil {
+				return nil, errors.New("cannot decode a record: the record batch is broken")
+			}
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+// kafkaXerialMagic is the header of snappy compressed data in the framing
+// format of snappy-java, which is used by Java clients.
+var kafkaXerialMagic = []byte("\x82SNAPPY\x00")
+
+func kafkaCompress(codec int16, b []byte) ([]byte, error) {
+	switch codec {
+	case kafkaCodecNone:
+		return b, nil
+	case kafkaCodecGzip:
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		w.Write(b)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case kafkaCodecSnappy:
+		return snappy.Encode(nil, b), nil
+	case kafkaCodecZstd:
+		w, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer w.Close()
+		return w.EncodeAll(b, nil), nil
+	}
+	return nil, fmt.Errorf("unsupported compression codec: %v", codec)
+}
+
+func kafkaDecompress(codec int16, b []byte) ([]byte, error) {
+	switch codec {
+	case kafkaCodecNone:
+		return b, nil
+	case kafkaCodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	case kafkaCodecSnappy:
+		if !bytes.HasPrefix(b, kafkaXerialMagic) {
+			return snappy.Decode(nil, b)
+		}
+		if len(b) < len(kafkaXerialMagic)+8 {
+			return nil, errKafkaTruncated
+		}
+		// magic, version, and compatible version are followed by blocks
+		// prefixed by their sizes.
+		d := &kafkaDecoder{b: b[len(kafkaXerialMagic)+8:]}
+		var res []byte
+		for len(d.b) > 0 && d.err == nil {
+			block, err := snappy.Decode(nil, d.readBytes())
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, block...)
+		}
+		return res, d.err
+	case kafkaCodecZstd:
+		r, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return r.DecodeAll(b, nil)
+	case kafkaCodecLZ4:
+		return nil, errors.New("lz4 compression isn't supported")
+	}
+	return nil, fmt.Errorf("unsupported compression codec: %v", codec)
+}
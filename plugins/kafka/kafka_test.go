@@ -0,0 +1,792 @@
+package kafka
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type fakeKafkaBatch struct {
+	base  int64
+	count int64
+	b     []byte
+}
+
+// fakeKafkaBroker is a single broker cluster having one topic. It only
+// implements requests sent by kafkaClient, and its consumer group only
+// supports one member at a time.
+type fakeKafkaBroker struct {
+	l     net.Listener
+	topic string
+
+	m          sync.Mutex
+	conns      map[net.Conn]struct{}
+	partitions [][]*fakeKafkaBatch
+	generation int32
+	memberID   string
+	committed  map[int32]int64
+	joins      int
+}
+
+func newFakeKafkaBroker(topic string, partitions int) (*fakeKafkaBroker, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &fakeKafkaBroker{
+		l:          l,
+		topic:      topic,
+		conns:      map[net.Conn]struct{}{},
+		partitions: make([][]*fakeKafkaBatch, partitions),
+		committed:  map[int32]int64{},
+	}
+	go b.serve()
+	return b, nil
+}
+
+func (b *fakeKafkaBroker) addr() string {
+	return b.l.Addr().String()
+}
+
+func (b *fakeKafkaBroker) serve() {
+	for {
+		conn, err := b.l.Accept()
+		if err != nil {
+			return
+		}
+		b.m.Lock()
+		b.conns[conn] = struct{}{}
+		b.m.Unlock()
+		go b.handle(conn)
+	}
+}
+
+// dropConnections closes all connections from clients.
+func (b *fakeKafkaBroker) dropConnections() {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for c := range b.conns {
+		c.Close()
+	}
+}
+
+func (b *fakeKafkaBroker) Close() {
+	b.l.Close()
+	b.dropConnections()
+}
+
+func (b *fakeKafkaBroker) numRecords() int64 {
+	b.m.Lock()
+	defer b.m.Unlock()
+	var n int64
+	for _, bs := range b.partitions {
+		for _, batch := range bs {
+			n += batch.count
+		}
+	}
+	return n
+}
+
+func (b *fakeKafkaBroker) committedOffsets() map[int32]int64 {
+	b.m.Lock()
+	defer b.m.Unlock()
+	res := map[int32]int64{}
+	for p, o := range b.committed {
+		res[p] = o
+	}
+	return res
+}
+
+func (b *fakeKafkaBroker) handle(conn net.Conn) {
+	defer func() {
+		b.m.Lock()
+		delete(b.conns, conn)
+		b.m.Unlock()
+		conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r, req); err != nil {
+			return
+		}
+		d := &kafkaDecoder{b: req}
+		apiKey := d.readInt16()
+		d.readInt16() // version
+		correlationID := d.readInt32()
+		d.readString() // client_id
+
+		e := &kafkaEncoder{}
+		e.putInt32(0)
+		e.putInt32(correlationID)
+		switch apiKey {
+		case kafkaAPIMetadata:
+			b.metadata(d, e)
+		case kafkaAPIProduce:
+			b.produce(d, e)
+		case kafkaAPIFetch:
+			b.fetch(d, e)
+		case kafkaAPIListOffsets:
+			b.listOffsets(d, e)
+		case kafkaAPIFindCoordinator:
+			b.findCoordinator(d, e)
+		case kafkaAPIJoinGroup:
+			b.joinGroup(d, e)
+		case kafkaAPISyncGroup:
+			b.syncGroup(d, e)
+		case kafkaAPIHeartbeat, kafkaAPILeaveGroup:
+			b.heartbeat(apiKey, d, e)
+		case kafkaAPIOffsetCommit:
+			b.offsetCommit(d, e)
+		case kafkaAPIOffsetFetch:
+			b.offsetFetch(d, e)
+		default:
+			return
+		}
+		res := e.Bytes()
+		binary.BigEndian.PutUint32(res, uint32(len(res)-4))
+		if _, err := conn.Write(res); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeKafkaBroker) hostPort() (string, int32) {
+	host, port, _ := net.SplitHostPort(b.addr())
+	p, _ := strconv.Atoi(port)
+	return host, int32(p)
+}
+
+func (b *fakeKafkaBroker) metadata(d *kafkaDecoder, e *kafkaEncoder) {
+	host, port := b.hostPort()
+	e.putInt32(0) // throttle_time_ms
+	e.putArrayLen(1)
+	e.putInt32(1)
+	e.putString(host)
+	e.putInt32(port)
+	e.putNullString()
+	e.putString("fake")
+	e.putInt32(1)
+
+	var topics []string
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		topics = append(topics, d.readString())
+	}
+	e.putArrayLen(len(topics))
+	for _, t := range topics {
+		if t != b.topic {
+			e.putInt16(int16(kafkaErrUnknownTopicOrPartition))
+			e.putString(t)
+			e.putBool(false)
+			e.putArrayLen(0)
+			continue
+		}
+		e.putInt16(0)
+		e.putString(t)
+		e.putBool(false)
+		e.putArrayLen(len(b.partitions))
+		for p := range b.partitions {
+			e.putInt16(0)
+			e.putInt32(int32(p))
+			e.putInt32(1)
+			e.putArrayLen(1)
+			e.putInt32(1)
+			e.putArrayLen(1)
+			e.putInt32(1)
+		}
+	}
+}
+
+func (b *fakeKafkaBroker) produce(d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readString() // transactional_id
+	d.readInt16()  // acks
+	d.readInt32()  // timeout
+	d.readArrayLen()
+	topic := d.readString()
+	d.readArrayLen()
+	p := d.readInt32()
+	batch := append([]byte(nil), d.readBytes()...)
+
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(1)
+	e.putInt32(p)
+	if topic != b.topic || int(p) >= len(b.partitions) {
+		e.putInt16(int16(kafkaErrUnknownTopicOrPartition))
+		e.putInt64(-1)
+	} else {
+		base := b.endOffset(p)
+		binary.BigEndian.PutUint64(batch, uint64(base))
+		count := int64(binary.BigEndian.Uint32(batch[kafkaBatchHeaderSize-4:]))
+		b.partitions[p] = append(b.partitions[p], &fakeKafkaBatch{base: base, count: count, b: batch})
+		e.putInt16(0)
+		e.putInt64(base)
+	}
+	e.putInt64(-1) // log_append_time_ms
+	e.putInt32(0)  // throttle_time_ms
+}
+
+func (b *fakeKafkaBroker) endOffset(p int32) int64 {
+	bs := b.partitions[p]
+	if len(bs) == 0 {
+		return 0
+	}
+	last := bs[len(bs)-1]
+	return last.base + last.count
+}
+
+func (b *fakeKafkaBroker) fetch(d *kafkaDecoder, e *kafkaEncoder) {
+	d.readInt32() // replica_id
+	maxWait := time.Duration(d.readInt32()) * time.Millisecond
+	d.readInt32() // min_bytes
+	d.readInt32() // max_bytes
+	d.readInt8()  // isolation_level
+	d.readArrayLen()
+	topic := d.readString()
+	reqs := map[int32]int64{}
+	var order []int32
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		p := d.readInt32()
+		reqs[p] = d.readInt64()
+		d.readInt32() // partition_max_bytes
+		order = append(order, p)
+	}
+
+	deadline := time.Now().Add(maxWait)
+	for {
+		b.m.Lock()
+		available := false
+		for p, o := range reqs {
+			if o < b.endOffset(p) {
+				available = true
+			}
+		}
+		if available || time.Now().After(deadline) {
+			break
+		}
+		b.m.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	defer b.m.Unlock()
+
+	e.putInt32(0) // throttle_time_ms
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(len(order))
+	for _, p := range order {
+		e.putInt32(p)
+		end := b.endOffset(p)
+		if reqs[p] > end {
+			e.putInt16(int16(kafkaErrOffsetOutOfRange))
+		} else {
+			e.putInt16(0)
+		}
+		e.putInt64(end)
+		e.putInt64(end)
+		e.putArrayLen(0)
+		var records []byte
+		for _, batch := range b.partitions[p] {
+			if batch.base+batch.count > reqs[p] {
+				records = append(records, batch.b...)
+			}
+		}
+		e.putBytes(records)
+	}
+}
+
+func (b *fakeKafkaBroker) listOffsets(d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readInt32() // replica_id
+	d.readArrayLen()
+	topic := d.readString()
+	d.readArrayLen()
+	p := d.readInt32()
+	ts := d.readInt64()
+
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(1)
+	e.putInt32(p)
+	e.putInt16(0)
+	e.putInt64(-1)
+	if ts == kafkaEarliestOffset {
+		e.putInt64(0)
+	} else {
+		e.putInt64(b.endOffset(p))
+	}
+}
+
+func (b *fakeKafkaBroker) findCoordinator(d *kafkaDecoder, e *kafkaEncoder) {
+	host, port := b.hostPort()
+	e.putInt32(0) // throttle_time_ms
+	e.putInt16(0)
+	e.putNullString()
+	e.putInt32(1)
+	e.putString(host)
+	e.putInt32(port)
+}
+
+func (b *fakeKafkaBroker) joinGroup(d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readString() // group
+	d.readInt32()  // session_timeout_ms
+	d.readInt32()  // rebalance_timeout_ms
+	d.readString() // member_id
+	d.readString() // protocol_type
+	d.readArrayLen()
+	protocol := d.readString()
+	meta := d.readBytes()
+
+	b.joins++
+	b.generation++
+	b.memberID = fmt.Sprintf("member-%v", b.joins)
+	e.putInt32(0) // throttle_time_ms
+	e.putInt16(0)
+	e.putInt32(b.generation)
+	e.putString(protocol)
+	e.putString(b.memberID)
+	e.putString(b.memberID)
+	e.putArrayLen(1)
+	e.putString(b.memberID)
+	e.putBytes(meta)
+}
+
+func (b *fakeKafkaBroker) syncGroup(d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readString() // group
+	generation := d.readInt32()
+	memberID := d.readString()
+	var assignment []byte
+	for i, n := 0, d.readArrayLen(); i < n; i++ {
+		id := d.readString()
+		a := d.readBytes()
+		if id == memberID {
+			assignment = a
+		}
+	}
+	e.putInt32(0) // throttle_time_ms
+	if generation != b.generation || memberID != b.memberID {
+		e.putInt16(int16(kafkaErrIllegalGeneration))
+	} else {
+		e.putInt16(0)
+	}
+	e.putBytes(assignment)
+}
+
+func (b *fakeKafkaBroker) heartbeat(apiKey int16, d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readString() // group
+	code := int16(0)
+	if apiKey == kafkaAPIHeartbeat {
+		if d.readInt32() != b.generation {
+			code = int16(kafkaErrIllegalGeneration)
+		}
+	}
+	if d.readString() != b.memberID {
+		code = int16(kafkaErrUnknownMemberID)
+	} else if apiKey == kafkaAPILeaveGroup {
+		b.memberID = ""
+	}
+	e.putInt32(0) // throttle_time_ms
+	e.putInt16(code)
+}
+
+func (b *fakeKafkaBroker) offsetCommit(d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readString() // group
+	generation := d.readInt32()
+	memberID := d.readString()
+	d.readInt64() // retention_time_ms
+	code := int16(0)
+	if generation != b.generation || memberID != b.memberID {
+		code = int16(kafkaErrIllegalGeneration)
+	}
+
+	e.putArrayLen(1)
+	d.readArrayLen()
+	e.putString(d.readString())
+	n := d.readArrayLen()
+	e.putArrayLen(n)
+	for i := 0; i < n; i++ {
+		p := d.readInt32()
+		o := d.readInt64()
+		d.readString() // metadata
+		if code == 0 {
+			b.committed[p] = o
+		}
+		e.putInt32(p)
+		e.putInt16(code)
+	}
+}
+
+func (b *fakeKafkaBroker) offsetFetch(d *kafkaDecoder, e *kafkaEncoder) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	d.readString() // group
+	e.putArrayLen(1)
+	d.readArrayLen()
+	e.putString(d.readString())
+	n := d.readArrayLen()
+	e.putArrayLen(n)
+	for i := 0; i < n; i++ {
+		p := d.readInt32()
+		e.putInt32(p)
+		if o, ok := b.committed[p]; ok {
+			e.putInt64(o)
+		} else {
+			e.putInt64(-1)
+		}
+		e.putNullString()
+		e.putInt16(0)
+	}
+}
+
+func TestKafkaSinkAndSource(t *testing.T) {
+	Convey("Given a kafka broker having a topic with 3 partitions", t, func() {
+		broker, err := newFakeKafkaBroker("readings", 3)
+		So(err, ShouldBeNil)
+		Reset(broker.Close)
+		ctx := core.NewContext(nil)
+
+		Convey("When writing tuples to a kafka sink", func() {
+			sink, err := createKafkaSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+				"brokers":     data.String(broker.addr()),
+				"topic":       data.String("readings"),
+				"key_field":   data.String("id"),
+				"compression": data.String("gzip"),
+			})
+			So(err, ShouldBeNil)
+			ts := time.Date(2016, 1, 2, 3, 4, 5, 6000000, time.UTC)
+			for i := 0; i < 6; i++ {
+				tu := core.NewTuple(data.Map{
+					"id":    data.String(fmt.Sprintf("sensor%v", i%2)),
+					"value": data.Int(i),
+				})
+				tu.Timestamp = ts
+				So(sink.Write(ctx, tu), ShouldBeNil)
+			}
+			So(sink.Close(ctx), ShouldBeNil)
+
+			Convey("Then the broker should have all records", func() {
+				So(broker.numRecords(), ShouldEqual, 6)
+			})
+
+			Convey("Then records having the same key should be in the same partition", func() {
+				expected := make([]int, 3)
+				for _, k := range []string{"sensor0", "sensor1"} {
+					expected[(kafkaMurmur2([]byte(k))&0x7fffffff)%3] += 3
+				}
+				broker.m.Lock()
+				defer broker.m.Unlock()
+				for p, bs := range broker.partitions {
+					So(len(bs), ShouldEqual, expected[p])
+				}
+			})
+
+			Convey("And a kafka source in a consumer group reads the topic", func() {
+				params := data.Map{
+					"brokers":        data.Array{data.String(broker.addr())},
+					"topic":          data.String("readings"),
+					"group_id":       data.String("test"),
+					"start_offset":   data.String("earliest"),
+					"fetch_max_wait": data.String("20ms"),
+				}
+				src, err := createKafkaSource(ctx, &bql.IOParams{Name: "in"}, params)
+				So(err, ShouldBeNil)
+				w := &tupleCollectorSink{}
+				w.c = sync.NewCond(&w.m)
+				ch := make(chan error, 1)
+				go func() {
+					ch <- src.GenerateStream(ctx, w)
+				}()
+				w.Wait(6)
+
+				Convey("Then it should receive all tuples with their metadata", func() {
+					So(src.Stop(ctx), ShouldBeNil)
+					So(<-ch, ShouldBeNil)
+					So(w.len(), ShouldEqual, 6)
+					values := map[int64]bool{}
+					w.forEachTuple(func(tu *core.Tuple) {
+						v, _ := data.AsInt(tu.Data["value"])
+						values[v] = true
+						So(tu.Timestamp.Equal(ts), ShouldBeTrue)
+						So(tu.Data["id"], ShouldEqual, tu.Metadata["kafka_key"])
+						So(tu.Metadata["kafka_topic"], ShouldEqual, data.String("readings"))
+						So(tu.Metadata, ShouldContainKey, "kafka_partition")
+						So(tu.Metadata, ShouldContainKey, "kafka_offset")
+					})
+					So(values, ShouldHaveLength, 6)
+				})
+
+				Convey("Then stopping it should commit offsets", func() {
+					So(src.Stop(ctx), ShouldBeNil)
+					So(<-ch, ShouldBeNil)
+					var sum int64
+					for _, o := range broker.committedOffsets() {
+						sum += o
+					}
+					So(sum, ShouldEqual, 6)
+
+					Convey("And another source in the group should resume from the committed offsets", func() {
+						sink, err := createKafkaSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+							"brokers": data.String(broker.addr()),
+							"topic":   data.String("readings"),
+						})
+						So(err, ShouldBeNil)
+						So(sink.Write(ctx, core.NewTuple(data.Map{"value": data.Int(100)})), ShouldBeNil)
+						So(sink.Close(ctx), ShouldBeNil)
+
+						src, err := createKafkaSource(ctx, &bql.IOParams{Name: "in"}, params)
+						So(err, ShouldBeNil)
+						w := &tupleCollectorSink{}
+						w.c = sync.NewCond(&w.m)
+						go func() {
+							ch <- src.GenerateStream(ctx, w)
+						}()
+						w.Wait(1)
+						So(src.Stop(ctx), ShouldBeNil)
+						So(<-ch, ShouldBeNil)
+						So(w.len(), ShouldEqual, 1)
+						So(w.get(0).Data["value"], ShouldEqual, data.Int(100))
+					})
+				})
+
+				Convey("Then it should not receive records while it's paused", func() {
+					Reset(func() {
+						src.Stop(ctx)
+						<-ch
+					})
+					rs := src.(core.Resumable)
+					So(rs.Pause(ctx), ShouldBeNil)
+					sink, err := createKafkaSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+						"brokers": data.String(broker.addr()),
+						"topic":   data.String("readings"),
+					})
+					So(err, ShouldBeNil)
+					So(sink.Write(ctx, core.NewTuple(data.Map{"value": data.Int(100)})), ShouldBeNil)
+					So(sink.Close(ctx), ShouldBeNil)
+					time.Sleep(100 * time.Millisecond)
+					So(w.len(), ShouldEqual, 6)
+
+					Convey("And it should receive them after it's resumed", func() {
+						So(rs.Resume(ctx), ShouldBeNil)
+						w.Wait(7)
+						So(w.get(6).Data["value"], ShouldEqual, data.Int(100))
+					})
+				})
+
+				Convey("Then it should reconnect when connections are lost", func() {
+					Reset(func() {
+						src.Stop(ctx)
+						<-ch
+					})
+					broker.dropConnections()
+					sink, err := createKafkaSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+						"brokers": data.String(broker.addr()),
+						"topic":   data.String("readings"),
+					})
+					So(err, ShouldBeNil)
+					So(sink.Write(ctx, core.NewTuple(data.Map{"value": data.Int(100)})), ShouldBeNil)
+					So(sink.Close(ctx), ShouldBeNil)
+					w.Wait(7)
+					So(w.get(6).Data["value"], ShouldEqual, data.Int(100))
+				})
+			})
+		})
+
+		Convey("When a kafka source without a group reads malformed records", func() {
+			src, err := createKafkaSource(ctx, &bql.IOParams{Name: "in"}, data.Map{
+				"brokers":        data.String(broker.addr()),
+				"topic":          data.String("readings"),
+				"start_offset":   data.String("earliest"),
+				"fetch_max_wait": data.String("20ms"),
+			})
+			So(err, ShouldBeNil)
+			c, err := dialKafka(broker.addr(), "test", time.Second)
+			So(err, ShouldBeNil)
+			defer c.Close()
+			for _, v := range []string{`{"a":1}`, `{broken`, `{"a":3}`} {
+				batch, err := encodeKafkaRecordBatch([]*kafkaRecord{{
+					timestamp: time.Now(),
+					value:     []byte(v),
+				}}, kafkaCodecNone)
+				So(err, ShouldBeNil)
+				_, err = c.produce("readings", 0, -1, time.Second, batch)
+				So(err, ShouldBeNil)
+			}
+
+			w := &tupleCollectorSink{}
+			w.c = sync.NewCond(&w.m)
+			ch := make(chan error, 1)
+			go func() {
+				ch <- src.GenerateStream(ctx, w)
+			}()
+			w.Wait(2)
+			So(src.Stop(ctx), ShouldBeNil)
+			So(<-ch, ShouldBeNil)
+
+			Convey("Then it should skip them", func() {
+				So(w.len(), ShouldEqual, 2)
+				So(w.get(0).Data["a"], ShouldEqual, data.Int(1))
+				So(w.get(1).Data["a"], ShouldEqual, data.Int(3))
+			})
+
+			Convey("Then it should not commit offsets", func() {
+				So(broker.committedOffsets(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestKafkaParams(t *testing.T) {
+	Convey("Given a context", t, func() {
+		ctx := core.NewContext(nil)
+		params := data.Map{
+			"brokers": data.String("localhost:9092, localhost:9093"),
+			"topic":   data.String("t"),
+		}
+
+		Convey("When creating a source without brokers", func() {
+			delete(params, "brokers")
+			_, err := createKafkaSource(ctx, &bql.IOParams{}, params)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a source with an invalid commit_mode", func() {
+			params["commit_mode"] = data.String("always")
+			_, err := createKafkaSource(ctx, &bql.IOParams{}, params)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a source with the avro format without a registry", func() {
+			params["format"] = data.String("avro")
+			_, err := createKafkaSource(ctx, &bql.IOParams{}, params)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a sink with acks=0", func() {
+			params["acks"] = data.Int(0)
+			_, err := createKafkaSink(ctx, &bql.IOParams{}, params)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a sink with unsupported compression", func() {
+			params["compression"] = data.String("lz4")
+			_, err := createKafkaSink(ctx, &bql.IOParams{}, params)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When parsing brokers", func() {
+			bs, err := kafkaBrokers(params["brokers"])
+
+			Convey("Then it should split them by commas", func() {
+				So(err, ShouldBeNil)
+				So(bs, ShouldResemble, []string{"localhost:9092", "localhost:9093"})
+			})
+		})
+	})
+}
+
+func TestKafkaMurmur2(t *testing.T) {
+	Convey("Given keys", t, func() {
+		cases := map[string]int32{
+			"21":                         -973932308,
+			"foobar":                     -790332482,
+			"a-little-bit-long-string":   -985981536,
+			"a-little-bit-longer-string": -1486304829,
+			"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+			"abc": 479470107,
+		}
+
+		Convey("Then murmur2 should return the same values as Java clients", func() {
+			for k, v := range cases {
+				So(kafkaMurmur2([]byte(k)), ShouldEqual, v)
+			}
+		})
+	})
+}
+
+// tupleCollectorSink collects tuples written to it.
+type tupleCollectorSink struct {
+	Tuples []*core.Tuple
+	m      sync.Mutex
+	c      *sync.Cond
+}
+
+func (s *tupleCollectorSink) Write(ctx *core.Context, t *core.Tuple) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.Tuples = append(s.Tuples, t)
+	s.c.Broadcast()
+	return nil
+}
+
+// Wait waits until the collector receives at least n tuples.
+func (s *tupleCollectorSink) Wait(n int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for len(s.Tuples) < n {
+		s.c.Wait()
+	}
+}
+
+func (s *tupleCollectorSink) get(n int) *core.Tuple {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.Tuples[n]
+}
+
+func (s *tupleCollectorSink) len() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return len(s.Tuples)
+}
+
+func (s *tupleCollectorSink) forEachTuple(f func(*core.Tuple)) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for _, t := range s.Tuples {
+		f(t)
+	}
+}
+
+func (s *tupleCollectorSink) Close(ctx *core.Context) error {
+	return nil
+}
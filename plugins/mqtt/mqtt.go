@@ -0,0 +1,538 @@
+// Package mqtt provides the "mqtt" source and sink, which subscribe and
+// publish messages of MQTT 3.1.1 brokers. The package registers them when
+// it's imported, so add it to the plugins section of build.yaml to use them:
+//
+//	plugins:
+//	  - gopkg.in/sensorbee/sensorbee.v0/plugins/mqtt
+package mqtt
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Formats of payloads of the mqtt source and sink.
+const (
+	// mqttFormatJSON decodes a payload as a JSON object.
+	mqttFormatJSON = "json"
+
+	// mqttFormatRaw puts a payload in the "payload" field as a blob.
+	mqttFormatRaw = "raw"
+)
+
+// mqttTopics returns topic filters given as an array or a string.
+func mqttTopics(v data.Value) ([]string, error) {
+	var res []string
+	switch v.Type() {
+	case data.TypeString:
+		s, _ := data.AsString(v)
+		res = append(res, s)
+	case data.TypeArray:
+		a, _ := data.AsArray(v)
+		for _, e := range a {
+			t, err := data.AsString(e)
+			if err != nil {
+				return nil, fmt.Errorf("topics must be strings: %v", err)
+			}
+			res = append(res, t)
+		}
+	default:
+		return nil, errors.New("topics must be a string or an array of strings")
+	}
+	if len(res) == 0 {
+		return nil, errors.New("topics must not be empty")
+	}
+	for _, t := range res {
+		if err := validateMQTTTopic(t, true); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// validateMQTTTopic validates a topic name, or a topic filter when filter
+// is true.
+func validateMQTTTopic(t string, filter bool) error {
+	if t == "" {
+		return errors.New("a topic must not be empty")
+	}
+	if len(t) > 65535 {
+		return errors.New("a topic is too long")
+	}
+	levels := strings.Split(t, "/")
+	for i, l := range levels {
+		if !strings.ContainsAny(l, "+#") {
+			continue
+		}
+		if !filter {
+			return fmt.Errorf("a topic to publish cannot have wildcards: %v", t)
+		}
+		if (l == "#" && i == len(levels)-1) || l == "+" {
+			continue
+		}
+		return fmt.Errorf("a wildcard must occupy an entire level of the topic filter: %v", t)
+	}
+	return nil
+}
+
+func validateMQTTQoS(qos int) error {
+	if qos < 0 || qos > 2 {
+		return fmt.Errorf("qos must be 0, 1, or 2: %v", qos)
+	}
+	return nil
+}
+
+type mqttSourceConfig struct {
+	mqttConnConfig
+	Topics            data.Value `bql:",required"`
+	QoS               int        `bql:"qos"`
+	CleanSession      bool
+	Format            string
+	ReconnectInterval time.Duration
+}
+
+// mqttSource subscribes to topics and emits a tuple for each message. The
+// source runs a session which connects to the broker, subscribes to the
+// topics, and receives messages. When the session fails, the source retries
+// it after reconnect_interval. Pausing or stopping the source ends the
+// session. When clean_session is false, the broker keeps messages with
+// QoS 1 or 2 while the source is paused and sends them after it's resumed.
+//
+// A message with QoS 1 is acknowledged after its tuple is written, so it'll
+// be sent again when the source fails to write it.
+type mqttSource struct {
+	conf     *mqttSourceConfig
+	topics   []string
+	tlsConf  *tls.Config
+	ioParams *bql.IOParams
+	decode   func(b []byte) (data.Map, error)
+
+	m       sync.Mutex
+	cond    *sync.Cond
+	paused  bool
+	stopped bool
+	// running is true while a session is running or waiting for a retry.
+	running bool
+	// interrupt is closed to end the running session.
+	interrupt chan struct{}
+}
+
+func createMQTTSource(ctx *core.Context, ioParams *bql.IOParams, params data.Map) (core.Source, error) {
+	c := &mqttSourceConfig{
+		mqttConnConfig: mqttConnConfig{
+			KeepAlive: 60 * time.Second,
+			Timeout:   10 * time.Second,
+		},
+		CleanSession:      true,
+		Format:            mqttFormatJSON,
+		ReconnectInterval: time.Second,
+	}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if !c.CleanSession && c.ClientID == "" {
+		// The broker identifies the session by the client ID.
+		return nil, errors.New("client_id is required when clean_session is false")
+	}
+	if err := c.validate(fmt.Sprintf("sensorbee-%v-%v", ioParams.Name, time.Now().UnixNano())); err != nil {
+		return nil, err
+	}
+	topics, err := mqttTopics(c.Topics)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMQTTQoS(c.QoS); err != nil {
+		return nil, err
+	}
+	tlsConf, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &mqttSource{
+		conf:     c,
+		topics:   topics,
+		tlsConf:  tlsConf,
+		ioParams: ioParams,
+	}
+	s.cond = sync.NewCond(&s.m)
+	switch c.Format {
+	case mqttFormatJSON:
+		s.decode = func(b []byte) (data.Map, error) {
+			m := data.Map{}
+			if err := json.Unmarshal(b, &m); err != nil {
+				return nil, err
+			}
+			return m, nil
+		}
+	case mqttFormatRaw:
+		s.decode = func(b []byte) (data.Map, error) {
+			return data.Map{"payload": data.Blob(b)}, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", c.Format)
+	}
+	return s, nil
+}
+
+func (s *mqttSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	for {
+		s.m.Lock()
+		for s.paused && !s.stopped {
+			s.cond.Wait()
+		}
+		if s.stopped {
+			s.m.Unlock()
+			return nil
+		}
+		interrupt := make(chan struct{})
+		s.interrupt = interrupt
+		s.running = true
+		s.m.Unlock()
+
+		if err := s.runSession(ctx, w, interrupt); err != nil {
+			ctx.ErrLog(err).WithField("node_name", s.ioParams.Name).
+				WithField("retry_in", s.conf.ReconnectInterval).
+				Error("The mqtt session failed")
+			select {
+			case <-interrupt:
+			case <-time.After(s.conf.ReconnectInterval):
+			}
+		}
+
+		s.m.Lock()
+		s.running = false
+		s.interrupt = nil
+		s.cond.Broadcast()
+		s.m.Unlock()
+	}
+}
+
+// interruptSession ends the running session and waits for it. The caller
+// must hold the lock.
+func (s *mqttSource) interruptSession() {
+	if s.interrupt != nil {
+		close(s.interrupt)
+		s.interrupt = nil
+	}
+	for s.running {
+		s.cond.Wait()
+	}
+}
+
+func (s *mqttSource) Pause(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.paused = true
+	s.interruptSession()
+	return nil
+}
+
+func (s *mqttSource) Resume(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.paused = false
+	s.cond.Broadcast()
+	return nil
+}
+
+func (s *mqttSource) Stop(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.stopped = true
+	s.interruptSession()
+	s.cond.Broadcast()
+	return nil
+}
+
+// runSession runs a session until interrupt is closed. It returns nil when
+// it's interrupted.
+func (s *mqttSource) runSession(ctx *core.Context, w core.Writer, interrupt <-chan struct{}) error {
+	conn, err := dialMQTT(&s.conf.mqttConnConfig, s.tlsConf, s.conf.CleanSession)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		// The connection is closed to unblock the reader.
+		select {
+		case <-interrupt:
+			conn.disconnect()
+		case <-done:
+		}
+	}()
+	go conn.keepAlive(s.conf.KeepAlive/2, done)
+
+	subID, err := conn.subscribe(s.topics, byte(s.conf.QoS))
+	if err != nil {
+		return err
+	}
+
+	// received has packet identifiers of messages with QoS 2 which have
+	// been written but not released by the broker yet.
+	received := map[uint16]bool{}
+	for {
+		if s.conf.KeepAlive > 0 {
+			conn.conn.SetReadDeadline(time.Now().Add(s.conf.KeepAlive * 3 / 2))
+		}
+		header, body, err := conn.readPacket()
+		if err != nil {
+			select {
+			case <-interrupt:
+				return nil
+			default:
+			}
+			return err
+		}
+
+		switch header >> 4 {
+		case mqttPublish:
+			msg, err := parseMQTTPublish(header, body)
+			if err != nil {
+				return err
+			}
+			if msg.qos == 2 && received[msg.id] {
+				// a duplicate of a message which has already been written
+				if err := conn.writeAck(mqttPubRec<<4, msg.id); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.write(ctx, w, msg); err != nil {
+				return err
+			}
+			switch msg.qos {
+			case 1:
+				err = conn.writeAck(mqttPubAck<<4, msg.id)
+			case 2:
+				received[msg.id] = true
+				err = conn.writeAck(mqttPubRec<<4, msg.id)
+			}
+			if err != nil {
+				return err
+			}
+
+		case mqttPubRel:
+			if len(body) != 2 {
+				return errors.New("the broker sent an invalid PUBREL")
+			}
+			id := binary.BigEndian.Uint16(body)
+			delete(received, id)
+			if err := conn.writeAck(mqttPubComp<<4, id); err != nil {
+				return err
+			}
+
+		case mqttSubAck:
+			if len(body) < 2 || binary.BigEndian.Uint16(body) != subID {
+				return errors.New("the broker sent an invalid SUBACK")
+			}
+			for i, code := range body[2:] {
+				if code == 0x80 && i < len(s.topics) {
+					return fmt.Errorf("the broker refused the subscription to %v", s.topics[i])
+				}
+			}
+
+		case mqttPingResp:
+
+		default:
+			return fmt.Errorf("the broker sent an unexpected packet: type %v", header>>4)
+		}
+	}
+}
+
+func (s *mqttSource) write(ctx *core.Context, w core.Writer, msg *mqttMessage) error {
+	meta := data.Map{
+		"mqtt_topic":    data.String(msg.topic),
+		"mqtt_qos":      data.Int(msg.qos),
+		"mqtt_retained": data.Bool(msg.retain),
+	}
+	m, err := s.decode(msg.payload)
+	if err != nil {
+		ctx.ErrLog(err).WithField("node_name", s.ioParams.Name).
+			WithField("topic", msg.topic).
+			Warning("Ignoring a malformed message")
+		meta["body"] = data.Blob(msg.payload)
+		ctx.DroppedTuple(core.NewTuple(meta), core.NTSource, s.ioParams.Name, core.ETOutput, err)
+		return nil
+	}
+	t := core.NewTuple(m)
+	t.Metadata = meta
+	return w.Write(ctx, t)
+}
+
+type mqttSinkConfig struct {
+	mqttConnConfig
+	Topic         string `bql:",required"`
+	TopicField    string
+	QoS           int `bql:"qos"`
+	Retain        bool
+	Format        string
+	MaxRetries    int
+	RetryInterval time.Duration
+}
+
+// mqttSink publishes a message for each tuple. The topic of a message is
+// taken from topic_field of the tuple when it has a string at the field,
+// and topic is used otherwise. The connection is established lazily and
+// reestablished when it's broken. Messages with QoS 1 or 2 are published
+// synchronously, that is, Write returns after the broker acknowledges them.
+type mqttSink struct {
+	conf       *mqttSinkConfig
+	tlsConf    *tls.Config
+	topicField data.Path
+	encode     func(m data.Map) ([]byte, error)
+
+	m    sync.Mutex
+	conn *mqttConn
+	done chan struct{}
+}
+
+func createMQTTSink(ctx *core.Context, ioParams *bql.IOParams, params data.Map) (core.Sink, error) {
+	c := &mqttSinkConfig{
+		mqttConnConfig: mqttConnConfig{
+			KeepAlive: 60 * time.Second,
+			Timeout:   10 * time.Second,
+		},
+		Format:        mqttFormatJSON,
+		MaxRetries:    3,
+		RetryInterval: time.Second,
+	}
+	if err := data.NewDecoder(nil).Decode(params, c); err != nil {
+		return nil, err
+	}
+	if err := c.validate(fmt.Sprintf("sensorbee-%v-%v", ioParams.Name, time.Now().UnixNano())); err != nil {
+		return nil, err
+	}
+	if err := validateMQTTTopic(c.Topic, false); err != nil {
+		return nil, err
+	}
+	if err := validateMQTTQoS(c.QoS); err != nil {
+		return nil, err
+	}
+	tlsConf, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &mqttSink{
+		conf:    c,
+		tlsConf: tlsConf,
+	}
+	if c.TopicField != "" {
+		if s.topicField, err = data.CompilePath(c.TopicField); err != nil {
+			return nil, fmt.Errorf("topic_field doesn't have a valid path: %v", err)
+		}
+	}
+	switch c.Format {
+	case mqttFormatJSON:
+		s.encode = func(m data.Map) ([]byte, error) {
+			return json.Marshal(m)
+		}
+	case mqttFormatRaw:
+		s.encode = func(m data.Map) ([]byte, error) {
+			v, ok := m["payload"]
+			if !ok {
+				return nil, errors.New("the tuple doesn't have 'payload'")
+			}
+			if b, err := data.AsBlob(v); err == nil {
+				return b, nil
+			}
+			str, err := data.AsString(v)
+			if err != nil {
+				return nil, errors.New("'payload' must be a blob or a string")
+			}
+			return []byte(str), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", c.Format)
+	}
+	return s, nil
+}
+
+func (s *mqttSink) Write(ctx *core.Context, t *core.Tuple) error {
+	payload, err := s.encode(t.Data)
+	if err != nil {
+		return err
+	}
+	topic := s.conf.Topic
+	if s.topicField != nil {
+		if v, err := t.Data.Get(s.topicField); err == nil {
+			if str, err := data.AsString(v); err == nil {
+				if err := validateMQTTTopic(str, false); err != nil {
+					return err
+				}
+				topic = str
+			}
+		}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	for i := 0; ; i++ {
+		err = s.publish(topic, payload)
+		if err == nil || i >= s.conf.MaxRetries {
+			return err
+		}
+		ctx.ErrLog(err).WithField("retry", i+1).Warning("Cannot publish a message to mqtt")
+		time.Sleep(s.conf.RetryInterval)
+	}
+}
+
+// publish publishes the message. The caller must hold the lock.
+func (s *mqttSink) publish(topic string, payload []byte) error {
+	if s.conn == nil {
+		conn, err := dialMQTT(&s.conf.mqttConnConfig, s.tlsConf, true)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		s.done = make(chan struct{})
+		go conn.keepAlive(s.conf.KeepAlive/2, s.done)
+	}
+	if err := s.conn.publish(topic, payload, byte(s.conf.QoS), s.conf.Retain); err != nil {
+		s.closeConn(false)
+		return err
+	}
+	return nil
+}
+
+// closeConn closes the connection. The caller must hold the lock.
+func (s *mqttSink) closeConn(graceful bool) error {
+	if s.conn == nil {
+		return nil
+	}
+	close(s.done)
+	var err error
+	if graceful {
+		err = s.conn.disconnect()
+	} else {
+		err = s.conn.Close()
+	}
+	s.conn = nil
+	s.done = nil
+	return err
+}
+
+func (s *mqttSink) Close(ctx *core.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.closeConn(true)
+}
+
+func init() {
+	bql.MustRegisterGlobalSourceCreator("mqtt", bql.SourceCreatorFunc(createMQTTSource))
+	bql.MustRegisterGlobalSinkCreator("mqtt", bql.SinkCreatorFunc(createMQTTSink))
+}
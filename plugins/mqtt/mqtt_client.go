@@ -0,0 +1,423 @@
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Types of MQTT 3.1.1 control packets. They're the upper 4 bits of the first
+// byte of the fixed header.
+const (
+	mqttConnect    = 1
+	mqttConnAck    = 2
+	mqttPublish    = 3
+	mqttPubAck     = 4
+	mqttPubRec     = 5
+	mqttPubRel     = 6
+	mqttPubComp    = 7
+	mqttSubscribe  = 8
+	mqttSubAck     = 9
+	mqttPingReq    = 12
+	mqttPingResp   = 13
+	mqttDisconnect = 14
+)
+
+// mqttMaxBodySize is the maximum size of a packet allowed by MQTT.
+const mqttMaxBodySize = 256 * 1024 * 1024
+
+// mqttConnConfig has parameters to connect to a broker. It's shared by the
+// mqtt source and sink.
+type mqttConnConfig struct {
+	// Broker is host:port of the broker. It can have a scheme, "tcp://" or
+	// "ssl://", and TLS is enabled with "ssl://", "tls://", or "mqtts://".
+	Broker    string `bql:",required"`
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+	Timeout   time.Duration
+
+	TLS                bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// validate normalizes the broker address and fills default values.
+func (c *mqttConnConfig) validate(defaultClientID string) error {
+	if i := strings.Index(c.Broker, "://"); i >= 0 {
+		switch strings.ToLower(c.Broker[:i]) {
+		case "tcp", "mqtt":
+		case "ssl", "tls", "mqtts":
+			c.TLS = true
+		default:
+			return fmt.Errorf("unsupported scheme of broker: %v", c.Broker)
+		}
+		c.Broker = c.Broker[i+3:]
+	}
+	if _, _, err := net.SplitHostPort(c.Broker); err != nil {
+		return fmt.Errorf("broker must be host:port: %v", err)
+	}
+	if c.ClientID == "" {
+		c.ClientID = defaultClientID
+	}
+	if len(c.ClientID) > 65535 {
+		return errors.New("client_id is too long")
+	}
+	if c.KeepAlive < 0 || c.KeepAlive > 65535*time.Second {
+		return fmt.Errorf("keep_alive must be between 0s and 65535s: %v", c.KeepAlive)
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if c.Password != "" && c.Username == "" {
+		return errors.New("password cannot be specified without username")
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("cert_file and key_file must be specified together")
+	}
+	if c.CAFile != "" || c.CertFile != "" || c.ServerName != "" || c.InsecureSkipVerify {
+		c.TLS = true
+	}
+	return nil
+}
+
+// tlsConfig returns the TLS configuration. It returns nil when TLS isn't
+// enabled.
+func (c *mqttConnConfig) tlsConfig() (*tls.Config, error) {
+	if !c.TLS {
+		return nil, nil
+	}
+	conf := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if conf.ServerName == "" {
+		conf.ServerName, _, _ = net.SplitHostPort(c.Broker)
+	}
+	if c.CAFile != "" {
+		b, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read ca_file: %v", err)
+		}
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("ca_file doesn't have any certificate: %v", c.CAFile)
+		}
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the client certificate: %v", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
+
+// mqttMessage is a message received by a PUBLISH packet.
+type mqttMessage struct {
+	topic   string
+	qos     byte
+	retain  bool
+	dup     bool
+	id      uint16
+	payload []byte
+}
+
+// mqttConn is a connection to a broker speaking MQTT 3.1.1. Packets can be
+// written concurrently, but they must be read by one goroutine.
+type mqttConn struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	wm     sync.Mutex
+	w      *bufio.Writer
+	nextID uint16
+}
+
+// dialMQTT connects to the broker and sends CONNECT. The broker discards
+// the previous session of the client when cleanSession is true.
+func dialMQTT(c *mqttConnConfig, tlsConf *tls.Config, cleanSession bool) (*mqttConn, error) {
+	dialer := &net.Dialer{Timeout: c.Timeout}
+	var conn net.Conn
+	var err error
+	if tlsConf != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.Broker, tlsConf)
+	} else {
+		conn, err = dialer.Dial("tcp", c.Broker)
+	}
+	if err != nil {
+		return nil, err
+	}
+	mc := &mqttConn{
+		conn:    conn,
+		r:       bufio.NewReader(conn),
+		w:       bufio.NewWriter(conn),
+		timeout: c.Timeout,
+	}
+	if err := mc.connect(c, cleanSession); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return mc, nil
+}
+
+func (c *mqttConn) connect(conf *mqttConnConfig, cleanSession bool) error {
+	var flags byte
+	if cleanSession {
+		flags |= 0x02
+	}
+	payload := mqttString(nil, conf.ClientID)
+	if conf.Username != "" {
+		flags |= 0x80
+		payload = mqttString(payload, conf.Username)
+		if conf.Password != "" {
+			flags |= 0x40
+			payload = mqttString(payload, conf.Password)
+		}
+	}
+	vh := mqttString(nil, "MQTT")
+	vh = append(vh, 4, flags, 0, 0) // level 4
+	binary.BigEndian.PutUint16(vh[len(vh)-2:], uint16(conf.KeepAlive/time.Second))
+	if err := c.writePacket(mqttConnect<<4, append(vh, payload...)); err != nil {
+		return err
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetReadDeadline(time.Time{})
+	header, body, err := c.readPacket()
+	if err != nil {
+		return fmt.Errorf("cannot receive CONNACK: %v", err)
+	}
+	if header>>4 != mqttConnAck || len(body) != 2 {
+		return errors.New("the broker sent an invalid CONNACK")
+	}
+	if body[1] != 0 {
+		return fmt.Errorf("the broker refused the connection: return code %v", body[1])
+	}
+	return nil
+}
+
+// writePacket writes a packet having the fixed header and the body.
+func (c *mqttConn) writePacket(header byte, body []byte) error {
+	c.wm.Lock()
+	defer c.wm.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	writeMQTTPacket(c.w, header, body)
+	return c.w.Flush()
+}
+
+// writeAck writes a packet having only a packet identifier such as PUBACK.
+func (c *mqttConn) writeAck(header byte, id uint16) error {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, id)
+	return c.writePacket(header, b)
+}
+
+// readPacket reads a packet and returns the first byte of its fixed header
+// and its body.
+func (c *mqttConn) readPacket() (byte, []byte, error) {
+	header, err := c.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	l := 0
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("the remaining length of the packet is malformed")
+		}
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		l |= int(b&0x7f) << uint(7*i)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if l > mqttMaxBodySize {
+		return 0, nil, fmt.Errorf("the packet is too large: %v bytes", l)
+	}
+	body := make([]byte, l)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// packetID returns a new packet identifier, which must not be 0.
+func (c *mqttConn) packetID() uint16 {
+	c.wm.Lock()
+	defer c.wm.Unlock()
+	c.nextID++
+	if c.nextID == 0 {
+		c.nextID = 1
+	}
+	return c.nextID
+}
+
+// subscribe sends SUBSCRIBE. The SUBACK has to be handled by the reader.
+func (c *mqttConn) subscribe(filters []string, qos byte) (uint16, error) {
+	id := c.packetID()
+	body := []byte{byte(id >> 8), byte(id)}
+	for _, f := range filters {
+		body = append(mqttString(body, f), qos)
+	}
+	return id, c.writePacket(mqttSubscribe<<4|0x02, body)
+}
+
+// publish sends PUBLISH and waits for its acknowledgement when qos is
+// greater than 0. It reads packets, so it must not be called while another
+// goroutine reads the connection.
+func (c *mqttConn) publish(topic string, payload []byte, qos byte, retain bool) error {
+	header := byte(mqttPublish<<4) | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := mqttString(nil, topic)
+	var id uint16
+	if qos > 0 {
+		id = c.packetID()
+		body = append(body, byte(id>>8), byte(id))
+	}
+	if err := c.writePacket(header, append(body, payload...)); err != nil {
+		return err
+	}
+	if qos == 0 {
+		return nil
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+	defer c.conn.SetReadDeadline(time.Time{})
+	for {
+		h, b, err := c.readPacket()
+		if err != nil {
+			return fmt.Errorf("cannot receive the acknowledgement of PUBLISH: %v", err)
+		}
+		if len(b) < 2 || binary.BigEndian.Uint16(b) != id {
+			// e.g. PINGRESP
+			continue
+		}
+		switch h >> 4 {
+		case mqttPubAck:
+			if qos == 1 {
+				return nil
+			}
+		case mqttPubRec:
+			if qos == 2 {
+				if err := c.writeAck(mqttPubRel<<4|0x02, id); err != nil {
+					return err
+				}
+			}
+		case mqttPubComp:
+			if qos == 2 {
+				return nil
+			}
+		}
+	}
+}
+
+// ping sends PINGREQ. The PINGRESP has to be handled by the reader.
+func (c *mqttConn) ping() error {
+	return c.writePacket(mqttPingReq<<4, nil)
+}
+
+// disconnect sends DISCONNECT and closes the connection.
+func (c *mqttConn) disconnect() error {
+	c.writePacket(mqttDisconnect<<4, nil)
+	return c.conn.Close()
+}
+
+func (c *mqttConn) Close() error {
+	return c.conn.Close()
+}
+
+// keepAlive sends PINGREQ periodically until done is closed so that the
+// broker doesn't close the connection.
+func (c *mqttConn) keepAlive(interval time.Duration, done <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if err := c.ping(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseMQTTPublish parses the PUBLISH packet.
+func parseMQTTPublish(header byte, body []byte) (*mqttMessage, error) {
+	m := &mqttMessage{
+		qos:    (header >> 1) & 0x03,
+		retain: header&0x01 != 0,
+		dup:    header&0x08 != 0,
+	}
+	if m.qos > 2 {
+		return nil, errors.New("PUBLISH has an invalid QoS")
+	}
+	if len(body) < 2 {
+		return nil, errors.New("PUBLISH is too short")
+	}
+	l := int(binary.BigEndian.Uint16(body))
+	body = body[2:]
+	if len(body) < l {
+		return nil, errors.New("PUBLISH is too short")
+	}
+	m.topic = string(body[:l])
+	body = body[l:]
+	if m.qos > 0 {
+		if len(body) < 2 {
+			return nil, errors.New("PUBLISH is too short")
+		}
+		m.id = binary.BigEndian.Uint16(body)
+		body = body[2:]
+	}
+	m.payload = body
+	return m, nil
+}
+
+// mqttString appends a length-prefixed string to b.
+func mqttString(b []byte, s string) []byte {
+	b = append(b, 0, 0)
+	binary.BigEndian.PutUint16(b[len(b)-2:], uint16(len(s)))
+	return append(b, s...)
+}
+
+// writeMQTTPacket writes a packet having the fixed header and the body.
+func writeMQTTPacket(w *bufio.Writer, header byte, body []byte) {
+	w.WriteByte(header)
+	l := len(body)
+	for {
+		b := byte(l % 128)
+		l /= 128
+		if l > 0 {
+			b |= 0x80
+		}
+		w.WriteByte(b)
+		if l == 0 {
+			break
+		}
+	}
+	w.Write(body)
+}
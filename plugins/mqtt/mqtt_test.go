@@ -0,0 +1,627 @@
+package mqtt
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// fakeMQTTBroker is a broker implementing a part of MQTT 3.1.1 used by the
+// mqtt source and sink. It doesn't keep sessions or retained messages, and
+// it delivers messages with the QoS of the subscription.
+type fakeMQTTBroker struct {
+	l net.Listener
+
+	m             sync.Mutex
+	cond          *sync.Cond
+	subscriptions map[*fakeMQTTClient][]string
+	clients       map[*fakeMQTTClient]struct{}
+	// acks counts acknowledgements sent by subscribers.
+	acks map[byte]int
+	// subscribes counts SUBSCRIBE packets.
+	subscribes int
+}
+
+type fakeMQTTClient struct {
+	conn   *mqttConn
+	qos    byte
+	nextID uint16
+}
+
+func newFakeMQTTBroker(tlsConf *tls.Config) (*fakeMQTTBroker, error) {
+	var l net.Listener
+	var err error
+	if tlsConf != nil {
+		l, err = tls.Listen("tcp", "127.0.0.1:0", tlsConf)
+	} else {
+		l, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := &fakeMQTTBroker{
+		l:             l,
+		subscriptions: map[*fakeMQTTClient][]string{},
+		clients:       map[*fakeMQTTClient]struct{}{},
+		acks:          map[byte]int{},
+	}
+	b.cond = sync.NewCond(&b.m)
+	go b.serve()
+	return b, nil
+}
+
+func (b *fakeMQTTBroker) addr() string {
+	return b.l.Addr().String()
+}
+
+func (b *fakeMQTTBroker) serve() {
+	for {
+		conn, err := b.l.Accept()
+		if err != nil {
+			return
+		}
+		c := &fakeMQTTClient{
+			conn: &mqttConn{
+				conn:    conn,
+				r:       bufio.NewReader(conn),
+				w:       bufio.NewWriter(conn),
+				timeout: time.Second,
+			},
+		}
+		b.m.Lock()
+		b.clients[c] = struct{}{}
+		b.m.Unlock()
+		go b.handle(c)
+	}
+}
+
+func (b *fakeMQTTBroker) Close() {
+	b.l.Close()
+	b.dropConnections()
+}
+
+// dropConnections closes all connections from clients.
+func (b *fakeMQTTBroker) dropConnections() {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for c := range b.clients {
+		c.conn.Close()
+	}
+}
+
+// waitSubscribers waits until n clients subscribe to topics.
+func (b *fakeMQTTBroker) waitSubscribers(n int) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for len(b.subscriptions) != n {
+		b.cond.Wait()
+	}
+}
+
+// waitSubscribes waits until the broker receives n SUBSCRIBE packets in
+// total.
+func (b *fakeMQTTBroker) waitSubscribes(n int) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for b.subscribes < n {
+		b.cond.Wait()
+	}
+}
+
+// waitAcks waits until subscribers send n acknowledgements of the type.
+func (b *fakeMQTTBroker) waitAcks(typ byte, n int) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for b.acks[typ] < n {
+		b.cond.Wait()
+	}
+}
+
+func (b *fakeMQTTBroker) ack(typ byte) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.acks[typ]++
+	b.cond.Broadcast()
+}
+
+func (b *fakeMQTTBroker) handle(c *fakeMQTTClient) {
+	defer func() {
+		c.conn.Close()
+		b.m.Lock()
+		delete(b.clients, c)
+		delete(b.subscriptions, c)
+		b.cond.Broadcast()
+		b.m.Unlock()
+	}()
+
+	h, _, err := c.conn.readPacket()
+	if err != nil || h>>4 != mqttConnect {
+		return
+	}
+	c.conn.writePacket(mqttConnAck<<4, []byte{0, 0})
+
+	for {
+		h, body, err := c.conn.readPacket()
+		if err != nil {
+			return
+		}
+		switch h >> 4 {
+		case mqttPublish:
+			msg, err := parseMQTTPublish(h, body)
+			if err != nil {
+				return
+			}
+			switch msg.qos {
+			case 1:
+				c.conn.writeAck(mqttPubAck<<4, msg.id)
+			case 2:
+				c.conn.writeAck(mqttPubRec<<4, msg.id)
+			}
+			b.deliver(msg)
+
+		case mqttPubRel:
+			c.conn.writeAck(mqttPubComp<<4, binary.BigEndian.Uint16(body))
+
+		case mqttPubAck, mqttPubComp:
+			b.ack(h >> 4)
+
+		case mqttPubRec:
+			b.ack(h >> 4)
+			c.conn.writeAck(mqttPubRel<<4|0x02, binary.BigEndian.Uint16(body))
+
+		case mqttSubscribe:
+			var filters []string
+			codes := []byte{}
+			for rest := body[2:]; len(rest) > 0; {
+				l := int(binary.BigEndian.Uint16(rest))
+				filters = append(filters, string(rest[2:2+l]))
+				c.qos = rest[2+l]
+				codes = append(codes, c.qos)
+				rest = rest[3+l:]
+			}
+			c.conn.writePacket(mqttSubAck<<4, append(body[:2:2], codes...))
+			b.m.Lock()
+			b.subscriptions[c] = filters
+			b.subscribes++
+			b.cond.Broadcast()
+			b.m.Unlock()
+
+		case mqttPingReq:
+			c.conn.writePacket(mqttPingResp<<4, nil)
+
+		case mqttDisconnect:
+			return
+		}
+	}
+}
+
+func (b *fakeMQTTBroker) deliver(msg *mqttMessage) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for c, filters := range b.subscriptions {
+		for _, f := range filters {
+			if !fakeMQTTTopicMatch(f, msg.topic) {
+				continue
+			}
+			qos := msg.qos
+			if c.qos < qos {
+				qos = c.qos
+			}
+			body := mqttString(nil, msg.topic)
+			if qos > 0 {
+				c.nextID++
+				body = append(body, byte(c.nextID>>8), byte(c.nextID))
+			}
+			c.conn.writePacket(mqttPublish<<4|qos<<1, append(body, msg.payload...))
+			break
+		}
+	}
+}
+
+func fakeMQTTTopicMatch(filter, topic string) bool {
+	fs := strings.Split(filter, "/")
+	ts := strings.Split(topic, "/")
+	for i, f := range fs {
+		if f == "#" {
+			return true
+		}
+		if i >= len(ts) || (f != "+" && f != ts[i]) {
+			return false
+		}
+	}
+	return len(fs) == len(ts)
+}
+
+// runMQTTSource runs the source in a goroutine and returns a function
+// stopping it. The function returns the result of GenerateStream and can be
+// called multiple times.
+func runMQTTSource(ctx *core.Context, src core.Source, w core.Writer) func() error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- src.GenerateStream(ctx, w)
+	}()
+	var once sync.Once
+	var err error
+	return func() error {
+		once.Do(func() {
+			if err = src.Stop(ctx); err == nil {
+				err = <-ch
+			}
+		})
+		return err
+	}
+}
+
+func TestMQTTSinkAndSource(t *testing.T) {
+	Convey("Given an mqtt broker", t, func() {
+		broker, err := newFakeMQTTBroker(nil)
+		So(err, ShouldBeNil)
+		Reset(broker.Close)
+		ctx := core.NewContext(nil)
+
+		Convey("When an mqtt source subscribes to a topic filter", func() {
+			src, err := createMQTTSource(ctx, &bql.IOParams{Name: "in"}, data.Map{
+				"broker":             data.String("tcp://" + broker.addr()),
+				"topics":             data.Array{data.String("sensors/+/temperature")},
+				"qos":                data.Int(1),
+				"reconnect_interval": data.String("10ms"),
+			})
+			So(err, ShouldBeNil)
+			w := &tupleCollectorSink{}
+			w.c = sync.NewCond(&w.m)
+			stop := runMQTTSource(ctx, src, w)
+			Reset(func() {
+				stop()
+			})
+			broker.waitSubscribers(1)
+
+			Convey("And an mqtt sink publishes tuples", func() {
+				sink, err := createMQTTSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+					"broker":      data.String(broker.addr()),
+					"topic":       data.String("sensors/unknown/temperature"),
+					"topic_field": data.String("topic"),
+					"qos":         data.Int(2),
+				})
+				So(err, ShouldBeNil)
+				for i, topic := range []string{"sensors/a/temperature", "sensors/a/humidity", "sensors/b/temperature"} {
+					So(sink.Write(ctx, core.NewTuple(data.Map{
+						"topic": data.String(topic),
+						"value": data.Int(i),
+					})), ShouldBeNil)
+				}
+				So(sink.Write(ctx, core.NewTuple(data.Map{"value": data.Int(3)})), ShouldBeNil)
+				So(sink.Close(ctx), ShouldBeNil)
+				w.Wait(3)
+
+				Convey("Then the source should receive messages matching the filter", func() {
+					So(stop(), ShouldBeNil)
+					So(w.len(), ShouldEqual, 3)
+					So(w.get(0).Data["value"], ShouldEqual, data.Int(0))
+					So(w.get(1).Data["value"], ShouldEqual, data.Int(2))
+					So(w.get(2).Data["value"], ShouldEqual, data.Int(3))
+					So(w.get(0).Metadata["mqtt_topic"], ShouldEqual, data.String("sensors/a/temperature"))
+					So(w.get(2).Metadata["mqtt_topic"], ShouldEqual, data.String("sensors/unknown/temperature"))
+					So(w.get(0).Metadata["mqtt_qos"], ShouldEqual, data.Int(1))
+				})
+
+				Convey("Then the source should acknowledge them", func() {
+					broker.waitAcks(mqttPubAck, 3)
+				})
+			})
+
+			Convey("And it's paused", func() {
+				rs := src.(core.Resumable)
+				So(rs.Pause(ctx), ShouldBeNil)
+				broker.waitSubscribers(0)
+
+				Convey("Then it should subscribe again after it's resumed", func() {
+					So(rs.Resume(ctx), ShouldBeNil)
+					broker.waitSubscribers(1)
+					broker.deliver(&mqttMessage{topic: "sensors/a/temperature", payload: []byte(`{"a":1}`)})
+					w.Wait(1)
+					So(w.get(0).Data["a"], ShouldEqual, data.Int(1))
+				})
+			})
+
+			Convey("And connections are lost", func() {
+				broker.dropConnections()
+
+				Convey("Then it should reconnect", func() {
+					broker.waitSubscribes(2)
+					broker.deliver(&mqttMessage{topic: "sensors/a/temperature", payload: []byte(`{"a":1}`)})
+					w.Wait(1)
+					So(w.get(0).Data["a"], ShouldEqual, data.Int(1))
+				})
+			})
+
+			Convey("And it receives malformed messages", func() {
+				for _, p := range []string{`{"a":1}`, `{broken`, `{"a":3}`} {
+					broker.deliver(&mqttMessage{topic: "sensors/a/temperature", qos: 1, payload: []byte(p)})
+				}
+				w.Wait(2)
+
+				Convey("Then it should skip them", func() {
+					So(stop(), ShouldBeNil)
+					So(w.len(), ShouldEqual, 2)
+					So(w.get(0).Data["a"], ShouldEqual, data.Int(1))
+					So(w.get(1).Data["a"], ShouldEqual, data.Int(3))
+				})
+			})
+		})
+
+		Convey("When an mqtt source with QoS 2 and the raw format subscribes to a topic", func() {
+			src, err := createMQTTSource(ctx, &bql.IOParams{Name: "in"}, data.Map{
+				"broker": data.String(broker.addr()),
+				"topics": data.String("raw/#"),
+				"qos":    data.Int(2),
+				"format": data.String("raw"),
+			})
+			So(err, ShouldBeNil)
+			w := &tupleCollectorSink{}
+			w.c = sync.NewCond(&w.m)
+			stop := runMQTTSource(ctx, src, w)
+			Reset(func() {
+				stop()
+			})
+			broker.waitSubscribers(1)
+
+			Convey("And an mqtt sink publishes payloads with QoS 2", func() {
+				sink, err := createMQTTSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+					"broker": data.String(broker.addr()),
+					"topic":  data.String("raw/data"),
+					"qos":    data.Int(2),
+					"format": data.String("raw"),
+				})
+				So(err, ShouldBeNil)
+				So(sink.Write(ctx, core.NewTuple(data.Map{"payload": data.Blob{0, 1, 2}})), ShouldBeNil)
+				So(sink.Write(ctx, core.NewTuple(data.Map{"payload": data.String("text")})), ShouldBeNil)
+				So(sink.Write(ctx, core.NewTuple(data.Map{"value": data.Int(1)})), ShouldNotBeNil)
+				So(sink.Close(ctx), ShouldBeNil)
+				w.Wait(2)
+
+				Convey("Then the source should receive them as blobs", func() {
+					So(stop(), ShouldBeNil)
+					So(w.get(0).Data["payload"], ShouldResemble, data.Blob{0, 1, 2})
+					So(w.get(1).Data["payload"], ShouldResemble, data.Blob("text"))
+					So(w.get(0).Metadata["mqtt_qos"], ShouldEqual, data.Int(2))
+				})
+
+				Convey("Then the source should complete the QoS 2 flow", func() {
+					broker.waitAcks(mqttPubComp, 2)
+					So(stop(), ShouldBeNil)
+					So(w.len(), ShouldEqual, 2)
+				})
+			})
+		})
+	})
+}
+
+func TestMQTTWithTLS(t *testing.T) {
+	Convey("Given an mqtt broker with TLS", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_mqtt_test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		cert, caFile := newTestCertificate(dir)
+		broker, err := newFakeMQTTBroker(&tls.Config{Certificates: []tls.Certificate{cert}})
+		So(err, ShouldBeNil)
+		Reset(broker.Close)
+		ctx := core.NewContext(nil)
+
+		Convey("When a source and a sink connect to it with the CA certificate", func() {
+			src, err := createMQTTSource(ctx, &bql.IOParams{Name: "in"}, data.Map{
+				"broker":  data.String(broker.addr()),
+				"topics":  data.String("secure"),
+				"ca_file": data.String(caFile),
+			})
+			So(err, ShouldBeNil)
+			w := &tupleCollectorSink{}
+			w.c = sync.NewCond(&w.m)
+			stop := runMQTTSource(ctx, src, w)
+			Reset(func() {
+				stop()
+			})
+			broker.waitSubscribers(1)
+
+			sink, err := createMQTTSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+				"broker":  data.String("ssl://" + broker.addr()),
+				"topic":   data.String("secure"),
+				"ca_file": data.String(caFile),
+			})
+			So(err, ShouldBeNil)
+			So(sink.Write(ctx, core.NewTuple(data.Map{"a": data.Int(1)})), ShouldBeNil)
+			So(sink.Close(ctx), ShouldBeNil)
+			w.Wait(1)
+			So(stop(), ShouldBeNil)
+
+			Convey("Then messages should be delivered", func() {
+				So(w.get(0).Data["a"], ShouldEqual, data.Int(1))
+			})
+		})
+
+		Convey("When a sink connects to it without the CA certificate", func() {
+			sink, err := createMQTTSink(ctx, &bql.IOParams{Name: "out"}, data.Map{
+				"broker":      data.String(broker.addr()),
+				"topic":       data.String("secure"),
+				"tls":         data.True,
+				"max_retries": data.Int(0),
+			})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				sink.Close(ctx)
+			})
+
+			Convey("Then it should fail to publish", func() {
+				So(sink.Write(ctx, core.NewTuple(data.Map{"a": data.Int(1)})), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// newTestCertificate creates a self-signed certificate for 127.0.0.1 and
+// writes it to a file in dir.
+func newTestCertificate(dir string) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	So(err, ShouldBeNil)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	So(err, ShouldBeNil)
+	caFile := filepath.Join(dir, "ca.pem")
+	So(ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600), ShouldBeNil)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestMQTTParams(t *testing.T) {
+	Convey("Given a context", t, func() {
+		ctx := core.NewContext(nil)
+		ioParams := &bql.IOParams{Name: "test"}
+
+		Convey("When creating a source without a broker", func() {
+			_, err := createMQTTSource(ctx, ioParams, data.Map{"topics": data.String("a")})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a source with an invalid QoS", func() {
+			_, err := createMQTTSource(ctx, ioParams, data.Map{
+				"broker": data.String("localhost:1883"),
+				"topics": data.String("a"),
+				"qos":    data.Int(3),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a source with an invalid topic filter", func() {
+			_, err := createMQTTSource(ctx, ioParams, data.Map{
+				"broker": data.String("localhost:1883"),
+				"topics": data.Array{data.String("a/#"), data.String("a/b#")},
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a source with a persistent session without client_id", func() {
+			_, err := createMQTTSource(ctx, ioParams, data.Map{
+				"broker":        data.String("localhost:1883"),
+				"topics":        data.String("a"),
+				"clean_session": data.False,
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a sink with a wildcard in the topic", func() {
+			_, err := createMQTTSink(ctx, ioParams, data.Map{
+				"broker": data.String("localhost:1883"),
+				"topic":  data.String("a/+"),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a sink with cert_file but without key_file", func() {
+			_, err := createMQTTSink(ctx, ioParams, data.Map{
+				"broker":    data.String("localhost:1883"),
+				"topic":     data.String("a"),
+				"cert_file": data.String("cert.pem"),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a sink with an unsupported scheme", func() {
+			_, err := createMQTTSink(ctx, ioParams, data.Map{
+				"broker": data.String("ws://localhost:1883"),
+				"topic":  data.String("a"),
+			})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// tupleCollectorSink collects tuples written to it.
+type tupleCollectorSink struct {
+	Tuples []*core.Tuple
+	m      sync.Mutex
+	c      *sync.Cond
+}
+
+func (s *tupleCollectorSink) Write(ctx *core.Context, t *core.Tuple) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.Tuples = append(s.Tuples, t)
+	s.c.Broadcast()
+	return nil
+}
+
+// Wait waits until the collector receives at least n tuples.
+func (s *tupleCollectorSink) Wait(n int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for len(s.Tuples) < n {
+		s.c.Wait()
+	}
+}
+
+func (s *tupleCollectorSink) get(n int) *core.Tuple {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.Tuples[n]
+}
+
+func (s *tupleCollectorSink) len() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return len(s.Tuples)
+}
+
+func (s *tupleCollectorSink) Close(ctx *core.Context) error {
+	return nil
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type alerts struct {
+	*topologies
+}
+
+func setUpAlertsRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(alerts{}, "/:topologyName/alerts")
+	root.Middleware((*alerts).fetchTopologyMiddleware)
+	root.Get("/", (*alerts).Index)
+	root.Get("/:alertName", (*alerts).Show)
+	root.Post("/:alertName/silences", (*alerts).Silence)
+	root.Delete("/:alertName/silences/:silenceID", (*alerts).Unsilence)
+}
+
+func (ac *alerts) fetchTopologyMiddleware(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if ac.fetchTopology() == nil {
+		return
+	}
+	next(rw, req)
+}
+
+// renderAlertError renders an error returned from a method of
+// TopologyBuilder handling alerts.
+func (ac *alerts) renderAlertError(err error, msg string) {
+	if core.IsNotExist(err) {
+		ac.ErrLog(err).Error("Cannot find the alert")
+		ac.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The alert was not found", http.StatusNotFound, err))
+		return
+	}
+	ac.ErrLog(err).Error(msg)
+	ac.RenderError(jasco.NewInternalServerError(err))
+}
+
+// Index returns statuses of all alerts in the topology.
+func (ac *alerts) Index(rw web.ResponseWriter, req *web.Request) {
+	sts := ac.topology.AlertStatuses()
+	ac.Render(map[string]interface{}{
+		"topology": ac.topologyName,
+		"count":    len(sts),
+		"alerts":   sts,
+	})
+}
+
+// Show returns the status of an alert.
+func (ac *alerts) Show(rw web.ResponseWriter, req *web.Request) {
+	st, err := ac.topology.AlertStatus(ac.PathParams().String("alertName", ""))
+	if err != nil {
+		ac.renderAlertError(err, "Cannot get the status of the alert")
+		return
+	}
+	ac.Render(map[string]interface{}{
+		"topology": ac.topologyName,
+		"alert":    st,
+	})
+}
+
+// Silence stops notifications of an alert for a while.
+func (ac *alerts) Silence(rw web.ResponseWriter, req *web.Request) {
+	var js map[string]interface{}
+	if apiErr := ac.ParseBody(&js); apiErr != nil {
+		ac.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		ac.RenderError(apiErr)
+		return
+	}
+	form, err := data.NewMap(js)
+	if err != nil {
+		ac.ErrLog(err).WithField("body", js).
+			Error("The request json may contain invalid value")
+		ac.RenderError(jasco.NewError(formValidationErrorCode, "The request json may contain invalid values.",
+			http.StatusBadRequest, err))
+		return
+	}
+
+	badRequest := func(err error, msg string) {
+		ac.ErrLog(err).Error(msg)
+		ac.RenderError(jasco.NewError(formValidationErrorCode, msg, http.StatusBadRequest, err))
+	}
+	var until time.Time
+	if v, ok := form["until"]; ok {
+		if until, err = data.ToTimestamp(v); err != nil {
+			badRequest(err, "'until' field must be a timestamp")
+			return
+		}
+	} else if v, ok := form["duration"]; ok {
+		d, err := data.ToDuration(v)
+		if err != nil || d <= 0 {
+			badRequest(err, "'duration' field must be a positive duration")
+			return
+		}
+		until = time.Now().Add(d)
+	} else {
+		badRequest(nil, "'until' or 'duration' field is required")
+		return
+	}
+	comment := ""
+	if v, ok := form["comment"]; ok {
+		if comment, err = data.AsString(v); err != nil {
+			badRequest(err, "'comment' field must be a string")
+			return
+		}
+	}
+
+	name := ac.PathParams().String("alertName", "")
+	id, err := ac.topology.SilenceAlert(name, form["key"], until, comment)
+	if err != nil {
+		ac.renderAlertError(err, "Cannot silence the alert")
+		return
+	}
+	st, err := ac.topology.AlertStatus(name)
+	if err != nil {
+		ac.renderAlertError(err, "Cannot get the status of the alert")
+		return
+	}
+	ac.Render(map[string]interface{}{
+		"topology":   ac.topologyName,
+		"silence_id": id,
+		"alert":      st,
+	})
+}
+
+// Unsilence removes a silence of an alert.
+func (ac *alerts) Unsilence(rw web.ResponseWriter, req *web.Request) {
+	id, err := strconv.ParseInt(ac.PathParams().String("silenceID", ""), 10, 64)
+	if err != nil {
+		ac.ErrLog(err).Error("The silence ID is invalid")
+		ac.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The silence was not found", http.StatusNotFound, err))
+		return
+	}
+	if err := ac.topology.UnsilenceAlert(ac.PathParams().String("alertName", ""), id); err != nil {
+		ac.renderAlertError(err, "Cannot unsilence the alert")
+		return
+	}
+	ac.Render(map[string]interface{}{})
+}
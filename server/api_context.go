@@ -10,12 +10,22 @@ type APIContext struct {
 }
 
 // SetUpAPIRouter sets up a router for APIs with user defined custom route.
-// Subrouters needs to have APIContext as their first field.
+// Subrouters needs to have APIContext as their first field. It also sets up
+// /metrics exporting metrics of topologies for Prometheus. Requests to all
+// of them are authenticated when authentication is enabled, and read-only
+// users can only send GET requests to them except for a few actions.
 func SetUpAPIRouter(prefix string, router *web.Router, route func(prefix string, r *web.Router)) {
+	setUpMetricsRouter(prefix, router)
 	root := router.Subrouter(APIContext{}, "/api/v1")
+	root.Middleware((*APIContext).authenticate)
+	root.Middleware((*APIContext).authorize)
 
 	setUpTopologiesRouter(prefix, root)
 	setUpServerStatusRouter(prefix, root)
+	setUpLintRouter(prefix, root)
+	setUpClusterRouter(prefix, root)
+	setUpReplicationRouter(prefix, root)
+	setUpUsageRouter(prefix, root)
 
 	if route != nil {
 		route(prefix, root)
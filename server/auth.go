@@ -0,0 +1,340 @@
+package server
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+var (
+	// ErrNoCredentials is returned from Authenticator.Authenticate when a
+	// request doesn't have any credentials. The server responds with 401.
+	ErrNoCredentials = errors.New("the request doesn't have credentials")
+
+	// ErrInvalidCredentials is returned from Authenticator.Authenticate when
+	// credentials in a request aren't accepted. The server responds with 403.
+	ErrInvalidCredentials = errors.New("the credentials aren't valid")
+)
+
+// Authenticator authenticates requests to the API. The server uses the
+// authenticator set to ContextGlobalVariables.Authenticator and doesn't
+// authenticate requests when it's nil.
+type Authenticator interface {
+	// Authenticate returns nil when the request is allowed. It returns
+	// ErrNoCredentials when the request doesn't have credentials. Other
+	// errors, including ErrInvalidCredentials, reject the request with 403.
+	Authenticate(req *http.Request) error
+}
+
+// Role is the role of a user of the API.
+type Role string
+
+const (
+	// RoleAdmin can perform all actions.
+	RoleAdmin Role = "admin"
+
+	// RoleReadOnly can only view topologies and nodes, and issue SELECT,
+	// EVAL, and DESCRIBE statements.
+	RoleReadOnly Role = "read_only"
+)
+
+// canModify returns true when the role can change topologies. An empty role
+// is given to requests which aren't authenticated and it can change them.
+func (r Role) canModify() bool {
+	return r != RoleReadOnly
+}
+
+// RoleAuthenticator is an Authenticator which also assigns roles to
+// requests. Requests allowed by an Authenticator which doesn't implement
+// this interface have RoleAdmin.
+type RoleAuthenticator interface {
+	Authenticator
+
+	// Role returns the role of the request allowed by Authenticate.
+	Role(req *http.Request) Role
+}
+
+type staticAuthenticator struct {
+	// apiKeys and bearerTokens have SHA-256 hashes of accepted credentials
+	// so that a lookup doesn't compare credentials byte by byte.
+	apiKeys      map[[sha256.Size]byte]Role
+	bearerTokens map[[sha256.Size]byte]Role
+}
+
+var _ RoleAuthenticator = &staticAuthenticator{}
+
+// NewStaticAuthenticator creates an Authenticator accepting API keys in the
+// X-API-Key header and bearer tokens in the Authorization header given in
+// the config. It returns nil when the config doesn't have any of them.
+//
+// The returned Authenticator is also a RoleAuthenticator. Credentials at the
+// top level of the config have RoleAdmin. When credentials are given to
+// multiple roles, RoleReadOnly is used.
+func NewStaticAuthenticator(conf *config.Auth) Authenticator {
+	if !conf.Enabled() {
+		return nil
+	}
+	a := &staticAuthenticator{
+		apiKeys:      map[[sha256.Size]byte]Role{},
+		bearerTokens: map[[sha256.Size]byte]Role{},
+	}
+	add := func(role Role, apiKeys, bearerTokens []string) {
+		for _, k := range apiKeys {
+			a.apiKeys[sha256.Sum256([]byte(k))] = role
+		}
+		for _, t := range bearerTokens {
+			a.bearerTokens[sha256.Sum256([]byte(t))] = role
+		}
+	}
+	add(RoleAdmin, conf.APIKeys, conf.BearerTokens)
+	if c := conf.Roles[string(RoleAdmin)]; c != nil {
+		add(RoleAdmin, c.APIKeys, c.BearerTokens)
+	}
+	if c := conf.Roles[string(RoleReadOnly)]; c != nil {
+		add(RoleReadOnly, c.APIKeys, c.BearerTokens)
+	}
+	return a
+}
+
+func (a *staticAuthenticator) Authenticate(req *http.Request) error {
+	_, err := a.authenticate(req)
+	return err
+}
+
+// Role returns the role of the request. It returns RoleReadOnly when the
+// request isn't authenticated.
+func (a *staticAuthenticator) Role(req *http.Request) Role {
+	r, err := a.authenticate(req)
+	if err != nil {
+		return RoleReadOnly
+	}
+	return r
+}
+
+func (a *staticAuthenticator) authenticate(req *http.Request) (Role, error) {
+	if key := req.Header.Get("X-API-Key"); key != "" {
+		if r, ok := a.apiKeys[sha256.Sum256([]byte(key))]; ok {
+			return r, nil
+		}
+		return "", ErrInvalidCredentials
+	}
+
+	const prefix = "bearer "
+	h := req.Header.Get("Authorization")
+	if h == "" {
+		return "", ErrNoCredentials
+	}
+	if len(h) <= len(prefix) || strings.ToLower(h[:len(prefix)]) != prefix {
+		return "", ErrInvalidCredentials
+	}
+	if r, ok := a.bearerTokens[sha256.Sum256([]byte(strings.TrimSpace(h[len(prefix):])))]; ok {
+		return r, nil
+	}
+	return "", ErrInvalidCredentials
+}
+
+// internalCredentials returns a header having an admin credential in the
+// config. The server sends it with requests to other servers, such as
+// heartbeats to the coordinator and snapshot fetches from the primary server,
+// so all servers working together need to accept the credential. It returns
+// nil when the config doesn't have any admin credential.
+func internalCredentials(conf *config.Auth) http.Header {
+	a, ok := NewStaticAuthenticator(conf).(*staticAuthenticator)
+	if !ok {
+		return nil
+	}
+	apiKeys, bearerTokens := conf.APIKeys, conf.BearerTokens
+	if c := conf.Roles[string(RoleAdmin)]; c != nil {
+		apiKeys = append(append([]string{}, apiKeys...), c.APIKeys...)
+		bearerTokens = append(append([]string{}, bearerTokens...), c.BearerTokens...)
+	}
+
+	h := http.Header{}
+	for _, k := range apiKeys {
+		if a.apiKeys[sha256.Sum256([]byte(k))] == RoleAdmin {
+			h.Set("X-API-Key", k)
+			return h
+		}
+	}
+	for _, t := range bearerTokens {
+		if a.bearerTokens[sha256.Sum256([]byte(t))] == RoleAdmin {
+			h.Set("Authorization", "Bearer "+t)
+			return h
+		}
+	}
+	return nil
+}
+
+// credentialsTransport is an http.RoundTripper adding credentials to
+// requests.
+type credentialsTransport struct {
+	header http.Header
+	base   http.RoundTripper
+}
+
+func (t *credentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the given request.
+	r := req.Clone(req.Context())
+	for k, v := range t.header {
+		r.Header[k] = v
+	}
+	return t.base.RoundTrip(r)
+}
+
+// newInternalHTTPClient creates a client which the server uses to send
+// requests to other servers. The client sends the credential returned from
+// internalCredentials when authentication is enabled. Its requests time out
+// after the given duration unless it's 0.
+func newInternalHTTPClient(conf *config.Auth, timeout time.Duration) *http.Client {
+	c := &http.Client{
+		Timeout: timeout,
+	}
+	if h := internalCredentials(conf); h != nil {
+		c.Transport = &credentialsTransport{
+			header: h,
+			base:   http.DefaultTransport,
+		}
+	}
+	return c
+}
+
+// validateAuthConfig returns an error when authentication is enabled on a
+// server sending requests to other servers but the auth config doesn't have
+// an admin credential to send with them.
+func validateAuthConfig(conf *config.Config) error {
+	if !conf.Auth.Enabled() {
+		return nil
+	}
+	if internalCredentials(conf.Auth) != nil {
+		return nil
+	}
+	if conf.Cluster != nil && conf.Cluster.Role != "standalone" {
+		return fmt.Errorf("auth must have an admin credential on a %v of a cluster", conf.Cluster.Role)
+	}
+	if conf.Replication != nil && conf.Replication.Role == "standby" {
+		return fmt.Errorf("auth must have an admin credential on a standby server")
+	}
+	return nil
+}
+
+// isAuthExemptPath returns true when the path can be requested without
+// credentials. An exempt path ending with '*' exempts all paths having the
+// prefix.
+func isAuthExemptPath(exempts []string, path string) bool {
+	for _, e := range exempts {
+		if strings.HasSuffix(e, "*") {
+			if strings.HasPrefix(path, e[:len(e)-1]) {
+				return true
+			}
+		} else if path == e {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate is a middleware rejecting requests which the authenticator
+// doesn't allow. It also sets the role of the request. Requests to exempt
+// paths in the auth section of the config are always allowed with
+// RoleReadOnly.
+func (c *Context) authenticate(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if c.authenticator == nil {
+		next(rw, req)
+		return
+	}
+	if c.config != nil && c.config.Auth != nil && isAuthExemptPath(c.config.Auth.ExemptPaths, req.URL.Path) {
+		c.role = RoleReadOnly
+		next(rw, req)
+		return
+	}
+
+	err := c.authenticator.Authenticate(req.Request)
+	if err == nil {
+		c.role = RoleAdmin
+		if ra, ok := c.authenticator.(RoleAuthenticator); ok {
+			c.role = ra.Role(req.Request)
+		}
+		c.AddLogField("role", c.role)
+		next(rw, req)
+		return
+	}
+	if err == ErrNoCredentials {
+		c.Log().Error("The request doesn't have credentials")
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="sensorbee"`)
+		c.RenderError(jasco.NewError(authenticationRequiredErrorCode,
+			"Authentication is required", http.StatusUnauthorized, err))
+		return
+	}
+	c.ErrLog(err).Error("Cannot authenticate the request")
+	c.RenderError(jasco.NewError(invalidCredentialsErrorCode,
+		"The credentials aren't accepted", http.StatusForbidden, err))
+}
+
+// permissionDeniedError returns an error telling that the role of the
+// request isn't allowed to perform the action.
+func (c *Context) permissionDeniedError(err error) *jasco.Error {
+	c.ErrLog(err).WithField("role", c.role).Error("The request isn't permitted")
+	e := jasco.NewError(permissionDeniedErrorCode, "The role isn't allowed to perform the action",
+		http.StatusForbidden, err)
+	e.Meta["role"] = string(c.role)
+	return e
+}
+
+// authorize is a middleware rejecting requests which the role of the request
+// isn't allowed to send. Read-only users can only send GET requests and
+// requests returned true from isReadOnlyAction. It protects all APIs
+// including topologies, the cluster API, the replication API, and custom
+// routes.
+func (c *APIContext) authorize(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if c.role.canModify() || req.Method == "GET" || req.Method == "HEAD" || isReadOnlyAction(req) {
+		next(rw, req)
+		return
+	}
+	c.RenderError(c.permissionDeniedError(fmt.Errorf("%v cannot send %v requests to %v",
+		c.role, req.Method, req.URL.Path)))
+}
+
+// isReadOnlyAction returns true when the request is sent to an action which
+// doesn't modify anything even though it isn't a GET request, that is, the
+// lint action and the queries action of a topology. Statements sent to the
+// queries action are checked by authorizeStmts.
+func isReadOnlyAction(req *web.Request) bool {
+	if req.Method != "POST" {
+		return false
+	}
+	p := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/api/v1"), "/"), "/")
+	switch {
+	case len(p) == 1 && p[0] == "lint":
+		return true
+	case len(p) == 3 && p[0] == "topologies" && p[2] == "queries":
+		return true
+	}
+	return false
+}
+
+// authorizeStmts returns an error when the role of the request isn't allowed
+// to issue the statements. Read-only users can only issue SELECT, EVAL, and
+// DESCRIBE statements.
+func (tc *topologies) authorizeStmts(stmts []interface{}) *jasco.Error {
+	if tc.role.canModify() {
+		return nil
+	}
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case parser.SelectStmt, parser.SelectUnionStmt, parser.EvalStmt, parser.DescribeStmt:
+			continue
+		}
+		e := tc.permissionDeniedError(fmt.Errorf("%v cannot issue the statement", tc.role))
+		e.Meta["statement"] = fmt.Sprint(stmt)
+		return e
+	}
+	return nil
+}
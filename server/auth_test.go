@@ -0,0 +1,423 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/cluster"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+func TestStaticAuthenticator(t *testing.T) {
+	Convey("Given a static authenticator", t, func() {
+		a := NewStaticAuthenticator(&config.Auth{
+			APIKeys:      []string{"key1"},
+			BearerTokens: []string{"token1"},
+		})
+		So(a, ShouldNotBeNil)
+
+		auth := func(header, value string) error {
+			req, err := http.NewRequest("GET", "/api/v1/topologies", nil)
+			So(err, ShouldBeNil)
+			if header != "" {
+				req.Header.Set(header, value)
+			}
+			return a.Authenticate(req)
+		}
+
+		Convey("When a request has a valid credential", func() {
+			Convey("Then it should be allowed", func() {
+				So(auth("X-API-Key", "key1"), ShouldBeNil)
+				So(auth("Authorization", "Bearer token1"), ShouldBeNil)
+				So(auth("Authorization", "bearer  token1"), ShouldBeNil)
+			})
+		})
+
+		Convey("When a request doesn't have credentials", func() {
+			Convey("Then it should fail with ErrNoCredentials", func() {
+				So(auth("", ""), ShouldEqual, ErrNoCredentials)
+			})
+		})
+
+		Convey("When a request has an invalid credential", func() {
+			Convey("Then it should fail with ErrInvalidCredentials", func() {
+				So(auth("X-API-Key", "token1"), ShouldEqual, ErrInvalidCredentials)
+				So(auth("Authorization", "Bearer key1"), ShouldEqual, ErrInvalidCredentials)
+				So(auth("Authorization", "Basic dXNlcjpwYXNz"), ShouldEqual, ErrInvalidCredentials)
+				So(auth("Authorization", "Bearer "), ShouldEqual, ErrInvalidCredentials)
+			})
+		})
+	})
+
+	Convey("Given an auth config without credentials", t, func() {
+		Convey("Then no authenticator should be created", func() {
+			So(NewStaticAuthenticator(&config.Auth{ExemptPaths: []string{"/metrics"}}), ShouldBeNil)
+			So(NewStaticAuthenticator(nil), ShouldBeNil)
+		})
+	})
+}
+
+func TestStaticAuthenticatorRoles(t *testing.T) {
+	Convey("Given a static authenticator having roles", t, func() {
+		a := NewStaticAuthenticator(&config.Auth{
+			APIKeys: []string{"key1"},
+			Roles: map[string]*config.AuthCredentials{
+				"admin": {
+					BearerTokens: []string{"token1"},
+				},
+				"read_only": {
+					APIKeys:      []string{"key2", "key3"},
+					BearerTokens: []string{"token2", "token1"},
+				},
+			},
+		})
+		So(a, ShouldNotBeNil)
+		ra, ok := a.(RoleAuthenticator)
+		So(ok, ShouldBeTrue)
+
+		role := func(header, value string) Role {
+			req, err := http.NewRequest("GET", "/api/v1/topologies", nil)
+			So(err, ShouldBeNil)
+			if header != "" {
+				req.Header.Set(header, value)
+			}
+			So(a.Authenticate(req), ShouldBeNil)
+			return ra.Role(req)
+		}
+
+		Convey("Then credentials at the top level should have the admin role", func() {
+			So(role("X-API-Key", "key1"), ShouldEqual, RoleAdmin)
+		})
+
+		Convey("Then credentials of the read-only role should have it", func() {
+			So(role("X-API-Key", "key2"), ShouldEqual, RoleReadOnly)
+			So(role("Authorization", "Bearer token2"), ShouldEqual, RoleReadOnly)
+		})
+
+		Convey("Then credentials given to both roles should be read-only", func() {
+			So(role("Authorization", "Bearer token1"), ShouldEqual, RoleReadOnly)
+		})
+
+		Convey("Then only the read-only role shouldn't modify topologies", func() {
+			So(RoleAdmin.canModify(), ShouldBeTrue)
+			So(RoleReadOnly.canModify(), ShouldBeFalse)
+			So(Role("").canModify(), ShouldBeTrue)
+		})
+	})
+}
+
+func TestInternalCredentials(t *testing.T) {
+	Convey("Given auth configs", t, func() {
+		Convey("Then an API key at the top level should be preferred", func() {
+			h := internalCredentials(&config.Auth{
+				APIKeys:      []string{"key1"},
+				BearerTokens: []string{"token1"},
+			})
+			So(h.Get("X-API-Key"), ShouldEqual, "key1")
+			So(h.Get("Authorization"), ShouldBeEmpty)
+		})
+
+		Convey("Then credentials of the admin role should be used", func() {
+			h := internalCredentials(&config.Auth{
+				Roles: map[string]*config.AuthCredentials{
+					"admin": {
+						BearerTokens: []string{"token1"},
+					},
+				},
+			})
+			So(h.Get("Authorization"), ShouldEqual, "Bearer token1")
+		})
+
+		Convey("Then credentials given to both roles should be skipped", func() {
+			h := internalCredentials(&config.Auth{
+				APIKeys:      []string{"key1"},
+				BearerTokens: []string{"token1"},
+				Roles: map[string]*config.AuthCredentials{
+					"read_only": {
+						APIKeys: []string{"key1"},
+					},
+				},
+			})
+			So(h.Get("X-API-Key"), ShouldBeEmpty)
+			So(h.Get("Authorization"), ShouldEqual, "Bearer token1")
+		})
+
+		Convey("Then nothing should be returned without admin credentials", func() {
+			So(internalCredentials(&config.Auth{
+				Roles: map[string]*config.AuthCredentials{
+					"read_only": {
+						APIKeys: []string{"key1"},
+					},
+				},
+			}), ShouldBeNil)
+			So(internalCredentials(&config.Auth{}), ShouldBeNil)
+			So(internalCredentials(nil), ShouldBeNil)
+		})
+	})
+}
+
+func TestValidateAuthConfig(t *testing.T) {
+	Convey("Given a config having only read-only credentials", t, func() {
+		newConfig := func(m data.Map) *config.Config {
+			m["auth"] = data.Map{
+				"roles": data.Map{
+					"read_only": data.Map{
+						"api_keys": data.Array{data.String("key1")},
+					},
+				},
+			}
+			c, err := config.New(m)
+			So(err, ShouldBeNil)
+			return c
+		}
+
+		Convey("Then it should be valid on a standalone server", func() {
+			So(validateAuthConfig(newConfig(data.Map{})), ShouldBeNil)
+			So(validateAuthConfig(newConfig(data.Map{
+				"replication": data.Map{"role": data.String("primary")},
+			})), ShouldBeNil)
+		})
+
+		Convey("Then it should be invalid on a server sending requests to other servers", func() {
+			for _, m := range []data.Map{
+				{"cluster": data.Map{"role": data.String("coordinator")}},
+				{"cluster": data.Map{
+					"role":          data.String("worker"),
+					"coordinator":   data.String("http://localhost:15601"),
+					"advertise_url": data.String("http://localhost:15602"),
+				}},
+				{"replication": data.Map{
+					"role":    data.String("standby"),
+					"primary": data.String("http://localhost:15601"),
+				}},
+			} {
+				So(validateAuthConfig(newConfig(m)), ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestClusterAgentCredentials(t *testing.T) {
+	Convey("Given a coordinator requiring authentication", t, func() {
+		s := newTestServer(data.Map{
+			"auth": data.Map{
+				"api_keys": data.Array{data.String("key1")},
+			},
+			"cluster": data.Map{
+				"role": data.String("coordinator"),
+			},
+		})
+		Reset(s.Close)
+
+		newAgent := func(key string) *cluster.Agent {
+			c, err := config.New(data.Map{
+				"auth": data.Map{
+					"api_keys": data.Array{data.String(key)},
+				},
+				"cluster": data.Map{
+					"role":          data.String("worker"),
+					"coordinator":   data.String(s.URL),
+					"advertise_url": data.String("http://localhost:15602"),
+				},
+			})
+			So(err, ShouldBeNil)
+			gvars, err := SetUpContextGlobalVariables(c)
+			So(err, ShouldBeNil)
+			return NewClusterAgent(gvars)
+		}
+
+		Convey("When a worker has the same credential", func() {
+			a := newAgent("key1")
+
+			Convey("Then its heartbeat should be accepted", func() {
+				So(a.Heartbeat(), ShouldBeNil)
+				So(s.gvars.Coordinator.Workers(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When a worker has a different credential", func() {
+			a := newAgent("key2")
+
+			Convey("Then its heartbeat should be rejected", func() {
+				err := a.Heartbeat()
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "403")
+			})
+		})
+	})
+}
+
+func TestIsAuthExemptPath(t *testing.T) {
+	Convey("Given exempt paths", t, func() {
+		exempts := []string{"/metrics", "/api/v1/runtime*"}
+
+		Convey("Then paths should be matched exactly or by prefix", func() {
+			So(isAuthExemptPath(exempts, "/metrics"), ShouldBeTrue)
+			So(isAuthExemptPath(exempts, "/metrics/x"), ShouldBeFalse)
+			So(isAuthExemptPath(exempts, "/api/v1/runtime"), ShouldBeTrue)
+			So(isAuthExemptPath(exempts, "/api/v1/runtime_status"), ShouldBeTrue)
+			So(isAuthExemptPath(exempts, "/api/v1/topologies"), ShouldBeFalse)
+			So(isAuthExemptPath(nil, "/metrics"), ShouldBeFalse)
+		})
+	})
+}
+
+// requestWithAPIKey sends a request having the API key to the server.
+func requestWithAPIKey(s *testServer, key, method, path string, body interface{}) (*http.Response, map[string]interface{}) {
+	req := s.newRequest(method, path, body)
+	req.Header.Set("X-API-Key", key)
+	res, _, js := s.do(req)
+	return res, js
+}
+
+func TestAuthorization(t *testing.T) {
+	Convey("Given an API server having roles", t, func() {
+		s := newTestServer(data.Map{
+			"auth": data.Map{
+				"api_keys": data.Array{data.String("key1")},
+				"roles": data.Map{
+					"read_only": data.Map{
+						"api_keys": data.Array{data.String("key2")},
+					},
+				},
+			},
+			"cluster": data.Map{
+				"role": data.String("coordinator"),
+			},
+			"replication": data.Map{
+				"role": data.String("primary"),
+			},
+		})
+		Reset(s.Close)
+
+		request := func(key, method, path string, body interface{}) (*http.Response, map[string]interface{}) {
+			return requestWithAPIKey(s, key, method, path, body)
+		}
+		heartbeat := map[string]interface{}{
+			"url":        "http://localhost:15602",
+			"topologies": []interface{}{},
+		}
+
+		Convey("When a read-only user promotes the server", func() {
+			res, js := request("key2", "POST", "/replication/promote", nil)
+
+			Convey("Then it should be rejected", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+				So(errorCode(js), ShouldEqual, permissionDeniedErrorCode)
+			})
+		})
+
+		Convey("When an admin user promotes the server", func() {
+			res, js := request("key1", "POST", "/replication/promote", nil)
+
+			Convey("Then it should reach the action", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusConflict)
+				So(errorCode(js), ShouldEqual, promotionErrorCode)
+			})
+		})
+
+		Convey("When a read-only user sends a heartbeat", func() {
+			res, js := request("key2", "POST", "/cluster/workers", heartbeat)
+
+			Convey("Then it should be rejected", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+				So(errorCode(js), ShouldEqual, permissionDeniedErrorCode)
+				So(s.gvars.Coordinator.Workers(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When an admin user registers a worker", func() {
+			res, _ := request("key1", "POST", "/cluster/workers", heartbeat)
+			So(res.StatusCode, ShouldEqual, http.StatusOK)
+
+			Convey("Then a read-only user should list it", func() {
+				res, js := request("key2", "GET", "/cluster/workers", nil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js["workers"], ShouldHaveLength, 1)
+			})
+
+			Convey("Then a read-only user shouldn't remove it", func() {
+				res, js := request("key2", "DELETE", "/cluster/workers?url=http://localhost:15602", nil)
+				So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+				So(errorCode(js), ShouldEqual, permissionDeniedErrorCode)
+				So(s.gvars.Coordinator.Workers(), ShouldHaveLength, 1)
+			})
+
+			Convey("Then an admin user should remove it", func() {
+				res, _ := request("key1", "DELETE", "/cluster/workers?url=http://localhost:15602", nil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(s.gvars.Coordinator.Workers(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a read-only user lints queries", func() {
+			res, _ := request("key2", "POST", "/lint", map[string]interface{}{
+				"queries": "EVAL 1;",
+			})
+
+			Convey("Then it should be allowed", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+
+	Convey("Given a standalone API server having roles and a topology", t, func() {
+		s := newTestServer(data.Map{
+			"auth": data.Map{
+				"api_keys": data.Array{data.String("key1")},
+				"roles": data.Map{
+					"read_only": data.Map{
+						"api_keys": data.Array{data.String("key2")},
+					},
+				},
+			},
+		})
+		Reset(s.Close)
+
+		request := func(key, method, path string, body interface{}) (*http.Response, map[string]interface{}) {
+			return requestWithAPIKey(s, key, method, path, body)
+		}
+		res, _ := request("key1", "POST", "/topologies", map[string]interface{}{"name": "test"})
+		So(res.StatusCode, ShouldEqual, http.StatusOK)
+
+		Convey("When a read-only user creates a topology", func() {
+			res, js := request("key2", "POST", "/topologies", map[string]interface{}{"name": "test2"})
+
+			Convey("Then it should be rejected", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+				So(errorCode(js), ShouldEqual, permissionDeniedErrorCode)
+			})
+		})
+
+		Convey("When a read-only user sends statements to the queries action", func() {
+			Convey("Then EVAL should be allowed", func() {
+				res, js := request("key2", "POST", "/topologies/test/queries", map[string]interface{}{
+					"queries": "EVAL 1 + 2;",
+				})
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js["result"], ShouldEqual, 3)
+			})
+
+			Convey("Then CREATE STREAM should be rejected", func() {
+				res, js := request("key2", "POST", "/topologies/test/queries", map[string]interface{}{
+					"queries": "CREATE STREAM s AS SELECT ISTREAM * FROM t [RANGE 1 TUPLES];",
+				})
+				So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+				So(errorCode(js), ShouldEqual, permissionDeniedErrorCode)
+			})
+		})
+
+		Convey("When a read-only user deletes the topology", func() {
+			res, js := request("key2", "DELETE", "/topologies/test", nil)
+
+			Convey("Then it should be rejected", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusForbidden)
+				So(errorCode(js), ShouldEqual, permissionDeniedErrorCode)
+				_, err := s.gvars.Topologies.Lookup("test")
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type backfills struct {
+	*topologies
+}
+
+func setUpBackfillsRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(backfills{}, "/:topologyName/backfills")
+	root.Middleware((*backfills).fetchTopologyMiddleware)
+	root.Post("/", (*backfills).Create)
+	root.Get("/", (*backfills).Index)
+	root.Get("/:streamName", (*backfills).Show)
+}
+
+func (bc *backfills) fetchTopologyMiddleware(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if bc.fetchTopology() == nil {
+		return
+	}
+	next(rw, req)
+}
+
+// Create creates a stream from a CREATE STREAM statement and backfills it
+// with the history of its rewindable input source.
+func (bc *backfills) Create(rw web.ResponseWriter, req *web.Request) {
+	var js map[string]interface{}
+	if apiErr := bc.ParseBody(&js); apiErr != nil {
+		bc.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		bc.RenderError(apiErr)
+		return
+	}
+	form, err := data.NewMap(js)
+	if err != nil {
+		bc.ErrLog(err).WithField("body", js).
+			Error("The request json may contain invalid value")
+		bc.RenderError(jasco.NewError(formValidationErrorCode, "The request json may contain invalid values.",
+			http.StatusBadRequest, err))
+		return
+	}
+
+	stmt, conf, apiErr := bc.parseBackfill(form)
+	if apiErr != nil {
+		bc.RenderError(apiErr)
+		return
+	}
+
+	n, err := bc.topology.Backfill(stmt, conf)
+	if err != nil {
+		bc.ErrLog(err).Error("Cannot backfill the stream")
+		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot backfill the stream", http.StatusBadRequest, err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = stmt.String()
+		bc.RenderError(e)
+		return
+	}
+	st, err := bc.topology.BackfillStatus(n.Name())
+	if err != nil {
+		bc.ErrLog(err).Error("Cannot get the progress of the backfill")
+		bc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	bc.Render(map[string]interface{}{
+		"topology": bc.topologyName,
+		"backfill": st,
+	})
+}
+
+func (bc *backfills) parseBackfill(form data.Map) (*parser.CreateStreamAsSelectStmt, *bql.BackfillConfig, *jasco.Error) {
+	v, ok := form["query"]
+	if !ok {
+		bc.Log().Error("The request json doesn't have 'query' field")
+		return nil, nil, jasco.NewError(formValidationErrorCode, "'query' field is missing",
+			http.StatusBadRequest, nil)
+	}
+	query, err := data.AsString(v)
+	if err != nil {
+		bc.ErrLog(err).Error("'query' must be a string")
+		return nil, nil, jasco.NewError(formValidationErrorCode, "'query' field must be a string",
+			http.StatusBadRequest, err)
+	}
+	s, _, err := parser.New().ParseStmt(query)
+	if err != nil {
+		bc.Log().WithField("parse_errors", err.Error()).
+			WithField("statement", query).Error("Cannot parse a statement")
+		e := jasco.NewError(bqlStmtParseErrorCode, "Cannot parse a BQL statement", http.StatusBadRequest, err)
+		e.Meta["statement"] = query
+		return nil, nil, e
+	}
+	stmt, ok := s.(parser.CreateStreamAsSelectStmt)
+	if !ok {
+		bc.Log().WithField("statement", query).Error("The statement isn't CREATE STREAM")
+		return nil, nil, jasco.NewError(formValidationErrorCode, "'query' field must be a CREATE STREAM statement",
+			http.StatusBadRequest, nil)
+	}
+
+	conf := &bql.BackfillConfig{}
+	for _, f := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &conf.Since}, {"until", &conf.Until}} {
+		v, ok := form[f.name]
+		if !ok {
+			continue
+		}
+		t, err := data.ToTimestamp(v)
+		if err != nil {
+			bc.ErrLog(err).Errorf("'%v' must be a timestamp", f.name)
+			return nil, nil, jasco.NewError(formValidationErrorCode, "'"+f.name+"' field must be a timestamp",
+				http.StatusBadRequest, err)
+		}
+		*f.dst = t
+	}
+	if v, ok := form["buffer_size"]; ok {
+		n, err := data.AsInt(v)
+		if err != nil || n <= 0 {
+			bc.ErrLog(err).Error("'buffer_size' must be a positive integer")
+			return nil, nil, jasco.NewError(formValidationErrorCode, "'buffer_size' field must be a positive integer",
+				http.StatusBadRequest, err)
+		}
+		conf.BufferSize = int(n)
+	}
+	return &stmt, conf, nil
+}
+
+// Index returns progresses of all backfills in the topology.
+func (bc *backfills) Index(rw web.ResponseWriter, req *web.Request) {
+	sts := bc.topology.BackfillStatuses()
+	bc.Render(map[string]interface{}{
+		"topology":  bc.topologyName,
+		"count":     len(sts),
+		"backfills": sts,
+	})
+}
+
+// Show returns the progress of the backfill of a stream.
+func (bc *backfills) Show(rw web.ResponseWriter, req *web.Request) {
+	name := bc.PathParams().String("streamName", "")
+	st, err := bc.topology.BackfillStatus(name)
+	if err != nil {
+		if core.IsNotExist(err) {
+			bc.ErrLog(err).Error("Cannot find the backfill")
+			bc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+				"The backfill was not found", http.StatusNotFound, err))
+			return
+		}
+		bc.ErrLog(err).Error("Cannot get the progress of the backfill")
+		bc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	bc.Render(map[string]interface{}{
+		"topology": bc.topologyName,
+		"backfill": st,
+	})
+}
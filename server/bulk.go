@@ -0,0 +1,214 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// maxBulkOperations is the maximum number of operations in a bulk request.
+const maxBulkOperations = 1000
+
+// bulkError is an error of an operation having the error code reported in
+// the result of the operation.
+type bulkError struct {
+	code string
+	err  error
+}
+
+func (e *bulkError) Error() string {
+	return e.err.Error()
+}
+
+// bulkOperation is an operation in a bulk request.
+type bulkOperation struct {
+	// Op is "create" or "delete".
+	Op   string
+	Name string
+
+	// Config and Queries are only used by "create". Queries are issued to
+	// the topology after it's created.
+	Config  data.Map
+	Queries string
+}
+
+// Bulk creates and deletes multiple topologies in one request. Operations are
+// executed in order and a failure of an operation doesn't stop following
+// ones. A topology created by a failed operation is removed so that the
+// operation can be retried. The response has the result of each operation.
+func (tc *topologies) Bulk(rw web.ResponseWriter, req *web.Request) {
+	var js map[string]interface{}
+	if apiErr := tc.ParseBody(&js); apiErr != nil {
+		tc.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		tc.RenderError(apiErr)
+		return
+	}
+	form, err := data.NewMap(js)
+	if err != nil {
+		tc.ErrLog(err).WithField("body", js).Error("The request json may contain invalid value")
+		tc.RenderError(jasco.NewError(formValidationErrorCode, "The request json may contain invalid values.",
+			http.StatusBadRequest, err))
+		return
+	}
+
+	ops, err := parseBulkOperations(form)
+	if err != nil {
+		tc.ErrLog(err).Error("Invalid bulk operations")
+		e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta["operations"] = []string{err.Error()}
+		tc.RenderError(e)
+		return
+	}
+
+	owner := requestOwner(req)
+	results := make([]map[string]interface{}, len(ops))
+	succeeded := 0
+	for i, op := range ops {
+		res := map[string]interface{}{
+			"op":   op.Op,
+			"name": op.Name,
+		}
+		var err error
+		switch op.Op {
+		case "create":
+			err = tc.bulkCreate(owner, op)
+		case "delete":
+			var stopped bool
+			if stopped, err = tc.destroyTopology(op.Name); err == nil && !stopped {
+				res["warning"] = "the topology wasn't stopped correctly"
+			}
+		}
+		if err != nil {
+			tc.ErrLog(err).WithField("operation", op.Op).WithField("name", op.Name).
+				Error("Cannot execute a bulk operation")
+			res["status"] = "error"
+			res["error"] = err.Error()
+			if be, ok := err.(*bulkError); ok {
+				res["code"] = be.code
+			}
+		} else {
+			res["status"] = "ok"
+			succeeded++
+		}
+		results[i] = res
+	}
+
+	tc.Render(map[string]interface{}{
+		"results": results,
+		"summary": map[string]interface{}{
+			"total":     len(ops),
+			"succeeded": succeeded,
+			"failed":    len(ops) - succeeded,
+		},
+	})
+}
+
+// bulkCreate creates a topology and issues the queries to it. The topology is
+// removed when one of the queries fails.
+func (tc *topologies) bulkCreate(owner string, op *bulkOperation) error {
+	stmts, err := parser.New().ParseStmts(op.Queries)
+	if err != nil {
+		return &bulkError{bqlStmtParseErrorCode, fmt.Errorf("cannot parse queries: %v", err)}
+	}
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case parser.SelectStmt, parser.SelectUnionStmt, parser.EvalStmt, parser.DescribeStmt:
+			return &bulkError{bqlStmtProcessingErrorCode,
+				fmt.Errorf("a bulk operation cannot have the statement: %v", stmt)}
+		}
+	}
+
+	tb, err := tc.createTopology(op.Name, op.Config)
+	if err != nil {
+		if os.IsExist(err) {
+			return &bulkError{formValidationErrorCode, errors.New("the name is already taken")}
+		}
+		return err
+	}
+	if err := tc.addBulkStmts(tb, owner, op.Name, stmts); err != nil {
+		if _, err := tc.destroyTopology(op.Name); err != nil {
+			tc.ErrLog(err).Error("Cannot remove the topology created by the failed bulk operation")
+		}
+		return err
+	}
+	return nil
+}
+
+func (tc *topologies) addBulkStmts(tb *bql.TopologyBuilder, owner, name string, stmts []interface{}) error {
+	for _, stmt := range stmts {
+		if _, err := tb.AddStmtAs(owner, stmt); err != nil {
+			return &bulkError{stmtErrorCode(err), fmt.Errorf("cannot process a statement '%v': %v", stmt, err)}
+		}
+		if tc.recorder != nil {
+			if err := tc.recorder.Append(name, fmt.Sprint(stmt)); err != nil {
+				tc.ErrLog(err).Error("Cannot persist the statement")
+			}
+		}
+	}
+	return nil
+}
+
+// parseBulkOperations validates the "operations" field of a bulk request.
+func parseBulkOperations(form data.Map) ([]*bulkOperation, error) {
+	v, ok := form["operations"]
+	if !ok {
+		return nil, errors.New("field is missing")
+	}
+	a, err := data.AsArray(v)
+	if err != nil {
+		return nil, errors.New("value must be an array")
+	}
+	if len(a) > maxBulkOperations {
+		return nil, fmt.Errorf("the number of operations must be at most %v", maxBulkOperations)
+	}
+
+	ops := make([]*bulkOperation, len(a))
+	for i, o := range a {
+		m, err := data.AsMap(o)
+		if err != nil {
+			return nil, fmt.Errorf("operation %v must be an object", i)
+		}
+		op := &bulkOperation{}
+		for k, v := range m {
+			var err error
+			switch k {
+			case "op":
+				op.Op, err = data.AsString(v)
+			case "name":
+				op.Name, err = data.AsString(v)
+			case "config":
+				op.Config, err = data.AsMap(v)
+			case "queries":
+				op.Queries, err = data.AsString(v)
+			default:
+				err = errors.New("unknown field")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%v of operation %v is invalid: %v", k, i, err)
+			}
+		}
+		if err := core.ValidateSymbol(op.Name); err != nil {
+			return nil, fmt.Errorf("operation %v has an invalid name: %v", i, err)
+		}
+		switch op.Op {
+		case "create":
+		case "delete":
+			if len(op.Config) > 0 || op.Queries != "" {
+				return nil, fmt.Errorf("delete operation %v cannot have config or queries", i)
+			}
+		default:
+			return nil, fmt.Errorf(`op of operation %v must be "create" or "delete"`, i)
+		}
+		ops[i] = op
+	}
+	return ops, nil
+}
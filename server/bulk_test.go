@@ -0,0 +1,235 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestParseBulkOperations(t *testing.T) {
+	Convey("Given valid bulk operations", t, func() {
+		form := data.Map{
+			"operations": data.Array{
+				data.Map{
+					"op":      data.String("create"),
+					"name":    data.String("t1"),
+					"config":  data.Map{"key": data.String("value")},
+					"queries": data.String("CREATE STATE s TYPE running_stats WITH field=\"x\";"),
+				},
+				data.Map{
+					"op":   data.String("delete"),
+					"name": data.String("t2"),
+				},
+			},
+		}
+
+		Convey("When parsing them", func() {
+			ops, err := parseBulkOperations(form)
+
+			Convey("Then they should be parsed in order", func() {
+				So(err, ShouldBeNil)
+				So(ops, ShouldResemble, []*bulkOperation{
+					{
+						Op:      "create",
+						Name:    "t1",
+						Config:  data.Map{"key": data.String("value")},
+						Queries: "CREATE STATE s TYPE running_stats WITH field=\"x\";",
+					},
+					{
+						Op:   "delete",
+						Name: "t2",
+					},
+				})
+			})
+		})
+	})
+
+	Convey("Given invalid bulk operations", t, func() {
+		op := func(m data.Map) data.Map {
+			return data.Map{"operations": data.Array{m}}
+		}
+		tooMany := make(data.Array, maxBulkOperations+1)
+		for i := range tooMany {
+			tooMany[i] = data.Map{"op": data.String("delete"), "name": data.String("t")}
+		}
+
+		cases := []struct {
+			title string
+			form  data.Map
+			err   string
+		}{
+			{"operations are missing", data.Map{}, "field is missing"},
+			{"operations isn't an array", data.Map{"operations": data.Map{}}, "value must be an array"},
+			{"there're too many operations", data.Map{"operations": tooMany}, "at most 1000"},
+			{"an operation isn't an object", data.Map{"operations": data.Array{data.Int(1)}}, "operation 0 must be an object"},
+			{"an operation has an unknown field", op(data.Map{
+				"op": data.String("create"), "name": data.String("t"), "owner": data.String("a"),
+			}), "owner of operation 0 is invalid: unknown field"},
+			{"a field has a wrong type", op(data.Map{
+				"op": data.String("create"), "name": data.String("t"), "config": data.String("a"),
+			}), "config of operation 0 is invalid"},
+			{"the name is missing", op(data.Map{"op": data.String("create")}), "operation 0 has an invalid name"},
+			{"the name is invalid", op(data.Map{
+				"op": data.String("create"), "name": data.String("in valid"),
+			}), "operation 0 has an invalid name"},
+			{"the op is unknown", op(data.Map{
+				"op": data.String("update"), "name": data.String("t"),
+			}), `op of operation 0 must be "create" or "delete"`},
+			{"a delete operation has queries", op(data.Map{
+				"op": data.String("delete"), "name": data.String("t"), "queries": data.String("a"),
+			}), "delete operation 0 cannot have config or queries"},
+			{"a delete operation has a config", op(data.Map{
+				"op": data.String("delete"), "name": data.String("t"), "config": data.Map{"a": data.Int(1)},
+			}), "delete operation 0 cannot have config or queries"},
+		}
+
+		for _, c := range cases {
+			c := c
+			Convey("When "+c.title, func() {
+				_, err := parseBulkOperations(c.form)
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldContainSubstring, c.err)
+				})
+			})
+		}
+	})
+}
+
+func TestBulk(t *testing.T) {
+	Convey("Given an API server", t, func() {
+		s := newTestServer(data.Map{})
+		Reset(s.Close)
+
+		Convey("When a later statement of a create operation fails", func() {
+			res, js := s.request("POST", "/topologies/bulk", map[string]interface{}{
+				"operations": []interface{}{
+					map[string]interface{}{
+						"op":      "create",
+						"name":    "t1",
+						"queries": "CREATE STATE s TYPE running_stats WITH field=\"x\";",
+					},
+					map[string]interface{}{
+						"op":      "create",
+						"name":    "t2",
+						"queries": "CREATE STATE s TYPE running_stats WITH field=\"x\"; CREATE STATE u TYPE no_such_type;",
+					},
+					map[string]interface{}{
+						"op":   "create",
+						"name": "t3",
+					},
+				},
+			})
+
+			Convey("Then the request should succeed", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js["summary"], ShouldResemble, map[string]interface{}{
+					"total":     3.0,
+					"succeeded": 2.0,
+					"failed":    1.0,
+				})
+			})
+
+			Convey("Then the operation should fail", func() {
+				results := js["results"].([]interface{})
+				So(results, ShouldHaveLength, 3)
+				r := results[1].(map[string]interface{})
+				So(r["status"], ShouldEqual, "error")
+				So(r["code"], ShouldEqual, bqlStmtProcessingErrorCode)
+				So(r["error"], ShouldContainSubstring, "no_such_type")
+				So(results[0].(map[string]interface{})["status"], ShouldEqual, "ok")
+				So(results[2].(map[string]interface{})["status"], ShouldEqual, "ok")
+			})
+
+			Convey("Then the topology created by the operation should be removed", func() {
+				_, err := s.gvars.Topologies.Lookup("t2")
+				So(err, ShouldNotBeNil)
+
+				res, js := s.request("GET", "/topologies/t2", nil)
+				So(res.StatusCode, ShouldEqual, http.StatusNotFound)
+				So(errorCode(js), ShouldEqual, requestResourceNotFoundErrorCode)
+			})
+
+			Convey("Then other topologies should be created", func() {
+				for _, name := range []string{"t1", "t3"} {
+					res, _ := s.request("GET", "/topologies/"+name, nil)
+					So(res.StatusCode, ShouldEqual, http.StatusOK)
+				}
+			})
+
+			Convey("Then the failed operation can be retried", func() {
+				res, js := s.request("POST", "/topologies/bulk", map[string]interface{}{
+					"operations": []interface{}{
+						map[string]interface{}{
+							"op":      "create",
+							"name":    "t2",
+							"queries": "CREATE STATE s TYPE running_stats WITH field=\"x\";",
+						},
+					},
+				})
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				results := js["results"].([]interface{})
+				So(results[0].(map[string]interface{})["status"], ShouldEqual, "ok")
+			})
+		})
+
+		Convey("When a create operation has a SELECT statement", func() {
+			_, js := s.request("POST", "/topologies/bulk", map[string]interface{}{
+				"operations": []interface{}{
+					map[string]interface{}{
+						"op":      "create",
+						"name":    "t1",
+						"queries": "SELECT RSTREAM * FROM s [RANGE 1 TUPLES];",
+					},
+				},
+			})
+
+			Convey("Then it should fail without creating the topology", func() {
+				r := js["results"].([]interface{})[0].(map[string]interface{})
+				So(r["status"], ShouldEqual, "error")
+				So(r["code"], ShouldEqual, bqlStmtProcessingErrorCode)
+				_, err := s.gvars.Topologies.Lookup("t1")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the operations are invalid", func() {
+			res, js := s.request("POST", "/topologies/bulk", map[string]interface{}{
+				"operations": []interface{}{
+					map[string]interface{}{
+						"op":   "create",
+						"name": "t1",
+					},
+					map[string]interface{}{
+						"op": "drop",
+					},
+				},
+			})
+
+			Convey("Then the request should fail", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(errorCode(js), ShouldEqual, formValidationErrorCode)
+				meta := js["error"].(map[string]interface{})["meta"].(map[string]interface{})
+				So(strings.Join(toStrings(meta["operations"]), ""), ShouldContainSubstring, "operation 1")
+			})
+
+			Convey("Then no operation should be executed", func() {
+				_, err := s.gvars.Topologies.Lookup("t1")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func toStrings(v interface{}) []string {
+	a, _ := v.([]interface{})
+	res := make([]string, len(a))
+	for i, s := range a {
+		res[i], _ = s.(string)
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type checkpoints struct {
+	*topologies
+}
+
+func setUpCheckpointsRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(checkpoints{}, "/:topologyName/checkpoints")
+	root.Middleware((*checkpoints).fetchTopologyMiddleware)
+	root.Get("/", (*checkpoints).Show)
+	root.Post("/", (*checkpoints).Create)
+	root.Post("/restore", (*checkpoints).Restore)
+}
+
+func (cc *checkpoints) fetchTopologyMiddleware(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if cc.fetchTopology() == nil {
+		return
+	}
+	next(rw, req)
+}
+
+// checkEnabled renders an error and returns false when checkpoints aren't
+// enabled in the topology.
+func (cc *checkpoints) checkEnabled(tp core.Topology) bool {
+	if en, _ := data.AsBool(tp.CheckpointStatus()["enabled"]); en {
+		return true
+	}
+	err := errors.New("checkpoints aren't enabled")
+	cc.ErrLog(err).Error("Cannot process the checkpoint request")
+	cc.RenderError(jasco.NewError(checkpointDisabledErrorCode,
+		"Checkpoints aren't enabled in the server config.", http.StatusBadRequest, err))
+	return false
+}
+
+// Show returns the status of checkpoints of the topology.
+func (cc *checkpoints) Show(rw web.ResponseWriter, req *web.Request) {
+	cc.Render(map[string]interface{}{
+		"topology":   cc.topologyName,
+		"checkpoint": cc.topology.Topology().CheckpointStatus(),
+	})
+}
+
+// Create takes a checkpoint of the topology.
+func (cc *checkpoints) Create(rw web.ResponseWriter, req *web.Request) {
+	tp := cc.topology.Topology()
+	if !cc.checkEnabled(tp) {
+		return
+	}
+	info, err := tp.Checkpoint()
+	if err != nil {
+		cc.ErrLog(err).Error("Cannot take a checkpoint of the topology")
+		cc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	cc.Render(map[string]interface{}{
+		"topology":   cc.topologyName,
+		"checkpoint": info,
+	})
+}
+
+// Restore restores states of the topology from the latest checkpoint. The
+// checkpoint is null in the response when there's no checkpoint.
+func (cc *checkpoints) Restore(rw web.ResponseWriter, req *web.Request) {
+	tp := cc.topology.Topology()
+	if !cc.checkEnabled(tp) {
+		return
+	}
+	info, err := tp.RestoreCheckpoint()
+	if err != nil {
+		cc.ErrLog(err).Error("Cannot restore the topology from the checkpoint")
+		cc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	res := map[string]interface{}{
+		"topology":   cc.topologyName,
+		"checkpoint": nil,
+	}
+	if info != nil {
+		res["checkpoint"] = info
+	}
+	cc.Render(res)
+}
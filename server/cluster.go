@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/server/cluster"
+)
+
+type clusterWorkers struct {
+	*APIContext
+}
+
+func setUpClusterRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(clusterWorkers{}, "/cluster")
+	root.Middleware((*clusterWorkers).requireCoordinator)
+	root.Post("/workers", (*clusterWorkers).Heartbeat)
+	root.Get("/workers", (*clusterWorkers).Index)
+	root.Delete("/workers", (*clusterWorkers).Destroy)
+}
+
+func (cw *clusterWorkers) requireCoordinator(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if cw.coordinator == nil {
+		cw.Log().Error("The server isn't running as a coordinator")
+		cw.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The server isn't running as a coordinator of a cluster", http.StatusNotFound, nil))
+		return
+	}
+	next(rw, req)
+}
+
+// Heartbeat registers a worker or updates its registration. Workers call
+// this action periodically.
+func (cw *clusterWorkers) Heartbeat(rw web.ResponseWriter, req *web.Request) {
+	var h cluster.HeartbeatRequest
+	if apiErr := cw.ParseBody(&h); apiErr != nil {
+		cw.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		cw.RenderError(apiErr)
+		return
+	}
+	if err := cw.coordinator.Heartbeat(h.URL, h.Topologies); err != nil {
+		cw.ErrLog(err).Error("Cannot register the worker")
+		e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta["url"] = []string{err.Error()}
+		cw.RenderError(e)
+		return
+	}
+	cw.Render(map[string]interface{}{})
+}
+
+// Index returns a list of registered workers.
+func (cw *clusterWorkers) Index(rw web.ResponseWriter, req *web.Request) {
+	cw.Render(map[string]interface{}{
+		"workers": cw.coordinator.Workers(),
+	})
+}
+
+// Destroy removes a worker specified by the "url" query parameter from the
+// cluster. Topologies placed on the worker are forgotten by the coordinator
+// until the worker sends a heartbeat again.
+func (cw *clusterWorkers) Destroy(rw web.ResponseWriter, req *web.Request) {
+	url := req.URL.Query().Get("url")
+	if err := cw.coordinator.RemoveWorker(url); err != nil {
+		if core.IsNotExist(err) {
+			cw.ErrLog(err).Error("The worker is not registered")
+			cw.RenderError(jasco.NewError(requestResourceNotFoundErrorCode, "The worker isn't registered",
+				http.StatusNotFound, err))
+			return
+		}
+		cw.ErrLog(err).Error("Cannot remove the worker")
+		cw.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	cw.Render(map[string]interface{}{})
+}
+
+// proxyToWorker forwards requests for topologies placed on workers when the
+// server is a coordinator of a cluster. A new topology is placed on a worker
+// before the request to create it is forwarded. Requests for topologies which
+// aren't placed on any worker, e.g. ones created from the config file of the
+// coordinator, are processed locally.
+func (tc *topologies) proxyToWorker(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if tc.coordinator == nil {
+		next(rw, req)
+		return
+	}
+
+	if tc.topologyName == "" {
+		if req.Method == "POST" && strings.HasSuffix(strings.TrimRight(req.URL.Path, "/"), "/topologies/bulk") {
+			tc.Log().Error("The bulk API isn't supported by a coordinator")
+			e := jasco.NewError(formValidationErrorCode, "The bulk API isn't supported by a coordinator of a cluster.",
+				http.StatusBadRequest, nil)
+			e.Meta["operations"] = []string{"send operations to each worker instead"}
+			tc.RenderError(e)
+			return
+		}
+		if req.Method == "POST" {
+			tc.placeAndProxy(rw, req, next)
+			return
+		}
+		next(rw, req)
+		return
+	}
+
+	w, err := tc.coordinator.Lookup(tc.topologyName)
+	if err != nil {
+		if core.IsNotExist(err) {
+			next(rw, req)
+			return
+		}
+		tc.ErrLog(err).Error("The worker running the topology is unavailable")
+		tc.RenderError(jasco.NewError(workerUnavailableErrorCode, "The worker running the topology is unavailable",
+			http.StatusServiceUnavailable, err))
+		return
+	}
+	tc.proxy(w, rw, req)
+
+	if req.Method == "DELETE" && strings.HasSuffix(strings.TrimRight(req.URL.Path, "/"), "/topologies/"+tc.topologyName) &&
+		rw.Status() < 300 {
+		tc.coordinator.Unplace(tc.topologyName)
+	}
+}
+
+// placeAndProxy places a new topology on a worker and forwards the request to
+// create it. When the request body doesn't have a valid name, the request is
+// processed locally so that the same validation errors are returned.
+func (tc *topologies) placeAndProxy(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot read the request body")
+		tc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var form struct {
+		Name interface{} `json:"name"`
+	}
+	name := ""
+	if err := json.Unmarshal(body, &form); err == nil {
+		name, _ = form.Name.(string)
+	}
+	if core.ValidateSymbol(name) != nil {
+		next(rw, req)
+		return
+	}
+	if _, err := tc.topologies.Lookup(name); err == nil {
+		// Let Create report that the name is already taken.
+		next(rw, req)
+		return
+	}
+
+	w, err := tc.coordinator.Place(name)
+	if err != nil {
+		if err == cluster.ErrNoWorker {
+			tc.ErrLog(err).Error("Cannot place the topology")
+			tc.RenderError(jasco.NewError(workerUnavailableErrorCode, "No worker is available",
+				http.StatusServiceUnavailable, err))
+			return
+		}
+		tc.ErrLog(err).Error("The name is already placed")
+		e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+			http.StatusBadRequest, nil)
+		e.Meta["name"] = []string{"already taken"}
+		tc.RenderError(e)
+		return
+	}
+	tc.AddLogField("worker", w)
+
+	if !tc.proxy(w, rw, req) || rw.Status() >= 300 {
+		tc.coordinator.Unplace(name)
+	}
+}
+
+// proxy forwards the request to the worker. It returns false when the worker
+// couldn't be reached.
+func (tc *topologies) proxy(worker string, rw web.ResponseWriter, req *web.Request) bool {
+	ok := true
+	err := cluster.Proxy(worker, rw, req.Request, func(err error) {
+		ok = false
+		tc.ErrLog(err).Error("Cannot forward the request to the worker")
+		tc.RenderError(jasco.NewError(workerUnavailableErrorCode, "The worker is unavailable",
+			http.StatusServiceUnavailable, err))
+	})
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot forward the request to the worker")
+		tc.RenderError(jasco.NewInternalServerError(err))
+		return false
+	}
+	return ok
+}
+
+// workerTopologies returns topologies running on alive workers. A worker
+// which doesn't respond is logged and ignored.
+func (tc *topologies) workerTopologies() []interface{} {
+	ts, errs := tc.clusterClient.ListTopologies(tc.coordinator.AliveWorkers())
+	for w, err := range errs {
+		tc.ErrLog(err).WithField("worker", w).Warn("Cannot list topologies on the worker")
+	}
+	return ts
+}
+
+// NewClusterAgent creates an agent which sends heartbeats to the coordinator
+// when the server runs as a worker of a cluster. It returns nil otherwise.
+// The caller must start the agent after the server is set up.
+func NewClusterAgent(gvars *ContextGlobalVariables) *cluster.Agent {
+	conf := gvars.Config.Cluster
+	if conf == nil || conf.Role != "worker" {
+		return nil
+	}
+	return &cluster.Agent{
+		Coordinator:  conf.Coordinator,
+		AdvertiseURL: conf.AdvertiseURL,
+		Interval:     time.Duration(conf.HeartbeatInterval * float64(time.Second)),
+		Topologies: func() ([]string, error) {
+			ts, err := gvars.Topologies.List()
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(ts))
+			for _, tb := range ts {
+				names = append(names, tb.Topology().Name())
+			}
+			return names, nil
+		},
+		Logger:     gvars.Logger,
+		HTTPClient: newInternalHTTPClient(gvars.Config.Auth, 0),
+	}
+}
+
+// SetUpShuffleServer starts a server receiving tuples shuffled from other
+// servers and registers the "cluster_shuffle" source type. It returns nil when
+// shuffle_listen_on isn't configured. It must be called before any topology
+// is created so that topologies can use the source type. The caller must
+// stop the returned server.
+func SetUpShuffleServer(gvars *ContextGlobalVariables) (*cluster.ShuffleServer, error) {
+	conf := gvars.Config.Cluster
+	if conf == nil || conf.ShuffleListenOn == "" {
+		return nil, nil
+	}
+	s := cluster.NewShuffleServer()
+	s.Logger = gvars.Logger
+	if err := s.Listen(conf.ShuffleListenOn); err != nil {
+		return nil, err
+	}
+	if err := bql.RegisterGlobalSourceCreator("cluster_shuffle", s); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HeartbeatRequest is the body of a heartbeat request which a worker sends
+// to "POST /api/v1/cluster/workers" of the coordinator.
+type HeartbeatRequest struct {
+	URL        string   `json:"url"`
+	Topologies []string `json:"topologies"`
+}
+
+// Agent runs on a worker and sends heartbeats to the coordinator.
+type Agent struct {
+	// Coordinator is the base URL of the coordinator.
+	Coordinator string
+
+	// AdvertiseURL is the base URL of this worker's API.
+	AdvertiseURL string
+
+	// Interval is the interval of heartbeats.
+	Interval time.Duration
+
+	// Topologies returns the names of topologies running on this worker.
+	Topologies func() ([]string, error)
+
+	// Logger is used to report failures of heartbeats.
+	Logger *logrus.Logger
+
+	// HTTPClient is used to send heartbeats. http.DefaultClient is used when
+	// it's nil.
+	HTTPClient *http.Client
+
+	m       sync.Mutex
+	stopCh  chan struct{}
+	stopped chan struct{}
+}
+
+// Heartbeat sends a heartbeat to the coordinator once.
+func (a *Agent) Heartbeat() error {
+	ts, err := a.Topologies()
+	if err != nil {
+		return fmt.Errorf("cannot list topologies: %v", err)
+	}
+	if ts == nil {
+		ts = []string{}
+	}
+	b, err := json.Marshal(&HeartbeatRequest{
+		URL:        a.AdvertiseURL,
+		Topologies: ts,
+	})
+	if err != nil {
+		return err
+	}
+
+	c := a.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	res, err := c.Post(strings.TrimRight(a.Coordinator, "/")+"/api/v1/cluster/workers",
+		"application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("the coordinator returned an error (%v): %s", res.Status, body)
+	}
+	return nil
+}
+
+// Start starts sending heartbeats periodically in a separate goroutine. The
+// first heartbeat is sent immediately.
+func (a *Agent) Start() {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if a.stopCh != nil {
+		return
+	}
+	a.stopCh = make(chan struct{})
+	a.stopped = make(chan struct{})
+
+	go func() {
+		defer close(a.stopped)
+		t := time.NewTicker(a.Interval)
+		defer t.Stop()
+
+		failing := false
+		for {
+			if err := a.Heartbeat(); err != nil {
+				// Only the first failure is logged to avoid flooding logs
+				// while the coordinator is down.
+				if !failing && a.Logger != nil {
+					a.Logger.WithField("err", err).WithField("coordinator", a.Coordinator).
+						Error("Cannot send a heartbeat to the coordinator")
+				}
+				failing = true
+			} else {
+				if failing && a.Logger != nil {
+					a.Logger.WithField("coordinator", a.Coordinator).
+						Info("Sent a heartbeat to the coordinator again")
+				}
+				failing = false
+			}
+
+			select {
+			case <-t.C:
+			case <-a.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops sending heartbeats.
+func (a *Agent) Stop() {
+	a.m.Lock()
+	defer a.m.Unlock()
+	if a.stopCh == nil {
+		return
+	}
+	close(a.stopCh)
+	<-a.stopped
+	a.stopCh = nil
+}
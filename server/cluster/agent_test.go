@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAgent(t *testing.T) {
+	Convey("Given a coordinator and an agent", t, func() {
+		c := NewCoordinator(time.Minute)
+		var m sync.Mutex
+		received := 0
+		coordinator := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method != "POST" || req.URL.Path != "/api/v1/cluster/workers" {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var h HeartbeatRequest
+			if err := json.NewDecoder(req.Body).Decode(&h); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if err := c.Heartbeat(h.URL, h.Topologies); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			m.Lock()
+			received++
+			m.Unlock()
+			rw.Write([]byte("{}"))
+		}))
+		Reset(coordinator.Close)
+
+		a := &Agent{
+			Coordinator:  coordinator.URL + "/",
+			AdvertiseURL: "http://worker1:15601",
+			Interval:     10 * time.Millisecond,
+			Topologies: func() ([]string, error) {
+				return []string{"t1"}, nil
+			},
+		}
+
+		Convey("When sending a heartbeat", func() {
+			So(a.Heartbeat(), ShouldBeNil)
+
+			Convey("Then the worker should be registered", func() {
+				ws := c.Workers()
+				So(len(ws), ShouldEqual, 1)
+				So(ws[0].URL, ShouldEqual, "http://worker1:15601")
+				So(ws[0].Topologies, ShouldResemble, []string{"t1"})
+				So(ws[0].Alive, ShouldBeTrue)
+			})
+
+			Convey("Then its topology should be placed", func() {
+				w, err := c.Lookup("t1")
+				So(err, ShouldBeNil)
+				So(w, ShouldEqual, "http://worker1:15601")
+			})
+		})
+
+		Convey("When starting the agent", func() {
+			a.Start()
+			Reset(a.Stop)
+			for i := 0; i < 500; i++ {
+				m.Lock()
+				n := received
+				m.Unlock()
+				if n >= 3 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then it should send heartbeats periodically", func() {
+				m.Lock()
+				defer m.Unlock()
+				So(received, ShouldBeGreaterThanOrEqualTo, 3)
+			})
+		})
+
+		Convey("When the coordinator is down", func() {
+			coordinator.Close()
+
+			Convey("Then sending a heartbeat should fail", func() {
+				So(a.Heartbeat(), ShouldNotBeNil)
+			})
+		})
+	})
+}
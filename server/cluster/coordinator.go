@@ -0,0 +1,236 @@
+// Package cluster implements the cluster mode of the SensorBee server. In
+// the cluster mode, worker servers register themselves with a coordinator
+// server by sending heartbeats, and the coordinator places topologies on
+// workers. Requests for a topology sent to the coordinator's API are
+// proxied to the worker running it, so clients can use the coordinator as if
+// it were a single server.
+//
+// Workers don't share anything with each other. A topology runs on exactly
+// one worker.
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+var (
+	// ErrNoWorker is returned when the coordinator doesn't have any worker
+	// on which a topology can be placed.
+	ErrNoWorker = errors.New("no worker is available")
+)
+
+// WorkerInfo has information of a worker registered with a coordinator.
+type WorkerInfo struct {
+	// URL is the base URL of the worker's API. It also identifies the
+	// worker.
+	URL string `json:"url"`
+
+	// Topologies is the names of topologies running on the worker reported
+	// in the last heartbeat.
+	Topologies []string `json:"topologies"`
+
+	// LastHeartbeat is the time when the coordinator received the last
+	// heartbeat from the worker.
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+
+	// Alive is true when the worker has sent a heartbeat within the timeout.
+	Alive bool `json:"alive"`
+}
+
+// Coordinator manages workers and placement of topologies on them.
+//
+// Coordinator is thread-safe.
+type Coordinator struct {
+	m       sync.Mutex
+	timeout time.Duration
+	workers map[string]*WorkerInfo
+
+	// placements maps the lowercased name of a topology to its placement.
+	placements map[string]*placement
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+type placement struct {
+	worker   string
+	placedAt time.Time
+}
+
+// NewCoordinator creates a new coordinator. A worker is regarded as down
+// when it doesn't send a heartbeat within the timeout.
+func NewCoordinator(timeout time.Duration) *Coordinator {
+	return &Coordinator{
+		timeout:    timeout,
+		workers:    map[string]*WorkerInfo{},
+		placements: map[string]*placement{},
+		now:        time.Now,
+	}
+}
+
+// Heartbeat registers a worker or updates the registration of the worker.
+// topologies is the names of topologies running on the worker, which are
+// used to restore placement, e.g. after the coordinator restarts.
+func (c *Coordinator) Heartbeat(url string, topologies []string) error {
+	url = strings.TrimRight(url, "/")
+	if url == "" {
+		return errors.New("the url of the worker must be specified")
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	now := c.now()
+	w, ok := c.workers[url]
+	if !ok {
+		w = &WorkerInfo{URL: url}
+		c.workers[url] = w
+	}
+	w.Topologies = append([]string{}, topologies...)
+	w.LastHeartbeat = now
+
+	reported := map[string]bool{}
+	for _, t := range topologies {
+		n := strings.ToLower(t)
+		reported[n] = true
+		c.placements[n] = &placement{
+			worker:   url,
+			placedAt: now,
+		}
+	}
+
+	// A topology placed on the worker but not reported was removed from the
+	// worker. However, a topology which has just been placed may not be
+	// reported yet because it's still being created.
+	for n, p := range c.placements {
+		if p.worker == url && !reported[n] && now.Sub(p.placedAt) > c.timeout {
+			delete(c.placements, n)
+		}
+	}
+	return nil
+}
+
+// Workers returns all registered workers sorted by their URLs.
+func (c *Coordinator) Workers() []*WorkerInfo {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	res := make([]*WorkerInfo, 0, len(c.workers))
+	for _, w := range c.workers {
+		i := *w
+		i.Topologies = append([]string{}, w.Topologies...)
+		i.Alive = c.isAlive(w)
+		res = append(res, &i)
+	}
+	sort.Sort(workersByURL(res))
+	return res
+}
+
+type workersByURL []*WorkerInfo
+
+func (w workersByURL) Len() int           { return len(w) }
+func (w workersByURL) Less(i, j int) bool { return w[i].URL < w[j].URL }
+func (w workersByURL) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+
+// RemoveWorker removes a worker and placement of topologies on it.
+func (c *Coordinator) RemoveWorker(url string) error {
+	url = strings.TrimRight(url, "/")
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.workers[url]; !ok {
+		return core.NotExistError(fmt.Errorf("worker '%v' is not registered", url))
+	}
+	delete(c.workers, url)
+	for n, p := range c.placements {
+		if p.worker == url {
+			delete(c.placements, n)
+		}
+	}
+	return nil
+}
+
+// Place assigns a topology to the alive worker having the fewest topologies
+// and returns the URL of the worker. It fails when the topology is already
+// placed. Unplace must be called when the caller fails to create the
+// topology on the worker.
+func (c *Coordinator) Place(topology string) (string, error) {
+	n := strings.ToLower(topology)
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if p, ok := c.placements[n]; ok {
+		return "", fmt.Errorf("topology '%v' is already placed on %v", topology, p.worker)
+	}
+
+	counts := map[string]int{}
+	for _, p := range c.placements {
+		counts[p.worker]++
+	}
+	var best *WorkerInfo
+	for _, w := range c.workers {
+		if !c.isAlive(w) {
+			continue
+		}
+		if best == nil || counts[w.URL] < counts[best.URL] ||
+			(counts[w.URL] == counts[best.URL] && w.URL < best.URL) {
+			best = w
+		}
+	}
+	if best == nil {
+		return "", ErrNoWorker
+	}
+	c.placements[n] = &placement{
+		worker:   best.URL,
+		placedAt: c.now(),
+	}
+	return best.URL, nil
+}
+
+// Unplace removes the placement of a topology.
+func (c *Coordinator) Unplace(topology string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	delete(c.placements, strings.ToLower(topology))
+}
+
+// Lookup returns the URL of the worker on which the topology is placed. It
+// returns core.NotExistError when the topology isn't placed on any worker.
+// It also fails when the worker is down.
+func (c *Coordinator) Lookup(topology string) (string, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	p, ok := c.placements[strings.ToLower(topology)]
+	if !ok {
+		return "", core.NotExistError(fmt.Errorf("topology '%v' is not placed on any worker", topology))
+	}
+	w, ok := c.workers[p.worker]
+	if !ok || !c.isAlive(w) {
+		return "", fmt.Errorf("the worker %v running topology '%v' is down", p.worker, topology)
+	}
+	return p.worker, nil
+}
+
+// AliveWorkers returns URLs of alive workers.
+func (c *Coordinator) AliveWorkers() []string {
+	var res []string
+	for _, w := range c.Workers() {
+		if w.Alive {
+			res = append(res, w.URL)
+		}
+	}
+	return res
+}
+
+// isAlive returns true when the worker sent a heartbeat within the timeout.
+// The caller must hold c.m.
+func (c *Coordinator) isAlive(w *WorkerInfo) bool {
+	return c.now().Sub(w.LastHeartbeat) <= c.timeout
+}
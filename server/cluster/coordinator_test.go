@@ -0,0 +1,145 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+func TestCoordinator(t *testing.T) {
+	Convey("Given a coordinator with two workers", t, func() {
+		now := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+		c := NewCoordinator(10 * time.Second)
+		c.now = func() time.Time { return now }
+		So(c.Heartbeat("http://w1:15601/", nil), ShouldBeNil)
+		So(c.Heartbeat("http://w2:15601", nil), ShouldBeNil)
+
+		Convey("When placing topologies", func() {
+			w1, err := c.Place("t1")
+			So(err, ShouldBeNil)
+			w2, err := c.Place("t2")
+			So(err, ShouldBeNil)
+
+			Convey("Then they should be placed on different workers", func() {
+				So(w1, ShouldEqual, "http://w1:15601")
+				So(w2, ShouldEqual, "http://w2:15601")
+			})
+
+			Convey("Then they should be looked up case-insensitively", func() {
+				w, err := c.Lookup("T1")
+				So(err, ShouldBeNil)
+				So(w, ShouldEqual, w1)
+			})
+
+			Convey("Then placing the same topology again should fail", func() {
+				_, err := c.Place("t1")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("And unplacing one of them", func() {
+				c.Unplace("t1")
+
+				Convey("Then it shouldn't be found", func() {
+					_, err := c.Lookup("t1")
+					So(core.IsNotExist(err), ShouldBeTrue)
+				})
+
+				Convey("Then a new topology should be placed on the emptier worker", func() {
+					w, err := c.Place("t3")
+					So(err, ShouldBeNil)
+					So(w, ShouldEqual, w1)
+				})
+			})
+
+			Convey("And a worker stops sending heartbeats", func() {
+				now = now.Add(8 * time.Second)
+				So(c.Heartbeat("http://w2:15601", []string{"t2"}), ShouldBeNil)
+				now = now.Add(8 * time.Second)
+
+				Convey("Then it should be down", func() {
+					ws := c.Workers()
+					So(len(ws), ShouldEqual, 2)
+					So(ws[0].Alive, ShouldBeFalse)
+					So(ws[1].Alive, ShouldBeTrue)
+					So(c.AliveWorkers(), ShouldResemble, []string{"http://w2:15601"})
+				})
+
+				Convey("Then looking up a topology on it should fail", func() {
+					_, err := c.Lookup("t1")
+					So(err, ShouldNotBeNil)
+					So(core.IsNotExist(err), ShouldBeFalse)
+				})
+
+				Convey("Then a new topology should be placed on the alive worker", func() {
+					w, err := c.Place("t3")
+					So(err, ShouldBeNil)
+					So(w, ShouldEqual, "http://w2:15601")
+				})
+			})
+
+			Convey("And a worker doesn't report a topology for a long time", func() {
+				now = now.Add(11 * time.Second)
+				So(c.Heartbeat("http://w1:15601", nil), ShouldBeNil)
+
+				Convey("Then the topology should be unplaced", func() {
+					_, err := c.Lookup("t1")
+					So(core.IsNotExist(err), ShouldBeTrue)
+				})
+			})
+
+			Convey("And a worker doesn't report a topology which has just been placed", func() {
+				So(c.Heartbeat("http://w1:15601", nil), ShouldBeNil)
+
+				Convey("Then the topology should still be placed", func() {
+					_, err := c.Lookup("t1")
+					So(err, ShouldBeNil)
+				})
+			})
+
+			Convey("And removing a worker", func() {
+				So(c.RemoveWorker("http://w1:15601"), ShouldBeNil)
+
+				Convey("Then topologies on it should be unplaced", func() {
+					_, err := c.Lookup("t1")
+					So(core.IsNotExist(err), ShouldBeTrue)
+				})
+
+				Convey("Then removing it again should fail", func() {
+					So(core.IsNotExist(c.RemoveWorker("http://w1:15601")), ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("When a worker reports a topology which isn't placed", func() {
+			So(c.Heartbeat("http://w2:15601", []string{"existing"}), ShouldBeNil)
+
+			Convey("Then the placement should be restored", func() {
+				w, err := c.Lookup("existing")
+				So(err, ShouldBeNil)
+				So(w, ShouldEqual, "http://w2:15601")
+			})
+		})
+	})
+
+	Convey("Given a coordinator without workers", t, func() {
+		c := NewCoordinator(10 * time.Second)
+
+		Convey("When placing a topology", func() {
+			_, err := c.Place("t1")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldEqual, ErrNoWorker)
+			})
+		})
+
+		Convey("When a worker without a url sends a heartbeat", func() {
+			err := c.Heartbeat("", nil)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
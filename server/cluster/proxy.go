@@ -0,0 +1,84 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+)
+
+// Proxy forwards a request received by the coordinator to a worker. The
+// path of the request is appended to the path of the worker's URL. onError
+// is called to write a response when the worker cannot be reached.
+func Proxy(workerURL string, rw http.ResponseWriter, req *http.Request, onError func(err error)) error {
+	u, err := url.Parse(workerURL)
+	if err != nil {
+		return fmt.Errorf("the url of the worker is invalid: %v", err)
+	}
+	p := httputil.NewSingleHostReverseProxy(u)
+	p.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
+		onError(fmt.Errorf("cannot connect to the worker %v: %v", workerURL, err))
+	}
+	p.ServeHTTP(rw, req)
+	return nil
+}
+
+// Client sends requests from the coordinator to workers.
+type Client struct {
+	// HTTPClient is used to send requests. http.DefaultClient is used when
+	// it's nil.
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new client whose requests time out after the given
+// duration.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+// ListTopologies collects the topologies from all workers via their
+// "GET /api/v1/topologies" APIs. A worker which fails to respond is reported
+// in the map of errors keyed by its URL instead of failing the whole
+// operation, so that a down worker doesn't hide topologies on other workers.
+func (c *Client) ListTopologies(workers []string) ([]interface{}, map[string]error) {
+	res := []interface{}{}
+	errs := map[string]error{}
+	for _, w := range workers {
+		var body struct {
+			Topologies []interface{} `json:"topologies"`
+		}
+		if err := c.get(w+"/api/v1/topologies", &body); err != nil {
+			errs[w] = err
+			continue
+		}
+		res = append(res, body.Topologies...)
+	}
+	return res, errs
+}
+
+func (c *Client) get(u string, v interface{}) error {
+	res, err := c.httpClient().Get(u)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("the worker returned an error (%v): %s", res.Status, b)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
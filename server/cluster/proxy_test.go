@@ -0,0 +1,112 @@
+package cluster
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProxy(t *testing.T) {
+	Convey("Given a worker", t, func() {
+		worker := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"method": req.Method,
+				"path":   req.URL.Path,
+				"query":  req.URL.RawQuery,
+			})
+		}))
+		Reset(worker.Close)
+
+		Convey("When proxying a request", func() {
+			rec := httptest.NewRecorder()
+			req, err := http.NewRequest("POST", "http://coordinator/api/v1/topologies/t1/queries?a=b", nil)
+			So(err, ShouldBeNil)
+			So(Proxy(worker.URL, rec, req, func(err error) {
+				t.Errorf("unexpected error: %v", err)
+			}), ShouldBeNil)
+
+			Convey("Then the worker should receive it", func() {
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				var res map[string]interface{}
+				So(json.Unmarshal(rec.Body.Bytes(), &res), ShouldBeNil)
+				So(res["method"], ShouldEqual, "POST")
+				So(res["path"], ShouldEqual, "/api/v1/topologies/t1/queries")
+				So(res["query"], ShouldEqual, "a=b")
+			})
+		})
+	})
+
+	Convey("Given a worker which is down", t, func() {
+		worker := httptest.NewServer(http.NotFoundHandler())
+		url := worker.URL
+		worker.Close()
+
+		Convey("When proxying a request", func() {
+			rec := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", "http://coordinator/api/v1/topologies/t1", nil)
+			So(err, ShouldBeNil)
+			var proxyErr error
+			So(Proxy(url, rec, req, func(err error) {
+				proxyErr = err
+			}), ShouldBeNil)
+
+			Convey("Then the error handler should be called", func() {
+				So(proxyErr, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestClientListTopologies(t *testing.T) {
+	Convey("Given two workers", t, func() {
+		newWorker := func(name string) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/api/v1/topologies" {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				b, _ := ioutil.ReadAll(req.Body)
+				if len(b) != 0 {
+					rw.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				json.NewEncoder(rw).Encode(map[string]interface{}{
+					"topologies": []interface{}{map[string]interface{}{"name": name}},
+				})
+			}))
+		}
+		w1 := newWorker("t1")
+		Reset(w1.Close)
+		w2 := newWorker("t2")
+		Reset(w2.Close)
+		c := NewClient(time.Second)
+
+		Convey("When listing topologies", func() {
+			ts, errs := c.ListTopologies([]string{w1.URL, w2.URL})
+
+			Convey("Then it should have topologies of both workers", func() {
+				So(errs, ShouldBeEmpty)
+				So(ts, ShouldResemble, []interface{}{
+					map[string]interface{}{"name": "t1"},
+					map[string]interface{}{"name": "t2"},
+				})
+			})
+		})
+
+		Convey("When one of them is down", func() {
+			w2.Close()
+			ts, errs := c.ListTopologies([]string{w1.URL, w2.URL})
+
+			Convey("Then it should have topologies of the alive worker", func() {
+				So(len(ts), ShouldEqual, 1)
+				So(errs, ShouldContainKey, w2.URL)
+			})
+		})
+	})
+}
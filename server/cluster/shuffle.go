@@ -0,0 +1,523 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Shuffling partitions a stream across servers. A "cluster_shuffle" sink
+// sends each tuple to one of the servers listed in its "workers" parameter
+// via gRPC, and a "cluster_shuffle" source on the receiving server emits
+// tuples sent to its channel. When the sink has the "key" parameter, tuples
+// having the same key are always sent to the same server, so that each server
+// can compute aggregates grouped by the key on its own partition:
+//
+//	-- on every worker
+//	CREATE SINK shuffle TYPE cluster_shuffle
+//	    WITH channel="events", key="user_id",
+//	         workers=["worker1:15602", "worker2:15602"];
+//	INSERT INTO shuffle FROM events_src;
+//	CREATE SOURCE events TYPE cluster_shuffle WITH channel="events";
+//
+// Aggregates which aren't grouped by the key can be computed in two phases:
+// each worker computes partial aggregates on its partition and sends them to
+// a single worker with a sink having only one worker and no key, and the
+// worker merges the partial aggregates, e.g. by summing partial counts.
+//
+// Tuples which fail to be sent are reported as errors of the sink. Use a
+// sink WAL to retry them when a worker is temporarily unavailable. Like the
+// WAL, timestamps in a tuple's data are received as integers because tuples
+// are encoded in msgpack.
+
+const (
+	shuffleServiceName = "sensorbee.cluster.Shuffle"
+	shuffleSendMethod  = "/" + shuffleServiceName + "/Send"
+
+	// shuffleChannelMetadataKey is the key of the gRPC metadata having the
+	// name of the channel to which tuples in a stream are sent.
+	shuffleChannelMetadataKey = "sensorbee-shuffle-channel"
+)
+
+func init() {
+	encoding.RegisterCodec(shuffleCodec{})
+	bql.MustRegisterGlobalSinkCreator("cluster_shuffle", bql.SinkCreatorFunc(createShuffleSink))
+}
+
+// shuffleCodec encodes messages of the shuffle service in msgpack. Every
+// message is a data.Map so that the service doesn't need protocol buffers.
+type shuffleCodec struct{}
+
+func (shuffleCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(*data.Map)
+	if !ok {
+		return nil, fmt.Errorf("unsupported message type: %T", v)
+	}
+	return data.MarshalMsgpack(*m)
+}
+
+func (shuffleCodec) Unmarshal(b []byte, v interface{}) error {
+	m, ok := v.(*data.Map)
+	if !ok {
+		return fmt.Errorf("unsupported message type: %T", v)
+	}
+	r, err := data.UnmarshalMsgpack(b)
+	if err != nil {
+		return err
+	}
+	*m = r
+	return nil
+}
+
+func (shuffleCodec) Name() string {
+	return "sensorbee-msgpack"
+}
+
+var shuffleStreamDesc = grpc.StreamDesc{
+	StreamName:    "Send",
+	ClientStreams: true,
+	Handler: func(srv interface{}, stream grpc.ServerStream) error {
+		return srv.(*ShuffleServer).receive(stream)
+	},
+}
+
+var shuffleServiceDesc = grpc.ServiceDesc{
+	ServiceName: shuffleServiceName,
+	HandlerType: (*interface{})(nil),
+	Streams:     []grpc.StreamDesc{shuffleStreamDesc},
+}
+
+func encodeShuffleTuple(t *core.Tuple) data.Map {
+	m := data.Map{
+		"data":     t.Data,
+		"batch_id": data.Int(t.BatchID),
+	}
+	if !t.Timestamp.IsZero() {
+		// Timestamps are encoded as integers in msgpack.
+		m["timestamp"] = data.Int(t.Timestamp.UnixNano())
+	}
+	return m
+}
+
+func decodeShuffleTuple(m data.Map) (*core.Tuple, error) {
+	v, ok := m["data"]
+	if !ok {
+		return nil, errors.New("a shuffled tuple doesn't have data")
+	}
+	d, err := data.AsMap(v)
+	if err != nil {
+		return nil, fmt.Errorf("the data of a shuffled tuple must be a map: %v", err)
+	}
+	t := core.NewTuple(d)
+	if v, ok := m["timestamp"]; ok {
+		ns, err := data.AsInt(v)
+		if err != nil {
+			return nil, fmt.Errorf("the timestamp of a shuffled tuple is invalid: %v", err)
+		}
+		t.Timestamp = time.Unix(0, ns).In(time.UTC)
+	}
+	if v, ok := m["batch_id"]; ok {
+		t.BatchID, _ = data.AsInt(v)
+	}
+	return t, nil
+}
+
+// ShuffleServer receives tuples shuffled from other servers and writes them
+// to "cluster_shuffle" sources. ShuffleServer implements bql.SourceCreator
+// which creates the sources.
+type ShuffleServer struct {
+	// Logger is used to report errors. Errors aren't logged when it's nil.
+	Logger *logrus.Logger
+
+	m        sync.RWMutex
+	channels map[string]*shuffleChannel
+	server   *grpc.Server
+	listener net.Listener
+}
+
+// NewShuffleServer creates a new shuffle server. Listen must be called to
+// receive tuples.
+func NewShuffleServer() *ShuffleServer {
+	s := &ShuffleServer{
+		channels: map[string]*shuffleChannel{},
+		server:   grpc.NewServer(),
+	}
+	s.server.RegisterService(&shuffleServiceDesc, s)
+	return s
+}
+
+// Listen starts receiving tuples on the address in a separate goroutine.
+func (s *ShuffleServer) Listen(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.m.Lock()
+	s.listener = l
+	s.m.Unlock()
+
+	go func() {
+		if err := s.server.Serve(l); err != nil && s.Logger != nil {
+			s.Logger.WithField("err", err).Error("The shuffle server stopped")
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address on which the server is listening. It returns an
+// empty string when Listen hasn't been called.
+func (s *ShuffleServer) Addr() string {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Stop stops the server and closes all streams from other servers.
+func (s *ShuffleServer) Stop() {
+	s.server.Stop()
+}
+
+// CreateSource creates a "cluster_shuffle" source. It accepts the "channel"
+// parameter which is the name of the channel the source receives tuples
+// from. The name of the source is used when it's omitted.
+func (s *ShuffleServer) CreateSource(ctx *core.Context, ioParams *bql.IOParams, params data.Map) (core.Source, error) {
+	channel := ioParams.Name
+	if v, ok := params["channel"]; ok {
+		c, err := data.AsString(v)
+		if err != nil {
+			return nil, fmt.Errorf("'channel' parameter must be a string: %v", err)
+		}
+		channel = c
+	}
+	if channel == "" {
+		return nil, errors.New("'channel' parameter must not be empty")
+	}
+
+	s.m.RLock()
+	_, ok := s.channels[channel]
+	s.m.RUnlock()
+	if ok {
+		return nil, fmt.Errorf("channel '%v' is already used by another source", channel)
+	}
+	return &shuffleSource{
+		server:  s,
+		channel: channel,
+		stopCh:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}, nil
+}
+
+type shuffleChannel struct {
+	m      sync.Mutex
+	ctx    *core.Context
+	w      core.Writer
+	closed bool
+}
+
+func (c *shuffleChannel) write(t *core.Tuple) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.closed {
+		return errors.New("the source has been stopped")
+	}
+	return c.w.Write(c.ctx, t)
+}
+
+func (s *ShuffleServer) register(name string, ctx *core.Context, w core.Writer) (*shuffleChannel, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if _, ok := s.channels[name]; ok {
+		return nil, fmt.Errorf("channel '%v' is already used by another source", name)
+	}
+	c := &shuffleChannel{
+		ctx: ctx,
+		w:   w,
+	}
+	s.channels[name] = c
+	return c, nil
+}
+
+// unregister removes the channel. The channel's writer is never called after
+// this method returns.
+func (s *ShuffleServer) unregister(name string, c *shuffleChannel) {
+	s.m.Lock()
+	if s.channels[name] == c {
+		delete(s.channels, name)
+	}
+	s.m.Unlock()
+
+	c.m.Lock()
+	c.closed = true
+	c.m.Unlock()
+}
+
+func (s *ShuffleServer) lookup(name string) *shuffleChannel {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return s.channels[name]
+}
+
+func (s *ShuffleServer) receive(stream grpc.ServerStream) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	names := md.Get(shuffleChannelMetadataKey)
+	if len(names) != 1 || names[0] == "" {
+		return status.Error(codes.InvalidArgument, "the channel must be specified")
+	}
+	name := names[0]
+
+	n := int64(0)
+	for {
+		var m data.Map
+		if err := stream.RecvMsg(&m); err != nil {
+			if err == io.EOF {
+				return stream.SendMsg(&data.Map{"num_received": data.Int(n)})
+			}
+			return err
+		}
+		t, err := decodeShuffleTuple(m)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		c := s.lookup(name)
+		if c == nil {
+			return status.Errorf(codes.NotFound, "channel '%v' doesn't exist", name)
+		}
+		if err := c.write(t); err != nil {
+			if s.Logger != nil {
+				s.Logger.WithFields(logrus.Fields{
+					"err":     err,
+					"channel": name,
+				}).Error("Cannot write a shuffled tuple")
+			}
+			return status.Errorf(codes.Unavailable, "cannot write a tuple to channel '%v': %v", name, err)
+		}
+		n++
+	}
+}
+
+type shuffleSource struct {
+	server  *ShuffleServer
+	channel string
+	stopCh  chan struct{}
+	stopped chan struct{}
+}
+
+func (s *shuffleSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	defer close(s.stopped)
+	c, err := s.server.register(s.channel, ctx, w)
+	if err != nil {
+		return err
+	}
+	defer s.server.unregister(s.channel, c)
+	<-s.stopCh
+	return nil
+}
+
+func (s *shuffleSource) Stop(ctx *core.Context) error {
+	close(s.stopCh)
+	<-s.stopped
+	return nil
+}
+
+// shuffleSink sends tuples to "cluster_shuffle" sources on other servers.
+type shuffleSink struct {
+	channel string
+	key     data.Path
+	targets []*shuffleTarget
+
+	m    sync.Mutex
+	next int
+}
+
+func createShuffleSink(ctx *core.Context, ioParams *bql.IOParams, params data.Map) (core.Sink, error) {
+	v, ok := params["channel"]
+	if !ok {
+		return nil, errors.New("'channel' parameter is missing")
+	}
+	channel, err := data.AsString(v)
+	if err != nil {
+		return nil, fmt.Errorf("'channel' parameter must be a string: %v", err)
+	}
+	if channel == "" {
+		return nil, errors.New("'channel' parameter must not be empty")
+	}
+
+	v, ok = params["workers"]
+	if !ok {
+		return nil, errors.New("'workers' parameter is missing")
+	}
+	a, err := data.AsArray(v)
+	if err != nil {
+		return nil, fmt.Errorf("'workers' parameter must be an array: %v", err)
+	}
+	if len(a) == 0 {
+		return nil, errors.New("'workers' parameter must have at least one address")
+	}
+	addrs := make([]string, len(a))
+	for i, w := range a {
+		addr, err := data.AsString(w)
+		if err != nil {
+			return nil, fmt.Errorf("'workers' parameter must be an array of strings: %v", err)
+		}
+		addrs[i] = addr
+	}
+
+	var key data.Path
+	if v, ok := params["key"]; ok {
+		k, err := data.AsString(v)
+		if err != nil {
+			return nil, fmt.Errorf("'key' parameter must be a string: %v", err)
+		}
+		key, err = data.CompilePath(k)
+		if err != nil {
+			return nil, fmt.Errorf("'key' parameter has an invalid path: %v", err)
+		}
+	}
+	return newShuffleSink(channel, key, addrs)
+}
+
+func newShuffleSink(channel string, key data.Path, addrs []string) (*shuffleSink, error) {
+	s := &shuffleSink{
+		channel: channel,
+		key:     key,
+	}
+	for _, addr := range addrs {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			s.Close(nil)
+			return nil, fmt.Errorf("cannot connect to worker %v: %v", addr, err)
+		}
+		s.targets = append(s.targets, &shuffleTarget{
+			addr:    addr,
+			channel: channel,
+			conn:    conn,
+		})
+	}
+	return s, nil
+}
+
+func (s *shuffleSink) Write(ctx *core.Context, t *core.Tuple) error {
+	i, err := s.partition(t)
+	if err != nil {
+		return err
+	}
+	return s.targets[i].send(encodeShuffleTuple(t))
+}
+
+// partition returns the index of the target to which the tuple is sent.
+// Tuples are sent in a round-robin manner when the sink doesn't have a key.
+func (s *shuffleSink) partition(t *core.Tuple) (int, error) {
+	if s.key == nil {
+		s.m.Lock()
+		defer s.m.Unlock()
+		i := s.next
+		s.next = (s.next + 1) % len(s.targets)
+		return i, nil
+	}
+	v, err := t.Data.Get(s.key)
+	if err != nil {
+		return 0, fmt.Errorf("the tuple doesn't have the shuffle key: %v", err)
+	}
+	return int(uint64(data.Hash(v)) % uint64(len(s.targets))), nil
+}
+
+func (s *shuffleSink) Close(ctx *core.Context) error {
+	var errs []string
+	for _, t := range s.targets {
+		if err := t.close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", t.addr, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("cannot close streams to workers: %v", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// shuffleTarget is a stream to a worker. The stream is opened lazily and
+// reopened after an error.
+type shuffleTarget struct {
+	addr    string
+	channel string
+	conn    *grpc.ClientConn
+
+	m      sync.Mutex
+	stream grpc.ClientStream
+	cancel func()
+}
+
+func (t *shuffleTarget) send(m data.Map) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if t.stream == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = metadata.AppendToOutgoingContext(ctx, shuffleChannelMetadataKey, t.channel)
+		st, err := t.conn.NewStream(ctx, &shuffleStreamDesc, shuffleSendMethod,
+			grpc.CallContentSubtype(shuffleCodec{}.Name()))
+		if err != nil {
+			cancel()
+			return fmt.Errorf("cannot open a stream to worker %v: %v", t.addr, err)
+		}
+		t.stream = st
+		t.cancel = cancel
+	}
+
+	if err := t.stream.SendMsg(&m); err != nil {
+		if err == io.EOF {
+			// The actual error is returned from RecvMsg.
+			var ack data.Map
+			if e := t.stream.RecvMsg(&ack); e != nil {
+				err = e
+			}
+		}
+		t.reset()
+		return fmt.Errorf("cannot send a tuple to worker %v: %v", t.addr, err)
+	}
+	return nil
+}
+
+// reset discards the current stream. The caller must hold t.m.
+func (t *shuffleTarget) reset() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	t.stream = nil
+	t.cancel = nil
+}
+
+func (t *shuffleTarget) close() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+	var err error
+	if t.stream != nil {
+		if err = t.stream.CloseSend(); err == nil {
+			var ack data.Map
+			err = t.stream.RecvMsg(&ack)
+		}
+		t.reset()
+	}
+	if e := t.conn.Close(); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
@@ -0,0 +1,204 @@
+package cluster
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type shuffleCollector struct {
+	m      sync.Mutex
+	tuples []*core.Tuple
+}
+
+func (c *shuffleCollector) Write(ctx *core.Context, t *core.Tuple) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.tuples = append(c.tuples, t)
+	return nil
+}
+
+func (c *shuffleCollector) wait(n int) []*core.Tuple {
+	for i := 0; i < 500; i++ {
+		c.m.Lock()
+		l := len(c.tuples)
+		c.m.Unlock()
+		if l >= n {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	return append([]*core.Tuple{}, c.tuples...)
+}
+
+// startShuffleSource creates a source on the server and starts it.
+func startShuffleSource(ctx *core.Context, s *ShuffleServer, channel string) (core.Source, *shuffleCollector) {
+	src, err := s.CreateSource(ctx, &bql.IOParams{Name: "src"}, data.Map{"channel": data.String(channel)})
+	So(err, ShouldBeNil)
+	c := &shuffleCollector{}
+	go src.GenerateStream(ctx, c)
+	for i := 0; i < 500 && s.lookup(channel) == nil; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	So(s.lookup(channel), ShouldNotBeNil)
+	return src, c
+}
+
+func TestShuffle(t *testing.T) {
+	ctx := core.NewContext(nil)
+
+	Convey("Given two shuffle servers", t, func() {
+		s1 := NewShuffleServer()
+		So(s1.Listen("127.0.0.1:0"), ShouldBeNil)
+		s2 := NewShuffleServer()
+		So(s2.Listen("127.0.0.1:0"), ShouldBeNil)
+		Reset(func() {
+			s1.Stop()
+			s2.Stop()
+		})
+
+		src1, c1 := startShuffleSource(ctx, s1, "events")
+		src2, c2 := startShuffleSource(ctx, s2, "events")
+		Reset(func() {
+			src1.Stop(ctx)
+			src2.Stop(ctx)
+		})
+		workers := data.Array{data.String(s1.Addr()), data.String(s2.Addr())}
+
+		Convey("When sending tuples with a key", func() {
+			si, err := createShuffleSink(ctx, &bql.IOParams{Name: "shuffle"}, data.Map{
+				"channel": data.String("events"),
+				"workers": workers,
+				"key":     data.String("user"),
+			})
+			So(err, ShouldBeNil)
+			ts := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+			for i := 0; i < 20; i++ {
+				t := core.NewTuple(data.Map{
+					"user": data.Int(i % 4),
+					"seq":  data.Int(i),
+				})
+				t.Timestamp = ts
+				So(si.Write(ctx, t), ShouldBeNil)
+			}
+			So(si.Close(ctx), ShouldBeNil)
+
+			Convey("Then each server should receive all tuples having the same key", func() {
+				var ts1, ts2 []*core.Tuple
+				for i := 0; i < 500 && len(ts1)+len(ts2) < 20; i++ {
+					ts1, ts2 = c1.wait(0), c2.wait(0)
+					time.Sleep(time.Millisecond)
+				}
+				So(len(ts1)+len(ts2), ShouldEqual, 20)
+
+				users := map[int64]int{}
+				for i, ts := range [][]*core.Tuple{ts1, ts2} {
+					for _, t := range ts {
+						u, _ := data.AsInt(t.Data["user"])
+						if s, ok := users[u]; ok {
+							So(s, ShouldEqual, i)
+						}
+						users[u] = i
+					}
+				}
+				So(len(users), ShouldEqual, 4)
+			})
+
+			Convey("Then tuples should have the original data and timestamp", func() {
+				tuples := append(c1.wait(0), c2.wait(0)...)
+				So(len(tuples), ShouldBeGreaterThan, 0)
+				t := tuples[0]
+				So(t.Data, ShouldContainKey, "seq")
+				So(t.Timestamp.Equal(ts), ShouldBeTrue)
+			})
+		})
+
+		Convey("When sending tuples without a key", func() {
+			si, err := createShuffleSink(ctx, &bql.IOParams{Name: "shuffle"}, data.Map{
+				"channel": data.String("events"),
+				"workers": workers,
+			})
+			So(err, ShouldBeNil)
+			for i := 0; i < 10; i++ {
+				So(si.Write(ctx, core.NewTuple(data.Map{"seq": data.Int(i)})), ShouldBeNil)
+			}
+			So(si.Close(ctx), ShouldBeNil)
+
+			Convey("Then they should be distributed evenly", func() {
+				So(len(c1.wait(5)), ShouldEqual, 5)
+				So(len(c2.wait(5)), ShouldEqual, 5)
+			})
+		})
+
+		Convey("When sending tuples to a channel which doesn't exist", func() {
+			si, err := createShuffleSink(ctx, &bql.IOParams{Name: "shuffle"}, data.Map{
+				"channel": data.String("no_such_channel"),
+				"workers": data.Array{data.String(s1.Addr())},
+			})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				si.Close(ctx)
+			})
+
+			Convey("Then the sink should eventually fail", func() {
+				var err error
+				for i := 0; i < 500 && err == nil; i++ {
+					err = si.Write(ctx, core.NewTuple(data.Map{"seq": data.Int(i)}))
+					time.Sleep(time.Millisecond)
+				}
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "doesn't exist")
+			})
+		})
+
+		Convey("When creating another source with the same channel", func() {
+			_, err := s1.CreateSource(ctx, &bql.IOParams{Name: "src"}, data.Map{"channel": data.String("events")})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a tuple doesn't have the key", func() {
+			si, err := createShuffleSink(ctx, &bql.IOParams{Name: "shuffle"}, data.Map{
+				"channel": data.String("events"),
+				"workers": workers,
+				"key":     data.String("user"),
+			})
+			So(err, ShouldBeNil)
+			Reset(func() {
+				si.Close(ctx)
+			})
+
+			Convey("Then writing it should fail", func() {
+				So(si.Write(ctx, core.NewTuple(data.Map{"seq": data.Int(1)})), ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given invalid sink parameters", t, func() {
+		cases := []data.Map{
+			{"workers": data.Array{data.String("localhost:15602")}},
+			{"channel": data.String("c")},
+			{"channel": data.String("c"), "workers": data.Array{}},
+			{"channel": data.String("c"), "workers": data.Array{data.Int(1)}},
+			{"channel": data.String("c"), "workers": data.Array{data.String("localhost:15602")}, "key": data.String("[")},
+		}
+		for _, c := range cases {
+			Convey("When creating a sink with "+c.String(), func() {
+				_, err := createShuffleSink(ctx, &bql.IOParams{Name: "shuffle"}, c)
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldNotBeNil)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+// testServer is an API server used by tests of handlers in this package. Tests
+// of the client package use testutil.Server instead, which cannot be used here
+// because testutil depends on this package.
+type testServer struct {
+	*httptest.Server
+	gvars *ContextGlobalVariables
+}
+
+// newTestServer starts an API server having the config. It must be called in
+// a Convey block.
+func newTestServer(conf data.Map) *testServer {
+	c, err := config.New(conf)
+	So(err, ShouldBeNil)
+	gvars, err := SetUpContextGlobalVariables(c)
+	So(err, ShouldBeNil)
+	jascoRoot := jasco.New("/", nil)
+	root, err := SetUpContextAndRouter("/", jascoRoot, gvars)
+	So(err, ShouldBeNil)
+	SetUpAPIRouter("/", root, nil)
+	return &testServer{
+		Server: httptest.NewServer(jascoRoot),
+		gvars:  gvars,
+	}
+}
+
+// Close stops the server and all topologies in it.
+func (s *testServer) Close() {
+	s.Server.Close()
+	ts, _ := s.gvars.Topologies.List()
+	for _, tb := range ts {
+		tb.Topology().Stop()
+	}
+}
+
+// newRequest creates a request to the API. body is encoded as JSON unless
+// it's an io.Reader.
+func (s *testServer) newRequest(method, path string, body interface{}) *http.Request {
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		r = b
+	default:
+		js, err := json.Marshal(b)
+		So(err, ShouldBeNil)
+		r = bytes.NewReader(js)
+	}
+	req, err := http.NewRequest(method, s.URL+"/api/v1"+path, r)
+	So(err, ShouldBeNil)
+	return req
+}
+
+// do sends the request and returns the response whose body is read. The body
+// is decoded to js when it's JSON.
+func (s *testServer) do(req *http.Request) (res *http.Response, body []byte, js map[string]interface{}) {
+	res, err := http.DefaultClient.Do(req)
+	So(err, ShouldBeNil)
+	defer res.Body.Close()
+	body, err = ioutil.ReadAll(res.Body)
+	So(err, ShouldBeNil)
+	if strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+		So(json.Unmarshal(body, &js), ShouldBeNil)
+	}
+	return res, body, js
+}
+
+// request sends a request to the API and returns the response with its JSON
+// body.
+func (s *testServer) request(method, path string, body interface{}) (*http.Response, map[string]interface{}) {
+	res, _, js := s.do(s.newRequest(method, path, body))
+	return res, js
+}
+
+// errorCode returns the code of the error in a JSON response.
+func errorCode(js map[string]interface{}) interface{} {
+	e, ok := js["error"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return e["code"]
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Auth has configuration parameters of authentication of HTTP requests.
+// Requests are authenticated only when at least one of APIKeys,
+// BearerTokens, or credentials in Roles is given.
+type Auth struct {
+	// APIKeys are static keys accepted in the X-API-Key header. Requests
+	// having them have the admin role.
+	APIKeys []string `json:"api_keys" yaml:"api_keys"`
+
+	// BearerTokens are tokens accepted in the Authorization header with the
+	// Bearer scheme. Requests having them have the admin role.
+	BearerTokens []string `json:"bearer_tokens" yaml:"bearer_tokens"`
+
+	// Roles has credentials of each role. A key is either "admin" or
+	// "read_only".
+	Roles map[string]*AuthCredentials `json:"roles" yaml:"roles"`
+
+	// ExemptPaths are paths which can be requested without credentials such
+	// as a health check. A path ending with '*' exempts all paths having the
+	// prefix.
+	ExemptPaths []string `json:"exempt_paths" yaml:"exempt_paths"`
+}
+
+// AuthCredentials has credentials of a role.
+type AuthCredentials struct {
+	// APIKeys are static keys accepted in the X-API-Key header.
+	APIKeys []string `json:"api_keys" yaml:"api_keys"`
+
+	// BearerTokens are tokens accepted in the Authorization header with the
+	// Bearer scheme.
+	BearerTokens []string `json:"bearer_tokens" yaml:"bearer_tokens"`
+}
+
+var (
+	authCredentialsPropertiesString = `
+		"api_keys": {
+			"type": "array",
+			"items": {
+				"type": "string",
+				"minLength": 1
+			}
+		},
+		"bearer_tokens": {
+			"type": "array",
+			"items": {
+				"type": "string",
+				"pattern": "^[^ \t]+$"
+			}
+		}`
+	authCredentialsSchemaString = fmt.Sprintf(`{
+		"type": "object",
+		"properties": {%v
+		},
+		"additionalProperties": false
+	}`, authCredentialsPropertiesString)
+
+	authSchemaString = fmt.Sprintf(`{
+	"type": "object",
+	"properties": {%v,
+		"roles": {
+			"type": "object",
+			"properties": {
+				"admin": %v,
+				"read_only": %v
+			},
+			"additionalProperties": false
+		},
+		"exempt_paths": {
+			"type": "array",
+			"items": {
+				"type": "string",
+				"pattern": "^/"
+			}
+		}
+	},
+	"additionalProperties": false
+}`, authCredentialsPropertiesString, authCredentialsSchemaString, authCredentialsSchemaString)
+	authSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(authSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	authSchema = s
+}
+
+// NewAuth creates an Auth config parameters from a given map.
+func NewAuth(m data.Map) (*Auth, error) {
+	if err := validate(authSchema, m); err != nil {
+		return nil, err
+	}
+	return newAuth(m), nil
+}
+
+func newAuth(m data.Map) *Auth {
+	roles := map[string]*AuthCredentials{}
+	for name, r := range mustAsMap(getWithDefault(m, "roles", data.Map{})) {
+		rm := mustAsMap(r)
+		roles[name] = &AuthCredentials{
+			APIKeys:      mustAsStringSlice(getWithDefault(rm, "api_keys", data.Array{})),
+			BearerTokens: mustAsStringSlice(getWithDefault(rm, "bearer_tokens", data.Array{})),
+		}
+	}
+	return &Auth{
+		APIKeys:      mustAsStringSlice(getWithDefault(m, "api_keys", data.Array{})),
+		BearerTokens: mustAsStringSlice(getWithDefault(m, "bearer_tokens", data.Array{})),
+		Roles:        roles,
+		ExemptPaths:  mustAsStringSlice(getWithDefault(m, "exempt_paths", data.Array{})),
+	}
+}
+
+// Enabled returns true when requests need to be authenticated.
+func (a *Auth) Enabled() bool {
+	if a == nil {
+		return false
+	}
+	if len(a.APIKeys) > 0 || len(a.BearerTokens) > 0 {
+		return true
+	}
+	for _, r := range a.Roles {
+		if len(r.APIKeys) > 0 || len(r.BearerTokens) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ToMap returns auth config information as data.Map. Empty parameters are
+// omitted.
+func (a *Auth) ToMap() data.Map {
+	m := data.Map{}
+	roles := data.Map{}
+	for name, r := range a.Roles {
+		roles[name] = r.ToMap()
+	}
+	if len(roles) > 0 {
+		m["roles"] = roles
+	}
+	addStringSlices(m, []stringSliceParam{
+		{"api_keys", a.APIKeys},
+		{"bearer_tokens", a.BearerTokens},
+		{"exempt_paths", a.ExemptPaths},
+	})
+	return m
+}
+
+// ToMap returns credentials of a role as data.Map. Empty parameters are
+// omitted.
+func (c *AuthCredentials) ToMap() data.Map {
+	m := data.Map{}
+	addStringSlices(m, []stringSliceParam{
+		{"api_keys", c.APIKeys},
+		{"bearer_tokens", c.BearerTokens},
+	})
+	return m
+}
+
+type stringSliceParam struct {
+	name   string
+	values []string
+}
+
+// addStringSlices adds non-empty parameters to m as arrays of strings.
+func addStringSlices(m data.Map, ps []stringSliceParam) {
+	for _, p := range ps {
+		if len(p.values) == 0 {
+			continue
+		}
+		arr := make(data.Array, len(p.values))
+		for i, v := range p.values {
+			arr[i] = data.String(v)
+		}
+		m[p.name] = arr
+	}
+}
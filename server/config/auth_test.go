@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestAuth(t *testing.T) {
+	Convey("Given a JSON config for auth section", t, func() {
+		Convey("When the config is valid", func() {
+			a, err := NewAuth(toMap(`{"api_keys":["k1","k2"],"bearer_tokens":["t1"],"exempt_paths":["/metrics","/api/v1/runtime*"]}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(a.Enabled(), ShouldBeTrue)
+				So(a.APIKeys, ShouldResemble, []string{"k1", "k2"})
+				So(a.BearerTokens, ShouldResemble, []string{"t1"})
+				So(a.ExemptPaths, ShouldResemble, []string{"/metrics", "/api/v1/runtime*"})
+				So(a.ToMap(), ShouldResemble, data.Map{
+					"api_keys":      data.Array{data.String("k1"), data.String("k2")},
+					"bearer_tokens": data.Array{data.String("t1")},
+					"exempt_paths":  data.Array{data.String("/metrics"), data.String("/api/v1/runtime*")},
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			a, err := NewAuth(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then authentication should be disabled", func() {
+				So(a.Enabled(), ShouldBeFalse)
+				So(a.ToMap(), ShouldResemble, data.Map{})
+			})
+		})
+
+		Convey("When roles are given", func() {
+			a, err := NewAuth(toMap(`{"api_keys":["k1"],"roles":{"read_only":{"api_keys":["k2"],"bearer_tokens":["t2"]}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have credentials of the role", func() {
+				So(a.Roles, ShouldContainKey, "read_only")
+				So(a.Roles["read_only"].APIKeys, ShouldResemble, []string{"k2"})
+				So(a.Roles["read_only"].BearerTokens, ShouldResemble, []string{"t2"})
+				So(a.ToMap(), ShouldResemble, data.Map{
+					"api_keys": data.Array{data.String("k1")},
+					"roles": data.Map{
+						"read_only": data.Map{
+							"api_keys":      data.Array{data.String("k2")},
+							"bearer_tokens": data.Array{data.String("t2")},
+						},
+					},
+				})
+			})
+		})
+
+		Convey("When only credentials of a role are given", func() {
+			a, err := NewAuth(toMap(`{"roles":{"read_only":{"bearer_tokens":["t1"]}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then authentication should be enabled", func() {
+				So(a.Enabled(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When only exempt paths are given", func() {
+			a, err := NewAuth(toMap(`{"exempt_paths":["/metrics"]}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then authentication should be disabled", func() {
+				So(a.Enabled(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"api_keys":"k1"}`,
+				`{"api_keys":[""]}`,
+				`{"api_keys":[1]}`,
+				`{"bearer_tokens":["a b"]}`,
+				`{"bearer_tokens":[""]}`,
+				`{"exempt_paths":["metrics"]}`,
+				`{"roles":{"writer":{"api_keys":["k1"]}}}`,
+				`{"roles":{"read_only":{"api_keys":[""]}}}`,
+				`{"roles":{"read_only":{"exempt_paths":["/metrics"]}}}`,
+				`{"roles":{"read_only":["k1"]}}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewAuth(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
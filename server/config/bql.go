@@ -0,0 +1,121 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// BQL has server-wide defaults of BQL statements. They're applied to all
+// topologies in the server unless the config of a topology overrides them
+// with the same keys.
+type BQL struct {
+	// DefaultEmitter is the emitter used when a SELECT statement omits it.
+	// It's one of "istream", "dstream", or "rstream". The emitter must be
+	// specified when it's empty.
+	DefaultEmitter string `json:"default_emitter" yaml:"default_emitter"`
+
+	// DefaultRange is the window used when an input stream of a SELECT
+	// statement doesn't have a RANGE clause, e.g. "1 tuples" or
+	// "10 seconds". A window must be specified when it's empty.
+	DefaultRange string `json:"default_range" yaml:"default_range"`
+
+	// RequireExplicitWindow disallows omitting windows even if DefaultRange
+	// is set.
+	RequireExplicitWindow bool `json:"require_explicit_window" yaml:"require_explicit_window"`
+
+	// EnvAllowlist has names of environment variables which parameters in
+	// WITH clauses can refer to with ${NAME}. A name ending with '*' allows
+	// all variables having the prefix. Parameters cannot refer to any
+	// variable when it's empty. Unlike other parameters, it isn't a part of
+	// the config of topologies so that BQL statements cannot change it.
+	EnvAllowlist []string `json:"env_allowlist" yaml:"env_allowlist"`
+}
+
+var (
+	bqlSchemaString = `{
+	"type": "object",
+	"properties": {
+		"default_emitter": {
+			"enum": ["istream", "dstream", "rstream"]
+		},
+		"default_range": {
+			"type": "string",
+			"pattern": "^[0-9]+(\\.[0-9]+)? +(tuples|seconds|milliseconds)$"
+		},
+		"require_explicit_window": {
+			"type": "boolean"
+		},
+		"env_allowlist": {
+			"type": "array",
+			"items": {
+				"type": "string",
+				"pattern": "^[A-Za-z_][A-Za-z0-9_]*\\*?$|^\\*$"
+			}
+		}
+	},
+	"additionalProperties": false
+}`
+	bqlSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(bqlSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	bqlSchema = s
+}
+
+// NewBQL creates a BQL config parameters from a given map.
+func NewBQL(m data.Map) (*BQL, error) {
+	if err := validate(bqlSchema, m); err != nil {
+		return nil, err
+	}
+	return newBQL(m), nil
+}
+
+func newBQL(m data.Map) *BQL {
+	return &BQL{
+		DefaultEmitter:        mustAsString(getWithDefault(m, "default_emitter", data.String(""))),
+		DefaultRange:          mustAsString(getWithDefault(m, "default_range", data.String(""))),
+		RequireExplicitWindow: mustToBool(getWithDefault(m, "require_explicit_window", data.False)),
+		EnvAllowlist:          mustAsStringSlice(getWithDefault(m, "env_allowlist", data.Array{})),
+	}
+}
+
+// ToMap returns BQL config information as data.Map. Empty parameters are
+// omitted.
+func (b *BQL) ToMap() data.Map {
+	m := data.Map{}
+	if b.DefaultEmitter != "" {
+		m["default_emitter"] = data.String(b.DefaultEmitter)
+	}
+	if b.DefaultRange != "" {
+		m["default_range"] = data.String(b.DefaultRange)
+	}
+	if b.RequireExplicitWindow {
+		m["require_explicit_window"] = data.True
+	}
+	if len(b.EnvAllowlist) > 0 {
+		a := make(data.Array, len(b.EnvAllowlist))
+		for i, n := range b.EnvAllowlist {
+			a[i] = data.String(n)
+		}
+		m["env_allowlist"] = a
+	}
+	return m
+}
+
+// TopologyConfig returns a copy of the config of a topology having the
+// defaults which the config doesn't set.
+func (b *BQL) TopologyConfig(conf data.Map) data.Map {
+	c := data.Map{}
+	if b != nil {
+		c = b.ToMap()
+		delete(c, "env_allowlist")
+	}
+	for k, v := range conf {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestBQL(t *testing.T) {
+	Convey("Given a JSON config for bql section", t, func() {
+		Convey("When the config is valid", func() {
+			b, err := NewBQL(toMap(`{"default_emitter":"rstream","default_range":"1 tuples","require_explicit_window":true}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(*b, ShouldResemble, BQL{
+					DefaultEmitter:        "rstream",
+					DefaultRange:          "1 tuples",
+					RequireExplicitWindow: true,
+				})
+			})
+
+			Convey("Then the config of a topology should override them", func() {
+				c := b.TopologyConfig(data.Map{
+					"default_range": data.String("5 seconds"),
+					"other":         data.Int(1),
+				})
+				So(c, ShouldResemble, data.Map{
+					"default_emitter":         data.String("rstream"),
+					"default_range":           data.String("5 seconds"),
+					"require_explicit_window": data.True,
+					"other":                   data.Int(1),
+				})
+			})
+		})
+
+		Convey("When the config has an allowlist of environment variables", func() {
+			b, err := NewBQL(toMap(`{"env_allowlist":["KAFKA_HOST","APP_*"]}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the allowlist", func() {
+				So(b.EnvAllowlist, ShouldResemble, []string{"KAFKA_HOST", "APP_*"})
+				So(b.ToMap()["env_allowlist"], ShouldResemble, data.Array{data.String("KAFKA_HOST"), data.String("APP_*")})
+			})
+
+			Convey("Then it shouldn't be a part of the config of a topology", func() {
+				So(b.TopologyConfig(nil), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			b, err := NewBQL(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it shouldn't add anything to the config of a topology", func() {
+				So(b.ToMap(), ShouldBeEmpty)
+				So(b.TopologyConfig(nil), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"default_emitter":"xstream"}`,
+				`{"default_range":"1"}`,
+				`{"default_range":"1 minutes"}`,
+				`{"require_explicit_window":"true"}`,
+				`{"env_allowlist":"HOME"}`,
+				`{"env_allowlist":["A-B"]}`,
+				`{"env_allowlist":["A*B"]}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewBQL(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
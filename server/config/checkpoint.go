@@ -0,0 +1,76 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Checkpoint has configuration parameters of checkpoints of topologies. A
+// checkpoint has states of UDSs and windows of boxes in a topology, and it's
+// stored in the UDS storage.
+type Checkpoint struct {
+	// Enabled is true when checkpoints are taken. The default value is false.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Interval is the interval of taking checkpoints in seconds. When it's
+	// 0, the default value, checkpoints are only taken via the API.
+	Interval float64 `json:"interval" yaml:"interval"`
+
+	// RestoreOnStartup is true when topologies defined in the config are
+	// restored from their latest checkpoints after their BQL files are
+	// applied on startup. The default value is true.
+	RestoreOnStartup bool `json:"restore_on_startup" yaml:"restore_on_startup"`
+}
+
+var (
+	checkpointSchemaString = `{
+	"type": "object",
+	"properties": {
+		"enabled": {
+			"type": "boolean"
+		},
+		"interval": {
+			"type": "number",
+			"minimum": 0
+		},
+		"restore_on_startup": {
+			"type": "boolean"
+		}
+	},
+	"additionalProperties": false
+}`
+	checkpointSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(checkpointSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	checkpointSchema = s
+}
+
+// NewCheckpoint creates a Checkpoint config parameters from a given map.
+func NewCheckpoint(m data.Map) (*Checkpoint, error) {
+	if err := validate(checkpointSchema, m); err != nil {
+		return nil, err
+	}
+	return newCheckpoint(m), nil
+}
+
+func newCheckpoint(m data.Map) *Checkpoint {
+	return &Checkpoint{
+		Enabled:          mustToBool(getWithDefault(m, "enabled", data.False)),
+		Interval:         mustToFloat(getWithDefault(m, "interval", data.Float(0))),
+		RestoreOnStartup: mustToBool(getWithDefault(m, "restore_on_startup", data.True)),
+	}
+}
+
+// ToMap returns checkpoint config information as data.Map.
+func (c *Checkpoint) ToMap() data.Map {
+	return data.Map{
+		"enabled":            data.Bool(c.Enabled),
+		"interval":           data.Float(c.Interval),
+		"restore_on_startup": data.Bool(c.RestoreOnStartup),
+	}
+}
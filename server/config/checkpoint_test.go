@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestCheckpoint(t *testing.T) {
+	Convey("Given a JSON config for checkpoint section", t, func() {
+		Convey("When the config is valid", func() {
+			c, err := NewCheckpoint(toMap(`{"enabled":true,"interval":60,"restore_on_startup":false}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(c.Enabled, ShouldBeTrue)
+				So(c.Interval, ShouldEqual, 60)
+				So(c.RestoreOnStartup, ShouldBeFalse)
+				So(c.ToMap(), ShouldResemble, data.Map{
+					"enabled":            data.True,
+					"interval":           data.Float(60),
+					"restore_on_startup": data.False,
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			c, err := NewCheckpoint(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then checkpoints should be disabled", func() {
+				So(c.Enabled, ShouldBeFalse)
+				So(c.Interval, ShouldEqual, 0)
+				So(c.RestoreOnStartup, ShouldBeTrue)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"enabled":"true"}`,
+				`{"interval":-1}`,
+				`{"interval":"1"}`,
+				`{"restore_on_startup":1}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewCheckpoint(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
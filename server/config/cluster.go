@@ -0,0 +1,136 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Cluster has configuration parameters of the cluster mode. In the cluster
+// mode, worker servers register themselves with a coordinator server, and
+// the coordinator places topologies created via its API on workers and
+// proxies requests for the topologies to them.
+type Cluster struct {
+	// Role is the role of the server in a cluster. It's one of "standalone",
+	// "coordinator", or "worker". The default value is "standalone", which
+	// disables the cluster mode.
+	Role string `json:"role" yaml:"role"`
+
+	// Coordinator is the base URL of the coordinator such as
+	// "http://coordinator:15601". It's required when Role is "worker".
+	Coordinator string `json:"coordinator,omitempty" yaml:"coordinator"`
+
+	// AdvertiseURL is the base URL through which the coordinator accesses
+	// this worker. It's required when Role is "worker".
+	AdvertiseURL string `json:"advertise_url,omitempty" yaml:"advertise_url"`
+
+	// HeartbeatInterval is the interval in seconds at which a worker sends
+	// a heartbeat to the coordinator.
+	HeartbeatInterval float64 `json:"heartbeat_interval" yaml:"heartbeat_interval"`
+
+	// WorkerTimeout is the time in seconds after which the coordinator
+	// regards a worker not sending heartbeats as down.
+	WorkerTimeout float64 `json:"worker_timeout" yaml:"worker_timeout"`
+
+	// ShuffleListenOn is the address on which the server receives tuples
+	// shuffled from other servers such as ":15602". The shuffle server isn't
+	// started when it's empty.
+	ShuffleListenOn string `json:"shuffle_listen_on,omitempty" yaml:"shuffle_listen_on"`
+}
+
+var (
+	clusterSchemaString = `{
+	"type": "object",
+	"properties": {
+		"role": {
+			"enum": ["standalone", "coordinator", "worker"]
+		},
+		"coordinator": {
+			"type": "string",
+			"pattern": "^https?://"
+		},
+		"advertise_url": {
+			"type": "string",
+			"pattern": "^https?://"
+		},
+		"heartbeat_interval": {
+			"type": "number",
+			"minimum": 0,
+			"exclusiveMinimum": true
+		},
+		"worker_timeout": {
+			"type": "number",
+			"minimum": 0,
+			"exclusiveMinimum": true
+		},
+		"shuffle_listen_on": {
+			"type": "string",
+			"pattern": "^.*:[0-9]+$"
+		}
+	},
+	"anyOf": [
+		{
+			"properties": {
+				"role": {
+					"enum": ["standalone", "coordinator"]
+				}
+			}
+		},
+		{
+			"properties": {
+				"role": {
+					"enum": ["worker"]
+				}
+			},
+			"required": ["role", "coordinator", "advertise_url"]
+		}
+	],
+	"additionalProperties": false
+}`
+	clusterSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(clusterSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	clusterSchema = s
+}
+
+// NewCluster creates a Cluster config parameters from a given map.
+func NewCluster(m data.Map) (*Cluster, error) {
+	if err := validate(clusterSchema, m); err != nil {
+		return nil, err
+	}
+	return newCluster(m), nil
+}
+
+func newCluster(m data.Map) *Cluster {
+	return &Cluster{
+		Role:              mustAsString(getWithDefault(m, "role", data.String("standalone"))),
+		Coordinator:       mustAsString(getWithDefault(m, "coordinator", data.String(""))),
+		AdvertiseURL:      mustAsString(getWithDefault(m, "advertise_url", data.String(""))),
+		HeartbeatInterval: mustToFloat(getWithDefault(m, "heartbeat_interval", data.Float(5))),
+		WorkerTimeout:     mustToFloat(getWithDefault(m, "worker_timeout", data.Float(30))),
+		ShuffleListenOn:   mustAsString(getWithDefault(m, "shuffle_listen_on", data.String(""))),
+	}
+}
+
+// ToMap returns cluster config information as data.Map.
+func (c *Cluster) ToMap() data.Map {
+	m := data.Map{
+		"role":               data.String(c.Role),
+		"heartbeat_interval": data.Float(c.HeartbeatInterval),
+		"worker_timeout":     data.Float(c.WorkerTimeout),
+	}
+	if c.Coordinator != "" {
+		m["coordinator"] = data.String(c.Coordinator)
+	}
+	if c.AdvertiseURL != "" {
+		m["advertise_url"] = data.String(c.AdvertiseURL)
+	}
+	if c.ShuffleListenOn != "" {
+		m["shuffle_listen_on"] = data.String(c.ShuffleListenOn)
+	}
+	return m
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"testing"
+)
+
+func TestCluster(t *testing.T) {
+	Convey("Given a JSON config for cluster section", t, func() {
+		Convey("When the config is empty", func() {
+			c, err := NewCluster(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have default values", func() {
+				So(c.Role, ShouldEqual, "standalone")
+				So(c.HeartbeatInterval, ShouldEqual, 5)
+				So(c.WorkerTimeout, ShouldEqual, 30)
+			})
+		})
+
+		Convey("When the config is a valid coordinator config", func() {
+			c, err := NewCluster(toMap(`{"role":"coordinator","worker_timeout":10}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(c.Role, ShouldEqual, "coordinator")
+				So(c.WorkerTimeout, ShouldEqual, 10)
+			})
+		})
+
+		Convey("When the config is a valid worker config", func() {
+			c, err := NewCluster(toMap(`{"role":"worker","coordinator":"http://coordinator:15601",
+				"advertise_url":"http://worker1:15601","heartbeat_interval":1.5}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(c.Role, ShouldEqual, "worker")
+				So(c.Coordinator, ShouldEqual, "http://coordinator:15601")
+				So(c.AdvertiseURL, ShouldEqual, "http://worker1:15601")
+				So(c.HeartbeatInterval, ShouldEqual, 1.5)
+			})
+
+			Convey("Then ToMap should return the same parameters", func() {
+				So(c.ToMap(), ShouldResemble, data.Map{
+					"role":               data.String("worker"),
+					"coordinator":        data.String("http://coordinator:15601"),
+					"advertise_url":      data.String("http://worker1:15601"),
+					"heartbeat_interval": data.Float(1.5),
+					"worker_timeout":     data.Float(30),
+				})
+			})
+		})
+
+		Convey("When the config has a shuffle address", func() {
+			c, err := NewCluster(toMap(`{"role":"worker","coordinator":"http://coordinator:15601",
+				"advertise_url":"http://worker1:15601","shuffle_listen_on":":15602"}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the address", func() {
+				So(c.ShuffleListenOn, ShouldEqual, ":15602")
+				So(c.ToMap()["shuffle_listen_on"], ShouldEqual, data.String(":15602"))
+			})
+		})
+
+		Convey("When the shuffle address doesn't have a port", func() {
+			_, err := NewCluster(toMap(`{"shuffle_listen_on":"localhost"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a worker config doesn't have the coordinator", func() {
+			_, err := NewCluster(toMap(`{"role":"worker","advertise_url":"http://worker1:15601"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the coordinator isn't a URL", func() {
+			_, err := NewCluster(toMap(`{"role":"worker","coordinator":"coordinator:15601",
+				"advertise_url":"http://worker1:15601"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the role is unknown", func() {
+			_, err := NewCluster(toMap(`{"role":"master"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the heartbeat interval isn't positive", func() {
+			_, err := NewCluster(toMap(`{"heartbeat_interval":0}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the config has an undefined field", func() {
+			_, err := NewCluster(toMap(`{"role":"coordinator","unknown":1}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
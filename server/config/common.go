@@ -36,6 +36,22 @@ func mustAsMap(v data.Value) data.Map {
 	return m
 }
 
+func mustToInt(v data.Value) int64 {
+	i, err := data.ToInt(v)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+func mustToFloat(v data.Value) float64 {
+	f, err := data.ToFloat(v)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
 func mustToBool(v data.Value) bool {
 	b, err := data.ToBool(v)
 	if err != nil {
@@ -44,6 +60,21 @@ func mustToBool(v data.Value) bool {
 	return b
 }
 
+func mustAsStringSlice(v data.Value) []string {
+	a, err := data.AsArray(v)
+	if err != nil {
+		panic(err)
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	s := make([]string, len(a))
+	for i, e := range a {
+		s[i] = mustAsString(e)
+	}
+	return s
+}
+
 func validate(schema *gojsonschema.Schema, m data.Map) error {
 	// GoLoader marshal and unmarshal the map.
 	res, err := schema.Validate(gojsonschema.NewGoLoader(m))
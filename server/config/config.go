@@ -22,6 +22,38 @@ type Config struct {
 
 	// Logging section has parameters related to logging.
 	Logging *Logging
+
+	// Cluster section has parameters of the cluster mode.
+	Cluster *Cluster
+
+	// Replication section has parameters of active/standby replication.
+	Replication *Replication
+
+	// Runtime section has parameters of the Go runtime such as GOMAXPROCS.
+	Runtime *Runtime
+
+	// BQL section has server-wide defaults of BQL statements.
+	BQL *BQL
+
+	// Hibernation section has parameters of hibernation of idle topologies.
+	Hibernation *Hibernation
+
+	// SelectResume section has parameters of resuming streaming SELECT
+	// statements after clients reconnect.
+	SelectResume *SelectResume
+
+	// Checkpoint section has parameters of checkpoints of topologies.
+	Checkpoint *Checkpoint
+
+	// Auth section has parameters of authentication of HTTP requests.
+	Auth *Auth
+
+	// Idempotency section has parameters of idempotency keys of requests.
+	Idempotency *Idempotency
+
+	// Webhooks section has webhooks receiving lifecycle events of
+	// topologies.
+	Webhooks *Webhooks
 }
 
 var (
@@ -31,10 +63,23 @@ var (
 		"network": %v,
 		"topologies": %v,
 		"storage": %v,
-		"logging": %v
+		"logging": %v,
+		"cluster": %v,
+		"replication": %v,
+		"runtime": %v,
+		"bql": %v,
+		"hibernation": %v,
+		"select_resume": %v,
+		"checkpoint": %v,
+		"auth": %v,
+		"idempotency": %v,
+		"webhooks": %v
 	},
 	"additionalProperties": false
-}`, networkSchemaString, topologiesSchemaString, storageSchemaString, loggingSchemaString)
+}`, networkSchemaString, topologiesSchemaString, storageSchemaString, loggingSchemaString, clusterSchemaString,
+		replicationSchemaString, runtimeSchemaString, bqlSchemaString, hibernationSchemaString,
+		selectResumeSchemaString, checkpointSchemaString, authSchemaString, idempotencySchemaString,
+		webhooksSchemaString)
 	rootSchema *gojsonschema.Schema
 )
 
@@ -51,22 +96,67 @@ func New(m data.Map) (*Config, error) {
 	if err := validate(rootSchema, m); err != nil {
 		return nil, err
 	}
+	rt, err := NewRuntime(mustAsMap(getWithDefault(m, "runtime", data.Map{})))
+	if err != nil {
+		return nil, err
+	}
 	return &Config{
-		Network:    newNetwork(mustAsMap(getWithDefault(m, "network", data.Map{}))),
-		Topologies: newTopologies(mustAsMap(getWithDefault(m, "topologies", data.Map{}))),
-		Storage:    newStorage(mustAsMap(getWithDefault(m, "storage", data.Map{}))),
-		Logging:    newLogging(mustAsMap(getWithDefault(m, "logging", data.Map{}))),
+		Network:      newNetwork(mustAsMap(getWithDefault(m, "network", data.Map{}))),
+		Topologies:   newTopologies(mustAsMap(getWithDefault(m, "topologies", data.Map{}))),
+		Storage:      newStorage(mustAsMap(getWithDefault(m, "storage", data.Map{}))),
+		Logging:      newLogging(mustAsMap(getWithDefault(m, "logging", data.Map{}))),
+		Cluster:      newCluster(mustAsMap(getWithDefault(m, "cluster", data.Map{}))),
+		Replication:  newReplication(mustAsMap(getWithDefault(m, "replication", data.Map{}))),
+		Runtime:      rt,
+		BQL:          newBQL(mustAsMap(getWithDefault(m, "bql", data.Map{}))),
+		Hibernation:  newHibernation(mustAsMap(getWithDefault(m, "hibernation", data.Map{}))),
+		SelectResume: newSelectResume(mustAsMap(getWithDefault(m, "select_resume", data.Map{}))),
+		Checkpoint:   newCheckpoint(mustAsMap(getWithDefault(m, "checkpoint", data.Map{}))),
+		Auth:         newAuth(mustAsMap(getWithDefault(m, "auth", data.Map{}))),
+		Idempotency:  newIdempotency(mustAsMap(getWithDefault(m, "idempotency", data.Map{}))),
+		Webhooks:     newWebhooks(mustAsMap(getWithDefault(m, "webhooks", data.Map{}))),
 	}, nil
 }
 
 // ToMap returns server config information as data.Map.
 func (c *Config) ToMap() data.Map {
-	return data.Map{
+	m := data.Map{
 		"network":    c.Network.ToMap(),
 		"topologies": c.Topologies.ToMap(),
 		"storage":    c.Storage.ToMap(),
 		"logging":    c.Logging.ToMap(),
 	}
+	if c.Cluster != nil {
+		m["cluster"] = c.Cluster.ToMap()
+	}
+	if c.Replication != nil {
+		m["replication"] = c.Replication.ToMap()
+	}
+	if c.Runtime != nil {
+		m["runtime"] = c.Runtime.ToMap()
+	}
+	if c.BQL != nil {
+		m["bql"] = c.BQL.ToMap()
+	}
+	if c.Hibernation != nil {
+		m["hibernation"] = c.Hibernation.ToMap()
+	}
+	if c.SelectResume != nil {
+		m["select_resume"] = c.SelectResume.ToMap()
+	}
+	if c.Checkpoint != nil {
+		m["checkpoint"] = c.Checkpoint.ToMap()
+	}
+	if c.Auth != nil {
+		m["auth"] = c.Auth.ToMap()
+	}
+	if c.Idempotency != nil {
+		m["idempotency"] = c.Idempotency.ToMap()
+	}
+	if c.Webhooks != nil {
+		m["webhooks"] = c.Webhooks.ToMap()
+	}
+	return m
 }
 
 // TODO: Add FromJSON or FromYAML if necessary
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Hibernation has configuration parameters of hibernation of idle
+// topologies. A hibernated topology is stopped and its states are kept as
+// checkpoints in the UDS storage. It's transparently restored when a request
+// to the topology arrives. When topologies are persisted with the topologies
+// storage and the UDS storage isn't in memory, a hibernated topology is
+// restored with its states even after the server restarts.
+type Hibernation struct {
+	// IdleTimeout is the time in seconds after which a topology without
+	// running sources or clients is hibernated. When it's 0, the default
+	// value, hibernation is disabled.
+	IdleTimeout float64 `json:"idle_timeout" yaml:"idle_timeout"`
+}
+
+var (
+	hibernationSchemaString = `{
+	"type": "object",
+	"properties": {
+		"idle_timeout": {
+			"type": "number",
+			"minimum": 0
+		}
+	},
+	"additionalProperties": false
+}`
+	hibernationSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(hibernationSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	hibernationSchema = s
+}
+
+// NewHibernation creates a Hibernation config parameters from a given map.
+func NewHibernation(m data.Map) (*Hibernation, error) {
+	if err := validate(hibernationSchema, m); err != nil {
+		return nil, err
+	}
+	return newHibernation(m), nil
+}
+
+func newHibernation(m data.Map) *Hibernation {
+	return &Hibernation{
+		IdleTimeout: mustToFloat(getWithDefault(m, "idle_timeout", data.Float(0))),
+	}
+}
+
+// ToMap returns hibernation config information as data.Map.
+func (h *Hibernation) ToMap() data.Map {
+	return data.Map{
+		"idle_timeout": data.Float(h.IdleTimeout),
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestHibernation(t *testing.T) {
+	Convey("Given a JSON config for hibernation section", t, func() {
+		Convey("When the config is valid", func() {
+			h, err := NewHibernation(toMap(`{"idle_timeout":300}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(h.IdleTimeout, ShouldEqual, 300)
+				So(h.ToMap(), ShouldResemble, data.Map{
+					"idle_timeout": data.Float(300),
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			h, err := NewHibernation(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then hibernation should be disabled", func() {
+				So(h.IdleTimeout, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"idle_timeout":-1}`,
+				`{"idle_timeout":"1"}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewHibernation(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Idempotency has configuration parameters of idempotency keys. When it's
+// enabled, a response to a request having the Idempotency-Key header is kept
+// and returned again to a retried request having the same key without
+// processing the request twice.
+type Idempotency struct {
+	// Retention is the time in seconds for which a response is kept. When
+	// it's 0, the default value, idempotency keys are ignored.
+	Retention float64 `json:"retention" yaml:"retention"`
+
+	// MaxKeys is the maximum number of responses kept at once. The oldest
+	// response is discarded when a new key exceeds the limit.
+	MaxKeys int `json:"max_keys" yaml:"max_keys"`
+}
+
+var (
+	idempotencySchemaString = `{
+	"type": "object",
+	"properties": {
+		"retention": {
+			"type": "number",
+			"minimum": 0
+		},
+		"max_keys": {
+			"type": "integer",
+			"minimum": 1
+		}
+	},
+	"additionalProperties": false
+}`
+	idempotencySchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(idempotencySchemaString))
+	if err != nil {
+		panic(err)
+	}
+	idempotencySchema = s
+}
+
+// NewIdempotency creates an Idempotency config parameters from a given map.
+func NewIdempotency(m data.Map) (*Idempotency, error) {
+	if err := validate(idempotencySchema, m); err != nil {
+		return nil, err
+	}
+	return newIdempotency(m), nil
+}
+
+func newIdempotency(m data.Map) *Idempotency {
+	return &Idempotency{
+		Retention: mustToFloat(getWithDefault(m, "retention", data.Float(0))),
+		MaxKeys:   int(mustToInt(getWithDefault(m, "max_keys", data.Int(10000)))),
+	}
+}
+
+// ToMap returns idempotency config information as data.Map.
+func (i *Idempotency) ToMap() data.Map {
+	return data.Map{
+		"retention": data.Float(i.Retention),
+		"max_keys":  data.Int(i.MaxKeys),
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestIdempotency(t *testing.T) {
+	Convey("Given a JSON config for idempotency section", t, func() {
+		Convey("When the config is valid", func() {
+			i, err := NewIdempotency(toMap(`{"retention":30,"max_keys":100}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(i.Retention, ShouldEqual, 30)
+				So(i.MaxKeys, ShouldEqual, 100)
+				So(i.ToMap(), ShouldResemble, data.Map{
+					"retention": data.Float(30),
+					"max_keys":  data.Int(100),
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			i, err := NewIdempotency(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then idempotency keys should be ignored", func() {
+				So(i.Retention, ShouldEqual, 0)
+				So(i.MaxKeys, ShouldEqual, 10000)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"retention":-1}`,
+				`{"retention":"1"}`,
+				`{"max_keys":0}`,
+				`{"max_keys":1.5}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewIdempotency(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
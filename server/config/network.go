@@ -15,6 +15,30 @@ const (
 type Network struct {
 	// ListenOn has binding information in "host:port" format.
 	ListenOn string `json:"listen_on" yaml:"listen_on"`
+
+	// TLS has parameters of TLS of the HTTP server. The server doesn't use
+	// TLS when it's nil.
+	TLS *TLS `json:"tls,omitempty" yaml:"tls"`
+}
+
+// TLS has parameters of TLS of the HTTP server. When it's enabled, the API
+// is served over https and wss.
+type TLS struct {
+	// CertFile and KeyFile are paths to PEM encoded files having the
+	// certificate and the private key of the server.
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+
+	// ClientCAFile is a path to a PEM encoded file having certificates of
+	// CAs which verify client certificates. Client certificates aren't
+	// requested when it's empty.
+	ClientCAFile string `json:"client_ca_file,omitempty" yaml:"client_ca_file"`
+
+	// ClientAuth is "require", the default value, or "verify_if_given". When
+	// it's "verify_if_given", a client without a certificate is accepted but
+	// a given certificate is still verified. It's only used when
+	// ClientCAFile is set.
+	ClientAuth string `json:"client_auth" yaml:"client_auth"`
 }
 
 var (
@@ -24,6 +48,28 @@ var (
 		"listen_on": {
 			"type": "string",
 			"pattern": "^.*:[0-9]+$"
+		},
+		"tls": {
+			"type": "object",
+			"properties": {
+				"cert_file": {
+					"type": "string",
+					"minLength": 1
+				},
+				"key_file": {
+					"type": "string",
+					"minLength": 1
+				},
+				"client_ca_file": {
+					"type": "string",
+					"minLength": 1
+				},
+				"client_auth": {
+					"enum": ["require", "verify_if_given"]
+				}
+			},
+			"required": ["cert_file", "key_file"],
+			"additionalProperties": false
 		}
 	},
 	"additionalProperties": false
@@ -48,14 +94,41 @@ func NewNetwork(m data.Map) (*Network, error) {
 }
 
 func newNetwork(m data.Map) *Network {
-	return &Network{
+	n := &Network{
 		ListenOn: mustAsString(getWithDefault(m, "listen_on", data.String(fmt.Sprintf(":%d", DefaultPort)))),
 	}
+	if v, ok := m["tls"]; ok {
+		t := mustAsMap(v)
+		n.TLS = &TLS{
+			CertFile:     mustAsString(t["cert_file"]),
+			KeyFile:      mustAsString(t["key_file"]),
+			ClientCAFile: mustAsString(getWithDefault(t, "client_ca_file", data.String(""))),
+			ClientAuth:   mustAsString(getWithDefault(t, "client_auth", data.String("require"))),
+		}
+	}
+	return n
 }
 
 // ToMap returns network config information as data.Map.
 func (n *Network) ToMap() data.Map {
-	return data.Map{
+	m := data.Map{
 		"listen_on": data.String(n.ListenOn),
 	}
+	if n.TLS != nil {
+		m["tls"] = n.TLS.ToMap()
+	}
+	return m
+}
+
+// ToMap returns tls config information as data.Map.
+func (t *TLS) ToMap() data.Map {
+	m := data.Map{
+		"cert_file":   data.String(t.CertFile),
+		"key_file":    data.String(t.KeyFile),
+		"client_auth": data.String(t.ClientAuth),
+	}
+	if t.ClientCAFile != "" {
+		m["client_ca_file"] = data.String(t.ClientCAFile)
+	}
+	return m
 }
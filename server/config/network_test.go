@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"testing"
 )
 
@@ -35,6 +36,60 @@ func TestNetwork(t *testing.T) {
 			})
 		})
 
+		Convey("When the config has tls", func() {
+			n, err := NewNetwork(toMap(`{"tls":{"cert_file":"server.crt","key_file":"server.key"}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters and default values", func() {
+				So(n.TLS, ShouldResemble, &TLS{
+					CertFile:   "server.crt",
+					KeyFile:    "server.key",
+					ClientAuth: "require",
+				})
+				So(n.ToMap()["tls"], ShouldResemble, data.Map{
+					"cert_file":   data.String("server.crt"),
+					"key_file":    data.String("server.key"),
+					"client_auth": data.String("require"),
+				})
+			})
+		})
+
+		Convey("When the config has tls with client authentication", func() {
+			n, err := NewNetwork(toMap(`{"tls":{"cert_file":"server.crt","key_file":"server.key","client_ca_file":"ca.crt","client_auth":"verify_if_given"}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(n.TLS.ClientCAFile, ShouldEqual, "ca.crt")
+				So(n.TLS.ClientAuth, ShouldEqual, "verify_if_given")
+			})
+		})
+
+		Convey("When the config doesn't have tls", func() {
+			n, err := NewNetwork(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then TLS should be disabled", func() {
+				So(n.TLS, ShouldBeNil)
+				So(n.ToMap(), ShouldNotContainKey, "tls")
+			})
+		})
+
+		Convey("When validating tls", func() {
+			for _, c := range []string{
+				`{"tls":{}}`,
+				`{"tls":{"cert_file":"server.crt"}}`,
+				`{"tls":{"key_file":"server.key"}}`,
+				`{"tls":{"cert_file":"","key_file":"server.key"}}`,
+				`{"tls":{"cert_file":"server.crt","key_file":"server.key","client_auth":"none"}}`,
+				`{"tls":{"cert_file":"server.crt","key_file":"server.key","unknown":1}}`,
+			} {
+				Convey("Then it should reject "+c, func() {
+					_, err := NewNetwork(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+
 		Convey("When validating listen_on", func() {
 			for _, addr := range []string{fmt.Sprintf("127.0.0.1:%d", DefaultPort), fmt.Sprintf("localhost:%d", DefaultPort), fmt.Sprintf(":%d", DefaultPort)} {
 				Convey(fmt.Sprint("Then it should accept ", addr), func() {
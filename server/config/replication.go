@@ -0,0 +1,111 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Replication has configuration parameters of active/standby replication.
+// A standby server periodically fetches definitions of topologies and
+// checkpoints of their states from the primary server, and restores them when
+// it's promoted.
+type Replication struct {
+	// Role is the role of the server in replication. It's one of "none",
+	// "primary", or "standby". The default value is "none", which disables
+	// replication.
+	Role string `json:"role" yaml:"role"`
+
+	// Primary is the base URL of the primary server such as
+	// "http://primary:15601". It's required when Role is "standby".
+	Primary string `json:"primary,omitempty" yaml:"primary"`
+
+	// SyncInterval is the interval in seconds at which a standby server
+	// fetches a snapshot from the primary server.
+	SyncInterval float64 `json:"sync_interval" yaml:"sync_interval"`
+
+	// FailoverTimeout is the time in seconds after which a standby server
+	// promotes itself when it cannot fetch a snapshot from the primary server.
+	// When it's 0, a standby server is only promoted via the API.
+	FailoverTimeout float64 `json:"failover_timeout" yaml:"failover_timeout"`
+}
+
+var (
+	replicationSchemaString = `{
+	"type": "object",
+	"properties": {
+		"role": {
+			"enum": ["none", "primary", "standby"]
+		},
+		"primary": {
+			"type": "string",
+			"pattern": "^https?://"
+		},
+		"sync_interval": {
+			"type": "number",
+			"minimum": 0,
+			"exclusiveMinimum": true
+		},
+		"failover_timeout": {
+			"type": "number",
+			"minimum": 0
+		}
+	},
+	"anyOf": [
+		{
+			"properties": {
+				"role": {
+					"enum": ["none", "primary"]
+				}
+			}
+		},
+		{
+			"properties": {
+				"role": {
+					"enum": ["standby"]
+				}
+			},
+			"required": ["role", "primary"]
+		}
+	],
+	"additionalProperties": false
+}`
+	replicationSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(replicationSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	replicationSchema = s
+}
+
+// NewReplication creates a Replication config parameters from a given map.
+func NewReplication(m data.Map) (*Replication, error) {
+	if err := validate(replicationSchema, m); err != nil {
+		return nil, err
+	}
+	return newReplication(m), nil
+}
+
+func newReplication(m data.Map) *Replication {
+	return &Replication{
+		Role:            mustAsString(getWithDefault(m, "role", data.String("none"))),
+		Primary:         mustAsString(getWithDefault(m, "primary", data.String(""))),
+		SyncInterval:    mustToFloat(getWithDefault(m, "sync_interval", data.Float(10))),
+		FailoverTimeout: mustToFloat(getWithDefault(m, "failover_timeout", data.Float(0))),
+	}
+}
+
+// ToMap returns replication config information as data.Map.
+func (r *Replication) ToMap() data.Map {
+	m := data.Map{
+		"role":             data.String(r.Role),
+		"sync_interval":    data.Float(r.SyncInterval),
+		"failover_timeout": data.Float(r.FailoverTimeout),
+	}
+	if r.Primary != "" {
+		m["primary"] = data.String(r.Primary)
+	}
+	return m
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"testing"
+)
+
+func TestReplication(t *testing.T) {
+	Convey("Given a JSON config for replication section", t, func() {
+		Convey("When the config is empty", func() {
+			r, err := NewReplication(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have default values", func() {
+				So(r.Role, ShouldEqual, "none")
+				So(r.SyncInterval, ShouldEqual, 10)
+				So(r.FailoverTimeout, ShouldEqual, 0)
+			})
+		})
+
+		Convey("When the config is a valid standby config", func() {
+			r, err := NewReplication(toMap(`{"role":"standby","primary":"http://primary:15601",
+				"sync_interval":2,"failover_timeout":30}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(r.Role, ShouldEqual, "standby")
+				So(r.Primary, ShouldEqual, "http://primary:15601")
+				So(r.SyncInterval, ShouldEqual, 2)
+				So(r.FailoverTimeout, ShouldEqual, 30)
+			})
+
+			Convey("Then ToMap should return the same parameters", func() {
+				So(r.ToMap(), ShouldResemble, data.Map{
+					"role":             data.String("standby"),
+					"primary":          data.String("http://primary:15601"),
+					"sync_interval":    data.Float(2),
+					"failover_timeout": data.Float(30),
+				})
+			})
+		})
+
+		Convey("When a standby config doesn't have the primary", func() {
+			_, err := NewReplication(toMap(`{"role":"standby"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the role is unknown", func() {
+			_, err := NewReplication(toMap(`{"role":"secondary"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the sync interval isn't positive", func() {
+			_, err := NewReplication(toMap(`{"sync_interval":0}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the failover timeout is negative", func() {
+			_, err := NewReplication(toMap(`{"failover_timeout":-1}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the config has an undefined field", func() {
+			_, err := NewReplication(toMap(`{"role":"primary","mode":"sync"}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
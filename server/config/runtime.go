@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Runtime has configuration parameters of the Go runtime applied on startup.
+type Runtime struct {
+	// MaxProcs is the maximum number of CPUs executing Go code
+	// simultaneously, i.e. GOMAXPROCS. When it's 0, the value given by the
+	// environment variable or the runtime is used.
+	MaxProcs int `json:"max_procs" yaml:"max_procs"`
+
+	// GOGC is the percentage of heap growth triggering garbage collection.
+	// It's -1 when the garbage collector is disabled by "off". When it's 0,
+	// the value given by the environment variable or the runtime is used.
+	GOGC int `json:"gogc" yaml:"gogc"`
+
+	// MemoryLimit is the soft memory limit of the runtime in bytes. When
+	// it's 0, the value given by the environment variable or the runtime is
+	// used. It can be written with a unit such as "512MB" or "2GiB" in a
+	// config file.
+	MemoryLimit int64 `json:"memory_limit" yaml:"memory_limit"`
+
+	// MemoryBallast is the size of memory in bytes allocated on startup but
+	// never used so that garbage collection runs less frequently when the
+	// heap is small. It can be written with a unit in the same way as
+	// MemoryLimit.
+	MemoryBallast int64 `json:"memory_ballast" yaml:"memory_ballast"`
+}
+
+var (
+	runtimeSizeSchema = `{
+			"oneOf": [
+				{"type": "integer", "minimum": 0},
+				{"type": "string", "pattern": "^[0-9]+ *(B|KB|MB|GB|TB|KiB|MiB|GiB|TiB)?$"}
+			]
+		}`
+	runtimeSchemaString = fmt.Sprintf(`{
+	"type": "object",
+	"properties": {
+		"max_procs": {
+			"type": "integer",
+			"minimum": 0
+		},
+		"gogc": {
+			"oneOf": [
+				{"type": "integer", "minimum": 1},
+				{"enum": ["off"]}
+			]
+		},
+		"memory_limit": %v,
+		"memory_ballast": %v
+	},
+	"additionalProperties": false
+}`, runtimeSizeSchema, runtimeSizeSchema)
+	runtimeSchema *gojsonschema.Schema
+
+	runtimeSizeRegexp = regexp.MustCompile("^([0-9]+) *([KMGT]i?)?B?$")
+	runtimeSizeUnits  = map[string]int64{
+		"":   1,
+		"K":  1000,
+		"M":  1000 * 1000,
+		"G":  1000 * 1000 * 1000,
+		"T":  1000 * 1000 * 1000 * 1000,
+		"Ki": 1 << 10,
+		"Mi": 1 << 20,
+		"Gi": 1 << 30,
+		"Ti": 1 << 40,
+	}
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(runtimeSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	runtimeSchema = s
+}
+
+// NewRuntime creates a Runtime config parameters from a given map.
+func NewRuntime(m data.Map) (*Runtime, error) {
+	if err := validate(runtimeSchema, m); err != nil {
+		return nil, err
+	}
+	for _, k := range []string{"memory_limit", "memory_ballast"} {
+		if v, ok := m[k]; ok {
+			if _, err := parseRuntimeSize(v); err != nil {
+				return nil, fmt.Errorf("%v: %v", k, err)
+			}
+		}
+	}
+	return newRuntime(m), nil
+}
+
+func newRuntime(m data.Map) *Runtime {
+	gogc := 0
+	if v, ok := m["gogc"]; ok {
+		if s, err := data.AsString(v); err == nil && s == "off" {
+			gogc = -1
+		} else {
+			gogc = int(mustToInt(v))
+		}
+	}
+	return &Runtime{
+		MaxProcs:      int(mustToInt(getWithDefault(m, "max_procs", data.Int(0)))),
+		GOGC:          gogc,
+		MemoryLimit:   mustParseRuntimeSize(getWithDefault(m, "memory_limit", data.Int(0))),
+		MemoryBallast: mustParseRuntimeSize(getWithDefault(m, "memory_ballast", data.Int(0))),
+	}
+}
+
+func parseRuntimeSize(v data.Value) (int64, error) {
+	if v.Type() != data.TypeString {
+		return data.ToInt(v)
+	}
+	s, _ := data.AsString(v)
+	ms := runtimeSizeRegexp.FindStringSubmatch(s)
+	if ms == nil {
+		return 0, fmt.Errorf("invalid size: %v", s)
+	}
+	n, err := strconv.ParseInt(ms[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	unit := runtimeSizeUnits[ms[2]]
+	if n > (1<<63-1)/unit {
+		return 0, fmt.Errorf("too large size: %v", s)
+	}
+	return n * unit, nil
+}
+
+func mustParseRuntimeSize(v data.Value) int64 {
+	n, err := parseRuntimeSize(v)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// ToMap returns runtime config information as data.Map.
+func (r *Runtime) ToMap() data.Map {
+	m := data.Map{
+		"max_procs":      data.Int(r.MaxProcs),
+		"memory_limit":   data.Int(r.MemoryLimit),
+		"memory_ballast": data.Int(r.MemoryBallast),
+	}
+	if r.GOGC < 0 {
+		m["gogc"] = data.String("off")
+	} else {
+		m["gogc"] = data.Int(r.GOGC)
+	}
+	return m
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestRuntime(t *testing.T) {
+	Convey("Given a JSON config for runtime section", t, func() {
+		Convey("When the config is valid", func() {
+			r, err := NewRuntime(toMap(`{"max_procs":4,"gogc":200,"memory_limit":"2GiB","memory_ballast":1000}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(r.MaxProcs, ShouldEqual, 4)
+				So(r.GOGC, ShouldEqual, 200)
+				So(r.MemoryLimit, ShouldEqual, 2<<30)
+				So(r.MemoryBallast, ShouldEqual, 1000)
+			})
+
+			Convey("Then ToMap should return the parameters", func() {
+				So(r.ToMap(), ShouldResemble, data.Map{
+					"max_procs":      data.Int(4),
+					"gogc":           data.Int(200),
+					"memory_limit":   data.Int(2 << 30),
+					"memory_ballast": data.Int(1000),
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			r, err := NewRuntime(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should leave the runtime unchanged", func() {
+				So(*r, ShouldResemble, Runtime{})
+			})
+		})
+
+		Convey("When gogc is off", func() {
+			r, err := NewRuntime(toMap(`{"gogc":"off"}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be -1", func() {
+				So(r.GOGC, ShouldEqual, -1)
+				So(r.ToMap()["gogc"], ShouldEqual, data.String("off"))
+			})
+		})
+
+		Convey("When sizes have units", func() {
+			for s, n := range map[string]int64{
+				"10":     10,
+				"10B":    10,
+				"1KB":    1000,
+				"1 MB":   1000 * 1000,
+				"3GB":    3 * 1000 * 1000 * 1000,
+				"2KiB":   2 << 10,
+				"512MiB": 512 << 20,
+				"1TiB":   1 << 40,
+			} {
+				s, n := s, n
+				Convey("Then it should accept "+s, func() {
+					r, err := NewRuntime(data.Map{"memory_limit": data.String(s)})
+					So(err, ShouldBeNil)
+					So(r.MemoryLimit, ShouldEqual, n)
+				})
+			}
+		})
+
+		Convey("When the config has invalid values", func() {
+			for _, js := range []string{
+				`{"max_procs":-1}`,
+				`{"max_procs":1.5}`,
+				`{"gogc":0}`,
+				`{"gogc":"on"}`,
+				`{"memory_limit":-1}`,
+				`{"memory_limit":"1XB"}`,
+				`{"memory_ballast":"99999999999TiB"}`,
+				`{"max_proc":1}`,
+			} {
+				Convey("Then it should be invalid: "+js, func() {
+					_, err := NewRuntime(toMap(js))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
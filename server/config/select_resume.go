@@ -0,0 +1,70 @@
+package config
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// SelectResume has configuration parameters of resuming streaming SELECT
+// statements. When it's enabled, a streaming SELECT statement isn't removed
+// as soon as its client disconnects. The client can resume the statement
+// with the token issued by the server within the grace period.
+type SelectResume struct {
+	// GracePeriod is the time in seconds for which a SELECT statement is
+	// kept after its client disconnects. When it's 0, the default value,
+	// resuming is disabled.
+	GracePeriod float64 `json:"grace_period" yaml:"grace_period"`
+
+	// BufferSize is the number of the latest tuples kept by a SELECT
+	// statement so that they can be sent again when the client resumes.
+	BufferSize int `json:"buffer_size" yaml:"buffer_size"`
+}
+
+var (
+	selectResumeSchemaString = `{
+	"type": "object",
+	"properties": {
+		"grace_period": {
+			"type": "number",
+			"minimum": 0
+		},
+		"buffer_size": {
+			"type": "integer",
+			"minimum": 1
+		}
+	},
+	"additionalProperties": false
+}`
+	selectResumeSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(selectResumeSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	selectResumeSchema = s
+}
+
+// NewSelectResume creates a SelectResume config parameters from a given map.
+func NewSelectResume(m data.Map) (*SelectResume, error) {
+	if err := validate(selectResumeSchema, m); err != nil {
+		return nil, err
+	}
+	return newSelectResume(m), nil
+}
+
+func newSelectResume(m data.Map) *SelectResume {
+	return &SelectResume{
+		GracePeriod: mustToFloat(getWithDefault(m, "grace_period", data.Float(0))),
+		BufferSize:  int(mustToInt(getWithDefault(m, "buffer_size", data.Int(1000)))),
+	}
+}
+
+// ToMap returns select_resume config information as data.Map.
+func (s *SelectResume) ToMap() data.Map {
+	return data.Map{
+		"grace_period": data.Float(s.GracePeriod),
+		"buffer_size":  data.Int(s.BufferSize),
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSelectResume(t *testing.T) {
+	Convey("Given a JSON config for select_resume section", t, func() {
+		Convey("When the config is valid", func() {
+			s, err := NewSelectResume(toMap(`{"grace_period":30,"buffer_size":100}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(s.GracePeriod, ShouldEqual, 30)
+				So(s.BufferSize, ShouldEqual, 100)
+				So(s.ToMap(), ShouldResemble, data.Map{
+					"grace_period": data.Float(30),
+					"buffer_size":  data.Int(100),
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			s, err := NewSelectResume(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then resuming should be disabled", func() {
+				So(s.GracePeriod, ShouldEqual, 0)
+				So(s.BufferSize, ShouldEqual, 1000)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"grace_period":-1}`,
+				`{"grace_period":"1"}`,
+				`{"buffer_size":0}`,
+				`{"buffer_size":1.5}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewSelectResume(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
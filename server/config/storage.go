@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
 )
@@ -8,19 +9,64 @@ import (
 // Storage has storage configuration parameters for components in SensorBee.
 type Storage struct {
 	UDS UDSStorage `json:"uds" yaml:"uds"`
+
+	// Topologies is the storage of definitions of topologies created via the
+	// API, which are restored when the server restarts.
+	Topologies TopologyStorage `json:"topologies" yaml:"topologies"`
 }
 
 // UDSStorage has configuration parameters for the storage of UDSs.
 type UDSStorage struct {
 	Type   string   `json:"type" yaml:"params"`
 	Params data.Map `json:"params" yaml:"params"`
+
+	// Encryption, if not nil, encrypts states before they're saved to the
+	// storage. It can be specified for storage types persisting states.
+	Encryption *StorageEncryption `json:"encryption,omitempty" yaml:"encryption"`
+}
+
+// TopologyStorage has configuration parameters for the storage of
+// definitions of topologies. Its Type is "none", which doesn't persist
+// definitions, or "fs", which records them to the file given by the "path"
+// parameter.
+type TopologyStorage struct {
+	Type   string   `json:"type" yaml:"type"`
+	Params data.Map `json:"params" yaml:"params"`
+}
+
+// StorageEncryption has configuration parameters of encryption at rest. A
+// key is a base64 encoded 16, 24, or 32 bytes AES key read from a file or an
+// environment variable, so that it doesn't need to be written in the config
+// file. Exactly one of KeyFile and KeyEnv must be specified.
+type StorageEncryption struct {
+	KeyFile string `json:"key_file,omitempty" yaml:"key_file"`
+	KeyEnv  string `json:"key_env,omitempty" yaml:"key_env"`
 }
 
 // Because data.Map doesn't support YAML encoding, UDSStorage.Params has type
 // map[string]interface{} instead of data.Map.
 
 var (
-	storageSchemaString = `{
+	storageEncryptionSchemaString = `{
+	"type": "object",
+	"properties": {
+		"key_file": {
+			"type": "string",
+			"minLength": 1
+		},
+		"key_env": {
+			"type": "string",
+			"minLength": 1
+		}
+	},
+	"oneOf": [
+		{"required": ["key_file"]},
+		{"required": ["key_env"]}
+	],
+	"additionalProperties": false
+}`
+
+	storageSchemaString = fmt.Sprintf(`{
 	"type": "object",
 	"properties": {
 		"uds": {
@@ -71,16 +117,150 @@ var (
 									"type": "null"
 								}
 							]
+						},
+						"encryption": %[1]v
+					},
+					"required": ["type"],
+					"additionalProperties": false
+				},
+				{
+					"type": "object",
+					"properties": {
+						"type": {
+							"enum": ["s3"]
+						},
+						"params": {
+							"type": "object",
+							"properties": {
+								"bucket": {
+									"type": "string",
+									"minLength": 1
+								},
+								"region": {
+									"type": "string"
+								},
+								"endpoint": {
+									"type": "string"
+								},
+								"prefix": {
+									"type": "string"
+								},
+								"access_key_id": {
+									"type": "string"
+								},
+								"secret_access_key": {
+									"type": "string"
+								}
+							},
+							"required": ["bucket"],
+							"additionalProperties": false
+						},
+						"encryption": %[1]v
+					},
+					"required": ["type", "params"],
+					"additionalProperties": false
+				},
+				{
+					"type": "object",
+					"properties": {
+						"type": {
+							"enum": ["redis"]
+						},
+						"params": {
+							"type": "object",
+							"properties": {
+								"address": {
+									"type": "string",
+									"minLength": 1
+								},
+								"password": {
+									"type": "string"
+								},
+								"db": {
+									"type": "integer",
+									"minimum": 0
+								},
+								"prefix": {
+									"type": "string"
+								}
+							},
+							"required": ["address"],
+							"additionalProperties": false
+						},
+						"encryption": %[1]v
+					},
+					"required": ["type", "params"],
+					"additionalProperties": false
+				},
+				{
+					"type": "object",
+					"properties": {
+						"type": {
+							"enum": ["sql"]
+						},
+						"params": {
+							"type": "object",
+							"properties": {
+								"driver": {
+									"type": "string",
+									"minLength": 1
+								},
+								"data_source": {
+									"type": "string"
+								},
+								"table": {
+									"type": "string",
+									"minLength": 1
+								}
+							},
+							"required": ["driver", "data_source"],
+							"additionalProperties": false
+						},
+						"encryption": %[1]v
+					},
+					"required": ["type", "params"],
+					"additionalProperties": false
+				}
+			]
+		},
+		"topologies": {
+			"anyOf": [
+				{
+					"type": "object",
+					"properties": {
+						"type": {
+							"enum": ["none"]
 						}
 					},
 					"required": ["type"],
 					"additionalProperties": false
+				},
+				{
+					"type": "object",
+					"properties": {
+						"type": {
+							"enum": ["fs"]
+						},
+						"params": {
+							"type": "object",
+							"properties": {
+								"path": {
+									"type": "string",
+									"minLength": 1
+								}
+							},
+							"required": ["path"],
+							"additionalProperties": false
+						}
+					},
+					"required": ["type", "params"],
+					"additionalProperties": false
 				}
 			]
 		}
 	},
 	"additionalProperties": false
-}`
+}`, storageEncryptionSchemaString)
 	storageSchema *gojsonschema.Schema
 	// TODO: add patterns for filepath validation
 )
@@ -112,19 +292,52 @@ func newStorage(m data.Map) *Storage {
 
 	return &Storage{
 		UDS: UDSStorage{
-			Type:   mustAsString(getWithDefault(m, "uds.type", data.String("in_memory"))),
-			Params: mustAsMap(udsParams),
+			Type:       mustAsString(getWithDefault(m, "uds.type", data.String("in_memory"))),
+			Params:     mustAsMap(udsParams),
+			Encryption: newStorageEncryption(getWithDefault(m, "uds.encryption", data.Null{})),
+		},
+		Topologies: TopologyStorage{
+			Type:   mustAsString(getWithDefault(m, "topologies.type", data.String("none"))),
+			Params: mustAsMap(getWithDefault(m, "topologies.params", data.Map{})),
 		},
 	}
 }
 
+func newStorageEncryption(v data.Value) *StorageEncryption {
+	if v.Type() == data.TypeNull {
+		return nil
+	}
+	m := mustAsMap(v)
+	return &StorageEncryption{
+		KeyFile: mustAsString(getWithDefault(m, "key_file", data.String(""))),
+		KeyEnv:  mustAsString(getWithDefault(m, "key_env", data.String(""))),
+	}
+}
+
 // ToMap returns storage config information as data.Map.
 func (s *Storage) ToMap() data.Map {
-	return data.Map{
-		"uds": data.Map{
-			"params": s.UDS.Params,
-			"type":   data.String(s.UDS.Type),
-		},
+	uds := data.Map{
+		"params": s.UDS.Params,
+		"type":   data.String(s.UDS.Type),
 	}
-
+	if e := s.UDS.Encryption; e != nil {
+		enc := data.Map{}
+		if e.KeyFile != "" {
+			enc["key_file"] = data.String(e.KeyFile)
+		}
+		if e.KeyEnv != "" {
+			enc["key_env"] = data.String(e.KeyEnv)
+		}
+		uds["encryption"] = enc
+	}
+	m := data.Map{
+		"uds": uds,
+	}
+	if s.Topologies.Type != "" {
+		m["topologies"] = data.Map{
+			"type":   data.String(s.Topologies.Type),
+			"params": s.Topologies.Params,
+		}
+	}
+	return m
 }
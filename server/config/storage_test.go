@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"testing"
 )
 
@@ -91,6 +92,14 @@ func TestUDSStorageInMemory(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 		})
+
+		Convey("When encryption is specified", func() {
+			_, err := NewStorage(toMap(`{"uds":{"type":"in_memory","encryption":{"key_env":"SB_UDS_KEY"}}}`))
+
+			Convey("Then it shouldn't be valid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
 
@@ -163,5 +172,175 @@ func TestUDSStorageFS(t *testing.T) {
 
 			// TODO: test invalid format
 		})
+
+		Convey("When encryption is specified", func() {
+			s, err := NewStorage(toMap(`{"uds":{"type":"fs","params":{"dir":"/path/to/dir"},"encryption":{"key_env":"SB_UDS_KEY"}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the encryption config", func() {
+				So(s.UDS.Encryption, ShouldResemble, &StorageEncryption{KeyEnv: "SB_UDS_KEY"})
+			})
+
+			Convey("Then ToMap should have it", func() {
+				m := s.ToMap()
+				e, err := m.Get(data.MustCompilePath("uds.encryption"))
+				So(err, ShouldBeNil)
+				So(e, ShouldResemble, data.Map{"key_env": data.String("SB_UDS_KEY")})
+			})
+		})
+
+		Convey("When encryption isn't specified", func() {
+			s, err := NewStorage(toMap(`{"uds":{"type":"fs"}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it shouldn't have the encryption config", func() {
+				So(s.UDS.Encryption, ShouldBeNil)
+				So(s.ToMap()["uds"], ShouldNotContainKey, "encryption")
+			})
+		})
+
+		Convey("When encryption has both key_file and key_env", func() {
+			_, err := NewStorage(toMap(`{"uds":{"type":"fs","encryption":{"key_file":"/path/to/key","key_env":"SB_UDS_KEY"}}}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When encryption doesn't have a key", func() {
+			_, err := NewStorage(toMap(`{"uds":{"type":"fs","encryption":{}}}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestUDSStorageRemote(t *testing.T) {
+	Convey("Given a JSON config for storage.uds section with a remote storage type", t, func() {
+		Convey("When the s3 config is valid", func() {
+			s, err := NewStorage(toMap(`{"uds":{"type":"s3","params":{"bucket":"states","region":"ap-northeast-1","prefix":"sensorbee/"}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(s.UDS.Type, ShouldEqual, "s3")
+				So(s.UDS.Params["bucket"], ShouldEqual, "states")
+				So(s.UDS.Params["region"], ShouldEqual, "ap-northeast-1")
+			})
+		})
+
+		Convey("When the s3 config doesn't have a bucket", func() {
+			_, err := NewStorage(toMap(`{"uds":{"type":"s3","params":{"region":"ap-northeast-1"}}}`))
+
+			Convey("Then it shouldn't be valid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the redis config is valid", func() {
+			s, err := NewStorage(toMap(`{"uds":{"type":"redis","params":{"address":"localhost:6379","db":2},"encryption":{"key_env":"SB_UDS_KEY"}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(s.UDS.Type, ShouldEqual, "redis")
+				So(s.UDS.Params["address"], ShouldEqual, "localhost:6379")
+				So(s.UDS.Params["db"], ShouldEqual, 2)
+				So(s.UDS.Encryption, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the redis config has a negative db", func() {
+			_, err := NewStorage(toMap(`{"uds":{"type":"redis","params":{"address":"localhost:6379","db":-1}}}`))
+
+			Convey("Then it shouldn't be valid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the sql config is valid", func() {
+			s, err := NewStorage(toMap(`{"uds":{"type":"sql","params":{"driver":"postgres","data_source":"dbname=sensorbee","table":"uds"}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(s.UDS.Type, ShouldEqual, "sql")
+				So(s.UDS.Params["driver"], ShouldEqual, "postgres")
+				So(s.UDS.Params["table"], ShouldEqual, "uds")
+			})
+		})
+
+		Convey("When the sql config doesn't have a driver", func() {
+			_, err := NewStorage(toMap(`{"uds":{"type":"sql","params":{"data_source":"dbname=sensorbee"}}}`))
+
+			Convey("Then it shouldn't be valid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When params is missing", func() {
+			for _, typ := range []string{"s3", "redis", "sql"} {
+				Convey(fmt.Sprint("Then it shouldn't be valid for ", typ), func() {
+					_, err := NewStorage(toMap(fmt.Sprintf(`{"uds":{"type":"%v"}}`, typ)))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
+
+func TestTopologyStorage(t *testing.T) {
+	Convey("Given a JSON config for storage.topologies section", t, func() {
+		Convey("When the section is missing", func() {
+			s, err := NewStorage(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then definitions shouldn't be persisted", func() {
+				So(s.Topologies.Type, ShouldEqual, "none")
+				So(s.Topologies.Params, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the type is fs", func() {
+			s, err := NewStorage(toMap(`{"topologies":{"type":"fs","params":{"path":"/path/to/topologies.log"}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(s.Topologies.Type, ShouldEqual, "fs")
+				So(s.Topologies.Params["path"], ShouldEqual, data.String("/path/to/topologies.log"))
+			})
+
+			Convey("Then ToMap should have the section", func() {
+				So(s.ToMap()["topologies"], ShouldResemble, data.Map{
+					"type": data.String("fs"),
+					"params": data.Map{
+						"path": data.String("/path/to/topologies.log"),
+					},
+				})
+			})
+		})
+
+		Convey("When the type is fs without the path", func() {
+			_, err := NewStorage(toMap(`{"topologies":{"type":"fs","params":{}}}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the type is none with params", func() {
+			_, err := NewStorage(toMap(`{"topologies":{"type":"none","params":{"path":"a"}}}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the type is unknown", func() {
+			_, err := NewStorage(toMap(`{"topologies":{"type":"bolt"}}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
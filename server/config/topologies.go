@@ -11,8 +11,13 @@ type Topology struct {
 	// in a config file.
 	Name string `json:"-" yaml:"-"`
 
-	// BQLFile is a file path to the BQL file executed on start up.
+	// BQLFile is a file path to the BQL file executed on start up. Nodes and
+	// states in the file can be declared in any order.
 	BQLFile string `json:"bql_file" yaml:"bql_file"`
+
+	// Config has initial configuration parameters of the topology. Components
+	// in the topology can read them via core.Context.Config.
+	Config data.Map `json:"config" yaml:"config"`
 }
 
 // Topologies is a set of configuration of topologies.
@@ -32,6 +37,9 @@ var (
 						"bql_file": {
 							"type": "string",
 							"minLength": 1
+						},
+						"config": {
+							"type": "object"
 						}
 					},
 					"additionalProperties": false
@@ -75,6 +83,7 @@ func newTopologies(m data.Map) Topologies {
 		t := &Topology{
 			Name:    name,
 			BQLFile: mustAsString(getWithDefault(mustAsMap(conf), "bql_file", data.String(""))),
+			Config:  mustAsMap(getWithDefault(mustAsMap(conf), "config", data.Map{})),
 		}
 		ts[name] = t
 	}
@@ -86,9 +95,13 @@ func (ts *Topologies) ToMap() data.Map {
 	m := data.Map{}
 	for k, v := range *ts {
 		v := v
-		m[k] = data.Map{
+		t := data.Map{
 			"bql_file": data.String(v.BQLFile),
 		}
+		if len(v.Config) > 0 {
+			t["config"] = v.Config.Copy()
+		}
+		m[k] = t
 	}
 	return m
 }
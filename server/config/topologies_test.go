@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"testing"
 )
 
@@ -32,6 +33,27 @@ func TestTopologies(t *testing.T) {
 			})
 		})
 
+		Convey("When the config has initial config parameters", func() {
+			ts, err := NewTopologies(toMap(`{"test":{"config":{"a":1,"b":"c"}}}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(ts["test"].Config, ShouldResemble, data.Map{"a": data.Int(1), "b": data.String("c")})
+			})
+
+			Convey("Then ToMap should have them", func() {
+				So(ts.ToMap()["test"].(data.Map)["config"], ShouldResemble, ts["test"].Config)
+			})
+		})
+
+		Convey("When config isn't an object", func() {
+			_, err := NewTopologies(toMap(`{"test":{"config":1}}`))
+
+			Convey("Then it should be invalid", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
 		Convey("When the config has an undefined field", func() {
 			_, err := NewTopologies(toMap(`{"test":{"bql_path":"/path/to/hoge.bql"}}`))
 
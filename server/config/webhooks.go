@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Webhooks has configuration parameters of webhooks receiving lifecycle
+// events of topologies, such as failures of nodes, as JSON.
+type Webhooks struct {
+	// Timeout is the timeout in seconds of a request sent to a webhook.
+	Timeout float64 `json:"timeout" yaml:"timeout"`
+
+	// Hooks are webhooks receiving events of all topologies on the server.
+	Hooks []*Webhook `json:"hooks" yaml:"hooks"`
+}
+
+// Webhook has parameters of a webhook.
+type Webhook struct {
+	// URL is the http or https URL to which events are POSTed.
+	URL string `json:"url" yaml:"url"`
+
+	// Events are the types of events sent to the webhook. All events are
+	// sent when it's empty.
+	Events []string `json:"events" yaml:"events"`
+
+	// Headers are additional HTTP headers of requests sent to the webhook
+	// such as a token to authenticate the server.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+}
+
+var (
+	webhookSchemaString = `{
+	"type": "object",
+	"properties": {
+		"url": {
+			"type": "string",
+			"pattern": "^https?://"
+		},
+		"events": {
+			"type": "array",
+			"items": {
+				"enum": ["topology_created", "topology_destroyed", "node_failed", "queue_saturated", "checkpoint_completed"]
+			}
+		},
+		"headers": {
+			"type": "object",
+			"additionalProperties": {
+				"type": "string"
+			}
+		}
+	},
+	"required": ["url"],
+	"additionalProperties": false
+}`
+	webhookSchema *gojsonschema.Schema
+
+	webhooksSchemaString = fmt.Sprintf(`{
+	"type": "object",
+	"properties": {
+		"timeout": {
+			"type": "number",
+			"exclusiveMinimum": true,
+			"minimum": 0
+		},
+		"hooks": {
+			"type": "array",
+			"items": %v
+		}
+	},
+	"additionalProperties": false
+}`, webhookSchemaString)
+	webhooksSchema *gojsonschema.Schema
+)
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(webhookSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	webhookSchema = s
+
+	s, err = gojsonschema.NewSchema(gojsonschema.NewStringLoader(webhooksSchemaString))
+	if err != nil {
+		panic(err)
+	}
+	webhooksSchema = s
+}
+
+// NewWebhooks creates a Webhooks config parameters from a given map.
+func NewWebhooks(m data.Map) (*Webhooks, error) {
+	if err := validate(webhooksSchema, m); err != nil {
+		return nil, err
+	}
+	return newWebhooks(m), nil
+}
+
+func newWebhooks(m data.Map) *Webhooks {
+	hs, _ := data.AsArray(getWithDefault(m, "hooks", data.Array{}))
+	hooks := make([]*Webhook, len(hs))
+	for i, h := range hs {
+		hooks[i] = newWebhook(mustAsMap(h))
+	}
+	return &Webhooks{
+		Timeout: mustToFloat(getWithDefault(m, "timeout", data.Float(5))),
+		Hooks:   hooks,
+	}
+}
+
+// NewWebhook creates a Webhook config parameters from a given map. It's
+// also used to validate webhooks registered with the API.
+func NewWebhook(m data.Map) (*Webhook, error) {
+	if err := validate(webhookSchema, m); err != nil {
+		return nil, err
+	}
+	return newWebhook(m), nil
+}
+
+func newWebhook(m data.Map) *Webhook {
+	headers := map[string]string{}
+	for k, v := range mustAsMap(getWithDefault(m, "headers", data.Map{})) {
+		headers[k] = mustAsString(v)
+	}
+	return &Webhook{
+		URL:     mustAsString(m["url"]),
+		Events:  mustAsStringSlice(getWithDefault(m, "events", data.Array{})),
+		Headers: headers,
+	}
+}
+
+// Accepts returns true when the event should be sent to the webhook.
+func (w *Webhook) Accepts(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ToMap returns webhooks config information as data.Map.
+func (w *Webhooks) ToMap() data.Map {
+	hooks := make(data.Array, len(w.Hooks))
+	for i, h := range w.Hooks {
+		hooks[i] = h.ToMap()
+	}
+	return data.Map{
+		"timeout": data.Float(w.Timeout),
+		"hooks":   hooks,
+	}
+}
+
+// ToMap returns webhook config information as data.Map.
+func (w *Webhook) ToMap() data.Map {
+	events := make(data.Array, len(w.Events))
+	for i, e := range w.Events {
+		events[i] = data.String(e)
+	}
+	headers := data.Map{}
+	for k, v := range w.Headers {
+		headers[k] = data.String(v)
+	}
+	return data.Map{
+		"url":     data.String(w.URL),
+		"events":  events,
+		"headers": headers,
+	}
+}
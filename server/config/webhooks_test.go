@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestWebhooks(t *testing.T) {
+	Convey("Given a JSON config for webhooks section", t, func() {
+		Convey("When the config is valid", func() {
+			w, err := NewWebhooks(toMap(`{"timeout":2,"hooks":[
+				{"url":"https://example.com/hook","events":["node_failed"],"headers":{"X-Token":"abc"}},
+				{"url":"http://localhost:8080/"}
+			]}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have given parameters", func() {
+				So(w.Timeout, ShouldEqual, 2)
+				So(w.Hooks, ShouldHaveLength, 2)
+				So(w.Hooks[0].URL, ShouldEqual, "https://example.com/hook")
+				So(w.Hooks[0].Events, ShouldResemble, []string{"node_failed"})
+				So(w.Hooks[0].Headers, ShouldResemble, map[string]string{"X-Token": "abc"})
+				So(w.Hooks[1].Events, ShouldBeEmpty)
+				So(w.Hooks[1].Headers, ShouldBeEmpty)
+			})
+
+			Convey("Then hooks should only accept given events", func() {
+				So(w.Hooks[0].Accepts("node_failed"), ShouldBeTrue)
+				So(w.Hooks[0].Accepts("topology_created"), ShouldBeFalse)
+				So(w.Hooks[1].Accepts("topology_created"), ShouldBeTrue)
+			})
+
+			Convey("Then it should be converted to a map", func() {
+				So(w.ToMap(), ShouldResemble, data.Map{
+					"timeout": data.Float(2),
+					"hooks": data.Array{
+						data.Map{
+							"url":     data.String("https://example.com/hook"),
+							"events":  data.Array{data.String("node_failed")},
+							"headers": data.Map{"X-Token": data.String("abc")},
+						},
+						data.Map{
+							"url":     data.String("http://localhost:8080/"),
+							"events":  data.Array{},
+							"headers": data.Map{},
+						},
+					},
+				})
+			})
+		})
+
+		Convey("When the config is empty", func() {
+			w, err := NewWebhooks(toMap(`{}`))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have default values", func() {
+				So(w.Timeout, ShouldEqual, 5)
+				So(w.Hooks, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When validating invalid values", func() {
+			for _, c := range []string{
+				`{"timeout":0}`,
+				`{"hooks":{}}`,
+				`{"hooks":[{}]}`,
+				`{"hooks":[{"url":"ftp://example.com/"}]}`,
+				`{"hooks":[{"url":"http://example.com/","events":["unknown"]}]}`,
+				`{"hooks":[{"url":"http://example.com/","headers":{"X-Token":1}}]}`,
+				`{"hooks":[{"url":"http://example.com/","unknown":1}]}`,
+				`{"unknown":1}`,
+			} {
+				Convey("Then it should fail: "+c, func() {
+					_, err := NewWebhooks(toMap(c))
+					So(err, ShouldNotBeNil)
+				})
+			}
+		})
+	})
+}
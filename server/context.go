@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/gocraft/web"
@@ -13,7 +14,9 @@ import (
 	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
 	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/cluster"
 	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+	"gopkg.in/sensorbee/sensorbee.v0/server/replication"
 	"gopkg.in/sensorbee/sensorbee.v0/server/udsstorage"
 )
 
@@ -24,6 +27,40 @@ type Context struct {
 	udsStorage udf.UDSStorage
 	topologies TopologyRegistry
 	config     *config.Config
+
+	// coordinator is non-nil when the server runs as a coordinator of a
+	// cluster.
+	coordinator   *cluster.Coordinator
+	clusterClient *cluster.Client
+
+	// recorder records definitions of topologies when the server is a
+	// primary or a standby server of replication. standby is non-nil only
+	// when the server is a standby server.
+	recorder *replication.Recorder
+	standby  *replication.Standby
+
+	// hibernator is non-nil when hibernation of idle topologies is enabled.
+	// It's also set to topologies.
+	hibernator *hibernator
+
+	// resumer is non-nil when streaming SELECT statements can be resumed
+	// after their clients reconnect.
+	resumer *selectResumer
+
+	// idempotency is non-nil when responses to requests having idempotency
+	// keys are kept.
+	idempotency *idempotencyStore
+
+	// authenticator is nil when requests aren't authenticated.
+	authenticator Authenticator
+
+	// role is the role of the request given by authenticator. It's empty
+	// when the request isn't authenticated.
+	role Role
+
+	// hooks sends lifecycle events of topologies to webhooks.
+	hooks *webhookDispatcher
+
 	// logger is used by core.Context, not for the server's Context. This logger
 	// can be shared with jasco.Context.
 	logger *logrus.Logger
@@ -50,13 +87,38 @@ type ContextGlobalVariables struct {
 
 	// Config has configuration parameters.
 	Config *config.Config
+
+	// Coordinator manages workers and placement of topologies when the
+	// server runs as a coordinator of a cluster. It's nil otherwise.
+	Coordinator *cluster.Coordinator
+
+	// Recorder records definitions of topologies created via the API so that
+	// standby servers can replicate them and hibernated topologies can be
+	// restored. It also persists them when the topology storage is
+	// configured. It's nil when none of them is enabled.
+	Recorder *replication.Recorder
+
+	// Standby fetches snapshots from the primary server when the server runs
+	// as a standby server. It's nil otherwise. Its Restore field is set by
+	// SetUpContextAndRouter.
+	Standby *replication.Standby
+
+	// Authenticator authenticates requests to the API. It's created from the
+	// auth section of the config and the caller can replace it with a custom
+	// one. Requests aren't authenticated when it's nil.
+	Authenticator Authenticator
+
+	// webhooks is set by SetUpContextAndRouter so that topologies restored
+	// by it can send events to webhooks.
+	webhooks *webhookDispatcher
 }
 
 // SetUpContextGlobalVariables create a new ContextGlobalVariables from a config.
 // DO NOT make any change on the config after calling this function. The caller
 // can change other members of ContextGlobalVariables.
 //
-// The caller must Close LogDestination.
+// The caller must Close LogDestination. The caller must also Close Recorder
+// when it isn't nil.
 func SetUpContextGlobalVariables(conf *config.Config) (*ContextGlobalVariables, error) {
 	logger := logrus.New()
 	logLevel, err := logrus.ParseLevel(conf.Logging.MinLogLevel)
@@ -75,6 +137,51 @@ func SetUpContextGlobalVariables(conf *config.Config) (*ContextGlobalVariables,
 		}
 	}()
 	logger.Out = w
+	applyRuntimeConfig(conf.Runtime, logger)
+
+	var coordinator *cluster.Coordinator
+	if conf.Cluster != nil && conf.Cluster.Role == "coordinator" {
+		coordinator = cluster.NewCoordinator(time.Duration(conf.Cluster.WorkerTimeout * float64(time.Second)))
+	}
+
+	var (
+		recorder *replication.Recorder
+		standby  *replication.Standby
+	)
+	if err := validateHibernationConfig(conf); err != nil {
+		return nil, err
+	}
+	if err := validateAuthConfig(conf); err != nil {
+		return nil, err
+	}
+	if s := conf.Storage.Topologies; s.Type == "fs" {
+		path, _ := data.AsString(s.Params["path"])
+		r, err := replication.OpenRecorder(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open the topology storage: %v", err)
+		}
+		recorder = r
+	}
+	if h := conf.Hibernation; h != nil && h.IdleTimeout > 0 && recorder == nil {
+		// Hibernated topologies are restored from their definitions.
+		recorder = replication.NewRecorder()
+	}
+	if r := conf.Replication; r != nil && r.Role != "none" {
+		// A standby server also records topologies because it'll be the
+		// primary server after it's promoted.
+		if recorder == nil {
+			recorder = replication.NewRecorder()
+		}
+		if r.Role == "standby" {
+			standby = &replication.Standby{
+				Primary:         r.Primary,
+				Interval:        time.Duration(r.SyncInterval * float64(time.Second)),
+				FailoverTimeout: time.Duration(r.FailoverTimeout * float64(time.Second)),
+				Logger:          logger,
+				HTTPClient:      newInternalHTTPClient(conf.Auth, 0),
+			}
+		}
+	}
 
 	closeWriter = false
 	return &ContextGlobalVariables{
@@ -82,6 +189,10 @@ func SetUpContextGlobalVariables(conf *config.Config) (*ContextGlobalVariables,
 		LogDestination: w,
 		Topologies:     NewDefaultTopologyRegistry(),
 		Config:         conf,
+		Coordinator:    coordinator,
+		Recorder:       recorder,
+		Standby:        standby,
+		Authenticator:  NewStaticAuthenticator(conf.Auth),
 	}, nil
 }
 
@@ -96,24 +207,87 @@ func SetUpContextAndRouter(prefix string, jascoRoot *web.Router, gvariables *Con
 	if err != nil {
 		return nil, err
 	}
+	gvars.webhooks = newWebhookDispatcher(gvars.Logger, gvars.Config.Webhooks)
+
+	var h *hibernator
+	if hc := gvars.Config.Hibernation; hc != nil && hc.IdleTimeout > 0 {
+		h = newHibernator(gvars.Topologies, gvars.Logger, gvars.Config, udsStorage, gvars.Recorder, gvars.webhooks)
+		h.loadHibernated()
+		gvars.Topologies = h
+		go h.run()
+	}
+
+	var resumer *selectResumer
+	if rc := gvars.Config.SelectResume; rc != nil && rc.GracePeriod > 0 {
+		resumer = newSelectResumer(gvars.Logger, rc)
+	}
+
+	var idempotency *idempotencyStore
+	if ic := gvars.Config.Idempotency; ic != nil && ic.Retention > 0 {
+		idempotency = newIdempotencyStore(ic)
+	}
 
 	// Topologies should be created after setting up everything necessary for it.
-	if err := setUpTopologies(gvars.Logger, gvars.Topologies, gvars.Config, udsStorage); err != nil {
+	if err := setUpTopologies(gvars.Logger, gvars.Topologies, gvars.Config, udsStorage, gvars.webhooks); err != nil {
 		return nil, err
 	}
 
+	if gvars.Standby == nil && gvars.Config.Storage.Topologies.Type != "none" {
+		// A standby server restores topologies from the primary server
+		// when it's promoted.
+		restoreRecordedTopologies(&gvars, udsStorage)
+	}
+
+	var clusterClient *cluster.Client
+	if gvars.Coordinator != nil {
+		clusterClient = &cluster.Client{
+			HTTPClient: newInternalHTTPClient(gvars.Config.Auth,
+				time.Duration(gvars.Config.Cluster.WorkerTimeout*float64(time.Second))),
+		}
+	}
+
+	if gvars.Standby != nil {
+		gvars.Standby.Restore = func(s *replication.Snapshot) error {
+			return restoreSnapshot(&gvars, udsStorage, s)
+		}
+	}
+
 	router := jascoRoot.Subrouter(Context{}, "/")
 	router.Middleware(func(c *Context, rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
 		c.logger = gvars.Logger
 		c.udsStorage = udsStorage
 		c.topologies = gvars.Topologies
 		c.config = gvars.Config
+		c.coordinator = gvars.Coordinator
+		c.clusterClient = clusterClient
+		c.recorder = gvars.Recorder
+		c.standby = gvars.Standby
+		c.hibernator = h
+		c.resumer = resumer
+		c.idempotency = idempotency
+		c.authenticator = gvars.Authenticator
+		c.hooks = gvars.webhooks
 		next(rw, req)
 	})
 	return router, nil
 }
 
 func setUpUDSStorage(conf *config.UDSStorage) (udf.UDSStorage, error) {
+	s, err := newUDSStorage(conf)
+	if err != nil {
+		return nil, err
+	}
+	if e := conf.Encryption; e != nil {
+		key, err := udsstorage.ReadEncryptionKey(e.KeyFile, e.KeyEnv)
+		if err != nil {
+			return nil, err
+		}
+		return udsstorage.NewEncrypted(s, key)
+	}
+	return s, nil
+}
+
+func newUDSStorage(conf *config.UDSStorage) (udf.UDSStorage, error) {
 	// Parameters are already validated in conf
 	switch conf.Type {
 	case "in_memory":
@@ -125,12 +299,50 @@ func setUpUDSStorage(conf *config.UDSStorage) (udf.UDSStorage, error) {
 			tempDir, _ = data.AsString(v)
 		}
 		return udsstorage.NewFS(dir, tempDir)
+	case "s3":
+		return udsstorage.NewS3(&udsstorage.S3Config{
+			Bucket:          udsStorageParam(conf, "bucket"),
+			Region:          udsStorageParam(conf, "region"),
+			Endpoint:        udsStorageParam(conf, "endpoint"),
+			Prefix:          udsStorageParam(conf, "prefix"),
+			AccessKeyID:     udsStorageParam(conf, "access_key_id"),
+			SecretAccessKey: udsStorageParam(conf, "secret_access_key"),
+		})
+	case "redis":
+		var db int64
+		if v, ok := conf.Params["db"]; ok {
+			db, _ = data.AsInt(v)
+		}
+		return udsstorage.NewRedis(&udsstorage.RedisConfig{
+			Address:  udsStorageParam(conf, "address"),
+			Password: udsStorageParam(conf, "password"),
+			DB:       int(db),
+			Prefix:   udsStorageParam(conf, "prefix"),
+		})
+	case "sql":
+		return udsstorage.NewSQL(&udsstorage.SQLConfig{
+			Driver:     udsStorageParam(conf, "driver"),
+			DataSource: udsStorageParam(conf, "data_source"),
+			Table:      udsStorageParam(conf, "table"),
+		})
 	default:
 		return nil, fmt.Errorf("unsupported uds storage type: %v", conf.Type)
 	}
 }
 
-func setUpTopologies(logger *logrus.Logger, r TopologyRegistry, conf *config.Config, us udf.UDSStorage) error {
+// udsStorageParam returns a string parameter of the storage. It returns an
+// empty string when the parameter is missing.
+func udsStorageParam(conf *config.UDSStorage, name string) string {
+	v, ok := conf.Params[name]
+	if !ok {
+		return ""
+	}
+	s, _ := data.AsString(v)
+	return s
+}
+
+func setUpTopologies(logger *logrus.Logger, r TopologyRegistry, conf *config.Config, us udf.UDSStorage,
+	wh *webhookDispatcher) error {
 	stopAll := true
 	defer func() {
 		if stopAll {
@@ -153,7 +365,7 @@ func setUpTopologies(logger *logrus.Logger, r TopologyRegistry, conf *config.Con
 
 	for name := range conf.Topologies {
 		logger.WithField("topology", name).Info("Setting up the topology")
-		tb, err := setUpTopology(logger, name, conf, us)
+		tb, err := setUpTopology(logger, name, conf, us, wh)
 		if err != nil {
 			return err
 		}
@@ -170,27 +382,17 @@ func setUpTopologies(logger *logrus.Logger, r TopologyRegistry, conf *config.Con
 	return nil
 }
 
-func setUpTopology(logger *logrus.Logger, name string, conf *config.Config, us udf.UDSStorage) (*bql.TopologyBuilder, error) {
-	cc := &core.ContextConfig{
-		Logger: logger,
-	}
-	cc.Flags.DroppedTupleLog.Set(conf.Logging.LogDroppedTuples)
-	cc.Flags.DestinationlessTupleLog.Set(conf.Logging.LogDestinationlessTuples)
-	cc.Flags.DroppedTupleSummarization.Set(conf.Logging.SummarizeDroppedTuples)
-
-	tp, err := core.NewDefaultTopology(core.NewContext(cc), name)
-	if err != nil {
-		return nil, err
+func setUpTopology(logger *logrus.Logger, name string, conf *config.Config, us udf.UDSStorage,
+	wh *webhookDispatcher) (*bql.TopologyBuilder, error) {
+	var tconf data.Map
+	if t, ok := conf.Topologies[name]; ok {
+		tconf = t.Config
 	}
-	tb, err := bql.NewTopologyBuilder(tp)
+	tb, err := newTopologyBuilder(logger, name, tconf, conf, us, wh)
 	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"err":      err,
-			"topology": name,
-		}).Error("Cannot create a topology builder")
 		return nil, err
 	}
-	tb.UDSStorage = us
+	tp := tb.Topology()
 
 	bqlFilePath := conf.Topologies[name].BQLFile
 	if bqlFilePath == "" {
@@ -226,7 +428,8 @@ func setUpTopology(logger *logrus.Logger, name string, conf *config.Config, us u
 		return nil, err
 	}
 
-	for _, stmt := range stmts {
+	// Statements in the file can be declared in any order.
+	for _, stmt := range parser.SortStmts(stmts) {
 		if _, err := tb.AddStmt(stmt); err != nil {
 			logger.WithFields(logrus.Fields{
 				"err":      err,
@@ -237,6 +440,126 @@ func setUpTopology(logger *logrus.Logger, name string, conf *config.Config, us u
 		}
 	}
 
+	if conf.Checkpoint != nil && conf.Checkpoint.Enabled && conf.Checkpoint.RestoreOnStartup {
+		info, err := tp.RestoreCheckpoint()
+		if err != nil {
+			// The topology can still run without the checkpoint.
+			logger.WithFields(logrus.Fields{
+				"err":      err,
+				"topology": name,
+			}).Error("Cannot restore the topology from the checkpoint")
+		} else if info != nil {
+			logger.WithFields(logrus.Fields{
+				"topology":   name,
+				"checkpoint": info["id"],
+			}).Info("Restored the topology from the checkpoint")
+		}
+	}
+
 	shouldStop = false
 	return tb, nil
 }
+
+// restoreRecordedTopologies creates topologies persisted by the recorder
+// and issues their statements again. A topology having the same name as one
+// in the config file isn't restored. Errors are only logged so that the
+// server can start with other topologies.
+func restoreRecordedTopologies(gvars *ContextGlobalVariables, us udf.UDSStorage) {
+	for _, name := range gvars.Recorder.Names() {
+		l := gvars.Logger.WithField("topology", name)
+		if h, ok := gvars.Topologies.(*hibernator); ok && h.isHibernated(name) {
+			// It's restored when it's looked up.
+			continue
+		}
+		if _, err := gvars.Topologies.Lookup(name); err == nil {
+			l.Warn("The persisted topology is already defined in the config file")
+			continue
+		}
+		tconf, stmts, ok := gvars.Recorder.Definition(name)
+		if !ok {
+			continue
+		}
+		l.Info("Restoring the persisted topology")
+
+		tb, err := newTopologyBuilder(gvars.Logger, name, tconf, gvars.Config, us, gvars.webhooks)
+		if err != nil {
+			l.WithField("err", err).Error("Cannot create the persisted topology")
+			continue
+		}
+		if err := gvars.Topologies.Register(name, tb); err != nil {
+			l.WithField("err", err).Error("Cannot register the persisted topology")
+			if err := tb.Topology().Stop(); err != nil {
+				l.WithField("err", err).Error("Cannot stop the topology")
+			}
+			continue
+		}
+		if err := replication.RestoreTopology(tb, &replication.TopologySnapshot{
+			Name:       name,
+			Config:     tconf,
+			Statements: stmts,
+		}); err != nil {
+			l.WithField("err", err).Error("Cannot restore the persisted topology completely")
+		}
+
+		if c := gvars.Config.Checkpoint; c != nil && c.Enabled && c.RestoreOnStartup {
+			info, err := tb.Topology().RestoreCheckpoint()
+			if err != nil {
+				l.WithField("err", err).Error("Cannot restore the topology from the checkpoint")
+			} else if info != nil {
+				l.WithField("checkpoint", info["id"]).Info("Restored the topology from the checkpoint")
+			}
+		}
+	}
+}
+
+// newTopologyBuilder creates an empty topology having the given config and
+// its builder. Lifecycle events of the topology are sent to wh when it isn't
+// nil.
+func newTopologyBuilder(logger *logrus.Logger, name string, tconf data.Map, conf *config.Config, us udf.UDSStorage,
+	wh *webhookDispatcher) (*bql.TopologyBuilder, error) {
+	cc := &core.ContextConfig{
+		Logger:            logger,
+		Config:            conf.BQL.TopologyConfig(tconf),
+		Checkpoint:        checkpointConfig(conf, us),
+		LifecycleListener: wh.listener(),
+	}
+	cc.Flags.DroppedTupleLog.Set(conf.Logging.LogDroppedTuples)
+	cc.Flags.DestinationlessTupleLog.Set(conf.Logging.LogDestinationlessTuples)
+	cc.Flags.DroppedTupleSummarization.Set(conf.Logging.SummarizeDroppedTuples)
+
+	tp, err := core.NewDefaultTopology(core.NewContext(cc), name)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := bql.NewTopologyBuilder(tp)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"err":      err,
+			"topology": name,
+		}).Error("Cannot create a topology builder")
+		if err := tp.Stop(); err != nil {
+			logger.WithFields(logrus.Fields{
+				"err":      err,
+				"topology": name,
+			}).Error("Cannot stop the topology")
+		}
+		return nil, err
+	}
+	tb.UDSStorage = us
+	if conf.BQL != nil {
+		tb.ParamEnvAllowlist = conf.BQL.EnvAllowlist
+	}
+	return tb, nil
+}
+
+// checkpointConfig returns the config of checkpoints of topologies stored in
+// the UDS storage. It returns nil when checkpoints are disabled.
+func checkpointConfig(conf *config.Config, us udf.UDSStorage) *core.CheckpointConfig {
+	if conf.Checkpoint == nil || !conf.Checkpoint.Enabled || us == nil {
+		return nil
+	}
+	return &core.CheckpointConfig{
+		Storage:  udf.NewCheckpointStorage(us),
+		Interval: time.Duration(conf.Checkpoint.Interval * float64(time.Second)),
+	}
+}
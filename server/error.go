@@ -1,28 +1,78 @@
 package server
 
+import (
+	"gopkg.in/sensorbee/sensorbee.v0/server/response"
+)
+
+// Error codes of the server. See response.ErrorCodes for the catalog of them
+// including HTTP status codes returned with them.
 const (
 	// requestResourceNotFoundErrorCode means that the request URI was
 	// correct but the requested resource was not found.
-	requestResourceNotFoundErrorCode = "E0001"
+	requestResourceNotFoundErrorCode = response.ResourceNotFoundErrorCode
 
 	// formValidationErrorCode means that validation of request body failed.
 	// When this error happens, Error.Meta should have detailed error messages
 	// for each field. Each field must have a slice of strings so that clients
 	// can always write error handling codes assuming that they're arrays.
-	formValidationErrorCode = "E0005"
+	formValidationErrorCode = response.FormValidationErrorCode
 
 	// bqlStmtParseErrorCode is returned when a statement cannot be parsed.
 	// When this error happens, Error.Meta should have parse error messages
 	// in Meta["parse_errors"] as an array of strings and the statement which
 	// couldn't be parsed in Meta["statement"].
-	bqlStmtParseErrorCode = "E0006"
+	bqlStmtParseErrorCode = response.BQLStmtParseErrorCode
 
 	// bqlStmtProcessingErrorCode is returned when a statement cannot be
 	// processed successfully. When this error happens, Error.Meta should have
 	// an error message in Meta["error"] and statement in Meta["statement"].
-	bqlStmtProcessingErrorCode = "E0007"
+	bqlStmtProcessingErrorCode = response.BQLStmtProcessingErrorCode
 
 	// nonWebSocketRequestErrorCode is returned when a requested action only
 	// supports WebSocket and a request is a regular HTTP request.
-	nonWebSocketRequestErrorCode = "E0008"
+	nonWebSocketRequestErrorCode = response.NonWebSocketRequestErrorCode
+
+	// workerUnavailableErrorCode is returned by a coordinator of a cluster
+	// when no worker can run a new topology or the worker running the
+	// requested topology is down.
+	workerUnavailableErrorCode = response.WorkerUnavailableErrorCode
+
+	// promotionErrorCode is returned when a standby server of replication
+	// cannot be promoted.
+	promotionErrorCode = response.PromotionErrorCode
+
+	// protectedNodeErrorCode is returned when a statement drops or alters a
+	// node or a state protected by another owner. Error.Meta has the same
+	// fields as bqlStmtProcessingErrorCode.
+	protectedNodeErrorCode = response.ProtectedNodeErrorCode
+
+	// tooManyStatementsErrorCode is returned when a statement isn't admitted
+	// because the topology is already running the maximum number of
+	// statements. Error.Meta has the same fields as
+	// bqlStmtProcessingErrorCode.
+	tooManyStatementsErrorCode = response.TooManyStatementsErrorCode
+
+	// checkpointDisabledErrorCode is returned when a checkpoint of a
+	// topology is requested but checkpoints aren't enabled in the config.
+	checkpointDisabledErrorCode = response.CheckpointDisabledErrorCode
+
+	// authenticationRequiredErrorCode is returned when authentication is
+	// enabled and a request doesn't have credentials.
+	authenticationRequiredErrorCode = response.AuthenticationRequiredErrorCode
+
+	// invalidCredentialsErrorCode is returned when credentials in a request
+	// aren't accepted.
+	invalidCredentialsErrorCode = response.InvalidCredentialsErrorCode
+
+	// resourceConflictErrorCode is returned when a request conflicts with
+	// the current state of the resource.
+	resourceConflictErrorCode = response.ResourceConflictErrorCode
+
+	// idempotencyKeyReusedErrorCode is returned when the idempotency key of
+	// a request was already used for a request having a different body.
+	idempotencyKeyReusedErrorCode = response.IdempotencyKeyReusedErrorCode
+
+	// permissionDeniedErrorCode is returned when the role of a request isn't
+	// allowed to perform the action. Error.Meta has "role".
+	permissionDeniedErrorCode = response.PermissionDeniedErrorCode
 )
@@ -0,0 +1,536 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+	"gopkg.in/sensorbee/sensorbee.v0/server/replication"
+)
+
+// hibernator is a TopologyRegistry which hibernates idle topologies. A
+// topology is idle when all of its sources are paused, no request to the
+// topology including a WebSocket connection is being processed, and no
+// request has arrived for the idle timeout. A hibernated topology is
+// stopped and unregistered from the underlying registry, and only its
+// definition and checkpoints of its states are kept. It's restored when it's
+// looked up, e.g. when a statement is issued to it.
+//
+// Checkpoints are saved to the UDS storage so that a hibernated topology can
+// be restored with its states after the server restarts. They're saved as a
+// single entry whose state name and tag are both "hibernation", which doesn't
+// conflict with UDSs saved by users because they cannot have the tag.
+//
+// Only topologies created via the API can be hibernated because they have to
+// be restored from statements recorded by replication.Recorder. Topologies
+// having protected nodes or states which cannot be saved aren't hibernated
+// either since they cannot be restored as they are.
+//
+// List doesn't return hibernated topologies. Hibernated returns their names.
+type hibernator struct {
+	TopologyRegistry
+
+	logger      *logrus.Logger
+	config      *config.Config
+	udsStorage  udf.UDSStorage
+	recorder    *replication.Recorder
+	webhooks    *webhookDispatcher
+	idleTimeout time.Duration
+
+	// m protects following fields. It isn't held while checkpoints are saved
+	// or loaded and while a topology is stopped or rebuilt, so that a slow
+	// topology doesn't block requests to others. A topology is marked as
+	// hibernating instead, and the mark is cleared when it's used in the
+	// meantime. Goroutines looking up a topology being restored wait for it.
+	m          sync.Mutex
+	activities map[string]*topologyActivity
+	hibernated map[string]*hibernatedTopology
+}
+
+type topologyActivity struct {
+	// clients is the number of requests being processed.
+	clients  int
+	lastUsed time.Time
+
+	// hibernating is set when the topology starts to be hibernated. It's
+	// cleared when the topology is used, and the hibernation is cancelled
+	// then.
+	hibernating bool
+}
+
+// use records that the topology is used now.
+func (a *topologyActivity) use() {
+	a.lastUsed = time.Now()
+	a.hibernating = false
+}
+
+type hibernatedTopology struct {
+	name       string
+	config     data.Map
+	statements []string
+
+	// waking is closed when the topology is restored or fails to be
+	// restored. It's nil when nobody is restoring the topology.
+	waking chan struct{}
+
+	// stopped is closed when the hibernated topology is stopped. It's nil
+	// when the topology was hibernated before the server restarted.
+	stopped chan struct{}
+}
+
+const (
+	// hibernationEntryName is the name and the tag of the entry of the UDS
+	// storage having checkpoints of a hibernated topology.
+	hibernationEntryName = "hibernation"
+)
+
+// hibernationEntry is the entry of the UDS storage having checkpoints of a
+// hibernated topology. Because UDSStorage cannot remove an entry, the entry
+// having Hibernated false is written when the topology is restored.
+type hibernationEntry struct {
+	Hibernated bool                           `json:"hibernated"`
+	States     []*replication.StateCheckpoint `json:"states,omitempty"`
+}
+
+func newHibernator(r TopologyRegistry, logger *logrus.Logger, conf *config.Config, us udf.UDSStorage,
+	recorder *replication.Recorder, wh *webhookDispatcher) *hibernator {
+	return &hibernator{
+		TopologyRegistry: r,
+		logger:           logger,
+		config:           conf,
+		udsStorage:       us,
+		recorder:         recorder,
+		webhooks:         wh,
+		idleTimeout:      time.Duration(conf.Hibernation.IdleTimeout * float64(time.Second)),
+		activities:       map[string]*topologyActivity{},
+		hibernated:       map[string]*hibernatedTopology{},
+	}
+}
+
+// validateHibernationConfig returns an error when hibernation is enabled
+// with features which don't support it.
+func validateHibernationConfig(conf *config.Config) error {
+	if conf.Hibernation == nil || conf.Hibernation.IdleTimeout == 0 {
+		return nil
+	}
+	if conf.Replication != nil && conf.Replication.Role != "none" {
+		return fmt.Errorf("hibernation cannot be enabled with replication")
+	}
+	if conf.Cluster != nil && conf.Cluster.Role == "worker" {
+		return fmt.Errorf("hibernation cannot be enabled on a worker of a cluster")
+	}
+	return nil
+}
+
+// loadHibernated marks recorded topologies which were hibernated before the
+// server restarted as hibernated. They're restored from the UDS storage when
+// they're looked up. It must be called before recorded topologies are
+// restored.
+func (h *hibernator) loadHibernated() {
+	h.m.Lock()
+	defer h.m.Unlock()
+	for _, name := range h.recorder.Names() {
+		l := h.logger.WithField("topology", name)
+		e, err := h.loadEntry(name)
+		if err != nil {
+			l.WithField("err", err).Error("Cannot load checkpoints of the hibernated topology")
+			continue
+		}
+		if !e.Hibernated {
+			continue
+		}
+		conf, stmts, ok := h.recorder.Definition(name)
+		if !ok {
+			continue
+		}
+		h.hibernated[strings.ToLower(name)] = &hibernatedTopology{
+			name:       name,
+			config:     conf,
+			statements: stmts,
+		}
+		l.Info("The persisted topology is hibernated")
+	}
+}
+
+// isHibernated returns true when the topology is hibernated.
+func (h *hibernator) isHibernated(name string) bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	_, ok := h.hibernated[strings.ToLower(name)]
+	return ok
+}
+
+// saveEntry writes the entry of the topology to the UDS storage.
+func (h *hibernator) saveEntry(name string, e *hibernationEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	w, err := h.udsStorage.Save(name, hibernationEntryName, hibernationEntryName)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		w.Abort()
+		return err
+	}
+	return w.Commit()
+}
+
+// loadEntry reads the entry of the topology from the UDS storage. It returns
+// an entry having Hibernated false when the topology has never been
+// hibernated.
+func (h *hibernator) loadEntry(name string) (*hibernationEntry, error) {
+	r, err := h.udsStorage.Load(name, hibernationEntryName, hibernationEntryName)
+	if err != nil {
+		if core.IsNotExist(err) {
+			return &hibernationEntry{}, nil
+		}
+		return nil, err
+	}
+	defer r.Close()
+	e := &hibernationEntry{}
+	if err := json.NewDecoder(r).Decode(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// run periodically hibernates idle topologies. It never returns.
+func (h *hibernator) run() {
+	interval := h.idleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for now := range t.C {
+		h.hibernateIdle(now)
+	}
+}
+
+func (h *hibernator) activity(name string) *topologyActivity {
+	n := strings.ToLower(name)
+	a, ok := h.activities[n]
+	if !ok {
+		a = &topologyActivity{
+			lastUsed: time.Now(),
+		}
+		h.activities[n] = a
+	}
+	return a
+}
+
+// begin marks the topology as being used by a request. The caller must call
+// end when the request is processed.
+func (h *hibernator) begin(name string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	a := h.activity(name)
+	a.clients++
+	a.use()
+}
+
+func (h *hibernator) end(name string) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	a := h.activity(name)
+	a.clients--
+	a.use()
+}
+
+func (h *hibernator) Register(name string, tb *bql.TopologyBuilder) error {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if _, ok := h.hibernated[strings.ToLower(name)]; ok {
+		return os.ErrExist
+	}
+	if err := h.TopologyRegistry.Register(name, tb); err != nil {
+		return err
+	}
+	h.activity(name).use()
+	return nil
+}
+
+// Lookup returns the topology. It restores the topology when it's
+// hibernated.
+func (h *hibernator) Lookup(name string) (*bql.TopologyBuilder, error) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if err := h.wake(name); err != nil {
+		return nil, err
+	}
+	tb, err := h.TopologyRegistry.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	h.activity(name).use()
+	return tb, nil
+}
+
+// Unregister removes the topology. A hibernated topology is restored before
+// it's removed so that the caller can stop it in the same way as others.
+func (h *hibernator) Unregister(name string) (*bql.TopologyBuilder, error) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if err := h.wake(name); err != nil {
+		return nil, err
+	}
+	tb, err := h.TopologyRegistry.Unregister(name)
+	if err != nil {
+		return nil, err
+	}
+	delete(h.activities, strings.ToLower(name))
+	return tb, nil
+}
+
+// Hibernated returns names of hibernated topologies in alphabetical order.
+func (h *hibernator) Hibernated() []string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	names := make([]string, 0, len(h.hibernated))
+	for _, ht := range h.hibernated {
+		names = append(names, ht.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hibernateIdle hibernates topologies which have been idle since
+// now - idleTimeout.
+func (h *hibernator) hibernateIdle(now time.Time) {
+	ts, err := h.TopologyRegistry.List()
+	if err != nil {
+		h.logger.WithField("err", err).Error("Cannot list topologies to be hibernated")
+		return
+	}
+
+	for _, tb := range ts {
+		name := tb.Topology().Name()
+		a, ok := h.markHibernating(name, now)
+		if !ok || !h.canHibernate(tb) {
+			continue
+		}
+		if err := h.hibernate(tb, a); err != nil {
+			h.logger.WithFields(logrus.Fields{
+				"topology": name,
+				"err":      err,
+			}).Error("Cannot hibernate the topology")
+		}
+	}
+}
+
+// markHibernating marks the topology as hibernating and returns its activity
+// when it has been idle since now - idleTimeout.
+func (h *hibernator) markHibernating(name string, now time.Time) (*topologyActivity, bool) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	a := h.activity(name)
+	if a.clients > 0 || now.Sub(a.lastUsed) < h.idleTimeout {
+		return nil, false
+	}
+	a.hibernating = true
+	return a, true
+}
+
+// canHibernate returns true when the topology can be restored after it's
+// hibernated and none of its sources is running.
+func (h *hibernator) canHibernate(tb *bql.TopologyBuilder) bool {
+	t := tb.Topology()
+	if _, _, ok := h.recorder.Definition(t.Name()); !ok {
+		return false
+	}
+	if tb.HasProtected() {
+		return false
+	}
+	for _, s := range t.Sources() {
+		if s.State().Get() != core.TSPaused {
+			return false
+		}
+	}
+	states, err := t.Context().SharedStates.List()
+	if err != nil {
+		return false
+	}
+	for _, s := range states {
+		if _, ok := s.(core.SavableSharedState); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hibernate checkpoints states of the topology marked by markHibernating
+// and stops it. The caller must not hold h.m. The topology isn't hibernated
+// when it's used, removed, or its source is resumed while its checkpoints
+// are being saved.
+func (h *hibernator) hibernate(tb *bql.TopologyBuilder, a *topologyActivity) error {
+	t := tb.Topology()
+	name := t.Name()
+	n := strings.ToLower(name)
+	l := h.logger.WithField("topology", name)
+	conf, stmts, ok := h.recorder.Definition(name)
+	if !ok {
+		return fmt.Errorf("the definition of the topology isn't recorded")
+	}
+	states, err := replication.CheckpointStates(t.Context())
+	if err != nil {
+		return err
+	}
+	if err := h.saveEntry(name, &hibernationEntry{
+		Hibernated: true,
+		States:     states,
+	}); err != nil {
+		return fmt.Errorf("cannot save checkpoints: %v", err)
+	}
+
+	h.m.Lock()
+	if h.activities[n] != a || !a.hibernating || a.clients > 0 || !h.canHibernate(tb) {
+		h.m.Unlock()
+		h.discardEntry(name)
+		l.Info("The topology was used while it was being hibernated")
+		return nil
+	}
+	if _, err := h.TopologyRegistry.Unregister(name); err != nil {
+		h.m.Unlock()
+		h.discardEntry(name)
+		return err
+	}
+	stopped := make(chan struct{})
+	h.hibernated[n] = &hibernatedTopology{
+		name:       name,
+		config:     conf,
+		statements: stmts,
+		stopped:    stopped,
+	}
+	h.m.Unlock()
+
+	if err := t.Stop(); err != nil {
+		l.WithField("err", err).Error("Cannot stop the hibernated topology")
+	}
+	close(stopped)
+	l.Info("Hibernated the idle topology")
+	return nil
+}
+
+// discardEntry writes an empty entry of the topology so that it isn't
+// restored from checkpoints saved by hibernate.
+func (h *hibernator) discardEntry(name string) {
+	if err := h.saveEntry(name, &hibernationEntry{}); err != nil {
+		h.logger.WithFields(logrus.Fields{
+			"topology": name,
+			"err":      err,
+		}).Error("Cannot discard checkpoints of the topology")
+	}
+}
+
+// wake restores the topology and registers it when it's hibernated. The
+// caller must hold h.m. wake releases h.m while the topology is being
+// rebuilt, and other goroutines waking the same topology wait for it.
+func (h *hibernator) wake(name string) error {
+	n := strings.ToLower(name)
+	for {
+		ht, ok := h.hibernated[n]
+		if !ok {
+			return nil
+		}
+		if ht.waking == nil {
+			break
+		}
+		waking := ht.waking
+		h.m.Unlock()
+		<-waking
+		h.m.Lock()
+	}
+
+	ht := h.hibernated[n]
+	ht.waking = make(chan struct{})
+	h.m.Unlock()
+	tb, err := h.rebuild(ht)
+	h.m.Lock()
+	close(ht.waking)
+	ht.waking = nil
+	if err != nil {
+		return err
+	}
+
+	l := h.logger.WithField("topology", ht.name)
+	if err := h.TopologyRegistry.Register(ht.name, tb); err != nil {
+		if err := tb.Topology().Stop(); err != nil {
+			l.WithField("err", err).Error("Cannot stop the topology")
+		}
+		return fmt.Errorf("cannot register the hibernated topology: %v", err)
+	}
+	delete(h.hibernated, n)
+	h.activity(ht.name).use()
+	l.Info("Restored the hibernated topology")
+	return nil
+}
+
+// rebuild creates the hibernated topology again from its definition and
+// checkpoints saved in the UDS storage. Sources are created as paused ones
+// because all of them were paused when the topology was hibernated. The
+// checkpoints are discarded once they're loaded so that they won't be
+// loaded again after the server restarts. It waits for the hibernated
+// topology to be stopped first. The caller must not hold h.m.
+func (h *hibernator) rebuild(ht *hibernatedTopology) (*bql.TopologyBuilder, error) {
+	if ht.stopped != nil {
+		<-ht.stopped
+	}
+	l := h.logger.WithField("topology", ht.name)
+	e, err := h.loadEntry(ht.name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load checkpoints of the hibernated topology: %v", err)
+	}
+	tb, err := newTopologyBuilder(h.logger, ht.name, ht.config, h.config, h.udsStorage, h.webhooks)
+	if err != nil {
+		return nil, fmt.Errorf("cannot restore the hibernated topology: %v", err)
+	}
+
+	s := &replication.TopologySnapshot{
+		Name:   ht.name,
+		Config: ht.config,
+		States: e.States,
+	}
+	p := parser.New()
+	for _, str := range ht.statements {
+		stmts, err := p.ParseStmts(str)
+		if err != nil {
+			// RestoreTopology reports the error.
+			s.Statements = append(s.Statements, str)
+			continue
+		}
+		for _, stmt := range stmts {
+			switch st := stmt.(type) {
+			case parser.CreateSourceStmt:
+				st.Paused = parser.Yes
+				stmt = st
+			case parser.ResumeSourceStmt:
+				continue
+			}
+			s.Statements = append(s.Statements, fmt.Sprint(stmt))
+		}
+	}
+	if err := replication.RestoreTopology(tb, s); err != nil {
+		l.WithField("err", err).Error("Cannot restore the hibernated topology completely")
+	}
+
+	if err := h.saveEntry(ht.name, &hibernationEntry{}); err != nil {
+		if err := tb.Topology().Stop(); err != nil {
+			l.WithField("err", err).Error("Cannot stop the topology")
+		}
+		return nil, fmt.Errorf("cannot discard checkpoints of the hibernated topology: %v", err)
+	}
+	return tb, nil
+}
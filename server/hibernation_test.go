@@ -0,0 +1,429 @@
+package server
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+	"gopkg.in/sensorbee/sensorbee.v0/server/replication"
+)
+
+// hibernationTestCounter is a savable state having a number.
+type hibernationTestCounter struct {
+	num int64
+}
+
+func (s *hibernationTestCounter) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (s *hibernationTestCounter) Save(ctx *core.Context, w io.Writer, params data.Map) error {
+	return binary.Write(w, binary.LittleEndian, s.num)
+}
+
+type hibernationTestCounterCreator struct{}
+
+func (hibernationTestCounterCreator) CreateState(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	s := &hibernationTestCounter{}
+	s.num, _ = data.AsInt(params["num"])
+	return s, nil
+}
+
+func (hibernationTestCounterCreator) LoadState(ctx *core.Context, r io.Reader, params data.Map) (core.SharedState, error) {
+	s := &hibernationTestCounter{}
+	if err := binary.Read(r, binary.LittleEndian, &s.num); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// hibernationTestPlain is a state which cannot be saved.
+type hibernationTestPlain struct{}
+
+func (hibernationTestPlain) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func init() {
+	udf.MustRegisterGlobalUDSCreator("hibernation_test_counter", hibernationTestCounterCreator{})
+	udf.MustRegisterGlobalUDSCreator("hibernation_test_plain", udf.UDSCreatorFunc(
+		func(ctx *core.Context, params data.Map) (core.SharedState, error) {
+			return hibernationTestPlain{}, nil
+		}))
+}
+
+// fakeTopologyRegistry is a TopologyRegistry recording names of registered
+// and unregistered topologies.
+type fakeTopologyRegistry struct {
+	TopologyRegistry
+
+	m            sync.Mutex
+	registered   []string
+	unregistered []string
+}
+
+func newFakeTopologyRegistry() *fakeTopologyRegistry {
+	return &fakeTopologyRegistry{
+		TopologyRegistry: NewDefaultTopologyRegistry(),
+	}
+}
+
+func (r *fakeTopologyRegistry) Register(name string, tb *bql.TopologyBuilder) error {
+	r.m.Lock()
+	r.registered = append(r.registered, name)
+	r.m.Unlock()
+	return r.TopologyRegistry.Register(name, tb)
+}
+
+func (r *fakeTopologyRegistry) Unregister(name string) (*bql.TopologyBuilder, error) {
+	r.m.Lock()
+	r.unregistered = append(r.unregistered, name)
+	r.m.Unlock()
+	return r.TopologyRegistry.Unregister(name)
+}
+
+// blockingUDSStorage blocks loading entries of hibernated topologies until
+// unblock is closed.
+type blockingUDSStorage struct {
+	udf.UDSStorage
+	loading chan struct{}
+	unblock chan struct{}
+}
+
+func (s *blockingUDSStorage) Load(topology, state, tag string) (io.ReadCloser, error) {
+	if tag == hibernationEntryName {
+		close(s.loading)
+		<-s.unblock
+	}
+	return s.UDSStorage.Load(topology, state, tag)
+}
+
+// blockingSaveUDSStorage blocks saving the first entry of a hibernated
+// topology until unblock is closed.
+type blockingSaveUDSStorage struct {
+	udf.UDSStorage
+	saving  chan struct{}
+	unblock chan struct{}
+	once    sync.Once
+}
+
+func (s *blockingSaveUDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter, error) {
+	if tag == hibernationEntryName {
+		s.once.Do(func() {
+			close(s.saving)
+			<-s.unblock
+		})
+	}
+	return s.UDSStorage.Save(topology, state, tag)
+}
+
+func newTestHibernator(r TopologyRegistry, us udf.UDSStorage, recorder *replication.Recorder) *hibernator {
+	conf, err := config.New(data.Map{
+		"hibernation": data.Map{
+			"idle_timeout": data.Float(60),
+		},
+	})
+	So(err, ShouldBeNil)
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	return newHibernator(r, logger, conf, us, recorder, nil)
+}
+
+// createTestTopology creates a topology via the hibernator and records it in
+// the same way as the API does.
+func createTestTopology(h *hibernator, name string, stmts ...string) *bql.TopologyBuilder {
+	tb, err := newTopologyBuilder(h.logger, name, data.Map{}, h.config, h.udsStorage, nil)
+	So(err, ShouldBeNil)
+	So(h.Register(name, tb), ShouldBeNil)
+	So(h.recorder.Create(name, data.Map{}), ShouldBeNil)
+	p := parser.New()
+	for _, s := range stmts {
+		stmt, _, err := p.ParseStmt(s)
+		So(err, ShouldBeNil)
+		_, err = tb.AddStmt(stmt)
+		So(err, ShouldBeNil)
+		So(h.recorder.Append(name, s), ShouldBeNil)
+	}
+	return tb
+}
+
+func hibernationCounterValue(tb *bql.TopologyBuilder, name string) int64 {
+	st, err := tb.Topology().Context().SharedStates.Get(name)
+	So(err, ShouldBeNil)
+	return st.(*hibernationTestCounter).num
+}
+
+func TestHibernator(t *testing.T) {
+	f, err := ioutil.TempFile("", "sbtest_hibernation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	Convey("Given a hibernator with a fake registry", t, func() {
+		r := newFakeTopologyRegistry()
+		us := udf.NewInMemoryUDSStorage()
+		recorder := replication.NewRecorder()
+		h := newTestHibernator(r, us, recorder)
+		Reset(func() {
+			ts, _ := r.List()
+			for _, tb := range ts {
+				tb.Topology().Stop()
+			}
+		})
+
+		Convey("When a topology isn't recorded", func() {
+			tp, err := core.NewDefaultTopology(core.NewContext(nil), "unrecorded")
+			So(err, ShouldBeNil)
+			tb, err := bql.NewTopologyBuilder(tp)
+			So(err, ShouldBeNil)
+			So(h.Register("unrecorded", tb), ShouldBeNil)
+
+			Convey("Then it cannot be hibernated", func() {
+				So(h.canHibernate(tb), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a recorded topology has a running source", func() {
+			tb := createTestTopology(h, "test1",
+				`CREATE SOURCE src TYPE file WITH path="`+f.Name()+`", rewindable=true;`)
+
+			Convey("Then it cannot be hibernated", func() {
+				So(h.canHibernate(tb), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a recorded topology has a state which cannot be saved", func() {
+			tb := createTestTopology(h, "test1", `CREATE STATE s TYPE hibernation_test_plain;`)
+
+			Convey("Then it cannot be hibernated", func() {
+				So(h.canHibernate(tb), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a recorded topology has a protected state", func() {
+			tb := createTestTopology(h, "test1")
+			stmt, _, err := parser.New().ParseStmt(`CREATE STATE s TYPE hibernation_test_counter WITH protected=true;`)
+			So(err, ShouldBeNil)
+			_, err = tb.AddStmtAs("owner", stmt)
+			So(err, ShouldBeNil)
+
+			Convey("Then it cannot be hibernated", func() {
+				So(h.canHibernate(tb), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a recorded topology has paused sources and savable states", func() {
+			tb := createTestTopology(h, "test1",
+				`CREATE PAUSED SOURCE src TYPE file WITH path="`+f.Name()+`", rewindable=true;`,
+				`CREATE STATE s TYPE hibernation_test_counter WITH num=1;`)
+			st, err := tb.Topology().Context().SharedStates.Get("s")
+			So(err, ShouldBeNil)
+			st.(*hibernationTestCounter).num = 10
+			So(h.canHibernate(tb), ShouldBeTrue)
+			now := time.Now()
+
+			Convey("Then it shouldn't be hibernated before the idle timeout", func() {
+				h.hibernateIdle(now.Add(h.idleTimeout / 2))
+				So(h.Hibernated(), ShouldBeEmpty)
+				So(r.unregistered, ShouldBeEmpty)
+			})
+
+			Convey("Then it shouldn't be hibernated while a client is using it", func() {
+				h.begin("test1")
+				h.hibernateIdle(now.Add(2 * h.idleTimeout))
+				So(h.Hibernated(), ShouldBeEmpty)
+
+				Convey("And it should be hibernated after the client finishes", func() {
+					h.end("test1")
+					h.hibernateIdle(time.Now().Add(2 * h.idleTimeout))
+					So(h.Hibernated(), ShouldResemble, []string{"test1"})
+				})
+			})
+
+			Convey("And checkpoints are being saved to hibernate it", func() {
+				bs := &blockingSaveUDSStorage{
+					UDSStorage: us,
+					saving:     make(chan struct{}),
+					unblock:    make(chan struct{}),
+				}
+				h.udsStorage = bs
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					h.hibernateIdle(now.Add(2 * h.idleTimeout))
+				}()
+				<-bs.saving
+
+				Convey("Then the hibernator shouldn't be locked", func() {
+					So(h.Hibernated(), ShouldBeEmpty)
+					h.begin("test2")
+					h.end("test2")
+					close(bs.unblock)
+					<-done
+					So(h.Hibernated(), ShouldResemble, []string{"test1"})
+				})
+
+				Convey("Then looking it up should cancel the hibernation", func() {
+					_, err := h.Lookup("test1")
+					So(err, ShouldBeNil)
+					close(bs.unblock)
+					<-done
+
+					So(h.Hibernated(), ShouldBeEmpty)
+					So(r.unregistered, ShouldBeEmpty)
+					So(tb.Topology().State().Get(), ShouldEqual, core.TSRunning)
+					e, err := h.loadEntry("test1")
+					So(err, ShouldBeNil)
+					So(e.Hibernated, ShouldBeFalse)
+				})
+
+				Convey("Then a client starting to use it should cancel the hibernation", func() {
+					h.begin("test1")
+					h.end("test1")
+					close(bs.unblock)
+					<-done
+					So(h.Hibernated(), ShouldBeEmpty)
+					So(r.unregistered, ShouldBeEmpty)
+				})
+
+				Convey("Then removing it should cancel the hibernation", func() {
+					tb2, err := h.Unregister("test1")
+					So(err, ShouldBeNil)
+					So(tb2, ShouldEqual, tb)
+					close(bs.unblock)
+					<-done
+
+					So(h.Hibernated(), ShouldBeEmpty)
+					So(r.unregistered, ShouldResemble, []string{"test1"})
+					e, err := h.loadEntry("test1")
+					So(err, ShouldBeNil)
+					So(e.Hibernated, ShouldBeFalse)
+				})
+			})
+
+			Convey("And it's hibernated after the idle timeout", func() {
+				h.hibernateIdle(now.Add(2 * h.idleTimeout))
+
+				Convey("Then it should be stopped and unregistered", func() {
+					So(h.Hibernated(), ShouldResemble, []string{"test1"})
+					So(r.unregistered, ShouldResemble, []string{"test1"})
+					So(tb.Topology().State().Get(), ShouldEqual, core.TSStopped)
+					ts, err := h.List()
+					So(err, ShouldBeNil)
+					So(ts, ShouldBeEmpty)
+				})
+
+				Convey("Then its checkpoints should be saved to the UDS storage", func() {
+					e, err := h.loadEntry("test1")
+					So(err, ShouldBeNil)
+					So(e.Hibernated, ShouldBeTrue)
+					So(e.States, ShouldHaveLength, 1)
+					So(e.States[0].Name, ShouldEqual, "s")
+					So(e.States[0].Type, ShouldEqual, "hibernation_test_counter")
+				})
+
+				Convey("Then a topology having the same name cannot be registered", func() {
+					tp, err := core.NewDefaultTopology(core.NewContext(nil), "test1")
+					So(err, ShouldBeNil)
+					tb2, err := bql.NewTopologyBuilder(tp)
+					So(err, ShouldBeNil)
+					defer tp.Stop()
+					So(h.Register("test1", tb2), ShouldEqual, os.ErrExist)
+				})
+
+				Convey("Then looking it up should restore it with its states", func() {
+					tb2, err := h.Lookup("TEST1")
+					So(err, ShouldBeNil)
+					So(tb2, ShouldNotEqual, tb)
+					So(h.Hibernated(), ShouldBeEmpty)
+					So(r.registered, ShouldResemble, []string{"test1", "test1"})
+					So(hibernationCounterValue(tb2, "s"), ShouldEqual, 10)
+
+					src, err := tb2.Topology().Source("src")
+					So(err, ShouldBeNil)
+					So(src.State().Get(), ShouldEqual, core.TSPaused)
+
+					Convey("And its checkpoints should be discarded", func() {
+						e, err := h.loadEntry("test1")
+						So(err, ShouldBeNil)
+						So(e.Hibernated, ShouldBeFalse)
+						So(e.States, ShouldBeEmpty)
+					})
+				})
+
+				Convey("Then unregistering it should restore and remove it", func() {
+					tb2, err := h.Unregister("test1")
+					So(err, ShouldBeNil)
+					defer tb2.Topology().Stop()
+					So(h.Hibernated(), ShouldBeEmpty)
+					_, err = r.Lookup("test1")
+					So(core.IsNotExist(err), ShouldBeTrue)
+				})
+
+				Convey("Then a hibernator created after a restart should restore it", func() {
+					h2 := newTestHibernator(newFakeTopologyRegistry(), us, recorder)
+					h2.loadHibernated()
+					So(h2.Hibernated(), ShouldResemble, []string{"test1"})
+					So(h2.isHibernated("TEST1"), ShouldBeTrue)
+
+					tb2, err := h2.Lookup("test1")
+					So(err, ShouldBeNil)
+					defer tb2.Topology().Stop()
+					So(hibernationCounterValue(tb2, "s"), ShouldEqual, 10)
+
+					Convey("And it shouldn't be restored again after another restart", func() {
+						h3 := newTestHibernator(newFakeTopologyRegistry(), us, recorder)
+						h3.loadHibernated()
+						So(h3.Hibernated(), ShouldBeEmpty)
+					})
+				})
+
+				Convey("Then the hibernator shouldn't be locked while it's being restored", func() {
+					bs := &blockingUDSStorage{
+						UDSStorage: us,
+						loading:    make(chan struct{}),
+						unblock:    make(chan struct{}),
+					}
+					h.udsStorage = bs
+
+					type result struct {
+						tb  *bql.TopologyBuilder
+						err error
+					}
+					results := make(chan result, 2)
+					for i := 0; i < 2; i++ {
+						go func() {
+							tb, err := h.Lookup("test1")
+							results <- result{tb, err}
+						}()
+					}
+					<-bs.loading
+					So(h.Hibernated(), ShouldResemble, []string{"test1"})
+					h.begin("test2")
+					h.end("test2")
+
+					close(bs.unblock)
+					r1, r2 := <-results, <-results
+					So(r1.err, ShouldBeNil)
+					So(r2.err, ShouldBeNil)
+					So(r1.tb, ShouldEqual, r2.tb)
+					So(r.registered, ShouldResemble, []string{"test1", "test1"})
+				})
+			})
+		})
+	})
+}
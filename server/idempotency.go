@@ -0,0 +1,244 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+const (
+	// idempotencyKeyHeader is the HTTP header having the idempotency key of
+	// a request.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader is set to true when the response is the one
+	// kept for a previous request having the same idempotency key.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key.
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	errIdempotencyKeyReused      = errors.New("the idempotency key was used for a request having a different body")
+	errIdempotentRequestInFlight = errors.New("a request having the same idempotency key is being processed")
+)
+
+// idempotentResponse is a response kept for an idempotency key. Only
+// responses rendered by Render or RenderError are kept.
+type idempotentResponse struct {
+	// fingerprint is the SHA-256 hash of the request body.
+	fingerprint [sha256.Size]byte
+	done        bool
+	expires     time.Time
+
+	// One of value or err is set when done is true.
+	value interface{}
+	err   *jasco.Error
+}
+
+// idempotencyStore keeps responses to requests having idempotency keys for
+// the retention period.
+type idempotencyStore struct {
+	retention time.Duration
+	maxKeys   int
+
+	m         sync.Mutex
+	responses map[string]*idempotentResponse
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+func newIdempotencyStore(conf *config.Idempotency) *idempotencyStore {
+	return &idempotencyStore{
+		retention: time.Duration(conf.Retention * float64(time.Second)),
+		maxKeys:   conf.MaxKeys,
+		responses: map[string]*idempotentResponse{},
+		now:       time.Now,
+	}
+}
+
+// begin starts processing a request having the key. It returns the kept
+// response when the request has already been processed. Otherwise, it
+// returns nil and the caller must call finish or abort after processing the
+// request.
+func (s *idempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (*idempotentResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	now := s.now()
+	if r, ok := s.responses[key]; ok && (!r.done || now.Before(r.expires)) {
+		switch {
+		case r.fingerprint != fingerprint:
+			return nil, errIdempotencyKeyReused
+		case !r.done:
+			return nil, errIdempotentRequestInFlight
+		}
+		return r, nil
+	}
+
+	if len(s.responses) >= s.maxKeys {
+		s.evict(now)
+	}
+	s.responses[key] = &idempotentResponse{
+		fingerprint: fingerprint,
+	}
+	return nil, nil
+}
+
+// evict removes expired responses. When no response has expired, it removes
+// the oldest one. Requests being processed are never removed. The caller
+// must hold the lock.
+func (s *idempotencyStore) evict(now time.Time) {
+	var (
+		oldestKey string
+		oldest    *idempotentResponse
+	)
+	for k, r := range s.responses {
+		if !r.done {
+			continue
+		}
+		if !now.Before(r.expires) {
+			delete(s.responses, k)
+			continue
+		}
+		if oldest == nil || r.expires.Before(oldest.expires) {
+			oldestKey, oldest = k, r
+		}
+	}
+	if oldest != nil && len(s.responses) >= s.maxKeys {
+		delete(s.responses, oldestKey)
+	}
+}
+
+// finish keeps the response rendered for the key.
+func (s *idempotencyStore) finish(key string, value interface{}, err *jasco.Error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	r, ok := s.responses[key]
+	if !ok {
+		return
+	}
+	r.done = true
+	r.expires = s.now().Add(s.retention)
+	r.value = value
+	r.err = err
+}
+
+// abort forgets the key so that the request can be retried.
+func (s *idempotencyStore) abort(key string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if r, ok := s.responses[key]; ok && !r.done {
+		delete(s.responses, key)
+	}
+}
+
+// idempotentRequest records the response rendered for a request having an
+// idempotency key.
+type idempotentRequest struct {
+	rendered bool
+	value    interface{}
+	err      *jasco.Error
+}
+
+// idempotent makes an action of topologies idempotent with the
+// Idempotency-Key header. The response to the first request having a key is
+// kept and returned again to following requests having the same key and the
+// same body. Responses which aren't rendered by Render or RenderError, such
+// as streams of SELECT statements, and responses with 5xx status codes
+// aren't kept so that the request can be retried.
+//
+// A key is scoped to the owner of the request given by requestOwner, the
+// method, and the path.
+func idempotent(action func(*topologies, web.ResponseWriter, *web.Request)) func(*topologies, web.ResponseWriter, *web.Request) {
+	return func(tc *topologies, rw web.ResponseWriter, req *web.Request) {
+		key := req.Header.Get(idempotencyKeyHeader)
+		if key == "" || tc.idempotency == nil {
+			action(tc, rw, req)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			tc.Log().Error("The idempotency key is too long")
+			e := jasco.NewError(formValidationErrorCode, "The request header is invalid.",
+				http.StatusBadRequest, nil)
+			e.Meta[idempotencyKeyHeader] = []string{"must be at most 255 characters"}
+			tc.RenderError(e)
+			return
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			tc.ErrLog(err).Error("Cannot read the request body")
+			tc.RenderError(jasco.NewInternalServerError(err))
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		storeKey := requestOwner(req) + "\x00" + req.Method + " " + req.URL.Path + "\x00" + key
+		res, err := tc.idempotency.begin(storeKey, sha256.Sum256(body))
+		switch err {
+		case nil:
+		case errIdempotencyKeyReused:
+			tc.ErrLog(err).Error("Cannot process the request")
+			tc.RenderError(jasco.NewError(idempotencyKeyReusedErrorCode, "The idempotency key was already used.",
+				http.StatusUnprocessableEntity, err))
+			return
+		default:
+			tc.ErrLog(err).Error("Cannot process the request")
+			tc.RenderError(jasco.NewError(resourceConflictErrorCode, "The request is being processed.",
+				http.StatusConflict, err))
+			return
+		}
+		if res != nil {
+			tc.Log().Info("Returning the response of the previous request having the same idempotency key")
+			rw.Header().Set(idempotentReplayedHeader, "true")
+			if res.err != nil {
+				tc.APIContext.RenderError(res.err)
+			} else {
+				tc.APIContext.Render(res.value)
+			}
+			return
+		}
+
+		ir := &idempotentRequest{}
+		tc.idempotentReq = ir
+		completed := false
+		defer func() {
+			tc.idempotentReq = nil
+			if !completed || !ir.rendered || rw.Status() >= 500 {
+				tc.idempotency.abort(storeKey)
+				return
+			}
+			tc.idempotency.finish(storeKey, ir.value, ir.err)
+		}()
+		action(tc, rw, req)
+		completed = true
+	}
+}
+
+// Render renders the value. It also records the value when the request has
+// an idempotency key.
+func (tc *topologies) Render(value interface{}) {
+	if ir := tc.idempotentReq; ir != nil {
+		ir.rendered, ir.value, ir.err = true, value, nil
+	}
+	tc.APIContext.Render(value)
+}
+
+// RenderError renders the error. It also records the error when the request
+// has an idempotency key.
+func (tc *topologies) RenderError(e *jasco.Error) {
+	if ir := tc.idempotentReq; ir != nil {
+		ir.rendered, ir.value, ir.err = true, nil, e
+	}
+	tc.APIContext.RenderError(e)
+}
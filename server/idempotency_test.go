@@ -0,0 +1,101 @@
+package server
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	Convey("Given an idempotency store", t, func() {
+		s := newIdempotencyStore(&config.Idempotency{
+			Retention: 60,
+			MaxKeys:   2,
+		})
+		now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+		s.now = func() time.Time {
+			return now
+		}
+		body := sha256.Sum256([]byte(`{"name":"test"}`))
+
+		Convey("When a request having a new key begins", func() {
+			res, err := s.begin("k1", body)
+			So(err, ShouldBeNil)
+			So(res, ShouldBeNil)
+
+			Convey("Then the same request should fail while it's being processed", func() {
+				_, err := s.begin("k1", body)
+				So(err, ShouldEqual, errIdempotentRequestInFlight)
+			})
+
+			Convey("Then a request having a different body should fail", func() {
+				_, err := s.begin("k1", sha256.Sum256([]byte(`{}`)))
+				So(err, ShouldEqual, errIdempotencyKeyReused)
+			})
+
+			Convey("And the request finishes", func() {
+				s.finish("k1", "result", nil)
+
+				Convey("Then the same request should return the response", func() {
+					res, err := s.begin("k1", body)
+					So(err, ShouldBeNil)
+					So(res, ShouldNotBeNil)
+					So(res.value, ShouldEqual, "result")
+				})
+
+				Convey("Then a request having a different body should still fail", func() {
+					_, err := s.begin("k1", sha256.Sum256([]byte(`{}`)))
+					So(err, ShouldEqual, errIdempotencyKeyReused)
+				})
+
+				Convey("Then the response should expire after the retention period", func() {
+					now = now.Add(time.Minute)
+					res, err := s.begin("k1", body)
+					So(err, ShouldBeNil)
+					So(res, ShouldBeNil)
+				})
+			})
+
+			Convey("And the request is aborted", func() {
+				s.abort("k1")
+
+				Convey("Then the request should be able to be retried", func() {
+					res, err := s.begin("k1", body)
+					So(err, ShouldBeNil)
+					So(res, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("When the number of keys reaches the limit", func() {
+			for _, k := range []string{"k1", "k2"} {
+				_, err := s.begin(k, body)
+				So(err, ShouldBeNil)
+				s.finish(k, k, nil)
+				now = now.Add(time.Second)
+			}
+			_, err := s.begin("k3", body)
+			So(err, ShouldBeNil)
+
+			Convey("Then the oldest response should be removed", func() {
+				So(s.responses, ShouldNotContainKey, "k1")
+				So(s.responses, ShouldContainKey, "k2")
+				So(s.responses, ShouldContainKey, "k3")
+			})
+
+			Convey("Then requests being processed shouldn't be removed", func() {
+				s.finish("k3", "k3", nil)
+				now = now.Add(time.Second)
+				_, err := s.begin("k4", body)
+				So(err, ShouldBeNil)
+				_, err = s.begin("k5", body)
+				So(err, ShouldBeNil)
+				So(s.responses, ShouldContainKey, "k4")
+				So(s.responses, ShouldContainKey, "k5")
+			})
+		})
+	})
+}
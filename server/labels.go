@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+)
+
+// labelSelector returns labels given by "label" query parameters of the
+// request. Each parameter has the form "key:value" and nodes listed by an
+// index action are filtered by all of them, e.g.
+// /sources?label=team:sensing&label=env:prod. It renders an error and
+// returns false when a parameter is malformed.
+func (tc *topologies) labelSelector(req *web.Request) (map[string]string, bool) {
+	sel := map[string]string{}
+	for _, l := range req.URL.Query()["label"] {
+		i := strings.Index(l, ":")
+		if i <= 0 {
+			err := fmt.Errorf("the label selector doesn't have the form key:value: %v", l)
+			tc.ErrLog(err).Error("Cannot parse the label selector")
+			e := jasco.NewError(formValidationErrorCode, "The request query is invalid.",
+				http.StatusBadRequest, err)
+			e.Meta["label"] = []string{err.Error()}
+			tc.RenderError(e)
+			return nil, false
+		}
+		sel[l[:i]] = l[i+1:]
+	}
+	return sel, true
+}
+
+// matchLabels returns true when labels have all key-value pairs in the
+// selector.
+func matchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
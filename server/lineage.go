@@ -0,0 +1,113 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+const (
+	// defaultLineageLimit is the default number of records returned by
+	// lineage.Index.
+	defaultLineageLimit = 10
+)
+
+type lineage struct {
+	*topologies
+}
+
+func setUpLineageRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(lineage{}, "/:topologyName/lineage")
+	root.Middleware((*lineage).fetchTopologyMiddleware)
+	root.Get("/", (*lineage).Index)
+	root.Get("/:lineageID", (*lineage).Show)
+}
+
+func (lc *lineage) fetchTopologyMiddleware(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if lc.fetchTopology() == nil {
+		return
+	}
+	next(rw, req)
+}
+
+// queryInt returns the integer query parameter of the request. It renders an
+// error and returns false when the parameter is invalid.
+func (lc *lineage) queryInt(req *web.Request, name string, def int) (int, bool) {
+	s := req.URL.Query().Get(name)
+	if s == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		lc.ErrLog(err).Error("Invalid query parameter")
+		e := jasco.NewError(formValidationErrorCode, "The request query is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta[name] = []string{"must be an integer"}
+		lc.RenderError(e)
+		return 0, false
+	}
+	return n, true
+}
+
+// Index returns lineage records of the latest tuples emitted by the stream
+// given by the "stream" query parameter.
+func (lc *lineage) Index(rw web.ResponseWriter, req *web.Request) {
+	stream := req.URL.Query().Get("stream")
+	if stream == "" {
+		err := errors.New("stream is required")
+		lc.ErrLog(err).Error("Invalid query parameter")
+		e := jasco.NewError(formValidationErrorCode, "The request query is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta["stream"] = []string{"is required"}
+		lc.RenderError(e)
+		return
+	}
+	limit, ok := lc.queryInt(req, "limit", defaultLineageLimit)
+	if !ok {
+		return
+	}
+	ls := lc.topology.RecentLineage(stream, limit)
+	lc.Render(map[string]interface{}{
+		"topology": lc.topologyName,
+		"stream":   stream,
+		"count":    len(ls),
+		"lineage":  ls,
+	})
+}
+
+// Show returns the lineage of a tuple. The "depth" query parameter limits
+// levels of input tuples expanded.
+func (lc *lineage) Show(rw web.ResponseWriter, req *web.Request) {
+	notFound := func(err error) {
+		lc.ErrLog(err).Error("Cannot find the lineage")
+		lc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The lineage was not found", http.StatusNotFound, err))
+	}
+	id, err := strconv.ParseInt(lc.PathParams().String("lineageID", ""), 10, 64)
+	if err != nil {
+		notFound(err)
+		return
+	}
+	depth, ok := lc.queryInt(req, "depth", 0)
+	if !ok {
+		return
+	}
+	l, err := lc.topology.Lineage(id, depth)
+	if err != nil {
+		if core.IsNotExist(err) {
+			notFound(err)
+			return
+		}
+		lc.ErrLog(err).Error("Cannot get the lineage")
+		lc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	lc.Render(map[string]interface{}{
+		"topology": lc.topologyName,
+		"lineage":  l,
+	})
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type lint struct {
+	*APIContext
+}
+
+func setUpLintRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(lint{}, "")
+	root.Post("/lint", (*lint).Lint)
+}
+
+// Lint parses queries in the request body without executing them. It
+// returns issues reported by the linter and the queries in the canonical
+// format.
+func (l *lint) Lint(rw web.ResponseWriter, req *web.Request) {
+	var js map[string]interface{}
+	if apiErr := l.ParseBody(&js); apiErr != nil {
+		l.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		l.RenderError(apiErr)
+		return
+	}
+
+	form, err := data.NewMap(js)
+	if err != nil {
+		l.ErrLog(err).WithField("body", js).
+			Error("The request json may contain invalid value")
+		l.RenderError(jasco.NewError(formValidationErrorCode, "The request json may contain invalid values.",
+			http.StatusBadRequest, err))
+		return
+	}
+
+	var queries string
+	if v, ok := form["queries"]; !ok {
+		l.Log().Error("The request json doesn't have 'queries' field")
+		l.RenderError(jasco.NewError(formValidationErrorCode, "'queries' field is missing",
+			http.StatusBadRequest, nil))
+		return
+	} else if f, err := data.AsString(v); err != nil {
+		l.ErrLog(err).Error("'queries' must be a string")
+		l.RenderError(jasco.NewError(formValidationErrorCode, "'queries' field must be a string",
+			http.StatusBadRequest, err))
+		return
+	} else {
+		queries = f
+	}
+
+	stmts, err := parser.New().ParseStmts(queries)
+	if err != nil {
+		l.Log().WithField("parse_errors", err.Error()).
+			WithField("statement", queries).Error("Cannot parse a statement")
+		e := jasco.NewError(bqlStmtParseErrorCode, "Cannot parse a BQL statement", http.StatusBadRequest, err)
+		e.Meta["parse_errors"] = strings.Split(err.Error(), "\n")
+		e.Meta["statement"] = queries
+		l.RenderError(e)
+		return
+	}
+
+	issues := parser.Lint(stmts)
+	if issues == nil {
+		issues = []parser.LintIssue{}
+	}
+	l.Render(map[string]interface{}{
+		"issues":    issues,
+		"formatted": parser.FormatStmts(stmts),
+	})
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gocraft/web"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type metrics struct {
+	*Context
+}
+
+// setUpMetricsRouter sets up the endpoint exporting metrics of nodes in the
+// Prometheus text exposition format. It's placed at /metrics, which is the
+// default path of Prometheus, rather than under the API prefix.
+func setUpMetricsRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(metrics{}, "")
+	root.Middleware((*metrics).authenticate)
+	root.Get("/metrics", (*metrics).Index)
+}
+
+// Index writes metrics of all nodes in topologies added to
+// core.DefaultMetrics, which has topologies created via the API as well as
+// ones created programmatically in the same process.
+func (mc *metrics) Index(rw web.ResponseWriter, req *web.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := writePrometheusMetrics(rw, core.DefaultMetrics.Topologies()); err != nil {
+		mc.ErrLog(err).Error("Cannot write metrics")
+	}
+}
+
+// nodeMetrics has the status of a node and labels identifying it. Labels of
+// the node are also added with the "label_" prefix.
+type nodeMetrics struct {
+	labels string
+	status data.Map
+}
+
+// metricFamily describes a metric computed from statuses of nodes.
+type metricFamily struct {
+	name string
+	typ  string
+	help string
+
+	// path is the path of the value in a status. When perInput is true, it's
+	// the path in the status of each input in "input_stats.inputs" and the
+	// metric has one sample per input.
+	path     data.Path
+	perInput bool
+}
+
+var (
+	nodeMetricFamilies = []metricFamily{
+		{"sensorbee_node_tuples_in_total", "counter",
+			"The number of tuples received by the node.",
+			data.MustCompilePath("input_stats.num_received_total"), false},
+		{"sensorbee_node_tuples_out_total", "counter",
+			"The number of tuples emitted by the node including dropped ones.",
+			data.MustCompilePath("output_stats.num_sent_total"), false},
+		{"sensorbee_node_errors_total", "counter",
+			"The number of errors the node had while processing tuples.",
+			data.MustCompilePath("input_stats.num_errors"), false},
+		{"sensorbee_node_dropped_tuples_total", "counter",
+			"The number of tuples dropped because no destination was connected to the node.",
+			data.MustCompilePath("output_stats.num_dropped"), false},
+		{"sensorbee_node_queue_depth", "gauge",
+			"The number of tuples buffered in the queue of the input.",
+			data.MustCompilePath("num_queued"), true},
+		{"sensorbee_node_queue_capacity", "gauge",
+			"The capacity of the queue of the input.",
+			data.MustCompilePath("queue_size"), true},
+	}
+
+	nodeInputsPath         = data.MustCompilePath("input_stats.inputs")
+	nodeProcessLatencyPath = data.MustCompilePath("process_latency")
+)
+
+// writePrometheusMetrics writes metrics of all nodes in the topologies in
+// the Prometheus text exposition format.
+func writePrometheusMetrics(w io.Writer, ts []core.Topology) error {
+	var nodes []nodeMetrics
+	for _, t := range ts {
+		ns := t.Nodes()
+		names := make([]string, 0, len(ns))
+		for name := range ns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			n := ns[name]
+			nodes = append(nodes, nodeMetrics{
+				labels: fmt.Sprintf(`topology="%v",node="%v",node_type="%v"%v`,
+					escapeLabelValue(t.Name()), escapeLabelValue(n.Name()), n.Type(),
+					formatNodeLabels(n.Labels())),
+				status: n.Status(),
+			})
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, f := range nodeMetricFamilies {
+		fmt.Fprintf(bw, "# HELP %v %v\n# TYPE %v %v\n", f.name, f.help, f.name, f.typ)
+		for _, n := range nodes {
+			if !f.perInput {
+				if v, err := n.status.Get(f.path); err == nil {
+					fmt.Fprintf(bw, "%v{%v} %v\n", f.name, n.labels, formatMetricValue(v))
+				}
+				continue
+			}
+			inputs, err := n.status.Get(nodeInputsPath)
+			if err != nil {
+				continue
+			}
+			m, err := data.AsMap(inputs)
+			if err != nil {
+				continue
+			}
+			for _, input := range sortedKeys(m) {
+				in, err := data.AsMap(m[input])
+				if err != nil {
+					continue
+				}
+				if v, err := in.Get(f.path); err == nil {
+					fmt.Fprintf(bw, "%v{%v,input=\"%v\"} %v\n", f.name, n.labels,
+						escapeLabelValue(input), formatMetricValue(v))
+				}
+			}
+		}
+	}
+
+	const latency = "sensorbee_node_process_latency_seconds"
+	fmt.Fprintf(bw, "# HELP %v %v\n# TYPE %v histogram\n", latency,
+		"The duration of processing a tuple by the node.", latency)
+	for _, n := range nodes {
+		v, err := n.status.Get(nodeProcessLatencyPath)
+		if err != nil {
+			continue
+		}
+		h, err := data.AsMap(v)
+		if err != nil {
+			continue
+		}
+		buckets, _ := data.AsArray(h["buckets"])
+		for _, b := range buckets {
+			bm, err := data.AsMap(b)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(bw, "%v_bucket{%v,le=\"%v\"} %v\n", latency, n.labels,
+				formatMetricValue(bm["le"]), formatMetricValue(bm["count"]))
+		}
+		fmt.Fprintf(bw, "%v_bucket{%v,le=\"+Inf\"} %v\n", latency, n.labels, formatMetricValue(h["count"]))
+		fmt.Fprintf(bw, "%v_sum{%v} %v\n", latency, n.labels, formatMetricValue(h["sum"]))
+		fmt.Fprintf(bw, "%v_count{%v} %v\n", latency, n.labels, formatMetricValue(h["count"]))
+	}
+	return bw.Flush()
+}
+
+func sortedKeys(m data.Map) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatMetricValue formats a numeric value as a sample value.
+func formatMetricValue(v data.Value) string {
+	switch v.Type() {
+	case data.TypeInt:
+		i, _ := data.AsInt(v)
+		return strconv.FormatInt(i, 10)
+	case data.TypeFloat:
+		f, _ := data.AsFloat(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return "NaN"
+}
+
+// formatNodeLabels formats labels of a node as label pairs sorted by their
+// keys. Each pair is preceded by a comma.
+func formatNodeLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b := bytes.NewBuffer(nil)
+	for _, k := range keys {
+		fmt.Fprintf(b, `,label_%v="%v"`, sanitizeLabelName(k), escapeLabelValue(labels[k]))
+	}
+	return b.String()
+}
+
+var labelNameRegexp = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// sanitizeLabelName replaces characters which cannot be used in a label name
+// with '_'. Keys of node labels are usually valid because core validates
+// them, but a custom node may have any keys.
+func sanitizeLabelName(s string) string {
+	return labelNameRegexp.ReplaceAllString(s, "_")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes a label value as required by the exposition
+// format.
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type metricsTestSource struct{}
+
+func (s *metricsTestSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	return w.Write(ctx, core.NewTuple(data.Map{"a": data.Int(1)}))
+}
+
+func (s *metricsTestSource) Stop(ctx *core.Context) error {
+	return nil
+}
+
+func TestWritePrometheusMetrics(t *testing.T) {
+	Convey("Given a topology having a source and a box", t, func() {
+		tp, err := core.NewDefaultTopology(core.NewContext(&core.ContextConfig{
+			Metrics: core.NewMetrics(),
+		}), "test_topology")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			tp.Stop()
+		})
+		_, err = tp.AddSource("source", &metricsTestSource{}, &core.SourceConfig{
+			PausedOnStartup: true,
+		})
+		So(err, ShouldBeNil)
+		bn, err := tp.AddBox("box", core.BoxFunc(func(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+			return w.Write(ctx, t)
+		}), &core.BoxConfig{
+			Labels: map[string]string{
+				"team": "data",
+				"env":  "prod",
+			},
+		})
+		So(err, ShouldBeNil)
+		So(bn.Input("source", nil), ShouldBeNil)
+
+		Convey("When writing metrics", func() {
+			buf := bytes.NewBuffer(nil)
+			So(writePrometheusMetrics(buf, []core.Topology{tp}), ShouldBeNil)
+			out := buf.String()
+
+			Convey("Then it should have counters of nodes", func() {
+				So(out, ShouldContainSubstring, "# TYPE sensorbee_node_tuples_in_total counter\n")
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_tuples_in_total{topology="test_topology",node="box",node_type="box",label_env="prod",label_team="data"} 0`)
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_tuples_out_total{topology="test_topology",node="source",node_type="source"} 0`)
+			})
+
+			Convey("Then it should have labels of nodes", func() {
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_tuples_out_total{topology="test_topology",node="box",node_type="box",label_env="prod",label_team="data"} 0`)
+				So(out, ShouldNotContainSubstring, `node="source",node_type="source",label_`)
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_process_latency_seconds_count{topology="test_topology",node="box",node_type="box",label_env="prod",label_team="data"} 0`)
+			})
+
+			Convey("Then it should have queue metrics of each input", func() {
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_queue_capacity{topology="test_topology",node="box",node_type="box",label_env="prod",label_team="data",input="source"} 1024`)
+			})
+
+			Convey("Then it should have latency histograms of boxes", func() {
+				So(out, ShouldContainSubstring, "# TYPE sensorbee_node_process_latency_seconds histogram\n")
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_process_latency_seconds_bucket{topology="test_topology",node="box",node_type="box",label_env="prod",label_team="data",le="+Inf"} 0`)
+				So(out, ShouldContainSubstring,
+					`sensorbee_node_process_latency_seconds_count{topology="test_topology",node="box",node_type="box",label_env="prod",label_team="data"} 0`)
+				So(out, ShouldNotContainSubstring, `process_latency_seconds_count{topology="test_topology",node="source"`)
+			})
+		})
+	})
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	Convey("Given label values having special characters", t, func() {
+		Convey("Then they should be escaped", func() {
+			So(escapeLabelValue("a\"b\\c\nd"), ShouldEqual, `a\"b\\c\nd`)
+		})
+	})
+}
+
+func TestFormatNodeLabels(t *testing.T) {
+	Convey("Given labels of a node", t, func() {
+		labels := map[string]string{
+			"team":   "a\"b",
+			"env":    "prod",
+			"app.id": "x",
+		}
+
+		Convey("Then they should be formatted in order with sanitized names", func() {
+			So(formatNodeLabels(labels), ShouldEqual, `,label_app_id="x",label_env="prod",label_team="a\"b"`)
+		})
+
+		Convey("Then nothing should be formatted without labels", func() {
+			So(formatNodeLabels(nil), ShouldBeEmpty)
+		})
+	})
+}
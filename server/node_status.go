@@ -0,0 +1,97 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gocraft/web"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// nodeControl has operations to change the state of a node. gracefulStop is
+// nil when the node doesn't have input queues to drain.
+type nodeControl struct {
+	node         core.Node
+	pause        func() error
+	resume       func() error
+	gracefulStop func()
+}
+
+// updateNodeStatus changes the state of a node as requested by the body of
+// PUT .../{node_kind}/{node_name}/status. The body has "state" which is one
+// of "running", "paused", and "stopped". When "drain" is true with "stopped",
+// the node processes tuples in its input queues before it stops. It renders
+// an error and returns false when the state cannot be changed.
+//
+// A node protected by an owner can only be changed by requests having the
+// same bearer token as the request which created it, like PAUSE or RESUME
+// statements.
+func (tc *topologies) updateNodeStatus(req *web.Request, nc *nodeControl) bool {
+	form, ok := tc.parseNodeForm()
+	if !ok {
+		return false
+	}
+
+	badRequest := func(field string, err error) bool {
+		tc.renderFieldError(field, err)
+		return false
+	}
+	state, ok := tc.stringField(form, "state", true)
+	if !ok {
+		return false
+	}
+	drain := false
+	if v, ok := form["drain"]; ok {
+		var err error
+		if drain, err = data.AsBool(v); err != nil {
+			return badRequest("drain", errors.New("drain must be a bool"))
+		}
+	}
+	if drain && state != "stopped" {
+		return badRequest("drain", errors.New("drain can only be used when stopping the node"))
+	}
+	if drain && nc.gracefulStop == nil {
+		return badRequest("drain", fmt.Errorf("%v '%v' doesn't have input queues to drain",
+			nc.node.Type(), nc.node.Name()))
+	}
+
+	if owner, protected := tc.topology.Ownership("node", nc.node.Name()); protected && owner != requestOwner(req) {
+		err := &bql.ProtectedError{Kind: "node", Name: nc.node.Name()}
+		tc.ErrLog(err).Error("Cannot change the status of the node")
+		e := stmtProcessingError(err)
+		e.Meta["error"] = err.Error()
+		tc.RenderError(e)
+		return false
+	}
+
+	var err error
+	switch state {
+	case "running":
+		err = nc.resume()
+	case "paused":
+		err = nc.pause()
+	case "stopped":
+		if drain {
+			nc.gracefulStop()
+		}
+		err = nc.node.Stop()
+	default:
+		return badRequest("state", fmt.Errorf("state must be running, paused, or stopped: %v", state))
+	}
+	if err != nil {
+		tc.ErrLog(err).WithField("state", state).Error("Cannot change the status of the node")
+		tc.RenderError(jasco.NewError(resourceConflictErrorCode, "The status of the node cannot be changed.",
+			http.StatusConflict, err))
+		return false
+	}
+	tc.Log().WithFields(logrus.Fields{
+		"state": state,
+		"drain": drain,
+	}).Info("Changed the status of the node")
+	return true
+}
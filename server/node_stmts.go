@@ -0,0 +1,142 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Actions creating, updating, and dropping nodes build BQL statements from
+// request bodies and process them in the same way as the Queries action, so
+// that ownership, persistence, and replication of statements work as they
+// do for statements sent as strings.
+
+// paramKeyPattern is the pattern of keys of WITH and SET clauses accepted by
+// the parser. Keys are checked so that persisted statements can be parsed
+// when the topology is restored.
+var paramKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// parseNodeForm parses the request body as a JSON object. It renders an error
+// and returns false when the body is invalid.
+func (tc *topologies) parseNodeForm() (data.Map, bool) {
+	var js map[string]interface{}
+	if apiErr := tc.ParseBody(&js); apiErr != nil {
+		tc.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		tc.RenderError(apiErr)
+		return nil, false
+	}
+	form, err := data.NewMap(js)
+	if err != nil {
+		tc.ErrLog(err).WithField("body", js).
+			Error("The request json may contain invalid value")
+		tc.RenderError(jasco.NewError(formValidationErrorCode, "The request json may contain invalid values.",
+			http.StatusBadRequest, err))
+		return nil, false
+	}
+	return form, true
+}
+
+// renderFieldError renders an error of a field of the request body.
+func (tc *topologies) renderFieldError(field string, err error) {
+	tc.ErrLog(err).Error("The request json has an invalid field")
+	e := jasco.NewError(formValidationErrorCode, "The request json has invalid fields.",
+		http.StatusBadRequest, err)
+	e.Meta[field] = []string{err.Error()}
+	tc.RenderError(e)
+}
+
+// stringField returns a string field of the request body. It renders an
+// error and returns false when the field is invalid or is required but
+// missing.
+func (tc *topologies) stringField(form data.Map, field string, required bool) (string, bool) {
+	v, ok := form[field]
+	if !ok {
+		if required {
+			tc.renderFieldError(field, fmt.Errorf("%v is required", field))
+			return "", false
+		}
+		return "", true
+	}
+	s, err := data.AsString(v)
+	if err != nil {
+		tc.renderFieldError(field, fmt.Errorf("%v must be a string", field))
+		return "", false
+	}
+	return s, true
+}
+
+// paramsField returns parameters of a WITH or SET clause given as an object
+// in the "params" field of the request body. It renders an error and returns
+// false when the field is invalid.
+func (tc *topologies) paramsField(form data.Map) ([]parser.SourceSinkParamAST, bool) {
+	v, ok := form["params"]
+	if !ok {
+		return nil, true
+	}
+	params, err := newSourceSinkParams(v)
+	if err != nil {
+		tc.renderFieldError("params", err)
+		return nil, false
+	}
+	return params, true
+}
+
+// newSourceSinkParams converts an object to parameters of a WITH or SET
+// clause. Parameters are sorted by their keys so that the same object always
+// results in the same statement.
+func newSourceSinkParams(v data.Value) ([]parser.SourceSinkParamAST, error) {
+	m, err := data.AsMap(v)
+	if err != nil {
+		return nil, fmt.Errorf("params must be an object")
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !paramKeyPattern.MatchString(k) {
+			return nil, fmt.Errorf("invalid parameter name: %v", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]parser.SourceSinkParamAST, len(keys))
+	for i, k := range keys {
+		params[i] = parser.SourceSinkParamAST{
+			Key:   parser.SourceSinkParamKey(k),
+			Value: m[k],
+		}
+	}
+	return params, nil
+}
+
+// addStmt processes a statement on behalf of the owner of the request and
+// persists it. It renders an error and returns false when the statement
+// cannot be processed.
+func (tc *topologies) addStmt(req *web.Request, stmt interface{}) (core.Node, bool) {
+	tb := tc.fetchTopology()
+	if tb == nil { // just in case
+		return nil, false
+	}
+
+	// TODO: change the return value of AddStmt to support the new response format.
+	n, err := tb.AddStmtAs(requestOwner(req), stmt)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot process a statement")
+		e := stmtProcessingError(err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = fmt.Sprint(stmt)
+		tc.RenderError(e)
+		return nil, false
+	}
+	if tc.recorder != nil {
+		if err := tc.recorder.Append(tc.topologyName, fmt.Sprint(stmt)); err != nil {
+			tc.ErrLog(err).Error("Cannot persist the statement")
+		}
+	}
+	return n, true
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestNewSourceSinkParams(t *testing.T) {
+	Convey("Given an object having parameters", t, func() {
+		v := data.Map{
+			"topic":   data.String("a \"b\""),
+			"num":     data.Int(1),
+			"brokers": data.Array{data.String("localhost:9092")},
+		}
+
+		Convey("When converting it to parameters", func() {
+			params, err := newSourceSinkParams(v)
+			So(err, ShouldBeNil)
+
+			Convey("Then they should be sorted by their keys", func() {
+				So(len(params), ShouldEqual, 3)
+				So(params[0].Key, ShouldEqual, "brokers")
+				So(params[1].Key, ShouldEqual, "num")
+				So(params[2].Key, ShouldEqual, "topic")
+			})
+
+			Convey("Then a statement having them should be parsed as it is", func() {
+				stmt := parser.CreateSourceStmt{
+					Name:               "src",
+					Type:               "dummy",
+					SourceSinkSpecsAST: parser.SourceSinkSpecsAST{Params: params},
+				}
+				parsed, _, err := parser.New().ParseStmt(fmt.Sprint(stmt))
+				So(err, ShouldBeNil)
+				So(parsed, ShouldResemble, stmt)
+			})
+		})
+	})
+
+	Convey("Given invalid parameters", t, func() {
+		for _, v := range []data.Value{
+			data.String("a"),
+			data.Map{"invalid key": data.Int(1)},
+			data.Map{"1a": data.Int(1)},
+		} {
+			Convey(fmt.Sprintf("Then %v should be rejected", v), func() {
+				_, err := newSourceSinkParams(v)
+				So(err, ShouldNotBeNil)
+			})
+		}
+	})
+}
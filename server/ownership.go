@@ -0,0 +1,59 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+)
+
+// requestOwner returns the owner of nodes and states created by statements
+// in the request. The owner is the hex encoded SHA-256 hash of the bearer
+// token given by the Authorization header so that the token itself isn't
+// kept in the server. It returns an empty string when the request doesn't
+// have a token, which means the request is anonymous.
+//
+// The token isn't verified here. It's only used to identify the client which
+// created protected nodes.
+func requestOwner(req *web.Request) string {
+	const prefix = "bearer "
+	h := req.Header.Get("Authorization")
+	if len(h) <= len(prefix) || strings.ToLower(h[:len(prefix)]) != prefix {
+		return ""
+	}
+	token := strings.TrimSpace(h[len(prefix):])
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// stmtProcessingError creates an error returned when a statement cannot be
+// processed. The caller sets Meta["error"] and Meta["statement"].
+func stmtProcessingError(err error) *jasco.Error {
+	switch stmtErrorCode(err) {
+	case protectedNodeErrorCode:
+		return jasco.NewError(protectedNodeErrorCode, "The node is protected by its owner", http.StatusForbidden, err)
+	case tooManyStatementsErrorCode:
+		return jasco.NewError(tooManyStatementsErrorCode, "The topology is running too many statements",
+			http.StatusTooManyRequests, err)
+	}
+	return jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+}
+
+// stmtErrorCode returns the error code of an error returned when a statement
+// cannot be processed. The error must not be wrapped.
+func stmtErrorCode(err error) string {
+	switch {
+	case bql.IsProtectedError(err):
+		return protectedNodeErrorCode
+	case bql.IsAdmissionError(err):
+		return tooManyStatementsErrorCode
+	}
+	return bqlStmtProcessingErrorCode
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/server/replication"
+)
+
+type replicationContext struct {
+	*APIContext
+}
+
+func setUpReplicationRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(replicationContext{}, "/replication")
+	root.Middleware((*replicationContext).requireReplication)
+	root.Get("/", (*replicationContext).Show)
+	root.Get("/snapshot", (*replicationContext).Snapshot)
+	root.Post("/promote", (*replicationContext).Promote)
+}
+
+func (rc *replicationContext) requireReplication(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	// The recorder is also used by hibernation.
+	if r := rc.config.Replication; r == nil || r.Role == "none" {
+		rc.Log().Error("Replication is disabled")
+		rc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"Replication is disabled on the server", http.StatusNotFound, nil))
+		return
+	}
+	next(rw, req)
+}
+
+// Show returns the status of replication.
+func (rc *replicationContext) Show(rw web.ResponseWriter, req *web.Request) {
+	res := map[string]interface{}{
+		"role": rc.config.Replication.Role,
+	}
+	if rc.standby != nil {
+		res["standby"] = rc.standby.Status()
+	}
+	rc.Render(map[string]interface{}{
+		"replication": res,
+	})
+}
+
+// Snapshot returns definitions of topologies created via the API and
+// checkpoints of their states. Standby servers periodically call this action.
+func (rc *replicationContext) Snapshot(rw web.ResponseWriter, req *web.Request) {
+	s, err := rc.recorder.Snapshot(func(name string) *core.Context {
+		tb, err := rc.topologies.Lookup(name)
+		if err != nil {
+			return nil
+		}
+		return tb.Topology().Context()
+	})
+	if err != nil {
+		rc.ErrLog(err).Error("Cannot create a snapshot")
+		rc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	rc.Render(map[string]interface{}{
+		"snapshot": s,
+	})
+}
+
+// Promote promotes a standby server. The server stops fetching snapshots and
+// restores topologies from the latest snapshot.
+func (rc *replicationContext) Promote(rw web.ResponseWriter, req *web.Request) {
+	if rc.standby == nil {
+		rc.Log().Error("The server isn't a standby server")
+		rc.RenderError(jasco.NewError(promotionErrorCode,
+			"The server isn't a standby server", http.StatusConflict, nil))
+		return
+	}
+
+	err := rc.standby.Promote()
+	switch err {
+	case nil:
+	case replication.ErrPromoted:
+		rc.ErrLog(err).Error("Cannot promote the server")
+		rc.RenderError(jasco.NewError(promotionErrorCode, err.Error(), http.StatusConflict, err))
+		return
+	case replication.ErrNoSnapshot:
+		rc.ErrLog(err).Error("Cannot promote the server")
+		rc.RenderError(jasco.NewError(promotionErrorCode, err.Error(), http.StatusConflict, err))
+		return
+	default:
+		// The server has been promoted but some topologies weren't restored
+		// correctly.
+		rc.ErrLog(err).Error("Cannot restore topologies completely")
+		rc.Render(map[string]interface{}{
+			"replication": map[string]interface{}{
+				"standby": rc.standby.Status(),
+			},
+			"warning": map[string]interface{}{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	rc.Render(map[string]interface{}{
+		"replication": map[string]interface{}{
+			"standby": rc.standby.Status(),
+		},
+	})
+}
+
+// restoreSnapshot creates topologies from a snapshot and records them so
+// that the server can be a new primary server. It continues restoring other
+// topologies when it fails to restore one of them.
+func restoreSnapshot(gvars *ContextGlobalVariables, us udf.UDSStorage, s *replication.Snapshot) error {
+	var errs []string
+	for _, ts := range s.Topologies {
+		l := gvars.Logger.WithField("topology", ts.Name)
+		l.Info("Restoring the topology from the snapshot")
+
+		tb, err := newTopologyBuilder(gvars.Logger, ts.Name, ts.Config, gvars.Config, us, gvars.webhooks)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cannot create topology '%v': %v", ts.Name, err))
+			continue
+		}
+		if err := gvars.Topologies.Register(ts.Name, tb); err != nil {
+			if err := tb.Topology().Stop(); err != nil {
+				l.WithField("err", err).Error("Cannot stop the topology")
+			}
+			if os.IsExist(err) {
+				err = errors.New("the name is already registered")
+			}
+			errs = append(errs, fmt.Sprintf("cannot register topology '%v': %v", ts.Name, err))
+			continue
+		}
+
+		if err := gvars.Recorder.Create(ts.Name, ts.Config); err != nil {
+			l.WithField("err", err).Error("Cannot persist the definition of the topology")
+		}
+		for _, stmt := range ts.Statements {
+			if err := gvars.Recorder.Append(ts.Name, stmt); err != nil {
+				l.WithField("err", err).Error("Cannot persist the statement")
+			}
+		}
+		if err := replication.RestoreTopology(tb, ts); err != nil {
+			l.WithField("err", err).Error("Cannot restore the topology completely")
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
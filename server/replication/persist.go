@@ -0,0 +1,191 @@
+package replication
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// recorderOp is an operation of a Recorder written to its log as a line of
+// JSON.
+type recorderOp struct {
+	// Op is one of "create", "append", or "remove".
+	Op       string   `json:"op"`
+	Topology string   `json:"topology"`
+	Config   data.Map `json:"config,omitempty"`
+	Stmt     string   `json:"stmt,omitempty"`
+}
+
+// recorderLog is an append-only log of operations of a Recorder.
+type recorderLog struct {
+	f *os.File
+}
+
+// OpenRecorder creates a recorder persisting definitions of topologies to a
+// file at the path so that they can be restored after the server restarts.
+// Definitions already in the file are loaded to the recorder. The file is
+// created when it doesn't exist.
+//
+// Every operation is appended to the file and synced before the method of
+// the recorder returns. The file is compacted when it's opened, so it only
+// grows while the server is running. A broken line at the end of the file,
+// which is written when the server crashes in the middle of writing it, is
+// discarded.
+func OpenRecorder(path string) (*Recorder, error) {
+	r := NewRecorder()
+	if err := r.replay(path); err != nil {
+		return nil, err
+	}
+	if err := r.compact(path); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	r.log = &recorderLog{f: f}
+	return r, nil
+}
+
+// replay applies operations in the file to the recorder.
+func (r *Recorder) replay(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		eof := err == io.EOF
+		if len(bytes.TrimSpace(line)) > 0 {
+			op := &recorderOp{}
+			if err := json.Unmarshal(line, op); err != nil {
+				if eof {
+					// The last line was partially written.
+					return nil
+				}
+				return fmt.Errorf("line %v of the topology log is broken: %v", lineNo, err)
+			}
+			if err := r.apply(op); err != nil {
+				return fmt.Errorf("line %v of the topology log is invalid: %v", lineNo, err)
+			}
+		}
+		if eof {
+			return nil
+		}
+	}
+}
+
+func (r *Recorder) apply(op *recorderOp) error {
+	switch op.Op {
+	case "create":
+		r.create(op.Topology, op.Config)
+	case "append":
+		r.append(op.Topology, op.Stmt)
+	case "remove":
+		delete(r.topologies, strings.ToLower(op.Topology))
+	default:
+		return fmt.Errorf("unknown operation '%v'", op.Op)
+	}
+	return nil
+}
+
+// compact rewrites the file only with operations necessary to create the
+// current definitions.
+func (r *Recorder) compact(path string) error {
+	defs := make([]*topologyDefinition, 0, len(r.topologies))
+	for _, d := range r.topologies {
+		defs = append(defs, d)
+	}
+	sort.Sort(definitionsByName(defs))
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	shouldRemove := true
+	defer func() {
+		if shouldRemove {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, d := range defs {
+		if err := enc.Encode(&recorderOp{Op: "create", Topology: d.name, Config: d.config}); err != nil {
+			return err
+		}
+		for _, stmt := range d.statements {
+			if err := enc.Encode(&recorderOp{Op: "append", Topology: d.name, Stmt: stmt}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	shouldRemove = false
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// write appends the operation to the log. It does nothing when the recorder
+// doesn't have a log. The caller must hold r.m.
+func (r *Recorder) write(op *recorderOp) error {
+	if r.log == nil {
+		return nil
+	}
+	if r.log.f == nil {
+		return errors.New("the recorder is already closed")
+	}
+	b, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	if _, err := r.log.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return r.log.f.Sync()
+}
+
+// Close closes the file of the recorder created by OpenRecorder. The
+// recorder keeps definitions in memory after it's closed, but new operations
+// cannot be persisted.
+func (r *Recorder) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.log == nil || r.log.f == nil {
+		return nil
+	}
+	err := r.log.f.Close()
+	r.log.f = nil
+	return err
+}
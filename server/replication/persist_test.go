@@ -0,0 +1,119 @@
+package replication
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestOpenRecorder(t *testing.T) {
+	Convey("Given a recorder persisting definitions to a file", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_recorder_test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		path := filepath.Join(dir, "topologies.log")
+
+		r, err := OpenRecorder(path)
+		So(err, ShouldBeNil)
+		So(r.Names(), ShouldBeEmpty)
+		So(r.Create("test1", data.Map{"a": data.Int(1)}), ShouldBeNil)
+		So(r.Append("test1", "CREATE STATE s TYPE test_counter;"), ShouldBeNil)
+		So(r.Create("test2", data.Map{}), ShouldBeNil)
+		So(r.Append("test2", "CREATE STATE t TYPE test_counter;"), ShouldBeNil)
+		So(r.Append("test1", "UPDATE STATE s SET num = 1;"), ShouldBeNil)
+		So(r.Append("unknown", "UPDATE STATE s SET num = 1;"), ShouldBeNil)
+
+		reopen := func() *Recorder {
+			So(r.Close(), ShouldBeNil)
+			r2, err := OpenRecorder(path)
+			So(err, ShouldBeNil)
+			return r2
+		}
+
+		Convey("When reopening the file", func() {
+			r2 := reopen()
+			Reset(func() {
+				r2.Close()
+			})
+
+			Convey("Then it should have the same definitions", func() {
+				So(r2.Names(), ShouldResemble, []string{"test1", "test2"})
+				conf, stmts, ok := r2.Definition("test1")
+				So(ok, ShouldBeTrue)
+				So(conf, ShouldResemble, data.Map{"a": data.Int(1)})
+				So(stmts, ShouldResemble, []string{
+					"CREATE STATE s TYPE test_counter;",
+					"UPDATE STATE s SET num = 1;",
+				})
+			})
+		})
+
+		Convey("When removing a topology and reopening the file", func() {
+			So(r.Remove("TEST1"), ShouldBeNil)
+			r2 := reopen()
+			Reset(func() {
+				r2.Close()
+			})
+
+			Convey("Then the topology shouldn't be restored", func() {
+				So(r2.Names(), ShouldResemble, []string{"test2"})
+			})
+
+			Convey("Then the file should be compacted", func() {
+				b, err := ioutil.ReadFile(path)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldNotContainSubstring, "test1")
+				So(strings.Count(string(b), "\n"), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the last line of the file is broken", func() {
+			So(r.Close(), ShouldBeNil)
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+			So(err, ShouldBeNil)
+			_, err = f.WriteString(`{"op":"append","topology":"test2","st`)
+			So(err, ShouldBeNil)
+			So(f.Close(), ShouldBeNil)
+
+			r2, err := OpenRecorder(path)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				r2.Close()
+			})
+
+			Convey("Then the line should be discarded", func() {
+				_, stmts, ok := r2.Definition("test2")
+				So(ok, ShouldBeTrue)
+				So(stmts, ShouldResemble, []string{"CREATE STATE t TYPE test_counter;"})
+			})
+		})
+
+		Convey("When a line in the middle of the file is broken", func() {
+			So(r.Close(), ShouldBeNil)
+			b, err := ioutil.ReadFile(path)
+			So(err, ShouldBeNil)
+			So(ioutil.WriteFile(path, append([]byte("{\n"), b...), 0600), ShouldBeNil)
+
+			Convey("Then it should fail", func() {
+				_, err := OpenRecorder(path)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the recorder is closed", func() {
+			So(r.Close(), ShouldBeNil)
+
+			Convey("Then new operations cannot be persisted", func() {
+				So(r.Create("test3", data.Map{}), ShouldNotBeNil)
+				So(r.Names(), ShouldContain, "test3")
+			})
+		})
+	})
+}
@@ -0,0 +1,296 @@
+package replication
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// counterState is a savable state having a number.
+type counterState struct {
+	m   sync.Mutex
+	num int64
+}
+
+func (s *counterState) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (s *counterState) Update(ctx *core.Context, params data.Map) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.num, _ = data.AsInt(params["num"])
+	return nil
+}
+
+func (s *counterState) Save(ctx *core.Context, w io.Writer, params data.Map) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return binary.Write(w, binary.LittleEndian, s.num)
+}
+
+func (s *counterState) value() int64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.num
+}
+
+type counterStateCreator struct{}
+
+func (counterStateCreator) CreateState(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	s := &counterState{}
+	s.num, _ = data.AsInt(params["num"])
+	return s, nil
+}
+
+func (counterStateCreator) LoadState(ctx *core.Context, r io.Reader, params data.Map) (core.SharedState, error) {
+	s := &counterState{}
+	if err := binary.Read(r, binary.LittleEndian, &s.num); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newTestTopologyBuilder(name string) *bql.TopologyBuilder {
+	tp, err := core.NewDefaultTopology(core.NewContext(nil), name)
+	So(err, ShouldBeNil)
+	tb, err := bql.NewTopologyBuilder(tp)
+	So(err, ShouldBeNil)
+	So(tb.UDSCreators.Register("test_counter", counterStateCreator{}), ShouldBeNil)
+	return tb
+}
+
+func addStmts(r *Recorder, tb *bql.TopologyBuilder, stmts ...string) {
+	for _, s := range stmts {
+		_, err := tb.AddStmt(mustParse(s))
+		So(err, ShouldBeNil)
+		r.Append(tb.Topology().Name(), s)
+	}
+}
+
+func counterValue(tb *bql.TopologyBuilder, name string) int64 {
+	st, err := tb.Topology().Context().SharedStates.Get(name)
+	So(err, ShouldBeNil)
+	return st.(*counterState).value()
+}
+
+func TestRecorderAndRestore(t *testing.T) {
+	Convey("Given a topology recorded by a recorder", t, func() {
+		r := NewRecorder()
+		tb := newTestTopologyBuilder("Test1")
+		Reset(func() {
+			tb.Topology().Stop()
+		})
+		r.Create("Test1", data.Map{"key": data.String("value")})
+		addStmts(r, tb,
+			`CREATE STATE c TYPE test_counter WITH num=1;`,
+			`UPDATE STATE c SET num=5;`,
+		)
+		r.Append("unknown", `CREATE STATE d TYPE test_counter;`)
+		contextOf := func(name string) *core.Context {
+			if name != "Test1" {
+				return nil
+			}
+			return tb.Topology().Context()
+		}
+
+		Convey("When creating a snapshot", func() {
+			s, err := r.Snapshot(contextOf)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the definition and states", func() {
+				So(len(s.Topologies), ShouldEqual, 1)
+				ts := s.Topologies[0]
+				So(ts.Name, ShouldEqual, "Test1")
+				So(ts.Config, ShouldResemble, data.Map{"key": data.String("value")})
+				So(len(ts.Statements), ShouldEqual, 2)
+				So(len(ts.States), ShouldEqual, 1)
+				So(ts.States[0].Name, ShouldEqual, "c")
+				So(ts.States[0].Type, ShouldEqual, "test_counter")
+			})
+
+			Convey("And restoring a topology from a JSON-encoded snapshot", func() {
+				b, err := json.Marshal(s)
+				So(err, ShouldBeNil)
+				var s2 Snapshot
+				So(json.Unmarshal(b, &s2), ShouldBeNil)
+
+				tb2 := newTestTopologyBuilder("Test1")
+				Reset(func() {
+					tb2.Topology().Stop()
+				})
+				So(RestoreTopology(tb2, s2.Topologies[0]), ShouldBeNil)
+
+				Convey("Then the state should have the latest value", func() {
+					So(counterValue(tb2, "c"), ShouldEqual, 5)
+				})
+			})
+
+			Convey("And restoring a topology from statements in the reverse order", func() {
+				ts := s.Topologies[0]
+				ts.Statements = []string{ts.Statements[1], ts.Statements[0]}
+				tb2 := newTestTopologyBuilder("Test1")
+				Reset(func() {
+					tb2.Topology().Stop()
+				})
+
+				Convey("Then it should succeed", func() {
+					So(RestoreTopology(tb2, ts), ShouldBeNil)
+					So(counterValue(tb2, "c"), ShouldEqual, 5)
+				})
+			})
+
+			Convey("And a statement cannot be issued while restoring", func() {
+				ts := s.Topologies[0]
+				ts.Statements = append([]string{`CREATE SOURCE s TYPE no_such_type;`}, ts.Statements...)
+				tb2 := newTestTopologyBuilder("Test1")
+				Reset(func() {
+					tb2.Topology().Stop()
+				})
+				err := RestoreTopology(tb2, ts)
+
+				Convey("Then it should fail after restoring the rest", func() {
+					So(err, ShouldNotBeNil)
+					So(counterValue(tb2, "c"), ShouldEqual, 5)
+				})
+			})
+		})
+
+		Convey("When the topology is removed", func() {
+			r.Remove("test1")
+			s, err := r.Snapshot(contextOf)
+			So(err, ShouldBeNil)
+
+			Convey("Then the snapshot shouldn't have it", func() {
+				So(s.Topologies, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestStandby(t *testing.T) {
+	Convey("Given a primary server and a standby", t, func() {
+		r := NewRecorder()
+		tb := newTestTopologyBuilder("test")
+		Reset(func() {
+			tb.Topology().Stop()
+		})
+		r.Create("test", nil)
+		addStmts(r, tb, `CREATE STATE c TYPE test_counter WITH num=3;`)
+
+		var m sync.Mutex
+		down := false
+		primary := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			m.Lock()
+			d := down
+			m.Unlock()
+			if d || req.URL.Path != "/api/v1/replication/snapshot" {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			s, err := r.Snapshot(func(string) *core.Context { return tb.Topology().Context() })
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(rw).Encode(map[string]interface{}{"snapshot": s})
+		}))
+		Reset(primary.Close)
+
+		var restored []*Snapshot
+		s := &Standby{
+			Primary:  primary.URL,
+			Interval: 10 * time.Millisecond,
+			Restore: func(s *Snapshot) error {
+				m.Lock()
+				defer m.Unlock()
+				restored = append(restored, s)
+				return nil
+			},
+		}
+		Reset(s.Stop)
+
+		Convey("When promoting it before fetching a snapshot", func() {
+			err := s.Promote()
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldEqual, ErrNoSnapshot)
+			})
+		})
+
+		Convey("When it fetches a snapshot", func() {
+			So(s.Sync(), ShouldBeNil)
+
+			Convey("Then the status should have the snapshot", func() {
+				st := s.Status()
+				So(st["promoted"], ShouldBeFalse)
+				So(st, ShouldContainKey, "last_sync")
+				So(st["snapshot"].(map[string]interface{})["topologies"], ShouldResemble, []string{"test"})
+			})
+
+			Convey("And it's promoted", func() {
+				So(s.Promote(), ShouldBeNil)
+
+				Convey("Then topologies should be restored from the snapshot", func() {
+					So(len(restored), ShouldEqual, 1)
+					So(restored[0].Topologies[0].Name, ShouldEqual, "test")
+					So(s.Status()["promoted"], ShouldBeTrue)
+				})
+
+				Convey("Then it cannot be promoted again", func() {
+					So(s.Promote(), ShouldEqual, ErrPromoted)
+				})
+			})
+		})
+
+		Convey("When the primary is down", func() {
+			m.Lock()
+			down = true
+			m.Unlock()
+
+			Convey("Then fetching a snapshot should fail", func() {
+				So(s.Sync(), ShouldNotBeNil)
+				So(s.Status(), ShouldContainKey, "last_error")
+			})
+		})
+
+		Convey("When the primary goes down after the standby starts with a failover timeout", func() {
+			s.FailoverTimeout = 50 * time.Millisecond
+			s.Start()
+			for i := 0; i < 500 && s.Status()["snapshot"] == nil; i++ {
+				time.Sleep(time.Millisecond)
+			}
+			m.Lock()
+			down = true
+			m.Unlock()
+
+			Convey("Then the standby should promote itself", func() {
+				for i := 0; i < 500 && s.Status()["promoted"] == false; i++ {
+					time.Sleep(time.Millisecond)
+				}
+				So(s.Status()["promoted"], ShouldBeTrue)
+				m.Lock()
+				defer m.Unlock()
+				So(len(restored), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func mustParse(s string) interface{} {
+	stmts, err := parser.New().ParseStmts(s)
+	So(err, ShouldBeNil)
+	So(len(stmts), ShouldEqual, 1)
+	return stmts[0]
+}
@@ -0,0 +1,52 @@
+package replication
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// RestoreTopology issues statements in the snapshot to the topology and then
+// loads checkpoints of its states. It doesn't stop at a failure so that as
+// much of the topology as possible is restored, and it returns an error
+// describing all failures. Statements are reordered by parser.SortStmts, so
+// they don't have to be in the order of their dependencies.
+//
+// Because statements are issued before states are loaded, sources may emit
+// tuples to the topology before its states are restored. Create sources in
+// the PAUSED state and resume them later when it matters.
+func RestoreTopology(tb *bql.TopologyBuilder, s *TopologySnapshot) error {
+	var (
+		errs  []string
+		stmts []interface{}
+	)
+	p := parser.New()
+	for _, str := range s.Statements {
+		ss, err := p.ParseStmts(str)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cannot parse '%v': %v", str, err))
+			continue
+		}
+		stmts = append(stmts, ss...)
+	}
+	for _, stmt := range parser.SortStmts(stmts) {
+		if _, err := tb.AddStmt(stmt); err != nil {
+			errs = append(errs, fmt.Sprintf("cannot issue '%v': %v", stmt, err))
+		}
+	}
+
+	for _, st := range s.States {
+		if err := tb.LoadState(st.Type, st.Name, bytes.NewReader(st.Data), data.Map{}); err != nil {
+			errs = append(errs, fmt.Sprintf("cannot load state '%v': %v", st.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("cannot restore topology '%v' completely: %v", s.Name, strings.Join(errs, "; "))
+	}
+	return nil
+}
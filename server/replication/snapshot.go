@@ -0,0 +1,239 @@
+// Package replication implements active/standby replication of topologies.
+// A primary server records definitions of topologies created via its API,
+// i.e. the statements issued to them, and serves snapshots having the
+// definitions and checkpoints of their states. A standby server periodically
+// fetches a snapshot from the primary server and restores topologies from
+// the latest snapshot when it's promoted.
+//
+// Topologies defined in the config file aren't replicated. The standby
+// server should have the same config file so that it creates them on
+// startup.
+package replication
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// Snapshot has definitions of topologies and checkpoints of their states.
+type Snapshot struct {
+	// CreatedAt is the time when the snapshot was created on the primary.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Topologies has snapshots of topologies sorted by their names.
+	Topologies []*TopologySnapshot `json:"topologies"`
+}
+
+// TopologySnapshot has a definition of a topology and checkpoints of its
+// states.
+type TopologySnapshot struct {
+	// Name is the name of the topology.
+	Name string `json:"name"`
+
+	// Config is the config of the topology given when it was created.
+	Config data.Map `json:"config,omitempty"`
+
+	// Statements is BQL statements issued to the topology in order.
+	Statements []string `json:"statements"`
+
+	// States has checkpoints of savable states in the topology.
+	States []*StateCheckpoint `json:"states"`
+}
+
+// StateCheckpoint has data of a state written by its Save method.
+type StateCheckpoint struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Data []byte `json:"data"`
+}
+
+// CheckpointStates saves all savable states in the context. States which
+// cannot be saved are skipped.
+func CheckpointStates(ctx *core.Context) ([]*StateCheckpoint, error) {
+	states, err := ctx.SharedStates.List()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*StateCheckpoint{}
+	for name, st := range states {
+		s, ok := st.(core.SavableSharedState)
+		if !ok {
+			continue
+		}
+		typeName, err := ctx.SharedStates.Type(name)
+		if err != nil {
+			return nil, err
+		}
+		b := bytes.NewBuffer(nil)
+		if err := s.Save(ctx, b, data.Map{}); err != nil {
+			return nil, fmt.Errorf("cannot save state '%v': %v", name, err)
+		}
+		res = append(res, &StateCheckpoint{
+			Name: name,
+			Type: typeName,
+			Data: b.Bytes(),
+		})
+	}
+	sort.Sort(checkpointsByName(res))
+	return res, nil
+}
+
+type checkpointsByName []*StateCheckpoint
+
+func (c checkpointsByName) Len() int           { return len(c) }
+func (c checkpointsByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+func (c checkpointsByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// Recorder records definitions of topologies on the primary server.
+//
+// Recorder is thread-safe.
+type Recorder struct {
+	m sync.Mutex
+
+	// topologies maps the lowercased name of a topology to its definition.
+	topologies map[string]*topologyDefinition
+
+	// log persists definitions. It's nil when the recorder only keeps them
+	// in memory.
+	log *recorderLog
+}
+
+type topologyDefinition struct {
+	name       string
+	config     data.Map
+	statements []string
+}
+
+// NewRecorder creates a new recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		topologies: map[string]*topologyDefinition{},
+	}
+}
+
+// Create records a new topology. It overwrites the previous definition of a
+// topology having the same name. It returns an error when the recorder
+// cannot persist the definition, but the definition is still recorded in
+// memory.
+func (r *Recorder) Create(name string, config data.Map) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.create(name, config)
+	return r.write(&recorderOp{Op: "create", Topology: name, Config: config})
+}
+
+func (r *Recorder) create(name string, config data.Map) {
+	r.topologies[strings.ToLower(name)] = &topologyDefinition{
+		name:   name,
+		config: config.Copy(),
+	}
+}
+
+// Append records a statement issued to a topology. It's ignored when the
+// topology isn't recorded. Like Create, it returns an error when the
+// statement cannot be persisted.
+func (r *Recorder) Append(topology, stmt string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if !r.append(topology, stmt) {
+		return nil
+	}
+	return r.write(&recorderOp{Op: "append", Topology: topology, Stmt: stmt})
+}
+
+func (r *Recorder) append(topology, stmt string) bool {
+	d, ok := r.topologies[strings.ToLower(topology)]
+	if ok {
+		d.statements = append(d.statements, stmt)
+	}
+	return ok
+}
+
+// Names returns names of recorded topologies in sorted order.
+func (r *Recorder) Names() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	names := make([]string, 0, len(r.topologies))
+	for _, d := range r.topologies {
+		names = append(names, d.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definition returns the config and the statements of a recorded topology.
+// It returns false when the topology isn't recorded.
+func (r *Recorder) Definition(topology string) (data.Map, []string, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	d, ok := r.topologies[strings.ToLower(topology)]
+	if !ok {
+		return nil, nil, false
+	}
+	return d.config.Copy(), append([]string{}, d.statements...), true
+}
+
+// Remove removes the definition of a topology. Like Create, it returns an
+// error when the removal cannot be persisted.
+func (r *Recorder) Remove(topology string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if _, ok := r.topologies[strings.ToLower(topology)]; !ok {
+		return nil
+	}
+	delete(r.topologies, strings.ToLower(topology))
+	return r.write(&recorderOp{Op: "remove", Topology: topology})
+}
+
+// Snapshot creates a snapshot of recorded topologies. contextOf returns the
+// context of a topology, which is used to checkpoint its states. A topology
+// is skipped when contextOf returns nil, e.g. when it has been removed.
+func (r *Recorder) Snapshot(contextOf func(name string) *core.Context) (*Snapshot, error) {
+	r.m.Lock()
+	defs := make([]*topologyDefinition, 0, len(r.topologies))
+	for _, d := range r.topologies {
+		defs = append(defs, &topologyDefinition{
+			name:       d.name,
+			config:     d.config,
+			statements: append([]string{}, d.statements...),
+		})
+	}
+	r.m.Unlock()
+	sort.Sort(definitionsByName(defs))
+
+	s := &Snapshot{
+		CreatedAt:  time.Now(),
+		Topologies: []*TopologySnapshot{},
+	}
+	for _, d := range defs {
+		ctx := contextOf(d.name)
+		if ctx == nil {
+			continue
+		}
+		states, err := CheckpointStates(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot checkpoint topology '%v': %v", d.name, err)
+		}
+		s.Topologies = append(s.Topologies, &TopologySnapshot{
+			Name:       d.name,
+			Config:     d.config,
+			Statements: d.statements,
+			States:     states,
+		})
+	}
+	return s, nil
+}
+
+type definitionsByName []*topologyDefinition
+
+func (d definitionsByName) Len() int           { return len(d) }
+func (d definitionsByName) Less(i, j int) bool { return d[i].name < d[j].name }
+func (d definitionsByName) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
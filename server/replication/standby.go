@@ -0,0 +1,264 @@
+package replication
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNoSnapshot is returned when a standby server is promoted before it
+	// fetches any snapshot from the primary server.
+	ErrNoSnapshot = errors.New("no snapshot has been fetched from the primary server")
+
+	// ErrPromoted is returned when a standby server is promoted twice.
+	ErrPromoted = errors.New("the server has already been promoted")
+)
+
+// Standby periodically fetches snapshots from the primary server and restores
+// topologies from the latest snapshot when it's promoted.
+//
+// Standby is thread-safe.
+type Standby struct {
+	// Primary is the base URL of the primary server.
+	Primary string
+
+	// Interval is the interval of fetching snapshots.
+	Interval time.Duration
+
+	// FailoverTimeout is the time after which the standby promotes itself when
+	// it cannot fetch a snapshot. The standby is only promoted by Promote
+	// when it's 0.
+	FailoverTimeout time.Duration
+
+	// Restore creates topologies from a snapshot. It's called once when the
+	// standby is promoted.
+	Restore func(s *Snapshot) error
+
+	// Logger is used to report failures. Nothing is logged when it's nil.
+	Logger *logrus.Logger
+
+	// HTTPClient is used to fetch snapshots. http.DefaultClient is used when
+	// it's nil.
+	HTTPClient *http.Client
+
+	m            sync.Mutex
+	snapshot     *Snapshot
+	lastSync     time.Time
+	lastAttempt  time.Time
+	lastError    error
+	promoted     bool
+	promotedAt   time.Time
+	promoteError error
+	stopCh       chan struct{}
+	stopped      chan struct{}
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// Sync fetches a snapshot from the primary server once.
+func (s *Standby) Sync() error {
+	snapshot, err := s.fetch()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.lastAttempt = s.timeNow()
+	s.lastError = err
+	if err != nil {
+		return err
+	}
+	s.snapshot = snapshot
+	s.lastSync = s.lastAttempt
+	return nil
+}
+
+func (s *Standby) fetch() (*Snapshot, error) {
+	c := s.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	res, err := c.Get(strings.TrimRight(s.Primary, "/") + "/api/v1/replication/snapshot")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(res.Body)
+		return nil, fmt.Errorf("the primary server returned an error (%v): %s", res.Status, b)
+	}
+	var body struct {
+		Snapshot *Snapshot `json:"snapshot"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("cannot decode the snapshot: %v", err)
+	}
+	if body.Snapshot == nil {
+		return nil, errors.New("the response doesn't have a snapshot")
+	}
+	return body.Snapshot, nil
+}
+
+// Promote stops fetching snapshots and restores topologies from the latest
+// snapshot. It fails when no snapshot has been fetched yet.
+func (s *Standby) Promote() error {
+	s.m.Lock()
+	if s.promoted {
+		s.m.Unlock()
+		return ErrPromoted
+	}
+	if s.snapshot == nil {
+		s.m.Unlock()
+		return ErrNoSnapshot
+	}
+	s.promoted = true
+	s.promotedAt = s.timeNow()
+	snapshot := s.snapshot
+	s.m.Unlock()
+
+	// Promote can be called from the sync goroutine, so it doesn't wait for
+	// the goroutine to stop.
+	s.stop(false)
+
+	err := s.Restore(snapshot)
+	s.m.Lock()
+	s.promoteError = err
+	s.m.Unlock()
+	return err
+}
+
+// Start starts fetching snapshots periodically in a separate goroutine.
+func (s *Standby) Start() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.stopCh != nil || s.promoted {
+		return
+	}
+	s.stopCh = make(chan struct{})
+	s.stopped = make(chan struct{})
+	stopCh, stopped := s.stopCh, s.stopped
+	started := s.timeNow()
+
+	go func() {
+		defer close(stopped)
+		t := time.NewTicker(s.Interval)
+		defer t.Stop()
+
+		failing := false
+		for {
+			if err := s.Sync(); err != nil {
+				if !failing && s.Logger != nil {
+					s.Logger.WithField("err", err).WithField("primary", s.Primary).
+						Error("Cannot fetch a snapshot from the primary server")
+				}
+				failing = true
+				if s.shouldFailover(started) {
+					if s.Logger != nil {
+						s.Logger.WithField("primary", s.Primary).
+							Warn("The primary server is down, promoting this server")
+					}
+					if err := s.Promote(); err != nil && s.Logger != nil {
+						s.Logger.WithField("err", err).Error("Cannot promote the server")
+					}
+					return
+				}
+			} else {
+				if failing && s.Logger != nil {
+					s.Logger.WithField("primary", s.Primary).
+						Info("Fetched a snapshot from the primary server again")
+				}
+				failing = false
+			}
+
+			select {
+			case <-t.C:
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// shouldFailover returns true when the standby has a snapshot and hasn't
+// fetched a new one within the failover timeout.
+func (s *Standby) shouldFailover(started time.Time) bool {
+	if s.FailoverTimeout <= 0 {
+		return false
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.snapshot == nil || s.promoted {
+		return false
+	}
+	last := s.lastSync
+	if last.Before(started) {
+		last = started
+	}
+	return s.timeNow().Sub(last) > s.FailoverTimeout
+}
+
+// Stop stops fetching snapshots.
+func (s *Standby) Stop() {
+	s.stop(true)
+}
+
+func (s *Standby) stop(wait bool) {
+	s.m.Lock()
+	stopCh, stopped := s.stopCh, s.stopped
+	s.stopCh = nil
+	s.m.Unlock()
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	if wait {
+		<-stopped
+	}
+}
+
+// Status returns the status of the standby.
+func (s *Standby) Status() map[string]interface{} {
+	s.m.Lock()
+	defer s.m.Unlock()
+	st := map[string]interface{}{
+		"primary":  s.Primary,
+		"promoted": s.promoted,
+	}
+	if !s.lastSync.IsZero() {
+		st["last_sync"] = s.lastSync
+	}
+	if s.lastError != nil {
+		st["last_error"] = s.lastError.Error()
+	}
+	if s.snapshot != nil {
+		names := make([]string, len(s.snapshot.Topologies))
+		for i, t := range s.snapshot.Topologies {
+			names[i] = t.Name
+		}
+		st["snapshot"] = map[string]interface{}{
+			"created_at": s.snapshot.CreatedAt,
+			"topologies": names,
+		}
+	}
+	if s.promoted {
+		st["promoted_at"] = s.promotedAt
+		if s.promoteError != nil {
+			st["promote_error"] = s.promoteError.Error()
+		}
+	}
+	return st
+}
+
+func (s *Standby) timeNow() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,121 @@
+package response
+
+import (
+	"net/http"
+)
+
+// Error codes returned in Error.Code. A code is stable: it's never changed
+// nor reused for another kind of error so that clients can branch on it.
+const (
+	// ResourceNotFoundErrorCode means that the request URI was correct but
+	// the requested resource was not found.
+	ResourceNotFoundErrorCode = "E0001"
+
+	// FormValidationErrorCode means that validation of the request body
+	// failed. Meta has a slice of error messages for each invalid field.
+	FormValidationErrorCode = "E0005"
+
+	// BQLStmtParseErrorCode means that a statement cannot be parsed. Meta has
+	// "parse_errors" and "statement".
+	BQLStmtParseErrorCode = "E0006"
+
+	// BQLStmtProcessingErrorCode means that a statement cannot be processed.
+	// Meta has "error" and "statement".
+	BQLStmtProcessingErrorCode = "E0007"
+
+	// NonWebSocketRequestErrorCode means that the action only supports
+	// WebSocket and the request is a regular HTTP request.
+	NonWebSocketRequestErrorCode = "E0008"
+
+	// WorkerUnavailableErrorCode means that no worker of a cluster can run a
+	// new topology or the worker running the requested topology is down.
+	WorkerUnavailableErrorCode = "E0009"
+
+	// PromotionErrorCode means that a standby server of replication cannot
+	// be promoted.
+	PromotionErrorCode = "E0010"
+
+	// ProtectedNodeErrorCode means that a statement drops or alters a node
+	// or a state protected by another owner. Meta has the same fields as
+	// BQLStmtProcessingErrorCode.
+	ProtectedNodeErrorCode = "E0011"
+
+	// TooManyStatementsErrorCode means that a statement isn't admitted
+	// because the topology is already running the maximum number of
+	// statements. Meta has the same fields as BQLStmtProcessingErrorCode.
+	TooManyStatementsErrorCode = "E0012"
+
+	// CheckpointDisabledErrorCode means that checkpoints aren't enabled in
+	// the config.
+	CheckpointDisabledErrorCode = "E0013"
+
+	// AuthenticationRequiredErrorCode means that the request doesn't have
+	// credentials.
+	AuthenticationRequiredErrorCode = "E0014"
+
+	// InvalidCredentialsErrorCode means that credentials in the request
+	// aren't accepted.
+	InvalidCredentialsErrorCode = "E0015"
+
+	// ResourceConflictErrorCode means that the request conflicts with the
+	// current state of the resource, e.g. a SELECT statement being streamed
+	// to another client is resumed.
+	ResourceConflictErrorCode = "E0016"
+
+	// IdempotencyKeyReusedErrorCode means that the idempotency key of the
+	// request was already used for a request having a different body.
+	IdempotencyKeyReusedErrorCode = "E0017"
+
+	// PermissionDeniedErrorCode means that the role of the request isn't
+	// allowed to perform the action, e.g. a read-only user creates a
+	// stream. Meta has "role".
+	PermissionDeniedErrorCode = "E0018"
+)
+
+// ErrorCode describes an error code in the catalog.
+type ErrorCode struct {
+	// Code is the code returned in Error.Code.
+	Code string `json:"code"`
+
+	// Status is the HTTP status code of responses having the code.
+	Status int `json:"status"`
+
+	// Retriable is true when the same request can succeed later without
+	// any change, e.g. after a worker recovers.
+	Retriable bool `json:"retriable"`
+
+	// Description describes when the error happens.
+	Description string `json:"description"`
+}
+
+// ErrorCodes is the catalog of all error codes returned from the server
+// sorted by codes. Codes not in the catalog can be returned by the web
+// framework, e.g. for internal server errors.
+var ErrorCodes = []*ErrorCode{
+	{ResourceNotFoundErrorCode, http.StatusNotFound, false, "The requested resource was not found."},
+	{FormValidationErrorCode, http.StatusBadRequest, false, "The request body is invalid."},
+	{BQLStmtParseErrorCode, http.StatusBadRequest, false, "A BQL statement cannot be parsed."},
+	{BQLStmtProcessingErrorCode, http.StatusBadRequest, false, "A BQL statement cannot be processed."},
+	{NonWebSocketRequestErrorCode, http.StatusBadRequest, false, "The action only supports WebSocket."},
+	{WorkerUnavailableErrorCode, http.StatusServiceUnavailable, true, "No worker of the cluster is available."},
+	{PromotionErrorCode, http.StatusConflict, false, "The server cannot be promoted."},
+	{ProtectedNodeErrorCode, http.StatusForbidden, false, "The node or the state is protected by its owner."},
+	{TooManyStatementsErrorCode, http.StatusTooManyRequests, true, "The topology is running too many statements."},
+	{CheckpointDisabledErrorCode, http.StatusBadRequest, false, "Checkpoints aren't enabled."},
+	{AuthenticationRequiredErrorCode, http.StatusUnauthorized, false, "Authentication is required."},
+	{InvalidCredentialsErrorCode, http.StatusForbidden, false, "The credentials aren't accepted."},
+	{ResourceConflictErrorCode, http.StatusConflict, false, "The request conflicts with the state of the resource."},
+	{IdempotencyKeyReusedErrorCode, http.StatusUnprocessableEntity, false, "The idempotency key was already used for another request."},
+	{PermissionDeniedErrorCode, http.StatusForbidden, false, "The role of the request isn't allowed to perform the action."},
+}
+
+// LookupErrorCode returns the description of the code. It returns nil when
+// the code isn't in the catalog.
+func LookupErrorCode(code string) *ErrorCode {
+	for _, c := range ErrorCodes {
+		if c.Code == code {
+			return c
+		}
+	}
+	return nil
+}
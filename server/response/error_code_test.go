@@ -0,0 +1,37 @@
+package response
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorCodes(t *testing.T) {
+	Convey("Given the catalog of error codes", t, func() {
+		Convey("Then codes should be unique and sorted", func() {
+			for i := 1; i < len(ErrorCodes); i++ {
+				So(ErrorCodes[i-1].Code, ShouldBeLessThan, ErrorCodes[i].Code)
+			}
+		})
+
+		Convey("Then every code should have a status and a description", func() {
+			for _, c := range ErrorCodes {
+				So(c.Status, ShouldBeGreaterThanOrEqualTo, 400)
+				So(c.Description, ShouldNotBeBlank)
+			}
+		})
+
+		Convey("When looking up a code", func() {
+			Convey("Then it should return its description", func() {
+				c := LookupErrorCode(TooManyStatementsErrorCode)
+				So(c, ShouldNotBeNil)
+				So(c.Status, ShouldEqual, 429)
+				So(c.Retriable, ShouldBeTrue)
+			})
+
+			Convey("Then it should return nil for an unknown code", func() {
+				So(LookupErrorCode("E9999"), ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,142 @@
+package response
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// TopologyGraph is a graph of nodes and edges of a topology. Its JSON
+// representation has "nodes" and "links" arrays so that it can directly be
+// given to D3's force layout.
+type TopologyGraph struct {
+	Name  string       `json:"name"`
+	Nodes []*GraphNode `json:"nodes"`
+	Links []*GraphLink `json:"links"`
+}
+
+// GraphNode is a node of a TopologyGraph.
+type GraphNode struct {
+	ID       string            `json:"id"`
+	NodeType string            `json:"node_type"`
+	State    string            `json:"state"`
+	Labels   map[string]string `json:"labels,omitempty"`
+
+	// NumReceived is the number of tuples received from inputs. It's 0 for
+	// a source.
+	NumReceived int64 `json:"num_received"`
+
+	// NumSent is the number of tuples sent to outputs. It's 0 for a sink.
+	NumSent int64 `json:"num_sent"`
+
+	NumErrors int64 `json:"num_errors"`
+}
+
+// GraphLink is an edge of a TopologyGraph. Source and Target are the IDs
+// of nodes.
+type GraphLink struct {
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	NumReceived int64  `json:"num_received"`
+	QueueSize   int64  `json:"queue_size"`
+	NumQueued   int64  `json:"num_queued"`
+}
+
+// NewTopologyGraph creates a graph of the topology from the statuses of its
+// nodes. Nodes and links are sorted by their names so that the result is
+// stable.
+func NewTopologyGraph(t core.Topology) *TopologyGraph {
+	g := &TopologyGraph{
+		Name:  t.Name(),
+		Nodes: []*GraphNode{},
+		Links: []*GraphLink{},
+	}
+
+	nodes := t.Nodes()
+	names := make([]string, 0, len(nodes))
+	for n := range nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		node := nodes[n]
+		st := node.Status()
+		gn := &GraphNode{
+			ID:       node.Name(),
+			NodeType: node.Type().String(),
+			State:    node.State().Get().String(),
+			Labels:   node.Labels(),
+		}
+		if out, ok := st["output_stats"].(data.Map); ok {
+			gn.NumSent = graphInt(out, "num_sent_total")
+		}
+		in, ok := st["input_stats"].(data.Map)
+		if !ok {
+			g.Nodes = append(g.Nodes, gn)
+			continue
+		}
+		gn.NumReceived = graphInt(in, "num_received_total")
+		gn.NumErrors = graphInt(in, "num_errors")
+		g.Nodes = append(g.Nodes, gn)
+
+		inputs, _ := in["inputs"].(data.Map)
+		srcs := make([]string, 0, len(inputs))
+		for s := range inputs {
+			srcs = append(srcs, s)
+		}
+		sort.Strings(srcs)
+		for _, s := range srcs {
+			is, _ := inputs[s].(data.Map)
+			g.Links = append(g.Links, &GraphLink{
+				Source:      s,
+				Target:      node.Name(),
+				NumReceived: graphInt(is, "num_received"),
+				QueueSize:   graphInt(is, "queue_size"),
+				NumQueued:   graphInt(is, "num_queued"),
+			})
+		}
+	}
+	return g
+}
+
+func graphInt(m data.Map, key string) int64 {
+	v, ok := m[key]
+	if !ok {
+		return 0
+	}
+	i, err := data.AsInt(v)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// DOT returns the graph in the DOT language of Graphviz. Sources, boxes,
+// and sinks have different shapes, and each edge is labeled with the number
+// of tuples queued in it.
+func (g *TopologyGraph) DOT() string {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "digraph %q {\n", g.Name)
+	fmt.Fprintln(b, "  rankdir=LR;")
+	for _, n := range g.Nodes {
+		shape := "box"
+		switch n.NodeType {
+		case core.NTSource.String():
+			shape = "invhouse"
+		case core.NTSink.String():
+			shape = "house"
+		}
+		fmt.Fprintf(b, "  %q [shape=%v, label=%q];\n", n.ID, shape,
+			fmt.Sprintf("%v\n%v (%v)", n.ID, n.NodeType, n.State))
+	}
+	for _, l := range g.Links {
+		fmt.Fprintf(b, "  %q -> %q [label=%q];\n", l.Source, l.Target,
+			fmt.Sprintf("%v/%v", l.NumQueued, l.QueueSize))
+	}
+	fmt.Fprintln(b, "}")
+	return b.String()
+}
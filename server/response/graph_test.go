@@ -0,0 +1,98 @@
+package response
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+)
+
+type graphTestSource struct {
+	stop chan struct{}
+}
+
+func (s *graphTestSource) GenerateStream(ctx *core.Context, w core.Writer) error {
+	<-s.stop
+	return nil
+}
+
+func (s *graphTestSource) Stop(ctx *core.Context) error {
+	close(s.stop)
+	return nil
+}
+
+type graphTestSink struct{}
+
+func (graphTestSink) Write(ctx *core.Context, t *core.Tuple) error {
+	return nil
+}
+
+func (graphTestSink) Close(ctx *core.Context) error {
+	return nil
+}
+
+func TestTopologyGraph(t *testing.T) {
+	Convey("Given a topology with a source, a box, and a sink", t, func() {
+		ctx := core.NewContext(nil)
+		t, err := core.NewDefaultTopology(ctx, "graph")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			t.Stop()
+		})
+
+		_, err = t.AddSource("src", &graphTestSource{stop: make(chan struct{})}, nil)
+		So(err, ShouldBeNil)
+		bn, err := t.AddBox("box", core.BoxFunc(func(ctx *core.Context, t *core.Tuple, w core.Writer) error {
+			return w.Write(ctx, t)
+		}), nil)
+		So(err, ShouldBeNil)
+		So(bn.Input("src", nil), ShouldBeNil)
+		sn, err := t.AddSink("snk", graphTestSink{}, nil)
+		So(err, ShouldBeNil)
+		So(sn.Input("box", nil), ShouldBeNil)
+		So(sn.Input("src", nil), ShouldBeNil)
+
+		Convey("When creating a graph of the topology", func() {
+			g := NewTopologyGraph(t)
+
+			Convey("Then it should have all nodes", func() {
+				So(len(g.Nodes), ShouldEqual, 3)
+				So(g.Nodes[0].ID, ShouldEqual, "box")
+				So(g.Nodes[0].NodeType, ShouldEqual, "box")
+				So(g.Nodes[1].ID, ShouldEqual, "snk")
+				So(g.Nodes[1].NodeType, ShouldEqual, "sink")
+				So(g.Nodes[2].ID, ShouldEqual, "src")
+				So(g.Nodes[2].NodeType, ShouldEqual, "source")
+				So(g.Nodes[2].State, ShouldEqual, "running")
+			})
+
+			Convey("Then it should have all edges", func() {
+				So(len(g.Links), ShouldEqual, 3)
+				So(*g.Links[0], ShouldResemble, GraphLink{Source: "src", Target: "box", QueueSize: 1024})
+				So(*g.Links[1], ShouldResemble, GraphLink{Source: "box", Target: "snk", QueueSize: 1024})
+				So(*g.Links[2], ShouldResemble, GraphLink{Source: "src", Target: "snk", QueueSize: 1024})
+			})
+
+			Convey("Then it should be converted to JSON having nodes and links", func() {
+				b, err := json.Marshal(g)
+				So(err, ShouldBeNil)
+				var js map[string]interface{}
+				So(json.Unmarshal(b, &js), ShouldBeNil)
+				So(js["name"], ShouldEqual, "graph")
+				So(len(js["nodes"].([]interface{})), ShouldEqual, 3)
+				So(js["links"].([]interface{})[0].(map[string]interface{})["source"], ShouldEqual, "src")
+			})
+
+			Convey("Then it should be converted to DOT", func() {
+				dot := g.DOT()
+				So(dot, ShouldStartWith, `digraph "graph" {`)
+				So(dot, ShouldContainSubstring, `"src" [shape=invhouse`)
+				So(dot, ShouldContainSubstring, `"snk" [shape=house`)
+				So(dot, ShouldContainSubstring, `"src" -> "box" [label="0/1024"];`)
+				So(strings.Count(dot, "->"), ShouldEqual, 3)
+			})
+		})
+	})
+}
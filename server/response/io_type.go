@@ -0,0 +1,55 @@
+package response
+
+import (
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// IOType is a part of the response which is returned by the types action. It
+// describes a type of sources or sinks.
+type IOType struct {
+	Name string `json:"name"`
+
+	// Declared is true when the creator declares its parameters. Description
+	// and Params are empty otherwise.
+	Declared        bool       `json:"declared"`
+	Description     string     `json:"description,omitempty"`
+	Params          []*IOParam `json:"params,omitempty"`
+	AllowUndeclared bool       `json:"allow_undeclared,omitempty"`
+}
+
+// IOParam describes a parameter of a source or a sink.
+type IOParam struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type,omitempty"`
+	Required    bool       `json:"required"`
+	Default     data.Value `json:"default,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// NewIOType returns the description of the type having the parameter
+// schema. s can be nil.
+func NewIOType(name string, s *bql.ParamSchema) *IOType {
+	t := &IOType{
+		Name: name,
+	}
+	if s == nil {
+		return t
+	}
+	t.Declared = true
+	t.Description = s.Description
+	t.AllowUndeclared = s.AllowUndeclared
+	for _, p := range s.Params {
+		param := &IOParam{
+			Name:        p.Name,
+			Required:    p.Required,
+			Default:     p.Default,
+			Description: p.Description,
+		}
+		if p.Type != 0 {
+			param.Type = p.Type.String()
+		}
+		t.Params = append(t.Params, param)
+	}
+	return t
+}
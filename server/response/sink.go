@@ -7,20 +7,22 @@ import (
 
 // Sink is a part of the response which is returned by sinks' action.
 type Sink struct {
-	NodeType string      `json:"node_type"`
-	Name     string      `json:"name"`
-	State    string      `json:"state"`
-	Status   data.Map    `json:"status,omitempty"`
-	Meta     interface{} `json:"meta,omitempty"`
+	NodeType string            `json:"node_type"`
+	Name     string            `json:"name"`
+	State    string            `json:"state"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Status   data.Map          `json:"status,omitempty"`
+	Meta     interface{}       `json:"meta,omitempty"`
 }
 
 // NewSink returns the result of the sink node. It generates status and
-// meta information if detailed argument is true.
+// meta information if detailed argument is true. Labels are always included.
 func NewSink(sn core.SinkNode, detailed bool) *Sink {
 	s := &Sink{
 		NodeType: core.NTSink.String(),
 		Name:     sn.Name(),
 		State:    sn.State().Get().String(),
+		Labels:   sn.Labels(),
 	}
 
 	if detailed {
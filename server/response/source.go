@@ -7,20 +7,22 @@ import (
 
 // Source is a part of the response which is returned by sources' action.
 type Source struct {
-	NodeType string      `json:"node_type"`
-	Name     string      `json:"name"`
-	State    string      `json:"state"`
-	Status   data.Map    `json:"status,omitempty"`
-	Meta     interface{} `json:"meta,omitempty"`
+	NodeType string            `json:"node_type"`
+	Name     string            `json:"name"`
+	State    string            `json:"state"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Status   data.Map          `json:"status,omitempty"`
+	Meta     interface{}       `json:"meta,omitempty"`
 }
 
 // NewSource returns the result of the source node. It generates status and
-// meta information if detailed argument is true.
+// meta information if detailed argument is true. Labels are always included.
 func NewSource(sn core.SourceNode, detailed bool) *Source {
 	s := &Source{
 		NodeType: core.NTSource.String(),
 		Name:     sn.Name(),
 		State:    sn.State().Get().String(),
+		Labels:   sn.Labels(),
 	}
 
 	if detailed {
@@ -7,20 +7,22 @@ import (
 
 // Stream is a part of the response which is returned by streams' action.
 type Stream struct {
-	NodeType string      `json:"node_type"`
-	Name     string      `json:"name"`
-	State    string      `json:"state"`
-	Status   data.Map    `json:"status,omitempty"`
-	Meta     interface{} `json:"meta,omitempty"`
+	NodeType string            `json:"node_type"`
+	Name     string            `json:"name"`
+	State    string            `json:"state"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Status   data.Map          `json:"status,omitempty"`
+	Meta     interface{}       `json:"meta,omitempty"`
 }
 
 // NewStream returns the result of the box node. It generates status and
-// meta information if detailed argument is true.
+// meta information if detailed argument is true. Labels are always included.
 func NewStream(bn core.BoxNode, detailed bool) *Stream {
 	s := &Stream{
 		NodeType: core.NTBox.String(),
 		Name:     bn.Name(),
 		State:    bn.State().Get().String(),
+		Labels:   bn.Labels(),
 	}
 
 	if detailed {
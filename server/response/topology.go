@@ -8,6 +8,10 @@ import (
 type Topology struct {
 	// Name is the name of the topology.
 	Name string `json:"name"`
+
+	// Hibernated is true when the topology is hibernated because it's idle.
+	// A hibernated topology is restored when it's accessed.
+	Hibernated bool `json:"hibernated,omitempty"`
 }
 
 // NewTopology creates a new response of a topology.
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+var (
+	// runtimeMutex protects runtimeGOGC and runtimeBallast.
+	runtimeMutex sync.Mutex
+
+	// runtimeGOGC is the GOGC applied by applyRuntimeConfig. It's 0 when
+	// the config doesn't have it. The runtime doesn't provide a way to get
+	// the current value without changing it.
+	runtimeGOGC int
+
+	// runtimeBallast is the memory ballast allocated on startup. It's kept
+	// so that it won't be collected.
+	runtimeBallast []byte
+)
+
+// applyRuntimeConfig applies parameters of the Go runtime. Parameters which
+// aren't specified in the config are left unchanged.
+func applyRuntimeConfig(conf *config.Runtime, logger *logrus.Logger) {
+	if conf == nil {
+		return
+	}
+	runtimeMutex.Lock()
+	defer runtimeMutex.Unlock()
+
+	if conf.MaxProcs > 0 {
+		prev := runtime.GOMAXPROCS(conf.MaxProcs)
+		logger.WithField("prev", prev).Infof("Setting GOMAXPROCS to %v", conf.MaxProcs)
+	}
+	if conf.GOGC != 0 {
+		debug.SetGCPercent(conf.GOGC)
+		runtimeGOGC = conf.GOGC
+		logger.Infof("Setting GOGC to %v", conf.GOGC)
+	}
+	if conf.MemoryLimit > 0 {
+		debug.SetMemoryLimit(conf.MemoryLimit)
+		logger.Infof("Setting the memory limit to %v bytes", conf.MemoryLimit)
+	}
+	if conf.MemoryBallast > 0 {
+		runtimeBallast = make([]byte, conf.MemoryBallast)
+		logger.Infof("Allocating %v bytes of the memory ballast", conf.MemoryBallast)
+	}
+}
+
+// currentGOGC returns the GOGC currently used. It returns -1 when the
+// garbage collector is disabled.
+func currentGOGC() int {
+	runtimeMutex.Lock()
+	defer runtimeMutex.Unlock()
+	if runtimeGOGC != 0 {
+		return runtimeGOGC
+	}
+	switch v := os.Getenv("GOGC"); v {
+	case "":
+		return 100
+	case "off":
+		return -1
+	}
+	// Setting the same value again to read it.
+	p := debug.SetGCPercent(100)
+	debug.SetGCPercent(p)
+	return p
+}
+
+func runtimeBallastSize() int {
+	runtimeMutex.Lock()
+	defer runtimeMutex.Unlock()
+	return len(runtimeBallast)
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gocraft/web"
+)
+
+// partEncodingHeader is the HTTP header with which a client requests each
+// part of a multipart response of a SELECT statement to be compressed. The
+// server sets the same header to the response when it compresses parts.
+const partEncodingHeader = "SensorBee-Part-Encoding"
+
+// selectEncoding is the compression applied to a multipart response of a
+// SELECT statement.
+type selectEncoding int
+
+const (
+	// selectEncodingIdentity doesn't compress the response.
+	selectEncodingIdentity selectEncoding = iota
+
+	// selectEncodingStreamGzip compresses the whole response body including
+	// boundaries with gzip. The compressed stream is flushed after each part
+	// so that clients can decode tuples as soon as they're written.
+	selectEncodingStreamGzip
+
+	// selectEncodingPartGzip compresses the body of each part with gzip and
+	// sets "Content-Encoding: gzip" to its header. Boundaries and headers of
+	// parts are left uncompressed, so clients can split parts as usual and
+	// only decompress their bodies.
+	selectEncodingPartGzip
+)
+
+// parseSelectEncoding returns the encoding requested by the client. Parts are
+// compressed when SensorBee-Part-Encoding is gzip. Otherwise, the whole
+// stream is compressed when Accept-Encoding accepts gzip.
+func parseSelectEncoding(req *web.Request) selectEncoding {
+	if acceptsGzip(req.Header.Get(partEncodingHeader)) {
+		return selectEncodingPartGzip
+	}
+	if acceptsGzip(req.Header.Get("Accept-Encoding")) {
+		return selectEncodingStreamGzip
+	}
+	return selectEncodingIdentity
+}
+
+// acceptsGzip returns true when the list of encodings in the format of
+// Accept-Encoding has gzip whose quality value isn't 0.
+func acceptsGzip(h string) bool {
+	for _, e := range strings.Split(h, ",") {
+		params := strings.Split(e, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		if name != "gzip" && name != "x-gzip" {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			v, err := strconv.ParseFloat(p[2:], 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		return q > 0
+	}
+	return false
+}
+
+// headerLines returns header lines of the response which tell the client how
+// it's encoded.
+func (e selectEncoding) headerLines() []string {
+	switch e {
+	case selectEncodingStreamGzip:
+		return []string{"Content-Encoding: gzip", "Vary: Accept-Encoding"}
+	case selectEncodingPartGzip:
+		return []string{partEncodingHeader + ": gzip"}
+	}
+	return nil
+}
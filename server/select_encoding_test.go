@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/gocraft/web"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseSelectEncoding(t *testing.T) {
+	Convey("Given requests having encoding headers", t, func() {
+		enc := func(h map[string]string) selectEncoding {
+			req, err := http.NewRequest("POST", "/", nil)
+			So(err, ShouldBeNil)
+			for k, v := range h {
+				req.Header.Set(k, v)
+			}
+			return parseSelectEncoding(&web.Request{Request: req})
+		}
+
+		Convey("Then the encoding should be chosen from them", func() {
+			So(enc(nil), ShouldEqual, selectEncodingIdentity)
+			So(enc(map[string]string{"Accept-Encoding": "deflate, gzip"}), ShouldEqual, selectEncodingStreamGzip)
+			So(enc(map[string]string{"Accept-Encoding": "GZIP;q=0.5"}), ShouldEqual, selectEncodingStreamGzip)
+			So(enc(map[string]string{"Accept-Encoding": "gzip;q=0"}), ShouldEqual, selectEncodingIdentity)
+			So(enc(map[string]string{"Accept-Encoding": "br"}), ShouldEqual, selectEncodingIdentity)
+			So(enc(map[string]string{
+				"Accept-Encoding":  "gzip",
+				partEncodingHeader: "gzip",
+			}), ShouldEqual, selectEncodingPartGzip)
+		})
+	})
+}
+
+func TestSelectMultipartWriterEncoding(t *testing.T) {
+	Convey("Given a multipart writer", t, func() {
+		buf := bytes.NewBuffer(nil)
+		newWriter := func(enc selectEncoding) *selectMultipartWriter {
+			w := newSelectMultipartWriter(nil, nil, bufio.NewReadWriter(bufio.NewReader(buf), bufio.NewWriter(buf)), "", enc)
+			header := textproto.MIMEHeader{}
+			header.Add("Content-Type", "application/json")
+			So(w.writePart(header, `{"a":1}`), ShouldBeTrue)
+			So(w.writePart(header, `{"a":2}`), ShouldBeTrue)
+			return w
+		}
+		readParts := func(boundary string, body []byte) ([]string, []string) {
+			r := multipart.NewReader(bytes.NewReader(body), boundary)
+			var bodies, encodings []string
+			for {
+				p, err := r.NextPart()
+				if err != nil {
+					break
+				}
+				b, err := ioutil.ReadAll(p)
+				So(err, ShouldBeNil)
+				bodies = append(bodies, string(b))
+				encodings = append(encodings, p.Header.Get("Content-Encoding"))
+			}
+			return bodies, encodings
+		}
+
+		Convey("When compressing the whole stream", func() {
+			w := newWriter(selectEncodingStreamGzip)
+			So(w.mw.Close(), ShouldBeNil)
+			So(w.gz.Close(), ShouldBeNil)
+			So(w.bufrw.Flush(), ShouldBeNil)
+
+			Convey("Then the decompressed stream should have the parts", func() {
+				r, err := gzip.NewReader(buf)
+				So(err, ShouldBeNil)
+				body, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+				bodies, _ := readParts(w.boundary(), body)
+				So(bodies, ShouldResemble, []string{`{"a":1}`, `{"a":2}`})
+			})
+		})
+
+		Convey("When compressing each part", func() {
+			w := newWriter(selectEncodingPartGzip)
+			So(w.mw.Close(), ShouldBeNil)
+			So(w.bufrw.Flush(), ShouldBeNil)
+
+			Convey("Then each part should be compressed", func() {
+				bodies, encodings := readParts(w.boundary(), buf.Bytes())
+				So(encodings, ShouldResemble, []string{"gzip", "gzip"})
+				So(len(bodies), ShouldEqual, 2)
+				for i, b := range bodies {
+					r, err := gzip.NewReader(bytes.NewReader([]byte(b)))
+					So(err, ShouldBeNil)
+					js, err := ioutil.ReadAll(r)
+					So(err, ShouldBeNil)
+					So(string(js), ShouldEqual, []string{`{"a":1}`, `{"a":2}`}[i])
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/textproto"
+	"strings"
+)
+
+// selectMultipartWriter writes results of a SELECT statement to a hijacked
+// connection as a multipart response. All errors after writing the header
+// are logged at info level because they might be caused by the client
+// closing the connection.
+type selectMultipartWriter struct {
+	tc    *topologies
+	conn  net.Conn
+	bufrw *bufio.ReadWriter
+	mw    *multipart.Writer
+	stmt  string
+
+	enc selectEncoding
+
+	// gz compresses the whole stream when enc is selectEncodingStreamGzip,
+	// or each part when enc is selectEncodingPartGzip. gzBuf has the
+	// compressed body of a part in the latter case.
+	gz    *gzip.Writer
+	gzBuf bytes.Buffer
+
+	writeErr error
+
+	// disconnected is closed when the client closes the connection. readErr
+	// has the error which Read returned at that time and must not be
+	// accessed before disconnected is closed.
+	disconnected chan struct{}
+	readErr      error
+}
+
+func newSelectMultipartWriter(tc *topologies, conn net.Conn, bufrw *bufio.ReadWriter, stmt string, enc selectEncoding) *selectMultipartWriter {
+	w := &selectMultipartWriter{
+		tc:           tc,
+		conn:         conn,
+		bufrw:        bufrw,
+		stmt:         stmt,
+		enc:          enc,
+		disconnected: make(chan struct{}),
+	}
+	switch enc {
+	case selectEncodingStreamGzip:
+		w.gz = gzip.NewWriter(bufrw)
+		w.mw = multipart.NewWriter(w.gz)
+	case selectEncodingPartGzip:
+		w.gz = gzip.NewWriter(&w.gzBuf)
+		w.mw = multipart.NewWriter(bufrw)
+	default:
+		w.mw = multipart.NewWriter(bufrw)
+	}
+	return w
+}
+
+func (w *selectMultipartWriter) boundary() string {
+	return w.mw.Boundary()
+}
+
+// writeHeader writes the status line and header lines of the response. Header
+// lines describing the encoding are added to lines. It returns false when it
+// cannot be written.
+func (w *selectMultipartWriter) writeHeader(lines []string) bool {
+	lines = append(lines, w.enc.headerLines()...)
+	if _, err := w.bufrw.WriteString(strings.Join(append(lines, "\r\n"), "\r\n")); err != nil {
+		w.tc.ErrLog(err).Error("Cannot write a header to the hijacked connection")
+		return false
+	}
+	w.bufrw.Flush()
+	go w.watchConnection()
+	return true
+}
+
+// watchConnection blocks on reading the connection until the client closes
+// it or the connection is closed by close. A client isn't supposed to send
+// anything after the request, so data read here is discarded. Because
+// it doesn't rely on SetReadDeadline, disconnection is detected as soon as
+// Read fails on any platform.
+//
+// A client which half-closes the connection after sending the request is
+// regarded as disconnected as net/http does.
+func (w *selectMultipartWriter) watchConnection() {
+	buf := make([]byte, 1024)
+	for {
+		if _, err := w.bufrw.Read(buf); err != nil {
+			w.readErr = err
+			close(w.disconnected)
+			return
+		}
+	}
+}
+
+// disconnectedCh returns a channel which is closed when the client closes the
+// connection. It's only closed after writeHeader succeeds.
+func (w *selectMultipartWriter) disconnectedCh() <-chan struct{} {
+	return w.disconnected
+}
+
+// isDisconnected returns true when the client has closed the connection.
+func (w *selectMultipartWriter) isDisconnected() bool {
+	select {
+	case <-w.disconnected:
+		return true
+	default:
+		return false
+	}
+}
+
+// writePart writes a JSON object as a part of the response. It returns false
+// when it cannot be written.
+func (w *selectMultipartWriter) writePart(header textproto.MIMEHeader, js string) bool {
+	// TODO: don't forget to convert \n to \r\n when returning
+	// pretty-printed JSON objects.
+	body := []byte(js)
+	if w.enc == selectEncodingPartGzip {
+		w.gzBuf.Reset()
+		w.gz.Reset(&w.gzBuf)
+		if _, err := w.gz.Write(body); err != nil {
+			w.writeErr = err
+			return false
+		}
+		if err := w.gz.Close(); err != nil {
+			w.writeErr = err
+			return false
+		}
+		body = w.gzBuf.Bytes()
+		header.Set("Content-Encoding", "gzip")
+	}
+	header.Set("Content-Length", fmt.Sprint(len(body)))
+
+	p, err := w.mw.CreatePart(header)
+	if err != nil {
+		w.writeErr = err
+		return false
+	}
+	if _, err := p.Write(body); err != nil {
+		w.writeErr = err
+		return false
+	}
+	if w.enc == selectEncodingStreamGzip {
+		if err := w.gz.Flush(); err != nil {
+			w.writeErr = err
+			return false
+		}
+	}
+	if err := w.bufrw.Flush(); err != nil {
+		w.writeErr = err
+		return false
+	}
+	return true
+}
+
+// close finishes the multipart response and closes the connection.
+func (w *selectMultipartWriter) close() {
+	disconnected := w.isDisconnected()
+	if disconnected && w.readErr != io.EOF {
+		w.tc.ErrLog(w.readErr).Info("The connection may be closed from the client side")
+	}
+	if w.writeErr != nil {
+		w.tc.ErrLog(w.writeErr).Info("Cannot write contents to the hijacked connection")
+	}
+
+	if err := w.mw.Close(); err != nil {
+		if w.writeErr == nil && !disconnected { // log it only when the write err hasn't happend
+			w.tc.ErrLog(err).Info("Cannot finish the multipart response")
+		}
+	}
+	if w.enc == selectEncodingStreamGzip {
+		w.gz.Close()
+	}
+	w.bufrw.Flush()
+	w.conn.Close()
+
+	w.tc.Log().WithField("statement", w.stmt).Info("Finish streaming SELECT responses")
+}
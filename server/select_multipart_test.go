@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSelectMultipartWriterDisconnection(t *testing.T) {
+	Convey("Given a multipart writer on a connection", t, func() {
+		server, client := net.Pipe()
+		Reset(func() {
+			server.Close()
+			client.Close()
+		})
+		go io.Copy(ioutil.Discard, client)
+		w := newSelectMultipartWriter(nil, server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)),
+			"", selectEncodingIdentity)
+		So(w.writeHeader([]string{"HTTP/1.1 200 OK"}), ShouldBeTrue)
+
+		Convey("When the client doesn't close the connection", func() {
+			Convey("Then it shouldn't be regarded as disconnected", func() {
+				select {
+				case <-w.disconnectedCh():
+					So("disconnected", ShouldBeNil)
+				case <-time.After(10 * time.Millisecond):
+				}
+				So(w.isDisconnected(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When the client closes the connection", func() {
+			client.Close()
+
+			Convey("Then it should be detected", func() {
+				select {
+				case <-w.disconnectedCh():
+				case <-time.After(time.Second):
+					So("timeout", ShouldBeNil)
+				}
+				So(w.isDisconnected(), ShouldBeTrue)
+				So(w.readErr, ShouldEqual, io.EOF)
+			})
+		})
+	})
+}
@@ -0,0 +1,359 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocraft/web"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+const (
+	// resumeTokenHeader is the HTTP header having the token to resume a
+	// streaming SELECT statement.
+	resumeTokenHeader = "SensorBee-Resume-Token"
+
+	// resumeGapHeader is the HTTP header which is set to true when some
+	// tuples emitted while the client was disconnected were dropped.
+	resumeGapHeader = "SensorBee-Resume-Gap"
+
+	// sequenceHeader is the header of each part of a multipart response of
+	// a resumable SELECT statement. It has the sequence number of the tuple
+	// starting from 1.
+	sequenceHeader = "SensorBee-Sequence"
+)
+
+var errSelectAlreadyAttached = errors.New("the SELECT statement is being streamed to another client")
+
+// selectResumer keeps streaming SELECT statements whose clients have
+// disconnected for the grace period so that the clients can resume them
+// without recreating the statements and losing tuples in their windows.
+type selectResumer struct {
+	logger      *logrus.Logger
+	gracePeriod time.Duration
+	bufferSize  int
+
+	m       sync.Mutex
+	selects map[string]*resumableSelect
+}
+
+func newSelectResumer(logger *logrus.Logger, conf *config.SelectResume) *selectResumer {
+	return &selectResumer{
+		logger:      logger,
+		gracePeriod: time.Duration(conf.GracePeriod * float64(time.Second)),
+		bufferSize:  conf.BufferSize,
+		selects:     map[string]*resumableSelect{},
+	}
+}
+
+// resumableSelect is a streaming SELECT statement which can be resumed. It
+// keeps the latest tuples in a buffer including ones which have already been
+// sent so that they can be sent again when the client didn't receive them.
+//
+// While a client is attached, the statement is blocked when all tuples in
+// the buffer haven't been sent yet. While no client is attached, the oldest
+// tuple is dropped from the buffer instead.
+type resumableSelect struct {
+	resumer  *selectResumer
+	token    string
+	topology string
+	stmt     string
+	sn       core.SinkNode
+	notify   chan struct{}
+
+	m        sync.Mutex
+	c        *sync.Cond
+	buf      []*core.Tuple
+	first    int64 // the sequence number of buf[0]
+	next     int64 // the sequence number of the next tuple to be sent
+	attached bool
+	eos      bool
+	removed  bool
+	expiry   *time.Timer
+}
+
+// start makes the result of a SELECT statement resumable. The returned
+// statement is attached to the caller.
+func (s *selectResumer) start(topology, stmt string, sn core.SinkNode, ch <-chan *core.Tuple) (*resumableSelect, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, fmt.Errorf("cannot generate a resume token: %v", err)
+	}
+	r := &resumableSelect{
+		resumer:  s,
+		token:    hex.EncodeToString(b),
+		topology: topology,
+		stmt:     stmt,
+		sn:       sn,
+		notify:   make(chan struct{}, 1),
+		first:    1,
+		next:     1,
+		attached: true,
+	}
+	r.c = sync.NewCond(&r.m)
+
+	s.m.Lock()
+	s.selects[r.token] = r
+	s.m.Unlock()
+	go r.pump(ch)
+	return r, nil
+}
+
+// resume attaches the caller to the SELECT statement having the token. The
+// statement sends tuples following lastSeq. When lastSeq is negative, it
+// sends tuples which haven't been sent yet. gap is true when some of tuples
+// following lastSeq have already been dropped.
+func (s *selectResumer) resume(topology, token string, lastSeq int64) (r *resumableSelect, gap bool, err error) {
+	s.m.Lock()
+	r, ok := s.selects[token]
+	s.m.Unlock()
+	if !ok || !strings.EqualFold(r.topology, topology) {
+		return nil, false, core.NotExistError(fmt.Errorf("the resume token is invalid or expired"))
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.removed {
+		return nil, false, core.NotExistError(fmt.Errorf("the resume token is invalid or expired"))
+	}
+	if r.attached {
+		return nil, false, errSelectAlreadyAttached
+	}
+	if r.expiry != nil {
+		r.expiry.Stop()
+		r.expiry = nil
+	}
+	r.attached = true
+
+	if lastSeq >= 0 {
+		next := lastSeq + 1
+		if last := r.first + int64(len(r.buf)); next > last {
+			next = last
+		}
+		r.next = next
+	}
+	if r.next < r.first {
+		gap = true
+		r.next = r.first
+	}
+	return r, gap, nil
+}
+
+// detach is called when the client of the statement disconnects. The
+// statement is removed if it isn't resumed within the grace period.
+func (s *selectResumer) detach(r *resumableSelect) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.attached = false
+	r.c.Broadcast()
+	r.expiry = time.AfterFunc(s.gracePeriod, func() {
+		r.m.Lock()
+		attached := r.attached
+		r.m.Unlock()
+		if !attached {
+			s.remove(r)
+		}
+	})
+}
+
+// remove stops the statement and invalidates its token.
+func (s *selectResumer) remove(r *resumableSelect) {
+	s.m.Lock()
+	delete(s.selects, r.token)
+	s.m.Unlock()
+
+	r.m.Lock()
+	r.removed = true
+	r.buf = nil
+	r.c.Broadcast()
+	r.m.Unlock()
+
+	// pump keeps reading tuples until the sink is stopped.
+	if err := r.sn.Stop(); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"err":       err,
+			"topology":  r.topology,
+			"node_type": core.NTSink,
+			"node_name": r.sn.Name(),
+		}).Error("Cannot stop the temporary sink")
+	}
+}
+
+// pump reads tuples emitted by the statement into the buffer.
+func (r *resumableSelect) pump(ch <-chan *core.Tuple) {
+	size := r.resumer.bufferSize
+	for t := range ch {
+		r.m.Lock()
+		for r.attached && !r.removed && len(r.buf) >= size && r.first >= r.next {
+			r.c.Wait()
+		}
+		if r.removed {
+			r.m.Unlock()
+			continue
+		}
+		if len(r.buf) >= size {
+			r.buf[0] = nil
+			r.buf = r.buf[1:]
+			r.first++
+		}
+		r.buf = append(r.buf, t)
+		r.m.Unlock()
+		r.signal()
+	}
+
+	r.m.Lock()
+	r.eos = true
+	r.m.Unlock()
+	r.signal()
+}
+
+func (r *resumableSelect) signal() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pending returns tuples which haven't been sent yet and the sequence number
+// of the first one. eos is true when all tuples have been sent and the
+// statement has finished.
+func (r *resumableSelect) pending() (ts []*core.Tuple, seq int64, eos bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.removed {
+		return nil, r.next, true
+	}
+	if r.next < r.first {
+		r.next = r.first
+	}
+	ts = append(ts, r.buf[r.next-r.first:]...)
+	return ts, r.next, r.eos && len(ts) == 0
+}
+
+// sent marks the tuple having the sequence number as sent.
+func (r *resumableSelect) sent(seq int64) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.next = seq + 1
+	r.c.Broadcast()
+}
+
+// ResumeSelect resumes a streaming SELECT statement with the token issued
+// when the statement started. The response has the same format as the one
+// of the original SELECT statement. The last_seq query parameter has the
+// sequence number of the last tuple the client received.
+func (tc *topologies) ResumeSelect(rw web.ResponseWriter, req *web.Request) {
+	if tc.resumer == nil {
+		tc.Log().Error("Resuming SELECT statements is disabled")
+		tc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode, "Resuming SELECT statements is disabled",
+			http.StatusNotFound, nil))
+		return
+	}
+
+	lastSeq := int64(-1)
+	if s := req.URL.Query().Get("last_seq"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			err := fmt.Errorf("last_seq must be a non-negative integer: %v", s)
+			tc.ErrLog(err).Error("Invalid sequence number")
+			e := jasco.NewError(formValidationErrorCode, "The request query is invalid.",
+				http.StatusBadRequest, err)
+			e.Meta["last_seq"] = []string{err.Error()}
+			tc.RenderError(e)
+			return
+		}
+		lastSeq = n
+	}
+
+	r, gap, err := tc.resumer.resume(tc.topologyName, tc.PathParams().String("token", ""), lastSeq)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot resume the SELECT statement")
+		if core.IsNotExist(err) {
+			tc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode, "The SELECT statement doesn't exist",
+				http.StatusNotFound, err))
+		} else {
+			tc.RenderError(jasco.NewError(resourceConflictErrorCode, "The SELECT statement cannot be resumed",
+				http.StatusConflict, err))
+		}
+		return
+	}
+	tc.streamResumableSelect(rw, r, gap, parseSelectEncoding(req))
+}
+
+// streamResumableSelect writes results of the statement as a multipart
+// response compressed by enc. The statement is detached when the connection
+// is lost.
+func (tc *topologies) streamResumableSelect(rw web.ResponseWriter, r *resumableSelect, gap bool, enc selectEncoding) {
+	finished := false
+	defer func() {
+		if finished {
+			tc.resumer.remove(r)
+		} else {
+			tc.resumer.detach(r)
+		}
+	}()
+
+	conn, bufrw, err := rw.Hijack()
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot hijack a connection")
+		tc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	mw := newSelectMultipartWriter(tc, conn, bufrw, r.stmt, enc)
+	defer mw.close()
+
+	res := []string{
+		"HTTP/1.1 200 OK",
+		fmt.Sprintf(`Content-Type: multipart/mixed; boundary="%v"`, mw.boundary()),
+		fmt.Sprintf("%v: %v", resumeTokenHeader, r.token),
+	}
+	if gap {
+		res = append(res, fmt.Sprintf("%v: true", resumeGapHeader))
+	}
+	if !mw.writeHeader(res) {
+		return
+	}
+
+	tc.Log().WithField("statement", r.stmt).Info("Start streaming resumable SELECT responses")
+	header := textproto.MIMEHeader{}
+	header.Add("Content-Type", "application/json")
+	for {
+		if mw.isDisconnected() {
+			// tuples must not be marked as sent after the client has gone
+			return
+		}
+		ts, seq, eos := r.pending()
+		if eos {
+			finished = true
+			return
+		}
+		for i, t := range ts {
+			header.Set(sequenceHeader, fmt.Sprint(seq+int64(i)))
+			if !mw.writePart(header, t.Data.String()) {
+				return
+			}
+			r.sent(seq + int64(i))
+		}
+		if len(ts) > 0 {
+			continue
+		}
+
+		select {
+		case <-r.notify:
+		case <-mw.disconnectedCh():
+			return
+		}
+	}
+}
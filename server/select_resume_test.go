@@ -0,0 +1,304 @@
+package server
+
+import (
+	"io/ioutil"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+// fakeSinkNode is a temporary sink of a SELECT statement which only records
+// whether it's stopped.
+type fakeSinkNode struct {
+	core.SinkNode
+	stopped int32
+}
+
+func (s *fakeSinkNode) Name() string {
+	return "fake_sink"
+}
+
+func (s *fakeSinkNode) Stop() error {
+	atomic.StoreInt32(&s.stopped, 1)
+	return nil
+}
+
+func (s *fakeSinkNode) isStopped() bool {
+	return atomic.LoadInt32(&s.stopped) != 0
+}
+
+func newTestSelectResumer(gracePeriod float64, bufferSize int) *selectResumer {
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	return newSelectResumer(logger, &config.SelectResume{
+		GracePeriod: gracePeriod,
+		BufferSize:  bufferSize,
+	})
+}
+
+func resumeTestTuple(n int) *core.Tuple {
+	return core.NewTuple(data.Map{"n": data.Int(n)})
+}
+
+// tupleNumbers returns values of "n" of the tuples.
+func tupleNumbers(ts []*core.Tuple) []int64 {
+	ns := make([]int64, len(ts))
+	for i, t := range ts {
+		ns[i], _ = data.AsInt(t.Data["n"])
+	}
+	return ns
+}
+
+// waitPending waits until the statement has n pending tuples and returns
+// them.
+func waitPending(r *resumableSelect, n int) (ts []*core.Tuple, seq int64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ts, seq, _ = r.pending()
+		if len(ts) >= n || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitBuffered waits until the statement has read n tuples from its
+// channel in total.
+func waitBuffered(r *resumableSelect, n int64) int64 {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r.m.Lock()
+		read := r.first + int64(len(r.buf)) - 1
+		r.m.Unlock()
+		if read >= n || time.Now().After(deadline) {
+			return read
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSelectResumer(t *testing.T) {
+	Convey("Given a select resumer", t, func() {
+		s := newTestSelectResumer(60, 3)
+		sn := &fakeSinkNode{}
+		ch := make(chan *core.Tuple, 10)
+		Reset(func() {
+			close(ch)
+		})
+
+		Convey("When starting a resumable SELECT statement", func() {
+			r, err := s.start("Test", "SELECT RSTREAM * FROM s [RANGE 1 TUPLES];", sn, ch)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have a random resume token", func() {
+				So(r.token, ShouldHaveLength, 32)
+				So(regexp.MustCompile("^[0-9a-f]+$").MatchString(r.token), ShouldBeTrue)
+				r2, err := s.start("Test", "SELECT RSTREAM * FROM s [RANGE 1 TUPLES];", &fakeSinkNode{}, make(chan *core.Tuple))
+				So(err, ShouldBeNil)
+				So(r2.token, ShouldNotEqual, r.token)
+			})
+
+			Convey("Then it should be attached", func() {
+				_, _, err := s.resume("test", r.token, -1)
+				So(err, ShouldEqual, errSelectAlreadyAttached)
+			})
+
+			Convey("Then tuples should be pending with sequence numbers from 1", func() {
+				ch <- resumeTestTuple(1)
+				ch <- resumeTestTuple(2)
+				ts, seq := waitPending(r, 2)
+				So(tupleNumbers(ts), ShouldResemble, []int64{1, 2})
+				So(seq, ShouldEqual, 1)
+
+				Convey("And sent tuples shouldn't be pending", func() {
+					r.sent(1)
+					ts, seq, eos := r.pending()
+					So(tupleNumbers(ts), ShouldResemble, []int64{2})
+					So(seq, ShouldEqual, 2)
+					So(eos, ShouldBeFalse)
+				})
+			})
+
+			Convey("Then pump should block while the buffer is full of unsent tuples", func() {
+				for i := 1; i <= 5; i++ {
+					ch <- resumeTestTuple(i)
+				}
+				So(waitBuffered(r, 3), ShouldEqual, 3)
+				time.Sleep(10 * time.Millisecond)
+				So(waitBuffered(r, 3), ShouldEqual, 3)
+				ts, _ := waitPending(r, 3)
+				So(tupleNumbers(ts), ShouldResemble, []int64{1, 2, 3})
+
+				Convey("And it should resume when a tuple is sent", func() {
+					r.sent(1)
+					So(waitBuffered(r, 4), ShouldEqual, 4)
+					ts, seq, _ := r.pending()
+					So(tupleNumbers(ts), ShouldResemble, []int64{2, 3, 4})
+					So(seq, ShouldEqual, 2)
+				})
+			})
+
+			Convey("Then pending should report the end of the stream after all tuples are sent", func() {
+				ch2 := make(chan *core.Tuple, 1)
+				r, err := s.start("Test", "SELECT RSTREAM * FROM s [RANGE 1 TUPLES];", sn, ch2)
+				So(err, ShouldBeNil)
+				ch2 <- resumeTestTuple(1)
+				close(ch2)
+				ts, _ := waitPending(r, 1)
+				So(ts, ShouldHaveLength, 1)
+				_, _, eos := r.pending()
+				So(eos, ShouldBeFalse)
+
+				r.sent(1)
+				deadline := time.Now().Add(5 * time.Second)
+				for !eos && time.Now().Before(deadline) {
+					_, _, eos = r.pending()
+					time.Sleep(time.Millisecond)
+				}
+				So(eos, ShouldBeTrue)
+			})
+
+			Convey("And it's detached after sending some tuples", func() {
+				ch <- resumeTestTuple(1)
+				ch <- resumeTestTuple(2)
+				waitPending(r, 2)
+				r.sent(1)
+				s.detach(r)
+
+				Convey("Then pump should drop the oldest tuples instead of blocking", func() {
+					for i := 3; i <= 6; i++ {
+						ch <- resumeTestTuple(i)
+					}
+					So(waitBuffered(r, 6), ShouldEqual, 6)
+					r.m.Lock()
+					So(r.first, ShouldEqual, 4)
+					So(tupleNumbers(r.buf), ShouldResemble, []int64{4, 5, 6})
+					r.m.Unlock()
+
+					Convey("And resuming after a dropped tuple should report a gap", func() {
+						r2, gap, err := s.resume("test", r.token, 1)
+						So(err, ShouldBeNil)
+						So(r2, ShouldEqual, r)
+						So(gap, ShouldBeTrue)
+						ts, seq, _ := r.pending()
+						So(tupleNumbers(ts), ShouldResemble, []int64{4, 5, 6})
+						So(seq, ShouldEqual, 4)
+					})
+
+					Convey("And resuming without the last sequence number should report a gap", func() {
+						_, gap, err := s.resume("test", r.token, -1)
+						So(err, ShouldBeNil)
+						So(gap, ShouldBeTrue)
+					})
+
+					Convey("And resuming after a tuple in the buffer shouldn't report a gap", func() {
+						_, gap, err := s.resume("test", r.token, 4)
+						So(err, ShouldBeNil)
+						So(gap, ShouldBeFalse)
+						ts, seq, _ := r.pending()
+						So(tupleNumbers(ts), ShouldResemble, []int64{5, 6})
+						So(seq, ShouldEqual, 5)
+					})
+				})
+
+				Convey("Then resuming should send unsent tuples again", func() {
+					_, gap, err := s.resume("TEST", r.token, -1)
+					So(err, ShouldBeNil)
+					So(gap, ShouldBeFalse)
+					ts, seq, _ := r.pending()
+					So(tupleNumbers(ts), ShouldResemble, []int64{2})
+					So(seq, ShouldEqual, 2)
+				})
+
+				Convey("Then resuming with the last sequence number should send tuples following it", func() {
+					_, gap, err := s.resume("test", r.token, 0)
+					So(err, ShouldBeNil)
+					So(gap, ShouldBeFalse)
+					ts, seq, _ := r.pending()
+					So(tupleNumbers(ts), ShouldResemble, []int64{1, 2})
+					So(seq, ShouldEqual, 1)
+				})
+
+				Convey("Then resuming with a sequence number not sent yet should be clamped", func() {
+					_, gap, err := s.resume("test", r.token, 100)
+					So(err, ShouldBeNil)
+					So(gap, ShouldBeFalse)
+					ts, seq, _ := r.pending()
+					So(ts, ShouldBeEmpty)
+					So(seq, ShouldEqual, 3)
+				})
+
+				Convey("Then resuming it twice should fail", func() {
+					_, _, err := s.resume("test", r.token, -1)
+					So(err, ShouldBeNil)
+					_, _, err = s.resume("test", r.token, -1)
+					So(err, ShouldEqual, errSelectAlreadyAttached)
+				})
+
+				Convey("Then resuming it with a wrong topology or token should fail", func() {
+					_, _, err := s.resume("test2", r.token, -1)
+					So(core.IsNotExist(err), ShouldBeTrue)
+					_, _, err = s.resume("test", "0123456789abcdef0123456789abcdef", -1)
+					So(core.IsNotExist(err), ShouldBeTrue)
+				})
+			})
+		})
+	})
+
+	Convey("Given a select resumer having a short grace period", t, func() {
+		s := newTestSelectResumer(0.01, 3)
+		sn := &fakeSinkNode{}
+		ch := make(chan *core.Tuple, 10)
+		Reset(func() {
+			close(ch)
+		})
+		r, err := s.start("test", "SELECT RSTREAM * FROM s [RANGE 1 TUPLES];", sn, ch)
+		So(err, ShouldBeNil)
+
+		Convey("When the statement isn't resumed within the grace period", func() {
+			s.detach(r)
+			deadline := time.Now().Add(5 * time.Second)
+			for !sn.isStopped() && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			Convey("Then it should be removed and its sink should be stopped", func() {
+				So(sn.isStopped(), ShouldBeTrue)
+				_, _, err := s.resume("test", r.token, -1)
+				So(core.IsNotExist(err), ShouldBeTrue)
+				_, _, eos := r.pending()
+				So(eos, ShouldBeTrue)
+			})
+
+			Convey("Then pump should discard following tuples", func() {
+				ch <- resumeTestTuple(1)
+				time.Sleep(10 * time.Millisecond)
+				r.m.Lock()
+				So(r.buf, ShouldBeEmpty)
+				r.m.Unlock()
+			})
+		})
+
+		Convey("When the statement is resumed within the grace period", func() {
+			s.detach(r)
+			_, _, err := s.resume("test", r.token, -1)
+			So(err, ShouldBeNil)
+			time.Sleep(50 * time.Millisecond)
+
+			Convey("Then it shouldn't be removed", func() {
+				So(sn.isStopped(), ShouldBeFalse)
+				s.m.Lock()
+				_, ok := s.selects[r.token]
+				s.m.Unlock()
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
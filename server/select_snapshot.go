@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// maxSnapshotCollect is the maximum number of tuples collected by a
+	// SELECT statement in the snapshot mode.
+	maxSnapshotCollect = 10000
+
+	// defaultSnapshotTimeout is used when only collect is given.
+	defaultSnapshotTimeout = 10 * time.Second
+
+	// maxSnapshotTimeout is the maximum duration for which a SELECT statement
+	// collects tuples in the snapshot mode.
+	maxSnapshotTimeout = 5 * time.Minute
+)
+
+// selectSnapshotOptions has options of the snapshot mode of SELECT
+// statements. In the snapshot mode, the server collects results of a SELECT
+// statement until it has collect tuples or timeout has passed, and returns
+// them as a single JSON array instead of a multipart stream.
+type selectSnapshotOptions struct {
+	collect int
+	timeout time.Duration
+}
+
+// parseSelectSnapshotOptions returns options given by "collect" and "timeout"
+// query parameters of the request. It returns nil when neither of them is
+// given. timeout is either a duration like "5s" or the number of seconds.
+// It renders an error and returns false when a parameter is invalid.
+func (tc *topologies) parseSelectSnapshotOptions(req *web.Request) (*selectSnapshotOptions, bool) {
+	q := req.URL.Query()
+	c, t := q.Get("collect"), q.Get("timeout")
+	if c == "" && t == "" {
+		return nil, true
+	}
+
+	invalid := func(field string, err error) (*selectSnapshotOptions, bool) {
+		tc.ErrLog(err).Error("Invalid options of the snapshot mode")
+		e := jasco.NewError(formValidationErrorCode, "The request query is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta[field] = []string{err.Error()}
+		tc.RenderError(e)
+		return nil, false
+	}
+
+	opts := &selectSnapshotOptions{
+		collect: maxSnapshotCollect,
+		timeout: defaultSnapshotTimeout,
+	}
+	if c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n <= 0 || n > maxSnapshotCollect {
+			return invalid("collect", fmt.Errorf("collect must be an integer from 1 to %v: %v", maxSnapshotCollect, c))
+		}
+		opts.collect = n
+	}
+	if t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil {
+			s, serr := strconv.ParseFloat(t, 64)
+			if serr != nil {
+				return invalid("timeout", fmt.Errorf("timeout must be a duration like 5s or the number of seconds: %v", t))
+			}
+			d = time.Duration(s * float64(time.Second))
+		}
+		if d <= 0 || d > maxSnapshotTimeout {
+			return invalid("timeout", fmt.Errorf("timeout must be positive and at most %v: %v", maxSnapshotTimeout, t))
+		}
+		opts.timeout = d
+	}
+	return opts, true
+}
+
+// handleSelectSnapshot runs the SELECT statement in the snapshot mode.
+func (tc *topologies) handleSelectSnapshot(stmt parser.SelectUnionStmt, stmtStr string, opts *selectSnapshotOptions) {
+	tb := tc.fetchTopology()
+	if tb == nil { // just in case
+		return
+	}
+
+	sn, ch, err := tb.AddSelectUnionStmt(&stmt)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot process a statement")
+		e := stmtProcessingError(err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = stmtStr
+		tc.RenderError(e)
+		return
+	}
+	defer tc.stopTemporarySink(sn, ch)
+
+	tc.Log().WithField("statement", stmtStr).Info("Start collecting SELECT responses")
+	results := make([]data.Map, 0, opts.collect)
+	timer := time.NewTimer(opts.timeout)
+	defer timer.Stop()
+	timedOut := false
+collect:
+	for len(results) < opts.collect {
+		select {
+		case t, ok := <-ch:
+			if !ok {
+				break collect
+			}
+			results = append(results, t.Data)
+		case <-timer.C:
+			timedOut = true
+			break collect
+		}
+	}
+	tc.Log().WithField("statement", stmtStr).WithField("count", len(results)).
+		Info("Finish collecting SELECT responses")
+
+	tc.Render(map[string]interface{}{
+		"topology_name": tc.topologyName,
+		"results":       results,
+		"count":         len(results),
+		"timed_out":     timedOut,
+	})
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSelectSnapshot(t *testing.T) {
+	f, err := ioutil.TempFile("", "sbtest_select_snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{"a":1}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	Convey("Given an API server having a topology", t, func() {
+		s := newTestServer(data.Map{})
+		Reset(s.Close)
+		res, _ := s.request("POST", "/topologies", map[string]interface{}{"name": "test"})
+		So(res.StatusCode, ShouldEqual, http.StatusOK)
+
+		addQuery := func(q string) {
+			res, _ := s.request("POST", "/topologies/test/queries", map[string]interface{}{"queries": q})
+			So(res.StatusCode, ShouldEqual, http.StatusOK)
+		}
+		selectSnapshot := func(stream, params string) (*http.Response, map[string]interface{}) {
+			return s.request("POST", "/topologies/test/queries?"+params, map[string]interface{}{
+				"queries": fmt.Sprintf("SELECT RSTREAM * FROM %v [RANGE 1 TUPLES];", stream),
+			})
+		}
+
+		Convey("When a source emits tuples endlessly", func() {
+			addQuery(fmt.Sprintf(`CREATE SOURCE src TYPE file WITH path="%v", repeat=-1, interval=0.001;`, f.Name()))
+
+			Convey("Then the snapshot should stop at the collect limit", func() {
+				res, js := selectSnapshot("src", "collect=3&timeout=1m")
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js["topology_name"], ShouldEqual, "test")
+				So(js["count"], ShouldEqual, 3)
+				So(js["timed_out"], ShouldBeFalse)
+				results := js["results"].([]interface{})
+				So(results, ShouldHaveLength, 3)
+				So(results[0], ShouldResemble, map[string]interface{}{"a": 1.0})
+			})
+		})
+
+		Convey("When a source doesn't emit tuples", func() {
+			addQuery(fmt.Sprintf(`CREATE PAUSED SOURCE src TYPE file WITH path="%v";`, f.Name()))
+
+			Convey("Then the snapshot should stop at the timeout", func() {
+				start := time.Now()
+				res, js := selectSnapshot("src", "collect=3&timeout=0.1")
+				So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js["count"], ShouldEqual, 0)
+				So(js["timed_out"], ShouldBeTrue)
+				So(js["results"], ShouldBeEmpty)
+			})
+
+			Convey("Then the timeout should accept a duration", func() {
+				res, js := selectSnapshot("src", "timeout=50ms")
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js["timed_out"], ShouldBeTrue)
+			})
+		})
+
+		Convey("When options of the snapshot mode are invalid", func() {
+			addQuery(fmt.Sprintf(`CREATE PAUSED SOURCE src TYPE file WITH path="%v";`, f.Name()))
+
+			cases := []struct {
+				params string
+				field  string
+			}{
+				{"collect=0", "collect"},
+				{"collect=-1", "collect"},
+				{"collect=a", "collect"},
+				{fmt.Sprintf("collect=%v", maxSnapshotCollect+1), "collect"},
+				{"timeout=0", "timeout"},
+				{"timeout=-1s", "timeout"},
+				{"timeout=a", "timeout"},
+				{"timeout=6m", "timeout"},
+				{"collect=1&timeout=301", "timeout"},
+			}
+			for _, c := range cases {
+				c := c
+				Convey("Then the request with "+c.params+" should fail", func() {
+					res, js := selectSnapshot("src", c.params)
+					So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+					So(errorCode(js), ShouldEqual, formValidationErrorCode)
+					meta := js["error"].(map[string]interface{})["meta"].(map[string]interface{})
+					So(meta[c.field], ShouldHaveLength, 1)
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocraft/web"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// sseKeepAliveInterval is the interval of comments sent to keep an idle
+	// stream of server-sent events alive through proxies.
+	sseKeepAliveInterval = 15 * time.Second
+
+	// sseEndEvent is the event sent when the SELECT statement finishes. A
+	// client should close the connection on it because EventSource of
+	// browsers reconnects to the server otherwise.
+	sseEndEvent = "end"
+)
+
+// selectSSEWriter writes results of a SELECT statement as server-sent events.
+// Each tuple is written as a message event whose data is the JSON of the
+// tuple.
+type selectSSEWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func newSelectSSEWriter(w io.Writer, flusher http.Flusher) *selectSSEWriter {
+	return &selectSSEWriter{
+		w:       w,
+		flusher: flusher,
+	}
+}
+
+// writeEvent writes an event. The event name is omitted when it's empty so
+// that the event is dispatched as a message event.
+func (w *selectSSEWriter) writeEvent(event string, id int64, js string) error {
+	var b strings.Builder
+	if event != "" {
+		fmt.Fprintf(&b, "event: %v\n", event)
+	}
+	if id > 0 {
+		fmt.Fprintf(&b, "id: %v\n", id)
+	}
+	for _, l := range strings.Split(js, "\n") {
+		fmt.Fprintf(&b, "data: %v\n", l)
+	}
+	b.WriteString("\n")
+	return w.write(b.String())
+}
+
+// writeComment writes a comment, which is ignored by clients.
+func (w *selectSSEWriter) writeComment(c string) error {
+	return w.write(fmt.Sprintf(": %v\n\n", c))
+}
+
+func (w *selectSSEWriter) write(s string) error {
+	if _, err := io.WriteString(w.w, s); err != nil {
+		return err
+	}
+	w.flusher.Flush()
+	return nil
+}
+
+// stream writes tuples received from ch until ch is closed or done is
+// closed. A keep-alive comment is written when no tuple is written for
+// keepAlive. It returns true when all results were written.
+func (w *selectSSEWriter) stream(ch <-chan *core.Tuple, done <-chan struct{}, keepAlive time.Duration) (bool, error) {
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	var id int64
+	for {
+		select {
+		case t, ok := <-ch:
+			if !ok {
+				return true, w.writeEvent(sseEndEvent, 0, "{}")
+			}
+			id++
+			if err := w.writeEvent("", id, t.Data.String()); err != nil {
+				return false, err
+			}
+			ticker.Reset(keepAlive)
+		case <-ticker.C:
+			if err := w.writeComment("keep-alive"); err != nil {
+				return false, err
+			}
+		case <-done:
+			return false, nil
+		}
+	}
+}
+
+// SSEQueries streams results of a SELECT statement given by the queries
+// query parameter as server-sent events so that browsers can receive them
+// with EventSource.
+func (tc *topologies) SSEQueries(rw web.ResponseWriter, req *web.Request) {
+	tb := tc.fetchTopology()
+	if tb == nil {
+		return
+	}
+
+	form := data.Map{}
+	if qs, ok := req.URL.Query()["queries"]; ok {
+		form["queries"] = data.String(qs[0])
+	}
+	stmts, apiErr := tc.parseQueries(form)
+	if apiErr != nil {
+		tc.RenderError(apiErr)
+		return
+	}
+
+	var stmt parser.SelectUnionStmt
+	if len(stmts) == 1 {
+		switch s := stmts[0].(type) {
+		case parser.SelectStmt:
+			stmt = parser.SelectUnionStmt{Selects: []parser.SelectStmt{s}}
+		case parser.SelectUnionStmt:
+			stmt = s
+		}
+	}
+	if len(stmt.Selects) == 0 {
+		err := fmt.Errorf("only a SELECT statement can be streamed as server-sent events")
+		tc.ErrLog(err).Error("Cannot process a statement")
+		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = req.URL.Query().Get("queries")
+		tc.RenderError(e)
+		return
+	}
+	if e := tc.authorizeStmts(stmts); e != nil {
+		tc.RenderError(e)
+		return
+	}
+
+	stmtStr := fmt.Sprint(stmts[0])
+	sn, ch, err := tb.AddSelectUnionStmt(&stmt)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot process a statement")
+		e := stmtProcessingError(err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = stmtStr
+		tc.RenderError(e)
+		return
+	}
+	defer tc.stopTemporarySink(sn, ch)
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+	rw.Flush()
+
+	tc.Log().WithField("statement", stmtStr).Info("Start streaming SELECT responses as server-sent events")
+	finished, err := newSelectSSEWriter(rw, rw).stream(ch, req.Context().Done(), sseKeepAliveInterval)
+	if err != nil {
+		// The client might have closed the connection.
+		tc.ErrLog(err).Info("Cannot write server-sent events")
+	}
+	tc.Log().WithFields(logrus.Fields{
+		"statement": stmtStr,
+		"finished":  finished,
+	}).Info("Finish streaming SELECT responses as server-sent events")
+}
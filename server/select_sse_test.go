@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestSelectSSEWriter(t *testing.T) {
+	Convey("Given a server-sent events writer", t, func() {
+		rec := httptest.NewRecorder()
+		w := newSelectSSEWriter(rec, rec)
+		ch := make(chan *core.Tuple, 10)
+		done := make(chan struct{})
+
+		Convey("When the SELECT statement finishes", func() {
+			ch <- core.NewTuple(data.Map{"a": data.Int(1)})
+			ch <- core.NewTuple(data.Map{"a": data.Int(2)})
+			close(ch)
+			finished, err := w.stream(ch, done, time.Minute)
+			So(err, ShouldBeNil)
+
+			Convey("Then tuples should be written as events followed by the end event", func() {
+				So(finished, ShouldBeTrue)
+				So(rec.Body.String(), ShouldEqual, "id: 1\ndata: {\"a\":1}\n\n"+
+					"id: 2\ndata: {\"a\":2}\n\n"+
+					"event: end\ndata: {}\n\n")
+				So(rec.Flushed, ShouldBeTrue)
+			})
+		})
+
+		Convey("When no tuple is written for a while", func() {
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				close(done)
+			}()
+			finished, err := w.stream(ch, done, 10*time.Millisecond)
+			So(err, ShouldBeNil)
+
+			Convey("Then keep-alive comments should be written", func() {
+				So(finished, ShouldBeFalse)
+				So(rec.Body.String(), ShouldStartWith, ": keep-alive\n\n")
+			})
+		})
+
+		Convey("When an event has multiple lines", func() {
+			So(w.writeEvent("test", 0, "a\nb"), ShouldBeNil)
+
+			Convey("Then each line should be written as data", func() {
+				So(rec.Body.String(), ShouldEqual, "event: test\ndata: a\ndata: b\n\n")
+			})
+		})
+	})
+}
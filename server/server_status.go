@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/user"
 	"runtime"
+	"runtime/debug"
 	"sync"
 )
 
@@ -21,6 +22,7 @@ type serverStatus struct {
 func setUpServerStatusRouter(prefix string, router *web.Router) {
 	root := router.Subrouter(serverStatus{}, "")
 	root.Get("/runtime_status", (*serverStatus).RuntimeStatus)
+	root.Get("/runtime", (*serverStatus).Runtime)
 }
 
 func (ss *serverStatus) RuntimeStatus(rw web.ResponseWriter, req *web.Request) {
@@ -57,3 +59,33 @@ func (ss *serverStatus) RuntimeStatus(rw web.ResponseWriter, req *web.Request) {
 	}
 	ss.Render(res)
 }
+
+// Runtime returns parameters of the Go runtime currently used and the runtime
+// section of the config.
+func (ss *serverStatus) Runtime(rw web.ResponseWriter, req *web.Request) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	gogc := interface{}(currentGOGC())
+	if gogc == -1 {
+		gogc = "off"
+	}
+	res := map[string]interface{}{
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"num_cpu":        runtime.NumCPU(),
+		"gogc":           gogc,
+		"memory_limit":   debug.SetMemoryLimit(-1),
+		"memory_ballast": runtimeBallastSize(),
+		"mem_stats": map[string]interface{}{
+			"heap_alloc": ms.HeapAlloc,
+			"heap_sys":   ms.HeapSys,
+			"sys":        ms.Sys,
+			"next_gc":    ms.NextGC,
+			"num_gc":     ms.NumGC,
+		},
+	}
+	if ss.config != nil && ss.config.Runtime != nil {
+		res["config"] = ss.config.Runtime.ToMap()
+	}
+	ss.Render(res)
+}
@@ -1,9 +1,13 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gocraft/web"
 	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"gopkg.in/sensorbee/sensorbee.v0/server/response"
 	"net/http"
 )
@@ -16,8 +20,12 @@ type sinks struct {
 func setUpSinksRouter(prefix string, router *web.Router) {
 	root := router.Subrouter(sinks{}, "/:topologyName/sinks")
 	root.Middleware((*sinks).fetchSink)
+	root.Post("/", (*sinks).Create)
 	root.Get("/", (*sinks).Index)
 	root.Get("/:sinkName", (*sinks).Show)
+	root.Put("/:sinkName", (*sinks).Update)
+	root.Delete("/:sinkName", (*sinks).Destroy)
+	root.Put("/:sinkName/status", (*sinks).UpdateStatus)
 }
 
 func (sc *sinks) fetchSink(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
@@ -37,6 +45,9 @@ func (sc *sinks) fetchSink(rw web.ResponseWriter, req *web.Request, next web.Nex
 		sc.sink = sink
 		sc.AddLogField("node_type", core.NTSink.String())
 		sc.AddLogField("node_name", sink.Name())
+		if ls := sink.Labels(); len(ls) > 0 {
+			sc.AddLogField("labels", ls)
+		}
 	}
 	next(rw, req)
 }
@@ -44,9 +55,16 @@ func (sc *sinks) fetchSink(rw web.ResponseWriter, req *web.Request, next web.Nex
 func (sc *sinks) Index(rw web.ResponseWriter, req *web.Request) {
 	// TODO: support pagination
 
+	sel, ok := sc.labelSelector(req)
+	if !ok {
+		return
+	}
 	sinks := sc.topology.Topology().Sinks()
 	res := make([]*response.Sink, 0, len(sinks))
 	for _, s := range sinks {
+		if !matchLabels(s.Labels(), sel) {
+			continue
+		}
 		res = append(res, response.NewSink(s, false))
 	}
 	sc.Render(map[string]interface{}{
@@ -56,6 +74,74 @@ func (sc *sinks) Index(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
+// Create creates a new sink in the same way as a CREATE SINK statement. The
+// request body has "name", "type", and "params" given to the WITH clause.
+// "from" is an optional array of names of sources or streams whose tuples are
+// written to the sink as INSERT INTO statements do. The sink is dropped when
+// one of them cannot be connected.
+func (sc *sinks) Create(rw web.ResponseWriter, req *web.Request) {
+	form, ok := sc.parseNodeForm()
+	if !ok {
+		return
+	}
+	name, ok := sc.stringField(form, "name", true)
+	if !ok {
+		return
+	}
+	typeName, ok := sc.stringField(form, "type", true)
+	if !ok {
+		return
+	}
+	params, ok := sc.paramsField(form)
+	if !ok {
+		return
+	}
+	var inputs []string
+	if v, ok := form["from"]; ok {
+		a, err := data.AsArray(v)
+		if err != nil {
+			sc.renderFieldError("from", errors.New("from must be an array of names of nodes"))
+			return
+		}
+		for _, e := range a {
+			in, err := data.AsString(e)
+			if err != nil {
+				sc.renderFieldError("from", errors.New("from must be an array of names of nodes"))
+				return
+			}
+			inputs = append(inputs, in)
+		}
+	}
+
+	n, ok := sc.addStmt(req, parser.CreateSinkStmt{
+		Name:               parser.StreamIdentifier(name),
+		Type:               parser.SourceSinkType(typeName),
+		SourceSinkSpecsAST: parser.SourceSinkSpecsAST{Params: params},
+	})
+	if !ok {
+		return
+	}
+	for _, in := range inputs {
+		if _, ok := sc.addStmt(req, parser.InsertIntoFromStmt{
+			Sink:  parser.StreamIdentifier(name),
+			Input: parser.StreamIdentifier(in),
+		}); !ok {
+			// The error has already been rendered.
+			drop := parser.DropSinkStmt{Sink: parser.StreamIdentifier(name)}
+			if _, err := sc.topology.AddStmtAs(requestOwner(req), drop); err != nil {
+				sc.ErrLog(err).Error("Cannot drop the sink which couldn't be connected to its inputs")
+			} else if sc.recorder != nil {
+				if err := sc.recorder.Append(sc.topologyName, fmt.Sprint(drop)); err != nil {
+					sc.ErrLog(err).Error("Cannot persist the statement")
+				}
+			}
+			return
+		}
+	}
+	sc.sink = n.(core.SinkNode)
+	sc.Show(rw, req)
+}
+
 func (sc *sinks) Show(rw web.ResponseWriter, req *web.Request) {
 	sc.Render(map[string]interface{}{
 		"topology": sc.topologyName,
@@ -63,5 +149,49 @@ func (sc *sinks) Show(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
-// TODO: Support Update(e.g. pause/resume) and Destroy if necessary. They can be
-// done by queries.
+// UpdateStatus pauses, resumes, stops, or drains the sink.
+func (sc *sinks) UpdateStatus(rw web.ResponseWriter, req *web.Request) {
+	if !sc.updateNodeStatus(req, &nodeControl{
+		node:         sc.sink,
+		pause:        sc.sink.Pause,
+		resume:       sc.sink.Resume,
+		gracefulStop: sc.sink.EnableGracefulStop,
+	}) {
+		return
+	}
+	sc.Show(rw, req)
+}
+
+// Update updates parameters of the sink in the same way as an UPDATE SINK
+// statement. The request body has "params" given to the SET clause.
+func (sc *sinks) Update(rw web.ResponseWriter, req *web.Request) {
+	form, ok := sc.parseNodeForm()
+	if !ok {
+		return
+	}
+	params, ok := sc.paramsField(form)
+	if !ok {
+		return
+	}
+	if len(params) == 0 {
+		sc.renderFieldError("params", errors.New("params must have at least one parameter"))
+		return
+	}
+	if _, ok := sc.addStmt(req, parser.UpdateSinkStmt{
+		Name:               parser.StreamIdentifier(sc.sink.Name()),
+		SourceSinkSpecsAST: parser.SourceSinkSpecsAST{Params: params},
+	}); !ok {
+		return
+	}
+	sc.Show(rw, req)
+}
+
+// Destroy drops the sink in the same way as a DROP SINK statement.
+func (sc *sinks) Destroy(rw web.ResponseWriter, req *web.Request) {
+	if _, ok := sc.addStmt(req, parser.DropSinkStmt{
+		Sink: parser.StreamIdentifier(sc.sink.Name()),
+	}); !ok {
+		return
+	}
+	sc.Render(map[string]interface{}{})
+}
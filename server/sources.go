@@ -1,9 +1,12 @@
 package server
 
 import (
+	"errors"
 	"github.com/gocraft/web"
 	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"gopkg.in/sensorbee/sensorbee.v0/server/response"
 	"net/http"
 )
@@ -16,8 +19,13 @@ type sources struct {
 func setUpSourcesRouter(prefix string, router *web.Router) {
 	root := router.Subrouter(sources{}, "/:topologyName/sources")
 	root.Middleware((*sources).fetchSource)
+	root.Post("/", (*sources).Create)
 	root.Get("/", (*sources).Index)
 	root.Get("/:sourceName", (*sources).Show)
+	root.Put("/:sourceName", (*sources).Update)
+	root.Delete("/:sourceName", (*sources).Destroy)
+	root.Get("/:sourceName/schema", (*sources).Schema)
+	root.Put("/:sourceName/status", (*sources).UpdateStatus)
 }
 
 func (sc *sources) fetchSource(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
@@ -37,6 +45,9 @@ func (sc *sources) fetchSource(rw web.ResponseWriter, req *web.Request, next web
 		sc.src = src
 		sc.AddLogField("node_type", core.NTSource.String())
 		sc.AddLogField("node_name", src.Name())
+		if ls := src.Labels(); len(ls) > 0 {
+			sc.AddLogField("labels", ls)
+		}
 	}
 	next(rw, req)
 }
@@ -44,9 +55,16 @@ func (sc *sources) fetchSource(rw web.ResponseWriter, req *web.Request, next web
 func (sc *sources) Index(rw web.ResponseWriter, req *web.Request) {
 	// TODO: support pagination
 
+	sel, ok := sc.labelSelector(req)
+	if !ok {
+		return
+	}
 	srcs := sc.topology.Topology().Sources()
 	res := make([]*response.Source, 0, len(srcs))
 	for _, s := range srcs {
+		if !matchLabels(s.Labels(), sel) {
+			continue
+		}
 		res = append(res, response.NewSource(s, false))
 	}
 	sc.Render(map[string]interface{}{
@@ -56,6 +74,49 @@ func (sc *sources) Index(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
+// Create creates a new source in the same way as a CREATE SOURCE statement.
+// The request body has "name", "type", "params" given to the WITH clause, and
+// "paused" corresponding to CREATE PAUSED SOURCE.
+func (sc *sources) Create(rw web.ResponseWriter, req *web.Request) {
+	form, ok := sc.parseNodeForm()
+	if !ok {
+		return
+	}
+	stmt := parser.CreateSourceStmt{}
+	name, ok := sc.stringField(form, "name", true)
+	if !ok {
+		return
+	}
+	typeName, ok := sc.stringField(form, "type", true)
+	if !ok {
+		return
+	}
+	params, ok := sc.paramsField(form)
+	if !ok {
+		return
+	}
+	if v, ok := form["paused"]; ok {
+		paused, err := data.AsBool(v)
+		if err != nil {
+			sc.renderFieldError("paused", errors.New("paused must be a bool"))
+			return
+		}
+		if paused {
+			stmt.Paused = parser.Yes
+		}
+	}
+	stmt.Name = parser.StreamIdentifier(name)
+	stmt.Type = parser.SourceSinkType(typeName)
+	stmt.Params = params
+
+	n, ok := sc.addStmt(req, stmt)
+	if !ok {
+		return
+	}
+	sc.src = n.(core.SourceNode)
+	sc.Show(rw, req)
+}
+
 func (sc *sources) Show(rw web.ResponseWriter, req *web.Request) {
 	sc.Render(map[string]interface{}{
 		"topology": sc.topologyName,
@@ -63,5 +124,70 @@ func (sc *sources) Show(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
-// TODO: Support Update(e.g. pause/resume) and Destroy if necessary. They can be
-// done by queries.
+// UpdateStatus pauses, resumes, or stops the source.
+func (sc *sources) UpdateStatus(rw web.ResponseWriter, req *web.Request) {
+	if !sc.updateNodeStatus(req, &nodeControl{
+		node:   sc.src,
+		pause:  sc.src.Pause,
+		resume: sc.src.Resume,
+	}) {
+		return
+	}
+	sc.Show(rw, req)
+}
+
+// Update updates parameters of the source in the same way as an UPDATE SOURCE
+// statement. The request body has "params" given to the SET clause.
+func (sc *sources) Update(rw web.ResponseWriter, req *web.Request) {
+	form, ok := sc.parseNodeForm()
+	if !ok {
+		return
+	}
+	params, ok := sc.paramsField(form)
+	if !ok {
+		return
+	}
+	if len(params) == 0 {
+		sc.renderFieldError("params", errors.New("params must have at least one parameter"))
+		return
+	}
+	if _, ok := sc.addStmt(req, parser.UpdateSourceStmt{
+		Name:               parser.StreamIdentifier(sc.src.Name()),
+		SourceSinkSpecsAST: parser.SourceSinkSpecsAST{Params: params},
+	}); !ok {
+		return
+	}
+	sc.Show(rw, req)
+}
+
+// Destroy drops the source in the same way as a DROP SOURCE statement.
+func (sc *sources) Destroy(rw web.ResponseWriter, req *web.Request) {
+	if _, ok := sc.addStmt(req, parser.DropSourceStmt{
+		Source: parser.StreamIdentifier(sc.src.Name()),
+	}); !ok {
+		return
+	}
+	sc.Render(map[string]interface{}{})
+}
+
+// Schema returns the schema of the source inferred by PROFILE STREAM.
+func (sc *sources) Schema(rw web.ResponseWriter, req *web.Request) {
+	d, err := sc.topology.Describe(sc.src.Name())
+	if err != nil {
+		sc.ErrLog(err).Error("Cannot describe the source")
+		sc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The source was not found", http.StatusNotFound, err))
+		return
+	}
+	if d["inferred_schema"].Type() == data.TypeNull {
+		sc.Log().Error("The source isn't profiled")
+		sc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The source isn't profiled, issue PROFILE STREAM first", http.StatusNotFound, nil))
+		return
+	}
+	sc.Render(map[string]interface{}{
+		"topology": sc.topologyName,
+		"source":   sc.src.Name(),
+		"schema":   d["inferred_schema"],
+	})
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+const (
+	// stateErrorTrailer is the HTTP trailer having the error message when
+	// saving a state fails after its data is partially sent.
+	stateErrorTrailer = "X-Sensorbee-Error"
+
+	// maxStateFieldSize is the maximum size of a form field other than the
+	// state data in a request loading a state.
+	maxStateFieldSize = 1024 * 1024
+)
+
+type states struct {
+	*topologies
+	stateName string
+}
+
+func setUpStatesRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(states{}, "/:topologyName/states/:stateName")
+	root.Middleware((*states).fetchTopologyMiddleware)
+	root.Post("/load", (*states).Load)
+	root.Get("/save", (*states).Save)
+}
+
+func (sc *states) fetchTopologyMiddleware(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	sc.stateName = sc.PathParams().String("stateName", "")
+	sc.AddLogField("state", sc.stateName)
+	if sc.fetchTopology() == nil {
+		return
+	}
+	next(rw, req)
+}
+
+func (sc *states) renderFormError(err error, field, msg string) {
+	sc.ErrLog(err).Error("The request body is invalid")
+	e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+		http.StatusBadRequest, err)
+	e.Meta[field] = []string{msg}
+	sc.RenderError(e)
+}
+
+// Load loads the state from a multipart/form-data body. The body can have
+// the following parts and the "state" part must come last so that the data
+// is streamed to the state without being buffered:
+//
+//	* type: the type of the state. It's optional when the topology already
+//	  has the state.
+//	* params: a JSON object having parameters of the state's Load method
+//	* state: the data written by the state's Save method, e.g. by Save of
+//	  this API
+//
+// Like LOAD STATE statements, an existing state is overwritten by its Load
+// method when it's provided. Otherwise, a new state is created from the data
+// and replaces the existing one.
+func (sc *states) Load(rw web.ResponseWriter, req *web.Request) {
+	mr, err := req.MultipartReader()
+	if err != nil {
+		sc.renderFormError(err, "body", "must be multipart/form-data")
+		return
+	}
+
+	var typeName string
+	params := data.Map{}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			err := errors.New("the state data is missing")
+			sc.renderFormError(err, "state", "is required")
+			return
+		} else if err != nil {
+			sc.renderFormError(err, "body", "is broken")
+			return
+		}
+
+		switch part.FormName() {
+		case "type":
+			b, err := ioutil.ReadAll(io.LimitReader(part, maxStateFieldSize))
+			if err != nil {
+				sc.renderFormError(err, "type", "cannot be read")
+				return
+			}
+			typeName = string(b)
+
+		case "params":
+			var js map[string]interface{}
+			if err := json.NewDecoder(io.LimitReader(part, maxStateFieldSize)).Decode(&js); err != nil {
+				sc.renderFormError(err, "params", "must be a JSON object")
+				return
+			}
+			if params, err = data.NewMap(js); err != nil {
+				sc.renderFormError(err, "params", "has invalid values")
+				return
+			}
+
+		case "state":
+			sc.load(req, typeName, part, params)
+			return
+
+		default:
+			// Unknown parts are ignored for forward compatibility.
+		}
+	}
+}
+
+func (sc *states) load(req *web.Request, typeName string, r io.Reader, params data.Map) {
+	if typeName == "" {
+		t, err := sc.topology.Topology().Context().SharedStates.Type(sc.stateName)
+		if err != nil {
+			if core.IsNotExist(err) {
+				sc.renderFormError(err, "type", "is required when the state doesn't exist")
+				return
+			}
+			sc.ErrLog(err).Error("Cannot get the type of the state")
+			sc.RenderError(jasco.NewInternalServerError(err))
+			return
+		}
+		typeName = t
+	}
+
+	if err := sc.topology.LoadStateAs(requestOwner(req), typeName, sc.stateName, r, params); err != nil {
+		sc.ErrLog(err).Error("Cannot load the state")
+		if bql.IsProtectedError(err) {
+			sc.RenderError(jasco.NewError(protectedNodeErrorCode, "The state is protected by its owner",
+				http.StatusForbidden, err))
+			return
+		}
+		e := jasco.NewError(formValidationErrorCode, "Cannot load the state.", http.StatusBadRequest, err)
+		e.Meta["state"] = []string{err.Error()}
+		sc.RenderError(e)
+		return
+	}
+	sc.Render(map[string]interface{}{
+		"topology": sc.topologyName,
+		"state":    sc.stateName,
+	})
+}
+
+// Save streams the data of the state written by its Save method. Because
+// the data isn't buffered, an error occurring after a part of the data is
+// sent is reported by the X-Sensorbee-Error trailer. Clients must check the
+// trailer to detect a truncated state.
+func (sc *states) Save(rw web.ResponseWriter, req *web.Request) {
+	ctx := sc.topology.Topology().Context()
+	st, err := ctx.SharedStates.Get(sc.stateName)
+	if err != nil {
+		if core.IsNotExist(err) {
+			sc.Log().Error("The state doesn't exist")
+			sc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode, "The state doesn't exist",
+				http.StatusNotFound, err))
+			return
+		}
+		sc.ErrLog(err).Error("Cannot get the state")
+		sc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	s, ok := st.(core.SavableSharedState)
+	if !ok {
+		err := fmt.Errorf("the state '%v' cannot be saved", sc.stateName)
+		sc.ErrLog(err).Error("Cannot save the state")
+		e := jasco.NewError(formValidationErrorCode, "The state cannot be saved.", http.StatusBadRequest, err)
+		e.Meta["state"] = []string{"doesn't support saving"}
+		sc.RenderError(e)
+		return
+	}
+
+	w := &stateResponseWriter{rw: rw}
+	if err := s.Save(ctx, w, data.Map{}); err != nil {
+		sc.ErrLog(err).Error("Cannot save the state")
+		if !w.started {
+			sc.RenderError(jasco.NewInternalServerError(err))
+			return
+		}
+		rw.Header().Set(stateErrorTrailer, err.Error())
+		return
+	}
+	w.start()
+}
+
+// stateResponseWriter writes data of a state to the response. The header is
+// sent on the first write so that an error can still be rendered when Save
+// fails without writing anything.
+type stateResponseWriter struct {
+	rw      web.ResponseWriter
+	started bool
+}
+
+func (w *stateResponseWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	h := w.rw.Header()
+	h.Set("Content-Type", "application/octet-stream")
+	h.Set("Trailer", stateErrorTrailer)
+	w.rw.WriteHeader(http.StatusOK)
+}
+
+func (w *stateResponseWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		// The header isn't sent yet so that an error can still be rendered.
+		return 0, nil
+	}
+	w.start()
+	return w.rw.Write(p)
+}
@@ -0,0 +1,244 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// bytesState is a state having bytes. Its Save fails after writing failAfter
+// bytes when failAfter is non-negative.
+type bytesState struct {
+	data      []byte
+	failAfter int
+}
+
+func (s *bytesState) Terminate(ctx *core.Context) error {
+	return nil
+}
+
+func (s *bytesState) Save(ctx *core.Context, w io.Writer, params data.Map) error {
+	if s.failAfter < 0 {
+		_, err := w.Write(s.data)
+		return err
+	}
+	if _, err := w.Write(s.data[:s.failAfter]); err != nil {
+		return err
+	}
+	return errors.New("saving failed")
+}
+
+type bytesStateCreator struct{}
+
+func (bytesStateCreator) CreateState(ctx *core.Context, params data.Map) (core.SharedState, error) {
+	s := &bytesState{failAfter: -1}
+	if v, ok := params["data"]; ok {
+		d, err := data.AsString(v)
+		if err != nil {
+			return nil, err
+		}
+		s.data = []byte(d)
+	}
+	if v, ok := params["fail_after"]; ok {
+		n, err := data.AsInt(v)
+		if err != nil {
+			return nil, err
+		}
+		s.failAfter = int(n)
+	}
+	return s, nil
+}
+
+// LoadState reads the data. The "prefix" parameter is prepended to it.
+func (bytesStateCreator) LoadState(ctx *core.Context, r io.Reader, params data.Map) (core.SharedState, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := params["prefix"]; ok {
+		p, err := data.AsString(v)
+		if err != nil {
+			return nil, err
+		}
+		b = append([]byte(p), b...)
+	}
+	return &bytesState{data: b, failAfter: -1}, nil
+}
+
+func init() {
+	udf.MustRegisterGlobalUDSCreator("test_bytes", bytesStateCreator{})
+}
+
+type multipartField struct {
+	name  string
+	value string
+}
+
+// newMultipartBody creates a multipart/form-data body having the fields in
+// order.
+func newMultipartBody(fields ...multipartField) (io.Reader, string) {
+	b := bytes.NewBuffer(nil)
+	w := multipart.NewWriter(b)
+	for _, f := range fields {
+		So(w.WriteField(f.name, f.value), ShouldBeNil)
+	}
+	So(w.Close(), ShouldBeNil)
+	return b, w.FormDataContentType()
+}
+
+func TestStates(t *testing.T) {
+	Convey("Given an API server having a topology", t, func() {
+		s := newTestServer(data.Map{})
+		Reset(s.Close)
+		res, _ := s.request("POST", "/topologies", map[string]interface{}{"name": "test"})
+		So(res.StatusCode, ShouldEqual, http.StatusOK)
+
+		addQuery := func(q string) {
+			res, _ := s.request("POST", "/topologies/test/queries", map[string]interface{}{"queries": q})
+			So(res.StatusCode, ShouldEqual, http.StatusOK)
+		}
+		stateData := func(name string) string {
+			tb, err := s.gvars.Topologies.Lookup("test")
+			So(err, ShouldBeNil)
+			st, err := tb.Topology().Context().SharedStates.Get(name)
+			So(err, ShouldBeNil)
+			return string(st.(*bytesState).data)
+		}
+		load := func(name string, fields ...multipartField) (*http.Response, map[string]interface{}) {
+			body, contentType := newMultipartBody(fields...)
+			req := s.newRequest("POST", "/topologies/test/states/"+name+"/load", body)
+			req.Header.Set("Content-Type", contentType)
+			res, _, js := s.do(req)
+			return res, js
+		}
+		errorMeta := func(js map[string]interface{}) map[string]interface{} {
+			return js["error"].(map[string]interface{})["meta"].(map[string]interface{})
+		}
+
+		Convey("When loading a state with type, params, and state parts in order", func() {
+			res, js := load("s",
+				multipartField{"type", "test_bytes"},
+				multipartField{"params", `{"prefix":"a"}`},
+				multipartField{"unknown", "ignored"},
+				multipartField{"state", "bc"})
+
+			Convey("Then the state should be created with the params", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(js, ShouldResemble, map[string]interface{}{
+					"topology": "test",
+					"state":    "s",
+				})
+				So(stateData("s"), ShouldEqual, "abc")
+			})
+		})
+
+		Convey("When loading a new state without the type part", func() {
+			res, js := load("s", multipartField{"state", "abc"})
+
+			Convey("Then it should fail because the type is missing", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(errorCode(js), ShouldEqual, formValidationErrorCode)
+				So(errorMeta(js)["type"], ShouldResemble,
+					[]interface{}{"is required when the state doesn't exist"})
+			})
+		})
+
+		Convey("When loading a new state having the type part after the state part", func() {
+			res, js := load("s",
+				multipartField{"state", "abc"},
+				multipartField{"type", "test_bytes"})
+
+			Convey("Then it should fail because parts after the state part are ignored", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(errorMeta(js)["type"], ShouldNotBeNil)
+			})
+		})
+
+		Convey("When loading a state without the state part", func() {
+			res, js := load("s", multipartField{"type", "test_bytes"})
+
+			Convey("Then it should fail", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(errorMeta(js)["state"], ShouldResemble, []interface{}{"is required"})
+			})
+		})
+
+		Convey("When loading a state with invalid params", func() {
+			res, js := load("s",
+				multipartField{"type", "test_bytes"},
+				multipartField{"params", `[1]`},
+				multipartField{"state", "abc"})
+
+			Convey("Then it should fail", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(errorMeta(js)["params"], ShouldResemble, []interface{}{"must be a JSON object"})
+			})
+		})
+
+		Convey("When loading a state with a body which isn't multipart", func() {
+			res, js := s.request("POST", "/topologies/test/states/s/load", map[string]interface{}{})
+
+			Convey("Then it should fail", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusBadRequest)
+				So(errorMeta(js)["body"], ShouldResemble, []interface{}{"must be multipart/form-data"})
+			})
+		})
+
+		Convey("When the topology has a state", func() {
+			addQuery(`CREATE STATE s TYPE test_bytes WITH data="abc";`)
+
+			Convey("Then loading it without the type part should use its type", func() {
+				res, _ := load("s", multipartField{"state", "def"})
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(stateData("s"), ShouldEqual, "def")
+			})
+
+			Convey("Then saving it should return its data", func() {
+				res, body, _ := s.do(s.newRequest("GET", "/topologies/test/states/s/save", nil))
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(res.Header.Get("Content-Type"), ShouldEqual, "application/octet-stream")
+				So(string(body), ShouldEqual, "abc")
+				So(res.Trailer.Get(stateErrorTrailer), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When saving a state which fails after writing a part of its data", func() {
+			addQuery(`CREATE STATE s TYPE test_bytes WITH data="abcdef", fail_after=3;`)
+			res, body, _ := s.do(s.newRequest("GET", "/topologies/test/states/s/save", nil))
+
+			Convey("Then the error should be reported by the trailer", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(string(body), ShouldEqual, "abc")
+				So(res.Trailer.Get(stateErrorTrailer), ShouldEqual, "saving failed")
+			})
+		})
+
+		Convey("When saving a state which fails before writing data", func() {
+			addQuery(`CREATE STATE s TYPE test_bytes WITH data="abc", fail_after=0;`)
+			res, js := s.request("GET", "/topologies/test/states/s/save", nil)
+
+			Convey("Then an error should be rendered", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusInternalServerError)
+				So(js["error"], ShouldNotBeNil)
+			})
+		})
+
+		Convey("When saving a state which doesn't exist", func() {
+			res, js := s.request("GET", "/topologies/test/states/s/save", nil)
+
+			Convey("Then it should fail", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusNotFound)
+				So(errorCode(js), ShouldEqual, requestResourceNotFoundErrorCode)
+			})
+		})
+	})
+}
@@ -1,11 +1,15 @@
 package server
 
 import (
+	"errors"
 	"github.com/gocraft/web"
 	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/parser"
 	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
 	"gopkg.in/sensorbee/sensorbee.v0/server/response"
 	"net/http"
+	"strings"
 )
 
 type streams struct {
@@ -16,8 +20,12 @@ type streams struct {
 func setUpStreamsRouter(prefix string, router *web.Router) {
 	root := router.Subrouter(streams{}, "/:topologyName/streams")
 	root.Middleware((*streams).fetchStream)
+	root.Post("/", (*streams).Create)
 	root.Get("/", (*streams).Index)
 	root.Get("/:streamName", (*streams).Show)
+	root.Delete("/:streamName", (*streams).Destroy)
+	root.Get("/:streamName/schema", (*streams).Schema)
+	root.Put("/:streamName/status", (*streams).UpdateStatus)
 }
 
 func (sc *streams) fetchStream(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
@@ -37,6 +45,9 @@ func (sc *streams) fetchStream(rw web.ResponseWriter, req *web.Request, next web
 		sc.stream = strm
 		sc.AddLogField("node_type", core.NTBox.String())
 		sc.AddLogField("node_name", strm.Name())
+		if ls := strm.Labels(); len(ls) > 0 {
+			sc.AddLogField("labels", ls)
+		}
 	}
 	next(rw, req)
 }
@@ -44,9 +55,16 @@ func (sc *streams) fetchStream(rw web.ResponseWriter, req *web.Request, next web
 func (sc *streams) Index(rw web.ResponseWriter, req *web.Request) {
 	// TODO: support pagination
 
+	sel, ok := sc.labelSelector(req)
+	if !ok {
+		return
+	}
 	strms := sc.topology.Topology().Boxes()
 	res := make([]*response.Stream, 0, len(strms))
 	for _, s := range strms {
+		if !matchLabels(s.Labels(), sel) {
+			continue
+		}
 		res = append(res, response.NewStream(s, false))
 	}
 	sc.Render(map[string]interface{}{
@@ -56,6 +74,62 @@ func (sc *streams) Index(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
+// Create creates a new stream in the same way as a CREATE STREAM statement.
+// The request body has "name", "select" having a SELECT statement, which may
+// have UNION ALL, and "params" given to the WITH clause.
+func (sc *streams) Create(rw web.ResponseWriter, req *web.Request) {
+	form, ok := sc.parseNodeForm()
+	if !ok {
+		return
+	}
+	name, ok := sc.stringField(form, "name", true)
+	if !ok {
+		return
+	}
+	sel, ok := sc.stringField(form, "select", true)
+	if !ok {
+		return
+	}
+	params, ok := sc.paramsField(form)
+	if !ok {
+		return
+	}
+
+	istmt, rest, err := parser.New().ParseStmt(sel)
+	if err == nil && strings.TrimSpace(rest) != "" {
+		err = errors.New("select must have only one statement")
+	}
+	if err != nil {
+		sc.renderFieldError("select", err)
+		return
+	}
+	var stmt interface{}
+	switch s := istmt.(type) {
+	case parser.SelectStmt:
+		stmt = parser.CreateStreamAsSelectStmt{
+			Name:               parser.StreamIdentifier(name),
+			Select:             s,
+			SourceSinkSpecsAST: parser.SourceSinkSpecsAST{Params: params},
+		}
+	case parser.SelectUnionStmt:
+		stmt = parser.CreateStreamAsSelectUnionStmt{
+			Name:               parser.StreamIdentifier(name),
+			SelectUnionStmt:    s,
+			SourceSinkSpecsAST: parser.SourceSinkSpecsAST{Params: params},
+		}
+	default:
+		sc.renderFieldError("select", errors.New("select must be a SELECT statement"))
+		return
+	}
+
+	n, ok := sc.addStmt(req, stmt)
+	if !ok {
+		return
+	}
+	sc.stream = n.(core.BoxNode)
+	sc.Show(rw, req)
+}
+
 func (sc *streams) Show(rw web.ResponseWriter, req *web.Request) {
 	sc.Render(map[string]interface{}{
 		"topology": sc.topologyName,
@@ -63,5 +137,48 @@ func (sc *streams) Show(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
-// TODO: Support Update(e.g. pause/resume) and Destroy if necessary. They can be
-// done by queries.
+// UpdateStatus pauses, resumes, stops, or drains the stream.
+func (sc *streams) UpdateStatus(rw web.ResponseWriter, req *web.Request) {
+	if !sc.updateNodeStatus(req, &nodeControl{
+		node:         sc.stream,
+		pause:        sc.stream.Pause,
+		resume:       sc.stream.Resume,
+		gracefulStop: sc.stream.EnableGracefulStop,
+	}) {
+		return
+	}
+	sc.Show(rw, req)
+}
+
+// Destroy drops the stream in the same way as a DROP STREAM statement.
+// Streams cannot be updated because BQL doesn't support it either.
+func (sc *streams) Destroy(rw web.ResponseWriter, req *web.Request) {
+	if _, ok := sc.addStmt(req, parser.DropStreamStmt{
+		Stream: parser.StreamIdentifier(sc.stream.Name()),
+	}); !ok {
+		return
+	}
+	sc.Render(map[string]interface{}{})
+}
+
+// Schema returns the schema of the stream inferred by PROFILE STREAM.
+func (sc *streams) Schema(rw web.ResponseWriter, req *web.Request) {
+	d, err := sc.topology.Describe(sc.stream.Name())
+	if err != nil {
+		sc.ErrLog(err).Error("Cannot describe the stream")
+		sc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The stream was not found", http.StatusNotFound, err))
+		return
+	}
+	if d["inferred_schema"].Type() == data.TypeNull {
+		sc.Log().Error("The stream isn't profiled")
+		sc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The stream isn't profiled, issue PROFILE STREAM first", http.StatusNotFound, nil))
+		return
+	}
+	sc.Render(map[string]interface{}{
+		"topology": sc.topologyName,
+		"stream":   sc.stream.Name(),
+		"schema":   d["inferred_schema"],
+	})
+}
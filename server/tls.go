@@ -0,0 +1,49 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+// NewTLSConfig creates a TLS config of the HTTP server from the tls section of
+// the config. It loads the certificate of the server and certificates of
+// client CAs.
+//
+// The config only offers HTTP/1.1 with ALPN because streaming SELECT
+// statements and WebSocket connections hijack connections, which HTTP/2
+// doesn't support.
+func NewTLSConfig(conf *config.TLS) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the certificate of the server: %v", err)
+	}
+	c := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"http/1.1"},
+	}
+	if conf.ClientCAFile == "" {
+		return c, nil
+	}
+
+	pem, err := ioutil.ReadFile(conf.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read certificates of client CAs: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%v doesn't have any PEM encoded certificate", conf.ClientCAFile)
+	}
+	c.ClientCAs = pool
+	switch conf.ClientAuth {
+	case "verify_if_given":
+		c.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return c, nil
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+// tlsTestCert creates a certificate signed by the parent. The certificate is
+// self-signed when the parent is nil.
+func tlsTestCert(name string, parent *tls.Certificate) (*tls.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	So(err, ShouldBeNil)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{name},
+	}
+	signer, signerKey := tmpl, interface{}(key)
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer = parent.Leaf
+		signerKey = parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	So(err, ShouldBeNil)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	So(err, ShouldBeNil)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	c, err := tls.X509KeyPair(certPEM, keyPEM)
+	So(err, ShouldBeNil)
+	c.Leaf, err = x509.ParseCertificate(der)
+	So(err, ShouldBeNil)
+	return &c, certPEM, keyPEM
+}
+
+// tlsTestHandshake returns the error of the handshake on the server side.
+// It uses a TCP connection rather than net.Pipe because both sides write
+// messages at the same time when the server rejects the client.
+func tlsTestHandshake(server, client *tls.Config) error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	So(err, ShouldBeNil)
+	defer l.Close()
+	go func() {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			return
+		}
+		c := tls.Client(conn, client)
+		c.Handshake()
+		// Read the alert sent when the server rejects the client.
+		c.Read(make([]byte, 1))
+		c.Close()
+	}()
+	conn, err := l.Accept()
+	So(err, ShouldBeNil)
+	defer conn.Close()
+	return tls.Server(conn, server).Handshake()
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	Convey("Given certificates of a CA, the server, and a client", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_tls_test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+		write := func(name string, b []byte) string {
+			p := filepath.Join(dir, name)
+			So(ioutil.WriteFile(p, b, 0600), ShouldBeNil)
+			return p
+		}
+
+		ca, caPEM, _ := tlsTestCert("ca", nil)
+		_, serverCert, serverKey := tlsTestCert("server", ca)
+		client, _, _ := tlsTestCert("client", ca)
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Leaf)
+
+		conf := &config.TLS{
+			CertFile:   write("server.crt", serverCert),
+			KeyFile:    write("server.key", serverKey),
+			ClientAuth: "require",
+		}
+
+		Convey("When creating a config without client CAs", func() {
+			c, err := NewTLSConfig(conf)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should only offer HTTP/1.1", func() {
+				So(c.NextProtos, ShouldResemble, []string{"http/1.1"})
+			})
+
+			Convey("Then it should accept a client without a certificate", func() {
+				So(tlsTestHandshake(c, &tls.Config{RootCAs: roots, ServerName: "server"}), ShouldBeNil)
+			})
+		})
+
+		Convey("When creating a config requiring client certificates", func() {
+			conf.ClientCAFile = write("ca.crt", caPEM)
+			c, err := NewTLSConfig(conf)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should accept a client having a certificate", func() {
+				So(tlsTestHandshake(c, &tls.Config{
+					RootCAs:      roots,
+					ServerName:   "server",
+					Certificates: []tls.Certificate{*client},
+				}), ShouldBeNil)
+			})
+
+			Convey("Then it should reject a client without a certificate", func() {
+				So(tlsTestHandshake(c, &tls.Config{RootCAs: roots, ServerName: "server"}), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When creating a config verifying client certificates if given", func() {
+			conf.ClientCAFile = write("ca.crt", caPEM)
+			conf.ClientAuth = "verify_if_given"
+			c, err := NewTLSConfig(conf)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should accept a client without a certificate", func() {
+				So(tlsTestHandshake(c, &tls.Config{RootCAs: roots, ServerName: "server"}), ShouldBeNil)
+			})
+
+			Convey("Then it should reject a client having an unknown certificate", func() {
+				other, _, _ := tlsTestCert("other", nil)
+				So(tlsTestHandshake(c, &tls.Config{
+					RootCAs:    roots,
+					ServerName: "server",
+					// Certificates isn't used because a client doesn't send a
+					// certificate which isn't issued by CAs the server accepts.
+					GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+						return other, nil
+					},
+				}), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When files are invalid", func() {
+			Convey("Then a missing certificate should be an error", func() {
+				conf.CertFile = filepath.Join(dir, "missing.crt")
+				_, err := NewTLSConfig(conf)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then a file of client CAs without certificates should be an error", func() {
+				conf.ClientCAFile = write("ca.crt", []byte("not a certificate"))
+				_, err := NewTLSConfig(conf)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
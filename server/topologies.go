@@ -3,10 +3,10 @@ package server
 import (
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/textproto"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,22 +25,40 @@ type topologies struct {
 	*APIContext
 	topologyName string
 	topology     *bql.TopologyBuilder
+
+	// idempotentReq is set while an action wrapped by idempotent processes
+	// a request having an idempotency key.
+	idempotentReq *idempotentRequest
 }
 
 func setUpTopologiesRouter(prefix string, router *web.Router) {
 	root := router.Subrouter(topologies{}, "/topologies")
 	root.Middleware((*topologies).extractName)
+	root.Middleware((*topologies).proxyToWorker)
+	root.Middleware((*topologies).trackActivity)
 	// TODO validation (root can validate with regex like "\w+")
-	root.Post("/", (*topologies).Create)
+	root.Post("/", idempotent((*topologies).Create))
+	root.Post("/bulk", (*topologies).Bulk)
 	root.Get("/", (*topologies).Index)
 	root.Get(`/:topologyName`, (*topologies).Show)
 	root.Delete(`/:topologyName`, (*topologies).Destroy)
-	root.Post(`/:topologyName/queries`, (*topologies).Queries)
+	root.Post(`/:topologyName/queries`, idempotent((*topologies).Queries))
+	root.Get(`/:topologyName/queries/sse`, (*topologies).SSEQueries)
 	root.Get(`/:topologyName/wsqueries`, (*topologies).WebSocketQueries)
+	root.Get(`/:topologyName/graph`, (*topologies).Graph)
+	root.Get(`/:topologyName/types`, (*topologies).Types)
+	root.Get(`/:topologyName/usage`, (*topologies).Usage)
+	root.Get(`/:topologyName/selects/:token`, (*topologies).ResumeSelect)
 
 	setUpSourcesRouter(prefix, root)
 	setUpStreamsRouter(prefix, root)
 	setUpSinksRouter(prefix, root)
+	setUpBackfillsRouter(prefix, root)
+	setUpAlertsRouter(prefix, root)
+	setUpLineageRouter(prefix, root)
+	setUpCheckpointsRouter(prefix, root)
+	setUpWebhooksRouter(prefix, root)
+	setUpStatesRouter(prefix, root)
 }
 
 func (tc *topologies) extractName(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
@@ -51,6 +69,18 @@ func (tc *topologies) extractName(rw web.ResponseWriter, req *web.Request, next
 	next(rw, req)
 }
 
+// trackActivity prevents the topology from being hibernated while the
+// request, including a WebSocket connection, is being processed.
+func (tc *topologies) trackActivity(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if tc.hibernator == nil || tc.topologyName == "" {
+		next(rw, req)
+		return
+	}
+	tc.hibernator.begin(tc.topologyName)
+	defer tc.hibernator.end(tc.topologyName)
+	next(rw, req)
+}
+
 // fetchTopology returns the topology having tc.topologyName. When this method
 // returns nil, the caller can just return from the action.
 func (tc *topologies) fetchTopology() *bql.TopologyBuilder {
@@ -117,10 +147,50 @@ func (tc *topologies) Create(rw web.ResponseWriter, req *web.Request) {
 		return
 	}
 
+	var conf data.Map
+	if c, ok := form["config"]; ok {
+		conf, err = data.AsMap(c)
+		if err != nil {
+			tc.ErrLog(err).Error("'config' field isn't an object")
+			e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+				http.StatusBadRequest, nil)
+			e.Meta["config"] = []string{"value must be an object"}
+			tc.RenderError(e)
+			return
+		}
+	}
+
 	// TODO: support other parameters
 
+	tb, err := tc.createTopology(name, conf)
+	if err != nil {
+		if os.IsExist(err) {
+			tc.Log().Error("the name is already registered")
+			e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+				http.StatusBadRequest, nil)
+			e.Meta["name"] = []string{"already taken"}
+			tc.RenderError(e)
+			return
+		}
+		tc.ErrLog(err).Error("Cannot create a new topology")
+		tc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+
+	// TODO: return 201
+	tc.Render(map[string]interface{}{
+		"topology": response.NewTopology(tb.Topology()),
+	})
+}
+
+// createTopology creates a new topology having the config and registers it.
+// It returns an error satisfying os.IsExist when the name is already taken.
+func (tc *topologies) createTopology(name string, conf data.Map) (*bql.TopologyBuilder, error) {
 	cc := &core.ContextConfig{
-		Logger: tc.logger,
+		Logger:            tc.logger,
+		Config:            tc.config.BQL.TopologyConfig(conf),
+		Checkpoint:        checkpointConfig(tc.config, tc.udsStorage),
+		LifecycleListener: tc.hooks.listener(),
 	}
 	// TODO: Be careful of race conditions on these fields.
 	cc.Flags.DroppedTupleLog.Set(tc.config.Logging.LogDroppedTuples)
@@ -129,15 +199,14 @@ func (tc *topologies) Create(rw web.ResponseWriter, req *web.Request) {
 
 	tp, err := core.NewDefaultTopology(core.NewContext(cc), name)
 	if err != nil {
-		tc.ErrLog(err).Error("Cannot create a new topology")
-		tc.RenderError(jasco.NewInternalServerError(err))
-		return
+		return nil, err
 	}
 	tb, err := bql.NewTopologyBuilder(tp)
 	if err != nil {
-		tc.ErrLog(err).Error("Cannot create a new topology builder")
-		tc.RenderError(jasco.NewInternalServerError(err))
-		return
+		if err := tp.Stop(); err != nil {
+			tc.ErrLog(err).Error("Cannot stop the created topology")
+		}
+		return nil, err
 	}
 	tb.UDSStorage = tc.udsStorage
 
@@ -145,24 +214,15 @@ func (tc *topologies) Create(rw web.ResponseWriter, req *web.Request) {
 		if err := tp.Stop(); err != nil {
 			tc.ErrLog(err).Error("Cannot stop the created topology")
 		}
-
-		if os.IsExist(err) {
-			tc.Log().Error("the name is already registered")
-			e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
-				http.StatusBadRequest, nil)
-			e.Meta["name"] = []string{"already taken"}
-			tc.RenderError(e)
-			return
+		return nil, err
+	}
+	if tc.recorder != nil {
+		if err := tc.recorder.Create(name, conf); err != nil {
+			tc.ErrLog(err).Error("Cannot persist the definition of the topology")
 		}
-		tc.ErrLog(err).WithField("err", err).Error("Cannot register the topology")
-		tc.Render(jasco.NewInternalServerError(err))
-		return
 	}
-
-	// TODO: return 201
-	tc.Render(map[string]interface{}{
-		"topology": response.NewTopology(tb.Topology()),
-	})
+	tc.hooks.emit(topologyCreatedEvent, name)
+	return tb, nil
 }
 
 // Index returned a list of registered topologies.
@@ -174,10 +234,21 @@ func (tc *topologies) Index(rw web.ResponseWriter, req *web.Request) {
 		return
 	}
 
-	res := []*response.Topology{}
+	res := []interface{}{}
 	for _, tb := range ts {
 		res = append(res, response.NewTopology(tb.Topology()))
 	}
+	if tc.hibernator != nil {
+		for _, name := range tc.hibernator.Hibernated() {
+			res = append(res, &response.Topology{
+				Name:       name,
+				Hibernated: true,
+			})
+		}
+	}
+	if tc.coordinator != nil {
+		res = append(res, tc.workerTopologies()...)
+	}
 	tc.Render(map[string]interface{}{
 		"topologies": res,
 	})
@@ -194,23 +265,89 @@ func (tc *topologies) Show(rw web.ResponseWriter, req *web.Request) {
 	})
 }
 
+// Graph returns the graph of nodes and edges of the topology. It returns
+// the graph in the DOT language of Graphviz when format=dot is given as a
+// query parameter. Otherwise, it returns a JSON graph which can be given to
+// D3.
+func (tc *topologies) Graph(rw web.ResponseWriter, req *web.Request) {
+	tb := tc.fetchTopology()
+	if tb == nil {
+		return
+	}
+
+	g := response.NewTopologyGraph(tb.Topology())
+	switch format := req.URL.Query().Get("format"); format {
+	case "", "json":
+		tc.Render(map[string]interface{}{
+			"graph": g,
+		})
+
+	case "dot":
+		rw.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		if _, err := rw.Write([]byte(g.DOT())); err != nil {
+			tc.ErrLog(err).Info("Cannot write the graph")
+		}
+
+	default:
+		tc.Log().WithField("format", format).Error("Unsupported graph format")
+		tc.RenderError(jasco.NewError(formValidationErrorCode, "'format' must be 'json' or 'dot'",
+			http.StatusBadRequest, nil))
+	}
+}
+
+// Types returns types of sources and sinks which can be created in the
+// topology with schemas of their parameters.
+func (tc *topologies) Types(rw web.ResponseWriter, req *web.Request) {
+	tb := tc.fetchTopology()
+	if tb == nil {
+		return
+	}
+
+	srcs, err := tb.SourceCreators.List()
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot list source types")
+		tc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+	sinks, err := tb.SinkCreators.List()
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot list sink types")
+		tc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+
+	srcTypes := make([]*response.IOType, 0, len(srcs))
+	for name, c := range srcs {
+		srcTypes = append(srcTypes, response.NewIOType(name, bql.ParamSchemaOf(c)))
+	}
+	sort.Sort(ioTypesByName(srcTypes))
+	sinkTypes := make([]*response.IOType, 0, len(sinks))
+	for name, c := range sinks {
+		sinkTypes = append(sinkTypes, response.NewIOType(name, bql.ParamSchemaOf(c)))
+	}
+	sort.Sort(ioTypesByName(sinkTypes))
+
+	tc.Render(map[string]interface{}{
+		"sources": srcTypes,
+		"sinks":   sinkTypes,
+	})
+}
+
+type ioTypesByName []*response.IOType
+
+func (t ioTypesByName) Len() int           { return len(t) }
+func (t ioTypesByName) Less(i, j int) bool { return t[i].Name < t[j].Name }
+func (t ioTypesByName) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
 // TODO: provide Update action (change state of the topology, etc.)
 
 func (tc *topologies) Destroy(rw web.ResponseWriter, req *web.Request) {
-	tb, err := tc.topologies.Unregister(tc.topologyName)
-	isNotExist := core.IsNotExist(err)
-	if err != nil && !isNotExist {
+	stopped, err := tc.destroyTopology(tc.topologyName)
+	if err != nil {
 		tc.ErrLog(err).Error("Cannot unregister the topology")
 		tc.RenderError(jasco.NewInternalServerError(err))
 		return
 	}
-	stopped := true
-	if tb != nil {
-		if err := tb.Topology().Stop(); err != nil {
-			stopped = false
-			tc.ErrLog(err).Error("Cannot stop the topology")
-		}
-	}
 
 	if stopped {
 		// TODO: return 204 when the topology didn't exist.
@@ -224,6 +361,32 @@ func (tc *topologies) Destroy(rw web.ResponseWriter, req *web.Request) {
 	}
 }
 
+// destroyTopology unregisters the topology and stops it. It doesn't return
+// an error when the topology doesn't exist. stopped is false when the topology
+// couldn't be stopped correctly.
+func (tc *topologies) destroyTopology(name string) (stopped bool, err error) {
+	tb, err := tc.topologies.Unregister(name)
+	if err != nil {
+		if core.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if tc.recorder != nil {
+		if err := tc.recorder.Remove(name); err != nil {
+			tc.ErrLog(err).Error("Cannot persist the removal of the topology")
+		}
+	}
+	stopped = true
+	if err := tb.Topology().Stop(); err != nil {
+		tc.ErrLog(err).Error("Cannot stop the topology")
+		stopped = false
+	}
+	tc.hooks.emit(topologyDestroyedEvent, name)
+	tc.hooks.removeTopology(name)
+	return stopped, nil
+}
+
 func (tc *topologies) Queries(rw web.ResponseWriter, req *web.Request) {
 	tb := tc.fetchTopology()
 	if tb == nil {
@@ -261,31 +424,56 @@ func (tc *topologies) Queries(rw web.ResponseWriter, req *web.Request) {
 	} else {
 		stmts = ss
 	}
+	if err := tc.authorizeStmts(stmts); err != nil {
+		tc.RenderError(err)
+		return
+	}
+
+	if d, ok := form["deliver_to"]; ok {
+		if !tc.role.canModify() {
+			tc.RenderError(tc.permissionDeniedError(fmt.Errorf("%v cannot deliver results to a sink", tc.role)))
+			return
+		}
+		tc.handleDelivery(stmts, d)
+		return
+	}
+
+	snapshot, ok := tc.parseSelectSnapshotOptions(req)
+	if !ok {
+		return
+	}
+	enc := parseSelectEncoding(req)
 
 	if len(stmts) == 1 {
 		stmtStr := fmt.Sprint(stmts[0])
+		if snapshot != nil {
+			switch stmt := stmts[0].(type) {
+			case parser.SelectStmt:
+				tc.handleSelectSnapshot(parser.SelectUnionStmt{[]parser.SelectStmt{stmt}}, stmtStr, snapshot)
+				return
+			case parser.SelectUnionStmt:
+				tc.handleSelectSnapshot(stmt, stmtStr, snapshot)
+				return
+			}
+		}
 		if stmt, ok := stmts[0].(parser.SelectStmt); ok {
-			tc.handleSelectStmt(rw, stmt, stmtStr)
+			tc.handleSelectStmt(rw, stmt, stmtStr, enc)
 			return
 		} else if stmt, ok := stmts[0].(parser.SelectUnionStmt); ok {
-			tc.handleSelectUnionStmt(rw, stmt, stmtStr)
+			tc.handleSelectUnionStmt(rw, stmt, stmtStr, enc)
 			return
 		} else if stmt, ok := stmts[0].(parser.EvalStmt); ok {
 			tc.handleEvalStmt(rw, stmt, stmtStr)
 			return
+		} else if stmt, ok := stmts[0].(parser.DescribeStmt); ok {
+			tc.handleDescribeStmt(rw, stmt, stmtStr)
+			return
 		}
 	}
 
 	// TODO: handle this atomically
 	for _, stmt := range stmts {
-		// TODO: change the return value of AddStmt to support the new response format.
-		_, err := tb.AddStmt(stmt)
-		if err != nil {
-			tc.ErrLog(err).Error("Cannot process a statement")
-			e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
-			e.Meta["error"] = err.Error()
-			e.Meta["statement"] = fmt.Sprint(stmt)
-			tc.RenderError(e)
+		if _, ok := tc.addStmt(req, stmt); !ok {
 			return
 		}
 	}
@@ -336,6 +524,8 @@ func (tc *topologies) parseQueries(form data.Map) ([]interface{}, *jasco.Error)
 			dataReturningStmtIndex = len(stmts)
 		} else if _, ok := stmt.(parser.EvalStmt); ok {
 			dataReturningStmtIndex = len(stmts)
+		} else if _, ok := stmt.(parser.DescribeStmt); ok {
+			dataReturningStmtIndex = len(stmts)
 		}
 
 		stmts = append(stmts, stmt)
@@ -344,10 +534,10 @@ func (tc *topologies) parseQueries(form data.Map) ([]interface{}, *jasco.Error)
 
 	if dataReturningStmtIndex >= 0 {
 		if len(stmts) != 1 {
-			errMsg := "A SELECT or EVAL statement cannot be issued with other statements"
+			errMsg := "A SELECT, EVAL, or DESCRIBE statement cannot be issued with other statements"
 			tc.Log().Error(errMsg)
 			e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, nil)
-			e.Meta["error"] = "a SELECT or EVAL statement cannot be issued with other statements"
+			e.Meta["error"] = "a SELECT, EVAL, or DESCRIBE statement cannot be issued with other statements"
 			e.Meta["statement"] = fmt.Sprint(stmts[dataReturningStmtIndex])
 			return nil, e
 		}
@@ -355,12 +545,100 @@ func (tc *topologies) parseQueries(form data.Map) ([]interface{}, *jasco.Error)
 	return stmts, nil
 }
 
-func (tc *topologies) handleSelectStmt(rw web.ResponseWriter, stmt parser.SelectStmt, stmtStr string) {
+// handleDelivery delivers results of a SELECT statement to a sink specified
+// by the deliver_to field instead of the connection. The deliver_to field
+// must be an object having the type of the sink, an optional name of the sink,
+// and parameters of the sink. The sink remains in the topology until it's
+// dropped by a DROP SINK statement.
+func (tc *topologies) handleDelivery(stmts []interface{}, d data.Value) {
+	tb := tc.fetchTopology()
+	if tb == nil { // just in case
+		return
+	}
+
+	deliveryErr := func(msg string, err error) {
+		tc.ErrLog(err).Error("Invalid delivery destination")
+		e := jasco.NewError(formValidationErrorCode, "The request body is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta["deliver_to"] = []string{msg}
+		tc.RenderError(e)
+	}
+
+	var stmt parser.SelectUnionStmt
+	if len(stmts) != 1 {
+		deliveryErr("only one SELECT statement can be delivered", nil)
+		return
+	}
+	switch s := stmts[0].(type) {
+	case parser.SelectStmt:
+		stmt = parser.SelectUnionStmt{Selects: []parser.SelectStmt{s}}
+	case parser.SelectUnionStmt:
+		stmt = s
+	default:
+		deliveryErr("only a SELECT statement can be delivered", nil)
+		return
+	}
+
+	m, err := data.AsMap(d)
+	if err != nil {
+		deliveryErr("value must be an object", err)
+		return
+	}
+	params := data.Map{}
+	var typeName, name string
+	for k, v := range m {
+		switch k {
+		case "type":
+			if typeName, err = data.AsString(v); err != nil {
+				deliveryErr("'type' must be a string", err)
+				return
+			}
+		case "name":
+			if name, err = data.AsString(v); err != nil {
+				deliveryErr("'name' must be a string", err)
+				return
+			}
+		default:
+			params[k] = v
+		}
+	}
+	if typeName == "" {
+		deliveryErr("'type' is missing", nil)
+		return
+	}
+
+	stmtStr := fmt.Sprint(stmts[0])
+	sn, err := tb.DeliverSelectUnionStmt(&stmt, name, typeName, params)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot process a statement")
+		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = stmtStr
+		tc.RenderError(e)
+		return
+	}
+	tc.Log().WithFields(logrus.Fields{
+		"statement": stmtStr,
+		"sink":      sn.Name(),
+	}).Info("Delivering results of a SELECT statement to a sink")
+
+	// TODO: support the new format
+	tc.Render(map[string]interface{}{
+		"topology_name": tc.topologyName,
+		"status":        "running",
+		"delivery": map[string]interface{}{
+			"sink": sn.Name(),
+			"type": typeName,
+		},
+	})
+}
+
+func (tc *topologies) handleSelectStmt(rw web.ResponseWriter, stmt parser.SelectStmt, stmtStr string, enc selectEncoding) {
 	tmpStmt := parser.SelectUnionStmt{[]parser.SelectStmt{stmt}}
-	tc.handleSelectUnionStmt(rw, tmpStmt, stmtStr)
+	tc.handleSelectUnionStmt(rw, tmpStmt, stmtStr, enc)
 }
 
-func (tc *topologies) handleSelectUnionStmt(rw web.ResponseWriter, stmt parser.SelectUnionStmt, stmtStr string) {
+func (tc *topologies) handleSelectUnionStmt(rw web.ResponseWriter, stmt parser.SelectUnionStmt, stmtStr string, enc selectEncoding) {
 	tb := tc.fetchTopology()
 	if tb == nil { // just in case
 		return
@@ -369,25 +647,24 @@ func (tc *topologies) handleSelectUnionStmt(rw web.ResponseWriter, stmt parser.S
 	sn, ch, err := tb.AddSelectUnionStmt(&stmt)
 	if err != nil {
 		tc.ErrLog(err).Error("Cannot process a statement")
-		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+		e := stmtProcessingError(err)
 		e.Meta["error"] = err.Error()
 		e.Meta["statement"] = stmtStr
 		tc.RenderError(e)
 		return
 	}
-	defer func() {
-		go func() {
-			// vacuum all tuples to avoid blocking the sink.
-			for _ = range ch {
-			}
-		}()
-		if err := sn.Stop(); err != nil {
-			tc.ErrLog(err).WithFields(logrus.Fields{
-				"node_type": core.NTSink,
-				"node_name": sn.Name(),
-			}).Error("Cannot stop the temporary sink")
+	if tc.resumer != nil {
+		r, err := tc.resumer.start(tc.topologyName, stmtStr, sn, ch)
+		if err != nil {
+			tc.stopTemporarySink(sn, ch)
+			tc.ErrLog(err).Error("Cannot make the SELECT statement resumable")
+			tc.RenderError(jasco.NewInternalServerError(err))
+			return
 		}
-	}()
+		tc.streamResumableSelect(rw, r, false, enc)
+		return
+	}
+	defer tc.stopTemporarySink(sn, ch)
 
 	conn, bufrw, err := rw.Hijack()
 	if err != nil {
@@ -395,49 +672,21 @@ func (tc *topologies) handleSelectUnionStmt(rw web.ResponseWriter, stmt parser.S
 		tc.RenderError(jasco.NewInternalServerError(err))
 		return
 	}
+	mw := newSelectMultipartWriter(tc, conn, bufrw, stmtStr, enc)
+	defer mw.close()
 
-	var (
-		writeErr error
-		readErr  error
-	)
-	mw := multipart.NewWriter(bufrw)
-	defer func() {
-		if writeErr != nil {
-			tc.ErrLog(writeErr).Info("Cannot write contents to the hijacked connection")
-		}
-
-		if err := mw.Close(); err != nil {
-			if writeErr == nil && readErr == nil { // log it only when the write err hasn't happend
-				tc.ErrLog(err).Info("Cannot finish the multipart response")
-			}
-		}
-		bufrw.Flush()
-		conn.Close()
-
-		tc.Log().WithField("statement", stmtStr).Info("Finish streaming SELECT responses")
-	}()
-
-	res := []string{
+	if !mw.writeHeader([]string{
 		"HTTP/1.1 200 OK",
-		fmt.Sprintf(`Content-Type: multipart/mixed; boundary="%v"`, mw.Boundary()),
-		"\r\n",
-	}
-	if _, err := bufrw.WriteString(strings.Join(res, "\r\n")); err != nil {
-		tc.ErrLog(err).Error("Cannot write a header to the hijacked connection")
+		fmt.Sprintf(`Content-Type: multipart/mixed; boundary="%v"`, mw.boundary()),
+	}) {
 		return
 	}
-	bufrw.Flush()
 
 	tc.Log().WithField("statement", stmtStr).Info("Start streaming SELECT responses")
 
-	// All error reporting logs after this is info level because they might be
-	// caused by the client closing the connection.
 	header := textproto.MIMEHeader{}
 	header.Add("Content-Type", "application/json")
 
-	readPoll := time.After(1 * time.Minute)
-	sent := false
-	dummyReadBuf := make([]byte, 1024)
 	for {
 		var t *core.Tuple
 		select {
@@ -446,59 +695,28 @@ func (tc *topologies) handleSelectUnionStmt(rw web.ResponseWriter, stmt parser.S
 				return
 			}
 			t = v
-			sent = true
-		case <-readPoll:
-			if sent {
-				sent = false
-				readPoll = time.After(1 * time.Minute)
-				continue
-			}
-
-			// Assuming there's no more data to be read. Because no tuple was
-			// written for past 1 minute, blocking read for 1ms here isn't a
-			// big deal.
-			// TODO: is there any better way to detect disconnection?
-			// TODO: If general errors are checked before checking the deadline,
-			//       this code doesn't have to add 1ms.
-			if err := conn.SetReadDeadline(time.Now().Add(1 * time.Millisecond)); err != nil {
-				tc.ErrLog(err).Error("Cannot check the status of connection due to the failure of conn.SetReadDeadline. Stopping streaming.")
-				// This isn't handled as a read error because some operating
-				// systems don't support SetReadDeadline.
-				return
-			}
-			if _, err := bufrw.Read(dummyReadBuf); err != nil {
-				type timeout interface {
-					Timeout() bool
-				}
-				if e, ok := err.(timeout); !ok || !e.Timeout() {
-					// Something happened on this connection.
-					readErr = err
-					tc.ErrLog(err).Error("The connection may be closed from the client side")
-					return
-				}
-			}
-			readPoll = time.After(1 * time.Minute)
-			continue
-		}
-
-		js := t.Data.String()
-		// TODO: don't forget to convert \n to \r\n when returning
-		// pretty-printed JSON objects.
-		header.Set("Content-Length", fmt.Sprint(len(js)))
-
-		w, err := mw.CreatePart(header)
-		if err != nil {
-			writeErr = err
+		case <-mw.disconnectedCh():
 			return
 		}
-		if _, err := io.WriteString(w, js); err != nil {
-			writeErr = err
+
+		if !mw.writePart(header, t.Data.String()) {
 			return
 		}
-		if err := bufrw.Flush(); err != nil {
-			writeErr = err
-			return
+	}
+}
+
+// stopTemporarySink stops the sink created for a SELECT statement.
+func (tc *topologies) stopTemporarySink(sn core.SinkNode, ch <-chan *core.Tuple) {
+	go func() {
+		// vacuum all tuples to avoid blocking the sink.
+		for _ = range ch {
 		}
+	}()
+	if err := sn.Stop(); err != nil {
+		tc.ErrLog(err).WithFields(logrus.Fields{
+			"node_type": core.NTSink,
+			"node_name": sn.Name(),
+		}).Error("Cannot stop the temporary sink")
 	}
 }
 
@@ -524,6 +742,28 @@ func (tc *topologies) handleEvalStmt(rw web.ResponseWriter, stmt parser.EvalStmt
 	})
 }
 
+func (tc *topologies) handleDescribeStmt(rw web.ResponseWriter, stmt parser.DescribeStmt, stmtStr string) {
+	tb := tc.fetchTopology()
+	if tb == nil { // just in case
+		return
+	}
+
+	result, err := tb.RunDescribeStmt(&stmt)
+	if err != nil {
+		tc.ErrLog(err).Error("Cannot process a statement")
+		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = stmtStr
+		tc.RenderError(e)
+		return
+	}
+
+	// the result is wrapped in the same way as EVAL
+	tc.Render(map[string]interface{}{
+		"result": result,
+	})
+}
+
 // WebSocketQueries handles requests using WebSocket. A single WebSocket
 // connection can concurrently issue multiple requests including requests
 // containing a SELECT statement.
@@ -584,6 +824,13 @@ func (tc *topologies) handleEvalStmt(rw web.ResponseWriter, stmt parser.EvalStmt
 // "eos", end of stream, responses are sent when SELECT statements has sent all
 // tuples. "payload" of "eos" is always null. "eos" isn't sent when an error
 // occurred.
+//
+// A WebSocket connection is a session of the topology. Nodes created by
+// CREATE TEMPORARY statements via the connection are removed when it's closed.
+//
+// When the client offers the "sensorbee.msgpack" subprotocol, requests and
+// responses are sent as msgpack binary frames having the same structure as
+// JSON ones.
 func (tc *topologies) WebSocketQueries(rw web.ResponseWriter, req *web.Request) {
 	// TODO: add a document describing which BQL statement returns which result.
 	if !strings.EqualFold(req.Header.Get("Upgrade"), "WebSocket") {
@@ -602,19 +849,32 @@ func (tc *topologies) WebSocketQueries(rw web.ResponseWriter, req *web.Request)
 	tc.Log().Info("Begin WebSocket connection")
 	defer tc.Log().Info("End WebSocket connection")
 
-	websocket.Handler(func(conn *websocket.Conn) {
-		for tc.processWebSocketMessage(conn, tb) {
+	// Temporary nodes created via this connection are removed when the
+	// connection is closed.
+	session := tb.NewSessionAs(requestOwner(req))
+	defer func() {
+		if err := session.Close(); err != nil {
+			tc.ErrLog(err).Error("Cannot remove temporary nodes of the WebSocket connection")
 		}
-	}).ServeHTTP(rw, req.Request)
+	}()
+
+	websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			for tc.processWebSocketMessage(conn, session) {
+			}
+		},
+		Handshake: webSocketHandshake,
+	}.ServeHTTP(rw, req.Request)
 }
 
 // processWebSocketMessage processes a request from the client. It returns true
 // if the caller can call this method again, in other words, the connection is
 // still alive.
-func (tc *topologies) processWebSocketMessage(conn *websocket.Conn, tb *bql.TopologyBuilder) bool {
+func (tc *topologies) processWebSocketMessage(conn *websocket.Conn, session *bql.Session) bool {
 	w := &webSocketTopologyQueryHandler{
-		tc:   tc,
-		conn: conn,
+		tc:      tc,
+		conn:    conn,
+		msgpack: usesMsgpack(conn),
 	}
 
 	var js map[string]interface{}
@@ -695,6 +955,9 @@ func (tc *topologies) processWebSocketMessage(conn *websocket.Conn, tb *bql.Topo
 	} else {
 		stmts = ss
 	}
+	if err := tc.authorizeStmts(stmts); err != nil {
+		return w.sendErr(err)
+	}
 
 	// Although these requests may fail asynchronously, the connect is probably
 	// still alive and next processWebSocketMessage can detect disconnection.
@@ -711,21 +974,32 @@ func (tc *topologies) processWebSocketMessage(conn *websocket.Conn, tb *bql.Topo
 			} else if stmt, ok := stmts[0].(parser.EvalStmt); ok {
 				w.handleEvalStmtWebSocket(conn, stmt, stmtStr)
 				return
+			} else if stmt, ok := stmts[0].(parser.DescribeStmt); ok {
+				w.handleDescribeStmtWebSocket(conn, stmt, stmtStr)
+				return
 			}
 		}
 
 		// TODO: handle this atomically
 		for _, stmt := range stmts {
 			// TODO: change the return value of AddStmt to support the new response format.
-			_, err = tb.AddStmt(stmt)
+			_, err = session.AddStmt(stmt)
 			if err != nil {
 				w.ErrLog(err).Error("Cannot process a statement")
-				e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+				e := stmtProcessingError(err)
 				e.Meta["error"] = err.Error()
 				e.Meta["statement"] = fmt.Sprint(stmt)
 				w.sendErr(e)
 				return
 			}
+			// Temporary nodes are removed when the session is closed and
+			// cannot be created without a session when the topology is
+			// restored.
+			if w.tc.recorder != nil && !bql.IsTemporaryStmt(stmt) {
+				if err := w.tc.recorder.Append(w.tc.topologyName, fmt.Sprint(stmt)); err != nil {
+					w.ErrLog(err).Error("Cannot persist the statement")
+				}
+			}
 		}
 
 		// TODO: define a proper response format
@@ -740,6 +1014,9 @@ type webSocketTopologyQueryHandler struct {
 	tc   *topologies
 	conn *websocket.Conn
 	rid  int64
+
+	// msgpack is true when messages are encoded in msgpack.
+	msgpack bool
 }
 
 func (w *webSocketTopologyQueryHandler) Log() *logrus.Entry {
@@ -750,15 +1027,34 @@ func (w *webSocketTopologyQueryHandler) ErrLog(err error) *logrus.Entry {
 	return w.tc.ErrLog(err).WithField("wsreqid", w.rid)
 }
 
-func (w *webSocketTopologyQueryHandler) receive(v interface{}) error {
-	return websocket.JSON.Receive(w.conn, v)
+func (w *webSocketTopologyQueryHandler) receive(v *map[string]interface{}) error {
+	if !w.msgpack {
+		return websocket.JSON.Receive(w.conn, v)
+	}
+	var m data.Map
+	if err := msgpackWebSocketCodec.Receive(w.conn, &m); err != nil {
+		return err
+	}
+	*v = data.NewIMap(m)
+	return nil
 }
 
 func (w *webSocketTopologyQueryHandler) send(msgType string, v interface{}) error {
-	return websocket.JSON.Send(w.conn, map[string]interface{}{
-		"rid":     w.rid,
-		"type":    msgType,
-		"payload": v,
+	if !w.msgpack {
+		return websocket.JSON.Send(w.conn, map[string]interface{}{
+			"rid":     w.rid,
+			"type":    msgType,
+			"payload": v,
+		})
+	}
+	p, err := toMsgpackPayload(v)
+	if err != nil {
+		return err
+	}
+	return msgpackWebSocketCodec.Send(w.conn, data.Map{
+		"rid":     data.Int(w.rid),
+		"type":    data.String(msgType),
+		"payload": p,
 	})
 }
 
@@ -788,7 +1084,7 @@ func (w *webSocketTopologyQueryHandler) handleSelectUnionStmtWebSocket(conn *web
 	sn, ch, err := tb.AddSelectUnionStmt(&stmt)
 	if err != nil {
 		w.ErrLog(err).Error("Cannot process a statement")
-		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+		e := stmtProcessingError(err)
 		e.Meta["error"] = err.Error()
 		e.Meta["statement"] = stmtStr
 		w.sendErr(e)
@@ -876,3 +1172,27 @@ func (w *webSocketTopologyQueryHandler) handleEvalStmtWebSocket(conn *websocket.
 		return
 	}
 }
+
+func (w *webSocketTopologyQueryHandler) handleDescribeStmtWebSocket(conn *websocket.Conn, stmt parser.DescribeStmt, stmtStr string) {
+	tb := w.tc.fetchTopology()
+	if tb == nil { // just in case
+		return
+	}
+
+	result, err := tb.RunDescribeStmt(&stmt)
+	if err != nil {
+		w.ErrLog(err).Error("Cannot process a statement")
+		e := jasco.NewError(bqlStmtProcessingErrorCode, "Cannot process a statement", http.StatusBadRequest, err)
+		e.Meta["error"] = err.Error()
+		e.Meta["statement"] = stmtStr
+		w.sendErr(e)
+		return
+	}
+
+	if err := w.send("result", map[string]interface{}{
+		"result": result,
+	}); err != nil {
+		w.ErrLog(err).Error("Cannot send data to the WebSocket client")
+		return
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/websocket"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestWebSocketTemporaryNodesRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sbtest_ws_temporary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src.jsonl")
+	if err := ioutil.WriteFile(src, []byte(`{"a":1}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given an API server persisting topologies", t, func() {
+		conf := data.Map{
+			"storage": data.Map{
+				"topologies": data.Map{
+					"type": data.String("fs"),
+					"params": data.Map{
+						"path": data.String(filepath.Join(dir, "topologies")),
+					},
+				},
+			},
+		}
+		s := newTestServer(conf)
+		Reset(func() {
+			s.Close()
+			os.Remove(filepath.Join(dir, "topologies"))
+		})
+		res, _ := s.request("POST", "/topologies", map[string]interface{}{"name": "test"})
+		So(res.StatusCode, ShouldEqual, http.StatusOK)
+
+		Convey("When a temporary node is created over a WebSocket before other nodes", func() {
+			url := "ws" + s.URL[len("http"):] + "/api/v1/topologies/test/wsqueries"
+			conn, err := websocket.Dial(url, "", s.URL)
+			So(err, ShouldBeNil)
+			for i, q := range []string{
+				fmt.Sprintf(`CREATE PAUSED SOURCE src TYPE file WITH path="%v";`, src),
+				`CREATE TEMPORARY STREAM tmp AS SELECT ISTREAM * FROM src [RANGE 1 TUPLES];`,
+				`CREATE STREAM s AS SELECT ISTREAM * FROM src [RANGE 1 TUPLES];`,
+			} {
+				So(websocket.JSON.Send(conn, map[string]interface{}{
+					"rid": i + 1,
+					"payload": map[string]interface{}{
+						"queries": q,
+					},
+				}), ShouldBeNil)
+				var js map[string]interface{}
+				So(websocket.JSON.Receive(conn, &js), ShouldBeNil)
+				So(js["type"], ShouldEqual, "result")
+			}
+			So(conn.Close(), ShouldBeNil)
+
+			tb, err := s.gvars.Topologies.Lookup("test")
+			So(err, ShouldBeNil)
+			deadline := time.Now().Add(5 * time.Second)
+			for time.Now().Before(deadline) {
+				if _, err := tb.Topology().Node("tmp"); err != nil {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			_, err = tb.Topology().Node("tmp")
+			So(err, ShouldNotBeNil)
+
+			Convey("Then the temporary node shouldn't be recorded", func() {
+				_, stmts, ok := s.gvars.Recorder.Definition("test")
+				So(ok, ShouldBeTrue)
+				So(stmts, ShouldHaveLength, 2)
+				for _, stmt := range stmts {
+					So(stmt, ShouldNotContainSubstring, "TEMPORARY")
+				}
+			})
+
+			Convey("Then other nodes should be restored after restarting the server", func() {
+				s.Close()
+				s = newTestServer(conf)
+
+				tb, err := s.gvars.Topologies.Lookup("test")
+				So(err, ShouldBeNil)
+				for _, name := range []string{"src", "s"} {
+					_, err := tb.Topology().Node(name)
+					So(err, ShouldBeNil)
+				}
+				_, err = tb.Topology().Node("tmp")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
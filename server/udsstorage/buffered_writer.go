@@ -0,0 +1,147 @@
+package udsstorage
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	// udsChunkSize is the size of a chunk of a state stored by storages
+	// which cannot store a large value at once, such as Redis or SQL
+	// databases. Because a chunk is read into memory at once, it shouldn't
+	// be too large.
+	udsChunkSize = 4 * 1024 * 1024
+)
+
+// bufferedUDSStorageWriter is a UDSStorageWriter which buffers a state in a
+// temporary file and passes it to commit at once. It's used by storages
+// which cannot write a state partially, such as remote storages. Because
+// the state is buffered in a file, a large state can be saved without
+// holding it in memory.
+type bufferedUDSStorageWriter struct {
+	f      *os.File
+	w      *bufio.Writer
+	size   int64
+	commit func(f *os.File, size int64) error
+}
+
+func newBufferedUDSStorageWriter(commit func(f *os.File, size int64) error) (*bufferedUDSStorageWriter, error) {
+	f, err := ioutil.TempFile("", "sensorbee-uds-")
+	if err != nil {
+		return nil, err
+	}
+	return &bufferedUDSStorageWriter{
+		f:      f,
+		w:      bufio.NewWriter(f),
+		commit: commit,
+	}, nil
+}
+
+func (w *bufferedUDSStorageWriter) Write(data []byte) (int, error) {
+	if w.f == nil {
+		return 0, errors.New("writer is already closed")
+	}
+	n, err := w.w.Write(data)
+	w.size += int64(n)
+	return n, err
+}
+
+// Commit passes the buffered state to the commit function. The file is
+// rewound to the beginning before it's passed.
+func (w *bufferedUDSStorageWriter) Commit() error {
+	if w.f == nil {
+		return errors.New("writer is already closed")
+	}
+	defer w.close()
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := w.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return w.commit(w.f, w.size)
+}
+
+func (w *bufferedUDSStorageWriter) Abort() error {
+	if w.f == nil {
+		return errors.New("writer is already closed")
+	}
+	w.close()
+	return nil
+}
+
+// close removes the temporary file.
+func (w *bufferedUDSStorageWriter) close() {
+	w.f.Close()
+	os.Remove(w.f.Name())
+	w.f = nil
+	w.w = nil
+}
+
+// chunkReader reads a state stored as chunks. fetch returns the i-th chunk
+// and it returns io.EOF when there's no more chunk.
+type chunkReader struct {
+	fetch func(i int) ([]byte, error)
+	close func() error
+	next  int
+	buf   []byte
+	err   error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.buf, r.err = r.fetch(r.next)
+		r.next++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *chunkReader) Close() error {
+	r.err = errors.New("reader is already closed")
+	r.buf = nil
+	if r.close != nil {
+		return r.close()
+	}
+	return nil
+}
+
+// readChunks calls f with each chunk of the reader.
+func readChunks(r io.Reader, f func(i int, chunk []byte) error) error {
+	buf := make([]byte, udsChunkSize)
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := f(i, buf[:n]); err != nil {
+				return err
+			}
+		}
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+// newSaveID returns a random ID identifying each save of a state. It's used
+// by storages storing a state as chunks to detect that the state is
+// overwritten while it's being loaded.
+func newSaveID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,369 @@
+package udsstorage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+)
+
+// encryptedUDSStorage is a UDSStorage which encrypts states with AES-GCM
+// before saving them to another UDSStorage. Each state is sealed with a
+// random nonce and its topology name, state name, and tag as additional
+// authenticated data, so a state file copied to a different name cannot be
+// loaded.
+//
+// A state is split into segments of encryptedUDSSegmentSize bytes and each
+// segment is sealed separately, so a large state can be saved and loaded
+// without holding it in memory. Note that a part of the state can be read
+// from the reader returned by Load before a corrupted segment is detected.
+type encryptedUDSStorage struct {
+	s    udf.UDSStorage
+	aead cipher.AEAD
+}
+
+var (
+	_ udf.UDSStorage = &encryptedUDSStorage{}
+
+	// encryptedUDSHeader is written at the beginning of each encrypted state
+	// to detect a state which isn't encrypted or encrypted in another format.
+	encryptedUDSHeader = []byte("SBENC2")
+
+	// encryptedUDSLegacyHeader is the header of states sealed at once by
+	// older versions. Such states can still be loaded.
+	encryptedUDSLegacyHeader = []byte("SBENC1")
+
+	// encryptedUDSSegmentSize is the size of plaintext in each segment.
+	encryptedUDSSegmentSize = 64 * 1024
+)
+
+// NewEncrypted returns a UDSStorage which encrypts states and saves them to
+// the given UDSStorage. The key must be 16, 24, or 32 bytes long to select
+// AES-128, AES-192, or AES-256, respectively.
+func NewEncrypted(s udf.UDSStorage, key []byte) (udf.UDSStorage, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(b)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedUDSStorage{
+		s:    s,
+		aead: aead,
+	}, nil
+}
+
+// ReadEncryptionKey reads a base64 encoded key from a file or an environment
+// variable. One of keyFile and keyEnv must be specified.
+func ReadEncryptionKey(keyFile, keyEnv string) ([]byte, error) {
+	var encoded string
+	switch {
+	case keyFile != "" && keyEnv != "":
+		return nil, errors.New("key_file and key_env cannot be specified at the same time")
+	case keyFile != "":
+		b, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the key file: %v", err)
+		}
+		encoded = string(b)
+	case keyEnv != "":
+		v, ok := os.LookupEnv(keyEnv)
+		if !ok {
+			return nil, fmt.Errorf("the environment variable %v isn't set", keyEnv)
+		}
+		encoded = v
+	default:
+		return nil, errors.New("key_file or key_env must be specified")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("the key isn't encoded in base64: %v", err)
+	}
+	return key, nil
+}
+
+func (s *encryptedUDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter, error) {
+	prefix := make([]byte, s.aead.NonceSize()-5)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return nil, fmt.Errorf("cannot generate a nonce: %v", err)
+	}
+	w, err := s.s.Save(topology, state, tag)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(append([]byte{}, encryptedUDSHeader...), prefix...)); err != nil {
+		w.Abort()
+		return nil, err
+	}
+	return &encryptedUDSStorageWriter{
+		w:   w,
+		seg: newEncryptedUDSSegmenter(s.aead, prefix, encryptedUDSAdditionalData(topology, state, tag)),
+	}, nil
+}
+
+func (s *encryptedUDSStorage) Load(topology, state, tag string) (io.ReadCloser, error) {
+	r, err := s.s.Load(topology, state, tag)
+	if err != nil {
+		return nil, err
+	}
+	ad := encryptedUDSAdditionalData(topology, state, tag)
+
+	header := make([]byte, len(encryptedUDSHeader))
+	if _, err := io.ReadFull(r, header); err != nil {
+		r.Close()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errors.New("the state isn't encrypted")
+		}
+		return nil, err
+	}
+	switch {
+	case bytes.Equal(header, encryptedUDSHeader):
+	case bytes.Equal(header, encryptedUDSLegacyHeader):
+		defer r.Close()
+		return s.loadLegacy(r, ad)
+	default:
+		r.Close()
+		return nil, errors.New("the state isn't encrypted")
+	}
+
+	prefix := make([]byte, s.aead.NonceSize()-5)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		r.Close()
+		return nil, errors.New("the encrypted state is too short")
+	}
+	er := &encryptedUDSStorageReader{
+		r:   r,
+		br:  bufio.NewReader(r),
+		seg: newEncryptedUDSSegmenter(s.aead, prefix, ad),
+	}
+	// The first segment is decrypted here so that a state encrypted with
+	// another key or copied from another name is detected by Load.
+	if err := er.fill(); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return er, nil
+}
+
+// loadLegacy loads a state saved in the "SBENC1" format, which seals the
+// whole state at once.
+func (s *encryptedUDSStorage) loadLegacy(r io.Reader, ad []byte) (io.ReadCloser, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	n := s.aead.NonceSize()
+	if len(b) < n {
+		return nil, errors.New("the encrypted state is too short")
+	}
+	plain, err := s.aead.Open(nil, b[:n], b[n:], ad)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the state: %v", err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(plain)), nil
+}
+
+func (s *encryptedUDSStorage) ListTopologies() ([]string, error) {
+	return s.s.ListTopologies()
+}
+
+func (s *encryptedUDSStorage) List(topology string) (map[string][]string, error) {
+	return s.s.List(topology)
+}
+
+// encryptedUDSAdditionalData returns additional data authenticated with a
+// state. An empty tag is same as "default" as described in UDSStorage.
+func encryptedUDSAdditionalData(topology, state, tag string) []byte {
+	if tag == "" || strings.ToLower(tag) == "default" {
+		tag = "default"
+	}
+	return []byte(fmt.Sprintf("%v\x00%v\x00%v", topology, state, tag))
+}
+
+// encryptedUDSSegmenter seals and opens segments of a state. The nonce of
+// each segment consists of a random prefix shared by the state, the 4-byte
+// big-endian index of the segment, and a byte which is 1 only for the last
+// segment. Segments therefore cannot be reordered, and a truncated state is
+// detected because its last segment doesn't have the flag.
+type encryptedUDSSegmenter struct {
+	aead  cipher.AEAD
+	nonce []byte
+	ad    []byte
+	index uint32
+}
+
+func newEncryptedUDSSegmenter(aead cipher.AEAD, prefix, ad []byte) *encryptedUDSSegmenter {
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, prefix)
+	return &encryptedUDSSegmenter{
+		aead:  aead,
+		nonce: nonce,
+		ad:    ad,
+	}
+}
+
+func (s *encryptedUDSSegmenter) next(last bool) ([]byte, error) {
+	if s.index == math.MaxUint32 {
+		return nil, errors.New("the state is too large to be encrypted")
+	}
+	n := len(s.nonce)
+	binary.BigEndian.PutUint32(s.nonce[n-5:n-1], s.index)
+	s.nonce[n-1] = 0
+	if last {
+		s.nonce[n-1] = 1
+	}
+	s.index++
+	return s.nonce, nil
+}
+
+func (s *encryptedUDSSegmenter) seal(dst, plain []byte, last bool) ([]byte, error) {
+	nonce, err := s.next(last)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(dst, nonce, plain, s.ad), nil
+}
+
+func (s *encryptedUDSSegmenter) open(dst, sealed []byte, last bool) ([]byte, error) {
+	nonce, err := s.next(last)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := s.aead.Open(dst, nonce, sealed, s.ad)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the state: %v", err)
+	}
+	return plain, nil
+}
+
+type encryptedUDSStorageWriter struct {
+	w      udf.UDSStorageWriter
+	seg    *encryptedUDSSegmenter
+	buf    []byte
+	sealed []byte
+	closed bool
+}
+
+func (w *encryptedUDSStorageWriter) Write(data []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("writer is already closed")
+	}
+	n := len(data)
+	for len(data) > 0 {
+		// A full segment is sealed only when more data arrives because
+		// the last segment must be sealed with the flag by Commit.
+		if len(w.buf) == encryptedUDSSegmentSize {
+			if err := w.flush(false); err != nil {
+				return 0, err
+			}
+		}
+		c := encryptedUDSSegmentSize - len(w.buf)
+		if c > len(data) {
+			c = len(data)
+		}
+		w.buf = append(w.buf, data[:c]...)
+		data = data[c:]
+	}
+	return n, nil
+}
+
+func (w *encryptedUDSStorageWriter) flush(last bool) error {
+	sealed, err := w.seg.seal(w.sealed[:0], w.buf, last)
+	if err != nil {
+		return err
+	}
+	w.sealed = sealed
+	w.buf = w.buf[:0]
+	_, err = w.w.Write(sealed)
+	return err
+}
+
+func (w *encryptedUDSStorageWriter) Commit() error {
+	if w.closed {
+		return errors.New("writer is already closed")
+	}
+	w.closed = true
+	if err := w.flush(true); err != nil {
+		w.w.Abort()
+		return err
+	}
+	return w.w.Commit()
+}
+
+func (w *encryptedUDSStorageWriter) Abort() error {
+	if w.closed {
+		return errors.New("writer is already closed")
+	}
+	w.closed = true
+	return w.w.Abort()
+}
+
+type encryptedUDSStorageReader struct {
+	r      io.ReadCloser
+	br     *bufio.Reader
+	seg    *encryptedUDSSegmenter
+	sealed []byte
+	plain  []byte
+	buf    []byte
+	last   bool
+}
+
+// fill decrypts the next segment.
+func (r *encryptedUDSStorageReader) fill() error {
+	if r.sealed == nil {
+		r.sealed = make([]byte, encryptedUDSSegmentSize+r.seg.aead.Overhead())
+	}
+	n, err := io.ReadFull(r.br, r.sealed)
+	switch err {
+	case nil:
+		// The segment is the last one when nothing follows it.
+		if _, err := r.br.Peek(1); err == io.EOF {
+			r.last = true
+		} else if err != nil {
+			return err
+		}
+	case io.EOF, io.ErrUnexpectedEOF:
+		r.last = true
+	default:
+		return err
+	}
+	plain, err := r.seg.open(r.plain[:0], r.sealed[:n], r.last)
+	if err != nil {
+		return err
+	}
+	r.plain = plain
+	r.buf = plain
+	return nil
+}
+
+func (r *encryptedUDSStorageReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.last {
+			return 0, io.EOF
+		}
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *encryptedUDSStorageReader) Close() error {
+	return r.r.Close()
+}
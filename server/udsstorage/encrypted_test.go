@@ -0,0 +1,258 @@
+package udsstorage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestEncrypted(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	Convey("Given an encrypted UDS storage", t, func() {
+		base := udf.NewInMemoryUDSStorage()
+		s, err := NewEncrypted(base, key)
+		So(err, ShouldBeNil)
+
+		w, err := s.Save("test_topology", "state1", "")
+		So(err, ShouldBeNil)
+		_, err = io.WriteString(w, "hoge")
+		So(err, ShouldBeNil)
+		So(w.Commit(), ShouldBeNil)
+
+		Convey("When loading the state", func() {
+			r, err := s.Load("test_topology", "state1", "default")
+			So(err, ShouldBeNil)
+			data, err := ioutil.ReadAll(r)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the right content", func() {
+				So(string(data), ShouldEqual, "hoge")
+			})
+		})
+
+		Convey("When loading the state from the underlying storage", func() {
+			r, err := base.Load("test_topology", "state1", "")
+			So(err, ShouldBeNil)
+			data, err := ioutil.ReadAll(r)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be encrypted", func() {
+				So(bytes.Contains(data, []byte("hoge")), ShouldBeFalse)
+			})
+		})
+
+		Convey("When loading the state with a different key", func() {
+			s2, err := NewEncrypted(base, bytes.Repeat([]byte{2}, 32))
+			So(err, ShouldBeNil)
+			_, err = s2.Load("test_topology", "state1", "")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the state is copied to another name", func() {
+			r, err := base.Load("test_topology", "state1", "")
+			So(err, ShouldBeNil)
+			data, err := ioutil.ReadAll(r)
+			So(err, ShouldBeNil)
+			w, err := base.Save("test_topology", "state2", "")
+			So(err, ShouldBeNil)
+			_, err = w.Write(data)
+			So(err, ShouldBeNil)
+			So(w.Commit(), ShouldBeNil)
+
+			Convey("Then it cannot be loaded", func() {
+				_, err := s.Load("test_topology", "state2", "")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a state isn't encrypted", func() {
+			w, err := base.Save("test_topology", "plain", "")
+			So(err, ShouldBeNil)
+			_, err = io.WriteString(w, "hoge")
+			So(err, ShouldBeNil)
+			So(w.Commit(), ShouldBeNil)
+
+			Convey("Then it cannot be loaded", func() {
+				_, err := s.Load("test_topology", "plain", "")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When saving a state is aborted", func() {
+			w, err := s.Save("test_topology", "state3", "")
+			So(err, ShouldBeNil)
+			_, err = io.WriteString(w, "hoge")
+			So(err, ShouldBeNil)
+			So(w.Abort(), ShouldBeNil)
+
+			Convey("Then the state shouldn't be saved", func() {
+				_, err := s.Load("test_topology", "state3", "")
+				So(core.IsNotExist(err), ShouldBeTrue)
+			})
+
+			Convey("Then the writer cannot be used again", func() {
+				_, err := io.WriteString(w, "hoge")
+				So(err, ShouldNotBeNil)
+				So(w.Commit(), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When saving states having multiple segments", func() {
+			size := encryptedUDSSegmentSize
+			encryptedUDSSegmentSize = 4
+			Reset(func() {
+				encryptedUDSSegmentSize = size
+			})
+			for _, v := range []string{"", "0123", "01234567", "0123456789"} {
+				w, err := s.Save("test_topology", "state"+fmt.Sprint(len(v)), "")
+				So(err, ShouldBeNil)
+				_, err = io.WriteString(w, v)
+				So(err, ShouldBeNil)
+				So(w.Commit(), ShouldBeNil)
+			}
+
+			Convey("Then they should be loaded", func() {
+				for _, v := range []string{"", "0123", "01234567", "0123456789"} {
+					r, err := s.Load("test_topology", "state"+fmt.Sprint(len(v)), "")
+					So(err, ShouldBeNil)
+					data, err := ioutil.ReadAll(r)
+					So(err, ShouldBeNil)
+					So(r.Close(), ShouldBeNil)
+					So(string(data), ShouldEqual, v)
+				}
+			})
+
+			Convey("Then a truncated state cannot be loaded", func() {
+				r, err := base.Load("test_topology", "state10", "")
+				So(err, ShouldBeNil)
+				data, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+				// drop the last segment having 2 bytes
+				data = data[:len(data)-2-16]
+				w, err := base.Save("test_topology", "truncated", "")
+				So(err, ShouldBeNil)
+				_, err = w.Write(data)
+				So(err, ShouldBeNil)
+				So(w.Commit(), ShouldBeNil)
+
+				// The error is reported by Load or Read depending on the
+				// position of the truncated segment.
+				r, err = s.Load("test_topology", "truncated", "")
+				if err == nil {
+					_, err = ioutil.ReadAll(r)
+				}
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a state was encrypted in the legacy format", func() {
+			b, err := aes.NewCipher(key)
+			So(err, ShouldBeNil)
+			aead, err := cipher.NewGCM(b)
+			So(err, ShouldBeNil)
+			nonce := make([]byte, aead.NonceSize())
+			data := append([]byte("SBENC1"), nonce...)
+			data = aead.Seal(data, nonce, []byte("legacy"), encryptedUDSAdditionalData("test_topology", "legacy", ""))
+			w, err := base.Save("test_topology", "legacy", "")
+			So(err, ShouldBeNil)
+			_, err = w.Write(data)
+			So(err, ShouldBeNil)
+			So(w.Commit(), ShouldBeNil)
+
+			Convey("Then it should be loaded", func() {
+				r, err := s.Load("test_topology", "legacy", "")
+				So(err, ShouldBeNil)
+				data, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+				So(string(data), ShouldEqual, "legacy")
+			})
+		})
+
+		Convey("When listing states", func() {
+			l, err := s.List("test_topology")
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the state", func() {
+				So(l, ShouldContainKey, "state1")
+			})
+		})
+	})
+
+	Convey("Given an invalid key", t, func() {
+		_, err := NewEncrypted(udf.NewInMemoryUDSStorage(), []byte("short"))
+
+		Convey("Then the storage shouldn't be created", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestReadEncryptionKey(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	Convey("Given a key file", t, func() {
+		f, err := ioutil.TempFile("", "sensorbee_uds_key")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.Remove(f.Name())
+		})
+		_, err = f.WriteString(encoded + "\n")
+		So(err, ShouldBeNil)
+		So(f.Close(), ShouldBeNil)
+
+		Convey("When reading the key", func() {
+			k, err := ReadEncryptionKey(f.Name(), "")
+
+			Convey("Then it should be decoded", func() {
+				So(err, ShouldBeNil)
+				So(k, ShouldResemble, key)
+			})
+		})
+	})
+
+	Convey("Given a key in an environment variable", t, func() {
+		So(os.Setenv("SENSORBEE_TEST_UDS_KEY", encoded), ShouldBeNil)
+		Reset(func() {
+			os.Unsetenv("SENSORBEE_TEST_UDS_KEY")
+		})
+
+		Convey("When reading the key", func() {
+			k, err := ReadEncryptionKey("", "SENSORBEE_TEST_UDS_KEY")
+
+			Convey("Then it should be decoded", func() {
+				So(err, ShouldBeNil)
+				So(k, ShouldResemble, key)
+			})
+		})
+
+		Convey("When reading a key from an undefined variable", func() {
+			_, err := ReadEncryptionKey("", "SENSORBEE_TEST_UDS_UNDEFINED_KEY")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given no key source", t, func() {
+		_, err := ReadEncryptionKey("", "")
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
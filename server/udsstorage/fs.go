@@ -55,9 +55,8 @@ func validateDir(dir string) error {
 }
 
 func (s *fsUDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter, error) {
-	if tag == "" || strings.ToLower(tag) == "default" {
-		tag = "default"
-	} else if err := core.ValidateSymbol(tag); err != nil {
+	tag, err := normalizeTag(tag)
+	if err != nil {
 		return nil, err
 	}
 
@@ -73,14 +72,25 @@ func (s *fsUDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter,
 	}, nil
 }
 
+// normalizeTag returns "default" when the tag is empty or "default" in any
+// case. Otherwise, it validates the tag as a symbol.
+func normalizeTag(tag string) (string, error) {
+	if tag == "" || strings.ToLower(tag) == "default" {
+		return "default", nil
+	}
+	if err := core.ValidateSymbol(tag); err != nil {
+		return "", err
+	}
+	return tag, nil
+}
+
 func (s *fsUDSStorage) stateTempFile(topology, state, tag string) (*os.File, error) {
 	return ioutil.TempFile(s.tempDirPath, s.stateFilename(topology, state, tag))
 }
 
 func (s *fsUDSStorage) Load(topology, state, tag string) (io.ReadCloser, error) {
-	if tag == "" || strings.ToLower(tag) == "default" {
-		tag = "default"
-	} else if err := core.ValidateSymbol(tag); err != nil {
+	tag, err := normalizeTag(tag)
+	if err != nil {
 		return nil, err
 	}
 
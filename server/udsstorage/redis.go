@@ -0,0 +1,246 @@
+package udsstorage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RedisConfig has parameters of a UDSStorage saving states to Redis.
+type RedisConfig struct {
+	// Address is the address of the Redis server such as "localhost:6379".
+	// It's required.
+	Address string
+
+	// Password is used to authenticate the connection when it isn't empty.
+	Password string
+
+	// DB is the index of the database.
+	DB int
+
+	// Prefix is prepended to all keys used by the storage. It's "sensorbee"
+	// by default.
+	Prefix string
+}
+
+// redisUDSStorage is a UDSStorage which saves states to Redis. A state is
+// stored as a list of chunks whose key is "<prefix>:state:<topology>:<state>:<tag>"
+// so that it can be larger than the maximum size of a string value and it
+// can be loaded without reading it into memory at once. Each chunk is
+// prefixed by the ID of the save to detect that the state is overwritten
+// while it's being loaded. A state saved as a string value by older versions
+// can also be loaded.
+//
+// To list states without scanning keys, the storage also has a set of
+// topologies, "<prefix>:topologies", and a set of states in each topology,
+// "<prefix>:states:<topology>", which has "<state>:<tag>" as its member.
+type redisUDSStorage struct {
+	prefix string
+	do     func(cmd string, args ...interface{}) (interface{}, error)
+}
+
+var (
+	_ udf.UDSStorage = &redisUDSStorage{}
+)
+
+// NewRedis returns a UDSStorage which saves states to Redis.
+func NewRedis(conf *RedisConfig) (udf.UDSStorage, error) {
+	if conf.Address == "" {
+		return nil, fmt.Errorf("address must be specified")
+	}
+	opts := []redis.DialOption{redis.DialDatabase(conf.DB)}
+	if conf.Password != "" {
+		opts = append(opts, redis.DialPassword(conf.Password))
+	}
+	pool := &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 4 * time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", conf.Address, opts...)
+		},
+	}
+
+	// Check the connection so that a wrong configuration is detected at
+	// startup.
+	c := pool.Get()
+	defer c.Close()
+	if _, err := c.Do("PING"); err != nil {
+		return nil, fmt.Errorf("cannot connect to redis (%v): %v", conf.Address, err)
+	}
+	return newRedisUDSStorage(conf.Prefix, func(cmd string, args ...interface{}) (interface{}, error) {
+		c := pool.Get()
+		defer c.Close()
+		return c.Do(cmd, args...)
+	}), nil
+}
+
+func newRedisUDSStorage(prefix string, do func(cmd string, args ...interface{}) (interface{}, error)) *redisUDSStorage {
+	if prefix == "" {
+		prefix = "sensorbee"
+	}
+	return &redisUDSStorage{
+		prefix: prefix,
+		do:     do,
+	}
+}
+
+func (s *redisUDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return newBufferedUDSStorageWriter(func(f *os.File, size int64) error {
+		// The state is written before it's registered to the sets so that
+		// a listed state can always be loaded.
+		if err := s.write(s.stateKey(topology, state, tag), f, size); err != nil {
+			return err
+		}
+		if _, err := s.do("SADD", s.topologiesKey(), topology); err != nil {
+			return err
+		}
+		_, err := s.do("SADD", s.statesKey(topology), state+":"+tag)
+		return err
+	})
+}
+
+// write writes chunks to a temporary list and renames it so that the
+// previous state is replaced atomically.
+func (s *redisUDSStorage) write(key string, r io.Reader, size int64) error {
+	if size == 0 {
+		// An empty list cannot exist in Redis.
+		_, err := s.do("SET", key, []byte{})
+		return err
+	}
+	id, err := newSaveID()
+	if err != nil {
+		return err
+	}
+	tmp := key + ":saving:" + id
+	err = readChunks(r, func(i int, chunk []byte) error {
+		_, err := s.do("RPUSH", tmp, append([]byte(id), chunk...))
+		return err
+	})
+	if err == nil {
+		_, err = s.do("RENAME", tmp, key)
+	}
+	if err != nil {
+		s.do("DEL", tmp)
+		return err
+	}
+	return nil
+}
+
+func (s *redisUDSStorage) Load(topology, state, tag string) (io.ReadCloser, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	key := s.stateKey(topology, state, tag)
+	t, err := redis.String(s.do("TYPE", key))
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case "list":
+		return s.listReader(key), nil
+	case "string":
+		return s.stringReader(key), nil
+	case "none":
+		return nil, core.NotExistError(fmt.Errorf("the state '%v' of the topology '%v' with the tag '%v' was not found",
+			state, topology, tag))
+	default:
+		return nil, fmt.Errorf("the state '%v' of the topology '%v' with the tag '%v' has an unexpected type: %v",
+			state, topology, tag, t)
+	}
+}
+
+func (s *redisUDSStorage) listReader(key string) io.ReadCloser {
+	var id []byte
+	return &chunkReader{
+		fetch: func(i int) ([]byte, error) {
+			b, err := redis.Bytes(s.do("LINDEX", key, i))
+			if err == redis.ErrNil {
+				return nil, io.EOF
+			} else if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				if len(b) < 16 {
+					return nil, errors.New("the state is broken")
+				}
+				id = b[:16]
+			}
+			if !bytes.HasPrefix(b, id) {
+				return nil, errors.New("the state was overwritten while it was being loaded")
+			}
+			return b[len(id):], nil
+		},
+	}
+}
+
+// stringReader reads a state saved as a string value by older versions.
+func (s *redisUDSStorage) stringReader(key string) io.ReadCloser {
+	return &chunkReader{
+		fetch: func(i int) ([]byte, error) {
+			start := i * udsChunkSize
+			b, err := redis.Bytes(s.do("GETRANGE", key, start, start+udsChunkSize-1))
+			if err != nil {
+				return nil, err
+			}
+			if len(b) == 0 {
+				return nil, io.EOF
+			}
+			return b, nil
+		},
+	}
+}
+
+func (s *redisUDSStorage) ListTopologies() ([]string, error) {
+	ts, err := redis.Strings(s.do("SMEMBERS", s.topologiesKey()))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ts)
+	return ts, nil
+}
+
+func (s *redisUDSStorage) List(topology string) (map[string][]string, error) {
+	ms, err := redis.Strings(s.do("SMEMBERS", s.statesKey(topology)))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ms)
+
+	res := map[string][]string{}
+	for _, m := range ms {
+		i := strings.LastIndex(m, ":")
+		if i < 0 {
+			continue
+		}
+		res[m[:i]] = append(res[m[:i]], m[i+1:])
+	}
+	if len(res) == 0 {
+		return nil, core.NotExistError(fmt.Errorf("a topology '%v' was not found", topology))
+	}
+	return res, nil
+}
+
+func (s *redisUDSStorage) stateKey(topology, state, tag string) string {
+	return fmt.Sprintf("%v:state:%v:%v:%v", s.prefix, topology, state, tag)
+}
+
+func (s *redisUDSStorage) topologiesKey() string {
+	return s.prefix + ":topologies"
+}
+
+func (s *redisUDSStorage) statesKey(topology string) string {
+	return fmt.Sprintf("%v:states:%v", s.prefix, topology)
+}
@@ -0,0 +1,154 @@
+package udsstorage
+
+import (
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// newFakeRedis returns a function which emulates commands used by
+// redisUDSStorage.
+func newFakeRedis() func(cmd string, args ...interface{}) (interface{}, error) {
+	strs := map[string][]byte{}
+	lists := map[string][][]byte{}
+	sets := map[string]map[string]bool{}
+	return func(cmd string, args ...interface{}) (interface{}, error) {
+		key := args[0].(string)
+		switch cmd {
+		case "SET":
+			delete(lists, key)
+			strs[key] = append([]byte(nil), args[1].([]byte)...)
+			return "OK", nil
+		case "GET":
+			v, ok := strs[key]
+			if !ok {
+				return nil, nil
+			}
+			return v, nil
+		case "GETRANGE":
+			v := strs[key]
+			start, end := args[1].(int), args[2].(int)+1
+			if start > len(v) {
+				start = len(v)
+			}
+			if end > len(v) {
+				end = len(v)
+			}
+			return v[start:end], nil
+		case "TYPE":
+			if _, ok := strs[key]; ok {
+				return "string", nil
+			}
+			if _, ok := lists[key]; ok {
+				return "list", nil
+			}
+			return "none", nil
+		case "RPUSH":
+			lists[key] = append(lists[key], append([]byte(nil), args[1].([]byte)...))
+			return int64(len(lists[key])), nil
+		case "LINDEX":
+			l := lists[key]
+			i := args[1].(int)
+			if i >= len(l) {
+				return nil, nil
+			}
+			return l[i], nil
+		case "RENAME":
+			l, ok := lists[key]
+			if !ok {
+				return nil, fmt.Errorf("no such key: %v", key)
+			}
+			delete(lists, key)
+			delete(strs, args[1].(string))
+			lists[args[1].(string)] = l
+			return "OK", nil
+		case "DEL":
+			delete(strs, key)
+			delete(lists, key)
+			return int64(1), nil
+		case "SADD":
+			if sets[key] == nil {
+				sets[key] = map[string]bool{}
+			}
+			sets[key][args[1].(string)] = true
+			return int64(1), nil
+		case "SMEMBERS":
+			res := []interface{}{}
+			for m := range sets[key] {
+				res = append(res, []byte(m))
+			}
+			return res, nil
+		default:
+			return nil, fmt.Errorf("unsupported command: %v", cmd)
+		}
+	}
+}
+
+func TestRedis(t *testing.T) {
+	Convey("Given a redis UDS storage", t, func() {
+		s := newRedisUDSStorage("", newFakeRedis())
+		testUDSStorage(s)
+	})
+
+	Convey("Given a redis UDS storage having a state saved as a string", t, func() {
+		do := newFakeRedis()
+		s := newRedisUDSStorage("", do)
+		_, err := do("SET", s.stateKey("test_topology", "state1", "default"), []byte("hoge"))
+		So(err, ShouldBeNil)
+
+		Convey("When loading the state", func() {
+			r, err := s.Load("test_topology", "state1", "")
+			So(err, ShouldBeNil)
+			b, err := ioutil.ReadAll(r)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should have the content", func() {
+				So(string(b), ShouldEqual, "hoge")
+			})
+		})
+	})
+
+	Convey("Given a redis UDS storage having a large state", t, func() {
+		chunkSize := udsChunkSize
+		udsChunkSize = 2
+		Reset(func() {
+			udsChunkSize = chunkSize
+		})
+		do := newFakeRedis()
+		s := newRedisUDSStorage("", do)
+		w, err := s.Save("test_topology", "state1", "")
+		So(err, ShouldBeNil)
+		_, err = io.WriteString(w, "hoge")
+		So(err, ShouldBeNil)
+		So(w.Commit(), ShouldBeNil)
+
+		Convey("When the state is overwritten while loading it", func() {
+			r, err := s.Load("test_topology", "state1", "")
+			So(err, ShouldBeNil)
+			b := make([]byte, 2)
+			_, err = io.ReadFull(r, b)
+			So(err, ShouldBeNil)
+
+			w, err := s.Save("test_topology", "state1", "")
+			So(err, ShouldBeNil)
+			_, err = io.WriteString(w, "fuga")
+			So(err, ShouldBeNil)
+			So(w.Commit(), ShouldBeNil)
+
+			Convey("Then reading the rest should fail", func() {
+				_, err := ioutil.ReadAll(r)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given no address", t, func() {
+		_, err := NewRedis(&RedisConfig{})
+
+		Convey("Then the storage shouldn't be created", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,462 @@
+package udsstorage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config has parameters of a UDSStorage saving states to Amazon S3 or an
+// object storage having S3 compatible API.
+type S3Config struct {
+	// Bucket is the name of the bucket. It's required.
+	Bucket string
+
+	// Region is the region of the bucket. It's "us-east-1" by default.
+	Region string
+
+	// Endpoint is the URL of the API such as "http://localhost:9000". When
+	// it's empty, the endpoint of Amazon S3 in Region is used. Objects are
+	// always accessed with path-style URLs.
+	Endpoint string
+
+	// Prefix is prepended to the key of each object.
+	Prefix string
+
+	// AccessKeyID and SecretAccessKey are credentials to access the bucket.
+	// When they're empty, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	// environment variables are used.
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// s3UDSStorage is a UDSStorage which saves states to S3. A state is stored
+// as an object whose key is "<prefix><topology>/<state>/<tag>.state". A
+// state larger than s3MultipartPartSize is uploaded by multipart upload so
+// that it can exceed the maximum size of a single PUT.
+//
+// Requests are signed with AWS Signature Version 4. The storage doesn't
+// depend on AWS SDK and only supports operations required by UDSStorage.
+type s3UDSStorage struct {
+	conf   S3Config
+	client *http.Client
+}
+
+var (
+	_ udf.UDSStorage = &s3UDSStorage{}
+)
+
+// NewS3 returns a UDSStorage which saves states to S3.
+func NewS3(conf *S3Config) (udf.UDSStorage, error) {
+	c := *conf
+	if c.Bucket == "" {
+		return nil, errors.New("bucket must be specified")
+	}
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = fmt.Sprintf("https://s3.%v.amazonaws.com", c.Region)
+	}
+	c.Endpoint = strings.TrimRight(c.Endpoint, "/")
+	if _, err := url.Parse(c.Endpoint); err != nil {
+		return nil, fmt.Errorf("endpoint (%v) isn't valid: %v", c.Endpoint, err)
+	}
+	if c.AccessKeyID == "" {
+		c.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if c.SecretAccessKey == "" {
+		c.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return nil, errors.New("credentials of S3 must be specified")
+	}
+	return &s3UDSStorage{
+		conf: c,
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+	}, nil
+}
+
+func (s *s3UDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	key := s.objectKey(topology, state, tag)
+	return newBufferedUDSStorageWriter(func(f *os.File, size int64) error {
+		if size <= s3MultipartPartSize {
+			_, err := s.put(key, nil, io.NewSectionReader(f, 0, size))
+			return err
+		}
+		return s.putMultipart(key, f, size)
+	})
+}
+
+var (
+	// s3MultipartPartSize is the size of each part of a multipart upload.
+	// S3 allows at most 10000 parts in an upload.
+	s3MultipartPartSize int64 = 64 * 1024 * 1024
+)
+
+// put uploads the body and returns the ETag of the object or the part.
+func (s *s3UDSStorage) put(key string, query url.Values, body *io.SectionReader) (string, error) {
+	res, err := s.doStream("PUT", key, query, body)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", s3Error(res)
+	}
+	return res.Header.Get("ETag"), nil
+}
+
+type s3InitiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+// putMultipart uploads a large state by multipart upload. The upload is
+// aborted when it fails so that uploaded parts don't remain.
+func (s *s3UDSStorage) putMultipart(key string, f *os.File, size int64) (err error) {
+	res, err := s.do("POST", key, url.Values{"uploads": []string{""}}, nil)
+	if err != nil {
+		return err
+	}
+	var init s3InitiateMultipartUploadResult
+	err = func() error {
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return s3Error(res)
+		}
+		return xml.NewDecoder(res.Body).Decode(&init)
+	}()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if res, e := s.do("DELETE", key, url.Values{"uploadId": []string{init.UploadID}}, nil); e == nil {
+			res.Body.Close()
+		}
+	}()
+
+	var complete s3CompleteMultipartUpload
+	for off := int64(0); off < size; off += s3MultipartPartSize {
+		n := size - off
+		if n > s3MultipartPartSize {
+			n = s3MultipartPartSize
+		}
+		num := len(complete.Parts) + 1
+		etag, err := s.put(key, url.Values{
+			"partNumber": []string{fmt.Sprint(num)},
+			"uploadId":   []string{init.UploadID},
+		}, io.NewSectionReader(f, off, n))
+		if err != nil {
+			return err
+		}
+		complete.Parts = append(complete.Parts, s3CompletedPart{num, etag})
+	}
+
+	body, err := xml.Marshal(&complete)
+	if err != nil {
+		return err
+	}
+	res, err = s.do("POST", key, url.Values{"uploadId": []string{init.UploadID}}, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return s3Error(res)
+	}
+	// S3 can return an error in the body with 200 OK.
+	b, err := ioutil.ReadAll(io.LimitReader(res.Body, 64*1024))
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(b, []byte("<Error>")) {
+		return fmt.Errorf("S3 returned an error: %v", strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+func (s *s3UDSStorage) Load(topology, state, tag string) (io.ReadCloser, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.do("GET", s.objectKey(topology, state, tag), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, core.NotExistError(fmt.Errorf("the state '%v' of the topology '%v' with the tag '%v' was not found",
+			state, topology, tag))
+	default:
+		defer res.Body.Close()
+		return nil, s3Error(res)
+	}
+}
+
+func (s *s3UDSStorage) ListTopologies() ([]string, error) {
+	keys, err := s.listKeys(s.conf.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []string{}
+	found := map[string]bool{}
+	for _, k := range keys {
+		t, _, _, ok := s.parseKey(k)
+		if !ok || found[t] {
+			continue
+		}
+		found[t] = true
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func (s *s3UDSStorage) List(topology string) (map[string][]string, error) {
+	keys, err := s.listKeys(s.conf.Prefix + topology + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string][]string{}
+	for _, k := range keys {
+		t, state, tag, ok := s.parseKey(k)
+		if !ok || t != topology {
+			continue
+		}
+		res[state] = append(res[state], tag)
+	}
+	if len(res) == 0 {
+		return nil, core.NotExistError(fmt.Errorf("a topology '%v' was not found", topology))
+	}
+	return res, nil
+}
+
+func (s *s3UDSStorage) objectKey(topology, state, tag string) string {
+	return fmt.Sprintf("%v%v/%v/%v.state", s.conf.Prefix, topology, state, tag)
+}
+
+// parseKey extracts the names of a topology, a state, and a tag from the key
+// of an object.
+func (s *s3UDSStorage) parseKey(key string) (topology, state, tag string, ok bool) {
+	if !strings.HasPrefix(key, s.conf.Prefix) || !strings.HasSuffix(key, ".state") {
+		return "", "", "", false
+	}
+	ns := strings.Split(strings.TrimSuffix(key[len(s.conf.Prefix):], ".state"), "/")
+	if len(ns) != 3 {
+		return "", "", "", false
+	}
+	return ns[0], ns[1], ns[2], true
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// listKeys returns the keys of all objects having the prefix.
+func (s *s3UDSStorage) listKeys(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{
+			"list-type": []string{"2"},
+			"prefix":    []string{prefix},
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+
+		res, err := s.do("GET", "", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		var r s3ListBucketResult
+		err = func() error {
+			defer res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				return s3Error(res)
+			}
+			return xml.NewDecoder(res.Body).Decode(&r)
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range r.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !r.IsTruncated || r.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = r.NextContinuationToken
+	}
+}
+
+// do sends a signed request for the object having the key. When the key is
+// empty, the request is sent to the bucket.
+func (s *s3UDSStorage) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	return s.doStream(method, key, query, io.NewSectionReader(bytes.NewReader(body), 0, int64(len(body))))
+}
+
+// doStream sends a signed request having the body. Because the hash of the
+// body is a part of the signature, the body is read twice.
+func (s *s3UDSStorage) doStream(method, key string, query url.Values, body *io.SectionReader) (*http.Response, error) {
+	path := "/" + s.conf.Bucket
+	if key != "" {
+		path += "/" + key
+	}
+	escapedPath := s3Escape(path, false)
+	u := s.conf.Endpoint + escapedPath
+	if len(query) > 0 {
+		u += "?" + s3CanonicalQuery(query)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return nil, err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = body.Size()
+	if req.ContentLength == 0 {
+		req.Body = http.NoBody
+	}
+	s.sign(req, escapedPath, hex.EncodeToString(h.Sum(nil)), time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign adds headers of AWS Signature Version 4 to the request. escapedPath
+// is the canonical URI of the request.
+func (s *s3UDSStorage) sign(req *http.Request, escapedPath string, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapedPath,
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.conf.Region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + s.conf.SecretAccessKey)
+	for _, v := range []string{date, s.conf.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		s.conf.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape escapes a string as described in the specification of AWS
+// Signature Version 4. Only unreserved characters aren't escaped.
+func s3Escape(s string, escapeSlash bool) string {
+	b := &bytes.Buffer{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery returns a query string sorted by keys.
+func s3CanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var ps []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			ps = append(ps, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(ps, "&")
+}
+
+func s3Error(res *http.Response) error {
+	b, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+	return fmt.Errorf("S3 returned an error (%v): %v", res.Status, strings.TrimSpace(string(b)))
+}
@@ -0,0 +1,209 @@
+package udsstorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newFakeS3 returns a server which emulates a part of S3 API used by
+// s3UDSStorage. It returns at most one key in a page of listing results to
+// test pagination.
+func newFakeS3(bucket string) *httptest.Server {
+	var m sync.Mutex
+	objects := map[string][]byte{}
+	uploads := map[string]map[int][]byte{}
+	nextUpload := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test_key/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/"+bucket) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+bucket), "/")
+		q := r.URL.Query()
+		b, _ := ioutil.ReadAll(r.Body)
+		if h := sha256.Sum256(b); r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(h[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case r.Method == "POST" && key != "" && q.Get("uploads") == "" && len(q["uploads"]) > 0:
+			nextUpload++
+			id := fmt.Sprint("upload", nextUpload)
+			uploads[id] = map[int][]byte{}
+			xml.NewEncoder(w).Encode(&s3InitiateMultipartUploadResult{UploadID: id})
+
+		case r.Method == "PUT" && key != "" && q.Get("uploadId") != "":
+			parts, ok := uploads[q.Get("uploadId")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			n, _ := strconv.Atoi(q.Get("partNumber"))
+			parts[n] = b
+			w.Header().Set("ETag", fmt.Sprintf(`"etag%v"`, n))
+
+		case r.Method == "POST" && key != "" && q.Get("uploadId") != "":
+			parts, ok := uploads[q.Get("uploadId")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var c s3CompleteMultipartUpload
+			if err := xml.Unmarshal(b, &c); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var obj []byte
+			for i, p := range c.Parts {
+				if p.PartNumber != i+1 || p.ETag != fmt.Sprintf(`"etag%v"`, i+1) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				obj = append(obj, parts[p.PartNumber]...)
+			}
+			objects[key] = obj
+			delete(uploads, q.Get("uploadId"))
+
+		case r.Method == "DELETE" && key != "" && q.Get("uploadId") != "":
+			delete(uploads, q.Get("uploadId"))
+			w.WriteHeader(http.StatusNoContent)
+
+		case r.Method == "PUT" && key != "":
+			objects[key] = b
+
+		case r.Method == "GET" && key != "":
+			b, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(b)
+
+		case r.Method == "GET" && q.Get("list-type") == "2":
+			var keys []string
+			for k := range objects {
+				if strings.HasPrefix(k, q.Get("prefix")) && k > q.Get("continuation-token") {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			var res s3ListBucketResult
+			if len(keys) > 0 {
+				res.Contents = append(res.Contents, struct{ Key string }{keys[0]})
+			}
+			if len(keys) > 1 {
+				res.IsTruncated = true
+				res.NextContinuationToken = keys[0]
+			}
+			xml.NewEncoder(w).Encode(&res)
+
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestS3(t *testing.T) {
+	Convey("Given an S3 UDS storage", t, func() {
+		ts := newFakeS3("test_bucket")
+		Reset(ts.Close)
+
+		s, err := NewS3(&S3Config{
+			Bucket:          "test_bucket",
+			Endpoint:        ts.URL,
+			Prefix:          "sensorbee/",
+			AccessKeyID:     "test_key",
+			SecretAccessKey: "test_secret",
+		})
+		So(err, ShouldBeNil)
+		testUDSStorage(s)
+
+		Convey("When saving a state larger than a part", func() {
+			size := s3MultipartPartSize
+			s3MultipartPartSize = 4
+			Reset(func() {
+				s3MultipartPartSize = size
+			})
+			w, err := s.Save("test", "state", "default")
+			So(err, ShouldBeNil)
+			_, err = io.WriteString(w, "0123456789")
+			So(err, ShouldBeNil)
+			So(w.Commit(), ShouldBeNil)
+
+			Convey("Then it should be uploaded by multipart upload", func() {
+				r, err := s.Load("test", "state", "default")
+				So(err, ShouldBeNil)
+				defer r.Close()
+				b, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, "0123456789")
+			})
+		})
+	})
+
+	Convey("Given no bucket", t, func() {
+		_, err := NewS3(&S3Config{
+			AccessKeyID:     "test_key",
+			SecretAccessKey: "test_secret",
+		})
+
+		Convey("Then the storage shouldn't be created", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given no credentials", t, func() {
+		id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+		Reset(func() {
+			os.Setenv("AWS_ACCESS_KEY_ID", id)
+			os.Setenv("AWS_SECRET_ACCESS_KEY", secret)
+		})
+		_, err := NewS3(&S3Config{Bucket: "test_bucket"})
+
+		Convey("Then the storage shouldn't be created", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestS3CanonicalQuery(t *testing.T) {
+	Convey("Given a query", t, func() {
+		q := url.Values{
+			"prefix":    []string{"a b/c"},
+			"list-type": []string{"2"},
+		}
+
+		Convey("When making a canonical query string", func() {
+			s := s3CanonicalQuery(q)
+
+			Convey("Then it should be sorted and escaped", func() {
+				So(s, ShouldEqual, "list-type=2&prefix=a%20b%2Fc")
+			})
+		})
+	})
+}
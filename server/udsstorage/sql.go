@@ -0,0 +1,267 @@
+package udsstorage
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SQLConfig has parameters of a UDSStorage saving states to a SQL database.
+type SQLConfig struct {
+	// Driver is the name of the database/sql driver such as "postgres" or
+	// "mysql". The driver isn't imported by this package and has to be
+	// registered by the program or a plugin.
+	Driver string
+
+	// DataSource is the driver specific data source name.
+	DataSource string
+
+	// Table is the name of the table having states. It's created when it
+	// doesn't exist. It's "sensorbee_uds" by default.
+	Table string
+}
+
+// sqlUDSStorage is a UDSStorage which saves states to a table of a SQL
+// database. The table has columns topology, state, tag, and data, and the
+// first three are its primary key.
+//
+// Because a value of a column is read into memory at once, the content of a
+// state is stored as chunks in another table, "<table>_chunks", which has
+// columns topology, state, tag, version, seq, and data. The data column of
+// the main table has sqlChunkedStateMarker followed by the version, which is
+// the ID of the save, and the number of chunks. A state saved by older
+// versions directly has its content in the data column.
+type sqlUDSStorage struct {
+	db    *sql.DB
+	table string
+
+	// postgres is true when the driver uses $n placeholders.
+	postgres bool
+}
+
+var (
+	_ udf.UDSStorage = &sqlUDSStorage{}
+)
+
+// NewSQL returns a UDSStorage which saves states to a SQL database.
+func NewSQL(conf *SQLConfig) (udf.UDSStorage, error) {
+	if conf.Driver == "" {
+		return nil, errors.New("driver must be specified")
+	}
+	table := conf.Table
+	if table == "" {
+		table = "sensorbee_uds"
+	}
+	if err := core.ValidateSymbol(table); err != nil {
+		return nil, fmt.Errorf("table name is invalid: %v", err)
+	}
+
+	db, err := sql.Open(conf.Driver, conf.DataSource)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot connect to the database: %v", err)
+	}
+
+	s := &sqlUDSStorage{
+		db:       db,
+		table:    table,
+		postgres: conf.Driver == "postgres",
+	}
+	blob := "BLOB"
+	switch conf.Driver {
+	case "postgres":
+		blob = "BYTEA"
+	case "mysql":
+		blob = "LONGBLOB"
+	}
+	if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (
+		topology VARCHAR(255) NOT NULL,
+		state VARCHAR(255) NOT NULL,
+		tag VARCHAR(255) NOT NULL,
+		data %v NOT NULL,
+		PRIMARY KEY (topology, state, tag))`, table, blob)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot create the table: %v", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v_chunks (
+		topology VARCHAR(255) NOT NULL,
+		state VARCHAR(255) NOT NULL,
+		tag VARCHAR(255) NOT NULL,
+		version VARCHAR(32) NOT NULL,
+		seq INTEGER NOT NULL,
+		data %v NOT NULL,
+		PRIMARY KEY (topology, state, tag, version, seq))`, table, blob)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot create the table of chunks: %v", err)
+	}
+	return s, nil
+}
+
+// sqlChunkedStateMarker is the prefix of the data column of a state stored
+// as chunks.
+const sqlChunkedStateMarker = "\x00sensorbee-chunked:"
+
+func (s *sqlUDSStorage) Save(topology, state, tag string) (udf.UDSStorageWriter, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	return newBufferedUDSStorageWriter(func(f *os.File, size int64) error {
+		version, err := newSaveID()
+		if err != nil {
+			return err
+		}
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := s.write(tx, topology, state, tag, version, f); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func (s *sqlUDSStorage) write(tx *sql.Tx, topology, state, tag, version string, r io.Reader) error {
+	if _, err := tx.Exec(s.query("DELETE FROM %v_chunks WHERE topology = ? AND state = ? AND tag = ?"),
+		topology, state, tag); err != nil {
+		return err
+	}
+	n := 0
+	if err := readChunks(r, func(i int, chunk []byte) error {
+		n++
+		_, err := tx.Exec(s.query("INSERT INTO %v_chunks (topology, state, tag, version, seq, data) VALUES (?, ?, ?, ?, ?, ?)"),
+			topology, state, tag, version, i, chunk)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(s.query("DELETE FROM %v WHERE topology = ? AND state = ? AND tag = ?"),
+		topology, state, tag); err != nil {
+		return err
+	}
+	_, err := tx.Exec(s.query("INSERT INTO %v (topology, state, tag, data) VALUES (?, ?, ?, ?)"),
+		topology, state, tag, []byte(fmt.Sprintf("%v%v:%v", sqlChunkedStateMarker, version, n)))
+	return err
+}
+
+func (s *sqlUDSStorage) Load(topology, state, tag string) (io.ReadCloser, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if err := s.db.QueryRow(s.query("SELECT data FROM %v WHERE topology = ? AND state = ? AND tag = ?"),
+		topology, state, tag).Scan(&data); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, core.NotExistError(fmt.Errorf("the state '%v' of the topology '%v' with the tag '%v' was not found",
+				state, topology, tag))
+		}
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte(sqlChunkedStateMarker)) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	vs := strings.SplitN(string(data[len(sqlChunkedStateMarker):]), ":", 2)
+	if len(vs) != 2 {
+		return nil, errors.New("the state is broken")
+	}
+	version := vs[0]
+	n, err := strconv.Atoi(vs[1])
+	if err != nil {
+		return nil, fmt.Errorf("the state is broken: %v", err)
+	}
+	return &chunkReader{
+		fetch: func(i int) ([]byte, error) {
+			if i >= n {
+				return nil, io.EOF
+			}
+			var chunk []byte
+			if err := s.db.QueryRow(s.query("SELECT data FROM %v_chunks WHERE topology = ? AND state = ? AND tag = ? AND version = ? AND seq = ?"),
+				topology, state, tag, version, i).Scan(&chunk); err != nil {
+				if err == sql.ErrNoRows {
+					return nil, errors.New("the state was overwritten while it was being loaded")
+				}
+				return nil, err
+			}
+			return chunk, nil
+		},
+	}, nil
+}
+
+func (s *sqlUDSStorage) ListTopologies() ([]string, error) {
+	rows, err := s.db.Query(s.query("SELECT DISTINCT topology FROM %v ORDER BY topology"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []string{}
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+func (s *sqlUDSStorage) List(topology string) (map[string][]string, error) {
+	rows, err := s.db.Query(s.query("SELECT state, tag FROM %v WHERE topology = ? ORDER BY state, tag"), topology)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[string][]string{}
+	for rows.Next() {
+		var state, tag string
+		if err := rows.Scan(&state, &tag); err != nil {
+			return nil, err
+		}
+		res[state] = append(res[state], tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, core.NotExistError(fmt.Errorf("a topology '%v' was not found", topology))
+	}
+	return res, nil
+}
+
+// query embeds the table name into q and rewrites placeholders for the
+// driver.
+func (s *sqlUDSStorage) query(q string) string {
+	q = fmt.Sprintf(q, s.table)
+	if !s.postgres {
+		return q
+	}
+
+	b := &bytes.Buffer{}
+	n := 0
+	for _, c := range q {
+		if c == '?' {
+			n++
+			fmt.Fprintf(b, "$%v", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package udsstorage
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/smartystreets/goconvey/convey"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	Convey("Given a SQL UDS storage", t, func() {
+		dir, err := ioutil.TempDir("", "sensorbee_uds_sql_test")
+		So(err, ShouldBeNil)
+		Reset(func() {
+			os.RemoveAll(dir)
+		})
+
+		s, err := NewSQL(&SQLConfig{
+			Driver:     "sqlite3",
+			DataSource: filepath.Join(dir, "uds.db"),
+		})
+		So(err, ShouldBeNil)
+		Reset(func() {
+			s.(*sqlUDSStorage).db.Close()
+		})
+		testUDSStorage(s)
+
+		Convey("When a state was saved by an older version", func() {
+			_, err := s.(*sqlUDSStorage).db.Exec("INSERT INTO sensorbee_uds (topology, state, tag, data) VALUES (?, ?, ?, ?)",
+				"test", "legacy", "default", []byte("legacy"))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be loaded", func() {
+				r, err := s.Load("test", "legacy", "")
+				So(err, ShouldBeNil)
+				defer r.Close()
+				b, err := ioutil.ReadAll(r)
+				So(err, ShouldBeNil)
+				So(string(b), ShouldEqual, "legacy")
+			})
+		})
+	})
+
+	Convey("Given an invalid table name", t, func() {
+		_, err := NewSQL(&SQLConfig{
+			Driver: "sqlite3",
+			Table:  "uds; DROP TABLE uds",
+		})
+
+		Convey("Then the storage shouldn't be created", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a postgres SQL UDS storage", t, func() {
+		s := &sqlUDSStorage{
+			table:    "uds",
+			postgres: true,
+		}
+
+		Convey("When building a query", func() {
+			q := s.query("SELECT data FROM %v WHERE topology = ? AND state = ?")
+
+			Convey("Then it should have numbered placeholders", func() {
+				So(q, ShouldEqual, "SELECT data FROM uds WHERE topology = $1 AND state = $2")
+			})
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package udsstorage
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/bql/udf"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"io"
+	"io/ioutil"
+)
+
+// testUDSStorage runs common tests of UDSStorage. It must be called in a
+// Convey block and s must be empty.
+func testUDSStorage(s udf.UDSStorage) {
+	save := func(topology, state, tag, data string) {
+		w, err := s.Save(topology, state, tag)
+		So(err, ShouldBeNil)
+		_, err = io.WriteString(w, data)
+		So(err, ShouldBeNil)
+		So(w.Commit(), ShouldBeNil)
+	}
+	load := func(topology, state, tag string) string {
+		r, err := s.Load(topology, state, tag)
+		So(err, ShouldBeNil)
+		defer r.Close()
+		data, err := ioutil.ReadAll(r)
+		So(err, ShouldBeNil)
+		return string(data)
+	}
+
+	Convey("When saving a state", func() {
+		save("test_topology", "state1", "", "hoge")
+
+		Convey("Then it should be loaded with the default tag", func() {
+			So(load("test_topology", "state1", "default"), ShouldEqual, "hoge")
+		})
+
+		Convey("Then it should be listed", func() {
+			ts, err := s.ListTopologies()
+			So(err, ShouldBeNil)
+			So(ts, ShouldResemble, []string{"test_topology"})
+
+			l, err := s.List("test_topology")
+			So(err, ShouldBeNil)
+			So(l, ShouldResemble, map[string][]string{"state1": {"default"}})
+		})
+
+		Convey("And overwriting it", func() {
+			save("test_topology", "state1", "", "fuga")
+
+			Convey("Then it should have the new content", func() {
+				So(load("test_topology", "state1", ""), ShouldEqual, "fuga")
+			})
+		})
+
+		Convey("And saving other states", func() {
+			save("test_topology", "state1", "tag2", "moge")
+			save("test_topology", "state2", "", "piyo")
+			save("test_topology2", "state1", "", "foo")
+
+			Convey("Then each state should be loaded", func() {
+				So(load("test_topology", "state1", ""), ShouldEqual, "hoge")
+				So(load("test_topology", "state1", "tag2"), ShouldEqual, "moge")
+				So(load("test_topology", "state2", ""), ShouldEqual, "piyo")
+				So(load("test_topology2", "state1", ""), ShouldEqual, "foo")
+			})
+
+			Convey("Then all of them should be listed", func() {
+				ts, err := s.ListTopologies()
+				So(err, ShouldBeNil)
+				So(ts, ShouldResemble, []string{"test_topology", "test_topology2"})
+
+				l, err := s.List("test_topology")
+				So(err, ShouldBeNil)
+				So(l, ShouldResemble, map[string][]string{
+					"state1": {"default", "tag2"},
+					"state2": {"default"},
+				})
+			})
+		})
+	})
+
+	Convey("When saving a state larger than a chunk", func() {
+		chunkSize := udsChunkSize
+		udsChunkSize = 3
+		Reset(func() {
+			udsChunkSize = chunkSize
+		})
+		save("test_topology", "state1", "", "0123456789")
+
+		Convey("Then it should be loaded", func() {
+			So(load("test_topology", "state1", ""), ShouldEqual, "0123456789")
+		})
+
+		Convey("And overwriting it with an empty state", func() {
+			save("test_topology", "state1", "", "")
+
+			Convey("Then it should be empty", func() {
+				So(load("test_topology", "state1", ""), ShouldEqual, "")
+			})
+		})
+	})
+
+	Convey("When aborting to save a state", func() {
+		w, err := s.Save("test_topology", "state1", "")
+		So(err, ShouldBeNil)
+		_, err = io.WriteString(w, "hoge")
+		So(err, ShouldBeNil)
+		So(w.Abort(), ShouldBeNil)
+
+		Convey("Then the state shouldn't be saved", func() {
+			_, err := s.Load("test_topology", "state1", "")
+			So(core.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("Then the writer cannot be used again", func() {
+			_, err := io.WriteString(w, "hoge")
+			So(err, ShouldNotBeNil)
+			So(w.Commit(), ShouldNotBeNil)
+		})
+	})
+
+	Convey("When loading a missing state", func() {
+		_, err := s.Load("test_topology", "state1", "")
+
+		Convey("Then it should fail with a not-exist error", func() {
+			So(core.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+
+	Convey("When listing a missing topology", func() {
+		_, err := s.List("test_topology")
+
+		Convey("Then it should fail with a not-exist error", func() {
+			So(core.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+
+	Convey("When saving a state with an invalid tag", func() {
+		_, err := s.Save("test_topology", "state1", "in/valid")
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
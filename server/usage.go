@@ -0,0 +1,57 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/gocraft/web"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+type usage struct {
+	*APIContext
+}
+
+func setUpUsageRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(usage{}, "")
+	root.Get("/usage", (*usage).Index)
+}
+
+// Index returns resources accumulated by each topology registered to the
+// server. Hibernated topologies and topologies on workers aren't included.
+func (uc *usage) Index(rw web.ResponseWriter, req *web.Request) {
+	ts, err := uc.topologies.List()
+	if err != nil {
+		uc.ErrLog(err).Error("Cannot list registered topologies")
+		uc.RenderError(jasco.NewInternalServerError(err))
+		return
+	}
+
+	names := make([]string, 0, len(ts))
+	for name := range ts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]data.Map, 0, len(names))
+	for _, name := range names {
+		u := ts[name].Topology().Usage()
+		u["topology"] = data.String(name)
+		res = append(res, u)
+	}
+	uc.Render(map[string]interface{}{
+		"usage": res,
+	})
+}
+
+// Usage returns resources accumulated by the topology.
+func (tc *topologies) Usage(rw web.ResponseWriter, req *web.Request) {
+	tb := tc.fetchTopology()
+	if tb == nil {
+		return
+	}
+	tc.Render(map[string]interface{}{
+		"topology": tc.topologyName,
+		"usage":    tb.Topology().Usage(),
+	})
+}
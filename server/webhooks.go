@@ -0,0 +1,330 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocraft/web"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/pfnet/jasco.v1"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+const (
+	// topologyCreatedEvent is emitted when a topology is created with the
+	// API.
+	topologyCreatedEvent core.LifecycleEventType = "topology_created"
+
+	// topologyDestroyedEvent is emitted when a topology is dropped with the
+	// API.
+	topologyDestroyedEvent core.LifecycleEventType = "topology_destroyed"
+
+	// maxWebhookDeliveries is the maximum number of requests sent to webhooks
+	// at once. Events are dropped while the limit is reached so that a slow
+	// webhook doesn't block topologies.
+	maxWebhookDeliveries = 16
+)
+
+// webhookDispatcher sends lifecycle events of topologies to webhooks. Server
+// wide webhooks come from the config and webhooks of each topology are
+// registered with the API. Webhooks of topologies aren't persisted.
+type webhookDispatcher struct {
+	logger *logrus.Logger
+	client *http.Client
+	global []*config.Webhook
+
+	m          sync.RWMutex
+	topologies map[string]map[int64]*config.Webhook
+	lastID     int64
+
+	sem chan struct{}
+}
+
+func newWebhookDispatcher(logger *logrus.Logger, conf *config.Webhooks) *webhookDispatcher {
+	d := &webhookDispatcher{
+		logger:     logger,
+		client:     &http.Client{Timeout: 5 * time.Second},
+		topologies: map[string]map[int64]*config.Webhook{},
+		sem:        make(chan struct{}, maxWebhookDeliveries),
+	}
+	if conf != nil {
+		d.client.Timeout = time.Duration(conf.Timeout * float64(time.Second))
+		d.global = conf.Hooks
+	}
+	return d
+}
+
+// listener returns the listener of lifecycle events passed to topologies. It
+// returns nil when d is nil.
+func (d *webhookDispatcher) listener() core.LifecycleListener {
+	if d == nil {
+		return nil
+	}
+	return d.notify
+}
+
+// notify sends the event to webhooks accepting it. It doesn't wait for
+// responses from webhooks.
+func (d *webhookDispatcher) notify(e *core.LifecycleEvent) {
+	if d == nil {
+		return
+	}
+	var hooks []*config.Webhook
+	for _, h := range d.global {
+		if h.Accepts(string(e.Type)) {
+			hooks = append(hooks, h)
+		}
+	}
+	d.m.RLock()
+	for _, h := range d.topologies[strings.ToLower(e.Topology)] {
+		if h.Accepts(string(e.Type)) {
+			hooks = append(hooks, h)
+		}
+	}
+	d.m.RUnlock()
+	if len(hooks) == 0 {
+		return
+	}
+
+	body := lifecycleEventMap(e).String()
+	for _, h := range hooks {
+		d.deliver(h, e, body)
+	}
+}
+
+// emit sends an event of the topology which isn't emitted by the topology
+// itself, such as its creation.
+func (d *webhookDispatcher) emit(t core.LifecycleEventType, topology string) {
+	d.notify(&core.LifecycleEvent{
+		Type:     t,
+		Topology: topology,
+		Time:     time.Now(),
+	})
+}
+
+func (d *webhookDispatcher) deliver(h *config.Webhook, e *core.LifecycleEvent, body string) {
+	l := d.logger.WithFields(logrus.Fields{
+		"topology": e.Topology,
+		"event":    e.Type,
+		"url":      h.URL,
+	})
+	select {
+	case d.sem <- struct{}{}:
+	default:
+		l.Warn("Dropped the event because too many requests are being sent to webhooks")
+		return
+	}
+
+	go func() {
+		defer func() {
+			<-d.sem
+		}()
+		if err := d.post(h, body); err != nil {
+			l.WithField("err", err).Error("Cannot send the event to the webhook")
+		}
+	}()
+}
+
+func (d *webhookDispatcher) post(h *config.Webhook, body string) error {
+	req, err := http.NewRequest("POST", h.URL, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded with status %v", res.Status)
+	}
+	return nil
+}
+
+// add registers a webhook of the topology and returns its ID.
+func (d *webhookDispatcher) add(topology string, h *config.Webhook) int64 {
+	d.m.Lock()
+	defer d.m.Unlock()
+	n := strings.ToLower(topology)
+	if d.topologies[n] == nil {
+		d.topologies[n] = map[int64]*config.Webhook{}
+	}
+	d.lastID++
+	d.topologies[n][d.lastID] = h
+	return d.lastID
+}
+
+// remove unregisters a webhook of the topology. It returns false when the
+// webhook doesn't exist.
+func (d *webhookDispatcher) remove(topology string, id int64) bool {
+	d.m.Lock()
+	defer d.m.Unlock()
+	n := strings.ToLower(topology)
+	if _, ok := d.topologies[n][id]; !ok {
+		return false
+	}
+	delete(d.topologies[n], id)
+	if len(d.topologies[n]) == 0 {
+		delete(d.topologies, n)
+	}
+	return true
+}
+
+// removeTopology unregisters all webhooks of the topology.
+func (d *webhookDispatcher) removeTopology(topology string) {
+	if d == nil {
+		return
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	delete(d.topologies, strings.ToLower(topology))
+}
+
+// list returns webhooks of the topology sorted by their IDs.
+func (d *webhookDispatcher) list(topology string) []data.Map {
+	d.m.RLock()
+	defer d.m.RUnlock()
+	hooks := d.topologies[strings.ToLower(topology)]
+	ids := make([]int64, 0, len(hooks))
+	for id := range hooks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	res := make([]data.Map, len(ids))
+	for i, id := range ids {
+		res[i] = webhookMap(id, hooks[id])
+	}
+	return res
+}
+
+// webhookMap returns the information of a webhook. Values of headers aren't
+// included because they may have credentials.
+func webhookMap(id int64, h *config.Webhook) data.Map {
+	m := h.ToMap()
+	names := make([]string, 0, len(h.Headers))
+	for k := range h.Headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	headers := make(data.Array, len(names))
+	for i, n := range names {
+		headers[i] = data.String(n)
+	}
+	m["id"] = data.Int(id)
+	m["headers"] = headers
+	return m
+}
+
+// lifecycleEventMap returns the JSON sent to webhooks.
+func lifecycleEventMap(e *core.LifecycleEvent) data.Map {
+	m := data.Map{
+		"event":    data.String(e.Type),
+		"topology": data.String(e.Topology),
+		"time":     data.Timestamp(e.Time),
+		"detail":   data.Map{},
+	}
+	if e.NodeName != "" {
+		m["node_type"] = data.String(e.NodeType.String())
+		m["node_name"] = data.String(e.NodeName)
+	}
+	if e.Detail != nil {
+		m["detail"] = e.Detail
+	}
+	return m
+}
+
+type webhooks struct {
+	*topologies
+}
+
+func setUpWebhooksRouter(prefix string, router *web.Router) {
+	root := router.Subrouter(webhooks{}, "/:topologyName/webhooks")
+	root.Middleware((*webhooks).fetchTopologyMiddleware)
+	root.Get("/", (*webhooks).Index)
+	root.Post("/", (*webhooks).Create)
+	root.Delete("/:webhookID", (*webhooks).Destroy)
+}
+
+func (wc *webhooks) fetchTopologyMiddleware(rw web.ResponseWriter, req *web.Request, next web.NextMiddlewareFunc) {
+	if wc.fetchTopology() == nil {
+		return
+	}
+	next(rw, req)
+}
+
+// Index returns webhooks registered to the topology. Server-wide webhooks
+// in the config aren't included.
+func (wc *webhooks) Index(rw web.ResponseWriter, req *web.Request) {
+	hooks := wc.hooks.list(wc.topologyName)
+	wc.Render(map[string]interface{}{
+		"topology": wc.topologyName,
+		"count":    len(hooks),
+		"webhooks": hooks,
+	})
+}
+
+// Create registers a webhook receiving events of the topology. The body has
+// the same fields as a hook in the webhooks section of the config.
+func (wc *webhooks) Create(rw web.ResponseWriter, req *web.Request) {
+	var js map[string]interface{}
+	if apiErr := wc.ParseBody(&js); apiErr != nil {
+		wc.ErrLog(apiErr.Err).Error("Cannot parse the request json")
+		wc.RenderError(apiErr)
+		return
+	}
+	form, err := data.NewMap(js)
+	if err != nil {
+		wc.ErrLog(err).WithField("body", js).
+			Error("The request json may contain invalid value")
+		wc.RenderError(jasco.NewError(formValidationErrorCode, "The request json may contain invalid values.",
+			http.StatusBadRequest, err))
+		return
+	}
+	h, err := config.NewWebhook(form)
+	if err != nil {
+		wc.ErrLog(err).Error("The webhook is invalid")
+		e := jasco.NewError(formValidationErrorCode, "The webhook is invalid.",
+			http.StatusBadRequest, err)
+		e.Meta["error"] = err.Error()
+		wc.RenderError(e)
+		return
+	}
+
+	id := wc.hooks.add(wc.topologyName, h)
+	wc.Log().WithField("url", h.URL).Info("Registered a webhook")
+	wc.Render(map[string]interface{}{
+		"topology": wc.topologyName,
+		"webhook":  webhookMap(id, h),
+	})
+}
+
+// Destroy unregisters a webhook of the topology.
+func (wc *webhooks) Destroy(rw web.ResponseWriter, req *web.Request) {
+	id, err := strconv.ParseInt(wc.PathParams().String("webhookID", ""), 10, 64)
+	if err != nil || !wc.hooks.remove(wc.topologyName, id) {
+		if err == nil {
+			err = fmt.Errorf("webhook %v doesn't exist", id)
+		}
+		wc.ErrLog(err).Error("Cannot find the webhook")
+		wc.RenderError(jasco.NewError(requestResourceNotFoundErrorCode,
+			"The webhook was not found", http.StatusNotFound, err))
+		return
+	}
+	wc.Render(map[string]interface{}{})
+}
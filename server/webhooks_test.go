@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/sensorbee/sensorbee.v0/core"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+	"gopkg.in/sensorbee/sensorbee.v0/server/config"
+)
+
+type webhookTestRequest struct {
+	header http.Header
+	body   map[string]interface{}
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	Convey("Given a webhook dispatcher having a server-wide webhook", t, func() {
+		reqs := make(chan *webhookTestRequest, 10)
+		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			b, _ := ioutil.ReadAll(req.Body)
+			r := &webhookTestRequest{header: req.Header}
+			json.Unmarshal(b, &r.body)
+			reqs <- r
+		}))
+		Reset(s.Close)
+		receive := func() *webhookTestRequest {
+			select {
+			case r := <-reqs:
+				return r
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		}
+		nothingReceived := func() bool {
+			select {
+			case <-reqs:
+				return false
+			case <-time.After(100 * time.Millisecond):
+				return true
+			}
+		}
+
+		logger := logrus.New()
+		logger.Out = ioutil.Discard
+		d := newWebhookDispatcher(logger, &config.Webhooks{
+			Timeout: 5,
+			Hooks: []*config.Webhook{{
+				URL:     s.URL + "/global",
+				Events:  []string{"node_failed"},
+				Headers: map[string]string{"X-Token": "secret"},
+			}},
+		})
+
+		Convey("When a node fails", func() {
+			d.notify(&core.LifecycleEvent{
+				Type:     core.LENodeFailed,
+				Topology: "test",
+				NodeType: core.NTBox,
+				NodeName: "box",
+				Time:     time.Now(),
+				Detail:   data.Map{"error": data.String("failure")},
+			})
+
+			Convey("Then the webhook should receive the event", func() {
+				r := receive()
+				So(r, ShouldNotBeNil)
+				So(r.header.Get("Content-Type"), ShouldEqual, "application/json")
+				So(r.header.Get("X-Token"), ShouldEqual, "secret")
+				So(r.body["event"], ShouldEqual, "node_failed")
+				So(r.body["topology"], ShouldEqual, "test")
+				So(r.body["node_type"], ShouldEqual, "box")
+				So(r.body["node_name"], ShouldEqual, "box")
+				So(r.body["detail"], ShouldResemble, map[string]interface{}{"error": "failure"})
+			})
+		})
+
+		Convey("When an event which the webhook doesn't accept is emitted", func() {
+			d.emit(topologyCreatedEvent, "test")
+
+			Convey("Then the webhook shouldn't receive it", func() {
+				So(nothingReceived(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a webhook is registered to a topology", func() {
+			id := d.add("Test", &config.Webhook{
+				URL:     s.URL + "/topology",
+				Headers: map[string]string{"X-Token": "secret"},
+			})
+
+			Convey("Then it should be listed without values of headers", func() {
+				hooks := d.list("test")
+				So(hooks, ShouldHaveLength, 1)
+				So(hooks[0]["id"], ShouldEqual, data.Int(id))
+				So(hooks[0]["url"], ShouldEqual, data.String(s.URL+"/topology"))
+				So(hooks[0]["headers"], ShouldResemble, data.Array{data.String("X-Token")})
+			})
+
+			Convey("Then it should receive events of the topology", func() {
+				d.emit(topologyDestroyedEvent, "test")
+				r := receive()
+				So(r, ShouldNotBeNil)
+				So(r.body["event"], ShouldEqual, "topology_destroyed")
+				So(r.body, ShouldNotContainKey, "node_name")
+			})
+
+			Convey("Then it shouldn't receive events of other topologies", func() {
+				d.emit(topologyCreatedEvent, "other")
+				So(nothingReceived(), ShouldBeTrue)
+			})
+
+			Convey("And it is removed", func() {
+				So(d.remove("test", id), ShouldBeTrue)
+
+				Convey("Then it shouldn't be listed", func() {
+					So(d.list("test"), ShouldBeEmpty)
+				})
+
+				Convey("Then it shouldn't be removed twice", func() {
+					So(d.remove("test", id), ShouldBeFalse)
+				})
+			})
+
+			Convey("And the topology is removed", func() {
+				d.removeTopology("test")
+
+				Convey("Then the webhook should also be removed", func() {
+					So(d.list("test"), ShouldBeEmpty)
+				})
+			})
+		})
+	})
+
+	Convey("Given a nil webhook dispatcher", t, func() {
+		var d *webhookDispatcher
+
+		Convey("Then it shouldn't provide a listener", func() {
+			So(d.listener(), ShouldBeNil)
+		})
+
+		Convey("Then emitting an event should do nothing", func() {
+			So(func() {
+				d.emit(topologyCreatedEvent, "test")
+			}, ShouldNotPanic)
+		})
+	})
+
+	Convey("Given a webhook which always fails", t, func() {
+		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		Reset(s.Close)
+		d := newWebhookDispatcher(logrus.New(), nil)
+
+		Convey("When posting an event", func() {
+			err := d.post(&config.Webhook{URL: s.URL}, "{}")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
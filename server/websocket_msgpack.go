@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/net/websocket"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+// msgpackSubprotocol is the WebSocket subprotocol with which requests and
+// responses of WebSocketQueries are sent as msgpack binary frames instead
+// of JSON text frames. Each frame has the same structure as the JSON one.
+// Like other msgpack encoding in SensorBee, timestamps in tuples are sent
+// as integers.
+const msgpackSubprotocol = "sensorbee.msgpack"
+
+// msgpackWebSocketCodec encodes WebSocket messages in msgpack. Every message
+// is a data.Map.
+var msgpackWebSocketCodec = websocket.Codec{
+	Marshal:   marshalMsgpackFrame,
+	Unmarshal: unmarshalMsgpackFrame,
+}
+
+func marshalMsgpackFrame(v interface{}) ([]byte, byte, error) {
+	m, ok := v.(data.Map)
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported message type: %T", v)
+	}
+	b, err := data.MarshalMsgpack(m)
+	return b, websocket.BinaryFrame, err
+}
+
+func unmarshalMsgpackFrame(msg []byte, payloadType byte, v interface{}) error {
+	if payloadType != websocket.BinaryFrame {
+		return errors.New("a message must be a binary frame with the msgpack subprotocol")
+	}
+	m, ok := v.(*data.Map)
+	if !ok {
+		return fmt.Errorf("unsupported message type: %T", v)
+	}
+	r, err := data.UnmarshalMsgpack(msg)
+	if err != nil {
+		return err
+	}
+	*m = r
+	return nil
+}
+
+// webSocketHandshake selects the msgpack subprotocol when the client offers
+// it. Otherwise, no subprotocol is selected and JSON is used. It also checks
+// the Origin header in the same way as websocket.Handler does.
+func webSocketHandshake(config *websocket.Config, req *http.Request) error {
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return errors.New("null origin")
+	}
+	config.Origin = origin
+
+	offered := config.Protocol
+	config.Protocol = nil
+	for _, p := range offered {
+		if p == msgpackSubprotocol {
+			config.Protocol = []string{p}
+			break
+		}
+	}
+	return nil
+}
+
+// usesMsgpack returns true when the connection uses the msgpack subprotocol.
+func usesMsgpack(conn *websocket.Conn) bool {
+	for _, p := range conn.Config().Protocol {
+		if p == msgpackSubprotocol {
+			return true
+		}
+	}
+	return false
+}
+
+// toMsgpackPayload converts a payload of a response to a data.Value so that
+// it can be encoded in msgpack. Payloads other than data.Value are converted
+// via JSON. Integers in them are kept as integers.
+func toMsgpackPayload(v interface{}) (data.Value, error) {
+	switch p := v.(type) {
+	case nil:
+		return data.Null{}, nil
+	case data.Value:
+		return p, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var i interface{}
+	if err := dec.Decode(&i); err != nil {
+		return nil, err
+	}
+	return data.NewValue(i)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/websocket"
+	"gopkg.in/sensorbee/sensorbee.v0/data"
+)
+
+func TestWebSocketMsgpack(t *testing.T) {
+	f, err := ioutil.TempFile("", "sbtest_websocket_msgpack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{"a":1,"b":"x"}` + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	Convey("Given an API server having a topology", t, func() {
+		s := newTestServer(data.Map{})
+		Reset(s.Close)
+		res, _ := s.request("POST", "/topologies", map[string]interface{}{"name": "test"})
+		So(res.StatusCode, ShouldEqual, http.StatusOK)
+		url := "ws" + s.URL[len("http"):] + "/api/v1/topologies/test/wsqueries"
+
+		Convey("When connecting with the msgpack subprotocol", func() {
+			conf, err := websocket.NewConfig(url, s.URL)
+			So(err, ShouldBeNil)
+			conf.Protocol = []string{"unknown", msgpackSubprotocol}
+			conn, err := websocket.DialConfig(conf)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				conn.Close()
+			})
+
+			Convey("Then the server should select the subprotocol", func() {
+				So(conn.Config().Protocol, ShouldResemble, []string{msgpackSubprotocol})
+			})
+
+			Convey("Then an EVAL statement should return a msgpack response", func() {
+				So(msgpackWebSocketCodec.Send(conn, data.Map{
+					"rid": data.Int(1),
+					"payload": data.Map{
+						"queries": data.String(`EVAL 1 + 2;`),
+					},
+				}), ShouldBeNil)
+
+				var m data.Map
+				So(msgpackWebSocketCodec.Receive(conn, &m), ShouldBeNil)
+				So(m, ShouldResemble, data.Map{
+					"rid":  data.Int(1),
+					"type": data.String("result"),
+					"payload": data.Map{
+						"result": data.Int(3),
+					},
+				})
+			})
+
+			Convey("Then a SELECT statement should return tuples in msgpack", func() {
+				res, _ := s.request("POST", "/topologies/test/queries", map[string]interface{}{
+					"queries": fmt.Sprintf(`CREATE PAUSED SOURCE src TYPE file WITH path="%v";`, f.Name()),
+				})
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(msgpackWebSocketCodec.Send(conn, data.Map{
+					"rid": data.Int(2),
+					"payload": data.Map{
+						"queries": data.String(`SELECT RSTREAM * FROM src [RANGE 1 TUPLES];`),
+					},
+				}), ShouldBeNil)
+
+				var m data.Map
+				So(msgpackWebSocketCodec.Receive(conn, &m), ShouldBeNil)
+				So(m["type"], ShouldEqual, data.String("sos"))
+
+				res, _ = s.request("POST", "/topologies/test/queries", map[string]interface{}{
+					"queries": `RESUME SOURCE src;`,
+				})
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+
+				m = nil
+				So(msgpackWebSocketCodec.Receive(conn, &m), ShouldBeNil)
+				So(m["rid"], ShouldEqual, data.Int(2))
+				So(m["type"], ShouldEqual, data.String("result"))
+				So(m["payload"], ShouldResemble, data.Map{
+					"a": data.Int(1),
+					"b": data.String("x"),
+				})
+			})
+
+			Convey("Then a text frame should be rejected", func() {
+				So(websocket.JSON.Send(conn, map[string]interface{}{
+					"rid": 3,
+					"payload": map[string]interface{}{
+						"queries": `EVAL 1;`,
+					},
+				}), ShouldBeNil)
+
+				var m data.Map
+				So(msgpackWebSocketCodec.Receive(conn, &m), ShouldBeNil)
+				So(m["type"], ShouldEqual, data.String("error"))
+				code, err := m.Get(data.MustCompilePath("payload.code"))
+				So(err, ShouldBeNil)
+				So(code, ShouldEqual, data.String(bqlStmtParseErrorCode))
+			})
+		})
+
+		Convey("When connecting without the msgpack subprotocol", func() {
+			conn, err := websocket.Dial(url, "", s.URL)
+			So(err, ShouldBeNil)
+			Reset(func() {
+				conn.Close()
+			})
+
+			Convey("Then responses should be sent in JSON", func() {
+				So(conn.Config().Protocol, ShouldBeEmpty)
+				So(websocket.JSON.Send(conn, map[string]interface{}{
+					"rid": 1,
+					"payload": map[string]interface{}{
+						"queries": `EVAL 1 + 2;`,
+					},
+				}), ShouldBeNil)
+
+				var js map[string]interface{}
+				So(websocket.JSON.Receive(conn, &js), ShouldBeNil)
+				So(js["type"], ShouldEqual, "result")
+				So(js["payload"], ShouldResemble, map[string]interface{}{"result": 3.0})
+			})
+		})
+	})
+}